@@ -8,6 +8,14 @@ import (
 	"github.com/hashicorp/boundary/api"
 )
 
+// WithMustChangePassword requires the user to change the password set by
+// SetPassword before they can authenticate.
+func WithMustChangePassword(mustChange bool) Option {
+	return func(o *options) {
+		o.postMap["must_change_password"] = mustChange
+	}
+}
+
 func (c *Client) SetPassword(ctx context.Context, accountId, password string, version uint32, opt ...Option) (*AccountUpdateResult, error) {
 	if accountId == "" {
 		return nil, fmt.Errorf("empty accountId value passed into SetPassword request")
@@ -39,6 +47,9 @@ func (c *Client) SetPassword(ctx context.Context, accountId, password string, ve
 		"version":  version,
 		"password": password,
 	}
+	if mustChange, ok := opts.postMap["must_change_password"]; ok {
+		reqBody["must_change_password"] = mustChange
+	}
 
 	req, err := c.client.NewRequest(ctx, "POST", fmt.Sprintf("accounts/%s:set-password", accountId), reqBody, apiOpts...)
 	if err != nil {
@@ -12,6 +12,7 @@ var (
 	ErrInvalidArgument  = &Error{Status: http.StatusBadRequest, Code: codes.InvalidArgument.String()}
 	ErrPermissionDenied = &Error{Status: http.StatusForbidden, Code: codes.PermissionDenied.String()}
 	ErrUnauthorized     = &Error{Status: http.StatusUnauthorized, Code: codes.Unauthenticated.String()}
+	ErrTooManyRequests  = &Error{Status: http.StatusTooManyRequests, Code: codes.ResourceExhausted.String()}
 )
 
 // AsServerError returns an api *Error from the provided error.  If the provided error
@@ -1,6 +1,9 @@
 package authtokens
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/hashicorp/boundary/api"
 )
 
@@ -44,3 +47,57 @@ func WithAutomaticVersioning(enable bool) Option {
 		o.withAutomaticVersioning = enable
 	}
 }
+
+func WithAccountId(inAccountId string) Option {
+	return func(o *options) {
+		o.postMap["account_id"] = inAccountId
+	}
+}
+
+func DefaultAccountId() Option {
+	return func(o *options) {
+		o.postMap["account_id"] = nil
+	}
+}
+
+func WithExpirationTime(inExpirationTime time.Time) Option {
+	return func(o *options) {
+		o.postMap["expiration_time"] = inExpirationTime
+	}
+}
+
+func DefaultExpirationTime() Option {
+	return func(o *options) {
+		o.postMap["expiration_time"] = nil
+	}
+}
+
+func WithFilter(inFilter string) Option {
+	return func(o *options) {
+		o.queryMap["filter"] = fmt.Sprintf("%v", inFilter)
+	}
+}
+
+func WithGrantStrings(inGrantStrings []string) Option {
+	return func(o *options) {
+		o.postMap["grant_strings"] = inGrantStrings
+	}
+}
+
+func DefaultGrantStrings() Option {
+	return func(o *options) {
+		o.postMap["grant_strings"] = nil
+	}
+}
+
+func WithSortBy(inSortBy string) Option {
+	return func(o *options) {
+		o.queryMap["sort_by"] = fmt.Sprintf("%v", inSortBy)
+	}
+}
+
+func WithSortDir(inSortDir string) Option {
+	return func(o *options) {
+		o.queryMap["sort_dir"] = fmt.Sprintf("%v", inSortDir)
+	}
+}
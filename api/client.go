@@ -58,6 +58,11 @@ type Config struct {
 	// per-call, regardless of any value set in Token.
 	RecoveryKmsWrapper wrapping.Wrapper
 
+	// TokenRefresher, if set, is called when a request is rejected because
+	// the token is not authenticated, such as when it has expired. The token
+	// it returns is set on the client and the request is made once more.
+	TokenRefresher func(ctx context.Context) (string, error)
+
 	// HttpClient is the HTTP client to use. Boundary sets sane defaults for the
 	// http.Client and its associated http.Transport created in DefaultConfig.
 	// If you must modify Boundary's defaults, it is suggested that you start
@@ -155,13 +160,27 @@ func DefaultConfig() (*Config, error) {
 		MinVersion: tls.VersionTLS12,
 	}
 
-	config.Backoff = retryablehttp.LinearJitterBackoff
+	config.Backoff = RetryAfterBackoff(retryablehttp.LinearJitterBackoff)
 	config.MaxRetries = 2
 	config.Headers = make(http.Header)
 
 	return config, nil
 }
 
+// RetryAfterBackoff returns a Backoff which waits for as long as the
+// Retry-After header of a 429 or 503 response asks, such as when a request is
+// refused by the controller's API rate limits, and otherwise uses fallback.
+func RetryAfterBackoff(fallback retryablehttp.Backoff) retryablehttp.Backoff {
+	return func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+		return fallback(min, max, attemptNum, resp)
+	}
+}
+
 // ConfigureTLS takes a set of TLS configurations and applies those to the the
 // HTTP client.
 func (c *Config) ConfigureTLS() error {
@@ -506,6 +525,15 @@ func (c *Client) SetToken(token string) {
 	c.config.Token = token
 }
 
+// SetTokenRefresher sets the function called to get a new token when a
+// request is rejected because the token is not authenticated.
+func (c *Client) SetTokenRefresher(refresher func(ctx context.Context) (string, error)) {
+	c.modifyLock.Lock()
+	defer c.modifyLock.Unlock()
+
+	c.config.TokenRefresher = refresher
+}
+
 // RecoveryKmsWrapper gets the configured recovery KMS wrapper.
 func (c *Client) RecoveryKmsWrapper() wrapping.Wrapper {
 	c.modifyLock.RLock()
@@ -552,6 +580,7 @@ func (c *Client) Clone() *Client {
 		Addr:               config.Addr,
 		Token:              config.Token,
 		RecoveryKmsWrapper: config.RecoveryKmsWrapper,
+		TokenRefresher:     config.TokenRefresher,
 		HttpClient:         config.HttpClient,
 		Headers:            make(http.Header),
 		MaxRetries:         config.MaxRetries,
@@ -694,6 +723,7 @@ func (c *Client) Do(r *retryablehttp.Request) (*Response, error) {
 	timeout := c.config.Timeout
 	token := c.config.Token
 	recoveryKmsWrapper := c.config.RecoveryKmsWrapper
+	tokenRefresher := c.config.TokenRefresher
 	outputCurlString := c.config.OutputCurlString
 	c.modifyLock.RUnlock()
 
@@ -726,7 +756,7 @@ func (c *Client) Do(r *retryablehttp.Request) (*Response, error) {
 	r.Request = r.Request.WithContext(ctx)
 
 	if backoff == nil {
-		backoff = retryablehttp.LinearJitterBackoff
+		backoff = RetryAfterBackoff(retryablehttp.LinearJitterBackoff)
 	}
 
 	if recoveryKmsWrapper != nil {
@@ -786,6 +816,18 @@ func (c *Client) Do(r *retryablehttp.Request) (*Response, error) {
 		result, err = client.Do(r)
 	}
 
+	if err == nil && result.StatusCode == http.StatusUnauthorized && tokenRefresher != nil && recoveryKmsWrapper == nil {
+		result.Body.Close()
+		var newToken string
+		newToken, err = tokenRefresher(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error refreshing token: %w", err)
+		}
+		c.SetToken(newToken)
+		r.Header.Set("authorization", "Bearer "+newToken)
+		result, err = client.Do(r)
+	}
+
 	if err != nil {
 		if strings.Contains(err.Error(), "tls: oversized") {
 			err = fmt.Errorf(
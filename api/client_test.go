@@ -1,9 +1,16 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfigSetAddress(t *testing.T) {
@@ -70,3 +77,148 @@ func TestConfigSetAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenRefresher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		refresher  func(context.Context) (string, error)
+		wantStatus int
+		wantToken  string
+		wantErr    string
+	}{
+		{
+			name:       "none",
+			wantStatus: http.StatusUnauthorized,
+			wantToken:  "stale",
+		},
+		{
+			name:       "refreshed",
+			refresher:  func(context.Context) (string, error) { return "fresh", nil },
+			wantStatus: http.StatusOK,
+			wantToken:  "fresh",
+		},
+		{
+			name:       "still-unauthenticated",
+			refresher:  func(context.Context) (string, error) { return "other", nil },
+			wantStatus: http.StatusUnauthorized,
+			wantToken:  "other",
+		},
+		{
+			name:      "error",
+			refresher: func(context.Context) (string, error) { return "", errors.New("no credentials") },
+			wantToken: "stale",
+			wantErr:   "error refreshing token: no credentials",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			client, err := NewClient(&Config{Addr: srv.URL, Token: "stale"})
+			require.NoError(err)
+			client.SetMaxRetries(0)
+			client.SetTokenRefresher(tt.refresher)
+
+			req, err := client.NewRequest(context.Background(), "GET", "scopes", nil)
+			require.NoError(err)
+			resp, err := client.Do(req)
+			if tt.wantErr != "" {
+				assert.EqualError(err, tt.wantErr)
+			} else {
+				require.NoError(err)
+				assert.Equal(tt.wantStatus, resp.HttpResponse().StatusCode)
+			}
+			assert.Equal(tt.wantToken, client.Token())
+		})
+	}
+}
+
+func TestRetryAfterBackoff(t *testing.T) {
+	fallback := func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		return time.Duration(attemptNum) * time.Millisecond
+	}
+	backoff := RetryAfterBackoff(fallback)
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: make(http.Header)}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name string
+		resp *http.Response
+		want time.Duration
+	}{
+		{
+			name: "no-response",
+			want: 3 * time.Millisecond,
+		},
+		{
+			name: "too-many-requests",
+			resp: response(http.StatusTooManyRequests, "7"),
+			want: 7 * time.Second,
+		},
+		{
+			name: "unavailable",
+			resp: response(http.StatusServiceUnavailable, "2"),
+			want: 2 * time.Second,
+		},
+		{
+			name: "no-header",
+			resp: response(http.StatusTooManyRequests, ""),
+			want: 3 * time.Millisecond,
+		},
+		{
+			name: "http-date-header",
+			resp: response(http.StatusTooManyRequests, "Wed, 21 Oct 2015 07:28:00 GMT"),
+			want: 3 * time.Millisecond,
+		},
+		{
+			name: "server-error",
+			resp: response(http.StatusInternalServerError, "7"),
+			want: 3 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, backoff(time.Second, time.Minute, 3, tt.resp))
+		})
+	}
+}
+
+func TestRetryTooManyRequests(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	assert, require := assert.New(t), require.New(t)
+	client, err := NewClient(&Config{Addr: srv.URL})
+	require.NoError(err)
+	client.SetMaxRetries(1)
+	req, err := client.NewRequest(context.Background(), "GET", "scopes", nil)
+	require.NoError(err)
+	start := time.Now()
+	resp, err := client.Do(req)
+	require.NoError(err)
+	assert.Equal(http.StatusOK, resp.HttpResponse().StatusCode)
+	assert.Equal(int32(2), atomic.LoadInt32(&requests))
+	// The Retry-After header is followed rather than the default wait
+	assert.Less(int64(time.Since(start)), int64(time.Second))
+}
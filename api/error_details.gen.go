@@ -2,8 +2,11 @@
 package api
 
 type ErrorDetails struct {
-	TraceId       string        `json:"TraceId,omitempty"`
-	RequestId     string        `json:"request_id,omitempty"`
-	ErrorId       string        `json:"error_id,omitempty"`
-	RequestFields []*FieldError `json:"request_fields,omitempty"`
+	TraceId           string        `json:"TraceId,omitempty"`
+	RequestId         string        `json:"request_id,omitempty"`
+	ErrorId           string        `json:"error_id,omitempty"`
+	RequestFields     []*FieldError `json:"request_fields,omitempty"`
+	RetryAfterSeconds uint32        `json:"retry_after_seconds,omitempty"`
+	Quota             string        `json:"quota,omitempty"`
+	QuotaScopeId      string        `json:"quota_scope_id,omitempty"`
 }
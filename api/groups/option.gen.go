@@ -1,6 +1,8 @@
 package groups
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/boundary/api"
 )
 
@@ -57,6 +59,12 @@ func DefaultDescription() Option {
 	}
 }
 
+func WithFilter(inFilter string) Option {
+	return func(o *options) {
+		o.queryMap["filter"] = fmt.Sprintf("%v", inFilter)
+	}
+}
+
 func WithName(inName string) Option {
 	return func(o *options) {
 		o.postMap["name"] = inName
@@ -68,3 +76,15 @@ func DefaultName() Option {
 		o.postMap["name"] = nil
 	}
 }
+
+func WithSortBy(inSortBy string) Option {
+	return func(o *options) {
+		o.queryMap["sort_by"] = fmt.Sprintf("%v", inSortBy)
+	}
+}
+
+func WithSortDir(inSortDir string) Option {
+	return func(o *options) {
+		o.queryMap["sort_dir"] = fmt.Sprintf("%v", inSortDir)
+	}
+}
@@ -0,0 +1,94 @@
+package hosts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// HostImportError is the reason one of the hosts passed to Import wasn't
+// created.
+type HostImportError struct {
+	// Row is the index of the host in the hosts passed to Import.
+	Row     uint32            `json:"row,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+type HostImportResult struct {
+	Items        []*Host
+	Errors       []*HostImportError
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n HostImportResult) GetItems() interface{} {
+	return n.Items
+}
+
+func (n HostImportResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n HostImportResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// Import creates hosts in the host catalog in one request. Only the name,
+// description, type and attributes of each host are used. Hosts which can't
+// be created are reported in the result's Errors rather than failing the
+// request.
+func (c *Client) Import(ctx context.Context, hostCatalogId string, hosts []*Host, opt ...Option) (*HostImportResult, error) {
+	if hostCatalogId == "" {
+		return nil, fmt.Errorf("empty hostCatalogId value passed into Import request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client")
+	}
+
+	_, apiOpts := getOpts(opt...)
+
+	items := make([]map[string]interface{}, 0, len(hosts))
+	for _, h := range hosts {
+		item := map[string]interface{}{}
+		if h.Name != "" {
+			item["name"] = h.Name
+		}
+		if h.Description != "" {
+			item["description"] = h.Description
+		}
+		if h.Type != "" {
+			item["type"] = h.Type
+		}
+		if len(h.Attributes) > 0 {
+			item["attributes"] = h.Attributes
+		}
+		items = append(items, item)
+	}
+	reqBody := map[string]interface{}{
+		"host_catalog_id": hostCatalogId,
+		"items":           items,
+	}
+
+	req, err := c.client.NewRequest(ctx, "POST", "hosts:import", reqBody, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Import request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Import call: %w", err)
+	}
+
+	target := new(HostImportResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Import response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
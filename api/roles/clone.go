@@ -0,0 +1,44 @@
+package roles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Clone creates a copy of the role with roleId in the same scope, with its
+// description, grant scope and grants but none of its principals. WithName
+// and WithDescription set the name and description of the copy.
+func (c *Client) Clone(ctx context.Context, roleId string, opt ...Option) (*RoleCreateResult, error) {
+	if roleId == "" {
+		return nil, fmt.Errorf("empty roleId value passed into Clone request")
+	}
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+
+	req, err := c.client.NewRequest(ctx, "POST", fmt.Sprintf("roles/%s:clone", roleId), opts.postMap, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Clone request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Clone call: %w", err)
+	}
+
+	target := new(RoleCreateResult)
+	target.Item = new(Role)
+	apiErr, err := resp.Decode(target.Item)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Clone response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
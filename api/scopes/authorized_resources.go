@@ -0,0 +1,71 @@
+package scopes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type AuthorizedResourcesResult struct {
+	Ids          []string `json:"ids,omitempty"`
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n AuthorizedResourcesResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n AuthorizedResourcesResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// ListAuthorizedResources returns the ids of the resources of resourceType
+// within the scope which the caller is allowed to perform action on.
+func (c *Client) ListAuthorizedResources(ctx context.Context, scopeId, resourceType, action string, opt ...Option) (*AuthorizedResourcesResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into ListAuthorizedResources request")
+	}
+	if resourceType == "" {
+		return nil, fmt.Errorf("empty resourceType value passed into ListAuthorizedResources request")
+	}
+	if action == "" {
+		return nil, fmt.Errorf("empty action value passed into ListAuthorizedResources request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+	opts.queryMap["type"] = resourceType
+	opts.queryMap["action"] = action
+
+	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("scopes/%s:authorized-resources", scopeId), nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ListAuthorizedResources request: %w", err)
+	}
+
+	q := url.Values{}
+	for k, v := range opts.queryMap {
+		q.Add(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during ListAuthorizedResources call: %w", err)
+	}
+
+	target := new(AuthorizedResourcesResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding ListAuthorizedResources response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
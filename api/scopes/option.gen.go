@@ -59,6 +59,12 @@ func DefaultDescription() Option {
 	}
 }
 
+func WithFilter(inFilter string) Option {
+	return func(o *options) {
+		o.queryMap["filter"] = fmt.Sprintf("%v", inFilter)
+	}
+}
+
 func WithName(inName string) Option {
 	return func(o *options) {
 		o.postMap["name"] = inName
@@ -71,6 +77,18 @@ func DefaultName() Option {
 	}
 }
 
+func WithReadOnly(inReadOnly bool) Option {
+	return func(o *options) {
+		o.postMap["read_only"] = inReadOnly
+	}
+}
+
+func DefaultReadOnly() Option {
+	return func(o *options) {
+		o.postMap["read_only"] = nil
+	}
+}
+
 func WithSkipAdminRoleCreation(inSkipAdminRoleCreation bool) Option {
 	return func(o *options) {
 		o.queryMap["skip_admin_role_creation"] = fmt.Sprintf("%v", inSkipAdminRoleCreation)
@@ -94,3 +112,15 @@ func DefaultSkipDefaultRoleCreation() Option {
 		o.postMap["skip_default_role_creation"] = nil
 	}
 }
+
+func WithSortBy(inSortBy string) Option {
+	return func(o *options) {
+		o.queryMap["sort_by"] = fmt.Sprintf("%v", inSortBy)
+	}
+}
+
+func WithSortDir(inSortDir string) Option {
+	return func(o *options) {
+		o.queryMap["sort_dir"] = fmt.Sprintf("%v", inSortDir)
+	}
+}
@@ -0,0 +1,74 @@
+package scopes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+)
+
+// QuotaUsage is an organization's use of its quotas. Limits of zero mean the
+// quota is unlimited.
+type QuotaUsage struct {
+	ScopeId           string    `json:"scope_id,omitempty"`
+	PeriodStart       time.Time `json:"period_start,omitempty"`
+	PeriodEnd         time.Time `json:"period_end,omitempty"`
+	Requests          uint64    `json:"requests,omitempty,string"`
+	MaxRequests       uint64    `json:"max_requests,omitempty,string"`
+	ActiveSessions    uint64    `json:"active_sessions,omitempty,string"`
+	MaxActiveSessions uint64    `json:"max_active_sessions,omitempty,string"`
+}
+
+type QuotaUsageReadResult struct {
+	Item         *QuotaUsage
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n QuotaUsageReadResult) GetItem() interface{} {
+	return n.Item
+}
+
+func (n QuotaUsageReadResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n QuotaUsageReadResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// GetQuotaUsage returns the requests made in the org with id scopeId in the
+// current quota period and its active sessions, along with its quotas.
+func (c *Client) GetQuotaUsage(ctx context.Context, scopeId string, opt ...Option) (*QuotaUsageReadResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into GetQuotaUsage request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client")
+	}
+
+	_, apiOpts := getOpts(opt...)
+
+	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("scopes/%s:quota-usage", scopeId), nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GetQuotaUsage request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during GetQuotaUsage call: %w", err)
+	}
+
+	target := new(QuotaUsageReadResult)
+	target.Item = new(QuotaUsage)
+	apiErr, err := resp.Decode(target.Item)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding GetQuotaUsage response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
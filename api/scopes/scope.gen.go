@@ -22,6 +22,7 @@ type Scope struct {
 	UpdatedTime time.Time  `json:"updated_time,omitempty"`
 	Version     uint32     `json:"version,omitempty"`
 	Type        string     `json:"type,omitempty"`
+	ReadOnly    bool       `json:"read_only,omitempty"`
 
 	responseBody *bytes.Buffer
 	responseMap  map[string]interface{}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type SearchResult struct {
+	Items        []*Result
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n SearchResult) GetItems() interface{} {
+	return n.Items
+}
+
+func (n SearchResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n SearchResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// WithTypes limits a search to resources of the given types, such as
+// "target". All types which can be searched for are searched by default.
+func WithTypes(types ...string) Option {
+	return func(o *options) {
+		o.queryMap["types"] = strings.Join(types, ",")
+	}
+}
+
+// WithRecursive includes the scopes within the scope in a search.
+func WithRecursive(recursive bool) Option {
+	return func(o *options) {
+		o.queryMap["recursive"] = strconv.FormatBool(recursive)
+	}
+}
+
+// Search returns the resources within the scope whose name, description, ID
+// or address match query, best matches first. Only resources the caller is
+// allowed to read are returned.
+func (c *Client) Search(ctx context.Context, scopeId, query string, opt ...Option) (*SearchResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into Search request")
+	}
+	if query == "" {
+		return nil, fmt.Errorf("empty query value passed into Search request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+	opts.queryMap["scope_id"] = scopeId
+	opts.queryMap["query"] = query
+
+	req, err := c.client.NewRequest(ctx, "GET", "search", nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Search request: %w", err)
+	}
+
+	q := url.Values{}
+	for k, v := range opts.queryMap {
+		if k == "types" {
+			for _, t := range strings.Split(v, ",") {
+				q.Add(k, t)
+			}
+			continue
+		}
+		q.Add(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Search call: %w", err)
+	}
+
+	target := new(SearchResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Search response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
@@ -0,0 +1,73 @@
+package sessions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// SessionCount is the number of sessions in a project by their status.
+type SessionCount struct {
+	Total      uint64 `json:"total,omitempty,string"`
+	Pending    uint64 `json:"pending,omitempty,string"`
+	Active     uint64 `json:"active,omitempty,string"`
+	Canceling  uint64 `json:"canceling,omitempty,string"`
+	Terminated uint64 `json:"terminated,omitempty,string"`
+}
+
+type SessionCountResult struct {
+	Item         *SessionCount
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n SessionCountResult) GetItem() interface{} {
+	return n.Item
+}
+
+func (n SessionCountResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n SessionCountResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// Count returns the number of sessions in the project with id scopeId by
+// their status. Callers who can only read their own sessions only have their
+// own counted.
+func (c *Client) Count(ctx context.Context, scopeId string, opt ...Option) (*SessionCountResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into Count request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client")
+	}
+
+	_, apiOpts := getOpts(opt...)
+
+	req, err := c.client.NewRequest(ctx, "GET", "sessions:count", nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Count request: %w", err)
+	}
+	req.URL.RawQuery = url.Values{"scope_id": []string{scopeId}}.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Count call: %w", err)
+	}
+
+	target := new(SessionCountResult)
+	target.Item = new(SessionCount)
+	apiErr, err := resp.Decode(target.Item)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Count response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
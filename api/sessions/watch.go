@@ -0,0 +1,70 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// StatusEvent is sent by the controller each time a watched session changes
+// status.
+type StatusEvent struct {
+	Id     string    `json:"id"`
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+// Watch subscribes to the status changes of the sessions and calls fn with
+// each one as it arrives. The current status of each session is sent first.
+// It returns nil once every session is terminated, or the error returned by
+// fn, which stops the watch. The stream is also ended when ctx is done or
+// the client or controller request timeout is reached.
+func (c *Client) Watch(ctx context.Context, sessionIds []string, fn func(*StatusEvent) error, opt ...Option) error {
+	if len(sessionIds) == 0 {
+		return fmt.Errorf("empty sessionIds value passed into Watch request")
+	}
+	if fn == nil {
+		return fmt.Errorf("nil fn value passed into Watch request")
+	}
+	if c.client == nil {
+		return errors.New("nil client")
+	}
+
+	_, apiOpts := getOpts(opt...)
+
+	req, err := c.client.NewRequest(ctx, "POST", "sessions:watch", map[string]interface{}{"ids": sessionIds}, apiOpts...)
+	if err != nil {
+		return fmt.Errorf("error creating Watch request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error performing client request during Watch call: %w", err)
+	}
+	if resp.HttpResponse().StatusCode >= 400 {
+		apiErr, err := resp.Decode(nil)
+		if err != nil {
+			return fmt.Errorf("error decoding Watch response: %w", err)
+		}
+		return apiErr
+	}
+
+	body := resp.HttpResponse().Body
+	defer body.Close()
+	dec := json.NewDecoder(body)
+	for {
+		ev := new(StatusEvent)
+		if err := dec.Decode(ev); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("error decoding Watch response: %w", err)
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+}
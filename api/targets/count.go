@@ -0,0 +1,67 @@
+package targets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// TargetCount is the number of targets in a project.
+type TargetCount struct {
+	Total uint64 `json:"total,omitempty,string"`
+}
+
+type TargetCountResult struct {
+	Item         *TargetCount
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n TargetCountResult) GetItem() interface{} {
+	return n.Item
+}
+
+func (n TargetCountResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n TargetCountResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// Count returns the number of targets in the project with id scopeId.
+func (c *Client) Count(ctx context.Context, scopeId string, opt ...Option) (*TargetCountResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into Count request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client")
+	}
+
+	_, apiOpts := getOpts(opt...)
+
+	req, err := c.client.NewRequest(ctx, "GET", "targets:count", nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Count request: %w", err)
+	}
+	req.URL.RawQuery = url.Values{"scope_id": []string{scopeId}}.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Count call: %w", err)
+	}
+
+	target := new(TargetCountResult)
+	target.Item = new(TargetCount)
+	apiErr, err := resp.Decode(target.Item)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Count response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
@@ -1,6 +1,8 @@
 package targets
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/boundary/api"
 )
 
@@ -45,6 +47,42 @@ func WithAutomaticVersioning(enable bool) Option {
 	}
 }
 
+func WithAccessSchedule(inAccessSchedule string) Option {
+	return func(o *options) {
+		o.postMap["access_schedule"] = inAccessSchedule
+	}
+}
+
+func DefaultAccessSchedule() Option {
+	return func(o *options) {
+		o.postMap["access_schedule"] = nil
+	}
+}
+
+func WithTcpTargetAllowedPorts(inAllowedPorts string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["allowed_ports"] = inAllowedPorts
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetAllowedPorts() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["allowed_ports"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
 func WithAttributes(inAttributes map[string]interface{}) Option {
 	return func(o *options) {
 		o.postMap["attributes"] = inAttributes
@@ -57,6 +95,42 @@ func DefaultAttributes() Option {
 	}
 }
 
+func WithAuthorizationValidSeconds(inAuthorizationValidSeconds uint32) Option {
+	return func(o *options) {
+		o.postMap["authorization_valid_seconds"] = inAuthorizationValidSeconds
+	}
+}
+
+func DefaultAuthorizationValidSeconds() Option {
+	return func(o *options) {
+		o.postMap["authorization_valid_seconds"] = nil
+	}
+}
+
+func WithConnectionBandwidthLimit(inConnectionBandwidthLimit uint32) Option {
+	return func(o *options) {
+		o.postMap["connection_bandwidth_limit"] = inConnectionBandwidthLimit
+	}
+}
+
+func DefaultConnectionBandwidthLimit() Option {
+	return func(o *options) {
+		o.postMap["connection_bandwidth_limit"] = nil
+	}
+}
+
+func WithConnectionIdleTimeoutSeconds(inConnectionIdleTimeoutSeconds uint32) Option {
+	return func(o *options) {
+		o.postMap["connection_idle_timeout_seconds"] = inConnectionIdleTimeoutSeconds
+	}
+}
+
+func DefaultConnectionIdleTimeoutSeconds() Option {
+	return func(o *options) {
+		o.postMap["connection_idle_timeout_seconds"] = nil
+	}
+}
+
 func WithTcpTargetDefaultPort(inDefaultPort uint32) Option {
 	return func(o *options) {
 		raw, ok := o.postMap["attributes"]
@@ -93,12 +167,78 @@ func DefaultDescription() Option {
 	}
 }
 
+func WithDryRun(inDryRun bool) Option {
+	return func(o *options) {
+		o.postMap["dry_run"] = inDryRun
+	}
+}
+
+func WithTcpTargetEndpointTls(inEndpointTls bool) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["endpoint_tls"] = inEndpointTls
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetEndpointTls() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["endpoint_tls"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithFilter(inFilter string) Option {
+	return func(o *options) {
+		o.queryMap["filter"] = fmt.Sprintf("%v", inFilter)
+	}
+}
+
 func WithHostId(inHostId string) Option {
 	return func(o *options) {
 		o.postMap["host_id"] = inHostId
 	}
 }
 
+func WithTcpTargetHostSelectionStrategy(inHostSelectionStrategy string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["host_selection_strategy"] = inHostSelectionStrategy
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetHostSelectionStrategy() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["host_selection_strategy"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithMaxAuthorizationFormat(inMaxAuthorizationFormat uint32) Option {
+	return func(o *options) {
+		o.postMap["max_authorization_format"] = inMaxAuthorizationFormat
+	}
+}
+
 func WithName(inName string) Option {
 	return func(o *options) {
 		o.postMap["name"] = inName
@@ -111,6 +251,30 @@ func DefaultName() Option {
 	}
 }
 
+func WithTcpTargetProtocol(inProtocol string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["protocol"] = inProtocol
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetProtocol() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["protocol"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
 func WithSessionConnectionLimit(inSessionConnectionLimit int32) Option {
 	return func(o *options) {
 		o.postMap["session_connection_limit"] = inSessionConnectionLimit
@@ -134,3 +298,195 @@ func DefaultSessionMaxSeconds() Option {
 		o.postMap["session_max_seconds"] = nil
 	}
 }
+
+func WithSortBy(inSortBy string) Option {
+	return func(o *options) {
+		o.queryMap["sort_by"] = fmt.Sprintf("%v", inSortBy)
+	}
+}
+
+func WithSortDir(inSortDir string) Option {
+	return func(o *options) {
+		o.queryMap["sort_dir"] = fmt.Sprintf("%v", inSortDir)
+	}
+}
+
+func WithTcpTargetSshHostKey(inSshHostKey string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["ssh_host_key"] = inSshHostKey
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetSshHostKey() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["ssh_host_key"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithTcpTargetSshPrivateKey(inSshPrivateKey string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["ssh_private_key"] = inSshPrivateKey
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetSshPrivateKey() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["ssh_private_key"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithTcpTargetSshUsername(inSshUsername string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["ssh_username"] = inSshUsername
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetSshUsername() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["ssh_username"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithTerminateSessionsAtScheduleEnd(inTerminateSessionsAtScheduleEnd bool) Option {
+	return func(o *options) {
+		o.postMap["terminate_sessions_at_schedule_end"] = inTerminateSessionsAtScheduleEnd
+	}
+}
+
+func DefaultTerminateSessionsAtScheduleEnd() Option {
+	return func(o *options) {
+		o.postMap["terminate_sessions_at_schedule_end"] = nil
+	}
+}
+
+func WithTcpTargetTlsCaBundle(inTlsCaBundle string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_ca_bundle"] = inTlsCaBundle
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetTlsCaBundle() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_ca_bundle"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithTcpTargetTlsClientCertificate(inTlsClientCertificate string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_client_certificate"] = inTlsClientCertificate
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetTlsClientCertificate() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_client_certificate"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithTcpTargetTlsClientPrivateKey(inTlsClientPrivateKey string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_client_private_key"] = inTlsClientPrivateKey
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetTlsClientPrivateKey() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_client_private_key"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithTcpTargetTlsServerName(inTlsServerName string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_server_name"] = inTlsServerName
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultTcpTargetTlsServerName() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_server_name"] = nil
+		o.postMap["attributes"] = val
+	}
+}
@@ -8,13 +8,16 @@ import (
 )
 
 type SessionAuthorization struct {
-	SessionId          string            `json:"session_id,omitempty"`
-	TargetId           string            `json:"target_id,omitempty"`
-	Scope              *scopes.ScopeInfo `json:"scope,omitempty"`
-	CreatedTime        time.Time         `json:"created_time,omitempty"`
-	UserId             string            `json:"user_id,omitempty"`
-	HostSetId          string            `json:"host_set_id,omitempty"`
-	HostId             string            `json:"host_id,omitempty"`
-	Type               string            `json:"type,omitempty"`
-	AuthorizationToken string            `json:"authorization_token,omitempty"`
+	SessionId           string            `json:"session_id,omitempty"`
+	TargetId            string            `json:"target_id,omitempty"`
+	Scope               *scopes.ScopeInfo `json:"scope,omitempty"`
+	CreatedTime         time.Time         `json:"created_time,omitempty"`
+	UserId              string            `json:"user_id,omitempty"`
+	HostSetId           string            `json:"host_set_id,omitempty"`
+	HostId              string            `json:"host_id,omitempty"`
+	Type                string            `json:"type,omitempty"`
+	AuthorizationToken  string            `json:"authorization_token,omitempty"`
+	AuthorizationFormat uint32            `json:"authorization_format,omitempty"`
+	DryRun              bool              `json:"dry_run,omitempty"`
+	DenialReasons       []string          `json:"denial_reasons,omitempty"`
 }
@@ -2,5 +2,16 @@
 package targets
 
 type TcpTargetAttributes struct {
-	DefaultPort uint32 `json:"default_port,omitempty"`
+	DefaultPort           uint32 `json:"default_port,omitempty"`
+	Protocol              string `json:"protocol,omitempty"`
+	AllowedPorts          string `json:"allowed_ports,omitempty"`
+	SshUsername           string `json:"ssh_username,omitempty"`
+	SshPrivateKey         string `json:"ssh_private_key,omitempty"`
+	SshHostKey            string `json:"ssh_host_key,omitempty"`
+	HostSelectionStrategy string `json:"host_selection_strategy,omitempty"`
+	EndpointTls           bool   `json:"endpoint_tls,omitempty"`
+	TlsServerName         string `json:"tls_server_name,omitempty"`
+	TlsCaBundle           string `json:"tls_ca_bundle,omitempty"`
+	TlsClientCertificate  string `json:"tls_client_certificate,omitempty"`
+	TlsClientPrivateKey   string `json:"tls_client_private_key,omitempty"`
 }
@@ -0,0 +1,68 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// UserCount is the number of users in an org or the global scope.
+type UserCount struct {
+	Total uint64 `json:"total,omitempty,string"`
+}
+
+type UserCountResult struct {
+	Item         *UserCount
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n UserCountResult) GetItem() interface{} {
+	return n.Item
+}
+
+func (n UserCountResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n UserCountResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// Count returns the number of users in the org, or global scope, with id
+// scopeId.
+func (c *Client) Count(ctx context.Context, scopeId string, opt ...Option) (*UserCountResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into Count request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client")
+	}
+
+	_, apiOpts := getOpts(opt...)
+
+	req, err := c.client.NewRequest(ctx, "GET", "users:count", nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Count request: %w", err)
+	}
+	req.URL.RawQuery = url.Values{"scope_id": []string{scopeId}}.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Count call: %w", err)
+	}
+
+	target := new(UserCountResult)
+	target.Item = new(UserCount)
+	apiErr, err := resp.Decode(target.Item)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Count response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
@@ -11,6 +11,7 @@ import "time"
 
 const (
 	TcpProxyV1     = "boundary-tcp-proxy-v1"
+	UdpProxyV1     = "boundary-udp-proxy-v1"
 	ServiceTokenV1 = "s1"
 )
 
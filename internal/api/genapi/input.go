@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/hostsets"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/roles"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/search"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/sessions"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/users"
@@ -221,6 +222,14 @@ var inputStructs = []*structInfo{
 			"Principals": "principalIds",
 			"Grants":     "grantStrings",
 		},
+		extraOptions: []fieldInfo{
+			{
+				Name:      "Template",
+				ProtoName: "template",
+				FieldType: "string",
+				Query:     true,
+			},
+		},
 		pathArgs:            []string{"role"},
 		versionEnabled:      true,
 		createResponseTypes: true,
@@ -275,6 +284,7 @@ var inputStructs = []*structInfo{
 		outFile: "authtokens/authtokens.gen.go",
 		templates: []*template.Template{
 			clientTemplate,
+			createTemplate,
 			readTemplate,
 			deleteTemplate,
 			listTemplate,
@@ -365,6 +375,18 @@ var inputStructs = []*structInfo{
 				FieldType:   "string",
 				SkipDefault: true,
 			},
+			{
+				Name:        "MaxAuthorizationFormat",
+				ProtoName:   "max_authorization_format",
+				FieldType:   "uint32",
+				SkipDefault: true,
+			},
+			{
+				Name:        "DryRun",
+				ProtoName:   "dry_run",
+				FieldType:   "bool",
+				SkipDefault: true,
+			},
 		},
 		versionEnabled:      true,
 		typeOnCreate:        true,
@@ -395,6 +417,10 @@ var inputStructs = []*structInfo{
 		inProto: &sessions.WorkerInfo{},
 		outFile: "sessions/workers.gen.go",
 	},
+	{
+		inProto: &sessions.Connection{},
+		outFile: "sessions/connection.gen.go",
+	},
 	{
 		inProto:     &targets.SessionAuthorization{},
 		outFile:     "targets/session_authorization.gen.go",
@@ -405,4 +431,11 @@ var inputStructs = []*structInfo{
 		outFile:     "targets/worker_info.gen.go",
 		subtypeName: "WorkerInfo",
 	},
+	{
+		inProto: &search.Result{},
+		outFile: "search/result.gen.go",
+		templates: []*template.Template{
+			clientTemplate,
+		},
+	},
 }
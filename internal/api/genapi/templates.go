@@ -108,6 +108,39 @@ func fillTemplates() {
 			}
 			optionsMap[input.Package] = optionMap
 		}
+		// Every resource which can be listed can filter and sort the listed
+		// items
+		for _, t := range in.templates {
+			if t == listTemplate {
+				optionMap := optionsMap[input.Package]
+				if optionMap == nil {
+					optionMap = map[string]fieldInfo{}
+				}
+				optionMap["Filter"] = fieldInfo{
+					Name:        "Filter",
+					ProtoName:   "filter",
+					FieldType:   "string",
+					Query:       true,
+					SkipDefault: true,
+				}
+				optionMap["SortBy"] = fieldInfo{
+					Name:        "SortBy",
+					ProtoName:   "sort_by",
+					FieldType:   "string",
+					Query:       true,
+					SkipDefault: true,
+				}
+				optionMap["SortDir"] = fieldInfo{
+					Name:        "SortDir",
+					ProtoName:   "sort_dir",
+					FieldType:   "string",
+					Query:       true,
+					SkipDefault: true,
+				}
+				optionsMap[input.Package] = optionMap
+			}
+		}
+
 		// Add in extra defined options
 		if len(in.extraOptions) > 0 {
 			optionMap := optionsMap[input.Package]
@@ -9,10 +9,13 @@ import (
 	"time"
 
 	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/event"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	"github.com/hashicorp/boundary/internal/gen/controller/tokens"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/quota"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/types/action"
@@ -55,6 +58,10 @@ type RequestInfo struct {
 	Token          string
 	TokenFormat    TokenFormat
 
+	// Quotas, if set, counts the request against the quota of the
+	// organization it's made in.
+	Quotas *quota.Accountant
+
 	// The following are useful for tests
 	scopeIdOverride      string
 	userIdOverride       string
@@ -68,10 +75,29 @@ type VerifyResults struct {
 	Error       error
 	Scope       *scopes.ScopeInfo
 
+	// OnlySelf is true if the action was allowed only because the resource
+	// belongs to the requesting user, such as through a read:self grant.
+	OnlySelf bool
+
 	// Used for additional verification
 	v *verifier
 }
 
+// AuditInfo describes the first authn/authz check made while handling a
+// request, for inclusion in the request's audit event.
+type AuditInfo struct {
+	UserId      string
+	AuthTokenId string
+	Scope       *scopes.ScopeInfo
+	Resource    perms.Resource
+	Action      action.Type
+	Allowed     bool
+
+	// RecoveryNonce is the nonce of the recovery token the request was
+	// authorized with, if it was.
+	RecoveryNonce string
+}
+
 type verifier struct {
 	logger          hclog.Logger
 	iamRepoFn       common.IamRepoFactory
@@ -83,6 +109,13 @@ type verifier struct {
 	act             action.Type
 	ctx             context.Context
 	acl             perms.ACL
+	audit           *AuditInfo
+	recoveryNonce   string
+	quotaCounted    bool
+
+	// apiToken is the api token the request was made with, if it was. The
+	// request is restricted to the token's grants as well as its user's.
+	apiToken *authtoken.AuthToken
 }
 
 // NewVerifierContext creates a context that carries a verifier object from the
@@ -106,6 +139,16 @@ func NewVerifierContext(ctx context.Context,
 	})
 }
 
+// GetAuditInfo returns the details of the first authn/authz check made for the
+// request whose verifier is carried by ctx, or nil if no check was made.
+func GetAuditInfo(ctx context.Context) *AuditInfo {
+	v, ok := ctx.Value(verifierKey).(*verifier)
+	if !ok {
+		return nil
+	}
+	return v.audit
+}
+
 // Verify takes in a context that has expected parameters as values and runs an
 // authn/authz check. It returns a user ID, the scope ID for the request (which
 // may come from the URL and may come from the token) and whether or not to
@@ -125,6 +168,7 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 	v.ctx = ctx
 
 	opts := getOpts(opt...)
+	defer v.recordAudit(&ret, opts)
 
 	ret.Scope = new(scopes.ScopeInfo)
 	if v.requestInfo.DisableAuthEntirely {
@@ -159,6 +203,9 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 
 	if v.requestInfo.EncryptedToken != "" {
 		v.decryptToken()
+		if v.requestInfo.TokenFormat == AuthTokenTypeUnknown {
+			v.writeInvalidToken("token not found or could not be decrypted")
+		}
 	}
 
 	var authResults perms.ACLResults
@@ -170,6 +217,7 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 	}
 
 	ret.AuthTokenId = v.requestInfo.PublicId
+	authResults.Allowed, ret.OnlySelf = checkSelf(authResults, opts.withOwnerId, ret.UserId)
 	if !authResults.Allowed {
 		if v.requestInfo.DisableAuthzFailures {
 			ret.Error = nil
@@ -182,10 +230,16 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 			if ret.UserId == "u_anon" {
 				ret.Error = handlers.UnauthenticatedError()
 			}
+			v.writeDenied(&ret, *v.res, v.act)
 			return
 		}
 	}
 
+	if err := v.countRequest(ret.Scope); err != nil {
+		ret.Error = err
+		return
+	}
+
 	ret.Error = nil
 	return
 }
@@ -203,6 +257,11 @@ func (r *VerifyResults) AdditionalVerification(ctx context.Context, opt ...Optio
 	ret.AuthTokenId = r.AuthTokenId
 	ret.v = r.v
 
+	if v.requestInfo.DisableAuthEntirely {
+		ret.Error = nil
+		return
+	}
+
 	opts := getOpts(opt...)
 
 	act := opts.withAction
@@ -263,7 +322,8 @@ func (r *VerifyResults) AdditionalVerification(ctx context.Context, opt ...Optio
 		return
 	}
 
-	aclResults := v.acl.Allowed(res, act)
+	aclResults := v.allowed(v.acl, res, act)
+	aclResults.Allowed, ret.OnlySelf = checkSelf(aclResults, opts.withOwnerId, ret.UserId)
 
 	if !aclResults.Allowed {
 		if v.requestInfo.DisableAuthzFailures {
@@ -277,6 +337,7 @@ func (r *VerifyResults) AdditionalVerification(ctx context.Context, opt ...Optio
 			if ret.UserId == "u_anon" {
 				ret.Error = handlers.UnauthenticatedError()
 			}
+			v.writeDenied(&ret, res, act)
 			return
 		}
 	}
@@ -285,7 +346,193 @@ func (r *VerifyResults) AdditionalVerification(ctx context.Context, opt ...Optio
 	return
 }
 
-func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string, scopeInfo *scopes.ScopeInfo, retAcl perms.ACL, retErr error) {
+// ResourcePermissions returns the resources of type typ within scopeId which
+// the requesting user is allowed to perform act on, according to the grants
+// looked up by Verify.
+func (r *VerifyResults) ResourcePermissions(scopeId string, typ resource.Type, act action.Type) perms.ResourcePermissions {
+	v := r.v
+	if v == nil {
+		return perms.ResourcePermissions{}
+	}
+	if v.requestInfo.DisableAuthEntirely || v.requestInfo.TokenFormat == AuthTokenTypeRecoveryKms {
+		return perms.ResourcePermissions{All: true}
+	}
+	ret := v.acl.ResourcePermissions(scopeId, typ, act)
+	if acl, ok := v.apiTokenACL(scopeId); ok {
+		ret = ret.Intersect(acl.ResourcePermissions(scopeId, typ, act))
+	}
+	return ret
+}
+
+// Quotas returns the accountant requests are counted with, or nil if quotas
+// are not enabled.
+func (r *VerifyResults) Quotas() *quota.Accountant {
+	if r.v == nil {
+		return nil
+	}
+	return r.v.requestInfo.Quotas
+}
+
+// OrgId returns the id of the organization s is, or is a project in. It
+// returns an empty string for the global scope.
+func OrgId(s *scopes.ScopeInfo) string {
+	switch s.GetType() {
+	case scope.Org.String():
+		return s.GetId()
+	case scope.Project.String():
+		return s.GetParentScopeId()
+	}
+	return ""
+}
+
+// countRequest counts the request against the quota of the organization it's
+// made in, the first time the request is verified. Requests made in the
+// global scope aren't counted.
+func (v *verifier) countRequest(s *scopes.ScopeInfo) error {
+	if v.requestInfo.Quotas == nil || v.quotaCounted {
+		return nil
+	}
+	v.quotaCounted = true
+	orgId := OrgId(s)
+	if orgId == "" {
+		return nil
+	}
+	return v.requestInfo.Quotas.CountRequest(orgId)
+}
+
+// checkSelf returns whether an action is allowed by results, and whether it
+// is only allowed because the resource, owned by ownerId, belongs to userId.
+func checkSelf(results perms.ACLResults, ownerId, userId string) (allowed, onlySelf bool) {
+	switch {
+	case !results.Allowed:
+		return false, false
+	case !results.OnlySelf:
+		return true, false
+	case ownerId != "" && ownerId == userId:
+		return true, true
+	}
+	return false, false
+}
+
+// recordAudit records the results of the first call to Verify for the
+// request's audit event.
+func (v *verifier) recordAudit(ret *VerifyResults, opts options) {
+	if v.audit != nil {
+		return
+	}
+	if info, ok := event.RequestInfoFromContext(v.ctx); ok {
+		info.UserId, info.AuthTokenId = ret.UserId, ret.AuthTokenId
+	}
+	v.audit = &AuditInfo{
+		UserId:      ret.UserId,
+		AuthTokenId: ret.AuthTokenId,
+		Scope:       ret.Scope,
+		Resource: perms.Resource{
+			ScopeId: opts.withScopeId,
+			Id:      opts.withId,
+			Pin:     opts.withPin,
+			Type:    opts.withType,
+		},
+		Action:        opts.withAction,
+		Allowed:       ret.Error == nil,
+		RecoveryNonce: v.recoveryNonce,
+	}
+}
+
+// writeInvalidToken writes a security event for a request made with an auth
+// token which isn't valid, for the reason given.
+func (v *verifier) writeInvalidToken(reason string) {
+	event.WriteSecurity(v.ctx, "auth.(verifier).writeInvalidToken", map[string]interface{}{
+		"kind":          event.InvalidAuthTokenKind,
+		"auth_token_id": v.requestInfo.PublicId,
+		"reason":        reason,
+	})
+}
+
+// writeSignInRestricted writes a security event for a request made with a
+// valid auth token from an address the token's scope doesn't allow signing in
+// from.
+func (v *verifier) writeSignInRestricted(scopeId, authMethodId string) {
+	event.WriteSecurity(v.ctx, "auth.(verifier).writeSignInRestricted", map[string]interface{}{
+		"kind":           event.SignInRestrictedKind,
+		"auth_token_id":  v.requestInfo.PublicId,
+		"scope_id":       scopeId,
+		"auth_method_id": authMethodId,
+	})
+}
+
+// clientIp returns the address of the client which made the request, if
+// known.
+func (v *verifier) clientIp() string {
+	if info, ok := event.RequestInfoFromContext(v.ctx); ok {
+		return info.ClientIp
+	}
+	return ""
+}
+
+// writeDenied writes a security event for a request which ret denied
+// performing act on res. The decision is "unauthenticated" if the request was
+// made as the anonymous user and "forbidden" otherwise.
+func (v *verifier) writeDenied(ret *VerifyResults, res perms.Resource, act action.Type) {
+	decision := "forbidden"
+	if ret.UserId == "u_anon" {
+		decision = "unauthenticated"
+	}
+	event.WriteSecurity(v.ctx, "auth.(verifier).writeDenied", map[string]interface{}{
+		"kind":          event.AuthorizationDeniedKind,
+		"user_id":       ret.UserId,
+		"auth_token_id": ret.AuthTokenId,
+		"scope_id":      res.ScopeId,
+		"resource_id":   res.Id,
+		"resource_type": res.Type.String(),
+		"action":        act.String(),
+		"decision":      decision,
+	})
+}
+
+// allowed returns whether acl, the ACL of the request's user, allows act on
+// res, and if the request was made with an api token, whether the token's
+// grants allow it too.
+func (v *verifier) allowed(acl perms.ACL, res perms.Resource, act action.Type) perms.ACLResults {
+	results := acl.Allowed(res, act)
+	if !results.Allowed {
+		return results
+	}
+	if tokenAcl, ok := v.apiTokenACL(res.ScopeId); ok {
+		tokenResults := tokenAcl.Allowed(res, act)
+		results.Allowed = tokenResults.Allowed
+		results.OnlySelf = results.OnlySelf || tokenResults.OnlySelf
+	}
+	return results
+}
+
+// apiTokenACL returns an ACL of the grants of the api token the request was
+// made with, applied in scopeId, and false if the request wasn't made with
+// one. An api token's grants restrict requests in every scope, so they're
+// applied in whichever scope is being checked. Grants which can't be parsed
+// allow nothing.
+func (v *verifier) apiTokenACL(scopeId string) (perms.ACL, bool) {
+	if v.apiToken == nil {
+		return perms.ACL{}, false
+	}
+	grants := make([]perms.Grant, 0, len(v.apiToken.GetGrants()))
+	for _, g := range v.apiToken.GetGrants() {
+		parsed, err := perms.Parse(
+			scopeId,
+			g,
+			perms.WithUserId(v.apiToken.GetIamUserId()),
+			perms.WithAccountId(v.apiToken.GetAuthAccountId()),
+			perms.WithSkipFinalValidation(true))
+		if err != nil {
+			v.logger.Error("api token acl: failed to parse grant", "error", err, "token_id", v.apiToken.GetPublicId())
+			continue
+		}
+		grants = append(grants, parsed)
+	}
+	return perms.NewACL(grants...), true
+}
+
+func (v *verifier) performAuthCheck() (aclResults perms.ACLResults, userId string, scopeInfo *scopes.ScopeInfo, retAcl perms.ACL, retErr error) {
 	// Ensure we return an error by default if we forget to set this somewhere
 	retErr = errors.New("unknown")
 	// Make the linter happy
@@ -294,6 +541,12 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 	userId = "u_anon"
 	var accountId string
 
+	iamRepo, err := v.iamRepoFn()
+	if err != nil {
+		retErr = fmt.Errorf("perform auth check: failed to get iam repo: %w", err)
+		return
+	}
+
 	// Validate the token and fetch the corresponding user ID
 	switch v.requestInfo.TokenFormat {
 	case AuthTokenTypeUnknown:
@@ -319,8 +572,26 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 			// Continue as the anonymous user as maybe this token is expired but
 			// we can still perform the action
 			v.logger.Error("perform auth check: error validating token; continuing as anonymous user", "error", err)
+			v.writeInvalidToken(err.Error())
 			break
 		}
+		if at == nil {
+			v.writeInvalidToken("token not found or expired")
+		}
+		if at != nil {
+			// Tokens can only be used from the addresses their scope allows
+			// signing in from
+			allowed, err := iamRepo.SignInAllowed(v.ctx, at.GetScopeId(), at.GetAuthMethodId(), v.clientIp())
+			if err != nil {
+				retErr = fmt.Errorf("perform auth check: failed to check sign in cidrs: %w", err)
+				return
+			}
+			if !allowed {
+				v.logger.Warn("perform auth check: token used from an address its scope doesn't allow signing in from; continuing as anonymous user", "token_id", at.GetPublicId())
+				v.writeSignInRestricted(at.GetScopeId(), at.GetAuthMethodId())
+				at = nil
+			}
+		}
 		if at != nil {
 			accountId = at.GetAuthAccountId()
 			userId = at.GetIamUserId()
@@ -329,15 +600,12 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 				userId = "u_anon"
 				accountId = ""
 			}
+			if at.GetApi() {
+				v.apiToken = at
+			}
 		}
 	}
 
-	iamRepo, err := v.iamRepoFn()
-	if err != nil {
-		retErr = fmt.Errorf("perform auth check: failed to get iam repo: %w", err)
-		return
-	}
-
 	// Look up scope details to return. We can skip a lookup when using the
 	// global scope
 	switch v.res.ScopeId {
@@ -402,7 +670,7 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 	}
 
 	retAcl = perms.NewACL(parsedGrants...)
-	aclResults = retAcl.Allowed(*v.res, v.act)
+	aclResults = v.allowed(retAcl, *v.res, v.act)
 	retErr = nil
 	return
 }
@@ -582,6 +850,7 @@ func (v *verifier) decryptToken() {
 			v.requestInfo.TokenFormat = AuthTokenTypeUnknown
 			return
 		}
+		v.recoveryNonce = info.Nonce
 		v.logger.Warn("recovery KMS was used to authorize a call", "url", v.requestInfo.Path, "method", v.requestInfo.Method)
 	}
 }
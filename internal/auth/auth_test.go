@@ -2,17 +2,27 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/authtoken/store"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/quota"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -133,3 +143,141 @@ func TestAuthTokenAuthenticator(t *testing.T) {
 		})
 	}
 }
+
+func TestVerify_signInCidrs(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	logger := hclog.New(nil)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	tokenRepo, err := authtoken.NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	tokenRepoFn := func() (*authtoken.Repository, error) {
+		return tokenRepo, nil
+	}
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iamRepo, nil
+	}
+	serversRepoFn := func() (*servers.Repository, error) {
+		return servers.NewRepository(rw, rw, kms)
+	}
+
+	o, _ := iam.TestScopes(t, iamRepo)
+	at := authtoken.TestAuthToken(t, conn, kms, o.GetPublicId())
+	encToken, err := authtoken.EncryptToken(context.Background(), kms, o.GetPublicId(), at.GetPublicId(), at.GetToken())
+	require.NoError(t, err)
+	tokValue := at.GetPublicId() + "_" + encToken
+
+	_, err = iamRepo.CreateSignInCidr(context.Background(), &iam.SignInCidr{ScopeId: o.GetPublicId(), Cidr: "10.0.0.0/8"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		clientIp string
+		userId   string
+	}{
+		{name: "allowed", clientIp: "10.1.2.3", userId: at.GetIamUserId()},
+		{name: "restricted", clientIp: "192.168.1.1", userId: "u_anon"},
+		{name: "unknown", userId: "u_anon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://127.0.0.1/v1/scopes/"+o.GetPublicId(), nil)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokValue))
+			requestInfo := RequestInfo{
+				Path:   req.URL.Path,
+				Method: req.Method,
+			}
+			requestInfo.PublicId, requestInfo.EncryptedToken, requestInfo.TokenFormat = GetTokenFromRequest(logger, kms, req)
+
+			ctx := event.NewRequestInfoContext(context.Background(), &event.RequestInfo{Id: "req_1234567890", ClientIp: tt.clientIp})
+			ctx = NewVerifierContext(ctx, logger, iamRepoFn, tokenRepoFn, serversRepoFn, kms, requestInfo)
+			res := Verify(ctx, WithScopeId(o.GetPublicId()), WithId(o.GetPublicId()), WithType(resource.Scope), WithAction(action.Read))
+			assert.Equal(t, tt.userId, res.UserId)
+		})
+	}
+}
+
+// quotaStore is a quota.Store which keeps no counts.
+type quotaStore struct{}
+
+func (quotaStore) AddRequests(context.Context, string, time.Time, uint64) (uint64, error) {
+	return 0, nil
+}
+
+func (quotaStore) RequestCount(context.Context, string, time.Time) (uint64, error) {
+	return 0, nil
+}
+
+func (quotaStore) ActiveSessionCount(context.Context, string) (uint64, error) {
+	return 0, nil
+}
+
+func TestVerifier_countRequest(t *testing.T) {
+	ctx := context.Background()
+	quotas, err := quota.NewAccountant(quotaStore{}, &quota.Config{MaxRequests: 2})
+	require.NoError(t, err)
+
+	org := &scopes.ScopeInfo{Id: "o_1234567890", Type: scope.Org.String(), ParentScopeId: scope.Global.String()}
+	proj := &scopes.ScopeInfo{Id: "p_1234567890", Type: scope.Project.String(), ParentScopeId: org.Id}
+	global := &scopes.ScopeInfo{Id: scope.Global.String(), Type: scope.Global.String()}
+
+	// Requests in the global scope aren't counted
+	v := &verifier{requestInfo: RequestInfo{Quotas: quotas}}
+	require.NoError(t, v.countRequest(global))
+
+	// A request is only counted once, however many times it's verified
+	v = &verifier{requestInfo: RequestInfo{Quotas: quotas}}
+	require.NoError(t, v.countRequest(proj))
+	require.NoError(t, v.countRequest(proj))
+
+	v = &verifier{requestInfo: RequestInfo{Quotas: quotas}}
+	require.NoError(t, v.countRequest(org))
+
+	u, err := quotas.Usage(ctx, org.Id)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, u.Requests)
+
+	v = &verifier{requestInfo: RequestInfo{Quotas: quotas}}
+	err = v.countRequest(proj)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, quota.ErrExceeded))
+}
+
+func TestVerifier_apiToken(t *testing.T) {
+	grant, err := perms.Parse("p_1234567890", "id=*;type=*;actions=*")
+	require.NoError(t, err)
+	userAcl := perms.NewACL(grant)
+	target := perms.Resource{ScopeId: "p_1234567890", Id: "ttcp_1234567890", Type: resource.Target}
+	session := perms.Resource{ScopeId: "p_1234567890", Id: "s_1234567890", Type: resource.Session}
+
+	// Without an api token only the user's grants are checked
+	v := &verifier{logger: hclog.NewNullLogger()}
+	assert.True(t, v.allowed(userAcl, target, action.Delete).Allowed)
+
+	v.apiToken = &authtoken.AuthToken{AuthToken: &store.AuthToken{
+		PublicId:  "at_1234567890",
+		IamUserId: "u_1234567890",
+		Api:       true,
+		Grants: []string{
+			"id=*;type=target;actions=read,authorize-session",
+			"id=*;type=session;actions=read:self",
+		},
+	}}
+	assert.True(t, v.allowed(userAcl, target, action.AuthorizeSession).Allowed)
+	assert.False(t, v.allowed(userAcl, target, action.Delete).Allowed)
+	results := v.allowed(userAcl, session, action.Read)
+	assert.True(t, results.Allowed)
+	assert.True(t, results.OnlySelf)
+
+	// The token's grants don't allow anything the user's grants don't
+	other := perms.Resource{ScopeId: "p_0987654321", Id: "ttcp_0987654321", Type: resource.Target}
+	assert.False(t, v.allowed(userAcl, other, action.AuthorizeSession).Allowed)
+
+	v.acl = userAcl
+	r := &VerifyResults{v: v}
+	assert.Equal(t, perms.ResourcePermissions{All: true}, r.ResourcePermissions("p_1234567890", resource.Target, action.Read))
+	assert.Equal(t, perms.ResourcePermissions{}, r.ResourcePermissions("p_1234567890", resource.Target, action.Delete))
+	assert.Equal(t, perms.ResourcePermissions{OnlySelf: true}, r.ResourcePermissions("p_1234567890", resource.Session, action.Read))
+}
@@ -25,6 +25,7 @@ type options struct {
 	withAction  action.Type
 	withType    resource.Type
 	withUserId  string
+	withOwnerId string
 	withKms     *kms.Kms
 }
 
@@ -71,6 +72,15 @@ func WithUserId(id string) Option {
 	}
 }
 
+// WithOwnerId provides the id of the user the resource belongs to. Actions
+// granted only on a user's own resources, such as through read:self, are
+// allowed if it is the requesting user.
+func WithOwnerId(id string) Option {
+	return func(o *options) {
+		o.withOwnerId = id
+	}
+}
+
 func WithKms(kms *kms.Kms) Option {
 	return func(o *options) {
 		o.withKms = kms
@@ -66,6 +66,20 @@ func (a *Account) SetTableName(n string) {
 	a.tableName = n
 }
 
+// Attributes returns the attributes of the account which are matched by the
+// attribute rules of its scope when it authenticates: its login_name,
+// auth_method_id, and its name if it has one.
+func (a *Account) Attributes() map[string]interface{} {
+	attrs := map[string]interface{}{
+		"login_name":     a.GetLoginName(),
+		"auth_method_id": a.GetAuthMethodId(),
+	}
+	if a.GetName() != "" {
+		attrs["name"] = a.GetName()
+	}
+	return attrs
+}
+
 func (a *Account) oplog(op oplog.OpType) oplog.Metadata {
 	metadata := oplog.Metadata{
 		"resource-public-id": []string{a.GetPublicId()},
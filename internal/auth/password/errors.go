@@ -18,4 +18,9 @@ var (
 	// ErrPasswordsEqual is returned from ChangePassword when the old and
 	// new passwords are equal.
 	ErrPasswordsEqual = errors.New("old and new password are equal")
+
+	// ErrMustChangePassword is returned from Authenticate when the password
+	// was set by an administrator with WithMustChangePassword and has not
+	// been changed with ChangePassword since.
+	ErrMustChangePassword = errors.New("password must be changed")
 )
@@ -18,10 +18,13 @@ type options struct {
 	withDescription string
 	withLoginName   string
 	withLimit       int
+	withOrder       string
 	withConfig      Configuration
 	withPublicId    string
 	password        string
 	withPassword    bool
+
+	withMustChangePassword bool
 }
 
 func getDefaultOptions() options {
@@ -67,6 +70,14 @@ func WithLimit(l int) Option {
 	}
 }
 
+// WithOrder provides an option to order the results of a listing by a column
+// of the listed resources, such as "create_time desc".
+func WithOrder(order string) Option {
+	return func(o *options) {
+		o.withOrder = order
+	}
+}
+
 // WithPassword provides an optional password.
 func WithPassword(password string) Option {
 	return func(o *options) {
@@ -81,3 +92,12 @@ func WithConfiguration(config Configuration) Option {
 		o.withConfig = config
 	}
 }
+
+// WithMustChangePassword provides an option to require the password set by
+// SetPassword to be changed with ChangePassword before the account can
+// authenticate.
+func WithMustChangePassword(mustChange bool) Option {
+	return func(o *options) {
+		o.withMustChangePassword = mustChange
+	}
+}
@@ -57,4 +57,11 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withConfig = c
 		assert.Equal(t, opts, testOpts)
 	})
+	t.Run("WithOrder", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithOrder("create_time desc"))
+		testOpts := getDefaultOptions()
+		testOpts.withOrder = "create_time desc"
+		assert.Equal(opts, testOpts)
+	})
 }
@@ -3,7 +3,7 @@ package password
 import (
 	"fmt"
 
-	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/types/publicid"
 )
 
 // PublicId prefixes for the resources in the password package.
@@ -13,7 +13,7 @@ const (
 )
 
 func newAuthMethodId() (string, error) {
-	id, err := db.NewPublicId(AuthMethodPrefix)
+	id, err := publicid.New(AuthMethodPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new password auth method id: %w", err)
 	}
@@ -21,7 +21,7 @@ func newAuthMethodId() (string, error) {
 }
 
 func newAccountId() (string, error) {
-	id, err := db.NewPublicId(AccountPrefix)
+	id, err := publicid.New(AccountPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new password account id: %w", err)
 	}
@@ -30,6 +30,10 @@ type authAccount struct {
 // Authenticate will update the stored values for password to the current
 // password settings for authMethodId if authentication is successful and
 // the stored values are not using the current password settings.
+//
+// Returns nil, ErrMustChangePassword if the password matches but was set by
+// an administrator with WithMustChangePassword and has not been changed
+// since.
 func (r *Repository) Authenticate(ctx context.Context, scopeId, authMethodId, loginName, password string) (*Account, error) {
 	if authMethodId == "" {
 		return nil, fmt.Errorf("password authenticate: no authMethodId: %w", db.ErrInvalidParameter)
@@ -94,12 +98,16 @@ func (r *Repository) Authenticate(ctx context.Context, scopeId, authMethodId, lo
 			return acct.Account, fmt.Errorf("password authenticate: update credential: %w", err)
 		}
 	}
+	if acct.MustChangePassword {
+		return nil, fmt.Errorf("password authenticate: %s: %w", acct.PublicId, ErrMustChangePassword)
+	}
 	return acct.Account, nil
 }
 
 // ChangePassword updates the password for accountId to new if old equals
-// the stored password. The account for the accountId is returned with a
-// new CredentialId if password is successfully changed.
+// the stored password and new satisfies the auth method's password policy.
+// The account for the accountId is returned with a new CredentialId if
+// password is successfully changed, and no longer must change its password.
 //
 // Returns nil, db.ErrorRecordNotFound if the account doesn't exist.
 // Returns nil, nil if old does not match the stored password for accountId.
@@ -173,7 +181,8 @@ func (r *Repository) ChangePassword(ctx context.Context, scopeId, accountId, old
 			updatedAccount = allocAccount()
 			updatedAccount.PublicId = accountId
 			updatedAccount.Version = version + 1
-			rowsUpdated, err := w.Update(ctx, updatedAccount, []string{"Version"}, nil, db.WithOplog(oplogWrapper, acct.Account.oplog(oplog.OpType_OP_TYPE_UPDATE)), db.WithVersion(&version))
+			updatedAccount.MustChangePassword = false
+			rowsUpdated, err := w.Update(ctx, updatedAccount, []string{"Version", "MustChangePassword"}, nil, db.WithOplog(oplogWrapper, acct.Account.oplog(oplog.OpType_OP_TYPE_UPDATE)), db.WithVersion(&version))
 			if err != nil {
 				return fmt.Errorf("change password: unable to update account version: %w", err)
 			}
@@ -247,7 +256,12 @@ func (r *Repository) authenticate(ctx context.Context, scopeId, authMethodId, lo
 
 // SetPassword sets the password for accountId to password. If password
 // contains an empty string, the password for accountId will be deleted.
-func (r *Repository) SetPassword(ctx context.Context, scopeId, accountId, password string, version uint32) (*Account, error) {
+//
+// Supports the WithMustChangePassword option, which requires the user to
+// change the password with ChangePassword before the account can
+// authenticate. Setting the password without the option clears the
+// requirement.
+func (r *Repository) SetPassword(ctx context.Context, scopeId, accountId, password string, version uint32, opt ...Option) (*Account, error) {
 	if accountId == "" {
 		return nil, fmt.Errorf("set password: no accountId: %w", db.ErrInvalidParameter)
 	}
@@ -258,6 +272,11 @@ func (r *Repository) SetPassword(ctx context.Context, scopeId, accountId, passwo
 		return nil, fmt.Errorf("set password: no scopeId: %w", db.ErrInvalidParameter)
 	}
 
+	opts := getOpts(opt...)
+	if opts.withMustChangePassword && password == "" {
+		return nil, fmt.Errorf("set password: must change password requires a password: %w", db.ErrInvalidParameter)
+	}
+
 	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
 	if err != nil {
 		return nil, fmt.Errorf("set password: unable to get oplog wrapper: %w", err)
@@ -294,7 +313,8 @@ func (r *Repository) SetPassword(ctx context.Context, scopeId, accountId, passwo
 			updatedAccount := allocAccount()
 			updatedAccount.PublicId = accountId
 			updatedAccount.Version = version + 1
-			rowsUpdated, err := w.Update(ctx, updatedAccount, []string{"Version"}, nil, db.WithOplog(oplogWrapper, updatedAccount.oplog(oplog.OpType_OP_TYPE_UPDATE)), db.WithVersion(&version))
+			updatedAccount.MustChangePassword = opts.withMustChangePassword
+			rowsUpdated, err := w.Update(ctx, updatedAccount, []string{"Version", "MustChangePassword"}, nil, db.WithOplog(oplogWrapper, updatedAccount.oplog(oplog.OpType_OP_TYPE_UPDATE)), db.WithVersion(&version))
 			if err != nil {
 				return fmt.Errorf("set password: unable to update account version: %w", err)
 			}
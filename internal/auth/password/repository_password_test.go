@@ -540,3 +540,65 @@ func TestRepository_SetPassword(t *testing.T) {
 	}
 
 }
+
+func TestRepository_SetPassword_MustChangePassword(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	o, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	authMethod := TestAuthMethods(t, conn, o.GetPublicId(), 1)[0]
+	ctx := context.Background()
+
+	t.Run("reset-then-change", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		inAcct := &Account{
+			Account: &store.Account{
+				AuthMethodId: authMethod.PublicId,
+				LoginName:    "mustchange",
+			},
+		}
+		acct, err := repo.CreateAccount(ctx, o.GetPublicId(), inAcct, WithPassword("12345678"))
+		require.NoError(err)
+		require.NotNil(acct)
+
+		reset := "resetpassword"
+		acct, err = repo.SetPassword(ctx, o.GetPublicId(), acct.PublicId, reset, acct.Version, WithMustChangePassword(true))
+		require.NoError(err)
+		require.NotNil(acct)
+		assert.NoError(db.TestVerifyOplog(t, rw, acct.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_UPDATE), db.WithCreateNotBefore(10*time.Second)))
+
+		authAcct, err := repo.Authenticate(ctx, o.GetPublicId(), authMethod.PublicId, "mustchange", reset)
+		assert.Truef(errors.Is(err, ErrMustChangePassword), "want err: %q got: %q", ErrMustChangePassword, err)
+		assert.Nil(authAcct)
+
+		// A wrong password is still rejected without revealing the
+		// requirement
+		authAcct, err = repo.Authenticate(ctx, o.GetPublicId(), authMethod.PublicId, "mustchange", "wrongpassword")
+		assert.NoError(err)
+		assert.Nil(authAcct)
+
+		changed, err := repo.ChangePassword(ctx, o.GetPublicId(), acct.PublicId, reset, "newpassword", acct.Version)
+		require.NoError(err)
+		require.NotNil(changed)
+		assert.False(changed.MustChangePassword)
+
+		authAcct, err = repo.Authenticate(ctx, o.GetPublicId(), authMethod.PublicId, "mustchange", "newpassword")
+		require.NoError(err)
+		require.NotNil(authAcct)
+		assert.False(authAcct.MustChangePassword)
+	})
+
+	t.Run("requires-password", func(t *testing.T) {
+		assert := assert.New(t)
+		acct := TestAccounts(t, conn, authMethod.PublicId, 1)[0]
+		got, err := repo.SetPassword(ctx, o.GetPublicId(), acct.PublicId, "", acct.Version, WithMustChangePassword(true))
+		assert.Truef(errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		assert.Nil(got)
+	})
+}
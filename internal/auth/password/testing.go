@@ -14,7 +14,7 @@ import (
 // TestAuthMethods creates count number of password auth methods to the provided DB
 // with the provided scope id.  If any errors are encountered during the creation of
 // the auth methods, the test will fail.
-func TestAuthMethods(t *testing.T, conn *gorm.DB, scopeId string, count int) []*AuthMethod {
+func TestAuthMethods(t testing.TB, conn *gorm.DB, scopeId string, count int) []*AuthMethod {
 	t.Helper()
 	assert, require := assert.New(t), require.New(t)
 	w := db.New(conn)
@@ -52,7 +52,7 @@ func TestAuthMethods(t *testing.T, conn *gorm.DB, scopeId string, count int) []*
 // TestAccounts creates count number of password account to the provided DB
 // with the provided auth method id.  The auth method must have been created previously.
 // If any errors are encountered during the creation of the account, the test will fail.
-func TestAccounts(t *testing.T, conn *gorm.DB, authMethodId string, count int) []*Account {
+func TestAccounts(t testing.TB, conn *gorm.DB, authMethodId string, count int) []*Account {
 	t.Helper()
 	assert, require := assert.New(t), require.New(t)
 	w := db.New(conn)
@@ -0,0 +1,78 @@
+package authtoken
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// AccessRecorder collects the ids of the auth tokens validated since it was
+// last flushed, so their approximate last access times can be updated in one
+// write instead of one write per validation. It is safe for concurrent use,
+// and is shared by the repositories given it with WithAccessRecorder.
+type AccessRecorder struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+// NewAccessRecorder creates an empty AccessRecorder.
+func NewAccessRecorder() *AccessRecorder {
+	return &AccessRecorder{ids: make(map[string]struct{})}
+}
+
+// Record notes that the auth token with id was accessed.
+func (a *AccessRecorder) Record(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ids[id] = struct{}{}
+}
+
+// take returns the recorded ids and clears them.
+func (a *AccessRecorder) take() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.ids) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(a.ids))
+	for id := range a.ids {
+		ids = append(ids, id)
+	}
+	a.ids = make(map[string]struct{})
+	return ids
+}
+
+// FlushAccessTimes sets the approximate last access time of the auth tokens
+// recorded by the repository's AccessRecorder to now and returns how many
+// were updated. Tokens deleted since they were recorded are skipped. If the
+// update fails the ids are recorded again so the next flush retries them.
+func (r *Repository) FlushAccessTimes(ctx context.Context) (int, error) {
+	if r.accessRecorder == nil {
+		return db.NoRowsAffected, fmt.Errorf("flush access times: no access recorder: %w", db.ErrInvalidParameter)
+	}
+	ids := r.accessRecorder.take()
+	if len(ids) == 0 {
+		return db.NoRowsAffected, nil
+	}
+	var rowsUpdated int
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			var err error
+			// Tokens are not replicated, so they don't need oplog entries.
+			rowsUpdated, err = w.Exec(ctx, flushAccessTimesQuery, []interface{}{ids})
+			return err
+		},
+	)
+	if err != nil {
+		for _, id := range ids {
+			r.accessRecorder.Record(id)
+		}
+		return db.NoRowsAffected, fmt.Errorf("flush access times: %w", err)
+	}
+	return rowsUpdated, nil
+}
@@ -6,9 +6,10 @@ import (
 	mathrand "math/rand"
 	"time"
 
+	"github.com/hashicorp/boundary/internal/types/publicid"
+
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/authtoken/store"
-	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/gen/controller/tokens"
 	"github.com/hashicorp/boundary/internal/kms"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
@@ -86,7 +87,7 @@ const (
 )
 
 func newAuthTokenId() (string, error) {
-	id, err := db.NewPublicId(AuthTokenPrefix)
+	id, err := publicid.New(AuthTokenPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new auth token id: %w", err)
 	}
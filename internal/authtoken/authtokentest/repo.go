@@ -0,0 +1,172 @@
+// Package authtokentest provides an in-memory implementation of
+// authtoken.Repo, so that code using auth token repositories can be tested
+// without a database.
+package authtokentest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/authtoken/store"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// tokenDuration is how long tokens are valid for, as in authtoken.Repository.
+const tokenDuration = 7 * 24 * time.Hour
+
+// Repo is an in-memory authtoken.Repo. Tokens are created in the scope of
+// their user, and the accounts they are created for aren't checked. Options
+// are ignored. It is safe for concurrent use.
+type Repo struct {
+	l      sync.Mutex
+	tokens map[string]*store.AuthToken
+}
+
+var _ authtoken.Repo = (*Repo)(nil)
+
+// NewRepo creates an empty Repo.
+func NewRepo() *Repo {
+	return &Repo{
+		tokens: make(map[string]*store.AuthToken),
+	}
+}
+
+// CreateAuthToken implements authtoken.Repo.
+func (r *Repo) CreateAuthToken(ctx context.Context, withIamUser *iam.User, withAuthAccountId string, opt ...authtoken.Option) (*authtoken.AuthToken, error) {
+	if withIamUser == nil {
+		return nil, fmt.Errorf("create: auth token: no user: %w", db.ErrInvalidParameter)
+	}
+	if withIamUser.GetPublicId() == "" {
+		return nil, fmt.Errorf("create: auth token: no user id: %w", db.ErrInvalidParameter)
+	}
+	if withAuthAccountId == "" {
+		return nil, fmt.Errorf("create: auth token: no auth account id: %w", db.ErrInvalidParameter)
+	}
+	id, err := db.NewPublicId(authtoken.AuthTokenPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create: auth token id: %w", err)
+	}
+	token, err := base62.Random(24)
+	if err != nil {
+		return nil, fmt.Errorf("create: auth token value: %w", err)
+	}
+	at := &store.AuthToken{
+		PublicId:                  id,
+		Token:                     authtoken.TokenValueVersionPrefix + token,
+		AuthAccountId:             withAuthAccountId,
+		ScopeId:                   withIamUser.GetScopeId(),
+		IamUserId:                 withIamUser.GetPublicId(),
+		CreateTime:                now(),
+		UpdateTime:                now(),
+		ApproximateLastAccessTime: now(),
+		ExpirationTime:            &timestamp.Timestamp{Timestamp: timestamppb.New(time.Now().Add(tokenDuration).Truncate(time.Second))},
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.tokens[id] = at
+	return &authtoken.AuthToken{AuthToken: proto.Clone(at).(*store.AuthToken)}, nil
+}
+
+// SetExpirationTime changes when the token with id expires, so that tests can
+// expire tokens.
+func (r *Repo) SetExpirationTime(id string, t time.Time) error {
+	r.l.Lock()
+	defer r.l.Unlock()
+	at, ok := r.tokens[id]
+	if !ok {
+		return fmt.Errorf("set expiration time: auth token %s: %w", id, db.ErrRecordNotFound)
+	}
+	at.ExpirationTime = &timestamp.Timestamp{Timestamp: timestamppb.New(t)}
+	return nil
+}
+
+// LookupAuthToken implements authtoken.Repo.
+func (r *Repo) LookupAuthToken(ctx context.Context, id string, opt ...authtoken.Option) (*authtoken.AuthToken, error) {
+	if id == "" {
+		return nil, fmt.Errorf("lookup: auth token: missing public id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	at, ok := r.tokens[id]
+	if !ok {
+		return nil, nil
+	}
+	return withoutToken(at), nil
+}
+
+// ValidateToken implements authtoken.Repo. Expired tokens are deleted.
+func (r *Repo) ValidateToken(ctx context.Context, id, token string, opt ...authtoken.Option) (*authtoken.AuthToken, error) {
+	if token == "" {
+		return nil, fmt.Errorf("validate token: auth token: missing token: %w", db.ErrInvalidParameter)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("validate token: auth token: missing public id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	at, ok := r.tokens[id]
+	if !ok {
+		return nil, nil
+	}
+	if time.Now().After(at.GetExpirationTime().GetTimestamp().AsTime()) {
+		delete(r.tokens, id)
+		return nil, nil
+	}
+	if at.Token != token {
+		return nil, nil
+	}
+	at.ApproximateLastAccessTime = now()
+	return withoutToken(at), nil
+}
+
+// ListAuthTokens implements authtoken.Repo. Tokens are ordered by public id.
+func (r *Repo) ListAuthTokens(ctx context.Context, withOrgId string, opt ...authtoken.Option) ([]*authtoken.AuthToken, error) {
+	if withOrgId == "" {
+		return nil, fmt.Errorf("list users: missing org id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	var tokens []*authtoken.AuthToken
+	for _, at := range r.tokens {
+		if at.ScopeId == withOrgId {
+			tokens = append(tokens, withoutToken(at))
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].PublicId < tokens[j].PublicId })
+	return tokens, nil
+}
+
+// DeleteAuthToken implements authtoken.Repo.
+func (r *Repo) DeleteAuthToken(ctx context.Context, id string, opt ...authtoken.Option) (int, error) {
+	if id == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete: auth token: missing public id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.tokens[id]; !ok {
+		return db.NoRowsAffected, nil
+	}
+	delete(r.tokens, id)
+	return 1, nil
+}
+
+// withoutToken returns a copy of at without its token value, as the
+// repository returns tokens after they are created.
+func withoutToken(at *store.AuthToken) *authtoken.AuthToken {
+	cp := proto.Clone(at).(*store.AuthToken)
+	cp.Token = ""
+	return &authtoken.AuthToken{AuthToken: cp}
+}
+
+func now() *timestamp.Timestamp {
+	return &timestamp.Timestamp{Timestamp: timestamppb.Now()}
+}
@@ -0,0 +1,52 @@
+package authtokentest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	repo := NewRepo()
+	u, err := iam.NewUser("o_1234567890")
+	require.NoError(err)
+	u.PublicId = "u_1234567890"
+
+	at, err := repo.CreateAuthToken(ctx, u, "apw_1234567890")
+	require.NoError(err)
+	db.AssertPublicId(t, authtoken.AuthTokenPrefix, at.PublicId)
+	assert.NotEmpty(at.Token)
+
+	got, err := repo.LookupAuthToken(ctx, at.PublicId)
+	require.NoError(err)
+	assert.Empty(got.Token)
+	assert.Equal(u.PublicId, got.IamUserId)
+
+	got, err = repo.ValidateToken(ctx, at.PublicId, at.Token)
+	require.NoError(err)
+	assert.NotNil(got)
+	got, err = repo.ValidateToken(ctx, at.PublicId, "wrong")
+	require.NoError(err)
+	assert.Nil(got)
+
+	tokens, err := repo.ListAuthTokens(ctx, u.ScopeId)
+	require.NoError(err)
+	assert.Len(tokens, 1)
+
+	// Expired tokens are deleted when validated
+	require.NoError(repo.SetExpirationTime(at.PublicId, time.Now().Add(-time.Minute)))
+	got, err = repo.ValidateToken(ctx, at.PublicId, at.Token)
+	require.NoError(err)
+	assert.Nil(got)
+	deleted, err := repo.DeleteAuthToken(ctx, at.PublicId)
+	require.NoError(err)
+	assert.Equal(0, deleted)
+}
@@ -1,5 +1,7 @@
 package authtoken
 
+import "time"
+
 // getOpts - iterate the inbound Options and return a struct
 func getOpts(opt ...Option) options {
 	opts := getDefaultOptions()
@@ -14,8 +16,12 @@ type Option func(*options)
 
 // options = how options are represented
 type options struct {
-	withTokenValue bool
-	withLimit      int
+	withTokenValue     bool
+	withLimit          int
+	withOrder          string
+	withUserId         string
+	withIdleLongerThan time.Duration
+	withAccessRecorder *AccessRecorder
 }
 
 func getDefaultOptions() options {
@@ -38,3 +44,37 @@ func WithLimit(limit int) Option {
 		o.withLimit = limit
 	}
 }
+
+// WithOrder provides an option to order the results of a listing by a column
+// of the listed resources, such as "create_time desc".
+func WithOrder(order string) Option {
+	return func(o *options) {
+		o.withOrder = order
+	}
+}
+
+// WithUserId restricts a listing to the auth tokens of the user with the
+// provided id.
+func WithUserId(id string) Option {
+	return func(o *options) {
+		o.withUserId = id
+	}
+}
+
+// WithIdleLongerThan restricts a listing to the auth tokens which haven't
+// been used for longer than d.
+func WithIdleLongerThan(d time.Duration) Option {
+	return func(o *options) {
+		o.withIdleLongerThan = d
+	}
+}
+
+// WithAccessRecorder provides an option for the repository to record the
+// auth tokens it validates in the AccessRecorder, to be written in a batch
+// by FlushAccessTimes, instead of updating their last access times as they
+// are validated.
+func WithAccessRecorder(a *AccessRecorder) Option {
+	return func(o *options) {
+		o.withAccessRecorder = a
+	}
+}
@@ -2,6 +2,7 @@ package authtoken
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,4 +17,26 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withTokenValue = true
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithIdleLongerThan", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithIdleLongerThan(time.Hour))
+		testOpts := getDefaultOptions()
+		testOpts.withIdleLongerThan = time.Hour
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithAccessRecorder", func(t *testing.T) {
+		assert := assert.New(t)
+		a := NewAccessRecorder()
+		opts := getOpts(WithAccessRecorder(a))
+		testOpts := getDefaultOptions()
+		testOpts.withAccessRecorder = a
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithOrder", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithOrder("create_time desc"))
+		testOpts := getDefaultOptions()
+		testOpts.withOrder = "create_time desc"
+		assert.Equal(opts, testOpts)
+	})
 }
@@ -0,0 +1,20 @@
+package authtoken
+
+const (
+	// flushAccessTimesQuery skips expired tokens, whose last access time
+	// can't be later than their expiration time.
+	flushAccessTimesQuery = `
+update auth_token
+   set approximate_last_access_time = now()
+ where public_id in (?)
+   and expiration_time > now();
+`
+
+	// deleteStaleQuery skips idle api tokens, which are only deleted once
+	// they expire.
+	deleteStaleQuery = `
+delete from auth_token
+ where expiration_time <= now()
+    or (not api and approximate_last_access_time <= now() - make_interval(secs => ?));
+`
+)
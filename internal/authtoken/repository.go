@@ -28,10 +28,25 @@ type Repository struct {
 	reader db.Reader
 	writer db.Writer
 	kms    *kms.Kms
+	// accessRecorder, if set, batches updates of last access times
+	accessRecorder *AccessRecorder
 	// defaultLimit provides a default for limiting the number of results returned from the repo
 	defaultLimit int
 }
 
+// Repo is the subset of Repository used to create, validate, look up, list
+// and delete auth tokens. authtokentest.Repo implements it in memory for tests
+// which don't need a database.
+type Repo interface {
+	CreateAuthToken(ctx context.Context, withIamUser *iam.User, withAuthAccountId string, opt ...Option) (*AuthToken, error)
+	LookupAuthToken(ctx context.Context, id string, opt ...Option) (*AuthToken, error)
+	ValidateToken(ctx context.Context, id, token string, opt ...Option) (*AuthToken, error)
+	ListAuthTokens(ctx context.Context, withOrgId string, opt ...Option) ([]*AuthToken, error)
+	DeleteAuthToken(ctx context.Context, id string, opt ...Option) (int, error)
+}
+
+var _ Repo = (*Repository)(nil)
+
 // NewRepository creates a new Repository. The returned repository is not safe for concurrent go
 // routines to access it.
 func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repository, error) {
@@ -50,10 +65,11 @@ func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repo
 		opts.withLimit = db.DefaultLimit
 	}
 	return &Repository{
-		reader:       r,
-		writer:       w,
-		kms:          kms,
-		defaultLimit: opts.withLimit,
+		reader:         r,
+		writer:         w,
+		kms:            kms,
+		accessRecorder: opts.withAccessRecorder,
+		defaultLimit:   opts.withLimit,
 	}, nil
 }
 
@@ -155,6 +171,13 @@ func (r *Repository) LookupAuthToken(ctx context.Context, id string, opt ...Opti
 		}
 		return nil, fmt.Errorf("auth token: lookup: %w", err)
 	}
+	if at.GetApi() {
+		grants, err := r.lookupApiTokenGrants(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("auth token: lookup: %w", err)
+		}
+		at.Grants = grants
+	}
 	if opts.withTokenValue {
 		databaseWrapper, err := r.kms.GetWrapper(ctx, at.GetScopeId(), kms.KeyPurposeDatabase, kms.WithKeyId(at.GetKeyId()))
 		if err != nil {
@@ -211,7 +234,9 @@ func (r *Repository) ValidateToken(ctx context.Context, id, token string, opt ..
 	sinceLastAccessed := now.Sub(lastAccessed) + timeSkew
 	// TODO (jimlambrt 9/2020) - investigate the need for the timeSkew and see
 	// if it can be eliminated.
-	if now.After(exp.Add(-timeSkew)) || sinceLastAccessed >= maxStaleness {
+	// Api tokens aren't deleted for being idle; they're only limited by
+	// their expiration time.
+	if now.After(exp.Add(-timeSkew)) || (!retAT.GetApi() && sinceLastAccessed >= maxStaleness) {
 		// If the token has expired or has become too stale, delete it from the DB.
 		_, err = r.writer.DoTx(
 			ctx,
@@ -242,27 +267,33 @@ func (r *Repository) ValidateToken(ctx context.Context, id, token string, opt ..
 		// To save the db from being updated too frequently, we only update the
 		// LastAccessTime if it hasn't been updated within lastAccessedUpdateDuration.
 		// TODO: Make this duration configurable.
-		_, err = r.writer.DoTx(
-			ctx,
-			db.StdRetryCnt,
-			db.ExpBackoff{},
-			func(_ db.Reader, w db.Writer) error {
-				at := retAT.toWritableAuthToken()
-				// Setting the ApproximateLastAccessTime to null through using the null mask allows a defined db's
-				// trigger to set ApproximateLastAccessTime to the commit
-				// timestamp. Tokens are not replicated, so they don't need oplog entries.
-				rowsUpdated, err := w.Update(
-					ctx,
-					at,
-					nil,
-					[]string{"ApproximateLastAccessTime"},
-				)
-				if err == nil && rowsUpdated > 1 {
-					return db.ErrMultipleRecords
-				}
-				return err
-			},
-		)
+		if r.accessRecorder != nil {
+			// The time is written along with those of other tokens by the
+			// next FlushAccessTimes.
+			r.accessRecorder.Record(retAT.GetPublicId())
+		} else {
+			_, err = r.writer.DoTx(
+				ctx,
+				db.StdRetryCnt,
+				db.ExpBackoff{},
+				func(_ db.Reader, w db.Writer) error {
+					at := retAT.toWritableAuthToken()
+					// Setting the ApproximateLastAccessTime to null through using the null mask allows a defined db's
+					// trigger to set ApproximateLastAccessTime to the commit
+					// timestamp. Tokens are not replicated, so they don't need oplog entries.
+					rowsUpdated, err := w.Update(
+						ctx,
+						at,
+						nil,
+						[]string{"ApproximateLastAccessTime"},
+					)
+					if err == nil && rowsUpdated > 1 {
+						return db.ErrMultipleRecords
+					}
+					return err
+				},
+			)
+		}
 	}
 
 	if err != nil {
@@ -271,7 +302,8 @@ func (r *Repository) ValidateToken(ctx context.Context, id, token string, opt ..
 	return retAT, nil
 }
 
-// ListAuthTokens in an org and supports the WithLimit option.
+// ListAuthTokens in an org and supports the WithLimit, WithOrder, WithUserId
+// and WithIdleLongerThan options.
 func (r *Repository) ListAuthTokens(ctx context.Context, withOrgId string, opt ...Option) ([]*AuthToken, error) {
 	if withOrgId == "" {
 		return nil, fmt.Errorf("list users: missing org id %w", db.ErrInvalidParameter)
@@ -282,8 +314,16 @@ func (r *Repository) ListAuthTokens(ctx context.Context, withOrgId string, opt .
 		// non-zero signals an override of the default limit for the repo.
 		limit = opts.withLimit
 	}
+	where, args := "auth_account_id in (select public_id from auth_account where scope_id = ?)", []interface{}{withOrgId}
+	if opts.withUserId != "" {
+		where, args = where+" and iam_user_id = ?", append(args, opts.withUserId)
+	}
+	if opts.withIdleLongerThan > 0 {
+		where = where + " and approximate_last_access_time < now() - make_interval(secs => ?)"
+		args = append(args, opts.withIdleLongerThan.Seconds())
+	}
 	var authTokens []*AuthToken
-	if err := r.reader.SearchWhere(ctx, &authTokens, "auth_account_id in (select public_id from auth_account where scope_id = ?)", []interface{}{withOrgId}, db.WithLimit(limit)); err != nil {
+	if err := r.reader.SearchWhere(ctx, &authTokens, where, args, db.WithLimit(limit), db.WithOrder(opts.withOrder)); err != nil {
 		return nil, fmt.Errorf("list users: %w", err)
 	}
 	for _, at := range authTokens {
@@ -335,6 +375,30 @@ func (r *Repository) DeleteAuthToken(ctx context.Context, id string, opt ...Opti
 	return rowsDeleted, nil
 }
 
+// DeleteStaleAuthTokens deletes the auth tokens which have expired or haven't
+// been used for longer than they are allowed to be idle, which api tokens
+// aren't limited by, returning how many were deleted. ValidateToken also deletes these tokens, but only when
+// they're presented, so those which are never used again are left behind
+// without this.
+func (r *Repository) DeleteStaleAuthTokens(ctx context.Context) (int, error) {
+	var rowsDeleted int
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			var err error
+			// tokens are not replicated, so they don't need oplog entries.
+			rowsDeleted, err = w.Exec(ctx, deleteStaleQuery, []interface{}{(maxStaleness - timeSkew).Seconds()})
+			return err
+		},
+	)
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("delete stale auth tokens: %w", err)
+	}
+	return rowsDeleted, nil
+}
+
 func allocAuthToken() *AuthToken {
 	fresh := &AuthToken{
 		AuthToken: &store.AuthToken{},
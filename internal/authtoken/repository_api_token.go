@@ -0,0 +1,149 @@
+package authtoken
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/kms"
+)
+
+// MaxApiTokenDuration is the longest an api token can be created for. Unlike
+// other auth tokens, api tokens aren't deleted for being idle, so they're
+// only limited by their expiration time.
+const MaxApiTokenDuration = 90 * 24 * time.Hour
+
+// apiTokenGrant is a grant an api token is restricted to.
+type apiTokenGrant struct {
+	AuthTokenId string `gorm:"primary_key"`
+	RawGrant    string `gorm:"primary_key"`
+}
+
+// TableName returns the table name for api token grants.
+func (g *apiTokenGrant) TableName() string {
+	return "auth_token_grant"
+}
+
+// CreateApiToken inserts an api token for the auth account with id
+// withAuthAccountId, which must be in the scope withScopeId and linked to a
+// user, and returns it. Api tokens are created by admins for automation, such
+// as CI/CD pipelines, rather than by authenticating. Requests made with one
+// are only allowed if both its user's grants and the token's grants allow
+// them. The token expires at expiration, which can't be later than
+// MaxApiTokenDuration from now, and isn't deleted for being idle. The returned
+// token contains the token value, which can't be retrieved again. All options
+// are ignored.
+func (r *Repository) CreateApiToken(ctx context.Context, withScopeId, withAuthAccountId string, grants []string, expiration time.Time, opt ...Option) (*AuthToken, error) {
+	switch {
+	case withScopeId == "":
+		return nil, fmt.Errorf("create api token: missing scope id: %w", db.ErrInvalidParameter)
+	case withAuthAccountId == "":
+		return nil, fmt.Errorf("create api token: missing auth account id: %w", db.ErrInvalidParameter)
+	case len(grants) == 0:
+		return nil, fmt.Errorf("create api token: missing grants: %w", db.ErrInvalidParameter)
+	case !expiration.After(time.Now()):
+		return nil, fmt.Errorf("create api token: expiration time is in the past: %w", db.ErrInvalidParameter)
+	case expiration.After(time.Now().Add(MaxApiTokenDuration)):
+		return nil, fmt.Errorf("create api token: expiration time is more than %s away: %w", MaxApiTokenDuration, db.ErrInvalidParameter)
+	}
+	// Grants given more than once are only stored once
+	var uniqueGrants []string
+	seen := make(map[string]bool, len(grants))
+	for _, g := range grants {
+		if g == "" {
+			return nil, fmt.Errorf("create api token: empty grant: %w", db.ErrInvalidParameter)
+		}
+		if !seen[g] {
+			seen[g] = true
+			uniqueGrants = append(uniqueGrants, g)
+		}
+	}
+
+	at := allocAuthToken()
+	at.AuthAccountId = withAuthAccountId
+	at.Api = true
+	id, err := newAuthTokenId()
+	if err != nil {
+		return nil, fmt.Errorf("create api token: %w", err)
+	}
+	at.PublicId = id
+	token, err := newAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("create api token: %w", err)
+	}
+	at.Token = token
+	// We truncate the expiration time to the nearest second, as for other
+	// auth tokens.
+	exp, err := ptypes.TimestampProto(expiration.Truncate(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("create api token: %w", err)
+	}
+	at.ExpirationTime = &timestamp.Timestamp{Timestamp: exp}
+
+	databaseWrapper, err := r.kms.GetWrapper(ctx, withScopeId, kms.KeyPurposeDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("create api token: unable to get database wrapper: %w", err)
+	}
+
+	var newAuthToken *writableAuthToken
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			acct := allocAuthAccount()
+			acct.PublicId = withAuthAccountId
+			if err := read.LookupByPublicId(ctx, acct); err != nil {
+				return fmt.Errorf("auth account lookup: %w", err)
+			}
+			if acct.GetScopeId() != withScopeId {
+				return fmt.Errorf("auth account %q is not in scope %q: %w", withAuthAccountId, withScopeId, db.ErrInvalidParameter)
+			}
+			if acct.GetIamUserId() == "" {
+				return fmt.Errorf("auth account %q is not linked to a user: %w", withAuthAccountId, db.ErrInvalidParameter)
+			}
+			at.ScopeId = acct.GetScopeId()
+			at.AuthMethodId = acct.GetAuthMethodId()
+			at.IamUserId = acct.GetIamUserId()
+
+			newAuthToken = at.toWritableAuthToken()
+			if err := newAuthToken.encrypt(ctx, databaseWrapper); err != nil {
+				return err
+			}
+			// tokens are not replicated, so they don't need oplog entries.
+			if err := w.Create(ctx, newAuthToken); err != nil {
+				return err
+			}
+			newAuthToken.CtToken = nil
+
+			items := make([]interface{}, 0, len(uniqueGrants))
+			for _, g := range uniqueGrants {
+				items = append(items, &apiTokenGrant{AuthTokenId: id, RawGrant: g})
+			}
+			return w.CreateItems(ctx, items)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create api token: %w", err)
+	}
+	ret := newAuthToken.toAuthToken()
+	ret.Grants = uniqueGrants
+	return ret, nil
+}
+
+// lookupApiTokenGrants returns the grants of the api token with id
+// authTokenId.
+func (r *Repository) lookupApiTokenGrants(ctx context.Context, authTokenId string) ([]string, error) {
+	var grants []*apiTokenGrant
+	if err := r.reader.SearchWhere(ctx, &grants, "auth_token_id = ?", []interface{}{authTokenId}, db.WithLimit(-1), db.WithOrder("raw_grant")); err != nil {
+		return nil, fmt.Errorf("lookup api token grants: %w", err)
+	}
+	ret := make([]string, 0, len(grants))
+	for _, g := range grants {
+		ret = append(ret, g.RawGrant)
+	}
+	return ret, nil
+}
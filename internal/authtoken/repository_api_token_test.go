@@ -0,0 +1,90 @@
+package authtoken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth/password"
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CreateApiToken(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := authtoken.NewRepository(rw, rw, kmsCache)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	org, _ := iam.TestScopes(t, iamRepo)
+	authMethod := password.TestAuthMethods(t, conn, org.GetPublicId(), 1)[0]
+	accts := password.TestAccounts(t, conn, authMethod.GetPublicId(), 2)
+	// Only the first account is linked to a user
+	u, err := iamRepo.LookupUserWithLogin(ctx, accts[0].GetPublicId(), iam.WithAutoVivify(true))
+	require.NoError(t, err)
+
+	grants := []string{"id=*;type=target;actions=authorize-session"}
+	expiration := time.Now().Add(time.Hour)
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			scopeId    string
+			accountId  string
+			grants     []string
+			expiration time.Time
+		}{
+			{name: "missing-scope", accountId: accts[0].GetPublicId(), grants: grants, expiration: expiration},
+			{name: "missing-account", scopeId: org.GetPublicId(), grants: grants, expiration: expiration},
+			{name: "missing-grants", scopeId: org.GetPublicId(), accountId: accts[0].GetPublicId(), expiration: expiration},
+			{name: "empty-grant", scopeId: org.GetPublicId(), accountId: accts[0].GetPublicId(), grants: []string{""}, expiration: expiration},
+			{name: "expired", scopeId: org.GetPublicId(), accountId: accts[0].GetPublicId(), grants: grants, expiration: time.Now().Add(-time.Hour)},
+			{name: "too-long", scopeId: org.GetPublicId(), accountId: accts[0].GetPublicId(), grants: grants, expiration: time.Now().Add(authtoken.MaxApiTokenDuration + time.Hour)},
+			{name: "wrong-scope", scopeId: "global", accountId: accts[0].GetPublicId(), grants: grants, expiration: expiration},
+			{name: "no-user", scopeId: org.GetPublicId(), accountId: accts[1].GetPublicId(), grants: grants, expiration: expiration},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := repo.CreateApiToken(ctx, tt.scopeId, tt.accountId, tt.grants, tt.expiration)
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, db.ErrInvalidParameter), "got %v", err)
+			})
+		}
+	})
+
+	at, err := repo.CreateApiToken(ctx, org.GetPublicId(), accts[0].GetPublicId(), append(grants, grants...), expiration)
+	require.NoError(t, err)
+	assert.True(t, at.GetApi())
+	assert.Equal(t, grants, at.GetGrants())
+	assert.NotEmpty(t, at.GetToken())
+	assert.Equal(t, u.GetPublicId(), at.GetIamUserId())
+	assert.Equal(t, expiration.Truncate(time.Second).Unix(), at.GetExpirationTime().GetTimestamp().AsTime().Unix())
+
+	validated, err := repo.ValidateToken(ctx, at.GetPublicId(), at.GetToken())
+	require.NoError(t, err)
+	require.NotNil(t, validated)
+	assert.True(t, validated.GetApi())
+	assert.Equal(t, grants, validated.GetGrants())
+	assert.Equal(t, u.GetPublicId(), validated.GetIamUserId())
+
+	// Tokens can't be made into api tokens, or api tokens into other tokens
+	_, err = rw.Exec(ctx, "update auth_token set api = false where public_id = ?", []interface{}{at.GetPublicId()})
+	require.Error(t, err)
+
+	// Api tokens are revoked by deleting them
+	rows, err := repo.DeleteAuthToken(ctx, at.GetPublicId())
+	require.NoError(t, err)
+	assert.Equal(t, 1, rows)
+	validated, err = repo.ValidateToken(ctx, at.GetPublicId(), at.GetToken())
+	require.NoError(t, err)
+	assert.Nil(t, validated)
+}
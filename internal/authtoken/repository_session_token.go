@@ -0,0 +1,71 @@
+package authtoken
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// CreateSessionToken inserts a token for a worker to use when updating the
+// status of sessionId, which expires at expiration, and returns it. The
+// returned token contains the token value, which can't be retrieved again.
+// All options are ignored.
+func (r *Repository) CreateSessionToken(ctx context.Context, sessionId string, expiration time.Time, opt ...Option) (*SessionToken, error) {
+	if sessionId == "" {
+		return nil, fmt.Errorf("create session token: missing session id: %w", db.ErrInvalidParameter)
+	}
+	if !expiration.After(time.Now()) {
+		return nil, fmt.Errorf("create session token: expiration time is in the past: %w", db.ErrInvalidParameter)
+	}
+
+	id, err := newSessionTokenId()
+	if err != nil {
+		return nil, fmt.Errorf("create session token: %w", err)
+	}
+	token, err := newAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("create session token: %w", err)
+	}
+	st := &SessionToken{
+		PublicId:       id,
+		SessionId:      sessionId,
+		TokenHash:      hashSessionToken(token),
+		ExpirationTime: expiration.Truncate(time.Second),
+	}
+	// tokens are not replicated, so they don't need oplog entries.
+	if err := r.writer.Create(ctx, st); err != nil {
+		return nil, fmt.Errorf("create session token: %w", err)
+	}
+	st.Token = token
+	return st, nil
+}
+
+// ValidateSessionToken returns true if token was created for sessionId and
+// hasn't expired. All options are ignored.
+//
+// NOTE: Do not log or add the token string to any errors to avoid leaking it as it is a secret.
+func (r *Repository) ValidateSessionToken(ctx context.Context, sessionId, token string, opt ...Option) (bool, error) {
+	if sessionId == "" {
+		return false, fmt.Errorf("validate session token: missing session id: %w", db.ErrInvalidParameter)
+	}
+	if token == "" {
+		return false, fmt.Errorf("validate session token: missing token: %w", db.ErrInvalidParameter)
+	}
+	var tokens []*SessionToken
+	if err := r.reader.SearchWhere(ctx, &tokens, "session_id = ? and token_hash = ? and expiration_time > current_timestamp", []interface{}{sessionId, hashSessionToken(token)}, db.WithLimit(1)); err != nil {
+		return false, fmt.Errorf("validate session token: %w", err)
+	}
+	return len(tokens) > 0, nil
+}
+
+// DeleteExpiredSessionTokens deletes session tokens which have expired,
+// returning the number deleted. All options are ignored.
+func (r *Repository) DeleteExpiredSessionTokens(ctx context.Context, opt ...Option) (int, error) {
+	rows, err := r.writer.Delete(ctx, &SessionToken{}, db.WithWhere("expiration_time <= current_timestamp"))
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("delete expired session tokens: %w", err)
+	}
+	return rows, nil
+}
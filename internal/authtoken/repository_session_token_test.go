@@ -0,0 +1,73 @@
+package authtoken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_SessionTokens(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := authtoken.NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	ctx := context.Background()
+
+	s1 := session.TestDefaultSession(t, conn, wrapper, iamRepo)
+	s2 := session.TestDefaultSession(t, conn, wrapper, iamRepo)
+
+	_, err = repo.CreateSessionToken(ctx, "", time.Now().Add(time.Hour))
+	assert.True(errors.Is(err, db.ErrInvalidParameter))
+	_, err = repo.CreateSessionToken(ctx, s1.PublicId, time.Now().Add(-time.Hour))
+	assert.True(errors.Is(err, db.ErrInvalidParameter))
+
+	st, err := repo.CreateSessionToken(ctx, s1.PublicId, time.Now().Add(time.Hour))
+	require.NoError(err)
+	assert.NotEmpty(st.PublicId)
+	assert.NotEmpty(st.Token)
+	assert.NotContains(string(st.TokenHash), st.Token)
+
+	ok, err := repo.ValidateSessionToken(ctx, s1.PublicId, st.Token)
+	require.NoError(err)
+	assert.True(ok)
+
+	// Tokens are only valid for their own session
+	ok, err = repo.ValidateSessionToken(ctx, s2.PublicId, st.Token)
+	require.NoError(err)
+	assert.False(ok)
+	ok, err = repo.ValidateSessionToken(ctx, s1.PublicId, st.Token+"x")
+	require.NoError(err)
+	assert.False(ok)
+	_, err = repo.ValidateSessionToken(ctx, s1.PublicId, "")
+	assert.True(errors.Is(err, db.ErrInvalidParameter))
+
+	// Expiration times can't be changed, so use a token which expires soon
+	_, err = rw.Exec(ctx, "update auth_token_session set expiration_time = now() where public_id = ?", []interface{}{st.PublicId})
+	require.Error(err)
+	expiring, err := repo.CreateSessionToken(ctx, s2.PublicId, time.Now().Add(2*time.Second))
+	require.NoError(err)
+	time.Sleep(2 * time.Second)
+	ok, err = repo.ValidateSessionToken(ctx, s2.PublicId, expiring.Token)
+	require.NoError(err)
+	assert.False(ok)
+
+	deleted, err := repo.DeleteExpiredSessionTokens(ctx)
+	require.NoError(err)
+	assert.Equal(1, deleted)
+	ok, err = repo.ValidateSessionToken(ctx, s1.PublicId, st.Token)
+	require.NoError(err)
+	assert.True(ok)
+}
@@ -549,6 +549,36 @@ func TestRepository_DeleteAuthToken(t *testing.T) {
 	}
 }
 
+func TestRepository_DeleteUser(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	ctx := context.Background()
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+
+	deleted := TestAuthToken(t, conn, kms, org.GetPublicId())
+	kept := TestAuthToken(t, conn, kms, org.GetPublicId())
+
+	// The user is only known from the auth token view
+	lookedUp, err := repo.LookupAuthToken(ctx, deleted.GetPublicId())
+	require.NoError(err)
+	n, err := iamRepo.DeleteUser(ctx, lookedUp.GetIamUserId(), iam.WithForce(true))
+	require.NoError(err)
+	assert.Equal(1, n)
+
+	got, err := repo.LookupAuthToken(ctx, deleted.GetPublicId())
+	require.NoError(err)
+	assert.Nil(got)
+	got, err = repo.LookupAuthToken(ctx, kept.GetPublicId())
+	require.NoError(err)
+	assert.NotNil(got)
+}
+
 func TestRepository_ListAuthTokens(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	rw := db.New(conn)
@@ -571,6 +601,7 @@ func TestRepository_ListAuthTokens(t *testing.T) {
 	var tests = []struct {
 		name    string
 		orgId   string
+		opts    []Option
 		want    []*AuthToken
 		wantErr error
 	}{
@@ -579,6 +610,18 @@ func TestRepository_ListAuthTokens(t *testing.T) {
 			orgId: org.GetPublicId(),
 			want:  []*AuthToken{at1, at2, at3},
 		},
+		{
+			name:  "by-user",
+			orgId: org.GetPublicId(),
+			opts:  []Option{WithUserId(at2.GetIamUserId())},
+			want:  []*AuthToken{at2},
+		},
+		{
+			name:  "idle-longer-than",
+			orgId: org.GetPublicId(),
+			opts:  []Option{WithIdleLongerThan(time.Hour)},
+			want:  []*AuthToken{},
+		},
 		{
 			name:  "empty",
 			orgId: emptyOrg.GetPublicId(),
@@ -600,7 +643,7 @@ func TestRepository_ListAuthTokens(t *testing.T) {
 			require.NoError(err)
 			require.NotNil(repo)
 
-			got, err := repo.ListAuthTokens(context.Background(), tt.orgId)
+			got, err := repo.ListAuthTokens(context.Background(), tt.orgId, tt.opts...)
 			if tt.wantErr != nil {
 				assert.Truef(errors.Is(err, tt.wantErr), "want err: %q got: %q", tt.wantErr, err)
 				return
@@ -612,3 +655,90 @@ func TestRepository_ListAuthTokens(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_FlushAccessTimes(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	ctx := context.Background()
+
+	defaultUpdateDuration := lastAccessedUpdateDuration
+	lastAccessedUpdateDuration = 0
+	timeSkew = 20 * time.Millisecond
+	defer func() { lastAccessedUpdateDuration = defaultUpdateDuration }()
+
+	t.Run("no-recorder", func(t *testing.T) {
+		repo, err := NewRepository(rw, rw, kms)
+		require.NoError(t, err)
+		_, err = repo.FlushAccessTimes(ctx)
+		assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+	})
+	t.Run("batched", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		repo, err := NewRepository(rw, rw, kms, WithAccessRecorder(NewAccessRecorder()))
+		require.NoError(err)
+
+		at := TestAuthToken(t, conn, kms, org.GetPublicId())
+		created, err := ptypes.Timestamp(at.GetApproximateLastAccessTime().GetTimestamp())
+		require.NoError(err)
+
+		// Validating twice records the token once and doesn't write it.
+		for i := 0; i < 2; i++ {
+			got, err := repo.ValidateToken(ctx, at.GetPublicId(), at.GetToken())
+			require.NoError(err)
+			require.NotNil(got)
+		}
+		got, err := repo.LookupAuthToken(ctx, at.GetPublicId())
+		require.NoError(err)
+		accessed, err := ptypes.Timestamp(got.GetApproximateLastAccessTime().GetTimestamp())
+		require.NoError(err)
+		assert.True(accessed.Equal(created))
+
+		n, err := repo.FlushAccessTimes(ctx)
+		require.NoError(err)
+		assert.Equal(1, n)
+		got, err = repo.LookupAuthToken(ctx, at.GetPublicId())
+		require.NoError(err)
+		accessed, err = ptypes.Timestamp(got.GetApproximateLastAccessTime().GetTimestamp())
+		require.NoError(err)
+		assert.True(accessed.After(created))
+
+		// Nothing is left to flush.
+		n, err = repo.FlushAccessTimes(ctx)
+		require.NoError(err)
+		assert.Equal(0, n)
+	})
+}
+
+func TestRepository_DeleteStaleAuthTokens(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	ctx := context.Background()
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+
+	fresh := TestAuthToken(t, conn, kms, org.GetPublicId())
+	stale := TestAuthToken(t, conn, kms, org.GetPublicId())
+	_, err = rw.Exec(ctx, "update auth_token set approximate_last_access_time = now() - interval '2 days' where public_id = ?",
+		[]interface{}{stale.GetPublicId()})
+	require.NoError(err)
+
+	n, err := repo.DeleteStaleAuthTokens(ctx)
+	require.NoError(err)
+	assert.Equal(1, n)
+
+	got, err := repo.LookupAuthToken(ctx, stale.GetPublicId())
+	require.NoError(err)
+	assert.Nil(got)
+	got, err = repo.LookupAuthToken(ctx, fresh.GetPublicId())
+	require.NoError(err)
+	assert.NotNil(got)
+}
@@ -0,0 +1,49 @@
+package authtoken
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+const (
+	SessionTokenPrefix = "ats"
+)
+
+// A SessionToken is given to a worker when it looks up a session. The worker
+// presents it to the controller when it updates the status of the session,
+// such as when authorizing a connection, on behalf of the session's user. It
+// only allows updating that session and expires with it.
+type SessionToken struct {
+	PublicId       string `gorm:"primary_key"`
+	SessionId      string
+	TokenHash      []byte
+	CreateTime     time.Time `gorm:"default:current_timestamp"`
+	ExpirationTime time.Time
+
+	// Token is the value given to the worker. It is only set when the token
+	// is created and isn't stored.
+	Token string `gorm:"-"`
+}
+
+// TableName returns the table name for session tokens.
+func (t *SessionToken) TableName() string {
+	return "auth_token_session"
+}
+
+func newSessionTokenId() (string, error) {
+	id, err := db.NewPublicId(SessionTokenPrefix)
+	if err != nil {
+		return "", fmt.Errorf("new session token id: %w", err)
+	}
+	return id, err
+}
+
+// hashSessionToken returns the hash of token which is stored in its place.
+// Tokens are random so they don't need a salt or a slow hash.
+func hashSessionToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
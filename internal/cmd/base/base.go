@@ -3,8 +3,6 @@ package base
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -17,12 +15,10 @@ import (
 	"syscall"
 
 	"github.com/hashicorp/boundary/api"
-	"github.com/hashicorp/boundary/api/authtokens"
 	"github.com/hashicorp/boundary/sdk/wrapper"
 	"github.com/mitchellh/cli"
 	"github.com/pkg/errors"
 	"github.com/posener/complete"
-	"github.com/zalando/go-keyring"
 )
 
 const (
@@ -59,6 +55,7 @@ type Command struct {
 	flagTLSInsecure   bool
 
 	flagFormat           string
+	flagField            string
 	FlagToken            string
 	FlagTokenName        string
 	FlagRecoveryConfig   string
@@ -71,6 +68,9 @@ type Command struct {
 	FlagAuthMethodId  string
 	FlagHostCatalogId string
 	FlagVersion       int
+	FlagFilter        string
+	FlagSortBy        string
+	FlagSortDir       string
 
 	client *api.Client
 }
@@ -175,7 +175,7 @@ func (c *Command) Client(opt ...Option) (*api.Client, error) {
 		c.client.SetMaxRetries(0)
 	}
 
-	tokenName := "default"
+	tokenName := CurrentProfile()
 	switch {
 	case c.FlagRecoveryConfig != "":
 		wrapper, err := wrapper.GetWrapperFromPath(c.FlagRecoveryConfig, "recovery")
@@ -214,9 +214,16 @@ func (c *Command) Client(opt ...Option) (*api.Client, error) {
 		}
 		os.Setenv(EnvTokenName, tokenName)
 		if tokenName != "none" {
-			authToken := c.ReadTokenFromKeyring(tokenName)
+			authToken, addr := c.readStoredToken(tokenName)
 			if authToken != nil {
 				c.client.SetToken(authToken.Token)
+				// Use the controller which issued the token unless another
+				// was given
+				if c.flagAddr == "" && addr != "" {
+					if err := c.client.SetAddr(addr); err != nil {
+						return nil, fmt.Errorf("error setting address on client: %w", err)
+					}
+				}
 			}
 		}
 	}
@@ -228,37 +235,6 @@ func (c *Command) Client(opt ...Option) (*api.Client, error) {
 	return c.client, nil
 }
 
-func (c *Command) ReadTokenFromKeyring(tokenName string) *authtokens.AuthToken {
-	token, err := keyring.Get("HashiCorp Boundary Auth Token", tokenName)
-	if err != nil {
-		if err == keyring.ErrNotFound {
-			c.UI.Info("No saved credential found, continuing without")
-		} else {
-			// TODO: potentially look for dbus-launch in advance and don't issue a warning at all
-			c.UI.Error(fmt.Sprintf("Error reading auth token from system credential store: %s", err))
-			c.UI.Warn("Token must be provided via BOUNDARY_TOKEN env var or -token flag. Reading the token can also be disabled via -token-name=none.")
-		}
-		token = ""
-	}
-	if token != "" {
-		tokenBytes, err := base64.RawStdEncoding.DecodeString(token)
-		switch {
-		case err != nil:
-			c.UI.Error(fmt.Errorf("Error base64-unmarshaling stored token from system credential store: %w", err).Error())
-		case len(tokenBytes) == 0:
-			c.UI.Error("Zero length token after decoding stored token from system credential store")
-		default:
-			var authToken authtokens.AuthToken
-			if err := json.Unmarshal(tokenBytes, &authToken); err != nil {
-				c.UI.Error(fmt.Sprintf("Error unmarshaling stored token information after reading from system credential store: %s", err))
-			} else {
-				return &authToken
-			}
-		}
-	}
-	return nil
-}
-
 type FlagSetBit uint
 
 const (
@@ -349,7 +325,7 @@ func (c *Command) FlagSet(bit FlagSetBit) *FlagSets {
 				Name:   "token-name",
 				Target: &c.FlagTokenName,
 				EnvVar: EnvTokenName,
-				Usage:  `If specified, the given value will be used as the name when storing the token in the system credential store. This can allow switching user identities for different commands. Set to "none" to disable storing the token.`,
+				Usage:  `If specified, the given value will be used as the name when storing the token in the system credential store. This can allow switching user identities for different commands. If not specified, the profile selected with "boundary config switch" is used. Set to "none" to disable storing the token.`,
 			})
 
 			f.StringVar(&StringVar{
@@ -392,7 +368,14 @@ func (c *Command) FlagSet(bit FlagSetBit) *FlagSets {
 					Default:    "table",
 					EnvVar:     EnvBoundaryCLIFormat,
 					Completion: complete.PredictSet("table", "json", "yaml"),
-					Usage:      "Print the output in the given format. Valid formats are \"table\" or \"json\".",
+					Usage:      "Print the output in the given format. Valid formats are \"table\", \"json\" or \"yaml\".",
+				})
+
+				f.StringVar(&StringVar{
+					Name:       "field",
+					Target:     &c.flagField,
+					Completion: complete.PredictAnything,
+					Usage:      "Print only the value of the given field, such as \"id\" or \"scope.id\", from the output or from each listed item. String and number values are printed without quotes.",
 				})
 			}
 		}
@@ -0,0 +1,136 @@
+package base
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/posener/complete"
+)
+
+// ControllerConfig loads a controller's configuration file so commands can
+// connect directly to the controller's database. It is meant to be embedded
+// in commands which operate on the database without going through the API.
+type ControllerConfig struct {
+	srv *Server
+
+	Config *config.Config
+
+	configWrapper wrapping.Wrapper
+
+	flagConfig    string
+	flagConfigKms string
+}
+
+// AddConfigFlags adds the -config and -config-kms flags to f.
+func (c *ControllerConfig) AddConfigFlags(f *FlagSet) {
+	f.StringVar(&StringVar{
+		Name:   "config",
+		Target: &c.flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the controller's configuration file.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:   "config-kms",
+		Target: &c.flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file, which has some drawbacks; see the help output for "boundary config encrypt -h" for details.`,
+	})
+}
+
+// LoadConfig loads the configuration file given by the config flags. It
+// returns a non-zero exit code on failure.
+func (c *ControllerConfig) LoadConfig(cmd *Command) int {
+	if len(c.flagConfig) == 0 {
+		cmd.UI.Error("Must specify a config file using -config")
+		return 1
+	}
+
+	wrapperPath := c.flagConfig
+	if c.flagConfigKms != "" {
+		wrapperPath = c.flagConfigKms
+	}
+	wrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		cmd.UI.Error(err.Error())
+		return 1
+	}
+	if wrapper != nil {
+		c.configWrapper = wrapper
+		if err := wrapper.Init(cmd.Context); err != nil {
+			cmd.UI.Error(fmt.Errorf("Could not initialize kms: %w", err).Error())
+			return 1
+		}
+	}
+
+	c.Config, err = config.LoadFile(c.flagConfig, wrapper)
+	if err != nil {
+		cmd.UI.Error("Error parsing config: " + err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+// FinalizeConfigWrapper finalizes the KMS used to decrypt the configuration,
+// if any. It should be deferred after a successful LoadConfig.
+func (c *ControllerConfig) FinalizeConfigWrapper(cmd *Command) {
+	if c.configWrapper == nil {
+		return
+	}
+	if err := c.configWrapper.Finalize(cmd.Context); err != nil {
+		cmd.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+	}
+}
+
+// ConnectToDatabase connects to the database and KMSes defined in the
+// configuration loaded by LoadConfig. It returns the database and a KMS cache
+// which can be used to create repositories.
+func (c *ControllerConfig) ConnectToDatabase(cmd *Command) (*db.Db, *kms.Kms, error) {
+	c.srv = NewServer(&Command{UI: cmd.UI})
+	if err := c.srv.SetupLogging("", "", c.Config.LogLevel, c.Config.LogFormat); err != nil {
+		return nil, nil, err
+	}
+	if err := c.srv.SetupKMSes(cmd.UI, c.Config); err != nil {
+		return nil, nil, err
+	}
+	if c.srv.RootKms == nil {
+		return nil, nil, fmt.Errorf("Root KMS not found after parsing KMS blocks")
+	}
+	if c.Config.Controller == nil || c.Config.Controller.Database == nil {
+		return nil, nil, fmt.Errorf(`"controller.database" config block not found`)
+	}
+	dbaseUrl, err := config.ParseAddress(c.Config.Controller.Database.Url)
+	if err != nil && err != config.ErrNotAUrl {
+		return nil, nil, fmt.Errorf("Error parsing database url: %w", err)
+	}
+	c.srv.DatabaseUrl = strings.TrimSpace(dbaseUrl)
+	if err := c.srv.ConnectToDatabase("postgres"); err != nil {
+		return nil, nil, fmt.Errorf("Error connecting to database: %w", err)
+	}
+
+	rw := db.New(c.srv.Database)
+	kmsRepo, err := kms.NewRepository(rw, rw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error creating kms repository: %w", err)
+	}
+	kmsCache, err := kms.NewKms(kmsRepo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error creating kms cache: %w", err)
+	}
+	if err := kmsCache.AddExternalWrappers(kms.WithRootWrapper(c.srv.RootKms)); err != nil {
+		return nil, nil, fmt.Errorf("Error adding config keys to kms: %w", err)
+	}
+	return rw, kmsCache, nil
+}
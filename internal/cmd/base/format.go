@@ -1,6 +1,7 @@
 package base
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/ghodss/yaml"
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/scopes"
 	"github.com/mitchellh/cli"
@@ -182,6 +184,18 @@ func PrintApiError(in *api.Error) string {
 	return WrapForHelpText(ret)
 }
 
+// Formatter encodes the output of a command in one of the structured output
+// formats.
+type Formatter interface {
+	Format(data interface{}) ([]byte, error)
+}
+
+// Formatters are the structured output formats, by their -format value.
+var Formatters = map[string]Formatter{
+	"json": JsonFormatter{},
+	"yaml": YamlFormatter{},
+}
+
 // An output formatter for json output of an object
 type JsonFormatter struct{}
 
@@ -189,6 +203,97 @@ func (j JsonFormatter) Format(data interface{}) ([]byte, error) {
 	return json.Marshal(data)
 }
 
+// An output formatter for yaml output of an object. Field names are the same
+// as in the json output.
+type YamlFormatter struct{}
+
+func (y YamlFormatter) Format(data interface{}) ([]byte, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	b, err = yaml.JSONToYAML(b)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b, []byte("\n")), nil
+}
+
+// PrintStructured prints data in the structured output format given with
+// -format. If -field was given only the value of that field is printed, from
+// each item if data is a list. Fields within objects are selected with dotted
+// paths such as "scope.id", and string and number values are printed without
+// quotes so they can be used directly in scripts.
+func (c *Command) PrintStructured(data interface{}) error {
+	format := Format(c.UI)
+	formatter, ok := Formatters[format]
+	if !ok {
+		// -field is printed the same way whatever the format
+		formatter = JsonFormatter{}
+	}
+	if c.flagField == "" {
+		b, err := formatter.Format(data)
+		if err != nil {
+			return err
+		}
+		c.UI.Output(string(b))
+		return nil
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return err
+	}
+	items, ok := decoded.([]interface{})
+	if !ok {
+		items = []interface{}{decoded}
+	}
+	var found bool
+	for _, item := range items {
+		val, ok := fieldValue(item, c.flagField)
+		if !ok {
+			continue
+		}
+		found = true
+		switch v := val.(type) {
+		case string:
+			c.UI.Output(v)
+		case json.Number:
+			c.UI.Output(v.String())
+		default:
+			b, err := formatter.Format(v)
+			if err != nil {
+				return err
+			}
+			c.UI.Output(string(b))
+		}
+	}
+	if !found && decoded != nil {
+		return fmt.Errorf("field %q not present in output", c.flagField)
+	}
+	return nil
+}
+
+// fieldValue returns the value at the dotted path in decoded JSON.
+func fieldValue(in interface{}, path string) (interface{}, bool) {
+	for _, name := range strings.Split(path, ".") {
+		obj, ok := in.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if in, ok = obj[name]; !ok {
+			return nil, false
+		}
+	}
+	return in, true
+}
+
 func Format(ui cli.Ui) string {
 	switch t := ui.(type) {
 	case *BoundaryUI:
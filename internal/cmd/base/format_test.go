@@ -0,0 +1,94 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintStructured(t *testing.T) {
+	type scope struct {
+		Id string `json:"id"`
+	}
+	type item struct {
+		Id      string `json:"id"`
+		Version uint32 `json:"version"`
+		Scope   *scope `json:"scope,omitempty"`
+	}
+	items := []*item{
+		{Id: "ttcp_1234567890", Version: 2, Scope: &scope{Id: "p_1234567890"}},
+		{Id: "ttcp_0987654321", Version: 1},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		field   string
+		data    interface{}
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "json",
+			format: "json",
+			data:   items[0],
+			want:   `{"id":"ttcp_1234567890","version":2,"scope":{"id":"p_1234567890"}}` + "\n",
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			data:   items[0],
+			want:   "id: ttcp_1234567890\nscope:\n  id: p_1234567890\nversion: 2\n",
+		},
+		{
+			name:   "field",
+			format: "json",
+			field:  "version",
+			data:   items[0],
+			want:   "2\n",
+		},
+		{
+			name:   "nested-field",
+			format: "json",
+			field:  "scope.id",
+			data:   items[0],
+			want:   "p_1234567890\n",
+		},
+		{
+			name:   "object-field",
+			format: "yaml",
+			field:  "scope",
+			data:   items[0],
+			want:   "id: p_1234567890\n",
+		},
+		{
+			name:   "list-field",
+			format: "json",
+			field:  "id",
+			data:   items,
+			want:   "ttcp_1234567890\nttcp_0987654321\n",
+		},
+		{
+			name:    "missing-field",
+			format:  "json",
+			field:   "name",
+			data:    items[0],
+			wantErr: `field "name" not present in output`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := NewCommand(&BoundaryUI{Ui: ui, Format: tt.format})
+			c.flagField = tt.field
+			err := c.PrintStructured(tt.data)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ui.OutputWriter.String())
+		})
+	}
+}
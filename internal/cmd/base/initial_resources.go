@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/publicid"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 )
@@ -97,7 +98,7 @@ func (b *Server) CreateInitialAuthMethod(ctx context.Context) (*password.AuthMet
 		return nil, nil, fmt.Errorf("error creating new in memory auth method: %w", err)
 	}
 	if b.DevAuthMethodId == "" {
-		b.DevAuthMethodId, err = db.NewPublicId(password.AuthMethodPrefix)
+		b.DevAuthMethodId, err = publicid.New(password.AuthMethodPrefix)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error generating initial auth method id: %w", err)
 		}
@@ -152,7 +153,7 @@ func (b *Server) CreateInitialAuthMethod(ctx context.Context) (*password.AuthMet
 
 	// Create a new user and associate it with the account
 	if b.DevUserId == "" {
-		b.DevUserId, err = db.NewPublicId(iam.UserPrefix)
+		b.DevUserId, err = publicid.New(iam.UserPrefix)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error generating initial user id: %w", err)
 		}
@@ -224,7 +225,7 @@ func (b *Server) CreateInitialScopes(ctx context.Context) (*iam.Scope, *iam.Scop
 
 	// Create the scopes
 	if b.DevOrgId == "" {
-		b.DevOrgId, err = db.NewPublicId(scope.Org.Prefix())
+		b.DevOrgId, err = publicid.New(scope.Org.Prefix())
 		if err != nil {
 			return nil, nil, fmt.Errorf("error generating initial org id: %w", err)
 		}
@@ -247,7 +248,7 @@ func (b *Server) CreateInitialScopes(ctx context.Context) (*iam.Scope, *iam.Scop
 	b.Info["generated org scope id"] = b.DevOrgId
 
 	if b.DevProjectId == "" {
-		b.DevProjectId, err = db.NewPublicId(scope.Project.Prefix())
+		b.DevProjectId, err = publicid.New(scope.Project.Prefix())
 		if err != nil {
 			return nil, nil, fmt.Errorf("error generating initial project id: %w", err)
 		}
@@ -302,7 +303,7 @@ func (b *Server) CreateInitialHostResources(ctx context.Context) (*static.HostCa
 
 	// Host Catalog
 	if b.DevHostCatalogId == "" {
-		b.DevHostCatalogId, err = db.NewPublicId(static.HostCatalogPrefix)
+		b.DevHostCatalogId, err = publicid.New(static.HostCatalogPrefix)
 		if err != nil {
 			return nil, nil, nil, fmt.Errorf("error generating initial host catalog id: %w", err)
 		}
@@ -324,7 +325,7 @@ func (b *Server) CreateInitialHostResources(ctx context.Context) (*static.HostCa
 
 	// Host
 	if b.DevHostId == "" {
-		b.DevHostId, err = db.NewPublicId(static.HostPrefix)
+		b.DevHostId, err = publicid.New(static.HostPrefix)
 		if err != nil {
 			return nil, nil, nil, fmt.Errorf("error generating initial host id: %w", err)
 		}
@@ -350,7 +351,7 @@ func (b *Server) CreateInitialHostResources(ctx context.Context) (*static.HostCa
 
 	// Host Set
 	if b.DevHostSetId == "" {
-		b.DevHostSetId, err = db.NewPublicId(static.HostSetPrefix)
+		b.DevHostSetId, err = publicid.New(static.HostSetPrefix)
 		if err != nil {
 			return nil, nil, nil, fmt.Errorf("error generating initial host set id: %w", err)
 		}
@@ -408,7 +409,7 @@ func (b *Server) CreateInitialTarget(ctx context.Context) (target.Target, error)
 
 	// Host Catalog
 	if b.DevTargetId == "" {
-		b.DevTargetId, err = db.NewPublicId(target.TcpTargetPrefix)
+		b.DevTargetId, err = publicid.New(target.TcpTargetPrefix)
 		if err != nil {
 			return nil, fmt.Errorf("error generating initial target id: %w", err)
 		}
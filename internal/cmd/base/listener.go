@@ -13,6 +13,7 @@ import (
 	// certificates that use it can be parsed.
 	_ "crypto/sha512"
 
+	"github.com/hashicorp/boundary/internal/cmd/config"
 	"github.com/hashicorp/boundary/internal/libs/alpnmux"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/shared-secure-libs/configutil"
@@ -20,6 +21,7 @@ import (
 	"github.com/hashicorp/shared-secure-libs/reloadutil"
 	"github.com/mitchellh/cli"
 	"github.com/pires/go-proxyproto"
+	"golang.org/x/crypto/acme"
 	"google.golang.org/grpc"
 )
 
@@ -65,9 +67,9 @@ func NewListener(l *configutil.Listener, logger hclog.Logger, ui cli.Ui) (*alpnm
 	case "cluster":
 		l.TLSDisable = true
 	case "proxy":
-		// TODO: Eventually we'll support bringing your own cert, and we'd only
-		// want to disable if you aren't actually bringing your own
-		l.TLSDisable = true
+		// Session connections use the certificate of their session, so the
+		// listener's own certificate is only needed for browser clients
+		l.TLSDisable = l.TLSCertFile == ""
 	}
 
 	finalAddr, ln, err := f(purpose, l, logger, ui)
@@ -84,6 +86,10 @@ func NewListener(l *configutil.Listener, logger hclog.Logger, ui cli.Ui) (*alpnm
 		"addr": finalAddr,
 	}
 
+	tlsLogger := logger
+	if tlsLogger == nil {
+		tlsLogger = hclog.NewNullLogger()
+	}
 	if _, ok := os.LookupEnv("BOUNDARY_LOG_CONNECTION_MUXING"); !ok {
 		logger = nil
 	}
@@ -98,19 +104,42 @@ func NewListener(l *configutil.Listener, logger hclog.Logger, ui cli.Ui) (*alpnm
 	if !l.TLSRequireAndVerifyClientCert {
 		l.TLSDisableClientCerts = true
 	}
-	tlsConfig, reloadFunc, err := listenerutil.TLSConfig(l, props, ui)
+	listenerTLS, err := config.ParseListenerTLS(l)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	// Register no proto, "http/1.1", and "h2", with same TLS config
-	if _, err = alpnMux.RegisterProto("", tlsConfig); err != nil {
+	tlsConfig, reloadFunc, err := listenerTLSConfig(l, listenerTLS, props, ui, tlsLogger)
+	if err != nil {
 		return nil, nil, nil, err
 	}
-	if _, err = alpnMux.RegisterProto("http/1.1", tlsConfig); err != nil {
-		return nil, nil, nil, err
+	// Register no proto, "http/1.1", and "h2", with same TLS config. Session
+	// clients of a proxy listener don't negotiate a proto, and browsers need
+	// http/1.1 for websockets, so only that is registered for the proxy.
+	protos := []string{"", "http/1.1", "h2"}
+	if purpose == "proxy" {
+		protos = []string{"http/1.1"}
 	}
-	if _, err = alpnMux.RegisterProto("h2", tlsConfig); err != nil {
-		return nil, nil, nil, err
+	for _, proto := range protos {
+		if _, err = alpnMux.RegisterProto(proto, tlsConfig); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if len(listenerTLS.ACMEDomains) > 0 {
+		challengeLn, err := alpnMux.RegisterProto(acme.ALPNProto, tlsConfig)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// ACME challenges are answered in the handshake, so there is nothing
+		// more to do with the connection
+		go func() {
+			for {
+				conn, err := challengeLn.Accept()
+				if err != nil {
+					return
+				}
+				conn.Close()
+			}
+		}()
 	}
 
 	return alpnMux, props, reloadFunc, nil
@@ -123,6 +152,8 @@ func tcpListenerFactory(purpose string, l *configutil.Listener, logger hclog.Log
 			l.Address = "127.0.0.1:9201"
 		case "proxy":
 			l.Address = "127.0.0.1:9202"
+		case "ops":
+			l.Address = "127.0.0.1:9203"
 		default:
 			l.Address = "127.0.0.1:9200"
 		}
@@ -136,6 +167,8 @@ func tcpListenerFactory(purpose string, l *configutil.Listener, logger hclog.Log
 				port = "9201"
 			case "proxy":
 				port = "9202"
+			case "ops":
+				port = "9203"
 			default:
 				port = "9200"
 			}
@@ -0,0 +1,252 @@
+package base
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/hashicorp/shared-secure-libs/listenerutil"
+	"github.com/hashicorp/shared-secure-libs/reloadutil"
+	"github.com/hashicorp/vault/sdk/helper/tlsutil"
+	"github.com/mitchellh/cli"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// certCheckInterval is how often certificate files are checked for changes.
+// They are checked when a handshake needs them, so an idle listener doesn't
+// check at all.
+const certCheckInterval = 10 * time.Second
+
+// listenerTLSConfig returns the TLS configuration for l. On top of what
+// listenerutil supports, the certificate can be chosen by server name from
+// extra certificates or from certificates issued by ACME, and certificate
+// files are reloaded when they change.
+func listenerTLSConfig(l *configutil.Listener, lt *config.ListenerTLS, props map[string]string, ui cli.Ui, logger hclog.Logger) (*tls.Config, reloadutil.ReloadFunc, error) {
+	if !lt.IsSet() && l.TLSCertFile == "" {
+		return listenerutil.TLSConfig(l, props, ui)
+	}
+
+	certs := &listenerCertificates{logger: logger}
+	var tlsConfig *tls.Config
+	switch l.TLSCertFile {
+	case "":
+		var err error
+		if tlsConfig, err = baseTLSConfig(l); err != nil {
+			return nil, nil, err
+		}
+		props["tls"] = "enabled"
+	default:
+		var reloadFunc reloadutil.ReloadFunc
+		var err error
+		tlsConfig, reloadFunc, err = listenerutil.TLSConfig(l, props, ui)
+		if err != nil {
+			return nil, nil, err
+		}
+		certs.defaultCert = tlsConfig.GetCertificate
+		certs.defaultFiles = newWatchedFiles(reloadFunc, l.TLSCertFile, l.TLSKeyFile)
+	}
+
+	for _, sc := range lt.SNICertificates {
+		fc, err := newFileCertificate(sc.CertFile, sc.KeyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		certs.sniCerts = append(certs.sniCerts, fc)
+	}
+
+	if len(lt.ACMEDomains) > 0 {
+		certs.acme = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(lt.ACMECachePath),
+			HostPolicy: autocert.HostWhitelist(lt.ACMEDomains...),
+			Email:      lt.ACMEEmail,
+		}
+		if lt.ACMEDirectoryURL != "" {
+			certs.acme.Client = &acme.Client{DirectoryURL: lt.ACMEDirectoryURL}
+		}
+		certs.acmeDomains = make(map[string]bool, len(lt.ACMEDomains))
+		for _, d := range lt.ACMEDomains {
+			certs.acmeDomains[strings.ToLower(d)] = true
+		}
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, acme.ALPNProto)
+	}
+
+	tlsConfig.GetCertificate = certs.getCertificate
+	return tlsConfig, certs.reload, nil
+}
+
+// baseTLSConfig returns the TLS configuration for a listener which has no
+// certificate of its own.
+func baseTLSConfig(l *configutil.Listener) (*tls.Config, error) {
+	if l.TLSRequireAndVerifyClientCert {
+		return nil, errors.New("'tls_require_and_verify_client_cert' requires 'tls_cert_file' to be set")
+	}
+	if l.TLSMinVersion == "" {
+		l.TLSMinVersion = "tls12"
+	}
+	minVersion, ok := tlsutil.TLSLookup[l.TLSMinVersion]
+	if !ok {
+		return nil, fmt.Errorf("'tls_min_version' value %q not supported, please specify one of [tls10,tls11,tls12,tls13]", l.TLSMinVersion)
+	}
+	return &tls.Config{
+		NextProtos:               []string{"h2", "http/1.1"},
+		MinVersion:               minVersion,
+		CipherSuites:             l.TLSCipherSuites,
+		PreferServerCipherSuites: l.TLSPreferServerCipherSuites,
+	}, nil
+}
+
+// listenerCertificates chooses the certificate for a handshake.
+type listenerCertificates struct {
+	logger hclog.Logger
+
+	defaultCert  func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	defaultFiles *watchedFiles
+
+	sniCerts []*fileCertificate
+
+	acme        *autocert.Manager
+	acmeDomains map[string]bool
+}
+
+// getCertificate returns, in order of preference, the certificate for an
+// ACME challenge, an SNI certificate valid for the requested server name, a
+// certificate issued by ACME for it, or the listener's own certificate.
+func (c *listenerCertificates) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if c.acme != nil && len(hello.SupportedProtos) == 1 && hello.SupportedProtos[0] == acme.ALPNProto {
+		return c.acme.GetCertificate(hello)
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if name != "" {
+		for _, fc := range c.sniCerts {
+			fc.files.check(c.logger)
+			cert := fc.get()
+			if cert.Leaf.VerifyHostname(name) == nil {
+				return cert, nil
+			}
+		}
+		if c.acmeDomains[name] {
+			return c.acme.GetCertificate(hello)
+		}
+	}
+
+	switch {
+	case c.defaultCert != nil:
+		c.defaultFiles.check(c.logger)
+		return c.defaultCert(hello)
+	case len(c.sniCerts) > 0:
+		return c.sniCerts[0].get(), nil
+	default:
+		return nil, fmt.Errorf("no certificate for server name %q", hello.ServerName)
+	}
+}
+
+// reload reloads all of the certificate files.
+func (c *listenerCertificates) reload() error {
+	var retErr *multierror.Error
+	if c.defaultFiles != nil {
+		retErr = multierror.Append(retErr, c.defaultFiles.reload())
+	}
+	for _, fc := range c.sniCerts {
+		retErr = multierror.Append(retErr, fc.files.reload())
+	}
+	return retErr.ErrorOrNil()
+}
+
+// fileCertificate is a certificate and key loaded from files.
+type fileCertificate struct {
+	certFile, keyFile string
+	cert              atomic.Value // *tls.Certificate
+	files             *watchedFiles
+}
+
+func newFileCertificate(certFile, keyFile string) (*fileCertificate, error) {
+	fc := &fileCertificate{certFile: certFile, keyFile: keyFile}
+	if err := fc.load(); err != nil {
+		return nil, err
+	}
+	fc.files = newWatchedFiles(fc.load, certFile, keyFile)
+	return fc, nil
+}
+
+func (fc *fileCertificate) load() error {
+	cert, err := tls.LoadX509KeyPair(fc.certFile, fc.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading certificate %s: %w", fc.certFile, err)
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return fmt.Errorf("error parsing certificate %s: %w", fc.certFile, err)
+	}
+	fc.cert.Store(&cert)
+	return nil
+}
+
+func (fc *fileCertificate) get() *tls.Certificate {
+	return fc.cert.Load().(*tls.Certificate)
+}
+
+// watchedFiles calls reload when any of its files change. If reloading
+// fails, the files are tried again once they change again.
+type watchedFiles struct {
+	paths  []string
+	reload func() error
+
+	l       sync.Mutex
+	modTime time.Time
+	checked time.Time
+}
+
+func newWatchedFiles(reload func() error, paths ...string) *watchedFiles {
+	w := &watchedFiles{paths: paths, reload: reload}
+	w.modTime, _ = w.latestModTime()
+	w.checked = time.Now()
+	return w
+}
+
+func (w *watchedFiles) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, p := range w.paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// check reloads the files if they have changed since they were loaded,
+// checking at most once every certCheckInterval.
+func (w *watchedFiles) check(logger hclog.Logger) {
+	w.l.Lock()
+	defer w.l.Unlock()
+	if time.Since(w.checked) < certCheckInterval {
+		return
+	}
+	w.checked = time.Now()
+
+	modTime, err := w.latestModTime()
+	if err != nil || !modTime.After(w.modTime) {
+		return
+	}
+	w.modTime = modTime
+	if err := w.reload(); err != nil {
+		logger.Error("error reloading certificate", "error", err)
+		return
+	}
+	logger.Info("reloaded certificate", "path", w.paths[0])
+}
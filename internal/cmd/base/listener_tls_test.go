@@ -0,0 +1,121 @@
+package base
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate writes a self-signed certificate for the names and its
+// key to dir, returning their paths.
+func writeTestCertificate(t *testing.T, dir, file string, names ...string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: names[0]},
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certPath, keyPath := filepath.Join(dir, file+".pem"), filepath.Join(dir, file+".key")
+	require.NoError(t, ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certPath, keyPath
+}
+
+func TestListenerTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boundary-listener-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defaultCert, defaultKey := writeTestCertificate(t, dir, "default", "default.example.com")
+	aCert, aKey := writeTestCertificate(t, dir, "a", "a.example.com", "*.a.example.com")
+	bCert, bKey := writeTestCertificate(t, dir, "b", "b.example.com")
+
+	l := &configutil.Listener{
+		Purpose:               []string{"api"},
+		TLSCertFile:           defaultCert,
+		TLSKeyFile:            defaultKey,
+		TLSDisableClientCerts: true,
+	}
+	lt := &config.ListenerTLS{
+		SNICertificates: []*config.SNICertificate{
+			{CertFile: aCert, KeyFile: aKey},
+			{CertFile: bCert, KeyFile: bKey},
+		},
+	}
+	tlsConfig, reloadFunc, err := listenerTLSConfig(l, lt, map[string]string{}, nil, hclog.NewNullLogger())
+	require.NoError(t, err)
+	require.NotNil(t, reloadFunc)
+
+	leafName := func(serverName string) string {
+		t.Helper()
+		cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+
+	assert.Equal(t, "a.example.com", leafName("a.example.com"))
+	assert.Equal(t, "a.example.com", leafName("www.a.example.com"))
+	assert.Equal(t, "b.example.com", leafName("B.example.com."))
+	assert.Equal(t, "default.example.com", leafName("c.example.com"))
+	assert.Equal(t, "default.example.com", leafName(""))
+
+	// Replace b's certificate; it is picked up when reloading
+	writeTestCertificate(t, dir, "b", "new.b.example.com", "b.example.com")
+	assert.Equal(t, "b.example.com", leafName("b.example.com"))
+	require.NoError(t, reloadFunc())
+	assert.Equal(t, "new.b.example.com", leafName("b.example.com"))
+}
+
+func TestWatchedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boundary-listener-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certPath, keyPath := writeTestCertificate(t, dir, "cert", "example.com")
+
+	var reloads int
+	w := newWatchedFiles(func() error {
+		reloads++
+		return nil
+	}, certPath, keyPath)
+
+	// Nothing has changed
+	w.checked = time.Time{}
+	w.check(hclog.NewNullLogger())
+	assert.Equal(t, 0, reloads)
+
+	// Changes aren't looked for until the interval has passed
+	writeTestCertificate(t, dir, "cert", "example.com")
+	w.modTime = w.modTime.Add(-time.Second)
+	w.check(hclog.NewNullLogger())
+	assert.Equal(t, 0, reloads)
+
+	w.checked = time.Time{}
+	w.check(hclog.NewNullLogger())
+	assert.Equal(t, 1, reloads)
+}
@@ -0,0 +1,62 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/libs/alpnmux"
+	"github.com/hashicorp/boundary/internal/metric"
+	"github.com/hashicorp/go-hclog"
+)
+
+// OpsHandler returns the handler for "ops" listeners, which serves the
+// Prometheus metrics at /metrics.
+func OpsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metric.Handler())
+	return mux
+}
+
+// ConfigureOpsListener creates the HTTP server for ln, an "ops" listener. It
+// returns the functions starting the server on each of the listener's
+// protocols.
+func ConfigureOpsListener(ctx context.Context, ln *ServerListener, logger hclog.Logger) ([]func(), error) {
+	server := &http.Server{
+		Handler:           OpsHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		IdleTimeout:       5 * time.Minute,
+		ErrorLog:          logger.StandardLogger(nil),
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	ln.HTTPServer = server
+
+	if ln.Config.TLSDisable {
+		// Clear out in case this is a second start
+		ln.Mux.UnregisterProto(alpnmux.NoProto)
+		l, err := ln.Mux.RegisterProto(alpnmux.NoProto, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error getting non-tls ops listener: %w", err)
+		}
+		if l == nil {
+			return nil, errors.New("could not get non-tls ops listener")
+		}
+		return []func(){func() { go server.Serve(l) }}, nil
+	}
+
+	var servers []func()
+	for _, proto := range []string{"", "http/1.1", "h2"} {
+		l := ln.Mux.GetListener(proto)
+		if l == nil {
+			return nil, fmt.Errorf("could not get tls proto %q ops listener", proto)
+		}
+		servers = append(servers, func() { go server.Serve(l) })
+	}
+	return servers, nil
+}
@@ -13,12 +13,14 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/cmd/config"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/docker"
+	"github.com/hashicorp/boundary/internal/event"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/boundary/sdk/strutil"
@@ -59,6 +61,8 @@ type Server struct {
 	InmemSink         *metrics.InmemSink
 	PrometheusEnabled bool
 
+	Eventer *event.Eventer
+
 	ReloadFuncsLock *sync.RWMutex
 	ReloadFuncs     map[string][]reloadutil.ReloadFunc
 
@@ -84,6 +88,10 @@ type Server struct {
 	DatabaseUrl            string
 	DevDatabaseCleanupFunc func() error
 
+	// SlowQueryThreshold is the duration at or above which queries are
+	// logged as observation events. Queries aren't logged if it is zero.
+	SlowQueryThreshold time.Duration
+
 	Database *gorm.DB
 }
 
@@ -207,6 +215,29 @@ func (b *Server) SetupMetrics(ui cli.Ui, telemetry *configutil.Telemetry) error
 	return nil
 }
 
+// SetupEventing creates the Eventer writing to the sinks configured in c and
+// makes it the system Eventer. The Eventer's sinks are closed at shutdown.
+func (b *Server) SetupEventing(c *event.Config) error {
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("Error validating events configuration: %w", err)
+	}
+	e, err := event.NewEventer(b.Logger.Named("event"), c)
+	if err != nil {
+		return fmt.Errorf("Error initializing eventing: %w", err)
+	}
+	b.Eventer = e
+	event.InitSysEventer(e)
+	b.ShutdownFuncs = append(b.ShutdownFuncs, func() error {
+		event.InitSysEventer(nil)
+		return e.Close()
+	})
+	if c != nil && len(c.Sinks) > 0 {
+		b.InfoKeys = append(b.InfoKeys, "event sinks")
+		b.Info["event sinks"] = strconv.Itoa(len(c.Sinks))
+	}
+	return nil
+}
+
 func (b *Server) PrintInfo(ui cli.Ui) {
 	verInfo := version.Get()
 	if verInfo.Version != "" {
@@ -437,7 +468,12 @@ func (b *Server) ConnectToDatabase(dialect string) error {
 	b.Database = dbase
 	if os.Getenv("BOUNDARY_DISABLE_GORM_FORMATTER") == "" {
 		gorm.LogFormatter = db.GetGormLogFormatter(b.Logger)
-		b.Database.SetLogger(db.GetGormLogger(b.Logger))
+		b.Database.SetLogger(db.GetGormLogger(b.Logger, db.WithSlowQueryThreshold(b.SlowQueryThreshold)))
+		if b.SlowQueryThreshold > 0 {
+			// gorm only reports queries and their durations in detailed
+			// log mode
+			b.Database.LogMode(true)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,220 @@
+package base
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/boundary/api/authtokens"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// keyringService is the service the CLI stores auth tokens under in the
+	// system credential store.
+	keyringService = "HashiCorp Boundary Auth Token"
+
+	// DefaultProfile is the name tokens are stored under when no other
+	// profile has been selected.
+	DefaultProfile = "default"
+
+	// EnvProfilesPath overrides the location of the file in which the CLI
+	// records its profiles.
+	EnvProfilesPath = "BOUNDARY_PROFILES_PATH"
+)
+
+// Profile is an auth token stored by the CLI under a name, given with
+// -token-name, along with the address of the controller which issued it.
+type Profile struct {
+	Name string `json:"name"`
+	Addr string `json:"addr,omitempty"`
+	// Token is the encoded token when it couldn't be saved in the system
+	// credential store.
+	Token string `json:"token,omitempty"`
+}
+
+// profiles is the contents of the profiles file.
+type profiles struct {
+	Current  string              `json:"current,omitempty"`
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+}
+
+// ProfilesPath returns the path of the file in which the CLI records its
+// profiles, which is boundary/profiles.json in the user's configuration
+// directory unless overridden by BOUNDARY_PROFILES_PATH.
+func ProfilesPath() (string, error) {
+	if path := os.Getenv(EnvProfilesPath); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find user configuration directory: %w", err)
+	}
+	return filepath.Join(dir, "boundary", "profiles.json"), nil
+}
+
+func readProfiles() (*profiles, error) {
+	path, err := ProfilesPath()
+	if err != nil {
+		return nil, err
+	}
+	p := &profiles{Profiles: make(map[string]*Profile)}
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return p, nil
+	case err != nil:
+		return nil, fmt.Errorf("unable to read profiles: %w", err)
+	}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("unable to parse profiles in %s: %w", path, err)
+	}
+	if p.Profiles == nil {
+		p.Profiles = make(map[string]*Profile)
+	}
+	for name, prof := range p.Profiles {
+		prof.Name = name
+	}
+	return p, nil
+}
+
+func (p *profiles) write() error {
+	path, err := ProfilesPath()
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode profiles: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("unable to create profiles directory: %w", err)
+	}
+	// The file can hold tokens, so it is only readable by the user
+	if err := ioutil.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("unable to write profiles: %w", err)
+	}
+	return nil
+}
+
+// CurrentProfile returns the name of the profile selected with
+// "boundary config switch", or DefaultProfile if none has been.
+func CurrentProfile() string {
+	p, err := readProfiles()
+	if err != nil || p.Current == "" {
+		return DefaultProfile
+	}
+	return p.Current
+}
+
+// ListProfiles returns the stored profiles sorted by name, and the name of
+// the current profile.
+func ListProfiles() (current string, list []*Profile, err error) {
+	p, err := readProfiles()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, prof := range p.Profiles {
+		list = append(list, prof)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	current = p.Current
+	if current == "" {
+		current = DefaultProfile
+	}
+	return current, list, nil
+}
+
+// SwitchProfile makes name the profile used by commands which aren't given
+// -token-name. The profile must have been stored by authenticating with it.
+func SwitchProfile(name string) error {
+	p, err := readProfiles()
+	if err != nil {
+		return err
+	}
+	if _, ok := p.Profiles[name]; !ok {
+		return fmt.Errorf("no profile named %q has been stored", name)
+	}
+	p.Current = name
+	return p.write()
+}
+
+// SaveToken stores token under tokenName in the system credential store and
+// records the profile, along with the address of the controller which issued
+// the token. If the system credential store can't be used the token is kept
+// in the profiles file instead and a warning is printed.
+func (c *Command) SaveToken(tokenName, addr string, token *authtokens.AuthToken) error {
+	marshaled, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("Error marshaling auth token to save to system credential store: %w", err)
+	}
+	encoded := base64.RawStdEncoding.EncodeToString(marshaled)
+	p, err := readProfiles()
+	if err != nil {
+		return err
+	}
+	prof := &Profile{Name: tokenName, Addr: addr}
+	// TODO: potentially look for dbus-launch in advance and don't issue a warning at all
+	if err := keyring.Set(keyringService, tokenName, encoded); err != nil {
+		path, _ := ProfilesPath()
+		c.UI.Warn(fmt.Sprintf("Unable to save auth token to system credential store (%s); storing it in %s instead.", err, path))
+		prof.Token = encoded
+	}
+	p.Profiles[tokenName] = prof
+	return p.write()
+}
+
+// ReadTokenFromKeyring returns the token stored under tokenName, or nil if
+// there isn't one.
+func (c *Command) ReadTokenFromKeyring(tokenName string) *authtokens.AuthToken {
+	authToken, _ := c.readStoredToken(tokenName)
+	return authToken
+}
+
+// readStoredToken returns the token stored under tokenName and the address
+// of the controller which issued it, if known.
+func (c *Command) readStoredToken(tokenName string) (*authtokens.AuthToken, string) {
+	var addr, token string
+	if p, err := readProfiles(); err != nil {
+		c.UI.Warn(err.Error())
+	} else if prof, ok := p.Profiles[tokenName]; ok {
+		addr, token = prof.Addr, prof.Token
+	}
+	if token == "" {
+		var err error
+		token, err = keyring.Get(keyringService, tokenName)
+		if err != nil {
+			if err == keyring.ErrNotFound {
+				c.UI.Info("No saved credential found, continuing without")
+			} else {
+				// TODO: potentially look for dbus-launch in advance and don't issue a warning at all
+				c.UI.Error(fmt.Sprintf("Error reading auth token from system credential store: %s", err))
+				c.UI.Warn("Token must be provided via BOUNDARY_TOKEN env var or -token flag. Reading the token can also be disabled via -token-name=none.")
+			}
+			token = ""
+		}
+	}
+	if token == "" {
+		return nil, ""
+	}
+	tokenBytes, err := base64.RawStdEncoding.DecodeString(token)
+	switch {
+	case err != nil:
+		c.UI.Error(fmt.Errorf("Error base64-unmarshaling stored token from system credential store: %w", err).Error())
+	case len(tokenBytes) == 0:
+		c.UI.Error("Zero length token after decoding stored token from system credential store")
+	default:
+		var authToken authtokens.AuthToken
+		if err := json.Unmarshal(tokenBytes, &authToken); err != nil {
+			c.UI.Error(fmt.Sprintf("Error unmarshaling stored token information after reading from system credential store: %s", err))
+		} else {
+			return &authToken, addr
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,63 @@
+package base
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/boundary/api/authtokens"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestTokenStore(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	keyring.MockInit()
+
+	dir, err := ioutil.TempDir("", "boundary-profiles")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "boundary", "profiles.json")
+	require.NoError(os.Setenv(EnvProfilesPath, path))
+	defer os.Unsetenv(EnvProfilesPath)
+
+	assert.Equal(DefaultProfile, CurrentProfile())
+	current, list, err := ListProfiles()
+	require.NoError(err)
+	assert.Equal(DefaultProfile, current)
+	assert.Empty(list)
+
+	c := NewCommand(cli.NewMockUi())
+	dev := &authtokens.AuthToken{Id: "at_1234567890", Token: "at_1234567890_dev", UserId: "u_1234567890"}
+	prod := &authtokens.AuthToken{Id: "at_0987654321", Token: "at_0987654321_prod", UserId: "u_0987654321"}
+	require.NoError(c.SaveToken(DefaultProfile, "http://127.0.0.1:9200", dev))
+	require.NoError(c.SaveToken("prod", "https://boundary.example.com", prod))
+
+	info, err := os.Stat(path)
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	current, list, err = ListProfiles()
+	require.NoError(err)
+	assert.Equal(DefaultProfile, current)
+	assert.Equal([]*Profile{
+		{Name: DefaultProfile, Addr: "http://127.0.0.1:9200"},
+		{Name: "prod", Addr: "https://boundary.example.com"},
+	}, list)
+
+	got, addr := c.readStoredToken("prod")
+	require.NotNil(got)
+	assert.Equal(prod.Token, got.Token)
+	assert.Equal("https://boundary.example.com", addr)
+
+	assert.Error(SwitchProfile("staging"))
+	require.NoError(SwitchProfile("prod"))
+	assert.Equal("prod", CurrentProfile())
+
+	got, addr = c.readStoredToken("missing")
+	assert.Nil(got)
+	assert.Empty(addr)
+}
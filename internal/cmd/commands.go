@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/commands/accounts"
+	"github.com/hashicorp/boundary/internal/cmd/commands/apply"
 	"github.com/hashicorp/boundary/internal/cmd/commands/authenticate"
 	"github.com/hashicorp/boundary/internal/cmd/commands/authmethods"
 	"github.com/hashicorp/boundary/internal/cmd/commands/authtokens"
@@ -18,6 +19,7 @@ import (
 	"github.com/hashicorp/boundary/internal/cmd/commands/hostcatalogs"
 	"github.com/hashicorp/boundary/internal/cmd/commands/hosts"
 	"github.com/hashicorp/boundary/internal/cmd/commands/hostsets"
+	"github.com/hashicorp/boundary/internal/cmd/commands/jobs"
 	"github.com/hashicorp/boundary/internal/cmd/commands/roles"
 	"github.com/hashicorp/boundary/internal/cmd/commands/scopes"
 	"github.com/hashicorp/boundary/internal/cmd/commands/server"
@@ -25,6 +27,7 @@ import (
 	"github.com/hashicorp/boundary/internal/cmd/commands/targets"
 	"github.com/hashicorp/boundary/internal/cmd/commands/users"
 	"github.com/hashicorp/boundary/internal/cmd/commands/version"
+	"github.com/hashicorp/boundary/internal/cmd/commands/workers"
 
 	"github.com/mitchellh/cli"
 )
@@ -60,6 +63,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 			}, nil
 		},
 
+		"apply": func() (cli.Command, error) {
+			return &apply.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+
 		"authenticate": func() (cli.Command, error) {
 			return &authenticate.Command{
 				Command: base.NewCommand(ui),
@@ -184,6 +193,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Command: base.NewCommand(ui),
 			}, nil
 		},
+		"auth-tokens create": func() (cli.Command, error) {
+			return &authtokens.Command{
+				Command: base.NewCommand(ui),
+				Func:    "create",
+			}, nil
+		},
 		"auth-tokens read": func() (cli.Command, error) {
 			return &authtokens.Command{
 				Command: base.NewCommand(ui),
@@ -226,6 +241,18 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "get-token",
 			}, nil
 		},
+		"config list-profiles": func() (cli.Command, error) {
+			return &config.ProfilesCommand{
+				Command: base.NewCommand(ui),
+				Func:    "list-profiles",
+			}, nil
+		},
+		"config switch": func() (cli.Command, error) {
+			return &config.ProfilesCommand{
+				Command: base.NewCommand(ui),
+				Func:    "switch",
+			}, nil
+		},
 		"config autocomplete": func() (cli.Command, error) {
 			return &config.AutocompleteCommand{
 				Command: base.NewCommand(ui),
@@ -257,6 +284,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "http",
 			}, nil
 		},
+		"connect kube": func() (cli.Command, error) {
+			return &connect.Command{
+				Command: base.NewCommand(ui),
+				Func:    "kube",
+			}, nil
+		},
 		"connect ssh": func() (cli.Command, error) {
 			return &connect.Command{
 				Command: base.NewCommand(ui),
@@ -286,6 +319,17 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Command: base.NewCommand(ui),
 			}, nil
 		},
+		"database verify": func() (cli.Command, error) {
+			return &database.VerifyCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+
+		"export": func() (cli.Command, error) {
+			return &apply.ExportCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
 
 		"groups": func() (cli.Command, error) {
 			return &groups.Command{
@@ -502,6 +546,11 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "update",
 			}, nil
 		},
+		"hosts import": func() (cli.Command, error) {
+			return &hosts.ImportCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
 
 		"roles": func() (cli.Command, error) {
 			return &roles.Command{
@@ -574,6 +623,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "remove-grants",
 			}, nil
 		},
+		"roles clone": func() (cli.Command, error) {
+			return &roles.Command{
+				Command: base.NewCommand(ui),
+				Func:    "clone",
+			}, nil
+		},
 
 		"scopes": func() (cli.Command, error) {
 			return &scopes.Command{
@@ -760,6 +815,53 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "remove-accounts",
 			}, nil
 		},
+
+		"jobs": func() (cli.Command, error) {
+			return &jobs.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"jobs list": func() (cli.Command, error) {
+			return &jobs.ListCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+
+		"workers": func() (cli.Command, error) {
+			return &workers.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"workers create-activation-token": func() (cli.Command, error) {
+			return &workers.AuthCommand{
+				Command: base.NewCommand(ui),
+				Func:    "create-activation-token",
+			}, nil
+		},
+		"workers revoke-certificates": func() (cli.Command, error) {
+			return &workers.AuthCommand{
+				Command: base.NewCommand(ui),
+				Func:    "revoke-certificates",
+			}, nil
+		},
+		"workers drain": func() (cli.Command, error) {
+			return &workers.DrainCommand{
+				Command: base.NewCommand(ui),
+				Func:    "drain",
+			}, nil
+		},
+		"workers undrain": func() (cli.Command, error) {
+			return &workers.DrainCommand{
+				Command: base.NewCommand(ui),
+				Func:    "undrain",
+			}, nil
+		},
+		"workers drain-status": func() (cli.Command, error) {
+			return &workers.DrainCommand{
+				Command: base.NewCommand(ui),
+				Func:    "drain-status",
+			}, nil
+		},
 	}
 }
 
@@ -28,6 +28,8 @@ type Command struct {
 	flagPassword        string
 	flagCurrentPassword string
 	flagNewPassword     string
+
+	flagMustChangePassword bool
 }
 
 func (c *Command) Synopsis() string {
@@ -44,8 +46,8 @@ func (c *Command) Synopsis() string {
 var flagsMap = map[string][]string{
 	"read":            {"id"},
 	"delete":          {"id"},
-	"list":            {"auth-method-id"},
-	"set-password":    {"id", "password", "version"},
+	"list":            {"auth-method-id", "filter", "sort-by", "sort-dir"},
+	"set-password":    {"id", "password", "must-change-password", "version"},
 	"change-password": {"id", "current-password", "new-password", "version"},
 }
 
@@ -148,6 +150,12 @@ func (c *Command) Flags() *base.FlagSets {
 				Target: &c.flagNewPassword,
 				Usage:  "The new password for the account. If not specified, the command will prompt for the password to be entered in a non-echoing way.",
 			})
+		case "must-change-password":
+			f.BoolVar(&base.BoolVar{
+				Name:   "must-change-password",
+				Target: &c.flagMustChangePassword,
+				Usage:  "If set, the user must change the password before they can authenticate.",
+			})
 		}
 	}
 
@@ -192,6 +200,17 @@ func (c *Command) Run(args []string) int {
 
 	var opts []accounts.Option
 
+	if c.FlagFilter != "" {
+		opts = append(opts, accounts.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, accounts.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, accounts.WithSortDir(c.FlagSortDir))
+	}
+
 	switch c.FlagName {
 	case "":
 	case "null":
@@ -273,6 +292,9 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listResult, err = accountClient.List(c.Context, c.FlagAuthMethodId, opts...)
 	case "set-password":
+		if c.flagMustChangePassword {
+			opts = append(opts, accounts.WithMustChangePassword(true))
+		}
 		result, err = accountClient.SetPassword(c.Context, c.FlagId, c.flagPassword, version, opts...)
 	case "change-password":
 		result, err = accountClient.ChangePassword(c.Context, c.FlagId, c.flagCurrentPassword, c.flagNewPassword, version, opts...)
@@ -294,7 +316,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -311,17 +333,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		accounts := listResult.GetItems().([]*accounts.Account)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(accounts) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(accounts)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(accounts); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(accounts) == 0 {
@@ -364,13 +384,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateAccountTableOutput(account))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(account)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(account); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
@@ -215,13 +215,11 @@ func (c *PasswordCommand) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateAccountTableOutput(account))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(account)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(account); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
@@ -0,0 +1,627 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authmethods"
+	"github.com/hashicorp/boundary/api/groups"
+	"github.com/hashicorp/boundary/api/hostcatalogs"
+	"github.com/hashicorp/boundary/api/hosts"
+	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/roles"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/api/users"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// Change is a resource apply created or updated, or would have with
+// -dry-run. The id of a resource which would be created is empty.
+type Change struct {
+	Action string `json:"action"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Id     string `json:"id,omitempty"`
+	// Parent is the name of the scope or host catalog the resource is in.
+	Parent string `json:"parent"`
+}
+
+// applier creates and updates the resources in a manifest. With dryRun set
+// it only records the changes it would make. The ids of resources which
+// don't exist yet are empty in a dry run, and their children are all
+// created.
+type applier struct {
+	ctx     context.Context
+	client  *api.Client
+	dryRun  bool
+	changes []*Change
+}
+
+func (a *applier) record(action, typ, name, id, parent string) {
+	a.changes = append(a.changes, &Change{Action: action, Type: typ, Name: name, Id: id, Parent: parent})
+}
+
+// needsUpdate compares a wanted value to an existing one, recording an
+// update of the resource the first time they differ.
+type needsUpdate struct {
+	a             *applier
+	typ, name, id string
+	parent        string
+	recorded      bool
+}
+
+func (n *needsUpdate) check(differ bool) bool {
+	if differ && !n.recorded {
+		n.a.record("update", n.typ, n.name, n.id, n.parent)
+		n.recorded = true
+	}
+	return differ
+}
+
+// sameSet reports whether want, if managed, has the same entries as have.
+func sameSet(want, have []string) bool {
+	if want == nil {
+		return true
+	}
+	if len(want) != len(have) {
+		return false
+	}
+	w, h := append([]string(nil), want...), append([]string(nil), have...)
+	sort.Strings(w)
+	sort.Strings(h)
+	for i := range w {
+		if w[i] != h[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// resolve maps names to ids using byName. Values which aren't names of
+// resources, but have the given id prefix if there is one, are used as ids.
+func resolve(typ string, in []string, byName map[string]string, prefix string) ([]string, error) {
+	if in == nil {
+		return nil, nil
+	}
+	out := make([]string, 0, len(in))
+	for _, name := range in {
+		switch id, ok := byName[name]; {
+		case ok:
+			out = append(out, id)
+		case prefix != "" && strings.HasPrefix(name, prefix):
+			out = append(out, name)
+		default:
+			return nil, fmt.Errorf("unknown %s %q", typ, name)
+		}
+	}
+	return out, nil
+}
+
+func (a *applier) apply(m *Manifest) error {
+	for _, o := range m.Orgs {
+		if err := a.applyOrg(o); err != nil {
+			return fmt.Errorf("org %q: %w", o.Name, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyScope(parentId, parent, typ, name, description string) (string, error) {
+	sc := scopes.NewClient(a.client)
+	var existing []*scopes.Scope
+	if parentId != "" {
+		l, err := sc.List(a.ctx, parentId)
+		if err != nil {
+			return "", fmt.Errorf("listing scopes: %w", err)
+		}
+		existing = l.Items
+	}
+	for _, s := range existing {
+		if s.Name != name {
+			continue
+		}
+		if s.Description != description {
+			a.record("update", typ, name, s.Id, parent)
+			if !a.dryRun {
+				if _, err := sc.Update(a.ctx, s.Id, s.Version, scopes.WithDescription(description)); err != nil {
+					return "", fmt.Errorf("updating %s: %w", typ, err)
+				}
+			}
+		}
+		return s.Id, nil
+	}
+	a.record("create", typ, name, "", parent)
+	if a.dryRun {
+		return "", nil
+	}
+	s, err := sc.Create(a.ctx, parentId, scopes.WithName(name), scopes.WithDescription(description))
+	if err != nil {
+		return "", fmt.Errorf("creating %s: %w", typ, err)
+	}
+	return s.Item.Id, nil
+}
+
+func (a *applier) applyOrg(o *Org) error {
+	orgId, err := a.applyScope(scope.Global.String(), "global", "org", o.Name, o.Description)
+	if err != nil {
+		return err
+	}
+	for _, am := range o.AuthMethods {
+		if err := a.applyAuthMethod(orgId, o.Name, am); err != nil {
+			return fmt.Errorf("auth method %q: %w", am.Name, err)
+		}
+	}
+	userIds, err := a.applyUsers(orgId, o.Name, o.Users)
+	if err != nil {
+		return err
+	}
+	if err := a.applyGroupsAndRoles(orgId, o.Name, userIds, o.Groups, o.Roles); err != nil {
+		return err
+	}
+	for _, p := range o.Projects {
+		if err := a.applyProject(orgId, o.Name, userIds, p); err != nil {
+			return fmt.Errorf("project %q: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyProject(orgId, org string, userIds map[string]string, p *Project) error {
+	projId, err := a.applyScope(orgId, org, "project", p.Name, p.Description)
+	if err != nil {
+		return err
+	}
+	if err := a.applyGroupsAndRoles(projId, p.Name, userIds, p.Groups, p.Roles); err != nil {
+		return err
+	}
+	hostSetIds := make(map[string]string)
+	for _, hc := range p.HostCatalogs {
+		if err := a.applyHostCatalog(projId, p.Name, hc, hostSetIds); err != nil {
+			return fmt.Errorf("host catalog %q: %w", hc.Name, err)
+		}
+	}
+	for _, t := range p.Targets {
+		if err := a.applyTarget(projId, p.Name, hostSetIds, t); err != nil {
+			return fmt.Errorf("target %q: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyAuthMethod(scopeId, parent string, am *AuthMethod) error {
+	amc := authmethods.NewClient(a.client)
+	var existing []*authmethods.AuthMethod
+	if scopeId != "" {
+		l, err := amc.List(a.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("listing: %w", err)
+		}
+		existing = l.Items
+	}
+	for _, e := range existing {
+		if e.Name != am.Name {
+			continue
+		}
+		if e.Description != am.Description {
+			a.record("update", "auth method", am.Name, e.Id, parent)
+			if !a.dryRun {
+				if _, err := amc.Update(a.ctx, e.Id, e.Version, authmethods.WithDescription(am.Description)); err != nil {
+					return fmt.Errorf("updating: %w", err)
+				}
+			}
+		}
+		return nil
+	}
+	a.record("create", "auth method", am.Name, "", parent)
+	if a.dryRun {
+		return nil
+	}
+	if _, err := amc.Create(a.ctx, "password", scopeId, authmethods.WithName(am.Name), authmethods.WithDescription(am.Description)); err != nil {
+		return fmt.Errorf("creating: %w", err)
+	}
+	return nil
+}
+
+// applyUsers returns the ids of the users in the scope by name, including
+// users not in the manifest.
+func (a *applier) applyUsers(scopeId, parent string, in []*User) (map[string]string, error) {
+	uc := users.NewClient(a.client)
+	ids := make(map[string]string)
+	existing := make(map[string]*users.User)
+	if scopeId != "" {
+		l, err := uc.List(a.ctx, scopeId)
+		if err != nil {
+			return nil, fmt.Errorf("listing users: %w", err)
+		}
+		for _, u := range l.Items {
+			if u.Name != "" {
+				existing[u.Name], ids[u.Name] = u, u.Id
+			}
+		}
+	}
+	for _, u := range in {
+		if e, ok := existing[u.Name]; ok {
+			if e.Description != u.Description {
+				a.record("update", "user", u.Name, e.Id, parent)
+				if !a.dryRun {
+					if _, err := uc.Update(a.ctx, e.Id, e.Version, users.WithDescription(u.Description)); err != nil {
+						return nil, fmt.Errorf("user %q: updating: %w", u.Name, err)
+					}
+				}
+			}
+			continue
+		}
+		a.record("create", "user", u.Name, "", parent)
+		ids[u.Name] = ""
+		if a.dryRun {
+			continue
+		}
+		res, err := uc.Create(a.ctx, scopeId, users.WithName(u.Name), users.WithDescription(u.Description))
+		if err != nil {
+			return nil, fmt.Errorf("user %q: creating: %w", u.Name, err)
+		}
+		ids[u.Name] = res.Item.Id
+	}
+	return ids, nil
+}
+
+func (a *applier) applyGroupsAndRoles(scopeId, parent string, userIds map[string]string, inGroups []*Group, inRoles []*Role) error {
+	gc := groups.NewClient(a.client)
+	groupIds := make(map[string]string)
+	existingGroups := make(map[string]*groups.Group)
+	if scopeId != "" {
+		l, err := gc.List(a.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("listing groups: %w", err)
+		}
+		for _, g := range l.Items {
+			if g.Name != "" {
+				existingGroups[g.Name], groupIds[g.Name] = g, g.Id
+			}
+		}
+	}
+	for _, g := range inGroups {
+		members, err := resolve("user", g.Members, userIds, "u_")
+		if err != nil {
+			return fmt.Errorf("group %q: %w", g.Name, err)
+		}
+		if err := a.applyGroup(gc, scopeId, parent, g, existingGroups[g.Name], members, groupIds); err != nil {
+			return fmt.Errorf("group %q: %w", g.Name, err)
+		}
+	}
+
+	rc := roles.NewClient(a.client)
+	existingRoles := make(map[string]*roles.Role)
+	if scopeId != "" {
+		l, err := rc.List(a.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("listing roles: %w", err)
+		}
+		for _, r := range l.Items {
+			if r.Name != "" {
+				existingRoles[r.Name] = r
+			}
+		}
+	}
+	for _, r := range inRoles {
+		var principals []string
+		if r.Users != nil || r.Groups != nil {
+			u, err := resolve("user", append([]string{}, r.Users...), userIds, "u_")
+			if err != nil {
+				return fmt.Errorf("role %q: %w", r.Name, err)
+			}
+			g, err := resolve("group", append([]string{}, r.Groups...), groupIds, "g_")
+			if err != nil {
+				return fmt.Errorf("role %q: %w", r.Name, err)
+			}
+			principals = append(u, g...)
+		}
+		if err := a.applyRole(rc, scopeId, parent, r, existingRoles[r.Name], principals); err != nil {
+			return fmt.Errorf("role %q: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyGroup(gc *groups.Client, scopeId, parent string, g *Group, e *groups.Group, members []string, groupIds map[string]string) error {
+	if e == nil {
+		a.record("create", "group", g.Name, "", parent)
+		groupIds[g.Name] = ""
+		if a.dryRun {
+			return nil
+		}
+		res, err := gc.Create(a.ctx, scopeId, groups.WithName(g.Name), groups.WithDescription(g.Description))
+		if err != nil {
+			return fmt.Errorf("creating: %w", err)
+		}
+		groupIds[g.Name] = res.Item.Id
+		if len(members) > 0 {
+			if _, err := gc.SetMembers(a.ctx, res.Item.Id, res.Item.Version, members); err != nil {
+				return fmt.Errorf("setting members: %w", err)
+			}
+		}
+		return nil
+	}
+	n := &needsUpdate{a: a, typ: "group", name: g.Name, id: e.Id, parent: parent}
+	version := e.Version
+	if n.check(e.Description != g.Description) && !a.dryRun {
+		res, err := gc.Update(a.ctx, e.Id, version, groups.WithDescription(g.Description))
+		if err != nil {
+			return fmt.Errorf("updating: %w", err)
+		}
+		version = res.Item.Version
+	}
+	if n.check(!sameSet(members, e.MemberIds)) && !a.dryRun {
+		if _, err := gc.SetMembers(a.ctx, e.Id, version, members); err != nil {
+			return fmt.Errorf("setting members: %w", err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyRole(rc *roles.Client, scopeId, parent string, r *Role, e *roles.Role, principals []string) error {
+	if e == nil {
+		a.record("create", "role", r.Name, "", parent)
+		if a.dryRun {
+			return nil
+		}
+		res, err := rc.Create(a.ctx, scopeId, roles.WithName(r.Name), roles.WithDescription(r.Description))
+		if err != nil {
+			return fmt.Errorf("creating: %w", err)
+		}
+		version := res.Item.Version
+		if len(r.Grants) > 0 {
+			res, err := rc.SetGrants(a.ctx, res.Item.Id, version, r.Grants)
+			if err != nil {
+				return fmt.Errorf("setting grants: %w", err)
+			}
+			version = res.Item.Version
+		}
+		if len(principals) > 0 {
+			if _, err := rc.SetPrincipals(a.ctx, res.Item.Id, version, principals); err != nil {
+				return fmt.Errorf("setting principals: %w", err)
+			}
+		}
+		return nil
+	}
+	n := &needsUpdate{a: a, typ: "role", name: r.Name, id: e.Id, parent: parent}
+	version := e.Version
+	if n.check(e.Description != r.Description) && !a.dryRun {
+		res, err := rc.Update(a.ctx, e.Id, version, roles.WithDescription(r.Description))
+		if err != nil {
+			return fmt.Errorf("updating: %w", err)
+		}
+		version = res.Item.Version
+	}
+	if n.check(!sameSet(r.Grants, e.GrantStrings)) && !a.dryRun {
+		res, err := rc.SetGrants(a.ctx, e.Id, version, r.Grants)
+		if err != nil {
+			return fmt.Errorf("setting grants: %w", err)
+		}
+		version = res.Item.Version
+	}
+	if n.check(!sameSet(principals, e.PrincipalIds)) && !a.dryRun {
+		if _, err := rc.SetPrincipals(a.ctx, e.Id, version, principals); err != nil {
+			return fmt.Errorf("setting principals: %w", err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyHostCatalog(scopeId, parent string, hc *HostCatalog, hostSetIds map[string]string) error {
+	hcc := hostcatalogs.NewClient(a.client)
+	var catalogId string
+	var existing []*hostcatalogs.HostCatalog
+	if scopeId != "" {
+		l, err := hcc.List(a.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("listing: %w", err)
+		}
+		existing = l.Items
+	}
+	found := false
+	for _, e := range existing {
+		if e.Name != hc.Name {
+			continue
+		}
+		found, catalogId = true, e.Id
+		if e.Description != hc.Description {
+			a.record("update", "host catalog", hc.Name, e.Id, parent)
+			if !a.dryRun {
+				if _, err := hcc.Update(a.ctx, e.Id, e.Version, hostcatalogs.WithDescription(hc.Description)); err != nil {
+					return fmt.Errorf("updating: %w", err)
+				}
+			}
+		}
+		break
+	}
+	if !found {
+		a.record("create", "host catalog", hc.Name, "", parent)
+		if !a.dryRun {
+			res, err := hcc.Create(a.ctx, "static", scopeId, hostcatalogs.WithName(hc.Name), hostcatalogs.WithDescription(hc.Description))
+			if err != nil {
+				return fmt.Errorf("creating: %w", err)
+			}
+			catalogId = res.Item.Id
+		}
+	}
+
+	hostIds, err := a.applyHosts(catalogId, hc.Name, hc.Hosts)
+	if err != nil {
+		return err
+	}
+	return a.applyHostSets(catalogId, hc.Name, hostIds, hc.HostSets, hostSetIds)
+}
+
+func (a *applier) applyHosts(catalogId, parent string, in []*Host) (map[string]string, error) {
+	hc := hosts.NewClient(a.client)
+	ids := make(map[string]string)
+	existing := make(map[string]*hosts.Host)
+	if catalogId != "" {
+		l, err := hc.List(a.ctx, catalogId)
+		if err != nil {
+			return nil, fmt.Errorf("listing hosts: %w", err)
+		}
+		for _, h := range l.Items {
+			if h.Name != "" {
+				existing[h.Name], ids[h.Name] = h, h.Id
+			}
+		}
+	}
+	for _, h := range in {
+		e, ok := existing[h.Name]
+		if !ok {
+			a.record("create", "host", h.Name, "", parent)
+			ids[h.Name] = ""
+			if a.dryRun {
+				continue
+			}
+			res, err := hc.Create(a.ctx, catalogId, hosts.WithName(h.Name), hosts.WithDescription(h.Description), hosts.WithStaticHostAddress(h.Address))
+			if err != nil {
+				return nil, fmt.Errorf("host %q: creating: %w", h.Name, err)
+			}
+			ids[h.Name] = res.Item.Id
+			continue
+		}
+		address, _ := e.Attributes["address"].(string)
+		if e.Description != h.Description || address != h.Address {
+			a.record("update", "host", h.Name, e.Id, parent)
+			if !a.dryRun {
+				if _, err := hc.Update(a.ctx, e.Id, e.Version, hosts.WithDescription(h.Description), hosts.WithStaticHostAddress(h.Address)); err != nil {
+					return nil, fmt.Errorf("host %q: updating: %w", h.Name, err)
+				}
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (a *applier) applyHostSets(catalogId, parent string, hostIds map[string]string, in []*HostSet, hostSetIds map[string]string) error {
+	hsc := hostsets.NewClient(a.client)
+	existing := make(map[string]*hostsets.HostSet)
+	if catalogId != "" {
+		l, err := hsc.List(a.ctx, catalogId)
+		if err != nil {
+			return fmt.Errorf("listing host sets: %w", err)
+		}
+		for _, hs := range l.Items {
+			if hs.Name != "" {
+				existing[hs.Name] = hs
+			}
+		}
+	}
+	for _, hs := range in {
+		wantHosts, err := resolve("host", hs.Hosts, hostIds, "")
+		if err != nil {
+			return fmt.Errorf("host set %q: %w", hs.Name, err)
+		}
+		e, ok := existing[hs.Name]
+		if !ok {
+			a.record("create", "host set", hs.Name, "", parent)
+			hostSetIds[hs.Name] = ""
+			if a.dryRun {
+				continue
+			}
+			res, err := hsc.Create(a.ctx, catalogId, hostsets.WithName(hs.Name), hostsets.WithDescription(hs.Description))
+			if err != nil {
+				return fmt.Errorf("host set %q: creating: %w", hs.Name, err)
+			}
+			hostSetIds[hs.Name] = res.Item.Id
+			if len(wantHosts) > 0 {
+				if _, err := hsc.SetHosts(a.ctx, res.Item.Id, res.Item.Version, wantHosts); err != nil {
+					return fmt.Errorf("host set %q: setting hosts: %w", hs.Name, err)
+				}
+			}
+			continue
+		}
+		hostSetIds[hs.Name] = e.Id
+		n := &needsUpdate{a: a, typ: "host set", name: hs.Name, id: e.Id, parent: parent}
+		version := e.Version
+		if n.check(e.Description != hs.Description) && !a.dryRun {
+			res, err := hsc.Update(a.ctx, e.Id, version, hostsets.WithDescription(hs.Description))
+			if err != nil {
+				return fmt.Errorf("host set %q: updating: %w", hs.Name, err)
+			}
+			version = res.Item.Version
+		}
+		if n.check(!sameSet(wantHosts, e.HostIds)) && !a.dryRun {
+			if _, err := hsc.SetHosts(a.ctx, e.Id, version, wantHosts); err != nil {
+				return fmt.Errorf("host set %q: setting hosts: %w", hs.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyTarget(scopeId, parent string, hostSetIds map[string]string, t *Target) error {
+	tc := targets.NewClient(a.client)
+	wantHostSets, err := resolve("host set", t.HostSets, hostSetIds, "")
+	if err != nil {
+		return err
+	}
+	opts := []targets.Option{targets.WithDescription(t.Description)}
+	if t.DefaultPort != 0 {
+		opts = append(opts, targets.WithTcpTargetDefaultPort(t.DefaultPort))
+	}
+	if t.SessionMaxSeconds != 0 {
+		opts = append(opts, targets.WithSessionMaxSeconds(t.SessionMaxSeconds))
+	}
+	if t.SessionConnectionLimit != 0 {
+		opts = append(opts, targets.WithSessionConnectionLimit(t.SessionConnectionLimit))
+	}
+
+	var e *targets.Target
+	if scopeId != "" {
+		l, err := tc.List(a.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("listing: %w", err)
+		}
+		for _, item := range l.Items {
+			if item.Name == t.Name {
+				e = item
+				break
+			}
+		}
+	}
+	if e == nil {
+		a.record("create", "target", t.Name, "", parent)
+		if a.dryRun {
+			return nil
+		}
+		res, err := tc.Create(a.ctx, "tcp", scopeId, append(opts, targets.WithName(t.Name))...)
+		if err != nil {
+			return fmt.Errorf("creating: %w", err)
+		}
+		if len(wantHostSets) > 0 {
+			if _, err := tc.SetHostSets(a.ctx, res.Item.Id, res.Item.Version, wantHostSets); err != nil {
+				return fmt.Errorf("setting host sets: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// Ports are decoded from json as numbers
+	port, _ := e.Attributes["default_port"].(float64)
+	n := &needsUpdate{a: a, typ: "target", name: t.Name, id: e.Id, parent: parent}
+	version := e.Version
+	if n.check(e.Description != t.Description ||
+		(t.DefaultPort != 0 && uint32(port) != t.DefaultPort) ||
+		(t.SessionMaxSeconds != 0 && e.SessionMaxSeconds != t.SessionMaxSeconds) ||
+		(t.SessionConnectionLimit != 0 && e.SessionConnectionLimit != t.SessionConnectionLimit)) && !a.dryRun {
+		res, err := tc.Update(a.ctx, e.Id, version, opts...)
+		if err != nil {
+			return fmt.Errorf("updating: %w", err)
+		}
+		version = res.Item.Version
+	}
+	if n.check(!sameSet(wantHostSets, e.HostSetIds)) && !a.dryRun {
+		if _, err := tc.SetHostSets(a.ctx, e.Id, version, wantHostSets); err != nil {
+			return fmt.Errorf("setting host sets: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/internal/servers/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `
+orgs:
+- name: eng
+  users:
+  - name: alice
+  groups:
+  - name: dbas
+    members: [alice]
+  projects:
+  - name: databases
+    roles:
+    - name: connect
+      grants: ["id=*;type=target;actions=authorize-session"]
+      groups: [dbas]
+    host_catalogs:
+    - name: postgres
+      hosts:
+      - name: pg1
+        address: 10.0.0.10
+      host_sets:
+      - name: postgres
+        hosts: [pg1]
+    targets:
+    - name: postgres
+      description: %s
+      default_port: 5432
+      host_sets: [postgres]
+`
+
+func TestApply(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	tc := controller.NewTestController(t, nil)
+	defer tc.Shutdown()
+	client := tc.Client()
+	client.SetToken(tc.Token().Token)
+	ctx := context.Background()
+
+	m, err := ParseManifest([]byte(fmt.Sprintf(testManifest, "primary")))
+	require.NoError(err)
+
+	// A dry run makes no changes
+	a := &applier{ctx: ctx, client: client, dryRun: true}
+	require.NoError(a.apply(m))
+	assert.Len(a.changes, 9)
+	a = &applier{ctx: ctx, client: client, dryRun: true}
+	require.NoError(a.apply(m))
+	assert.Len(a.changes, 9)
+
+	a = &applier{ctx: ctx, client: client}
+	require.NoError(a.apply(m))
+	var created []string
+	for _, ch := range a.changes {
+		assert.Equal("create", ch.Action)
+		created = append(created, ch.Type)
+	}
+	assert.Equal([]string{"org", "user", "group", "project", "role", "host catalog", "host", "host set", "target"}, created)
+
+	// Applying again changes nothing
+	a = &applier{ctx: ctx, client: client}
+	require.NoError(a.apply(m))
+	assert.Empty(a.changes)
+
+	m, err = ParseManifest([]byte(fmt.Sprintf(testManifest, "replica")))
+	require.NoError(err)
+	a = &applier{ctx: ctx, client: client}
+	require.NoError(a.apply(m))
+	require.Len(a.changes, 1)
+	assert.Equal("update", a.changes[0].Action)
+	assert.Equal("target", a.changes[0].Type)
+
+	tgt, err := targets.NewClient(client).Read(ctx, a.changes[0].Id)
+	require.NoError(err)
+	assert.Equal("replica", tgt.Item.Description)
+	require.Len(tgt.Item.HostSetIds, 1)
+	hs, err := hostsets.NewClient(client).Read(ctx, tgt.Item.HostSetIds[0])
+	require.NoError(err)
+	assert.Equal("postgres", hs.Item.Name)
+	assert.Len(hs.Item.HostIds, 1)
+}
@@ -0,0 +1,162 @@
+package apply
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+// Command creates and updates the resources described in a manifest.
+type Command struct {
+	*base.Command
+
+	flagFile   string
+	flagDryRun bool
+}
+
+func (c *Command) Synopsis() string {
+	return "Create and update resources from a manifest"
+}
+
+func (c *Command) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary apply [options]",
+		"",
+		"  Create and update the orgs, projects, auth methods, users, groups, roles, host catalogs and targets described in a YAML or JSON manifest. Resources are matched to existing ones by name, so applying the same manifest again changes nothing. Resources which aren't in the manifest are left alone. Example:",
+		"",
+		`    $ boundary apply -file boundary.yaml`,
+		"",
+		"  A manifest looks like:",
+		"",
+		"    orgs:",
+		"    - name: eng",
+		"      users:",
+		"      - name: alice",
+		"      groups:",
+		"      - name: dbas",
+		"        members: [alice]",
+		"      projects:",
+		"      - name: databases",
+		"        roles:",
+		"        - name: connect",
+		`          grants: ["id=*;type=target;actions=authorize-session"]`,
+		"          groups: [dbas]",
+		"        host_catalogs:",
+		"        - name: postgres",
+		"          hosts:",
+		"          - name: pg1",
+		"            address: 10.0.0.10",
+		"          host_sets:",
+		"          - name: postgres",
+		"            hosts: [pg1]",
+		"        targets:",
+		"        - name: postgres",
+		"          default_port: 5432",
+		"          host_sets: [postgres]",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:       "file",
+		Target:     &c.flagFile,
+		Completion: complete.PredictFiles("*"),
+		Usage:      "The path of the YAML or JSON manifest to apply.",
+	})
+
+	f.BoolVar(&base.BoolVar{
+		Name:   "dry-run",
+		Target: &c.flagDryRun,
+		Usage:  "If set, print the changes which would be made without making them.",
+	})
+
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.flagFile == "" {
+		c.UI.Error("Manifest must be provided via -file")
+		return 1
+	}
+	m, err := LoadManifest(c.flagFile)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading manifest: %s", err))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	a := &applier{ctx: c.Context, client: client, dryRun: c.flagDryRun}
+	applyErr := a.apply(m)
+
+	switch base.Format(c.UI) {
+	case "json", "yaml":
+		if err := c.PrintStructured(a.changes); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
+			return 1
+		}
+	default:
+		c.UI.Output(generateChangesTableOutput(a.changes, c.flagDryRun))
+	}
+
+	if applyErr != nil {
+		if apiErr := api.AsServerError(applyErr); apiErr != nil {
+			// The error is prefixed with the resource being applied
+			where := strings.TrimSuffix(strings.TrimSuffix(applyErr.Error(), apiErr.Error()), ": ")
+			c.UI.Error(fmt.Sprintf("Error from controller when applying manifest (%s): %s", where, base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error applying manifest: %s", applyErr))
+		return 2
+	}
+	return 0
+}
+
+func generateChangesTableOutput(changes []*Change, dryRun bool) string {
+	if len(changes) == 0 {
+		return "No changes."
+	}
+	header := "Changes made:"
+	if dryRun {
+		header = "Changes which would be made:"
+	}
+	output := []string{"", header}
+	for _, ch := range changes {
+		line := fmt.Sprintf("  %s %s %q in %s", ch.Action, ch.Type, ch.Name, ch.Parent)
+		if ch.Id != "" {
+			line += fmt.Sprintf(" (%s)", ch.Id)
+		}
+		output = append(output, line)
+	}
+	return base.WrapForHelpText(output)
+}
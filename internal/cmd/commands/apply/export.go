@@ -0,0 +1,105 @@
+package apply
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ExportCommand)(nil)
+var _ cli.CommandAutocomplete = (*ExportCommand)(nil)
+
+// ExportCommand prints the resources in a scope as a manifest which apply
+// can recreate them from.
+type ExportCommand struct {
+	*base.Command
+}
+
+func (c *ExportCommand) Synopsis() string {
+	return "Export resources as a manifest"
+}
+
+func (c *ExportCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary export [options]",
+		"",
+		"  Print the orgs, projects, auth methods, users, groups, roles, host catalogs and targets in the given scope as a manifest which \"boundary apply\" can recreate them from, such as in another environment. If the scope is the global scope every org is exported. The manifest is printed as YAML unless the format is json. Example:",
+		"",
+		`    $ boundary export -scope-id o_1234567890 > boundary.yaml`,
+		"",
+		"  Secrets such as passwords can't be read and so aren't exported, and the recreated resources have new IDs. Resources with no name, or of types apply doesn't manage, are skipped and listed on stderr.",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *ExportCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:    "scope-id",
+		Target:  &c.FlagScopeId,
+		Default: scope.Global.String(),
+		EnvVar:  "BOUNDARY_SCOPE_ID",
+		Usage:   "The global scope or the org to export.",
+	})
+
+	return set
+}
+
+func (c *ExportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ExportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ExportCommand) Run(args []string) int {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	e := &exporter{ctx: c.Context, client: client}
+	m, err := e.export(c.FlagScopeId)
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			c.UI.Error(fmt.Sprintf("Error from controller when exporting: %s", base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error exporting: %s", err))
+		return 2
+	}
+	for _, s := range e.skipped {
+		c.UI.Warn(fmt.Sprintf("Skipped %s", s))
+	}
+
+	switch base.Format(c.UI) {
+	case "json", "yaml":
+		if err := c.PrintStructured(m); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
+			return 1
+		}
+	default:
+		b, err := base.YamlFormatter{}.Format(m)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	}
+	return 0
+}
@@ -0,0 +1,336 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authmethods"
+	"github.com/hashicorp/boundary/api/groups"
+	"github.com/hashicorp/boundary/api/hostcatalogs"
+	"github.com/hashicorp/boundary/api/hosts"
+	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/roles"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/api/users"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// exporter reads the resources in orgs into a manifest which apply can
+// recreate them from. Secrets such as passwords aren't readable and so
+// aren't exported. Resources which can't be described by a manifest, because
+// they have no name or are of a type apply doesn't manage, are skipped and
+// noted.
+type exporter struct {
+	ctx     context.Context
+	client  *api.Client
+	skipped []string
+}
+
+func (e *exporter) skip(typ, id, parent, why string) {
+	e.skipped = append(e.skipped, fmt.Sprintf("%s %s in %s: %s", typ, id, parent, why))
+}
+
+// export exports the org with the given id, or every org if it's the global
+// scope.
+func (e *exporter) export(scopeId string) (*Manifest, error) {
+	sc := scopes.NewClient(e.client)
+	var orgs []*scopes.Scope
+	switch {
+	case scopeId == scope.Global.String():
+		l, err := sc.List(e.ctx, scopeId)
+		if err != nil {
+			return nil, fmt.Errorf("listing orgs: %w", err)
+		}
+		orgs = l.Items
+	case strings.HasPrefix(scopeId, scope.Org.Prefix()+"_"):
+		r, err := sc.Read(e.ctx, scopeId)
+		if err != nil {
+			return nil, fmt.Errorf("reading org: %w", err)
+		}
+		orgs = []*scopes.Scope{r.Item}
+	default:
+		return nil, fmt.Errorf("scope %q is neither the global scope nor an org", scopeId)
+	}
+
+	m := new(Manifest)
+	for _, s := range orgs {
+		if s.Name == "" {
+			e.skip("org", s.Id, "global", "it has no name")
+			continue
+		}
+		o, err := e.exportOrg(s)
+		if err != nil {
+			return nil, fmt.Errorf("org %q: %w", s.Name, err)
+		}
+		m.Orgs = append(m.Orgs, o)
+	}
+	sort.Slice(m.Orgs, func(i, j int) bool { return m.Orgs[i].Name < m.Orgs[j].Name })
+	return m, nil
+}
+
+func (e *exporter) exportOrg(s *scopes.Scope) (*Org, error) {
+	o := &Org{Name: s.Name, Description: s.Description}
+	parent := fmt.Sprintf("org %q", s.Name)
+
+	aml, err := authmethods.NewClient(e.client).List(e.ctx, s.Id)
+	if err != nil {
+		return nil, fmt.Errorf("listing auth methods: %w", err)
+	}
+	for _, am := range aml.Items {
+		switch {
+		case am.Type != "password":
+			e.skip("auth method", am.Id, parent, fmt.Sprintf("%s auth methods aren't supported", am.Type))
+		case am.Name == "":
+			e.skip("auth method", am.Id, parent, "it has no name")
+		default:
+			o.AuthMethods = append(o.AuthMethods, &AuthMethod{Name: am.Name, Description: am.Description})
+		}
+	}
+	sort.Slice(o.AuthMethods, func(i, j int) bool { return o.AuthMethods[i].Name < o.AuthMethods[j].Name })
+
+	// Users are referred to by name where they have one, and by id otherwise
+	userNames := make(map[string]string)
+	ul, err := users.NewClient(e.client).List(e.ctx, s.Id)
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	for _, u := range ul.Items {
+		if u.Name == "" {
+			e.skip("user", u.Id, parent, "it has no name")
+			continue
+		}
+		userNames[u.Id] = u.Name
+		o.Users = append(o.Users, &User{Name: u.Name, Description: u.Description})
+	}
+	sort.Slice(o.Users, func(i, j int) bool { return o.Users[i].Name < o.Users[j].Name })
+
+	if o.Groups, o.Roles, err = e.exportGroupsAndRoles(s.Id, parent, userNames); err != nil {
+		return nil, err
+	}
+
+	pl, err := scopes.NewClient(e.client).List(e.ctx, s.Id)
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+	for _, ps := range pl.Items {
+		if ps.Name == "" {
+			e.skip("project", ps.Id, parent, "it has no name")
+			continue
+		}
+		p, err := e.exportProject(ps, parent, userNames)
+		if err != nil {
+			return nil, fmt.Errorf("project %q: %w", ps.Name, err)
+		}
+		o.Projects = append(o.Projects, p)
+	}
+	sort.Slice(o.Projects, func(i, j int) bool { return o.Projects[i].Name < o.Projects[j].Name })
+	return o, nil
+}
+
+func (e *exporter) exportGroupsAndRoles(scopeId, parent string, userNames map[string]string) ([]*Group, []*Role, error) {
+	name := func(names map[string]string, id string) string {
+		if n, ok := names[id]; ok {
+			return n
+		}
+		return id
+	}
+
+	var outGroups []*Group
+	groupNames := make(map[string]string)
+	gc := groups.NewClient(e.client)
+	gl, err := gc.List(e.ctx, scopeId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing groups: %w", err)
+	}
+	for _, item := range gl.Items {
+		if item.Name == "" {
+			e.skip("group", item.Id, parent, "it has no name")
+			continue
+		}
+		groupNames[item.Id] = item.Name
+		r, err := gc.Read(e.ctx, item.Id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading group %q: %w", item.Name, err)
+		}
+		g := &Group{Name: item.Name, Description: item.Description, Members: []string{}}
+		for _, id := range r.Item.MemberIds {
+			g.Members = append(g.Members, name(userNames, id))
+		}
+		sort.Strings(g.Members)
+		outGroups = append(outGroups, g)
+	}
+	sort.Slice(outGroups, func(i, j int) bool { return outGroups[i].Name < outGroups[j].Name })
+
+	var outRoles []*Role
+	rc := roles.NewClient(e.client)
+	rl, err := rc.List(e.ctx, scopeId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing roles: %w", err)
+	}
+	for _, item := range rl.Items {
+		if item.Name == "" {
+			e.skip("role", item.Id, parent, "it has no name")
+			continue
+		}
+		r, err := rc.Read(e.ctx, item.Id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading role %q: %w", item.Name, err)
+		}
+		role := &Role{
+			Name:        item.Name,
+			Description: item.Description,
+			Grants:      append([]string{}, r.Item.GrantStrings...),
+			Users:       []string{},
+			Groups:      []string{},
+		}
+		for _, p := range r.Item.Principals {
+			switch p.Type {
+			case "group":
+				role.Groups = append(role.Groups, name(groupNames, p.Id))
+			default:
+				role.Users = append(role.Users, name(userNames, p.Id))
+			}
+		}
+		sort.Strings(role.Users)
+		sort.Strings(role.Groups)
+		outRoles = append(outRoles, role)
+	}
+	sort.Slice(outRoles, func(i, j int) bool { return outRoles[i].Name < outRoles[j].Name })
+	return outGroups, outRoles, nil
+}
+
+func (e *exporter) exportProject(s *scopes.Scope, org string, userNames map[string]string) (*Project, error) {
+	p := &Project{Name: s.Name, Description: s.Description}
+	parent := fmt.Sprintf("project %q in %s", s.Name, org)
+
+	var err error
+	if p.Groups, p.Roles, err = e.exportGroupsAndRoles(s.Id, parent, userNames); err != nil {
+		return nil, err
+	}
+
+	hostSetNames := make(map[string]string)
+	hcl, err := hostcatalogs.NewClient(e.client).List(e.ctx, s.Id)
+	if err != nil {
+		return nil, fmt.Errorf("listing host catalogs: %w", err)
+	}
+	for _, item := range hcl.Items {
+		switch {
+		case item.Type != "static":
+			e.skip("host catalog", item.Id, parent, fmt.Sprintf("%s host catalogs aren't supported", item.Type))
+			continue
+		case item.Name == "":
+			e.skip("host catalog", item.Id, parent, "it has no name")
+			continue
+		}
+		hc, err := e.exportHostCatalog(item, parent, hostSetNames)
+		if err != nil {
+			return nil, fmt.Errorf("host catalog %q: %w", item.Name, err)
+		}
+		p.HostCatalogs = append(p.HostCatalogs, hc)
+	}
+	sort.Slice(p.HostCatalogs, func(i, j int) bool { return p.HostCatalogs[i].Name < p.HostCatalogs[j].Name })
+
+	tc := targets.NewClient(e.client)
+	tl, err := tc.List(e.ctx, s.Id)
+	if err != nil {
+		return nil, fmt.Errorf("listing targets: %w", err)
+	}
+	for _, item := range tl.Items {
+		switch {
+		case item.Type != "tcp":
+			e.skip("target", item.Id, parent, fmt.Sprintf("%s targets aren't supported", item.Type))
+			continue
+		case item.Name == "":
+			e.skip("target", item.Id, parent, "it has no name")
+			continue
+		}
+		r, err := tc.Read(e.ctx, item.Id)
+		if err != nil {
+			return nil, fmt.Errorf("reading target %q: %w", item.Name, err)
+		}
+		// Ports are decoded from json as numbers
+		port, _ := r.Item.Attributes["default_port"].(float64)
+		t := &Target{
+			Name:                   item.Name,
+			Description:            item.Description,
+			DefaultPort:            uint32(port),
+			SessionMaxSeconds:      r.Item.SessionMaxSeconds,
+			SessionConnectionLimit: r.Item.SessionConnectionLimit,
+			HostSets:               []string{},
+		}
+		for _, id := range r.Item.HostSetIds {
+			if n, ok := hostSetNames[id]; ok {
+				t.HostSets = append(t.HostSets, n)
+			}
+		}
+		sort.Strings(t.HostSets)
+		p.Targets = append(p.Targets, t)
+	}
+	sort.Slice(p.Targets, func(i, j int) bool { return p.Targets[i].Name < p.Targets[j].Name })
+	return p, nil
+}
+
+// exportHostCatalog exports a static host catalog, adding the names of its
+// host sets to hostSetNames. Host set names must be unique within a project
+// for targets to refer to them, so later host sets with a name already seen
+// are skipped.
+func (e *exporter) exportHostCatalog(item *hostcatalogs.HostCatalog, project string, hostSetNames map[string]string) (*HostCatalog, error) {
+	hc := &HostCatalog{Name: item.Name, Description: item.Description}
+	parent := fmt.Sprintf("host catalog %q in %s", item.Name, project)
+
+	hostNames := make(map[string]string)
+	hl, err := hosts.NewClient(e.client).List(e.ctx, item.Id)
+	if err != nil {
+		return nil, fmt.Errorf("listing hosts: %w", err)
+	}
+	for _, h := range hl.Items {
+		if h.Name == "" {
+			e.skip("host", h.Id, parent, "it has no name")
+			continue
+		}
+		address, _ := h.Attributes["address"].(string)
+		hostNames[h.Id] = h.Name
+		hc.Hosts = append(hc.Hosts, &Host{Name: h.Name, Description: h.Description, Address: address})
+	}
+	sort.Slice(hc.Hosts, func(i, j int) bool { return hc.Hosts[i].Name < hc.Hosts[j].Name })
+
+	seen := make(map[string]bool)
+	for _, n := range hostSetNames {
+		seen[n] = true
+	}
+	hsc := hostsets.NewClient(e.client)
+	hsl, err := hsc.List(e.ctx, item.Id)
+	if err != nil {
+		return nil, fmt.Errorf("listing host sets: %w", err)
+	}
+	for _, hs := range hsl.Items {
+		switch {
+		case hs.Name == "":
+			e.skip("host set", hs.Id, parent, "it has no name")
+			continue
+		case seen[hs.Name]:
+			e.skip("host set", hs.Id, parent, fmt.Sprintf("another host set in %s is named %q", project, hs.Name))
+			continue
+		}
+		r, err := hsc.Read(e.ctx, hs.Id)
+		if err != nil {
+			return nil, fmt.Errorf("reading host set %q: %w", hs.Name, err)
+		}
+		set := &HostSet{Name: hs.Name, Description: hs.Description, Hosts: []string{}}
+		for _, id := range r.Item.HostIds {
+			if n, ok := hostNames[id]; ok {
+				set.Hosts = append(set.Hosts, n)
+			}
+		}
+		sort.Strings(set.Hosts)
+		seen[hs.Name] = true
+		hostSetNames[hs.Id] = hs.Name
+		hc.HostSets = append(hc.HostSets, set)
+	}
+	sort.Slice(hc.HostSets, func(i, j int) bool { return hc.HostSets[i].Name < hc.HostSets[j].Name })
+	return hc, nil
+}
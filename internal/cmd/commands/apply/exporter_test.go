@@ -0,0 +1,51 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/servers/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	tc := controller.NewTestController(t, nil)
+	defer tc.Shutdown()
+	client := tc.Client()
+	client.SetToken(tc.Token().Token)
+	ctx := context.Background()
+
+	m, err := ParseManifest([]byte(fmt.Sprintf(testManifest, "primary")))
+	require.NoError(err)
+	require.NoError((&applier{ctx: ctx, client: client}).apply(m))
+
+	e := &exporter{ctx: ctx, client: client}
+	exported, err := e.export("global")
+	require.NoError(err)
+
+	var org *Org
+	for _, o := range exported.Orgs {
+		if o.Name == "eng" {
+			org = o
+		}
+	}
+	require.NotNil(org)
+	require.Len(org.Projects, 1)
+	p := org.Projects[0]
+	assert.Equal([]*Group{{Name: "dbas", Members: []string{"alice"}}}, org.Groups)
+	require.Len(p.Targets, 1)
+	assert.Equal("primary", p.Targets[0].Description)
+	assert.Equal(uint32(5432), p.Targets[0].DefaultPort)
+	assert.Equal([]string{"postgres"}, p.Targets[0].HostSets)
+	require.Len(p.HostCatalogs, 1)
+	assert.Equal([]*Host{{Name: "pg1", Address: "10.0.0.10"}}, p.HostCatalogs[0].Hosts)
+
+	// The exported manifest describes what exists, so applying it changes
+	// nothing
+	a := &applier{ctx: ctx, client: client}
+	require.NoError(a.apply(exported))
+	assert.Empty(a.changes)
+}
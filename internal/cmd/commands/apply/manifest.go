@@ -0,0 +1,236 @@
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+)
+
+// Manifest describes the resources apply creates or updates. Resources are
+// matched to existing ones by name within their parent, so every resource
+// must be named and names must be unique among its siblings. Lists of
+// members, principals, grants, hosts and host sets are only managed when
+// present; an empty list removes every existing entry.
+type Manifest struct {
+	Orgs []*Org `json:"orgs"`
+}
+
+// Org is an org scope and the resources within it.
+type Org struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	AuthMethods []*AuthMethod `json:"auth_methods,omitempty"`
+	Users       []*User       `json:"users,omitempty"`
+	Groups      []*Group      `json:"groups,omitempty"`
+	Roles       []*Role       `json:"roles,omitempty"`
+	Projects    []*Project    `json:"projects,omitempty"`
+}
+
+// Project is a project scope and the resources within it.
+type Project struct {
+	Name         string         `json:"name"`
+	Description  string         `json:"description,omitempty"`
+	Groups       []*Group       `json:"groups,omitempty"`
+	Roles        []*Role        `json:"roles,omitempty"`
+	HostCatalogs []*HostCatalog `json:"host_catalogs,omitempty"`
+	Targets      []*Target      `json:"targets,omitempty"`
+}
+
+// AuthMethod is a password auth method.
+type AuthMethod struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type User struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Group lists its members by the names of users in the org, or by id.
+type Group struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Members     []string `json:"members"`
+}
+
+// Role lists its principals by the names of users in the org and groups in
+// the role's scope, or by id, such as u_anon for anonymous users.
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Grants      []string `json:"grants"`
+	Users       []string `json:"users"`
+	Groups      []string `json:"groups"`
+}
+
+// HostCatalog is a static host catalog with its hosts and host sets.
+type HostCatalog struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Hosts       []*Host    `json:"hosts,omitempty"`
+	HostSets    []*HostSet `json:"host_sets,omitempty"`
+}
+
+type Host struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Address     string `json:"address"`
+}
+
+// HostSet lists its hosts by the names of hosts in its catalog.
+type HostSet struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Hosts       []string `json:"hosts"`
+}
+
+// Target is a tcp target. It lists its host sets by name, so the names of
+// host sets must be unique within a project.
+type Target struct {
+	Name                   string   `json:"name"`
+	Description            string   `json:"description,omitempty"`
+	DefaultPort            uint32   `json:"default_port,omitempty"`
+	SessionMaxSeconds      uint32   `json:"session_max_seconds,omitempty"`
+	SessionConnectionLimit int32    `json:"session_connection_limit,omitempty"`
+	HostSets               []string `json:"host_sets"`
+}
+
+// LoadManifest reads and validates a manifest from a YAML or JSON file.
+func LoadManifest(path string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifest: %w", err)
+	}
+	return ParseManifest(b)
+}
+
+// ParseManifest parses and validates a YAML or JSON manifest. Unknown fields
+// are rejected so that misspelled fields aren't silently ignored.
+func ParseManifest(b []byte) (*Manifest, error) {
+	b, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse manifest: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	m := new(Manifest)
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("unable to parse manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// names checks that names are set and unique among the resources of type
+// typ in parent.
+type names map[string]bool
+
+func (n names) add(typ, parent, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s in %s has no name", typ, parent)
+	}
+	if n[name] {
+		return fmt.Errorf("%s %q appears more than once in %s", typ, name, parent)
+	}
+	n[name] = true
+	return nil
+}
+
+func (m *Manifest) validate() error {
+	orgs := names{}
+	for _, o := range m.Orgs {
+		if err := orgs.add("org", "the manifest", o.Name); err != nil {
+			return err
+		}
+		parent := fmt.Sprintf("org %q", o.Name)
+		authMethods, users := names{}, names{}
+		for _, am := range o.AuthMethods {
+			if err := authMethods.add("auth method", parent, am.Name); err != nil {
+				return err
+			}
+		}
+		for _, u := range o.Users {
+			if err := users.add("user", parent, u.Name); err != nil {
+				return err
+			}
+		}
+		if err := validateGroupsAndRoles(parent, o.Groups, o.Roles); err != nil {
+			return err
+		}
+		projects := names{}
+		for _, p := range o.Projects {
+			if err := projects.add("project", parent, p.Name); err != nil {
+				return err
+			}
+			if err := p.validate(fmt.Sprintf("project %q in %s", p.Name, parent)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateGroupsAndRoles(parent string, groups []*Group, roles []*Role) error {
+	groupNames, roleNames := names{}, names{}
+	for _, g := range groups {
+		if err := groupNames.add("group", parent, g.Name); err != nil {
+			return err
+		}
+	}
+	for _, r := range roles {
+		if err := roleNames.add("role", parent, r.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Project) validate(parent string) error {
+	if err := validateGroupsAndRoles(parent, p.Groups, p.Roles); err != nil {
+		return err
+	}
+	catalogs, hostSets := names{}, names{}
+	for _, hc := range p.HostCatalogs {
+		if err := catalogs.add("host catalog", parent, hc.Name); err != nil {
+			return err
+		}
+		catalog := fmt.Sprintf("host catalog %q in %s", hc.Name, parent)
+		hosts := names{}
+		for _, h := range hc.Hosts {
+			if err := hosts.add("host", catalog, h.Name); err != nil {
+				return err
+			}
+			if h.Address == "" {
+				return fmt.Errorf("host %q in %s has no address", h.Name, catalog)
+			}
+		}
+		for _, hs := range hc.HostSets {
+			if err := hostSets.add("host set", parent, hs.Name); err != nil {
+				return err
+			}
+			for _, h := range hs.Hosts {
+				if !hosts[h] {
+					return fmt.Errorf("host set %q in %s includes unknown host %q", hs.Name, catalog, h)
+				}
+			}
+		}
+	}
+	targets := names{}
+	for _, t := range p.Targets {
+		if err := targets.add("target", parent, t.Name); err != nil {
+			return err
+		}
+		for _, hs := range t.HostSets {
+			if !hostSets[hs] {
+				return fmt.Errorf("target %q in %s includes unknown host set %q", t.Name, parent, hs)
+			}
+		}
+	}
+	return nil
+}
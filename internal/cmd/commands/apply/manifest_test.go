@@ -0,0 +1,129 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			name: "valid",
+			manifest: `
+orgs:
+- name: eng
+  users:
+  - name: alice
+  groups:
+  - name: dbas
+    members: [alice]
+  projects:
+  - name: databases
+    host_catalogs:
+    - name: postgres
+      hosts:
+      - name: pg1
+        address: 10.0.0.10
+      host_sets:
+      - name: postgres
+        hosts: [pg1]
+    targets:
+    - name: postgres
+      default_port: 5432
+      host_sets: [postgres]
+`,
+		},
+		{
+			name:     "json",
+			manifest: `{"orgs": [{"name": "eng", "projects": [{"name": "databases"}]}]}`,
+		},
+		{
+			name: "unknown-field",
+			manifest: `
+orgs:
+- name: eng
+  user: [alice]
+`,
+			wantErr: `unknown field "user"`,
+		},
+		{
+			name: "no-name",
+			manifest: `
+orgs:
+- description: eng
+`,
+			wantErr: "org in the manifest has no name",
+		},
+		{
+			name: "duplicate-name",
+			manifest: `
+orgs:
+- name: eng
+  roles:
+  - name: admin
+  - name: admin
+`,
+			wantErr: `role "admin" appears more than once in org "eng"`,
+		},
+		{
+			name: "no-address",
+			manifest: `
+orgs:
+- name: eng
+  projects:
+  - name: databases
+    host_catalogs:
+    - name: postgres
+      hosts:
+      - name: pg1
+`,
+			wantErr: `host "pg1" in host catalog "postgres" in project "databases" in org "eng" has no address`,
+		},
+		{
+			name: "unknown-host",
+			manifest: `
+orgs:
+- name: eng
+  projects:
+  - name: databases
+    host_catalogs:
+    - name: postgres
+      host_sets:
+      - name: postgres
+        hosts: [pg1]
+`,
+			wantErr: `includes unknown host "pg1"`,
+		},
+		{
+			name: "unknown-host-set",
+			manifest: `
+orgs:
+- name: eng
+  projects:
+  - name: databases
+    targets:
+    - name: postgres
+      host_sets: [postgres]
+`,
+			wantErr: `target "postgres" in project "databases" in org "eng" includes unknown host set "postgres"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseManifest([]byte(tt.manifest))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, m.Orgs)
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package authenticate
 
 import (
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -16,7 +14,6 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/mitchellh/go-wordwrap"
 	"github.com/posener/complete"
-	"github.com/zalando/go-keyring"
 )
 
 var _ cli.Command = (*PasswordCommand)(nil)
@@ -149,28 +146,20 @@ func (c *PasswordCommand) Run(args []string) int {
 			fmt.Sprintf("  User ID:         %s", token.UserId),
 		}))
 
-	case "json":
-		jsonOut, err := base.JsonFormatter{}.Format(token)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(token); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(jsonOut))
 	}
 
-	tokenName := "default"
+	tokenName := base.CurrentProfile()
 	if c.Command.FlagTokenName != "" {
 		tokenName = c.Command.FlagTokenName
 	}
 	if tokenName != "none" {
-		marshaled, err := json.Marshal(token)
-		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error marshaling auth token to save to system credential store: %s", err))
-			return 1
-		}
-		// TODO: potentially look for dbus-launch in advance and don't issue a warning at all
-		if err := keyring.Set("HashiCorp Boundary Auth Token", tokenName, base64.RawStdEncoding.EncodeToString(marshaled)); err != nil {
-			c.UI.Error(fmt.Sprintf("Error saving auth token to system credential store: %s", err))
+		if err := c.SaveToken(tokenName, client.Addr(), token); err != nil {
+			c.UI.Error(err.Error())
 			c.UI.Warn("The token printed above must be manually passed in via the BOUNDARY_TOKEN env var or -token flag. Storing the token can also be disabled via -token-name=none.")
 		}
 	}
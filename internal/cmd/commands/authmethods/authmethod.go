@@ -30,7 +30,7 @@ func (c *Command) Synopsis() string {
 var flagsMap = map[string][]string{
 	"read":   {"id"},
 	"delete": {"id"},
-	"list":   {"scope-id"},
+	"list":   {"scope-id", "filter", "sort-by", "sort-dir"},
 }
 
 func (c *Command) Help() string {
@@ -129,6 +129,17 @@ func (c *Command) Run(args []string) int {
 
 	var opts []authmethods.Option
 
+	if c.FlagFilter != "" {
+		opts = append(opts, authmethods.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, authmethods.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, authmethods.WithSortDir(c.FlagSortDir))
+	}
+
 	switch c.FlagName {
 	case "":
 	case "null":
@@ -180,7 +191,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -197,17 +208,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedMethods := listResult.GetItems().([]*authmethods.AuthMethod)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedMethods) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedMethods)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedMethods); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedMethods) == 0 {
@@ -254,13 +263,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateAuthMethodTableOutput(method))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(method)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(method); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
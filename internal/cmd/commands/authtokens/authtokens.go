@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/boundary/api/authtokens"
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/common"
-	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/boundary/sdk/strutil"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
@@ -22,6 +21,10 @@ type Command struct {
 	*base.Command
 
 	Func string
+
+	flagAccountId string
+	flagGrants    []string
+	flagTtl       time.Duration
 }
 
 func (c *Command) Synopsis() string {
@@ -29,13 +32,15 @@ func (c *Command) Synopsis() string {
 }
 
 var flagsMap = map[string][]string{
+	"create": {"scope-id", "accountid", "grant", "ttl"},
 	"read":   {"id"},
 	"delete": {"id"},
-	"list":   {"scope-id"},
+	"list":   {"scope-id", "filter", "sort-by", "sort-dir"},
 }
 
 func (c *Command) Help() string {
 	helpMap := common.HelpMap("auth token")
+	helpMap["create"] = createHelp
 	if c.Func == "" {
 		return helpMap["base"]()
 	}
@@ -47,7 +52,7 @@ func (c *Command) Flags() *base.FlagSets {
 
 	if len(flagsMap[c.Func]) > 0 {
 		f := set.NewFlagSet("Command Options")
-		common.PopulateCommonFlags(c.Command, f, resource.AuthToken.String(), flagsMap[c.Func])
+		populateFlags(c, f, flagsMap[c.Func])
 	}
 
 	return set
@@ -81,6 +86,16 @@ func (c *Command) Run(args []string) int {
 		c.UI.Error("Scope ID must be passed in via -scope-id")
 		return 1
 	}
+	if c.Func == "create" {
+		if c.flagAccountId == "" {
+			c.UI.Error("Account ID must be passed in via -account-id")
+			return 1
+		}
+		if len(c.flagGrants) == 0 {
+			c.UI.Error("At least one grant must be passed in via -grant")
+			return 1
+		}
+	}
 
 	client, err := c.Client()
 	if err != nil {
@@ -91,10 +106,32 @@ func (c *Command) Run(args []string) int {
 	authtokenClient := authtokens.NewClient(client)
 
 	existed := true
+	var opts []authtokens.Option
+
+	if c.FlagFilter != "" {
+		opts = append(opts, authtokens.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, authtokens.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, authtokens.WithSortDir(c.FlagSortDir))
+	}
+
+	if c.Func == "create" {
+		opts = append(opts, authtokens.WithAccountId(c.flagAccountId), authtokens.WithGrantStrings(c.flagGrants))
+		if c.flagTtl != 0 {
+			opts = append(opts, authtokens.WithExpirationTime(time.Now().Add(c.flagTtl)))
+		}
+	}
+
 	var result api.GenericResult
 	var listResult api.GenericListResult
 
 	switch c.Func {
+	case "create":
+		result, err = authtokenClient.Create(c.Context, c.FlagScopeId, opts...)
 	case "read":
 		result, err = authtokenClient.Read(c.Context, c.FlagId)
 	case "delete":
@@ -104,7 +141,7 @@ func (c *Command) Run(args []string) int {
 			err = nil
 		}
 	case "list":
-		listResult, err = authtokenClient.List(c.Context, c.FlagScopeId)
+		listResult, err = authtokenClient.List(c.Context, c.FlagScopeId, opts...)
 	}
 
 	plural := "auth token"
@@ -123,7 +160,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -140,17 +177,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedTokens := listResult.GetItems().([]*authtokens.AuthToken)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedTokens) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedTokens)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedTokens); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedTokens) == 0 {
@@ -185,13 +220,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateAuthTokenTableOutput(token))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(token)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(token); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
@@ -1,12 +1,54 @@
 package authtokens
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/boundary/api/authtokens"
 	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/common"
+	"github.com/hashicorp/boundary/internal/types/resource"
 )
 
+func createHelp() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary auth-tokens create [options] [args]",
+		"",
+		"  Create an API token for automation, such as a CI/CD pipeline. Requests made with the token are only allowed if both the account's user and the token's grants allow them. The token can't be renewed and is only shown once. Example:",
+		"",
+		`    $ boundary auth-tokens create -scope-id o_1234567890 -account-id acctpw_1234567890 -grant "id=*;type=target;actions=authorize-session" -ttl 720h`,
+		"",
+		"",
+	})
+}
+
+func populateFlags(c *Command, f *base.FlagSet, flagNames []string) {
+	common.PopulateCommonFlags(c.Command, f, resource.AuthToken.String(), flagNames)
+
+	for _, name := range flagNames {
+		switch name {
+		case "accountid":
+			f.StringVar(&base.StringVar{
+				Name:   "account-id",
+				Target: &c.flagAccountId,
+				Usage:  "The ID of the account of the user the API token is for.",
+			})
+		case "grant":
+			f.StringSliceVar(&base.StringSliceVar{
+				Name:   "grant",
+				Target: &c.flagGrants,
+				Usage:  "The grants the API token is restricted to. May be specified multiple times. Can be in compact string format or JSON (be sure to escape JSON properly).",
+			})
+		case "ttl":
+			f.DurationVar(&base.DurationVar{
+				Name:   "ttl",
+				Target: &c.flagTtl,
+				Usage:  "How long the API token is valid for. Defaults to 30 days and can't be more than 90 days.",
+			})
+		}
+	}
+}
+
 func generateAuthTokenTableOutput(in *authtokens.AuthToken) string {
 	nonAttributeMap := map[string]interface{}{
 		"ID":                         in.Id,
@@ -18,6 +60,13 @@ func generateAuthTokenTableOutput(in *authtokens.AuthToken) string {
 		"Approximate Last Used Time": in.ApproximateLastUsedTime.Local().Format(time.RFC1123),
 	}
 
+	if in.Token != "" {
+		nonAttributeMap["Token"] = in.Token
+	}
+	if in.Api {
+		nonAttributeMap["API"] = in.Api
+	}
+
 	maxLength := base.MaxAttributesLength(nonAttributeMap, nil, nil)
 
 	ret := []string{
@@ -29,5 +78,15 @@ func generateAuthTokenTableOutput(in *authtokens.AuthToken) string {
 		base.ScopeInfoForOutput(in.Scope, maxLength),
 	}
 
+	if len(in.GrantStrings) > 0 {
+		ret = append(ret,
+			"",
+			"  Grants:",
+		)
+		for _, grant := range in.GrantStrings {
+			ret = append(ret, fmt.Sprintf("    %s", grant))
+		}
+	}
+
 	return base.WrapForHelpText(ret)
 }
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ProfilesCommand)(nil)
+var _ cli.CommandAutocomplete = (*ProfilesCommand)(nil)
+
+type ProfilesCommand struct {
+	*base.Command
+
+	Func string
+}
+
+func (c *ProfilesCommand) Synopsis() string {
+	switch c.Func {
+	case "switch":
+		return "Switch the profile used for stored tokens"
+	default:
+		return "List the profiles of stored tokens"
+	}
+}
+
+func (c *ProfilesCommand) Help() string {
+	var args []string
+	switch c.Func {
+	case "switch":
+		args = append(args,
+			"Usage: boundary config switch [options] <name>",
+			"",
+			"  Switch the profile used by commands which aren't given -token-name. Commands then use the token stored under that name, and the controller which issued it unless -addr or BOUNDARY_ADDR is set. Example:",
+			"",
+			`    $ boundary config switch prod`,
+			"",
+			"  A profile is stored by authenticating with -token-name set to its name.",
+			"",
+		)
+	default:
+		args = append(args,
+			"Usage: boundary config list-profiles [options]",
+			"",
+			"  List the profiles under which the Boundary CLI has stored tokens, along with the controller each was issued by. Example:",
+			"",
+			`    $ boundary config list-profiles`,
+			"",
+		)
+	}
+
+	return base.WrapForHelpText(args) + c.Flags().Help()
+}
+
+func (c *ProfilesCommand) Flags() *base.FlagSets {
+	if c.Func == "switch" {
+		return c.FlagSet(base.FlagSetNone)
+	}
+	return c.FlagSet(base.FlagSetOutputFormat)
+}
+
+func (c *ProfilesCommand) AutocompleteArgs() complete.Predictor {
+	if c.Func != "switch" {
+		return complete.PredictNothing
+	}
+	return complete.PredictFunc(func(complete.Args) []string {
+		_, list, err := base.ListProfiles()
+		if err != nil {
+			return nil
+		}
+		names := make([]string, 0, len(list))
+		for _, p := range list {
+			names = append(names, p.Name)
+		}
+		return names
+	})
+}
+
+func (c *ProfilesCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ProfilesCommand) Run(args []string) int {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.Func == "switch" {
+		if len(f.Args()) != 1 {
+			c.UI.Error("A single profile name must be given")
+			return 1
+		}
+		if err := base.SwitchProfile(f.Args()[0]); err != nil {
+			c.UI.Error(fmt.Sprintf("Error switching profile: %s", err))
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("Switched to profile %q.", f.Args()[0]))
+		return 0
+	}
+
+	current, list, err := base.ListProfiles()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error listing profiles: %s", err))
+		return 1
+	}
+
+	switch base.Format(c.UI) {
+	case "json", "yaml":
+		out := struct {
+			Current  string          `json:"current"`
+			Profiles []*base.Profile `json:"profiles"`
+		}{Current: current}
+		for _, p := range list {
+			// Never print a token kept in the profiles file
+			out.Profiles = append(out.Profiles, &base.Profile{Name: p.Name, Addr: p.Addr})
+		}
+		if err := c.PrintStructured(out); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
+			return 1
+		}
+
+	case "table":
+		if len(list) == 0 {
+			c.UI.Output("No profiles found")
+			return 0
+		}
+		output := []string{"", "Profile information:"}
+		for _, p := range list {
+			marker := " "
+			if p.Name == current {
+				marker = "*"
+			}
+			output = append(output, fmt.Sprintf("%s %s", marker, p.Name))
+			if p.Addr != "" {
+				output = append(output, fmt.Sprintf("    Address: %s", p.Addr))
+			}
+		}
+		c.UI.Output(base.WrapForHelpText(output))
+	}
+
+	return 0
+}
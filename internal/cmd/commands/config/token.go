@@ -115,7 +115,7 @@ func (c *TokenCommand) Run(args []string) (ret int) {
 
 	// Read from keyring first
 	var authToken *authtokens.AuthToken
-	tokenName := "default"
+	tokenName := base.CurrentProfile()
 	if c.FlagTokenName != "" {
 		tokenName = c.FlagTokenName
 	}
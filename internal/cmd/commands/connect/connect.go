@@ -26,13 +26,12 @@ import (
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	targetspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"github.com/mitchellh/cli"
-	"github.com/mr-tron/base58"
 	"github.com/posener/complete"
 	"go.uber.org/atomic"
-	"google.golang.org/protobuf/proto"
 	"nhooyr.io/websocket"
 	"nhooyr.io/websocket/wspb"
 )
@@ -65,12 +64,16 @@ type Command struct {
 	flagListenPort int
 	flagTargetId   string
 	flagHostId     string
+	flagTargetPort int
 	flagExec       string
 	flagUsername   string
 
 	// HTTP
 	httpFlags
 
+	// Kube
+	kubeFlags
+
 	// Postgres
 	postgresFlags
 
@@ -87,6 +90,7 @@ type Command struct {
 	connWg             *sync.WaitGroup
 	listenerCloseOnce  sync.Once
 	listener           *net.TCPListener
+	udpListener        *net.UDPConn
 	listenerAddr       *net.TCPAddr
 	connsLeftCh        chan int32
 	connectionsLeft    atomic.Int32
@@ -102,6 +106,8 @@ func (c *Command) Synopsis() string {
 		return "Authorize a session against a target (or consume an existing authorization token) and launch a proxied connection"
 	case "http":
 		return httpSynopsis
+	case "kube":
+		return kubeSynopsis
 	case "postgres":
 		return postgresSynopsis
 	case "rdp":
@@ -167,6 +173,12 @@ func (c *Command) Flags() *base.FlagSets {
 		Usage:  "The ID of a specific host to connect to out of the hosts from the target's host sets. If not specified, one is chosen at random.",
 	})
 
+	f.IntVar(&base.IntVar{
+		Name:   "target-port",
+		Target: &c.flagTargetPort,
+		Usage:  "The port on the host to connect to. It must be the target's default port or one of its allowed ports. If not specified, the default port is used.",
+	})
+
 	f.StringVar(&base.StringVar{
 		Name:       "exec",
 		Target:     &c.flagExec,
@@ -196,6 +208,9 @@ func (c *Command) Flags() *base.FlagSets {
 	case "http":
 		httpOptions(c, set)
 
+	case "kube":
+		kubeOptions(c, set)
+
 	case "postgres":
 		postgresOptions(c, set)
 
@@ -246,6 +261,8 @@ func (c *Command) Run(args []string) (retCode int) {
 		switch c.Func {
 		case "http":
 			c.flagExec = c.httpFlags.defaultExec()
+		case "kube":
+			c.flagExec = c.kubeFlags.defaultExec()
 		case "ssh":
 			c.flagExec = c.sshFlags.defaultExec()
 		case "postgres":
@@ -261,6 +278,11 @@ func (c *Command) Run(args []string) (retCode int) {
 		return 1
 	}
 
+	if c.flagTargetPort < 0 || c.flagTargetPort > 65535 {
+		c.UI.Error("-target-port must be between 1 and 65535")
+		return 1
+	}
+
 	c.connsLeftCh = make(chan int32)
 
 	if c.flagListenAddr == "" {
@@ -314,6 +336,7 @@ func (c *Command) Run(args []string) (retCode int) {
 		if len(c.flagHostId) != 0 {
 			opts = append(opts, targets.WithHostId(c.flagHostId))
 		}
+		opts = append(opts, targets.WithMaxAuthorizationFormat(session.CurrentAuthzFormat))
 
 		sar, err := targetClient.AuthorizeSession(c.Context, c.flagTargetId, opts...)
 		if err != nil {
@@ -327,19 +350,13 @@ func (c *Command) Run(args []string) (retCode int) {
 		authzString = sar.GetItem().(*targets.SessionAuthorization).AuthorizationToken
 	}
 
-	marshaled, err := base58.FastBase58Decoding(authzString)
+	c.sessionAuthzData, _, err = session.DecodeAuthorizationData(authzString)
 	if err != nil {
-		c.UI.Error(fmt.Errorf("Unable to base58-decode authorization data: %w", err).Error())
-		return 1
-	}
-	if len(marshaled) == 0 {
-		c.UI.Error("Zero length authorization information after decoding")
-		return 1
-	}
-
-	c.sessionAuthzData = new(targetspb.SessionAuthorizationData)
-	if err := proto.Unmarshal(marshaled, c.sessionAuthzData); err != nil {
-		c.UI.Error(fmt.Errorf("Unable to proto-decode authorization data: %w", err).Error())
+		if errors.Is(err, session.ErrUnsupportedAuthzFormat) {
+			c.UI.Error(fmt.Errorf("Authorization data is in a format this version of Boundary cannot read; please upgrade: %w", err).Error())
+			return 1
+		}
+		c.UI.Error(fmt.Errorf("Unable to decode authorization data: %w", err).Error())
 		return 1
 	}
 
@@ -349,7 +366,6 @@ func (c *Command) Run(args []string) (retCode int) {
 	}
 
 	c.connectionsLeft.Store(c.sessionAuthzData.ConnectionLimit)
-	workerAddr := c.sessionAuthzData.GetWorkerInfo()[0].GetAddress()
 
 	parsedCert, err := x509.ParseCertificate(c.sessionAuthzData.Certificate)
 	if err != nil {
@@ -393,10 +409,36 @@ func (c *Command) Run(args []string) (retCode int) {
 	// hijacked, just setting for completeness
 	transport.IdleConnTimeout = 0
 
-	c.listener, err = net.ListenTCP("tcp", &net.TCPAddr{
-		IP:   listenAddr,
-		Port: c.flagListenPort,
-	})
+	protocol := c.sessionAuthzData.GetProtocol()
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	if protocol == "udp" && c.Func != "connect" {
+		c.UI.Error(fmt.Sprintf("The %s helper can't be used with a udp target", c.Func))
+		return 1
+	}
+
+	switch protocol {
+	case "udp":
+		c.udpListener, err = net.ListenUDP("udp", &net.UDPAddr{
+			IP:   listenAddr,
+			Port: c.flagListenPort,
+		})
+		if err == nil {
+			// Only the IP and port of the listener address are used, such as
+			// by -exec
+			udpAddr := c.udpListener.LocalAddr().(*net.UDPAddr)
+			c.listenerAddr = &net.TCPAddr{IP: udpAddr.IP, Port: udpAddr.Port}
+		}
+	default:
+		c.listener, err = net.ListenTCP("tcp", &net.TCPAddr{
+			IP:   listenAddr,
+			Port: c.flagListenPort,
+		})
+		if err == nil {
+			c.listenerAddr = c.listener.Addr().(*net.TCPAddr)
+		}
+	}
 	if err != nil {
 		c.UI.Error(fmt.Errorf("Error starting listening port: %w", err).Error())
 		return 1
@@ -405,7 +447,11 @@ func (c *Command) Run(args []string) (retCode int) {
 	listenerCloseFunc := func() {
 		// Forces the for loop to exist instead of spinning on errors
 		c.connectionsLeft.Store(0)
-		if err := c.listener.Close(); err != nil {
+		var closer io.Closer = c.listener
+		if c.udpListener != nil {
+			closer = c.udpListener
+		}
+		if err := closer.Close(); err != nil {
 			c.UI.Error(fmt.Errorf("Error closing listener on shutdown: %w", err).Error())
 			retCode = 1
 		}
@@ -416,11 +462,9 @@ func (c *Command) Run(args []string) (retCode int) {
 		c.listenerCloseOnce.Do(listenerCloseFunc)
 	}()
 
-	c.listenerAddr = c.listener.Addr().(*net.TCPAddr)
-
 	if c.flagExec == "" {
 		sessInfo := SessionInfo{
-			Protocol:        "tcp",
+			Protocol:        protocol,
 			Address:         c.listenerAddr.IP.String(),
 			Port:            c.listenerAddr.Port,
 			Expiration:      c.expiration,
@@ -431,52 +475,54 @@ func (c *Command) Run(args []string) (retCode int) {
 		switch base.Format(c.UI) {
 		case "table":
 			c.UI.Output(generateSessionInfoTableOutput(sessInfo))
-		case "json":
-			out, err := json.Marshal(&sessInfo)
-			if err != nil {
+		case "json", "yaml":
+			if err := c.PrintStructured(&sessInfo); err != nil {
 				c.UI.Error(fmt.Errorf("error marshaling session information: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(out))
 		}
 	}
 
 	c.connWg = new(sync.WaitGroup)
 
 	c.connWg.Add(1)
-	go func() {
-		defer c.connWg.Done()
-		for {
-			listeningConn, err := c.listener.AcceptTCP()
-			if err != nil {
-				select {
-				case <-c.proxyCtx.Done():
-					return
-				case <-c.Context.Done():
-					return
-				default:
-					// When this hits zero we trigger listener close so this
-					// isn't actually an error condition
-					if c.connectionsLeft.Load() == 0 {
+	switch {
+	case c.udpListener != nil:
+		go c.relayUdp(tofuToken, transport)
+	default:
+		go func() {
+			defer c.connWg.Done()
+			for {
+				listeningConn, err := c.listener.AcceptTCP()
+				if err != nil {
+					select {
+					case <-c.proxyCtx.Done():
 						return
+					case <-c.Context.Done():
+						return
+					default:
+						// When this hits zero we trigger listener close so this
+						// isn't actually an error condition
+						if c.connectionsLeft.Load() == 0 {
+							return
+						}
+						c.UI.Error(fmt.Errorf("Error accepting connection: %w", err).Error())
+						continue
 					}
-					c.UI.Error(fmt.Errorf("Error accepting connection: %w", err).Error())
-					continue
 				}
+				c.connWg.Add(1)
+				go func() {
+					defer listeningConn.Close()
+					if err := c.handleConnection(
+						listeningConn,
+						tofuToken,
+						transport); err != nil {
+						c.UI.Error(err.Error())
+					}
+				}()
 			}
-			c.connWg.Add(1)
-			go func() {
-				defer listeningConn.Close()
-				if err := c.handleConnection(
-					listeningConn,
-					workerAddr,
-					tofuToken,
-					transport); err != nil {
-					c.UI.Error(err.Error())
-				}
-			}()
-		}
-	}()
+		}()
+	}
 
 	timer := time.NewTimer(time.Until(c.expiration))
 	c.connWg.Add(1)
@@ -551,47 +597,61 @@ func (c *Command) Run(args []string) (retCode int) {
 
 func (c *Command) handleConnection(
 	listeningConn *net.TCPConn,
-	workerAddr string,
 	tofuToken string,
 	transport *http.Transport) error {
 
 	defer c.connWg.Done()
 
-	conn, resp, err := websocket.Dial(
-		c.proxyCtx,
-		fmt.Sprintf("wss://%s/v1/proxy", workerAddr),
-		&websocket.DialOptions{
-			HTTPClient: &http.Client{
-				Transport: transport,
-			},
-			Subprotocols: []string{globals.TcpProxyV1},
-		},
-	)
+	conn, err := c.openProxy(tofuToken, transport, globals.TcpProxyV1)
 	if err != nil {
-		switch {
-		case strings.Contains(err.Error(), "tls: internal error"):
-			return errors.New("Session is unauthorized")
-		case strings.Contains(err.Error(), "connect: connection refused"):
-			return fmt.Errorf("Unable to connect to worker at %s", workerAddr)
-		default:
-			return fmt.Errorf("Error dialing the worker: %w", err)
-		}
+		return err
+	}
+
+	// Get a wrapped net.Conn so we can use io.Copy
+	netConn := websocket.NetConn(c.proxyCtx, conn, websocket.MessageBinary)
+
+	localWg := new(sync.WaitGroup)
+	localWg.Add(2)
+
+	go func() {
+		defer localWg.Done()
+		io.Copy(netConn, listeningConn)
+		netConn.Close()
+		listeningConn.Close()
+	}()
+	go func() {
+		defer localWg.Done()
+		io.Copy(listeningConn, netConn)
+		listeningConn.Close()
+		netConn.Close()
+	}()
+	localWg.Wait()
+
+	return nil
+}
+
+// openProxy opens a proxy connection to a worker using the given websocket
+// subprotocol and completes the handshake for it.
+func (c *Command) openProxy(tofuToken string, transport *http.Transport, subprotocol string) (*websocket.Conn, error) {
+	conn, resp, err := c.dialWorker(transport, subprotocol)
+	if err != nil {
+		return nil, err
 	}
 
 	if resp == nil {
-		return errors.New("Response from worker is nil")
+		return nil, errors.New("Response from worker is nil")
 	}
 	if resp.Header == nil {
-		return errors.New("Response header is nil")
+		return nil, errors.New("Response header is nil")
 	}
 	negProto := resp.Header.Get("Sec-WebSocket-Protocol")
-	if negProto != globals.TcpProxyV1 {
-		return fmt.Errorf("Unexpected negotiated protocol: %s", negProto)
+	if negProto != subprotocol {
+		return nil, fmt.Errorf("Unexpected negotiated protocol: %s", negProto)
 	}
 
-	handshake := proxy.ClientHandshake{TofuToken: tofuToken}
+	handshake := proxy.ClientHandshake{TofuToken: tofuToken, Port: uint32(c.flagTargetPort)}
 	if err := wspb.Write(c.proxyCtx, conn, &handshake); err != nil {
-		return fmt.Errorf("error sending handshake to worker: %w", err)
+		return nil, fmt.Errorf("error sending handshake to worker: %w", err)
 	}
 	var handshakeResult proxy.HandshakeResult
 	if err := wspb.Read(c.proxyCtx, conn, &handshakeResult); err != nil {
@@ -600,43 +660,59 @@ func (c *Command) handleConnection(
 			// There's no reason to think we'd be able to authorize any more
 			// connections after the first has failed
 			c.connsLeftCh <- 0
-			return errors.New("Unable to authorize connection")
+			return nil, errors.New("Unable to authorize connection")
 		}
 		switch {
 		case strings.Contains(err.Error(), "tofu token not allowed"):
 			// Nothing will be able to be done here, so cancel the context too
 			c.proxyCancel()
-			return errors.New("Session is already in use")
+			return nil, errors.New("Session is already in use")
+		case strings.Contains(err.Error(), "port not allowed"):
+			c.proxyCancel()
+			return nil, fmt.Errorf("Port %d is not allowed by the target", c.flagTargetPort)
 		default:
-			return fmt.Errorf("error reading handshake result: %w", err)
+			return nil, fmt.Errorf("error reading handshake result: %w", err)
 		}
 	}
 
 	if handshakeResult.GetConnectionsLeft() != -1 {
 		c.connsLeftCh <- handshakeResult.GetConnectionsLeft()
 	}
+	return conn, nil
+}
 
-	// Get a wrapped net.Conn so we can use io.Copy
-	netConn := websocket.NetConn(c.proxyCtx, conn, websocket.MessageBinary)
-
-	localWg := new(sync.WaitGroup)
-	localWg.Add(2)
-
-	go func() {
-		defer localWg.Done()
-		io.Copy(netConn, listeningConn)
-		netConn.Close()
-		listeningConn.Close()
-	}()
-	go func() {
-		defer localWg.Done()
-		io.Copy(listeningConn, netConn)
-		listeningConn.Close()
-		netConn.Close()
-	}()
-	localWg.Wait()
-
-	return nil
+// dialWorker opens a proxy connection to the first of the session's workers
+// which can be reached, trying them in the order the controller returned
+// them. Any other error ends the attempt, as another worker would fail the
+// same way.
+func (c *Command) dialWorker(transport *http.Transport, subprotocol string) (*websocket.Conn, *http.Response, error) {
+	var lastErr error
+	for _, worker := range c.sessionAuthzData.GetWorkerInfo() {
+		workerAddr := worker.GetAddress()
+		conn, resp, err := websocket.Dial(
+			c.proxyCtx,
+			fmt.Sprintf("wss://%s/v1/proxy", workerAddr),
+			&websocket.DialOptions{
+				HTTPClient: &http.Client{
+					Transport: transport,
+				},
+				Subprotocols: []string{subprotocol},
+			},
+		)
+		switch {
+		case err == nil:
+			return conn, resp, nil
+		case strings.Contains(err.Error(), "tls: internal error"):
+			return nil, nil, errors.New("Session is unauthorized")
+		case errors.Is(err, syscall.ECONNREFUSED),
+			errors.Is(err, syscall.EHOSTUNREACH),
+			errors.Is(err, syscall.ENETUNREACH):
+			lastErr = fmt.Errorf("Unable to connect to worker at %s", workerAddr)
+		default:
+			return nil, nil, fmt.Errorf("Error dialing the worker: %w", err)
+		}
+	}
+	return nil, nil, lastErr
 }
 
 func (c *Command) updateConnsLeft(connsLeft int32) {
@@ -674,6 +750,9 @@ func (c *Command) handleExec(passthroughArgs []string) {
 	case "http":
 		args = append(args, c.httpFlags.buildArgs(c, port, ip, addr)...)
 
+	case "kube":
+		args = append(args, c.kubeFlags.buildArgs(c, port, ip, addr)...)
+
 	case "postgres":
 		args = append(args, c.postgresFlags.buildArgs(c, port, ip, addr)...)
 
@@ -0,0 +1,65 @@
+package connect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/posener/complete"
+)
+
+const (
+	kubeSynopsis = "Authorize a session against a target and invoke a Kubernetes client to connect"
+)
+
+func kubeOptions(c *Command, set *base.FlagSets) {
+	f := set.NewFlagSet("Kubernetes Options")
+
+	f.StringVar(&base.StringVar{
+		Name:       "style",
+		Target:     &c.flagKubeStyle,
+		EnvVar:     "BOUNDARY_CONNECT_KUBE_STYLE",
+		Completion: complete.PredictSet("kubectl"),
+		Default:    "kubectl",
+		Usage:      `Specifies how the CLI will attempt to invoke a Kubernetes client. This will also set a suitable default for -exec if a value was not specified. Currently-understood values are "kubectl".`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "host",
+		Target:     &c.flagKubeHost,
+		EnvVar:     "BOUNDARY_CONNECT_KUBE_HOST",
+		Completion: complete.PredictNothing,
+		Usage:      `Specifies the host value to use. The specified hostname will be passed through to the client (if supported) for use as the TLS server name when verifying the API server's certificate.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "scheme",
+		Target:     &c.flagKubeScheme,
+		Default:    "https",
+		EnvVar:     "BOUNDARY_CONNECT_KUBE_SCHEME",
+		Completion: complete.PredictNothing,
+		Usage:      `Specifies the scheme to use.`,
+	})
+}
+
+type kubeFlags struct {
+	flagKubeStyle  string
+	flagKubeHost   string
+	flagKubeScheme string
+}
+
+func (k *kubeFlags) defaultExec() string {
+	return strings.ToLower(k.flagKubeStyle)
+}
+
+func (k *kubeFlags) buildArgs(c *Command, port, ip, addr string) []string {
+	var args []string
+	switch k.flagKubeStyle {
+	case "kubectl":
+		args = append(args, "--server", fmt.Sprintf("%s://%s", k.flagKubeScheme, addr))
+		if k.flagKubeHost != "" {
+			args = append(args, "--tls-server-name", strings.TrimSuffix(k.flagKubeHost, "/"))
+		}
+	}
+	return args
+}
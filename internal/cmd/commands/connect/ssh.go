@@ -48,6 +48,11 @@ func (s *sshFlags) buildArgs(c *Command, port, ip, addr string) []string {
 	case "ssh":
 		args = append(args, "-p", port, ip)
 		args = append(args, "-o", fmt.Sprintf("HostKeyAlias=%s", c.sessionAuthzData.HostId))
+		if c.sessionAuthzData.GetProtocol() == "ssh" {
+			// The worker presents a new host key for every connection to an
+			// ssh target and checks the host's key itself
+			args = append(args, "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null")
+		}
 	case "putty":
 		args = append(args, "-P", port, ip)
 	}
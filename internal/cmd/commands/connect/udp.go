@@ -0,0 +1,87 @@
+package connect
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/boundary/globals"
+	"nhooyr.io/websocket"
+)
+
+// maxDatagramSize is the largest payload a UDP datagram can carry.
+const maxDatagramSize = 65507
+
+// relayUdp reads datagrams from the udp listener and relays them through the
+// worker. Each local peer gets its own proxy connection, so datagrams sent
+// back by the endpoint are returned to the peer which it is talking to.
+func (c *Command) relayUdp(tofuToken string, transport *http.Transport) {
+	defer c.connWg.Done()
+
+	var peersLock sync.Mutex
+	peers := make(map[string]*websocket.Conn)
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, peerAddr, err := c.udpListener.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-c.proxyCtx.Done():
+				return
+			case <-c.Context.Done():
+				return
+			default:
+				// When this hits zero we trigger listener close so this
+				// isn't actually an error condition
+				if c.connectionsLeft.Load() == 0 {
+					return
+				}
+				c.UI.Error(err.Error())
+				continue
+			}
+		}
+
+		peersLock.Lock()
+		conn, ok := peers[peerAddr.String()]
+		peersLock.Unlock()
+		if !ok {
+			conn, err = c.openProxy(tofuToken, transport, globals.UdpProxyV1)
+			if err != nil {
+				c.UI.Error(err.Error())
+				continue
+			}
+			conn.SetReadLimit(maxDatagramSize)
+
+			peersLock.Lock()
+			peers[peerAddr.String()] = conn
+			peersLock.Unlock()
+
+			c.connWg.Add(1)
+			go func(conn *websocket.Conn, peerAddr *net.UDPAddr) {
+				defer c.connWg.Done()
+				defer func() {
+					peersLock.Lock()
+					delete(peers, peerAddr.String())
+					peersLock.Unlock()
+					conn.Close(websocket.StatusNormalClosure, "")
+				}()
+				for {
+					typ, b, err := conn.Read(c.proxyCtx)
+					if err != nil {
+						return
+					}
+					if typ != websocket.MessageBinary {
+						continue
+					}
+					if _, err := c.udpListener.WriteToUDP(b, peerAddr); err != nil {
+						return
+					}
+				}
+			}(conn, peerAddr)
+		}
+
+		if err := conn.Write(c.proxyCtx, websocket.MessageBinary, buf[:n]); err != nil {
+			c.UI.Error(err.Error())
+		}
+	}
+}
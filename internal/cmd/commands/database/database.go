@@ -27,6 +27,10 @@ func (c *Command) Help() string {
 		"",
 		`      $ boundary database init`,
 		"",
+		"    Verify the database schema:",
+		"",
+		`      $ boundary database verify`,
+		"",
 		"  Please see the database subcommand help for detailed usage information.",
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
 )
 
 type RoleInfo struct {
@@ -174,3 +175,54 @@ func generateInitialTargetTableOutput(in *TargetInfo) string {
 
 	return base.WrapForHelpText(ret)
 }
+
+func generateSchemaReportTableOutput(in *db.SchemaReport) string {
+	nonAttributeMap := map[string]interface{}{
+		"Version":          in.Version,
+		"Expected Version": in.ExpectedVersion,
+		"Dirty":            in.Dirty,
+	}
+
+	maxLength := 0
+	for k := range nonAttributeMap {
+		if len(k) > maxLength {
+			maxLength = len(k)
+		}
+	}
+
+	ret := []string{
+		"",
+		"Database schema information:",
+		base.WrapMap(2, maxLength+2, nonAttributeMap),
+	}
+
+	if !in.Drifted() {
+		ret = append(ret, "", "  The database schema matches the migrations.")
+		return base.WrapForHelpText(ret)
+	}
+
+	if len(in.Missing) > 0 {
+		ret = append(ret, "", "  Missing:")
+		for _, o := range in.Missing {
+			ret = append(ret, fmt.Sprintf("    %s %s", o.Type, o.Name))
+		}
+	}
+	if len(in.Unexpected) > 0 {
+		ret = append(ret, "", "  Unexpected:")
+		for _, o := range in.Unexpected {
+			ret = append(ret, fmt.Sprintf("    %s %s", o.Type, o.Name))
+		}
+	}
+	if len(in.Changed) > 0 {
+		ret = append(ret, "", "  Changed:")
+		for _, o := range in.Changed {
+			ret = append(ret,
+				fmt.Sprintf("    %s %s", o.Type, o.Name),
+				fmt.Sprintf("      Expected: %s", o.Expected),
+				fmt.Sprintf("      Actual:   %s", o.Actual),
+			)
+		}
+	}
+
+	return base.WrapForHelpText(ret)
+}
@@ -292,16 +292,13 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 	}
 
 	var jsonMap map[string]interface{}
-	if base.Format(c.UI) == "json" {
+	if base.Format(c.UI) != "table" {
 		jsonMap = make(map[string]interface{})
 		defer func() {
-			b, err := base.JsonFormatter{}.Format(jsonMap)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(jsonMap); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				retCode = 1
-				return
 			}
-			c.UI.Output(string(b))
 		}()
 	}
 
@@ -322,7 +319,7 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateInitialRoleTableOutput(roleInfo))
-	case "json":
+	case "json", "yaml":
 		jsonMap["login_role"] = roleInfo
 	}
 
@@ -350,7 +347,7 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateInitialAuthTableOutput(authMethodInfo))
-	case "json":
+	case "json", "yaml":
 		jsonMap["auth_method"] = authMethodInfo
 	}
 
@@ -372,7 +369,7 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateInitialScopeTableOutput(orgScopeInfo))
-	case "json":
+	case "json", "yaml":
 		jsonMap["org_scope"] = orgScopeInfo
 	}
 
@@ -384,7 +381,7 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateInitialScopeTableOutput(projScopeInfo))
-	case "json":
+	case "json", "yaml":
 		jsonMap["proj_scope"] = projScopeInfo
 	}
 
@@ -411,7 +408,7 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateInitialHostResourcesTableOutput(hostInfo))
-	case "json":
+	case "json", "yaml":
 		jsonMap["host_resources"] = hostInfo
 	}
 
@@ -437,7 +434,7 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateInitialTargetTableOutput(targetInfo))
-	case "json":
+	case "json", "yaml":
 		jsonMap["target"] = targetInfo
 	}
 
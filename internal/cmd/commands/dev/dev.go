@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/config"
@@ -68,6 +69,12 @@ Usage: boundary dev [options]
 
       $ boundary dev
 
+  This runs a controller and a worker in a single process, backed by a
+  Postgres database started in Docker unless -database-url is given. An org,
+  project, password auth method, admin user, host catalog, host set, host
+  and TCP target are generated, and once the environment is ready the
+  commands to log in and connect to the target are printed.
+
   For a full list of examples, please see the documentation.
 
 ` + c.Flags().Help()
@@ -328,6 +335,11 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	if err := c.SetupEventing(c.Config.Events); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	if c.flagRecoveryKey != "" {
 		c.Config.DevRecoveryKey = c.flagRecoveryKey
 	}
@@ -351,7 +363,7 @@ func (c *Command) Run(args []string) int {
 	c.Info["[Recovery] AEAD Key Bytes"] = c.Config.DevRecoveryKey
 
 	// Initialize the listeners
-	if err := c.SetupListeners(c.UI, c.Config.SharedConfig, []string{"api", "cluster", "proxy"}); err != nil {
+	if err := c.SetupListeners(c.UI, c.Config.SharedConfig, []string{"api", "cluster", "proxy", "ops"}); err != nil {
 		c.UI.Error(err.Error())
 		return 1
 	}
@@ -458,6 +470,8 @@ func (c *Command) Run(args []string) int {
 		}
 	}
 
+	c.UI.Output(c.loginInstructions())
+
 	// Wait for shutdown
 	shutdownTriggered := false
 
@@ -485,3 +499,40 @@ func (c *Command) Run(args []string) int {
 
 	return 0
 }
+
+// loginInstructions returns the commands with which to authenticate to the
+// dev environment and connect to its generated target. The -addr flag is only
+// included when the API isn't listening at the address the CLI would use
+// by default.
+func (c *Command) loginInstructions() string {
+	var addr string
+	for _, ln := range c.Listeners {
+		if ln.Config.Purpose[0] != "api" {
+			continue
+		}
+		switch {
+		case ln.Config.Type == "unix":
+			addr = "unix://" + ln.Config.Address
+		case ln.Config.TLSDisable:
+			addr = "http://" + ln.Mux.Addr().String()
+		default:
+			addr = "https://" + ln.Mux.Addr().String()
+		}
+	}
+	var addrFlag string
+	if def, err := api.DefaultConfig(); addr != "" && (err != nil || addr != def.Addr) {
+		addrFlag = fmt.Sprintf(" -addr %s", addr)
+	}
+	// The commands aren't wrapped so that they can be copied as-is
+	return strings.Join([]string{
+		"==> Boundary dev environment started. Log in with:",
+		"",
+		fmt.Sprintf("      $ boundary authenticate password%s -auth-method-id %s -login-name %s -password %s",
+			addrFlag, c.DevAuthMethodId, c.DevLoginName, c.DevPassword),
+		"",
+		"    and connect to the generated target with:",
+		"",
+		fmt.Sprintf("      $ boundary connect%s -target-id %s", addrFlag, c.DevTargetId),
+		"",
+	}, "\n")
+}
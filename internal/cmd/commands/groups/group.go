@@ -46,7 +46,7 @@ var flagsMap = map[string][]string{
 	"update":         {"id", "name", "description", "version"},
 	"read":           {"id"},
 	"delete":         {"id"},
-	"list":           {"scope-id"},
+	"list":           {"scope-id", "filter", "sort-by", "sort-dir"},
 	"add-members":    {"id", "member", "version"},
 	"set-members":    {"id", "member", "version"},
 	"remove-members": {"id", "member", "version"},
@@ -105,6 +105,17 @@ func (c *Command) Run(args []string) int {
 
 	var opts []groups.Option
 
+	if c.FlagFilter != "" {
+		opts = append(opts, groups.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, groups.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, groups.WithSortDir(c.FlagSortDir))
+	}
+
 	switch c.FlagName {
 	case "":
 	case "null":
@@ -200,7 +211,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -217,17 +228,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedGroups := listResult.GetItems().([]*groups.Group)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedGroups) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedGroups)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedGroups); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedGroups) == 0 {
@@ -269,13 +278,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateGroupTableOutput(group))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(group)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(group); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
@@ -30,7 +30,7 @@ func (c *Command) Synopsis() string {
 var flagsMap = map[string][]string{
 	"read":   {"id"},
 	"delete": {"id"},
-	"list":   {"scope-id"},
+	"list":   {"scope-id", "filter", "sort-by", "sort-dir"},
 }
 
 func (c *Command) Help() string {
@@ -130,6 +130,17 @@ func (c *Command) Run(args []string) int {
 
 	var opts []hostcatalogs.Option
 
+	if c.FlagFilter != "" {
+		opts = append(opts, hostcatalogs.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, hostcatalogs.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, hostcatalogs.WithSortDir(c.FlagSortDir))
+	}
+
 	switch c.FlagName {
 	case "":
 	case "null":
@@ -181,7 +192,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -198,17 +209,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedCatalogs := listResult.GetItems().([]*hostcatalogs.HostCatalog)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedCatalogs) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedCatalogs)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedCatalogs); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedCatalogs) == 0 {
@@ -251,13 +260,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateHostCatalogTableOutput(catalog))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(catalog)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(catalog); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
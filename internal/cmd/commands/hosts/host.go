@@ -30,7 +30,7 @@ func (c *Command) Synopsis() string {
 var flagsMap = map[string][]string{
 	"read":   {"id"},
 	"delete": {"id"},
-	"list":   {"host-catalog-id"},
+	"list":   {"host-catalog-id", "filter", "sort-by", "sort-dir"},
 }
 
 func (c *Command) Help() string {
@@ -129,6 +129,17 @@ func (c *Command) Run(args []string) int {
 
 	var opts []hosts.Option
 
+	if c.FlagFilter != "" {
+		opts = append(opts, hosts.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, hosts.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, hosts.WithSortDir(c.FlagSortDir))
+	}
+
 	switch c.FlagName {
 	case "":
 	case "null":
@@ -180,7 +191,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -197,17 +208,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedHosts := listResult.GetItems().([]*hosts.Host)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedHosts) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedHosts)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedHosts); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedHosts) == 0 {
@@ -250,13 +259,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateHostTableOutput(host))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(host)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(host); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
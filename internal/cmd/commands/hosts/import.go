@@ -0,0 +1,244 @@
+package hosts
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/hosts"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/common"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ImportCommand)(nil)
+var _ cli.CommandAutocomplete = (*ImportCommand)(nil)
+
+// importRow is one host in an import file.
+type importRow struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Address     string `json:"address"`
+}
+
+type ImportCommand struct {
+	*base.Command
+
+	flagFile   string
+	flagFormat string
+}
+
+func (c *ImportCommand) Synopsis() string {
+	return "Create many static hosts from a CSV or JSON file"
+}
+
+func (c *ImportCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary hosts import [options] [args]",
+		"",
+		"  Create static hosts in a host catalog from a CSV or JSON file. Example:",
+		"",
+		`    $ boundary hosts import -host-catalog-id hcst_1234567890 -file hosts.csv`,
+		"",
+		"  A CSV file must have a header row naming its columns, which can be name,",
+		"  description and address. A JSON file must contain an array of objects",
+		"  with the same keys. Hosts which can't be created are reported without",
+		"  preventing the others from being created.",
+		"",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *ImportCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+	common.PopulateCommonFlags(c.Command, f, resource.Host.String(), []string{"host-catalog-id"})
+
+	f.StringVar(&base.StringVar{
+		Name:       "file",
+		Target:     &c.flagFile,
+		Completion: complete.PredictFiles("*"),
+		Usage:      `The file to read hosts from, or "-" to read them from standard input.`,
+	})
+	f.StringVar(&base.StringVar{
+		Name:       "format",
+		Target:     &c.flagFormat,
+		Completion: complete.PredictSet("csv", "json"),
+		Usage:      `The format of the file, "csv" or "json". Defaults to the file's extension, or "csv" if it has neither.`,
+	})
+
+	return set
+}
+
+func (c *ImportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *ImportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ImportCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.FlagHostCatalogId == "" {
+		c.UI.Error("Host Catalog ID must be passed in via -host-catalog-id")
+		return 1
+	}
+	if c.flagFile == "" {
+		c.UI.Error("A file must be passed in via -file")
+		return 1
+	}
+
+	format := strings.ToLower(c.flagFormat)
+	if format == "" {
+		format = "csv"
+		if strings.ToLower(filepath.Ext(c.flagFile)) == ".json" {
+			format = "json"
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if c.flagFile != "-" {
+		file, err := os.Open(c.flagFile)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error opening file: %s", err))
+			return 1
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var rows []*importRow
+	var err error
+	switch format {
+	case "csv":
+		rows, err = readCsvRows(in)
+	case "json":
+		err = json.NewDecoder(in).Decode(&rows)
+	default:
+		c.UI.Error(fmt.Sprintf("Unknown format %q", c.flagFormat))
+		return 1
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading hosts: %s", err))
+		return 1
+	}
+	if len(rows) == 0 {
+		c.UI.Error("No hosts found in file")
+		return 1
+	}
+
+	toImport := make([]*hosts.Host, 0, len(rows))
+	for _, r := range rows {
+		h := &hosts.Host{
+			Name:        r.Name,
+			Description: r.Description,
+			Type:        "static",
+		}
+		if r.Address != "" {
+			h.Attributes = map[string]interface{}{"address": r.Address}
+		}
+		toImport = append(toImport, h)
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	result, err := hosts.NewClient(client).Import(c.Context, c.FlagHostCatalogId, toImport)
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			c.UI.Error(fmt.Sprintf("Error from controller when performing import on hosts: %s", base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error trying to import hosts: %s", err.Error()))
+		return 2
+	}
+
+	switch base.Format(c.UI) {
+	case "json", "yaml":
+		if err := c.PrintStructured(result); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
+			return 1
+		}
+
+	case "table":
+		output := []string{
+			"",
+			fmt.Sprintf("Imported %d of %d hosts.", len(result.Items), len(rows)),
+		}
+		if len(result.Errors) > 0 {
+			output = append(output, "", "Hosts not imported:")
+			for _, e := range result.Errors {
+				output = append(output, fmt.Sprintf("  Host %d: %s", e.Row+1, e.Message))
+				var fields []string
+				for k := range e.Fields {
+					fields = append(fields, k)
+				}
+				sort.Strings(fields)
+				for _, k := range fields {
+					output = append(output, fmt.Sprintf("    %s: %s", k, e.Fields[k]))
+				}
+			}
+		}
+		c.UI.Output(base.WrapForHelpText(output))
+	}
+
+	if len(result.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// readCsvRows reads the hosts in a CSV file whose first row names its
+// columns.
+func readCsvRows(in io.Reader) ([]*importRow, error) {
+	records, err := csv.NewReader(in).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := records[0]
+	for i, col := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(col))
+		switch header[i] {
+		case "name", "description", "address":
+		default:
+			return nil, fmt.Errorf("unknown column %q", col)
+		}
+	}
+	var rows []*importRow
+	for _, record := range records[1:] {
+		r := &importRow{}
+		for i, v := range record {
+			switch header[i] {
+			case "name":
+				r.Name = v
+			case "description":
+				r.Description = v
+			case "address":
+				r.Address = v
+			}
+		}
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+}
+
+func (c *Command) Synopsis() string {
+	return "Inspect the background jobs run by Boundary controllers"
+}
+
+func (c *Command) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary jobs [sub command] [options] [args]",
+		"",
+		"  This command allows inspection of the background jobs the controllers run on a schedule. These commands connect directly to the database using the controller's configuration file. Example:",
+		"",
+		"    List the jobs and the outcome of their last run:",
+		"",
+		`      $ boundary jobs list -config=/etc/boundary/controller.hcl`,
+		"",
+		"  Please see the jobs subcommand help for detailed usage information.",
+	})
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	return nil
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ListCommand)(nil)
+var _ cli.CommandAutocomplete = (*ListCommand)(nil)
+
+// ListCommand lists the jobs registered by the controllers' schedulers.
+type ListCommand struct {
+	*base.Command
+	base.ControllerConfig
+}
+
+func (c *ListCommand) Synopsis() string {
+	return "List the background jobs run by the controllers"
+}
+
+func (c *ListCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary jobs list [options]",
+		"",
+		"  List the background jobs run by the controllers along with their schedule and the outcome of their last run. Example:",
+		"",
+		`    $ boundary jobs list -config=/etc/boundary/controller.hcl`,
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *ListCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	c.AddConfigFlags(f)
+
+	return set
+}
+
+func (c *ListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ListCommand) Run(args []string) int {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if result := c.LoadConfig(c.Command); result > 0 {
+		return result
+	}
+	defer c.FinalizeConfigWrapper(c.Command)
+
+	rw, _, err := c.ConnectToDatabase(c.Command)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	repo, err := scheduler.NewRepository(rw, rw)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating scheduler repository: %w", err).Error())
+		return 1
+	}
+	jobs, err := repo.ListJobs(c.Context)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error listing jobs: %w", err).Error())
+		return 1
+	}
+
+	switch base.Format(c.UI) {
+	case "json", "yaml":
+		if len(jobs) == 0 {
+			c.UI.Output("null")
+			return 0
+		}
+		if err := c.PrintStructured(jobs); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
+			return 1
+		}
+
+	case "table":
+		if len(jobs) == 0 {
+			c.UI.Output("No jobs found")
+			return 0
+		}
+		output := []string{
+			"",
+			"Job information:",
+		}
+		for i, j := range jobs {
+			if i > 0 {
+				output = append(output, "")
+			}
+			output = append(output,
+				fmt.Sprintf("  Name:                 %s", j.Name),
+				fmt.Sprintf("    Description:        %s", j.Description),
+				fmt.Sprintf("    Next Scheduled Run: %s", formatTime(j.NextScheduledRun)),
+			)
+			if j.RunningServerId != nil {
+				output = append(output,
+					fmt.Sprintf("    Running On:         %s", *j.RunningServerId),
+				)
+			}
+			if j.LastRunStatus != nil {
+				output = append(output,
+					fmt.Sprintf("    Last Run Status:    %s", *j.LastRunStatus),
+				)
+			}
+			if j.LastRunEndTime != nil {
+				output = append(output,
+					fmt.Sprintf("    Last Run End Time:  %s", formatTime(*j.LastRunEndTime)),
+				)
+			}
+			if j.LastRunError != nil {
+				output = append(output,
+					fmt.Sprintf("    Last Run Error:     %s", *j.LastRunError),
+				)
+			}
+			if j.FailureCount > 0 {
+				output = append(output,
+					fmt.Sprintf("    Failure Count:      %d", j.FailureCount),
+				)
+			}
+		}
+		c.UI.Output(base.WrapForHelpText(output))
+	}
+	return 0
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format(time.RFC1123)
+}
@@ -59,6 +59,16 @@ func removePrincipalsHelp() string {
 	})
 }
 
+func cloneHelp() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary roles clone [options] [args]",
+		"",
+		`  Creates a copy of a role given its ID, in the same scope and with the same description, grant scope and grants. Principals are not copied. Example:`,
+		"",
+		`    $ boundary roles clone -id r_1234567890 -name "Read-only operators"`,
+	})
+}
+
 func populateFlags(c *Command, f *base.FlagSet, flagNames []string) {
 	common.PopulateCommonFlags(c.Command, f, resource.Role.String(), flagNames)
 
@@ -82,6 +92,12 @@ func populateFlags(c *Command, f *base.FlagSet, flagNames []string) {
 				Target: &c.flagGrants,
 				Usage:  "The grants to add, remove, or set. May be specified multiple times. Can be in compact string format or JSON (be sure to escape JSON properly).",
 			})
+		case "template":
+			f.StringVar(&base.StringVar{
+				Name:   "template",
+				Target: &c.flagTemplate,
+				Usage:  `The name of a role template to create the role with the grants of: "auditor", "session-manager" or "target-admin"`,
+			})
 		}
 	}
 }
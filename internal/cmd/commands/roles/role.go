@@ -26,6 +26,7 @@ type Command struct {
 	flagGrantScopeId string
 	flagPrincipals   []string
 	flagGrants       []string
+	flagTemplate     string
 }
 
 func (c *Command) Synopsis() string {
@@ -36,6 +37,8 @@ func (c *Command) Synopsis() string {
 		return principalsGrantsSynopsisFunc(c.Func, true)
 	case "add-grants", "set-grants", "remove-grants":
 		return principalsGrantsSynopsisFunc(c.Func, false)
+	case "clone":
+		return "Create a copy of a role with its grants"
 	}
 	return ""
 }
@@ -48,21 +51,23 @@ var helpMap = func() map[string]func() string {
 	ret["add-grants"] = addPrincipalsHelp
 	ret["set-grants"] = setPrincipalsHelp
 	ret["remove-grants"] = removePrincipalsHelp
+	ret["clone"] = cloneHelp
 	return ret
 }
 
 var flagsMap = map[string][]string{
-	"create":            {"scope-id", "name", "description", "grantscopeid"},
+	"create":            {"scope-id", "name", "description", "grantscopeid", "template"},
 	"update":            {"id", "name", "description", "grantscopeid", "version"},
 	"read":              {"id"},
 	"delete":            {"id"},
-	"list":              {"scope-id"},
+	"list":              {"scope-id", "filter", "sort-by", "sort-dir"},
 	"add-principals":    {"id", "principal", "version"},
 	"set-principals":    {"id", "principal", "version"},
 	"remove-principals": {"id", "principal", "version"},
 	"add-grants":        {"id", "grant", "version"},
 	"set-grants":        {"id", "grant", "version"},
 	"remove-grants":     {"id", "grant", "version"},
+	"clone":             {"id", "name", "description"},
 }
 
 func (c *Command) Help() string {
@@ -118,6 +123,17 @@ func (c *Command) Run(args []string) int {
 
 	var opts []roles.Option
 
+	if c.FlagFilter != "" {
+		opts = append(opts, roles.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, roles.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, roles.WithSortDir(c.FlagSortDir))
+	}
+
 	switch c.FlagName {
 	case "":
 	case "null":
@@ -139,6 +155,9 @@ func (c *Command) Run(args []string) int {
 	default:
 		opts = append(opts, roles.WithGrantScopeId(c.flagGrantScopeId))
 	}
+	if c.flagTemplate != "" {
+		opts = append(opts, roles.WithTemplate(c.flagTemplate))
+	}
 
 	principals := c.flagPrincipals
 	grants := c.flagGrants
@@ -193,7 +212,7 @@ func (c *Command) Run(args []string) int {
 	// Perform check-and-set when needed
 	var version uint32
 	switch c.Func {
-	case "create", "read", "delete", "list":
+	case "create", "read", "delete", "list", "clone":
 		// These don't udpate so don't need the existing version
 	default:
 		switch c.FlagVersion {
@@ -235,6 +254,8 @@ func (c *Command) Run(args []string) int {
 		result, err = roleClient.SetGrants(c.Context, c.FlagId, version, grants, opts...)
 	case "remove-grants":
 		result, err = roleClient.RemoveGrants(c.Context, c.FlagId, version, grants, opts...)
+	case "clone":
+		result, err = roleClient.Clone(c.Context, c.FlagId, opts...)
 	}
 
 	plural := "role"
@@ -253,7 +274,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -270,17 +291,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedRoles := listResult.GetItems().([]*roles.Role)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedRoles) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedRoles)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedRoles); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedRoles) == 0 {
@@ -322,13 +341,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateRoleTableOutput(role))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(role)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(role); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
@@ -21,6 +21,9 @@ func generateScopeTableOutput(in *scopes.Scope) string {
 	if in.Description != "" {
 		nonAttributeMap["Description"] = in.Description
 	}
+	if in.ReadOnly {
+		nonAttributeMap["Read Only"] = in.ReadOnly
+	}
 
 	maxLength := base.MaxAttributesLength(nonAttributeMap, nil, nil)
 
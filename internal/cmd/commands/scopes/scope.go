@@ -3,6 +3,7 @@ package scopes
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/scopes"
@@ -24,6 +25,7 @@ type Command struct {
 
 	flagSkipAdminRoleCreation   bool
 	flagSkipDefaultRoleCreation bool
+	flagReadOnly                string
 }
 
 func (c *Command) Synopsis() string {
@@ -32,10 +34,10 @@ func (c *Command) Synopsis() string {
 
 var flagsMap = map[string][]string{
 	"create": {"scope-id", "name", "description", "skip-admin-role-creation", "skip-default-role-creation"},
-	"update": {"id", "name", "description", "version"},
+	"update": {"id", "name", "description", "version", "read-only"},
 	"read":   {"id"},
 	"delete": {"id"},
-	"list":   {"scope-id"},
+	"list":   {"scope-id", "filter", "sort-by", "sort-dir"},
 }
 
 func (c *Command) Help() string {
@@ -63,6 +65,13 @@ func (c *Command) Flags() *base.FlagSets {
 			Usage:  "If set, a role granting the anonymous user access to log into auth methods and a few other actions within the newly-created scope will not automatically be created",
 		})
 	}
+	if c.Func == "update" {
+		f.StringVar(&base.StringVar{
+			Name:   "read-only",
+			Target: &c.flagReadOnly,
+			Usage:  "If true, resources in the scope, and in the projects of an org, can't be created or changed until the scope is updated with false.",
+		})
+	}
 
 	return set
 }
@@ -104,6 +113,17 @@ func (c *Command) Run(args []string) int {
 
 	var opts []scopes.Option
 
+	if c.FlagFilter != "" {
+		opts = append(opts, scopes.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, scopes.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, scopes.WithSortDir(c.FlagSortDir))
+	}
+
 	switch c.FlagName {
 	case "":
 	case "null":
@@ -120,6 +140,19 @@ func (c *Command) Run(args []string) int {
 		opts = append(opts, scopes.WithDescription(c.FlagDescription))
 	}
 
+	switch c.flagReadOnly {
+	case "":
+	case "null":
+		opts = append(opts, scopes.DefaultReadOnly())
+	default:
+		readOnly, err := strconv.ParseBool(c.flagReadOnly)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error parsing %q: %s", c.flagReadOnly, err))
+			return 1
+		}
+		opts = append(opts, scopes.WithReadOnly(readOnly))
+	}
+
 	if c.flagSkipAdminRoleCreation {
 		opts = append(opts, scopes.WithSkipAdminRoleCreation(c.flagSkipAdminRoleCreation))
 	}
@@ -180,7 +213,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -197,17 +230,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedScopes := listResult.GetItems().([]*scopes.Scope)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedScopes) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedScopes)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedScopes); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedScopes) == 0 {
@@ -249,13 +280,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateScopeTableOutput(scope))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(scope)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(scope); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
@@ -1,13 +1,17 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/event"
 	"github.com/hashicorp/boundary/internal/servers/controller"
 	"github.com/hashicorp/boundary/internal/servers/worker"
 	"github.com/hashicorp/boundary/sdk/wrapper"
@@ -36,11 +40,12 @@ type Command struct {
 
 	configWrapper wrapping.Wrapper
 
-	flagConfig      string
-	flagConfigKms   string
-	flagLogLevel    string
-	flagLogFormat   string
-	flagCombineLogs bool
+	flagConfig              string
+	flagConfigKms           string
+	flagLogLevel            string
+	flagLogFormat           string
+	flagCombineLogs         bool
+	flagSkipPreflightChecks bool
 }
 
 func (c *Command) Synopsis() string {
@@ -102,6 +107,12 @@ func (c *Command) Flags() *base.FlagSets {
 		Usage:      `Log format. Supported values are "standard" and "json".`,
 	})
 
+	f.BoolVar(&base.BoolVar{
+		Name:   "skip-preflight-checks",
+		Target: &c.flagSkipPreflightChecks,
+		Usage:  "If set, a controller whose preflight checks of its database, KMS and clock fail starts anyway, logging the failures as warnings.",
+	})
+
 	return set
 }
 
@@ -140,6 +151,11 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	if err := c.SetupEventing(c.Config.Events); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	if err := c.SetupKMSes(c.UI, c.Config); err != nil {
 		c.UI.Error(err.Error())
 		return 1
@@ -150,7 +166,7 @@ func (c *Command) Run(args []string) int {
 			return 1
 		}
 	}
-	if c.WorkerAuthKms == nil {
+	if c.WorkerAuthKms == nil && (c.Config.Worker == nil || c.Config.Worker.AuthStoragePath == "") {
 		c.UI.Error("Worker Auth KMS not found after parsing KMS blocks")
 		return 1
 	}
@@ -233,7 +249,7 @@ func (c *Command) Run(args []string) int {
 			c.Config.Worker.Controllers = []string{clusterAddr}
 		}
 	}
-	if err := c.SetupListeners(c.UI, c.Config.SharedConfig, []string{"api", "cluster", "proxy"}); err != nil {
+	if err := c.SetupListeners(c.UI, c.Config.SharedConfig, []string{"api", "cluster", "proxy", "ops"}); err != nil {
 		c.UI.Error(err.Error())
 		return 1
 	}
@@ -264,6 +280,14 @@ func (c *Command) Run(args []string) int {
 			return 1
 		}
 		c.DatabaseUrl = strings.TrimSpace(dbaseUrl)
+		if t := c.Config.Controller.Database.SlowQueryThreshold; t != "" {
+			threshold, err := time.ParseDuration(t)
+			if err != nil || threshold <= 0 {
+				c.UI.Error(fmt.Sprintf("Invalid \"slow_query_threshold\" %q in \"controller.database\" config block", t))
+				return 1
+			}
+			c.SlowQueryThreshold = threshold
+		}
 		if err := c.ConnectToDatabase("postgres"); err != nil {
 			c.UI.Error(fmt.Errorf("Error connecting to database: %w", err).Error())
 			return 1
@@ -355,8 +379,9 @@ func (c *Command) ParseFlagsAndConfig(args []string) int {
 
 func (c *Command) StartController() error {
 	conf := &controller.Config{
-		RawConfig: c.Config,
-		Server:    c.Server,
+		RawConfig:           c.Config,
+		Server:              c.Server,
+		SkipPreflightChecks: c.flagSkipPreflightChecks,
 	}
 
 	var err error
@@ -430,53 +455,27 @@ func (c *Command) WaitForInterrupt() int {
 			shutdownTriggered = true
 
 		case <-c.SighupCh:
-			c.UI.Output("==> Boundary controller reload triggered")
-
-			// Check for new log level
-			var level hclog.Level
-			var err error
-			var newConf *config.Config
+			c.UI.Output("==> Boundary server reload triggered")
 
-			if c.flagConfig == "" {
-				goto RUNRELOADFUNCS
-			}
-
-			newConf, err = config.LoadFile(c.flagConfig, c.configWrapper)
-			if err != nil {
+			var reloadErrors *multierror.Error
+			if err := c.reloadConfig(); err != nil {
 				c.Logger.Error("could not reload config", "path", c.flagConfig, "error", err)
-				goto RUNRELOADFUNCS
+				reloadErrors = multierror.Append(reloadErrors, err)
 			}
-
-			// Ensure at least one config was found.
-			if newConf == nil {
-				c.Logger.Error("no config found at reload time")
-				goto RUNRELOADFUNCS
+			if err := c.Reload(); err != nil {
+				c.UI.Error(fmt.Errorf("Error(s) were encountered during server reload: %w", err).Error())
+				reloadErrors = multierror.Append(reloadErrors, err)
 			}
 
-			if newConf.LogLevel != "" {
-				configLogLevel := strings.ToLower(strings.TrimSpace(newConf.LogLevel))
-				switch configLogLevel {
-				case "trace":
-					level = hclog.Trace
-				case "debug":
-					level = hclog.Debug
-				case "notice", "info", "":
-					level = hclog.Info
-				case "warn", "warning":
-					level = hclog.Warn
-				case "err", "error":
-					level = hclog.Error
-				default:
-					c.Logger.Error("unknown log level found on reload", "level", newConf.LogLevel)
-					goto RUNRELOADFUNCS
-				}
-				c.Logger.SetLevel(level)
+			data := map[string]interface{}{
+				"path":   c.flagConfig,
+				"status": "success",
 			}
-
-		RUNRELOADFUNCS:
-			if err := c.Reload(); err != nil {
-				c.UI.Error(fmt.Errorf("Error(s) were encountered during controller reload: %w", err).Error())
+			if err := reloadErrors.ErrorOrNil(); err != nil {
+				data["status"] = "failure"
+				data["error"] = err.Error()
 			}
+			event.WriteSystem(context.Background(), "server.(Command).reload", data)
 
 		case <-c.SigUSR2Ch:
 			buf := make([]byte, 32*1024*1024)
@@ -488,6 +487,52 @@ func (c *Command) WaitForInterrupt() int {
 	return 0
 }
 
+// reloadConfig reads the config file again and applies the settings which
+// can be changed while running: the log level, the controller's API rate
+// limits and the worker's tags. Listener certificates are reloaded by Reload.
+func (c *Command) reloadConfig() error {
+	if c.flagConfig == "" {
+		return nil
+	}
+
+	newConf, err := config.LoadFile(c.flagConfig, c.configWrapper)
+	if err != nil {
+		return err
+	}
+	// Ensure at least one config was found.
+	if newConf == nil {
+		return errors.New("no config found at reload time")
+	}
+
+	var reloadErrors *multierror.Error
+	if newConf.LogLevel != "" {
+		configLogLevel := strings.ToLower(strings.TrimSpace(newConf.LogLevel))
+		switch configLogLevel {
+		case "trace":
+			c.Logger.SetLevel(hclog.Trace)
+		case "debug":
+			c.Logger.SetLevel(hclog.Debug)
+		case "notice", "info", "":
+			c.Logger.SetLevel(hclog.Info)
+		case "warn", "warning":
+			c.Logger.SetLevel(hclog.Warn)
+		case "err", "error":
+			c.Logger.SetLevel(hclog.Error)
+		default:
+			reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("unknown log level %q", newConf.LogLevel))
+		}
+	}
+	if c.Config.Controller != nil {
+		if err := c.controller.Reload(newConf); err != nil {
+			reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("error reloading controller: %w", err))
+		}
+	}
+	if c.Config.Worker != nil {
+		c.worker.Reload(newConf)
+	}
+	return reloadErrors.ErrorOrNil()
+}
+
 func (c *Command) Reload() error {
 	c.ReloadFuncsLock.RLock()
 	defer c.ReloadFuncsLock.RUnlock()
@@ -28,6 +28,12 @@ func generateSessionTableOutput(in *sessions.Session) string {
 	if len(strings.TrimSpace(in.TerminationReason)) > 0 {
 		nonAttributeMap["Termination Reason"] = in.TerminationReason
 	}
+	if in.HostAddress != "" {
+		nonAttributeMap["Host Address"] = in.HostAddress
+	}
+	if in.HostSelectionReason != "" {
+		nonAttributeMap["Host Selection Reason"] = in.HostSelectionReason
+	}
 
 	maxLength := base.MaxAttributesLength(nonAttributeMap, nil, nil)
 
@@ -61,6 +67,34 @@ func generateSessionTableOutput(in *sessions.Session) string {
 		}
 	}
 
+	var connectionMaps []map[string]interface{}
+	if len(in.Connections) > 0 {
+		for _, conn := range in.Connections {
+			m := map[string]interface{}{
+				"ID":               conn.Id,
+				"Client Address":   fmt.Sprintf("%s:%d", conn.ClientTcpAddress, conn.ClientTcpPort),
+				"Endpoint Address": fmt.Sprintf("%s:%d", conn.EndpointTcpAddress, conn.EndpointTcpPort),
+				"Bytes Up":         conn.BytesUp,
+				"Bytes Down":       conn.BytesDown,
+				"Created Time":     conn.CreatedTime.Local().Format(time.RFC1123),
+			}
+			if conn.DatagramsUp > 0 || conn.DatagramsDown > 0 {
+				m["Datagrams Up"] = conn.DatagramsUp
+				m["Datagrams Down"] = conn.DatagramsDown
+			}
+			if !conn.LastActivityTime.IsZero() {
+				m["Last Activity Time"] = conn.LastActivityTime.Local().Format(time.RFC1123)
+			}
+			if conn.ClosedReason != "" {
+				m["Closed Reason"] = conn.ClosedReason
+			}
+			connectionMaps = append(connectionMaps, m)
+		}
+		if l := len("Endpoint Address"); l > maxLength {
+			maxLength = l
+		}
+	}
+
 	ret := []string{
 		"",
 		"Session information:",
@@ -95,5 +129,18 @@ func generateSessionTableOutput(in *sessions.Session) string {
 		}
 	}
 
+	if len(in.Connections) > 0 {
+		ret = append(ret,
+			"",
+			"  Connections:",
+		)
+		for _, m := range connectionMaps {
+			ret = append(ret,
+				base.WrapMap(4, maxLength, m),
+				"",
+			)
+		}
+	}
+
 	return base.WrapForHelpText(ret)
 }
@@ -30,7 +30,7 @@ func (c *Command) Synopsis() string {
 var flagsMap = map[string][]string{
 	"read":   {"id"},
 	"cancel": {"id"},
-	"list":   {"scope-id"},
+	"list":   {"scope-id", "filter", "sort-by", "sort-dir"},
 }
 
 func (c *Command) Help() string {
@@ -110,6 +110,19 @@ func (c *Command) Run(args []string) int {
 
 	sessionClient := sessions.NewClient(client)
 
+	var opts []sessions.Option
+
+	if c.FlagFilter != "" {
+		opts = append(opts, sessions.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, sessions.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, sessions.WithSortDir(c.FlagSortDir))
+	}
+
 	var result api.GenericResult
 	var listResult api.GenericListResult
 
@@ -119,7 +132,7 @@ func (c *Command) Run(args []string) int {
 	case "cancel":
 		result, err = sessionClient.Cancel(c.Context, c.FlagId, 0, sessions.WithAutomaticVersioning(true))
 	case "list":
-		listResult, err = sessionClient.List(c.Context, c.FlagScopeId)
+		listResult, err = sessionClient.List(c.Context, c.FlagScopeId, opts...)
 	}
 
 	plural := "session"
@@ -139,17 +152,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedSessions := listResult.GetItems().([]*sessions.Session)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedSessions) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedSessions)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedSessions); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedSessions) == 0 {
@@ -184,13 +195,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateSessionTableOutput(sess))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(sess)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(sess); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
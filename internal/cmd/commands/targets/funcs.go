@@ -41,6 +41,21 @@ func generateTargetTableOutput(in *targets.Target) string {
 	if in.Description != "" {
 		nonAttributeMap["Description"] = in.Description
 	}
+	if in.ConnectionIdleTimeoutSeconds != 0 {
+		nonAttributeMap["Connection Idle Timeout Seconds"] = in.ConnectionIdleTimeoutSeconds
+	}
+	if in.ConnectionBandwidthLimit != 0 {
+		nonAttributeMap["Connection Bandwidth Limit"] = in.ConnectionBandwidthLimit
+	}
+	if in.AuthorizationValidSeconds != 0 {
+		nonAttributeMap["Authorization Valid Seconds"] = in.AuthorizationValidSeconds
+	}
+	if in.AccessSchedule != "" {
+		nonAttributeMap["Access Schedule"] = in.AccessSchedule
+	}
+	if in.TerminateSessionsAtScheduleEnd {
+		nonAttributeMap["Terminate Sessions At Schedule End"] = in.TerminateSessionsAtScheduleEnd
+	}
 
 	maxLength := base.MaxAttributesLength(nonAttributeMap, in.Attributes, keySubstMap)
 
@@ -121,6 +136,46 @@ func generateAuthorizationTableOutput(in *targets.SessionAuthorization) string {
 	return base.WrapForHelpText(ret)
 }
 
+func generateDryRunTableOutput(in *targets.SessionAuthorization) string {
+	nonAttributeMap := map[string]interface{}{
+		"Target ID":   in.TargetId,
+		"Can Connect": len(in.DenialReasons) == 0,
+	}
+	if in.Scope != nil {
+		nonAttributeMap["Scope ID"] = in.Scope.Id
+	}
+	if in.UserId != "" {
+		nonAttributeMap["User ID"] = in.UserId
+	}
+	if in.HostId != "" {
+		nonAttributeMap["Host ID"] = in.HostId
+	}
+	if in.Type != "" {
+		nonAttributeMap["Type"] = in.Type
+	}
+
+	maxLength := 0
+	for k := range nonAttributeMap {
+		if len(k) > maxLength {
+			maxLength = len(k)
+		}
+	}
+
+	ret := []string{
+		"",
+		"Session authorization dry run:",
+		base.WrapMap(2, maxLength+2, nonAttributeMap),
+	}
+	if len(in.DenialReasons) > 0 {
+		ret = append(ret, "", "  Denial Reasons:")
+		for _, r := range in.DenialReasons {
+			ret = append(ret, fmt.Sprintf("    %s", r))
+		}
+	}
+
+	return base.WrapForHelpText(ret)
+}
+
 var keySubstMap = map[string]string{
 	"default_port": "Default Port",
 }
@@ -25,6 +25,7 @@ type Command struct {
 
 	flagHostSets []string
 	flagHostId   string
+	flagDryRun   bool
 }
 
 func (c *Command) Synopsis() string {
@@ -39,10 +40,10 @@ func (c *Command) Synopsis() string {
 }
 
 var flagsMap = map[string][]string{
-	"authorize-session": {"id", "host-id"},
+	"authorize-session": {"id", "host-id", "dry-run"},
 	"read":              {"id"},
 	"delete":            {"id"},
-	"list":              {"scope-id"},
+	"list":              {"scope-id", "filter", "sort-by", "sort-dir"},
 	"add-host-sets":     {"id", "host-set", "version"},
 	"remove-host-sets":  {"id", "host-set", "version"},
 	"set-host-sets":     {"id", "host-set", "version"},
@@ -134,6 +135,10 @@ func (c *Command) Help() string {
 			"",
 			`      $ boundary targets authorize-session -id ttcp_1234567890`,
 			"",
+			"    Check whether a session could be authorized without creating one:",
+			"",
+			`      $ boundary targets authorize-session -id ttcp_1234567890 -dry-run`,
+			"",
 			"",
 		})
 	default:
@@ -165,6 +170,12 @@ func (c *Command) Flags() *base.FlagSets {
 				Target: &c.flagHostId,
 				Usage:  "The ID of a specific host to connect to out of the hosts from the target's host sets. If not specified, one is chosen at random.",
 			})
+		case "dry-run":
+			f.BoolVar(&base.BoolVar{
+				Name:   "dry-run",
+				Target: &c.flagDryRun,
+				Usage:  "If set, checks whether a session could be authorized without creating one, and prints the reasons it would be denied.",
+			})
 		}
 	}
 
@@ -214,6 +225,17 @@ func (c *Command) Run(args []string) int {
 
 	var opts []targets.Option
 
+	if c.FlagFilter != "" {
+		opts = append(opts, targets.WithFilter(c.FlagFilter))
+	}
+
+	if c.FlagSortBy != "" {
+		opts = append(opts, targets.WithSortBy(c.FlagSortBy))
+	}
+	if c.FlagSortDir != "" {
+		opts = append(opts, targets.WithSortDir(c.FlagSortDir))
+	}
+
 	switch c.FlagName {
 	case "":
 	case "null":
@@ -252,6 +274,9 @@ func (c *Command) Run(args []string) int {
 		if len(c.flagHostId) != 0 {
 			opts = append(opts, targets.WithHostId(c.flagHostId))
 		}
+		if c.flagDryRun {
+			opts = append(opts, targets.WithDryRun(true))
+		}
 	}
 
 	// Perform check-and-set when needed
@@ -316,7 +341,7 @@ func (c *Command) Run(args []string) int {
 	switch c.Func {
 	case "delete":
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			c.UI.Output("null")
 		case "table":
 			output := "The delete operation completed successfully"
@@ -333,17 +358,15 @@ func (c *Command) Run(args []string) int {
 	case "list":
 		listedTargets := listResult.GetItems().([]*targets.Target)
 		switch base.Format(c.UI) {
-		case "json":
+		case "json", "yaml":
 			if len(listedTargets) == 0 {
 				c.UI.Output("null")
 				return 0
 			}
-			b, err := base.JsonFormatter{}.Format(listedTargets)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			if err := c.PrintStructured(listedTargets); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 
 		case "table":
 			if len(listedTargets) == 0 {
@@ -385,14 +408,16 @@ func (c *Command) Run(args []string) int {
 		sa := sar.GetItem().(*targets.SessionAuthorization)
 		switch base.Format(c.UI) {
 		case "table":
+			if sa.DryRun {
+				c.UI.Output(generateDryRunTableOutput(sa))
+				break
+			}
 			c.UI.Output(generateAuthorizationTableOutput(sa))
-		case "json":
-			b, err := base.JsonFormatter{}.Format(sa)
-			if err != nil {
-				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+		case "json", "yaml":
+			if err := c.PrintStructured(sa); err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 				return 1
 			}
-			c.UI.Output(string(b))
 		}
 		return 0
 	}
@@ -401,13 +426,11 @@ func (c *Command) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateTargetTableOutput(target))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(target)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(target); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
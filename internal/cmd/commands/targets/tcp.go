@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"net/textproto"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/targets"
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/common"
+	"github.com/hashicorp/boundary/internal/cmd/config"
 	"github.com/hashicorp/boundary/sdk/strutil"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
@@ -25,6 +27,22 @@ type TcpCommand struct {
 	flagDefaultPort            string
 	flagSessionMaxSeconds      string
 	flagSessionConnectionLimit string
+	flagConnectionIdleTimeout  string
+	flagConnectionBandwidth    string
+	flagAuthorizationValid     string
+	flagAccessSchedule         string
+	flagTerminateAtScheduleEnd string
+	flagAllowedPorts           string
+	flagProtocol               string
+	flagSshUsername            string
+	flagSshPrivateKey          string
+	flagSshHostKey             string
+	flagHostSelectionStrategy  string
+	flagEndpointTls            string
+	flagTlsServerName          string
+	flagTlsCaBundle            string
+	flagTlsClientCertificate   string
+	flagTlsClientPrivateKey    string
 }
 
 func (c *TcpCommand) Synopsis() string {
@@ -32,8 +50,8 @@ func (c *TcpCommand) Synopsis() string {
 }
 
 var tcpFlagsMap = map[string][]string{
-	"create": {"scope-id", "name", "description", "default-port", "session-max-seconds", "session-connection-limit"},
-	"update": {"id", "name", "description", "version", "default-port", "session-max-seconds", "session-connection-limit"},
+	"create": {"scope-id", "name", "description", "default-port", "session-max-seconds", "session-connection-limit", "connection-idle-timeout-seconds", "connection-bandwidth-limit", "authorization-valid-seconds", "access-schedule", "terminate-sessions-at-schedule-end", "allowed-ports", "protocol", "ssh-username", "ssh-private-key", "ssh-host-key", "host-selection-strategy", "endpoint-tls", "tls-server-name", "tls-ca-bundle", "tls-client-certificate", "tls-client-private-key"},
+	"update": {"id", "name", "description", "version", "default-port", "session-max-seconds", "session-connection-limit", "connection-idle-timeout-seconds", "connection-bandwidth-limit", "authorization-valid-seconds", "access-schedule", "terminate-sessions-at-schedule-end", "allowed-ports", "protocol", "ssh-username", "ssh-private-key", "ssh-host-key", "host-selection-strategy", "endpoint-tls", "tls-server-name", "tls-ca-bundle", "tls-client-certificate", "tls-client-private-key"},
 }
 
 func (c *TcpCommand) Help() string {
@@ -89,6 +107,103 @@ func (c *TcpCommand) Flags() *base.FlagSets {
 				Target: &c.flagSessionConnectionLimit,
 				Usage:  "The maximum number of connections allowed for a session. -1 means unlimited.",
 			})
+		case "connection-idle-timeout-seconds":
+			f.StringVar(&base.StringVar{
+				Name:   "connection-idle-timeout-seconds",
+				Target: &c.flagConnectionIdleTimeout,
+				Usage:  `How long a connection can go without any traffic before it's closed. Can be specified as an integer number of seconds or a duration string. 0 means connections are never closed for being idle.`,
+			})
+		case "connection-bandwidth-limit":
+			f.StringVar(&base.StringVar{
+				Name:   "connection-bandwidth-limit",
+				Target: &c.flagConnectionBandwidth,
+				Usage:  "How many bytes per second each connection can proxy in each direction. 0 means connections are not limited.",
+			})
+		case "authorization-valid-seconds":
+			f.StringVar(&base.StringVar{
+				Name:   "authorization-valid-seconds",
+				Target: &c.flagAuthorizationValid,
+				Usage:  `How long after a session is authorized it can be connected to before it's canceled. Can be specified as an integer number of seconds or a duration string. 0 means sessions can be connected to until they expire.`,
+			})
+		case "access-schedule":
+			f.StringVar(&base.StringVar{
+				Name:   "access-schedule",
+				Target: &c.flagAccessSchedule,
+				Usage:  `The weekly windows during which sessions can be authorized, as an optional time zone followed by windows separated by semicolons, such as "TZ=America/New_York mon-fri 09:00-17:00; sat 10:00-14:00".`,
+			})
+		case "terminate-sessions-at-schedule-end":
+			f.StringVar(&base.StringVar{
+				Name:   "terminate-sessions-at-schedule-end",
+				Target: &c.flagTerminateAtScheduleEnd,
+				Usage:  "If true, sessions expire when the access schedule window they were authorized in ends.",
+			})
+		case "allowed-ports":
+			f.StringVar(&base.StringVar{
+				Name:   "allowed-ports",
+				Target: &c.flagAllowedPorts,
+				Usage:  `The ports and ranges of ports, besides the default port, that clients can connect to with "boundary connect -target-port", such as "5432,5433,6000-6010".`,
+			})
+		case "protocol":
+			f.StringVar(&base.StringVar{
+				Name:       "protocol",
+				Target:     &c.flagProtocol,
+				Completion: complete.PredictSet("tcp", "udp", "ssh"),
+				Usage:      `The protocol proxied to the endpoint: "tcp" (the default), "udp" or "ssh".`,
+			})
+		case "ssh-username":
+			f.StringVar(&base.StringVar{
+				Name:   "ssh-username",
+				Target: &c.flagSshUsername,
+				Usage:  `The username workers authenticate to the endpoint as when the protocol is "ssh".`,
+			})
+		case "ssh-private-key":
+			f.StringVar(&base.StringVar{
+				Name:   "ssh-private-key",
+				Target: &c.flagSshPrivateKey,
+				Usage:  `The PEM encoded private key workers authenticate to the endpoint with when the protocol is "ssh". To avoid it being in the command line, it can be read from a file with "file://" or from an environment variable with "env://".`,
+			})
+		case "ssh-host-key":
+			f.StringVar(&base.StringVar{
+				Name:   "ssh-host-key",
+				Target: &c.flagSshHostKey,
+				Usage:  `The public key, in authorized_keys format, the endpoint must present when the protocol is "ssh". It can be read from a file with "file://".`,
+			})
+		case "host-selection-strategy":
+			f.StringVar(&base.StringVar{
+				Name:   "host-selection-strategy",
+				Target: &c.flagHostSelectionStrategy,
+				Usage:  `How the host of a session is chosen when none is requested: "random" (the default), "round_robin" or "sticky", which gives each user the host they were last given.`,
+			})
+		case "endpoint-tls":
+			f.StringVar(&base.StringVar{
+				Name:   "endpoint-tls",
+				Target: &c.flagEndpointTls,
+				Usage:  `If true, workers connect to the endpoint with TLS when the protocol is "tcp".`,
+			})
+		case "tls-server-name":
+			f.StringVar(&base.StringVar{
+				Name:   "tls-server-name",
+				Target: &c.flagTlsServerName,
+				Usage:  "The name the endpoint's certificate is verified against when endpoint TLS is used. Defaults to the host's address.",
+			})
+		case "tls-ca-bundle":
+			f.StringVar(&base.StringVar{
+				Name:   "tls-ca-bundle",
+				Target: &c.flagTlsCaBundle,
+				Usage:  `The PEM encoded certificates the endpoint's certificate is verified with when endpoint TLS is used, instead of the worker's system roots. It can be read from a file with "file://".`,
+			})
+		case "tls-client-certificate":
+			f.StringVar(&base.StringVar{
+				Name:   "tls-client-certificate",
+				Target: &c.flagTlsClientCertificate,
+				Usage:  `The PEM encoded certificate workers authenticate to the endpoint with when endpoint TLS is used. It can be read from a file with "file://".`,
+			})
+		case "tls-client-private-key":
+			f.StringVar(&base.StringVar{
+				Name:   "tls-client-private-key",
+				Target: &c.flagTlsClientPrivateKey,
+				Usage:  `The PEM encoded private key of the TLS client certificate. To avoid it being in the command line, it can be read from a file with "file://" or from an environment variable with "env://".`,
+			})
 		}
 	}
 
@@ -194,6 +309,198 @@ func (c *TcpCommand) Run(args []string) int {
 		opts = append(opts, targets.WithSessionConnectionLimit(int32(limit)))
 	}
 
+	switch c.flagConnectionIdleTimeout {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultConnectionIdleTimeoutSeconds())
+	default:
+		var final uint32
+		secs, err := strconv.ParseUint(c.flagConnectionIdleTimeout, 10, 32)
+		if err == nil {
+			final = uint32(secs)
+		} else {
+			dur, err := time.ParseDuration(c.flagConnectionIdleTimeout)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error parsing %q: %s", c.flagConnectionIdleTimeout, err))
+				return 1
+			}
+			final = uint32(dur.Seconds())
+		}
+		opts = append(opts, targets.WithConnectionIdleTimeoutSeconds(final))
+	}
+
+	switch c.flagConnectionBandwidth {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultConnectionBandwidthLimit())
+	default:
+		limit, err := strconv.ParseUint(c.flagConnectionBandwidth, 10, 32)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error parsing %q: %s", c.flagConnectionBandwidth, err))
+			return 1
+		}
+		opts = append(opts, targets.WithConnectionBandwidthLimit(uint32(limit)))
+	}
+
+	switch c.flagAuthorizationValid {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultAuthorizationValidSeconds())
+	default:
+		var final uint32
+		secs, err := strconv.ParseUint(c.flagAuthorizationValid, 10, 32)
+		if err == nil {
+			final = uint32(secs)
+		} else {
+			dur, err := time.ParseDuration(c.flagAuthorizationValid)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error parsing %q: %s", c.flagAuthorizationValid, err))
+				return 1
+			}
+			final = uint32(dur.Seconds())
+		}
+		opts = append(opts, targets.WithAuthorizationValidSeconds(final))
+	}
+
+	switch c.flagAccessSchedule {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultAccessSchedule())
+	default:
+		opts = append(opts, targets.WithAccessSchedule(c.flagAccessSchedule))
+	}
+
+	switch c.flagAllowedPorts {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetAllowedPorts())
+	default:
+		opts = append(opts, targets.WithTcpTargetAllowedPorts(c.flagAllowedPorts))
+	}
+
+	switch c.flagProtocol {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetProtocol())
+	default:
+		opts = append(opts, targets.WithTcpTargetProtocol(c.flagProtocol))
+	}
+
+	switch c.flagSshUsername {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetSshUsername())
+	default:
+		opts = append(opts, targets.WithTcpTargetSshUsername(c.flagSshUsername))
+	}
+
+	switch c.flagSshPrivateKey {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetSshPrivateKey())
+	default:
+		key, err := config.ParseAddress(c.flagSshPrivateKey)
+		if err != nil && err != config.ErrNotAUrl {
+			c.UI.Error(fmt.Sprintf("Error reading ssh private key: %s", err))
+			return 1
+		}
+		opts = append(opts, targets.WithTcpTargetSshPrivateKey(key))
+	}
+
+	switch c.flagSshHostKey {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetSshHostKey())
+	default:
+		key, err := config.ParseAddress(c.flagSshHostKey)
+		if err != nil && err != config.ErrNotAUrl {
+			c.UI.Error(fmt.Sprintf("Error reading ssh host key: %s", err))
+			return 1
+		}
+		opts = append(opts, targets.WithTcpTargetSshHostKey(strings.TrimSpace(key)))
+	}
+
+	switch c.flagHostSelectionStrategy {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetHostSelectionStrategy())
+	default:
+		opts = append(opts, targets.WithTcpTargetHostSelectionStrategy(c.flagHostSelectionStrategy))
+	}
+
+	switch c.flagEndpointTls {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetEndpointTls())
+	default:
+		endpointTls, err := strconv.ParseBool(c.flagEndpointTls)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error parsing %q: %s", c.flagEndpointTls, err))
+			return 1
+		}
+		opts = append(opts, targets.WithTcpTargetEndpointTls(endpointTls))
+	}
+
+	switch c.flagTlsServerName {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetTlsServerName())
+	default:
+		opts = append(opts, targets.WithTcpTargetTlsServerName(c.flagTlsServerName))
+	}
+
+	switch c.flagTlsCaBundle {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetTlsCaBundle())
+	default:
+		bundle, err := config.ParseAddress(c.flagTlsCaBundle)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading tls ca bundle: %s", err))
+			return 1
+		}
+		opts = append(opts, targets.WithTcpTargetTlsCaBundle(bundle))
+	}
+
+	switch c.flagTlsClientCertificate {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetTlsClientCertificate())
+	default:
+		cert, err := config.ParseAddress(c.flagTlsClientCertificate)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading tls client certificate: %s", err))
+			return 1
+		}
+		opts = append(opts, targets.WithTcpTargetTlsClientCertificate(cert))
+	}
+
+	switch c.flagTlsClientPrivateKey {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTcpTargetTlsClientPrivateKey())
+	default:
+		key, err := config.ParseAddress(c.flagTlsClientPrivateKey)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading tls client private key: %s", err))
+			return 1
+		}
+		opts = append(opts, targets.WithTcpTargetTlsClientPrivateKey(key))
+	}
+
+	switch c.flagTerminateAtScheduleEnd {
+	case "":
+	case "null":
+		opts = append(opts, targets.DefaultTerminateSessionsAtScheduleEnd())
+	default:
+		terminate, err := strconv.ParseBool(c.flagTerminateAtScheduleEnd)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error parsing %q: %s", c.flagTerminateAtScheduleEnd, err))
+			return 1
+		}
+		opts = append(opts, targets.WithTerminateSessionsAtScheduleEnd(terminate))
+	}
+
 	targetClient := targets.NewClient(client)
 
 	// Perform check-and-set when needed
@@ -233,13 +540,11 @@ func (c *TcpCommand) Run(args []string) int {
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateTargetTableOutput(target))
-	case "json":
-		b, err := base.JsonFormatter{}.Format(target)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	case "json", "yaml":
+		if err := c.PrintStructured(target); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 	}
 
 	return 0
@@ -45,13 +45,11 @@ func (c *Command) AutocompleteFlags() complete.Flags {
 func (c *Command) Run(args []string) int {
 	verInfo := ver.Get()
 
-	if base.Format(c.UI) == "json" {
-		b, err := base.JsonFormatter{}.Format(verInfo)
-		if err != nil {
-			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+	if base.Format(c.UI) != "table" {
+		if err := c.PrintStructured(verInfo); err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
 			return 1
 		}
-		c.UI.Output(string(b))
 		return 0
 	}
 
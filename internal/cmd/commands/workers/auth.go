@@ -0,0 +1,155 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*AuthCommand)(nil)
+var _ cli.CommandAutocomplete = (*AuthCommand)(nil)
+
+// AuthCommand creates activation tokens for workers and revokes the
+// certificates issued to them.
+type AuthCommand struct {
+	*base.Command
+	base.ControllerConfig
+
+	Func string
+
+	flagName string
+	flagTtl  time.Duration
+}
+
+func (c *AuthCommand) Synopsis() string {
+	switch c.Func {
+	case "create-activation-token":
+		return "Create an activation token for a worker"
+	case "revoke-certificates":
+		return "Revoke the certificates issued to a worker"
+	}
+	return ""
+}
+
+func (c *AuthCommand) Help() string {
+	var info []string
+	switch c.Func {
+	case "create-activation-token":
+		info = []string{
+			"Usage: boundary workers create-activation-token [options]",
+			"",
+			"  Create a single use activation token for a worker. The worker presents the token to a controller to receive the certificate it uses to authenticate. The token includes the fingerprints of the controllers' certificate authorities, which the worker uses to verify the controller it registers with. Example:",
+			"",
+			`    $ boundary workers create-activation-token -config=/etc/boundary/controller.hcl -name=worker1`,
+			"",
+			`  The token should be set as the "activation_token" value in the worker's configuration along with an "auth_storage_path".`,
+			"",
+		}
+	case "revoke-certificates":
+		info = []string{
+			"Usage: boundary workers revoke-certificates [options]",
+			"",
+			"  Revoke every certificate issued to a worker. The worker will be refused by controllers until it registers again with a new activation token. Example:",
+			"",
+			`    $ boundary workers revoke-certificates -config=/etc/boundary/controller.hcl -name=worker1`,
+			"",
+		}
+	}
+	return base.WrapForHelpText(info) + c.Flags().Help()
+}
+
+func (c *AuthCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	c.AddConfigFlags(f)
+
+	f.StringVar(&base.StringVar{
+		Name:   "name",
+		Target: &c.flagName,
+		Usage:  "The name of the worker.",
+	})
+
+	if c.Func == "create-activation-token" {
+		f.DurationVar(&base.DurationVar{
+			Name:    "ttl",
+			Target:  &c.flagTtl,
+			Default: 24 * time.Hour,
+			Usage:   "How long the activation token can be used.",
+		})
+	}
+
+	return set
+}
+
+func (c *AuthCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *AuthCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *AuthCommand) Run(args []string) int {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if c.flagName == "" {
+		c.UI.Error("Must specify a worker name using -name")
+		return 1
+	}
+	if result := c.LoadConfig(c.Command); result > 0 {
+		return result
+	}
+	defer c.FinalizeConfigWrapper(c.Command)
+
+	repo, err := serversRepo(c.Command, &c.ControllerConfig)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	switch c.Func {
+	case "create-activation-token":
+		token, err := repo.CreateActivationToken(c.Context, c.flagName, c.flagTtl)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error creating activation token: %w", err).Error())
+			return 1
+		}
+		switch base.Format(c.UI) {
+		case "json", "yaml":
+			c.UI.Output(fmt.Sprintf(`{"name":%q,"activation_token":%q}`, c.flagName, token))
+		default:
+			c.UI.Output(base.WrapForHelpText([]string{
+				"",
+				"Worker activation token information:",
+				base.WrapMap(2, 0, map[string]interface{}{
+					"Name":             c.flagName,
+					"Activation Token": token,
+					"Expiration":       time.Now().Add(c.flagTtl).Local().Format(time.RFC1123),
+				}),
+			}))
+		}
+
+	case "revoke-certificates":
+		revoked, err := repo.RevokeWorkerCertificates(c.Context, c.flagName)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error revoking worker certificates: %w", err).Error())
+			return 1
+		}
+		switch base.Format(c.UI) {
+		case "json", "yaml":
+			c.UI.Output(fmt.Sprintf(`{"name":%q,"revoked":%d}`, c.flagName, revoked))
+		default:
+			c.UI.Output(fmt.Sprintf("Revoked %d certificates for worker %q.", revoked, c.flagName))
+		}
+	}
+
+	return 0
+}
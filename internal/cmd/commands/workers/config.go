@@ -0,0 +1,16 @@
+package workers
+
+import (
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/servers"
+)
+
+// serversRepo connects to the database and KMSes defined in the loaded
+// configuration and returns a servers repository.
+func serversRepo(cmd *base.Command, conf *base.ControllerConfig) (*servers.Repository, error) {
+	rw, kmsCache, err := conf.ConnectToDatabase(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return servers.NewRepository(rw, rw, kmsCache)
+}
@@ -0,0 +1,202 @@
+package workers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*DrainCommand)(nil)
+var _ cli.CommandAutocomplete = (*DrainCommand)(nil)
+
+// drainPollInterval is how often the worker is looked up while waiting for it
+// to finish draining. Workers report their status every few seconds.
+const drainPollInterval = 2 * time.Second
+
+// DrainCommand starts and stops draining workers and shows the progress of a
+// drain.
+type DrainCommand struct {
+	*base.Command
+	base.ControllerConfig
+
+	Func string
+
+	flagName string
+	flagWait bool
+}
+
+func (c *DrainCommand) Synopsis() string {
+	switch c.Func {
+	case "drain":
+		return "Stop assigning new sessions to a worker"
+	case "undrain":
+		return "Resume assigning new sessions to a draining worker"
+	case "drain-status":
+		return "Show the drain status of a worker"
+	}
+	return ""
+}
+
+func (c *DrainCommand) Help() string {
+	var info []string
+	switch c.Func {
+	case "drain":
+		info = []string{
+			"Usage: boundary workers drain [options]",
+			"",
+			"  Drain a worker before maintenance. No new sessions are assigned to a draining worker; its existing sessions are unaffected. The worker remains draining, including across restarts, until it is undrained. Example:",
+			"",
+			`    $ boundary workers drain -config=/etc/boundary/controller.hcl -name=worker1 -wait`,
+			"",
+		}
+	case "undrain":
+		info = []string{
+			"Usage: boundary workers undrain [options]",
+			"",
+			"  Undrain a worker so that it is assigned new sessions again. Example:",
+			"",
+			`    $ boundary workers undrain -config=/etc/boundary/controller.hcl -name=worker1`,
+			"",
+		}
+	case "drain-status":
+		info = []string{
+			"Usage: boundary workers drain-status [options]",
+			"",
+			"  Show whether a worker is draining along with the number of sessions and connections it last reported. Example:",
+			"",
+			`    $ boundary workers drain-status -config=/etc/boundary/controller.hcl -name=worker1`,
+			"",
+		}
+	}
+	return base.WrapForHelpText(info) + c.Flags().Help()
+}
+
+func (c *DrainCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	c.AddConfigFlags(f)
+
+	f.StringVar(&base.StringVar{
+		Name:   "name",
+		Target: &c.flagName,
+		Usage:  "The name of the worker.",
+	})
+
+	if c.Func == "drain" {
+		f.BoolVar(&base.BoolVar{
+			Name:   "wait",
+			Target: &c.flagWait,
+			Usage:  "Wait until the worker reports no active sessions before returning.",
+		})
+	}
+
+	return set
+}
+
+func (c *DrainCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *DrainCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *DrainCommand) Run(args []string) int {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if c.flagName == "" {
+		c.UI.Error("Must specify a worker name using -name")
+		return 1
+	}
+	if result := c.LoadConfig(c.Command); result > 0 {
+		return result
+	}
+	defer c.FinalizeConfigWrapper(c.Command)
+
+	repo, err := serversRepo(c.Command, &c.ControllerConfig)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	switch c.Func {
+	case "drain", "undrain":
+		err := repo.SetWorkerDraining(c.Context, c.flagName, c.Func == "drain")
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			c.UI.Error(fmt.Sprintf("Worker %q has never reported its status to a controller.", c.flagName))
+			return 1
+		case err != nil:
+			c.UI.Error(fmt.Errorf("Error trying to %s worker: %w", c.Func, err).Error())
+			return 1
+		}
+	}
+
+	worker, err := repo.LookupWorker(c.Context, c.flagName)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error looking up worker: %w", err).Error())
+		return 1
+	}
+
+	if c.flagWait {
+		for worker.GetActiveSessionCount() > 0 {
+			if base.Format(c.UI) == "table" {
+				c.UI.Info(fmt.Sprintf("Waiting for %d active sessions to finish...", worker.GetActiveSessionCount()))
+			}
+			select {
+			case <-c.Context.Done():
+				c.UI.Error("Interrupted while waiting for worker to drain.")
+				return 1
+			case <-time.After(drainPollInterval):
+			}
+			if worker, err = repo.LookupWorker(c.Context, c.flagName); err != nil {
+				c.UI.Error(fmt.Errorf("Error looking up worker: %w", err).Error())
+				return 1
+			}
+		}
+	}
+
+	switch base.Format(c.UI) {
+	case "json", "yaml":
+		err := c.PrintStructured(struct {
+			Name                  string    `json:"name"`
+			Draining              bool      `json:"draining"`
+			ActiveSessionCount    uint32    `json:"active_session_count"`
+			ActiveConnectionCount uint32    `json:"active_connection_count"`
+			UpdateTime            time.Time `json:"update_time"`
+		}{
+			Name:                  worker.GetName(),
+			Draining:              worker.GetDraining(),
+			ActiveSessionCount:    worker.GetActiveSessionCount(),
+			ActiveConnectionCount: worker.GetActiveConnectionCount(),
+			UpdateTime:            worker.GetUpdateTime().GetTimestamp().AsTime(),
+		})
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting output: %w", err).Error())
+			return 1
+		}
+	default:
+		c.UI.Output(base.WrapForHelpText([]string{
+			"",
+			"Worker drain information:",
+			base.WrapMap(2, 0, map[string]interface{}{
+				"Name":               worker.GetName(),
+				"Draining":           worker.GetDraining(),
+				"Active Sessions":    worker.GetActiveSessionCount(),
+				"Active Connections": worker.GetActiveConnectionCount(),
+				"Last Status Time":   worker.GetUpdateTime().GetTimestamp().AsTime().Local().Format(time.RFC1123),
+			}),
+		}))
+	}
+	return 0
+}
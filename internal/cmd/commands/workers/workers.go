@@ -0,0 +1,52 @@
+package workers
+
+import (
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+}
+
+func (c *Command) Synopsis() string {
+	return "Manage the authentication and draining of Boundary workers"
+}
+
+func (c *Command) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary workers [sub command] [options] [args]",
+		"",
+		"  This command allows management of the certificates workers use to authenticate to controllers and the draining of workers for maintenance. These commands connect directly to the database using the controller's configuration file. Example:",
+		"",
+		"    Create an activation token for a worker:",
+		"",
+		`      $ boundary workers create-activation-token -config=/etc/boundary/controller.hcl -name=worker1`,
+		"",
+		"    Drain a worker and wait for its sessions to finish:",
+		"",
+		`      $ boundary workers drain -config=/etc/boundary/controller.hcl -name=worker1 -wait`,
+		"",
+		"  Please see the workers subcommand help for detailed usage information.",
+	})
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	return nil
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -57,6 +57,24 @@ func PopulateCommonFlags(c *base.Command, f *base.FlagSet, resourceType string,
 				Target: &c.FlagHostCatalogId,
 				Usage:  "The host-catalog resource to use for the operation",
 			})
+		case "filter":
+			f.StringVar(&base.StringVar{
+				Name:   "filter",
+				Target: &c.FlagFilter,
+				Usage:  fmt.Sprintf(`A boolean expression, such as '"/item/name" == "web"', which each listed %s must match`, resourceType),
+			})
+		case "sort-by":
+			f.StringVar(&base.StringVar{
+				Name:   "sort-by",
+				Target: &c.FlagSortBy,
+				Usage:  `The field to sort the listed items by, such as "created_time"`,
+			})
+		case "sort-dir":
+			f.StringVar(&base.StringVar{
+				Name:   "sort-dir",
+				Target: &c.FlagSortDir,
+				Usage:  `The direction to sort the listed items in, "asc" (the default) or "desc"`,
+			})
 		}
 	}
 }
@@ -12,8 +12,13 @@ import (
 	"os"
 	"strings"
 
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/flowexport"
+	"github.com/hashicorp/boundary/internal/quota"
+	"github.com/hashicorp/boundary/internal/ratelimit"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/hashicorp/shared-secure-libs/configutil"
 )
 
@@ -83,8 +88,9 @@ worker {
 type Config struct {
 	*configutil.SharedConfig `hcl:"-"`
 
-	Worker     *Worker     `hcl:"worker"`
-	Controller *Controller `hcl:"controller"`
+	Worker     *Worker       `hcl:"worker"`
+	Controller *Controller   `hcl:"controller"`
+	Events     *event.Config `hcl:"-"`
 
 	// Dev-related options
 	DevController        bool   `hcl:"-"`
@@ -98,6 +104,35 @@ type Controller struct {
 	Name        string    `hcl:"name"`
 	Description string    `hcl:"description"`
 	Database    *Database `hcl:"database"`
+
+	// ApiRateLimit limits the rate of API requests per auth token and per
+	// client IP address. No limits are applied if it is not set.
+	ApiRateLimit *ratelimit.Config `hcl:"api_rate_limit"`
+
+	// Quota counts the API requests and active sessions of each organization
+	// and limits them to the configured quotas. Nothing is counted if it is
+	// not set.
+	Quota *quota.Config `hcl:"quota"`
+
+	// EnforceUniqueNames rejects target and role names which only differ by
+	// case from the name of another target or role in the same scope. Names
+	// which match exactly are always rejected.
+	EnforceUniqueNames bool `hcl:"enforce_unique_names"`
+
+	// SessionReplication replicates the summaries of terminated sessions to
+	// a database in another region for reporting.
+	SessionReplication *SessionReplication `hcl:"session_replication"`
+
+	// ConnectionExport sends records of closed session connections to a
+	// NetFlow/IPFIX or JSON collector. Nothing is exported if it is not set.
+	ConnectionExport *flowexport.Config `hcl:"connection_export"`
+}
+
+type SessionReplication struct {
+	// DatabaseUrl is the url of the database summaries are replicated to.
+	// Its schema must have been initialized with boundary database init or
+	// migrate.
+	DatabaseUrl string `hcl:"database_url"`
 }
 
 type Worker struct {
@@ -105,11 +140,74 @@ type Worker struct {
 	Description string   `hcl:"description"`
 	Controllers []string `hcl:"controllers"`
 	PublicAddr  string   `hcl:"public_addr"`
+
+	// Tags are key/value pairs reported by the worker to the controllers.
+	// A key may have multiple values.
+	Tags map[string][]string `hcl:"tags"`
+
+	// MaxSessions is the maximum number of sessions the worker will be
+	// assigned. Zero means there is no limit.
+	MaxSessions uint32 `hcl:"max_sessions"`
+
+	// AuthStoragePath is the directory where the worker stores the
+	// certificate it uses to authenticate to controllers. When set, the
+	// worker authenticates with a certificate issued by a controller instead
+	// of with the worker auth KMS.
+	AuthStoragePath string `hcl:"auth_storage_path"`
+
+	// ActivationToken is the single use token the worker presents to a
+	// controller to register and receive a certificate. It is only used when
+	// no certificate is found in AuthStoragePath.
+	ActivationToken string `hcl:"activation_token"`
+
+	// BrowserOrigins are the origin patterns, such as "app.example.com", of
+	// the pages allowed to connect to the worker's browser ingress. The
+	// ingress is served on proxy listeners which have a TLS certificate
+	// configured.
+	BrowserOrigins []string `hcl:"browser_origins"`
+
+	// ReportCompression is the compressor, "gzip" or "snappy", used for the
+	// status and connection reports the worker sends to controllers. Reports
+	// are not compressed when it is empty.
+	ReportCompression string `hcl:"report_compression"`
+
+	// BatchConnectionReports holds the reports of connections closing until
+	// the worker next reports its status, and sends them to the controller
+	// together instead of one request per connection.
+	BatchConnectionReports bool `hcl:"batch_connection_reports"`
+
+	// Targets are pass-through targets the worker registers with the
+	// controllers when it starts and deregisters when it shuts down, so lab
+	// setups don't need targets, host catalogs and hosts created for them.
+	// Sessions for them are only proxied by the worker.
+	Targets []*WorkerTarget `hcl:"target"`
+}
+
+// WorkerTarget is a target defined by a labeled target block in a worker's
+// configuration.
+type WorkerTarget struct {
+	// Name is the target's name, taken from the block's label.
+	Name        string `hcl:",key"`
+	Description string `hcl:"description"`
+
+	// Address and Port are where the worker connects for the target's
+	// sessions.
+	Address string `hcl:"address"`
+	Port    int    `hcl:"port"`
+
+	// ScopeId is the project the target is created in. Its roles determine
+	// who can authorize sessions for the target.
+	ScopeId string `hcl:"scope_id"`
 }
 
 type Database struct {
 	Url          string `hcl:"url"`
 	MigrationUrl string `hcl:"migration_url"`
+
+	// SlowQueryThreshold is the duration, such as "500ms", at or above which
+	// queries are logged as observation events, with the parameters which
+	// may hold secrets scrubbed. Queries aren't logged if it is empty.
+	SlowQueryThreshold string `hcl:"slow_query_threshold"`
 }
 
 // DevWorker is a Config that is used for dev mode of Boundary
@@ -216,9 +314,47 @@ func Parse(d string) (*Config, error) {
 	}
 	result.SharedConfig = sharedConfig
 
+	for i, l := range sharedConfig.Listeners {
+		if _, err := ParseListenerTLS(l); err != nil {
+			return nil, fmt.Errorf("error parsing listener %d: %w", i, err)
+		}
+	}
+
+	list, ok := obj.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, errors.New("error parsing: file doesn't contain a root object")
+	}
+	if o := list.Filter("events"); len(o.Items) > 0 {
+		if result.Events, err = parseEvents(o); err != nil {
+			return nil, fmt.Errorf("error parsing 'events': %w", err)
+		}
+	}
+
 	return result, nil
 }
 
+// parseEvents decodes the events block. Each sink is decoded on its own since
+// the HCL decoder can't decode repeated blocks containing lists when they are
+// nested in another block.
+func parseEvents(list *ast.ObjectList) (*event.Config, error) {
+	if len(list.Items) > 1 {
+		return nil, errors.New("only one 'events' block is permitted")
+	}
+	eventsList, ok := list.Items[0].Val.(*ast.ObjectType)
+	if !ok {
+		return nil, errors.New("could not parse 'events' as an object")
+	}
+	conf := new(event.Config)
+	for i, item := range eventsList.List.Filter("sink").Items {
+		sink := new(event.SinkConfig)
+		if err := hcl.DecodeObject(sink, item.Val); err != nil {
+			return nil, fmt.Errorf("error decoding sink %d: %w", i, err)
+		}
+		conf.Sinks = append(conf.Sinks, sink)
+	}
+	return conf, nil
+}
+
 // Sanitized returns a copy of the config with all values that are considered
 // sensitive stripped. It also strips all `*Raw` values that are mainly
 // used for parsing.
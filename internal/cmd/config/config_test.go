@@ -4,8 +4,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/quota"
+	"github.com/hashicorp/boundary/internal/ratelimit"
 	"github.com/hashicorp/shared-secure-libs/configutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDevController(t *testing.T) {
@@ -115,3 +119,255 @@ func TestDevWorker(t *testing.T) {
 
 	assert.Equal(t, exp, actual)
 }
+
+func TestWorkerTags(t *testing.T) {
+	actual, err := Parse(`
+worker {
+	name = "tagged-worker"
+	tags {
+		type = ["web", "prod"]
+		region = ["us-east-1"]
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string][]string{
+		"type":   {"web", "prod"},
+		"region": {"us-east-1"},
+	}, actual.Worker.Tags)
+}
+
+func TestWorkerReports(t *testing.T) {
+	actual, err := Parse(`
+worker {
+	name = "reporting-worker"
+	report_compression = "snappy"
+	batch_connection_reports = true
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "snappy", actual.Worker.ReportCompression)
+	assert.True(t, actual.Worker.BatchConnectionReports)
+}
+
+func TestWorkerTargets(t *testing.T) {
+	actual, err := Parse(`
+worker {
+	name = "lab-worker"
+	target "postgres" {
+		address = "127.0.0.1"
+		port = 5432
+		scope_id = "p_1234567890"
+	}
+	target "ssh" {
+		description = "Lab ssh"
+		address = "lab.example.com"
+		port = 22
+		scope_id = "p_0987654321"
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*WorkerTarget{
+		{Name: "postgres", Address: "127.0.0.1", Port: 5432, ScopeId: "p_1234567890"},
+		{Name: "ssh", Description: "Lab ssh", Address: "lab.example.com", Port: 22, ScopeId: "p_0987654321"},
+	}, actual.Worker.Targets)
+}
+
+func TestDatabaseSlowQueryThreshold(t *testing.T) {
+	actual, err := Parse(`
+controller {
+	name = "c1"
+	database {
+		url = "env://BOUNDARY_PG_URL"
+		slow_query_threshold = "500ms"
+	}
+}
+`)
+	require.NoError(t, err)
+	require.NotNil(t, actual.Controller.Database)
+	assert.Equal(t, "500ms", actual.Controller.Database.SlowQueryThreshold)
+}
+
+func TestEvents(t *testing.T) {
+	actual, err := Parse(`
+events {
+	sink {
+		type = "stderr"
+		event_types = ["system"]
+	}
+	sink {
+		type = "file"
+		event_types = ["audit"]
+		file_path = "/var/log/boundary/audit.log"
+		rotate_bytes = 1048576
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.NotNil(t, actual.Events)
+	require.Len(t, actual.Events.Sinks, 2)
+	assert.Equal(t, event.StderrSink, actual.Events.Sinks[0].Type)
+	assert.Equal(t, []string{"system"}, actual.Events.Sinks[0].EventTypes)
+	assert.Equal(t, event.FileSink, actual.Events.Sinks[1].Type)
+	assert.Equal(t, "/var/log/boundary/audit.log", actual.Events.Sinks[1].FilePath)
+	assert.Equal(t, int64(1048576), actual.Events.Sinks[1].RotateBytes)
+	assert.NoError(t, actual.Events.Validate())
+}
+
+func TestApiRateLimit(t *testing.T) {
+	actual, err := Parse(`
+controller {
+	name = "c1"
+	api_rate_limit {
+		per_token_rate = 10
+		per_ip_rate = 50
+		per_ip_burst = 100
+		store = "database"
+	}
+}
+`)
+	require.NoError(t, err)
+	require.NotNil(t, actual.Controller.ApiRateLimit)
+	assert.Equal(t, &ratelimit.Config{
+		PerTokenRate: 10,
+		PerIpRate:    50,
+		PerIpBurst:   100,
+		Store:        ratelimit.DatabaseStore,
+	}, actual.Controller.ApiRateLimit)
+	assert.NoError(t, actual.Controller.ApiRateLimit.Validate())
+}
+
+func TestQuota(t *testing.T) {
+	actual, err := Parse(`
+controller {
+	name = "c1"
+	quota {
+		period = "daily"
+		max_requests = 1000
+		max_active_sessions = 10
+		scope "o_1234567890" {
+			max_requests = 5000
+		}
+	}
+}
+`)
+	require.NoError(t, err)
+	require.NotNil(t, actual.Controller.Quota)
+	assert.Equal(t, &quota.Config{
+		Period:            quota.Daily,
+		MaxRequests:       1000,
+		MaxActiveSessions: 10,
+		Scopes: []*quota.ScopeConfig{
+			{ScopeId: "o_1234567890", MaxRequests: 5000},
+		},
+	}, actual.Controller.Quota)
+	assert.NoError(t, actual.Controller.Quota.Validate())
+}
+
+func TestListenerTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    *ListenerTLS
+		wantErr string
+	}{
+		{
+			name: "sni and acme",
+			config: `
+listener "tcp" {
+	purpose = "api"
+	tls_cert_file = "default.pem"
+	tls_key_file = "default.key"
+	tls_sni_certificate {
+		cert_file = "a.pem"
+		key_file = "a.key"
+	}
+	tls_sni_certificate {
+		cert_file = "b.pem"
+		key_file = "b.key"
+	}
+	tls_acme_domains = ["boundary.example.com"]
+	tls_acme_email = "ops@example.com"
+	tls_acme_cache_path = "/var/lib/boundary/acme"
+}
+`,
+			want: &ListenerTLS{
+				SNICertificates: []*SNICertificate{
+					{CertFile: "a.pem", KeyFile: "a.key"},
+					{CertFile: "b.pem", KeyFile: "b.key"},
+				},
+				ACMEDomains:   []string{"boundary.example.com"},
+				ACMEEmail:     "ops@example.com",
+				ACMECachePath: "/var/lib/boundary/acme",
+			},
+		},
+		{
+			name: "not an api listener",
+			config: `
+listener "tcp" {
+	purpose = "cluster"
+	tls_acme_domains = ["boundary.example.com"]
+	tls_acme_cache_path = "/var/lib/boundary/acme"
+}
+`,
+			wantErr: "only supported on api listeners",
+		},
+		{
+			name: "tls disabled",
+			config: `
+listener "tcp" {
+	purpose = "api"
+	tls_disable = true
+	tls_acme_domains = ["boundary.example.com"]
+	tls_acme_cache_path = "/var/lib/boundary/acme"
+}
+`,
+			wantErr: "tls is disabled",
+		},
+		{
+			name: "acme without cache path",
+			config: `
+listener "tcp" {
+	purpose = "api"
+	tls_acme_domains = ["boundary.example.com"]
+}
+`,
+			wantErr: "tls_acme_cache_path must be set",
+		},
+		{
+			name: "sni certificate without key",
+			config: `
+listener "tcp" {
+	purpose = "api"
+	tls_sni_certificate {
+		cert_file = "a.pem"
+	}
+}
+`,
+			wantErr: "must set cert_file and key_file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Parse(tt.config)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got, err := ParseListenerTLS(actual.Listeners[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/shared-secure-libs/configutil"
+)
+
+// ListenerTLS is the certificate management configured for an api listener
+// in addition to its tls_cert_file and tls_key_file.
+type ListenerTLS struct {
+	// SNICertificates are additional certificates, each used for the server
+	// names it is valid for.
+	SNICertificates []*SNICertificate `json:"tls_sni_certificate"`
+
+	// ACMEDomains are the domains to obtain certificates for from an ACME
+	// server, such as Let's Encrypt. The certificates are renewed before they
+	// expire.
+	ACMEDomains []string `json:"tls_acme_domains"`
+
+	// ACMEEmail is the contact address given to the ACME server.
+	ACMEEmail string `json:"tls_acme_email"`
+
+	// ACMECachePath is the directory the ACME account key and the issued
+	// certificates are stored in.
+	ACMECachePath string `json:"tls_acme_cache_path"`
+
+	// ACMEDirectoryURL is the directory of the ACME server. It defaults to
+	// Let's Encrypt.
+	ACMEDirectoryURL string `json:"tls_acme_directory_url"`
+}
+
+// SNICertificate is a certificate and key loaded from files.
+type SNICertificate struct {
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+}
+
+// IsSet returns true if any certificate management is configured.
+func (t *ListenerTLS) IsSet() bool {
+	return len(t.SNICertificates) > 0 || len(t.ACMEDomains) > 0
+}
+
+// ParseListenerTLS returns the certificate management configured for l.
+// configutil doesn't know about these settings so they are read from the
+// listener's raw configuration.
+func ParseListenerTLS(l *configutil.Listener) (*ListenerTLS, error) {
+	ret := new(ListenerTLS)
+	if l.RawConfig == nil {
+		return ret, nil
+	}
+	raw, err := json.Marshal(l.RawConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, ret); err != nil {
+		return nil, fmt.Errorf("error decoding tls settings: %w", err)
+	}
+	if !ret.IsSet() {
+		return ret, nil
+	}
+
+	if len(l.Purpose) != 1 || l.Purpose[0] != "api" {
+		return nil, errors.New("tls_sni_certificate and tls_acme_domains are only supported on api listeners")
+	}
+	if l.TLSDisable {
+		return nil, errors.New("tls_sni_certificate and tls_acme_domains can't be used when tls is disabled")
+	}
+	for i, c := range ret.SNICertificates {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("tls_sni_certificate %d must set cert_file and key_file", i)
+		}
+	}
+	if len(ret.ACMEDomains) > 0 && ret.ACMECachePath == "" {
+		return nil, errors.New("tls_acme_cache_path must be set when using tls_acme_domains")
+	}
+	return ret, nil
+}
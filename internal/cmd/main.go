@@ -33,7 +33,7 @@ func setupEnv(args []string) (retArgs []string, format string, outputCurlString
 		}
 	}
 
-	var nextArgFormat bool
+	var nextArgFormat, field bool
 
 	for _, arg := range args {
 		if nextArgFormat {
@@ -66,6 +66,10 @@ func setupEnv(args []string) (retArgs []string, format string, outputCurlString
 		if arg == "-format" {
 			nextArgFormat = true
 		}
+
+		if arg == "-field" || strings.HasPrefix(arg, "-field=") {
+			field = true
+		}
 	}
 
 	envBoundaryCLIFormat := os.Getenv(base.EnvBoundaryCLIFormat)
@@ -78,6 +82,11 @@ func setupEnv(args []string) (retArgs []string, format string, outputCurlString
 	if format == "" {
 		format = "table"
 	}
+	// Commands print a single field through the same path as structured
+	// output, so use it instead of the table
+	if field && format == "table" {
+		format = "json"
+	}
 
 	return args, format, outputCurlString
 }
@@ -160,7 +169,7 @@ func RunCustom(args []string, runOpts *RunOptions) int {
 	}
 
 	switch format {
-	case "table", "json":
+	case "table", "json", "yaml":
 	default:
 		ui.Error(fmt.Sprintf("Invalid output format: %s", format))
 		return 1
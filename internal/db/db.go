@@ -1,11 +1,16 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/hashicorp/boundary/internal/db/migrations"
 	"github.com/hashicorp/boundary/internal/docker"
 	"github.com/hashicorp/go-hclog"
@@ -15,7 +20,8 @@ import (
 )
 
 var (
-	StartDbInDocker = docker.StartDbInDocker
+	StartDbInDocker     = docker.StartDbInDocker
+	StartTestDbInDocker = docker.StartTestDbInDocker
 )
 
 type DbType int
@@ -50,11 +56,17 @@ func Migrate(connectionUrl string, migrationsDirectory string) error {
 	if _, err := os.Stat(migrationsDirectory); os.IsNotExist(err) {
 		return errors.New("error migrations directory does not exist")
 	}
+	driver, err := newMigrationDriver(connectionUrl)
+	if err != nil {
+		return fmt.Errorf("unable to create migration driver: %w", err)
+	}
 	// run migrations
-	m, err := migrate.New(fmt.Sprintf("file://%s", migrationsDirectory), connectionUrl)
+	m, err := migrate.NewWithDatabaseInstance(fmt.Sprintf("file://%s", migrationsDirectory), "postgres", driver)
 	if err != nil {
+		_ = driver.Close()
 		return fmt.Errorf("unable to create migrations: %w", err)
 	}
+	defer m.Close()
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
 		return fmt.Errorf("unable to run migrations: %w", err)
 	}
@@ -76,8 +88,19 @@ func InitStore(dialect string, cleanup func() error, url string) (bool, error) {
 		}
 		return false, mErr.ErrorOrNil()
 	}
-	m, err := migrate.NewWithSourceInstance("httpfs", source, url)
+	driver, err := newMigrationDriver(url)
+	if err != nil {
+		mErr = multierror.Append(mErr, fmt.Errorf("error creating migration database driver: %w", err))
+		if cleanup != nil {
+			if err := cleanup(); err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("error cleaning up from creating migration database driver: %w", err))
+			}
+		}
+		return false, mErr.ErrorOrNil()
+	}
+	m, err := migrate.NewWithInstance("httpfs", source, "postgres", driver)
 	if err != nil {
+		_ = driver.Close()
 		mErr = multierror.Append(mErr, fmt.Errorf("error creating migrations: %w", err))
 		if cleanup != nil {
 			if err := cleanup(); err != nil {
@@ -87,6 +110,7 @@ func InitStore(dialect string, cleanup func() error, url string) (bool, error) {
 		return false, mErr.ErrorOrNil()
 
 	}
+	defer m.Close()
 	if err := m.Up(); err != nil {
 		if err == migrate.ErrNoChange {
 			return false, nil
@@ -102,6 +126,79 @@ func InitStore(dialect string, cleanup func() error, url string) (bool, error) {
 	return true, mErr.ErrorOrNil()
 }
 
+// migrationLockName is the name of the advisory lock held while migrations are
+// applied, so controllers starting at the same time don't apply them at once.
+const migrationLockName = "migrations"
+
+// lockedMigrationDriver is a migration database driver which holds the
+// migrationLockName lock while migrations are applied, in place of the
+// driver's own advisory lock.
+type lockedMigrationDriver struct {
+	database.Driver
+	rw     *Db
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu   sync.Mutex
+	lock *Lock
+}
+
+// newMigrationDriver opens a connection to the Postgres database at url and
+// returns a migration driver using it. Closing the driver closes the
+// connection.
+func newMigrationDriver(url string) (database.Driver, error) {
+	conn, err := Open(Postgres, url)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := postgres.WithInstance(conn.DB(), &postgres.Config{})
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lockedMigrationDriver{Driver: driver, rw: New(conn), ctx: ctx, cancel: cancel}, nil
+}
+
+// Lock waits for the migration lock until it's acquired or the driver is
+// closed.
+func (d *lockedMigrationDriver) Lock() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.lock != nil {
+		return database.ErrLocked
+	}
+	l, err := d.rw.Lock(d.ctx, migrationLockName)
+	if err != nil {
+		return err
+	}
+	d.lock = l
+	return nil
+}
+
+// Unlock releases the migration lock if it's held.
+func (d *lockedMigrationDriver) Unlock() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.lock == nil {
+		return nil
+	}
+	l := d.lock
+	d.lock = nil
+	return l.Unlock(context.Background())
+}
+
+// Close abandons any wait for the migration lock, releases it if it's held
+// and closes the driver's connection.
+func (d *lockedMigrationDriver) Close() error {
+	d.cancel()
+	if err := d.Unlock(); err != nil {
+		_ = d.Driver.Close()
+		return err
+	}
+	return d.Driver.Close()
+}
+
 func GetGormLogFormatter(log hclog.Logger) func(values ...interface{}) (messages []interface{}) {
 	return func(values ...interface{}) (messages []interface{}) {
 		if len(values) > 2 && values[0].(string) == "log" {
@@ -116,10 +213,17 @@ func GetGormLogFormatter(log hclog.Logger) func(values ...interface{}) (messages
 }
 
 type gormLogger struct {
-	logger hclog.Logger
+	logger             hclog.Logger
+	slowQueryThreshold time.Duration
 }
 
 func (g gormLogger) Print(values ...interface{}) {
+	if len(values) > 5 && values[0] == "sql" {
+		if g.slowQueryThreshold > 0 {
+			g.logSlowQuery(values...)
+		}
+		return
+	}
 	formatted := gorm.LogFormatter(values...)
 	if formatted == nil {
 		return
@@ -129,6 +233,10 @@ func (g gormLogger) Print(values ...interface{}) {
 	panic("unhandled error case")
 }
 
-func GetGormLogger(log hclog.Logger) gormLogger {
-	return gormLogger{logger: log}
+// GetGormLogger returns a gorm logger passing errors from the database
+// adapter to log. Supported options: WithSlowQueryThreshold, which also
+// requires the gorm DB to have detailed logging enabled with LogMode(true).
+func GetGormLogger(log hclog.Logger, opt ...Option) gormLogger {
+	opts := GetOpts(opt...)
+	return gormLogger{logger: log, slowQueryThreshold: opts.withSlowQueryThreshold}
 }
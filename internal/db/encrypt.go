@@ -0,0 +1,374 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+// WrapperFn returns the wrapper used to encrypt and decrypt the fields of
+// resources in the scope. An empty keyId asks for the wrapper with the
+// scope's current key; otherwise the wrapper must be able to decrypt with
+// the key with keyId.
+type WrapperFn func(ctx context.Context, scopeId, keyId string) (wrapping.Wrapper, error)
+
+// Resources opt in to having their sensitive fields encrypted by the Writer
+// and decrypted by the Reader with encrypt struct tags:
+//
+//	encrypt:"scope"     the string field holding the scope id whose database
+//	                    key encrypts the fields
+//	encrypt:"key_id"    the string field the id of the key is stored in
+//	encrypt:"pt,<name>" a plaintext field, which must not be stored and so
+//	                    must be tagged gorm:"-"
+//	encrypt:"ct,<name>" the []byte field the plaintext field with the same
+//	                    name is stored in once encrypted
+//
+// The tags can be on the resource or on a struct embedded in it, such as
+// the generated store struct. Create and Update refuse resources with
+// plaintext fields set unless the WithWrapperFn option is given, so a
+// resource can't be written without encrypting its secrets.
+const encryptTag = "encrypt"
+
+// encryptedField is a plaintext field and the field its ciphertext is
+// stored in.
+type encryptedField struct {
+	name string
+	pt   []int
+	ct   []int
+}
+
+// encryptInfo describes the encrypt tags of a resource type.
+type encryptInfo struct {
+	scope  []int
+	keyId  []int
+	fields []encryptedField
+}
+
+// encryptInfos caches the encryptInfo of each resource type, or nil if the
+// type has no encrypt tags.
+var encryptInfos sync.Map
+
+// encryptInfoFor returns the encryptInfo for the resource type t, or nil if
+// it has no encrypt tags.
+func encryptInfoFor(t reflect.Type) (*encryptInfo, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	if v, ok := encryptInfos.Load(t); ok {
+		return v.(*encryptInfo), nil
+	}
+	info, err := parseEncryptTags(t)
+	if err != nil {
+		return nil, err
+	}
+	encryptInfos.Store(t, info)
+	return info, nil
+}
+
+func parseEncryptTags(t reflect.Type) (*encryptInfo, error) {
+	info := &encryptInfo{}
+	pts := map[string][]int{}
+	cts := map[string][]int{}
+	var walk func(t reflect.Type, index []int) error
+	walk = func(t reflect.Type, index []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fIndex := append(append([]int{}, index...), i)
+			if f.Anonymous && len(index) == 0 {
+				ft := f.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					if err := walk(ft, fIndex); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			tag, ok := f.Tag.Lookup(encryptTag)
+			if !ok {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			switch {
+			case tag == "scope" || tag == "key_id":
+				if f.Type.Kind() != reflect.String {
+					return fmt.Errorf("%s field %s is not a string", tag, f.Name)
+				}
+				if tag == "scope" {
+					info.scope = fIndex
+				} else {
+					info.keyId = fIndex
+				}
+			case len(parts) == 2 && parts[0] == "pt":
+				if f.Type.Kind() != reflect.String && f.Type != reflect.TypeOf([]byte(nil)) {
+					return fmt.Errorf("plaintext field %s is not a string or []byte", f.Name)
+				}
+				if f.Tag.Get("gorm") != "-" {
+					return fmt.Errorf("plaintext field %s must be tagged gorm:\"-\"", f.Name)
+				}
+				pts[parts[1]] = fIndex
+			case len(parts) == 2 && parts[0] == "ct":
+				if f.Type != reflect.TypeOf([]byte(nil)) {
+					return fmt.Errorf("ciphertext field %s is not a []byte", f.Name)
+				}
+				cts[parts[1]] = fIndex
+			default:
+				return fmt.Errorf("invalid encrypt tag %q on field %s", tag, f.Name)
+			}
+		}
+		return nil
+	}
+	if err := walk(t, nil); err != nil {
+		return nil, fmt.Errorf("%s: %w", t.Name(), err)
+	}
+	if info.scope == nil && info.keyId == nil && len(pts) == 0 && len(cts) == 0 {
+		return nil, nil
+	}
+	if info.scope == nil || info.keyId == nil {
+		return nil, fmt.Errorf("%s: encrypt tags for both the scope and key_id fields are required", t.Name())
+	}
+	for name, pt := range pts {
+		ct, ok := cts[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: no ciphertext field for %q", t.Name(), name)
+		}
+		info.fields = append(info.fields, encryptedField{name: name, pt: pt, ct: ct})
+		delete(cts, name)
+	}
+	for name := range cts {
+		return nil, fmt.Errorf("%s: no plaintext field for %q", t.Name(), name)
+	}
+	return info, nil
+}
+
+// fieldByIndex returns the field of v with index, or an invalid Value if an
+// embedded struct pointer on the way to it is nil.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return reflect.Value{}
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// ciphertextPaths returns paths with the plaintext fields replaced by their
+// ciphertext fields, and whether any were replaced. Paths which aren't
+// plaintext fields are returned unchanged.
+func (info *encryptInfo) ciphertextPaths(t reflect.Type, paths []string) ([]string, bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	ret := make([]string, 0, len(paths))
+	var replacedAny bool
+	for _, p := range paths {
+		replaced := false
+		for _, f := range info.fields {
+			if strings.EqualFold(p, t.FieldByIndex(f.pt).Name) {
+				ret = append(ret, t.FieldByIndex(f.ct).Name)
+				replaced, replacedAny = true, true
+				break
+			}
+		}
+		if !replaced {
+			ret = append(ret, p)
+		}
+	}
+	return ret, replacedAny
+}
+
+// encryptedPaths returns the field mask paths which update the ciphertext
+// fields of the plaintext fields in paths, and the key id field if there are
+// any. Paths which aren't plaintext fields are returned unchanged.
+func (info *encryptInfo) encryptedPaths(t reflect.Type, paths []string) []string {
+	ret, encrypted := info.ciphertextPaths(t, paths)
+	if encrypted {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		ret = append(ret, t.FieldByIndex(info.keyId).Name)
+	}
+	return ret
+}
+
+// hasPlaintext reports whether any of the plaintext fields of v are set.
+func (info *encryptInfo) hasPlaintext(v reflect.Value) bool {
+	for _, f := range info.fields {
+		if pt := fieldByIndex(v, f.pt); pt.IsValid() && pt.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Encrypt encrypts the plaintext fields of the resource tagged for
+// encryption into their ciphertext fields with the current database key of
+// its scope, sets its key id field and clears the plaintext fields, so they
+// aren't written to the oplog. Empty plaintext fields are left unencrypted,
+// and the scope id is only required if there's a plaintext field to encrypt.
+// Resources without encrypt tags are left unchanged.
+func Encrypt(ctx context.Context, resource interface{}, fn WrapperFn) error {
+	info, err := encryptInfoFor(reflect.TypeOf(resource))
+	if err != nil || info == nil {
+		return err
+	}
+	if fn == nil {
+		return fmt.Errorf("encrypt: missing wrapper function: %w", ErrInvalidParameter)
+	}
+	v := reflect.ValueOf(resource)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("encrypt: resource is not a pointer: %w", ErrInvalidParameter)
+	}
+	v = v.Elem()
+	if !info.hasPlaintext(v) {
+		return nil
+	}
+	scope := fieldByIndex(v, info.scope)
+	if !scope.IsValid() || scope.String() == "" {
+		return fmt.Errorf("encrypt: missing scope id: %w", ErrInvalidParameter)
+	}
+	var wrapper wrapping.Wrapper
+	for _, f := range info.fields {
+		pt := fieldByIndex(v, f.pt)
+		if !pt.IsValid() || pt.Len() == 0 {
+			continue
+		}
+		if wrapper == nil {
+			if wrapper, err = fn(ctx, scope.String(), ""); err != nil {
+				return fmt.Errorf("encrypt: unable to get wrapper: %w", err)
+			}
+		}
+		var b []byte
+		if pt.Kind() == reflect.String {
+			b = []byte(pt.String())
+		} else {
+			b = pt.Bytes()
+		}
+		blob, err := wrapper.Encrypt(ctx, b, nil)
+		if err != nil {
+			return fmt.Errorf("encrypt: %s: %w", f.name, err)
+		}
+		ct, err := proto.Marshal(blob)
+		if err != nil {
+			return fmt.Errorf("encrypt: %s: %w", f.name, err)
+		}
+		ctField := fieldByIndex(v, f.ct)
+		if !ctField.IsValid() {
+			return fmt.Errorf("encrypt: %s: missing ciphertext field: %w", f.name, ErrInvalidParameter)
+		}
+		ctField.SetBytes(ct)
+		pt.Set(reflect.Zero(pt.Type()))
+	}
+	if wrapper != nil {
+		if keyId := fieldByIndex(v, info.keyId); keyId.IsValid() {
+			keyId.SetString(wrapper.KeyID())
+		}
+	}
+	return nil
+}
+
+// Decrypt decrypts the ciphertext fields of the resource tagged for
+// encryption into their plaintext fields with the key identified by its key
+// id field. Empty ciphertext fields are left undecrypted. Resources without
+// encrypt tags are left unchanged.
+func Decrypt(ctx context.Context, resource interface{}, fn WrapperFn) error {
+	info, err := encryptInfoFor(reflect.TypeOf(resource))
+	if err != nil || info == nil {
+		return err
+	}
+	if fn == nil {
+		return fmt.Errorf("decrypt: missing wrapper function: %w", ErrInvalidParameter)
+	}
+	v := reflect.ValueOf(resource)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("decrypt: resource is not a pointer: %w", ErrInvalidParameter)
+	}
+	v = v.Elem()
+	scope, keyId := fieldByIndex(v, info.scope), fieldByIndex(v, info.keyId)
+	if !scope.IsValid() || !keyId.IsValid() || keyId.String() == "" {
+		return nil
+	}
+	var wrapper wrapping.Wrapper
+	for _, f := range info.fields {
+		ct := fieldByIndex(v, f.ct)
+		if !ct.IsValid() || ct.Len() == 0 {
+			continue
+		}
+		if wrapper == nil {
+			if wrapper, err = fn(ctx, scope.String(), keyId.String()); err != nil {
+				return fmt.Errorf("decrypt: unable to get wrapper: %w", err)
+			}
+		}
+		blob := new(wrapping.EncryptedBlobInfo)
+		if err := proto.Unmarshal(ct.Bytes(), blob); err != nil {
+			return fmt.Errorf("decrypt: %s: %w", f.name, err)
+		}
+		b, err := wrapper.Decrypt(ctx, blob, nil)
+		if err != nil {
+			return fmt.Errorf("decrypt: %s: %w", f.name, err)
+		}
+		pt := fieldByIndex(v, f.pt)
+		if pt.Kind() == reflect.String {
+			pt.SetString(string(b))
+		} else {
+			pt.SetBytes(b)
+		}
+	}
+	return nil
+}
+
+// decryptAll decrypts each resource in resources, which is a pointer to a
+// slice of resources as passed to SearchWhere.
+func decryptAll(ctx context.Context, resources interface{}, fn WrapperFn) error {
+	v := reflect.Indirect(reflect.ValueOf(resources))
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		if item.Kind() != reflect.Ptr {
+			item = item.Addr()
+		}
+		if item.IsNil() {
+			continue
+		}
+		if err := Decrypt(ctx, item.Interface(), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vetEncryption encrypts the resource before it is written if it has
+// plaintext fields set, or returns an error if it has them but no WrapperFn
+// was given.
+func vetEncryption(ctx context.Context, resource interface{}, opts Options) error {
+	info, err := encryptInfoFor(reflect.TypeOf(resource))
+	if err != nil || info == nil {
+		return err
+	}
+	v := reflect.Indirect(reflect.ValueOf(resource))
+	if v.Kind() != reflect.Struct || !info.hasPlaintext(v) {
+		return nil
+	}
+	if opts.withWrapperFn == nil {
+		return fmt.Errorf("resource has encrypted fields but no wrapper function was provided: %w", ErrInvalidParameter)
+	}
+	return Encrypt(ctx, resource, opts.withWrapperFn)
+}
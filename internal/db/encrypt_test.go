@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSecretStore struct {
+	PublicId string
+	ScopeId  string `encrypt:"scope"`
+	KeyId    string `encrypt:"key_id"`
+	CtSecret []byte `encrypt:"ct,secret"`
+	CtToken  []byte `encrypt:"ct,token"`
+}
+
+type testSecret struct {
+	*testSecretStore
+	Secret []byte `gorm:"-" encrypt:"pt,secret"`
+	Token  string `gorm:"-" encrypt:"pt,token"`
+}
+
+func Test_Encrypt(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	wrapper := TestWrapper(t)
+	var scopes []string
+	fn := func(_ context.Context, scopeId, _ string) (wrapping.Wrapper, error) {
+		scopes = append(scopes, scopeId)
+		return wrapper, nil
+	}
+
+	t.Run("round-trip", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		scopes = nil
+		s := &testSecret{
+			testSecretStore: &testSecretStore{PublicId: "s_1234567890", ScopeId: "o_1234567890"},
+			Secret:          []byte("secret"),
+			Token:           "token",
+		}
+		require.NoError(Encrypt(ctx, s, fn))
+		assert.Equal([]string{"o_1234567890"}, scopes)
+		assert.Equal(wrapper.KeyID(), s.KeyId)
+		assert.NotEmpty(s.CtSecret)
+		assert.NotEqual([]byte("secret"), s.CtSecret)
+		assert.NotEmpty(s.CtToken)
+		assert.Empty(s.Secret)
+		assert.Empty(s.Token)
+
+		read := &testSecret{testSecretStore: s.testSecretStore}
+		require.NoError(Decrypt(ctx, read, fn))
+		assert.Equal([]byte("secret"), read.Secret)
+		assert.Equal("token", read.Token)
+	})
+	t.Run("empty-plaintext", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		s := &testSecret{
+			testSecretStore: &testSecretStore{ScopeId: "o_1234567890"},
+			Token:           "token",
+		}
+		require.NoError(Encrypt(ctx, s, fn))
+		assert.Empty(s.CtSecret)
+		assert.NotEmpty(s.CtToken)
+
+		read := &testSecret{testSecretStore: s.testSecretStore}
+		require.NoError(Decrypt(ctx, read, fn))
+		assert.Empty(read.Secret)
+		assert.Equal("token", read.Token)
+	})
+	t.Run("missing-scope", func(t *testing.T) {
+		s := &testSecret{testSecretStore: &testSecretStore{}, Token: "token"}
+		err := Encrypt(ctx, s, fn)
+		assert.True(t, errors.Is(err, ErrInvalidParameter))
+	})
+	t.Run("no-plaintext-without-scope", func(t *testing.T) {
+		assert := assert.New(t)
+		scopes = nil
+		s := &testSecret{testSecretStore: &testSecretStore{PublicId: "s_1234567890"}}
+		assert.NoError(Encrypt(ctx, s, fn))
+		assert.Empty(scopes)
+		assert.Empty(s.KeyId)
+		assert.NoError(vetEncryption(ctx, s, GetOpts()))
+	})
+	t.Run("missing-fn", func(t *testing.T) {
+		s := &testSecret{testSecretStore: &testSecretStore{ScopeId: "o_1234567890"}, Token: "token"}
+		err := vetEncryption(ctx, s, GetOpts())
+		assert.True(t, errors.Is(err, ErrInvalidParameter))
+		assert.NoError(t, vetEncryption(ctx, s, GetOpts(WithWrapperFn(fn))))
+		assert.NotEmpty(t, s.CtToken)
+	})
+	t.Run("not-tagged", func(t *testing.T) {
+		type plain struct{ Secret []byte }
+		assert.NoError(t, Encrypt(ctx, &plain{Secret: []byte("secret")}, nil))
+		assert.NoError(t, vetEncryption(ctx, &plain{}, GetOpts()))
+	})
+}
+
+func Test_encryptInfoFor(t *testing.T) {
+	t.Parallel()
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			in   interface{}
+		}{
+			{
+				name: "stored-plaintext",
+				in: struct {
+					ScopeId  string `encrypt:"scope"`
+					KeyId    string `encrypt:"key_id"`
+					Secret   []byte `encrypt:"pt,secret"`
+					CtSecret []byte `encrypt:"ct,secret"`
+				}{},
+			},
+			{
+				name: "missing-ciphertext",
+				in: struct {
+					ScopeId string `encrypt:"scope"`
+					KeyId   string `encrypt:"key_id"`
+					Secret  []byte `gorm:"-" encrypt:"pt,secret"`
+				}{},
+			},
+			{
+				name: "missing-key-id",
+				in: struct {
+					ScopeId  string `encrypt:"scope"`
+					Secret   []byte `gorm:"-" encrypt:"pt,secret"`
+					CtSecret []byte `encrypt:"ct,secret"`
+				}{},
+			},
+			{
+				name: "string-ciphertext",
+				in: struct {
+					ScopeId  string `encrypt:"scope"`
+					KeyId    string `encrypt:"key_id"`
+					Secret   []byte `gorm:"-" encrypt:"pt,secret"`
+					CtSecret string `encrypt:"ct,secret"`
+				}{},
+			},
+			{
+				name: "unknown-tag",
+				in: struct {
+					ScopeId string `encrypt:"scope"`
+					KeyId   string `encrypt:"key_id"`
+					Secret  []byte `encrypt:"secret"`
+				}{},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := encryptInfoFor(reflect.TypeOf(tt.in))
+				assert.Error(t, err)
+			})
+		}
+	})
+	t.Run("update-paths", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		typ := reflect.TypeOf(&testSecret{})
+		info, err := encryptInfoFor(typ)
+		require.NoError(err)
+		require.NotNil(info)
+		assert.Equal([]string{"Name"}, info.encryptedPaths(typ, []string{"Name"}))
+		assert.Equal([]string{"Name", "CtSecret", "KeyId"}, info.encryptedPaths(typ, []string{"Name", "secret"}))
+		nullPaths, replaced := info.ciphertextPaths(typ, []string{"Token"})
+		assert.True(replaced)
+		assert.Equal([]string{"CtToken"}, nullPaths)
+	})
+}
@@ -1,9 +1,7 @@
 package db
 
 import (
-	"errors"
-
-	"github.com/lib/pq"
+	"github.com/hashicorp/boundary/internal/errors"
 )
 
 // Errors returned from this package may be tested against these errors
@@ -26,7 +24,7 @@ var (
 
 	// ErrNotUnique is returned by create and update methods when a write
 	// to the repository resulted in a unique constraint violation.
-	ErrNotUnique = errors.New("unique constraint violation")
+	ErrNotUnique = errors.ErrNotUnique
 
 	// ErrRecordNotFound returns a "record not found" error and it only occurs
 	// when attempting to read from the database into struct.
@@ -37,55 +35,32 @@ var (
 	// write to the repository would result in more than one record being
 	// changed resulting in the transaction being rolled back.
 	ErrMultipleRecords = errors.New("multiple records")
+
+	// ErrLockHeld is returned by TryLock when the lock is held by another
+	// session.
+	ErrLockHeld = errors.New("lock held")
 )
 
 // IsUniqueError returns a boolean indicating whether the error is known to
 // report a unique constraint violation.
 func IsUniqueError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	var pqError *pq.Error
-	if errors.As(err, &pqError) {
-		if pqError.Code.Name() == "unique_violation" {
-			return true
-		}
-	}
-
-	return false
+	return errors.IsUniqueError(err)
 }
 
 // IsCheckConstraintError returns a boolean indicating whether the error is
 // known to report a check constraint violation.
 func IsCheckConstraintError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	var pqError *pq.Error
-	if errors.As(err, &pqError) {
-		if pqError.Code.Name() == "check_violation" {
-			return true
-		}
-	}
-
-	return false
+	return errors.IsCheckConstraintError(err)
 }
 
 // IsNotNullError returns a boolean indicating whether the error is known
 // to report a not-null constraint violation.
 func IsNotNullError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	var pqError *pq.Error
-	if errors.As(err, &pqError) {
-		if pqError.Code.Name() == "not_null_violation" {
-			return true
-		}
-	}
+	return errors.IsNotNullError(err)
+}
 
-	return false
+// IsForeignKeyError returns a boolean indicating whether the error is known
+// to report a foreign key constraint violation.
+func IsForeignKeyError(err error) bool {
+	return errors.IsForeignKeyError(err)
 }
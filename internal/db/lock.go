@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/metric"
+)
+
+// A Lock is a Postgres session level advisory lock held by this process. Locks
+// are named; every process using the same name against the same database
+// contends for the same lock, so they can be used to make sure only one
+// controller runs a job, applies migrations or acts as leader at a time.
+//
+// Each Lock holds a connection from the pool until it's unlocked. The lock is
+// released by the database if the connection is lost, so the holder of a lock
+// must be prepared to lose it.
+type Lock struct {
+	name       string
+	key        int64
+	conn       *sql.Conn
+	acquiredAt time.Time
+}
+
+// Name returns the name the lock was acquired with.
+func (l *Lock) Name() string {
+	return l.name
+}
+
+// lockKey returns the advisory lock key for name.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// TryLock acquires the advisory lock name if no one else holds it, without
+// waiting. It returns ErrLockHeld if the lock is held by another session,
+// including another Lock of this process. The caller must Unlock the returned
+// Lock. TryLock must not be called on a Db used within a transaction.
+func (rw *Db) TryLock(ctx context.Context, name string) (*Lock, error) {
+	const op = "db.TryLock"
+	start := time.Now()
+	l, err := rw.lock(ctx, op, name, "select pg_try_advisory_lock($1)")
+	observeLockWait(name, start, err)
+	return l, err
+}
+
+// Lock acquires the advisory lock name, waiting until it's released if it's
+// held by another session. If ctx is canceled while waiting the wait is
+// abandoned and the context's error is returned. The caller must Unlock the
+// returned Lock. Lock must not be called on a Db used within a transaction.
+func (rw *Db) Lock(ctx context.Context, name string) (*Lock, error) {
+	const op = "db.Lock"
+	start := time.Now()
+	l, err := rw.lock(ctx, op, name, "select true from pg_advisory_lock($1)")
+	observeLockWait(name, start, err)
+	return l, err
+}
+
+// lock runs the lock query, which must return whether the lock was acquired,
+// on a connection of its own which it keeps for the returned Lock.
+func (rw *Db) lock(ctx context.Context, op, name, query string) (*Lock, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%s: missing name: %w", op, ErrInvalidParameter)
+	}
+	if rw.underlying == nil || rw.underlying.DB() == nil {
+		return nil, fmt.Errorf("%s: missing underlying db: %w", op, ErrInvalidParameter)
+	}
+	conn, err := rw.underlying.DB().Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: unable to get connection: %w", op, err)
+	}
+	key := lockKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, query, key).Scan(&acquired); err != nil {
+		// The lock may have been acquired before the query was canceled, so
+		// the connection is closed rather than returned to the pool to make
+		// sure the lock is released.
+		discardConn(conn)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, name, ctx.Err())
+		}
+		return nil, fmt.Errorf("%s: %s: %w", op, name, err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%s: %s: %w", op, name, ErrLockHeld)
+	}
+	metric.DbLocksHeld.WithLabelValues(name).Inc()
+	return &Lock{name: name, key: key, conn: conn, acquiredAt: time.Now()}, nil
+}
+
+// Unlock releases the lock and the connection it holds. The lock is released
+// even if Unlock returns an error, by closing its connection. Unlocking a Lock
+// more than once returns ErrInvalidParameter.
+func (l *Lock) Unlock(ctx context.Context) error {
+	const op = "db.(Lock).Unlock"
+	if l == nil || l.conn == nil {
+		return fmt.Errorf("%s: lock is not held: %w", op, ErrInvalidParameter)
+	}
+	conn := l.conn
+	l.conn = nil
+	metric.DbLocksHeld.WithLabelValues(l.name).Dec()
+	metric.DbLockHeldDuration.WithLabelValues(l.name).Observe(time.Since(l.acquiredAt).Seconds())
+
+	var released bool
+	if err := conn.QueryRowContext(ctx, "select pg_advisory_unlock($1)", l.key).Scan(&released); err != nil {
+		discardConn(conn)
+		return fmt.Errorf("%s: %s: %w", op, l.name, err)
+	}
+	if !released {
+		// The lock was lost, such as by the connection being reset.
+		discardConn(conn)
+		return fmt.Errorf("%s: %s: lock was not held by its connection", op, l.name)
+	}
+	if err := conn.Close(); err != nil {
+		return fmt.Errorf("%s: %s: %w", op, l.name, err)
+	}
+	return nil
+}
+
+// Check returns an error if the lock's connection to the database can't be
+// used, in which case the lock may have been released by the database and the
+// holder should assume it was lost and Unlock it.
+func (l *Lock) Check(ctx context.Context) error {
+	const op = "db.(Lock).Check"
+	if l == nil || l.conn == nil {
+		return fmt.Errorf("%s: lock is not held: %w", op, ErrInvalidParameter)
+	}
+	if err := l.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %s: %w", op, l.name, err)
+	}
+	return nil
+}
+
+// discardConn closes conn's underlying connection instead of returning it to
+// the pool, which releases any session level locks it holds.
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
+
+// observeLockWait records the time taken trying to acquire the lock name,
+// which started at start, by whether it was acquired.
+func observeLockWait(name string, start time.Time, err error) {
+	outcome := "acquired"
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrLockHeld):
+		outcome = "held"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		outcome = "canceled"
+	default:
+		outcome = "error"
+	}
+	metric.DbLockWaitDuration.WithLabelValues(name, outcome).Observe(time.Since(start).Seconds())
+}
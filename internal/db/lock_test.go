@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDb_Lock(t *testing.T) {
+	t.Parallel()
+	conn, _ := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	t.Run("invalid", func(t *testing.T) {
+		assert := assert.New(t)
+		_, err := rw.TryLock(ctx, "")
+		assert.True(errors.Is(err, ErrInvalidParameter))
+		_, err = rw.Lock(ctx, "")
+		assert.True(errors.Is(err, ErrInvalidParameter))
+		var l *Lock
+		assert.True(errors.Is(l.Unlock(ctx), ErrInvalidParameter))
+		assert.True(errors.Is(l.Check(ctx), ErrInvalidParameter))
+	})
+
+	t.Run("try-lock", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		l, err := rw.TryLock(ctx, "try-lock")
+		require.NoError(err)
+		assert.Equal("try-lock", l.Name())
+
+		_, err = rw.TryLock(ctx, "try-lock")
+		assert.True(errors.Is(err, ErrLockHeld))
+
+		other, err := rw.TryLock(ctx, "other")
+		require.NoError(err)
+		require.NoError(other.Unlock(ctx))
+
+		require.NoError(l.Unlock(ctx))
+		assert.True(errors.Is(l.Unlock(ctx), ErrInvalidParameter))
+
+		l, err = rw.TryLock(ctx, "try-lock")
+		require.NoError(err)
+		require.NoError(l.Unlock(ctx))
+	})
+
+	t.Run("lock-waits", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		l, err := rw.Lock(ctx, "lock-waits")
+		require.NoError(err)
+
+		acquired := make(chan *Lock)
+		go func() {
+			l, err := rw.Lock(ctx, "lock-waits")
+			assert.NoError(err)
+			acquired <- l
+		}()
+		select {
+		case <-acquired:
+			t.Fatal("lock acquired while held")
+		case <-time.After(500 * time.Millisecond):
+		}
+		require.NoError(l.Unlock(ctx))
+
+		select {
+		case l = <-acquired:
+			require.NotNil(l)
+			require.NoError(l.Unlock(ctx))
+		case <-time.After(10 * time.Second):
+			t.Fatal("lock not acquired once released")
+		}
+	})
+
+	t.Run("lock-canceled", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		l, err := rw.Lock(ctx, "lock-canceled")
+		require.NoError(err)
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		defer cancel()
+		_, err = rw.Lock(cancelCtx, "lock-canceled")
+		assert.True(errors.Is(err, context.DeadlineExceeded), "got %v", err)
+
+		// The canceled wait doesn't leave the lock held once it's released
+		require.NoError(l.Unlock(ctx))
+		l, err = rw.TryLock(ctx, "lock-canceled")
+		require.NoError(err)
+		require.NoError(l.Unlock(ctx))
+	})
+
+	t.Run("check", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		l, err := rw.TryLock(ctx, "check")
+		require.NoError(err)
+		require.NoError(l.Check(ctx))
+
+		// Terminating the lock's session releases the lock, which Check
+		// reports as an error.
+		_, err = rw.Exec(ctx, "select pg_terminate_backend(pid) from pg_locks where locktype = 'advisory' and granted and pid <> pg_backend_pid() and database = (select oid from pg_database where datname = current_database())", nil)
+		require.NoError(err)
+		assert.Error(l.Check(ctx))
+		assert.Error(l.Unlock(ctx))
+		assert.True(errors.Is(l.Check(ctx), ErrInvalidParameter))
+
+		l, err = rw.TryLock(ctx, "check")
+		require.NoError(err)
+		require.NoError(l.Unlock(ctx))
+	})
+}
@@ -1481,3877 +1481,7497 @@ commit;
 
 `),
 	},
-	"migrations/11_auth_token.down.sql": {
-		name: "11_auth_token.down.sql",
+	"migrations/100_session_host_selection.down.sql": {
+		name: "100_session_host_selection.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop view auth_token_account cascade;
-  drop table auth_token cascade;
+  drop view session_with_state;
 
-  drop function update_last_access_time cascade;
-  drop function immutable_auth_token_columns cascade;
-  drop function expire_time_not_older_than_token cascade;
+  create view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit,
+    s.allowed_ports
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit', 'allowed_ports');
+
+  alter table session
+    drop column host_selection_reason,
+    drop column host_address;
 
 commit;
 
 `),
 	},
-	"migrations/11_auth_token.up.sql": {
-		name: "11_auth_token.up.sql",
+	"migrations/100_session_host_selection.up.sql": {
+		name: "100_session_host_selection.up.sql",
 		bytes: []byte(`
 begin;
 
-  -- an auth token belongs to 1 and only 1 auth account
-  -- an auth account can have 0 to many auth tokens
-  create table auth_token (
-    public_id wt_public_id primary key,
-    token bytea not null unique,
-    -- TODO: Make key_id a foreign key once we have DEKs
-    key_id text not null
-      constraint key_id_must_not_be_empty
-      check(length(trim(key_id)) > 0),
-    auth_account_id wt_public_id not null
-      references auth_account(public_id)
-      on delete cascade
-      on update cascade,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    -- This column is not updated every time this auth token is accessed.
-    -- It is updated after X minutes from the last time it was updated on
-    -- a per row basis.
-    approximate_last_access_time wt_timestamp
-      constraint last_access_time_must_not_be_after_expiration_time
-      check(
-        approximate_last_access_time <= expiration_time
-      ),
-    expiration_time wt_timestamp
-      constraint create_time_must_not_be_after_expiration_time
-      check(
-        create_time <= expiration_time
-      )
-  );
-
-  create view auth_token_account as
-        select at.public_id,
-               at.token,
-               at.auth_account_id,
-               at.create_time,
-               at.update_time,
-               at.approximate_last_access_time,
-               at.expiration_time,
-               aa.scope_id,
-               aa.iam_user_id,
-               aa.auth_method_id
-          from auth_token as at
-    inner join auth_account as aa
-            on at.auth_account_id = aa.public_id;
+  -- host_address is the address of the host chosen for the session when it
+  -- was authorized, and host_selection_reason is why that host was chosen,
+  -- such as 'requested' when the client asked for it or 'random'. They let
+  -- operators find out why a session went to a host after the host or its
+  -- address has changed. They are null for sessions authorized before they
+  -- were recorded.
+  alter table session
+    add column host_address text
+      constraint host_address_must_not_be_empty
+      check(length(trim(host_address)) > 0),
+    add column host_selection_reason text
+      constraint host_selection_reason_must_not_be_empty
+      check(length(trim(host_selection_reason)) > 0);
+
+  drop trigger immutable_columns on session;
 
-  create or replace function
-    update_last_access_time()
-    returns trigger
-  as $$
-  begin
-    if new.approximate_last_access_time is distinct from old.approximate_last_access_time then
-      new.approximate_last_access_time = now();
-    end if;
-    return new;
-  end;
-  $$ language plpgsql;
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit', 'allowed_ports', 'host_address', 'host_selection_reason');
 
-  comment on function
-    update_last_access_time()
-  is
-    'function used in before update triggers to properly set last_access_time columns';
+  create or replace view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit,
+    s.allowed_ports,
+    s.host_address,
+    s.host_selection_reason
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
 
-  create or replace function
-    immutable_auth_token_columns()
-    returns trigger
-  as $$
-  begin
-    if new.auth_account_id is distinct from old.auth_account_id then
-      raise exception 'auth_account_id is read-only';
-    end if;
-    if new.token is distinct from old.token then
-      raise exception 'token is read-only';
-    end if;
-    return new;
-  end;
-  $$ language plpgsql;
+commit;
 
-  comment on function
-    immutable_auth_token_columns()
-  is
-    'function used in before update triggers to make specific columns immutable';
+`),
+	},
+	"migrations/101_target_host_selection.down.sql": {
+		name: "101_target_host_selection.down.sql",
+		bytes: []byte(`
+begin;
 
-  -- This allows the expiration to be calculated on the server side and still hold the constraint that
-  -- the expiration time cant be before the creation time of the auth token.
-  create or replace function
-    expire_time_not_older_than_token()
-    returns trigger
-  as $$
-  begin
-    if new.expiration_time < new.create_time then
-      new.expiration_time = new.create_time;
-    end if;
-    return new;
-  end;
-  $$ language plpgsql;
+  drop table target_host_affinity;
+  drop table target_round_robin;
 
-  comment on function
-      expire_time_not_older_than_token()
-  is
-    'function used in before insert triggers to ensure expiration time is not older than create time';
+  drop view search_resource;
+  drop view target_all_subtypes;
 
-  create trigger
-    default_create_time_column
-  before insert on auth_token
-    for each row execute procedure default_create_time();
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+
+  alter table target_tcp
+    drop column host_selection_strategy;
 
-  create trigger
-    expire_time_not_older_than_token
-  before insert on auth_token
-    for each row execute procedure expire_time_not_older_than_token();
+commit;
 
-  create trigger
-    update_time_column
-  before update on auth_token
-    for each row execute procedure update_time_column();
+`),
+	},
+	"migrations/101_target_host_selection.up.sql": {
+		name: "101_target_host_selection.up.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger
-    update_last_access_time
-  before update on auth_token
-    for each row execute procedure update_last_access_time();
+  -- host_selection_strategy is how the host of a session is chosen from the
+  -- target's host sets when the user doesn't ask for one: 'random',
+  -- 'round_robin' through the hosts in order, or 'sticky', which chooses the
+  -- host the user was last given while it's still in the target's host sets.
+  alter table target_tcp
+    add column host_selection_strategy text not null default 'random'
+      constraint host_selection_strategy_must_be_random_round_robin_or_sticky
+      check(host_selection_strategy in ('random', 'round_robin', 'sticky'));
+
+  -- search_resource depends on target_all_subtypes, so the column is added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy
+    from target_tcp;
+
+  -- target_round_robin counts the hosts chosen for each target by the
+  -- round_robin strategy. Every controller takes the next host from the
+  -- shared count, so sessions are spread evenly however many controllers
+  -- there are.
+  create table target_round_robin (
+    target_id wt_public_id primary key
+      references target (public_id)
+      on delete cascade
+      on update cascade,
+    host_count bigint not null default 0
+      constraint host_count_must_not_be_negative
+      check(host_count >= 0),
+    update_time wt_timestamp not null
+  );
 
+  -- target_host_affinity records the host each user was last given by the
+  -- sticky strategy of each target. The record is removed with the target,
+  -- user, host set or host.
+  create table target_host_affinity (
+    target_id wt_public_id not null
+      references target (public_id)
+      on delete cascade
+      on update cascade,
+    user_id wt_user_id not null
+      references iam_user (public_id)
+      on delete cascade
+      on update cascade,
+    host_set_id wt_public_id not null
+      references host_set (public_id)
+      on delete cascade
+      on update cascade,
+    host_id wt_public_id not null
+      references host (public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp not null,
+    update_time wt_timestamp not null,
+    primary key (target_id, user_id)
+  );
 
   create trigger
-    immutable_auth_token_columns
-  before update on auth_token
-    for each row execute procedure immutable_auth_token_columns();
-
-  create trigger 
     immutable_columns
   before
-  update on auth_token
-    for each row execute procedure immutable_columns('public_id', 'auth_account_id', 'create_time');
+  update on target_host_affinity
+    for each row execute procedure immutable_columns('target_id', 'user_id', 'create_time');
 
 commit;
 
 `),
 	},
-	"migrations/12_auth_password.down.sql": {
-		name: "12_auth_password.down.sql",
+	"migrations/102_target_endpoint_tls.down.sql": {
+		name: "102_target_endpoint_tls.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table auth_password_credential;
-  drop table auth_password_conf cascade;
-  drop table if exists auth_password_account;
-  drop table if exists auth_password_method;
+  drop view search_resource;
+  drop view target_all_subtypes;
 
-  drop function insert_auth_password_credential_subtype;
-  drop function insert_auth_password_conf_subtype;
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+  alter table target_tcp
+    drop column tls_key_id,
+    drop column tls_client_private_key,
+    drop column tls_client_certificate,
+    drop column tls_ca_bundle,
+    drop column tls_server_name,
+    drop column endpoint_tls;
 
 commit;
 
 `),
 	},
-	"migrations/12_auth_password.up.sql": {
-		name: "12_auth_password.up.sql",
+	"migrations/102_target_endpoint_tls.up.sql": {
+		name: "102_target_endpoint_tls.up.sql",
 		bytes: []byte(`
 begin;
 
-/*
+  -- When endpoint_tls is true, workers proxying the connections of a target
+  -- whose protocol is tcp originate TLS to its hosts rather than relaying
+  -- the client's bytes in plaintext. tls_server_name is the name the host's
+  -- certificate is verified against, and the host's address if it's null.
+  -- tls_ca_bundle is the PEM encoded certificates the host's certificate is
+  -- verified with, and the worker's system roots if it's null.
+  -- tls_client_certificate and tls_client_private_key are the PEM encoded
+  -- certificate and key workers authenticate to the host with. The CA bundle
+  -- and the private key are encrypted with the database key of the target's
+  -- scope, whose version is tls_key_id.
+  alter table target_tcp
+    add column endpoint_tls boolean not null default false,
+    add column tls_server_name text
+      constraint tls_server_name_must_not_be_empty
+      check(length(trim(tls_server_name)) > 0),
+    add column tls_ca_bundle bytea,
+    add column tls_client_certificate text
+      constraint tls_client_certificate_must_not_be_empty
+      check(length(trim(tls_client_certificate)) > 0),
+    add column tls_client_private_key bytea,
+    -- TODO: Make tls_key_id a foreign key once we have DEKs
+    add column tls_key_id text
+      constraint tls_key_id_must_not_be_empty
+      check(length(trim(tls_key_id)) > 0);
+
+  -- search_resource depends on target_all_subtypes, so the columns are added
+  -- to the end of the view rather than dropping and recreating it. The CA
+  -- bundle and private key are left out so they're only read when a worker
+  -- looks a session up.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy,
+    endpoint_tls,
+    tls_server_name,
+    tls_client_certificate
+    from target_tcp;
 
-       ┌────────────────┐                 ┌──────────────────────┐             ┌────────────────────────────┐
-       │  auth_method   │                 │ auth_password_method │             │     auth_password_conf     │
-       ├────────────────┤                 ├──────────────────────┤             ├────────────────────────────┤
-       │ public_id (pk) │                 │ public_id (pk,fk)    │            ╱│ private_id         (pk,fk) │
-       │ scope_id  (fk) │┼┼─────────────○┼│ scope_id  (fk)       │┼┼─────────○─│ password_method_id (fk)    │
-       │                │                 │ ...                  │            ╲│                            │
-       └────────────────┘                 └──────────────────────┘             └────────────────────────────┘
-                ┼                                     ┼                                       ┼
-                ┼                                     ┼                                       ┼
-                │                                     │                                       │
-                │ ▲fk1                                │ ▲fk1                                  │ ▲fk1
-                │                                     │                                       │
-                ○                                     ○                                       ○
-               ╱│╲                                   ╱│╲                                     ╱│╲
-  ┌──────────────────────────┐          ┌──────────────────────────┐          ┌───────────────────────────────┐
-  │       auth_account       │          │  auth_password_account   │          │   auth_password_credential    │
-  ├──────────────────────────┤          ├──────────────────────────┤          ├───────────────────────────────┤
-  │ public_id         (pk)   │          │ public_id      (pk,fk2)  │          │ private_id          (pk)      │
-  │ scope_id          (fk1)  │   ◀fk2   │ scope_id       (fk1,fk2) │   ◀fk2   │ password_method_id  (fk1,fk2) │
-  │ auth_method_id    (fk1)  │┼┼──────○┼│ auth_method_id (fk1,fk2) │┼┼──────○┼│ password_conf_id    (fk1)     │
-  │ iam_user_scope_id (fk2)  │          │ ...                      │          │ password_account_id (fk2)     │
-  │ iam_user_id       (fk2)  │          └──────────────────────────┘          └───────────────────────────────┘
-  └──────────────────────────┘
+commit;
 
-  An auth_method is a base type. An auth_password_method is an auth_method
-  subtype. For every row in auth_password_method there is one row in auth_method
-  with the same public_id and scope_id.
+`),
+	},
+	"migrations/103_kms_key_usage.down.sql": {
+		name: "103_kms_key_usage.down.sql",
+		bytes: []byte(`
+begin;
 
-  Similarly, an auth_account is a base type. An auth_password_account is an
-  auth_account subtype. For every row in auth_password_account there is one row
-  in auth_account with the same public_id, scope_id, and auth_method_id.
+  drop view kms_key_usage_view;
+  drop table kms_key_usage;
 
-  Both auth_password_conf and auth_password_credential are base types. Each
-  password key derivation function will require a auth_password_conf and
-  auth_password_credential table.
+commit;
 
-  An auth_method can have 0 or 1 auth_password_method.
-  An auth_account can have 0 or 1 auth_password_account.
+`),
+	},
+	"migrations/103_kms_key_usage.up.sql": {
+		name: "103_kms_key_usage.up.sql",
+		bytes: []byte(`
+begin;
 
-  An auth_password_method belongs to 1 auth_method.
-  An auth_password_method can have 0 to many auth_password_accounts.
-  An auth_password_method can have 0 to many auth_password_confs.
+  -- kms_key_usage counts the encrypt and decrypt operations done with each
+  -- version of each scope's data encryption keys. Every controller adds the
+  -- operations it has counted, so security teams can detect unusual use of a
+  -- scope's keys, and check nothing still decrypts with a key version that
+  -- was rotated out. Key versions aren't referenced, so the counts are kept
+  -- after a key version is destroyed.
+  create table kms_key_usage (
+    key_version_id wt_private_id not null,
+    operation text not null
+      constraint operation_must_be_encrypt_or_decrypt
+      check(operation in ('encrypt', 'decrypt')),
+    scope_id wt_scope_id not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    purpose text not null
+      constraint purpose_must_be_database_oplog_tokens_or_sessions
+      check(purpose in ('database', 'oplog', 'tokens', 'sessions')),
+    use_count bigint not null default 0
+      constraint use_count_must_not_be_negative
+      check(use_count >= 0),
+    first_use_time wt_timestamp not null,
+    last_use_time wt_timestamp not null,
+    primary key (key_version_id, operation)
+  );
 
-  An auth_password_account belongs to 1 auth_account.
-  An auth_password_account belongs to 1 auth_password_method.
-  An auth_password_account can have 0 or 1 auth_password_credential.
+  -- kms_key_usage_view adds the version of each key version to its usage.
+  -- The version is 0 if the key version has been destroyed.
+  create view kms_key_usage_view
+  as
+  select
+    u.scope_id,
+    u.purpose,
+    u.key_version_id,
+    coalesce(v.version, 0) as key_version,
+    u.operation,
+    u.use_count,
+    u.first_use_time,
+    u.last_use_time
+  from kms_key_usage u
+    left join (
+      select private_id, version from kms_database_key_version
+      union all
+      select private_id, version from kms_oplog_key_version
+      union all
+      select private_id, version from kms_token_key_version
+      union all
+      select private_id, version from kms_session_key_version
+    ) v on v.private_id = u.key_version_id;
 
-  An auth_password_conf belongs to 1 auth_password_method.
-  An auth_password_conf can have 0 to many auth_password_credentials.
+commit;
 
-  An auth_password_credential belongs to 1 auth_password_account.
-  An auth_password_credential belongs to 1 auth_password_conf.
+`),
+	},
+	"migrations/104_list_sort_indexes.down.sql": {
+		name: "104_list_sort_indexes.down.sql",
+		bytes: []byte(`
+begin;
 
-*/
+  drop index target_tcp_scope_id_create_time_ix;
+  drop index static_host_set_catalog_id_create_time_ix;
+  drop index static_host_catalog_id_create_time_ix;
+  drop index static_host_catalog_scope_id_create_time_ix;
+  drop index auth_token_auth_account_id_create_time_ix;
+  drop index auth_password_account_auth_method_id_create_time_ix;
+  drop index auth_password_method_scope_id_create_time_ix;
+  drop index iam_role_scope_id_create_time_ix;
+  drop index iam_group_scope_id_create_time_ix;
+  drop index iam_user_scope_id_name_ix;
+  drop index iam_user_scope_id_create_time_ix;
+  drop index iam_scope_parent_id_create_time_ix;
 
-  create table auth_password_method (
-    public_id wt_public_id
-      primary key,
-    scope_id wt_scope_id
-      not null,
-    password_conf_id wt_private_id, -- FK to auth_password_conf added below
-    name text,
-    description text,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    min_login_name_length int
-      not null
-      default 3,
-    min_password_length int
-      not null
-      default 8,
-    version wt_version,
-    foreign key (scope_id, public_id)
-      references auth_method (scope_id, public_id)
-      on delete cascade
-      on update cascade,
-    unique(scope_id, name),
-    unique(scope_id, public_id)
-  );
+commit;
 
-  create trigger
-    update_version_column
-  after update on auth_password_method
-    for each row execute procedure update_version_column();
+`),
+	},
+	"migrations/104_list_sort_indexes.up.sql": {
+		name: "104_list_sort_indexes.up.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger
-    insert_auth_method_subtype
-  before insert on auth_password_method
-    for each row execute procedure insert_auth_method_subtype();
+  -- Listings can be sorted by creation time, so each resource which is
+  -- listed within a parent is indexed by its parent and create_time. Sorting
+  -- by name uses the unique(parent, name) constraints, except for users whose
+  -- constraint has its columns the other way around. Sessions are already
+  -- indexed by session_scope_id_create_time_ix.
+  create index iam_scope_parent_id_create_time_ix
+    on iam_scope (parent_id, create_time);
 
-  create table auth_password_account (
-    public_id wt_public_id
-      primary key,
-    auth_method_id wt_public_id
-      not null,
-    -- NOTE(mgaffney): The scope_id type is not wt_scope_id because the domain
-    -- check is executed before the insert trigger which retrieves the scope_id
-    -- causing an insert to fail.
-    scope_id text not null,
-    name text,
-    description text,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    login_name text not null
-      constraint login_name_must_be_lowercase
-      check(lower(trim(login_name)) = login_name)
-      constraint login_name_must_not_be_empty
-      check(length(trim(login_name)) > 0),
-    version wt_version,
-    foreign key (scope_id, auth_method_id)
-      references auth_password_method (scope_id, public_id)
-      on delete cascade
-      on update cascade,
-    foreign key (scope_id, auth_method_id, public_id)
-      references auth_account (scope_id, auth_method_id, public_id)
-      on delete cascade
-      on update cascade,
-    unique(auth_method_id, name),
-    unique(auth_method_id, login_name),
-    unique(auth_method_id, public_id)
-  );
+  create index iam_user_scope_id_create_time_ix
+    on iam_user (scope_id, create_time);
 
-  create trigger
-    update_version_column
-  after update on auth_password_account
-    for each row execute procedure update_version_column();
+  create index iam_user_scope_id_name_ix
+    on iam_user (scope_id, name);
 
-  create trigger
-    insert_auth_account_subtype
-  before insert on auth_password_account
-    for each row execute procedure insert_auth_account_subtype();
+  create index iam_group_scope_id_create_time_ix
+    on iam_group (scope_id, create_time);
 
-  create table auth_password_conf (
-    private_id wt_private_id
-      primary key,
-    password_method_id wt_public_id
-      not null
-      references auth_password_method (public_id)
-      on delete cascade
-      on update cascade
-      deferrable initially deferred,
-    unique(password_method_id, private_id)
-  );
+  create index iam_role_scope_id_create_time_ix
+    on iam_role (scope_id, create_time);
 
-  alter table auth_password_method
-    add constraint current_conf_fkey
-    foreign key (public_id, password_conf_id)
-    references auth_password_conf (password_method_id, private_id)
-    on delete cascade
-    on update cascade
-    deferrable initially deferred;
+  create index auth_password_method_scope_id_create_time_ix
+    on auth_password_method (scope_id, create_time);
 
-  -- insert_auth_password_conf_subtype() is a trigger function for subtypes of
-  -- auth_password_conf
-  create or replace function
-    insert_auth_password_conf_subtype()
-    returns trigger
-  as $$
-  begin
-    insert into auth_password_conf
-      (private_id, password_method_id)
-    values
-      (new.private_id, new.password_method_id);
-    return new;
-  end;
-  $$ language plpgsql;
+  create index auth_password_account_auth_method_id_create_time_ix
+    on auth_password_account (auth_method_id, create_time);
 
-  create table auth_password_credential (
-    private_id wt_private_id
-      primary key,
-    password_account_id wt_public_id
-      not null
-      unique,
-    password_conf_id wt_private_id,
-    password_method_id wt_public_id
-      not null,
-    foreign key (password_method_id, password_conf_id)
-      references auth_password_conf (password_method_id, private_id)
-      on delete cascade
-      on update cascade,
-    foreign key (password_method_id, password_account_id)
-      references auth_password_account (auth_method_id, public_id)
-      on delete cascade
-      on update cascade,
-    unique(password_method_id, password_conf_id, password_account_id)
-  );
+  create index auth_token_auth_account_id_create_time_ix
+    on auth_token (auth_account_id, create_time);
 
-  -- insert_auth_password_credential_subtype() is a trigger function for
-  -- subtypes of auth_password_credential
-  create or replace function
-    insert_auth_password_credential_subtype()
-    returns trigger
-  as $$
-  begin
+  create index static_host_catalog_scope_id_create_time_ix
+    on static_host_catalog (scope_id, create_time);
 
-    select auth_password_account.auth_method_id
-      into new.password_method_id
-    from auth_password_account
-    where auth_password_account.public_id = new.password_account_id;
+  create index static_host_catalog_id_create_time_ix
+    on static_host (catalog_id, create_time);
 
-    insert into auth_password_credential
-      (private_id, password_account_id, password_conf_id, password_method_id)
-    values
-      (new.private_id, new.password_account_id, new.password_conf_id, new.password_method_id);
-    return new;
-  end;
-  $$ language plpgsql;
+  create index static_host_set_catalog_id_create_time_ix
+    on static_host_set (catalog_id, create_time);
 
-  -- update_auth_password_credential_subtype() is an after update trigger
-  -- function for subtypes of auth_password_credential
-  create or replace function
-    update_auth_password_credential_subtype()
-    returns trigger
-  as $$
-  begin
-    /*
-      The configuration id of a credential is updated when a credential is
-      rehashed during authentication.
-    */
-    if new.password_conf_id is distinct from old.password_conf_id then
-      update auth_password_credential
-         set password_conf_id = new.password_conf_id
-       where private_id = new.private_id;
-    end if;
-    return null; -- result is ignored since this is an after trigger
-  end;
-  $$ language plpgsql;
+  create index target_tcp_scope_id_create_time_ix
+    on target_tcp (scope_id, create_time);
 
-  -- delete_auth_password_credential_subtype() is an after delete trigger
-  -- function for subtypes of auth_password_credential
-  create or replace function
-    delete_auth_password_credential_subtype()
-    returns trigger
-  as $$
-  begin
-    delete
-      from auth_password_credential
-     where private_id = old.private_id;
-    return null; -- result is ignored since this is an after trigger
-  end;
-  $$ language plpgsql;
+commit;
 
-  --
-  -- triggers for time columns
-  --
+`),
+	},
+	"migrations/105_oplog_hmac_chain.down.sql": {
+		name: "105_oplog_hmac_chain.down.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger
-    update_time_column
-  before
-  update on auth_password_method
-    for each row execute procedure update_time_column();
+  drop index oplog_entry_aggregate_name_id_ix;
+
+  drop trigger immutable_columns on oplog_entry;
 
   create trigger
     immutable_columns
   before
-  update on auth_password_method
-    for each row execute procedure immutable_columns('create_time');
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data');
 
-  create trigger
-    default_create_time_column
-  before
-  insert on auth_password_method
-    for each row execute procedure default_create_time();
+  alter table oplog_entry
+    drop constraint hmac_must_have_hmac_key_id,
+    drop column hmac,
+    drop column prev_hmac,
+    drop column hmac_key_id;
 
-  create trigger
-    update_time_column
-  before
-  update on auth_password_account
-    for each row execute procedure update_time_column();
+commit;
+
+`),
+	},
+	"migrations/105_oplog_hmac_chain.up.sql": {
+		name: "105_oplog_hmac_chain.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- Each oplog entry stores an hmac of its content and of the hmac of the
+  -- previous entry of its aggregate, made with a key derived from the oplog
+  -- key version hmac_key_id. The entries of an aggregate are written one at a
+  -- time since they redeem the aggregate's ticket, so they form a chain in
+  -- which altering, reordering or removing an entry can be detected. Entries
+  -- written before the chain was added have no hmac.
+  alter table oplog_entry
+    add column hmac_key_id text
+      constraint hmac_key_id_must_not_be_empty
+      check(length(trim(hmac_key_id)) > 0),
+    add column prev_hmac bytea,
+    add column hmac bytea,
+    add constraint hmac_must_have_hmac_key_id
+      check((hmac is null) = (hmac_key_id is null));
+
+  drop trigger immutable_columns on oplog_entry;
 
   create trigger
     immutable_columns
   before
-  update on auth_password_account
-    for each row execute procedure immutable_columns('create_time');
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data', 'hmac_key_id', 'prev_hmac', 'hmac');
 
-  create trigger
-    default_create_time_column
-  before
-  insert on auth_password_account
-    for each row execute procedure default_create_time();
-
-  -- The tickets for oplog are the subtypes not the base types because no updates
-  -- are done to any values in the base types.
-  insert into oplog_ticket
-    (name, version)
-  values
-    ('auth_password_method', 1),
-    ('auth_password_account', 1),
-    ('auth_password_credential', 1);
+  -- finds the previous entry of an aggregate when an entry is written
+  create index oplog_entry_aggregate_name_id_ix
+    on oplog_entry (aggregate_name, id desc);
 
 commit;
 
 `),
 	},
-	"migrations/13_auth_password_argon.down.sql": {
-		name: "13_auth_password_argon.down.sql",
+	"migrations/106_scope_read_only.down.sql": {
+		name: "106_scope_read_only.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table auth_password_argon2_cred;
-  drop table auth_password_argon2_conf;
+  alter table iam_scope
+    drop constraint global_scope_is_never_read_only,
+    drop column read_only;
 
 commit;
 
 `),
 	},
-	"migrations/13_auth_password_argon.up.sql": {
-		name: "13_auth_password_argon.up.sql",
+	"migrations/106_scope_read_only.up.sql": {
+		name: "106_scope_read_only.up.sql",
 		bytes: []byte(`
 begin;
 
-  create table auth_password_argon2_conf (
-    private_id wt_private_id primary key
-      references auth_password_conf (private_id)
-      on delete cascade
-      on update cascade,
-    password_method_id wt_public_id not null,
-    create_time wt_timestamp,
-    iterations int not null default 3
-      constraint iterations_must_be_greater_than_0
-      check(iterations > 0),
-    memory int not null default 65536
-      constraint memory_must_be_greater_than_0
-      check(memory > 0),
-    threads int not null default 1
-      constraint threads_must_be_greater_than_0
-      check(threads > 0),
-    -- salt_length unit is bytes
-    salt_length int not null default 32
-    -- minimum of 16 bytes (128 bits)
-      constraint salt_must_be_at_least_16_bytes
-      check(salt_length >= 16),
-    -- key_length unit is bytes
-    key_length int not null default 32
-    -- minimum of 16 bytes (128 bits)
-      constraint key_length_must_be_at_least_16_bytes
-      check(key_length >= 16),
-    unique(password_method_id, iterations, memory, threads, salt_length, key_length),
-    unique (password_method_id, private_id),
-    foreign key (password_method_id, private_id)
-      references auth_password_conf (password_method_id, private_id)
-      on delete cascade
-      on update cascade
-      deferrable initially deferred
-  );
+  -- read_only freezes a scope: resources in it, and in the projects of an
+  -- org, can't be created or changed until the flag is cleared. Updating only
+  -- the flag is still allowed on a read only scope. This is enforced when the
+  -- resources are vetted for write, not by the database. The global scope
+  -- can't be made read only.
+  alter table iam_scope
+    add column read_only boolean not null default false,
+    add constraint global_scope_is_never_read_only
+      check(type != 'global' or not read_only);
 
-  create or replace function
-    read_only_auth_password_argon2_conf()
-    returns trigger
-  as $$
-  begin
-    raise exception 'auth_password_argon2_conf is read-only';
-  end;
-  $$ language plpgsql;
+commit;
 
-  create trigger
-    read_only_auth_password_argon2_conf
-  before
-  update on auth_password_argon2_conf
-    for each row execute procedure read_only_auth_password_argon2_conf();
+`),
+	},
+	"migrations/107_session_egress_violation.down.sql": {
+		name: "107_session_egress_violation.down.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger
-    insert_auth_password_conf_subtype
-  before insert on auth_password_argon2_conf
-    for each row execute procedure insert_auth_password_conf_subtype();
+  drop table session_egress_violation;
 
-  create table auth_password_argon2_cred (
-    private_id wt_private_id primary key
-      references auth_password_credential (private_id)
+commit;
+
+`),
+	},
+	"migrations/107_session_egress_violation.up.sql": {
+		name: "107_session_egress_violation.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- session_egress_violation records the addresses workers refused to dial
+  -- for a session because they aren't its endpoint, the host and port in its
+  -- authorization data, or one of its allowed ports on that host. Rows are
+  -- only ever inserted.
+  create table session_egress_violation (
+    session_id wt_public_id not null
+      references session (public_id)
       on delete cascade
       on update cascade,
-    password_account_id wt_public_id not null,
-    password_conf_id wt_private_id,
-    -- NOTE(mgaffney): The password_method_id type is not wt_public_id because
-    -- the domain check is executed before the insert trigger which retrieves
-    -- the password_method_id causing an insert to fail.
-    password_method_id text not null,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    salt bytea not null -- cannot be changed unless derived_key is changed too
-      constraint salt_must_not_be_empty
-      check(length(salt) > 0),
-    derived_key bytea not null
-      constraint derived_key_must_not_be_empty
-      check(length(derived_key) > 0),
-    -- TODO: Make key_id a foreign key once we have DEKs
-    key_id text not null
-      constraint key_id_must_not_be_empty
-      check(length(trim(key_id)) > 0),
-    foreign key (password_method_id, password_conf_id)
-      references auth_password_argon2_conf (password_method_id, private_id)
+    -- the connection the address was dialed for, if any
+    connection_id wt_public_id
+      references session_connection (public_id)
       on delete cascade
       on update cascade,
-    foreign key (password_method_id, password_conf_id, password_account_id)
-      references auth_password_credential (password_method_id, password_conf_id, password_account_id)
-      on delete cascade
-      on update cascade
-      deferrable initially deferred
+    worker_id text not null
+      constraint worker_id_must_not_be_empty
+      check(length(trim(worker_id)) > 0),
+    endpoint text not null
+      constraint endpoint_must_not_be_empty
+      check(length(trim(endpoint)) > 0),
+    address text not null
+      constraint address_must_not_be_empty
+      check(length(trim(address)) > 0),
+    create_time wt_timestamp
   );
 
-  create trigger
-    insert_auth_password_credential_subtype
-  before insert on auth_password_argon2_cred
-    for each row execute procedure insert_auth_password_credential_subtype();
-
-  create trigger
-    update_auth_password_credential_subtype
-  after update on auth_password_argon2_cred
-    for each row execute procedure update_auth_password_credential_subtype();
-
-  create trigger
-    delete_auth_password_credential_subtype
-  after delete on auth_password_argon2_cred
-    for each row execute procedure delete_auth_password_credential_subtype();
-
-  --
-  -- triggers for time columns
-  --
+  create index session_egress_violation_session_id_ix
+    on session_egress_violation (session_id);
 
   create trigger
     immutable_columns
   before
-  update on auth_password_argon2_conf
-    for each row execute procedure immutable_columns('create_time');
+  update on session_egress_violation
+    for each row execute procedure immutable_columns('session_id', 'connection_id', 'worker_id', 'endpoint', 'address', 'create_time');
 
   create trigger
     default_create_time_column
   before
-  insert on auth_password_argon2_conf
+  insert on session_egress_violation
     for each row execute procedure default_create_time();
 
+commit;
+
+`),
+	},
+	"migrations/108_auth_token_api.down.sql": {
+		name: "108_auth_token_api.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table auth_token_grant;
+
+  drop view auth_token_account;
+  create view auth_token_account as
+        select at.public_id,
+               at.token,
+               at.auth_account_id,
+               at.create_time,
+               at.update_time,
+               at.approximate_last_access_time,
+               at.expiration_time,
+               aa.scope_id,
+               aa.iam_user_id,
+               aa.auth_method_id
+          from auth_token as at
+    inner join auth_account as aa
+            on at.auth_account_id = aa.public_id;
+
+  drop trigger immutable_columns on auth_token;
   create trigger
-    update_time_column
+    immutable_columns
   before
-  update on auth_password_argon2_cred
-    for each row execute procedure update_time_column();
+  update on auth_token
+    for each row execute procedure immutable_columns('public_id', 'auth_account_id', 'create_time');
+
+  alter table auth_token
+    drop column api;
+
+commit;
+
+`),
+	},
+	"migrations/108_auth_token_api.up.sql": {
+		name: "108_auth_token_api.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- api is true for auth tokens created for automation by admins, rather than
+  -- by authenticating. API tokens are restricted to their grants in
+  -- auth_token_grant, expire at the time set when they're created and aren't
+  -- deleted for being idle.
+  alter table auth_token
+    add column api boolean not null default false;
 
+  drop trigger immutable_columns on auth_token;
   create trigger
     immutable_columns
   before
-  update on auth_password_argon2_cred
-    for each row execute procedure immutable_columns('create_time');
+  update on auth_token
+    for each row execute procedure immutable_columns('public_id', 'auth_account_id', 'create_time', 'api');
+
+  create or replace view auth_token_account as
+        select at.public_id,
+               at.token,
+               at.auth_account_id,
+               at.create_time,
+               at.update_time,
+               at.approximate_last_access_time,
+               at.expiration_time,
+               aa.scope_id,
+               aa.iam_user_id,
+               aa.auth_method_id,
+               at.api
+          from auth_token as at
+    inner join auth_account as aa
+            on at.auth_account_id = aa.public_id;
+
+  -- auth_token_grant holds the grants an api token is restricted to. A
+  -- request made with the token is only allowed if both the grants of the
+  -- token's user and the token's grants allow it.
+  create table auth_token_grant (
+    auth_token_id wt_public_id not null
+      references auth_token(public_id)
+      on delete cascade
+      on update cascade,
+    raw_grant text not null
+      constraint raw_grant_must_not_be_empty
+      check(length(trim(raw_grant)) > 0),
+    create_time wt_timestamp,
+    primary key(auth_token_id, raw_grant)
+  );
 
   create trigger
     default_create_time_column
-  before
-  insert on auth_password_argon2_cred
+  before insert on auth_token_grant
     for each row execute procedure default_create_time();
 
-  -- The tickets for oplog are the subtypes not the base types because no updates
-  -- are done to any values in the base types.
-  insert into oplog_ticket
-    (name, version)
-  values
-    ('auth_password_argon2_conf', 1),
-    ('auth_password_argon2_cred', 1);
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_grant
+    for each row execute procedure immutable_columns('auth_token_id', 'raw_grant', 'create_time');
 
 commit;
 
 `),
 	},
-	"migrations/14_auth_password_views.down.sql": {
-		name: "14_auth_password_views.down.sql",
+	"migrations/109_iam_sign_in_cidr.down.sql": {
+		name: "109_iam_sign_in_cidr.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop view auth_password_current_conf;
-  drop view auth_password_conf_union;
+  drop table iam_sign_in_cidr;
 
 commit;
 
 `),
 	},
-	"migrations/14_auth_password_views.up.sql": {
-		name: "14_auth_password_views.up.sql",
+	"migrations/109_iam_sign_in_cidr.up.sql": {
+		name: "109_iam_sign_in_cidr.up.sql",
 		bytes: []byte(`
 begin;
 
-  -- auth_password_conf_union is a union of the configuration settings
-  -- of all supported key derivation functions.
-  -- It will be updated as new key derivation functions are supported.
-  create or replace view auth_password_conf_union as
-      -- Do not change the order of the columns when adding new configurations.
-      -- Union with new tables appending new columns as needed.
-      select c.password_method_id, c.private_id as password_conf_id, c.private_id,
-             'argon2' as conf_type,
-             c.iterations, c.memory, c.threads, c.salt_length, c.key_length
-        from auth_password_argon2_conf c;
+  -- iam_sign_in_cidr restricts the addresses the accounts of a scope's auth
+  -- methods can sign in from. Once a scope has any, its accounts can only
+  -- authenticate, and use the auth tokens they were issued, from an address
+  -- within one of them. A cidr with an auth_method_id only applies to the
+  -- accounts of that auth method.
+  create table iam_sign_in_cidr (
+    public_id wt_public_id primary key,
+    scope_id wt_scope_id not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    auth_method_id wt_public_id,
+    cidr cidr not null,
+    description text,
+    create_time wt_timestamp,
+    foreign key (scope_id, auth_method_id)
+      references auth_method (scope_id, public_id)
+      on delete cascade
+      on update cascade
+  );
 
-  -- auth_password_current_conf provides a view of the current password
-  -- configuration for each password auth method.
-  -- The view will be updated as new key derivation functions are supported
-  -- but the query to create the view should not need to be updated.
-  create or replace view auth_password_current_conf as
-      -- Rerun this query whenever auth_password_conf_union is updated.
-      select pm.min_login_name_length, pm.min_password_length, c.*
-        from auth_password_method pm
-  inner join auth_password_conf_union c
-          on pm.password_conf_id = c.password_conf_id;
+  create index iam_sign_in_cidr_scope_id_ix
+    on iam_sign_in_cidr (scope_id);
+
+  create trigger
+    default_create_time_column
+  before
+  insert on iam_sign_in_cidr
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_sign_in_cidr
+    for each row execute procedure immutable_columns('public_id', 'scope_id', 'auth_method_id', 'cidr', 'create_time');
 
 commit;
 
 `),
 	},
-	"migrations/20_host.down.sql": {
-		name: "20_host.down.sql",
+	"migrations/110_resource_count_indexes.down.sql": {
+		name: "110_resource_count_indexes.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table host_set;
-  drop table host;
-  drop table host_catalog;
+  drop index target_scope_id_ix;
 
-  drop function insert_host_set_subtype;
-  drop function insert_host_subtype;
-  drop function insert_host_catalog_subtype;
+commit;
 
-  delete
-    from oplog_ticket
-   where name in (
-          'host_catalog',
-          'host',
-          'host_set'
-        );
+`),
+	},
+	"migrations/110_resource_count_indexes.up.sql": {
+		name: "110_resource_count_indexes.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- Targets are counted per project from the base target table, which isn't
+  -- otherwise indexed by scope. Users are counted with
+  -- iam_user_scope_id_create_time_ix and sessions with
+  -- session_scope_id_create_time_ix.
+  create index target_scope_id_ix
+    on target (scope_id);
 
 commit;
 
 `),
 	},
-	"migrations/20_host.up.sql": {
-		name: "20_host.up.sql",
+	"migrations/111_target_authorization_valid.down.sql": {
+		name: "111_target_authorization_valid.down.sql",
 		bytes: []byte(`
 begin;
 
-/*
+  update session
+     set termination_reason = 'canceled'
+   where termination_reason = 'never-connected';
 
-                               ┌─────────────────┐
-                               │      host       │
-                               ├─────────────────┤
-                               │ public_id  (pk) │
-                               │ catalog_id (fk) │
-                               │                 │
-                               └─────────────────┘
-                                       ╲│╱
-                                        ○
-                                        │
-                                        ┼
-                                        ┼
-  ┌─────────────────┐          ┌─────────────────┐
-  │    iam_scope    │          │  host_catalog   │
-  ├─────────────────┤          ├─────────────────┤
-  │ public_id (pk)  │         ╱│ public_id (pk)  │
-  │                 │┼┼──────○─│ scope_id  (fk)  │
-  │                 │         ╲│                 │
-  └─────────────────┘          └─────────────────┘
-                                        ┼
-                                        ┼
-                                        │
-                                        ○
-                                       ╱│╲
-                               ┌─────────────────┐
-                               │    host_set     │
-                               ├─────────────────┤
-                               │ public_id  (pk) │
-                               │ catalog_id (fk) │
-                               │                 │
-                               └─────────────────┘
+  delete from session_termination_reason_enm
+   where name = 'never-connected';
 
-*/
+  alter table session_termination_reason_enm
+    drop constraint only_predefined_session_termination_reasons_allowed,
+    add constraint only_predefined_session_termination_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'terminated',
+          'network error',
+          'system error',
+          'connection limit',
+          'canceled'
+        )
+      );
 
-  -- host_catalog
-  create table host_catalog (
-    public_id wt_public_id
-      primary key,
-    scope_id wt_scope_id
-      not null
-      references iam_scope (public_id)
-      on delete cascade
-      on update cascade,
+  drop view search_resource;
+  drop view target_all_subtypes;
 
-    -- The order of columns is important for performance. See:
-    -- https://dba.stackexchange.com/questions/58970/enforcing-constraints-two-tables-away/58972#58972
-    -- https://dba.stackexchange.com/questions/27481/is-a-composite-index-also-good-for-queries-on-the-first-field
-    unique(scope_id, public_id)
-  );
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy,
+    endpoint_tls,
+    tls_server_name,
+    tls_client_certificate
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+  alter table target_tcp
+    drop column authorization_valid_seconds;
 
-  create trigger immutable_columns before update on host_catalog
-    for each row execute procedure immutable_columns('public_id', 'scope_id');
+commit;
 
-  -- insert_host_catalog_subtype() is a before insert trigger
-  -- function for subtypes of host_catalog
-  create or replace function insert_host_catalog_subtype()
-    returns trigger
-  as $$
-  begin
-    insert into host_catalog
-      (public_id, scope_id)
-    values
-      (new.public_id, new.scope_id);
-    return new;
-  end;
-  $$ language plpgsql;
+`),
+	},
+	"migrations/111_target_authorization_valid.up.sql": {
+		name: "111_target_authorization_valid.up.sql",
+		bytes: []byte(`
+begin;
 
-  -- delete_host_catalog_subtype() is an after delete trigger
-  -- function for subtypes of host_catalog
-  create or replace function delete_host_catalog_subtype()
-    returns trigger
-  as $$
-  begin
-    delete from host_catalog
-    where public_id = old.public_id;
-    return null; -- result is ignored since this is an after trigger
-  end;
-  $$ language plpgsql;
+  -- authorization_valid_seconds is how long a session authorized for the
+  -- target can stay pending before it's terminated with a reason of
+  -- never-connected. 0 means sessions can stay pending until they expire.
+  alter table target_tcp
+    add column authorization_valid_seconds integer not null default 0
+      constraint authorization_valid_seconds_must_not_be_negative
+      check(authorization_valid_seconds >= 0);
+
+  -- search_resource depends on target_all_subtypes, so the column is added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy,
+    endpoint_tls,
+    tls_server_name,
+    tls_client_certificate,
+    authorization_valid_seconds
+    from target_tcp;
+
+  alter table session_termination_reason_enm
+    drop constraint only_predefined_session_termination_reasons_allowed,
+    add constraint only_predefined_session_termination_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'terminated',
+          'network error',
+          'system error',
+          'connection limit',
+          'canceled',
+          'never-connected'
+        )
+      );
 
-  -- host
-  create table host (
-    public_id wt_public_id primary key,
-    catalog_id wt_public_id not null
-      references host_catalog (public_id)
-      on delete cascade
-      on update cascade,
-    unique(catalog_id, public_id)
-  );
+  insert into session_termination_reason_enm (name)
+  values
+    ('never-connected');
 
-  create trigger immutable_columns before update on host
-    for each row execute procedure immutable_columns('public_id', 'catalog_id');
+commit;
 
-  -- insert_host_subtype() is a before insert trigger
-  -- function for subtypes of host
-  create or replace function insert_host_subtype()
-    returns trigger
-  as $$
-  begin
-    insert into host
-      (public_id, catalog_id)
-    values
-      (new.public_id, new.catalog_id);
-    return new;
-  end;
-  $$ language plpgsql;
+`),
+	},
+	"migrations/112_worker_target.down.sql": {
+		name: "112_worker_target.down.sql",
+		bytes: []byte(`
+begin;
 
-  -- delete_host_subtype() is an after delete trigger
-  -- function for subtypes of host
-  create or replace function delete_host_subtype()
-    returns trigger
-  as $$
-  begin
-    delete from host
-    where public_id = old.public_id;
-    return null; -- result is ignored since this is an after trigger
-  end;
-  $$ language plpgsql;
+  drop table worker_target;
 
-  -- host_set
-  create table host_set (
-    public_id wt_public_id primary key,
-    catalog_id wt_public_id not null
-      references host_catalog (public_id)
+commit;
+
+`),
+	},
+	"migrations/112_worker_target.up.sql": {
+		name: "112_worker_target.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- worker_target records the targets workers register from the targets
+  -- defined in their configuration. Sessions for them are only proxied by
+  -- the worker which registered them. Each target's address is held by a
+  -- static host catalog of its own, which is deleted along with the target
+  -- when the worker deregisters its targets.
+  create table worker_target (
+    target_id wt_public_id primary key
+      references target_tcp(public_id)
       on delete cascade
       on update cascade,
-    unique(catalog_id, public_id)
+    worker_name text not null
+      constraint worker_name_must_not_be_empty
+      check(length(trim(worker_name)) > 0),
+    host_catalog_id wt_public_id not null
+      references static_host_catalog(public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp
   );
 
-  create trigger immutable_columns before update on host_set
-    for each row execute procedure immutable_columns('public_id', 'catalog_id');
+  create index worker_target_worker_name_ix
+    on worker_target (worker_name);
 
-  -- insert_host_set_subtype() is a before insert trigger
-  -- function for subtypes of host_set
-  create or replace function insert_host_set_subtype()
-    returns trigger
-  as $$
-  begin
-    insert into host_set
-      (public_id, catalog_id)
-    values
-      (new.public_id, new.catalog_id);
-    return new;
-  end;
-  $$ language plpgsql;
+commit;
 
-  -- delete_host_set_subtype() is an after delete trigger
-  -- function for subtypes of host_set
-  create or replace function delete_host_set_subtype()
-    returns trigger
-  as $$
-  begin
-    delete from host_set
-    where public_id = old.public_id;
-    return null; -- result is ignored since this is an after trigger
-  end;
-  $$ language plpgsql;
+`),
+	},
+	"migrations/113_worker_certificate_replaces.down.sql": {
+		name: "113_worker_certificate_replaces.down.sql",
+		bytes: []byte(`
+begin;
 
-  insert into oplog_ticket (name, version)
-  values
-    ('host_catalog', 1),
-    ('host', 1),
-    ('host_set', 1);
+  alter table worker_auth_certificate
+    drop column replaces_serial_number;
 
 commit;
 
 `),
 	},
-	"migrations/22_static_host.down.sql": {
-		name: "22_static_host.down.sql",
+	"migrations/113_worker_certificate_replaces.up.sql": {
+		name: "113_worker_certificate_replaces.up.sql",
 		bytes: []byte(`
 begin;
 
-  drop table static_host_set_member cascade;
-  drop table static_host_set cascade;
-  drop table static_host cascade;
-  drop table static_host_catalog cascade;
+  -- replaces_serial_number is the certificate a rotated certificate replaces.
+  -- The replaced certificate stays valid until the new one is first
+  -- presented, so a worker which never receives its new certificate can
+  -- rotate again with the one it has. When the new certificate is first
+  -- presented, the replaced certificate and any other certificates issued
+  -- to replace it are revoked and replaces_serial_number is cleared.
+  alter table worker_auth_certificate
+    add column replaces_serial_number text
+      references worker_auth_certificate(serial_number)
+      on delete cascade
+      on update cascade;
 
-  delete
-    from oplog_ticket
-   where name in (
-          'static_host_catalog',
-          'static_host',
-          'static_host_set',
-          'static_host_set_member'
-        );
+commit;
+
+`),
+	},
+	"migrations/114_target_key_id.down.sql": {
+		name: "114_target_key_id.down.sql",
+		bytes: []byte(`
+begin;
+
+  alter table target_tcp
+    drop constraint encrypted_fields_must_have_key_id,
+    add column tls_key_id text
+      constraint tls_key_id_must_not_be_empty
+      check(length(trim(tls_key_id)) > 0);
+
+  update target_tcp
+     set tls_key_id = key_id
+   where tls_ca_bundle is not null
+      or tls_client_private_key is not null;
+
+  update target_tcp
+     set key_id = null
+   where ssh_private_key is null;
+
+  alter table target_tcp
+    add constraint ssh_private_key_must_have_key_id
+      check((ssh_private_key is null) = (key_id is null));
 
 commit;
 
 `),
 	},
-	"migrations/22_static_host.up.sql": {
-		name: "22_static_host.up.sql",
+	"migrations/114_target_key_id.up.sql": {
+		name: "114_target_key_id.up.sql",
 		bytes: []byte(`
 begin;
 
-/*
+  -- The ssh private key, tls ca bundle and tls client private key of a tcp
+  -- target are all encrypted with the database key of its scope, and share
+  -- key_id as the id of the key version they were last encrypted with. Key
+  -- versions aren't removed, so those encrypted with an earlier version can
+  -- still be decrypted. key_id isn't cleared when they are, since the
+  -- others may still need it.
+  update target_tcp
+     set key_id = tls_key_id
+   where key_id is null
+     and tls_key_id is not null;
+
+  alter table target_tcp
+    drop constraint ssh_private_key_must_have_key_id,
+    drop column tls_key_id,
+    add constraint encrypted_fields_must_have_key_id
+      check(
+        key_id is not null
+        or (ssh_private_key is null
+            and tls_ca_bundle is null
+            and tls_client_private_key is null)
+      );
 
-  ┌─────────────────┐          ┌─────────────────────┐
-  │      host       │          │     static_host     │
-  ├─────────────────┤          ├─────────────────────┤
-  │ public_id  (pk) │          │ public_id  (pk)     │
-  │ catalog_id (fk) │┼┼──────○┼│ catalog_id (fk)     │┼┼─────────────────────┐
-  │                 │          │ address             │             ◀fk1      │
-  └─────────────────┘          └─────────────────────┘                       │
-          ╲│╱                            ╲│╱                                 │
-           ○                              ○                                  │
-           │                              │                                  │
-           ┼                              ┼                                  ○
-           ┼                              ┼                                 ╱│╲
-  ┌─────────────────┐          ┌─────────────────────┐          ┌────────────────────────┐
-  │  host_catalog   │          │ static_host_catalog │          │ static_host_set_member │
-  ├─────────────────┤          ├─────────────────────┤          ├────────────────────────┤
-  │ public_id (pk)  │          │ public_id (pk)      │          │ host_id    (pk,fk1)    │
-  │ scope_id  (fk)  │┼┼──────○┼│ scope_id  (fk)      │          │ set_id     (pk,fk2)    │
-  │                 │          │                     │          │ catalog_id (fk1,fk2)   │
-  └─────────────────┘          └─────────────────────┘          └────────────────────────┘
-           ┼                              ┼                                 ╲│╱
-           ┼                              ┼                                  ○
-           │                              │                                  │
-           ○                              ○                                  │
-          ╱│╲                            ╱│╲                                 │
-  ┌─────────────────┐          ┌─────────────────────┐                       │
-  │    host_set     │          │   static_host_set   │                       │
-  ├─────────────────┤          ├─────────────────────┤                       │
-  │ public_id  (pk) │          │ public_id  (pk)     │             ◀fk2      │
-  │ catalog_id (fk) │┼┼──────○┼│ catalog_id (fk)     │┼┼─────────────────────┘
-  │                 │          │                     │
-  └─────────────────┘          └─────────────────────┘
-
-*/
-
-  create table static_host_catalog (
-    public_id wt_public_id
-      primary key,
-    scope_id wt_scope_id
-      not null
-      references iam_scope (public_id)
-      on delete cascade
-      on update cascade,
-    name text,
-    description text,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    version wt_version,
-    foreign key (scope_id, public_id)
-      references host_catalog (scope_id, public_id)
-      on delete cascade
-      on update cascade,
-    unique(scope_id, name)
-  );
+commit;
 
-  create trigger update_version_column after update on static_host_catalog
-    for each row execute procedure update_version_column();
+`),
+	},
+	"migrations/115_advisory_locks.down.sql": {
+		name: "115_advisory_locks.down.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger update_time_column before update on static_host_catalog
-    for each row execute procedure update_time_column();
+  alter table job
+    add column run_expiration_time timestamp with time zone;
 
-  create trigger default_create_time_column before insert on static_host_catalog
-    for each row execute procedure default_create_time();
+  update job
+     set run_expiration_time = now()
+   where running_server_id is not null;
 
-  create trigger immutable_columns before update on static_host_catalog
-    for each row execute procedure immutable_columns('public_id', 'scope_id','create_time');
+  alter table job
+    add constraint run_expiration_time_must_be_set_when_running
+      check((running_server_id is null) = (run_expiration_time is null));
 
-  create trigger insert_host_catalog_subtype before insert on static_host_catalog
-    for each row execute procedure insert_host_catalog_subtype();
+  create table controller_lease (
+    name text primary key
+      constraint name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    holder text not null
+      constraint holder_must_not_be_empty
+      check(length(trim(holder)) > 0),
+    acquire_time wt_timestamp not null,
+    expiration_time wt_timestamp not null,
+    constraint expiration_time_must_be_after_acquire_time
+      check(expiration_time > acquire_time)
+  );
 
-  create trigger delete_host_catalog_subtype after delete on static_host_catalog
-    for each row execute procedure delete_host_catalog_subtype();
+commit;
 
-  create table static_host (
-    public_id wt_public_id primary key,
-    catalog_id wt_public_id not null
-      references static_host_catalog (public_id)
-      on delete cascade
-      on update cascade,
-    name text,
-    description text,
-    address text not null
-      constraint address_must_be_more_than_2_characters
-      check(length(trim(address)) > 2)
-      constraint address_must_be_less_than_256_characters
-      check(length(trim(address)) < 256),
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    version wt_version,
-    unique(catalog_id, name),
+`),
+	},
+	"migrations/115_advisory_locks.up.sql": {
+		name: "115_advisory_locks.up.sql",
+		bytes: []byte(`
+begin;
 
-    foreign key (catalog_id, public_id)
-      references host (catalog_id, public_id)
-      on delete cascade
-      on update cascade,
+  -- Controllers now coordinate through database advisory locks, which the
+  -- database releases when the holding connection is lost: the leader holds
+  -- the controller-leader lock instead of a lease, and a job's claim is a lock
+  -- held for the duration of the run instead of an expiring claim. The job's
+  -- running_server_id records which controller is running it.
+  drop table controller_lease;
 
-    -- The order of columns is important for performance. See:
-    -- https://dba.stackexchange.com/questions/58970/enforcing-constraints-two-tables-away/58972#58972
-    -- https://dba.stackexchange.com/questions/27481/is-a-composite-index-also-good-for-queries-on-the-first-field
-    unique(catalog_id, public_id)
-  );
+  alter table job
+    drop constraint run_expiration_time_must_be_set_when_running,
+    drop column run_expiration_time;
 
-  create trigger update_version_column after update on static_host
-    for each row execute procedure update_version_column();
+commit;
 
-  create trigger update_time_column before update on static_host
-    for each row execute procedure update_time_column();
+`),
+	},
+	"migrations/11_auth_token.down.sql": {
+		name: "11_auth_token.down.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger default_create_time_column before insert on static_host
-    for each row execute procedure default_create_time();
+  drop view auth_token_account cascade;
+  drop table auth_token cascade;
 
-  create trigger immutable_columns before update on static_host
-    for each row execute procedure immutable_columns('public_id', 'catalog_id','create_time');
+  drop function update_last_access_time cascade;
+  drop function immutable_auth_token_columns cascade;
+  drop function expire_time_not_older_than_token cascade;
 
-  create trigger insert_host_subtype before insert on static_host
-    for each row execute procedure insert_host_subtype();
+commit;
 
-  create trigger delete_host_subtype after delete on static_host
-    for each row execute procedure delete_host_subtype();
+`),
+	},
+	"migrations/11_auth_token.up.sql": {
+		name: "11_auth_token.up.sql",
+		bytes: []byte(`
+begin;
 
-  create table static_host_set (
+  -- an auth token belongs to 1 and only 1 auth account
+  -- an auth account can have 0 to many auth tokens
+  create table auth_token (
     public_id wt_public_id primary key,
-    catalog_id wt_public_id not null
-      references static_host_catalog (public_id)
+    token bytea not null unique,
+    -- TODO: Make key_id a foreign key once we have DEKs
+    key_id text not null
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    auth_account_id wt_public_id not null
+      references auth_account(public_id)
       on delete cascade
       on update cascade,
-    name text,
-    description text,
     create_time wt_timestamp,
     update_time wt_timestamp,
-    version wt_version,
-    unique(catalog_id, name),
-    foreign key (catalog_id, public_id)
-      references host_set (catalog_id, public_id)
-      on delete cascade
-      on update cascade,
-    unique(catalog_id, public_id)
+    -- This column is not updated every time this auth token is accessed.
+    -- It is updated after X minutes from the last time it was updated on
+    -- a per row basis.
+    approximate_last_access_time wt_timestamp
+      constraint last_access_time_must_not_be_after_expiration_time
+      check(
+        approximate_last_access_time <= expiration_time
+      ),
+    expiration_time wt_timestamp
+      constraint create_time_must_not_be_after_expiration_time
+      check(
+        create_time <= expiration_time
+      )
   );
 
-  create trigger update_version_column after update on static_host_set
-    for each row execute procedure update_version_column();
-
-  create trigger update_time_column before update on static_host_set
-    for each row execute procedure update_time_column();
-
-  create trigger default_create_time_column before insert on static_host_set
-    for each row execute procedure default_create_time();
-
-  create trigger immutable_columns before update on static_host_set
-    for each row execute procedure immutable_columns('public_id', 'catalog_id','create_time');
+  create view auth_token_account as
+        select at.public_id,
+               at.token,
+               at.auth_account_id,
+               at.create_time,
+               at.update_time,
+               at.approximate_last_access_time,
+               at.expiration_time,
+               aa.scope_id,
+               aa.iam_user_id,
+               aa.auth_method_id
+          from auth_token as at
+    inner join auth_account as aa
+            on at.auth_account_id = aa.public_id;
 
-  create trigger insert_host_set_subtype before insert on static_host_set
-    for each row execute procedure insert_host_set_subtype();
+  create or replace function
+    update_last_access_time()
+    returns trigger
+  as $$
+  begin
+    if new.approximate_last_access_time is distinct from old.approximate_last_access_time then
+      new.approximate_last_access_time = now();
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
 
-  create trigger delete_host_set_subtype after delete on static_host_set
-    for each row execute procedure delete_host_set_subtype();
+  comment on function
+    update_last_access_time()
+  is
+    'function used in before update triggers to properly set last_access_time columns';
 
-  create table static_host_set_member (
-    host_id wt_public_id not null,
-    set_id wt_public_id not null,
-    catalog_id wt_public_id not null,
-    primary key(host_id, set_id),
-    foreign key (catalog_id, host_id) -- fk1
-      references static_host (catalog_id, public_id)
-      on delete cascade
-      on update cascade,
-    foreign key (catalog_id, set_id) -- fk2
-      references static_host_set (catalog_id, public_id)
-      on delete cascade
-      on update cascade
-  );
+  create or replace function
+    immutable_auth_token_columns()
+    returns trigger
+  as $$
+  begin
+    if new.auth_account_id is distinct from old.auth_account_id then
+      raise exception 'auth_account_id is read-only';
+    end if;
+    if new.token is distinct from old.token then
+      raise exception 'token is read-only';
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
 
-  create trigger immutable_columns before update on static_host_set_member
-    for each row execute procedure immutable_columns('host_id', 'set_id', 'catalog_id');
+  comment on function
+    immutable_auth_token_columns()
+  is
+    'function used in before update triggers to make specific columns immutable';
 
-  create or replace function insert_static_host_set_member()
+  -- This allows the expiration to be calculated on the server side and still hold the constraint that
+  -- the expiration time cant be before the creation time of the auth token.
+  create or replace function
+    expire_time_not_older_than_token()
     returns trigger
   as $$
   begin
-    select static_host_set.catalog_id
-      into new.catalog_id
-    from static_host_set
-    where static_host_set.public_id = new.set_id;
+    if new.expiration_time < new.create_time then
+      new.expiration_time = new.create_time;
+    end if;
     return new;
   end;
   $$ language plpgsql;
 
-  create trigger insert_static_host_set_member before insert on static_host_set_member
-    for each row execute procedure insert_static_host_set_member();
-
-  insert into oplog_ticket (name, version)
-  values
-    ('static_host_catalog', 1),
-    ('static_host', 1),
-    ('static_host_set', 1),
-    ('static_host_set_member', 1);
+  comment on function
+      expire_time_not_older_than_token()
+  is
+    'function used in before insert triggers to ensure expiration time is not older than create time';
 
-commit;
+  create trigger
+    default_create_time_column
+  before insert on auth_token
+    for each row execute procedure default_create_time();
 
-`),
-	},
-	"migrations/30_keys.down.sql": {
-		name: "30_keys.down.sql",
-		bytes: []byte(`
-begin;
+  create trigger
+    expire_time_not_older_than_token
+  before insert on auth_token
+    for each row execute procedure expire_time_not_older_than_token();
 
-drop function kms_version_column cascade;
+  create trigger
+    update_time_column
+  before update on auth_token
+    for each row execute procedure update_time_column();
 
-commit;
+  create trigger
+    update_last_access_time
+  before update on auth_token
+    for each row execute procedure update_last_access_time();
 
-`),
-	},
-	"migrations/30_keys.up.sql": {
-		name: "30_keys.up.sql",
-		bytes: []byte(`
-begin;
 
--- kms_version_column() will increment the version column whenever row data
--- is inserted and should only be used in an before insert trigger.  This
--- function will overwrite any explicit values to the version column.
-create or replace function
-  kms_version_column()
-  returns trigger
-as $$
-declare 
-  _key_id text;
-  _max bigint; 
-begin
-  execute format('SELECT $1.%I', tg_argv[0]) into _key_id using new;
-  execute format('select max(version) + 1 from %I where %I = $1', tg_relid::regclass, tg_argv[0]) using _key_id into _max;
-  if _max is null then
-  	_max = 1;
-  end if;
-  new.version = _max;
-  return new;
-end;
-$$ language plpgsql;
+  create trigger
+    immutable_auth_token_columns
+  before update on auth_token
+    for each row execute procedure immutable_auth_token_columns();
+
+  create trigger 
+    immutable_columns
+  before
+  update on auth_token
+    for each row execute procedure immutable_columns('public_id', 'auth_account_id', 'create_time');
+
+commit;
 
-comment on function
-  kms_version_column()
-is
-  'function used in before insert triggers to properly set version columns for kms_* tables with a version column';
-  
-  commit;
 `),
 	},
-	"migrations/31_keys.down.sql": {
-		name: "31_keys.down.sql",
+	"migrations/12_auth_password.down.sql": {
+		name: "12_auth_password.down.sql",
 		bytes: []byte(`
 begin;
 
-drop table kms_root_key cascade;
-drop table kms_root_key_version cascade;
-drop table kms_database_key cascade;
-drop table kms_database_key_version cascade;
-drop table kms_oplog_key cascade;
-drop table kms_oplog_key_version cascade;
-drop table kms_session_key cascade;
-drop table kms_session_key_version cascade;
+  drop table auth_password_credential;
+  drop table auth_password_conf cascade;
+  drop table if exists auth_password_account;
+  drop table if exists auth_password_method;
+
+  drop function insert_auth_password_credential_subtype;
+  drop function insert_auth_password_conf_subtype;
 
 commit;
 
 `),
 	},
-	"migrations/31_keys.up.sql": {
-		name: "31_keys.up.sql",
+	"migrations/12_auth_password.up.sql": {
+		name: "12_auth_password.up.sql",
 		bytes: []byte(`
 begin;
 
 /*
-             ┌────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┐            
-             ├────────────────────────────────────────────────────────────────────────────────────────────┐                           ○            
-             ├────────────────────────────────────────────────────────────────┐                           ○                           ┼            
-             ├────────────────────────────────────┐                           ○                           ┼              ┌────────────────────────┐
-             │                                    ○                           ┼              ┌────────────────────────┐  │     kms_token_key      │
-             ┼                                    ┼              ┌────────────────────────┐  │    kms_session_key     │  ├────────────────────────┤
-┌────────────────────────┐           ┌────────────────────────┐  │     kms_oplog_key      │  ├────────────────────────┤  │private_id              │
-│      kms_root_key      │           │    kms_database_key    │  ├────────────────────────┤  │private_id              │  │root_key_id             │
-├────────────────────────┤           ├────────────────────────┤  │private_id              │  │root_key_id             │  │                        │
-│private_id              │           │private_id              │  │root_key_id             │  │                        │  │                        │
-│scope_id                │           │root_key_id             │  │                        │  │                        │  │                        │
-│                        │           │                        │  │                        │  │                        │  └────────────────────────┘
-└────────────────────────┘           └────────────────────────┘  └────────────────────────┘  └────────────────────────┘               ┼            
-             ┼                                    ┼                           ┼                           ┼                           │            
-             │                                    │                           │                           │                           │            
-             │                                    │                           │                           │                           │            
-             │                                    │                           │                           │                           │            
-             │                                    │                           │                           │                           │            
-             ┼                                    ┼                           ┼                           ┼                           │            
-            ╱│╲                                  ╱│╲                         ╱│╲                         ╱│╲                          ┼            
-┌────────────────────────┐           ┌────────────────────────┐  ┌────────────────────────┐  ┌────────────────────────┐              ╱│╲           
-│  kms_root_key_version  │           │kms_database_key_version│  │ kms_oplog_key_version  │  │kms_session_key_version │  ┌────────────────────────┐
-├────────────────────────┤           ├────────────────────────┤  ├────────────────────────┤  ├────────────────────────┤  │ kms_token_key_version  │
-│private_id              │           │private_id              │  │private_id              │  │private_id              │  ├────────────────────────┤
-│root_key_id             │           │database_key_id         │  │oplog_key_id            │  │session_key_id          │  │private_id              │
-│key                     │           │root_key_id             │  │root_key_id             │  │root_key_id             │  │token_key_id            │
-│version                 │           │key                     │  │key                     │  │key                     │  │root_key_id             │
-│                        │           │version                 │  │version                 │  │version                 │  │key                     │
-└────────────────────────┘           └────────────────────────┘  │                        │  │                        │  │version                 │
-             ┼                                    ┼              └────────────────────────┘  │                        │  │                        │
-             │                                    ○                           ┼              └────────────────────────┘  │                        │
-             ├────────────────────────────────────┘                           ○                           ┼              └────────────────────────┘
-             ├────────────────────────────────────────────────────────────────┘                           ○                           ┼            
-             ├────────────────────────────────────────────────────────────────────────────────────────────┘                           ○            
-             └────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┘
-*/
-
-create table kms_root_key (
-  private_id wt_private_id primary key,
-  scope_id wt_scope_id not null unique -- there can only be one root key for a scope.
-    references iam_scope(public_id) 
-    on delete cascade 
-    on update cascade,
-  create_time wt_timestamp
-);
 
- -- define the immutable fields for kms_root_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_root_key
-  for each row execute procedure immutable_columns('private_id', 'scope_id', 'create_time');
+       ┌────────────────┐                 ┌──────────────────────┐             ┌────────────────────────────┐
+       │  auth_method   │                 │ auth_password_method │             │     auth_password_conf     │
+       ├────────────────┤                 ├──────────────────────┤             ├────────────────────────────┤
+       │ public_id (pk) │                 │ public_id (pk,fk)    │            ╱│ private_id         (pk,fk) │
+       │ scope_id  (fk) │┼┼─────────────○┼│ scope_id  (fk)       │┼┼─────────○─│ password_method_id (fk)    │
+       │                │                 │ ...                  │            ╲│                            │
+       └────────────────┘                 └──────────────────────┘             └────────────────────────────┘
+                ┼                                     ┼                                       ┼
+                ┼                                     ┼                                       ┼
+                │                                     │                                       │
+                │ ▲fk1                                │ ▲fk1                                  │ ▲fk1
+                │                                     │                                       │
+                ○                                     ○                                       ○
+               ╱│╲                                   ╱│╲                                     ╱│╲
+  ┌──────────────────────────┐          ┌──────────────────────────┐          ┌───────────────────────────────┐
+  │       auth_account       │          │  auth_password_account   │          │   auth_password_credential    │
+  ├──────────────────────────┤          ├──────────────────────────┤          ├───────────────────────────────┤
+  │ public_id         (pk)   │          │ public_id      (pk,fk2)  │          │ private_id          (pk)      │
+  │ scope_id          (fk1)  │   ◀fk2   │ scope_id       (fk1,fk2) │   ◀fk2   │ password_method_id  (fk1,fk2) │
+  │ auth_method_id    (fk1)  │┼┼──────○┼│ auth_method_id (fk1,fk2) │┼┼──────○┼│ password_conf_id    (fk1)     │
+  │ iam_user_scope_id (fk2)  │          │ ...                      │          │ password_account_id (fk2)     │
+  │ iam_user_id       (fk2)  │          └──────────────────────────┘          └───────────────────────────────┘
+  └──────────────────────────┘
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_root_key
-  for each row execute procedure default_create_time();
+  An auth_method is a base type. An auth_password_method is an auth_method
+  subtype. For every row in auth_password_method there is one row in auth_method
+  with the same public_id and scope_id.
 
-create table kms_root_key_version (
-  private_id wt_private_id primary key,
-  root_key_id  wt_private_id not null
-    references kms_root_key(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(root_key_id, version)
-);
+  Similarly, an auth_account is a base type. An auth_password_account is an
+  auth_account subtype. For every row in auth_password_account there is one row
+  in auth_account with the same public_id, scope_id, and auth_method_id.
 
- -- define the immutable fields for kms_root_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_root_key_version
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'version', 'key', 'create_time');
+  Both auth_password_conf and auth_password_credential are base types. Each
+  password key derivation function will require a auth_password_conf and
+  auth_password_credential table.
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_root_key_version
-  for each row execute procedure default_create_time();
+  An auth_method can have 0 or 1 auth_password_method.
+  An auth_account can have 0 or 1 auth_password_account.
 
+  An auth_password_method belongs to 1 auth_method.
+  An auth_password_method can have 0 to many auth_password_accounts.
+  An auth_password_method can have 0 to many auth_password_confs.
 
-create trigger
-	kms_version_column
-before insert on kms_root_key_version
-	for each row execute procedure kms_version_column('root_key_id');
+  An auth_password_account belongs to 1 auth_account.
+  An auth_password_account belongs to 1 auth_password_method.
+  An auth_password_account can have 0 or 1 auth_password_credential.
 
-create table kms_database_key (
-  private_id wt_private_id primary key,
-  root_key_id wt_private_id not null unique -- there can be only one database dek per root key
-    references kms_root_key(private_id)
-    on delete cascade
-    on update cascade,
-  create_time wt_timestamp
-);
+  An auth_password_conf belongs to 1 auth_password_method.
+  An auth_password_conf can have 0 to many auth_password_credentials.
 
- -- define the immutable fields for kms_database_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_database_key
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+  An auth_password_credential belongs to 1 auth_password_account.
+  An auth_password_credential belongs to 1 auth_password_conf.
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_database_key
-  for each row execute procedure default_create_time();
+*/
 
-create table kms_database_key_version (
-  private_id wt_private_id primary key,
-  database_key_id wt_private_id not null
-    references kms_database_key(private_id) 
-    on delete cascade 
-    on update cascade, 
-  root_key_version_id wt_private_id not null
-    references kms_root_key_version(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(database_key_id, version)
-);
-
- -- define the immutable fields for kms_database_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_database_key_version
-  for each row execute procedure immutable_columns('private_id', 'database_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
-  
-create trigger 
-  default_create_time_column
-before
-insert on kms_database_key_version
-  for each row execute procedure default_create_time();
-
-create trigger
-	kms_version_column
-before insert on kms_database_key_version
-	for each row execute procedure kms_version_column('database_key_id');
+  create table auth_password_method (
+    public_id wt_public_id
+      primary key,
+    scope_id wt_scope_id
+      not null,
+    password_conf_id wt_private_id, -- FK to auth_password_conf added below
+    name text,
+    description text,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    min_login_name_length int
+      not null
+      default 3,
+    min_password_length int
+      not null
+      default 8,
+    version wt_version,
+    foreign key (scope_id, public_id)
+      references auth_method (scope_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(scope_id, name),
+    unique(scope_id, public_id)
+  );
 
-create table kms_oplog_key (
-  private_id wt_private_id primary key,
-  root_key_id wt_private_id not null unique -- there can be only one oplog dek per root key
-    references kms_root_key(private_id)
-    on delete cascade
-    on update cascade,
-  create_time wt_timestamp
-);
+  create trigger
+    update_version_column
+  after update on auth_password_method
+    for each row execute procedure update_version_column();
 
- -- define the immutable fields for kms_oplog_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_oplog_key
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+  create trigger
+    insert_auth_method_subtype
+  before insert on auth_password_method
+    for each row execute procedure insert_auth_method_subtype();
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_oplog_key
-  for each row execute procedure default_create_time();
+  create table auth_password_account (
+    public_id wt_public_id
+      primary key,
+    auth_method_id wt_public_id
+      not null,
+    -- NOTE(mgaffney): The scope_id type is not wt_scope_id because the domain
+    -- check is executed before the insert trigger which retrieves the scope_id
+    -- causing an insert to fail.
+    scope_id text not null,
+    name text,
+    description text,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    login_name text not null
+      constraint login_name_must_be_lowercase
+      check(lower(trim(login_name)) = login_name)
+      constraint login_name_must_not_be_empty
+      check(length(trim(login_name)) > 0),
+    version wt_version,
+    foreign key (scope_id, auth_method_id)
+      references auth_password_method (scope_id, public_id)
+      on delete cascade
+      on update cascade,
+    foreign key (scope_id, auth_method_id, public_id)
+      references auth_account (scope_id, auth_method_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(auth_method_id, name),
+    unique(auth_method_id, login_name),
+    unique(auth_method_id, public_id)
+  );
 
-create table kms_oplog_key_version (
-  private_id wt_private_id primary key,
-  oplog_key_id wt_private_id not null
-    references kms_oplog_key(private_id) 
-    on delete cascade 
-    on update cascade, 
-  root_key_version_id wt_private_id not null
-    references kms_root_key_version(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(oplog_key_id, version)
-);
+  create trigger
+    update_version_column
+  after update on auth_password_account
+    for each row execute procedure update_version_column();
 
- -- define the immutable fields for kms_oplog_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_oplog_key_version
-  for each row execute procedure immutable_columns('private_id', 'oplog_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
-  
-create trigger 
-  default_create_time_column
-before
-insert on kms_oplog_key_version
-  for each row execute procedure default_create_time();
+  create trigger
+    insert_auth_account_subtype
+  before insert on auth_password_account
+    for each row execute procedure insert_auth_account_subtype();
 
-create trigger
-	kms_version_column
-before insert on kms_oplog_key_version
-	for each row execute procedure kms_version_column('oplog_key_id');
+  create table auth_password_conf (
+    private_id wt_private_id
+      primary key,
+    password_method_id wt_public_id
+      not null
+      references auth_password_method (public_id)
+      on delete cascade
+      on update cascade
+      deferrable initially deferred,
+    unique(password_method_id, private_id)
+  );
 
-create table kms_session_key (
-  private_id wt_private_id primary key,
-  root_key_id wt_private_id not null unique -- there can be only one session dek per root key
-    references kms_root_key(private_id)
+  alter table auth_password_method
+    add constraint current_conf_fkey
+    foreign key (public_id, password_conf_id)
+    references auth_password_conf (password_method_id, private_id)
     on delete cascade
-    on update cascade,
-  create_time wt_timestamp
-);
-
- -- define the immutable fields for kms_oplog_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_session_key
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+    on update cascade
+    deferrable initially deferred;
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_session_key
-  for each row execute procedure default_create_time();
+  -- insert_auth_password_conf_subtype() is a trigger function for subtypes of
+  -- auth_password_conf
+  create or replace function
+    insert_auth_password_conf_subtype()
+    returns trigger
+  as $$
+  begin
+    insert into auth_password_conf
+      (private_id, password_method_id)
+    values
+      (new.private_id, new.password_method_id);
+    return new;
+  end;
+  $$ language plpgsql;
 
-create table kms_session_key_version (
-  private_id wt_private_id primary key,
-  session_key_id wt_private_id not null
-    references kms_session_key(private_id) 
-    on delete cascade 
-    on update cascade, 
-  root_key_version_id wt_private_id not null
-    references kms_root_key_version(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(session_key_id, version)
-);
+  create table auth_password_credential (
+    private_id wt_private_id
+      primary key,
+    password_account_id wt_public_id
+      not null
+      unique,
+    password_conf_id wt_private_id,
+    password_method_id wt_public_id
+      not null,
+    foreign key (password_method_id, password_conf_id)
+      references auth_password_conf (password_method_id, private_id)
+      on delete cascade
+      on update cascade,
+    foreign key (password_method_id, password_account_id)
+      references auth_password_account (auth_method_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(password_method_id, password_conf_id, password_account_id)
+  );
 
+  -- insert_auth_password_credential_subtype() is a trigger function for
+  -- subtypes of auth_password_credential
+  create or replace function
+    insert_auth_password_credential_subtype()
+    returns trigger
+  as $$
+  begin
 
- -- define the immutable fields for kms_session_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_session_key_version
-  for each row execute procedure immutable_columns('private_id', 'session_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
-  
-create trigger 
-  default_create_time_column
-before
-insert on kms_session_key_version
-  for each row execute procedure default_create_time();
+    select auth_password_account.auth_method_id
+      into new.password_method_id
+    from auth_password_account
+    where auth_password_account.public_id = new.password_account_id;
 
-create trigger
-	kms_version_column
-before insert on kms_session_key_version
-	for each row execute procedure kms_version_column('session_key_id');
+    insert into auth_password_credential
+      (private_id, password_account_id, password_conf_id, password_method_id)
+    values
+      (new.private_id, new.password_account_id, new.password_conf_id, new.password_method_id);
+    return new;
+  end;
+  $$ language plpgsql;
 
-create table kms_token_key (
-  private_id wt_private_id primary key,
-  root_key_id wt_private_id not null unique -- there can be only one token dek per root key
-    references kms_root_key(private_id)
-    on delete cascade
-    on update cascade,
-  create_time wt_timestamp
-);
+  -- update_auth_password_credential_subtype() is an after update trigger
+  -- function for subtypes of auth_password_credential
+  create or replace function
+    update_auth_password_credential_subtype()
+    returns trigger
+  as $$
+  begin
+    /*
+      The configuration id of a credential is updated when a credential is
+      rehashed during authentication.
+    */
+    if new.password_conf_id is distinct from old.password_conf_id then
+      update auth_password_credential
+         set password_conf_id = new.password_conf_id
+       where private_id = new.private_id;
+    end if;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
 
- -- define the immutable fields for kms_token_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_token_key
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+  -- delete_auth_password_credential_subtype() is an after delete trigger
+  -- function for subtypes of auth_password_credential
+  create or replace function
+    delete_auth_password_credential_subtype()
+    returns trigger
+  as $$
+  begin
+    delete
+      from auth_password_credential
+     where private_id = old.private_id;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_token_key
-  for each row execute procedure default_create_time();
+  --
+  -- triggers for time columns
+  --
 
-create table kms_token_key_version (
-  private_id wt_private_id primary key,
-  token_key_id wt_private_id not null
-    references kms_token_key(private_id) 
-    on delete cascade 
-    on update cascade, 
-  root_key_version_id wt_private_id not null
-    references kms_root_key_version(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(token_key_id, version)
-);
+  create trigger
+    update_time_column
+  before
+  update on auth_password_method
+    for each row execute procedure update_time_column();
 
- -- define the immutable fields for kms_token_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_token_key_version
-  for each row execute procedure immutable_columns('private_id', 'token_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
-  
-create trigger 
-  default_create_time_column
-before
-insert on kms_token_key_version
-  for each row execute procedure default_create_time();
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_method
+    for each row execute procedure immutable_columns('create_time');
 
-create trigger
-	kms_version_column
-before insert on kms_token_key_version
-	for each row execute procedure kms_version_column('token_key_id');
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_method
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before
+  update on auth_password_account
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_account
+    for each row execute procedure immutable_columns('create_time');
+
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_account
+    for each row execute procedure default_create_time();
 
+  -- The tickets for oplog are the subtypes not the base types because no updates
+  -- are done to any values in the base types.
   insert into oplog_ticket
     (name, version)
   values
-    ('kms_root_key', 1),
-    ('kms_root_key_version', 1);
-    
+    ('auth_password_method', 1),
+    ('auth_password_account', 1),
+    ('auth_password_credential', 1);
+
 commit;
+
 `),
 	},
-	"migrations/40_targets.down.sql": {
-		name: "40_targets.down.sql",
+	"migrations/13_auth_password_argon.down.sql": {
+		name: "13_auth_password_argon.down.sql",
 		bytes: []byte(`
 begin;
 
-drop function insert_target_subtype;
-drop function delete_target_subtype;
-drop function target_scope_valid;
-drop function target_host_set_scope_valid
+  drop table auth_password_argon2_cred;
+  drop table auth_password_argon2_conf;
 
 commit;
 
 `),
 	},
-	"migrations/40_targets.up.sql": {
-		name: "40_targets.up.sql",
+	"migrations/13_auth_password_argon.up.sql": {
+		name: "13_auth_password_argon.up.sql",
 		bytes: []byte(`
 begin;
 
+  create table auth_password_argon2_conf (
+    private_id wt_private_id primary key
+      references auth_password_conf (private_id)
+      on delete cascade
+      on update cascade,
+    password_method_id wt_public_id not null,
+    create_time wt_timestamp,
+    iterations int not null default 3
+      constraint iterations_must_be_greater_than_0
+      check(iterations > 0),
+    memory int not null default 65536
+      constraint memory_must_be_greater_than_0
+      check(memory > 0),
+    threads int not null default 1
+      constraint threads_must_be_greater_than_0
+      check(threads > 0),
+    -- salt_length unit is bytes
+    salt_length int not null default 32
+    -- minimum of 16 bytes (128 bits)
+      constraint salt_must_be_at_least_16_bytes
+      check(salt_length >= 16),
+    -- key_length unit is bytes
+    key_length int not null default 32
+    -- minimum of 16 bytes (128 bits)
+      constraint key_length_must_be_at_least_16_bytes
+      check(key_length >= 16),
+    unique(password_method_id, iterations, memory, threads, salt_length, key_length),
+    unique (password_method_id, private_id),
+    foreign key (password_method_id, private_id)
+      references auth_password_conf (password_method_id, private_id)
+      on delete cascade
+      on update cascade
+      deferrable initially deferred
+  );
 
--- insert_target_subtype() is a before insert trigger
--- function for subtypes of target
-create or replace function
-  insert_target_subtype()
-  returns trigger
-as $$
-begin
-  insert into target
-    (public_id, scope_id)
-  values
-    (new.public_id, new.scope_id);
-  return new;
-end;
-$$ language plpgsql;
+  create or replace function
+    read_only_auth_password_argon2_conf()
+    returns trigger
+  as $$
+  begin
+    raise exception 'auth_password_argon2_conf is read-only';
+  end;
+  $$ language plpgsql;
 
--- delete_target_subtype() is an after delete trigger
--- function for subtypes of host
-create or replace function delete_target_subtype()
-  returns trigger
-as $$
-begin
-  delete from target
-  where 
-    public_id = old.public_id;
-  return null; -- result is ignored since this is an after trigger
-end;
-$$ language plpgsql;
+  create trigger
+    read_only_auth_password_argon2_conf
+  before
+  update on auth_password_argon2_conf
+    for each row execute procedure read_only_auth_password_argon2_conf();
 
--- target_scope_valid() is a before insert trigger function for target
-create or replace function
-  target_scope_valid()
-  returns trigger
-as $$
-declare scope_type text;
-begin
-  -- Fetch the type of scope
-  select isc.type from iam_scope isc where isc.public_id = new.scope_id into scope_type;
-  if scope_type = 'project' then
-    return new;
-  end if;
-  raise exception 'invalid target scope type % (must be project)', scope_type;
-end;
-$$ language plpgsql;
+  create trigger
+    insert_auth_password_conf_subtype
+  before insert on auth_password_argon2_conf
+    for each row execute procedure insert_auth_password_conf_subtype();
 
--- target_host_set_scope_valid() is a before insert trigger function for target_host_set 
-create or replace function
-  target_host_set_scope_valid()
-  returns trigger
-as $$
-begin
-    perform from
-      host_catalog hc,
-      host_set hs,
-      target t,
-      iam_scope s
-    where
-      hc.public_id = hs.catalog_id and 
-      hc.scope_id = t.scope_id and
-      t.public_id = new.target_id;
-if not found then
-  raise exception 'target scope and host set scope are not equal';
-end if;
-return new;
-end;
-$$ language plpgsql;
+  create table auth_password_argon2_cred (
+    private_id wt_private_id primary key
+      references auth_password_credential (private_id)
+      on delete cascade
+      on update cascade,
+    password_account_id wt_public_id not null,
+    password_conf_id wt_private_id,
+    -- NOTE(mgaffney): The password_method_id type is not wt_public_id because
+    -- the domain check is executed before the insert trigger which retrieves
+    -- the password_method_id causing an insert to fail.
+    password_method_id text not null,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    salt bytea not null -- cannot be changed unless derived_key is changed too
+      constraint salt_must_not_be_empty
+      check(length(salt) > 0),
+    derived_key bytea not null
+      constraint derived_key_must_not_be_empty
+      check(length(derived_key) > 0),
+    -- TODO: Make key_id a foreign key once we have DEKs
+    key_id text not null
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    foreign key (password_method_id, password_conf_id)
+      references auth_password_argon2_conf (password_method_id, private_id)
+      on delete cascade
+      on update cascade,
+    foreign key (password_method_id, password_conf_id, password_account_id)
+      references auth_password_credential (password_method_id, password_conf_id, password_account_id)
+      on delete cascade
+      on update cascade
+      deferrable initially deferred
+  );
+
+  create trigger
+    insert_auth_password_credential_subtype
+  before insert on auth_password_argon2_cred
+    for each row execute procedure insert_auth_password_credential_subtype();
+
+  create trigger
+    update_auth_password_credential_subtype
+  after update on auth_password_argon2_cred
+    for each row execute procedure update_auth_password_credential_subtype();
+
+  create trigger
+    delete_auth_password_credential_subtype
+  after delete on auth_password_argon2_cred
+    for each row execute procedure delete_auth_password_credential_subtype();
+
+  --
+  -- triggers for time columns
+  --
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_argon2_conf
+    for each row execute procedure immutable_columns('create_time');
+
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_argon2_conf
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before
+  update on auth_password_argon2_cred
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_argon2_cred
+    for each row execute procedure immutable_columns('create_time');
+
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_argon2_cred
+    for each row execute procedure default_create_time();
+
+  -- The tickets for oplog are the subtypes not the base types because no updates
+  -- are done to any values in the base types.
+  insert into oplog_ticket
+    (name, version)
+  values
+    ('auth_password_argon2_conf', 1),
+    ('auth_password_argon2_cred', 1);
 
 commit;
+
 `),
 	},
-	"migrations/41_targets.down.sql": {
-		name: "41_targets.down.sql",
+	"migrations/14_auth_password_views.down.sql": {
+		name: "14_auth_password_views.down.sql",
 		bytes: []byte(`
 begin;
 
-drop table target cascade;
-drop table target_host_set cascade;
-drop table target_tcp;
-drop view target_all_subtypes;
-drop view target_host_set_catalog;
-
-
-delete
-from oplog_ticket
-where name in (
-        'target_tcp'
-    );
+  drop view auth_password_current_conf;
+  drop view auth_password_conf_union;
 
 commit;
+
 `),
 	},
-	"migrations/41_targets.up.sql": {
-		name: "41_targets.up.sql",
+	"migrations/14_auth_password_views.up.sql": {
+		name: "14_auth_password_views.up.sql",
 		bytes: []byte(`
-/*
-                                   ┌─────────────────┐
-┌─────────────────┐                │   target_tcp    │
-│     target      │                ├─────────────────┤
-├─────────────────┤                │public_id        │
-│public_id        │┼─────────────○┼│scope_id         │
-│scope_id         │                │default_port     │
-│                 │                │name (not null)  │
-└─────────────────┘                │description      │
-         ┼                         └─────────────────┘
-         │                                            
-         ○                                            
-        ╱│╲                                           
-┌─────────────────┐                                   
-│ target_host_set │                                   
-├─────────────────┤                                   
-│target_id        │                                   
-│host_set_id      │                                   
-│                 │                                   
-└─────────────────┘                                   
-        ╲│╱                                           
-         ○                                            
-         │                                            
-         │                                            
-         ┼                                            
-┌─────────────────┐                                   
-│    host_set     │                                   
-├─────────────────┤                                   
-│public_id        │                                   
-│catalog_id       │                                   
-│                 │                                   
-└─────────────────┘                                                                            
+begin;
 
-*/
+  -- auth_password_conf_union is a union of the configuration settings
+  -- of all supported key derivation functions.
+  -- It will be updated as new key derivation functions are supported.
+  create or replace view auth_password_conf_union as
+      -- Do not change the order of the columns when adding new configurations.
+      -- Union with new tables appending new columns as needed.
+      select c.password_method_id, c.private_id as password_conf_id, c.private_id,
+             'argon2' as conf_type,
+             c.iterations, c.memory, c.threads, c.salt_length, c.key_length
+        from auth_password_argon2_conf c;
 
-begin;
+  -- auth_password_current_conf provides a view of the current password
+  -- configuration for each password auth method.
+  -- The view will be updated as new key derivation functions are supported
+  -- but the query to create the view should not need to be updated.
+  create or replace view auth_password_current_conf as
+      -- Rerun this query whenever auth_password_conf_union is updated.
+      select pm.min_login_name_length, pm.min_password_length, c.*
+        from auth_password_method pm
+  inner join auth_password_conf_union c
+          on pm.password_conf_id = c.password_conf_id;
 
-create table target (
-  public_id wt_public_id primary key,
-  scope_id wt_scope_id not null 
-    references iam_scope(public_id) 
-    on delete cascade 
-    on update cascade,
-  create_time wt_timestamp
-);
+commit;
 
-create trigger 
-  immutable_columns
-before
-update on target
-  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+`),
+	},
+	"migrations/20_host.down.sql": {
+		name: "20_host.down.sql",
+		bytes: []byte(`
+begin;
 
-create trigger 
-  default_create_time_column
-before
-insert on target
-  for each row execute procedure default_create_time();
+  drop table host_set;
+  drop table host;
+  drop table host_catalog;
 
-create trigger 
-  target_scope_valid
-before insert on target
-  for each row execute procedure target_scope_valid();
+  drop function insert_host_set_subtype;
+  drop function insert_host_subtype;
+  drop function insert_host_catalog_subtype;
 
+  delete
+    from oplog_ticket
+   where name in (
+          'host_catalog',
+          'host',
+          'host_set'
+        );
 
-create table target_host_set(
-  target_id wt_public_id
-    references target(public_id)
-    on delete cascade
-    on update cascade,
-  host_set_id wt_public_id
-    references host_set(public_id)
-    on delete cascade
-    on update cascade,
-  primary key(target_id, host_set_id),
-  create_time wt_timestamp
-);
+commit;
 
-create trigger 
-  immutable_columns
-before
-update on target_host_set
-  for each row execute procedure immutable_columns('target_id', 'host_set_id', 'create_time');
+`),
+	},
+	"migrations/20_host.up.sql": {
+		name: "20_host.up.sql",
+		bytes: []byte(`
+begin;
 
-create trigger 
-  target_host_set_scope_valid
-before
-insert on target_host_set
-  for each row execute procedure target_host_set_scope_valid();
+/*
 
-create table target_tcp (
-  public_id wt_public_id primary key
-    references target(public_id)
-    on delete cascade
-    on update cascade,
-  scope_id wt_scope_id not null 
-    references iam_scope(public_id) 
-    on delete cascade 
-    on update cascade,
-  name text not null, -- name is not optional for a target subtype
-  description text,
-  default_port int, -- default_port can be null
-   -- max duration of the session in seconds.
-   -- default is 8 hours
-  session_max_seconds int not null default 28800
-    constraint session_max_seconds_must_be_greater_than_0
-    check(session_max_seconds > 0),
-  -- limit on number of session connections allowed. -1 equals no limit
-  session_connection_limit int not null default 1
-    constraint session_connection_limit_must_be_greater_than_0_or_negative_1
-    check(session_connection_limit > 0 or session_connection_limit = -1),
-  create_time wt_timestamp,
-  update_time wt_timestamp,
-  version wt_version,
-  unique(scope_id, name) -- name must be unique within a scope
-);
-
-create trigger
-  insert_target_subtype
-before insert on target_tcp
-  for each row execute procedure insert_target_subtype();
+                               ┌─────────────────┐
+                               │      host       │
+                               ├─────────────────┤
+                               │ public_id  (pk) │
+                               │ catalog_id (fk) │
+                               │                 │
+                               └─────────────────┘
+                                       ╲│╱
+                                        ○
+                                        │
+                                        ┼
+                                        ┼
+  ┌─────────────────┐          ┌─────────────────┐
+  │    iam_scope    │          │  host_catalog   │
+  ├─────────────────┤          ├─────────────────┤
+  │ public_id (pk)  │         ╱│ public_id (pk)  │
+  │                 │┼┼──────○─│ scope_id  (fk)  │
+  │                 │         ╲│                 │
+  └─────────────────┘          └─────────────────┘
+                                        ┼
+                                        ┼
+                                        │
+                                        ○
+                                       ╱│╲
+                               ┌─────────────────┐
+                               │    host_set     │
+                               ├─────────────────┤
+                               │ public_id  (pk) │
+                               │ catalog_id (fk) │
+                               │                 │
+                               └─────────────────┘
 
-create trigger
-  delete_target_subtype
-after delete on target_tcp
-  for each row execute procedure delete_target_subtype();
+*/
 
- -- define the immutable fields for target 
-create trigger 
-  immutable_columns
-before
-update on target_tcp
-  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+  -- host_catalog
+  create table host_catalog (
+    public_id wt_public_id
+      primary key,
+    scope_id wt_scope_id
+      not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
 
-create trigger
-  update_version_column
-after update on target_tcp
-  for each row execute procedure update_version_column();
+    -- The order of columns is important for performance. See:
+    -- https://dba.stackexchange.com/questions/58970/enforcing-constraints-two-tables-away/58972#58972
+    -- https://dba.stackexchange.com/questions/27481/is-a-composite-index-also-good-for-queries-on-the-first-field
+    unique(scope_id, public_id)
+  );
 
-create trigger
-  update_time_column
-before update on target_tcp
-  for each row execute procedure update_time_column();
+  create trigger immutable_columns before update on host_catalog
+    for each row execute procedure immutable_columns('public_id', 'scope_id');
 
-create trigger 
-  default_create_time_column
-before
-insert on target_tcp
-  for each row execute procedure default_create_time();
+  -- insert_host_catalog_subtype() is a before insert trigger
+  -- function for subtypes of host_catalog
+  create or replace function insert_host_catalog_subtype()
+    returns trigger
+  as $$
+  begin
+    insert into host_catalog
+      (public_id, scope_id)
+    values
+      (new.public_id, new.scope_id);
+    return new;
+  end;
+  $$ language plpgsql;
 
-create trigger 
-  target_scope_valid
-before insert on target_tcp
-  for each row execute procedure target_scope_valid();
+  -- delete_host_catalog_subtype() is an after delete trigger
+  -- function for subtypes of host_catalog
+  create or replace function delete_host_catalog_subtype()
+    returns trigger
+  as $$
+  begin
+    delete from host_catalog
+    where public_id = old.public_id;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
 
+  -- host
+  create table host (
+    public_id wt_public_id primary key,
+    catalog_id wt_public_id not null
+      references host_catalog (public_id)
+      on delete cascade
+      on update cascade,
+    unique(catalog_id, public_id)
+  );
 
--- target_all_subtypes is a union of all target subtypes 
-create view target_all_subtypes
-as 
-select 
-  public_id, 
-  scope_id, 
-  name, 
-  description, 
-  default_port, 
-  session_max_seconds,
-  session_connection_limit,
-  version, 
-  create_time,
-  update_time,
-  'tcp' as type
-  from target_tcp;
+  create trigger immutable_columns before update on host
+    for each row execute procedure immutable_columns('public_id', 'catalog_id');
 
-create view target_set
-as
-select 
-  hs.public_id,
-  hs.catalog_id,
-  ths.target_id
-from
-  target_host_set ths,
-  host_set hs
-where
-  hs.public_id = ths.host_set_id;
+  -- insert_host_subtype() is a before insert trigger
+  -- function for subtypes of host
+  create or replace function insert_host_subtype()
+    returns trigger
+  as $$
+  begin
+    insert into host
+      (public_id, catalog_id)
+    values
+      (new.public_id, new.catalog_id);
+    return new;
+  end;
+  $$ language plpgsql;
 
-insert into oplog_ticket
-  (name, version)
-values
-  ('target_tcp', 1);
+  -- delete_host_subtype() is an after delete trigger
+  -- function for subtypes of host
+  create or replace function delete_host_subtype()
+    returns trigger
+  as $$
+  begin
+    delete from host
+    where public_id = old.public_id;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
 
-commit;
+  -- host_set
+  create table host_set (
+    public_id wt_public_id primary key,
+    catalog_id wt_public_id not null
+      references host_catalog (public_id)
+      on delete cascade
+      on update cascade,
+    unique(catalog_id, public_id)
+  );
 
-`),
-	},
-	"migrations/50_session.down.sql": {
-		name: "50_session.down.sql",
-		bytes: []byte(`
-begin;
+  create trigger immutable_columns before update on host_set
+    for each row execute procedure immutable_columns('public_id', 'catalog_id');
 
-  drop table session_state;
-  drop table session_state_enm;
-  drop table session;
-  drop table session_termination_reason_enm;
-  drop function insert_session_state;
-  drop function insert_new_session_state;
-  drop function insert_session;
-  drop function update_session_state_on_termination_reason;
-  drop function insert_session_state;
+  -- insert_host_set_subtype() is a before insert trigger
+  -- function for subtypes of host_set
+  create or replace function insert_host_set_subtype()
+    returns trigger
+  as $$
+  begin
+    insert into host_set
+      (public_id, catalog_id)
+    values
+      (new.public_id, new.catalog_id);
+    return new;
+  end;
+  $$ language plpgsql;
 
+  -- delete_host_set_subtype() is an after delete trigger
+  -- function for subtypes of host_set
+  create or replace function delete_host_set_subtype()
+    returns trigger
+  as $$
+  begin
+    delete from host_set
+    where public_id = old.public_id;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
 
-  delete
-  from oplog_ticket
-  where name in (
-          'session'
-      );
+  insert into oplog_ticket (name, version)
+  values
+    ('host_catalog', 1),
+    ('host', 1),
+    ('host_set', 1);
 
 commit;
 
 `),
 	},
-	"migrations/50_session.up.sql": {
-		name: "50_session.up.sql",
+	"migrations/22_static_host.down.sql": {
+		name: "22_static_host.down.sql",
 		bytes: []byte(`
 begin;
 
-/*
-
-             ┌─────────────────┐               ┌─────────────────┐   ┌─────────────────┐
-             │  iam_scope_org  │               │    iam_user     │   │   auth_token    │
-             ├─────────────────┤               ├─────────────────┤   ├─────────────────┤
-             │ public_id  (pk) │               │ public_id (pk)  │   │ public_id  (pk) │
-             │                 │               │                 │   │                 │
-             └─────────────────┘               └─────────────────┘   └─────────────────┘
-                 ▲fk7 ┼                            ▲fk1 ┼                ▲fk6 ┼
-                      ┼                                 ┼                     ┼
-                      ├─────────────────────────────────┴─────────────────────┘
-                      │
-                      ○
-                     ╱│╲
-        ┌──────────────────────────┐          ┌─────────────────┐   ┌─────────────────┐
-        │         session          │╲  fk3▶   │     server      │   │     target      │
-        ├──────────────────────────┤─○──────○┼├─────────────────┤   ├─────────────────┤
-        │ public_id          (pk)  │╱         │ private_id (pk) │   │ public_id  (pk) │
-        │ user_id            (fk1) │          │ type       (pk) │   │                 │
-        │ host_id            (fk2) │          └─────────────────┘   └─────────────────┘
-        │ server_id          (fk3) │                                    ▲fk4 ┼
-        │ server_type        (fk3) │╲                                        ┼
-        │ target_id          (fk4) │─○─────────────────┬─────────────────────┤
-        │ host_set_id        (fk5) │╱                  ┼                     ┼
-        │ auth_token_id      (fk6) │              ▼fk5 ┼                ▼fk2 ┼
-        │ scope_id           (fk7) │          ┌─────────────────┐   ┌─────────────────┐
-        │ termination_reason (fk8) │          │    host_set     │   │      host       │
-        └──────────────────────────┘          ├─────────────────┤   ├─────────────────┤
-                 ▲fk1 ┼           ╲│╱         │ public_id  (pk) │   │ public_id  (pk) │
-                      ┼            ○          │                 │   │                 │
-                      │            │          └─────────────────┘   └─────────────────┘
-                      │            │
-                      └─┐          │
-                        │          │            ┌───────────────────────────────┐
-                        │          │            │session_termination_reason_enm │
-                        │          │     fk8▶   ├───────────────────────────────┤
-                        ┼          └──────────○┼│ name                          │
-                       ╱│╲                      └───────────────────────────────┘
-  ┌──────────────────────────────────────────┐
-  │              session_state               │
-  ├──────────────────────────────────────────┤┼○┐
-  │ session_id        (pk,fk1,fk2,unq1,unq2) │  │◀fk2
-  │ state             (fk3)                  │  │
-  │ previous_end_time (fk2,unq1)             │┼○┘
-  │ start_time        (pk)                   │
-  │ end_time          (unq2)                 │
-  └──────────────────────────────────────────┘
-                       ╲│╱
-                        ○
-                        │
-                        │
-                        ┼
-                  ▼fk3  ┼
-        ┌───────────────────────────────┐
-        │       session_state_enm       │
-        ├───────────────────────────────┤
-        │ name                          │
-        └───────────────────────────────┘
+  drop table static_host_set_member cascade;
+  drop table static_host_set cascade;
+  drop table static_host cascade;
+  drop table static_host_catalog cascade;
 
-*/
+  delete
+    from oplog_ticket
+   where name in (
+          'static_host_catalog',
+          'static_host',
+          'static_host_set',
+          'static_host_set_member'
+        );
 
-  create table session_termination_reason_enm (
-    name text primary key
-      constraint only_predefined_session_termination_reasons_allowed
-      check (
-        name in (
-          'unknown',
-          'timed out',
-          'closed by end-user',
-          'terminated',
-          'network error',
-          'system error',
-          'connection limit',
-          'canceled'
-        )
-      )
-  );
+commit;
 
-  insert into session_termination_reason_enm (name)
-  values
-    ('unknown'),
-    ('timed out'),
-    ('closed by end-user'),
-    ('terminated'),
-    ('network error'),
-    ('system error'),
-    ('connection limit'),
-    ('canceled');
+`),
+	},
+	"migrations/22_static_host.up.sql": {
+		name: "22_static_host.up.sql",
+		bytes: []byte(`
+begin;
 
-  create table session (
-    public_id wt_public_id primary key,
-    -- the user of the session
-    user_id text -- fk1
-      -- not using the wt_user_id domain type because it is marked 'not null'
-      references iam_user (public_id)
-      on delete set null
-      on update cascade,
-    -- the host the user is connected to via this session
-    host_id wt_public_id -- fk2
-      references host (public_id)
-      on delete set null
-      on update cascade,
-    -- the worker proxying the connection between the user and the host
-    server_id text, -- fk3
-    server_type text,-- fk3
-    foreign key (server_id, server_type)
-      references server (private_id, type)
-      on delete set null
-      on update cascade,
-    -- the target the host was chosen from and the user was authorized to
-    -- connect to
-    target_id wt_public_id -- fk4
-      references target (public_id)
-      on delete set null
-      on update cascade,
-    -- the host set the host was chosen from and the user was authorized to
-    -- connect to via the target
-    host_set_id wt_public_id -- fk5
-      references host_set (public_id)
-      on delete set null
-      on update cascade,
-    -- the auth token of the user when this session was created
-    auth_token_id wt_public_id -- fk6
-      references auth_token (public_id)
-      on delete set null
-      on update cascade,
-    -- the project which owns this session
-    scope_id wt_scope_id -- fk7
-      references iam_scope_project (scope_id)
-      on delete set null
-      on update cascade,
-    -- Certificate to use when connecting (or if using custom certs, to
-	  -- serve as the "login"). Raw DER bytes.  
-    certificate bytea not null,
-    -- after this time the connection will be expired, e.g. forcefully terminated
-    expiration_time wt_timestamp, -- maybe null
-    -- limit on number of session connections allowed.  default of 0 equals no limit
-    connection_limit int not null default 1
-      constraint connection_limit_must_be_greater_than_0_or_negative_1
-      check(connection_limit > 0 or connection_limit = -1), 
-    -- trust of first use token 
-    tofu_token bytea, -- will be null when session is first created
-    -- the reason this session ended (null until terminated)
-     -- TODO: Make key_id a foreign key once we have DEKs
-    key_id text, -- will be null on insert
-      -- references kms_database_key_version(private_id) 
-      -- on delete restrict
-      -- on update cascade,
-    termination_reason text -- fk8
-      references session_termination_reason_enm (name)
-      on delete restrict
+/*
+
+  ┌─────────────────┐          ┌─────────────────────┐
+  │      host       │          │     static_host     │
+  ├─────────────────┤          ├─────────────────────┤
+  │ public_id  (pk) │          │ public_id  (pk)     │
+  │ catalog_id (fk) │┼┼──────○┼│ catalog_id (fk)     │┼┼─────────────────────┐
+  │                 │          │ address             │             ◀fk1      │
+  └─────────────────┘          └─────────────────────┘                       │
+          ╲│╱                            ╲│╱                                 │
+           ○                              ○                                  │
+           │                              │                                  │
+           ┼                              ┼                                  ○
+           ┼                              ┼                                 ╱│╲
+  ┌─────────────────┐          ┌─────────────────────┐          ┌────────────────────────┐
+  │  host_catalog   │          │ static_host_catalog │          │ static_host_set_member │
+  ├─────────────────┤          ├─────────────────────┤          ├────────────────────────┤
+  │ public_id (pk)  │          │ public_id (pk)      │          │ host_id    (pk,fk1)    │
+  │ scope_id  (fk)  │┼┼──────○┼│ scope_id  (fk)      │          │ set_id     (pk,fk2)    │
+  │                 │          │                     │          │ catalog_id (fk1,fk2)   │
+  └─────────────────┘          └─────────────────────┘          └────────────────────────┘
+           ┼                              ┼                                 ╲│╱
+           ┼                              ┼                                  ○
+           │                              │                                  │
+           ○                              ○                                  │
+          ╱│╲                            ╱│╲                                 │
+  ┌─────────────────┐          ┌─────────────────────┐                       │
+  │    host_set     │          │   static_host_set   │                       │
+  ├─────────────────┤          ├─────────────────────┤                       │
+  │ public_id  (pk) │          │ public_id  (pk)     │             ◀fk2      │
+  │ catalog_id (fk) │┼┼──────○┼│ catalog_id (fk)     │┼┼─────────────────────┘
+  │                 │          │                     │
+  └─────────────────┘          └─────────────────────┘
+
+*/
+
+  create table static_host_catalog (
+    public_id wt_public_id
+      primary key,
+    scope_id wt_scope_id
+      not null
+      references iam_scope (public_id)
+      on delete cascade
       on update cascade,
-    version wt_version,
+    name text,
+    description text,
     create_time wt_timestamp,
     update_time wt_timestamp,
-    endpoint text -- not part of the warehouse, used to send info to the worker
+    version wt_version,
+    foreign key (scope_id, public_id)
+      references host_catalog (scope_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(scope_id, name)
   );
 
-  create trigger 
-    immutable_columns
-  before
-  update on session
-    for each row execute procedure immutable_columns('public_id', 'certificate', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
-  
-  -- session table has some cascades of FK to null, so we need to be careful
-  -- which columns trigger an update of the version column
-  create trigger 
-    update_version_column 
-  after update of version, termination_reason, key_id, tofu_token, server_id, server_type on session
+  create trigger update_version_column after update on static_host_catalog
     for each row execute procedure update_version_column();
-    
-  create trigger 
-    update_time_column 
-  before update on session 
+
+  create trigger update_time_column before update on static_host_catalog
     for each row execute procedure update_time_column();
-    
-  create trigger 
-    default_create_time_column
-  before
-  insert on session
+
+  create trigger default_create_time_column before insert on static_host_catalog
     for each row execute procedure default_create_time();
 
-  create or replace function
-    insert_session()
-    returns trigger
-  as $$
-  begin
-    case 
-      when new.user_id is null then
-        raise exception 'user_id is null';
-      when new.host_id is null then
-        raise exception 'host_id is null';
-      when new.target_id is null then
-        raise exception 'target_id is null';
-      when new.host_set_id is null then
-        raise exception 'host_set_id is null';
-      when new.auth_token_id is null then
-        raise exception 'auth_token_id is null';
-      when new.scope_id is null then
-        raise exception 'scope_id is null';
-      when new.endpoint is null then
-        raise exception 'endpoint is null';
-    else
-    end case;
+  create trigger immutable_columns before update on static_host_catalog
+    for each row execute procedure immutable_columns('public_id', 'scope_id','create_time');
+
+  create trigger insert_host_catalog_subtype before insert on static_host_catalog
+    for each row execute procedure insert_host_catalog_subtype();
+
+  create trigger delete_host_catalog_subtype after delete on static_host_catalog
+    for each row execute procedure delete_host_catalog_subtype();
+
+  create table static_host (
+    public_id wt_public_id primary key,
+    catalog_id wt_public_id not null
+      references static_host_catalog (public_id)
+      on delete cascade
+      on update cascade,
+    name text,
+    description text,
+    address text not null
+      constraint address_must_be_more_than_2_characters
+      check(length(trim(address)) > 2)
+      constraint address_must_be_less_than_256_characters
+      check(length(trim(address)) < 256),
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    version wt_version,
+    unique(catalog_id, name),
+
+    foreign key (catalog_id, public_id)
+      references host (catalog_id, public_id)
+      on delete cascade
+      on update cascade,
+
+    -- The order of columns is important for performance. See:
+    -- https://dba.stackexchange.com/questions/58970/enforcing-constraints-two-tables-away/58972#58972
+    -- https://dba.stackexchange.com/questions/27481/is-a-composite-index-also-good-for-queries-on-the-first-field
+    unique(catalog_id, public_id)
+  );
+
+  create trigger update_version_column after update on static_host
+    for each row execute procedure update_version_column();
+
+  create trigger update_time_column before update on static_host
+    for each row execute procedure update_time_column();
+
+  create trigger default_create_time_column before insert on static_host
+    for each row execute procedure default_create_time();
+
+  create trigger immutable_columns before update on static_host
+    for each row execute procedure immutable_columns('public_id', 'catalog_id','create_time');
+
+  create trigger insert_host_subtype before insert on static_host
+    for each row execute procedure insert_host_subtype();
+
+  create trigger delete_host_subtype after delete on static_host
+    for each row execute procedure delete_host_subtype();
+
+  create table static_host_set (
+    public_id wt_public_id primary key,
+    catalog_id wt_public_id not null
+      references static_host_catalog (public_id)
+      on delete cascade
+      on update cascade,
+    name text,
+    description text,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    version wt_version,
+    unique(catalog_id, name),
+    foreign key (catalog_id, public_id)
+      references host_set (catalog_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(catalog_id, public_id)
+  );
+
+  create trigger update_version_column after update on static_host_set
+    for each row execute procedure update_version_column();
+
+  create trigger update_time_column before update on static_host_set
+    for each row execute procedure update_time_column();
+
+  create trigger default_create_time_column before insert on static_host_set
+    for each row execute procedure default_create_time();
+
+  create trigger immutable_columns before update on static_host_set
+    for each row execute procedure immutable_columns('public_id', 'catalog_id','create_time');
+
+  create trigger insert_host_set_subtype before insert on static_host_set
+    for each row execute procedure insert_host_set_subtype();
+
+  create trigger delete_host_set_subtype after delete on static_host_set
+    for each row execute procedure delete_host_set_subtype();
+
+  create table static_host_set_member (
+    host_id wt_public_id not null,
+    set_id wt_public_id not null,
+    catalog_id wt_public_id not null,
+    primary key(host_id, set_id),
+    foreign key (catalog_id, host_id) -- fk1
+      references static_host (catalog_id, public_id)
+      on delete cascade
+      on update cascade,
+    foreign key (catalog_id, set_id) -- fk2
+      references static_host_set (catalog_id, public_id)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger immutable_columns before update on static_host_set_member
+    for each row execute procedure immutable_columns('host_id', 'set_id', 'catalog_id');
+
+  create or replace function insert_static_host_set_member()
+    returns trigger
+  as $$
+  begin
+    select static_host_set.catalog_id
+      into new.catalog_id
+    from static_host_set
+    where static_host_set.public_id = new.set_id;
     return new;
   end;
   $$ language plpgsql;
 
-  create trigger 
-    insert_session
-  before insert on session
-    for each row execute procedure insert_session();
+  create trigger insert_static_host_set_member before insert on static_host_set_member
+    for each row execute procedure insert_static_host_set_member();
+
+  insert into oplog_ticket (name, version)
+  values
+    ('static_host_catalog', 1),
+    ('static_host', 1),
+    ('static_host_set', 1),
+    ('static_host_set_member', 1);
+
+commit;
+
+`),
+	},
+	"migrations/30_keys.down.sql": {
+		name: "30_keys.down.sql",
+		bytes: []byte(`
+begin;
+
+drop function kms_version_column cascade;
+
+commit;
+
+`),
+	},
+	"migrations/30_keys.up.sql": {
+		name: "30_keys.up.sql",
+		bytes: []byte(`
+begin;
+
+-- kms_version_column() will increment the version column whenever row data
+-- is inserted and should only be used in an before insert trigger.  This
+-- function will overwrite any explicit values to the version column.
+create or replace function
+  kms_version_column()
+  returns trigger
+as $$
+declare 
+  _key_id text;
+  _max bigint; 
+begin
+  execute format('SELECT $1.%I', tg_argv[0]) into _key_id using new;
+  execute format('select max(version) + 1 from %I where %I = $1', tg_relid::regclass, tg_argv[0]) using _key_id into _max;
+  if _max is null then
+  	_max = 1;
+  end if;
+  new.version = _max;
+  return new;
+end;
+$$ language plpgsql;
+
+comment on function
+  kms_version_column()
+is
+  'function used in before insert triggers to properly set version columns for kms_* tables with a version column';
+  
+  commit;
+`),
+	},
+	"migrations/31_keys.down.sql": {
+		name: "31_keys.down.sql",
+		bytes: []byte(`
+begin;
+
+drop table kms_root_key cascade;
+drop table kms_root_key_version cascade;
+drop table kms_database_key cascade;
+drop table kms_database_key_version cascade;
+drop table kms_oplog_key cascade;
+drop table kms_oplog_key_version cascade;
+drop table kms_session_key cascade;
+drop table kms_session_key_version cascade;
+
+commit;
+
+`),
+	},
+	"migrations/31_keys.up.sql": {
+		name: "31_keys.up.sql",
+		bytes: []byte(`
+begin;
+
+/*
+             ┌────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┐            
+             ├────────────────────────────────────────────────────────────────────────────────────────────┐                           ○            
+             ├────────────────────────────────────────────────────────────────┐                           ○                           ┼            
+             ├────────────────────────────────────┐                           ○                           ┼              ┌────────────────────────┐
+             │                                    ○                           ┼              ┌────────────────────────┐  │     kms_token_key      │
+             ┼                                    ┼              ┌────────────────────────┐  │    kms_session_key     │  ├────────────────────────┤
+┌────────────────────────┐           ┌────────────────────────┐  │     kms_oplog_key      │  ├────────────────────────┤  │private_id              │
+│      kms_root_key      │           │    kms_database_key    │  ├────────────────────────┤  │private_id              │  │root_key_id             │
+├────────────────────────┤           ├────────────────────────┤  │private_id              │  │root_key_id             │  │                        │
+│private_id              │           │private_id              │  │root_key_id             │  │                        │  │                        │
+│scope_id                │           │root_key_id             │  │                        │  │                        │  │                        │
+│                        │           │                        │  │                        │  │                        │  └────────────────────────┘
+└────────────────────────┘           └────────────────────────┘  └────────────────────────┘  └────────────────────────┘               ┼            
+             ┼                                    ┼                           ┼                           ┼                           │            
+             │                                    │                           │                           │                           │            
+             │                                    │                           │                           │                           │            
+             │                                    │                           │                           │                           │            
+             │                                    │                           │                           │                           │            
+             ┼                                    ┼                           ┼                           ┼                           │            
+            ╱│╲                                  ╱│╲                         ╱│╲                         ╱│╲                          ┼            
+┌────────────────────────┐           ┌────────────────────────┐  ┌────────────────────────┐  ┌────────────────────────┐              ╱│╲           
+│  kms_root_key_version  │           │kms_database_key_version│  │ kms_oplog_key_version  │  │kms_session_key_version │  ┌────────────────────────┐
+├────────────────────────┤           ├────────────────────────┤  ├────────────────────────┤  ├────────────────────────┤  │ kms_token_key_version  │
+│private_id              │           │private_id              │  │private_id              │  │private_id              │  ├────────────────────────┤
+│root_key_id             │           │database_key_id         │  │oplog_key_id            │  │session_key_id          │  │private_id              │
+│key                     │           │root_key_id             │  │root_key_id             │  │root_key_id             │  │token_key_id            │
+│version                 │           │key                     │  │key                     │  │key                     │  │root_key_id             │
+│                        │           │version                 │  │version                 │  │version                 │  │key                     │
+└────────────────────────┘           └────────────────────────┘  │                        │  │                        │  │version                 │
+             ┼                                    ┼              └────────────────────────┘  │                        │  │                        │
+             │                                    ○                           ┼              └────────────────────────┘  │                        │
+             ├────────────────────────────────────┘                           ○                           ┼              └────────────────────────┘
+             ├────────────────────────────────────────────────────────────────┘                           ○                           ┼            
+             ├────────────────────────────────────────────────────────────────────────────────────────────┘                           ○            
+             └────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┘
+*/
+
+create table kms_root_key (
+  private_id wt_private_id primary key,
+  scope_id wt_scope_id not null unique -- there can only be one root key for a scope.
+    references iam_scope(public_id) 
+    on delete cascade 
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_root_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_root_key
+  for each row execute procedure immutable_columns('private_id', 'scope_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_root_key
+  for each row execute procedure default_create_time();
+
+create table kms_root_key_version (
+  private_id wt_private_id primary key,
+  root_key_id  wt_private_id not null
+    references kms_root_key(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(root_key_id, version)
+);
+
+ -- define the immutable fields for kms_root_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_root_key_version
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'version', 'key', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_root_key_version
+  for each row execute procedure default_create_time();
+
+
+create trigger
+	kms_version_column
+before insert on kms_root_key_version
+	for each row execute procedure kms_version_column('root_key_id');
+
+create table kms_database_key (
+  private_id wt_private_id primary key,
+  root_key_id wt_private_id not null unique -- there can be only one database dek per root key
+    references kms_root_key(private_id)
+    on delete cascade
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_database_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_database_key
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_database_key
+  for each row execute procedure default_create_time();
+
+create table kms_database_key_version (
+  private_id wt_private_id primary key,
+  database_key_id wt_private_id not null
+    references kms_database_key(private_id) 
+    on delete cascade 
+    on update cascade, 
+  root_key_version_id wt_private_id not null
+    references kms_root_key_version(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(database_key_id, version)
+);
+
+ -- define the immutable fields for kms_database_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_database_key_version
+  for each row execute procedure immutable_columns('private_id', 'database_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
+  
+create trigger 
+  default_create_time_column
+before
+insert on kms_database_key_version
+  for each row execute procedure default_create_time();
+
+create trigger
+	kms_version_column
+before insert on kms_database_key_version
+	for each row execute procedure kms_version_column('database_key_id');
+
+create table kms_oplog_key (
+  private_id wt_private_id primary key,
+  root_key_id wt_private_id not null unique -- there can be only one oplog dek per root key
+    references kms_root_key(private_id)
+    on delete cascade
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_oplog_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_oplog_key
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_oplog_key
+  for each row execute procedure default_create_time();
+
+create table kms_oplog_key_version (
+  private_id wt_private_id primary key,
+  oplog_key_id wt_private_id not null
+    references kms_oplog_key(private_id) 
+    on delete cascade 
+    on update cascade, 
+  root_key_version_id wt_private_id not null
+    references kms_root_key_version(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(oplog_key_id, version)
+);
+
+ -- define the immutable fields for kms_oplog_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_oplog_key_version
+  for each row execute procedure immutable_columns('private_id', 'oplog_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
+  
+create trigger 
+  default_create_time_column
+before
+insert on kms_oplog_key_version
+  for each row execute procedure default_create_time();
+
+create trigger
+	kms_version_column
+before insert on kms_oplog_key_version
+	for each row execute procedure kms_version_column('oplog_key_id');
+
+create table kms_session_key (
+  private_id wt_private_id primary key,
+  root_key_id wt_private_id not null unique -- there can be only one session dek per root key
+    references kms_root_key(private_id)
+    on delete cascade
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_oplog_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_session_key
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_session_key
+  for each row execute procedure default_create_time();
+
+create table kms_session_key_version (
+  private_id wt_private_id primary key,
+  session_key_id wt_private_id not null
+    references kms_session_key(private_id) 
+    on delete cascade 
+    on update cascade, 
+  root_key_version_id wt_private_id not null
+    references kms_root_key_version(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(session_key_id, version)
+);
+
+
+ -- define the immutable fields for kms_session_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_session_key_version
+  for each row execute procedure immutable_columns('private_id', 'session_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
+  
+create trigger 
+  default_create_time_column
+before
+insert on kms_session_key_version
+  for each row execute procedure default_create_time();
+
+create trigger
+	kms_version_column
+before insert on kms_session_key_version
+	for each row execute procedure kms_version_column('session_key_id');
+
+create table kms_token_key (
+  private_id wt_private_id primary key,
+  root_key_id wt_private_id not null unique -- there can be only one token dek per root key
+    references kms_root_key(private_id)
+    on delete cascade
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_token_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_token_key
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_token_key
+  for each row execute procedure default_create_time();
+
+create table kms_token_key_version (
+  private_id wt_private_id primary key,
+  token_key_id wt_private_id not null
+    references kms_token_key(private_id) 
+    on delete cascade 
+    on update cascade, 
+  root_key_version_id wt_private_id not null
+    references kms_root_key_version(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(token_key_id, version)
+);
+
+ -- define the immutable fields for kms_token_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_token_key_version
+  for each row execute procedure immutable_columns('private_id', 'token_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
+  
+create trigger 
+  default_create_time_column
+before
+insert on kms_token_key_version
+  for each row execute procedure default_create_time();
+
+create trigger
+	kms_version_column
+before insert on kms_token_key_version
+	for each row execute procedure kms_version_column('token_key_id');
+
+  insert into oplog_ticket
+    (name, version)
+  values
+    ('kms_root_key', 1),
+    ('kms_root_key_version', 1);
+    
+commit;
+`),
+	},
+	"migrations/40_targets.down.sql": {
+		name: "40_targets.down.sql",
+		bytes: []byte(`
+begin;
+
+drop function insert_target_subtype;
+drop function delete_target_subtype;
+drop function target_scope_valid;
+drop function target_host_set_scope_valid
+
+commit;
+
+`),
+	},
+	"migrations/40_targets.up.sql": {
+		name: "40_targets.up.sql",
+		bytes: []byte(`
+begin;
+
+
+-- insert_target_subtype() is a before insert trigger
+-- function for subtypes of target
+create or replace function
+  insert_target_subtype()
+  returns trigger
+as $$
+begin
+  insert into target
+    (public_id, scope_id)
+  values
+    (new.public_id, new.scope_id);
+  return new;
+end;
+$$ language plpgsql;
+
+-- delete_target_subtype() is an after delete trigger
+-- function for subtypes of host
+create or replace function delete_target_subtype()
+  returns trigger
+as $$
+begin
+  delete from target
+  where 
+    public_id = old.public_id;
+  return null; -- result is ignored since this is an after trigger
+end;
+$$ language plpgsql;
+
+-- target_scope_valid() is a before insert trigger function for target
+create or replace function
+  target_scope_valid()
+  returns trigger
+as $$
+declare scope_type text;
+begin
+  -- Fetch the type of scope
+  select isc.type from iam_scope isc where isc.public_id = new.scope_id into scope_type;
+  if scope_type = 'project' then
+    return new;
+  end if;
+  raise exception 'invalid target scope type % (must be project)', scope_type;
+end;
+$$ language plpgsql;
+
+-- target_host_set_scope_valid() is a before insert trigger function for target_host_set 
+create or replace function
+  target_host_set_scope_valid()
+  returns trigger
+as $$
+begin
+    perform from
+      host_catalog hc,
+      host_set hs,
+      target t,
+      iam_scope s
+    where
+      hc.public_id = hs.catalog_id and 
+      hc.scope_id = t.scope_id and
+      t.public_id = new.target_id;
+if not found then
+  raise exception 'target scope and host set scope are not equal';
+end if;
+return new;
+end;
+$$ language plpgsql;
+
+commit;
+`),
+	},
+	"migrations/41_targets.down.sql": {
+		name: "41_targets.down.sql",
+		bytes: []byte(`
+begin;
+
+drop table target cascade;
+drop table target_host_set cascade;
+drop table target_tcp;
+drop view target_all_subtypes;
+drop view target_host_set_catalog;
+
+
+delete
+from oplog_ticket
+where name in (
+        'target_tcp'
+    );
+
+commit;
+`),
+	},
+	"migrations/41_targets.up.sql": {
+		name: "41_targets.up.sql",
+		bytes: []byte(`
+/*
+                                   ┌─────────────────┐
+┌─────────────────┐                │   target_tcp    │
+│     target      │                ├─────────────────┤
+├─────────────────┤                │public_id        │
+│public_id        │┼─────────────○┼│scope_id         │
+│scope_id         │                │default_port     │
+│                 │                │name (not null)  │
+└─────────────────┘                │description      │
+         ┼                         └─────────────────┘
+         │                                            
+         ○                                            
+        ╱│╲                                           
+┌─────────────────┐                                   
+│ target_host_set │                                   
+├─────────────────┤                                   
+│target_id        │                                   
+│host_set_id      │                                   
+│                 │                                   
+└─────────────────┘                                   
+        ╲│╱                                           
+         ○                                            
+         │                                            
+         │                                            
+         ┼                                            
+┌─────────────────┐                                   
+│    host_set     │                                   
+├─────────────────┤                                   
+│public_id        │                                   
+│catalog_id       │                                   
+│                 │                                   
+└─────────────────┘                                                                            
+
+*/
+
+begin;
+
+create table target (
+  public_id wt_public_id primary key,
+  scope_id wt_scope_id not null 
+    references iam_scope(public_id) 
+    on delete cascade 
+    on update cascade,
+  create_time wt_timestamp
+);
+
+create trigger 
+  immutable_columns
+before
+update on target
+  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on target
+  for each row execute procedure default_create_time();
+
+create trigger 
+  target_scope_valid
+before insert on target
+  for each row execute procedure target_scope_valid();
+
+
+create table target_host_set(
+  target_id wt_public_id
+    references target(public_id)
+    on delete cascade
+    on update cascade,
+  host_set_id wt_public_id
+    references host_set(public_id)
+    on delete cascade
+    on update cascade,
+  primary key(target_id, host_set_id),
+  create_time wt_timestamp
+);
+
+create trigger 
+  immutable_columns
+before
+update on target_host_set
+  for each row execute procedure immutable_columns('target_id', 'host_set_id', 'create_time');
+
+create trigger 
+  target_host_set_scope_valid
+before
+insert on target_host_set
+  for each row execute procedure target_host_set_scope_valid();
+
+create table target_tcp (
+  public_id wt_public_id primary key
+    references target(public_id)
+    on delete cascade
+    on update cascade,
+  scope_id wt_scope_id not null 
+    references iam_scope(public_id) 
+    on delete cascade 
+    on update cascade,
+  name text not null, -- name is not optional for a target subtype
+  description text,
+  default_port int, -- default_port can be null
+   -- max duration of the session in seconds.
+   -- default is 8 hours
+  session_max_seconds int not null default 28800
+    constraint session_max_seconds_must_be_greater_than_0
+    check(session_max_seconds > 0),
+  -- limit on number of session connections allowed. -1 equals no limit
+  session_connection_limit int not null default 1
+    constraint session_connection_limit_must_be_greater_than_0_or_negative_1
+    check(session_connection_limit > 0 or session_connection_limit = -1),
+  create_time wt_timestamp,
+  update_time wt_timestamp,
+  version wt_version,
+  unique(scope_id, name) -- name must be unique within a scope
+);
+
+create trigger
+  insert_target_subtype
+before insert on target_tcp
+  for each row execute procedure insert_target_subtype();
+
+create trigger
+  delete_target_subtype
+after delete on target_tcp
+  for each row execute procedure delete_target_subtype();
+
+ -- define the immutable fields for target 
+create trigger 
+  immutable_columns
+before
+update on target_tcp
+  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+
+create trigger
+  update_version_column
+after update on target_tcp
+  for each row execute procedure update_version_column();
+
+create trigger
+  update_time_column
+before update on target_tcp
+  for each row execute procedure update_time_column();
+
+create trigger 
+  default_create_time_column
+before
+insert on target_tcp
+  for each row execute procedure default_create_time();
+
+create trigger 
+  target_scope_valid
+before insert on target_tcp
+  for each row execute procedure target_scope_valid();
+
+
+-- target_all_subtypes is a union of all target subtypes 
+create view target_all_subtypes
+as 
+select 
+  public_id, 
+  scope_id, 
+  name, 
+  description, 
+  default_port, 
+  session_max_seconds,
+  session_connection_limit,
+  version, 
+  create_time,
+  update_time,
+  'tcp' as type
+  from target_tcp;
+
+create view target_set
+as
+select 
+  hs.public_id,
+  hs.catalog_id,
+  ths.target_id
+from
+  target_host_set ths,
+  host_set hs
+where
+  hs.public_id = ths.host_set_id;
+
+insert into oplog_ticket
+  (name, version)
+values
+  ('target_tcp', 1);
+
+commit;
+
+`),
+	},
+	"migrations/50_session.down.sql": {
+		name: "50_session.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table session_state;
+  drop table session_state_enm;
+  drop table session;
+  drop table session_termination_reason_enm;
+  drop function insert_session_state;
+  drop function insert_new_session_state;
+  drop function insert_session;
+  drop function update_session_state_on_termination_reason;
+  drop function insert_session_state;
+
+
+  delete
+  from oplog_ticket
+  where name in (
+          'session'
+      );
+
+commit;
+
+`),
+	},
+	"migrations/50_session.up.sql": {
+		name: "50_session.up.sql",
+		bytes: []byte(`
+begin;
+
+/*
+
+             ┌─────────────────┐               ┌─────────────────┐   ┌─────────────────┐
+             │  iam_scope_org  │               │    iam_user     │   │   auth_token    │
+             ├─────────────────┤               ├─────────────────┤   ├─────────────────┤
+             │ public_id  (pk) │               │ public_id (pk)  │   │ public_id  (pk) │
+             │                 │               │                 │   │                 │
+             └─────────────────┘               └─────────────────┘   └─────────────────┘
+                 ▲fk7 ┼                            ▲fk1 ┼                ▲fk6 ┼
+                      ┼                                 ┼                     ┼
+                      ├─────────────────────────────────┴─────────────────────┘
+                      │
+                      ○
+                     ╱│╲
+        ┌──────────────────────────┐          ┌─────────────────┐   ┌─────────────────┐
+        │         session          │╲  fk3▶   │     server      │   │     target      │
+        ├──────────────────────────┤─○──────○┼├─────────────────┤   ├─────────────────┤
+        │ public_id          (pk)  │╱         │ private_id (pk) │   │ public_id  (pk) │
+        │ user_id            (fk1) │          │ type       (pk) │   │                 │
+        │ host_id            (fk2) │          └─────────────────┘   └─────────────────┘
+        │ server_id          (fk3) │                                    ▲fk4 ┼
+        │ server_type        (fk3) │╲                                        ┼
+        │ target_id          (fk4) │─○─────────────────┬─────────────────────┤
+        │ host_set_id        (fk5) │╱                  ┼                     ┼
+        │ auth_token_id      (fk6) │              ▼fk5 ┼                ▼fk2 ┼
+        │ scope_id           (fk7) │          ┌─────────────────┐   ┌─────────────────┐
+        │ termination_reason (fk8) │          │    host_set     │   │      host       │
+        └──────────────────────────┘          ├─────────────────┤   ├─────────────────┤
+                 ▲fk1 ┼           ╲│╱         │ public_id  (pk) │   │ public_id  (pk) │
+                      ┼            ○          │                 │   │                 │
+                      │            │          └─────────────────┘   └─────────────────┘
+                      │            │
+                      └─┐          │
+                        │          │            ┌───────────────────────────────┐
+                        │          │            │session_termination_reason_enm │
+                        │          │     fk8▶   ├───────────────────────────────┤
+                        ┼          └──────────○┼│ name                          │
+                       ╱│╲                      └───────────────────────────────┘
+  ┌──────────────────────────────────────────┐
+  │              session_state               │
+  ├──────────────────────────────────────────┤┼○┐
+  │ session_id        (pk,fk1,fk2,unq1,unq2) │  │◀fk2
+  │ state             (fk3)                  │  │
+  │ previous_end_time (fk2,unq1)             │┼○┘
+  │ start_time        (pk)                   │
+  │ end_time          (unq2)                 │
+  └──────────────────────────────────────────┘
+                       ╲│╱
+                        ○
+                        │
+                        │
+                        ┼
+                  ▼fk3  ┼
+        ┌───────────────────────────────┐
+        │       session_state_enm       │
+        ├───────────────────────────────┤
+        │ name                          │
+        └───────────────────────────────┘
+
+*/
+
+  create table session_termination_reason_enm (
+    name text primary key
+      constraint only_predefined_session_termination_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'terminated',
+          'network error',
+          'system error',
+          'connection limit',
+          'canceled'
+        )
+      )
+  );
+
+  insert into session_termination_reason_enm (name)
+  values
+    ('unknown'),
+    ('timed out'),
+    ('closed by end-user'),
+    ('terminated'),
+    ('network error'),
+    ('system error'),
+    ('connection limit'),
+    ('canceled');
+
+  create table session (
+    public_id wt_public_id primary key,
+    -- the user of the session
+    user_id text -- fk1
+      -- not using the wt_user_id domain type because it is marked 'not null'
+      references iam_user (public_id)
+      on delete set null
+      on update cascade,
+    -- the host the user is connected to via this session
+    host_id wt_public_id -- fk2
+      references host (public_id)
+      on delete set null
+      on update cascade,
+    -- the worker proxying the connection between the user and the host
+    server_id text, -- fk3
+    server_type text,-- fk3
+    foreign key (server_id, server_type)
+      references server (private_id, type)
+      on delete set null
+      on update cascade,
+    -- the target the host was chosen from and the user was authorized to
+    -- connect to
+    target_id wt_public_id -- fk4
+      references target (public_id)
+      on delete set null
+      on update cascade,
+    -- the host set the host was chosen from and the user was authorized to
+    -- connect to via the target
+    host_set_id wt_public_id -- fk5
+      references host_set (public_id)
+      on delete set null
+      on update cascade,
+    -- the auth token of the user when this session was created
+    auth_token_id wt_public_id -- fk6
+      references auth_token (public_id)
+      on delete set null
+      on update cascade,
+    -- the project which owns this session
+    scope_id wt_scope_id -- fk7
+      references iam_scope_project (scope_id)
+      on delete set null
+      on update cascade,
+    -- Certificate to use when connecting (or if using custom certs, to
+	  -- serve as the "login"). Raw DER bytes.  
+    certificate bytea not null,
+    -- after this time the connection will be expired, e.g. forcefully terminated
+    expiration_time wt_timestamp, -- maybe null
+    -- limit on number of session connections allowed.  default of 0 equals no limit
+    connection_limit int not null default 1
+      constraint connection_limit_must_be_greater_than_0_or_negative_1
+      check(connection_limit > 0 or connection_limit = -1), 
+    -- trust of first use token 
+    tofu_token bytea, -- will be null when session is first created
+    -- the reason this session ended (null until terminated)
+     -- TODO: Make key_id a foreign key once we have DEKs
+    key_id text, -- will be null on insert
+      -- references kms_database_key_version(private_id) 
+      -- on delete restrict
+      -- on update cascade,
+    termination_reason text -- fk8
+      references session_termination_reason_enm (name)
+      on delete restrict
+      on update cascade,
+    version wt_version,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    endpoint text -- not part of the warehouse, used to send info to the worker
+  );
+
+  create trigger 
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
+  
+  -- session table has some cascades of FK to null, so we need to be careful
+  -- which columns trigger an update of the version column
+  create trigger 
+    update_version_column 
+  after update of version, termination_reason, key_id, tofu_token, server_id, server_type on session
+    for each row execute procedure update_version_column();
+    
+  create trigger 
+    update_time_column 
+  before update on session 
+    for each row execute procedure update_time_column();
+    
+  create trigger 
+    default_create_time_column
+  before
+  insert on session
+    for each row execute procedure default_create_time();
+
+  create or replace function
+    insert_session()
+    returns trigger
+  as $$
+  begin
+    case 
+      when new.user_id is null then
+        raise exception 'user_id is null';
+      when new.host_id is null then
+        raise exception 'host_id is null';
+      when new.target_id is null then
+        raise exception 'target_id is null';
+      when new.host_set_id is null then
+        raise exception 'host_set_id is null';
+      when new.auth_token_id is null then
+        raise exception 'auth_token_id is null';
+      when new.scope_id is null then
+        raise exception 'scope_id is null';
+      when new.endpoint is null then
+        raise exception 'endpoint is null';
+    else
+    end case;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    insert_session
+  before insert on session
+    for each row execute procedure insert_session();
+
+  create or replace function 
+    insert_new_session_state()
+    returns trigger
+  as $$
+  begin
+    insert into session_state (session_id, state)
+    values
+      (new.public_id, 'pending');
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    insert_new_session_state
+  after insert on session
+    for each row execute procedure insert_new_session_state();
+
+  -- update_connection_state_on_closed_reason() is used in an update insert trigger on the
+  -- session_connection table.  it will valiadate that all the session's
+  -- connections are closed, and then insert a state of "closed" in
+  -- session_connection_state for the closed session connection. 
+  create or replace function 
+    update_session_state_on_termination_reason()
+    returns trigger
+  as $$
+  begin
+    if new.termination_reason is not null then
+      perform  from 
+        session
+      where 
+        public_id = new.public_id and 
+        public_id not in (
+            select session_id 
+              from session_connection
+            where
+              public_id in (
+                select connection_id
+                  from session_connection_state
+                where 
+                  state != 'closed' and 
+                  end_time is null
+              )
+        );
+      if not found then 
+        raise 'session %s has open connections', new.public_id;
+      end if;
+
+      -- check to see if there's a terminated state already, before inserting a
+      -- new one.
+      perform from
+        session_state ss
+      where
+        ss.session_id = new.public_id and 
+        ss.state = 'terminated';
+      if found then 
+        return new;
+      end if;
+
+      insert into session_state (session_id, state)
+      values
+        (new.public_id, 'terminated');
+      end if;
+      return new;
+  end;
+  $$ language plpgsql;
+
+
+  create trigger 
+    update_session_state_on_termination_reason
+  after update of termination_reason on session
+    for each row execute procedure update_session_state_on_termination_reason();
+ 
+
+  -- cancel_session will insert a cancel state for the session, if there's isn't
+  -- a canceled state already.  It's used by cancel_session_with_null_fk.
+  create or replace function
+    cancel_session(in sessionId text) returns void 
+  as $$
+  declare
+    rows_affected numeric;
+  begin 
+    insert into session_state(session_id, state) 
+    select 
+	    sessionId::text, 'canceling' 
+    from
+      session s
+    where 
+      s.public_id = sessionId::text and
+      s.public_id not in (
+        select 
+          session_id 
+        from 
+          session_state 
+        where 
+          session_id = sessionId::text and 
+          state = 'canceling'
+      ) limit 1;
+      get diagnostics rows_affected = row_count;
+      if rows_affected > 1 then
+          raise exception 'cancel session: more than one row affected: %', rows_affected; 
+      end if;
+  end;
+  $$ language plpgsql;
+
+  -- cancel_session_with_null_fk is intended to be a before update trigger that
+  -- sets the session's state to cancel if a FK is set to null.
+  create or replace function 
+    cancel_session_with_null_fk()
+    returns trigger
+  as $$
+  begin
+   case 
+      when new.user_id is null then
+        perform cancel_session(new.public_id);
+      when new.host_id is null then
+        perform cancel_session(new.public_id);
+      when new.target_id is null then
+        perform cancel_session(new.public_id);
+      when new.host_set_id is null then
+        perform cancel_session(new.public_id);
+      when new.auth_token_id is null then
+        perform cancel_session(new.public_id);
+      when new.scope_id is null then
+        perform cancel_session(new.public_id);
+    end case;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    cancel_session_with_null_fk
+  before update of user_id, host_id, target_id, host_set_id, auth_token_id, scope_id on session
+    for each row execute procedure cancel_session_with_null_fk();
+
+  create table session_state_enm (
+    name text primary key
+      constraint only_predefined_session_states_allowed
+      check (
+        name in ('pending', 'active', 'canceling', 'terminated')
+      )
+  );
+
+  insert into session_state_enm (name)
+  values
+    ('pending'),
+    ('active'),
+    ('canceling'),
+    ('terminated');
+
+/*
+
+
+                                              ┌────────────────┐
+         start                                │                │
+           .                                  │   Canceling    │
+          (●)                           ┌────▶│                │─────┐
+           '                            │     │                │     │
+           │                            │     └────────────────┘     │
+           │                            │                            │
+           ▼                            │                            ▼
+  ┌────────────────┐           ┌────────────────┐           ┌────────────────┐
+  │                │           │                │           │                │
+  │    Pending     │           │     Active     │           │   Terminated   │
+  │                │──────────▶│                │──────────▶│                │
+  │                │           │                │           │                │
+  └────────────────┘           └────────────────┘           └────────────────┘
+           │                                                         │
+           │                                                         │
+           │                                                         │
+           │                                                         │
+           └──────────────────────▶  ◉ End  ◀────────────────────────┘
+
+*/
+
+  -- Design influenced by:
+  -- Joe Celko's SQL for Smarties: Advanced SQL Programming, 5th edition
+  -- Chapter 12, p270
+  create table session_state (
+    session_id wt_public_id not null -- fk1, fk2
+      references session (public_id)
+      on delete cascade
+      on update cascade,
+    state text not null -- fk3
+      references session_state_enm(name)
+      on delete restrict
+      on update cascade,
+    previous_end_time timestamp with time zone, -- fk2 -- null means first state
+    start_time timestamp with time zone default current_timestamp not null,
+      constraint previous_end_time_and_start_time_in_sequence
+        check (previous_end_time <= start_time),
+    end_time timestamp with time zone, -- null means unfinished current state
+      constraint start_and_end_times_in_sequence
+        check (start_time <= end_time),
+      constraint end_times_in_sequence
+        check (previous_end_time <> end_time),
+    primary key (session_id, start_time),
+    unique (session_id, previous_end_time), -- null means first state
+    unique (session_id, end_time), -- one null current state
+    foreign key (session_id, previous_end_time) -- self-reference
+      references session_state (session_id, end_time)
+  );
+
+
+  create trigger 
+    immutable_columns
+  before
+  update on session_state
+    for each row execute procedure immutable_columns('session_id', 'state', 'start_time', 'previous_end_time');
+
+    
+  create or replace function
+    insert_session_state()
+    returns trigger
+  as $$
+  begin
+
+    update session_state
+       set end_time = now()
+     where session_id = new.session_id
+       and end_time is null;
+
+    if not found then
+      new.previous_end_time = null;
+      new.start_time = now();
+      new.end_time = null;
+      return new;
+    end if;
+
+    new.previous_end_time = now();
+    new.start_time = now();
+    new.end_time = null;
+    return new;
+
+  end;
+  $$ language plpgsql;
+
+
+  create trigger insert_session_state before insert on session_state
+    for each row execute procedure insert_session_state();
+
+  create view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time
+  from  
+    session s,
+    session_state ss
+  where 
+    s.public_id = ss.session_id;
+
+commit;
+
+`),
+	},
+	"migrations/51_connection.down.sql": {
+		name: "51_connection.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table session_connection_state;
+  drop table session_connection_state_enm;
+  drop table session_connection;
+  drop table session_connection_closed_reason_enm;
+  drop function insert_session_connection_state;
+  drop function insert_new_connection_state;
+  drop function update_connection_state_on_closed_reason;
+commit;
+
+`),
+	},
+	"migrations/51_connection.up.sql": {
+		name: "51_connection.up.sql",
+		bytes: []byte(`
+begin;
+
+/*
+
+               ┌────────────────┐
+               │    session     │
+               ├────────────────┤
+               │ public_id (pk) │
+               │                │
+               │                │
+               └────────────────┘
+                   ▲fk1 ┼
+                        ┼
+                        │
+                        │
+                        ┼
+                       ╱│╲                  ┌──────────────────────────────────────┐
+            ┌───────────────────────┐       │ session_connection_closed_reason_enm │
+            │  session_connection   │╲ fk2▶ ├──────────────────────────────────────┤
+            ├───────────────────────┤─○───○┼│ name                                 │
+            │ public_id     (pk)    │╱      └──────────────────────────────────────┘
+            │ session_id    (fk1)   │
+            │ closed_reason (fk2)   │
+            └───────────────────────┘
+                   ▲fk1 ┼
+                        ┼
+                        │
+                        │
+                        ┼
+                       ╱│╲
+  ┌──────────────────────────────────────────┐
+  │         session_connection_state         │
+  ├──────────────────────────────────────────┤┼○┐
+  │ connection_id     (pk,fk1,fk2,unq1,unq2) │  │◀fk2
+  │ state             (fk3)                  │  │
+  │ previous_end_time (fk2,unq1)             │┼○┘
+  │ start_time        (pk)                   │
+  │ end_time          (unq2)                 │
+  └──────────────────────────────────────────┘
+                       ╲│╱
+                        ○
+                        │
+                        │
+                        ┼
+                  ▼fk3  ┼
+        ┌───────────────────────────────┐
+        │ session_connection_state_enm  │
+        ├───────────────────────────────┤
+        │ name                          │
+        └───────────────────────────────┘
+
+*/
+
+  create table session_connection_closed_reason_enm (
+    name text primary key
+      constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error'
+        )
+      )
+  );
+
+  insert into session_connection_closed_reason_enm (name)
+  values
+    ('unknown'),
+    ('timed out'),
+    ('closed by end-user'),
+    ('canceled'),
+    ('network error'),
+    ('system error');
+
+  -- A session connection is one connection proxied by a worker from a client to
+  -- a endpoint for a session. The client initiates the connection to the worker
+  -- and the worker initiates the connection to the endpoint.
+  -- A session can have zero or more session connections.
+  create table session_connection (
+    public_id wt_public_id primary key,
+    session_id wt_public_id not null
+      references session (public_id)
+      on delete cascade
+      on update cascade,
+    -- the client_tcp_address is the network address of the client which initiated
+    -- the connection to a worker
+    client_tcp_address inet,  -- maybe null on insert
+    -- the client_tcp_port is the network port at the address of the client the
+    -- worker proxied a connection for the user
+    client_tcp_port integer  -- maybe null on insert
+      constraint client_tcp_port_must_be_greater_than_0
+      check(client_tcp_port > 0)
+      constraint client_tcp_port_must_less_than_or_equal_to_65535
+      check(client_tcp_port <= 65535),
+    -- the endpoint_tcp_address is the network address of the endpoint which the
+    -- worker initiated the connection to, for the user
+    endpoint_tcp_address inet, -- maybe be null on insert
+    -- the endpoint_tcp_port is the network port at the address of the endpoint the
+    -- worker proxied a connection to, for the user
+    endpoint_tcp_port integer -- maybe null on insert
+      constraint endpoint_tcp_port_must_be_greater_than_0
+      check(endpoint_tcp_port > 0)
+      constraint endpoint_tcp_port_must_less_than_or_equal_to_65535
+      check(endpoint_tcp_port <= 65535),
+    -- the total number of bytes received by the worker from the client and sent
+    -- to the endpoint for this connection
+    bytes_up bigint -- can be null
+      constraint bytes_up_must_be_null_or_a_non_negative_number
+      check (
+        bytes_up is null
+        or
+        bytes_up >= 0
+      ),
+    -- the total number of bytes received by the worker from the endpoint and sent
+    -- to the client for this connection
+    bytes_down bigint -- can be null
+      constraint bytes_down_must_be_null_or_a_non_negative_number
+      check (
+        bytes_down is null
+        or
+        bytes_down >= 0
+      ),
+    closed_reason text
+      references session_connection_closed_reason_enm (name)
+      on delete restrict
+      on update cascade,
+    version wt_version,
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger 
+    immutable_columns
+  before
+  update on session_connection
+    for each row execute procedure immutable_columns('public_id', 'session_id', 'create_time');
+
+  create trigger 
+    update_version_column 
+  after update on session_connection
+    for each row execute procedure update_version_column();
+    
+  create trigger 
+    update_time_column 
+  before update on session_connection 
+    for each row execute procedure update_time_column();
+    
+  create trigger 
+    default_create_time_column
+  before
+  insert on session_connection
+    for each row execute procedure default_create_time();
+
+  -- insert_new_connection_state() is used in an after insert trigger on the
+  -- session_connection table.  it will insert a state of "authorized" in
+  -- session_connection_state for the new session connection. 
+  create or replace function 
+    insert_new_connection_state()
+    returns trigger
+  as $$
+  begin
+    insert into session_connection_state (connection_id, state)
+    values
+      (new.public_id, 'authorized');
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    insert_new_connection_state
+  after insert on session_connection
+    for each row execute procedure insert_new_connection_state();
+
+  -- update_connection_state_on_closed_reason() is used in an update trigger on the
+  -- session_connection table.  it will insert a state of "closed" in
+  -- session_connection_state for the closed session connection. 
+  create or replace function 
+    update_connection_state_on_closed_reason()
+    returns trigger
+  as $$
+  begin
+    if new.closed_reason is not null then
+      -- check to see if there's a closed state already, before inserting a
+      -- new one.
+      perform from
+        session_connection_state cs
+      where
+        cs.connection_id = new.public_id and 
+        cs.state = 'closed';
+      if not found then 
+        insert into session_connection_state (connection_id, state)
+        values
+          (new.public_id, 'closed');
+      end if;
+      -- whenever we close a connection, we want to terminate the session if
+      -- possible.  
+      perform terminate_session_if_possible(new.session_id);
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    update_connection_state_on_closed_reason
+  after update of closed_reason on session_connection
+    for each row execute procedure update_connection_state_on_closed_reason();
+
+  create table session_connection_state_enm (
+    name text primary key
+      constraint only_predefined_session_connection_states_allowed
+      check (
+        name in ('authorized', 'connected', 'closed')
+      )
+  );
+
+  insert into session_connection_state_enm (name)
+  values
+    ('authorized'),
+    ('connected'),
+    ('closed');
+
+
+  create table session_connection_state (
+    connection_id wt_public_id not null
+      references session_connection (public_id)
+      on delete cascade
+      on update cascade,
+    state text not null
+      references session_connection_state_enm(name)
+      on delete restrict
+      on update cascade,
+    previous_end_time timestamp with time zone, -- fk2 -- null means first state
+    start_time timestamp with time zone default current_timestamp not null,
+      constraint previous_end_time_and_start_time_in_sequence
+        check (previous_end_time <= start_time),
+    end_time timestamp with time zone, -- null means unfinished current state
+      constraint start_and_end_times_in_sequence
+        check (start_time <= end_time),
+      constraint end_times_in_sequence
+        check (previous_end_time <> end_time),
+    primary key (connection_id, start_time),
+    unique (connection_id, previous_end_time), -- null means first state
+    unique (connection_id, end_time), -- one null current state
+    foreign key (connection_id, previous_end_time) -- self-reference
+      references session_connection_state (connection_id, end_time)
+  );
+
+  create trigger 
+    immutable_columns
+  before
+  update on session_connection_state
+    for each row execute procedure immutable_columns('connection_id', 'state', 'start_time', 'previous_end_time');
+
+  create or replace function
+    insert_session_connection_state()
+    returns trigger
+  as $$
+  begin
+
+    update session_connection_state
+       set end_time = now()
+     where connection_id = new.connection_id
+       and end_time is null;
+
+    if not found then
+      new.previous_end_time = null;
+      new.start_time = now();
+      new.end_time = null;
+      return new;
+    end if;
+
+    new.previous_end_time = now();
+    new.start_time = now();
+    new.end_time = null;
+    return new;
+
+  end;
+  $$ language plpgsql;
+
+  create trigger insert_session_connection_state before insert on session_connection_state
+    for each row execute procedure insert_session_connection_state();
+
+-- terminate_session_if_possible takes a session id and terminates the session
+-- if the following conditions are met:
+--    * the session is expired and all its connections are closed.
+--    * the session is canceling and all its connections are closed
+--    * the session has exhausted its connection limit and all its connections
+--      are closed.  
+--
+--      Note: this function should align closely with the domain function
+--      TerminateCompletedSessions 
+create or replace function 
+    terminate_session_if_possible(terminate_session_id text)
+    returns void
+  as $$
+  begin 
+    -- is terminate_session_id in a canceling state
+    with canceling_session(session_id) as
+    (
+      select 
+        session_id
+      from
+        session_state ss
+      where 
+        ss.session_id = terminate_session_id and
+        ss.state = 'canceling' and 
+        ss.end_time is null
+    )
+    update session us
+      set termination_reason = 
+      case 
+        -- timed out sessions
+        when now() > us.expiration_time then 'timed out'
+        -- canceling sessions
+        when us.public_id in(
+          select 
+            session_id 
+          from 
+            canceling_session cs 
+          where
+            us.public_id = cs.session_id
+          ) then 'canceled' 
+        -- default: session connection limit reached.
+        else 'connection limit'
+      end
+    where
+      -- limit update to just the terminating_session_id
+      us.public_id = terminate_session_id and
+      termination_reason is null and
+      -- session expired or connection limit reached
+      (
+        -- expired sessions...
+        now() > us.expiration_time or 
+        -- connection limit reached...
+        (
+          -- handle unlimited connections...
+          connection_limit != -1 and
+          (
+            select count (*) 
+              from session_connection sc 
+            where 
+              sc.session_id = us.public_id
+          ) >= connection_limit
+        ) or 
+        -- canceled sessions
+        us.public_id in (
+          select 
+            session_id
+          from
+            canceling_session cs
+          where 
+            us.public_id = cs.session_id 
+        )
+      ) and 
+      -- make sure there are no existing connections
+      us.public_id not in (
+        select 
+          session_id 
+        from 
+            session_connection
+          where public_id in (
+          select 
+            connection_id
+          from 
+            session_connection_state
+          where 
+            state != 'closed' and
+            end_time is null
+        )
+    );
+ end;
+  $$ language plpgsql;
+
+
+commit;
+
+`),
+	},
+	"migrations/60_wh_domain_types.down.sql": {
+		name: "60_wh_domain_types.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop function wh_current_time_id;
+  drop function wh_current_date_id;
+  drop function wh_time_id;
+  drop function wh_date_id;
+  drop domain wh_dim_text;
+  drop domain wh_timestamp;
+  drop domain wh_public_id;
+  drop domain wh_dim_id;
+  drop function wh_dim_id;
+  drop domain wh_bytes_transmitted;
+  drop domain wh_inet_port;
+
+commit;
+
+`),
+	},
+	"migrations/60_wh_domain_types.up.sql": {
+		name: "60_wh_domain_types.up.sql",
+		bytes: []byte(`
+begin;
+
+  create extension if not exists "pgcrypto";
+
+  create domain wh_inet_port as integer
+  check(
+    value > 0
+    and
+    value <= 65535
+  );
+  comment on domain wh_inet_port is
+  'An ordinal number between 1 and 65535 representing a network port';
+
+  create domain wh_bytes_transmitted as bigint
+  check(
+    value >= 0
+  );
+  comment on domain wh_bytes_transmitted is
+  'A non-negative integer representing the number of bytes transmitted';
+
+  -- wh_dim_id generates and returns a random ID which should be considered as
+  -- universally unique.
+  create or replace function wh_dim_id()
+    returns text
+  as $$
+    select encode(digest(gen_random_bytes(16), 'sha256'), 'base64');
+  $$ language sql;
+
+  create domain wh_dim_id as text
+  check(
+    length(trim(value)) > 0
+  );
+  comment on domain wh_dim_id is
+  'Random ID generated with pgcrypto';
+
+  create domain wh_public_id as text
+  check(
+    value = 'None'
+    or
+    length(trim(value)) > 10
+  );
+  comment on domain wh_public_id is
+  'Equivalent to wt_public_id but also allows the value to be ''None''';
+
+  create domain wh_timestamp as timestamp with time zone not null;
+  comment on domain wh_timestamp is
+  'Timestamp used in warehouse tables';
+
+  create domain wh_dim_text as text not null
+  check(
+    length(trim(value)) > 0
+  );
+  comment on domain wh_dim_text is
+  'Text fields in dimension tables are always not null and always not empty strings';
+
+  -- wh_date_id returns the wh_date_dimension id for ts.
+  create or replace function wh_date_id(ts wh_timestamp)
+    returns integer
+  as $$
+    select to_char(ts, 'YYYYMMDD')::integer;
+  $$ language sql;
+
+  -- wh_time_id returns the wh_time_of_day_dimension id for ts.
+  create or replace function wh_time_id(ts wh_timestamp)
+    returns integer
+  as $$
+    select to_char(ts, 'SSSS')::integer;
+  $$ language sql;
+
+  -- wh_date_id returns the wh_date_dimension id for current_timestamp.
+  create or replace function wh_current_date_id()
+    returns integer
+  as $$
+    select wh_date_id(current_timestamp);
+  $$ language sql;
+
+  -- wh_time_id returns the wh_time_of_day_dimension id for current_timestamp.
+  create or replace function wh_current_time_id()
+    returns integer
+  as $$
+    select wh_time_id(current_timestamp);
+  $$ language sql;
+
+commit;
+
+`),
+	},
+	"migrations/62_wh_datetime.down.sql": {
+		name: "62_wh_datetime.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table wh_time_of_day_dimension;
+  drop table wh_date_dimension;
+
+commit;
+
+`),
+	},
+	"migrations/62_wh_datetime.up.sql": {
+		name: "62_wh_datetime.up.sql",
+		bytes: []byte(`
+begin;
+
+  create table wh_date_dimension (
+    id                            integer      primary key,
+    date                          date         not null,
+    calendar_quarter              wh_dim_text,
+    calendar_month                wh_dim_text,
+    calendar_year                 smallint     not null,
+    day_of_week                   wh_dim_text,
+    day_of_week_number            smallint     not null,
+    day_of_week_number_iso        smallint     not null,
+    day_of_week_number_zero_based smallint     not null,
+    day_number_in_calendar_month  smallint     not null,
+    day_number_in_calendar_year   smallint     not null,
+    weekday_indicator             wh_dim_text
+  );
+
+  insert into wh_date_dimension (
+    id, date,
+    calendar_quarter, calendar_month, calendar_year,
+    day_of_week, day_of_week_number, day_of_week_number_iso, day_of_week_number_zero_based,
+    day_number_in_calendar_month, day_number_in_calendar_year,
+    weekday_indicator
+  ) values (
+    -1, 'infinity',
+    'None', 'None', -1,
+    'None', -1, -1, -1,
+    -1, -1,
+    'None'
+  );
+
+  insert
+    into wh_date_dimension
+  select to_char(t.day, 'YYYYMMDD')::integer as id,
+         t.day::date                         as date,
+         'Q' || to_char(t.day, 'Q')          as calendar_quarter,
+         to_char(t.day, 'Month')             as calendar_month,
+         extract(year from t.day)            as calendar_year,
+         to_char(t.day, 'Day')               as day_of_week,
+         to_char(t.day, 'D')::int            as day_of_week_number,
+         extract(isodow from t.day)          as day_of_week_number_iso,
+         extract(dow from t.day)             as day_of_week_number_zero_based,
+         extract(day from t.day)             as day_number_in_calendar_month,
+         extract(doy from t.day)             as day_number_in_calendar_year,
+         case extract(isodow from t.day)
+           when 6 then 'Weekend'
+           when 7 then 'Weekend'
+           else 'Weekday'
+         end                                 as weekday_indicator
+    from generate_series(
+           date_trunc('day', timestamp '2019-10-09'),
+           date_trunc('day', timestamp '2019-10-09' + interval '50 years'),
+           interval '1 day'
+         ) as t(day);
+
+  create table wh_time_of_day_dimension (
+    id                 integer      primary key,
+    time_no_zone       time         not null,
+    time_at_utc        timetz       not null,
+    hour_of_day        smallint     not null,
+    minute_of_hour     smallint     not null,
+    second_of_minute   smallint     not null,
+    display_time_24    wh_dim_text,
+    display_time_12    wh_dim_text,
+    meridiem_indicator wh_dim_text
+  );
+
+  set timezone = 'UTC';
+
+  insert into wh_time_of_day_dimension (
+    id, time_no_zone, time_at_utc,
+    hour_of_day, minute_of_hour, second_of_minute,
+    display_time_24, display_time_12, meridiem_indicator
+  ) values (
+    -1, 'allballs', 'allballs',
+    -1, -1, -1,
+    'None', 'None', 'None'
+  );
+
+  insert
+    into wh_time_of_day_dimension
+  select to_char(t.second, 'SSSS')::integer as id,
+         t.second::time                     as time_no_zone,
+         t.second::time                     as time_at_utc,
+         extract(hour from t.second)        as hour_of_day,
+         extract(minute from t.second)      as minute_of_hour,
+         extract(second from t.second)      as second_of_minute,
+         to_char(t.second, 'HH24:MI:SS')    as display_time_24,
+         to_char(t.second, 'HH12:MI:SS AM') as display_time_12,
+         to_char(t.second, 'PM')            as meridiem_indicator
+    from generate_series(
+           date_trunc('day', current_timestamp),
+           date_trunc('day', current_timestamp) + interval '24 hours' - interval '1 second',
+           interval '1 second'
+         ) as t(second);
+
+commit;
+
+`),
+	},
+	"migrations/65_wh_session_dimensions.down.sql": {
+		name: "65_wh_session_dimensions.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop view whx_user_dimension_target;
+  drop view whx_user_dimension_source;
+  drop view whx_host_dimension_target;
+  drop view whx_host_dimension_source;
+  drop table wh_user_dimension;
+  drop table wh_host_dimension;
+
+commit;
+
+`),
+	},
+	"migrations/65_wh_session_dimensions.up.sql": {
+		name: "65_wh_session_dimensions.up.sql",
+		bytes: []byte(`
+begin;
+
+  create table wh_host_dimension (
+    -- random id generated using encode(digest(gen_random_bytes(16), 'sha256'), 'base64')
+    -- this is done to prevent conflicts with rows in other clusters
+    -- which enables warehouse data from multiple clusters to be loaded into a
+    -- single database instance
+    id                              wh_dim_id     primary key default wh_dim_id(),
+
+    host_id                         wh_public_id  not null,
+    host_type                       wh_dim_text,
+    host_name                       wh_dim_text,
+    host_description                wh_dim_text,
+    host_address                    wh_dim_text,
+
+    host_set_id                     wh_public_id  not null,
+    host_set_type                   wh_dim_text,
+    host_set_name                   wh_dim_text,
+    host_set_description            wh_dim_text,
+
+    host_catalog_id                 wh_public_id  not null,
+    host_catalog_type               wh_dim_text,
+    host_catalog_name               wh_dim_text,
+    host_catalog_description        wh_dim_text,
+
+    target_id                       wh_public_id  not null,
+    target_type                     wh_dim_text,
+    target_name                     wh_dim_text,
+    target_description              wh_dim_text,
+    target_default_port_number      integer       not null,
+    target_session_max_seconds      integer       not null,
+    target_session_connection_limit integer       not null,
+
+    project_id                      wt_scope_id   not null,
+    project_name                    wh_dim_text,
+    project_description             wh_dim_text,
+
+    host_organization_id            wt_scope_id   not null,
+    host_organization_name          wh_dim_text,
+    host_organization_description   wh_dim_text,
+
+    current_row_indicator           wh_dim_text,
+    row_effective_time              wh_timestamp,
+    row_expiration_time             wh_timestamp
+  );
+
+  -- https://www.postgresql.org/docs/current/indexes-partial.html
+  create unique index wh_host_dim_current_constraint
+    on wh_host_dimension (target_id, host_set_id, host_id)
+    where current_row_indicator = 'Current';
+
+  -- The whx_host_dimension_source and whx_host_dimension_target views are used
+  -- by an insert trigger to determine if the current row for the dimension has
+  -- changed and new one needs to be inserted. The first column in the target
+  -- view must be the current warehouse id and all remaining columns must match
+  -- the columns in the source view.
+
+  -- The whx_host_dimension_source view shows the current values in the
+  -- operational tables of the host dimension.
+  create view whx_host_dimension_source as
+  select -- id is the first column in the target view
+         h.public_id                     as host_id,
+         'static host'                   as host_type,
+         coalesce(h.name, 'None')        as host_name,
+         coalesce(h.description, 'None') as host_description,
+         coalesce(h.address, 'Unknown')  as host_address,
+         s.public_id                     as host_set_id,
+         'static host set'               as host_set_type,
+         coalesce(s.name, 'None')        as host_set_name,
+         coalesce(s.description, 'None') as host_set_description,
+         c.public_id                     as host_catalog_id,
+         'static host catalog'           as host_catalog_type,
+         coalesce(c.name, 'None')        as host_catalog_name,
+         coalesce(c.description, 'None') as host_catalog_description,
+         t.public_id                     as target_id,
+         'tcp target'                    as target_type,
+         coalesce(t.name, 'None')        as target_name,
+         coalesce(t.description, 'None') as target_description,
+         coalesce(t.default_port, 0)     as target_default_port_number,
+         t.session_max_seconds           as target_session_max_seconds,
+         t.session_connection_limit      as target_session_connection_limit,
+         p.public_id                     as project_id,
+         coalesce(p.name, 'None')        as project_name,
+         coalesce(p.description, 'None') as project_description,
+         o.public_id                     as host_organization_id,
+         coalesce(o.name, 'None')        as host_organization_name,
+         coalesce(o.description, 'None') as host_organization_description
+    from static_host as h,
+         static_host_catalog as c,
+         static_host_set_member as m,
+         static_host_set as s,
+         target_host_set as ts,
+         target_tcp as t,
+         iam_scope as p,
+         iam_scope as o
+   where h.catalog_id = c.public_id
+     and h.public_id = m.host_id
+     and s.public_id = m.set_id
+     and t.public_id = ts.target_id
+     and s.public_id = ts.host_set_id
+     and p.public_id = t.scope_id
+     and p.type = 'project'
+     and o.public_id = p.parent_id
+     and o.type = 'org'
+  ;
+
+  -- The whx_host_dimension_target view shows the rows in the wh_host_dimension
+  -- table marked as 'Current'.
+  create view whx_host_dimension_target as
+  select id,
+         host_id,
+         host_type,
+         host_name,
+         host_description,
+         host_address,
+         host_set_id,
+         host_set_type,
+         host_set_name,
+         host_set_description,
+         host_catalog_id,
+         host_catalog_type,
+         host_catalog_name,
+         host_catalog_description,
+         target_id,
+         target_type,
+         target_name,
+         target_description,
+         target_default_port_number,
+         target_session_max_seconds,
+         target_session_connection_limit,
+         project_id,
+         project_name,
+         project_description,
+         host_organization_id,
+         host_organization_name,
+         host_organization_description
+    from wh_host_dimension
+   where current_row_indicator = 'Current'
+  ;
+
+  create table wh_user_dimension (
+    -- random id generated using encode(digest(gen_random_bytes(16), 'sha256'), 'base64')
+    -- this is done to prevent conflicts with rows in other clusters
+    -- which enables warehouse data from multiple clusters to be loaded into a
+    -- single database instance
+    id                            wh_dim_id     primary key default wh_dim_id(),
+
+    user_id                       wt_user_id    not null,
+    user_name                     wh_dim_text,
+    user_description              wh_dim_text,
+
+    auth_account_id               wh_public_id  not null,
+    auth_account_type             wh_dim_text,
+    auth_account_name             wh_dim_text,
+    auth_account_description      wh_dim_text,
+
+    auth_method_id                wh_public_id  not null,
+    auth_method_type              wh_dim_text,
+    auth_method_name              wh_dim_text,
+    auth_method_description       wh_dim_text,
+
+    user_organization_id          wt_scope_id   not null,
+    user_organization_name        wh_dim_text,
+    user_organization_description wh_dim_text,
+
+    current_row_indicator         wh_dim_text,
+    row_effective_time            wh_timestamp,
+    row_expiration_time           wh_timestamp
+  );
+
+  -- The whx_user_dimension_source and whx_user_dimension_target views are used
+  -- by an insert trigger to determine if the current row for the dimension has
+  -- changed and new one needs to be inserted. The first column in the target
+  -- view must be the current warehouse id and all remaining columns must match
+  -- the columns in the source view.
+
+  -- The whx_user_dimension_source view shows the current values in the
+  -- operational tables of the user dimension.
+  create view whx_user_dimension_source as
+       select -- id is the first column in the target view
+              u.public_id                       as user_id,
+              coalesce(u.name, 'None')          as user_name,
+              coalesce(u.description, 'None')   as user_description,
+              coalesce(aa.public_id, 'None')    as auth_account_id,
+              case when aa.public_id is null then 'None'
+                   else 'password auth account'
+                   end                          as auth_account_type,
+              coalesce(apa.name, 'None')        as auth_account_name,
+              coalesce(apa.description, 'None') as auth_account_description,
+              coalesce(am.public_id, 'None')    as auth_method_id,
+              case when am.public_id is null then 'None'
+                   else 'password auth method'
+                   end                          as auth_method_type,
+              coalesce(apm.name, 'None')        as auth_method_name,
+              coalesce(apm.description, 'None') as auth_method_description,
+              org.public_id                     as user_organization_id,
+              coalesce(org.name, 'None')        as user_organization_name,
+              coalesce(org.description, 'None') as user_organization_description
+         from iam_user as u
+    left join auth_account as aa on           u.public_id = aa.iam_user_id
+    left join auth_method as am on            aa.auth_method_id = am.public_id
+    left join auth_password_account as apa on aa.public_id = apa.public_id
+    left join auth_password_method as apm on  am.public_id = apm.public_id
+         join iam_scope as org on             u.scope_id = org.public_id
+  ;
+
+  -- The whx_user_dimension_target view shows the rows in the wh_user_dimension
+  -- table marked as 'Current'.
+  create view whx_user_dimension_target as
+    select id,
+           user_id,
+           user_name,
+           user_description,
+           auth_account_id,
+           auth_account_type,
+           auth_account_name,
+           auth_account_description,
+           auth_method_id,
+           auth_method_type,
+           auth_method_name,
+           auth_method_description,
+           user_organization_id,
+           user_organization_name,
+           user_organization_description
+      from wh_user_dimension
+     where current_row_indicator = 'Current'
+  ;
+
+commit;
+
+`),
+	},
+	"migrations/66_wh_session_dimensions.down.sql": {
+		name: "66_wh_session_dimensions.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop function wh_upsert_user;
+  drop function wh_upsert_host;
+
+commit;
+
+`),
+	},
+	"migrations/66_wh_session_dimensions.up.sql": {
+		name: "66_wh_session_dimensions.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- wh_upsert_host returns the wh_host_dimension id for p_host_id,
+  -- p_host_set_id, and p_target_id. wh_upsert_host compares the current values
+  -- in the wh_host_dimension with the current values in the operational tables
+  -- for the provide parameters. If the values between the operational tables
+  -- and the wh_host_dimension differ, a new row is inserted in the
+  -- wh_host_dimension to match the current values in the operational tables and
+  -- the new id is returned. If the values do not differ, the current id is
+  -- returned.
+  create or replace function wh_upsert_host(p_host_id wt_public_id, p_host_set_id wt_public_id, p_target_id wt_public_id)
+    returns wh_dim_id
+  as $$
+  declare
+    src     whx_host_dimension_target%rowtype;
+    target  whx_host_dimension_target%rowtype;
+    new_row wh_host_dimension%rowtype;
+  begin
+    select * into target
+      from whx_host_dimension_target as t
+     where t.host_id               = p_host_id
+       and t.host_set_id           = p_host_set_id
+       and t.target_id             = p_target_id;
+
+    select target.id, t.* into src
+      from whx_host_dimension_source as t
+     where t.host_id               = p_host_id
+       and t.host_set_id           = p_host_set_id
+       and t.target_id             = p_target_id;
+
+    if src is distinct from target then
+
+      -- expire the current row
+      update wh_host_dimension
+         set current_row_indicator = 'Expired',
+             row_expiration_time   = current_timestamp
+       where host_id               = p_host_id
+         and host_set_id           = p_host_set_id
+         and target_id             = p_target_id
+         and current_row_indicator = 'Current';
+
+      -- insert a new row
+      insert into wh_host_dimension (
+             host_id,                    host_type,                  host_name,                       host_description,         host_address,
+             host_set_id,                host_set_type,              host_set_name,                   host_set_description,
+             host_catalog_id,            host_catalog_type,          host_catalog_name,               host_catalog_description,
+             target_id,                  target_type,                target_name,                     target_description,
+             target_default_port_number, target_session_max_seconds, target_session_connection_limit,
+             project_id,                 project_name,               project_description,
+             host_organization_id,       host_organization_name,     host_organization_description,
+             current_row_indicator,      row_effective_time,         row_expiration_time
+      )
+      select host_id,                    host_type,                  host_name,                       host_description,         host_address,
+             host_set_id,                host_set_type,              host_set_name,                   host_set_description,
+             host_catalog_id,            host_catalog_type,          host_catalog_name,               host_catalog_description,
+             target_id,                  target_type,                target_name,                     target_description,
+             target_default_port_number, target_session_max_seconds, target_session_connection_limit,
+             project_id,                 project_name,               project_description,
+             host_organization_id,       host_organization_name,     host_organization_description,
+             'Current',                  current_timestamp,          'infinity'::timestamptz
+        from whx_host_dimension_source
+       where host_id               = p_host_id
+         and host_set_id           = p_host_set_id
+         and target_id             = p_target_id
+      returning * into new_row;
+
+      return new_row.id;
+    end if;
+    return target.id;
+
+  end;
+  $$ language plpgsql;
+
+  -- wh_upsert_user returns the wh_user_dimension id for p_user_id and
+  -- p_auth_token_id. wh_upsert_user compares the current values in the
+  -- wh_user_dimension with the current values in the operational tables for the
+  -- provide parameters. If the values between the operational tables and the
+  -- wh_user_dimension differ, a new row is inserted in the wh_user_dimension to
+  -- match the current values in the operational tables and the new id is
+  -- returned. If the values do not differ, the current id is returned.
+  create or replace function wh_upsert_user(p_user_id wt_user_id, p_auth_token_id wt_public_id)
+    returns wh_dim_id
+  as $$
+  declare
+    src     whx_user_dimension_target%rowtype;
+    target  whx_user_dimension_target%rowtype;
+    new_row wh_user_dimension%rowtype;
+    acct_id wt_public_id;
+  begin
+    select auth_account_id into strict acct_id
+      from auth_token
+     where public_id = p_auth_token_id;
+
+    select * into target
+      from whx_user_dimension_target as t
+     where t.user_id               = p_user_id
+       and t.auth_account_id       = acct_id;
+
+    select target.id, t.* into src
+      from whx_user_dimension_source as t
+     where t.user_id               = p_user_id
+       and t.auth_account_id       = acct_id;
+
+    if src is distinct from target then
+
+      -- expire the current row
+      update wh_user_dimension
+         set current_row_indicator = 'Expired',
+             row_expiration_time   = current_timestamp
+       where user_id               = p_user_id
+         and auth_account_id       = acct_id
+         and current_row_indicator = 'Current';
+
+      -- insert a new row
+      insert into wh_user_dimension (
+             user_id,               user_name,              user_description,
+             auth_account_id,       auth_account_type,      auth_account_name,             auth_account_description,
+             auth_method_id,        auth_method_type,       auth_method_name,              auth_method_description,
+             user_organization_id,  user_organization_name, user_organization_description,
+             current_row_indicator, row_effective_time,     row_expiration_time
+      )
+      select user_id,               user_name,              user_description,
+             auth_account_id,       auth_account_type,      auth_account_name,             auth_account_description,
+             auth_method_id,        auth_method_type,       auth_method_name,              auth_method_description,
+             user_organization_id,  user_organization_name, user_organization_description,
+             'Current',             current_timestamp,      'infinity'::timestamptz
+        from whx_user_dimension_source
+       where user_id               = p_user_id
+         and auth_account_id       = acct_id
+      returning * into new_row;
+
+      return new_row.id;
+    end if;
+    return target.id;
+
+  end;
+  $$ language plpgsql;
+commit;
+
+`),
+	},
+	"migrations/68_wh_session_facts.down.sql": {
+		name: "68_wh_session_facts.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table wh_session_connection_accumulating_fact;
+  drop table wh_session_accumulating_fact;
+
+commit;
+
+`),
+	},
+	"migrations/68_wh_session_facts.up.sql": {
+		name: "68_wh_session_facts.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- Column names for numeric fields that are not a measurement end in id or
+  -- number. This naming convention enables automatic field type detection in
+  -- certain data analysis tools.
+  -- https://help.tableau.com/current/pro/desktop/en-us/data_clean_adm.htm
+
+  -- The wh_session_accumulating_fact table is an accumulating snapshot.
+  -- The table wh_session_accumulating_fact is an accumulating fact table.
+  -- The grain of the fact table is one row per session.
+  create table wh_session_accumulating_fact (
+    session_id wt_public_id primary key,
+    -- auth token id is a degenerate dimension
+    auth_token_id wt_public_id not null,
+
+    -- foreign keys to the dimension tables
+    host_id wh_dim_id not null
+      references wh_host_dimension (id)
+      on delete restrict
+      on update cascade,
+    user_id wh_dim_id not null
+      references wh_user_dimension (id)
+      on delete restrict
+      on update cascade,
+
+    -- TODO(mgaffney) 09/2020: add dimension and foreign key for the session
+    -- termination reason
+
+    -- date and time foreign keys
+    session_pending_date_id integer not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_pending_time_id integer not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_pending_time wh_timestamp,
+
+    session_active_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_active_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_active_time wh_timestamp default 'infinity'::timestamptz,
+
+    session_canceling_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_canceling_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_canceling_time wh_timestamp default 'infinity'::timestamptz,
+
+    session_terminated_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_terminated_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_terminated_time wh_timestamp default 'infinity'::timestamptz,
+
+    -- TODO(mgaffney) 09/2020: add columns for session expiration
+
+    -- TODO(mgaffney) 09/2020: add connection limit. This may need a dimension
+    -- table and foreign key column to represent unlimited connections.
+
+    -- The total number of connections made during the session.
+    total_connection_count bigint, -- will be null until the first connection is created
+
+    -- The total number of bytes received by workers from the client and sent
+    -- to the endpoint for this session.
+    -- total_bytes_up is a fully additive measurement.
+    total_bytes_up wh_bytes_transmitted, -- will be null until the first connection is closed
+    -- The total number of bytes received by workers from the endpoint and sent
+    -- to the client for this session.
+    -- total_bytes_down is a fully additive measurement.
+    total_bytes_down wh_bytes_transmitted -- will be null until the first connection is closed
+  );
+
+  -- The wh_session_connection_accumulating_fact table is an accumulating fact table.
+  -- The grain of the fact table is one row per session connection.
+  create table wh_session_connection_accumulating_fact  (
+    connection_id wt_public_id primary key,
+    -- session_id is a degenerate dimension
+    session_id wt_public_id not null
+      references wh_session_accumulating_fact (session_id)
+      on delete cascade
+      on update cascade,
+
+    -- foreign keys to the dimension tables
+    host_id wh_dim_id not null
+      references wh_host_dimension (id)
+      on delete restrict
+      on update cascade,
+    user_id wh_dim_id not null
+      references wh_user_dimension (id)
+      on delete restrict
+      on update cascade,
+
+    -- TODO(mgaffney) 09/2020: add dimension and foreign key for the connection
+    -- closed reason
+
+    -- date and time foreign keys and timestamps
+    connection_authorized_date_id integer not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_authorized_time_id integer not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_authorized_time wh_timestamp,
+
+    connection_connected_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_connected_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_connected_time wh_timestamp default 'infinity'::timestamptz,
+
+    connection_closed_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_closed_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_closed_time wh_timestamp default 'infinity'::timestamptz,
+
+    -- TODO(mgaffney) 09/2020: add a connection_duration_in_seconds column
+
+    -- The client address and port are degenerate dimensions
+    client_tcp_address inet, -- can be null
+    client_tcp_port_number wh_inet_port, -- can be null
+
+    -- The endpoint address and port are degenerate dimensions
+    endpoint_tcp_address inet, -- can be null
+    endpoint_tcp_port_number wh_inet_port, -- can be null
+
+    -- the connection_count must always be 1
+    -- this is a common pattern in data warehouse models
+    -- See The Data Warehouse Toolkit, Third Edition
+    -- by Ralph Kimball and Margy Ross for more information
+    connection_count smallint default 1 not null
+      constraint connection_count_must_be_1
+      check(connection_count = 1),
+
+    -- The total number of bytes received by the worker from the client and sent
+    -- to the endpoint for this connection.
+    -- bytes_up is a fully additive measurement.
+    bytes_up wh_bytes_transmitted, -- can be null
+    -- The total number of bytes received by the worker from the endpoint and sent
+    -- to the client for this connection.
+    -- bytes_down is a fully additive measurement.
+    bytes_down wh_bytes_transmitted -- can be null
+  );
+
+  -- TODO(mgaffney) 09/2020: Research and test if the comment fields are used by
+  -- data analysis tools.
+  comment on table wh_session_connection_accumulating_fact is
+    'The Wh Session Connection Accumulating Fact table is an accumulating fact table. '
+    'The grain of the fact table is one row per session connection.';
+  comment on column wh_session_connection_accumulating_fact.bytes_up is
+    'Bytes Up is the total number of bytes received by the worker from the '
+    'client and sent to the endpoint for this connection. Bytes Up is a fully '
+    'additive measurement.';
+  comment on column wh_session_connection_accumulating_fact.bytes_down is
+    'Bytes Down is the total number of bytes received by the worker from the '
+    'endpoint and sent to the client for this connection. Bytes Down is a fully '
+    'additive measurement.';
+
+  create index on wh_session_connection_accumulating_fact(session_id);
+
+commit;
+
+`),
+	},
+	"migrations/69_wh_session_facts.down.sql": {
+		name: "69_wh_session_facts.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger wh_insert_session_connection_state on session_connection_state;
+  drop function wh_insert_session_connection_state;
+
+  drop trigger wh_insert_session_state on session_state;
+  drop function wh_insert_session_state;
+
+  drop trigger wh_update_session_connection on session_connection;
+  drop function wh_update_session_connection;
+
+  drop trigger wh_insert_session_connection on session_connection;
+  drop function wh_insert_session_connection;
+
+  drop trigger wh_insert_session on session;
+  drop function wh_insert_session;
+
+  drop function wh_rollup_connections;
+
+commit;
+
+`),
+	},
+	"migrations/69_wh_session_facts.up.sql": {
+		name: "69_wh_session_facts.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- wh_rollup_connections calculates the aggregate values from
+  -- wh_session_connection_accumulating_fact for p_session_id and updates
+  -- wh_session_accumulating_fact for p_session_id with those values.
+  create or replace function wh_rollup_connections(p_session_id wt_public_id)
+    returns void
+  as $$
+  declare
+    session_row wh_session_accumulating_fact%rowtype;
+  begin
+    with
+    session_totals (session_id, total_connection_count, total_bytes_up, total_bytes_down) as (
+      select session_id,
+             sum(connection_count),
+             sum(bytes_up),
+             sum(bytes_down)
+        from wh_session_connection_accumulating_fact
+       where session_id = p_session_id
+       group by session_id
+    )
+    update wh_session_accumulating_fact
+       set total_connection_count = session_totals.total_connection_count,
+           total_bytes_up         = session_totals.total_bytes_up,
+           total_bytes_down       = session_totals.total_bytes_down
+      from session_totals
+     where wh_session_accumulating_fact.session_id = session_totals.session_id
+    returning wh_session_accumulating_fact.* into strict session_row;
+  end;
+  $$ language plpgsql;
+
+  --
+  -- Session triggers
+  --
+
+  -- wh_insert_session returns an after insert trigger for the session table
+  -- which inserts a row in wh_session_accumulating_fact for the new session.
+  -- wh_insert_session also calls the wh_upsert_host and wh_upsert_user
+  -- functions which can result in new rows in wh_host_dimension and
+  -- wh_user_dimension respectively.
+  create or replace function wh_insert_session()
+    returns trigger
+  as $$
+  declare
+    new_row wh_session_accumulating_fact%rowtype;
+  begin
+    with
+    pending_timestamp (date_dim_id, time_dim_id, ts) as (
+      select wh_date_id(start_time), wh_time_id(start_time), start_time
+        from session_state
+       where session_id = new.public_id
+         and state = 'pending'
+    )
+    insert into wh_session_accumulating_fact (
+           session_id,
+           auth_token_id,
+           host_id,
+           user_id,
+           session_pending_date_id,
+           session_pending_time_id,
+           session_pending_time
+    )
+    select new.public_id,
+           new.auth_token_id,
+           wh_upsert_host(new.host_id, new.host_set_id, new.target_id),
+           wh_upsert_user(new.user_id, new.auth_token_id),
+           pending_timestamp.date_dim_id,
+           pending_timestamp.time_dim_id,
+           pending_timestamp.ts
+      from pending_timestamp
+      returning * into strict new_row;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger wh_insert_session
+    after insert on session
+    for each row
+    execute function wh_insert_session();
+
+  --
+  -- Session Connection triggers
+  --
+
+  -- wh_insert_session_connection returns an after insert trigger for the
+  -- session_connection table which inserts a row in
+  -- wh_session_connection_accumulating_fact for the new session connection.
+  -- wh_insert_session_connection also calls wh_rollup_connections which can
+  -- result in updates to wh_session_accumulating_fact.
+  create or replace function wh_insert_session_connection()
+    returns trigger
+  as $$
+  declare
+    new_row wh_session_connection_accumulating_fact%rowtype;
+  begin
+    with
+    authorized_timestamp (date_dim_id, time_dim_id, ts) as (
+      select wh_date_id(start_time), wh_time_id(start_time), start_time
+        from session_connection_state
+       where connection_id = new.public_id
+         and state = 'authorized'
+    ),
+    session_dimension (host_dim_id, user_dim_id) as (
+      select host_id, user_id
+        from wh_session_accumulating_fact
+       where session_id = new.session_id
+    )
+    insert into wh_session_connection_accumulating_fact (
+           connection_id,
+           session_id,
+           host_id,
+           user_id,
+           connection_authorized_date_id,
+           connection_authorized_time_id,
+           connection_authorized_time,
+           client_tcp_address,
+           client_tcp_port_number,
+           endpoint_tcp_address,
+           endpoint_tcp_port_number,
+           bytes_up,
+           bytes_down
+    )
+    select new.public_id,
+           new.session_id,
+           session_dimension.host_dim_id,
+           session_dimension.user_dim_id,
+           authorized_timestamp.date_dim_id,
+           authorized_timestamp.time_dim_id,
+           authorized_timestamp.ts,
+           new.client_tcp_address,
+           new.client_tcp_port,
+           new.endpoint_tcp_address,
+           new.endpoint_tcp_port,
+           new.bytes_up,
+           new.bytes_down
+      from authorized_timestamp,
+           session_dimension
+      returning * into strict new_row;
+    perform wh_rollup_connections(new.session_id);
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger wh_insert_session_connection
+    after insert on session_connection
+    for each row
+    execute function wh_insert_session_connection();
 
-  create or replace function 
-    insert_new_session_state()
+  -- wh_update_session_connection returns an after update trigger for the
+  -- session_connection table which updates a row in
+  -- wh_session_connection_accumulating_fact for the session connection.
+  -- wh_update_session_connection also calls wh_rollup_connections which can
+  -- result in updates to wh_session_accumulating_fact.
+  create or replace function wh_update_session_connection()
     returns trigger
   as $$
+  declare
+    updated_row wh_session_connection_accumulating_fact%rowtype;
   begin
-    insert into session_state (session_id, state)
-    values
-      (new.public_id, 'pending');
-    return new;
+        update wh_session_connection_accumulating_fact
+           set client_tcp_address       = new.client_tcp_address,
+               client_tcp_port_number   = new.client_tcp_port,
+               endpoint_tcp_address     = new.endpoint_tcp_address,
+               endpoint_tcp_port_number = new.endpoint_tcp_port,
+               bytes_up                 = new.bytes_up,
+               bytes_down               = new.bytes_down
+         where connection_id = new.public_id
+     returning * into strict updated_row;
+    perform wh_rollup_connections(new.session_id);
+    return null;
   end;
   $$ language plpgsql;
 
-  create trigger 
-    insert_new_session_state
-  after insert on session
-    for each row execute procedure insert_new_session_state();
+  create trigger wh_update_session_connection
+    after update on session_connection
+    for each row
+    execute function wh_update_session_connection();
 
-  -- update_connection_state_on_closed_reason() is used in an update insert trigger on the
-  -- session_connection table.  it will valiadate that all the session's
-  -- connections are closed, and then insert a state of "closed" in
-  -- session_connection_state for the closed session connection. 
-  create or replace function 
-    update_session_state_on_termination_reason()
+  --
+  -- Session State trigger
+  --
+
+  -- wh_insert_session_state returns an after insert trigger for the
+  -- session_state table which updates wh_session_accumulating_fact.
+  create or replace function wh_insert_session_state()
     returns trigger
   as $$
+  declare
+    date_col text;
+    time_col text;
+    ts_col text;
+    q text;
+    session_row wh_session_accumulating_fact%rowtype;
   begin
-    if new.termination_reason is not null then
-      perform  from 
-        session
-      where 
-        public_id = new.public_id and 
-        public_id not in (
-            select session_id 
-              from session_connection
-            where
-              public_id in (
-                select connection_id
-                  from session_connection_state
-                where 
-                  state != 'closed' and 
-                  end_time is null
-              )
-        );
-      if not found then 
-        raise 'session %s has open connections', new.public_id;
-      end if;
+    if new.state = 'pending' then
+      -- The pending state is the first state which is handled by the
+      -- wh_insert_session trigger. The update statement in this trigger will
+      -- fail for the pending state because the row for the session has not yet
+      -- been inserted into the wh_session_accumulating_fact table.
+      return null;
+    end if;
 
-      -- check to see if there's a terminated state already, before inserting a
-      -- new one.
-      perform from
-        session_state ss
-      where
-        ss.session_id = new.public_id and 
-        ss.state = 'terminated';
-      if found then 
-        return new;
-      end if;
+    date_col = 'session_' || new.state || '_date_id';
+    time_col = 'session_' || new.state || '_time_id';
+    ts_col   = 'session_' || new.state || '_time';
 
-      insert into session_state (session_id, state)
-      values
-        (new.public_id, 'terminated');
-      end if;
-      return new;
+    q = format('update wh_session_accumulating_fact
+                   set (%I, %I, %I) = (select wh_date_id(%L), wh_time_id(%L), %L::timestamptz)
+                 where session_id = %L
+                returning *',
+                date_col,       time_col,       ts_col,
+                new.start_time, new.start_time, new.start_time,
+                new.session_id);
+    execute q into strict session_row;
+
+    return null;
   end;
   $$ language plpgsql;
 
+  create trigger wh_insert_session_state
+    after insert on session_state
+    for each row
+    execute function wh_insert_session_state();
 
-  create trigger 
-    update_session_state_on_termination_reason
-  after update of termination_reason on session
-    for each row execute procedure update_session_state_on_termination_reason();
- 
-
-  -- cancel_session will insert a cancel state for the session, if there's isn't
-  -- a canceled state already.  It's used by cancel_session_with_null_fk.
-  create or replace function
-    cancel_session(in sessionId text) returns void 
-  as $$
-  declare
-    rows_affected numeric;
-  begin 
-    insert into session_state(session_id, state) 
-    select 
-	    sessionId::text, 'canceling' 
-    from
-      session s
-    where 
-      s.public_id = sessionId::text and
-      s.public_id not in (
-        select 
-          session_id 
-        from 
-          session_state 
-        where 
-          session_id = sessionId::text and 
-          state = 'canceling'
-      ) limit 1;
-      get diagnostics rows_affected = row_count;
-      if rows_affected > 1 then
-          raise exception 'cancel session: more than one row affected: %', rows_affected; 
-      end if;
-  end;
-  $$ language plpgsql;
+  --
+  -- Session Connection State trigger
+  --
 
-  -- cancel_session_with_null_fk is intended to be a before update trigger that
-  -- sets the session's state to cancel if a FK is set to null.
-  create or replace function 
-    cancel_session_with_null_fk()
+  -- wh_insert_session_connection_state returns an after insert trigger for the
+  -- session_connection_state table which updates
+  -- wh_session_connection_accumulating_fact.
+  create or replace function wh_insert_session_connection_state()
     returns trigger
   as $$
+  declare
+    date_col text;
+    time_col text;
+    ts_col text;
+    q text;
+    connection_row wh_session_connection_accumulating_fact%rowtype;
   begin
-   case 
-      when new.user_id is null then
-        perform cancel_session(new.public_id);
-      when new.host_id is null then
-        perform cancel_session(new.public_id);
-      when new.target_id is null then
-        perform cancel_session(new.public_id);
-      when new.host_set_id is null then
-        perform cancel_session(new.public_id);
-      when new.auth_token_id is null then
-        perform cancel_session(new.public_id);
-      when new.scope_id is null then
-        perform cancel_session(new.public_id);
-    end case;
-    return new;
+    if new.state = 'authorized' then
+      -- The authorized state is the first state which is handled by the
+      -- wh_insert_session_connection trigger. The update statement in this
+      -- trigger will fail for the authorized state because the row for the
+      -- session connection has not yet been inserted into the
+      -- wh_session_connection_accumulating_fact table.
+      return null;
+    end if;
+
+    date_col = 'connection_' || new.state || '_date_id';
+    time_col = 'connection_' || new.state || '_time_id';
+    ts_col   = 'connection_' || new.state || '_time';
+
+    q = format('update wh_session_connection_accumulating_fact
+                   set (%I, %I, %I) = (select wh_date_id(%L), wh_time_id(%L), %L::timestamptz)
+                 where connection_id = %L
+                returning *',
+                date_col,       time_col,       ts_col,
+                new.start_time, new.start_time, new.start_time,
+                new.connection_id);
+    execute q into strict connection_row;
+
+    return null;
   end;
   $$ language plpgsql;
 
-  create trigger 
-    cancel_session_with_null_fk
-  before update of user_id, host_id, target_id, host_set_id, auth_token_id, scope_id on session
-    for each row execute procedure cancel_session_with_null_fk();
+  create trigger wh_insert_session_connection_state
+    after insert on session_connection_state
+    for each row
+    execute function wh_insert_session_connection_state();
+
+commit;
+
+`),
+	},
+	"migrations/70_servers.down.sql": {
+		name: "70_servers.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table server_tag;
+
+  alter table server
+    drop column release_version;
+
+commit;
+
+`),
+	},
+	"migrations/70_servers.up.sql": {
+		name: "70_servers.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- release_version is the version of boundary the server is running.
+  alter table server
+    add column release_version text;
+
+  -- server_tag contains the key/value tags reported by a server. A key can
+  -- have multiple values. Tags are replaced in their entirety each time a
+  -- worker reports its status.
+  create table server_tag (
+    server_id text not null,
+    server_type text not null,
+    key text not null
+      constraint server_tag_key_must_not_be_empty
+      check(length(trim(key)) > 0),
+    value text not null
+      constraint server_tag_value_must_not_be_empty
+      check(length(trim(value)) > 0),
+    primary key (server_id, server_type, key, value),
+    foreign key (server_id, server_type)
+      references server (private_id, type)
+      on delete cascade
+      on update cascade
+  );
+
+  create index server_tag_key_value_idx
+    on server_tag (key, value);
 
-  create table session_state_enm (
-    name text primary key
-      constraint only_predefined_session_states_allowed
-      check (
-        name in ('pending', 'active', 'canceling', 'terminated')
-      )
-  );
+commit;
 
-  insert into session_state_enm (name)
-  values
-    ('pending'),
-    ('active'),
-    ('canceling'),
-    ('terminated');
+`),
+	},
+	"migrations/71_server_load.down.sql": {
+		name: "71_server_load.down.sql",
+		bytes: []byte(`
+begin;
 
-/*
+  alter table server
+    drop column active_session_count,
+    drop column active_connection_count,
+    drop column max_sessions;
 
+commit;
 
-                                              ┌────────────────┐
-         start                                │                │
-           .                                  │   Canceling    │
-          (●)                           ┌────▶│                │─────┐
-           '                            │     │                │     │
-           │                            │     └────────────────┘     │
-           │                            │                            │
-           ▼                            │                            ▼
-  ┌────────────────┐           ┌────────────────┐           ┌────────────────┐
-  │                │           │                │           │                │
-  │    Pending     │           │     Active     │           │   Terminated   │
-  │                │──────────▶│                │──────────▶│                │
-  │                │           │                │           │                │
-  └────────────────┘           └────────────────┘           └────────────────┘
-           │                                                         │
-           │                                                         │
-           │                                                         │
-           │                                                         │
-           └──────────────────────▶  ◉ End  ◀────────────────────────┘
+`),
+	},
+	"migrations/71_server_load.up.sql": {
+		name: "71_server_load.up.sql",
+		bytes: []byte(`
+begin;
 
-*/
+  -- Load reported by workers in their status updates. max_sessions is the
+  -- maximum number of sessions a worker will accept, where zero means the
+  -- worker does not impose a limit.
+  alter table server
+    add column active_session_count integer not null default 0
+      constraint active_session_count_must_be_zero_or_greater
+      check(active_session_count >= 0),
+    add column active_connection_count integer not null default 0
+      constraint active_connection_count_must_be_zero_or_greater
+      check(active_connection_count >= 0),
+    add column max_sessions integer not null default 0
+      constraint max_sessions_must_be_zero_or_greater
+      check(max_sessions >= 0);
 
-  -- Design influenced by:
-  -- Joe Celko's SQL for Smarties: Advanced SQL Programming, 5th edition
-  -- Chapter 12, p270
-  create table session_state (
-    session_id wt_public_id not null -- fk1, fk2
-      references session (public_id)
-      on delete cascade
-      on update cascade,
-    state text not null -- fk3
-      references session_state_enm(name)
+commit;
+
+`),
+	},
+	"migrations/72_worker_auth.down.sql": {
+		name: "72_worker_auth.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table worker_auth_certificate;
+  drop table worker_activation_token;
+  drop table worker_auth_ca;
+
+commit;
+
+`),
+	},
+	"migrations/72_worker_auth.up.sql": {
+		name: "72_worker_auth.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- worker_auth_ca contains the certificate authorities controllers use to
+  -- issue certificates to workers. The private key is encrypted with the
+  -- global scope's database key. A new authority is created when the current
+  -- one is close to expiring; older authorities are kept until they expire so
+  -- the certificates they issued remain valid.
+  create table worker_auth_ca (
+    private_id wt_private_id primary key,
+    certificate bytea not null,
+    private_key bytea not null, -- encrypted
+    key_id text not null
+      references kms_database_key_version(private_id)
       on delete restrict
       on update cascade,
-    previous_end_time timestamp with time zone, -- fk2 -- null means first state
-    start_time timestamp with time zone default current_timestamp not null,
-      constraint previous_end_time_and_start_time_in_sequence
-        check (previous_end_time <= start_time),
-    end_time timestamp with time zone, -- null means unfinished current state
-      constraint start_and_end_times_in_sequence
-        check (start_time <= end_time),
-      constraint end_times_in_sequence
-        check (previous_end_time <> end_time),
-    primary key (session_id, start_time),
-    unique (session_id, previous_end_time), -- null means first state
-    unique (session_id, end_time), -- one null current state
-    foreign key (session_id, previous_end_time) -- self-reference
-      references session_state (session_id, end_time)
+    not_before wt_timestamp not null,
+    not_after wt_timestamp not null,
+    create_time wt_timestamp,
+    constraint not_after_must_be_after_not_before
+      check(not_after > not_before)
   );
 
+  -- worker_activation_token contains the single use tokens workers present to
+  -- register with a controller. Only the sha256 of a token is stored.
+  create table worker_activation_token (
+    token_hash bytea primary key,
+    worker_name text not null
+      constraint worker_name_must_not_be_empty
+      check(length(trim(worker_name)) > 0),
+    expiration_time wt_timestamp not null,
+    redeemed_time timestamp with time zone,
+    create_time wt_timestamp
+  );
 
-  create trigger 
-    immutable_columns
-  before
-  update on session_state
-    for each row execute procedure immutable_columns('session_id', 'state', 'start_time', 'previous_end_time');
+  -- worker_auth_certificate contains the certificates issued to workers. A
+  -- worker connection is refused if its certificate is not found here or has
+  -- been revoked.
+  create table worker_auth_certificate (
+    serial_number text primary key,
+    worker_name text not null
+      constraint worker_name_must_not_be_empty
+      check(length(trim(worker_name)) > 0),
+    ca_id wt_private_id
+      references worker_auth_ca(private_id)
+      on delete cascade
+      on update cascade,
+    not_after wt_timestamp not null,
+    revoked_time timestamp with time zone,
+    create_time wt_timestamp
+  );
 
-    
-  create or replace function
-    insert_session_state()
-    returns trigger
-  as $$
-  begin
+  create index worker_auth_certificate_worker_name_idx
+    on worker_auth_certificate (worker_name);
 
-    update session_state
-       set end_time = now()
-     where session_id = new.session_id
-       and end_time is null;
+commit;
 
-    if not found then
-      new.previous_end_time = null;
-      new.start_time = now();
-      new.end_time = null;
-      return new;
-    end if;
+`),
+	},
+	"migrations/73_server_drain.down.sql": {
+		name: "73_server_drain.down.sql",
+		bytes: []byte(`
+begin;
 
-    new.previous_end_time = now();
-    new.start_time = now();
-    new.end_time = null;
-    return new;
+  alter table server
+    drop column draining;
 
-  end;
-  $$ language plpgsql;
+commit;
 
+`),
+	},
+	"migrations/73_server_drain.up.sql": {
+		name: "73_server_drain.up.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger insert_session_state before insert on session_state
-    for each row execute procedure insert_session_state();
+  -- A draining worker is not assigned new sessions but continues to handle
+  -- its existing sessions. It is set by an operator and not changed by the
+  -- worker's status updates.
+  alter table server
+    add column draining boolean not null default false;
 
-  create view session_with_state as
-  select
-    s.public_id,
-    s.user_id,
-    s.host_id,
-    s.server_id,
-    s.server_type,
-    s.target_id,
-    s.host_set_id,
-    s.auth_token_id,
-    s.scope_id,
-    s.certificate,
-    s.expiration_time,
-    s.connection_limit,
-    s.tofu_token,
-    s.key_id,
-    s.termination_reason,
-    s.version,
-    s.create_time,
-    s.update_time,
-    s.endpoint,
-    ss.state,
-    ss.previous_end_time,
-    ss.start_time,
-    ss.end_time
-  from  
-    session s,
-    session_state ss
-  where 
-    s.public_id = ss.session_id;
+commit;
+
+`),
+	},
+	"migrations/74_controller_lease.down.sql": {
+		name: "74_controller_lease.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table controller_lease;
 
 commit;
 
 `),
 	},
-	"migrations/51_connection.down.sql": {
-		name: "51_connection.down.sql",
+	"migrations/74_controller_lease.up.sql": {
+		name: "74_controller_lease.up.sql",
 		bytes: []byte(`
 begin;
 
-  drop table session_connection_state;
-  drop table session_connection_state_enm;
-  drop table session_connection;
-  drop table session_connection_closed_reason_enm;
-  drop function insert_session_connection_state;
-  drop function insert_new_connection_state;
-  drop function update_connection_state_on_closed_reason;
+  -- controller_lease contains the leases controllers hold to coordinate work
+  -- which must only be done by one controller at a time. A lease is held by
+  -- the named controller until its expiration time; the holder extends the
+  -- expiration while it is running and any controller may take the lease
+  -- once it has expired. Times are set from the database's clock so the
+  -- clocks of the controllers do not need to agree.
+  create table controller_lease (
+    name text primary key
+      constraint name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    holder text not null
+      constraint holder_must_not_be_empty
+      check(length(trim(holder)) > 0),
+    acquire_time wt_timestamp not null,
+    expiration_time wt_timestamp not null,
+    constraint expiration_time_must_be_after_acquire_time
+      check(expiration_time > acquire_time)
+  );
+
 commit;
 
 `),
 	},
-	"migrations/51_connection.up.sql": {
-		name: "51_connection.up.sql",
+	"migrations/75_job.down.sql": {
+		name: "75_job.down.sql",
 		bytes: []byte(`
 begin;
 
-/*
+  drop table job;
 
-               ┌────────────────┐
-               │    session     │
-               ├────────────────┤
-               │ public_id (pk) │
-               │                │
-               │                │
-               └────────────────┘
-                   ▲fk1 ┼
-                        ┼
-                        │
-                        │
-                        ┼
-                       ╱│╲                  ┌──────────────────────────────────────┐
-            ┌───────────────────────┐       │ session_connection_closed_reason_enm │
-            │  session_connection   │╲ fk2▶ ├──────────────────────────────────────┤
-            ├───────────────────────┤─○───○┼│ name                                 │
-            │ public_id     (pk)    │╱      └──────────────────────────────────────┘
-            │ session_id    (fk1)   │
-            │ closed_reason (fk2)   │
-            └───────────────────────┘
-                   ▲fk1 ┼
-                        ┼
-                        │
-                        │
-                        ┼
-                       ╱│╲
-  ┌──────────────────────────────────────────┐
-  │         session_connection_state         │
-  ├──────────────────────────────────────────┤┼○┐
-  │ connection_id     (pk,fk1,fk2,unq1,unq2) │  │◀fk2
-  │ state             (fk3)                  │  │
-  │ previous_end_time (fk2,unq1)             │┼○┘
-  │ start_time        (pk)                   │
-  │ end_time          (unq2)                 │
-  └──────────────────────────────────────────┘
-                       ╲│╱
-                        ○
-                        │
-                        │
-                        ┼
-                  ▼fk3  ┼
-        ┌───────────────────────────────┐
-        │ session_connection_state_enm  │
-        ├───────────────────────────────┤
-        │ name                          │
-        └───────────────────────────────┘
+commit;
 
-*/
+`),
+	},
+	"migrations/75_job.up.sql": {
+		name: "75_job.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- job contains the background jobs registered with the controllers'
+  -- schedulers. A controller runs a job by claiming it once its next scheduled
+  -- run time has passed; the claim is held by setting running_server_id until
+  -- run_expiration_time, which the controller extends while the job runs. If
+  -- the controller stops, another controller can claim the job once the claim
+  -- has expired. Times are set from the database's clock so the clocks of the
+  -- controllers do not need to agree.
+  create table job (
+    name text primary key
+      constraint name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    description text not null,
+    next_scheduled_run wt_timestamp not null,
+    running_server_id text,
+    run_expiration_time timestamp with time zone,
+    last_run_start_time timestamp with time zone,
+    last_run_end_time timestamp with time zone,
+    last_run_status text
+      constraint last_run_status_must_be_valid
+      check(last_run_status in ('completed', 'failed', 'interrupted')),
+    last_run_error text,
+    failure_count integer not null default 0
+      constraint failure_count_must_be_zero_or_greater
+      check(failure_count >= 0),
+    create_time wt_timestamp,
+    constraint run_expiration_time_must_be_set_when_running
+      check((running_server_id is null) = (run_expiration_time is null))
+  );
+
+commit;
+
+`),
+	},
+	"migrations/76_api_rate_limit.down.sql": {
+		name: "76_api_rate_limit.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table api_rate_limit_bucket;
+
+commit;
+
+`),
+	},
+	"migrations/76_api_rate_limit.up.sql": {
+		name: "76_api_rate_limit.up.sql",
+		bytes: []byte(`
+begin;
 
-  create table session_connection_closed_reason_enm (
-    name text primary key
-      constraint only_predefined_session_connection_closed_reasons_allowed
-      check (
-        name in (
-          'unknown',
-          'timed out',
-          'closed by end-user',
-          'canceled',
-          'network error',
-          'system error'
-        )
-      )
+  -- api_rate_limit_bucket contains the token buckets used to rate limit API
+  -- requests when controllers share their rate limits through the database.
+  -- Each bucket holds up to its burst of tokens and is refilled at its rate
+  -- when it is next used. allowed records whether the last request taking a
+  -- token from the bucket was allowed.
+  create table api_rate_limit_bucket (
+    key text primary key
+      constraint key_must_not_be_empty
+      check(length(trim(key)) > 0),
+    tokens double precision not null,
+    allowed boolean not null,
+    update_time wt_timestamp not null
   );
 
-  insert into session_connection_closed_reason_enm (name)
-  values
-    ('unknown'),
-    ('timed out'),
-    ('closed by end-user'),
-    ('canceled'),
-    ('network error'),
-    ('system error');
+  create index api_rate_limit_bucket_update_time_ix
+    on api_rate_limit_bucket (update_time);
 
-  -- A session connection is one connection proxied by a worker from a client to
-  -- a endpoint for a session. The client initiates the connection to the worker
-  -- and the worker initiates the connection to the endpoint.
-  -- A session can have zero or more session connections.
-  create table session_connection (
-    public_id wt_public_id primary key,
-    session_id wt_public_id not null
-      references session (public_id)
-      on delete cascade
-      on update cascade,
-    -- the client_tcp_address is the network address of the client which initiated
-    -- the connection to a worker
-    client_tcp_address inet,  -- maybe null on insert
-    -- the client_tcp_port is the network port at the address of the client the
-    -- worker proxied a connection for the user
-    client_tcp_port integer  -- maybe null on insert
-      constraint client_tcp_port_must_be_greater_than_0
-      check(client_tcp_port > 0)
-      constraint client_tcp_port_must_less_than_or_equal_to_65535
-      check(client_tcp_port <= 65535),
-    -- the endpoint_tcp_address is the network address of the endpoint which the
-    -- worker initiated the connection to, for the user
-    endpoint_tcp_address inet, -- maybe be null on insert
-    -- the endpoint_tcp_port is the network port at the address of the endpoint the
-    -- worker proxied a connection to, for the user
-    endpoint_tcp_port integer -- maybe null on insert
-      constraint endpoint_tcp_port_must_be_greater_than_0
-      check(endpoint_tcp_port > 0)
-      constraint endpoint_tcp_port_must_less_than_or_equal_to_65535
-      check(endpoint_tcp_port <= 65535),
-    -- the total number of bytes received by the worker from the client and sent
-    -- to the endpoint for this connection
-    bytes_up bigint -- can be null
-      constraint bytes_up_must_be_null_or_a_non_negative_number
+commit;
+
+`),
+	},
+	"migrations/77_udp_proxy.down.sql": {
+		name: "77_udp_proxy.down.sql",
+		bytes: []byte(`
+begin;
+
+  alter table session_connection
+    drop column datagrams_up,
+    drop column datagrams_down;
+
+  drop view target_all_subtypes;
+
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type
+    from target_tcp;
+
+  alter table target_tcp
+    drop column protocol;
+
+commit;
+
+`),
+	},
+	"migrations/77_udp_proxy.up.sql": {
+		name: "77_udp_proxy.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- protocol is the transport protocol proxied to a tcp target's hosts. udp
+  -- targets relay datagrams rather than a stream, and their endpoints use the
+  -- udp scheme.
+  alter table target_tcp
+    add column protocol text not null default 'tcp'
+      constraint protocol_must_be_tcp_or_udp
+      check(protocol in ('tcp', 'udp'));
+
+  drop view target_all_subtypes;
+
+  -- target_all_subtypes is a union of all target subtypes
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type
+    from target_tcp;
+
+  -- the total number of datagrams received by the worker from the client and
+  -- sent to the endpoint for a udp connection, and the reverse. Connections
+  -- to tcp targets don't carry datagrams and record 0 when closed.
+  alter table session_connection
+    add column datagrams_up bigint -- can be null
+      constraint datagrams_up_must_be_null_or_a_non_negative_number
       check (
-        bytes_up is null
+        datagrams_up is null
         or
-        bytes_up >= 0
+        datagrams_up >= 0
       ),
-    -- the total number of bytes received by the worker from the endpoint and sent
-    -- to the client for this connection
-    bytes_down bigint -- can be null
-      constraint bytes_down_must_be_null_or_a_non_negative_number
+    add column datagrams_down bigint -- can be null
+      constraint datagrams_down_must_be_null_or_a_non_negative_number
       check (
-        bytes_down is null
+        datagrams_down is null
         or
-        bytes_down >= 0
-      ),
-    closed_reason text
-      references session_connection_closed_reason_enm (name)
-      on delete restrict
-      on update cascade,
-    version wt_version,
-    create_time wt_timestamp,
-    update_time wt_timestamp
-  );
+        datagrams_down >= 0
+      );
 
-  create trigger 
+commit;
+
+`),
+	},
+	"migrations/78_session_certificate_encryption.down.sql": {
+		name: "78_session_certificate_encryption.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger immutable_columns on session;
+
+  create trigger
     immutable_columns
   before
-  update on session_connection
-    for each row execute procedure immutable_columns('public_id', 'session_id', 'create_time');
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
 
-  create trigger 
-    update_version_column 
-  after update on session_connection
-    for each row execute procedure update_version_column();
-    
-  create trigger 
-    update_time_column 
-  before update on session_connection 
-    for each row execute procedure update_time_column();
-    
-  create trigger 
-    default_create_time_column
-  before
-  insert on session_connection
-    for each row execute procedure default_create_time();
+  alter table session
+    drop column certificate_key_id;
 
-  -- insert_new_connection_state() is used in an after insert trigger on the
-  -- session_connection table.  it will insert a state of "authorized" in
-  -- session_connection_state for the new session connection. 
-  create or replace function 
-    insert_new_connection_state()
-    returns trigger
-  as $$
-  begin
-    insert into session_connection_state (connection_id, state)
-    values
-      (new.public_id, 'authorized');
-    return new;
-  end;
-  $$ language plpgsql;
+commit;
 
-  create trigger 
-    insert_new_connection_state
-  after insert on session_connection
-    for each row execute procedure insert_new_connection_state();
+`),
+	},
+	"migrations/78_session_certificate_encryption.up.sql": {
+		name: "78_session_certificate_encryption.up.sql",
+		bytes: []byte(`
+begin;
 
-  -- update_connection_state_on_closed_reason() is used in an update trigger on the
-  -- session_connection table.  it will insert a state of "closed" in
-  -- session_connection_state for the closed session connection. 
-  create or replace function 
-    update_connection_state_on_closed_reason()
-    returns trigger
-  as $$
-  begin
-    if new.closed_reason is not null then
-      -- check to see if there's a closed state already, before inserting a
-      -- new one.
-      perform from
-        session_connection_state cs
-      where
-        cs.connection_id = new.public_id and 
-        cs.state = 'closed';
-      if not found then 
-        insert into session_connection_state (connection_id, state)
-        values
-          (new.public_id, 'closed');
-      end if;
-      -- whenever we close a connection, we want to terminate the session if
-      -- possible.  
-      perform terminate_session_if_possible(new.session_id);
-    end if;
-    return new;
-  end;
-  $$ language plpgsql;
+  -- certificate_key_id is the id of the scope's database key the session's
+  -- certificate is encrypted with. It is null for sessions created before
+  -- certificates were encrypted, whose certificate is stored as is.
+  alter table session
+    add column certificate_key_id text -- can be null
+      constraint certificate_key_id_must_be_null_or_not_empty
+      check(
+        certificate_key_id is null
+        or
+        length(trim(certificate_key_id)) > 0
+      );
 
-  create trigger 
-    update_connection_state_on_closed_reason
-  after update of closed_reason on session_connection
-    for each row execute procedure update_connection_state_on_closed_reason();
+  drop trigger immutable_columns on session;
 
-  create table session_connection_state_enm (
-    name text primary key
-      constraint only_predefined_session_connection_states_allowed
-      check (
-        name in ('authorized', 'connected', 'closed')
-      )
-  );
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
 
-  insert into session_connection_state_enm (name)
-  values
-    ('authorized'),
-    ('connected'),
-    ('closed');
+commit;
 
+`),
+	},
+	"migrations/79_connection_idle_timeout.down.sql": {
+		name: "79_connection_idle_timeout.down.sql",
+		bytes: []byte(`
+begin;
 
-  create table session_connection_state (
-    connection_id wt_public_id not null
-      references session_connection (public_id)
-      on delete cascade
-      on update cascade,
-    state text not null
-      references session_connection_state_enm(name)
-      on delete restrict
-      on update cascade,
-    previous_end_time timestamp with time zone, -- fk2 -- null means first state
-    start_time timestamp with time zone default current_timestamp not null,
-      constraint previous_end_time_and_start_time_in_sequence
-        check (previous_end_time <= start_time),
-    end_time timestamp with time zone, -- null means unfinished current state
-      constraint start_and_end_times_in_sequence
-        check (start_time <= end_time),
-      constraint end_times_in_sequence
-        check (previous_end_time <> end_time),
-    primary key (connection_id, start_time),
-    unique (connection_id, previous_end_time), -- null means first state
-    unique (connection_id, end_time), -- one null current state
-    foreign key (connection_id, previous_end_time) -- self-reference
-      references session_connection_state (connection_id, end_time)
-  );
+  delete from session_connection_closed_reason_enm
+   where name = 'idle-timeout';
 
-  create trigger 
-    immutable_columns
-  before
-  update on session_connection_state
-    for each row execute procedure immutable_columns('connection_id', 'state', 'start_time', 'previous_end_time');
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed,
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error'
+        )
+      );
 
-  create or replace function
-    insert_session_connection_state()
-    returns trigger
-  as $$
-  begin
+  alter table session_connection
+    drop column last_activity_time;
 
-    update session_connection_state
-       set end_time = now()
-     where connection_id = new.connection_id
-       and end_time is null;
+  drop view target_all_subtypes;
+
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type
+    from target_tcp;
+
+  alter table target_tcp
+    drop column connection_idle_timeout_seconds;
 
-    if not found then
-      new.previous_end_time = null;
-      new.start_time = now();
-      new.end_time = null;
-      return new;
-    end if;
+commit;
 
-    new.previous_end_time = now();
-    new.start_time = now();
-    new.end_time = null;
-    return new;
+`),
+	},
+	"migrations/79_connection_idle_timeout.up.sql": {
+		name: "79_connection_idle_timeout.up.sql",
+		bytes: []byte(`
+begin;
 
-  end;
-  $$ language plpgsql;
+  -- connection_idle_timeout_seconds is how long a connection to one of the
+  -- target's hosts can go without any traffic before it's closed. 0 means
+  -- connections are never closed for being idle.
+  alter table target_tcp
+    add column connection_idle_timeout_seconds integer not null default 0
+      constraint connection_idle_timeout_seconds_must_not_be_negative
+      check(connection_idle_timeout_seconds >= 0);
 
-  create trigger insert_session_connection_state before insert on session_connection_state
-    for each row execute procedure insert_session_connection_state();
+  drop view target_all_subtypes;
 
--- terminate_session_if_possible takes a session id and terminates the session
--- if the following conditions are met:
---    * the session is expired and all its connections are closed.
---    * the session is canceling and all its connections are closed
---    * the session has exhausted its connection limit and all its connections
---      are closed.  
---
---      Note: this function should align closely with the domain function
---      TerminateCompletedSessions 
-create or replace function 
-    terminate_session_if_possible(terminate_session_id text)
-    returns void
-  as $$
-  begin 
-    -- is terminate_session_id in a canceling state
-    with canceling_session(session_id) as
-    (
-      select 
-        session_id
-      from
-        session_state ss
-      where 
-        ss.session_id = terminate_session_id and
-        ss.state = 'canceling' and 
-        ss.end_time is null
-    )
-    update session us
-      set termination_reason = 
-      case 
-        -- timed out sessions
-        when now() > us.expiration_time then 'timed out'
-        -- canceling sessions
-        when us.public_id in(
-          select 
-            session_id 
-          from 
-            canceling_session cs 
-          where
-            us.public_id = cs.session_id
-          ) then 'canceled' 
-        -- default: session connection limit reached.
-        else 'connection limit'
-      end
-    where
-      -- limit update to just the terminating_session_id
-      us.public_id = terminate_session_id and
-      termination_reason is null and
-      -- session expired or connection limit reached
-      (
-        -- expired sessions...
-        now() > us.expiration_time or 
-        -- connection limit reached...
-        (
-          -- handle unlimited connections...
-          connection_limit != -1 and
-          (
-            select count (*) 
-              from session_connection sc 
-            where 
-              sc.session_id = us.public_id
-          ) >= connection_limit
-        ) or 
-        -- canceled sessions
-        us.public_id in (
-          select 
-            session_id
-          from
-            canceling_session cs
-          where 
-            us.public_id = cs.session_id 
-        )
-      ) and 
-      -- make sure there are no existing connections
-      us.public_id not in (
-        select 
-          session_id 
-        from 
-            session_connection
-          where public_id in (
-          select 
-            connection_id
-          from 
-            session_connection_state
-          where 
-            state != 'closed' and
-            end_time is null
+  -- target_all_subtypes is a union of all target subtypes
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type
+    from target_tcp;
+
+  -- last_activity_time is when traffic was last proxied for the connection,
+  -- as reported by the worker proxying it. It's only updated about once a
+  -- minute while there is traffic.
+  alter table session_connection
+    add column last_activity_time timestamp with time zone; -- can be null
+
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed,
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error',
+          'idle-timeout'
         )
-    );
- end;
-  $$ language plpgsql;
+      );
 
+  insert into session_connection_closed_reason_enm (name)
+  values
+    ('idle-timeout');
 
 commit;
 
 `),
 	},
-	"migrations/60_wh_domain_types.down.sql": {
-		name: "60_wh_domain_types.down.sql",
+	"migrations/80_session_worker_token.down.sql": {
+		name: "80_session_worker_token.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop function wh_current_time_id;
-  drop function wh_current_date_id;
-  drop function wh_time_id;
-  drop function wh_date_id;
-  drop domain wh_dim_text;
-  drop domain wh_timestamp;
-  drop domain wh_public_id;
-  drop domain wh_dim_id;
-  drop function wh_dim_id;
-  drop domain wh_bytes_transmitted;
-  drop domain wh_inet_port;
+  drop table auth_token_session;
 
 commit;
 
 `),
 	},
-	"migrations/60_wh_domain_types.up.sql": {
-		name: "60_wh_domain_types.up.sql",
+	"migrations/80_session_worker_token.up.sql": {
+		name: "80_session_worker_token.up.sql",
 		bytes: []byte(`
 begin;
 
-  create extension if not exists "pgcrypto";
-
-  create domain wh_inet_port as integer
-  check(
-    value > 0
-    and
-    value <= 65535
+  -- auth_token_session holds the tokens workers are given when they look up
+  -- a session, which they must present to update the session's status on
+  -- behalf of its user. Only a hash of each token is stored as the tokens are
+  -- never returned once they are created.
+  create table auth_token_session (
+    public_id wt_public_id primary key,
+    session_id wt_public_id not null
+      references session (public_id)
+      on delete cascade
+      on update cascade,
+    token_hash bytea not null unique,
+    create_time wt_timestamp,
+    expiration_time timestamp with time zone not null
+      constraint expiration_time_must_be_after_create_time
+      check(expiration_time > create_time)
   );
-  comment on domain wh_inet_port is
-  'An ordinal number between 1 and 65535 representing a network port';
 
-  create domain wh_bytes_transmitted as bigint
-  check(
-    value >= 0
-  );
-  comment on domain wh_bytes_transmitted is
-  'A non-negative integer representing the number of bytes transmitted';
+  create index auth_token_session_session_id_ix
+    on auth_token_session (session_id);
 
-  -- wh_dim_id generates and returns a random ID which should be considered as
-  -- universally unique.
-  create or replace function wh_dim_id()
-    returns text
-  as $$
-    select encode(digest(gen_random_bytes(16), 'sha256'), 'base64');
-  $$ language sql;
+  create trigger immutable_columns before update on auth_token_session
+    for each row execute procedure immutable_columns('public_id', 'session_id', 'token_hash', 'create_time', 'expiration_time');
 
-  create domain wh_dim_id as text
-  check(
-    length(trim(value)) > 0
-  );
-  comment on domain wh_dim_id is
-  'Random ID generated with pgcrypto';
+  create trigger default_create_time_column before insert on auth_token_session
+    for each row execute procedure default_create_time();
 
-  create domain wh_public_id as text
-  check(
-    value = 'None'
-    or
-    length(trim(value)) > 10
-  );
-  comment on domain wh_public_id is
-  'Equivalent to wt_public_id but also allows the value to be ''None''';
+commit;
 
-  create domain wh_timestamp as timestamp with time zone not null;
-  comment on domain wh_timestamp is
-  'Timestamp used in warehouse tables';
+`),
+	},
+	"migrations/81_connection_protocol_metadata.down.sql": {
+		name: "81_connection_protocol_metadata.down.sql",
+		bytes: []byte(`
+begin;
 
-  create domain wh_dim_text as text not null
-  check(
-    length(trim(value)) > 0
-  );
-  comment on domain wh_dim_text is
-  'Text fields in dimension tables are always not null and always not empty strings';
+  drop index session_connection_protocol_ix;
 
-  -- wh_date_id returns the wh_date_dimension id for ts.
-  create or replace function wh_date_id(ts wh_timestamp)
-    returns integer
-  as $$
-    select to_char(ts, 'YYYYMMDD')::integer;
-  $$ language sql;
+  alter table session_connection
+    drop column protocol_metadata,
+    drop column protocol;
 
-  -- wh_time_id returns the wh_time_of_day_dimension id for ts.
-  create or replace function wh_time_id(ts wh_timestamp)
-    returns integer
-  as $$
-    select to_char(ts, 'SSSS')::integer;
-  $$ language sql;
+commit;
 
-  -- wh_date_id returns the wh_date_dimension id for current_timestamp.
-  create or replace function wh_current_date_id()
-    returns integer
-  as $$
-    select wh_date_id(current_timestamp);
-  $$ language sql;
+`),
+	},
+	"migrations/81_connection_protocol_metadata.up.sql": {
+		name: "81_connection_protocol_metadata.up.sql",
+		bytes: []byte(`
+begin;
 
-  -- wh_time_id returns the wh_time_of_day_dimension id for current_timestamp.
-  create or replace function wh_current_time_id()
-    returns integer
-  as $$
-    select wh_time_id(current_timestamp);
-  $$ language sql;
+  -- protocol is the application protocol the worker detected being spoken
+  -- over the connection, such as ssh, and protocol_metadata is what the
+  -- worker learned about it when the connection was made, such as the
+  -- software version the endpoint identified itself with. Both are null when
+  -- the worker couldn't detect the protocol.
+  alter table session_connection
+    add column protocol text -- can be null
+      constraint protocol_must_not_be_empty
+      check(length(trim(protocol)) > 0),
+    add column protocol_metadata jsonb -- can be null
+      constraint protocol_metadata_must_be_an_object
+      check(jsonb_typeof(protocol_metadata) = 'object');
+
+  create index session_connection_protocol_ix
+    on session_connection (protocol);
 
 commit;
 
 `),
 	},
-	"migrations/62_wh_datetime.down.sql": {
-		name: "62_wh_datetime.down.sql",
+	"migrations/82_resource_scope.down.sql": {
+		name: "82_resource_scope.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table wh_time_of_day_dimension;
-  drop table wh_date_dimension;
+  drop view resource_scope;
 
 commit;
 
 `),
 	},
-	"migrations/62_wh_datetime.up.sql": {
-		name: "62_wh_datetime.up.sql",
+	"migrations/82_resource_scope.up.sql": {
+		name: "82_resource_scope.up.sql",
 		bytes: []byte(`
 begin;
 
-  create table wh_date_dimension (
-    id                            integer      primary key,
-    date                          date         not null,
-    calendar_quarter              wh_dim_text,
-    calendar_month                wh_dim_text,
-    calendar_year                 smallint     not null,
-    day_of_week                   wh_dim_text,
-    day_of_week_number            smallint     not null,
-    day_of_week_number_iso        smallint     not null,
-    day_of_week_number_zero_based smallint     not null,
-    day_number_in_calendar_month  smallint     not null,
-    day_number_in_calendar_year   smallint     not null,
-    weekday_indicator             wh_dim_text
-  );
-
-  insert into wh_date_dimension (
-    id, date,
-    calendar_quarter, calendar_month, calendar_year,
-    day_of_week, day_of_week_number, day_of_week_number_iso, day_of_week_number_zero_based,
-    day_number_in_calendar_month, day_number_in_calendar_year,
-    weekday_indicator
-  ) values (
-    -1, 'infinity',
-    'None', 'None', -1,
-    'None', -1, -1, -1,
-    -1, -1,
-    'None'
-  );
-
-  insert
-    into wh_date_dimension
-  select to_char(t.day, 'YYYYMMDD')::integer as id,
-         t.day::date                         as date,
-         'Q' || to_char(t.day, 'Q')          as calendar_quarter,
-         to_char(t.day, 'Month')             as calendar_month,
-         extract(year from t.day)            as calendar_year,
-         to_char(t.day, 'Day')               as day_of_week,
-         to_char(t.day, 'D')::int            as day_of_week_number,
-         extract(isodow from t.day)          as day_of_week_number_iso,
-         extract(dow from t.day)             as day_of_week_number_zero_based,
-         extract(day from t.day)             as day_number_in_calendar_month,
-         extract(doy from t.day)             as day_number_in_calendar_year,
-         case extract(isodow from t.day)
-           when 6 then 'Weekend'
-           when 7 then 'Weekend'
-           else 'Weekday'
-         end                                 as weekday_indicator
-    from generate_series(
-           date_trunc('day', timestamp '2019-10-09'),
-           date_trunc('day', timestamp '2019-10-09' + interval '50 years'),
-           interval '1 day'
-         ) as t(day);
+  -- resource_scope is the scope of each resource which belongs to one,
+  -- including resources such as hosts whose scope is that of their parent.
+  -- Repositories use it to check within a transaction that the resources an
+  -- operation references are in the scope the operation is performed in.
+  create view resource_scope
+  as
+  select public_id, scope_id from iam_user
+   union all
+  select public_id, scope_id from iam_group
+   union all
+  select public_id, scope_id from iam_role
+   union all
+  select public_id, scope_id from auth_method
+   union all
+  select public_id, scope_id from auth_account
+   union all
+  select at.public_id, aa.scope_id
+    from auth_token at
+    join auth_account aa on aa.public_id = at.auth_account_id
+   union all
+  select public_id, scope_id from host_catalog
+   union all
+  select h.public_id, hc.scope_id
+    from host h
+    join host_catalog hc on hc.public_id = h.catalog_id
+   union all
+  select hs.public_id, hc.scope_id
+    from host_set hs
+    join host_catalog hc on hc.public_id = hs.catalog_id
+   union all
+  select public_id, scope_id from target
+   union all
+  select public_id, scope_id from session;
 
-  create table wh_time_of_day_dimension (
-    id                 integer      primary key,
-    time_no_zone       time         not null,
-    time_at_utc        timetz       not null,
-    hour_of_day        smallint     not null,
-    minute_of_hour     smallint     not null,
-    second_of_minute   smallint     not null,
-    display_time_24    wh_dim_text,
-    display_time_12    wh_dim_text,
-    meridiem_indicator wh_dim_text
-  );
+commit;
 
-  set timezone = 'UTC';
+`),
+	},
+	"migrations/83_name_lower_index.down.sql": {
+		name: "83_name_lower_index.down.sql",
+		bytes: []byte(`
+begin;
 
-  insert into wh_time_of_day_dimension (
-    id, time_no_zone, time_at_utc,
-    hour_of_day, minute_of_hour, second_of_minute,
-    display_time_24, display_time_12, meridiem_indicator
-  ) values (
-    -1, 'allballs', 'allballs',
-    -1, -1, -1,
-    'None', 'None', 'None'
-  );
+  drop index iam_role_scope_id_lower_name_ix;
+  drop index target_tcp_scope_id_lower_name_ix;
 
-  insert
-    into wh_time_of_day_dimension
-  select to_char(t.second, 'SSSS')::integer as id,
-         t.second::time                     as time_no_zone,
-         t.second::time                     as time_at_utc,
-         extract(hour from t.second)        as hour_of_day,
-         extract(minute from t.second)      as minute_of_hour,
-         extract(second from t.second)      as second_of_minute,
-         to_char(t.second, 'HH24:MI:SS')    as display_time_24,
-         to_char(t.second, 'HH12:MI:SS AM') as display_time_12,
-         to_char(t.second, 'PM')            as meridiem_indicator
-    from generate_series(
-           date_trunc('day', current_timestamp),
-           date_trunc('day', current_timestamp) + interval '24 hours' - interval '1 second',
-           interval '1 second'
-         ) as t(second);
+commit;
+
+`),
+	},
+	"migrations/83_name_lower_index.up.sql": {
+		name: "83_name_lower_index.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- Names are unique within a scope when compared exactly, which the
+  -- unique(scope_id, name) constraints enforce. Controllers configured with
+  -- enforce_unique_names also reject names which only differ by case, and
+  -- these indexes make looking up such a conflicting name cheap.
+  create index target_tcp_scope_id_lower_name_ix
+    on target_tcp (scope_id, lower(name));
+
+  create index iam_role_scope_id_lower_name_ix
+    on iam_role (scope_id, lower(name));
 
 commit;
 
 `),
 	},
-	"migrations/65_wh_session_dimensions.down.sql": {
-		name: "65_wh_session_dimensions.down.sql",
+	"migrations/84_search_resource.down.sql": {
+		name: "84_search_resource.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop view whx_user_dimension_target;
-  drop view whx_user_dimension_source;
-  drop view whx_host_dimension_target;
-  drop view whx_host_dimension_source;
-  drop table wh_user_dimension;
-  drop table wh_host_dimension;
+  drop view search_resource;
 
 commit;
 
 `),
 	},
-	"migrations/65_wh_session_dimensions.up.sql": {
-		name: "65_wh_session_dimensions.up.sql",
+	"migrations/84_search_resource.up.sql": {
+		name: "84_search_resource.up.sql",
 		bytes: []byte(`
 begin;
 
-  create table wh_host_dimension (
-    -- random id generated using encode(digest(gen_random_bytes(16), 'sha256'), 'base64')
-    -- this is done to prevent conflicts with rows in other clusters
-    -- which enables warehouse data from multiple clusters to be loaded into a
-    -- single database instance
-    id                              wh_dim_id     primary key default wh_dim_id(),
+  -- search_resource is the searchable fields of each type of resource which
+  -- can be searched for. owner_id is the user the resource belongs to, if
+  -- any, and parent_id the resource it's a child of, if any, such as the
+  -- host catalog of a host. The search repository matches a query against
+  -- the name, description, public_id and address of the resources.
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
 
-    host_id                         wh_public_id  not null,
-    host_type                       wh_dim_text,
-    host_name                       wh_dim_text,
-    host_description                wh_dim_text,
-    host_address                    wh_dim_text,
+commit;
 
-    host_set_id                     wh_public_id  not null,
-    host_set_type                   wh_dim_text,
-    host_set_name                   wh_dim_text,
-    host_set_description            wh_dim_text,
+`),
+	},
+	"migrations/85_session_client_info.down.sql": {
+		name: "85_session_client_info.down.sql",
+		bytes: []byte(`
+begin;
 
-    host_catalog_id                 wh_public_id  not null,
-    host_catalog_type               wh_dim_text,
-    host_catalog_name               wh_dim_text,
-    host_catalog_description        wh_dim_text,
+  drop view session_with_state;
 
-    target_id                       wh_public_id  not null,
-    target_type                     wh_dim_text,
-    target_name                     wh_dim_text,
-    target_description              wh_dim_text,
-    target_default_port_number      integer       not null,
-    target_session_max_seconds      integer       not null,
-    target_session_connection_limit integer       not null,
+  create view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
 
-    project_id                      wt_scope_id   not null,
-    project_name                    wh_dim_text,
-    project_description             wh_dim_text,
+  drop trigger immutable_columns on session;
 
-    host_organization_id            wt_scope_id   not null,
-    host_organization_name          wh_dim_text,
-    host_organization_description   wh_dim_text,
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
 
-    current_row_indicator           wh_dim_text,
-    row_effective_time              wh_timestamp,
-    row_expiration_time             wh_timestamp
-  );
+  drop index session_client_ip_ix;
 
-  -- https://www.postgresql.org/docs/current/indexes-partial.html
-  create unique index wh_host_dim_current_constraint
-    on wh_host_dimension (target_id, host_set_id, host_id)
-    where current_row_indicator = 'Current';
+  alter table session
+    drop column user_agent,
+    drop column client_ip;
 
-  -- The whx_host_dimension_source and whx_host_dimension_target views are used
-  -- by an insert trigger to determine if the current row for the dimension has
-  -- changed and new one needs to be inserted. The first column in the target
-  -- view must be the current warehouse id and all remaining columns must match
-  -- the columns in the source view.
+commit;
 
-  -- The whx_host_dimension_source view shows the current values in the
-  -- operational tables of the host dimension.
-  create view whx_host_dimension_source as
-  select -- id is the first column in the target view
-         h.public_id                     as host_id,
-         'static host'                   as host_type,
-         coalesce(h.name, 'None')        as host_name,
-         coalesce(h.description, 'None') as host_description,
-         coalesce(h.address, 'Unknown')  as host_address,
-         s.public_id                     as host_set_id,
-         'static host set'               as host_set_type,
-         coalesce(s.name, 'None')        as host_set_name,
-         coalesce(s.description, 'None') as host_set_description,
-         c.public_id                     as host_catalog_id,
-         'static host catalog'           as host_catalog_type,
-         coalesce(c.name, 'None')        as host_catalog_name,
-         coalesce(c.description, 'None') as host_catalog_description,
-         t.public_id                     as target_id,
-         'tcp target'                    as target_type,
-         coalesce(t.name, 'None')        as target_name,
-         coalesce(t.description, 'None') as target_description,
-         coalesce(t.default_port, 0)     as target_default_port_number,
-         t.session_max_seconds           as target_session_max_seconds,
-         t.session_connection_limit      as target_session_connection_limit,
-         p.public_id                     as project_id,
-         coalesce(p.name, 'None')        as project_name,
-         coalesce(p.description, 'None') as project_description,
-         o.public_id                     as host_organization_id,
-         coalesce(o.name, 'None')        as host_organization_name,
-         coalesce(o.description, 'None') as host_organization_description
-    from static_host as h,
-         static_host_catalog as c,
-         static_host_set_member as m,
-         static_host_set as s,
-         target_host_set as ts,
-         target_tcp as t,
-         iam_scope as p,
-         iam_scope as o
-   where h.catalog_id = c.public_id
-     and h.public_id = m.host_id
-     and s.public_id = m.set_id
-     and t.public_id = ts.target_id
-     and s.public_id = ts.host_set_id
-     and p.public_id = t.scope_id
-     and p.type = 'project'
-     and o.public_id = p.parent_id
-     and o.type = 'org'
-  ;
+`),
+	},
+	"migrations/85_session_client_info.up.sql": {
+		name: "85_session_client_info.up.sql",
+		bytes: []byte(`
+begin;
 
-  -- The whx_host_dimension_target view shows the rows in the wh_host_dimension
-  -- table marked as 'Current'.
-  create view whx_host_dimension_target as
-  select id,
-         host_id,
-         host_type,
-         host_name,
-         host_description,
-         host_address,
-         host_set_id,
-         host_set_type,
-         host_set_name,
-         host_set_description,
-         host_catalog_id,
-         host_catalog_type,
-         host_catalog_name,
-         host_catalog_description,
-         target_id,
-         target_type,
-         target_name,
-         target_description,
-         target_default_port_number,
-         target_session_max_seconds,
-         target_session_connection_limit,
-         project_id,
-         project_name,
-         project_description,
-         host_organization_id,
-         host_organization_name,
-         host_organization_description
-    from wh_host_dimension
-   where current_row_indicator = 'Current'
-  ;
+  -- client_ip and user_agent are the source address and user agent of the
+  -- API request which authorized the session. They are null for sessions
+  -- authorized before they were recorded.
+  alter table session
+    add column client_ip text -- can be null
+      constraint client_ip_must_be_null_or_not_empty
+      check(
+        client_ip is null
+        or
+        length(trim(client_ip)) > 0
+      ),
+    add column user_agent text -- can be null
+      constraint user_agent_must_be_null_or_not_empty
+      check(
+        user_agent is null
+        or
+        length(trim(user_agent)) > 0
+      )
+      constraint user_agent_must_not_be_too_long
+      check(length(user_agent) <= 1024);
 
-  create table wh_user_dimension (
-    -- random id generated using encode(digest(gen_random_bytes(16), 'sha256'), 'base64')
-    -- this is done to prevent conflicts with rows in other clusters
-    -- which enables warehouse data from multiple clusters to be loaded into a
-    -- single database instance
-    id                            wh_dim_id     primary key default wh_dim_id(),
+  create index session_client_ip_ix
+    on session (client_ip);
 
-    user_id                       wt_user_id    not null,
-    user_name                     wh_dim_text,
-    user_description              wh_dim_text,
+  drop trigger immutable_columns on session;
 
-    auth_account_id               wh_public_id  not null,
-    auth_account_type             wh_dim_text,
-    auth_account_name             wh_dim_text,
-    auth_account_description      wh_dim_text,
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent');
 
-    auth_method_id                wh_public_id  not null,
-    auth_method_type              wh_dim_text,
-    auth_method_name              wh_dim_text,
-    auth_method_description       wh_dim_text,
+  create or replace view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
 
-    user_organization_id          wt_scope_id   not null,
-    user_organization_name        wh_dim_text,
-    user_organization_description wh_dim_text,
+commit;
 
-    current_row_indicator         wh_dim_text,
-    row_effective_time            wh_timestamp,
-    row_expiration_time           wh_timestamp
-  );
+`),
+	},
+	"migrations/86_connection_bandwidth_limit.down.sql": {
+		name: "86_connection_bandwidth_limit.down.sql",
+		bytes: []byte(`
+begin;
 
-  -- The whx_user_dimension_source and whx_user_dimension_target views are used
-  -- by an insert trigger to determine if the current row for the dimension has
-  -- changed and new one needs to be inserted. The first column in the target
-  -- view must be the current warehouse id and all remaining columns must match
-  -- the columns in the source view.
+  drop view session_with_state;
 
-  -- The whx_user_dimension_source view shows the current values in the
-  -- operational tables of the user dimension.
-  create view whx_user_dimension_source as
-       select -- id is the first column in the target view
-              u.public_id                       as user_id,
-              coalesce(u.name, 'None')          as user_name,
-              coalesce(u.description, 'None')   as user_description,
-              coalesce(aa.public_id, 'None')    as auth_account_id,
-              case when aa.public_id is null then 'None'
-                   else 'password auth account'
-                   end                          as auth_account_type,
-              coalesce(apa.name, 'None')        as auth_account_name,
-              coalesce(apa.description, 'None') as auth_account_description,
-              coalesce(am.public_id, 'None')    as auth_method_id,
-              case when am.public_id is null then 'None'
-                   else 'password auth method'
-                   end                          as auth_method_type,
-              coalesce(apm.name, 'None')        as auth_method_name,
-              coalesce(apm.description, 'None') as auth_method_description,
-              org.public_id                     as user_organization_id,
-              coalesce(org.name, 'None')        as user_organization_name,
-              coalesce(org.description, 'None') as user_organization_description
-         from iam_user as u
-    left join auth_account as aa on           u.public_id = aa.iam_user_id
-    left join auth_method as am on            aa.auth_method_id = am.public_id
-    left join auth_password_account as apa on aa.public_id = apa.public_id
-    left join auth_password_method as apm on  am.public_id = apm.public_id
-         join iam_scope as org on             u.scope_id = org.public_id
-  ;
+  create view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
 
-  -- The whx_user_dimension_target view shows the rows in the wh_user_dimension
-  -- table marked as 'Current'.
-  create view whx_user_dimension_target as
-    select id,
-           user_id,
-           user_name,
-           user_description,
-           auth_account_id,
-           auth_account_type,
-           auth_account_name,
-           auth_account_description,
-           auth_method_id,
-           auth_method_type,
-           auth_method_name,
-           auth_method_description,
-           user_organization_id,
-           user_organization_name,
-           user_organization_description
-      from wh_user_dimension
-     where current_row_indicator = 'Current'
-  ;
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent');
+
+  alter table session
+    drop column connection_bandwidth_limit;
+
+  drop view search_resource;
+  drop view target_all_subtypes;
+
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+  alter table target_tcp
+    drop column connection_bandwidth_limit;
 
 commit;
 
 `),
 	},
-	"migrations/66_wh_session_dimensions.down.sql": {
-		name: "66_wh_session_dimensions.down.sql",
+	"migrations/86_connection_bandwidth_limit.up.sql": {
+		name: "86_connection_bandwidth_limit.up.sql",
 		bytes: []byte(`
 begin;
 
-  drop function wh_upsert_user;
-  drop function wh_upsert_host;
+  -- connection_bandwidth_limit is how many bytes per second each connection
+  -- to one of the target's hosts can proxy in each direction. 0 means
+  -- connections are not limited.
+  alter table target_tcp
+    add column connection_bandwidth_limit integer not null default 0
+      constraint connection_bandwidth_limit_must_not_be_negative
+      check(connection_bandwidth_limit >= 0);
+
+  -- search_resource depends on target_all_subtypes, so the column is added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit
+    from target_tcp;
+
+  -- the session's connection_bandwidth_limit is copied from its target when
+  -- the session is authorized, like its connection_limit, and is enforced by
+  -- the worker proxying the session's connections.
+  alter table session
+    add column connection_bandwidth_limit integer not null default 0
+      constraint connection_bandwidth_limit_must_not_be_negative
+      check(connection_bandwidth_limit >= 0);
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit');
+
+  create or replace view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
 
 commit;
 
 `),
 	},
-	"migrations/66_wh_session_dimensions.up.sql": {
-		name: "66_wh_session_dimensions.up.sql",
+	"migrations/87_session_list_indexes.down.sql": {
+		name: "87_session_list_indexes.down.sql",
 		bytes: []byte(`
 begin;
 
-  -- wh_upsert_host returns the wh_host_dimension id for p_host_id,
-  -- p_host_set_id, and p_target_id. wh_upsert_host compares the current values
-  -- in the wh_host_dimension with the current values in the operational tables
-  -- for the provide parameters. If the values between the operational tables
-  -- and the wh_host_dimension differ, a new row is inserted in the
-  -- wh_host_dimension to match the current values in the operational tables and
-  -- the new id is returned. If the values do not differ, the current id is
-  -- returned.
-  create or replace function wh_upsert_host(p_host_id wt_public_id, p_host_set_id wt_public_id, p_target_id wt_public_id)
-    returns wh_dim_id
-  as $$
-  declare
-    src     whx_host_dimension_target%rowtype;
-    target  whx_host_dimension_target%rowtype;
-    new_row wh_host_dimension%rowtype;
-  begin
-    select * into target
-      from whx_host_dimension_target as t
-     where t.host_id               = p_host_id
-       and t.host_set_id           = p_host_set_id
-       and t.target_id             = p_target_id;
-
-    select target.id, t.* into src
-      from whx_host_dimension_source as t
-     where t.host_id               = p_host_id
-       and t.host_set_id           = p_host_set_id
-       and t.target_id             = p_target_id;
-
-    if src is distinct from target then
+  drop index session_user_id_create_time_ix;
+  drop index session_scope_id_create_time_ix;
+  drop index session_state_session_id_start_time_ix;
 
-      -- expire the current row
-      update wh_host_dimension
-         set current_row_indicator = 'Expired',
-             row_expiration_time   = current_timestamp
-       where host_id               = p_host_id
-         and host_set_id           = p_host_set_id
-         and target_id             = p_target_id
-         and current_row_indicator = 'Current';
+commit;
 
-      -- insert a new row
-      insert into wh_host_dimension (
-             host_id,                    host_type,                  host_name,                       host_description,         host_address,
-             host_set_id,                host_set_type,              host_set_name,                   host_set_description,
-             host_catalog_id,            host_catalog_type,          host_catalog_name,               host_catalog_description,
-             target_id,                  target_type,                target_name,                     target_description,
-             target_default_port_number, target_session_max_seconds, target_session_connection_limit,
-             project_id,                 project_name,               project_description,
-             host_organization_id,       host_organization_name,     host_organization_description,
-             current_row_indicator,      row_effective_time,         row_expiration_time
-      )
-      select host_id,                    host_type,                  host_name,                       host_description,         host_address,
-             host_set_id,                host_set_type,              host_set_name,                   host_set_description,
-             host_catalog_id,            host_catalog_type,          host_catalog_name,               host_catalog_description,
-             target_id,                  target_type,                target_name,                     target_description,
-             target_default_port_number, target_session_max_seconds, target_session_connection_limit,
-             project_id,                 project_name,               project_description,
-             host_organization_id,       host_organization_name,     host_organization_description,
-             'Current',                  current_timestamp,          'infinity'::timestamptz
-        from whx_host_dimension_source
-       where host_id               = p_host_id
-         and host_set_id           = p_host_set_id
-         and target_id             = p_target_id
-      returning * into new_row;
+`),
+	},
+	"migrations/87_session_list_indexes.up.sql": {
+		name: "87_session_list_indexes.up.sql",
+		bytes: []byte(`
+begin;
 
-      return new_row.id;
-    end if;
-    return target.id;
+  -- session_state_session_id_start_time_ix covers the lateral join which
+  -- finds the current state of each session when sessions are listed, so
+  -- it's found with an index only scan of the session's newest state.
+  create index session_state_session_id_start_time_ix
+    on session_state (session_id, start_time desc)
+    include (state, previous_end_time, end_time);
 
-  end;
-  $$ language plpgsql;
+  -- sessions are listed within a scope, and for a user, newest first
+  create index session_scope_id_create_time_ix
+    on session (scope_id, create_time desc);
 
-  -- wh_upsert_user returns the wh_user_dimension id for p_user_id and
-  -- p_auth_token_id. wh_upsert_user compares the current values in the
-  -- wh_user_dimension with the current values in the operational tables for the
-  -- provide parameters. If the values between the operational tables and the
-  -- wh_user_dimension differ, a new row is inserted in the wh_user_dimension to
-  -- match the current values in the operational tables and the new id is
-  -- returned. If the values do not differ, the current id is returned.
-  create or replace function wh_upsert_user(p_user_id wt_user_id, p_auth_token_id wt_public_id)
-    returns wh_dim_id
-  as $$
-  declare
-    src     whx_user_dimension_target%rowtype;
-    target  whx_user_dimension_target%rowtype;
-    new_row wh_user_dimension%rowtype;
-    acct_id wt_public_id;
-  begin
-    select auth_account_id into strict acct_id
-      from auth_token
-     where public_id = p_auth_token_id;
+  create index session_user_id_create_time_ix
+    on session (user_id, create_time desc);
 
-    select * into target
-      from whx_user_dimension_target as t
-     where t.user_id               = p_user_id
-       and t.auth_account_id       = acct_id;
+commit;
 
-    select target.id, t.* into src
-      from whx_user_dimension_source as t
-     where t.user_id               = p_user_id
-       and t.auth_account_id       = acct_id;
+`),
+	},
+	"migrations/88_session_server_id_index.down.sql": {
+		name: "88_session_server_id_index.down.sql",
+		bytes: []byte(`
+begin;
 
-    if src is distinct from target then
+  drop index session_server_id_ix;
 
-      -- expire the current row
-      update wh_user_dimension
-         set current_row_indicator = 'Expired',
-             row_expiration_time   = current_timestamp
-       where user_id               = p_user_id
-         and auth_account_id       = acct_id
-         and current_row_indicator = 'Current';
+commit;
 
-      -- insert a new row
-      insert into wh_user_dimension (
-             user_id,               user_name,              user_description,
-             auth_account_id,       auth_account_type,      auth_account_name,             auth_account_description,
-             auth_method_id,        auth_method_type,       auth_method_name,              auth_method_description,
-             user_organization_id,  user_organization_name, user_organization_description,
-             current_row_indicator, row_effective_time,     row_expiration_time
-      )
-      select user_id,               user_name,              user_description,
-             auth_account_id,       auth_account_type,      auth_account_name,             auth_account_description,
-             auth_method_id,        auth_method_type,       auth_method_name,              auth_method_description,
-             user_organization_id,  user_organization_name, user_organization_description,
-             'Current',             current_timestamp,      'infinity'::timestamptz
-        from whx_user_dimension_source
-       where user_id               = p_user_id
-         and auth_account_id       = acct_id
-      returning * into new_row;
+`),
+	},
+	"migrations/88_session_server_id_index.up.sql": {
+		name: "88_session_server_id_index.up.sql",
+		bytes: []byte(`
+begin;
 
-      return new_row.id;
-    end if;
-    return target.id;
+  -- workers reconcile their sessions by listing those assigned to them
+  create index session_server_id_ix
+    on session (server_id);
 
-  end;
-  $$ language plpgsql;
 commit;
 
 `),
 	},
-	"migrations/68_wh_session_facts.down.sql": {
-		name: "68_wh_session_facts.down.sql",
+	"migrations/89_move_project.down.sql": {
+		name: "89_move_project.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table wh_session_connection_accumulating_fact;
-  drop table wh_session_accumulating_fact;
+  drop trigger iam_scope_move_project on iam_scope;
+  drop function iam_scope_move_project;
+
+  drop trigger immutable_columns on iam_scope;
+  create trigger immutable_columns
+  before
+  update on iam_scope
+    for each row execute procedure immutable_columns('public_id', 'create_time', 'type', 'parent_id');
 
 commit;
 
 `),
 	},
-	"migrations/68_wh_session_facts.up.sql": {
-		name: "68_wh_session_facts.up.sql",
+	"migrations/89_move_project.up.sql": {
+		name: "89_move_project.up.sql",
 		bytes: []byte(`
 begin;
 
-  -- Column names for numeric fields that are not a measurement end in id or
-  -- number. This naming convention enables automatic field type detection in
-  -- certain data analysis tools.
-  -- https://help.tableau.com/current/pro/desktop/en-us/data_clean_adm.htm
+  -- projects can be moved to another org by changing their parent_id, so it
+  -- is no longer immutable
+  drop trigger immutable_columns on iam_scope;
+  create trigger immutable_columns
+  before
+  update on iam_scope
+    for each row execute procedure immutable_columns('public_id', 'create_time', 'type');
+
+  -- iam_scope_move_project only allows the parent of projects to change, and
+  -- keeps iam_scope_project in step so the project's name must be unique in
+  -- its new org. A role in the old org which grants access to the project
+  -- would be left granting access to a project outside its org, so the
+  -- project can't be moved while there are any.
+  create or replace function
+    iam_scope_move_project()
+    returns trigger
+  as $$
+  begin
+    if new.parent_id is distinct from old.parent_id then
+      if new.type != 'project' then
+        raise exception 'only projects can be moved to another scope';
+      end if;
+      perform from iam_role
+        where scope_id = old.parent_id
+          and grant_scope_id = new.public_id;
+      if found then
+        raise exception 'project has grants from roles in org %', old.parent_id;
+      end if;
+      update iam_scope_project
+         set parent_id = new.parent_id
+       where scope_id = new.public_id;
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
 
-  -- The wh_session_accumulating_fact table is an accumulating snapshot.
-  -- The table wh_session_accumulating_fact is an accumulating fact table.
-  -- The grain of the fact table is one row per session.
-  create table wh_session_accumulating_fact (
-    session_id wt_public_id primary key,
-    -- auth token id is a degenerate dimension
-    auth_token_id wt_public_id not null,
+  create trigger
+    iam_scope_move_project
+  before
+  update of parent_id on iam_scope
+    for each row execute procedure iam_scope_move_project();
 
-    -- foreign keys to the dimension tables
-    host_id wh_dim_id not null
-      references wh_host_dimension (id)
-      on delete restrict
-      on update cascade,
-    user_id wh_dim_id not null
-      references wh_user_dimension (id)
-      on delete restrict
-      on update cascade,
+commit;
 
-    -- TODO(mgaffney) 09/2020: add dimension and foreign key for the session
-    -- termination reason
+`),
+	},
+	"migrations/90_server_tag_source.down.sql": {
+		name: "90_server_tag_source.down.sql",
+		bytes: []byte(`
+begin;
 
-    -- date and time foreign keys
-    session_pending_date_id integer not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_pending_time_id integer not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_pending_time wh_timestamp,
+  delete from server_tag
+    where source = 'api';
 
-    session_active_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_active_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_active_time wh_timestamp default 'infinity'::timestamptz,
+  alter table server_tag
+    drop constraint server_tag_pkey;
+  alter table server_tag
+    add primary key (server_id, server_type, key, value);
 
-    session_canceling_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_canceling_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_canceling_time wh_timestamp default 'infinity'::timestamptz,
+  alter table server_tag
+    drop column source;
 
-    session_terminated_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_terminated_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_terminated_time wh_timestamp default 'infinity'::timestamptz,
+commit;
 
-    -- TODO(mgaffney) 09/2020: add columns for session expiration
+`),
+	},
+	"migrations/90_server_tag_source.up.sql": {
+		name: "90_server_tag_source.up.sql",
+		bytes: []byte(`
+begin;
 
-    -- TODO(mgaffney) 09/2020: add connection limit. This may need a dimension
-    -- table and foreign key column to represent unlimited connections.
+  -- source records where a server tag came from. Tags from a worker's
+  -- configuration are replaced each time the worker reports its status; tags
+  -- set through the api are kept until they are set again or the server is
+  -- deleted. The same tag can come from both sources.
+  alter table server_tag
+    add column source text not null default 'configuration'
+      constraint server_tag_source_valid
+      check(source in ('configuration', 'api'));
 
-    -- The total number of connections made during the session.
-    total_connection_count bigint, -- will be null until the first connection is created
+  alter table server_tag
+    drop constraint server_tag_pkey;
+  alter table server_tag
+    add primary key (server_id, server_type, key, value, source);
 
-    -- The total number of bytes received by workers from the client and sent
-    -- to the endpoint for this session.
-    -- total_bytes_up is a fully additive measurement.
-    total_bytes_up wh_bytes_transmitted, -- will be null until the first connection is closed
-    -- The total number of bytes received by workers from the endpoint and sent
-    -- to the client for this session.
-    -- total_bytes_down is a fully additive measurement.
-    total_bytes_down wh_bytes_transmitted -- will be null until the first connection is closed
-  );
+commit;
 
-  -- The wh_session_connection_accumulating_fact table is an accumulating fact table.
-  -- The grain of the fact table is one row per session connection.
-  create table wh_session_connection_accumulating_fact  (
-    connection_id wt_public_id primary key,
-    -- session_id is a degenerate dimension
-    session_id wt_public_id not null
-      references wh_session_accumulating_fact (session_id)
-      on delete cascade
-      on update cascade,
+`),
+	},
+	"migrations/91_password_must_change.down.sql": {
+		name: "91_password_must_change.down.sql",
+		bytes: []byte(`
+begin;
 
-    -- foreign keys to the dimension tables
-    host_id wh_dim_id not null
-      references wh_host_dimension (id)
-      on delete restrict
-      on update cascade,
-    user_id wh_dim_id not null
-      references wh_user_dimension (id)
-      on delete restrict
-      on update cascade,
+  alter table auth_password_account
+    drop column must_change_password;
 
-    -- TODO(mgaffney) 09/2020: add dimension and foreign key for the connection
-    -- closed reason
+commit;
 
-    -- date and time foreign keys and timestamps
-    connection_authorized_date_id integer not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_authorized_time_id integer not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_authorized_time wh_timestamp,
+`),
+	},
+	"migrations/91_password_must_change.up.sql": {
+		name: "91_password_must_change.up.sql",
+		bytes: []byte(`
+begin;
 
-    connection_connected_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_connected_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_connected_time wh_timestamp default 'infinity'::timestamptz,
+  -- must_change_password is set when an administrator sets an account's
+  -- password and requires the user to change it before they can
+  -- authenticate. It is cleared when the user changes their password.
+  alter table auth_password_account
+    add column must_change_password boolean not null default false;
 
-    connection_closed_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_closed_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_closed_time wh_timestamp default 'infinity'::timestamptz,
+commit;
 
-    -- TODO(mgaffney) 09/2020: add a connection_duration_in_seconds column
+`),
+	},
+	"migrations/92_target_access_schedule.down.sql": {
+		name: "92_target_access_schedule.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop view search_resource;
+  drop view target_all_subtypes;
+
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+  alter table target_tcp
+    drop column terminate_sessions_at_schedule_end,
+    drop column access_schedule;
+
+commit;
+
+`),
+	},
+	"migrations/92_target_access_schedule.up.sql": {
+		name: "92_target_access_schedule.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- access_schedule is the weekly windows during which sessions can be
+  -- authorized for the target, such as 'TZ=Europe/London mon-fri
+  -- 09:00-17:00'. It is parsed and validated by the controller. Null means
+  -- sessions can be authorized at any time.
+  alter table target_tcp
+    add column access_schedule text
+      constraint access_schedule_must_not_be_empty
+      check(length(trim(access_schedule)) > 0);
+
+  -- terminate_sessions_at_schedule_end limits the expiration time of a
+  -- session to the end of the access schedule window it was authorized in,
+  -- so the session is terminated when the window ends.
+  alter table target_tcp
+    add column terminate_sessions_at_schedule_end boolean not null default false;
+
+  -- search_resource depends on target_all_subtypes, so the columns are added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end
+    from target_tcp;
+
+commit;
+
+`),
+	},
+	"migrations/93_session_summary.down.sql": {
+		name: "93_session_summary.down.sql",
+		bytes: []byte(`
+begin;
 
-    -- The client address and port are degenerate dimensions
-    client_tcp_address inet, -- can be null
-    client_tcp_port_number wh_inet_port, -- can be null
+  drop table session_summary;
 
-    -- The endpoint address and port are degenerate dimensions
-    endpoint_tcp_address inet, -- can be null
-    endpoint_tcp_port_number wh_inet_port, -- can be null
+commit;
 
-    -- the connection_count must always be 1
-    -- this is a common pattern in data warehouse models
-    -- See The Data Warehouse Toolkit, Third Edition
-    -- by Ralph Kimball and Margy Ross for more information
-    connection_count smallint default 1 not null
-      constraint connection_count_must_be_1
-      check(connection_count = 1),
+`),
+	},
+	"migrations/93_session_summary.up.sql": {
+		name: "93_session_summary.up.sql",
+		bytes: []byte(`
+begin;
 
-    -- The total number of bytes received by the worker from the client and sent
-    -- to the endpoint for this connection.
-    -- bytes_up is a fully additive measurement.
-    bytes_up wh_bytes_transmitted, -- can be null
-    -- The total number of bytes received by the worker from the endpoint and sent
-    -- to the client for this connection.
-    -- bytes_down is a fully additive measurement.
-    bytes_down wh_bytes_transmitted -- can be null
+  -- session_summary holds the summaries of terminated sessions replicated
+  -- from the primary database to a database in another region for
+  -- reporting. It is only written in the replica database. It has no
+  -- foreign keys since the sessions and the resources they reference are not
+  -- replicated, and are usually purged from the primary database long before
+  -- their summaries.
+  create table session_summary (
+    public_id text primary key,
+    scope_id text not null,
+    user_id text not null,
+    target_id text not null,
+    host_id text not null,
+    host_set_id text not null,
+    auth_token_id text not null,
+    server_id text not null,
+    endpoint text not null,
+    termination_reason text not null,
+    create_time timestamp with time zone not null,
+    terminate_time timestamp with time zone not null,
+    connection_count bigint not null default 0
+      constraint connection_count_must_not_be_negative
+      check(connection_count >= 0),
+    bytes_up bigint not null default 0
+      constraint bytes_up_must_not_be_negative
+      check(bytes_up >= 0),
+    bytes_down bigint not null default 0
+      constraint bytes_down_must_not_be_negative
+      check(bytes_down >= 0)
   );
 
-  -- TODO(mgaffney) 09/2020: Research and test if the comment fields are used by
-  -- data analysis tools.
-  comment on table wh_session_connection_accumulating_fact is
-    'The Wh Session Connection Accumulating Fact table is an accumulating fact table. '
-    'The grain of the fact table is one row per session connection.';
-  comment on column wh_session_connection_accumulating_fact.bytes_up is
-    'Bytes Up is the total number of bytes received by the worker from the '
-    'client and sent to the endpoint for this connection. Bytes Up is a fully '
-    'additive measurement.';
-  comment on column wh_session_connection_accumulating_fact.bytes_down is
-    'Bytes Down is the total number of bytes received by the worker from the '
-    'endpoint and sent to the client for this connection. Bytes Down is a fully '
-    'additive measurement.';
+  create index session_summary_terminate_time_public_id_idx
+    on session_summary (terminate_time, public_id);
 
-  create index on wh_session_connection_accumulating_fact(session_id);
+  create index session_summary_scope_id_terminate_time_idx
+    on session_summary (scope_id, terminate_time);
 
 commit;
 
 `),
 	},
-	"migrations/69_wh_session_facts.down.sql": {
-		name: "69_wh_session_facts.down.sql",
+	"migrations/94_quota_request_count.down.sql": {
+		name: "94_quota_request_count.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop trigger wh_insert_session_connection_state on session_connection_state;
-  drop function wh_insert_session_connection_state;
+  drop table quota_request_count;
 
-  drop trigger wh_insert_session_state on session_state;
-  drop function wh_insert_session_state;
+commit;
 
-  drop trigger wh_update_session_connection on session_connection;
-  drop function wh_update_session_connection;
+`),
+	},
+	"migrations/94_quota_request_count.up.sql": {
+		name: "94_quota_request_count.up.sql",
+		bytes: []byte(`
+begin;
 
-  drop trigger wh_insert_session_connection on session_connection;
-  drop function wh_insert_session_connection;
+  -- quota_request_count counts the API requests made in each organization in
+  -- each quota period, for enforcing request quotas and for billing. Every
+  -- controller adds the requests it has counted to the row for the period.
+  create table quota_request_count (
+    scope_id wt_scope_id not null
+      references iam_scope_org (scope_id)
+      on delete cascade
+      on update cascade,
+    period_start timestamp with time zone not null,
+    request_count bigint not null default 0
+      constraint request_count_must_not_be_negative
+      check(request_count >= 0),
+    update_time wt_timestamp not null,
+    primary key (scope_id, period_start)
+  );
 
-  drop trigger wh_insert_session on session;
-  drop function wh_insert_session;
+commit;
 
-  drop function wh_rollup_connections;
+`),
+	},
+	"migrations/95_account_attribute_rules.down.sql": {
+		name: "95_account_attribute_rules.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table iam_user_alias;
+  drop table iam_attribute_rule_member;
+  drop table iam_attribute_rule;
+  drop table auth_account_attributes;
 
 commit;
 
 `),
 	},
-	"migrations/69_wh_session_facts.up.sql": {
-		name: "69_wh_session_facts.up.sql",
+	"migrations/95_account_attribute_rules.up.sql": {
+		name: "95_account_attribute_rules.up.sql",
 		bytes: []byte(`
 begin;
 
-  -- wh_rollup_connections calculates the aggregate values from
-  -- wh_session_connection_accumulating_fact for p_session_id and updates
-  -- wh_session_accumulating_fact for p_session_id with those values.
-  create or replace function wh_rollup_connections(p_session_id wt_public_id)
-    returns void
-  as $$
-  declare
-    session_row wh_session_accumulating_fact%rowtype;
-  begin
-    with
-    session_totals (session_id, total_connection_count, total_bytes_up, total_bytes_down) as (
-      select session_id,
-             sum(connection_count),
-             sum(bytes_up),
-             sum(bytes_down)
-        from wh_session_connection_accumulating_fact
-       where session_id = p_session_id
-       group by session_id
-    )
-    update wh_session_accumulating_fact
-       set total_connection_count = session_totals.total_connection_count,
-           total_bytes_up         = session_totals.total_bytes_up,
-           total_bytes_down       = session_totals.total_bytes_down
-      from session_totals
-     where wh_session_accumulating_fact.session_id = session_totals.session_id
-    returning wh_session_accumulating_fact.* into strict session_row;
-  end;
-  $$ language plpgsql;
+  -- auth_account_attributes holds the attributes of each account, as given by
+  -- its auth method the last time the account authenticated. They are
+  -- replaced at every login and matched by the predicates of
+  -- iam_attribute_rule.
+  create table auth_account_attributes (
+    account_id wt_public_id primary key
+      references auth_account (public_id)
+      on delete cascade
+      on update cascade,
+    attributes jsonb not null default '{}'
+      constraint attributes_must_be_an_object
+      check(jsonb_typeof(attributes) = 'object'),
+    update_time wt_timestamp
+  );
 
-  --
-  -- Session triggers
-  --
+  create trigger
+    update_time_column
+  before
+  update on auth_account_attributes
+    for each row execute procedure update_time_column();
 
-  -- wh_insert_session returns an after insert trigger for the session table
-  -- which inserts a row in wh_session_accumulating_fact for the new session.
-  -- wh_insert_session also calls the wh_upsert_host and wh_upsert_user
-  -- functions which can result in new rows in wh_host_dimension and
-  -- wh_user_dimension respectively.
-  create or replace function wh_insert_session()
-    returns trigger
-  as $$
-  declare
-    new_row wh_session_accumulating_fact%rowtype;
-  begin
-    with
-    pending_timestamp (date_dim_id, time_dim_id, ts) as (
-      select wh_date_id(start_time), wh_time_id(start_time), start_time
-        from session_state
-       where session_id = new.public_id
-         and state = 'pending'
-    )
-    insert into wh_session_accumulating_fact (
-           session_id,
-           auth_token_id,
-           host_id,
-           user_id,
-           session_pending_date_id,
-           session_pending_time_id,
-           session_pending_time
-    )
-    select new.public_id,
-           new.auth_token_id,
-           wh_upsert_host(new.host_id, new.host_set_id, new.target_id),
-           wh_upsert_user(new.user_id, new.auth_token_id),
-           pending_timestamp.date_dim_id,
-           pending_timestamp.time_dim_id,
-           pending_timestamp.ts
-      from pending_timestamp
-      returning * into strict new_row;
-    return null;
-  end;
-  $$ language plpgsql;
+  -- iam_attribute_rule maps a predicate on the attributes of the accounts in
+  -- a scope to either membership of a group or a user alias, taken from the
+  -- value of the account's alias_attribute. Rules are evaluated every time an
+  -- account in the scope authenticates.
+  create table iam_attribute_rule (
+    public_id wt_public_id primary key,
+    scope_id wt_scope_id not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    attribute text not null
+      constraint attribute_must_not_be_empty
+      check(length(trim(attribute)) > 0),
+    operator text not null
+      constraint operator_must_be_known
+      check(operator in ('exists', 'equals', 'contains')),
+    value text not null default '',
+    group_id wt_public_id
+      references iam_group (public_id)
+      on delete cascade
+      on update cascade,
+    alias_attribute text
+      constraint alias_attribute_must_not_be_empty
+      check(length(trim(alias_attribute)) > 0),
+    create_time wt_timestamp,
+    constraint rule_must_have_one_result
+      check((group_id is null) <> (alias_attribute is null))
+  );
 
-  create trigger wh_insert_session
-    after insert on session
-    for each row
-    execute function wh_insert_session();
+  create trigger
+    default_create_time_column
+  before
+  insert on iam_attribute_rule
+    for each row execute procedure default_create_time();
 
-  --
-  -- Session Connection triggers
-  --
+  create trigger
+    immutable_columns
+  before
+  update on iam_attribute_rule
+    for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+
+  -- iam_attribute_rule_member records the group memberships added by
+  -- attribute rules, so they can be removed when the rule stops matching
+  -- without removing memberships added directly. Deleting the membership
+  -- deletes the record.
+  create table iam_attribute_rule_member (
+    rule_id wt_public_id not null
+      references iam_attribute_rule (public_id)
+      on delete cascade
+      on update cascade,
+    group_id wt_public_id not null,
+    member_id wt_user_id not null,
+    create_time wt_timestamp,
+    primary key (rule_id, member_id),
+    foreign key (group_id, member_id)
+      references iam_group_member_user (group_id, member_id)
+      on delete cascade
+      on update cascade
+  );
 
-  -- wh_insert_session_connection returns an after insert trigger for the
-  -- session_connection table which inserts a row in
-  -- wh_session_connection_accumulating_fact for the new session connection.
-  -- wh_insert_session_connection also calls wh_rollup_connections which can
-  -- result in updates to wh_session_accumulating_fact.
-  create or replace function wh_insert_session_connection()
-    returns trigger
-  as $$
-  declare
-    new_row wh_session_connection_accumulating_fact%rowtype;
-  begin
-    with
-    authorized_timestamp (date_dim_id, time_dim_id, ts) as (
-      select wh_date_id(start_time), wh_time_id(start_time), start_time
-        from session_connection_state
-       where connection_id = new.public_id
-         and state = 'authorized'
-    ),
-    session_dimension (host_dim_id, user_dim_id) as (
-      select host_id, user_id
-        from wh_session_accumulating_fact
-       where session_id = new.session_id
-    )
-    insert into wh_session_connection_accumulating_fact (
-           connection_id,
-           session_id,
-           host_id,
-           user_id,
-           connection_authorized_date_id,
-           connection_authorized_time_id,
-           connection_authorized_time,
-           client_tcp_address,
-           client_tcp_port_number,
-           endpoint_tcp_address,
-           endpoint_tcp_port_number,
-           bytes_up,
-           bytes_down
-    )
-    select new.public_id,
-           new.session_id,
-           session_dimension.host_dim_id,
-           session_dimension.user_dim_id,
-           authorized_timestamp.date_dim_id,
-           authorized_timestamp.time_dim_id,
-           authorized_timestamp.ts,
-           new.client_tcp_address,
-           new.client_tcp_port,
-           new.endpoint_tcp_address,
-           new.endpoint_tcp_port,
-           new.bytes_up,
-           new.bytes_down
-      from authorized_timestamp,
-           session_dimension
-      returning * into strict new_row;
-    perform wh_rollup_connections(new.session_id);
-    return null;
-  end;
-  $$ language plpgsql;
+  create trigger
+    default_create_time_column
+  before
+  insert on iam_attribute_rule_member
+    for each row execute procedure default_create_time();
+
+  -- iam_user_alias holds the aliases attribute rules have given users. An
+  -- alias is unique within the scope of the rule which gave it, and is kept
+  -- by the first user given it.
+  create table iam_user_alias (
+    scope_id wt_scope_id not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    alias text not null,
+    user_id wt_user_id not null
+      references iam_user (public_id)
+      on delete cascade
+      on update cascade,
+    rule_id wt_public_id not null
+      references iam_attribute_rule (public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp,
+    primary key (scope_id, alias)
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on iam_user_alias
+    for each row execute procedure default_create_time();
 
-  create trigger wh_insert_session_connection
-    after insert on session_connection
-    for each row
-    execute function wh_insert_session_connection();
+commit;
 
-  -- wh_update_session_connection returns an after update trigger for the
-  -- session_connection table which updates a row in
-  -- wh_session_connection_accumulating_fact for the session connection.
-  -- wh_update_session_connection also calls wh_rollup_connections which can
-  -- result in updates to wh_session_accumulating_fact.
-  create or replace function wh_update_session_connection()
+`),
+	},
+	"migrations/96_delete_user_auth_tokens.down.sql": {
+		name: "96_delete_user_auth_tokens.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger delete_user_auth_tokens on iam_user;
+  drop function delete_user_auth_tokens;
+
+commit;
+
+`),
+	},
+	"migrations/96_delete_user_auth_tokens.up.sql": {
+		name: "96_delete_user_auth_tokens.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- delete_user_auth_tokens is a before delete trigger on iam_user which
+  -- deletes the auth tokens of the user's accounts. Deleting a user only
+  -- unlinks its accounts, so without it the tokens would keep authenticating
+  -- requests. The user's sessions are canceled by cancel_session_with_null_fk
+  -- when their user_id is set to null.
+  create function
+    delete_user_auth_tokens()
     returns trigger
   as $$
-  declare
-    updated_row wh_session_connection_accumulating_fact%rowtype;
   begin
-        update wh_session_connection_accumulating_fact
-           set client_tcp_address       = new.client_tcp_address,
-               client_tcp_port_number   = new.client_tcp_port,
-               endpoint_tcp_address     = new.endpoint_tcp_address,
-               endpoint_tcp_port_number = new.endpoint_tcp_port,
-               bytes_up                 = new.bytes_up,
-               bytes_down               = new.bytes_down
-         where connection_id = new.public_id
-     returning * into strict updated_row;
-    perform wh_rollup_connections(new.session_id);
-    return null;
+    delete from auth_token
+     where auth_account_id in (
+       select public_id
+         from auth_account
+        where iam_user_id = old.public_id
+     );
+    return old;
   end;
   $$ language plpgsql;
 
-  create trigger wh_update_session_connection
-    after update on session_connection
-    for each row
-    execute function wh_update_session_connection();
+  create trigger
+    delete_user_auth_tokens
+  before delete on iam_user
+    for each row execute procedure delete_user_auth_tokens();
 
-  --
-  -- Session State trigger
-  --
+commit;
 
-  -- wh_insert_session_state returns an after insert trigger for the
-  -- session_state table which updates wh_session_accumulating_fact.
-  create or replace function wh_insert_session_state()
-    returns trigger
-  as $$
-  declare
-    date_col text;
-    time_col text;
-    ts_col text;
-    q text;
-    session_row wh_session_accumulating_fact%rowtype;
-  begin
-    if new.state = 'pending' then
-      -- The pending state is the first state which is handled by the
-      -- wh_insert_session trigger. The update statement in this trigger will
-      -- fail for the pending state because the row for the session has not yet
-      -- been inserted into the wh_session_accumulating_fact table.
-      return null;
-    end if;
+`),
+	},
+	"migrations/97_target_allowed_ports.down.sql": {
+		name: "97_target_allowed_ports.down.sql",
+		bytes: []byte(`
+begin;
 
-    date_col = 'session_' || new.state || '_date_id';
-    time_col = 'session_' || new.state || '_time_id';
-    ts_col   = 'session_' || new.state || '_time';
+  drop view session_with_state;
 
-    q = format('update wh_session_accumulating_fact
-                   set (%I, %I, %I) = (select wh_date_id(%L), wh_time_id(%L), %L::timestamptz)
-                 where session_id = %L
-                returning *',
-                date_col,       time_col,       ts_col,
-                new.start_time, new.start_time, new.start_time,
-                new.session_id);
-    execute q into strict session_row;
+  create view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
 
-    return null;
-  end;
-  $$ language plpgsql;
+  drop trigger immutable_columns on session;
 
-  create trigger wh_insert_session_state
-    after insert on session_state
-    for each row
-    execute function wh_insert_session_state();
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit');
 
-  --
-  -- Session Connection State trigger
-  --
+  alter table session
+    drop column allowed_ports;
 
-  -- wh_insert_session_connection_state returns an after insert trigger for the
-  -- session_connection_state table which updates
-  -- wh_session_connection_accumulating_fact.
-  create or replace function wh_insert_session_connection_state()
+  drop view search_resource;
+  drop view target_all_subtypes;
+
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+  alter table target_tcp
+    drop column allowed_ports;
+
+commit;
+
+`),
+	},
+	"migrations/97_target_allowed_ports.up.sql": {
+		name: "97_target_allowed_ports.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- allowed_ports is the ports and ranges of ports, besides default_port,
+  -- which clients can ask to connect to on the target's hosts, such as
+  -- '5432,5433,6000-6010'. It is parsed and validated by the controller. Null
+  -- means only the default port can be connected to.
+  alter table target_tcp
+    add column allowed_ports text
+      constraint allowed_ports_must_not_be_empty
+      check(length(trim(allowed_ports)) > 0);
+
+  -- search_resource depends on target_all_subtypes, so the column is added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports
+    from target_tcp;
+
+  -- the session's allowed_ports are copied from its target when the session
+  -- is authorized, and are checked by the worker against the port each
+  -- connection asks for.
+  alter table session
+    add column allowed_ports text;
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit', 'allowed_ports');
+
+  create or replace view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit,
+    s.allowed_ports
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
+
+commit;
+
+`),
+	},
+	"migrations/98_ssh_targets.down.sql": {
+		name: "98_ssh_targets.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop view search_resource;
+  drop view target_all_subtypes;
+
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+  alter table target_tcp
+    drop constraint ssh_private_key_must_have_key_id,
+    drop column key_id,
+    drop column ssh_host_key,
+    drop column ssh_private_key,
+    drop column ssh_username;
+
+  update target_tcp
+     set protocol = 'tcp'
+   where protocol = 'ssh';
+
+  alter table target_tcp
+    drop constraint protocol_must_be_tcp_udp_or_ssh;
+
+  alter table target_tcp
+    add constraint protocol_must_be_tcp_or_udp
+      check(protocol in ('tcp', 'udp'));
+
+commit;
+
+`),
+	},
+	"migrations/98_ssh_targets.up.sql": {
+		name: "98_ssh_targets.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- ssh targets are proxied by workers which terminate the client's ssh
+  -- connection and authenticate to the target's hosts themselves, with
+  -- credentials brokered by the target, so users never see them.
+  alter table target_tcp
+    drop constraint protocol_must_be_tcp_or_udp;
+
+  alter table target_tcp
+    add constraint protocol_must_be_tcp_udp_or_ssh
+      check(protocol in ('tcp', 'udp', 'ssh'));
+
+  -- ssh_private_key is encrypted with the database key of the target's
+  -- scope, whose version is key_id. ssh_host_key is the public key the
+  -- target's hosts must present, in authorized_keys format.
+  alter table target_tcp
+    add column ssh_username text
+      constraint ssh_username_must_not_be_empty
+      check(length(trim(ssh_username)) > 0),
+    add column ssh_private_key bytea,
+    add column ssh_host_key text
+      constraint ssh_host_key_must_not_be_empty
+      check(length(trim(ssh_host_key)) > 0),
+    -- TODO: Make key_id a foreign key once we have DEKs
+    add column key_id text
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    add constraint ssh_private_key_must_have_key_id
+      check((ssh_private_key is null) = (key_id is null));
+
+  -- search_resource depends on target_all_subtypes, so the columns are added
+  -- to the end of the view rather than dropping and recreating it. The
+  -- private key is left out so it's only read when brokering a session.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key
+    from target_tcp;
+
+commit;
+
+`),
+	},
+	"migrations/99_group_member_history.down.sql": {
+		name: "99_group_member_history.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger iam_group_member_user_hst_delete on iam_group_member_user;
+  drop trigger iam_group_member_user_hst_insert on iam_group_member_user;
+  drop function iam_group_member_user_hst_delete;
+  drop function iam_group_member_user_hst_insert;
+  drop table iam_group_member_user_hst;
+
+commit;
+
+`),
+	},
+	"migrations/99_group_member_history.up.sql": {
+		name: "99_group_member_history.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- iam_group_member_user_hst records the periods users were members of
+  -- groups, so past memberships can be found after they are removed. A
+  -- period whose valid_to is null is current. Rows don't reference the
+  -- group or user, so they are kept when either is deleted.
+  create table iam_group_member_user_hst (
+    group_id wt_public_id not null,
+    member_id wt_user_id not null,
+    valid_from wt_timestamp not null,
+    valid_to timestamp with time zone,
+    primary key (group_id, member_id, valid_from),
+    constraint valid_to_must_not_be_before_valid_from
+      check(valid_to >= valid_from)
+  );
+
+  create index iam_group_member_user_hst_group_id_valid_from_ix
+    on iam_group_member_user_hst (group_id, valid_from);
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_group_member_user_hst
+    for each row execute procedure immutable_columns('group_id', 'member_id', 'valid_from');
+
+  -- iam_group_member_user_hst_insert starts a period when a user is added
+  -- to a group. A user removed and added again in the same transaction
+  -- reopens the period the removal closed.
+  create function
+    iam_group_member_user_hst_insert()
     returns trigger
   as $$
-  declare
-    date_col text;
-    time_col text;
-    ts_col text;
-    q text;
-    connection_row wh_session_connection_accumulating_fact%rowtype;
   begin
-    if new.state = 'authorized' then
-      -- The authorized state is the first state which is handled by the
-      -- wh_insert_session_connection trigger. The update statement in this
-      -- trigger will fail for the authorized state because the row for the
-      -- session connection has not yet been inserted into the
-      -- wh_session_connection_accumulating_fact table.
-      return null;
-    end if;
-
-    date_col = 'connection_' || new.state || '_date_id';
-    time_col = 'connection_' || new.state || '_time_id';
-    ts_col   = 'connection_' || new.state || '_time';
-
-    q = format('update wh_session_connection_accumulating_fact
-                   set (%I, %I, %I) = (select wh_date_id(%L), wh_time_id(%L), %L::timestamptz)
-                 where connection_id = %L
-                returning *',
-                date_col,       time_col,       ts_col,
-                new.start_time, new.start_time, new.start_time,
-                new.connection_id);
-    execute q into strict connection_row;
+    insert into iam_group_member_user_hst
+      (group_id, member_id, valid_from)
+    values
+      (new.group_id, new.member_id, new.create_time)
+    on conflict (group_id, member_id, valid_from) do update
+      set valid_to = null;
+    return new;
+  end;
+  $$ language plpgsql;
 
-    return null;
+  create trigger
+    iam_group_member_user_hst_insert
+  after
+  insert on iam_group_member_user
+    for each row execute procedure iam_group_member_user_hst_insert();
+
+  -- iam_group_member_user_hst_delete ends the current period when a user
+  -- is removed from a group, including when the group or user is deleted.
+  create function
+    iam_group_member_user_hst_delete()
+    returns trigger
+  as $$
+  begin
+    update iam_group_member_user_hst
+       set valid_to = current_timestamp
+     where group_id = old.group_id
+       and member_id = old.member_id
+       and valid_to is null;
+    return old;
   end;
   $$ language plpgsql;
 
-  create trigger wh_insert_session_connection_state
-    after insert on session_connection_state
-    for each row
-    execute function wh_insert_session_connection_state();
+  create trigger
+    iam_group_member_user_hst_delete
+  after
+  delete on iam_group_member_user
+    for each row execute procedure iam_group_member_user_hst_delete();
+
+  -- Existing memberships are recorded from when they were created; the
+  -- memberships removed before now can't be recovered.
+  insert into iam_group_member_user_hst
+    (group_id, member_id, valid_from)
+  select group_id, member_id, create_time
+    from iam_group_member_user;
 
 commit;
 
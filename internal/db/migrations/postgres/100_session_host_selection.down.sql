@@ -0,0 +1,52 @@
+begin;
+
+  drop view session_with_state;
+
+  create view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit,
+    s.allowed_ports
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit', 'allowed_ports');
+
+  alter table session
+    drop column host_selection_reason,
+    drop column host_address;
+
+commit;
@@ -0,0 +1,62 @@
+begin;
+
+  -- host_address is the address of the host chosen for the session when it
+  -- was authorized, and host_selection_reason is why that host was chosen,
+  -- such as 'requested' when the client asked for it or 'random'. They let
+  -- operators find out why a session went to a host after the host or its
+  -- address has changed. They are null for sessions authorized before they
+  -- were recorded.
+  alter table session
+    add column host_address text
+      constraint host_address_must_not_be_empty
+      check(length(trim(host_address)) > 0),
+    add column host_selection_reason text
+      constraint host_selection_reason_must_not_be_empty
+      check(length(trim(host_selection_reason)) > 0);
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit', 'allowed_ports', 'host_address', 'host_selection_reason');
+
+  create or replace view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit,
+    s.allowed_ports,
+    s.host_address,
+    s.host_selection_reason
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
+
+commit;
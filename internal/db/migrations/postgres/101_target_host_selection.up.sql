@@ -0,0 +1,85 @@
+begin;
+
+  -- host_selection_strategy is how the host of a session is chosen from the
+  -- target's host sets when the user doesn't ask for one: 'random',
+  -- 'round_robin' through the hosts in order, or 'sticky', which chooses the
+  -- host the user was last given while it's still in the target's host sets.
+  alter table target_tcp
+    add column host_selection_strategy text not null default 'random'
+      constraint host_selection_strategy_must_be_random_round_robin_or_sticky
+      check(host_selection_strategy in ('random', 'round_robin', 'sticky'));
+
+  -- search_resource depends on target_all_subtypes, so the column is added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy
+    from target_tcp;
+
+  -- target_round_robin counts the hosts chosen for each target by the
+  -- round_robin strategy. Every controller takes the next host from the
+  -- shared count, so sessions are spread evenly however many controllers
+  -- there are.
+  create table target_round_robin (
+    target_id wt_public_id primary key
+      references target (public_id)
+      on delete cascade
+      on update cascade,
+    host_count bigint not null default 0
+      constraint host_count_must_not_be_negative
+      check(host_count >= 0),
+    update_time wt_timestamp not null
+  );
+
+  -- target_host_affinity records the host each user was last given by the
+  -- sticky strategy of each target. The record is removed with the target,
+  -- user, host set or host.
+  create table target_host_affinity (
+    target_id wt_public_id not null
+      references target (public_id)
+      on delete cascade
+      on update cascade,
+    user_id wt_user_id not null
+      references iam_user (public_id)
+      on delete cascade
+      on update cascade,
+    host_set_id wt_public_id not null
+      references host_set (public_id)
+      on delete cascade
+      on update cascade,
+    host_id wt_public_id not null
+      references host (public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp not null,
+    update_time wt_timestamp not null,
+    primary key (target_id, user_id)
+  );
+
+  create trigger
+    immutable_columns
+  before
+  update on target_host_affinity
+    for each row execute procedure immutable_columns('target_id', 'user_id', 'create_time');
+
+commit;
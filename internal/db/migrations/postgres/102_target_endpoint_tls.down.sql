@@ -0,0 +1,94 @@
+begin;
+
+  drop view search_resource;
+  drop view target_all_subtypes;
+
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+  alter table target_tcp
+    drop column tls_key_id,
+    drop column tls_client_private_key,
+    drop column tls_client_certificate,
+    drop column tls_ca_bundle,
+    drop column tls_server_name,
+    drop column endpoint_tls;
+
+commit;
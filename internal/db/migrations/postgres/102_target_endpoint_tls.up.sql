@@ -0,0 +1,60 @@
+begin;
+
+  -- When endpoint_tls is true, workers proxying the connections of a target
+  -- whose protocol is tcp originate TLS to its hosts rather than relaying
+  -- the client's bytes in plaintext. tls_server_name is the name the host's
+  -- certificate is verified against, and the host's address if it's null.
+  -- tls_ca_bundle is the PEM encoded certificates the host's certificate is
+  -- verified with, and the worker's system roots if it's null.
+  -- tls_client_certificate and tls_client_private_key are the PEM encoded
+  -- certificate and key workers authenticate to the host with. The CA bundle
+  -- and the private key are encrypted with the database key of the target's
+  -- scope, whose version is tls_key_id.
+  alter table target_tcp
+    add column endpoint_tls boolean not null default false,
+    add column tls_server_name text
+      constraint tls_server_name_must_not_be_empty
+      check(length(trim(tls_server_name)) > 0),
+    add column tls_ca_bundle bytea,
+    add column tls_client_certificate text
+      constraint tls_client_certificate_must_not_be_empty
+      check(length(trim(tls_client_certificate)) > 0),
+    add column tls_client_private_key bytea,
+    -- TODO: Make tls_key_id a foreign key once we have DEKs
+    add column tls_key_id text
+      constraint tls_key_id_must_not_be_empty
+      check(length(trim(tls_key_id)) > 0);
+
+  -- search_resource depends on target_all_subtypes, so the columns are added
+  -- to the end of the view rather than dropping and recreating it. The CA
+  -- bundle and private key are left out so they're only read when a worker
+  -- looks a session up.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy,
+    endpoint_tls,
+    tls_server_name,
+    tls_client_certificate
+    from target_tcp;
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+  drop view kms_key_usage_view;
+  drop table kms_key_usage;
+
+commit;
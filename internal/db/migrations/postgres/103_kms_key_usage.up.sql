@@ -0,0 +1,53 @@
+begin;
+
+  -- kms_key_usage counts the encrypt and decrypt operations done with each
+  -- version of each scope's data encryption keys. Every controller adds the
+  -- operations it has counted, so security teams can detect unusual use of a
+  -- scope's keys, and check nothing still decrypts with a key version that
+  -- was rotated out. Key versions aren't referenced, so the counts are kept
+  -- after a key version is destroyed.
+  create table kms_key_usage (
+    key_version_id wt_private_id not null,
+    operation text not null
+      constraint operation_must_be_encrypt_or_decrypt
+      check(operation in ('encrypt', 'decrypt')),
+    scope_id wt_scope_id not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    purpose text not null
+      constraint purpose_must_be_database_oplog_tokens_or_sessions
+      check(purpose in ('database', 'oplog', 'tokens', 'sessions')),
+    use_count bigint not null default 0
+      constraint use_count_must_not_be_negative
+      check(use_count >= 0),
+    first_use_time wt_timestamp not null,
+    last_use_time wt_timestamp not null,
+    primary key (key_version_id, operation)
+  );
+
+  -- kms_key_usage_view adds the version of each key version to its usage.
+  -- The version is 0 if the key version has been destroyed.
+  create view kms_key_usage_view
+  as
+  select
+    u.scope_id,
+    u.purpose,
+    u.key_version_id,
+    coalesce(v.version, 0) as key_version,
+    u.operation,
+    u.use_count,
+    u.first_use_time,
+    u.last_use_time
+  from kms_key_usage u
+    left join (
+      select private_id, version from kms_database_key_version
+      union all
+      select private_id, version from kms_oplog_key_version
+      union all
+      select private_id, version from kms_token_key_version
+      union all
+      select private_id, version from kms_session_key_version
+    ) v on v.private_id = u.key_version_id;
+
+commit;
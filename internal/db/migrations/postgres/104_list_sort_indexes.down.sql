@@ -0,0 +1,16 @@
+begin;
+
+  drop index target_tcp_scope_id_create_time_ix;
+  drop index static_host_set_catalog_id_create_time_ix;
+  drop index static_host_catalog_id_create_time_ix;
+  drop index static_host_catalog_scope_id_create_time_ix;
+  drop index auth_token_auth_account_id_create_time_ix;
+  drop index auth_password_account_auth_method_id_create_time_ix;
+  drop index auth_password_method_scope_id_create_time_ix;
+  drop index iam_role_scope_id_create_time_ix;
+  drop index iam_group_scope_id_create_time_ix;
+  drop index iam_user_scope_id_name_ix;
+  drop index iam_user_scope_id_create_time_ix;
+  drop index iam_scope_parent_id_create_time_ix;
+
+commit;
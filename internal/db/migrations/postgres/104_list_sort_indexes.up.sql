@@ -0,0 +1,44 @@
+begin;
+
+  -- Listings can be sorted by creation time, so each resource which is
+  -- listed within a parent is indexed by its parent and create_time. Sorting
+  -- by name uses the unique(parent, name) constraints, except for users whose
+  -- constraint has its columns the other way around. Sessions are already
+  -- indexed by session_scope_id_create_time_ix.
+  create index iam_scope_parent_id_create_time_ix
+    on iam_scope (parent_id, create_time);
+
+  create index iam_user_scope_id_create_time_ix
+    on iam_user (scope_id, create_time);
+
+  create index iam_user_scope_id_name_ix
+    on iam_user (scope_id, name);
+
+  create index iam_group_scope_id_create_time_ix
+    on iam_group (scope_id, create_time);
+
+  create index iam_role_scope_id_create_time_ix
+    on iam_role (scope_id, create_time);
+
+  create index auth_password_method_scope_id_create_time_ix
+    on auth_password_method (scope_id, create_time);
+
+  create index auth_password_account_auth_method_id_create_time_ix
+    on auth_password_account (auth_method_id, create_time);
+
+  create index auth_token_auth_account_id_create_time_ix
+    on auth_token (auth_account_id, create_time);
+
+  create index static_host_catalog_scope_id_create_time_ix
+    on static_host_catalog (scope_id, create_time);
+
+  create index static_host_catalog_id_create_time_ix
+    on static_host (catalog_id, create_time);
+
+  create index static_host_set_catalog_id_create_time_ix
+    on static_host_set (catalog_id, create_time);
+
+  create index target_tcp_scope_id_create_time_ix
+    on target_tcp (scope_id, create_time);
+
+commit;
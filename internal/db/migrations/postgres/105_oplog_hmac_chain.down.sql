@@ -0,0 +1,19 @@
+begin;
+
+  drop index oplog_entry_aggregate_name_id_ix;
+
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data');
+
+  alter table oplog_entry
+    drop constraint hmac_must_have_hmac_key_id,
+    drop column hmac,
+    drop column prev_hmac,
+    drop column hmac_key_id;
+
+commit;
@@ -0,0 +1,30 @@
+begin;
+
+  -- Each oplog entry stores an hmac of its content and of the hmac of the
+  -- previous entry of its aggregate, made with a key derived from the oplog
+  -- key version hmac_key_id. The entries of an aggregate are written one at a
+  -- time since they redeem the aggregate's ticket, so they form a chain in
+  -- which altering, reordering or removing an entry can be detected. Entries
+  -- written before the chain was added have no hmac.
+  alter table oplog_entry
+    add column hmac_key_id text
+      constraint hmac_key_id_must_not_be_empty
+      check(length(trim(hmac_key_id)) > 0),
+    add column prev_hmac bytea,
+    add column hmac bytea,
+    add constraint hmac_must_have_hmac_key_id
+      check((hmac is null) = (hmac_key_id is null));
+
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data', 'hmac_key_id', 'prev_hmac', 'hmac');
+
+  -- finds the previous entry of an aggregate when an entry is written
+  create index oplog_entry_aggregate_name_id_ix
+    on oplog_entry (aggregate_name, id desc);
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+  alter table iam_scope
+    drop constraint global_scope_is_never_read_only,
+    drop column read_only;
+
+commit;
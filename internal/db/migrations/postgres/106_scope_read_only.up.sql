@@ -0,0 +1,13 @@
+begin;
+
+  -- read_only freezes a scope: resources in it, and in the projects of an
+  -- org, can't be created or changed until the flag is cleared. Updating only
+  -- the flag is still allowed on a read only scope. This is enforced when the
+  -- resources are vetted for write, not by the database. The global scope
+  -- can't be made read only.
+  alter table iam_scope
+    add column read_only boolean not null default false,
+    add constraint global_scope_is_never_read_only
+      check(type != 'global' or not read_only);
+
+commit;
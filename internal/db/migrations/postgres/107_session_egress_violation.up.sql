@@ -0,0 +1,44 @@
+begin;
+
+  -- session_egress_violation records the addresses workers refused to dial
+  -- for a session because they aren't its endpoint, the host and port in its
+  -- authorization data, or one of its allowed ports on that host. Rows are
+  -- only ever inserted.
+  create table session_egress_violation (
+    session_id wt_public_id not null
+      references session (public_id)
+      on delete cascade
+      on update cascade,
+    -- the connection the address was dialed for, if any
+    connection_id wt_public_id
+      references session_connection (public_id)
+      on delete cascade
+      on update cascade,
+    worker_id text not null
+      constraint worker_id_must_not_be_empty
+      check(length(trim(worker_id)) > 0),
+    endpoint text not null
+      constraint endpoint_must_not_be_empty
+      check(length(trim(endpoint)) > 0),
+    address text not null
+      constraint address_must_not_be_empty
+      check(length(trim(address)) > 0),
+    create_time wt_timestamp
+  );
+
+  create index session_egress_violation_session_id_ix
+    on session_egress_violation (session_id);
+
+  create trigger
+    immutable_columns
+  before
+  update on session_egress_violation
+    for each row execute procedure immutable_columns('session_id', 'connection_id', 'worker_id', 'endpoint', 'address', 'create_time');
+
+  create trigger
+    default_create_time_column
+  before
+  insert on session_egress_violation
+    for each row execute procedure default_create_time();
+
+commit;
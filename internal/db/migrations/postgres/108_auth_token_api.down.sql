@@ -0,0 +1,31 @@
+begin;
+
+  drop table auth_token_grant;
+
+  drop view auth_token_account;
+  create view auth_token_account as
+        select at.public_id,
+               at.token,
+               at.auth_account_id,
+               at.create_time,
+               at.update_time,
+               at.approximate_last_access_time,
+               at.expiration_time,
+               aa.scope_id,
+               aa.iam_user_id,
+               aa.auth_method_id
+          from auth_token as at
+    inner join auth_account as aa
+            on at.auth_account_id = aa.public_id;
+
+  drop trigger immutable_columns on auth_token;
+  create trigger
+    immutable_columns
+  before
+  update on auth_token
+    for each row execute procedure immutable_columns('public_id', 'auth_account_id', 'create_time');
+
+  alter table auth_token
+    drop column api;
+
+commit;
@@ -0,0 +1,59 @@
+begin;
+
+  -- api is true for auth tokens created for automation by admins, rather than
+  -- by authenticating. API tokens are restricted to their grants in
+  -- auth_token_grant, expire at the time set when they're created and aren't
+  -- deleted for being idle.
+  alter table auth_token
+    add column api boolean not null default false;
+
+  drop trigger immutable_columns on auth_token;
+  create trigger
+    immutable_columns
+  before
+  update on auth_token
+    for each row execute procedure immutable_columns('public_id', 'auth_account_id', 'create_time', 'api');
+
+  create or replace view auth_token_account as
+        select at.public_id,
+               at.token,
+               at.auth_account_id,
+               at.create_time,
+               at.update_time,
+               at.approximate_last_access_time,
+               at.expiration_time,
+               aa.scope_id,
+               aa.iam_user_id,
+               aa.auth_method_id,
+               at.api
+          from auth_token as at
+    inner join auth_account as aa
+            on at.auth_account_id = aa.public_id;
+
+  -- auth_token_grant holds the grants an api token is restricted to. A
+  -- request made with the token is only allowed if both the grants of the
+  -- token's user and the token's grants allow it.
+  create table auth_token_grant (
+    auth_token_id wt_public_id not null
+      references auth_token(public_id)
+      on delete cascade
+      on update cascade,
+    raw_grant text not null
+      constraint raw_grant_must_not_be_empty
+      check(length(trim(raw_grant)) > 0),
+    create_time wt_timestamp,
+    primary key(auth_token_id, raw_grant)
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on auth_token_grant
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_grant
+    for each row execute procedure immutable_columns('auth_token_id', 'raw_grant', 'create_time');
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop table iam_sign_in_cidr;
+
+commit;
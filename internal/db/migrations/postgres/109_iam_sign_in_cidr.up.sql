@@ -0,0 +1,39 @@
+begin;
+
+  -- iam_sign_in_cidr restricts the addresses the accounts of a scope's auth
+  -- methods can sign in from. Once a scope has any, its accounts can only
+  -- authenticate, and use the auth tokens they were issued, from an address
+  -- within one of them. A cidr with an auth_method_id only applies to the
+  -- accounts of that auth method.
+  create table iam_sign_in_cidr (
+    public_id wt_public_id primary key,
+    scope_id wt_scope_id not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    auth_method_id wt_public_id,
+    cidr cidr not null,
+    description text,
+    create_time wt_timestamp,
+    foreign key (scope_id, auth_method_id)
+      references auth_method (scope_id, public_id)
+      on delete cascade
+      on update cascade
+  );
+
+  create index iam_sign_in_cidr_scope_id_ix
+    on iam_sign_in_cidr (scope_id);
+
+  create trigger
+    default_create_time_column
+  before
+  insert on iam_sign_in_cidr
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_sign_in_cidr
+    for each row execute procedure immutable_columns('public_id', 'scope_id', 'auth_method_id', 'cidr', 'create_time');
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop index target_scope_id_ix;
+
+commit;
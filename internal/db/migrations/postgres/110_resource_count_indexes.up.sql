@@ -0,0 +1,10 @@
+begin;
+
+  -- Targets are counted per project from the base target table, which isn't
+  -- otherwise indexed by scope. Users are counted with
+  -- iam_user_scope_id_create_time_ix and sessions with
+  -- session_scope_id_create_time_ix.
+  create index target_scope_id_ix
+    on target (scope_id);
+
+commit;
@@ -0,0 +1,115 @@
+begin;
+
+  update session
+     set termination_reason = 'canceled'
+   where termination_reason = 'never-connected';
+
+  delete from session_termination_reason_enm
+   where name = 'never-connected';
+
+  alter table session_termination_reason_enm
+    drop constraint only_predefined_session_termination_reasons_allowed,
+    add constraint only_predefined_session_termination_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'terminated',
+          'network error',
+          'system error',
+          'connection limit',
+          'canceled'
+        )
+      );
+
+  drop view search_resource;
+  drop view target_all_subtypes;
+
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy,
+    endpoint_tls,
+    tls_server_name,
+    tls_client_certificate
+    from target_tcp;
+
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+  alter table target_tcp
+    drop column authorization_valid_seconds;
+
+commit;
@@ -0,0 +1,63 @@
+begin;
+
+  -- authorization_valid_seconds is how long a session authorized for the
+  -- target can stay pending before it's terminated with a reason of
+  -- never-connected. 0 means sessions can stay pending until they expire.
+  alter table target_tcp
+    add column authorization_valid_seconds integer not null default 0
+      constraint authorization_valid_seconds_must_not_be_negative
+      check(authorization_valid_seconds >= 0);
+
+  -- search_resource depends on target_all_subtypes, so the column is added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key,
+    host_selection_strategy,
+    endpoint_tls,
+    tls_server_name,
+    tls_client_certificate,
+    authorization_valid_seconds
+    from target_tcp;
+
+  alter table session_termination_reason_enm
+    drop constraint only_predefined_session_termination_reasons_allowed,
+    add constraint only_predefined_session_termination_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'terminated',
+          'network error',
+          'system error',
+          'connection limit',
+          'canceled',
+          'never-connected'
+        )
+      );
+
+  insert into session_termination_reason_enm (name)
+  values
+    ('never-connected');
+
+commit;
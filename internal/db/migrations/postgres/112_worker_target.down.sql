@@ -0,0 +1,5 @@
+begin;
+
+  drop table worker_target;
+
+commit;
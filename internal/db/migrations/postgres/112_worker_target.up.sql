@@ -0,0 +1,26 @@
+begin;
+
+  -- worker_target records the targets workers register from the targets
+  -- defined in their configuration. Sessions for them are only proxied by
+  -- the worker which registered them. Each target's address is held by a
+  -- static host catalog of its own, which is deleted along with the target
+  -- when the worker deregisters its targets.
+  create table worker_target (
+    target_id wt_public_id primary key
+      references target_tcp(public_id)
+      on delete cascade
+      on update cascade,
+    worker_name text not null
+      constraint worker_name_must_not_be_empty
+      check(length(trim(worker_name)) > 0),
+    host_catalog_id wt_public_id not null
+      references static_host_catalog(public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp
+  );
+
+  create index worker_target_worker_name_ix
+    on worker_target (worker_name);
+
+commit;
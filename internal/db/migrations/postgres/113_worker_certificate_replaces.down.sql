@@ -0,0 +1,6 @@
+begin;
+
+  alter table worker_auth_certificate
+    drop column replaces_serial_number;
+
+commit;
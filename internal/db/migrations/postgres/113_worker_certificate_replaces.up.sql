@@ -0,0 +1,15 @@
+begin;
+
+  -- replaces_serial_number is the certificate a rotated certificate replaces.
+  -- The replaced certificate stays valid until the new one is first
+  -- presented, so a worker which never receives its new certificate can
+  -- rotate again with the one it has. When the new certificate is first
+  -- presented, the replaced certificate and any other certificates issued
+  -- to replace it are revoked and replaces_serial_number is cleared.
+  alter table worker_auth_certificate
+    add column replaces_serial_number text
+      references worker_auth_certificate(serial_number)
+      on delete cascade
+      on update cascade;
+
+commit;
@@ -0,0 +1,22 @@
+begin;
+
+  alter table target_tcp
+    drop constraint encrypted_fields_must_have_key_id,
+    add column tls_key_id text
+      constraint tls_key_id_must_not_be_empty
+      check(length(trim(tls_key_id)) > 0);
+
+  update target_tcp
+     set tls_key_id = key_id
+   where tls_ca_bundle is not null
+      or tls_client_private_key is not null;
+
+  update target_tcp
+     set key_id = null
+   where ssh_private_key is null;
+
+  alter table target_tcp
+    add constraint ssh_private_key_must_have_key_id
+      check((ssh_private_key is null) = (key_id is null));
+
+commit;
@@ -0,0 +1,25 @@
+begin;
+
+  -- The ssh private key, tls ca bundle and tls client private key of a tcp
+  -- target are all encrypted with the database key of its scope, and share
+  -- key_id as the id of the key version they were last encrypted with. Key
+  -- versions aren't removed, so those encrypted with an earlier version can
+  -- still be decrypted. key_id isn't cleared when they are, since the
+  -- others may still need it.
+  update target_tcp
+     set key_id = tls_key_id
+   where key_id is null
+     and tls_key_id is not null;
+
+  alter table target_tcp
+    drop constraint ssh_private_key_must_have_key_id,
+    drop column tls_key_id,
+    add constraint encrypted_fields_must_have_key_id
+      check(
+        key_id is not null
+        or (ssh_private_key is null
+            and tls_ca_bundle is null
+            and tls_client_private_key is null)
+      );
+
+commit;
@@ -0,0 +1,27 @@
+begin;
+
+  alter table job
+    add column run_expiration_time timestamp with time zone;
+
+  update job
+     set run_expiration_time = now()
+   where running_server_id is not null;
+
+  alter table job
+    add constraint run_expiration_time_must_be_set_when_running
+      check((running_server_id is null) = (run_expiration_time is null));
+
+  create table controller_lease (
+    name text primary key
+      constraint name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    holder text not null
+      constraint holder_must_not_be_empty
+      check(length(trim(holder)) > 0),
+    acquire_time wt_timestamp not null,
+    expiration_time wt_timestamp not null,
+    constraint expiration_time_must_be_after_acquire_time
+      check(expiration_time > acquire_time)
+  );
+
+commit;
@@ -0,0 +1,14 @@
+begin;
+
+  -- Controllers now coordinate through database advisory locks, which the
+  -- database releases when the holding connection is lost: the leader holds
+  -- the controller-leader lock instead of a lease, and a job's claim is a lock
+  -- held for the duration of the run instead of an expiring claim. The job's
+  -- running_server_id records which controller is running it.
+  drop table controller_lease;
+
+  alter table job
+    drop constraint run_expiration_time_must_be_set_when_running,
+    drop column run_expiration_time;
+
+commit;
@@ -0,0 +1,29 @@
+begin;
+
+  -- release_version is the version of boundary the server is running.
+  alter table server
+    add column release_version text;
+
+  -- server_tag contains the key/value tags reported by a server. A key can
+  -- have multiple values. Tags are replaced in their entirety each time a
+  -- worker reports its status.
+  create table server_tag (
+    server_id text not null,
+    server_type text not null,
+    key text not null
+      constraint server_tag_key_must_not_be_empty
+      check(length(trim(key)) > 0),
+    value text not null
+      constraint server_tag_value_must_not_be_empty
+      check(length(trim(value)) > 0),
+    primary key (server_id, server_type, key, value),
+    foreign key (server_id, server_type)
+      references server (private_id, type)
+      on delete cascade
+      on update cascade
+  );
+
+  create index server_tag_key_value_idx
+    on server_tag (key, value);
+
+commit;
@@ -0,0 +1,8 @@
+begin;
+
+  alter table server
+    drop column active_session_count,
+    drop column active_connection_count,
+    drop column max_sessions;
+
+commit;
@@ -0,0 +1,17 @@
+begin;
+
+  -- Load reported by workers in their status updates. max_sessions is the
+  -- maximum number of sessions a worker will accept, where zero means the
+  -- worker does not impose a limit.
+  alter table server
+    add column active_session_count integer not null default 0
+      constraint active_session_count_must_be_zero_or_greater
+      check(active_session_count >= 0),
+    add column active_connection_count integer not null default 0
+      constraint active_connection_count_must_be_zero_or_greater
+      check(active_connection_count >= 0),
+    add column max_sessions integer not null default 0
+      constraint max_sessions_must_be_zero_or_greater
+      check(max_sessions >= 0);
+
+commit;
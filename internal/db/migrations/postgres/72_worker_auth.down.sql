@@ -0,0 +1,7 @@
+begin;
+
+  drop table worker_auth_certificate;
+  drop table worker_activation_token;
+  drop table worker_auth_ca;
+
+commit;
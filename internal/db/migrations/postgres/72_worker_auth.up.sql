@@ -0,0 +1,55 @@
+begin;
+
+  -- worker_auth_ca contains the certificate authorities controllers use to
+  -- issue certificates to workers. The private key is encrypted with the
+  -- global scope's database key. A new authority is created when the current
+  -- one is close to expiring; older authorities are kept until they expire so
+  -- the certificates they issued remain valid.
+  create table worker_auth_ca (
+    private_id wt_private_id primary key,
+    certificate bytea not null,
+    private_key bytea not null, -- encrypted
+    key_id text not null
+      references kms_database_key_version(private_id)
+      on delete restrict
+      on update cascade,
+    not_before wt_timestamp not null,
+    not_after wt_timestamp not null,
+    create_time wt_timestamp,
+    constraint not_after_must_be_after_not_before
+      check(not_after > not_before)
+  );
+
+  -- worker_activation_token contains the single use tokens workers present to
+  -- register with a controller. Only the sha256 of a token is stored.
+  create table worker_activation_token (
+    token_hash bytea primary key,
+    worker_name text not null
+      constraint worker_name_must_not_be_empty
+      check(length(trim(worker_name)) > 0),
+    expiration_time wt_timestamp not null,
+    redeemed_time timestamp with time zone,
+    create_time wt_timestamp
+  );
+
+  -- worker_auth_certificate contains the certificates issued to workers. A
+  -- worker connection is refused if its certificate is not found here or has
+  -- been revoked.
+  create table worker_auth_certificate (
+    serial_number text primary key,
+    worker_name text not null
+      constraint worker_name_must_not_be_empty
+      check(length(trim(worker_name)) > 0),
+    ca_id wt_private_id
+      references worker_auth_ca(private_id)
+      on delete cascade
+      on update cascade,
+    not_after wt_timestamp not null,
+    revoked_time timestamp with time zone,
+    create_time wt_timestamp
+  );
+
+  create index worker_auth_certificate_worker_name_idx
+    on worker_auth_certificate (worker_name);
+
+commit;
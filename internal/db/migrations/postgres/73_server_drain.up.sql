@@ -0,0 +1,9 @@
+begin;
+
+  -- A draining worker is not assigned new sessions but continues to handle
+  -- its existing sessions. It is set by an operator and not changed by the
+  -- worker's status updates.
+  alter table server
+    add column draining boolean not null default false;
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop table controller_lease;
+
+commit;
@@ -0,0 +1,22 @@
+begin;
+
+  -- controller_lease contains the leases controllers hold to coordinate work
+  -- which must only be done by one controller at a time. A lease is held by
+  -- the named controller until its expiration time; the holder extends the
+  -- expiration while it is running and any controller may take the lease
+  -- once it has expired. Times are set from the database's clock so the
+  -- clocks of the controllers do not need to agree.
+  create table controller_lease (
+    name text primary key
+      constraint name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    holder text not null
+      constraint holder_must_not_be_empty
+      check(length(trim(holder)) > 0),
+    acquire_time wt_timestamp not null,
+    expiration_time wt_timestamp not null,
+    constraint expiration_time_must_be_after_acquire_time
+      check(expiration_time > acquire_time)
+  );
+
+commit;
@@ -0,0 +1,32 @@
+begin;
+
+  -- job contains the background jobs registered with the controllers'
+  -- schedulers. A controller runs a job by claiming it once its next scheduled
+  -- run time has passed; the claim is held by setting running_server_id until
+  -- run_expiration_time, which the controller extends while the job runs. If
+  -- the controller stops, another controller can claim the job once the claim
+  -- has expired. Times are set from the database's clock so the clocks of the
+  -- controllers do not need to agree.
+  create table job (
+    name text primary key
+      constraint name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    description text not null,
+    next_scheduled_run wt_timestamp not null,
+    running_server_id text,
+    run_expiration_time timestamp with time zone,
+    last_run_start_time timestamp with time zone,
+    last_run_end_time timestamp with time zone,
+    last_run_status text
+      constraint last_run_status_must_be_valid
+      check(last_run_status in ('completed', 'failed', 'interrupted')),
+    last_run_error text,
+    failure_count integer not null default 0
+      constraint failure_count_must_be_zero_or_greater
+      check(failure_count >= 0),
+    create_time wt_timestamp,
+    constraint run_expiration_time_must_be_set_when_running
+      check((running_server_id is null) = (run_expiration_time is null))
+  );
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop table api_rate_limit_bucket;
+
+commit;
@@ -0,0 +1,20 @@
+begin;
+
+  -- api_rate_limit_bucket contains the token buckets used to rate limit API
+  -- requests when controllers share their rate limits through the database.
+  -- Each bucket holds up to its burst of tokens and is refilled at its rate
+  -- when it is next used. allowed records whether the last request taking a
+  -- token from the bucket was allowed.
+  create table api_rate_limit_bucket (
+    key text primary key
+      constraint key_must_not_be_empty
+      check(length(trim(key)) > 0),
+    tokens double precision not null,
+    allowed boolean not null,
+    update_time wt_timestamp not null
+  );
+
+  create index api_rate_limit_bucket_update_time_ix
+    on api_rate_limit_bucket (update_time);
+
+commit;
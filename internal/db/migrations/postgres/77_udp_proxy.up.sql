@@ -0,0 +1,50 @@
+begin;
+
+  -- protocol is the transport protocol proxied to a tcp target's hosts. udp
+  -- targets relay datagrams rather than a stream, and their endpoints use the
+  -- udp scheme.
+  alter table target_tcp
+    add column protocol text not null default 'tcp'
+      constraint protocol_must_be_tcp_or_udp
+      check(protocol in ('tcp', 'udp'));
+
+  drop view target_all_subtypes;
+
+  -- target_all_subtypes is a union of all target subtypes
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type
+    from target_tcp;
+
+  -- the total number of datagrams received by the worker from the client and
+  -- sent to the endpoint for a udp connection, and the reverse. Connections
+  -- to tcp targets don't carry datagrams and record 0 when closed.
+  alter table session_connection
+    add column datagrams_up bigint -- can be null
+      constraint datagrams_up_must_be_null_or_a_non_negative_number
+      check (
+        datagrams_up is null
+        or
+        datagrams_up >= 0
+      ),
+    add column datagrams_down bigint -- can be null
+      constraint datagrams_down_must_be_null_or_a_non_negative_number
+      check (
+        datagrams_down is null
+        or
+        datagrams_down >= 0
+      );
+
+commit;
@@ -0,0 +1,23 @@
+begin;
+
+  -- certificate_key_id is the id of the scope's database key the session's
+  -- certificate is encrypted with. It is null for sessions created before
+  -- certificates were encrypted, whose certificate is stored as is.
+  alter table session
+    add column certificate_key_id text -- can be null
+      constraint certificate_key_id_must_be_null_or_not_empty
+      check(
+        certificate_key_id is null
+        or
+        length(trim(certificate_key_id)) > 0
+      );
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
+
+commit;
@@ -0,0 +1,57 @@
+begin;
+
+  -- connection_idle_timeout_seconds is how long a connection to one of the
+  -- target's hosts can go without any traffic before it's closed. 0 means
+  -- connections are never closed for being idle.
+  alter table target_tcp
+    add column connection_idle_timeout_seconds integer not null default 0
+      constraint connection_idle_timeout_seconds_must_not_be_negative
+      check(connection_idle_timeout_seconds >= 0);
+
+  drop view target_all_subtypes;
+
+  -- target_all_subtypes is a union of all target subtypes
+  create view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type
+    from target_tcp;
+
+  -- last_activity_time is when traffic was last proxied for the connection,
+  -- as reported by the worker proxying it. It's only updated about once a
+  -- minute while there is traffic.
+  alter table session_connection
+    add column last_activity_time timestamp with time zone; -- can be null
+
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed,
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error',
+          'idle-timeout'
+        )
+      );
+
+  insert into session_connection_closed_reason_enm (name)
+  values
+    ('idle-timeout');
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop table auth_token_session;
+
+commit;
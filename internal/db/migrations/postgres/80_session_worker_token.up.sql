@@ -0,0 +1,29 @@
+begin;
+
+  -- auth_token_session holds the tokens workers are given when they look up
+  -- a session, which they must present to update the session's status on
+  -- behalf of its user. Only a hash of each token is stored as the tokens are
+  -- never returned once they are created.
+  create table auth_token_session (
+    public_id wt_public_id primary key,
+    session_id wt_public_id not null
+      references session (public_id)
+      on delete cascade
+      on update cascade,
+    token_hash bytea not null unique,
+    create_time wt_timestamp,
+    expiration_time timestamp with time zone not null
+      constraint expiration_time_must_be_after_create_time
+      check(expiration_time > create_time)
+  );
+
+  create index auth_token_session_session_id_ix
+    on auth_token_session (session_id);
+
+  create trigger immutable_columns before update on auth_token_session
+    for each row execute procedure immutable_columns('public_id', 'session_id', 'token_hash', 'create_time', 'expiration_time');
+
+  create trigger default_create_time_column before insert on auth_token_session
+    for each row execute procedure default_create_time();
+
+commit;
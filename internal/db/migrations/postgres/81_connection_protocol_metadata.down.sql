@@ -0,0 +1,9 @@
+begin;
+
+  drop index session_connection_protocol_ix;
+
+  alter table session_connection
+    drop column protocol_metadata,
+    drop column protocol;
+
+commit;
@@ -0,0 +1,19 @@
+begin;
+
+  -- protocol is the application protocol the worker detected being spoken
+  -- over the connection, such as ssh, and protocol_metadata is what the
+  -- worker learned about it when the connection was made, such as the
+  -- software version the endpoint identified itself with. Both are null when
+  -- the worker couldn't detect the protocol.
+  alter table session_connection
+    add column protocol text -- can be null
+      constraint protocol_must_not_be_empty
+      check(length(trim(protocol)) > 0),
+    add column protocol_metadata jsonb -- can be null
+      constraint protocol_metadata_must_be_an_object
+      check(jsonb_typeof(protocol_metadata) = 'object');
+
+  create index session_connection_protocol_ix
+    on session_connection (protocol);
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop view resource_scope;
+
+commit;
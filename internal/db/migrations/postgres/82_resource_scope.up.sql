@@ -0,0 +1,37 @@
+begin;
+
+  -- resource_scope is the scope of each resource which belongs to one,
+  -- including resources such as hosts whose scope is that of their parent.
+  -- Repositories use it to check within a transaction that the resources an
+  -- operation references are in the scope the operation is performed in.
+  create view resource_scope
+  as
+  select public_id, scope_id from iam_user
+   union all
+  select public_id, scope_id from iam_group
+   union all
+  select public_id, scope_id from iam_role
+   union all
+  select public_id, scope_id from auth_method
+   union all
+  select public_id, scope_id from auth_account
+   union all
+  select at.public_id, aa.scope_id
+    from auth_token at
+    join auth_account aa on aa.public_id = at.auth_account_id
+   union all
+  select public_id, scope_id from host_catalog
+   union all
+  select h.public_id, hc.scope_id
+    from host h
+    join host_catalog hc on hc.public_id = h.catalog_id
+   union all
+  select hs.public_id, hc.scope_id
+    from host_set hs
+    join host_catalog hc on hc.public_id = hs.catalog_id
+   union all
+  select public_id, scope_id from target
+   union all
+  select public_id, scope_id from session;
+
+commit;
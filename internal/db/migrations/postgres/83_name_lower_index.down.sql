@@ -0,0 +1,6 @@
+begin;
+
+  drop index iam_role_scope_id_lower_name_ix;
+  drop index target_tcp_scope_id_lower_name_ix;
+
+commit;
@@ -0,0 +1,13 @@
+begin;
+
+  -- Names are unique within a scope when compared exactly, which the
+  -- unique(scope_id, name) constraints enforce. Controllers configured with
+  -- enforce_unique_names also reject names which only differ by case, and
+  -- these indexes make looking up such a conflicting name cheap.
+  create index target_tcp_scope_id_lower_name_ix
+    on target_tcp (scope_id, lower(name));
+
+  create index iam_role_scope_id_lower_name_ix
+    on iam_role (scope_id, lower(name));
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop view search_resource;
+
+commit;
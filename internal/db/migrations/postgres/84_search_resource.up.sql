@@ -0,0 +1,63 @@
+begin;
+
+  -- search_resource is the searchable fields of each type of resource which
+  -- can be searched for. owner_id is the user the resource belongs to, if
+  -- any, and parent_id the resource it's a child of, if any, such as the
+  -- host catalog of a host. The search repository matches a query against
+  -- the name, description, public_id and address of the resources.
+  create view search_resource
+  as
+  select public_id,
+         'target' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from target_all_subtypes
+   union all
+  select h.public_id,
+         'host' as type,
+         c.scope_id,
+         h.name,
+         h.description,
+         h.address,
+         null::text as owner_id,
+         h.catalog_id as parent_id
+    from static_host h
+    join static_host_catalog c
+      on h.catalog_id = c.public_id
+   union all
+  select public_id,
+         'session' as type,
+         scope_id,
+         null::text as name,
+         null::text as description,
+         endpoint as address,
+         user_id as owner_id,
+         null::text as parent_id
+    from session
+   where scope_id is not null
+   union all
+  select public_id,
+         'user' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_user
+   union all
+  select public_id,
+         'group' as type,
+         scope_id,
+         name,
+         description,
+         null::text as address,
+         null::text as owner_id,
+         null::text as parent_id
+    from iam_group;
+
+commit;
@@ -0,0 +1,68 @@
+begin;
+
+  -- client_ip and user_agent are the source address and user agent of the
+  -- API request which authorized the session. They are null for sessions
+  -- authorized before they were recorded.
+  alter table session
+    add column client_ip text -- can be null
+      constraint client_ip_must_be_null_or_not_empty
+      check(
+        client_ip is null
+        or
+        length(trim(client_ip)) > 0
+      ),
+    add column user_agent text -- can be null
+      constraint user_agent_must_be_null_or_not_empty
+      check(
+        user_agent is null
+        or
+        length(trim(user_agent)) > 0
+      )
+      constraint user_agent_must_not_be_too_long
+      check(length(user_agent) <= 1024);
+
+  create index session_client_ip_ix
+    on session (client_ip);
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent');
+
+  create or replace view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
+
+commit;
@@ -0,0 +1,82 @@
+begin;
+
+  -- connection_bandwidth_limit is how many bytes per second each connection
+  -- to one of the target's hosts can proxy in each direction. 0 means
+  -- connections are not limited.
+  alter table target_tcp
+    add column connection_bandwidth_limit integer not null default 0
+      constraint connection_bandwidth_limit_must_not_be_negative
+      check(connection_bandwidth_limit >= 0);
+
+  -- search_resource depends on target_all_subtypes, so the column is added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit
+    from target_tcp;
+
+  -- the session's connection_bandwidth_limit is copied from its target when
+  -- the session is authorized, like its connection_limit, and is enforced by
+  -- the worker proxying the session's connections.
+  alter table session
+    add column connection_bandwidth_limit integer not null default 0
+      constraint connection_bandwidth_limit_must_not_be_negative
+      check(connection_bandwidth_limit >= 0);
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit');
+
+  create or replace view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+  drop index session_user_id_create_time_ix;
+  drop index session_scope_id_create_time_ix;
+  drop index session_state_session_id_start_time_ix;
+
+commit;
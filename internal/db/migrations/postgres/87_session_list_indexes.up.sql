@@ -0,0 +1,17 @@
+begin;
+
+  -- session_state_session_id_start_time_ix covers the lateral join which
+  -- finds the current state of each session when sessions are listed, so
+  -- it's found with an index only scan of the session's newest state.
+  create index session_state_session_id_start_time_ix
+    on session_state (session_id, start_time desc)
+    include (state, previous_end_time, end_time);
+
+  -- sessions are listed within a scope, and for a user, newest first
+  create index session_scope_id_create_time_ix
+    on session (scope_id, create_time desc);
+
+  create index session_user_id_create_time_ix
+    on session (user_id, create_time desc);
+
+commit;
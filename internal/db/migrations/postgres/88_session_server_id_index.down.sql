@@ -0,0 +1,5 @@
+begin;
+
+  drop index session_server_id_ix;
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+  -- workers reconcile their sessions by listing those assigned to them
+  create index session_server_id_ix
+    on session (server_id);
+
+commit;
@@ -0,0 +1,12 @@
+begin;
+
+  drop trigger iam_scope_move_project on iam_scope;
+  drop function iam_scope_move_project;
+
+  drop trigger immutable_columns on iam_scope;
+  create trigger immutable_columns
+  before
+  update on iam_scope
+    for each row execute procedure immutable_columns('public_id', 'create_time', 'type', 'parent_id');
+
+commit;
@@ -0,0 +1,45 @@
+begin;
+
+  -- projects can be moved to another org by changing their parent_id, so it
+  -- is no longer immutable
+  drop trigger immutable_columns on iam_scope;
+  create trigger immutable_columns
+  before
+  update on iam_scope
+    for each row execute procedure immutable_columns('public_id', 'create_time', 'type');
+
+  -- iam_scope_move_project only allows the parent of projects to change, and
+  -- keeps iam_scope_project in step so the project's name must be unique in
+  -- its new org. A role in the old org which grants access to the project
+  -- would be left granting access to a project outside its org, so the
+  -- project can't be moved while there are any.
+  create or replace function
+    iam_scope_move_project()
+    returns trigger
+  as $$
+  begin
+    if new.parent_id is distinct from old.parent_id then
+      if new.type != 'project' then
+        raise exception 'only projects can be moved to another scope';
+      end if;
+      perform from iam_role
+        where scope_id = old.parent_id
+          and grant_scope_id = new.public_id;
+      if found then
+        raise exception 'project has grants from roles in org %', old.parent_id;
+      end if;
+      update iam_scope_project
+         set parent_id = new.parent_id
+       where scope_id = new.public_id;
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger
+    iam_scope_move_project
+  before
+  update of parent_id on iam_scope
+    for each row execute procedure iam_scope_move_project();
+
+commit;
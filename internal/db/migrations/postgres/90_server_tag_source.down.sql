@@ -0,0 +1,14 @@
+begin;
+
+  delete from server_tag
+    where source = 'api';
+
+  alter table server_tag
+    drop constraint server_tag_pkey;
+  alter table server_tag
+    add primary key (server_id, server_type, key, value);
+
+  alter table server_tag
+    drop column source;
+
+commit;
@@ -0,0 +1,17 @@
+begin;
+
+  -- source records where a server tag came from. Tags from a worker's
+  -- configuration are replaced each time the worker reports its status; tags
+  -- set through the api are kept until they are set again or the server is
+  -- deleted. The same tag can come from both sources.
+  alter table server_tag
+    add column source text not null default 'configuration'
+      constraint server_tag_source_valid
+      check(source in ('configuration', 'api'));
+
+  alter table server_tag
+    drop constraint server_tag_pkey;
+  alter table server_tag
+    add primary key (server_id, server_type, key, value, source);
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+  alter table auth_password_account
+    drop column must_change_password;
+
+commit;
@@ -0,0 +1,9 @@
+begin;
+
+  -- must_change_password is set when an administrator sets an account's
+  -- password and requires the user to change it before they can
+  -- authenticate. It is cleared when the user changes their password.
+  alter table auth_password_account
+    add column must_change_password boolean not null default false;
+
+commit;
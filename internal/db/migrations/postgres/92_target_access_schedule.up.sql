@@ -0,0 +1,41 @@
+begin;
+
+  -- access_schedule is the weekly windows during which sessions can be
+  -- authorized for the target, such as 'TZ=Europe/London mon-fri
+  -- 09:00-17:00'. It is parsed and validated by the controller. Null means
+  -- sessions can be authorized at any time.
+  alter table target_tcp
+    add column access_schedule text
+      constraint access_schedule_must_not_be_empty
+      check(length(trim(access_schedule)) > 0);
+
+  -- terminate_sessions_at_schedule_end limits the expiration time of a
+  -- session to the end of the access schedule window it was authorized in,
+  -- so the session is terminated when the window ends.
+  alter table target_tcp
+    add column terminate_sessions_at_schedule_end boolean not null default false;
+
+  -- search_resource depends on target_all_subtypes, so the columns are added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end
+    from target_tcp;
+
+commit;
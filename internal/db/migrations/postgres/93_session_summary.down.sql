@@ -0,0 +1,5 @@
+begin;
+
+  drop table session_summary;
+
+commit;
@@ -0,0 +1,39 @@
+begin;
+
+  -- session_summary holds the summaries of terminated sessions replicated
+  -- from the primary database to a database in another region for
+  -- reporting. It is only written in the replica database. It has no
+  -- foreign keys since the sessions and the resources they reference are not
+  -- replicated, and are usually purged from the primary database long before
+  -- their summaries.
+  create table session_summary (
+    public_id text primary key,
+    scope_id text not null,
+    user_id text not null,
+    target_id text not null,
+    host_id text not null,
+    host_set_id text not null,
+    auth_token_id text not null,
+    server_id text not null,
+    endpoint text not null,
+    termination_reason text not null,
+    create_time timestamp with time zone not null,
+    terminate_time timestamp with time zone not null,
+    connection_count bigint not null default 0
+      constraint connection_count_must_not_be_negative
+      check(connection_count >= 0),
+    bytes_up bigint not null default 0
+      constraint bytes_up_must_not_be_negative
+      check(bytes_up >= 0),
+    bytes_down bigint not null default 0
+      constraint bytes_down_must_not_be_negative
+      check(bytes_down >= 0)
+  );
+
+  create index session_summary_terminate_time_public_id_idx
+    on session_summary (terminate_time, public_id);
+
+  create index session_summary_scope_id_terminate_time_idx
+    on session_summary (scope_id, terminate_time);
+
+commit;
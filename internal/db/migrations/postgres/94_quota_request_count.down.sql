@@ -0,0 +1,5 @@
+begin;
+
+  drop table quota_request_count;
+
+commit;
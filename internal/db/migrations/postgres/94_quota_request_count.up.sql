@@ -0,0 +1,19 @@
+begin;
+
+  -- quota_request_count counts the API requests made in each organization in
+  -- each quota period, for enforcing request quotas and for billing. Every
+  -- controller adds the requests it has counted to the row for the period.
+  create table quota_request_count (
+    scope_id wt_scope_id not null
+      references iam_scope_org (scope_id)
+      on delete cascade
+      on update cascade,
+    period_start timestamp with time zone not null,
+    request_count bigint not null default 0
+      constraint request_count_must_not_be_negative
+      check(request_count >= 0),
+    update_time wt_timestamp not null,
+    primary key (scope_id, period_start)
+  );
+
+commit;
@@ -0,0 +1,8 @@
+begin;
+
+  drop table iam_user_alias;
+  drop table iam_attribute_rule_member;
+  drop table iam_attribute_rule;
+  drop table auth_account_attributes;
+
+commit;
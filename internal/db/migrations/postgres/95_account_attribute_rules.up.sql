@@ -0,0 +1,117 @@
+begin;
+
+  -- auth_account_attributes holds the attributes of each account, as given by
+  -- its auth method the last time the account authenticated. They are
+  -- replaced at every login and matched by the predicates of
+  -- iam_attribute_rule.
+  create table auth_account_attributes (
+    account_id wt_public_id primary key
+      references auth_account (public_id)
+      on delete cascade
+      on update cascade,
+    attributes jsonb not null default '{}'
+      constraint attributes_must_be_an_object
+      check(jsonb_typeof(attributes) = 'object'),
+    update_time wt_timestamp
+  );
+
+  create trigger
+    update_time_column
+  before
+  update on auth_account_attributes
+    for each row execute procedure update_time_column();
+
+  -- iam_attribute_rule maps a predicate on the attributes of the accounts in
+  -- a scope to either membership of a group or a user alias, taken from the
+  -- value of the account's alias_attribute. Rules are evaluated every time an
+  -- account in the scope authenticates.
+  create table iam_attribute_rule (
+    public_id wt_public_id primary key,
+    scope_id wt_scope_id not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    attribute text not null
+      constraint attribute_must_not_be_empty
+      check(length(trim(attribute)) > 0),
+    operator text not null
+      constraint operator_must_be_known
+      check(operator in ('exists', 'equals', 'contains')),
+    value text not null default '',
+    group_id wt_public_id
+      references iam_group (public_id)
+      on delete cascade
+      on update cascade,
+    alias_attribute text
+      constraint alias_attribute_must_not_be_empty
+      check(length(trim(alias_attribute)) > 0),
+    create_time wt_timestamp,
+    constraint rule_must_have_one_result
+      check((group_id is null) <> (alias_attribute is null))
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on iam_attribute_rule
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_attribute_rule
+    for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+
+  -- iam_attribute_rule_member records the group memberships added by
+  -- attribute rules, so they can be removed when the rule stops matching
+  -- without removing memberships added directly. Deleting the membership
+  -- deletes the record.
+  create table iam_attribute_rule_member (
+    rule_id wt_public_id not null
+      references iam_attribute_rule (public_id)
+      on delete cascade
+      on update cascade,
+    group_id wt_public_id not null,
+    member_id wt_user_id not null,
+    create_time wt_timestamp,
+    primary key (rule_id, member_id),
+    foreign key (group_id, member_id)
+      references iam_group_member_user (group_id, member_id)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on iam_attribute_rule_member
+    for each row execute procedure default_create_time();
+
+  -- iam_user_alias holds the aliases attribute rules have given users. An
+  -- alias is unique within the scope of the rule which gave it, and is kept
+  -- by the first user given it.
+  create table iam_user_alias (
+    scope_id wt_scope_id not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    alias text not null,
+    user_id wt_user_id not null
+      references iam_user (public_id)
+      on delete cascade
+      on update cascade,
+    rule_id wt_public_id not null
+      references iam_attribute_rule (public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp,
+    primary key (scope_id, alias)
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on iam_user_alias
+    for each row execute procedure default_create_time();
+
+commit;
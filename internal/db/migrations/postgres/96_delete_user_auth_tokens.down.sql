@@ -0,0 +1,6 @@
+begin;
+
+  drop trigger delete_user_auth_tokens on iam_user;
+  drop function delete_user_auth_tokens;
+
+commit;
@@ -0,0 +1,28 @@
+begin;
+
+  -- delete_user_auth_tokens is a before delete trigger on iam_user which
+  -- deletes the auth tokens of the user's accounts. Deleting a user only
+  -- unlinks its accounts, so without it the tokens would keep authenticating
+  -- requests. The user's sessions are canceled by cancel_session_with_null_fk
+  -- when their user_id is set to null.
+  create function
+    delete_user_auth_tokens()
+    returns trigger
+  as $$
+  begin
+    delete from auth_token
+     where auth_account_id in (
+       select public_id
+         from auth_account
+        where iam_user_id = old.public_id
+     );
+    return old;
+  end;
+  $$ language plpgsql;
+
+  create trigger
+    delete_user_auth_tokens
+  before delete on iam_user
+    for each row execute procedure delete_user_auth_tokens();
+
+commit;
@@ -0,0 +1,85 @@
+begin;
+
+  -- allowed_ports is the ports and ranges of ports, besides default_port,
+  -- which clients can ask to connect to on the target's hosts, such as
+  -- '5432,5433,6000-6010'. It is parsed and validated by the controller. Null
+  -- means only the default port can be connected to.
+  alter table target_tcp
+    add column allowed_ports text
+      constraint allowed_ports_must_not_be_empty
+      check(length(trim(allowed_ports)) > 0);
+
+  -- search_resource depends on target_all_subtypes, so the column is added
+  -- to the end of the view rather than dropping and recreating it.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports
+    from target_tcp;
+
+  -- the session's allowed_ports are copied from its target when the session
+  -- is authorized, and are checked by the worker against the port each
+  -- connection asks for.
+  alter table session
+    add column allowed_ports text;
+
+  drop trigger immutable_columns on session;
+
+  create trigger
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'certificate_key_id', 'expiration_time', 'connection_limit', 'create_time', 'endpoint', 'client_ip', 'user_agent', 'connection_bandwidth_limit', 'allowed_ports');
+
+  create or replace view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time,
+    s.client_ip,
+    s.user_agent,
+    s.connection_bandwidth_limit,
+    s.allowed_ports
+  from
+    session s,
+    session_state ss
+  where
+    s.public_id = ss.session_id;
+
+commit;
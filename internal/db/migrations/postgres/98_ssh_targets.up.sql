@@ -0,0 +1,58 @@
+begin;
+
+  -- ssh targets are proxied by workers which terminate the client's ssh
+  -- connection and authenticate to the target's hosts themselves, with
+  -- credentials brokered by the target, so users never see them.
+  alter table target_tcp
+    drop constraint protocol_must_be_tcp_or_udp;
+
+  alter table target_tcp
+    add constraint protocol_must_be_tcp_udp_or_ssh
+      check(protocol in ('tcp', 'udp', 'ssh'));
+
+  -- ssh_private_key is encrypted with the database key of the target's
+  -- scope, whose version is key_id. ssh_host_key is the public key the
+  -- target's hosts must present, in authorized_keys format.
+  alter table target_tcp
+    add column ssh_username text
+      constraint ssh_username_must_not_be_empty
+      check(length(trim(ssh_username)) > 0),
+    add column ssh_private_key bytea,
+    add column ssh_host_key text
+      constraint ssh_host_key_must_not_be_empty
+      check(length(trim(ssh_host_key)) > 0),
+    -- TODO: Make key_id a foreign key once we have DEKs
+    add column key_id text
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    add constraint ssh_private_key_must_have_key_id
+      check((ssh_private_key is null) = (key_id is null));
+
+  -- search_resource depends on target_all_subtypes, so the columns are added
+  -- to the end of the view rather than dropping and recreating it. The
+  -- private key is left out so it's only read when brokering a session.
+  create or replace view target_all_subtypes
+  as
+  select
+    public_id,
+    scope_id,
+    name,
+    description,
+    default_port,
+    session_max_seconds,
+    session_connection_limit,
+    protocol,
+    connection_idle_timeout_seconds,
+    version,
+    create_time,
+    update_time,
+    'tcp' as type,
+    connection_bandwidth_limit,
+    access_schedule,
+    terminate_sessions_at_schedule_end,
+    allowed_ports,
+    ssh_username,
+    ssh_host_key
+    from target_tcp;
+
+commit;
@@ -0,0 +1,9 @@
+begin;
+
+  drop trigger iam_group_member_user_hst_delete on iam_group_member_user;
+  drop trigger iam_group_member_user_hst_insert on iam_group_member_user;
+  drop function iam_group_member_user_hst_delete;
+  drop function iam_group_member_user_hst_insert;
+  drop table iam_group_member_user_hst;
+
+commit;
@@ -0,0 +1,79 @@
+begin;
+
+  -- iam_group_member_user_hst records the periods users were members of
+  -- groups, so past memberships can be found after they are removed. A
+  -- period whose valid_to is null is current. Rows don't reference the
+  -- group or user, so they are kept when either is deleted.
+  create table iam_group_member_user_hst (
+    group_id wt_public_id not null,
+    member_id wt_user_id not null,
+    valid_from wt_timestamp not null,
+    valid_to timestamp with time zone,
+    primary key (group_id, member_id, valid_from),
+    constraint valid_to_must_not_be_before_valid_from
+      check(valid_to >= valid_from)
+  );
+
+  create index iam_group_member_user_hst_group_id_valid_from_ix
+    on iam_group_member_user_hst (group_id, valid_from);
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_group_member_user_hst
+    for each row execute procedure immutable_columns('group_id', 'member_id', 'valid_from');
+
+  -- iam_group_member_user_hst_insert starts a period when a user is added
+  -- to a group. A user removed and added again in the same transaction
+  -- reopens the period the removal closed.
+  create function
+    iam_group_member_user_hst_insert()
+    returns trigger
+  as $$
+  begin
+    insert into iam_group_member_user_hst
+      (group_id, member_id, valid_from)
+    values
+      (new.group_id, new.member_id, new.create_time)
+    on conflict (group_id, member_id, valid_from) do update
+      set valid_to = null;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger
+    iam_group_member_user_hst_insert
+  after
+  insert on iam_group_member_user
+    for each row execute procedure iam_group_member_user_hst_insert();
+
+  -- iam_group_member_user_hst_delete ends the current period when a user
+  -- is removed from a group, including when the group or user is deleted.
+  create function
+    iam_group_member_user_hst_delete()
+    returns trigger
+  as $$
+  begin
+    update iam_group_member_user_hst
+       set valid_to = current_timestamp
+     where group_id = old.group_id
+       and member_id = old.member_id
+       and valid_to is null;
+    return old;
+  end;
+  $$ language plpgsql;
+
+  create trigger
+    iam_group_member_user_hst_delete
+  after
+  delete on iam_group_member_user
+    for each row execute procedure iam_group_member_user_hst_delete();
+
+  -- Existing memberships are recorded from when they were created; the
+  -- memberships removed before now can't be recovered.
+  insert into iam_group_member_user_hst
+    (group_id, member_id, valid_from)
+  select group_id, member_id, create_time
+    from iam_group_member_user;
+
+commit;
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is an up migration of a database dialect.
+type Migration struct {
+	// Version is the version of the schema after the migration, taken from
+	// the number its file name starts with
+	Version uint
+	// Name is the file name of the migration
+	Name string
+	// Statements are the SQL statements of the migration
+	Statements string
+}
+
+// UpMigrations returns the up migrations of the dialect in the order they
+// are applied.
+func UpMigrations(dialect string) ([]Migration, error) {
+	var migrationsMap map[string]*fakeFile
+	switch dialect {
+	case "postgres":
+		migrationsMap = postgresMigrations
+	default:
+		return nil, fmt.Errorf("unknown migrations dialect %s", dialect)
+	}
+
+	var ret []Migration
+	for k, f := range migrationsMap {
+		name := strings.TrimPrefix(k, "migrations/")
+		if !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		i := strings.Index(name, "_")
+		if i < 0 {
+			return nil, fmt.Errorf("migration %s has no version", name)
+		}
+		version, err := strconv.ParseUint(name[:i], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", name, err)
+		}
+		ret = append(ret, Migration{
+			Version:    uint(version),
+			Name:       name,
+			Statements: string(f.bytes),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Version < ret[j].Version })
+	return ret, nil
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpMigrations(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	_, err := UpMigrations("unknown")
+	assert.Error(err)
+
+	got, err := UpMigrations("postgres")
+	require.NoError(err)
+	require.NotEmpty(got)
+	assert.Equal(uint(1), got[0].Version)
+	assert.Equal("01_domain_types.up.sql", got[0].Name)
+	for i := 1; i < len(got); i++ {
+		assert.Less(got[i-1].Version, got[i].Version)
+		assert.Contains(got[i].Name, ".up.sql")
+		assert.NotEmpty(got[i].Statements)
+	}
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"time"
+
 	"github.com/hashicorp/boundary/internal/oplog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 )
@@ -40,6 +42,10 @@ type Options struct {
 	withWhereClause     string
 	withWhereClauseArgs []interface{}
 	withOrder           string
+
+	withWrapperFn WrapperFn
+
+	withSlowQueryThreshold time.Duration
 }
 
 type oplogOpts struct {
@@ -155,3 +161,19 @@ func WithOrder(withOrder string) Option {
 		o.withOrder = withOrder
 	}
 }
+
+// WithWrapperFn provides an option to encrypt the fields of a resource tagged
+// for encryption when it is written and decrypt them when it is read.
+func WithWrapperFn(fn WrapperFn) Option {
+	return func(o *Options) {
+		o.withWrapperFn = fn
+	}
+}
+
+// WithSlowQueryThreshold provides an option to log the queries which take
+// at least threshold to run.
+func WithSlowQueryThreshold(threshold time.Duration) Option {
+	return func(o *Options) {
+		o.withSlowQueryThreshold = threshold
+	}
+}
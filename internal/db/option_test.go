@@ -2,6 +2,7 @@ package db
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/stretchr/testify/assert"
@@ -175,4 +176,16 @@ func Test_getOpts(t *testing.T) {
 		testOpts.withOrder = "version desc"
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithSlowQueryThreshold", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of 0
+		opts := GetOpts()
+		testOpts := getDefaultOptions()
+		testOpts.withSlowQueryThreshold = 0
+		assert.Equal(opts, testOpts)
+
+		opts = GetOpts(WithSlowQueryThreshold(time.Second))
+		testOpts.withSlowQueryThreshold = time.Second
+		assert.Equal(opts, testOpts)
+	})
 }
@@ -3,13 +3,15 @@ package db
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/db/common"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/metric"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/oplog/store"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
@@ -180,6 +182,7 @@ func New(underlying *gorm.DB) *Db {
 // is the number of rows affected by the sql. No options are currently
 // supported.
 func (rw *Db) Exec(ctx context.Context, sql string, values []interface{}, opt ...Option) (int, error) {
+	defer observeDuration("exec", time.Now())
 	if sql == "" {
 		return NoRowsAffected, fmt.Errorf("missing sql: %w", ErrInvalidParameter)
 	}
@@ -195,6 +198,7 @@ func (rw *Db) Exec(ctx context.Context, sql string, values []interface{}, opt ..
 // caller must close the returned *sql.Rows. Query can/should be used in
 // combination with ScanRows.
 func (rw *Db) Query(ctx context.Context, sql string, values []interface{}, opt ...Option) (*sql.Rows, error) {
+	defer observeDuration("query", time.Now())
 	if sql == "" {
 		return nil, fmt.Errorf("raw missing sql: %w", ErrInvalidParameter)
 	}
@@ -229,11 +233,15 @@ func (rw *Db) lookupAfterWrite(ctx context.Context, i interface{}, opt ...Option
 	return nil
 }
 
-// Create an object in the db with options: WithOplog, NewOplogMsg and
-// WithLookup.  WithOplog will write an oplog entry for the create.
-// NewOplogMsg will return in-memory oplog message.  WithOplog and NewOplogMsg
-// cannot be used together.  WithLookup with to force a lookup after create.
+// Create an object in the db with options: WithOplog, NewOplogMsg,
+// WithLookup and WithWrapperFn.  WithOplog will write an oplog entry for the
+// create. NewOplogMsg will return in-memory oplog message.  WithOplog and
+// NewOplogMsg cannot be used together.  WithLookup with to force a lookup
+// after create. WithWrapperFn is required to create resources with plaintext
+// fields tagged for encryption set, which are encrypted before they are
+// written.
 func (rw *Db) Create(ctx context.Context, i interface{}, opt ...Option) error {
+	defer observeDuration("create", time.Now())
 	if rw.underlying == nil {
 		return fmt.Errorf("create: missing underlying db: %w", ErrInvalidParameter)
 	}
@@ -256,6 +264,9 @@ func (rw *Db) Create(ctx context.Context, i interface{}, opt ...Option) error {
 	// db to manage them
 	setFieldsToNil(i, []string{"CreateTime", "UpdateTime"})
 
+	if err := vetEncryption(ctx, i, opts); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
 	if !opts.withSkipVetForWrite {
 		if vetter, ok := i.(VetForWriter); ok {
 			if err := vetter.VetForWrite(ctx, rw, CreateOp); err != nil {
@@ -293,9 +304,10 @@ func (rw *Db) Create(ctx context.Context, i interface{}, opt ...Option) error {
 }
 
 // CreateItems will create multiple items of the same type. Supported options:
-// WithOplog and WithOplogMsgs.  WithOplog and WithOplogMsgs may not be used
-// together.  WithLookup is not a supported option.
+// WithOplog, WithOplogMsgs and WithWrapperFn.  WithOplog and WithOplogMsgs may
+// not be used together.  WithLookup is not a supported option.
 func (rw *Db) CreateItems(ctx context.Context, createItems []interface{}, opt ...Option) error {
+	defer observeDuration("create_items", time.Now())
 	if rw.underlying == nil {
 		return fmt.Errorf("create items: missing underlying db: %w", ErrInvalidParameter)
 	}
@@ -335,7 +347,7 @@ func (rw *Db) CreateItems(ctx context.Context, createItems []interface{}, opt ..
 		}
 	}
 	for _, item := range createItems {
-		if err := rw.Create(ctx, item); err != nil {
+		if err := rw.Create(ctx, item, WithWrapperFn(opts.withWrapperFn)); err != nil {
 			return fmt.Errorf("create items: %w", err)
 		}
 
@@ -366,15 +378,19 @@ func (rw *Db) CreateItems(ctx context.Context, createItems []interface{}, opt ..
 // which almost always should be to rollback.  Update returns the number of
 // rows updated.
 //
-// Supported options: WithOplog, NewOplogMsg and WithVersion.
+// Supported options: WithOplog, NewOplogMsg, WithVersion and WithWrapperFn.
 // WithOplog will write an oplog entry for the update. NewOplogMsg
 // will return in-memory oplog message.  WithOplog and NewOplogMsg cannot be
 // used together.   If WithVersion is used, then the update will include the
 // version number in the update where clause, which basically makes the update
 // use optimistic locking and the update will only succeed if the existing rows
 // version matches the WithVersion option.  Zero is not a valid value for the
-// WithVersion option and will return an error.
+// WithVersion option and will return an error.  WithWrapperFn is required to
+// update resources with plaintext fields tagged for encryption set; plaintext
+// fields in fieldMaskPaths are encrypted and their ciphertext fields updated,
+// and plaintext fields in setToNullPaths set their ciphertext fields to null.
 func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string, setToNullPaths []string, opt ...Option) (int, error) {
+	defer observeDuration("update", time.Now())
 	if rw.underlying == nil {
 		return NoRowsAffected, fmt.Errorf("update: missing underlying db %w", ErrInvalidParameter)
 	}
@@ -390,6 +406,20 @@ func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string
 		return NoRowsAffected, fmt.Errorf("update: both WithOplog and NewOplogMsg options have been specified: %w", ErrInvalidParameter)
 	}
 
+	// plaintext fields tagged for encryption are updated by updating their
+	// ciphertext fields
+	info, err := encryptInfoFor(reflect.TypeOf(i))
+	if err != nil {
+		return NoRowsAffected, fmt.Errorf("update: %w", err)
+	}
+	if info != nil {
+		if err := vetEncryption(ctx, i, opts); err != nil {
+			return NoRowsAffected, fmt.Errorf("update: %w", err)
+		}
+		fieldMaskPaths = info.encryptedPaths(reflect.TypeOf(i), fieldMaskPaths)
+		setToNullPaths, _ = info.ciphertextPaths(reflect.TypeOf(i), setToNullPaths)
+	}
+
 	// we need to filter out some non-updatable fields (like: CreateTime, etc)
 	fieldMaskPaths = filterPaths(fieldMaskPaths)
 	setToNullPaths = filterPaths(setToNullPaths)
@@ -509,6 +539,7 @@ func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string
 // WithWhere allows specifying a constraint. Delete returns the number of rows
 // deleted and any errors.
 func (rw *Db) Delete(ctx context.Context, i interface{}, opt ...Option) (int, error) {
+	defer observeDuration("delete", time.Now())
 	if rw.underlying == nil {
 		return NoRowsAffected, fmt.Errorf("delete: missing underlying db %w", ErrInvalidParameter)
 	}
@@ -572,6 +603,7 @@ func (rw *Db) Delete(ctx context.Context, i interface{}, opt ...Option) (int, er
 // WithOplog and WithOplogMsgs.  WithOplog and WithOplogMsgs may not be used
 // together.
 func (rw *Db) DeleteItems(ctx context.Context, deleteItems []interface{}, opt ...Option) (int, error) {
+	defer observeDuration("delete_items", time.Now())
 	if rw.underlying == nil {
 		return NoRowsAffected, fmt.Errorf("delete items: missing underlying db: %w", ErrInvalidParameter)
 	}
@@ -741,7 +773,7 @@ func (rw *Db) addOplogForItems(ctx context.Context, opType OpType, opts Options,
 	}
 	entry, err := oplog.NewEntry(
 		replayable.TableName(),
-		oplogArgs.metadata,
+		withRequestMetadata(ctx, oplogArgs.metadata),
 		oplogArgs.wrapper,
 		ticketer,
 	)
@@ -774,7 +806,7 @@ func (rw *Db) addOplog(ctx context.Context, opType OpType, opts Options, ticket
 	}
 	entry, err := oplog.NewEntry(
 		replayable.TableName(),
-		oplogArgs.metadata,
+		withRequestMetadata(ctx, oplogArgs.metadata),
 		oplogArgs.wrapper,
 		ticketer,
 	)
@@ -826,7 +858,7 @@ func (rw *Db) WriteOplogEntryWith(ctx context.Context, wrapper wrapping.Wrapper,
 
 	entry, err := oplog.NewEntry(
 		ticket.Name,
-		metadata,
+		withRequestMetadata(ctx, metadata),
 		wrapper,
 		ticketer,
 	)
@@ -845,6 +877,25 @@ func (rw *Db) WriteOplogEntryWith(ctx context.Context, wrapper wrapping.Wrapper,
 	return nil
 }
 
+// withRequestMetadata returns a copy of metadata which also records the id of
+// the API request, and the user making it, for which an oplog entry is
+// written, so the entry can be correlated with the request's events.
+func withRequestMetadata(ctx context.Context, metadata oplog.Metadata) oplog.Metadata {
+	info, ok := event.RequestInfoFromContext(ctx)
+	if !ok || metadata == nil {
+		return metadata
+	}
+	md := make(oplog.Metadata, len(metadata)+2)
+	for k, v := range metadata {
+		md[k] = v
+	}
+	md["request-id"] = []string{info.Id}
+	if info.UserId != "" {
+		md["actor-user-id"] = []string{info.UserId}
+	}
+	return md
+}
+
 func (rw *Db) newOplogMessage(ctx context.Context, opType OpType, i interface{}, opt ...Option) (*oplog.Message, error) {
 	opts := GetOpts(opt...)
 	replayable, ok := i.(oplog.ReplayableMessage)
@@ -892,7 +943,7 @@ func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler Tx
 			if err := newTx.Rollback().Error; err != nil {
 				return info, err
 			}
-			if errors.Is(err, oplog.ErrTicketAlreadyRedeemed) {
+			if errors.Is(err, oplog.ErrTicketAlreadyRedeemed) || errors.IsRetryable(err) {
 				d := backOff.Duration(attempts)
 				info.Retries++
 				info.Backoff = info.Backoff + d
@@ -903,6 +954,17 @@ func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler Tx
 		}
 
 		if err := newTx.Commit().Error; err != nil {
+			// serialization failures may not be reported until the commit,
+			// so they need to be retried here as well. Other errors, such as
+			// a lost connection, leave it unknown whether the transaction
+			// was committed, so retrying could apply it twice.
+			if errors.IsTxConflict(err) {
+				d := backOff.Duration(attempts)
+				info.Retries++
+				info.Backoff = info.Backoff + d
+				time.Sleep(d)
+				continue
+			}
 			if err := newTx.Rollback().Error; err != nil {
 				return info, err
 			}
@@ -912,9 +974,11 @@ func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler Tx
 	}
 }
 
-// LookupByPublicId will lookup resource by its public_id or private_id, which
-// must be unique. Options are ignored.
+// LookupById will lookup resource by its public_id or private_id, which
+// must be unique. Supports the WithWrapperFn option to decrypt the fields of
+// the resource tagged for encryption.
 func (rw *Db) LookupById(ctx context.Context, resourceWithIder interface{}, opt ...Option) error {
+	defer observeDuration("lookup", time.Now())
 	if rw.underlying == nil {
 		return fmt.Errorf("lookup by id: underlying db nil %w", ErrInvalidParameter)
 	}
@@ -931,6 +995,11 @@ func (rw *Db) LookupById(ctx context.Context, resourceWithIder interface{}, opt
 		}
 		return err
 	}
+	if opts := GetOpts(opt...); opts.withWrapperFn != nil {
+		if err := Decrypt(ctx, resourceWithIder, opts.withWrapperFn); err != nil {
+			return fmt.Errorf("lookup by id: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -953,13 +1022,14 @@ func primaryKeyWhere(resourceWithIder interface{}) (pkey string, w string, e err
 }
 
 // LookupByPublicId will lookup resource by its public_id, which must be unique.
-// Options are ignored.
+// Supports the WithWrapperFn option.
 func (rw *Db) LookupByPublicId(ctx context.Context, resource ResourcePublicIder, opt ...Option) error {
 	return rw.LookupById(ctx, resource, opt...)
 }
 
 // LookupWhere will lookup the first resource using a where clause with parameters (it only returns the first one)
 func (rw *Db) LookupWhere(ctx context.Context, resource interface{}, where string, args ...interface{}) error {
+	defer observeDuration("lookup_where", time.Now())
 	if rw.underlying == nil {
 		return errors.New("error underlying db nil for lookup by")
 	}
@@ -978,8 +1048,11 @@ func (rw *Db) LookupWhere(ctx context.Context, resource interface{}, where strin
 // SearchWhere will search for all the resources it can find using a where
 // clause with parameters.  Supports the WithLimit option.  If
 // WithLimit < 0, then unlimited results are returned.  If WithLimit == 0, then
-// default limits are used for results.  Supports the WithOrder option.
+// default limits are used for results.  Supports the WithOrder option, and
+// the WithWrapperFn option to decrypt the fields of the resources tagged for
+// encryption.
 func (rw *Db) SearchWhere(ctx context.Context, resources interface{}, where string, args []interface{}, opt ...Option) error {
+	defer observeDuration("search_where", time.Now())
 	opts := GetOpts(opt...)
 	if rw.underlying == nil {
 		return errors.New("error underlying db nil for search by")
@@ -1012,6 +1085,11 @@ func (rw *Db) SearchWhere(ctx context.Context, resources interface{}, where stri
 		// searching with a slice parameter does not return a gorm.ErrRecordNotFound
 		return err
 	}
+	if opts.withWrapperFn != nil {
+		if err := decryptAll(ctx, resources, opts.withWrapperFn); err != nil {
+			return fmt.Errorf("search where: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -1114,3 +1192,9 @@ func clear(v reflect.Value, fields map[string]bool, depth int) {
 		}
 	}
 }
+
+// observeDuration records the time taken by the database operation op, which
+// started at start.
+func observeDuration(op string, start time.Time) {
+	metric.DbQueryDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+}
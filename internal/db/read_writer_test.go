@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/hashicorp/boundary/internal/db/db_test"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/event"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/oplog/store"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
@@ -2940,3 +2941,19 @@ func TestDb_lookupAfterWrite(t *testing.T) {
 		})
 	}
 }
+
+func Test_withRequestMetadata(t *testing.T) {
+	assert := assert.New(t)
+	md := oplog.Metadata{"op-type": []string{"create"}}
+
+	assert.Equal(md, withRequestMetadata(context.Background(), md))
+
+	ctx := event.NewRequestInfoContext(context.Background(), &event.RequestInfo{Id: "req_1234567890", UserId: "u_1234567890"})
+	assert.Equal(oplog.Metadata{
+		"op-type":       []string{"create"},
+		"request-id":    []string{"req_1234567890"},
+		"actor-user-id": []string{"u_1234567890"},
+	}, withRequestMetadata(ctx, md))
+	// The original metadata is left unchanged
+	assert.Len(md, 1)
+}
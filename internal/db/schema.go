@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db/migrations"
+	"github.com/lib/pq"
+)
+
+// verifySchemaName is the schema the migrations are applied to when
+// verifying a database's schema. It only exists within the transaction
+// VerifySchema rolls back.
+const verifySchemaName = "boundary_verify_schema"
+
+// transactionStatements matches the statements which begin and commit the
+// transaction each migration runs in, so migrations can be applied within
+// the transaction of VerifySchema.
+var transactionStatements = regexp.MustCompile(`(?im)^\s*(begin|commit);\s*$`)
+
+// schemaObjectQueries return the name and definition of each kind of
+// object in the schema named $1 which is compared by VerifySchema. Objects
+// created by extensions are left out.
+var schemaObjectQueries = []struct {
+	typ   string
+	query string
+}{
+	{
+		typ: "table",
+		query: `
+select c.relname, ''
+  from pg_class c
+  join pg_namespace n on n.oid = c.relnamespace
+ where n.nspname = $1 and c.relkind in ('r', 'p');
+`,
+	},
+	{
+		typ: "column",
+		query: `
+select c.relname || '.' || a.attname,
+       format_type(a.atttypid, a.atttypmod)
+         || case when a.attnotnull then ' not null' else '' end
+         || coalesce(' default ' || pg_get_expr(d.adbin, d.adrelid), '')
+  from pg_attribute a
+  join pg_class c on c.oid = a.attrelid
+  join pg_namespace n on n.oid = c.relnamespace
+  left join pg_attrdef d on d.adrelid = a.attrelid and d.adnum = a.attnum
+ where n.nspname = $1 and c.relkind in ('r', 'p', 'v')
+   and a.attnum > 0 and not a.attisdropped;
+`,
+	},
+	{
+		typ: "constraint",
+		query: `
+select c.relname || '.' || con.conname, pg_get_constraintdef(con.oid)
+  from pg_constraint con
+  join pg_class c on c.oid = con.conrelid
+  join pg_namespace n on n.oid = con.connamespace
+ where n.nspname = $1;
+`,
+	},
+	{
+		typ: "index",
+		query: `
+select indexname, indexdef
+  from pg_indexes
+ where schemaname = $1;
+`,
+	},
+	{
+		typ: "trigger",
+		query: `
+select c.relname || '.' || t.tgname, pg_get_triggerdef(t.oid)
+  from pg_trigger t
+  join pg_class c on c.oid = t.tgrelid
+  join pg_namespace n on n.oid = c.relnamespace
+ where n.nspname = $1 and not t.tgisinternal;
+`,
+	},
+	{
+		typ: "view",
+		query: `
+select c.relname, pg_get_viewdef(c.oid, true)
+  from pg_class c
+  join pg_namespace n on n.oid = c.relnamespace
+ where n.nspname = $1 and c.relkind in ('v', 'm');
+`,
+	},
+	{
+		typ: "function",
+		query: `
+select p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')',
+       'md5:' || md5(p.prosrc)
+  from pg_proc p
+  join pg_namespace n on n.oid = p.pronamespace
+ where n.nspname = $1
+   and not exists (
+     select from pg_depend d
+      where d.objid = p.oid and d.deptype = 'e'
+   );
+`,
+	},
+}
+
+// SchemaObject is an object in a database schema, such as a table, column,
+// constraint, index, trigger, view or function. The definitions of
+// functions are the md5 of their source.
+type SchemaObject struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Definition string `json:"definition,omitempty"`
+}
+
+// SchemaChange is an object in a database schema whose definition differs
+// from the definition created by the migrations.
+type SchemaChange struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// SchemaReport is the result of verifying a database's schema. Missing are
+// objects the migrations create which the database doesn't have, Unexpected
+// are objects the database has which the migrations don't create and
+// Changed are objects whose definitions differ.
+type SchemaReport struct {
+	Version         uint           `json:"version"`
+	Dirty           bool           `json:"dirty"`
+	ExpectedVersion uint           `json:"expected_version"`
+	Missing         []SchemaObject `json:"missing,omitempty"`
+	Unexpected      []SchemaObject `json:"unexpected,omitempty"`
+	Changed         []SchemaChange `json:"changed,omitempty"`
+}
+
+// Drifted returns true if the schema isn't the one created by the
+// migrations, including when not every migration has been run.
+func (r *SchemaReport) Drifted() bool {
+	return r.Dirty || r.Version != r.ExpectedVersion ||
+		len(r.Missing) > 0 || len(r.Unexpected) > 0 || len(r.Changed) > 0
+}
+
+// VerifySchema compares the schema of the database with the schema created
+// by Boundary's migrations and reports the differences. The expected schema
+// is created by applying every migration to a new schema within a
+// transaction which is then rolled back, so the connection must be allowed
+// to create schemas, as when running migrations. Nothing is changed in the
+// database.
+func VerifySchema(ctx context.Context, d *sql.DB) (*SchemaReport, error) {
+	if d == nil {
+		return nil, fmt.Errorf("verify schema: missing database: %w", ErrInvalidParameter)
+	}
+	migs, err := migrations.UpMigrations("postgres")
+	if err != nil {
+		return nil, fmt.Errorf("verify schema: %w", err)
+	}
+	if len(migs) == 0 {
+		return nil, fmt.Errorf("verify schema: no migrations found")
+	}
+	report := &SchemaReport{
+		ExpectedVersion: migs[len(migs)-1].Version,
+	}
+
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("verify schema: unable to begin transaction: %w", err)
+	}
+	// Nothing done by VerifySchema is kept
+	defer tx.Rollback()
+
+	var version int64
+	if err := tx.QueryRowContext(ctx, "select version, dirty from schema_migrations").Scan(&version, &report.Dirty); err != nil {
+		return nil, fmt.Errorf("verify schema: unable to read migration state: %w", err)
+	}
+	report.Version = uint(version)
+
+	var liveSchema string
+	if err := tx.QueryRowContext(ctx, "select current_schema()").Scan(&liveSchema); err != nil {
+		return nil, fmt.Errorf("verify schema: unable to read current schema: %w", err)
+	}
+	actual, err := schemaObjects(ctx, tx, liveSchema, liveSchema)
+	if err != nil {
+		return nil, fmt.Errorf("verify schema: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "create schema "+pq.QuoteIdentifier(verifySchemaName)); err != nil {
+		return nil, fmt.Errorf("verify schema: unable to create schema: %w", err)
+	}
+	// Objects from extensions installed in the live schema, such as
+	// pgcrypto, are still found by the migrations
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("set local search_path to %s, %s", pq.QuoteIdentifier(verifySchemaName), pq.QuoteIdentifier(liveSchema))); err != nil {
+		return nil, fmt.Errorf("verify schema: unable to set search path: %w", err)
+	}
+	for _, m := range migs {
+		if _, err := tx.ExecContext(ctx, transactionStatements.ReplaceAllString(m.Statements, "")); err != nil {
+			return nil, fmt.Errorf("verify schema: unable to apply migration %s: %w", m.Name, err)
+		}
+	}
+	expected, err := schemaObjects(ctx, tx, verifySchemaName, liveSchema)
+	if err != nil {
+		return nil, fmt.Errorf("verify schema: %w", err)
+	}
+	// schema_migrations is created by the tool running the migrations
+	for k, o := range actual {
+		if strings.HasPrefix(o.Name, "schema_migrations") {
+			delete(actual, k)
+		}
+	}
+
+	keys := make([]string, 0, len(expected)+len(actual))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	for k := range actual {
+		if _, ok := expected[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e, inExpected := expected[k]
+		a, inActual := actual[k]
+		switch {
+		case !inActual:
+			report.Missing = append(report.Missing, e)
+		case !inExpected:
+			report.Unexpected = append(report.Unexpected, a)
+		case e.Definition != a.Definition:
+			report.Changed = append(report.Changed, SchemaChange{
+				Type:     e.Type,
+				Name:     e.Name,
+				Expected: e.Definition,
+				Actual:   a.Definition,
+			})
+		}
+	}
+	return report, nil
+}
+
+// schemaObjects returns the objects in the schema keyed by their type and
+// name. Definitions are read with only the schema in the search path and
+// have references qualified with the schema or the live schema removed, so
+// the definitions of objects in different schemas can be compared.
+func schemaObjects(ctx context.Context, tx *sql.Tx, schema, liveSchema string) (map[string]SchemaObject, error) {
+	if _, err := tx.ExecContext(ctx, "set local search_path to "+pq.QuoteIdentifier(schema)); err != nil {
+		return nil, fmt.Errorf("unable to set search path: %w", err)
+	}
+	normalize := strings.NewReplacer(schema+".", "", liveSchema+".", "")
+	objects := make(map[string]SchemaObject)
+	for _, q := range schemaObjectQueries {
+		rows, err := tx.QueryContext(ctx, q.query, schema)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list %s objects in %s: %w", q.typ, schema, err)
+		}
+		for rows.Next() {
+			var name, def string
+			if err := rows.Scan(&name, &def); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("unable to read %s objects in %s: %w", q.typ, schema, err)
+			}
+			objects[q.typ+"/"+name] = SchemaObject{
+				Type:       q.typ,
+				Name:       name,
+				Definition: normalize.Replace(def),
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to list %s objects in %s: %w", q.typ, schema, err)
+		}
+	}
+	return objects, nil
+}
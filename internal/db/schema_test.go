@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySchema(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := TestSetup(t, "postgres")
+	d := conn.DB()
+
+	t.Run("missing-database", func(t *testing.T) {
+		_, err := VerifySchema(ctx, nil)
+		assert.Error(t, err)
+	})
+	t.Run("migrated", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		report, err := VerifySchema(ctx, d)
+		require.NoError(err)
+		assert.False(report.Drifted(), "%+v", report)
+		assert.Equal(report.ExpectedVersion, report.Version)
+
+		// Nothing is left behind
+		var count int
+		require.NoError(d.QueryRow("select count(*) from pg_namespace where nspname = $1", verifySchemaName).Scan(&count))
+		assert.Equal(0, count)
+	})
+	t.Run("drifted", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, err := d.Exec(`
+create index verify_schema_test_ix on iam_scope (description);
+drop index session_client_ip_ix;
+alter table session_state drop constraint start_and_end_times_in_sequence;
+alter table session_state add constraint start_and_end_times_in_sequence check (start_time < end_time);
+`)
+		require.NoError(err)
+
+		report, err := VerifySchema(ctx, d)
+		require.NoError(err)
+		assert.True(report.Drifted())
+		assert.Contains(report.Unexpected, SchemaObject{
+			Type:       "index",
+			Name:       "verify_schema_test_ix",
+			Definition: "CREATE INDEX verify_schema_test_ix ON iam_scope USING btree (description)",
+		})
+		var missing []string
+		for _, o := range report.Missing {
+			missing = append(missing, o.Type+"/"+o.Name)
+		}
+		assert.Contains(missing, "index/session_client_ip_ix")
+		require.Len(report.Changed, 1)
+		assert.Equal("constraint", report.Changed[0].Type)
+		assert.Equal("session_state.start_and_end_times_in_sequence", report.Changed[0].Name)
+		assert.Equal("CHECK ((start_time < end_time))", report.Changed[0].Actual)
+	})
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+const resourceScopeQuery = `
+select public_id, scope_id
+  from resource_scope
+ where public_id in (%s)
+`
+
+// CheckScope verifies that each of the resources with the publicIds is in
+// the scope. Resources such as hosts which don't have a scope of their own are
+// in the scope of their parent. If any resources don't exist or are in
+// another scope, it returns an error wrapping an *errors.Multi with an
+// ItemError wrapping ErrInvalidParameter for each of them, whose Index is the
+// resource's index in publicIds.
+//
+// Repositories should call it with the reader of the transaction making the
+// change which references the resources, so a resource can't be moved or
+// deleted between it being checked and being referenced.
+func CheckScope(ctx context.Context, r Reader, scopeId string, publicIds ...string) error {
+	if scopeId == "" {
+		return fmt.Errorf("check scope: missing scope id: %w", ErrInvalidParameter)
+	}
+	if len(publicIds) == 0 {
+		return nil
+	}
+	inClause := make([]string, 0, len(publicIds))
+	args := make([]interface{}, 0, len(publicIds))
+	for i, id := range publicIds {
+		inClause = append(inClause, fmt.Sprintf("$%d", i+1))
+		args = append(args, id)
+	}
+	rows, err := r.Query(ctx, fmt.Sprintf(resourceScopeQuery, strings.Join(inClause, ",")), args)
+	if err != nil {
+		return fmt.Errorf("check scope: %w", err)
+	}
+	defer rows.Close()
+	scopes := make(map[string]string, len(publicIds))
+	for rows.Next() {
+		var id, resourceScopeId string
+		if err := rows.Scan(&id, &resourceScopeId); err != nil {
+			return fmt.Errorf("check scope: %w", err)
+		}
+		scopes[id] = resourceScopeId
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("check scope: %w", err)
+	}
+	var multi errors.Multi
+	for i, id := range publicIds {
+		switch resourceScopeId, ok := scopes[id]; {
+		case !ok:
+			multi.Append(i, id, fmt.Errorf("not found: %w", ErrInvalidParameter))
+		case resourceScopeId != scopeId:
+			multi.Append(i, id, fmt.Errorf("not in scope %s: %w", scopeId, ErrInvalidParameter))
+		}
+	}
+	if err := multi.ErrorOrNil(); err != nil {
+		return fmt.Errorf("check scope: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckScope(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	rw := db.New(conn)
+
+	org, prj := iam.TestScopes(t, iamRepo)
+	_, otherPrj := iam.TestScopes(t, iamRepo)
+	user := iam.TestUser(t, iamRepo, org.PublicId)
+	catalog := static.TestCatalogs(t, conn, prj.PublicId, 1)[0]
+	host := static.TestHosts(t, conn, catalog.PublicId, 1)[0]
+	set := static.TestSets(t, conn, catalog.PublicId, 1)[0]
+	tgt := target.TestTcpTarget(t, conn, prj.PublicId, "test target")
+	otherCatalog := static.TestCatalogs(t, conn, otherPrj.PublicId, 1)[0]
+	otherHost := static.TestHosts(t, conn, otherCatalog.PublicId, 1)[0]
+
+	tests := []struct {
+		name      string
+		scopeId   string
+		publicIds []string
+		wantErr   bool
+	}{
+		{
+			name:      "project-resources",
+			scopeId:   prj.PublicId,
+			publicIds: []string{catalog.PublicId, host.PublicId, set.PublicId, tgt.PublicId},
+		},
+		{
+			name:      "org-resources",
+			scopeId:   org.PublicId,
+			publicIds: []string{user.PublicId},
+		},
+		{
+			name:    "no-resources",
+			scopeId: prj.PublicId,
+		},
+		{
+			name:      "other-scope",
+			scopeId:   prj.PublicId,
+			publicIds: []string{host.PublicId, otherHost.PublicId},
+			wantErr:   true,
+		},
+		{
+			name:      "parent-scope",
+			scopeId:   org.PublicId,
+			publicIds: []string{tgt.PublicId},
+			wantErr:   true,
+		},
+		{
+			name:      "not-found",
+			scopeId:   prj.PublicId,
+			publicIds: []string{host.PublicId, "hst_doesnotexist"},
+			wantErr:   true,
+		},
+		{
+			name:      "missing-scope-id",
+			publicIds: []string{host.PublicId},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			err := db.CheckScope(context.Background(), rw, tt.scopeId, tt.publicIds...)
+			if tt.wantErr {
+				assert.Error(err)
+				assert.True(errors.Is(err, db.ErrInvalidParameter))
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+
+	t.Run("failed-resources", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		err := db.CheckScope(context.Background(), rw, prj.PublicId, host.PublicId, "hst_doesnotexist", set.PublicId, otherHost.PublicId)
+		require.Error(err)
+		var multi *errors.Multi
+		require.True(errors.As(err, &multi))
+		require.Len(multi.Errors, 2)
+		assert.Equal(1, multi.Errors[0].Index)
+		assert.Equal("hst_doesnotexist", multi.Errors[0].Id)
+		assert.Equal(3, multi.Errors[1].Index)
+		assert.Equal(otherHost.PublicId, multi.Errors[1].Id)
+	})
+
+	t.Run("within-transaction", func(t *testing.T) {
+		_, err := rw.DoTx(context.Background(), db.StdRetryCnt, db.ExpBackoff{}, func(r db.Reader, _ db.Writer) error {
+			return db.CheckScope(context.Background(), r, prj.PublicId, host.PublicId)
+		})
+		require.NoError(t, err)
+	})
+}
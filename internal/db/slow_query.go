@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/event"
+)
+
+// ScrubbedValue replaces the parameters of slow queries which may hold
+// secrets.
+const ScrubbedValue = "<scrubbed>"
+
+var (
+	// insertColumnsRe matches the column and value lists of an insert.
+	insertColumnsRe = regexp.MustCompile(`(?is)insert\s+into\s+[^(]+\(([^)]*)\)\s*values\s*\(([^)]*)\)`)
+	// comparisonRe matches a column compared with or set to a parameter.
+	comparisonRe = regexp.MustCompile(`(?i)"?([a-z_][a-z0-9_]*)"?\s*(?:=|<>|!=|<=|>=|<|>|\blike\b|\bilike\b)\s*\$(\d+)`)
+	// inListRe matches a column tested against a list of values.
+	inListRe = regexp.MustCompile(`(?i)"?([a-z_][a-z0-9_]*)"?\s+in\s*\(([^)]*)\)`)
+	// parameterRe matches a parameter.
+	parameterRe = regexp.MustCompile(`^\$(\d+)$`)
+)
+
+// sensitiveColumnWords are the parts of column names whose values may be
+// secret: encrypted values, key material, credentials and tokens.
+var sensitiveColumnWords = []string{"secret", "password", "passphrase", "token", "key", "hmac", "salt", "data", "cert"}
+
+// logSlowQuery writes an observation event for a query gorm reported with
+// values if it took at least the logger's threshold. The values are "sql",
+// the source location, the duration, the query, its parameters and the
+// number of rows affected.
+func (g gormLogger) logSlowQuery(values ...interface{}) {
+	duration, ok := values[2].(time.Duration)
+	if !ok || duration < g.slowQueryThreshold {
+		return
+	}
+	query, _ := values[3].(string)
+	params, _ := values[4].([]interface{})
+	data := map[string]interface{}{
+		"location":    values[1],
+		"query":       query,
+		"parameters":  scrubQueryParameters(query, params),
+		"duration_ms": duration.Milliseconds(),
+	}
+	if rows, ok := values[5].(int64); ok {
+		data["rows_affected"] = rows
+	}
+	event.WriteObservation(context.Background(), "db.(gormLogger).logSlowQuery", data)
+}
+
+// scrubQueryParameters returns the parameters of query with ScrubbedValue in
+// place of every binary value, which includes all encrypted values, and of
+// every value bound to a column whose name contains one of
+// sensitiveColumnWords. A parameter is bound to a column when it's in the
+// column's position in an insert, or is compared with or assigned to the
+// column. Other values are returned as they are passed to the database.
+func scrubQueryParameters(query string, params []interface{}) []interface{} {
+	sensitive := make(map[int]bool)
+	bind := func(column, param string) {
+		m := parameterRe.FindStringSubmatch(strings.TrimSpace(param))
+		if m == nil || !sensitiveColumn(column) {
+			return
+		}
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			sensitive[n] = true
+		}
+	}
+	for _, m := range insertColumnsRe.FindAllStringSubmatch(query, -1) {
+		columns, values := strings.Split(m[1], ","), strings.Split(m[2], ",")
+		for i := 0; i < len(columns) && i < len(values); i++ {
+			bind(strings.Trim(strings.TrimSpace(columns[i]), `"`), values[i])
+		}
+	}
+	for _, m := range comparisonRe.FindAllStringSubmatch(query, -1) {
+		bind(m[1], "$"+m[2])
+	}
+	for _, m := range inListRe.FindAllStringSubmatch(query, -1) {
+		for _, v := range strings.Split(m[2], ",") {
+			bind(m[1], v)
+		}
+	}
+
+	scrubbed := make([]interface{}, 0, len(params))
+	for i, p := range params {
+		if v, ok := p.(driver.Valuer); ok {
+			var err error
+			if p, err = v.Value(); err != nil {
+				p = ScrubbedValue
+			}
+		}
+		switch p.(type) {
+		case []byte:
+			p = ScrubbedValue
+		}
+		// parameters are numbered from 1
+		if sensitive[i+1] {
+			p = ScrubbedValue
+		}
+		scrubbed = append(scrubbed, p)
+	}
+	return scrubbed
+}
+
+// sensitiveColumn returns true if values of the column name may be secret.
+func sensitiveColumn(name string) bool {
+	name = strings.ToLower(name)
+	if strings.HasPrefix(name, "ct_") {
+		return true
+	}
+	for _, w := range sensitiveColumnWords {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+	return false
+}
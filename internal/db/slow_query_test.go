@@ -0,0 +1,91 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_scrubQueryParameters(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name   string
+		query  string
+		params []interface{}
+		want   []interface{}
+	}{
+		{
+			name:   "insert",
+			query:  `INSERT INTO "auth_token" ("public_id","auth_account_id","token","key_id","expiration_time") VALUES ($1,$2,$3,$4,$5) RETURNING "auth_token"."create_time"`,
+			params: []interface{}{"at_1234567890", "apa_1234567890", "not-binary", "kdkv_1234567890", now},
+			want:   []interface{}{"at_1234567890", "apa_1234567890", ScrubbedValue, ScrubbedValue, now},
+		},
+		{
+			name:   "update",
+			query:  `UPDATE "auth_password_argon2_cred" SET "ct_salt" = $1, "version" = $2 WHERE (public_id = $3)`,
+			params: []interface{}{"salt", 2, "arg2cred_1234567890"},
+			want:   []interface{}{ScrubbedValue, 2, "arg2cred_1234567890"},
+		},
+		{
+			name:   "raw",
+			query:  "select * from auth_token where token = $1 and public_id in ($2, $3) and status <> $4",
+			params: []interface{}{"secret", "at_1", "at_2", "canceled"},
+			want:   []interface{}{ScrubbedValue, "at_1", "at_2", "canceled"},
+		},
+		{
+			name:   "sensitive-in-list",
+			query:  "delete from kms_oplog_key where private_id in ($1,$2)",
+			params: []interface{}{"kopk_1", "kopk_2"},
+			want:   []interface{}{"kopk_1", "kopk_2"},
+		},
+		{
+			name:   "binary",
+			query:  "select $1",
+			params: []interface{}{[]byte("ciphertext")},
+			want:   []interface{}{ScrubbedValue},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scrubQueryParameters(tt.query, tt.params))
+		})
+	}
+}
+
+func Test_gormLoggerSlowQuery(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var buf bytes.Buffer
+	eventer, err := event.NewEventer(hclog.NewNullLogger(), &event.Config{})
+	require.NoError(err)
+	eventer.AddSink(event.NewWriterSink(&buf), event.ObservationType)
+	event.InitSysEventer(eventer)
+	defer event.InitSysEventer(nil)
+
+	logger := GetGormLogger(hclog.NewNullLogger(), WithSlowQueryThreshold(100*time.Millisecond))
+	query := `UPDATE "auth_token" SET "token" = $1 WHERE (public_id = $2)`
+	logger.Print("sql", "repository.go:10", 50*time.Millisecond, query, []interface{}{"fast", "at_1"}, int64(1))
+	assert.Zero(buf.Len())
+
+	logger.Print("sql", "repository.go:10", 150*time.Millisecond, query, []interface{}{"slow", "at_1"}, int64(1))
+	var got event.Event
+	require.NoError(json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(event.ObservationType, got.Type)
+	assert.Equal(query, got.Data["query"])
+	assert.Equal([]interface{}{ScrubbedValue, "at_1"}, got.Data["parameters"])
+	assert.Equal(float64(150), got.Data["duration_ms"])
+	assert.Equal(float64(1), got.Data["rows_affected"])
+	assert.Equal("repository.go:10", got.Data["location"])
+
+	// Without a threshold, queries aren't logged
+	buf.Reset()
+	GetGormLogger(hclog.NewNullLogger()).Print("sql", "repository.go:10", time.Hour, query, []interface{}{"slow", "at_1"}, int64(1))
+	assert.Zero(buf.Len())
+}
@@ -3,8 +3,12 @@ package db
 import (
 	"context"
 	"crypto/rand"
+	"database/sql"
 	"encoding/base64"
+	"fmt"
+	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,16 +22,46 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-// setup the tests (initialize the database one-time and intialized testDatabaseURL). Do not close the returned db.
-func TestSetup(t *testing.T, dialect string) (*gorm.DB, string) {
-	cleanup, url, _, err := StartDbInDocker(dialect)
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Cleanup(func() {
-		assert.NoError(t, cleanup(), "Got error cleaning up db in docker.")
-	})
-	_, err = InitStore(dialect, cleanup, url)
+const (
+	// testTemplateName is the database the migrations are run in once per
+	// test binary, which the database of each test is cloned from.
+	testTemplateName = "boundary_template"
+
+	// testServerIdle is how long a test database server is kept running
+	// after its last test database is dropped, so that tests which run one
+	// after another share it.
+	testServerIdle = 10 * time.Second
+
+	// testServerExpiry is how long docker runs a test database server
+	// before removing it, in case the test binary exits before it's idle.
+	testServerExpiry = time.Hour
+)
+
+// testServer is a database server shared by the tests of a test binary.
+type testServer struct {
+	dialect string
+	// url is the url of the server's default database, which test
+	// databases are created and dropped through.
+	url     string
+	conn    *sql.DB
+	cleanup func() error
+
+	// dbs is the number of test databases which haven't been dropped.
+	dbs  int
+	idle *time.Timer
+}
+
+var (
+	testServersMu sync.Mutex
+	testServers   = make(map[string]*testServer)
+)
+
+// setup the tests. The database server is started and migrated once per
+// test binary, and each test gets its own database cloned from the migrated
+// template, which is dropped when the test completes. Do not close the
+// returned db.
+func TestSetup(t testing.TB, dialect string) (*gorm.DB, string) {
+	url, err := testDatabase(t, dialect)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -41,8 +75,145 @@ func TestSetup(t *testing.T, dialect string) (*gorm.DB, string) {
 	return db, url
 }
 
+// testDatabase creates a database for t, cloned from the template of the
+// dialect's test server, and returns its url. The database is dropped when t
+// completes.
+func testDatabase(t testing.TB, dialect string) (string, error) {
+	testServersMu.Lock()
+	defer testServersMu.Unlock()
+	s, ok := testServers[dialect]
+	if !ok {
+		var err error
+		if s, err = startTestServer(dialect); err != nil {
+			return "", err
+		}
+		testServers[dialect] = s
+	}
+	if s.idle != nil {
+		s.idle.Stop()
+		s.idle = nil
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("unable to generate test database name: %w", err)
+	}
+	name := fmt.Sprintf("boundary_%x", suffix)
+	// Clones are serialized by testServersMu, as postgres refuses to clone
+	// a template which another clone is reading.
+	if _, err := s.conn.Exec(fmt.Sprintf("create database %s template %s", name, testTemplateName)); err != nil {
+		return "", fmt.Errorf("unable to create test database: %w", err)
+	}
+	s.dbs++
+	t.Cleanup(func() {
+		assert.NoError(t, s.dropDatabase(name), "Got error dropping test database.")
+	})
+	return testDatabaseUrl(s.url, name)
+}
+
+// startTestServer starts a database server in docker and runs the migrations
+// in its template database.
+func startTestServer(dialect string) (*testServer, error) {
+	cleanup, url, _, err := StartTestDbInDocker(dialect, testServerExpiry)
+	if err != nil {
+		return nil, err
+	}
+	s := &testServer{
+		dialect: dialect,
+		url:     url,
+		cleanup: cleanup,
+	}
+	if err := s.initTemplate(); err != nil {
+		if s.conn != nil {
+			_ = s.conn.Close()
+		}
+		_ = cleanup()
+		return nil, fmt.Errorf("unable to create test template database: %w", err)
+	}
+	return s, nil
+}
+
+func (s *testServer) initTemplate() error {
+	var err error
+	if s.conn, err = sql.Open(s.dialect, s.url); err != nil {
+		return err
+	}
+	if _, err := s.conn.Exec(fmt.Sprintf("create database %s", testTemplateName)); err != nil {
+		return err
+	}
+	url, err := testDatabaseUrl(s.url, testTemplateName)
+	if err != nil {
+		return err
+	}
+	if _, err := InitStore(s.dialect, nil, url); err != nil {
+		return err
+	}
+	// The migrations leave their connections open, and a database can't be
+	// cloned while it has connections.
+	if err := s.disconnect(testTemplateName); err != nil {
+		return err
+	}
+	_, err = s.conn.Exec(fmt.Sprintf("alter database %s with is_template true allow_connections false", testTemplateName))
+	return err
+}
+
+// dropDatabase drops the test database name. The server is stopped once it
+// has been idle for testServerIdle.
+func (s *testServer) dropDatabase(name string) error {
+	testServersMu.Lock()
+	defer testServersMu.Unlock()
+	s.dbs--
+	if s.dbs == 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(testServerIdle, func() {
+			testServersMu.Lock()
+			defer testServersMu.Unlock()
+			if s.idle != timer {
+				return
+			}
+			delete(testServers, s.dialect)
+			_ = s.conn.Close()
+			_ = s.cleanup()
+		})
+		s.idle = timer
+	}
+
+	// Connections left open by the test would prevent dropping the database.
+	if err := s.disconnect(name); err != nil {
+		return err
+	}
+	if _, err := s.conn.Exec(fmt.Sprintf("drop database %s", name)); err != nil {
+		return fmt.Errorf("unable to drop test database: %w", err)
+	}
+	return nil
+}
+
+// disconnect terminates the connections to the database name.
+func (s *testServer) disconnect(name string) error {
+	const query = `
+select pg_terminate_backend(pid)
+  from pg_stat_activity
+ where datname = $1
+   and pid <> pg_backend_pid()
+`
+	if _, err := s.conn.Exec(query, name); err != nil {
+		return fmt.Errorf("unable to disconnect from database %s: %w", name, err)
+	}
+	return nil
+}
+
+// testDatabaseUrl returns serverUrl with its database replaced by name.
+func testDatabaseUrl(serverUrl, name string) (string, error) {
+	u, err := url.Parse(serverUrl)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse database url: %w", err)
+	}
+	u.Path = "/" + name
+	return u.String(), nil
+}
+
 // TestWrapper initializes an AEAD wrapping.Wrapper for testing the oplog
-func TestWrapper(t *testing.T) wrapping.Wrapper {
+func TestWrapper(t testing.TB) wrapping.Wrapper {
 	rootKey := make([]byte, 32)
 	n, err := rand.Read(rootKey)
 	if err != nil {
@@ -66,7 +237,7 @@ func TestWrapper(t *testing.T) wrapping.Wrapper {
 
 // AssertPublicId is a test helper that asserts that the provided id is in
 // the format of a public id.
-func AssertPublicId(t *testing.T, prefix, actual string) {
+func AssertPublicId(t testing.TB, prefix, actual string) {
 	t.Helper()
 	assert.NotEmpty(t, actual)
 	parts := strings.Split(actual, "_")
@@ -77,7 +248,7 @@ func AssertPublicId(t *testing.T, prefix, actual string) {
 // TestVerifyOplog will verify that there is an oplog entry. An error is
 // returned if the entry or it's metadata is not found.  Returning an error
 // allows clients to test if an entry was not written, which is a valid use case.
-func TestVerifyOplog(t *testing.T, r Reader, resourcePublicId string, opt ...TestOption) error {
+func TestVerifyOplog(t testing.TB, r Reader, resourcePublicId string, opt ...TestOption) error {
 	// sql where clauses
 	const (
 		whereBase = `
@@ -3,11 +3,17 @@ package docker
 import (
 	"errors"
 	"sync"
+	"time"
 )
 
 var (
 	StartDbInDocker func(string) (func() error, string, string, error) = startDbInDockerUnsupported
 
+	// StartTestDbInDocker is like StartDbInDocker, but docker removes the
+	// container after expire even if cleanup is never called, so that a
+	// database shared by the tests of a test binary doesn't outlive it.
+	StartTestDbInDocker func(string, time.Duration) (func() error, string, string, error) = startTestDbInDockerUnsupported
+
 	ErrDockerUnsupported = errors.New("docker is not currently supported on this platform")
 
 	mx = sync.Mutex{}
@@ -16,3 +22,7 @@ var (
 func startDbInDockerUnsupported(dialect string) (cleanup func() error, retURL, container string, err error) {
 	return nil, "", "", ErrDockerUnsupported
 }
+
+func startTestDbInDockerUnsupported(dialect string, expire time.Duration) (cleanup func() error, retURL, container string, err error) {
+	return nil, "", "", ErrDockerUnsupported
+}
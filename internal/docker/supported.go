@@ -6,15 +6,27 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ory/dockertest/v3"
 )
 
 func init() {
 	StartDbInDocker = startDbInDockerSupported
+	StartTestDbInDocker = startTestDbInDockerSupported
 }
 
 func startDbInDockerSupported(dialect string) (cleanup func() error, retURL, container string, err error) {
+	return startDbInDocker(dialect, 0)
+}
+
+func startTestDbInDockerSupported(dialect string, expire time.Duration) (cleanup func() error, retURL, container string, err error) {
+	return startDbInDocker(dialect, expire)
+}
+
+// startDbInDocker starts a database container. If expire is not zero, docker
+// removes the container once it has run for expire.
+func startDbInDocker(dialect string, expire time.Duration) (cleanup func() error, retURL, container string, err error) {
 	// TODO: Debug what part of this method is actually causing race condition issues with our test and fix.
 	mx.Lock()
 	defer mx.Unlock()
@@ -40,6 +52,13 @@ func startDbInDockerSupported(dialect string) (cleanup func() error, retURL, con
 		return cleanupDockerResource(pool, resource)
 	}
 
+	if expire > 0 {
+		if err := resource.Expire(uint(expire.Seconds())); err != nil {
+			_ = cleanup()
+			return func() error { return nil }, "", "", fmt.Errorf("could not set container expiry: %w", err)
+		}
+	}
+
 	url = fmt.Sprintf(url, resource.GetPort("5432/tcp"))
 
 	if err := pool.Retry(func() error {
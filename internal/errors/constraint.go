@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// postgres error code names, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	uniqueViolation     = "unique_violation"
+	checkViolation      = "check_violation"
+	notNullViolation    = "not_null_violation"
+	foreignKeyViolation = "foreign_key_violation"
+	exclusionViolation  = "exclusion_violation"
+)
+
+// Constraint describes the database constraint reported by a constraint
+// violation error.
+type Constraint struct {
+	// Table is the name of the table the violation was reported against.
+	Table string
+
+	// Name is the name of the violated constraint.
+	Name string
+}
+
+// IsUniqueError returns a boolean indicating whether the error is known to
+// report a unique constraint violation.
+func IsUniqueError(err error) bool {
+	return isPqError(err, uniqueViolation)
+}
+
+// IsCheckConstraintError returns a boolean indicating whether the error is
+// known to report a check constraint violation.
+func IsCheckConstraintError(err error) bool {
+	return isPqError(err, checkViolation)
+}
+
+// IsNotNullError returns a boolean indicating whether the error is known
+// to report a not-null constraint violation.
+func IsNotNullError(err error) bool {
+	return isPqError(err, notNullViolation)
+}
+
+// IsForeignKeyError returns a boolean indicating whether the error is known
+// to report a foreign key constraint violation. This is typically the
+// result of inserting a row that references a parent which does not exist
+// or deleting a parent which is still referenced.
+func IsForeignKeyError(err error) bool {
+	return isPqError(err, foreignKeyViolation)
+}
+
+// IsExclusionConstraintError returns a boolean indicating whether the error
+// is known to report an exclusion constraint violation.
+func IsExclusionConstraintError(err error) bool {
+	return isPqError(err, exclusionViolation)
+}
+
+// ViolatedConstraint returns the table and name of the constraint reported
+// by err. It returns false if err is not a database error or if the
+// database error does not identify a constraint.
+func ViolatedConstraint(err error) (Constraint, bool) {
+	if err == nil {
+		return Constraint{}, false
+	}
+	var pqError *pq.Error
+	if !errors.As(err, &pqError) {
+		return Constraint{}, false
+	}
+	if pqError.Constraint == "" {
+		return Constraint{}, false
+	}
+	return Constraint{
+		Table: pqError.Table,
+		Name:  pqError.Constraint,
+	}, true
+}
+
+func isPqError(err error, codeName string) bool {
+	if err == nil {
+		return false
+	}
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		if pqError.Code.Name() == codeName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_IsForeignKeyError(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   error
+		want bool
+	}{
+		{
+			name: "nil-error",
+			in:   nil,
+			want: false,
+		},
+		{
+			name: "postgres-is-unique-not-foreign-key",
+			in: &pq.Error{
+				Code: pq.ErrorCode("23505"),
+			},
+			want: false,
+		},
+		{
+			name: "postgres-is-foreign-key",
+			in: &pq.Error{
+				Code: pq.ErrorCode("23503"),
+			},
+			want: true,
+		},
+		{
+			name: "wrapped-postgres-is-foreign-key",
+			in: fmt.Errorf("wrapped: %w", &pq.Error{
+				Code: pq.ErrorCode("23503"),
+			}),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			err := tt.in
+			got := IsForeignKeyError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestError_IsExclusionConstraintError(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   error
+		want bool
+	}{
+		{
+			name: "nil-error",
+			in:   nil,
+			want: false,
+		},
+		{
+			name: "postgres-is-check-constraint-not-exclusion",
+			in: &pq.Error{
+				Code: pq.ErrorCode("23514"),
+			},
+			want: false,
+		},
+		{
+			name: "postgres-is-exclusion",
+			in: &pq.Error{
+				Code: pq.ErrorCode("23P01"),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			err := tt.in
+			got := IsExclusionConstraintError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestError_ViolatedConstraint(t *testing.T) {
+	var tests = []struct {
+		name   string
+		in     error
+		want   Constraint
+		wantOk bool
+	}{
+		{
+			name:   "nil-error",
+			in:     nil,
+			wantOk: false,
+		},
+		{
+			name:   "not-a-postgres-error",
+			in:     New("not a postgres error"),
+			wantOk: false,
+		},
+		{
+			name: "postgres-without-constraint",
+			in: &pq.Error{
+				Code:  pq.ErrorCode("23502"),
+				Table: "static_host",
+			},
+			wantOk: false,
+		},
+		{
+			name: "wrapped-postgres-foreign-key",
+			in: fmt.Errorf("create: static host: %w", &pq.Error{
+				Code:       pq.ErrorCode("23503"),
+				Table:      "static_host",
+				Constraint: "static_host_catalog_fkey",
+			}),
+			want: Constraint{
+				Table: "static_host",
+				Name:  "static_host_catalog_fkey",
+			},
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got, ok := ViolatedConstraint(tt.in)
+			assert.Equal(tt.wantOk, ok)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
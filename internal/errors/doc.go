@@ -0,0 +1,8 @@
+// Package errors provides functions for classifying errors returned from
+// the database so repositories can convert low level driver errors into
+// errors that are meaningful to their callers.
+//
+// The package is named errors and callers are expected to import it in
+// place of the standard library errors package. The standard library
+// functions Is, As, Unwrap and New are re-exported for convenience.
+package errors
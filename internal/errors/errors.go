@@ -0,0 +1,31 @@
+package errors
+
+import (
+	"errors"
+)
+
+// New returns an error that formats as the given text. It is equivalent to
+// the standard library errors.New.
+func New(text string) error {
+	return errors.New(text)
+}
+
+// Is reports whether any error in err's chain matches target. It is
+// equivalent to the standard library errors.Is.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target, and if so,
+// sets target to that error value and returns true. It is equivalent to
+// the standard library errors.As.
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err, if err's
+// type contains an Unwrap method returning error. It is equivalent to the
+// standard library errors.Unwrap.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"errors"
+	"sort"
+)
+
+// Keys for metadata commonly attached to errors.
+const (
+	ResourceIdKey = "resource_id"
+	ScopeIdKey    = "scope_id"
+	RequestIdKey  = "request_id"
+)
+
+// Metadata is structured key/value information describing the context in
+// which an error occurred. Metadata is intended for operators and the
+// event system and is never included in an error's message.
+type Metadata map[string]string
+
+// metadataError wraps an error with metadata without altering the message
+// of the wrapped error.
+type metadataError struct {
+	err error
+	md  Metadata
+}
+
+// Error returns the message of the wrapped error. Metadata is intentionally
+// excluded so it is not leaked into user facing messages.
+func (e *metadataError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *metadataError) Unwrap() error {
+	return e.err
+}
+
+// WithMetadata returns an error wrapping err which carries md. The returned
+// error has the same message as err and err can still be matched with Is
+// and As. Keys with empty values are ignored. If err is nil, WithMetadata
+// returns nil.
+func WithMetadata(err error, md Metadata) error {
+	if err == nil {
+		return nil
+	}
+	cp := make(Metadata, len(md))
+	for k, v := range md {
+		if v == "" {
+			continue
+		}
+		cp[k] = v
+	}
+	if len(cp) == 0 {
+		return err
+	}
+	return &metadataError{err: err, md: cp}
+}
+
+// WithResourceId returns an error wrapping err which carries the resource
+// id and scope id as metadata.
+func WithResourceId(err error, resourceId, scopeId string) error {
+	return WithMetadata(err, Metadata{
+		ResourceIdKey: resourceId,
+		ScopeIdKey:    scopeId,
+	})
+}
+
+// MetadataFrom returns the metadata attached to any error in err's chain.
+// The metadata from every error in the chain is merged; when the same key
+// is attached more than once, the value closest to the head of the chain
+// wins. MetadataFrom returns nil if no metadata is found.
+func MetadataFrom(err error) Metadata {
+	var md Metadata
+	for ; err != nil; err = errors.Unwrap(err) {
+		e, ok := err.(*metadataError)
+		if !ok {
+			continue
+		}
+		if md == nil {
+			md = make(Metadata, len(e.md))
+		}
+		for k, v := range e.md {
+			if _, ok := md[k]; !ok {
+				md[k] = v
+			}
+		}
+	}
+	return md
+}
+
+// Pairs returns the metadata as a flattened slice of alternating keys and
+// values suitable for passing to a structured logger.
+func (md Metadata) Pairs() []interface{} {
+	if len(md) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]interface{}, 0, len(md)*2)
+	for _, k := range keys {
+		pairs = append(pairs, k, md[k])
+	}
+	return pairs
+}
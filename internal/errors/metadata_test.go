@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMetadata(t *testing.T) {
+	errTest := New("test error")
+	t.Run("nil-error", func(t *testing.T) {
+		assert.Nil(t, WithMetadata(nil, Metadata{ResourceIdKey: "s_1234567890"}))
+	})
+	t.Run("empty-metadata", func(t *testing.T) {
+		err := WithMetadata(errTest, Metadata{ResourceIdKey: ""})
+		assert.Equal(t, errTest, err)
+		assert.Nil(t, MetadataFrom(err))
+	})
+	t.Run("message-unchanged", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		err := WithResourceId(fmt.Errorf("lookup session: %w", errTest), "s_1234567890", "p_1234567890")
+		require.Error(err)
+		assert.Equal("lookup session: test error", err.Error())
+		assert.True(Is(err, errTest))
+	})
+	t.Run("survives-wrapping", func(t *testing.T) {
+		assert := assert.New(t)
+		err := WithResourceId(errTest, "s_1234567890", "p_1234567890")
+		err = fmt.Errorf("outer: %w", err)
+		err = WithMetadata(err, Metadata{RequestIdKey: "req_1234567890", ResourceIdKey: "s_outer"})
+		err = fmt.Errorf("outermost: %w", err)
+		assert.Equal(Metadata{
+			ResourceIdKey: "s_outer",
+			ScopeIdKey:    "p_1234567890",
+			RequestIdKey:  "req_1234567890",
+		}, MetadataFrom(err))
+		assert.True(Is(err, errTest))
+		assert.Equal("outermost: outer: test error", err.Error())
+	})
+	t.Run("pairs", func(t *testing.T) {
+		assert := assert.New(t)
+		md := Metadata{
+			ScopeIdKey:    "p_1234567890",
+			ResourceIdKey: "s_1234567890",
+		}
+		assert.Equal([]interface{}{ResourceIdKey, "s_1234567890", ScopeIdKey, "p_1234567890"}, md.Pairs())
+		assert.Nil(Metadata(nil).Pairs())
+	})
+}
@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ItemError is the reason one item of a batch operation failed.
+type ItemError struct {
+	// Index is the index of the item in the items passed to the operation.
+	Index int
+
+	// Id is the id of the item, if it has one.
+	Id string
+
+	Err error
+}
+
+// Error returns the message of the wrapped error, prefixed with the item's
+// id, or with its index if it doesn't have one.
+func (e *ItemError) Error() string {
+	if e.Id != "" {
+		return fmt.Sprintf("%s: %s", e.Id, e.Err)
+	}
+	return fmt.Sprintf("item %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the wrapped error.
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// Multi is returned by batch operations when some of their items failed,
+// with an ItemError for each item which did, so callers can tell which items
+// failed and why. A Multi matches a target with Is or As if any of its items'
+// errors do.
+type Multi struct {
+	Errors []*ItemError
+}
+
+// Append adds an ItemError for the item at index with id, if err isn't nil.
+func (m *Multi) Append(index int, id string, err error) {
+	if err == nil {
+		return
+	}
+	m.Errors = append(m.Errors, &ItemError{Index: index, Id: id, Err: err})
+}
+
+// ErrorOrNil returns m if any items failed and nil otherwise.
+func (m *Multi) ErrorOrNil() error {
+	if m == nil || len(m.Errors) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Ids returns the ids of the items which failed, skipping items without one.
+func (m *Multi) Ids() []string {
+	var ids []string
+	for _, e := range m.Errors {
+		if e.Id != "" {
+			ids = append(ids, e.Id)
+		}
+	}
+	return ids
+}
+
+// Error returns the messages of the items' errors.
+func (m *Multi) Error() string {
+	if len(m.Errors) == 1 {
+		return m.Errors[0].Error()
+	}
+	msgs := make([]string, 0, len(m.Errors))
+	for _, e := range m.Errors {
+		msgs = append(msgs, e.Error())
+	}
+	return fmt.Sprintf("%d items failed: %s", len(m.Errors), strings.Join(msgs, "; "))
+}
+
+// Is reports whether the error of any of the items matches target.
+func (m *Multi) Is(target error) bool {
+	for _, e := range m.Errors {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the items which matches target, and if so,
+// sets target to that error value and returns true.
+func (m *Multi) As(target interface{}) bool {
+	for _, e := range m.Errors {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
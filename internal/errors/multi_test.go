@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMulti(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		assert := assert.New(t)
+		var m *Multi
+		assert.NoError(m.ErrorOrNil())
+		m = &Multi{}
+		m.Append(0, "u_1234567890", nil)
+		assert.Empty(m.Errors)
+		assert.NoError(m.ErrorOrNil())
+	})
+
+	t.Run("one", func(t *testing.T) {
+		assert := assert.New(t)
+		m := &Multi{}
+		m.Append(2, "u_1234567890", &ResourceInUse{Resource: "user", PublicId: "u_1234567890", ReferencedBy: []string{"r_1234567890"}})
+		err := fmt.Errorf("delete users: %w", m.ErrorOrNil())
+		assert.Equal("delete users: u_1234567890: user u_1234567890 is referenced by r_1234567890: resource in use", err.Error())
+	})
+
+	t.Run("many", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		notFound := New("not found")
+		m := &Multi{}
+		m.Append(0, "", New("missing id"))
+		m.Append(3, "u_1234567890", fmt.Errorf("lookup: %w", notFound))
+		m.Append(5, "u_0987654321", &ResourceInUse{Resource: "user", PublicId: "u_0987654321", ReferencedBy: []string{"r_1234567890"}})
+		err := fmt.Errorf("delete users: %w", m.ErrorOrNil())
+		assert.Equal("delete users: 3 items failed: item 0: missing id; u_1234567890: lookup: not found; u_0987654321: user u_0987654321 is referenced by r_1234567890: resource in use", err.Error())
+		assert.Equal([]string{"u_1234567890", "u_0987654321"}, m.Ids())
+
+		assert.True(Is(err, notFound))
+		assert.True(Is(err, ErrResourceInUse))
+		assert.False(Is(err, ErrReadOnlyScope))
+
+		var multi *Multi
+		require.True(As(err, &multi))
+		require.Len(multi.Errors, 3)
+		assert.Equal(3, multi.Errors[1].Index)
+
+		var inUse *ResourceInUse
+		require.True(As(err, &inUse))
+		assert.Equal("u_0987654321", inUse.PublicId)
+
+		var item *ItemError
+		require.True(As(err, &item))
+		assert.Equal(0, item.Index)
+
+		var notUnique *NotUnique
+		assert.False(As(err, &notUnique))
+	})
+}
@@ -0,0 +1,33 @@
+package errors
+
+import "fmt"
+
+// ErrNotUnique is returned when a write would result in a unique constraint
+// violation. NotUnique errors match it with Is.
+var ErrNotUnique = New("unique constraint violation")
+
+// NotUnique reports that a resource can't be given a name because another
+// resource in the same scope already has it.
+type NotUnique struct {
+	// Resource is the type of the resource, such as "target".
+	Resource string
+
+	// Name is the name which isn't unique.
+	Name string
+
+	// ScopeId is the scope the name must be unique within.
+	ScopeId string
+
+	// ConflictingId is the id of the resource which already has the name.
+	ConflictingId string
+}
+
+// Error returns a message naming the resource which already has the name.
+func (e *NotUnique) Error() string {
+	return fmt.Sprintf("%s %s already exists in scope %s as %s: %s", e.Resource, e.Name, e.ScopeId, e.ConflictingId, ErrNotUnique)
+}
+
+// Unwrap returns ErrNotUnique.
+func (e *NotUnique) Unwrap() error {
+	return ErrNotUnique
+}
@@ -0,0 +1,25 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotUnique(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	err := fmt.Errorf("create tcp target: %w", &NotUnique{
+		Resource:      "target",
+		Name:          "web",
+		ScopeId:       "p_1234567890",
+		ConflictingId: "ttcp_1234567890",
+	})
+	assert.Equal("create tcp target: target web already exists in scope p_1234567890 as ttcp_1234567890: unique constraint violation", err.Error())
+	assert.True(Is(err, ErrNotUnique))
+
+	var nu *NotUnique
+	require.True(As(err, &nu))
+	assert.Equal("ttcp_1234567890", nu.ConflictingId)
+}
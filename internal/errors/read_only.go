@@ -0,0 +1,24 @@
+package errors
+
+import "fmt"
+
+// ErrReadOnlyScope is returned when a resource can't be written because its
+// scope is read only. ReadOnlyScope errors match it with Is.
+var ErrReadOnlyScope = New("scope is read only")
+
+// ReadOnlyScope reports that a resource can't be written because its scope,
+// or the org its scope is in, is read only.
+type ReadOnlyScope struct {
+	// ScopeId is the id of the read only scope.
+	ScopeId string
+}
+
+// Error returns a message with the id of the read only scope.
+func (e *ReadOnlyScope) Error() string {
+	return fmt.Sprintf("%s: %s", e.ScopeId, ErrReadOnlyScope)
+}
+
+// Unwrap returns ErrReadOnlyScope.
+func (e *ReadOnlyScope) Unwrap() error {
+	return ErrReadOnlyScope
+}
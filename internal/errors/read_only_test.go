@@ -0,0 +1,20 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyScope(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	err := fmt.Errorf("create: vet for write failed: %w", &ReadOnlyScope{ScopeId: "o_1234567890"})
+	assert.Equal("create: vet for write failed: o_1234567890: scope is read only", err.Error())
+	assert.True(Is(err, ErrReadOnlyScope))
+
+	var readOnly *ReadOnlyScope
+	require.True(As(err, &readOnly))
+	assert.Equal("o_1234567890", readOnly.ScopeId)
+}
@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrResourceInUse is returned when a resource can't be deleted because
+// other resources reference it. ResourceInUse errors match it with Is.
+var ErrResourceInUse = New("resource in use")
+
+// ResourceInUse reports that a resource can't be deleted because other
+// resources still reference it.
+type ResourceInUse struct {
+	// Resource is the type of the resource, such as "user".
+	Resource string
+
+	// PublicId is the id of the resource which is in use.
+	PublicId string
+
+	// ReferencedBy are the ids of the resources which reference it.
+	ReferencedBy []string
+}
+
+// Error returns a message listing the resources which reference the
+// resource.
+func (e *ResourceInUse) Error() string {
+	return fmt.Sprintf("%s %s is referenced by %s: %s", e.Resource, e.PublicId, strings.Join(e.ReferencedBy, ", "), ErrResourceInUse)
+}
+
+// Unwrap returns ErrResourceInUse.
+func (e *ResourceInUse) Unwrap() error {
+	return ErrResourceInUse
+}
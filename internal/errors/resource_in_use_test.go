@@ -0,0 +1,24 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceInUse(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	err := fmt.Errorf("delete user: %w", &ResourceInUse{
+		Resource:     "user",
+		PublicId:     "u_1234567890",
+		ReferencedBy: []string{"r_1234567890", "s_1234567890"},
+	})
+	assert.Equal("delete user: user u_1234567890 is referenced by r_1234567890, s_1234567890: resource in use", err.Error())
+	assert.True(Is(err, ErrResourceInUse))
+
+	var inUse *ResourceInUse
+	require.True(As(err, &inUse))
+	assert.Equal([]string{"r_1234567890", "s_1234567890"}, inUse.ReferencedBy)
+}
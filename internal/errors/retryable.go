@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// postgres error code names for transient errors, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	serializationFailure = "serialization_failure"
+	deadlockDetected     = "deadlock_detected"
+	tooManyConnections   = "too_many_connections"
+	cannotConnectNow     = "cannot_connect_now"
+)
+
+// IsRetryable returns a boolean indicating whether the error is known to
+// be transient, meaning the operation that produced it may succeed if it is
+// attempted again. Postgres serialization failures, detected deadlocks,
+// connections refused because there are too many or because the server is
+// starting up, connections which were reset or closed unexpectedly, and gRPC
+// errors with a status code of Unavailable are all considered retryable.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case isPqError(err, serializationFailure),
+		isPqError(err, deadlockDetected),
+		isPqError(err, tooManyConnections),
+		isPqError(err, cannotConnectNow):
+		return true
+	case errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		if grpcErr.GRPCStatus().Code() == codes.Unavailable {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTxConflict returns a boolean indicating whether the error is a Postgres
+// serialization failure or detected deadlock. The transaction which produced
+// it was rolled back, so unlike other retryable errors it is safe to retry
+// even when it is returned by a commit.
+func IsTxConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	return isPqError(err, serializationFailure) || isPqError(err, deadlockDetected)
+}
@@ -0,0 +1,153 @@
+package errors
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestError_IsRetryable(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   error
+		want bool
+	}{
+		{
+			name: "nil-error",
+			in:   nil,
+			want: false,
+		},
+		{
+			name: "unknown-error",
+			in:   New("unknown error"),
+			want: false,
+		},
+		{
+			name: "postgres-unique-not-retryable",
+			in: &pq.Error{
+				Code: pq.ErrorCode("23505"),
+			},
+			want: false,
+		},
+		{
+			name: "postgres-serialization-failure",
+			in: &pq.Error{
+				Code: pq.ErrorCode("40001"),
+			},
+			want: true,
+		},
+		{
+			name: "postgres-deadlock-detected",
+			in: fmt.Errorf("wrapped: %w", &pq.Error{
+				Code: pq.ErrorCode("40P01"),
+			}),
+			want: true,
+		},
+		{
+			name: "postgres-too-many-connections",
+			in: &pq.Error{
+				Code: pq.ErrorCode("53300"),
+			},
+			want: true,
+		},
+		{
+			name: "bad-conn",
+			in:   fmt.Errorf("wrapped: %w", driver.ErrBadConn),
+			want: true,
+		},
+		{
+			name: "connection-reset",
+			in: &net.OpError{
+				Op:  "read",
+				Net: "tcp",
+				Err: os.NewSyscallError("read", syscall.ECONNRESET),
+			},
+			want: true,
+		},
+		{
+			name: "grpc-unavailable",
+			in:   status.Error(codes.Unavailable, "connection closed"),
+			want: true,
+		},
+		{
+			name: "grpc-internal-not-retryable",
+			in:   status.Error(codes.Internal, "internal"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got := IsRetryable(tt.in)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestError_IsTxConflict(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   error
+		want bool
+	}{
+		{
+			name: "nil-error",
+			in:   nil,
+			want: false,
+		},
+		{
+			name: "postgres-serialization-failure",
+			in: &pq.Error{
+				Code: pq.ErrorCode("40001"),
+			},
+			want: true,
+		},
+		{
+			name: "postgres-deadlock-detected",
+			in: fmt.Errorf("wrapped: %w", &pq.Error{
+				Code: pq.ErrorCode("40P01"),
+			}),
+			want: true,
+		},
+		{
+			name: "postgres-too-many-connections",
+			in: &pq.Error{
+				Code: pq.ErrorCode("53300"),
+			},
+			want: false,
+		},
+		{
+			name: "bad-conn",
+			in:   fmt.Errorf("wrapped: %w", driver.ErrBadConn),
+			want: false,
+		},
+		{
+			name: "broken-pipe",
+			in:   os.NewSyscallError("write", syscall.EPIPE),
+			want: false,
+		},
+		{
+			name: "unexpected-eof",
+			in:   fmt.Errorf("wrapped: %w", io.ErrUnexpectedEOF),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got := IsTxConflict(tt.in)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SinkType is the type of destination a sink writes events to.
+type SinkType string
+
+const (
+	StderrSink  SinkType = "stderr"
+	FileSink    SinkType = "file"
+	SyslogSink  SinkType = "syslog"
+	WebhookSink SinkType = "webhook"
+)
+
+const (
+	defaultWebhookTimeout = 5 * time.Second
+	defaultSyslogTag      = "boundary"
+)
+
+// Config is the configuration of the events emitted by a controller or
+// worker, as given in the "events" block of the configuration file.
+type Config struct {
+	Sinks []*SinkConfig `hcl:"sink"`
+}
+
+// SinkConfig configures a single sink.
+type SinkConfig struct {
+	// Type is the type of the sink.
+	Type SinkType `hcl:"type"`
+
+	// EventTypes restricts the sink to events of the listed types. If empty,
+	// the sink receives all events.
+	EventTypes []string `hcl:"event_types"`
+
+	// FilePath is the path of the file written by a file sink.
+	FilePath string `hcl:"file_path"`
+
+	// RotateBytes is the size at which a file sink's file is rotated. If
+	// zero, the file is not rotated by size.
+	RotateBytes int64 `hcl:"rotate_bytes"`
+
+	// RotateDuration is the age at which a file sink's file is rotated, for
+	// example "24h". If empty, the file is not rotated by age.
+	RotateDuration string `hcl:"rotate_duration"`
+
+	// RotateMaxFiles is the number of rotated files a file sink keeps. If
+	// zero, all rotated files are kept.
+	RotateMaxFiles int `hcl:"rotate_max_files"`
+
+	// SyslogFacility is the facility of a syslog sink's messages, for
+	// example "LOCAL0". Defaults to "USER".
+	SyslogFacility string `hcl:"syslog_facility"`
+
+	// SyslogTag is the tag of a syslog sink's messages. Defaults to
+	// "boundary".
+	SyslogTag string `hcl:"syslog_tag"`
+
+	// WebhookUrl is the URL a webhook sink POSTs each event to.
+	WebhookUrl string `hcl:"webhook_url"`
+
+	// WebhookHeaders are extra headers sent with each webhook request.
+	WebhookHeaders map[string]string `hcl:"webhook_headers"`
+
+	// WebhookTimeout is how long a webhook sink waits for each request, for
+	// example "10s". Defaults to 5 seconds.
+	WebhookTimeout string `hcl:"webhook_timeout"`
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for i, s := range c.Sinks {
+		if s == nil {
+			continue
+		}
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("events sink %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *SinkConfig) validate() error {
+	for _, t := range s.EventTypes {
+		if !Type(t).Valid() {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	switch s.Type {
+	case StderrSink:
+	case FileSink:
+		if s.FilePath == "" {
+			return errors.New("file sink requires file_path")
+		}
+		if s.RotateBytes < 0 {
+			return errors.New("rotate_bytes must not be negative")
+		}
+		if s.RotateMaxFiles < 0 {
+			return errors.New("rotate_max_files must not be negative")
+		}
+		if _, err := s.rotateDuration(); err != nil {
+			return err
+		}
+	case SyslogSink:
+	case WebhookSink:
+		if s.WebhookUrl == "" {
+			return errors.New("webhook sink requires webhook_url")
+		}
+		if _, err := s.webhookTimeout(); err != nil {
+			return err
+		}
+	case "":
+		return errors.New("missing sink type")
+	default:
+		return fmt.Errorf("unknown sink type %q", s.Type)
+	}
+	return nil
+}
+
+func (s *SinkConfig) rotateDuration() (time.Duration, error) {
+	if s.RotateDuration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s.RotateDuration)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid rotate_duration %q", s.RotateDuration)
+	}
+	return d, nil
+}
+
+func (s *SinkConfig) webhookTimeout() (time.Duration, error) {
+	if s.WebhookTimeout == "" {
+		return defaultWebhookTimeout, nil
+	}
+	d, err := time.ParseDuration(s.WebhookTimeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid webhook_timeout %q", s.WebhookTimeout)
+	}
+	return d, nil
+}
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sink    *SinkConfig
+		wantErr string
+	}{
+		{
+			name: "stderr",
+			sink: &SinkConfig{Type: StderrSink, EventTypes: []string{"audit", "system"}},
+		},
+		{
+			name:    "missing-type",
+			sink:    &SinkConfig{},
+			wantErr: "missing sink type",
+		},
+		{
+			name:    "unknown-type",
+			sink:    &SinkConfig{Type: "kafka"},
+			wantErr: `unknown sink type "kafka"`,
+		},
+		{
+			name:    "unknown-event-type",
+			sink:    &SinkConfig{Type: StderrSink, EventTypes: []string{"debug"}},
+			wantErr: `unknown event type "debug"`,
+		},
+		{
+			name: "file",
+			sink: &SinkConfig{Type: FileSink, FilePath: "/var/log/boundary/events.log", RotateBytes: 1024, RotateDuration: "24h", RotateMaxFiles: 3},
+		},
+		{
+			name:    "file-missing-path",
+			sink:    &SinkConfig{Type: FileSink},
+			wantErr: "file sink requires file_path",
+		},
+		{
+			name:    "file-bad-duration",
+			sink:    &SinkConfig{Type: FileSink, FilePath: "events.log", RotateDuration: "daily"},
+			wantErr: `invalid rotate_duration "daily"`,
+		},
+		{
+			name: "webhook",
+			sink: &SinkConfig{Type: WebhookSink, WebhookUrl: "https://example.com/events", WebhookTimeout: "1s"},
+		},
+		{
+			name:    "webhook-missing-url",
+			sink:    &SinkConfig{Type: WebhookSink},
+			wantErr: "webhook sink requires webhook_url",
+		},
+		{
+			name:    "webhook-bad-timeout",
+			sink:    &SinkConfig{Type: WebhookSink, WebhookUrl: "https://example.com/events", WebhookTimeout: "0s"},
+			wantErr: `invalid webhook_timeout "0s"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Config{Sinks: []*SinkConfig{tt.sink}}).Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, "events sink 0: "+tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+// Package event emits structured events describing what Boundary is doing to
+// configurable sinks.
+//
+// Events have one of four types: audit events record operations performed
+// on behalf of users, system events record significant changes in the
+// state of a controller or worker, observation events record
+// measurements of the work being done, and security events record failed
+// authentications, invalid auth tokens, denied authorizations and workers
+// refusing to dial addresses sessions aren't authorized for, so they can be
+// sent to a dedicated sink for alerting. Events are serialized as JSON.
+//
+// An Eventer writes events to its sinks, each of which can be restricted to
+// a subset of the event types. The supported sinks write to stderr, to a file
+// which is rotated by size or age, to syslog, or to a webhook.
+//
+// Code emitting events uses WriteAudit, WriteSystem, WriteObservation and
+// WriteSecurity, which write to the Eventer in the context or, if there is
+// none, to the system Eventer set with InitSysEventer. If neither is set,
+// events are discarded.
+package event
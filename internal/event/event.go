@@ -0,0 +1,119 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/helper/base62"
+)
+
+// Type is the type of an event.
+type Type string
+
+const (
+	// AuditType is the type of events recording operations performed on
+	// behalf of users.
+	AuditType Type = "audit"
+
+	// SystemType is the type of events recording significant changes in the
+	// state of a controller or worker.
+	SystemType Type = "system"
+
+	// ObservationType is the type of events recording measurements of the
+	// work being done.
+	ObservationType Type = "observation"
+
+	// SecurityType is the type of events recording failed authentications,
+	// invalid auth tokens, denied authorizations, sign ins from addresses
+	// outside a scope's sign in cidrs and egress violations.
+	SecurityType Type = "security"
+)
+
+// The kinds of security events, given by their "kind" data field.
+const (
+	AuthenticationFailedKind = "authentication_failed"
+	InvalidAuthTokenKind     = "invalid_auth_token"
+	AuthorizationDeniedKind  = "authorization_denied"
+	EgressViolationKind      = "egress_violation"
+	SignInRestrictedKind     = "sign_in_restricted"
+)
+
+// Valid returns true if t is a known event type.
+func (t Type) Valid() bool {
+	switch t {
+	case AuditType, SystemType, ObservationType, SecurityType:
+		return true
+	}
+	return false
+}
+
+// Event is a single structured event. Request is set for events written while
+// handling an API request.
+type Event struct {
+	Id        string                 `json:"id"`
+	Type      Type                   `json:"type"`
+	CreatedAt time.Time              `json:"created_at"`
+	Op        string                 `json:"op"`
+	Request   *RequestInfo           `json:"request,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// newEvent returns a new event of type t for the operation op.
+func newEvent(t Type, op string, data map[string]interface{}) (*Event, error) {
+	id, err := base62.Random(10)
+	if err != nil {
+		return nil, fmt.Errorf("error generating event id: %w", err)
+	}
+	return &Event{
+		Id:        "e_" + id,
+		Type:      t,
+		CreatedAt: time.Now(),
+		Op:        op,
+		Data:      data,
+	}, nil
+}
+
+// WriteAudit emits an audit event for the operation op. Unlike other events,
+// the error writing an audit event is returned so callers can refuse to
+// perform operations which can't be audited.
+func WriteAudit(ctx context.Context, op string, data map[string]interface{}) error {
+	return write(ctx, AuditType, op, data)
+}
+
+// WriteSystem emits a system event for the operation op. Failures to write
+// the event are logged by the Eventer.
+func WriteSystem(ctx context.Context, op string, data map[string]interface{}) {
+	_ = write(ctx, SystemType, op, data)
+}
+
+// WriteObservation emits an observation event for the operation op. Failures
+// to write the event are logged by the Eventer.
+func WriteObservation(ctx context.Context, op string, data map[string]interface{}) {
+	_ = write(ctx, ObservationType, op, data)
+}
+
+// WriteSecurity emits a security event for the operation op. Its data must
+// include its "kind". Failures to write the event are logged by the Eventer.
+func WriteSecurity(ctx context.Context, op string, data map[string]interface{}) {
+	_ = write(ctx, SecurityType, op, data)
+}
+
+func write(ctx context.Context, t Type, op string, data map[string]interface{}) error {
+	e, ok := EventerFromContext(ctx)
+	if !ok {
+		e = SysEventer()
+	}
+	if e == nil {
+		return nil
+	}
+	ev, err := newEvent(t, op, data)
+	if err != nil {
+		return err
+	}
+	if info, ok := RequestInfoFromContext(ctx); ok {
+		req := *info
+		ev.Request = &req
+	}
+	return e.Emit(ctx, ev)
+}
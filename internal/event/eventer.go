@@ -0,0 +1,143 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+)
+
+type eventerContextKey struct{}
+
+var (
+	sysEventerMu sync.RWMutex
+	sysEventer   *Eventer
+)
+
+// InitSysEventer sets the Eventer used when there is none in the context. It
+// may be set to nil to discard those events.
+func InitSysEventer(e *Eventer) {
+	sysEventerMu.Lock()
+	defer sysEventerMu.Unlock()
+	sysEventer = e
+}
+
+// SysEventer returns the Eventer set with InitSysEventer, or nil if none has
+// been set.
+func SysEventer() *Eventer {
+	sysEventerMu.RLock()
+	defer sysEventerMu.RUnlock()
+	return sysEventer
+}
+
+// NewEventerContext returns a context carrying e.
+func NewEventerContext(ctx context.Context, e *Eventer) context.Context {
+	return context.WithValue(ctx, eventerContextKey{}, e)
+}
+
+// EventerFromContext returns the Eventer carried by ctx, if any.
+func EventerFromContext(ctx context.Context) (*Eventer, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	e, ok := ctx.Value(eventerContextKey{}).(*Eventer)
+	return e, ok && e != nil
+}
+
+// Eventer writes events to its sinks.
+type Eventer struct {
+	logger hclog.Logger
+	sinks  []*filteredSink
+}
+
+// filteredSink is a sink which only receives events of the given types. If
+// types is empty, it receives all events.
+type filteredSink struct {
+	Sink
+	name  string
+	types map[Type]bool
+}
+
+func (s *filteredSink) accepts(t Type) bool {
+	return len(s.types) == 0 || s.types[t]
+}
+
+// NewEventer creates an Eventer writing to the sinks described by c. Errors
+// writing to sinks are logged to logger. A nil c creates an Eventer without
+// sinks.
+func NewEventer(logger hclog.Logger, c *Config) (*Eventer, error) {
+	if logger == nil {
+		return nil, errors.New("new eventer: missing logger")
+	}
+	e := &Eventer{
+		logger: logger,
+	}
+	if c == nil {
+		return e, nil
+	}
+	for i, sc := range c.Sinks {
+		if sc == nil {
+			continue
+		}
+		name := fmt.Sprintf("%s-%d", sc.Type, i)
+		s, err := newSink(sc)
+		if err != nil {
+			e.Close()
+			return nil, fmt.Errorf("new eventer: sink %s: %w", name, err)
+		}
+		types := make(map[Type]bool, len(sc.EventTypes))
+		for _, t := range sc.EventTypes {
+			types[Type(t)] = true
+		}
+		e.sinks = append(e.sinks, &filteredSink{Sink: s, name: name, types: types})
+	}
+	return e, nil
+}
+
+// AddSink adds s to the Eventer's sinks, receiving events of the given types
+// or all events if none are given. It must not be called once the Eventer is
+// in use.
+func (e *Eventer) AddSink(s Sink, types ...Type) {
+	fs := &filteredSink{
+		Sink:  s,
+		name:  fmt.Sprintf("sink-%d", len(e.sinks)),
+		types: make(map[Type]bool, len(types)),
+	}
+	for _, t := range types {
+		fs.types[t] = true
+	}
+	e.sinks = append(e.sinks, fs)
+}
+
+// Emit writes ev to each sink accepting its type. Failures are logged, and
+// an error combining them is returned.
+func (e *Eventer) Emit(ctx context.Context, ev *Event) error {
+	if ev == nil {
+		return errors.New("emit: missing event")
+	}
+	var result *multierror.Error
+	for _, s := range e.sinks {
+		if !s.accepts(ev.Type) {
+			continue
+		}
+		if err := s.Write(ctx, ev); err != nil {
+			e.logger.Error("error writing event", "sink", s.name, "event_id", ev.Id, "error", err)
+			result = multierror.Append(result, fmt.Errorf("sink %s: %w", s.name, err))
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+// Close closes all of the Eventer's sinks.
+func (e *Eventer) Close() error {
+	var result *multierror.Error
+	for _, s := range e.sinks {
+		if err := s.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("sink %s: %w", s.name, err))
+		}
+	}
+	return result.ErrorOrNil()
+}
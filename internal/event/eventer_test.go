@@ -0,0 +1,126 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type errSink struct{}
+
+func (errSink) Write(context.Context, *Event) error { return errors.New("write failed") }
+func (errSink) Close() error                        { return nil }
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []*Event {
+	t.Helper()
+	var events []*Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		ev := new(Event)
+		require.NoError(t, json.Unmarshal([]byte(line), ev))
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestEventer_Emit(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var all, audit bytes.Buffer
+	e, err := NewEventer(hclog.NewNullLogger(), nil)
+	require.NoError(err)
+	e.sinks = []*filteredSink{
+		{Sink: NewWriterSink(&all), name: "all"},
+		{Sink: NewWriterSink(&audit), name: "audit", types: map[Type]bool{AuditType: true}},
+	}
+	ctx := NewEventerContext(context.Background(), e)
+
+	require.NoError(WriteAudit(ctx, "op.audit", map[string]interface{}{"k": "v"}))
+	WriteSystem(ctx, "op.system", nil)
+	WriteObservation(ctx, "op.observation", nil)
+	WriteSecurity(ctx, "op.security", map[string]interface{}{"kind": AuthorizationDeniedKind})
+
+	got := decodeEvents(t, &all)
+	require.Len(got, 4)
+	assert.Equal(AuditType, got[0].Type)
+	assert.Equal("op.audit", got[0].Op)
+	assert.Equal("v", got[0].Data["k"])
+	assert.True(strings.HasPrefix(got[0].Id, "e_"))
+	assert.Equal(SystemType, got[1].Type)
+	assert.Equal(ObservationType, got[2].Type)
+	assert.Equal(SecurityType, got[3].Type)
+	assert.Equal(AuthorizationDeniedKind, got[3].Data["kind"])
+
+	got = decodeEvents(t, &audit)
+	require.Len(got, 1)
+	assert.Equal("op.audit", got[0].Op)
+
+	e.sinks = append(e.sinks, &filteredSink{Sink: errSink{}, name: "err"})
+	assert.Error(WriteAudit(ctx, "op.audit", nil))
+}
+
+func TestRequestInfo(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var buf bytes.Buffer
+	e, err := NewEventer(hclog.NewNullLogger(), nil)
+	require.NoError(err)
+	e.sinks = []*filteredSink{{Sink: NewWriterSink(&buf), name: "buf"}}
+	ctx := NewEventerContext(context.Background(), e)
+
+	_, ok := RequestInfoFromContext(ctx)
+	assert.False(ok)
+	WriteSystem(ctx, "op.none", nil)
+
+	info, err := NewRequestInfo()
+	require.NoError(err)
+	assert.True(strings.HasPrefix(info.Id, RequestIdPrefix))
+	ctx = NewRequestInfoContext(ctx, info)
+	got, ok := RequestInfoFromContext(ctx)
+	require.True(ok)
+	assert.Same(info, got)
+
+	// The actor is set once the request is authenticated
+	info.UserId, info.AuthTokenId = "u_1234567890", "at_1234567890"
+	WriteSystem(ctx, "op.request", nil)
+
+	events := decodeEvents(t, &buf)
+	require.Len(events, 2)
+	assert.Nil(events[0].Request)
+	assert.Equal(&RequestInfo{Id: info.Id, UserId: "u_1234567890", AuthTokenId: "at_1234567890"}, events[1].Request)
+}
+
+func TestSysEventer(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	// Without any eventer events are discarded
+	InitSysEventer(nil)
+	assert.NoError(WriteAudit(context.Background(), "op", nil))
+
+	var sys, fromCtx bytes.Buffer
+	e, err := NewEventer(hclog.NewNullLogger(), nil)
+	require.NoError(err)
+	e.sinks = []*filteredSink{{Sink: NewWriterSink(&sys), name: "sys"}}
+	InitSysEventer(e)
+	defer InitSysEventer(nil)
+
+	ctxEventer, err := NewEventer(hclog.NewNullLogger(), nil)
+	require.NoError(err)
+	ctxEventer.sinks = []*filteredSink{{Sink: NewWriterSink(&fromCtx), name: "ctx"}}
+
+	WriteSystem(context.Background(), "op.sys", nil)
+	WriteSystem(NewEventerContext(context.Background(), ctxEventer), "op.ctx", nil)
+
+	got := decodeEvents(t, &sys)
+	require.Len(got, 1)
+	assert.Equal("op.sys", got[0].Op)
+	got = decodeEvents(t, &fromCtx)
+	require.Len(got, 1)
+	assert.Equal("op.ctx", got[0].Op)
+}
@@ -0,0 +1,51 @@
+package event
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/vault/sdk/helper/base62"
+)
+
+// RequestIdPrefix is the prefix of the ids assigned to API requests.
+const RequestIdPrefix = "req_"
+
+type requestInfoContextKey struct{}
+
+// RequestInfo identifies the API request, and the actor making it, for which
+// an operation is performed. It is carried in the request's context so that
+// the events and oplog entries written while handling the request can be
+// correlated. ClientIp and UserAgent describe the client which made the
+// request.
+type RequestInfo struct {
+	Id          string `json:"id"`
+	UserId      string `json:"user_id,omitempty"`
+	AuthTokenId string `json:"auth_token_id,omitempty"`
+	ClientIp    string `json:"client_ip,omitempty"`
+	UserAgent   string `json:"user_agent,omitempty"`
+}
+
+// NewRequestInfo returns a RequestInfo with a new request id.
+func NewRequestInfo() (*RequestInfo, error) {
+	id, err := base62.Random(10)
+	if err != nil {
+		return nil, fmt.Errorf("error generating request id: %w", err)
+	}
+	return &RequestInfo{Id: RequestIdPrefix + id}, nil
+}
+
+// NewRequestInfoContext returns a context carrying info. The actor is set on
+// info once the request has been authenticated, so info must not be shared
+// between requests.
+func NewRequestInfoContext(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoContextKey{}, info)
+}
+
+// RequestInfoFromContext returns the RequestInfo carried by ctx.
+func RequestInfoFromContext(ctx context.Context) (*RequestInfo, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	info, ok := ctx.Value(requestInfoContextKey{}).(*RequestInfo)
+	return info, ok && info != nil
+}
@@ -0,0 +1,75 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink is a destination for events.
+type Sink interface {
+	// Write writes ev to the sink.
+	Write(ctx context.Context, ev *Event) error
+
+	// Close releases the sink's resources.
+	Close() error
+}
+
+// newSink creates the sink described by c.
+func newSink(c *SinkConfig) (Sink, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	switch c.Type {
+	case StderrSink:
+		return NewWriterSink(os.Stderr), nil
+	case FileSink:
+		d, _ := c.rotateDuration()
+		return NewFileSink(c.FilePath, c.RotateBytes, d, c.RotateMaxFiles)
+	case SyslogSink:
+		return NewSyslogSink(c.SyslogFacility, c.SyslogTag)
+	case WebhookSink:
+		timeout, _ := c.webhookTimeout()
+		return NewWebhookSink(c.WebhookUrl, c.WebhookHeaders, timeout)
+	}
+	return nil, fmt.Errorf("unknown sink type %q", c.Type)
+}
+
+// writerSink writes events as JSON lines to an io.Writer.
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink returns a sink writing events as JSON lines to w. The sink
+// does not close w.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Write(_ context.Context, ev *Event) error {
+	b, err := marshalLine(ev)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(b)
+	return err
+}
+
+func (s *writerSink) Close() error {
+	return nil
+}
+
+// marshalLine returns ev as JSON followed by a newline.
+func marshalLine(ev *Event) ([]byte, error) {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding event: %w", err)
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,194 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileSink writes events as JSON lines to a file, rotating it when it
+// reaches a size or age. Rotated files are renamed with the time of rotation
+// inserted before the file's extension.
+type fileSink struct {
+	mu sync.Mutex
+
+	path        string
+	rotateBytes int64
+	rotateAge   time.Duration
+	maxFiles    int
+
+	f        *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+}
+
+// NewFileSink returns a sink writing events to the file at path. The file is
+// rotated once writing an event would make it larger than rotateBytes, or
+// once it is older than rotateAge; a zero value disables that kind of
+// rotation. At most maxFiles rotated files are kept, unless maxFiles is zero
+// in which case all are kept.
+func NewFileSink(path string, rotateBytes int64, rotateAge time.Duration, maxFiles int) (Sink, error) {
+	s := &fileSink{
+		path:        path,
+		rotateBytes: rotateBytes,
+		rotateAge:   rotateAge,
+		maxFiles:    maxFiles,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("error creating event file directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening event file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("error opening event file: %w", err)
+	}
+	s.f = f
+	s.size = info.Size()
+	s.openedAt = time.Now()
+	return nil
+}
+
+func (s *fileSink) Write(_ context.Context, ev *Event) error {
+	b, err := marshalLine(ev)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("event file %s is closed", s.path)
+	}
+	if s.f == nil {
+		// A previous rotation failed to reopen the file
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	var rotateErr error
+	if s.needsRotation(int64(len(b))) {
+		// If rotation fails the event is still written if the file could be
+		// reopened, and the error is returned after writing it.
+		if rotateErr = s.rotate(); s.f == nil {
+			return rotateErr
+		}
+	}
+	n, err := s.f.Write(b)
+	s.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return rotateErr
+}
+
+func (s *fileSink) needsRotation(n int64) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.rotateBytes > 0 && s.size+n > s.rotateBytes {
+		return true
+	}
+	return s.rotateAge > 0 && time.Since(s.openedAt) >= s.rotateAge
+}
+
+// rotate renames the file with the time of rotation and opens a new file at
+// the original path. If the file can't be renamed, the original file is
+// reopened and the error returned.
+func (s *fileSink) rotate() error {
+	closeErr := s.f.Close()
+	s.f = nil
+	if closeErr != nil {
+		return s.reopen(fmt.Errorf("error closing event file for rotation: %w", closeErr))
+	}
+	ext := filepath.Ext(s.path)
+	base := strings.TrimSuffix(s.path, ext)
+	rotated := fmt.Sprintf("%s-%d%s", base, time.Now().UnixNano(), ext)
+	if err := os.Rename(s.path, rotated); err != nil {
+		return s.reopen(fmt.Errorf("error rotating event file: %w", err))
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	return s.prune(base, ext)
+}
+
+// reopen opens the file at the original path after a failed rotation and
+// returns err, along with any error reopening the file.
+func (s *fileSink) reopen(err error) error {
+	if openErr := s.open(); openErr != nil {
+		return fmt.Errorf("%v; %w", err, openErr)
+	}
+	return err
+}
+
+// prune removes the oldest rotated files beyond maxFiles. Only files named
+// as rotate names them are considered, so other files which share the
+// prefix of the file's name are left alone.
+func (s *fileSink) prune(base, ext string) error {
+	if s.maxFiles <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(base + "-*" + ext)
+	if err != nil {
+		return fmt.Errorf("error listing rotated event files: %w", err)
+	}
+	rotatedName := regexp.MustCompile("^" + regexp.QuoteMeta(filepath.Base(base)) + "-([0-9]+)" + regexp.QuoteMeta(ext) + "$")
+	type rotatedFile struct {
+		path      string
+		rotatedAt int64
+	}
+	var files []rotatedFile
+	for _, m := range matches {
+		sub := rotatedName.FindStringSubmatch(filepath.Base(m))
+		if sub == nil {
+			continue
+		}
+		rotatedAt, err := strconv.ParseInt(sub[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, rotatedFile{path: m, rotatedAt: rotatedAt})
+	}
+	if len(files) <= s.maxFiles {
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].rotatedAt < files[j].rotatedAt
+	})
+	for _, f := range files[:len(files)-s.maxFiles] {
+		if err := os.Remove(f.path); err != nil {
+			return fmt.Errorf("error removing rotated event file: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
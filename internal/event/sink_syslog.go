@@ -0,0 +1,71 @@
+// +build !windows,!plan9
+
+package event
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+	"strings"
+)
+
+var syslogFacilities = map[string]syslog.Priority{
+	"KERN":     syslog.LOG_KERN,
+	"USER":     syslog.LOG_USER,
+	"MAIL":     syslog.LOG_MAIL,
+	"DAEMON":   syslog.LOG_DAEMON,
+	"AUTH":     syslog.LOG_AUTH,
+	"SYSLOG":   syslog.LOG_SYSLOG,
+	"LPR":      syslog.LOG_LPR,
+	"NEWS":     syslog.LOG_NEWS,
+	"UUCP":     syslog.LOG_UUCP,
+	"CRON":     syslog.LOG_CRON,
+	"AUTHPRIV": syslog.LOG_AUTHPRIV,
+	"FTP":      syslog.LOG_FTP,
+	"LOCAL0":   syslog.LOG_LOCAL0,
+	"LOCAL1":   syslog.LOG_LOCAL1,
+	"LOCAL2":   syslog.LOG_LOCAL2,
+	"LOCAL3":   syslog.LOG_LOCAL3,
+	"LOCAL4":   syslog.LOG_LOCAL4,
+	"LOCAL5":   syslog.LOG_LOCAL5,
+	"LOCAL6":   syslog.LOG_LOCAL6,
+	"LOCAL7":   syslog.LOG_LOCAL7,
+}
+
+// syslogSink writes each event as a JSON message to the local syslog daemon.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink returns a sink writing events to the local syslog daemon
+// with the given facility and tag. Empty values use the "USER" facility and
+// the "boundary" tag.
+func NewSyslogSink(facility, tag string) (Sink, error) {
+	priority := syslog.LOG_USER
+	if facility != "" {
+		var ok bool
+		if priority, ok = syslogFacilities[strings.ToUpper(facility)]; !ok {
+			return nil, fmt.Errorf("unknown syslog facility %q", facility)
+		}
+	}
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+	w, err := syslog.New(priority|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to syslog: %w", err)
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) Write(_ context.Context, ev *Event) error {
+	b, err := marshalLine(ev)
+	if err != nil {
+		return err
+	}
+	return s.w.Info(string(b))
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,11 @@
+// +build windows plan9
+
+package event
+
+import "errors"
+
+// NewSyslogSink returns an error as syslog is not supported on this
+// platform.
+func NewSyslogSink(_, _ string) (Sink, error) {
+	return nil, errors.New("syslog sinks are not supported on this platform")
+}
@@ -0,0 +1,183 @@
+package event
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink_Rotation(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(err)
+	path := filepath.Join(dir, "events.log")
+
+	ev, err := newEvent(SystemType, "op", nil)
+	require.NoError(err)
+	line, err := marshalLine(ev)
+	require.NoError(err)
+
+	// Room for two events per file, keeping two rotated files
+	s, err := NewFileSink(path, int64(2*len(line)), 0, 2)
+	require.NoError(err)
+	defer s.Close()
+	for i := 0; i < 7; i++ {
+		require.NoError(s.Write(context.Background(), ev))
+		// Ensure the rotated file names differ
+		time.Sleep(time.Millisecond)
+	}
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "events-*.log"))
+	require.NoError(err)
+	assert.Len(rotated, 2)
+	current, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	assert.Equal(line, current)
+	for _, r := range rotated {
+		contents, err := ioutil.ReadFile(r)
+		require.NoError(err)
+		assert.Len(contents, 2*len(line))
+	}
+}
+
+func TestFileSink_PruneOnlyRotated(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(err)
+	path := filepath.Join(dir, "events.log")
+
+	// Files sharing the prefix of the file's name which rotation didn't
+	// create are never removed
+	unrelated := []string{"events-archive.log", "events-1-old.log", "events-.log"}
+	for _, name := range unrelated {
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	ev, err := newEvent(SystemType, "op", nil)
+	require.NoError(err)
+	line, err := marshalLine(ev)
+	require.NoError(err)
+	s, err := NewFileSink(path, int64(len(line)), 0, 1)
+	require.NoError(err)
+	defer s.Close()
+	for i := 0; i < 4; i++ {
+		require.NoError(s.Write(context.Background(), ev))
+		time.Sleep(time.Millisecond)
+	}
+
+	for _, name := range unrelated {
+		assert.FileExists(filepath.Join(dir, name))
+	}
+	// Only one rotated file is kept alongside them
+	rotated, err := filepath.Glob(filepath.Join(dir, "events-*.log"))
+	require.NoError(err)
+	assert.Len(rotated, len(unrelated)+1)
+}
+
+func TestFileSink_RotationFailure(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(err)
+	path := filepath.Join(dir, "events.log")
+
+	ev, err := newEvent(SystemType, "op", nil)
+	require.NoError(err)
+	line, err := marshalLine(ev)
+	require.NoError(err)
+	s, err := NewFileSink(path, int64(len(line)), 0, 0)
+	require.NoError(err)
+	defer s.Close()
+	require.NoError(s.Write(context.Background(), ev))
+
+	// Renaming the file fails once it's gone, so the original path is
+	// reopened and the event is still written
+	require.NoError(os.Remove(path))
+	assert.Error(s.Write(context.Background(), ev))
+	current, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	assert.Equal(line, current)
+
+	time.Sleep(time.Millisecond)
+	require.NoError(s.Write(context.Background(), ev))
+	rotated, err := filepath.Glob(filepath.Join(dir, "events-*.log"))
+	require.NoError(err)
+	assert.Len(rotated, 1)
+}
+
+func TestWebhookSink(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	type request struct {
+		body   []byte
+		header string
+	}
+	requests := make(chan request, 1)
+	var status int32 = http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		requests <- request{body: body, header: r.Header.Get("Authorization")}
+	}))
+	defer srv.Close()
+
+	s, err := newWebhookSink(srv.URL, map[string]string{"Authorization": "Bearer token"}, time.Second, 1)
+	require.NoError(err)
+	defer s.Close()
+
+	ev, err := newEvent(AuditType, "op", map[string]interface{}{"k": "v"})
+	require.NoError(err)
+	want, err := marshalLine(ev)
+	require.NoError(err)
+	require.NoError(s.Write(context.Background(), ev))
+	got := <-requests
+	assert.Equal(want, got.body)
+	assert.Equal("Bearer token", got.header)
+
+	// A failure to send is returned by the next write
+	atomic.StoreInt32(&status, http.StatusInternalServerError)
+	require.NoError(s.Write(context.Background(), ev))
+	<-requests
+	require.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.failures == 1
+	}, time.Second, 10*time.Millisecond)
+	atomic.StoreInt32(&status, http.StatusOK)
+	assert.Error(s.Write(context.Background(), ev))
+	<-requests
+}
+
+func TestWebhookSink_QueueFull(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer srv.Close()
+
+	s, err := newWebhookSink(srv.URL, nil, 5*time.Second, 1)
+	require.NoError(err)
+	ev, err := newEvent(AuditType, "op", nil)
+	require.NoError(err)
+
+	// Writing doesn't wait for the webhook, but once the one event being
+	// sent and the one queued fill the queue, further events are dropped
+	require.NoError(s.Write(context.Background(), ev))
+	<-received
+	require.NoError(s.Write(context.Background(), ev))
+	assert.Error(s.Write(context.Background(), ev))
+
+	close(release)
+	require.NoError(s.Close())
+	assert.Error(s.Write(context.Background(), ev))
+}
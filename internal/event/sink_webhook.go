@@ -0,0 +1,144 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// webhookQueueSize is the number of events a webhook sink holds while
+// waiting to send them. Events written while the queue is full are dropped.
+const webhookQueueSize = 1024
+
+// webhookSink POSTs each event as JSON to a URL. Events are queued and sent
+// in the background so a slow or unreachable webhook doesn't hold up the
+// code emitting them.
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	timeout time.Duration
+
+	queue  chan []byte
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	closed   bool
+	failures int
+	lastErr  error
+}
+
+// NewWebhookSink returns a sink which POSTs each event as JSON to url with
+// the given extra headers, waiting up to timeout for each request. A
+// response with a status other than 2xx is an error. Events are sent in the
+// background; failures to send them are returned by the next Write.
+func NewWebhookSink(url string, headers map[string]string, timeout time.Duration) (Sink, error) {
+	return newWebhookSink(url, headers, timeout, webhookQueueSize)
+}
+
+func newWebhookSink(url string, headers map[string]string, timeout time.Duration, queueSize int) (*webhookSink, error) {
+	if url == "" {
+		return nil, fmt.Errorf("missing webhook url")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &webhookSink{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+		timeout: timeout,
+		queue:   make(chan []byte, queueSize),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go s.send(ctx)
+	return s, nil
+}
+
+// Write queues ev to be sent. It returns an error if the queue is full, or
+// if events queued by earlier writes could not be sent.
+func (s *webhookSink) Write(_ context.Context, ev *Event) error {
+	b, err := marshalLine(ev)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("webhook sink is closed")
+	}
+	select {
+	case s.queue <- b:
+	default:
+		return errors.New("webhook queue is full, event dropped")
+	}
+	if s.failures > 0 {
+		err = fmt.Errorf("%d events could not be sent to the webhook: %w", s.failures, s.lastErr)
+		s.failures, s.lastErr = 0, nil
+	}
+	return err
+}
+
+// send posts the queued events until the queue is closed.
+func (s *webhookSink) send(ctx context.Context) {
+	defer close(s.done)
+	for b := range s.queue {
+		if err := s.post(ctx, b); err != nil {
+			s.mu.Lock()
+			s.failures++
+			s.lastErr = err
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *webhookSink) post(ctx context.Context, b []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("error creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close waits up to the request timeout for the queued events to be sent,
+// then abandons any which haven't been.
+func (s *webhookSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(s.timeout):
+		s.cancel()
+		<-s.done
+	}
+	s.cancel()
+	s.client.CloseIdleConnections()
+	return nil
+}
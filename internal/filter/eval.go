@@ -0,0 +1,175 @@
+package filter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type node interface {
+	eval(data map[string]interface{}) bool
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n *andNode) eval(data map[string]interface{}) bool {
+	return n.left.eval(data) && n.right.eval(data)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n *orNode) eval(data map[string]interface{}) bool {
+	return n.left.eval(data) || n.right.eval(data)
+}
+
+type notNode struct {
+	n node
+}
+
+func (n *notNode) eval(data map[string]interface{}) bool {
+	return !n.n.eval(data)
+}
+
+type operator int
+
+const (
+	opEqual operator = iota
+	opContains
+	opMatches
+	opEmpty
+	opIn
+)
+
+// selector is the path to a value as JSON pointer reference tokens.
+type selector []string
+
+// String returns s as a JSON pointer.
+func (s selector) String() string {
+	var b strings.Builder
+	for _, t := range s {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(t))
+	}
+	return b.String()
+}
+
+// lookup returns the value at s in data and whether it was found.
+func (s selector) lookup(data map[string]interface{}) (interface{}, bool) {
+	var cur interface{} = data
+	for _, t := range s {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if cur, ok = v[t]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(t)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+type matchNode struct {
+	selector selector
+	op       operator
+	negate   bool
+	value    string
+	re       *regexp.Regexp
+}
+
+func (n *matchNode) eval(data map[string]interface{}) bool {
+	v, found := n.selector.lookup(data)
+	var ret bool
+	switch n.op {
+	case opEqual:
+		ret = found && equal(v, n.value)
+	case opContains:
+		ret = found && contains(v, n.value)
+	case opMatches:
+		if s, ok := scalarString(v); found && ok {
+			ret = n.re.MatchString(s)
+		}
+	case opEmpty:
+		ret = !found || empty(v)
+	case opIn:
+		ret = found && contains(v, n.value)
+	}
+	if n.negate {
+		return !ret
+	}
+	return ret
+}
+
+// equal compares a value decoded from JSON with a value from an expression,
+// converting the expression's value to the type of v.
+func equal(v interface{}, value string) bool {
+	switch v := v.(type) {
+	case string:
+		return v == value
+	case float64:
+		f, err := strconv.ParseFloat(value, 64)
+		return err == nil && f == v
+	case bool:
+		b, err := strconv.ParseBool(value)
+		return err == nil && b == v
+	case nil:
+		return value == "null"
+	}
+	return false
+}
+
+// contains returns true if v is a list containing value, a map with the key
+// value or a string with value as a substring.
+func contains(v interface{}, value string) bool {
+	switch v := v.(type) {
+	case string:
+		return strings.Contains(v, value)
+	case []interface{}:
+		for _, e := range v {
+			if equal(e, value) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		_, ok := v[value]
+		return ok
+	}
+	return false
+}
+
+func empty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func scalarString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
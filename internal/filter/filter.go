@@ -0,0 +1,114 @@
+// Package filter implements the expressions used to filter the items returned
+// by list requests. An expression is evaluated against the JSON form of each
+// item, which is found under the "item" key, and is made of matches combined
+// with "and", "or", "not" and parentheses. A match compares the value found
+// at a selector, either a JSON pointer such as "/item/name" or a dotted path
+// such as item.name, to a value:
+//
+//	"/item/name" == "web"
+//	"/item/name" != "web"
+//	"/item/description" contains "prod"
+//	"/item/name" matches "^web-[0-9]+$"
+//	"/item/description" is empty
+//	"admin" in "/item/authorized_actions"
+//
+// Each operator other than "is empty" can be negated with "not", e.g.
+// "not contains" and "not in", and "is not empty" negates "is empty".
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Filter is a parsed filter expression. The zero value and a nil Filter match
+// every item.
+type Filter struct {
+	expr string
+	root node
+}
+
+// New parses expr. An empty expr returns a Filter matching every item.
+func New(expr string) (*Filter, error) {
+	f := &Filter{expr: strings.TrimSpace(expr)}
+	if f.expr == "" {
+		return f, nil
+	}
+	p, err := newParser(f.expr)
+	if err != nil {
+		return nil, err
+	}
+	if f.root, err = p.parse(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// String returns the expression f was parsed from.
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// Match returns true if the JSON form of item, using the proto field names,
+// matches f.
+func (f *Filter) Match(item proto.Message) (bool, error) {
+	if f == nil || f.root == nil {
+		return true, nil
+	}
+	raw, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(item)
+	if err != nil {
+		return false, fmt.Errorf("error encoding item: %w", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return false, fmt.Errorf("error decoding item: %w", err)
+	}
+	return f.MatchValue(map[string]interface{}{"item": m}), nil
+}
+
+// MatchValue returns true if data, as decoded from JSON, matches f.
+func (f *Filter) MatchValue(data map[string]interface{}) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+	return f.root.eval(data)
+}
+
+// Equality returns the value selector must be equal to for an item to match
+// f, if f requires one. Only matches which must all hold for f to match, that
+// is those which are not under an "or" or "not", are considered. It allows a
+// repository to narrow its query before items are matched against f.
+// Selectors are compared as JSON pointers, so "/item/name" and item.name are
+// the same selector.
+func (f *Filter) Equality(selector string) (string, bool) {
+	if f == nil || f.root == nil {
+		return "", false
+	}
+	path, err := parseSelector(selector)
+	if err != nil {
+		return "", false
+	}
+	return equality(f.root, path.String())
+}
+
+func equality(n node, selector string) (string, bool) {
+	switch n := n.(type) {
+	case *andNode:
+		if v, ok := equality(n.left, selector); ok {
+			return v, true
+		}
+		return equality(n.right, selector)
+	case *matchNode:
+		if n.op == opEqual && !n.negate && n.selector.String() == selector {
+			return n.value, true
+		}
+	}
+	return "", false
+}
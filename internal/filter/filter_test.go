@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"testing"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/groups"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestFilter_MatchValue(t *testing.T) {
+	data := map[string]interface{}{
+		"item": map[string]interface{}{
+			"name":               "web-01",
+			"description":        "",
+			"version":            float64(3),
+			"enabled":            true,
+			"authorized_actions": []interface{}{"read", "update"},
+			"attributes":         map[string]interface{}{"address": "10.0.0.1"},
+			"a/b":                "slash",
+		},
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "", want: true},
+		{expr: `"/item/name" == "web-01"`, want: true},
+		{expr: `item.name == "web-01"`, want: true},
+		{expr: `"/item/name" == web-01`, want: true},
+		{expr: `"/item/name" != "web-01"`, want: false},
+		{expr: `"/item/name" == "web-02"`, want: false},
+		{expr: `"/item/version" == 3`, want: true},
+		{expr: `"/item/version" == "3.0"`, want: true},
+		{expr: `"/item/enabled" == true`, want: true},
+		{expr: `"/item/name" contains "web"`, want: true},
+		{expr: `"/item/name" not contains "web"`, want: false},
+		{expr: `"/item/attributes" contains "address"`, want: true},
+		{expr: `"/item/attributes/address" == "10.0.0.1"`, want: true},
+		{expr: `"/item/a~1b" == "slash"`, want: true},
+		{expr: `"/item/name" matches "^web-[0-9]+$"`, want: true},
+		{expr: "\"/item/name\" not matches `^db`", want: true},
+		{expr: `"/item/description" is empty`, want: true},
+		{expr: `"/item/missing" is empty`, want: true},
+		{expr: `"/item/name" is not empty`, want: true},
+		{expr: `"update" in "/item/authorized_actions"`, want: true},
+		{expr: `"delete" in "/item/authorized_actions"`, want: false},
+		{expr: `"delete" not in "/item/authorized_actions"`, want: true},
+		{expr: `"/item/missing" == "x"`, want: false},
+		{expr: `"/item/missing" != "x"`, want: true},
+		{expr: `"/item/name" == "web-01" and "/item/version" == 2`, want: false},
+		{expr: `"/item/name" == "web-01" or "/item/version" == 2`, want: true},
+		{expr: `not "/item/name" == "web-01"`, want: false},
+		{expr: `("/item/version" == 2 or "/item/version" == 3) and "/item/enabled" == true`, want: true},
+		{expr: `"/item/version" == 2 or "/item/version" == 3 and "/item/enabled" == false`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := New(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.MatchValue(data))
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, expr := range []string{
+		`"/item/name"`,
+		`"/item/name" ==`,
+		`"/item/name" = "x"`,
+		`"/item/name" like "x"`,
+		`"/item/name" == "x" and`,
+		`("/item/name" == "x"`,
+		`"/item/name" == "x")`,
+		`"/item/name" == "x`,
+		`"/item/name" is "x"`,
+		`"/item/name" not == "x"`,
+		`"/item/name" matches "["`,
+		`"" == "x"`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := New(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	f, err := New(`"/item/name" == "g1" and "/item/scope_id" == "o_1234567890"`)
+	require.NoError(err)
+
+	ok, err := f.Match(&pb.Group{Name: wrapperspb.String("g1"), ScopeId: "o_1234567890"})
+	require.NoError(err)
+	assert.True(ok)
+	ok, err = f.Match(&pb.Group{Name: wrapperspb.String("g2"), ScopeId: "o_1234567890"})
+	require.NoError(err)
+	assert.False(ok)
+
+	var nilFilter *Filter
+	ok, err = nilFilter.Match(&pb.Group{})
+	require.NoError(err)
+	assert.True(ok)
+}
+
+func TestFilter_Equality(t *testing.T) {
+	tests := []struct {
+		expr     string
+		selector string
+		want     string
+		wantOk   bool
+	}{
+		{expr: `"/item/name" == "g1"`, selector: "/item/name", want: "g1", wantOk: true},
+		{expr: `item.name == "g1"`, selector: "/item/name", want: "g1", wantOk: true},
+		{expr: `"/item/name" == "g1"`, selector: "item.name", want: "g1", wantOk: true},
+		{expr: `"/item/version" == 1 and ("/item/name" == "g1")`, selector: "/item/name", want: "g1", wantOk: true},
+		{expr: `"/item/name" == "g1" or "/item/name" == "g2"`, selector: "/item/name"},
+		{expr: `not "/item/name" == "g1"`, selector: "/item/name"},
+		{expr: `"/item/name" != "g1"`, selector: "/item/name"},
+		{expr: `"/item/name" contains "g1"`, selector: "/item/name"},
+		{expr: `"/item/status" == "active"`, selector: "/item/name"},
+		{expr: "", selector: "/item/name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := New(tt.expr)
+			require.NoError(t, err)
+			got, ok := f.Equality(tt.selector)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
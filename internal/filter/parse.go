@@ -0,0 +1,288 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenEqual
+	tokenNotEqual
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// keyword returns true if t is the unquoted word kw.
+func (t token) keyword(kw string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, kw)
+}
+
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case strings.HasPrefix(expr[i:], "=="):
+			tokens = append(tokens, token{kind: tokenEqual, text: "==", pos: i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "!="):
+			tokens = append(tokens, token{kind: tokenNotEqual, text: "!=", pos: i})
+			i += 2
+		case c == '"':
+			// Find the closing quote, skipping escaped characters
+			end := i + 1
+			for ; end < len(expr) && expr[end] != '"'; end++ {
+				if expr[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			s, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d: %w", i, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s, pos: i})
+			i = end + 1
+		case c == '`':
+			end := strings.IndexByte(expr[i+1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: expr[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			start := i
+			for i < len(expr) && isWordChar(rune(expr[i])) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: expr[start:i], pos: start})
+		}
+	}
+	return tokens, nil
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:/+", r)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func newParser(expr string) (*parser, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, error) {
+	t, ok := p.peek()
+	if !ok {
+		return token{}, errors.New("unexpected end of expression")
+	}
+	p.pos++
+	return t, nil
+}
+
+func (p *parser) parse() (node, error) {
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+	return n, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || !t.keyword("or") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || !t.keyword("and") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case t.keyword("not"):
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{n: n}, nil
+	case t.kind == tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d", t.pos)
+		}
+		return n, nil
+	case t.kind == tokenWord, t.kind == tokenString:
+		return p.parseMatch(t)
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+// parseMatch parses a match starting with first, which is either the
+// selector or, for "in", the value.
+func (p *parser) parseMatch(first token) (node, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	n := new(matchNode)
+	if t.keyword("not") {
+		n.negate = true
+		if t, err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case t.kind == tokenEqual && !n.negate:
+		n.op = opEqual
+	case t.kind == tokenNotEqual && !n.negate:
+		n.op, n.negate = opEqual, true
+	case t.keyword("contains"):
+		n.op = opContains
+	case t.keyword("matches"):
+		n.op = opMatches
+	case t.keyword("in"):
+		n.op = opIn
+	case t.keyword("is") && !n.negate:
+		n.op = opEmpty
+		if t, err = p.next(); err != nil {
+			return nil, err
+		}
+		if t.keyword("not") {
+			n.negate = true
+			if t, err = p.next(); err != nil {
+				return nil, err
+			}
+		}
+		if !t.keyword("empty") {
+			return nil, fmt.Errorf("expected \"empty\" at position %d", t.pos)
+		}
+		if n.selector, err = parseSelector(first.text); err != nil {
+			return nil, fmt.Errorf("invalid selector at position %d: %w", first.pos, err)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unknown operator %q at position %d", t.text, t.pos)
+	}
+
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, fmt.Errorf("expected a value at position %d", value.pos)
+	}
+	sel := first
+	if n.op == opIn {
+		sel, value = value, first
+	}
+	if n.selector, err = parseSelector(sel.text); err != nil {
+		return nil, fmt.Errorf("invalid selector at position %d: %w", sel.pos, err)
+	}
+	n.value = value.text
+	if n.op == opMatches {
+		if n.re, err = regexp.Compile(n.value); err != nil {
+			return nil, fmt.Errorf("invalid regular expression at position %d: %w", value.pos, err)
+		}
+	}
+	return n, nil
+}
+
+// parseSelector parses a JSON pointer, such as "/item/name", or a dotted
+// path, such as item.name.
+func parseSelector(s string) (selector, error) {
+	if s == "" {
+		return nil, errors.New("empty selector")
+	}
+	if !strings.HasPrefix(s, "/") {
+		return selector(strings.Split(s, ".")), nil
+	}
+	parts := strings.Split(s[1:], "/")
+	for i, t := range parts {
+		parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return selector(parts), nil
+}
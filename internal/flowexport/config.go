@@ -0,0 +1,128 @@
+package flowexport
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// The formats connection records can be exported in.
+const (
+	// IpfixFormat exports records as IPFIX (NetFlow v10) messages.
+	IpfixFormat = "ipfix"
+
+	// JsonFormat exports records as lines of JSON.
+	JsonFormat = "json"
+)
+
+const (
+	defaultInterval           = 10 * time.Second
+	defaultMaxBufferedRecords = 10000
+)
+
+// Config is the configuration of connection export, as given in the
+// "connection_export" block of a controller's configuration.
+type Config struct {
+	// Format is the format records are exported in, either "ipfix" or
+	// "json". If empty, "ipfix" is used.
+	Format string `hcl:"format"`
+
+	// Address is the host and port of the collector records are sent to.
+	Address string `hcl:"address"`
+
+	// Network is how records are sent to the collector, either "udp" or
+	// "tcp". If empty, "udp" is used.
+	Network string `hcl:"network"`
+
+	// Interval is how often buffered records are sent. If empty, records
+	// are sent every 10 seconds.
+	Interval string `hcl:"interval"`
+
+	// ObservationDomainId is the observation domain id of IPFIX messages.
+	ObservationDomainId uint32 `hcl:"observation_domain_id"`
+
+	// EnterpriseNumber is the private enterprise number of the IPFIX
+	// information elements holding the session, connection and target ids.
+	// If zero, the ids are not included in IPFIX messages.
+	EnterpriseNumber uint32 `hcl:"enterprise_number"`
+
+	// MaxBufferedRecords is the most records kept while waiting to be sent.
+	// Records closed while the buffer is full are dropped. If zero, 10000
+	// records are kept.
+	MaxBufferedRecords int `hcl:"max_buffered_records"`
+}
+
+// Validate returns an error if c is invalid. A nil Config is valid.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Format {
+	case "", IpfixFormat, JsonFormat:
+	default:
+		return fmt.Errorf("connection_export: unknown format %q", c.Format)
+	}
+	switch c.Network {
+	case "", "udp", "tcp":
+	default:
+		return fmt.Errorf("connection_export: unknown network %q", c.Network)
+	}
+	if c.Address == "" {
+		return errors.New("connection_export: missing address")
+	}
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return fmt.Errorf("connection_export: invalid address %q: %w", c.Address, err)
+	}
+	if _, err := c.interval(); err != nil {
+		return err
+	}
+	if c.MaxBufferedRecords < 0 {
+		return errors.New("connection_export: max_buffered_records must not be negative")
+	}
+	return nil
+}
+
+// Enabled returns true if c is set.
+func (c *Config) Enabled() bool {
+	return c != nil
+}
+
+// FlushInterval returns how often buffered records are sent. c must be
+// valid.
+func (c *Config) FlushInterval() time.Duration {
+	d, _ := c.interval()
+	return d
+}
+
+func (c *Config) interval() (time.Duration, error) {
+	if c.Interval == "" {
+		return defaultInterval, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("connection_export: invalid interval %q", c.Interval)
+	}
+	return d, nil
+}
+
+func (c *Config) format() string {
+	if c.Format == "" {
+		return IpfixFormat
+	}
+	return c.Format
+}
+
+func (c *Config) network() string {
+	if c.Network == "" {
+		return "udp"
+	}
+	return c.Network
+}
+
+func (c *Config) maxBufferedRecords() int {
+	if c.MaxBufferedRecords == 0 {
+		return defaultMaxBufferedRecords
+	}
+	return c.MaxBufferedRecords
+}
@@ -0,0 +1,27 @@
+package flowexport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	var c *Config
+	assert.NoError(t, c.Validate())
+	assert.NoError(t, (&Config{Address: "127.0.0.1:4739"}).Validate())
+	assert.NoError(t, (&Config{Format: JsonFormat, Network: "tcp", Address: "collector:9000", Interval: "1m"}).Validate())
+	assert.Error(t, (&Config{}).Validate())
+	assert.Error(t, (&Config{Address: "collector"}).Validate())
+	assert.Error(t, (&Config{Address: "collector:4739", Format: "netflow5"}).Validate())
+	assert.Error(t, (&Config{Address: "collector:4739", Network: "sctp"}).Validate())
+	assert.Error(t, (&Config{Address: "collector:4739", Interval: "soon"}).Validate())
+	assert.Error(t, (&Config{Address: "collector:4739", Interval: "-1s"}).Validate())
+	assert.Error(t, (&Config{Address: "collector:4739", MaxBufferedRecords: -1}).Validate())
+}
+
+func TestConfig_FlushInterval(t *testing.T) {
+	assert.Equal(t, defaultInterval, (&Config{}).FlushInterval())
+	assert.Equal(t, time.Minute, (&Config{Interval: "1m"}).FlushInterval())
+}
@@ -0,0 +1,194 @@
+// Package flowexport exports records of closed session connections to a
+// NetFlow/IPFIX or JSON collector, so connections through Boundary can be
+// reported on alongside the rest of a network's flows.
+package flowexport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// writeTimeout bounds how long dialing the collector and sending each
+// message to it can take.
+const writeTimeout = 5 * time.Second
+
+// A Record is a closed session connection. Up is from the client to the
+// endpoint and down is from the endpoint to the client.
+type Record struct {
+	ConnectionId string
+	SessionId    string
+	UserId       string
+	TargetId     string
+	ScopeId      string
+
+	// Protocol is the transport protocol of the connection, either "tcp" or
+	// "udp".
+	Protocol string
+
+	ClientAddress   string
+	ClientPort      uint32
+	EndpointAddress string
+	EndpointPort    uint32
+
+	BytesUp       uint64
+	BytesDown     uint64
+	DatagramsUp   uint64
+	DatagramsDown uint64
+
+	StartTime    time.Time
+	EndTime      time.Time
+	ClosedReason string
+}
+
+// A message is an encoded message holding records records.
+type message struct {
+	data    []byte
+	records int
+}
+
+// An encoder encodes records into messages for the collector.
+type encoder interface {
+	// encode returns messages holding all of records, in order. seq is the
+	// number of records sent before records.
+	encode(records []*Record, seq uint32, now time.Time) []*message
+}
+
+// An Exporter buffers records and sends them to a collector when flushed.
+// It is safe for concurrent use.
+type Exporter struct {
+	conf *Config
+	enc  encoder
+
+	mu      sync.Mutex
+	records []*Record
+	dropped uint64
+
+	// sendMu serializes flushes, which own conn and seq.
+	sendMu sync.Mutex
+	conn   net.Conn
+	seq    uint32
+}
+
+// NewExporter returns an Exporter configured by conf.
+func NewExporter(conf *Config) (*Exporter, error) {
+	if !conf.Enabled() {
+		return nil, errors.New("new connection exporter: missing config")
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, fmt.Errorf("new connection exporter: %w", err)
+	}
+	e := &Exporter{conf: conf}
+	switch conf.format() {
+	case JsonFormat:
+		e.enc = jsonEncoder{}
+	default:
+		e.enc = &ipfixEncoder{
+			observationDomainId: conf.ObservationDomainId,
+			enterpriseNumber:    conf.EnterpriseNumber,
+		}
+	}
+	return e, nil
+}
+
+// Add buffers records to be sent by the next Flush. Records which don't fit
+// in the buffer are dropped.
+func (e *Exporter) Add(records ...*Record) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		if len(e.records) >= e.conf.maxBufferedRecords() {
+			e.dropped++
+			continue
+		}
+		e.records = append(e.records, r)
+	}
+}
+
+// Dropped returns the number of records dropped because the buffer was full.
+func (e *Exporter) Dropped() uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.dropped
+}
+
+// Flush sends the buffered records to the collector and returns the number
+// sent. Records which couldn't be sent are kept to be sent by the next
+// Flush, as long as they fit in the buffer.
+func (e *Exporter) Flush(ctx context.Context) (int, error) {
+	e.sendMu.Lock()
+	defer e.sendMu.Unlock()
+
+	e.mu.Lock()
+	records := e.records
+	e.records = nil
+	e.mu.Unlock()
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	sent := 0
+	err := e.send(ctx, records, &sent)
+	if err != nil {
+		e.requeue(records[sent:])
+		return sent, fmt.Errorf("flush connection records: %w", err)
+	}
+	return sent, nil
+}
+
+func (e *Exporter) send(ctx context.Context, records []*Record, sent *int) error {
+	if e.conn == nil {
+		d := net.Dialer{Timeout: writeTimeout}
+		conn, err := d.DialContext(ctx, e.conf.network(), e.conf.Address)
+		if err != nil {
+			return fmt.Errorf("unable to dial collector: %w", err)
+		}
+		e.conn = conn
+	}
+	for _, m := range e.enc.encode(records, e.seq, time.Now()) {
+		if err := e.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+			e.closeConn()
+			return fmt.Errorf("unable to set write deadline: %w", err)
+		}
+		if _, err := e.conn.Write(m.data); err != nil {
+			e.closeConn()
+			return fmt.Errorf("unable to write to collector: %w", err)
+		}
+		*sent += m.records
+		e.seq += uint32(m.records)
+	}
+	return nil
+}
+
+// requeue puts records back in front of the records added since they were
+// taken, dropping the oldest records if they don't all fit.
+func (e *Exporter) requeue(records []*Record) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	all := append(records, e.records...)
+	if max := e.conf.maxBufferedRecords(); len(all) > max {
+		e.dropped += uint64(len(all) - max)
+		all = all[len(all)-max:]
+	}
+	e.records = all
+}
+
+// Close closes the connection to the collector. Buffered records are not
+// sent.
+func (e *Exporter) Close() error {
+	e.sendMu.Lock()
+	defer e.sendMu.Unlock()
+	return e.closeConn()
+}
+
+func (e *Exporter) closeConn() error {
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	return err
+}
@@ -0,0 +1,82 @@
+package flowexport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExporter_FlushJson(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer l.Close()
+
+	e, err := NewExporter(&Config{Format: JsonFormat, Network: "tcp", Address: l.Addr().String()})
+	require.NoError(err)
+	defer e.Close()
+
+	e.Add(testRecord("10.0.0.1", "10.0.0.2"), testRecord("10.0.0.3", "10.0.0.2"))
+	n, err := e.Flush(context.Background())
+	require.NoError(err)
+	assert.Equal(2, n)
+
+	conn, err := l.Accept()
+	require.NoError(err)
+	defer conn.Close()
+	require.NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+	scanner := bufio.NewScanner(conn)
+	var got []map[string]interface{}
+	for len(got) < 2 && scanner.Scan() {
+		m := make(map[string]interface{})
+		require.NoError(json.Unmarshal(scanner.Bytes(), &m))
+		got = append(got, m)
+	}
+	require.Len(got, 2)
+	assert.Equal("10.0.0.1", got[0]["client_address"])
+	assert.Equal("u_1234567890", got[0]["user_id"])
+	assert.Equal(float64(1500), got[0]["duration_ms"])
+	assert.Equal("10.0.0.3", got[1]["client_address"])
+
+	n, err = e.Flush(context.Background())
+	assert.NoError(err)
+	assert.Equal(0, n)
+}
+
+func TestExporter_FlushFailure(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	addr := l.Addr().String()
+	require.NoError(l.Close())
+
+	e, err := NewExporter(&Config{Network: "tcp", Address: addr, MaxBufferedRecords: 2})
+	require.NoError(err)
+	e.Add(testRecord("10.0.0.1", "10.0.0.2"), testRecord("10.0.0.3", "10.0.0.2"), testRecord("10.0.0.4", "10.0.0.2"))
+	assert.Equal(uint64(1), e.Dropped())
+
+	n, err := e.Flush(context.Background())
+	assert.Error(err)
+	assert.Equal(0, n)
+	e.Add(testRecord("10.0.0.5", "10.0.0.2"))
+	assert.Equal(uint64(2), e.Dropped())
+
+	// The records which weren't sent are kept for the next flush
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	require.Len(e.records, 2)
+	assert.Equal("10.0.0.1", e.records[0].ClientAddress)
+}
+
+func TestNewExporter(t *testing.T) {
+	_, err := NewExporter(nil)
+	assert.Error(t, err)
+	_, err = NewExporter(&Config{Format: "netflow5", Address: "collector:2055"})
+	assert.Error(t, err)
+}
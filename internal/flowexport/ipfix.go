@@ -0,0 +1,274 @@
+package flowexport
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+// IPFIX constants from RFC 7011.
+const (
+	ipfixVersion       = 10
+	ipfixHeaderLen     = 16
+	ipfixSetHeaderLen  = 4
+	ipfixTemplateSetId = 2
+
+	// The templates of records with IPv4 and IPv6 addresses. Records whose
+	// client and endpoint addresses are of different families use the IPv6
+	// template with IPv4-mapped addresses.
+	ipv4TemplateId = 256
+	ipv6TemplateId = 257
+
+	// ipfixVariableLen is the field length of variable-length information
+	// elements.
+	ipfixVariableLen = 0xffff
+
+	// reversePen is the private enterprise number of the reverse information
+	// elements of RFC 5103, which count the endpoint's traffic.
+	reversePen = 29305
+)
+
+// IANA IPFIX information elements.
+const (
+	ieOctetDeltaCount          = 1
+	iePacketDeltaCount         = 2
+	ieProtocolIdentifier       = 4
+	ieSourceTransportPort      = 7
+	ieSourceIPv4Address        = 8
+	ieDestinationTransportPort = 11
+	ieDestinationIPv4Address   = 12
+	ieSourceIPv6Address        = 27
+	ieDestinationIPv6Address   = 28
+	ieFlowEndReason            = 136
+	ieFlowStartMilliseconds    = 152
+	ieFlowEndMilliseconds      = 153
+	ieUserName                 = 371
+)
+
+// The information elements of the enterprise given by
+// Config.EnterpriseNumber.
+const (
+	ieSessionId    = 1
+	ieConnectionId = 2
+	ieTargetId     = 3
+)
+
+// flowEndReason values, from the IANA registry.
+const (
+	flowEndIdleTimeout = 1
+	flowEndEndOfFlow   = 3
+	flowEndForcedEnd   = 4
+)
+
+type ipfixField struct {
+	id     uint16
+	length uint16
+	pen    uint32
+}
+
+// ipfixEncoder encodes records as IPFIX messages. Every message starts with
+// the template set, so collectors which miss a message or start listening
+// late can decode the next one.
+type ipfixEncoder struct {
+	observationDomainId uint32
+	enterpriseNumber    uint32
+}
+
+func (e *ipfixEncoder) fields(addrLen uint16, srcAddr, dstAddr uint16) []ipfixField {
+	fields := []ipfixField{
+		{id: srcAddr, length: addrLen},
+		{id: dstAddr, length: addrLen},
+		{id: ieSourceTransportPort, length: 2},
+		{id: ieDestinationTransportPort, length: 2},
+		{id: ieProtocolIdentifier, length: 1},
+		{id: ieOctetDeltaCount, length: 8},
+		{id: iePacketDeltaCount, length: 8},
+		{id: ieOctetDeltaCount, length: 8, pen: reversePen},
+		{id: iePacketDeltaCount, length: 8, pen: reversePen},
+		{id: ieFlowStartMilliseconds, length: 8},
+		{id: ieFlowEndMilliseconds, length: 8},
+		{id: ieFlowEndReason, length: 1},
+		{id: ieUserName, length: ipfixVariableLen},
+	}
+	if e.enterpriseNumber != 0 {
+		fields = append(fields,
+			ipfixField{id: ieSessionId, length: ipfixVariableLen, pen: e.enterpriseNumber},
+			ipfixField{id: ieConnectionId, length: ipfixVariableLen, pen: e.enterpriseNumber},
+			ipfixField{id: ieTargetId, length: ipfixVariableLen, pen: e.enterpriseNumber},
+		)
+	}
+	return fields
+}
+
+// templateSet returns the template set defining both templates.
+func (e *ipfixEncoder) templateSet() []byte {
+	b := make([]byte, ipfixSetHeaderLen)
+	for _, t := range []struct {
+		id     uint16
+		fields []ipfixField
+	}{
+		{ipv4TemplateId, e.fields(4, ieSourceIPv4Address, ieDestinationIPv4Address)},
+		{ipv6TemplateId, e.fields(16, ieSourceIPv6Address, ieDestinationIPv6Address)},
+	} {
+		b = appendUint16(b, t.id)
+		b = appendUint16(b, uint16(len(t.fields)))
+		for _, f := range t.fields {
+			if f.pen != 0 {
+				b = appendUint16(b, f.id|0x8000)
+				b = appendUint16(b, f.length)
+				b = appendUint32(b, f.pen)
+				continue
+			}
+			b = appendUint16(b, f.id)
+			b = appendUint16(b, f.length)
+		}
+	}
+	binary.BigEndian.PutUint16(b[0:], ipfixTemplateSetId)
+	binary.BigEndian.PutUint16(b[2:], uint16(len(b)))
+	return b
+}
+
+func (e *ipfixEncoder) encode(records []*Record, seq uint32, now time.Time) []*message {
+	templates := e.templateSet()
+	var msgs []*message
+	var cur *message
+	var setStart int
+	var setId uint16
+
+	closeSet := func() {
+		if cur != nil && setId != 0 {
+			binary.BigEndian.PutUint16(cur.data[setStart+2:], uint16(len(cur.data)-setStart))
+		}
+		setId = 0
+	}
+	closeMessage := func() {
+		if cur == nil {
+			return
+		}
+		closeSet()
+		binary.BigEndian.PutUint16(cur.data[2:], uint16(len(cur.data)))
+		msgs = append(msgs, cur)
+		seq += uint32(cur.records)
+		cur = nil
+	}
+
+	for _, r := range records {
+		id, data := e.dataRecord(r)
+		need := len(data)
+		if setId != id {
+			need += ipfixSetHeaderLen
+		}
+		if cur != nil && cur.records > 0 && len(cur.data)+need > maxMessageSize {
+			closeMessage()
+		}
+		if cur == nil {
+			cur = &message{data: make([]byte, ipfixHeaderLen, maxMessageSize)}
+			binary.BigEndian.PutUint16(cur.data[0:], ipfixVersion)
+			binary.BigEndian.PutUint32(cur.data[4:], uint32(now.Unix()))
+			binary.BigEndian.PutUint32(cur.data[8:], seq)
+			binary.BigEndian.PutUint32(cur.data[12:], e.observationDomainId)
+			cur.data = append(cur.data, templates...)
+		}
+		if setId != id {
+			closeSet()
+			setId = id
+			setStart = len(cur.data)
+			cur.data = appendUint16(cur.data, id)
+			cur.data = appendUint16(cur.data, 0)
+		}
+		cur.data = append(cur.data, data...)
+		cur.records++
+	}
+	closeMessage()
+	return msgs
+}
+
+// dataRecord returns the id of the template r is encoded with and the
+// encoded record.
+func (e *ipfixEncoder) dataRecord(r *Record) (uint16, []byte) {
+	client, endpoint := net.ParseIP(r.ClientAddress), net.ParseIP(r.EndpointAddress)
+	id := uint16(ipv6TemplateId)
+	var b []byte
+	if client4, endpoint4 := client.To4(), endpoint.To4(); client4 != nil && endpoint4 != nil {
+		id = ipv4TemplateId
+		b = append(b, client4...)
+		b = append(b, endpoint4...)
+	} else {
+		b = append(b, ipv6Bytes(client)...)
+		b = append(b, ipv6Bytes(endpoint)...)
+	}
+	b = appendUint16(b, uint16(r.ClientPort))
+	b = appendUint16(b, uint16(r.EndpointPort))
+	b = append(b, protocolNumber(r.Protocol))
+	b = appendUint64(b, r.BytesUp)
+	b = appendUint64(b, r.DatagramsUp)
+	b = appendUint64(b, r.BytesDown)
+	b = appendUint64(b, r.DatagramsDown)
+	b = appendUint64(b, uint64(r.StartTime.UnixNano()/int64(time.Millisecond)))
+	b = appendUint64(b, uint64(r.EndTime.UnixNano()/int64(time.Millisecond)))
+	b = append(b, flowEndReason(r.ClosedReason))
+	b = appendVariable(b, r.UserId)
+	if e.enterpriseNumber != 0 {
+		b = appendVariable(b, r.SessionId)
+		b = appendVariable(b, r.ConnectionId)
+		b = appendVariable(b, r.TargetId)
+	}
+	return id, b
+}
+
+// ipv6Bytes returns ip as 16 bytes, or the unspecified address if ip is
+// nil.
+func ipv6Bytes(ip net.IP) []byte {
+	if ip16 := ip.To16(); ip16 != nil {
+		return ip16
+	}
+	return net.IPv6unspecified
+}
+
+// protocolNumber returns the IANA protocol number of protocol.
+func protocolNumber(protocol string) byte {
+	if protocol == "udp" {
+		return 17
+	}
+	return 6
+}
+
+// flowEndReason returns the IPFIX flowEndReason of a connection's closed
+// reason.
+func flowEndReason(closedReason string) byte {
+	switch closedReason {
+	case "timed out", "idle-timeout":
+		return flowEndIdleTimeout
+	case "canceled", "system error":
+		return flowEndForcedEnd
+	default:
+		return flowEndEndOfFlow
+	}
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
+// appendVariable appends s as a variable-length information element,
+// truncated to the most bytes a message can hold.
+func appendVariable(b []byte, s string) []byte {
+	if len(s) > maxMessageSize {
+		s = s[:maxMessageSize]
+	}
+	if len(s) < 255 {
+		b = append(b, byte(len(s)))
+	} else {
+		b = append(b, 255)
+		b = appendUint16(b, uint16(len(s)))
+	}
+	return append(b, s...)
+}
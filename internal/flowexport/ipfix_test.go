@@ -0,0 +1,125 @@
+package flowexport
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecord(client, endpoint string) *Record {
+	start := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	return &Record{
+		ConnectionId:    "sc_1234567890",
+		SessionId:       "s_1234567890",
+		UserId:          "u_1234567890",
+		TargetId:        "ttcp_1234567890",
+		ScopeId:         "p_1234567890",
+		Protocol:        "tcp",
+		ClientAddress:   client,
+		ClientPort:      50000,
+		EndpointAddress: endpoint,
+		EndpointPort:    22,
+		BytesUp:         100,
+		BytesDown:       2000,
+		StartTime:       start,
+		EndTime:         start.Add(1500 * time.Millisecond),
+		ClosedReason:    "closed by end-user",
+	}
+}
+
+func TestIpfixEncoder_encode(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	now := time.Date(2021, time.June, 1, 12, 1, 0, 0, time.UTC)
+	e := &ipfixEncoder{observationDomainId: 7}
+	msgs := e.encode([]*Record{
+		testRecord("10.0.0.1", "10.0.0.2"),
+		testRecord("::1", "10.0.0.2"),
+	}, 42, now)
+	require.Len(msgs, 1)
+	m := msgs[0].data
+	assert.Equal(2, msgs[0].records)
+
+	// Message header
+	assert.Equal(uint16(ipfixVersion), binary.BigEndian.Uint16(m[0:]))
+	assert.Equal(uint16(len(m)), binary.BigEndian.Uint16(m[2:]))
+	assert.Equal(uint32(now.Unix()), binary.BigEndian.Uint32(m[4:]))
+	assert.Equal(uint32(42), binary.BigEndian.Uint32(m[8:]))
+	assert.Equal(uint32(7), binary.BigEndian.Uint32(m[12:]))
+
+	// The template set defines both templates
+	b := m[ipfixHeaderLen:]
+	require.Equal(uint16(ipfixTemplateSetId), binary.BigEndian.Uint16(b[0:]))
+	templatesLen := binary.BigEndian.Uint16(b[2:])
+	assert.Equal(uint16(ipv4TemplateId), binary.BigEndian.Uint16(b[4:]))
+	assert.Equal(uint16(13), binary.BigEndian.Uint16(b[6:]))
+	b = b[templatesLen:]
+
+	// The IPv4 record
+	require.Equal(uint16(ipv4TemplateId), binary.BigEndian.Uint16(b[0:]))
+	setLen := binary.BigEndian.Uint16(b[2:])
+	r := b[ipfixSetHeaderLen:setLen]
+	assert.Equal([]byte{10, 0, 0, 1, 10, 0, 0, 2}, r[0:8])
+	assert.Equal(uint16(50000), binary.BigEndian.Uint16(r[8:]))
+	assert.Equal(uint16(22), binary.BigEndian.Uint16(r[10:]))
+	assert.Equal(byte(6), r[12])
+	assert.Equal(uint64(100), binary.BigEndian.Uint64(r[13:]))
+	assert.Equal(uint64(2000), binary.BigEndian.Uint64(r[29:]))
+	start := binary.BigEndian.Uint64(r[45:])
+	end := binary.BigEndian.Uint64(r[53:])
+	assert.Equal(uint64(1500), end-start)
+	assert.Equal(byte(flowEndEndOfFlow), r[61])
+	assert.Equal(byte(len("u_1234567890")), r[62])
+	assert.Equal("u_1234567890", string(r[63:]))
+	b = b[setLen:]
+
+	// The record with mixed address families uses the IPv6 template
+	require.Equal(uint16(ipv6TemplateId), binary.BigEndian.Uint16(b[0:]))
+	setLen = binary.BigEndian.Uint16(b[2:])
+	assert.Equal(int(setLen), len(b))
+	r = b[ipfixSetHeaderLen:]
+	assert.Equal(byte(1), r[15])
+	assert.Equal([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 10, 0, 0, 2}, r[16:32])
+}
+
+func TestIpfixEncoder_encodeEnterpriseIds(t *testing.T) {
+	assert := assert.New(t)
+	e := &ipfixEncoder{enterpriseNumber: 12345}
+	assert.Len(e.fields(4, ieSourceIPv4Address, ieDestinationIPv4Address), 16)
+
+	msgs := e.encode([]*Record{testRecord("10.0.0.1", "10.0.0.2")}, 0, time.Now())
+	assert.Len(msgs, 1)
+	assert.Contains(string(msgs[0].data), "s_1234567890")
+	assert.Contains(string(msgs[0].data), "sc_1234567890")
+	assert.Contains(string(msgs[0].data), "ttcp_1234567890")
+}
+
+func TestIpfixEncoder_encodeSplitsMessages(t *testing.T) {
+	assert := assert.New(t)
+	var records []*Record
+	for i := 0; i < 100; i++ {
+		records = append(records, testRecord(fmt.Sprintf("10.0.0.%d", i), "10.0.1.1"))
+	}
+	msgs := (&ipfixEncoder{}).encode(records, 10, time.Now())
+	assert.True(len(msgs) > 1)
+	seq, total := uint32(10), 0
+	for _, m := range msgs {
+		assert.LessOrEqual(len(m.data), maxMessageSize)
+		assert.Equal(uint16(len(m.data)), binary.BigEndian.Uint16(m.data[2:]))
+		assert.Equal(seq, binary.BigEndian.Uint32(m.data[8:]))
+		seq += uint32(m.records)
+		total += m.records
+	}
+	assert.Equal(len(records), total)
+}
+
+func TestAppendVariable(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal([]byte{3, 'a', 'b', 'c'}, appendVariable(nil, "abc"))
+	long := appendVariable(nil, string(make([]byte, 300)))
+	assert.Equal([]byte{255, 1, 44}, long[:3])
+	assert.Len(long, 303)
+}
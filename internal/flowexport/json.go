@@ -0,0 +1,80 @@
+package flowexport
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// maxMessageSize is the most bytes put in one message, unless a single
+// record needs more, so messages sent over UDP aren't fragmented.
+const maxMessageSize = 1400
+
+// jsonRecord is the JSON form of a Record.
+type jsonRecord struct {
+	ConnectionId    string    `json:"connection_id"`
+	SessionId       string    `json:"session_id"`
+	UserId          string    `json:"user_id,omitempty"`
+	TargetId        string    `json:"target_id,omitempty"`
+	ScopeId         string    `json:"scope_id,omitempty"`
+	Protocol        string    `json:"protocol"`
+	ClientAddress   string    `json:"client_address"`
+	ClientPort      uint32    `json:"client_port"`
+	EndpointAddress string    `json:"endpoint_address"`
+	EndpointPort    uint32    `json:"endpoint_port"`
+	BytesUp         uint64    `json:"bytes_up"`
+	BytesDown       uint64    `json:"bytes_down"`
+	DatagramsUp     uint64    `json:"datagrams_up,omitempty"`
+	DatagramsDown   uint64    `json:"datagrams_down,omitempty"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	DurationMs      int64     `json:"duration_ms"`
+	ClosedReason    string    `json:"closed_reason,omitempty"`
+}
+
+// jsonEncoder encodes each record as a line of JSON. Lines are packed into
+// messages of at most maxMessageSize bytes.
+type jsonEncoder struct{}
+
+func (jsonEncoder) encode(records []*Record, _ uint32, _ time.Time) []*message {
+	var msgs []*message
+	cur := &message{}
+	for _, r := range records {
+		line, err := json.Marshal(&jsonRecord{
+			ConnectionId:    r.ConnectionId,
+			SessionId:       r.SessionId,
+			UserId:          r.UserId,
+			TargetId:        r.TargetId,
+			ScopeId:         r.ScopeId,
+			Protocol:        r.Protocol,
+			ClientAddress:   r.ClientAddress,
+			ClientPort:      r.ClientPort,
+			EndpointAddress: r.EndpointAddress,
+			EndpointPort:    r.EndpointPort,
+			BytesUp:         r.BytesUp,
+			BytesDown:       r.BytesDown,
+			DatagramsUp:     r.DatagramsUp,
+			DatagramsDown:   r.DatagramsDown,
+			StartTime:       r.StartTime.UTC(),
+			EndTime:         r.EndTime.UTC(),
+			DurationMs:      r.EndTime.Sub(r.StartTime).Milliseconds(),
+			ClosedReason:    r.ClosedReason,
+		})
+		if err != nil {
+			// A jsonRecord always marshals; count the record as sent so it
+			// isn't retried forever.
+			cur.records++
+			continue
+		}
+		line = append(line, '\n')
+		if cur.records > 0 && len(cur.data)+len(line) > maxMessageSize {
+			msgs = append(msgs, cur)
+			cur = &message{}
+		}
+		cur.data = append(cur.data, line...)
+		cur.records++
+	}
+	if cur.records > 0 {
+		msgs = append(msgs, cur)
+	}
+	return msgs
+}
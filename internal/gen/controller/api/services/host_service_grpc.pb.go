@@ -45,6 +45,14 @@ type HostServiceClient interface {
 	// DeleteHost removes a Host from Boundary. If the provided Host ID
 	// is malformed or not provided an error is returned.
 	DeleteHost(ctx context.Context, in *DeleteHostRequest, opts ...grpc.CallOption) (*DeleteHostResponse, error)
+	// ImportHosts creates many Hosts in a Host Catalog in one request. Only
+	// the name, description and attributes of the provided Hosts are used.
+	// Hosts which are invalid or whose name is already in use in the Host
+	// Catalog are not created, and an error is returned for each of them
+	// instead; the rest are created. An error is returned for the whole
+	// request if the Host Catalog id is missing or malformed, or if too many
+	// Hosts are provided.
+	ImportHosts(ctx context.Context, in *ImportHostsRequest, opts ...grpc.CallOption) (*ImportHostsResponse, error)
 }
 
 type hostServiceClient struct {
@@ -100,6 +108,15 @@ func (c *hostServiceClient) DeleteHost(ctx context.Context, in *DeleteHostReques
 	return out, nil
 }
 
+func (c *hostServiceClient) ImportHosts(ctx context.Context, in *ImportHostsRequest, opts ...grpc.CallOption) (*ImportHostsResponse, error) {
+	out := new(ImportHostsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.HostService/ImportHosts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HostServiceServer is the server API for HostService service.
 type HostServiceServer interface {
 	// GetHost returns a stored Host if present.  The provided request
@@ -130,6 +147,14 @@ type HostServiceServer interface {
 	// DeleteHost removes a Host from Boundary. If the provided Host ID
 	// is malformed or not provided an error is returned.
 	DeleteHost(context.Context, *DeleteHostRequest) (*DeleteHostResponse, error)
+	// ImportHosts creates many Hosts in a Host Catalog in one request. Only
+	// the name, description and attributes of the provided Hosts are used.
+	// Hosts which are invalid or whose name is already in use in the Host
+	// Catalog are not created, and an error is returned for each of them
+	// instead; the rest are created. An error is returned for the whole
+	// request if the Host Catalog id is missing or malformed, or if too many
+	// Hosts are provided.
+	ImportHosts(context.Context, *ImportHostsRequest) (*ImportHostsResponse, error)
 }
 
 // UnimplementedHostServiceServer can be embedded to have forward compatible implementations.
@@ -151,6 +176,9 @@ func (*UnimplementedHostServiceServer) UpdateHost(context.Context, *UpdateHostRe
 func (*UnimplementedHostServiceServer) DeleteHost(context.Context, *DeleteHostRequest) (*DeleteHostResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteHost not implemented")
 }
+func (*UnimplementedHostServiceServer) ImportHosts(context.Context, *ImportHostsRequest) (*ImportHostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportHosts not implemented")
+}
 
 func RegisterHostServiceServer(s *grpc.Server, srv HostServiceServer) {
 	s.RegisterService(&_HostService_serviceDesc, srv)
@@ -246,6 +274,24 @@ func _HostService_DeleteHost_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HostService_ImportHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportHostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostServiceServer).ImportHosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.HostService/ImportHosts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostServiceServer).ImportHosts(ctx, req.(*ImportHostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _HostService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.HostService",
 	HandlerType: (*HostServiceServer)(nil),
@@ -270,6 +316,10 @@ var _HostService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteHost",
 			Handler:    _HostService_DeleteHost_Handler,
 		},
+		{
+			MethodName: "ImportHosts",
+			Handler:    _HostService_ImportHosts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/host_service.proto",
@@ -78,6 +78,11 @@ type RoleServiceClient interface {
 	// grants will be removed. If missing, malformed, or references a non-existing
 	// resource, an error is returned.
 	RemoveRoleGrants(ctx context.Context, in *RemoveRoleGrantsRequest, opts ...grpc.CallOption) (*RemoveRoleGrantsResponse, error)
+	// CloneRole creates a copy of a Role in the same scope, with its
+	// description, grant scope and grants but no principals. A name for the
+	// copy can be provided and must not be in use by another Role in the
+	// scope.
+	CloneRole(ctx context.Context, in *CloneRoleRequest, opts ...grpc.CallOption) (*CloneRoleResponse, error)
 }
 
 type roleServiceClient struct {
@@ -187,6 +192,15 @@ func (c *roleServiceClient) RemoveRoleGrants(ctx context.Context, in *RemoveRole
 	return out, nil
 }
 
+func (c *roleServiceClient) CloneRole(ctx context.Context, in *CloneRoleRequest, opts ...grpc.CallOption) (*CloneRoleResponse, error) {
+	out := new(CloneRoleResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/CloneRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RoleServiceServer is the server API for RoleService service.
 type RoleServiceServer interface {
 	// GetRole returns a stored Role if present. The provided request must include
@@ -250,6 +264,11 @@ type RoleServiceServer interface {
 	// grants will be removed. If missing, malformed, or references a non-existing
 	// resource, an error is returned.
 	RemoveRoleGrants(context.Context, *RemoveRoleGrantsRequest) (*RemoveRoleGrantsResponse, error)
+	// CloneRole creates a copy of a Role in the same scope, with its
+	// description, grant scope and grants but no principals. A name for the
+	// copy can be provided and must not be in use by another Role in the
+	// scope.
+	CloneRole(context.Context, *CloneRoleRequest) (*CloneRoleResponse, error)
 }
 
 // UnimplementedRoleServiceServer can be embedded to have forward compatible implementations.
@@ -289,6 +308,9 @@ func (*UnimplementedRoleServiceServer) SetRoleGrants(context.Context, *SetRoleGr
 func (*UnimplementedRoleServiceServer) RemoveRoleGrants(context.Context, *RemoveRoleGrantsRequest) (*RemoveRoleGrantsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveRoleGrants not implemented")
 }
+func (*UnimplementedRoleServiceServer) CloneRole(context.Context, *CloneRoleRequest) (*CloneRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneRole not implemented")
+}
 
 func RegisterRoleServiceServer(s *grpc.Server, srv RoleServiceServer) {
 	s.RegisterService(&_RoleService_serviceDesc, srv)
@@ -492,6 +514,24 @@ func _RoleService_RemoveRoleGrants_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RoleService_CloneRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).CloneRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/CloneRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).CloneRole(ctx, req.(*CloneRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RoleService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.RoleService",
 	HandlerType: (*RoleServiceServer)(nil),
@@ -540,6 +580,10 @@ var _RoleService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveRoleGrants",
 			Handler:    _RoleService_RemoveRoleGrants_Handler,
 		},
+		{
+			MethodName: "CloneRole",
+			Handler:    _RoleService_CloneRole_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/role_service.proto",
@@ -43,6 +43,16 @@ type ScopeServiceClient interface {
 	// DeleteScope remotes a Scope and all child resources from Boundary. If the
 	// provided Scope IDs are malformed or not provided an error is returned.
 	DeleteScope(ctx context.Context, in *DeleteScopeRequest, opts ...grpc.CallOption) (*DeleteScopeResponse, error)
+	// ListAuthorizedResources returns the IDs of the resources of a type
+	// within a Scope which the requester is allowed to perform an action on.
+	// Listing resources of the type in the Scope must be allowed. Only types
+	// of resources which are listed within a Scope are supported.
+	ListAuthorizedResources(ctx context.Context, in *ListAuthorizedResourcesRequest, opts ...grpc.CallOption) (*ListAuthorizedResourcesResponse, error)
+	// GetQuotaUsage returns an organization's use of its quotas: the API
+	// requests made in it in the current period and its pending and active
+	// sessions, with its limits. An error is returned if quotas are not
+	// enabled or the Scope is not an organization.
+	GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error)
 }
 
 type scopeServiceClient struct {
@@ -98,6 +108,24 @@ func (c *scopeServiceClient) DeleteScope(ctx context.Context, in *DeleteScopeReq
 	return out, nil
 }
 
+func (c *scopeServiceClient) ListAuthorizedResources(ctx context.Context, in *ListAuthorizedResourcesRequest, opts ...grpc.CallOption) (*ListAuthorizedResourcesResponse, error) {
+	out := new(ListAuthorizedResourcesResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/ListAuthorizedResources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error) {
+	out := new(GetQuotaUsageResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/GetQuotaUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ScopeServiceServer is the server API for ScopeService service.
 type ScopeServiceServer interface {
 	// GetScope returns a stored Scope if present.  The provided request
@@ -126,6 +154,16 @@ type ScopeServiceServer interface {
 	// DeleteScope remotes a Scope and all child resources from Boundary. If the
 	// provided Scope IDs are malformed or not provided an error is returned.
 	DeleteScope(context.Context, *DeleteScopeRequest) (*DeleteScopeResponse, error)
+	// ListAuthorizedResources returns the IDs of the resources of a type
+	// within a Scope which the requester is allowed to perform an action on.
+	// Listing resources of the type in the Scope must be allowed. Only types
+	// of resources which are listed within a Scope are supported.
+	ListAuthorizedResources(context.Context, *ListAuthorizedResourcesRequest) (*ListAuthorizedResourcesResponse, error)
+	// GetQuotaUsage returns an organization's use of its quotas: the API
+	// requests made in it in the current period and its pending and active
+	// sessions, with its limits. An error is returned if quotas are not
+	// enabled or the Scope is not an organization.
+	GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error)
 }
 
 // UnimplementedScopeServiceServer can be embedded to have forward compatible implementations.
@@ -147,6 +185,12 @@ func (*UnimplementedScopeServiceServer) UpdateScope(context.Context, *UpdateScop
 func (*UnimplementedScopeServiceServer) DeleteScope(context.Context, *DeleteScopeRequest) (*DeleteScopeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteScope not implemented")
 }
+func (*UnimplementedScopeServiceServer) ListAuthorizedResources(context.Context, *ListAuthorizedResourcesRequest) (*ListAuthorizedResourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuthorizedResources not implemented")
+}
+func (*UnimplementedScopeServiceServer) GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuotaUsage not implemented")
+}
 
 func RegisterScopeServiceServer(s *grpc.Server, srv ScopeServiceServer) {
 	s.RegisterService(&_ScopeService_serviceDesc, srv)
@@ -242,6 +286,42 @@ func _ScopeService_DeleteScope_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ScopeService_ListAuthorizedResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuthorizedResourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).ListAuthorizedResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/ListAuthorizedResources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).ListAuthorizedResources(ctx, req.(*ListAuthorizedResourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_GetQuotaUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotaUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).GetQuotaUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/GetQuotaUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).GetQuotaUsage(ctx, req.(*GetQuotaUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ScopeService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.ScopeService",
 	HandlerType: (*ScopeServiceServer)(nil),
@@ -266,6 +346,14 @@ var _ScopeService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteScope",
 			Handler:    _ScopeService_DeleteScope_Handler,
 		},
+		{
+			MethodName: "ListAuthorizedResources",
+			Handler:    _ScopeService_ListAuthorizedResources_Handler,
+		},
+		{
+			MethodName: "GetQuotaUsage",
+			Handler:    _ScopeService_GetQuotaUsage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/scope_service.proto",
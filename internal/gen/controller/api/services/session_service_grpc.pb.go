@@ -27,6 +27,12 @@ type SessionServiceClient interface {
 	// the Sessions being retrieved. If the scope ID is missing, malformed, or
 	// reference a non existing scope, an error is returned.
 	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// CountSessions returns the number of Sessions inside the scope referenced
+	// inside the request by their status, without listing them. Callers who
+	// can only read their own Sessions only have their own counted. If the
+	// scope ID is missing, malformed, or reference a non existing scope, an
+	// error is returned.
+	CountSessions(ctx context.Context, in *CountSessionsRequest, opts ...grpc.CallOption) (*CountSessionsResponse, error)
 	// CancelSession cancels an existing Session in boundary.  An error
 	// is returned if the request attempts to cancel a Session that does
 	// not exist.
@@ -59,6 +65,15 @@ func (c *sessionServiceClient) ListSessions(ctx context.Context, in *ListSession
 	return out, nil
 }
 
+func (c *sessionServiceClient) CountSessions(ctx context.Context, in *CountSessionsRequest, opts ...grpc.CallOption) (*CountSessionsResponse, error) {
+	out := new(CountSessionsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.SessionService/CountSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *sessionServiceClient) CancelSession(ctx context.Context, in *CancelSessionRequest, opts ...grpc.CallOption) (*CancelSessionResponse, error) {
 	out := new(CancelSessionResponse)
 	err := c.cc.Invoke(ctx, "/controller.api.services.v1.SessionService/CancelSession", in, out, opts...)
@@ -80,6 +95,12 @@ type SessionServiceServer interface {
 	// the Sessions being retrieved. If the scope ID is missing, malformed, or
 	// reference a non existing scope, an error is returned.
 	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// CountSessions returns the number of Sessions inside the scope referenced
+	// inside the request by their status, without listing them. Callers who
+	// can only read their own Sessions only have their own counted. If the
+	// scope ID is missing, malformed, or reference a non existing scope, an
+	// error is returned.
+	CountSessions(context.Context, *CountSessionsRequest) (*CountSessionsResponse, error)
 	// CancelSession cancels an existing Session in boundary.  An error
 	// is returned if the request attempts to cancel a Session that does
 	// not exist.
@@ -96,6 +117,9 @@ func (*UnimplementedSessionServiceServer) GetSession(context.Context, *GetSessio
 func (*UnimplementedSessionServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
 }
+func (*UnimplementedSessionServiceServer) CountSessions(context.Context, *CountSessionsRequest) (*CountSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountSessions not implemented")
+}
 func (*UnimplementedSessionServiceServer) CancelSession(context.Context, *CancelSessionRequest) (*CancelSessionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelSession not implemented")
 }
@@ -140,6 +164,24 @@ func _SessionService_ListSessions_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SessionService_CountSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).CountSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.SessionService/CountSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).CountSessions(ctx, req.(*CountSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _SessionService_CancelSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CancelSessionRequest)
 	if err := dec(in); err != nil {
@@ -170,6 +212,10 @@ var _SessionService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListSessions",
 			Handler:    _SessionService_ListSessions_Handler,
 		},
+		{
+			MethodName: "CountSessions",
+			Handler:    _SessionService_CountSessions_Handler,
+		},
 		{
 			MethodName: "CancelSession",
 			Handler:    _SessionService_CancelSession_Handler,
@@ -27,6 +27,10 @@ type TargetServiceClient interface {
 	// the Targets being retrieved. If the scope ID is missing, malformed, or
 	// reference a non existing scope, an error is returned.
 	ListTargets(ctx context.Context, in *ListTargetsRequest, opts ...grpc.CallOption) (*ListTargetsResponse, error)
+	// CountTargets returns the number of Targets inside the project referenced
+	// inside the request without listing them. If the scope ID is missing,
+	// malformed, or reference a non existing scope, an error is returned.
+	CountTargets(ctx context.Context, in *CountTargetsRequest, opts ...grpc.CallOption) (*CountTargetsResponse, error)
 	// CreateTarget creates and stores a Target in boundary.  The provided
 	// request must include the scope id in which the Target will be created.
 	// If the scope id is missing, malformed or references a non existing
@@ -98,6 +102,15 @@ func (c *targetServiceClient) ListTargets(ctx context.Context, in *ListTargetsRe
 	return out, nil
 }
 
+func (c *targetServiceClient) CountTargets(ctx context.Context, in *CountTargetsRequest, opts ...grpc.CallOption) (*CountTargetsResponse, error) {
+	out := new(CountTargetsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/CountTargets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *targetServiceClient) CreateTarget(ctx context.Context, in *CreateTargetRequest, opts ...grpc.CallOption) (*CreateTargetResponse, error) {
 	out := new(CreateTargetResponse)
 	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/CreateTarget", in, out, opts...)
@@ -173,6 +186,10 @@ type TargetServiceServer interface {
 	// the Targets being retrieved. If the scope ID is missing, malformed, or
 	// reference a non existing scope, an error is returned.
 	ListTargets(context.Context, *ListTargetsRequest) (*ListTargetsResponse, error)
+	// CountTargets returns the number of Targets inside the project referenced
+	// inside the request without listing them. If the scope ID is missing,
+	// malformed, or reference a non existing scope, an error is returned.
+	CountTargets(context.Context, *CountTargetsRequest) (*CountTargetsResponse, error)
 	// CreateTarget creates and stores a Target in boundary.  The provided
 	// request must include the scope id in which the Target will be created.
 	// If the scope id is missing, malformed or references a non existing
@@ -228,6 +245,9 @@ func (*UnimplementedTargetServiceServer) GetTarget(context.Context, *GetTargetRe
 func (*UnimplementedTargetServiceServer) ListTargets(context.Context, *ListTargetsRequest) (*ListTargetsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListTargets not implemented")
 }
+func (*UnimplementedTargetServiceServer) CountTargets(context.Context, *CountTargetsRequest) (*CountTargetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountTargets not implemented")
+}
 func (*UnimplementedTargetServiceServer) CreateTarget(context.Context, *CreateTargetRequest) (*CreateTargetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateTarget not implemented")
 }
@@ -290,6 +310,24 @@ func _TargetService_ListTargets_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TargetService_CountTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountTargetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).CountTargets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/CountTargets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).CountTargets(ctx, req.(*CountTargetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TargetService_CreateTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateTargetRequest)
 	if err := dec(in); err != nil {
@@ -428,6 +466,10 @@ var _TargetService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListTargets",
 			Handler:    _TargetService_ListTargets_Handler,
 		},
+		{
+			MethodName: "CountTargets",
+			Handler:    _TargetService_CountTargets_Handler,
+		},
 		{
 			MethodName: "CreateTarget",
 			Handler:    _TargetService_CreateTarget_Handler,
@@ -21,6 +21,19 @@ type ServerCoordinationServiceClient interface {
 	// returns the status response which includes the changes the controller would like to make to
 	// jobs as well as provide a list of the controllers in the system.
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// SetWorkerTags replaces the tags set at runtime for the worker making the
+	// request. Unlike the tags in the worker's configuration they are kept when
+	// the worker reports its status, and apply to worker filters from the next
+	// session authorization.
+	SetWorkerTags(ctx context.Context, in *SetWorkerTagsRequest, opts ...grpc.CallOption) (*SetWorkerTagsResponse, error)
+	// RegisterWorkerTargets replaces the targets registered by the worker making
+	// the request with a target for each of the targets given, which are
+	// defined in its configuration. Sessions for them are only proxied by the
+	// worker.
+	RegisterWorkerTargets(ctx context.Context, in *RegisterWorkerTargetsRequest, opts ...grpc.CallOption) (*RegisterWorkerTargetsResponse, error)
+	// DeregisterWorkerTargets deletes the targets registered by the worker
+	// making the request.
+	DeregisterWorkerTargets(ctx context.Context, in *DeregisterWorkerTargetsRequest, opts ...grpc.CallOption) (*DeregisterWorkerTargetsResponse, error)
 }
 
 type serverCoordinationServiceClient struct {
@@ -40,12 +53,52 @@ func (c *serverCoordinationServiceClient) Status(ctx context.Context, in *Status
 	return out, nil
 }
 
+func (c *serverCoordinationServiceClient) SetWorkerTags(ctx context.Context, in *SetWorkerTagsRequest, opts ...grpc.CallOption) (*SetWorkerTagsResponse, error) {
+	out := new(SetWorkerTagsResponse)
+	err := c.cc.Invoke(ctx, "/controller.servers.services.v1.ServerCoordinationService/SetWorkerTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverCoordinationServiceClient) RegisterWorkerTargets(ctx context.Context, in *RegisterWorkerTargetsRequest, opts ...grpc.CallOption) (*RegisterWorkerTargetsResponse, error) {
+	out := new(RegisterWorkerTargetsResponse)
+	err := c.cc.Invoke(ctx, "/controller.servers.services.v1.ServerCoordinationService/RegisterWorkerTargets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverCoordinationServiceClient) DeregisterWorkerTargets(ctx context.Context, in *DeregisterWorkerTargetsRequest, opts ...grpc.CallOption) (*DeregisterWorkerTargetsResponse, error) {
+	out := new(DeregisterWorkerTargetsResponse)
+	err := c.cc.Invoke(ctx, "/controller.servers.services.v1.ServerCoordinationService/DeregisterWorkerTargets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ServerCoordinationServiceServer is the server API for ServerCoordinationService service.
 type ServerCoordinationServiceServer interface {
 	// Status gets worker status requests which include the ongoing jobs the worker is handling and
 	// returns the status response which includes the changes the controller would like to make to
 	// jobs as well as provide a list of the controllers in the system.
 	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	// SetWorkerTags replaces the tags set at runtime for the worker making the
+	// request. Unlike the tags in the worker's configuration they are kept when
+	// the worker reports its status, and apply to worker filters from the next
+	// session authorization.
+	SetWorkerTags(context.Context, *SetWorkerTagsRequest) (*SetWorkerTagsResponse, error)
+	// RegisterWorkerTargets replaces the targets registered by the worker making
+	// the request with a target for each of the targets given, which are
+	// defined in its configuration. Sessions for them are only proxied by the
+	// worker.
+	RegisterWorkerTargets(context.Context, *RegisterWorkerTargetsRequest) (*RegisterWorkerTargetsResponse, error)
+	// DeregisterWorkerTargets deletes the targets registered by the worker
+	// making the request.
+	DeregisterWorkerTargets(context.Context, *DeregisterWorkerTargetsRequest) (*DeregisterWorkerTargetsResponse, error)
 }
 
 // UnimplementedServerCoordinationServiceServer can be embedded to have forward compatible implementations.
@@ -55,6 +108,15 @@ type UnimplementedServerCoordinationServiceServer struct {
 func (*UnimplementedServerCoordinationServiceServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (*UnimplementedServerCoordinationServiceServer) SetWorkerTags(context.Context, *SetWorkerTagsRequest) (*SetWorkerTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetWorkerTags not implemented")
+}
+func (*UnimplementedServerCoordinationServiceServer) RegisterWorkerTargets(context.Context, *RegisterWorkerTargetsRequest) (*RegisterWorkerTargetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWorkerTargets not implemented")
+}
+func (*UnimplementedServerCoordinationServiceServer) DeregisterWorkerTargets(context.Context, *DeregisterWorkerTargetsRequest) (*DeregisterWorkerTargetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeregisterWorkerTargets not implemented")
+}
 
 func RegisterServerCoordinationServiceServer(s *grpc.Server, srv ServerCoordinationServiceServer) {
 	s.RegisterService(&_ServerCoordinationService_serviceDesc, srv)
@@ -78,6 +140,60 @@ func _ServerCoordinationService_Status_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ServerCoordinationService_SetWorkerTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetWorkerTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerCoordinationServiceServer).SetWorkerTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.servers.services.v1.ServerCoordinationService/SetWorkerTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerCoordinationServiceServer).SetWorkerTags(ctx, req.(*SetWorkerTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerCoordinationService_RegisterWorkerTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWorkerTargetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerCoordinationServiceServer).RegisterWorkerTargets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.servers.services.v1.ServerCoordinationService/RegisterWorkerTargets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerCoordinationServiceServer).RegisterWorkerTargets(ctx, req.(*RegisterWorkerTargetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerCoordinationService_DeregisterWorkerTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeregisterWorkerTargetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerCoordinationServiceServer).DeregisterWorkerTargets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.servers.services.v1.ServerCoordinationService/DeregisterWorkerTargets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerCoordinationServiceServer).DeregisterWorkerTargets(ctx, req.(*DeregisterWorkerTargetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ServerCoordinationService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.servers.services.v1.ServerCoordinationService",
 	HandlerType: (*ServerCoordinationServiceServer)(nil),
@@ -86,6 +202,18 @@ var _ServerCoordinationService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Status",
 			Handler:    _ServerCoordinationService_Status_Handler,
 		},
+		{
+			MethodName: "SetWorkerTags",
+			Handler:    _ServerCoordinationService_SetWorkerTags_Handler,
+		},
+		{
+			MethodName: "RegisterWorkerTargets",
+			Handler:    _ServerCoordinationService_RegisterWorkerTargets_Handler,
+		},
+		{
+			MethodName: "DeregisterWorkerTargets",
+			Handler:    _ServerCoordinationService_DeregisterWorkerTargets_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/servers/services/v1/server_coordination_service.proto",
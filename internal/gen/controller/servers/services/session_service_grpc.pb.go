@@ -28,6 +28,9 @@ type SessionServiceClient interface {
 	ConnectConnection(ctx context.Context, in *ConnectConnectionRequest, opts ...grpc.CallOption) (*ConnectConnectionResponse, error)
 	// CloseConnections updates a connection to set it to closed
 	CloseConnection(ctx context.Context, in *CloseConnectionRequest, opts ...grpc.CallOption) (*CloseConnectionResponse, error)
+	// ReportEgressViolation records that a worker refused to dial an address
+	// for a session because it isn't the session's endpoint.
+	ReportEgressViolation(ctx context.Context, in *ReportEgressViolationRequest, opts ...grpc.CallOption) (*ReportEgressViolationResponse, error)
 }
 
 type sessionServiceClient struct {
@@ -83,6 +86,15 @@ func (c *sessionServiceClient) CloseConnection(ctx context.Context, in *CloseCon
 	return out, nil
 }
 
+func (c *sessionServiceClient) ReportEgressViolation(ctx context.Context, in *ReportEgressViolationRequest, opts ...grpc.CallOption) (*ReportEgressViolationResponse, error) {
+	out := new(ReportEgressViolationResponse)
+	err := c.cc.Invoke(ctx, "/controller.servers.services.v1.SessionService/ReportEgressViolation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SessionServiceServer is the server API for SessionService service.
 type SessionServiceServer interface {
 	// GetSession allows a worker to retrieve session information from the
@@ -96,6 +108,9 @@ type SessionServiceServer interface {
 	ConnectConnection(context.Context, *ConnectConnectionRequest) (*ConnectConnectionResponse, error)
 	// CloseConnections updates a connection to set it to closed
 	CloseConnection(context.Context, *CloseConnectionRequest) (*CloseConnectionResponse, error)
+	// ReportEgressViolation records that a worker refused to dial an address
+	// for a session because it isn't the session's endpoint.
+	ReportEgressViolation(context.Context, *ReportEgressViolationRequest) (*ReportEgressViolationResponse, error)
 }
 
 // UnimplementedSessionServiceServer can be embedded to have forward compatible implementations.
@@ -117,6 +132,9 @@ func (*UnimplementedSessionServiceServer) ConnectConnection(context.Context, *Co
 func (*UnimplementedSessionServiceServer) CloseConnection(context.Context, *CloseConnectionRequest) (*CloseConnectionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CloseConnection not implemented")
 }
+func (*UnimplementedSessionServiceServer) ReportEgressViolation(context.Context, *ReportEgressViolationRequest) (*ReportEgressViolationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportEgressViolation not implemented")
+}
 
 func RegisterSessionServiceServer(s *grpc.Server, srv SessionServiceServer) {
 	s.RegisterService(&_SessionService_serviceDesc, srv)
@@ -212,6 +230,24 @@ func _SessionService_CloseConnection_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SessionService_ReportEgressViolation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportEgressViolationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).ReportEgressViolation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.servers.services.v1.SessionService/ReportEgressViolation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).ReportEgressViolation(ctx, req.(*ReportEgressViolationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SessionService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.servers.services.v1.SessionService",
 	HandlerType: (*SessionServiceServer)(nil),
@@ -236,6 +272,10 @@ var _SessionService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CloseConnection",
 			Handler:    _SessionService_CloseConnection_Handler,
 		},
+		{
+			MethodName: "ReportEgressViolation",
+			Handler:    _SessionService_ReportEgressViolation_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/servers/services/v1/session_service.proto",
@@ -0,0 +1,134 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// WorkerAuthServiceClient is the client API for WorkerAuthService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WorkerAuthServiceClient interface {
+	// RegisterWorker allows a worker to exchange an activation token for a
+	// certificate it can use to authenticate to controllers. This is the only
+	// call available on a connection made for registration.
+	RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error)
+	// RotateCertificate allows a worker authenticated with a certificate to
+	// exchange it for a new one. The certificate used to authenticate the
+	// connection is revoked.
+	RotateCertificate(ctx context.Context, in *RotateCertificateRequest, opts ...grpc.CallOption) (*RotateCertificateResponse, error)
+}
+
+type workerAuthServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkerAuthServiceClient(cc grpc.ClientConnInterface) WorkerAuthServiceClient {
+	return &workerAuthServiceClient{cc}
+}
+
+func (c *workerAuthServiceClient) RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error) {
+	out := new(RegisterWorkerResponse)
+	err := c.cc.Invoke(ctx, "/controller.servers.services.v1.WorkerAuthService/RegisterWorker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerAuthServiceClient) RotateCertificate(ctx context.Context, in *RotateCertificateRequest, opts ...grpc.CallOption) (*RotateCertificateResponse, error) {
+	out := new(RotateCertificateResponse)
+	err := c.cc.Invoke(ctx, "/controller.servers.services.v1.WorkerAuthService/RotateCertificate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkerAuthServiceServer is the server API for WorkerAuthService service.
+type WorkerAuthServiceServer interface {
+	// RegisterWorker allows a worker to exchange an activation token for a
+	// certificate it can use to authenticate to controllers. This is the only
+	// call available on a connection made for registration.
+	RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error)
+	// RotateCertificate allows a worker authenticated with a certificate to
+	// exchange it for a new one. The certificate used to authenticate the
+	// connection is revoked.
+	RotateCertificate(context.Context, *RotateCertificateRequest) (*RotateCertificateResponse, error)
+}
+
+// UnimplementedWorkerAuthServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedWorkerAuthServiceServer struct {
+}
+
+func (*UnimplementedWorkerAuthServiceServer) RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWorker not implemented")
+}
+func (*UnimplementedWorkerAuthServiceServer) RotateCertificate(context.Context, *RotateCertificateRequest) (*RotateCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateCertificate not implemented")
+}
+
+func RegisterWorkerAuthServiceServer(s *grpc.Server, srv WorkerAuthServiceServer) {
+	s.RegisterService(&_WorkerAuthService_serviceDesc, srv)
+}
+
+func _WorkerAuthService_RegisterWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerAuthServiceServer).RegisterWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.servers.services.v1.WorkerAuthService/RegisterWorker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerAuthServiceServer).RegisterWorker(ctx, req.(*RegisterWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkerAuthService_RotateCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerAuthServiceServer).RotateCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.servers.services.v1.WorkerAuthService/RotateCertificate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerAuthServiceServer).RotateCertificate(ctx, req.(*RotateCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WorkerAuthService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "controller.servers.services.v1.WorkerAuthService",
+	HandlerType: (*WorkerAuthServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterWorker",
+			Handler:    _WorkerAuthService_RegisterWorker_Handler,
+		},
+		{
+			MethodName: "RotateCertificate",
+			Handler:    _WorkerAuthService_RotateCertificate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "controller/servers/services/v1/worker_auth_service.proto",
+}
@@ -17,6 +17,7 @@ type options struct {
 	withName        string
 	withDescription string
 	withLimit       int
+	withOrder       string
 	withAddress     string
 	withPublicId    string
 }
@@ -52,6 +53,14 @@ func WithLimit(l int) Option {
 	}
 }
 
+// WithOrder provides an option to order the results of a listing by a column
+// of the listed resources, such as "create_time desc".
+func WithOrder(order string) Option {
+	return func(o *options) {
+		o.withOrder = order
+	}
+}
+
 // WithAddress provides an optional address.
 func WithAddress(address string) Option {
 	return func(o *options) {
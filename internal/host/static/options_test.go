@@ -38,4 +38,11 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withPublicId = "test"
 		assert.Equal(t, opts, testOpts)
 	})
+	t.Run("WithOrder", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithOrder("create_time desc"))
+		testOpts := getDefaultOptions()
+		testOpts.withOrder = "create_time desc"
+		assert.Equal(opts, testOpts)
+	})
 }
@@ -3,7 +3,7 @@ package static
 import (
 	"fmt"
 
-	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/types/publicid"
 )
 
 // PublicId prefixes for the resources in the static package.
@@ -14,7 +14,7 @@ const (
 )
 
 func newHostCatalogId() (string, error) {
-	id, err := db.NewPublicId(HostCatalogPrefix)
+	id, err := publicid.New(HostCatalogPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new host catalog id: %w", err)
 	}
@@ -22,7 +22,7 @@ func newHostCatalogId() (string, error) {
 }
 
 func newHostId() (string, error) {
-	id, err := db.NewPublicId(HostPrefix)
+	id, err := publicid.New(HostPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new host id: %w", err)
 	}
@@ -30,7 +30,7 @@ func newHostId() (string, error) {
 }
 
 func newHostSetId() (string, error) {
-	id, err := db.NewPublicId(HostSetPrefix)
+	id, err := publicid.New(HostSetPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new host set id: %w", err)
 	}
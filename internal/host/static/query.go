@@ -42,5 +42,12 @@ final (action, host_id) as (
 )
 select * from final
 order by action, host_id;
+`
+
+	catalogHostNamesQuery = `
+select name
+  from static_host
+ where catalog_id = $1
+   and name is not null;
 `
 )
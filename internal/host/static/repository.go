@@ -1,6 +1,7 @@
 package static
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/boundary/internal/db"
@@ -18,6 +19,30 @@ type Repository struct {
 	defaultLimit int
 }
 
+// Repo is the subset of Repository used to create, look up, list and delete
+// host catalogs, hosts and host sets, and to add hosts to sets.
+// statictest.Repo implements it in memory for tests which don't need a
+// database.
+type Repo interface {
+	CreateCatalog(ctx context.Context, c *HostCatalog, opt ...Option) (*HostCatalog, error)
+	LookupCatalog(ctx context.Context, id string, opt ...Option) (*HostCatalog, error)
+	ListCatalogs(ctx context.Context, scopeId string, opt ...Option) ([]*HostCatalog, error)
+	DeleteCatalog(ctx context.Context, id string, opt ...Option) (int, error)
+
+	CreateHost(ctx context.Context, scopeId string, h *Host, opt ...Option) (*Host, error)
+	LookupHost(ctx context.Context, publicId string, opt ...Option) (*Host, error)
+	ListHosts(ctx context.Context, catalogId string, opt ...Option) ([]*Host, error)
+	DeleteHost(ctx context.Context, scopeId string, publicId string, opt ...Option) (int, error)
+
+	CreateSet(ctx context.Context, scopeId string, s *HostSet, opt ...Option) (*HostSet, error)
+	LookupSet(ctx context.Context, publicId string, opt ...Option) (*HostSet, []*Host, error)
+	ListSets(ctx context.Context, catalogId string, opt ...Option) ([]*HostSet, error)
+	DeleteSet(ctx context.Context, scopeId string, publicId string, opt ...Option) (int, error)
+	AddSetMembers(ctx context.Context, scopeId string, setId string, version uint32, hostIds []string, opt ...Option) ([]*Host, error)
+}
+
+var _ Repo = (*Repository)(nil)
+
 // NewRepository creates a new Repository. The returned repository should
 // only be used for one transaction and it is not safe for concurrent go
 // routines to access it. WithLimit option is used as a repo wide default
@@ -65,7 +65,10 @@ func (r *Repository) CreateHost(ctx context.Context, scopeId string, h *Host, op
 
 	var newHost *Host
 	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
-		func(_ db.Reader, w db.Writer) error {
+		func(reader db.Reader, w db.Writer) error {
+			if err := db.CheckScope(ctx, reader, scopeId, h.CatalogId); err != nil {
+				return err
+			}
 			newHost = h.clone()
 			return w.Create(ctx, newHost, db.WithOplog(oplogWrapper, h.oplog(oplog.OpType_OP_TYPE_CREATE)))
 		},
@@ -80,6 +83,10 @@ func (r *Repository) CreateHost(ctx context.Context, scopeId string, h *Host, op
 			return nil, fmt.Errorf("create: static host: in catalog: %s: %q: %w",
 				h.CatalogId, h.Address, ErrInvalidAddress)
 		}
+		if db.IsForeignKeyError(err) {
+			return nil, fmt.Errorf("create: static host: catalog %s not found: %w",
+				h.CatalogId, db.ErrInvalidParameter)
+		}
 		return nil, fmt.Errorf("create: static host: in catalog: %s: %w", h.CatalogId, err)
 	}
 	return newHost, nil
@@ -193,8 +200,8 @@ func (r *Repository) LookupHost(ctx context.Context, publicId string, opt ...Opt
 	return h, nil
 }
 
-// ListHosts returns a slice of Hosts for the catalogId.
-// WithLimit is the only option supported.
+// ListHosts returns a slice of Hosts for the catalogId. Supports the WithLimit
+// and WithOrder options.
 func (r *Repository) ListHosts(ctx context.Context, catalogId string, opt ...Option) ([]*Host, error) {
 	if catalogId == "" {
 		return nil, fmt.Errorf("list: static host: missing catalog id: %w", db.ErrInvalidParameter)
@@ -206,7 +213,7 @@ func (r *Repository) ListHosts(ctx context.Context, catalogId string, opt ...Opt
 		limit = opts.withLimit
 	}
 	var hosts []*Host
-	err := r.reader.SearchWhere(ctx, &hosts, "catalog_id = ?", []interface{}{catalogId}, db.WithLimit(limit))
+	err := r.reader.SearchWhere(ctx, &hosts, "catalog_id = ?", []interface{}{catalogId}, db.WithLimit(limit), db.WithOrder(opts.withOrder))
 	if err != nil {
 		return nil, fmt.Errorf("list: static host: %w", err)
 	}
@@ -0,0 +1,159 @@
+package static
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+)
+
+const (
+	// MaxImportHosts is the most hosts which can be imported at once.
+	MaxImportHosts = 5000
+
+	// importBatchSize is the most hosts created in one transaction.
+	importBatchSize = 100
+)
+
+// ImportHosts creates hosts in catalogId and returns the hosts it created.
+// hosts are not changed. Each host is validated as CreateHost would, and hosts
+// with a name used by an existing host in the catalog or by an earlier host
+// in hosts are not created. The remaining hosts are created in batches, each
+// in its own transaction, so a batch which fails doesn't undo the batches
+// before it; every host of a failed batch gets the batch's error. If any hosts
+// weren't created, the hosts which were are returned along with an
+// *errors.Multi with an ItemError for each host which wasn't, whose Index is
+// the host's index in hosts. Any other error means no hosts were created. At
+// most MaxImportHosts hosts can be imported at once. All options are ignored.
+func (r *Repository) ImportHosts(ctx context.Context, scopeId, catalogId string, hosts []*Host, opt ...Option) ([]*Host, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("import: static hosts: no scopeId: %w", db.ErrInvalidParameter)
+	}
+	if catalogId == "" {
+		return nil, fmt.Errorf("import: static hosts: no catalog id: %w", db.ErrInvalidParameter)
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("import: static hosts: no hosts: %w", db.ErrInvalidParameter)
+	}
+	if len(hosts) > MaxImportHosts {
+		return nil, fmt.Errorf("import: static hosts: more than %d hosts: %w", MaxImportHosts, db.ErrInvalidParameter)
+	}
+	if err := db.CheckScope(ctx, r.reader, scopeId, catalogId); err != nil {
+		return nil, fmt.Errorf("import: static hosts: %w", err)
+	}
+	names, err := r.catalogHostNames(ctx, catalogId)
+	if err != nil {
+		return nil, fmt.Errorf("import: static hosts: %w", err)
+	}
+
+	importErrs := &errors.Multi{}
+	var valid []*Host
+	var rows []int
+	for i, h := range hosts {
+		nh, err := newImportHost(catalogId, h)
+		if err == nil && nh.Name != "" {
+			if names[nh.Name] {
+				err = fmt.Errorf("name %s already exists: %w", nh.Name, db.ErrNotUnique)
+			}
+			names[nh.Name] = true
+		}
+		if err != nil {
+			importErrs.Append(i, "", err)
+			continue
+		}
+		valid = append(valid, nh)
+		rows = append(rows, i)
+	}
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, fmt.Errorf("import: static hosts: unable to get oplog wrapper: %w", err)
+	}
+
+	var created []*Host
+	for start := 0; start < len(valid); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+		items := make([]interface{}, 0, len(batch))
+		ids := make([]string, 0, len(batch))
+		for _, h := range batch {
+			items = append(items, h)
+			ids = append(ids, h.PublicId)
+		}
+		metadata := oplog.Metadata{
+			"resource-public-id": ids,
+			"resource-type":      []string{"static-host"},
+			"op-type":            []string{oplog.OpType_OP_TYPE_CREATE.String()},
+			"catalog-id":         []string{catalogId},
+		}
+		_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+			func(_ db.Reader, w db.Writer) error {
+				return w.CreateItems(ctx, items, db.WithOplog(oplogWrapper, metadata))
+			},
+		)
+		if err != nil {
+			if db.IsUniqueError(err) {
+				err = fmt.Errorf("name already exists: %w", db.ErrNotUnique)
+			}
+			for _, row := range rows[start:end] {
+				importErrs.Append(row, "", err)
+			}
+			continue
+		}
+		created = append(created, batch...)
+	}
+	if err := importErrs.ErrorOrNil(); err != nil {
+		return created, fmt.Errorf("import: static hosts: %w", err)
+	}
+	return created, nil
+}
+
+// newImportHost returns a copy of h, in catalogId and with a new PublicId,
+// or an error if h can't be created.
+func newImportHost(catalogId string, h *Host) (*Host, error) {
+	switch {
+	case h == nil || h.Host == nil:
+		return nil, fmt.Errorf("missing host: %w", db.ErrInvalidParameter)
+	case h.PublicId != "":
+		return nil, fmt.Errorf("public id not empty: %w", db.ErrInvalidParameter)
+	case h.CatalogId != "" && h.CatalogId != catalogId:
+		return nil, fmt.Errorf("host is in catalog %s: %w", h.CatalogId, db.ErrInvalidParameter)
+	}
+	h = h.clone()
+	h.CatalogId = catalogId
+	h.Address = strings.TrimSpace(h.Address)
+	if len(h.Address) < MinHostAddressLength || len(h.Address) > MaxHostAddressLength {
+		return nil, fmt.Errorf("bad address: %w", ErrInvalidAddress)
+	}
+	id, err := newHostId()
+	if err != nil {
+		return nil, err
+	}
+	h.PublicId = id
+	return h, nil
+}
+
+// catalogHostNames returns the names of the hosts in catalogId.
+func (r *Repository) catalogHostNames(ctx context.Context, catalogId string) (map[string]bool, error) {
+	rows, err := r.reader.Query(ctx, catalogHostNamesQuery, []interface{}{catalogId})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list host names: %w", err)
+	}
+	defer rows.Close()
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("unable to list host names: %w", err)
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
@@ -0,0 +1,110 @@
+package static
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/host/static/store"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ImportHosts(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, prj := iam.TestScopes(t, iamRepo)
+	catalogs := TestCatalogs(t, conn, prj.PublicId, 2)
+	catalog, otherCatalog := catalogs[0], catalogs[1]
+
+	existing, err := NewHost(catalog.PublicId, WithName("existing"), WithAddress("10.0.0.1"))
+	require.NoError(t, err)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	_, err = repo.CreateHost(context.Background(), prj.PublicId, existing)
+	require.NoError(t, err)
+
+	newHost := func(name, address string) *Host {
+		return &Host{Host: &store.Host{Name: name, Address: address}}
+	}
+
+	t.Run("invalid-args", func(t *testing.T) {
+		assert := assert.New(t)
+		ctx := context.Background()
+		hosts := []*Host{newHost("", "10.0.0.2")}
+
+		_, err := repo.ImportHosts(ctx, "", catalog.PublicId, hosts)
+		assert.Truef(errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		_, err = repo.ImportHosts(ctx, prj.PublicId, "", hosts)
+		assert.Truef(errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		_, err = repo.ImportHosts(ctx, prj.PublicId, catalog.PublicId, nil)
+		assert.Truef(errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		_, err = repo.ImportHosts(ctx, prj.PublicId, catalog.PublicId, make([]*Host, MaxImportHosts+1))
+		assert.Truef(errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+	})
+
+	t.Run("per-row-errors", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		hosts := []*Host{
+			newHost("web-1", " 10.0.1.1 "),
+			newHost("existing", "10.0.1.2"),
+			newHost("web-1", "10.0.1.3"),
+			newHost("", "a"),
+			nil,
+			{Host: &store.Host{CatalogId: otherCatalog.PublicId, Address: "10.0.1.4"}},
+			newHost("", "10.0.1.5"),
+		}
+		created, err := repo.ImportHosts(context.Background(), prj.PublicId, catalog.PublicId, hosts)
+		var importErrs *errors.Multi
+		require.True(errors.As(err, &importErrs))
+		require.Len(created, 2)
+		assert.Equal("web-1", created[0].Name)
+		assert.Equal("10.0.1.1", created[0].Address)
+		assert.Equal(catalog.PublicId, created[0].CatalogId)
+		assert.NotEmpty(created[0].PublicId)
+		assert.Equal("10.0.1.5", created[1].Address)
+
+		rows := make(map[int]error)
+		for _, e := range importErrs.Errors {
+			rows[e.Index] = e.Err
+		}
+		assert.Len(rows, 5)
+		assert.True(errors.Is(rows[1], db.ErrNotUnique))
+		assert.True(errors.Is(rows[2], db.ErrNotUnique))
+		assert.True(errors.Is(rows[3], ErrInvalidAddress))
+		assert.True(errors.Is(rows[4], db.ErrInvalidParameter))
+		assert.True(errors.Is(rows[5], db.ErrInvalidParameter))
+
+		for _, h := range created {
+			got, err := repo.LookupHost(context.Background(), h.PublicId)
+			require.NoError(err)
+			require.NotNil(got)
+			assert.Equal(h.Address, got.Address)
+			assert.NoError(db.TestVerifyOplog(t, rw, h.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+		}
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		var hosts []*Host
+		for i := 0; i < importBatchSize*2+1; i++ {
+			hosts = append(hosts, newHost(fmt.Sprintf("batch-%d", i), fmt.Sprintf("10.1.%d.%d", i/256, i%256)))
+		}
+		created, err := repo.ImportHosts(context.Background(), prj.PublicId, otherCatalog.PublicId, hosts)
+		require.NoError(err)
+		assert.Len(created, len(hosts))
+
+		listed, err := repo.ListHosts(context.Background(), otherCatalog.PublicId, WithLimit(-1))
+		require.NoError(err)
+		assert.Len(listed, len(hosts))
+	})
+}
@@ -59,7 +59,10 @@ func (r *Repository) CreateSet(ctx context.Context, scopeId string, s *HostSet,
 
 	var newHostSet *HostSet
 	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
-		func(_ db.Reader, w db.Writer) error {
+		func(reader db.Reader, w db.Writer) error {
+			if err := db.CheckScope(ctx, reader, scopeId, s.CatalogId); err != nil {
+				return err
+			}
 			newHostSet = s.clone()
 			return w.Create(ctx, newHostSet, db.WithOplog(oplogWrapper, s.oplog(oplog.OpType_OP_TYPE_CREATE)))
 		},
@@ -70,6 +73,10 @@ func (r *Repository) CreateSet(ctx context.Context, scopeId string, s *HostSet,
 			return nil, fmt.Errorf("create: static host set: in catalog: %s: name %s already exists: %w",
 				s.CatalogId, s.Name, db.ErrNotUnique)
 		}
+		if db.IsForeignKeyError(err) {
+			return nil, fmt.Errorf("create: static host set: catalog %s not found: %w",
+				s.CatalogId, db.ErrInvalidParameter)
+		}
 		return nil, fmt.Errorf("create: static host set: in catalog: %s: %w", s.CatalogId, err)
 	}
 	return newHostSet, nil
@@ -210,8 +217,8 @@ func (r *Repository) LookupSet(ctx context.Context, publicId string, opt ...Opti
 	return s, hosts, nil
 }
 
-// ListSets returns a slice of HostSets for the catalogId. WithLimit is the
-// only option supported.
+// ListSets returns a slice of HostSets for the catalogId. Supports the
+// WithLimit and WithOrder options.
 func (r *Repository) ListSets(ctx context.Context, catalogId string, opt ...Option) ([]*HostSet, error) {
 	if catalogId == "" {
 		return nil, fmt.Errorf("list: static host set: missing catalog id: %w", db.ErrInvalidParameter)
@@ -223,7 +230,7 @@ func (r *Repository) ListSets(ctx context.Context, catalogId string, opt ...Opti
 		limit = opts.withLimit
 	}
 	var sets []*HostSet
-	err := r.reader.SearchWhere(ctx, &sets, "catalog_id = ?", []interface{}{catalogId}, db.WithLimit(limit))
+	err := r.reader.SearchWhere(ctx, &sets, "catalog_id = ?", []interface{}{catalogId}, db.WithLimit(limit), db.WithOrder(opts.withOrder))
 	if err != nil {
 		return nil, fmt.Errorf("list: static host set: %w", err)
 	}
@@ -8,6 +8,7 @@ import (
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
 )
@@ -15,7 +16,9 @@ import (
 // AddSetMembers adds hostIds to setId in the repository. It returns a
 // slice of all hosts in setId. A host must belong to the same catalog as
 // the set to be added. The version must match the current version of the
-// setId in the repository.
+// setId in the repository. If any hosts are empty, don't exist or are in
+// another scope, the returned error wraps an *errors.Multi with an ItemError
+// for each of them, whose Index is the host's index in hostIds.
 func (r *Repository) AddSetMembers(ctx context.Context, scopeId string, setId string, version uint32, hostIds []string, opt ...Option) ([]*Host, error) {
 	if scopeId == "" {
 		return nil, fmt.Errorf("add: static host set members: missing scope id: %w", db.ErrInvalidParameter)
@@ -43,6 +46,12 @@ func (r *Repository) AddSetMembers(ctx context.Context, scopeId string, setId st
 
 	var hosts []*Host
 	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(reader db.Reader, w db.Writer) error {
+		if err := db.CheckScope(ctx, reader, scopeId, setId); err != nil {
+			return err
+		}
+		if err := db.CheckScope(ctx, reader, scopeId, hostIds...); err != nil {
+			return err
+		}
 		set := newHostSetForMembers(setId, version)
 		metadata := set.oplog(oplog.OpType_OP_TYPE_CREATE)
 
@@ -68,14 +77,19 @@ func (r *Repository) AddSetMembers(ctx context.Context, scopeId string, setId st
 
 func (r *Repository) newMembers(setId string, hostIds []string) ([]interface{}, error) {
 	var members []interface{}
-	for _, id := range hostIds {
+	var multi errors.Multi
+	for i, id := range hostIds {
 		var m *HostSetMember
 		m, err := NewHostSetMember(setId, id)
 		if err != nil {
-			return nil, fmt.Errorf("new members: %w", err)
+			multi.Append(i, id, err)
+			continue
 		}
 		members = append(members, m)
 	}
+	if err := multi.ErrorOrNil(); err != nil {
+		return nil, fmt.Errorf("new members: %w", err)
+	}
 	return members, nil
 }
 
@@ -213,7 +227,10 @@ func deleteMembers(ctx context.Context, w db.Writer, members []interface{}) ([]*
 // repository. It returns a slice of all hosts in setId and a count of
 // hosts added or deleted. A host must belong to the same catalog as the
 // set to be added. The version must match the current version of the setId
-// in the repository. If hostIds is empty, all hosts will be removed setId.
+// in the repository. If hostIds is empty, all hosts will be removed setId. If
+// any hosts to be added don't exist or are in another scope, the returned
+// error wraps an *errors.Multi with an ItemError for each of them, whose
+// Index is the host's index in hostIds.
 func (r *Repository) SetSetMembers(ctx context.Context, scopeId string, setId string, version uint32, hostIds []string, opt ...Option) ([]*Host, int, error) {
 	if scopeId == "" {
 		return nil, db.NoRowsAffected, fmt.Errorf("set: static host set members: missing scope id: %w", db.ErrInvalidParameter)
@@ -243,6 +260,7 @@ func (r *Repository) SetSetMembers(ctx context.Context, scopeId string, setId st
 		return nil, db.NoRowsAffected, fmt.Errorf("set: static host set members: %w", err)
 	}
 	var deletions, additions []interface{}
+	var addedIds []string
 	for _, c := range changes {
 		m, err := NewHostSetMember(setId, c.HostId)
 		if err != nil {
@@ -253,6 +271,7 @@ func (r *Repository) SetSetMembers(ctx context.Context, scopeId string, setId st
 			deletions = append(deletions, m)
 		case "add":
 			additions = append(additions, m)
+			addedIds = append(addedIds, c.HostId)
 		}
 	}
 
@@ -264,6 +283,12 @@ func (r *Repository) SetSetMembers(ctx context.Context, scopeId string, setId st
 		}
 
 		_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(reader db.Reader, w db.Writer) error {
+			if err := db.CheckScope(ctx, reader, scopeId, setId); err != nil {
+				return err
+			}
+			if err := db.CheckScope(ctx, reader, scopeId, addedIds...); err != nil {
+				return hostIndexes(err, hostIds)
+			}
 			set := newHostSetForMembers(setId, version)
 			metadata := set.oplog(oplog.OpType_OP_TYPE_UPDATE)
 			var msgs []*oplog.Message
@@ -304,6 +329,27 @@ func (r *Repository) SetSetMembers(ctx context.Context, scopeId string, setId st
 	return hosts, len(changes), nil
 }
 
+// hostIndexes sets the Index of each ItemError of the *errors.Multi err
+// wraps, if any, to the index of the host in hostIds, and returns err.
+func hostIndexes(err error, hostIds []string) error {
+	var multi *errors.Multi
+	if !errors.As(err, &multi) {
+		return err
+	}
+	idx := make(map[string]int, len(hostIds))
+	for i, id := range hostIds {
+		if _, ok := idx[id]; !ok {
+			idx[id] = i
+		}
+	}
+	for _, e := range multi.Errors {
+		if i, ok := idx[e.Id]; ok {
+			e.Index = i
+		}
+	}
+	return err
+}
+
 type change struct {
 	Action string
 	HostId string
@@ -0,0 +1,365 @@
+// Package statictest provides an in-memory implementation of static.Repo, so
+// that code using static host repositories can be tested without a database.
+package statictest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/host/static/store"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Repo is an in-memory static.Repo. Names are unique within a catalog's
+// scope or a host's or set's catalog, as in the database, but catalogs,
+// hosts and sets can be created in scopes and catalogs which don't exist.
+// Options are ignored. It is safe for concurrent use.
+type Repo struct {
+	l        sync.Mutex
+	catalogs map[string]*store.HostCatalog
+	hosts    map[string]*store.Host
+	sets     map[string]*store.HostSet
+	// members are the ids of the hosts in each set.
+	members map[string]map[string]bool
+}
+
+var _ static.Repo = (*Repo)(nil)
+
+// NewRepo creates an empty Repo.
+func NewRepo() *Repo {
+	return &Repo{
+		catalogs: make(map[string]*store.HostCatalog),
+		hosts:    make(map[string]*store.Host),
+		sets:     make(map[string]*store.HostSet),
+		members:  make(map[string]map[string]bool),
+	}
+}
+
+// CreateCatalog implements static.Repo.
+func (r *Repo) CreateCatalog(ctx context.Context, c *static.HostCatalog, opt ...static.Option) (*static.HostCatalog, error) {
+	switch {
+	case c == nil || c.HostCatalog == nil:
+		return nil, fmt.Errorf("create: static host catalog: %w", db.ErrInvalidParameter)
+	case c.ScopeId == "":
+		return nil, fmt.Errorf("create: static host catalog: no scope id: %w", db.ErrInvalidParameter)
+	case c.PublicId != "":
+		return nil, fmt.Errorf("create: static host catalog: public id not empty: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	for _, e := range r.catalogs {
+		if c.Name != "" && e.ScopeId == c.ScopeId && e.Name == c.Name {
+			return nil, fmt.Errorf("create: static host catalog: name %s already exists in scope %s: %w", c.Name, c.ScopeId, db.ErrNotUnique)
+		}
+	}
+	id, err := db.NewPublicId(static.HostCatalogPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create: static host catalog: %w", err)
+	}
+	n := proto.Clone(c.HostCatalog).(*store.HostCatalog)
+	n.PublicId = id
+	n.CreateTime, n.UpdateTime = now(), now()
+	r.catalogs[id] = n
+	return catalog(n), nil
+}
+
+// LookupCatalog implements static.Repo.
+func (r *Repo) LookupCatalog(ctx context.Context, id string, opt ...static.Option) (*static.HostCatalog, error) {
+	if id == "" {
+		return nil, fmt.Errorf("lookup: static host catalog: missing public id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	c, ok := r.catalogs[id]
+	if !ok {
+		return nil, nil
+	}
+	return catalog(c), nil
+}
+
+// ListCatalogs implements static.Repo.
+func (r *Repo) ListCatalogs(ctx context.Context, scopeId string, opt ...static.Option) ([]*static.HostCatalog, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list: static host catalog: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	var catalogs []*static.HostCatalog
+	for _, c := range r.catalogs {
+		if c.ScopeId == scopeId {
+			catalogs = append(catalogs, catalog(c))
+		}
+	}
+	sort.Slice(catalogs, func(i, j int) bool { return catalogs[i].PublicId < catalogs[j].PublicId })
+	return catalogs, nil
+}
+
+// DeleteCatalog implements static.Repo. The catalog's hosts and sets are
+// deleted with it.
+func (r *Repo) DeleteCatalog(ctx context.Context, id string, opt ...static.Option) (int, error) {
+	if id == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete: static host catalog: missing public id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.catalogs[id]; !ok {
+		return db.NoRowsAffected, nil
+	}
+	delete(r.catalogs, id)
+	for hid, h := range r.hosts {
+		if h.CatalogId == id {
+			r.deleteHost(hid)
+		}
+	}
+	for sid, s := range r.sets {
+		if s.CatalogId == id {
+			delete(r.sets, sid)
+			delete(r.members, sid)
+		}
+	}
+	return 1, nil
+}
+
+// CreateHost implements static.Repo.
+func (r *Repo) CreateHost(ctx context.Context, scopeId string, h *static.Host, opt ...static.Option) (*static.Host, error) {
+	switch {
+	case h == nil || h.Host == nil:
+		return nil, fmt.Errorf("create: static host: %w", db.ErrInvalidParameter)
+	case h.CatalogId == "":
+		return nil, fmt.Errorf("create: static host: no catalog id: %w", db.ErrInvalidParameter)
+	case h.PublicId != "":
+		return nil, fmt.Errorf("create: static host: public id not empty: %w", db.ErrInvalidParameter)
+	case scopeId == "":
+		return nil, fmt.Errorf("create: static host: no scopeId: %w", db.ErrInvalidParameter)
+	}
+	address := strings.TrimSpace(h.Address)
+	if len(address) < static.MinHostAddressLength || len(address) > static.MaxHostAddressLength {
+		return nil, fmt.Errorf("create: static host: bad address: %w", static.ErrInvalidAddress)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	for _, e := range r.hosts {
+		if h.Name != "" && e.CatalogId == h.CatalogId && e.Name == h.Name {
+			return nil, fmt.Errorf("create: static host: in catalog: %s: name %s already exists: %w", h.CatalogId, h.Name, db.ErrNotUnique)
+		}
+	}
+	id, err := db.NewPublicId(static.HostPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create: static host: %w", err)
+	}
+	n := proto.Clone(h.Host).(*store.Host)
+	n.PublicId = id
+	n.Address = address
+	n.CreateTime, n.UpdateTime = now(), now()
+	n.Version = 1
+	r.hosts[id] = n
+	return host(n), nil
+}
+
+// LookupHost implements static.Repo.
+func (r *Repo) LookupHost(ctx context.Context, publicId string, opt ...static.Option) (*static.Host, error) {
+	if publicId == "" {
+		return nil, fmt.Errorf("lookup: static host: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	h, ok := r.hosts[publicId]
+	if !ok {
+		return nil, nil
+	}
+	return host(h), nil
+}
+
+// ListHosts implements static.Repo.
+func (r *Repo) ListHosts(ctx context.Context, catalogId string, opt ...static.Option) ([]*static.Host, error) {
+	if catalogId == "" {
+		return nil, fmt.Errorf("list: static host: missing catalog id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	var hosts []*static.Host
+	for _, h := range r.hosts {
+		if h.CatalogId == catalogId {
+			hosts = append(hosts, host(h))
+		}
+	}
+	sortHosts(hosts)
+	return hosts, nil
+}
+
+// DeleteHost implements static.Repo. The host is removed from its sets.
+func (r *Repo) DeleteHost(ctx context.Context, scopeId string, publicId string, opt ...static.Option) (int, error) {
+	if publicId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete: static host: missing public id: %w", db.ErrInvalidParameter)
+	}
+	if scopeId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete: static host: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.hosts[publicId]; !ok {
+		return db.NoRowsAffected, nil
+	}
+	r.deleteHost(publicId)
+	return 1, nil
+}
+
+func (r *Repo) deleteHost(id string) {
+	delete(r.hosts, id)
+	for _, m := range r.members {
+		delete(m, id)
+	}
+}
+
+// CreateSet implements static.Repo.
+func (r *Repo) CreateSet(ctx context.Context, scopeId string, s *static.HostSet, opt ...static.Option) (*static.HostSet, error) {
+	switch {
+	case s == nil || s.HostSet == nil:
+		return nil, fmt.Errorf("create: static host set: %w", db.ErrInvalidParameter)
+	case s.CatalogId == "":
+		return nil, fmt.Errorf("create: static host set: no catalog id: %w", db.ErrInvalidParameter)
+	case s.PublicId != "":
+		return nil, fmt.Errorf("create: static host set: public id not empty: %w", db.ErrInvalidParameter)
+	case scopeId == "":
+		return nil, fmt.Errorf("create: static host set: no scopeId: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	for _, e := range r.sets {
+		if s.Name != "" && e.CatalogId == s.CatalogId && e.Name == s.Name {
+			return nil, fmt.Errorf("create: static host set: in catalog: %s: name %s already exists: %w", s.CatalogId, s.Name, db.ErrNotUnique)
+		}
+	}
+	id, err := db.NewPublicId(static.HostSetPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create: static host set: %w", err)
+	}
+	n := proto.Clone(s.HostSet).(*store.HostSet)
+	n.PublicId = id
+	n.CreateTime, n.UpdateTime = now(), now()
+	n.Version = 1
+	r.sets[id] = n
+	r.members[id] = make(map[string]bool)
+	return set(n), nil
+}
+
+// LookupSet implements static.Repo.
+func (r *Repo) LookupSet(ctx context.Context, publicId string, opt ...static.Option) (*static.HostSet, []*static.Host, error) {
+	if publicId == "" {
+		return nil, nil, fmt.Errorf("lookup: static host set: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	s, ok := r.sets[publicId]
+	if !ok {
+		return nil, nil, nil
+	}
+	var hosts []*static.Host
+	for id := range r.members[publicId] {
+		hosts = append(hosts, host(r.hosts[id]))
+	}
+	sortHosts(hosts)
+	return set(s), hosts, nil
+}
+
+// ListSets implements static.Repo.
+func (r *Repo) ListSets(ctx context.Context, catalogId string, opt ...static.Option) ([]*static.HostSet, error) {
+	if catalogId == "" {
+		return nil, fmt.Errorf("list: static host set: missing catalog id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	var sets []*static.HostSet
+	for _, s := range r.sets {
+		if s.CatalogId == catalogId {
+			sets = append(sets, set(s))
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].PublicId < sets[j].PublicId })
+	return sets, nil
+}
+
+// DeleteSet implements static.Repo.
+func (r *Repo) DeleteSet(ctx context.Context, scopeId string, publicId string, opt ...static.Option) (int, error) {
+	if publicId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete: static host set: missing public id: %w", db.ErrInvalidParameter)
+	}
+	if scopeId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete: static host set: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.sets[publicId]; !ok {
+		return db.NoRowsAffected, nil
+	}
+	delete(r.sets, publicId)
+	delete(r.members, publicId)
+	return 1, nil
+}
+
+// AddSetMembers implements static.Repo. Hosts must be in the set's catalog.
+func (r *Repo) AddSetMembers(ctx context.Context, scopeId string, setId string, version uint32, hostIds []string, opt ...static.Option) ([]*static.Host, error) {
+	switch {
+	case scopeId == "":
+		return nil, fmt.Errorf("add: static host set members: missing scope id: %w", db.ErrInvalidParameter)
+	case setId == "":
+		return nil, fmt.Errorf("add: static host set members: missing set id: %w", db.ErrInvalidParameter)
+	case version == 0:
+		return nil, fmt.Errorf("add: static host set members: version is zero: %w", db.ErrInvalidParameter)
+	case len(hostIds) == 0:
+		return nil, fmt.Errorf("add: static host set members: empty hostIds: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	s, ok := r.sets[setId]
+	if !ok || s.Version != version {
+		return nil, fmt.Errorf("add: static host set members: set %s version %d: %w", setId, version, db.ErrRecordNotFound)
+	}
+	for _, id := range hostIds {
+		h, ok := r.hosts[id]
+		if !ok || h.CatalogId != s.CatalogId {
+			return nil, fmt.Errorf("add: static host set members: host %s not in catalog %s: %w", id, s.CatalogId, db.ErrInvalidParameter)
+		}
+		if r.members[setId][id] {
+			return nil, fmt.Errorf("add: static host set members: host %s already in set %s: %w", id, setId, db.ErrNotUnique)
+		}
+	}
+	for _, id := range hostIds {
+		r.members[setId][id] = true
+	}
+	s.Version++
+	s.UpdateTime = now()
+	var hosts []*static.Host
+	for id := range r.members[setId] {
+		hosts = append(hosts, host(r.hosts[id]))
+	}
+	sortHosts(hosts)
+	return hosts, nil
+}
+
+func catalog(c *store.HostCatalog) *static.HostCatalog {
+	return &static.HostCatalog{HostCatalog: proto.Clone(c).(*store.HostCatalog)}
+}
+
+func host(h *store.Host) *static.Host {
+	return &static.Host{Host: proto.Clone(h).(*store.Host)}
+}
+
+func set(s *store.HostSet) *static.HostSet {
+	return &static.HostSet{HostSet: proto.Clone(s).(*store.HostSet)}
+}
+
+func sortHosts(hosts []*static.Host) {
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].PublicId < hosts[j].PublicId })
+}
+
+func now() *timestamp.Timestamp {
+	return &timestamp.Timestamp{Timestamp: timestamppb.Now()}
+}
@@ -0,0 +1,66 @@
+package statictest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	repo := NewRepo()
+	const scopeId = "p_1234567890"
+
+	c, err := static.NewHostCatalog(scopeId, static.WithName("catalog"))
+	require.NoError(err)
+	c, err = repo.CreateCatalog(ctx, c)
+	require.NoError(err)
+	db.AssertPublicId(t, static.HostCatalogPrefix, c.PublicId)
+
+	h, err := static.NewHost(c.PublicId, static.WithAddress(" 127.0.0.1 "))
+	require.NoError(err)
+	h, err = repo.CreateHost(ctx, scopeId, h)
+	require.NoError(err)
+	assert.Equal("127.0.0.1", h.Address)
+	bad, err := static.NewHost(c.PublicId, static.WithAddress("a"))
+	require.NoError(err)
+	_, err = repo.CreateHost(ctx, scopeId, bad)
+	assert.True(errors.Is(err, static.ErrInvalidAddress))
+
+	s, err := static.NewHostSet(c.PublicId)
+	require.NoError(err)
+	s, err = repo.CreateSet(ctx, scopeId, s)
+	require.NoError(err)
+	hosts, err := repo.AddSetMembers(ctx, scopeId, s.PublicId, s.Version, []string{h.PublicId})
+	require.NoError(err)
+	assert.Len(hosts, 1)
+	_, err = repo.AddSetMembers(ctx, scopeId, s.PublicId, s.Version, []string{h.PublicId})
+	assert.True(errors.Is(err, db.ErrRecordNotFound), "stale version")
+
+	gotSet, hosts, err := repo.LookupSet(ctx, s.PublicId)
+	require.NoError(err)
+	assert.Equal(s.Version+1, gotSet.Version)
+	assert.Equal([]*static.Host{h}, hosts)
+
+	// Deleting a host removes it from its sets
+	deleted, err := repo.DeleteHost(ctx, scopeId, h.PublicId)
+	require.NoError(err)
+	assert.Equal(1, deleted)
+	_, hosts, err = repo.LookupSet(ctx, s.PublicId)
+	require.NoError(err)
+	assert.Empty(hosts)
+
+	// Deleting a catalog deletes its sets
+	deleted, err = repo.DeleteCatalog(ctx, c.PublicId)
+	require.NoError(err)
+	assert.Equal(1, deleted)
+	sets, err := repo.ListSets(ctx, c.PublicId)
+	require.NoError(err)
+	assert.Empty(sets)
+}
@@ -0,0 +1,135 @@
+package iam
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+const (
+	AttributeRulePrefix = "iamar"
+)
+
+// The operators of attribute rule predicates. For attributes whose value is a
+// list, equals and contains match if any element of the list matches.
+const (
+	// AttributeExists matches accounts which have the attribute.
+	AttributeExists = "exists"
+
+	// AttributeEquals matches accounts whose attribute equals the rule's
+	// value.
+	AttributeEquals = "equals"
+
+	// AttributeContains matches accounts whose attribute contains the rule's
+	// value.
+	AttributeContains = "contains"
+)
+
+// An AttributeRule maps a predicate on the attributes of the accounts in a
+// scope to either membership of a group or a user alias. Rules are evaluated
+// every time an account in the scope authenticates, with the attributes its
+// auth method gives it. Users are added to the group of every group rule
+// which matches, and removed from the groups which rules added them to when
+// the rules no longer match. The value of a matching alias rule's
+// AliasAttribute becomes an alias of the user.
+type AttributeRule struct {
+	PublicId  string `gorm:"primary_key"`
+	ScopeId   string
+	Attribute string
+	Operator  string
+	Value     string
+
+	// Exactly one of GroupId and AliasAttribute is set.
+	GroupId        string `gorm:"default:null"`
+	AliasAttribute string `gorm:"default:null"`
+
+	CreateTime time.Time `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for attribute rules.
+func (r *AttributeRule) TableName() string {
+	return "iam_attribute_rule"
+}
+
+func newAttributeRuleId() (string, error) {
+	id, err := db.NewPublicId(AttributeRulePrefix)
+	if err != nil {
+		return "", fmt.Errorf("new attribute rule id: %w", err)
+	}
+	return id, nil
+}
+
+// Matches returns true if attrs satisfies the rule's predicate.
+func (r *AttributeRule) Matches(attrs map[string]interface{}) bool {
+	v, ok := attrs[r.Attribute]
+	if !ok {
+		return false
+	}
+	if r.Operator == AttributeExists {
+		return true
+	}
+	values, ok := v.([]interface{})
+	if !ok {
+		values = []interface{}{v}
+	}
+	for _, v := range values {
+		s, ok := attributeString(v)
+		if !ok {
+			continue
+		}
+		switch r.Operator {
+		case AttributeEquals:
+			if s == r.Value {
+				return true
+			}
+		case AttributeContains:
+			if strings.Contains(s, r.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// alias returns the alias a matching alias rule gives the user with attrs,
+// or false if the user's AliasAttribute isn't set to a single value.
+func (r *AttributeRule) alias(attrs map[string]interface{}) (string, bool) {
+	s, ok := attributeString(attrs[r.AliasAttribute])
+	if !ok || strings.TrimSpace(s) == "" {
+		return "", false
+	}
+	return s, true
+}
+
+// attributeString returns the string form of an attribute value, or false if
+// the value isn't a string, number or boolean.
+func attributeString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool, float64, int, int64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// validate returns an error if the rule is incomplete or has an unknown
+// operator.
+func (r *AttributeRule) validate() error {
+	switch {
+	case r.ScopeId == "":
+		return fmt.Errorf("missing scope id: %w", db.ErrInvalidParameter)
+	case strings.TrimSpace(r.Attribute) == "":
+		return fmt.Errorf("missing attribute: %w", db.ErrInvalidParameter)
+	case (r.GroupId == "") == (r.AliasAttribute == ""):
+		return fmt.Errorf("exactly one of group id and alias attribute must be set: %w", db.ErrInvalidParameter)
+	}
+	switch r.Operator {
+	case AttributeExists, AttributeEquals, AttributeContains:
+	default:
+		return fmt.Errorf("unknown operator %q: %w", r.Operator, db.ErrInvalidParameter)
+	}
+	return nil
+}
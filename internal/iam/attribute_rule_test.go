@@ -0,0 +1,66 @@
+package iam
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributeRule_Matches(t *testing.T) {
+	attrs := map[string]interface{}{
+		"login_name": "alice",
+		"email":      "alice@example.com",
+		"groups":     []interface{}{"eng", "ops"},
+		"level":      float64(3),
+		"admin":      true,
+		"manager":    map[string]interface{}{"name": "bob"},
+	}
+	tests := []struct {
+		name string
+		rule AttributeRule
+		want bool
+	}{
+		{"exists", AttributeRule{Attribute: "email", Operator: AttributeExists}, true},
+		{"not exists", AttributeRule{Attribute: "phone", Operator: AttributeExists}, false},
+		{"equals", AttributeRule{Attribute: "login_name", Operator: AttributeEquals, Value: "alice"}, true},
+		{"not equals", AttributeRule{Attribute: "login_name", Operator: AttributeEquals, Value: "ali"}, false},
+		{"contains", AttributeRule{Attribute: "email", Operator: AttributeContains, Value: "@example.com"}, true},
+		{"not contains", AttributeRule{Attribute: "email", Operator: AttributeContains, Value: "@example.org"}, false},
+		{"list equals", AttributeRule{Attribute: "groups", Operator: AttributeEquals, Value: "ops"}, true},
+		{"list not equals", AttributeRule{Attribute: "groups", Operator: AttributeEquals, Value: "sales"}, false},
+		{"number", AttributeRule{Attribute: "level", Operator: AttributeEquals, Value: "3"}, true},
+		{"bool", AttributeRule{Attribute: "admin", Operator: AttributeEquals, Value: "true"}, true},
+		{"object", AttributeRule{Attribute: "manager", Operator: AttributeContains, Value: "bob"}, false},
+		{"object exists", AttributeRule{Attribute: "manager", Operator: AttributeExists}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Matches(attrs))
+		})
+	}
+}
+
+func TestAttributeRule_validate(t *testing.T) {
+	valid := AttributeRule{ScopeId: "o_1234567890", Attribute: "email", Operator: AttributeExists, GroupId: "g_1234567890"}
+	assert.NoError(t, valid.validate())
+
+	aliasRule := valid
+	aliasRule.GroupId, aliasRule.AliasAttribute = "", "email"
+	assert.NoError(t, aliasRule.validate())
+
+	both := valid
+	both.AliasAttribute = "email"
+	assert.Error(t, both.validate())
+
+	neither := valid
+	neither.GroupId = ""
+	assert.Error(t, neither.validate())
+
+	badOperator := valid
+	badOperator.Operator = "matches"
+	assert.Error(t, badOperator.validate())
+
+	noAttribute := valid
+	noAttribute.Attribute = " "
+	assert.Error(t, noAttribute.validate())
+}
@@ -3,6 +3,7 @@ package iam
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/iam/store"
@@ -56,6 +57,19 @@ func (v *GroupMember) SetTableName(n string) {
 	}
 }
 
+// GroupMembership is a period during which a user was a member of a group.
+type GroupMembership struct {
+	GroupId  string
+	MemberId string
+
+	// ValidFrom is when the user was added to the group.
+	ValidFrom time.Time
+
+	// ValidTo is when the user was removed from the group. It is the zero
+	// time if the user is still a member.
+	ValidTo time.Time
+}
+
 // GroupMemberUser is a group member that's a User
 type GroupMemberUser struct {
 	*store.GroupMemberUser
@@ -105,6 +119,9 @@ func (m *GroupMemberUser) VetForWrite(ctx context.Context, r db.Reader, opType d
 	if m.MemberId == "" {
 		return fmt.Errorf("group member: missing member id: %w", db.ErrInvalidParameter)
 	}
+	if err := vetGroupScopeForWrite(ctx, r, m.GroupId); err != nil {
+		return fmt.Errorf("group member: %w", err)
+	}
 	return nil
 }
 
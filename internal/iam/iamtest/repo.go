@@ -0,0 +1,417 @@
+// Package iamtest provides an in-memory implementation of iam.Repo, so that
+// code using iam repositories can be tested without a database.
+package iamtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Repo is an in-memory iam.Repo. It starts with only the global scope. Scopes
+// must be created in existing parents, and users, groups and roles in
+// existing scopes, and deleting a scope deletes everything in it. Users,
+// groups and roles have no accounts, members, principals or grants, and
+// creating a scope doesn't create roles for the creating user. Options are
+// ignored. It is safe for concurrent use.
+type Repo struct {
+	l      sync.Mutex
+	scopes map[string]*store.Scope
+	users  map[string]*store.User
+	groups map[string]*store.Group
+	roles  map[string]*store.Role
+}
+
+var _ iam.Repo = (*Repo)(nil)
+
+// NewRepo creates a Repo containing the global scope.
+func NewRepo() *Repo {
+	return &Repo{
+		scopes: map[string]*store.Scope{
+			scope.Global.String(): {
+				PublicId:    scope.Global.String(),
+				Type:        scope.Global.String(),
+				Name:        "global",
+				Description: "Global Scope",
+				CreateTime:  now(),
+				UpdateTime:  now(),
+				Version:     1,
+			},
+		},
+		users:  make(map[string]*store.User),
+		groups: make(map[string]*store.Group),
+		roles:  make(map[string]*store.Role),
+	}
+}
+
+// CreateScope implements iam.Repo.
+func (r *Repo) CreateScope(ctx context.Context, s *iam.Scope, userId string, opt ...iam.Option) (*iam.Scope, error) {
+	switch {
+	case s == nil || s.Scope == nil:
+		return nil, fmt.Errorf("create scope: missing scope %w", db.ErrInvalidParameter)
+	case s.PublicId != "":
+		return nil, fmt.Errorf("create scope: public id not empty: %w", db.ErrInvalidParameter)
+	case s.ParentId == "":
+		return nil, fmt.Errorf("create scope: missing parent id: %w", db.ErrInvalidParameter)
+	}
+	var parentType scope.Type
+	switch scope.Map[s.Type] {
+	case scope.Org:
+		parentType = scope.Global
+	case scope.Project:
+		parentType = scope.Org
+	default:
+		return nil, fmt.Errorf("create scope: invalid type: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	parent, ok := r.scopes[s.ParentId]
+	if !ok || parent.Type != parentType.String() {
+		return nil, fmt.Errorf("create scope: parent %s is not a %s: %w", s.ParentId, parentType, db.ErrInvalidParameter)
+	}
+	for _, e := range r.scopes {
+		if s.Name != "" && e.ParentId == s.ParentId && e.Name == s.Name {
+			return nil, fmt.Errorf("create scope: scope %s already exists in %s: %w", s.Name, s.ParentId, db.ErrNotUnique)
+		}
+	}
+	id, err := db.NewPublicId(scope.Map[s.Type].Prefix())
+	if err != nil {
+		return nil, fmt.Errorf("create scope: %w", err)
+	}
+	n := proto.Clone(s.Scope).(*store.Scope)
+	n.PublicId = id
+	n.CreateTime, n.UpdateTime = now(), now()
+	n.Version = 1
+	r.scopes[id] = n
+	return &iam.Scope{Scope: proto.Clone(n).(*store.Scope)}, nil
+}
+
+// LookupScope implements iam.Repo.
+func (r *Repo) LookupScope(ctx context.Context, withPublicId string, opt ...iam.Option) (*iam.Scope, error) {
+	if withPublicId == "" {
+		return nil, fmt.Errorf("lookup scope: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	s, ok := r.scopes[withPublicId]
+	if !ok {
+		return nil, nil
+	}
+	return &iam.Scope{Scope: proto.Clone(s).(*store.Scope)}, nil
+}
+
+// ListOrgs implements iam.Repo.
+func (r *Repo) ListOrgs(ctx context.Context, opt ...iam.Option) ([]*iam.Scope, error) {
+	return r.listScopes(scope.Global.String()), nil
+}
+
+// ListProjects implements iam.Repo.
+func (r *Repo) ListProjects(ctx context.Context, withOrgId string, opt ...iam.Option) ([]*iam.Scope, error) {
+	if withOrgId == "" {
+		return nil, fmt.Errorf("list projects: missing org id %w", db.ErrInvalidParameter)
+	}
+	return r.listScopes(withOrgId), nil
+}
+
+// listScopes returns the children of parentId ordered by public id.
+func (r *Repo) listScopes(parentId string) []*iam.Scope {
+	r.l.Lock()
+	defer r.l.Unlock()
+	var scopes []*iam.Scope
+	for _, s := range r.scopes {
+		if s.ParentId == parentId {
+			scopes = append(scopes, &iam.Scope{Scope: proto.Clone(s).(*store.Scope)})
+		}
+	}
+	sort.Slice(scopes, func(i, j int) bool { return scopes[i].PublicId < scopes[j].PublicId })
+	return scopes
+}
+
+// DeleteScope implements iam.Repo.
+func (r *Repo) DeleteScope(ctx context.Context, withPublicId string, opt ...iam.Option) (int, error) {
+	if withPublicId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete scope: missing public id %w", db.ErrInvalidParameter)
+	}
+	if withPublicId == scope.Global.String() {
+		return db.NoRowsAffected, fmt.Errorf("delete scope: invalid to delete global scope: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.scopes[withPublicId]; !ok {
+		return db.NoRowsAffected, nil
+	}
+	r.deleteScope(withPublicId)
+	return 1, nil
+}
+
+// deleteScope deletes the scope with id and everything in it.
+func (r *Repo) deleteScope(id string) {
+	delete(r.scopes, id)
+	for cid, s := range r.scopes {
+		if s.ParentId == id {
+			r.deleteScope(cid)
+		}
+	}
+	for uid, u := range r.users {
+		if u.ScopeId == id {
+			delete(r.users, uid)
+		}
+	}
+	for gid, g := range r.groups {
+		if g.ScopeId == id {
+			delete(r.groups, gid)
+		}
+	}
+	for rid, role := range r.roles {
+		if role.ScopeId == id {
+			delete(r.roles, rid)
+		}
+	}
+}
+
+// CreateUser implements iam.Repo. Users can only be created in the global
+// scope and orgs.
+func (r *Repo) CreateUser(ctx context.Context, user *iam.User, opt ...iam.Option) (*iam.User, error) {
+	switch {
+	case user == nil || user.User == nil:
+		return nil, fmt.Errorf("create user: missing user %w", db.ErrInvalidParameter)
+	case user.PublicId != "":
+		return nil, fmt.Errorf("create user: public id is not empty %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if s, ok := r.scopes[user.ScopeId]; !ok || s.Type == scope.Project.String() {
+		return nil, fmt.Errorf("create user: scope %s is not the global scope or an org: %w", user.ScopeId, db.ErrInvalidParameter)
+	}
+	for _, e := range r.users {
+		if user.Name != "" && e.ScopeId == user.ScopeId && e.Name == user.Name {
+			return nil, fmt.Errorf("create user: user %s already exists in org %s: %w", user.Name, user.ScopeId, db.ErrNotUnique)
+		}
+	}
+	id, err := db.NewPublicId(iam.UserPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+	n := proto.Clone(user.User).(*store.User)
+	n.PublicId = id
+	n.CreateTime, n.UpdateTime = now(), now()
+	n.Version = 1
+	r.users[id] = n
+	return &iam.User{User: proto.Clone(n).(*store.User)}, nil
+}
+
+// LookupUser implements iam.Repo. Users have no accounts.
+func (r *Repo) LookupUser(ctx context.Context, userId string, opt ...iam.Option) (*iam.User, []string, error) {
+	if userId == "" {
+		return nil, nil, fmt.Errorf("lookup user: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	u, ok := r.users[userId]
+	if !ok {
+		return nil, nil, nil
+	}
+	return &iam.User{User: proto.Clone(u).(*store.User)}, nil, nil
+}
+
+// ListUsers implements iam.Repo.
+func (r *Repo) ListUsers(ctx context.Context, withOrgId string, opt ...iam.Option) ([]*iam.User, error) {
+	if withOrgId == "" {
+		return nil, fmt.Errorf("list users: missing org id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	var users []*iam.User
+	for _, u := range r.users {
+		if u.ScopeId == withOrgId {
+			users = append(users, &iam.User{User: proto.Clone(u).(*store.User)})
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].PublicId < users[j].PublicId })
+	return users, nil
+}
+
+// DeleteUser implements iam.Repo.
+func (r *Repo) DeleteUser(ctx context.Context, withPublicId string, opt ...iam.Option) (int, error) {
+	if withPublicId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete user: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.users[withPublicId]; !ok {
+		return db.NoRowsAffected, nil
+	}
+	delete(r.users, withPublicId)
+	return 1, nil
+}
+
+// CreateGroup implements iam.Repo.
+func (r *Repo) CreateGroup(ctx context.Context, group *iam.Group, opt ...iam.Option) (*iam.Group, error) {
+	switch {
+	case group == nil || group.Group == nil:
+		return nil, fmt.Errorf("create group: missing group %w", db.ErrInvalidParameter)
+	case group.PublicId != "":
+		return nil, fmt.Errorf("create group: public id not empty: %w", db.ErrInvalidParameter)
+	case group.ScopeId == "":
+		return nil, fmt.Errorf("create group: missing group scope id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.scopes[group.ScopeId]; !ok {
+		return nil, fmt.Errorf("create group: scope %s: %w", group.ScopeId, db.ErrInvalidParameter)
+	}
+	for _, e := range r.groups {
+		if group.Name != "" && e.ScopeId == group.ScopeId && e.Name == group.Name {
+			return nil, fmt.Errorf("create group: group %s already exists in scope %s: %w", group.Name, group.ScopeId, db.ErrNotUnique)
+		}
+	}
+	id, err := db.NewPublicId(iam.GroupPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create group: %w", err)
+	}
+	n := proto.Clone(group.Group).(*store.Group)
+	n.PublicId = id
+	n.CreateTime, n.UpdateTime = now(), now()
+	n.Version = 1
+	r.groups[id] = n
+	return &iam.Group{Group: proto.Clone(n).(*store.Group)}, nil
+}
+
+// LookupGroup implements iam.Repo. Groups have no members.
+func (r *Repo) LookupGroup(ctx context.Context, withPublicId string, opt ...iam.Option) (*iam.Group, []*iam.GroupMember, error) {
+	if withPublicId == "" {
+		return nil, nil, fmt.Errorf("lookup group: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	g, ok := r.groups[withPublicId]
+	if !ok {
+		return nil, nil, nil
+	}
+	return &iam.Group{Group: proto.Clone(g).(*store.Group)}, nil, nil
+}
+
+// ListGroups implements iam.Repo.
+func (r *Repo) ListGroups(ctx context.Context, withScopeId string, opt ...iam.Option) ([]*iam.Group, error) {
+	if withScopeId == "" {
+		return nil, fmt.Errorf("list groups: missing scope id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	var groups []*iam.Group
+	for _, g := range r.groups {
+		if g.ScopeId == withScopeId {
+			groups = append(groups, &iam.Group{Group: proto.Clone(g).(*store.Group)})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].PublicId < groups[j].PublicId })
+	return groups, nil
+}
+
+// DeleteGroup implements iam.Repo.
+func (r *Repo) DeleteGroup(ctx context.Context, withPublicId string, opt ...iam.Option) (int, error) {
+	if withPublicId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete group: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.groups[withPublicId]; !ok {
+		return db.NoRowsAffected, nil
+	}
+	delete(r.groups, withPublicId)
+	return 1, nil
+}
+
+// CreateRole implements iam.Repo. The grant scope of the role is its own
+// scope if it isn't set.
+func (r *Repo) CreateRole(ctx context.Context, role *iam.Role, opt ...iam.Option) (*iam.Role, error) {
+	switch {
+	case role == nil || role.Role == nil:
+		return nil, fmt.Errorf("create role: missing role %w", db.ErrInvalidParameter)
+	case role.PublicId != "":
+		return nil, fmt.Errorf("create role: public id not empty: %w", db.ErrInvalidParameter)
+	case role.ScopeId == "":
+		return nil, fmt.Errorf("create role: missing role scope id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.scopes[role.ScopeId]; !ok {
+		return nil, fmt.Errorf("create role: scope %s: %w", role.ScopeId, db.ErrInvalidParameter)
+	}
+	for _, e := range r.roles {
+		if role.Name != "" && e.ScopeId == role.ScopeId && e.Name == role.Name {
+			return nil, fmt.Errorf("create role: role %s already exists in scope %s: %w", role.Name, role.ScopeId, db.ErrNotUnique)
+		}
+	}
+	id, err := db.NewPublicId(iam.RolePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create role: %w", err)
+	}
+	n := proto.Clone(role.Role).(*store.Role)
+	n.PublicId = id
+	if n.GrantScopeId == "" {
+		n.GrantScopeId = n.ScopeId
+	}
+	n.CreateTime, n.UpdateTime = now(), now()
+	n.Version = 1
+	r.roles[id] = n
+	return &iam.Role{Role: proto.Clone(n).(*store.Role)}, nil
+}
+
+// LookupRole implements iam.Repo. Roles have no principals or grants.
+func (r *Repo) LookupRole(ctx context.Context, withPublicId string, opt ...iam.Option) (*iam.Role, []iam.PrincipalRole, []*iam.RoleGrant, error) {
+	if withPublicId == "" {
+		return nil, nil, nil, fmt.Errorf("lookup role: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	role, ok := r.roles[withPublicId]
+	if !ok {
+		return nil, nil, nil, nil
+	}
+	return &iam.Role{Role: proto.Clone(role).(*store.Role)}, nil, nil, nil
+}
+
+// ListRoles implements iam.Repo.
+func (r *Repo) ListRoles(ctx context.Context, withScopeId string, opt ...iam.Option) ([]*iam.Role, error) {
+	if withScopeId == "" {
+		return nil, fmt.Errorf("list roles: missing scope id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	var roles []*iam.Role
+	for _, role := range r.roles {
+		if role.ScopeId == withScopeId {
+			roles = append(roles, &iam.Role{Role: proto.Clone(role).(*store.Role)})
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].PublicId < roles[j].PublicId })
+	return roles, nil
+}
+
+// DeleteRole implements iam.Repo.
+func (r *Repo) DeleteRole(ctx context.Context, withPublicId string, opt ...iam.Option) (int, error) {
+	if withPublicId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete role: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.roles[withPublicId]; !ok {
+		return db.NoRowsAffected, nil
+	}
+	delete(r.roles, withPublicId)
+	return 1, nil
+}
+
+func now() *timestamp.Timestamp {
+	return &timestamp.Timestamp{Timestamp: timestamppb.Now()}
+}
@@ -0,0 +1,126 @@
+package iamtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_Scopes(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	repo := NewRepo()
+
+	global, err := repo.LookupScope(ctx, scope.Global.String())
+	require.NoError(err)
+	require.NotNil(global)
+
+	org, err := iam.NewOrg(iam.WithName("org"))
+	require.NoError(err)
+	org, err = repo.CreateScope(ctx, org, "")
+	require.NoError(err)
+	db.AssertPublicId(t, scope.Org.Prefix(), org.PublicId)
+	assert.Equal(uint32(1), org.Version)
+
+	dup, err := iam.NewOrg(iam.WithName("org"))
+	require.NoError(err)
+	_, err = repo.CreateScope(ctx, dup, "")
+	assert.True(errors.Is(err, db.ErrNotUnique))
+
+	proj, err := iam.NewProject(org.PublicId)
+	require.NoError(err)
+	proj, err = repo.CreateScope(ctx, proj, "")
+	require.NoError(err)
+
+	badProj := &iam.Scope{Scope: &store.Scope{Type: scope.Project.String(), ParentId: proj.PublicId}}
+	_, err = repo.CreateScope(ctx, badProj, "")
+	assert.True(errors.Is(err, db.ErrInvalidParameter))
+
+	orgs, err := repo.ListOrgs(ctx)
+	require.NoError(err)
+	assert.Len(orgs, 1)
+	projects, err := repo.ListProjects(ctx, org.PublicId)
+	require.NoError(err)
+	assert.Len(projects, 1)
+
+	// Deleting an org deletes its projects and everything in them
+	g, err := iam.NewGroup(proj.PublicId)
+	require.NoError(err)
+	g, err = repo.CreateGroup(ctx, g)
+	require.NoError(err)
+	deleted, err := repo.DeleteScope(ctx, org.PublicId)
+	require.NoError(err)
+	assert.Equal(1, deleted)
+	got, err := repo.LookupScope(ctx, proj.PublicId)
+	require.NoError(err)
+	assert.Nil(got)
+	gotGroup, _, err := repo.LookupGroup(ctx, g.PublicId)
+	require.NoError(err)
+	assert.Nil(gotGroup)
+
+	_, err = repo.DeleteScope(ctx, scope.Global.String())
+	assert.True(errors.Is(err, db.ErrInvalidParameter))
+}
+
+func TestRepo_UsersGroupsRoles(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	repo := NewRepo()
+	org, err := iam.NewOrg()
+	require.NoError(err)
+	org, err = repo.CreateScope(ctx, org, "")
+	require.NoError(err)
+
+	u, err := iam.NewUser(org.PublicId, iam.WithName("alice"))
+	require.NoError(err)
+	u, err = repo.CreateUser(ctx, u)
+	require.NoError(err)
+	db.AssertPublicId(t, iam.UserPrefix, u.PublicId)
+	gotUser, accts, err := repo.LookupUser(ctx, u.PublicId)
+	require.NoError(err)
+	assert.Equal(u, gotUser)
+	assert.Empty(accts)
+	users, err := repo.ListUsers(ctx, org.PublicId)
+	require.NoError(err)
+	assert.Len(users, 1)
+
+	role, err := iam.NewRole(org.PublicId)
+	require.NoError(err)
+	role, err = repo.CreateRole(ctx, role)
+	require.NoError(err)
+	assert.Equal(org.PublicId, role.GrantScopeId)
+	roles, err := repo.ListRoles(ctx, org.PublicId)
+	require.NoError(err)
+	assert.Len(roles, 1)
+
+	g, err := iam.NewGroup(org.PublicId)
+	require.NoError(err)
+	g, err = repo.CreateGroup(ctx, g)
+	require.NoError(err)
+	groups, err := repo.ListGroups(ctx, org.PublicId)
+	require.NoError(err)
+	assert.Len(groups, 1)
+
+	for _, del := range []func(context.Context, string, ...iam.Option) (int, error){repo.DeleteUser, repo.DeleteRole, repo.DeleteGroup} {
+		for _, id := range []string{u.PublicId, role.PublicId, g.PublicId} {
+			_, err := del(ctx, id)
+			require.NoError(err)
+		}
+	}
+	users, err = repo.ListUsers(ctx, org.PublicId)
+	require.NoError(err)
+	assert.Empty(users)
+	roles, err = repo.ListRoles(ctx, org.PublicId)
+	require.NoError(err)
+	assert.Empty(roles)
+	groups, err = repo.ListGroups(ctx, org.PublicId)
+	require.NoError(err)
+	assert.Empty(groups)
+}
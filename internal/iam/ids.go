@@ -3,6 +3,8 @@ package iam
 import (
 	"fmt"
 
+	"github.com/hashicorp/boundary/internal/types/publicid"
+
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/types/scope"
 )
@@ -15,7 +17,7 @@ const (
 )
 
 func newRoleId() (string, error) {
-	id, err := db.NewPublicId(RolePrefix)
+	id, err := publicid.New(RolePrefix)
 	if err != nil {
 		return "", fmt.Errorf("new role id: %w", err)
 	}
@@ -23,7 +25,7 @@ func newRoleId() (string, error) {
 }
 
 func newUserId() (string, error) {
-	id, err := db.NewPublicId(UserPrefix)
+	id, err := publicid.New(UserPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new user id: %w", err)
 	}
@@ -31,7 +33,7 @@ func newUserId() (string, error) {
 }
 
 func newGroupId() (string, error) {
-	id, err := db.NewPublicId(GroupPrefix)
+	id, err := publicid.New(GroupPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new group id: %w", err)
 	}
@@ -42,7 +44,7 @@ func newScopeId(scopeType scope.Type) (string, error) {
 	if scopeType == scope.Unknown {
 		return "", fmt.Errorf("new scope id: unknown is not supported %w", db.ErrInvalidParameter)
 	}
-	id, err := db.NewPublicId(scopeType.Prefix())
+	id, err := publicid.New(scopeType.Prefix())
 	if err != nil {
 		return "", fmt.Errorf("new %s id: %w", scopeType.String(), err)
 	}
@@ -21,6 +21,7 @@ type options struct {
 	withDescription             string
 	withGroupGrants             bool
 	withLimit                   int
+	withOrder                   string
 	withAutoVivify              bool
 	withGrantScopeId            string
 	withSkipVetForWrite         bool
@@ -29,6 +30,10 @@ type options struct {
 	withSkipDefaultRoleCreation bool
 	withUserId                  string
 	withRandomReader            io.Reader
+	withUniqueNames             bool
+	withAdminUserCheck          bool
+	withForce                   bool
+	withReferencesQuery         string
 }
 
 func getDefaultOptions() options {
@@ -81,6 +86,14 @@ func WithLimit(limit int) Option {
 	}
 }
 
+// WithOrder provides an option to order the results of a listing by a column
+// of the listed resources, such as "create_time desc".
+func WithOrder(order string) Option {
+	return func(o *options) {
+		o.withOrder = order
+	}
+}
+
 // WithAutoVivify provides an option to enable user auto vivification when
 // calling repo.LookupUserWithLogin().
 func WithAutoVivify(enable bool) Option {
@@ -142,3 +155,37 @@ func WithRandomReader(reader io.Reader) Option {
 		o.withRandomReader = reader
 	}
 }
+
+// WithUniqueNames provides an option for a repository to require role names
+// to be unique within their scope ignoring case. Names which match exactly
+// are always rejected.
+func WithUniqueNames(unique bool) Option {
+	return func(o *options) {
+		o.withUniqueNames = unique
+	}
+}
+
+// withAdminUserCheck provides an option for CreateScope to check within its
+// transaction that the user being made the scope's admin exists.
+func withAdminUserCheck(check bool) Option {
+	return func(o *options) {
+		o.withAdminUserCheck = check
+	}
+}
+
+// WithForce provides an option to delete a user or group which is still
+// referenced by other resources.
+func WithForce(force bool) Option {
+	return func(o *options) {
+		o.withForce = force
+	}
+}
+
+// withReferencesQuery provides an option for delete to return a
+// ResourceInUse error, unless WithForce is set, if the query returns the ids
+// of any resources referencing the resource being deleted.
+func withReferencesQuery(query string) Option {
+	return func(o *options) {
+		o.withReferencesQuery = query
+	}
+}
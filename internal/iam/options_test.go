@@ -74,4 +74,25 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withDisassociate = true
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithUniqueNames", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithUniqueNames(true))
+		testOpts := getDefaultOptions()
+		testOpts.withUniqueNames = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithForce", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithForce(true))
+		testOpts := getDefaultOptions()
+		testOpts.withForce = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithOrder", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithOrder("create_time desc"))
+		testOpts := getDefaultOptions()
+		testOpts.withOrder = "create_time desc"
+		assert.Equal(opts, testOpts)
+	})
 }
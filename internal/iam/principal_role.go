@@ -109,6 +109,9 @@ func (role *UserRole) VetForWrite(ctx context.Context, r db.Reader, opType db.Op
 	if role.PrincipalId == "" {
 		return fmt.Errorf("new user role: missing user id %w", db.ErrInvalidParameter)
 	}
+	if err := vetRoleScopeForWrite(ctx, r, role.RoleId); err != nil {
+		return fmt.Errorf("new user role: %w", err)
+	}
 	return nil
 }
 
@@ -182,6 +185,9 @@ func (role *GroupRole) VetForWrite(ctx context.Context, r db.Reader, opType db.O
 	if role.PrincipalId == "" {
 		return fmt.Errorf("new group role: missing user id %w", db.ErrInvalidParameter)
 	}
+	if err := vetRoleScopeForWrite(ctx, r, role.RoleId); err != nil {
+		return fmt.Errorf("new group role: %w", err)
+	}
 	return nil
 }
 
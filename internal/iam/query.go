@@ -4,6 +4,42 @@ package iam
 // the db via sql.DB vs the standard pattern of using the internal/db package to
 // interact with the db.
 const (
+	// userReferencesQuery returns the roles the user is assigned to and the
+	// sessions of the user which haven't been terminated.
+	userReferencesQuery = `
+	select role_id
+	  from iam_user_role
+	 where principal_id = $1
+	 union all
+	select s.public_id
+	  from session s
+	 where s.user_id = $1
+	   and not exists (
+	     select from session_state ss
+	      where ss.session_id = s.public_id and ss.state = 'terminated'
+	   )
+	order by 1;
+	`
+
+	// readOnlyScopeQuery returns the scope $1 if it's read only, or else its
+	// parent if that's read only.
+	readOnlyScopeQuery = `
+	select public_id
+	  from iam_scope
+	 where read_only
+	   and public_id in ($1, (select parent_id from iam_scope where public_id = $1))
+	order by type = 'project'
+	limit 1;
+	`
+
+	// groupReferencesQuery returns the roles the group is assigned to.
+	groupReferencesQuery = `
+	select role_id
+	  from iam_group_role
+	 where principal_id = $1
+	order by 1;
+	`
+
 	// whereUserAccount - given an auth account id, return the associated user.
 	whereUserAccount = `	
 	select iam_user.*
@@ -109,3 +145,188 @@ const (
 	order by action, member_id;
 	`
 )
+
+const (
+	// authorizedResourcesQuery - returns the ids of the resources in a table
+	// within a scope which match a condition.
+	authorizedResourcesQuery = `
+	select public_id
+	  from %s
+	 where %s = $1
+	   and (%s)
+	 order by public_id`
+
+	// conflictingRoleNameQuery returns the role, other than the one with the
+	// id $2, whose name matches $3 ignoring case within the scope $1, or
+	// within the scope of the role with the id $2 if $1 is empty. A role
+	// whose name matches exactly is preferred.
+	conflictingRoleNameQuery = `
+select public_id, scope_id
+  from iam_role
+ where scope_id = coalesce(nullif($1, ''), (select scope_id from iam_role where public_id = $2))
+   and public_id != $2
+   and lower(name) = lower($3)
+ order by name = $3 desc
+ limit 1;
+`
+
+	// accountScopeUserQuery returns the scope and user of an account.
+	accountScopeUserQuery = `
+select scope_id, iam_user_id
+  from auth_account
+ where public_id = $1;
+`
+
+	// setAccountAttributesQuery replaces the attributes of an account.
+	setAccountAttributesQuery = `
+insert into auth_account_attributes
+  (account_id, attributes)
+values
+  (?, ?)
+on conflict (account_id) do update
+  set attributes = excluded.attributes;
+`
+
+	// addRuleMemberQuery adds a user to the group of a matching attribute
+	// rule, if it isn't a member already.
+	addRuleMemberQuery = `
+insert into iam_group_member_user
+  (group_id, member_id)
+values
+  (?, ?)
+on conflict do nothing;
+`
+
+	// recordRuleMemberQuery records that the membership of the user ? in
+	// the group ? is kept by the rule ?, if the rule has just added it (the
+	// fourth parameter) or another rule added it.
+	recordRuleMemberQuery = `
+insert into iam_attribute_rule_member
+  (rule_id, group_id, member_id)
+select ?, ?, ?
+ where ? or exists (
+   select from iam_attribute_rule_member
+    where group_id = ? and member_id = ?
+ )
+on conflict do nothing;
+`
+
+	// deleteRuleMemberQuery removes a user from the group an attribute rule
+	// added them to.
+	deleteRuleMemberQuery = `
+delete from iam_group_member_user gm
+ using iam_attribute_rule_member rm
+ where rm.rule_id = ?
+   and rm.member_id = ?
+   and gm.group_id = rm.group_id
+   and gm.member_id = rm.member_id;
+`
+
+	// forgetRuleMemberQuery removes the record that an attribute rule keeps
+	// a user's membership of its group, leaving the membership.
+	forgetRuleMemberQuery = `
+delete from iam_attribute_rule_member
+ where rule_id = ? and member_id = ?;
+`
+
+	// addUserAliasQuery gives a user an alias, unless another user already
+	// has it.
+	addUserAliasQuery = `
+insert into iam_user_alias
+  (scope_id, alias, user_id, rule_id)
+values
+  (?, ?, ?, ?)
+on conflict (scope_id, alias) do nothing;
+`
+
+	// deleteUserAliasesQuery removes the aliases an attribute rule gave a
+	// user, other than ?.
+	deleteUserAliasesQuery = `
+delete from iam_user_alias
+ where rule_id = ? and user_id = ? and alias != ?;
+`
+
+	// userAliasOwnerQuery returns the user with an alias in a scope.
+	userAliasOwnerQuery = `
+select user_id
+  from iam_user_alias
+ where scope_id = $1 and alias = $2;
+`
+
+	// groupMembersAtQuery returns the membership periods of the group $1
+	// which include the time $2.
+	groupMembersAtQuery = `
+select group_id, member_id, valid_from, valid_to
+  from iam_group_member_user_hst
+ where group_id = $1
+   and valid_from <= $2
+   and (valid_to is null or valid_to > $2)
+ order by member_id;
+`
+
+	// duplicateUserCandidatesQuery returns the pairs of users in the scope $1
+	// whose accounts share a login name or email address, ignoring case.
+	duplicateUserCandidatesQuery = `
+	with
+	user_identity (user_id, value) as (
+	  select a.iam_user_id, lower(p.login_name)
+	    from auth_account a
+	    join auth_password_account p
+	      on p.public_id = a.public_id
+	   where a.scope_id = $1
+	     and a.iam_user_id is not null
+	   union
+	  select a.iam_user_id, lower(aa.attributes->>'email')
+	    from auth_account a
+	    join auth_account_attributes aa
+	      on aa.account_id = a.public_id
+	   where a.scope_id = $1
+	     and a.iam_user_id is not null
+	     and coalesce(aa.attributes->>'email', '') <> ''
+	)
+	select i1.user_id, i2.user_id, i1.value
+	  from user_identity i1
+	  join user_identity i2
+	    on i1.value = i2.value
+	   and i1.user_id < i2.user_id
+	order by 1, 2, 3;
+	`
+)
+
+// mergeUserQueries give the user $1 the accounts, role assignments, group
+// memberships, aliases and sessions of the user $2. The auth tokens of the
+// accounts follow them. Role assignments and group memberships the users
+// share are left to be removed with $2.
+var mergeUserQueries = []string{
+	`update auth_account
+	    set iam_user_id = $1
+	  where iam_user_id = $2;`,
+	`insert into iam_user_role (role_id, principal_id)
+	 select role_id, $1
+	   from iam_user_role
+	  where principal_id = $2
+	 on conflict do nothing;`,
+	`insert into iam_group_member_user (group_id, member_id)
+	 select group_id, $1
+	   from iam_group_member_user
+	  where member_id = $2
+	 on conflict do nothing;`,
+	`update iam_user_alias
+	    set user_id = $1
+	  where user_id = $2;`,
+	`update session
+	    set user_id = $1
+	  where user_id = $2;`,
+}
+
+const authMethodScopeQuery = `
+select scope_id
+  from auth_method
+ where public_id = $1;
+`
+
+const countUsersQuery = `
+select count(*)
+  from iam_user
+ where scope_id = $1;
+`
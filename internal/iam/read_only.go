@@ -0,0 +1,75 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// VetScopeForWrite returns a ReadOnlyScope error if resources in the scope
+// with id scopeId can't be written because the scope, or the org it's in, is
+// read only. It's intended to be called from the VetForWrite functions of
+// resources within a scope.
+func VetScopeForWrite(ctx context.Context, r db.Reader, scopeId string) error {
+	if r == nil {
+		return fmt.Errorf("vet scope for write: missing reader: %w", db.ErrInvalidParameter)
+	}
+	if scopeId == "" {
+		return fmt.Errorf("vet scope for write: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.Query(ctx, readOnlyScopeQuery, []interface{}{scopeId})
+	if err != nil {
+		return fmt.Errorf("vet scope for write: %w", err)
+	}
+	defer rows.Close()
+	var readOnlyId string
+	for rows.Next() {
+		if err := rows.Scan(&readOnlyId); err != nil {
+			return fmt.Errorf("vet scope for write: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("vet scope for write: %w", err)
+	}
+	if readOnlyId != "" {
+		return &errors.ReadOnlyScope{ScopeId: readOnlyId}
+	}
+	return nil
+}
+
+// vetResourceScopeForWrite calls VetScopeForWrite with the scope of the
+// resource, which is looked up with a copy of the resource when it isn't set,
+// such as for updates, so the resource being written isn't changed.
+func vetResourceScopeForWrite(ctx context.Context, r db.Reader, resource ResourceWithScope) error {
+	scopeId := resource.GetScopeId()
+	if scopeId == "" {
+		cp, ok := resource.(Cloneable)
+		if !ok {
+			return fmt.Errorf("vet scope for write: unable to look up scope of %s: %w", resource.GetPublicId(), db.ErrInvalidParameter)
+		}
+		found := cp.Clone().(ResourceWithScope)
+		if err := r.LookupById(ctx, found); err != nil {
+			return fmt.Errorf("vet scope for write: unable to look up scope of %s: %w", resource.GetPublicId(), err)
+		}
+		scopeId = found.GetScopeId()
+	}
+	return VetScopeForWrite(ctx, r, scopeId)
+}
+
+// vetRoleScopeForWrite calls VetScopeForWrite with the scope of the role with
+// id roleId, for writes of the role's grants and principals.
+func vetRoleScopeForWrite(ctx context.Context, r db.Reader, roleId string) error {
+	role := allocRole()
+	role.PublicId = roleId
+	return vetResourceScopeForWrite(ctx, r, &role)
+}
+
+// vetGroupScopeForWrite calls VetScopeForWrite with the scope of the group
+// with id groupId, for writes of the group's members.
+func vetGroupScopeForWrite(ctx context.Context, r db.Reader, groupId string) error {
+	group := allocGroup()
+	group.PublicId = groupId
+	return vetResourceScopeForWrite(ctx, r, &group)
+}
@@ -0,0 +1,100 @@
+package iam
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVetScopeForWrite(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+	org, proj := TestScopes(t, repo)
+	role := TestRole(t, conn, proj.PublicId)
+	group := TestGroup(t, conn, org.PublicId)
+	user := TestUser(t, repo, org.PublicId)
+
+	readOnly := func(t *testing.T, scope *Scope, readOnly bool) *Scope {
+		t.Helper()
+		s := scope.Clone().(*Scope)
+		s.ReadOnly = readOnly
+		updated, rowsUpdated, err := repo.UpdateScope(ctx, s, scope.Version, []string{"ReadOnly"})
+		require.NoError(t, err)
+		require.Equal(t, 1, rowsUpdated)
+		assert.Equal(t, readOnly, updated.ReadOnly)
+		return updated
+	}
+	assertReadOnly := func(t *testing.T, err error, scopeId string) {
+		t.Helper()
+		require.Error(t, err)
+		assert.Truef(t, errors.Is(err, errors.ErrReadOnlyScope), "want err: %q got: %q", errors.ErrReadOnlyScope, err)
+		var readOnlyErr *errors.ReadOnlyScope
+		require.True(t, errors.As(err, &readOnlyErr))
+		assert.Equal(t, scopeId, readOnlyErr.ScopeId)
+	}
+
+	require.NoError(t, VetScopeForWrite(ctx, rw, proj.PublicId))
+	org = readOnly(t, org, true)
+	assertReadOnly(t, VetScopeForWrite(ctx, rw, org.PublicId), org.PublicId)
+	assertReadOnly(t, VetScopeForWrite(ctx, rw, proj.PublicId), org.PublicId)
+	require.NoError(t, VetScopeForWrite(ctx, rw, "global"))
+
+	t.Run("create", func(t *testing.T) {
+		r, err := NewRole(proj.PublicId)
+		require.NoError(t, err)
+		_, err = repo.CreateRole(ctx, r)
+		assertReadOnly(t, err, org.PublicId)
+
+		p, err := NewProject(org.PublicId)
+		require.NoError(t, err)
+		_, err = repo.CreateScope(ctx, p, "")
+		assertReadOnly(t, err, org.PublicId)
+	})
+	t.Run("update", func(t *testing.T) {
+		r := role.Clone().(*Role)
+		r.ScopeId = ""
+		r.Name = "frozen"
+		_, _, _, _, err := repo.UpdateRole(ctx, r, role.Version, []string{"Name"})
+		assertReadOnly(t, err, org.PublicId)
+
+		u := user.Clone().(*User)
+		u.Name = "frozen"
+		_, _, _, err = repo.UpdateUser(ctx, u, user.Version, []string{"Name"})
+		assertReadOnly(t, err, org.PublicId)
+
+		s := org.Clone().(*Scope)
+		s.Name = "frozen"
+		_, _, err = repo.UpdateScope(ctx, s, org.Version, []string{"Name"})
+		assertReadOnly(t, err, org.PublicId)
+	})
+	t.Run("principals-and-grants", func(t *testing.T) {
+		_, err := repo.AddRoleGrants(ctx, role.PublicId, role.Version, []string{"id=*;type=*;actions=read"})
+		assertReadOnly(t, err, org.PublicId)
+
+		_, err = repo.AddGroupMembers(ctx, group.PublicId, group.Version, []string{user.PublicId})
+		assertReadOnly(t, err, org.PublicId)
+	})
+	t.Run("global", func(t *testing.T) {
+		s := allocScope()
+		s.PublicId = "global"
+		s.ReadOnly = true
+		_, _, err := repo.UpdateScope(ctx, &s, 1, []string{"ReadOnly"})
+		assert.Error(t, err)
+	})
+
+	// The scope can be made writable again
+	org = readOnly(t, org, false)
+	require.NoError(t, VetScopeForWrite(ctx, rw, proj.PublicId))
+	r, err := NewRole(proj.PublicId)
+	require.NoError(t, err)
+	_, err = repo.CreateRole(ctx, r)
+	require.NoError(t, err)
+}
@@ -2,11 +2,11 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/types/scope"
@@ -24,10 +24,43 @@ type Repository struct {
 
 	// defaultLimit provides a default for limiting the number of results returned from the repo
 	defaultLimit int
+
+	// uniqueNames is true if role names must be unique within their scope
+	// ignoring case, rather than only when compared exactly.
+	uniqueNames bool
+}
+
+// Repo is the subset of Repository used to create, look up, list and delete
+// scopes, users, groups and roles. iamtest.Repo implements it in memory for
+// tests which don't need a database.
+type Repo interface {
+	CreateScope(ctx context.Context, s *Scope, userId string, opt ...Option) (*Scope, error)
+	LookupScope(ctx context.Context, withPublicId string, opt ...Option) (*Scope, error)
+	ListOrgs(ctx context.Context, opt ...Option) ([]*Scope, error)
+	ListProjects(ctx context.Context, withOrgId string, opt ...Option) ([]*Scope, error)
+	DeleteScope(ctx context.Context, withPublicId string, opt ...Option) (int, error)
+
+	CreateUser(ctx context.Context, user *User, opt ...Option) (*User, error)
+	LookupUser(ctx context.Context, userId string, opt ...Option) (*User, []string, error)
+	ListUsers(ctx context.Context, withOrgId string, opt ...Option) ([]*User, error)
+	DeleteUser(ctx context.Context, withPublicId string, opt ...Option) (int, error)
+
+	CreateGroup(ctx context.Context, group *Group, opt ...Option) (*Group, error)
+	LookupGroup(ctx context.Context, withPublicId string, opt ...Option) (*Group, []*GroupMember, error)
+	ListGroups(ctx context.Context, withScopeId string, opt ...Option) ([]*Group, error)
+	DeleteGroup(ctx context.Context, withPublicId string, opt ...Option) (int, error)
+
+	CreateRole(ctx context.Context, role *Role, opt ...Option) (*Role, error)
+	LookupRole(ctx context.Context, withPublicId string, opt ...Option) (*Role, []PrincipalRole, []*RoleGrant, error)
+	ListRoles(ctx context.Context, withScopeId string, opt ...Option) ([]*Role, error)
+	DeleteRole(ctx context.Context, withPublicId string, opt ...Option) (int, error)
 }
 
+var _ Repo = (*Repository)(nil)
+
 // NewRepository creates a new iam Repository. Supports the options: WithLimit
-// which sets a default limit on results returned by repo operations.
+// which sets a default limit on results returned by repo operations, and
+// WithUniqueNames.
 func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repository, error) {
 	if r == nil {
 		return nil, errors.New("error creating db repository with nil reader")
@@ -48,11 +81,12 @@ func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repo
 		writer:       w,
 		kms:          kms,
 		defaultLimit: opts.withLimit,
+		uniqueNames:  opts.withUniqueNames,
 	}, nil
 }
 
-// list will return a listing of resources and honor the WithLimit option or the
-// repo defaultLimit
+// list will return a listing of resources and honor the WithOrder option and
+// the WithLimit option or the repo defaultLimit
 func (r *Repository) list(ctx context.Context, resources interface{}, where string, args []interface{}, opt ...Option) error {
 	opts := getOpts(opt...)
 	limit := r.defaultLimit
@@ -60,7 +94,17 @@ func (r *Repository) list(ctx context.Context, resources interface{}, where stri
 		// non-zero signals an override of the default limit for the repo.
 		limit = opts.withLimit
 	}
-	return r.reader.SearchWhere(ctx, resources, where, args, db.WithLimit(limit))
+	return r.reader.SearchWhere(ctx, resources, where, args, db.WithLimit(limit), db.WithOrder(opts.withOrder))
+}
+
+// withNameCondition adds a condition on the resource's name to where and args
+// if the WithName option is set, so listings can be narrowed by name.
+func withNameCondition(where string, args []interface{}, opt ...Option) (string, []interface{}) {
+	opts := getOpts(opt...)
+	if opts.withName == "" {
+		return where, args
+	}
+	return where + " and name = ?", append(args, opts.withName)
 }
 
 // create will create a new iam resource in the db repository with an oplog entry
@@ -195,13 +239,19 @@ func (r *Repository) delete(ctx context.Context, resource Resource, opt ...Optio
 		return db.NoRowsAffected, fmt.Errorf("unable to get oplog wrapper: %w", err)
 	}
 
+	opts := getOpts(opt...)
 	var rowsDeleted int
 	var deleteResource interface{}
 	_, err = r.writer.DoTx(
 		ctx,
 		db.StdRetryCnt,
 		db.ExpBackoff{},
-		func(_ db.Reader, w db.Writer) error {
+		func(reader db.Reader, w db.Writer) error {
+			if opts.withReferencesQuery != "" && !opts.withForce {
+				if err := checkReferences(ctx, reader, opts.withReferencesQuery, resource); err != nil {
+					return err
+				}
+			}
 			deleteResource = resourceCloner.Clone()
 			rowsDeleted, err = w.Delete(
 				ctx,
@@ -218,6 +268,35 @@ func (r *Repository) delete(ctx context.Context, resource Resource, opt ...Optio
 	return rowsDeleted, err
 }
 
+// checkReferences returns a ResourceInUse error listing the ids returned by
+// query, which is given the resource's public id, if it returns any.
+func checkReferences(ctx context.Context, r db.Reader, query string, resource Resource) error {
+	rows, err := r.Query(ctx, query, []interface{}{resource.GetPublicId()})
+	if err != nil {
+		return fmt.Errorf("unable to check references: %w", err)
+	}
+	defer rows.Close()
+	var referencedBy []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("unable to check references: %w", err)
+		}
+		referencedBy = append(referencedBy, id)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to check references: %w", err)
+	}
+	if len(referencedBy) > 0 {
+		return &errors.ResourceInUse{
+			Resource:     resource.ResourceType().String(),
+			PublicId:     resource.GetPublicId(),
+			ReferencedBy: referencedBy,
+		}
+	}
+	return nil
+}
+
 func (r *Repository) stdMetadata(ctx context.Context, resource Resource) (oplog.Metadata, error) {
 	if s, ok := resource.(*Scope); ok {
 		newScope := allocScope()
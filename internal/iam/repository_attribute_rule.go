@@ -0,0 +1,244 @@
+package iam
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// AttributeRuleResults are the groups and aliases the attribute rules of an
+// account's scope gave its user when the account authenticated.
+type AttributeRuleResults struct {
+	GroupIds []string
+	Aliases  []string
+}
+
+// CreateAttributeRule inserts rule into the repository and returns a new
+// AttributeRule containing the rule's PublicId. rule is not changed. Rules
+// can be created in the global scope and in orgs, the scopes auth methods
+// are created in. The group of a group rule must be in the rule's scope or
+// in one of its projects. All options are ignored.
+func (r *Repository) CreateAttributeRule(ctx context.Context, rule *AttributeRule, opt ...Option) (*AttributeRule, error) {
+	if rule == nil {
+		return nil, fmt.Errorf("create attribute rule: missing rule: %w", db.ErrInvalidParameter)
+	}
+	if rule.PublicId != "" {
+		return nil, fmt.Errorf("create attribute rule: public id not empty: %w", db.ErrInvalidParameter)
+	}
+	if err := rule.validate(); err != nil {
+		return nil, fmt.Errorf("create attribute rule: %w", err)
+	}
+	if rule.ScopeId != scope.Global.String() && !strings.HasPrefix(rule.ScopeId, scope.Org.Prefix()+"_") {
+		return nil, fmt.Errorf("create attribute rule: rules can only be created in the global scope or an org: %w", db.ErrInvalidParameter)
+	}
+	if rule.GroupId != "" {
+		g, _, err := r.LookupGroup(ctx, rule.GroupId)
+		if err != nil {
+			return nil, fmt.Errorf("create attribute rule: %w", err)
+		}
+		if g == nil {
+			return nil, fmt.Errorf("create attribute rule: group %s: %w", rule.GroupId, db.ErrRecordNotFound)
+		}
+		if g.GetScopeId() != rule.ScopeId {
+			s, err := r.LookupScope(ctx, g.GetScopeId())
+			if err != nil {
+				return nil, fmt.Errorf("create attribute rule: %w", err)
+			}
+			if s == nil || s.GetParentId() != rule.ScopeId {
+				return nil, fmt.Errorf("create attribute rule: group %s is not within scope %s: %w", rule.GroupId, rule.ScopeId, db.ErrInvalidParameter)
+			}
+		}
+	}
+
+	id, err := newAttributeRuleId()
+	if err != nil {
+		return nil, fmt.Errorf("create attribute rule: %w", err)
+	}
+	newRule := *rule
+	newRule.PublicId = id
+	// rules only affect logins, which aren't replicated, so they don't need
+	// oplog entries.
+	if err := r.writer.Create(ctx, &newRule); err != nil {
+		return nil, fmt.Errorf("create attribute rule: %w", err)
+	}
+	return &newRule, nil
+}
+
+// ListAttributeRules returns the attribute rules of scopeId. All options
+// are ignored.
+func (r *Repository) ListAttributeRules(ctx context.Context, scopeId string, opt ...Option) ([]*AttributeRule, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list attribute rules: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	var rules []*AttributeRule
+	if err := r.reader.SearchWhere(ctx, &rules, "scope_id = ?", []interface{}{scopeId}, db.WithOrder("create_time")); err != nil {
+		return nil, fmt.Errorf("list attribute rules: %w", err)
+	}
+	return rules, nil
+}
+
+// DeleteAttributeRule deletes the attribute rule with id and returns the
+// number of rules deleted. The aliases the rule gave users are deleted with
+// it, while the group memberships it added are kept. All options are
+// ignored.
+func (r *Repository) DeleteAttributeRule(ctx context.Context, id string, opt ...Option) (int, error) {
+	if id == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete attribute rule: missing public id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.writer.Delete(ctx, &AttributeRule{PublicId: id})
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("delete attribute rule: %s: %w", id, err)
+	}
+	return rows, nil
+}
+
+// ApplyAccountAttributes stores attrs as the attributes of the account with
+// id accountId and applies the attribute rules of the account's scope to its
+// user, in one transaction. It should be called every time the account
+// authenticates, after its user has been looked up. Group memberships are
+// changed without incrementing the version of the groups. An alias already
+// held by another user isn't reassigned. All options are ignored.
+func (r *Repository) ApplyAccountAttributes(ctx context.Context, accountId string, attrs map[string]interface{}, opt ...Option) (*AttributeRuleResults, error) {
+	if accountId == "" {
+		return nil, fmt.Errorf("apply account attributes: missing account id: %w", db.ErrInvalidParameter)
+	}
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, fmt.Errorf("apply account attributes: unable to encode attributes: %w", err)
+	}
+
+	var results *AttributeRuleResults
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			results = &AttributeRuleResults{}
+			scopeId, userId, err := accountScopeUser(ctx, reader, accountId)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Exec(ctx, setAccountAttributesQuery, []interface{}{accountId, string(encoded)}); err != nil {
+				return fmt.Errorf("unable to set attributes: %w", err)
+			}
+			if userId == "" {
+				return nil
+			}
+
+			var rules []*AttributeRule
+			if err := reader.SearchWhere(ctx, &rules, "scope_id = ?", []interface{}{scopeId}); err != nil {
+				return fmt.Errorf("unable to list rules: %w", err)
+			}
+
+			// Groups are added first so a group kept by any matching rule
+			// isn't removed because another rule for it doesn't match.
+			granted := make(map[string]bool)
+			for _, rule := range rules {
+				if rule.GroupId == "" || !rule.Matches(attrs) {
+					continue
+				}
+				added, err := w.Exec(ctx, addRuleMemberQuery, []interface{}{rule.GroupId, userId})
+				if err != nil {
+					return fmt.Errorf("unable to add member of group %s: %w", rule.GroupId, err)
+				}
+				if _, err := w.Exec(ctx, recordRuleMemberQuery, []interface{}{rule.PublicId, rule.GroupId, userId, added > 0, rule.GroupId, userId}); err != nil {
+					return fmt.Errorf("unable to record member of group %s: %w", rule.GroupId, err)
+				}
+				granted[rule.GroupId] = true
+			}
+
+			for _, rule := range rules {
+				switch {
+				case rule.GroupId != "" && !rule.Matches(attrs):
+					query := deleteRuleMemberQuery
+					if granted[rule.GroupId] {
+						query = forgetRuleMemberQuery
+					}
+					if _, err := w.Exec(ctx, query, []interface{}{rule.PublicId, userId}); err != nil {
+						return fmt.Errorf("unable to remove member of group %s: %w", rule.GroupId, err)
+					}
+
+				case rule.AliasAttribute != "":
+					alias, ok := rule.alias(attrs)
+					if !rule.Matches(attrs) || !ok {
+						alias = ""
+					}
+					if _, err := w.Exec(ctx, deleteUserAliasesQuery, []interface{}{rule.PublicId, userId, alias}); err != nil {
+						return fmt.Errorf("unable to delete aliases: %w", err)
+					}
+					if alias == "" {
+						continue
+					}
+					if _, err := w.Exec(ctx, addUserAliasQuery, []interface{}{scopeId, alias, userId, rule.PublicId}); err != nil {
+						return fmt.Errorf("unable to add alias: %w", err)
+					}
+					owner, err := aliasOwner(ctx, reader, scopeId, alias)
+					if err != nil {
+						return err
+					}
+					if owner == userId {
+						results.Aliases = append(results.Aliases, alias)
+					}
+				}
+			}
+
+			for id := range granted {
+				results.GroupIds = append(results.GroupIds, id)
+			}
+			sort.Strings(results.GroupIds)
+			sort.Strings(results.Aliases)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("apply account attributes: %s: %w", accountId, err)
+	}
+	return results, nil
+}
+
+// accountScopeUser returns the scope and the id of the user of the account
+// with id accountId. The user id is empty if the account has no user.
+func accountScopeUser(ctx context.Context, reader db.Reader, accountId string) (string, string, error) {
+	rows, err := reader.Query(ctx, accountScopeUserQuery, []interface{}{accountId})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to look up account: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", "", fmt.Errorf("unable to look up account: %w", err)
+		}
+		return "", "", fmt.Errorf("account %s: %w", accountId, db.ErrRecordNotFound)
+	}
+	var scopeId string
+	var userId sql.NullString
+	if err := rows.Scan(&scopeId, &userId); err != nil {
+		return "", "", fmt.Errorf("unable to look up account: %w", err)
+	}
+	return scopeId, userId.String, nil
+}
+
+// aliasOwner returns the id of the user with alias in scopeId.
+func aliasOwner(ctx context.Context, reader db.Reader, scopeId, alias string) (string, error) {
+	rows, err := reader.Query(ctx, userAliasOwnerQuery, []interface{}{scopeId, alias})
+	if err != nil {
+		return "", fmt.Errorf("unable to look up alias: %w", err)
+	}
+	defer rows.Close()
+	var userId string
+	for rows.Next() {
+		if err := rows.Scan(&userId); err != nil {
+			return "", fmt.Errorf("unable to look up alias: %w", err)
+		}
+	}
+	return userId, rows.Err()
+}
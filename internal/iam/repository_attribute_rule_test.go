@@ -0,0 +1,182 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CreateAttributeRule(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	org, proj := TestScopes(t, repo)
+	otherOrg, _ := TestScopes(t, repo)
+	orgGroup := TestGroup(t, conn, org.PublicId)
+	projGroup := TestGroup(t, conn, proj.PublicId)
+	otherGroup := TestGroup(t, conn, otherOrg.PublicId)
+
+	tests := []struct {
+		name      string
+		rule      *AttributeRule
+		wantErrIs error
+	}{
+		{
+			name: "org group",
+			rule: &AttributeRule{ScopeId: org.PublicId, Attribute: "groups", Operator: AttributeEquals, Value: "eng", GroupId: orgGroup.PublicId},
+		},
+		{
+			name: "project group",
+			rule: &AttributeRule{ScopeId: org.PublicId, Attribute: "groups", Operator: AttributeEquals, Value: "ops", GroupId: projGroup.PublicId},
+		},
+		{
+			name: "alias",
+			rule: &AttributeRule{ScopeId: org.PublicId, Attribute: "email", Operator: AttributeExists, AliasAttribute: "email"},
+		},
+		{
+			name:      "group in another org",
+			rule:      &AttributeRule{ScopeId: org.PublicId, Attribute: "groups", Operator: AttributeExists, GroupId: otherGroup.PublicId},
+			wantErrIs: db.ErrInvalidParameter,
+		},
+		{
+			name:      "missing group",
+			rule:      &AttributeRule{ScopeId: org.PublicId, Attribute: "groups", Operator: AttributeExists, GroupId: "g_1234567890"},
+			wantErrIs: db.ErrRecordNotFound,
+		},
+		{
+			name:      "project scope",
+			rule:      &AttributeRule{ScopeId: proj.PublicId, Attribute: "groups", Operator: AttributeExists, GroupId: projGroup.PublicId},
+			wantErrIs: db.ErrInvalidParameter,
+		},
+		{
+			name:      "unknown operator",
+			rule:      &AttributeRule{ScopeId: org.PublicId, Attribute: "groups", Operator: "matches", GroupId: orgGroup.PublicId},
+			wantErrIs: db.ErrInvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.CreateAttributeRule(ctx, tt.rule)
+			if tt.wantErrIs != nil {
+				require.Error(err)
+				assert.True(errors.Is(err, tt.wantErrIs), "unexpected error %v", err)
+				return
+			}
+			require.NoError(err)
+			assert.NotEmpty(got.PublicId)
+			assert.Empty(tt.rule.PublicId)
+			assert.False(got.CreateTime.IsZero())
+		})
+	}
+
+	rules, err := repo.ListAttributeRules(ctx, org.PublicId)
+	require.NoError(t, err)
+	assert.Len(t, rules, 3)
+
+	deleted, err := repo.DeleteAttributeRule(ctx, rules[0].PublicId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	rules, err = repo.ListAttributeRules(ctx, org.PublicId)
+	require.NoError(t, err)
+	assert.Len(t, rules, 2)
+}
+
+func TestRepository_ApplyAccountAttributes(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	org, proj := TestScopes(t, repo)
+	authMethodId := testAuthMethod(t, conn, org.PublicId)
+	user := TestUser(t, repo, org.PublicId)
+	acct := testAccount(t, conn, org.PublicId, authMethodId, user.PublicId)
+	otherUser := TestUser(t, repo, org.PublicId)
+	otherAcct := testAccount(t, conn, org.PublicId, authMethodId, otherUser.PublicId)
+
+	engGroup := TestGroup(t, conn, org.PublicId)
+	opsGroup := TestGroup(t, conn, proj.PublicId)
+	directGroup := TestGroup(t, conn, org.PublicId)
+	TestGroupMember(t, conn, directGroup.PublicId, user.PublicId)
+
+	newRule := func(r *AttributeRule) *AttributeRule {
+		t.Helper()
+		r.ScopeId = org.PublicId
+		r, err := repo.CreateAttributeRule(ctx, r)
+		require.NoError(t, err)
+		return r
+	}
+	newRule(&AttributeRule{Attribute: "groups", Operator: AttributeEquals, Value: "eng", GroupId: engGroup.PublicId})
+	newRule(&AttributeRule{Attribute: "groups", Operator: AttributeEquals, Value: "ops", GroupId: opsGroup.PublicId})
+	// Both rules keep the ops group
+	newRule(&AttributeRule{Attribute: "oncall", Operator: AttributeExists, GroupId: opsGroup.PublicId})
+	// Memberships added directly are never removed by rules
+	newRule(&AttributeRule{Attribute: "contractor", Operator: AttributeExists, GroupId: directGroup.PublicId})
+	newRule(&AttributeRule{Attribute: "email", Operator: AttributeContains, Value: "@example.com", AliasAttribute: "email"})
+
+	members := func(g *Group) []string {
+		t.Helper()
+		ms, err := repo.ListGroupMembers(ctx, g.PublicId)
+		require.NoError(t, err)
+		var ids []string
+		for _, m := range ms {
+			ids = append(ids, m.MemberId)
+		}
+		return ids
+	}
+
+	assert, require := assert.New(t), require.New(t)
+	got, err := repo.ApplyAccountAttributes(ctx, acct.PublicId, map[string]interface{}{
+		"groups":     []interface{}{"eng", "ops"},
+		"oncall":     true,
+		"contractor": true,
+		"email":      "alice@example.com",
+	})
+	require.NoError(err)
+	assert.ElementsMatch([]string{engGroup.PublicId, opsGroup.PublicId, directGroup.PublicId}, got.GroupIds)
+	assert.Equal([]string{"alice@example.com"}, got.Aliases)
+	assert.Equal([]string{user.PublicId}, members(engGroup))
+	assert.Equal([]string{user.PublicId}, members(opsGroup))
+
+	// The ops group is kept by the oncall rule
+	got, err = repo.ApplyAccountAttributes(ctx, acct.PublicId, map[string]interface{}{
+		"groups": []interface{}{"eng"},
+		"oncall": true,
+		"email":  "alice@example.com",
+	})
+	require.NoError(err)
+	assert.ElementsMatch([]string{engGroup.PublicId, opsGroup.PublicId}, got.GroupIds)
+	assert.Equal([]string{user.PublicId}, members(opsGroup))
+
+	// Memberships are removed once no rule matches, except those added
+	// directly, and the alias follows the attribute
+	got, err = repo.ApplyAccountAttributes(ctx, acct.PublicId, map[string]interface{}{
+		"email": "alice.smith@example.com",
+	})
+	require.NoError(err)
+	assert.Empty(got.GroupIds)
+	assert.Equal([]string{"alice.smith@example.com"}, got.Aliases)
+	assert.Empty(members(engGroup))
+	assert.Empty(members(opsGroup))
+	assert.Equal([]string{user.PublicId}, members(directGroup))
+
+	// An alias isn't taken from the user who has it
+	got, err = repo.ApplyAccountAttributes(ctx, otherAcct.PublicId, map[string]interface{}{
+		"email": "alice.smith@example.com",
+	})
+	require.NoError(err)
+	assert.Empty(got.Aliases)
+
+	_, err = repo.ApplyAccountAttributes(ctx, "acct_1234567890", nil)
+	require.Error(err)
+	assert.True(errors.Is(err, db.ErrRecordNotFound))
+}
@@ -2,9 +2,11 @@ package iam
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	dbcommon "github.com/hashicorp/boundary/internal/db/common"
@@ -149,7 +151,9 @@ func (r *Repository) LookupGroup(ctx context.Context, withPublicId string, opt .
 	return &g, members, nil
 }
 
-// DeleteGroup will delete a group from the repository.
+// DeleteGroup will delete a group from the repository. It returns a
+// ResourceInUse error listing the roles the group is assigned to, unless the
+// WithForce option is set.
 func (r *Repository) DeleteGroup(ctx context.Context, withPublicId string, opt ...Option) (int, error) {
 	if withPublicId == "" {
 		return db.NoRowsAffected, fmt.Errorf("delete group: missing public id %w", db.ErrInvalidParameter)
@@ -159,20 +163,21 @@ func (r *Repository) DeleteGroup(ctx context.Context, withPublicId string, opt .
 	if err := r.reader.LookupByPublicId(ctx, &g); err != nil {
 		return db.NoRowsAffected, fmt.Errorf("delete group: failed %w for %s", err, withPublicId)
 	}
-	rowsDeleted, err := r.delete(ctx, &g)
+	rowsDeleted, err := r.delete(ctx, &g, append(opt, withReferencesQuery(groupReferencesQuery))...)
 	if err != nil {
 		return db.NoRowsAffected, fmt.Errorf("delete group: failed %w for %s", err, withPublicId)
 	}
 	return rowsDeleted, nil
 }
 
-// ListGroups in a scope and supports WithLimit option.
+// ListGroups in a scope and supports the WithLimit, WithName and WithOrder options.
 func (r *Repository) ListGroups(ctx context.Context, withScopeId string, opt ...Option) ([]*Group, error) {
 	if withScopeId == "" {
 		return nil, fmt.Errorf("list groups: missing scope id %w", db.ErrInvalidParameter)
 	}
 	var grps []*Group
-	err := r.list(ctx, &grps, "scope_id = ?", []interface{}{withScopeId}, opt...)
+	where, args := withNameCondition("scope_id = ?", []interface{}{withScopeId}, opt...)
+	err := r.list(ctx, &grps, where, args, opt...)
 	if err != nil {
 		return nil, fmt.Errorf("list groups: %w", err)
 	}
@@ -191,6 +196,39 @@ func (r *Repository) ListGroupMembers(ctx context.Context, withGroupId string, o
 	return members, nil
 }
 
+// ListGroupMembersAt returns the memberships of the group groupId which
+// included the time at, ordered by member id. The group and its members
+// need not exist anymore. Memberships are recorded from when the history of
+// memberships was added; memberships removed before then aren't known. No
+// options are currently supported.
+func (r *Repository) ListGroupMembersAt(ctx context.Context, groupId string, at time.Time, opt ...Option) ([]*GroupMembership, error) {
+	if groupId == "" {
+		return nil, fmt.Errorf("list group members at: missing group id: %w", db.ErrInvalidParameter)
+	}
+	if at.IsZero() {
+		return nil, fmt.Errorf("list group members at: missing time: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, groupMembersAtQuery, []interface{}{groupId, at})
+	if err != nil {
+		return nil, fmt.Errorf("list group members at: %w", err)
+	}
+	defer rows.Close()
+	var memberships []*GroupMembership
+	for rows.Next() {
+		m := &GroupMembership{}
+		var validTo sql.NullTime
+		if err := rows.Scan(&m.GroupId, &m.MemberId, &m.ValidFrom, &validTo); err != nil {
+			return nil, fmt.Errorf("list group members at: %w", err)
+		}
+		m.ValidTo = validTo.Time
+		memberships = append(memberships, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list group members at: %w", err)
+	}
+	return memberships, nil
+}
+
 // AddGroupMembers provides the ability to add members (userIds) to a group
 // (groupId).  The group's current db version must match the groupVersion or an
 // error will be returned.  Zero is not a valid value for the WithVersion option
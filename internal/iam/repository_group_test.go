@@ -2,13 +2,13 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	dbassert "github.com/hashicorp/boundary/internal/db/assert"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/go-uuid"
 	"github.com/stretchr/testify/assert"
@@ -545,6 +545,25 @@ func TestRepository_DeleteGroup(t *testing.T) {
 			assert.NoError(err)
 		})
 	}
+	t.Run("in-use", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		g := TestGroup(t, conn, org.PublicId)
+		role := TestRole(t, conn, org.PublicId)
+		TestGroupRole(t, conn, role.PublicId, g.PublicId)
+
+		deletedRows, err := repo.DeleteGroup(context.Background(), g.PublicId)
+		require.Error(err)
+		assert.Equal(0, deletedRows)
+		assert.True(errors.Is(err, errors.ErrResourceInUse))
+		var inUse *errors.ResourceInUse
+		require.True(errors.As(err, &inUse))
+		assert.Equal(g.PublicId, inUse.PublicId)
+		assert.Equal([]string{role.PublicId}, inUse.ReferencedBy)
+
+		deletedRows, err = repo.DeleteGroup(context.Background(), g.PublicId, WithForce(true))
+		require.NoError(err)
+		assert.Equal(1, deletedRows)
+	})
 }
 
 func TestRepository_ListGroups(t *testing.T) {
@@ -1136,3 +1155,72 @@ func TestRepository_SetGroupMembers(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_ListGroupMembersAt(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	org, _ := TestScopes(t, repo)
+	ctx := context.Background()
+
+	dbNow := func() time.Time {
+		rows, err := rw.Query(ctx, "select current_timestamp", nil)
+		require.NoError(err)
+		defer rows.Close()
+		require.True(rows.Next())
+		var now time.Time
+		require.NoError(rows.Scan(&now))
+		return now
+	}
+	memberIds := func(ms []*GroupMembership) []string {
+		var ids []string
+		for _, m := range ms {
+			ids = append(ids, m.MemberId)
+		}
+		return ids
+	}
+
+	_, err := repo.ListGroupMembersAt(ctx, "", time.Now())
+	assert.True(errors.Is(err, db.ErrInvalidParameter))
+	_, err = repo.ListGroupMembersAt(ctx, "g_1234567890", time.Time{})
+	assert.True(errors.Is(err, db.ErrInvalidParameter))
+
+	g := TestGroup(t, conn, org.PublicId)
+	u1, u2 := TestUser(t, repo, org.PublicId), TestUser(t, repo, org.PublicId)
+	before := dbNow()
+	_, err = repo.AddGroupMembers(ctx, g.PublicId, 1, []string{u1.PublicId, u2.PublicId})
+	require.NoError(err)
+	bothMembers := dbNow()
+	_, err = repo.DeleteGroupMembers(ctx, g.PublicId, 2, []string{u1.PublicId})
+	require.NoError(err)
+	oneMember := dbNow()
+	_, err = repo.DeleteGroup(ctx, g.PublicId)
+	require.NoError(err)
+	deleted := dbNow()
+
+	got, err := repo.ListGroupMembersAt(ctx, g.PublicId, before)
+	require.NoError(err)
+	assert.Empty(got)
+
+	got, err = repo.ListGroupMembersAt(ctx, g.PublicId, bothMembers)
+	require.NoError(err)
+	wantIds := []string{u1.PublicId, u2.PublicId}
+	sort.Strings(wantIds)
+	assert.Equal(wantIds, memberIds(got))
+	for _, m := range got {
+		assert.Equal(g.PublicId, m.GroupId)
+		assert.False(m.ValidTo.IsZero())
+	}
+
+	got, err = repo.ListGroupMembersAt(ctx, g.PublicId, oneMember)
+	require.NoError(err)
+	assert.Equal([]string{u2.PublicId}, memberIds(got))
+	assert.True(got[0].ValidFrom.Before(oneMember))
+
+	got, err = repo.ListGroupMembersAt(ctx, g.PublicId, deleted)
+	require.NoError(err)
+	assert.Empty(got)
+}
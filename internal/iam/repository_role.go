@@ -2,12 +2,14 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/boundary/internal/db"
 	dbcommon "github.com/hashicorp/boundary/internal/db/common"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
 )
 
 // CreateRole will create a role in the repository and return the written
@@ -31,9 +33,17 @@ func (r *Repository) CreateRole(ctx context.Context, role *Role, opt ...Option)
 	}
 	c := role.Clone().(*Role)
 	c.PublicId = id
+	if r.uniqueNames {
+		if err := conflictingRoleName(ctx, r.reader, c.ScopeId, c.PublicId, c.Name); err != nil {
+			return nil, fmt.Errorf("create role: %w", err)
+		}
+	}
 	resource, err := r.create(ctx, c)
 	if err != nil {
 		if db.IsUniqueError(err) {
+			if nuErr := conflictingRoleName(ctx, r.reader, c.ScopeId, c.PublicId, c.Name); errors.Is(nuErr, errors.ErrNotUnique) {
+				return nil, fmt.Errorf("create role: %w", nuErr)
+			}
 			return nil, fmt.Errorf("create role: role %s already exists in scope %s: %w", role.Name, role.ScopeId, db.ErrNotUnique)
 		}
 		return nil, fmt.Errorf("create role: %w for %s", err, c.PublicId)
@@ -90,6 +100,11 @@ func (r *Repository) UpdateRole(ctx context.Context, role *Role, version uint32,
 		func(read db.Reader, w db.Writer) error {
 			var err error
 			c := role.Clone().(*Role)
+			if r.uniqueNames && containsField(dbMask, "name") {
+				if err := conflictingRoleName(ctx, read, c.ScopeId, c.PublicId, c.Name); err != nil {
+					return err
+				}
+			}
 			resource, rowsUpdated, err = r.update(ctx, c, version, dbMask, nullFields)
 			if err != nil {
 				return err
@@ -111,8 +126,14 @@ func (r *Repository) UpdateRole(ctx context.Context, role *Role, version uint32,
 	)
 	if err != nil {
 		if db.IsUniqueError(err) {
+			if nuErr := conflictingRoleName(ctx, r.reader, role.ScopeId, role.PublicId, role.Name); errors.Is(nuErr, errors.ErrNotUnique) {
+				return nil, nil, nil, db.NoRowsAffected, fmt.Errorf("update role: %w", nuErr)
+			}
 			return nil, nil, nil, db.NoRowsAffected, fmt.Errorf("update role: role %s already exists in org %s: %w", role.Name, role.ScopeId, db.ErrNotUnique)
 		}
+		if errors.Is(err, errors.ErrNotUnique) {
+			return nil, nil, nil, db.NoRowsAffected, fmt.Errorf("update role: %w", err)
+		}
 		return nil, nil, nil, db.NoRowsAffected, fmt.Errorf("update role: %w for %s", err, role.PublicId)
 	}
 	return resource.(*Role), pr, rg, rowsUpdated, err
@@ -177,15 +198,179 @@ func (r *Repository) DeleteRole(ctx context.Context, withPublicId string, opt ..
 	return rowsDeleted, nil
 }
 
-// ListRoles in a scope and supports WithLimit option.
+// ListRoles in a scope and supports the WithLimit, WithName and WithOrder options.
 func (r *Repository) ListRoles(ctx context.Context, withScopeId string, opt ...Option) ([]*Role, error) {
 	if withScopeId == "" {
 		return nil, fmt.Errorf("list roles: missing scope id %w", db.ErrInvalidParameter)
 	}
 	var roles []*Role
-	err := r.list(ctx, &roles, "scope_id = ?", []interface{}{withScopeId}, opt...)
+	where, args := withNameCondition("scope_id = ?", []interface{}{withScopeId}, opt...)
+	err := r.list(ctx, &roles, where, args, opt...)
 	if err != nil {
 		return nil, fmt.Errorf("list roles: %w", err)
 	}
 	return roles, nil
 }
+
+// CloneRole creates a copy of the role with roleId in the same scope, with
+// its description, grant scope and grants. Principals are not copied, so the
+// copy grants nothing until principals are added to it. The copy has no name
+// unless WithName is given; WithDescription replaces the copied
+// description. The copy and its grants are returned.
+func (r *Repository) CloneRole(ctx context.Context, roleId string, opt ...Option) (*Role, []*RoleGrant, error) {
+	if roleId == "" {
+		return nil, nil, fmt.Errorf("clone role: missing role id: %w", db.ErrInvalidParameter)
+	}
+	src, _, grants, err := r.LookupRole(ctx, roleId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clone role: %w", err)
+	}
+	if src == nil {
+		return nil, nil, fmt.Errorf("clone role: %s: %w", roleId, db.ErrRecordNotFound)
+	}
+	opts := getOpts(opt...)
+	role, err := NewRole(src.ScopeId, WithName(opts.withName), WithDescription(src.Description), WithGrantScopeId(src.GrantScopeId))
+	if err != nil {
+		return nil, nil, fmt.Errorf("clone role: %w", err)
+	}
+	if opts.withDescription != "" {
+		role.Description = opts.withDescription
+	}
+	rawGrants := make([]string, 0, len(grants))
+	for _, g := range grants {
+		rawGrants = append(rawGrants, g.RawGrant)
+	}
+	cloned, clonedGrants, err := r.createRoleWithGrants(ctx, role, rawGrants)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clone role: %w", err)
+	}
+	return cloned, clonedGrants, nil
+}
+
+// createRoleWithGrants creates role along with grants in one transaction,
+// returning the created role and grants.
+func (r *Repository) createRoleWithGrants(ctx context.Context, role *Role, grants []string) (*Role, []*RoleGrant, error) {
+	id, err := newRoleId()
+	if err != nil {
+		return nil, nil, err
+	}
+	c := role.Clone().(*Role)
+	c.PublicId = id
+	if r.uniqueNames {
+		if err := conflictingRoleName(ctx, r.reader, c.ScopeId, c.PublicId, c.Name); err != nil {
+			return nil, nil, err
+		}
+	}
+	newGrants := make([]interface{}, 0, len(grants))
+	for _, g := range grants {
+		rg, err := NewRoleGrant(id, g)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create in memory role grant: %w", err)
+		}
+		newGrants = append(newGrants, rg)
+	}
+	metadata, err := r.stdMetadata(ctx, c)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get metadata: %w", err)
+	}
+	metadata["op-type"] = []string{oplog.OpType_OP_TYPE_CREATE.String()}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, c.ScopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get oplog wrapper: %w", err)
+	}
+
+	var created *Role
+	var createdGrants []*RoleGrant
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			created = c.Clone().(*Role)
+			if err := w.Create(ctx, created, db.WithOplog(oplogWrapper, metadata)); err != nil {
+				return fmt.Errorf("unable to create role: %w", err)
+			}
+			createdGrants = nil
+			if len(newGrants) == 0 {
+				return nil
+			}
+
+			// The role is the aggregate, so its version is updated along with
+			// adding the grants.
+			msgs := make([]*oplog.Message, 0, len(newGrants)+1)
+			roleTicket, err := w.GetTicket(created)
+			if err != nil {
+				return fmt.Errorf("unable to get ticket: %w", err)
+			}
+			var roleOplogMsg oplog.Message
+			rowsUpdated, err := w.Update(ctx, created, []string{"Version"}, nil, db.NewOplogMsg(&roleOplogMsg), db.WithVersion(&created.Version))
+			if err != nil {
+				return fmt.Errorf("unable to update role version for adding grants: %w", err)
+			}
+			if rowsUpdated != 1 {
+				return fmt.Errorf("updated role but %d rows updated", rowsUpdated)
+			}
+			msgs = append(msgs, &roleOplogMsg)
+			roleGrantOplogMsgs := make([]*oplog.Message, 0, len(newGrants))
+			if err := w.CreateItems(ctx, newGrants, db.NewOplogMsgs(&roleGrantOplogMsgs)); err != nil {
+				return fmt.Errorf("unable to add grants: %w", err)
+			}
+			msgs = append(msgs, roleGrantOplogMsgs...)
+			if err := w.WriteOplogEntryWith(ctx, oplogWrapper, roleTicket, metadata, msgs); err != nil {
+				return fmt.Errorf("unable to write oplog: %w", err)
+			}
+			for _, g := range newGrants {
+				createdGrants = append(createdGrants, g.(*RoleGrant))
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		if db.IsUniqueError(err) {
+			if nuErr := conflictingRoleName(ctx, r.reader, c.ScopeId, c.PublicId, c.Name); errors.Is(nuErr, errors.ErrNotUnique) {
+				return nil, nil, nuErr
+			}
+			return nil, nil, fmt.Errorf("role %s already exists in scope %s: %w", c.Name, c.ScopeId, db.ErrNotUnique)
+		}
+		return nil, nil, fmt.Errorf("%w for %s", err, c.PublicId)
+	}
+	return created, createdGrants, nil
+}
+
+// conflictingRoleName returns a NotUnique error if a role other than the one
+// with publicId has the name within the scope, ignoring case. If scopeId is
+// empty the scope of the role with publicId is used. It returns nil if there
+// is no such role.
+func conflictingRoleName(ctx context.Context, r db.Reader, scopeId, publicId, name string) error {
+	if name == "" {
+		return nil
+	}
+	rows, err := r.Query(ctx, conflictingRoleNameQuery, []interface{}{scopeId, publicId, name})
+	if err != nil {
+		return fmt.Errorf("conflicting role name: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var conflictingId, conflictingScopeId string
+		if err := rows.Scan(&conflictingId, &conflictingScopeId); err != nil {
+			return fmt.Errorf("conflicting role name: %w", err)
+		}
+		return &errors.NotUnique{
+			Resource:      "role",
+			Name:          name,
+			ScopeId:       conflictingScopeId,
+			ConflictingId: conflictingId,
+		}
+	}
+	return rows.Err()
+}
+
+// containsField returns true if the field is one of the fields.
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
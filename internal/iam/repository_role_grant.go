@@ -3,11 +3,13 @@ package iam
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/types/resource"
 )
 
 // AddRoleGrant will add role grants associated with the role ID in the
@@ -462,3 +464,73 @@ select role_scope as scope_id, role_grant as grant from final;
 	}
 	return grants, nil
 }
+
+// authorizedResourceTables gives, for each type of resource listed within a
+// scope, the table it's looked up in, the table's scope column and the
+// table's column for the user a resource belongs to, if any.
+var authorizedResourceTables = map[resource.Type]struct {
+	table, scopeColumn, ownerColumn string
+}{
+	resource.Scope:       {"iam_scope", "parent_id", ""},
+	resource.User:        {"iam_user", "scope_id", ""},
+	resource.Group:       {"iam_group", "scope_id", ""},
+	resource.Role:        {"iam_role", "scope_id", ""},
+	resource.AuthMethod:  {"auth_method", "scope_id", ""},
+	resource.AuthToken:   {"auth_token_account", "scope_id", "iam_user_id"},
+	resource.HostCatalog: {"host_catalog", "scope_id", ""},
+	resource.Target:      {"target", "scope_id", ""},
+	resource.Session:     {"session", "scope_id", "user_id"},
+}
+
+// ListAuthorizedResources returns the ids of the resources of type typ within
+// scopeId which are described by p, as returned by the ACL of userId. Only
+// types of resources which are listed within a scope are supported. No
+// options are currently supported.
+func (r *Repository) ListAuthorizedResources(ctx context.Context, userId, scopeId string, typ resource.Type, p perms.ResourcePermissions, opt ...Option) ([]string, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list authorized resources: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	t, ok := authorizedResourceTables[typ]
+	if !ok {
+		return nil, fmt.Errorf("list authorized resources: unsupported resource type %q: %w", typ.String(), db.ErrInvalidParameter)
+	}
+
+	args := []interface{}{scopeId}
+	var conditions []string
+	switch {
+	case p.All:
+		conditions = append(conditions, "true")
+	default:
+		if len(p.Ids) > 0 {
+			var inClauseSpots []string
+			for _, id := range p.Ids {
+				args = append(args, id)
+				inClauseSpots = append(inClauseSpots, fmt.Sprintf("$%d", len(args)))
+			}
+			conditions = append(conditions, fmt.Sprintf("public_id in (%s)", strings.Join(inClauseSpots, ",")))
+		}
+		if p.OnlySelf && t.ownerColumn != "" {
+			args = append(args, userId)
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", t.ownerColumn, len(args)))
+		}
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(authorizedResourcesQuery, t.table, t.scopeColumn, strings.Join(conditions, " or "))
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("list authorized resources: %w", err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("list authorized resources: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
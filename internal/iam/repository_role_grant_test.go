@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -564,3 +567,68 @@ func TestRepository_SetRoleGrants_Parameters(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_ListAuthorizedResources(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	org, proj := TestScopes(t, repo)
+	user := TestUser(t, repo, org.PublicId)
+
+	var groupIds []string
+	for i := 0; i < 3; i++ {
+		groupIds = append(groupIds, TestGroup(t, conn, org.PublicId).PublicId)
+	}
+	sort.Strings(groupIds)
+	projGroup := TestGroup(t, conn, proj.PublicId)
+
+	tests := []struct {
+		name    string
+		typ     resource.Type
+		perms   perms.ResourcePermissions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "all",
+			typ:   resource.Group,
+			perms: perms.ResourcePermissions{All: true},
+			want:  groupIds,
+		},
+		{
+			name:  "ids",
+			typ:   resource.Group,
+			perms: perms.ResourcePermissions{Ids: []string{groupIds[2], groupIds[0], projGroup.PublicId, "r_1234567890"}},
+			want:  []string{groupIds[0], groupIds[2]},
+		},
+		{
+			name:  "self without owners",
+			typ:   resource.Group,
+			perms: perms.ResourcePermissions{OnlySelf: true},
+		},
+		{
+			name: "nothing",
+			typ:  resource.Group,
+		},
+		{
+			name:    "unsupported type",
+			typ:     resource.Host,
+			perms:   perms.ResourcePermissions{All: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.ListAuthorizedResources(context.Background(), user.PublicId, org.PublicId, tt.typ, tt.perms)
+			if tt.wantErr {
+				require.Error(err)
+				assert.True(errors.Is(err, db.ErrInvalidParameter))
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
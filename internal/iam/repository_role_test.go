@@ -2,13 +2,13 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	dbassert "github.com/hashicorp/boundary/internal/db/assert"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/iam/store"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/go-uuid"
@@ -171,6 +171,69 @@ func TestRepository_CreateRole(t *testing.T) {
 	}
 }
 
+func TestRepository_UniqueRoleNames(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	uniqueRepo := TestRepo(t, conn, wrapper, WithUniqueNames(true))
+	org, proj := TestScopes(t, repo)
+	existing := TestRole(t, conn, org.PublicId, WithName("Admins"))
+
+	t.Run("exact-match", func(t *testing.T) {
+		require, assert := require.New(t), assert.New(t)
+		role, err := NewRole(org.PublicId, WithName("Admins"))
+		require.NoError(err)
+		_, err = repo.CreateRole(context.Background(), role)
+		require.Error(err)
+		var nuErr *errors.NotUnique
+		require.True(errors.As(err, &nuErr))
+		assert.Equal(existing.PublicId, nuErr.ConflictingId)
+		assert.True(errors.Is(err, db.ErrNotUnique))
+	})
+	t.Run("case-only-without-flag", func(t *testing.T) {
+		require := require.New(t)
+		role, err := NewRole(org.PublicId, WithName("ADMINS"))
+		require.NoError(err)
+		_, err = repo.CreateRole(context.Background(), role)
+		require.NoError(err)
+	})
+	t.Run("case-only-with-flag", func(t *testing.T) {
+		require, assert := require.New(t), assert.New(t)
+		role, err := NewRole(org.PublicId, WithName("admins"))
+		require.NoError(err)
+		_, err = uniqueRepo.CreateRole(context.Background(), role)
+		require.Error(err)
+		var nuErr *errors.NotUnique
+		require.True(errors.As(err, &nuErr))
+		assert.Equal(existing.PublicId, nuErr.ConflictingId)
+
+		other := TestRole(t, conn, org.PublicId, WithName("other"))
+		update := other.Clone().(*Role)
+		update.Name = "aDMINs"
+		_, _, _, _, err = uniqueRepo.UpdateRole(context.Background(), update, other.Version, []string{"Name"})
+		require.Error(err)
+		require.True(errors.As(err, &nuErr))
+		assert.Equal(existing.PublicId, nuErr.ConflictingId)
+	})
+	t.Run("unnamed-with-flag", func(t *testing.T) {
+		require := require.New(t)
+		for i := 0; i < 2; i++ {
+			role, err := NewRole(org.PublicId)
+			require.NoError(err)
+			_, err = uniqueRepo.CreateRole(context.Background(), role)
+			require.NoError(err)
+		}
+	})
+	t.Run("other-scope-with-flag", func(t *testing.T) {
+		require := require.New(t)
+		role, err := NewRole(proj.PublicId, WithName("admins"))
+		require.NoError(err)
+		_, err = uniqueRepo.CreateRole(context.Background(), role)
+		require.NoError(err)
+	})
+}
+
 func TestRepository_UpdateRole(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -376,7 +439,7 @@ func TestRepository_UpdateRole(t *testing.T) {
 			newScopeId:  org.PublicId,
 			wantErr:     true,
 			wantDup:     true,
-			wantErrMsg:  " already exists in org " + org.PublicId,
+			wantErrMsg:  " already exists in scope " + org.PublicId,
 			wantIsError: db.ErrNotUnique,
 		},
 	}
@@ -638,3 +701,88 @@ func TestRepository_ListRoles(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_CloneRole(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+	org, proj := TestScopes(t, repo)
+
+	src := TestRole(t, conn, org.PublicId, WithDescription("source"), WithGrantScopeId(proj.PublicId))
+	TestRoleGrant(t, conn, src.PublicId, "id=*;type=target;actions=read")
+	TestRoleGrant(t, conn, src.PublicId, "id=*;type=session;actions=list")
+	user := TestUser(t, repo, org.PublicId)
+	TestUserRole(t, conn, src.PublicId, user.PublicId)
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, grants, err := repo.CloneRole(ctx, src.PublicId, WithName("copy"))
+		require.NoError(err)
+		require.NotNil(got)
+		assert.NotEqual(src.PublicId, got.PublicId)
+		assert.Equal("copy", got.Name)
+		assert.Equal("source", got.Description)
+		assert.Equal(src.ScopeId, got.ScopeId)
+		assert.Equal(proj.PublicId, got.GrantScopeId)
+		assert.Len(grants, 2)
+
+		_, principals, lookedUpGrants, err := repo.LookupRole(ctx, got.PublicId)
+		require.NoError(err)
+		assert.Empty(principals)
+		var raw []string
+		for _, g := range lookedUpGrants {
+			raw = append(raw, g.RawGrant)
+		}
+		assert.ElementsMatch([]string{"id=*;type=target;actions=read", "id=*;type=session;actions=list"}, raw)
+		assert.NoError(db.TestVerifyOplog(t, db.New(conn), got.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+	})
+	t.Run("missing-id", func(t *testing.T) {
+		_, _, err := repo.CloneRole(ctx, "")
+		assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+	})
+	t.Run("not-found", func(t *testing.T) {
+		id, err := newRoleId()
+		require.NoError(t, err)
+		_, _, err = repo.CloneRole(ctx, id)
+		assert.True(t, errors.Is(err, db.ErrRecordNotFound))
+	})
+}
+
+func TestRepository_CreateRoleFromTemplate(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+	_, proj := TestScopes(t, repo)
+
+	for _, tmpl := range RoleTemplates() {
+		tmpl := tmpl
+		t.Run(tmpl.Name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, grants, err := repo.CreateRoleFromTemplate(ctx, proj.PublicId, tmpl.Name)
+			require.NoError(err)
+			assert.Equal(tmpl.RoleName, got.Name)
+			assert.Equal(tmpl.Description, got.Description)
+			assert.Equal(proj.PublicId, got.ScopeId)
+			assert.Len(grants, len(tmpl.Grants))
+		})
+	}
+	t.Run("with-name", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, _, err := repo.CreateRoleFromTemplate(ctx, proj.PublicId, "auditor", WithName("Project Auditor"), WithDescription("audits"))
+		require.NoError(err)
+		assert.Equal("Project Auditor", got.Name)
+		assert.Equal("audits", got.Description)
+	})
+	t.Run("duplicate-name", func(t *testing.T) {
+		_, _, err := repo.CreateRoleFromTemplate(ctx, proj.PublicId, "auditor")
+		assert.True(t, errors.Is(err, db.ErrNotUnique))
+	})
+	t.Run("unknown-template", func(t *testing.T) {
+		_, _, err := repo.CreateRoleFromTemplate(ctx, proj.PublicId, "superuser")
+		assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+	})
+}
@@ -157,6 +157,17 @@ func (r *Repository) CreateScope(ctx context.Context, s *Scope, userId string, o
 		db.StdRetryCnt,
 		db.ExpBackoff{},
 		func(dbr db.Reader, w db.Writer) error {
+			if opts.withAdminUserCheck {
+				u := allocUser()
+				u.PublicId = userId
+				if err := dbr.LookupByPublicId(ctx, &u); err != nil {
+					if errors.Is(err, db.ErrRecordNotFound) {
+						return fmt.Errorf("admin user %s not found: %w", userId, db.ErrInvalidParameter)
+					}
+					return fmt.Errorf("error looking up admin user: %w", err)
+				}
+			}
+
 			if err := w.Create(
 				ctx,
 				scopeRaw,
@@ -343,12 +354,37 @@ func (r *Repository) CreateScope(ctx context.Context, s *Scope, userId string, o
 	return scopeRaw.(*Scope), nil
 }
 
+// CreateScopeWithAdmin will create a scope in the repository like
+// CreateScope, along with an "Administration" role in the scope granting
+// adminUserId all actions. The scope, its keys, its default and admin roles
+// and their grants and principals are created in a single transaction, so
+// either all of them are created or none are.
+//
+// Unlike CreateScope, the admin role is never skipped: adminUserId must be
+// an existing user other than the anonymous, authenticated and recovery
+// users, and WithSkipAdminRoleCreation is not supported. An error wrapping
+// db.ErrInvalidParameter is returned otherwise. Supported options include:
+// WithPublicId, WithRandomReader and WithSkipDefaultRoleCreation.
+func (r *Repository) CreateScopeWithAdmin(ctx context.Context, s *Scope, adminUserId string, opt ...Option) (*Scope, error) {
+	switch adminUserId {
+	case "":
+		return nil, fmt.Errorf("create scope with admin: missing admin user id: %w", db.ErrInvalidParameter)
+	case "u_anon", "u_auth", "u_recovery":
+		return nil, fmt.Errorf("create scope with admin: %s cannot be an admin user: %w", adminUserId, db.ErrInvalidParameter)
+	}
+	if getOpts(opt...).withSkipAdminRoleCreation {
+		return nil, fmt.Errorf("create scope with admin: admin role creation cannot be skipped: %w", db.ErrInvalidParameter)
+	}
+	return r.CreateScope(ctx, s, adminUserId, append(opt, withAdminUserCheck(true))...)
+}
+
 // UpdateScope will update a scope in the repository and return the written
 // scope.  fieldMaskPaths provides field_mask.proto paths for fields that should
 // be updated.  Fields will be set to NULL if the field is a zero value and
-// included in fieldMask. Name and Description are the only updatable fields,
-// and everything else is ignored.  If no updatable fields are included in the
-// fieldMaskPaths, then an error is returned.
+// included in fieldMask. Name, Description and ReadOnly are the only
+// updatable fields, and everything else is ignored.  If no updatable fields
+// are included in the fieldMaskPaths, then an error is returned. While a scope
+// is read only, ReadOnly is the only field which can be updated.
 func (r *Repository) UpdateScope(ctx context.Context, scope *Scope, version uint32, fieldMaskPaths []string, opt ...Option) (*Scope, int, error) {
 	if scope == nil {
 		return nil, db.NoRowsAffected, fmt.Errorf("update scope: missing scope: %w", db.ErrInvalidParameter)
@@ -364,9 +400,10 @@ func (r *Repository) UpdateScope(ctx context.Context, scope *Scope, version uint
 		map[string]interface{}{
 			"name":        scope.Name,
 			"description": scope.Description,
+			"ReadOnly":    scope.ReadOnly,
 		},
 		fieldMaskPaths,
-		nil,
+		[]string{"ReadOnly"},
 	)
 	// nada to update, so reload scope from db and return it
 	if len(dbMask) == 0 && len(nullFields) == 0 {
@@ -383,6 +420,112 @@ func (r *Repository) UpdateScope(ctx context.Context, scope *Scope, version uint
 	return resource.(*Scope), rowsUpdated, err
 }
 
+// MoveScope will move the project with scopeId to the org with newParentId
+// and return the moved project. version must match the project's current
+// version. The project's name must be unique in the new org, or an error
+// wrapping db.ErrNotUnique is returned.
+//
+// The project can't be moved while a role in its current org grants access
+// to it, since the role would then grant access to a project in another org;
+// the roles' grant scopes must be changed first. Roles in the project, and
+// their principals, move with it. The project's keys are wrapped by its own
+// root key rather than by its org's keys, so they don't change.
+func (r *Repository) MoveScope(ctx context.Context, scopeId, newParentId string, version uint32, opt ...Option) (*Scope, int, error) {
+	if scopeId == "" {
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: missing public id: %w", db.ErrInvalidParameter)
+	}
+	if newParentId == "" {
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: missing parent id: %w", db.ErrInvalidParameter)
+	}
+	if version == 0 {
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: missing version: %w", db.ErrInvalidParameter)
+	}
+	p, err := r.LookupScope(ctx, scopeId)
+	if err != nil {
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: %w", err)
+	}
+	switch {
+	case p == nil:
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: %s: %w", scopeId, db.ErrRecordNotFound)
+	case p.Type != scope.Project.String():
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: %s is not a project: %w", scopeId, db.ErrInvalidParameter)
+	case p.ParentId == newParentId:
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: %s is already in %s: %w", scopeId, newParentId, db.ErrInvalidParameter)
+	}
+	org, err := r.LookupScope(ctx, newParentId)
+	if err != nil {
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: %w", err)
+	}
+	if org == nil || org.Type != scope.Org.String() {
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: %s is not an org: %w", newParentId, db.ErrInvalidParameter)
+	}
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: unable to get oplog wrapper: %w", err)
+	}
+	metadata := oplog.Metadata{
+		"resource-public-id": []string{scopeId},
+		"scope-id":           []string{newParentId},
+		"scope-type":         []string{p.Type},
+		"resource-type":      []string{p.ResourceType().String()},
+		"op-type":            []string{oplog.OpType_OP_TYPE_UPDATE.String()},
+	}
+
+	var moved *Scope
+	var rowsUpdated int
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			// Moving the project changes both orgs, so neither can be read only
+			for _, id := range []string{scopeId, newParentId} {
+				if err := VetScopeForWrite(ctx, reader, id); err != nil {
+					return err
+				}
+			}
+			var roles []*Role
+			if err := reader.SearchWhere(ctx, &roles, "scope_id = ? and grant_scope_id = ?", []interface{}{p.ParentId, scopeId}); err != nil {
+				return fmt.Errorf("unable to find roles granting access to project: %w", err)
+			}
+			if len(roles) > 0 {
+				roleIds := make([]string, 0, len(roles))
+				for _, role := range roles {
+					roleIds = append(roleIds, role.PublicId)
+				}
+				return fmt.Errorf("roles %s in %s grant access to the project: %w", strings.Join(roleIds, ", "), p.ParentId, db.ErrInvalidParameter)
+			}
+
+			moved = p.Clone().(*Scope)
+			moved.ParentId = newParentId
+			var err error
+			rowsUpdated, err = w.Update(
+				ctx,
+				moved,
+				[]string{"ParentId"},
+				nil,
+				db.WithOplog(oplogWrapper, metadata),
+				db.WithVersion(&version),
+				// The parent can't be changed by UpdateScope, and was checked above
+				db.WithSkipVetForWrite(true),
+			)
+			if err == nil && rowsUpdated > 1 {
+				// return err, which will result in a rollback of the update
+				return errors.New("error more than 1 resource would have been updated")
+			}
+			return err
+		},
+	)
+	if err != nil {
+		if db.IsUniqueError(err) {
+			return nil, db.NoRowsAffected, fmt.Errorf("move scope: %s name %s already exists in %s: %w", scopeId, p.Name, newParentId, db.ErrNotUnique)
+		}
+		return nil, db.NoRowsAffected, fmt.Errorf("move scope: failed for %s: %w", scopeId, err)
+	}
+	return moved, rowsUpdated, nil
+}
+
 // LookupScope will look up a scope in the repository.  If the scope is not
 // found, it will return nil, nil.
 func (r *Repository) LookupScope(ctx context.Context, withPublicId string, opt ...Option) (*Scope, error) {
@@ -417,26 +560,30 @@ func (r *Repository) DeleteScope(ctx context.Context, withPublicId string, opt .
 		}
 		return db.NoRowsAffected, fmt.Errorf("delete scope: failed %w for %s", err, withPublicId)
 	}
+	// The scope's keys were deleted with it
+	r.kms.InvalidateWrappers(withPublicId)
 	return rowsDeleted, nil
 }
 
-// ListProjects in an org and supports the WithLimit option.
+// ListProjects in an org and supports the WithLimit, WithName and WithOrder options.
 func (r *Repository) ListProjects(ctx context.Context, withOrgId string, opt ...Option) ([]*Scope, error) {
 	if withOrgId == "" {
 		return nil, fmt.Errorf("list projects: missing org id %w", db.ErrInvalidParameter)
 	}
 	var projects []*Scope
-	err := r.list(ctx, &projects, "parent_id = ? and type = ?", []interface{}{withOrgId, scope.Project.String()}, opt...)
+	where, args := withNameCondition("parent_id = ? and type = ?", []interface{}{withOrgId, scope.Project.String()}, opt...)
+	err := r.list(ctx, &projects, where, args, opt...)
 	if err != nil {
 		return nil, fmt.Errorf("list projects: %w", err)
 	}
 	return projects, nil
 }
 
-// ListOrgs and supports the WithLimit option.
+// ListOrgs and supports the WithLimit, WithName and WithOrder options.
 func (r *Repository) ListOrgs(ctx context.Context, opt ...Option) ([]*Scope, error) {
 	var orgs []*Scope
-	err := r.list(ctx, &orgs, "parent_id = ? and type = ?", []interface{}{"global", scope.Org.String()}, opt...)
+	where, args := withNameCondition("parent_id = ? and type = ?", []interface{}{"global", scope.Org.String()}, opt...)
+	err := r.list(ctx, &orgs, where, args, opt...)
 	if err != nil {
 		return nil, fmt.Errorf("list orgs: %w", err)
 	}
@@ -2,6 +2,7 @@ package iam
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -131,6 +132,88 @@ func Test_Repository_Scope_Create(t *testing.T) {
 	}
 }
 
+func Test_Repository_Scope_CreateWithAdmin(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	user := TestUser(t, repo, "global")
+	ctx := context.Background()
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		s, err := NewOrg(WithName(testId(t)))
+		require.NoError(err)
+		s, err = repo.CreateScopeWithAdmin(ctx, s, user.GetPublicId())
+		require.NoError(err)
+		require.NotNil(s)
+
+		foundRoles, err := repo.ListRoles(ctx, s.GetPublicId())
+		require.NoError(err)
+		assert.Len(foundRoles, 2)
+		var adminRole *Role
+		for _, r := range foundRoles {
+			if r.GetName() == "Administration" {
+				adminRole = r
+			}
+		}
+		require.NotNil(adminRole)
+		_, principals, grants, err := repo.LookupRole(ctx, adminRole.GetPublicId())
+		require.NoError(err)
+		require.Len(principals, 1)
+		assert.Equal(user.GetPublicId(), principals[0].GetPrincipalId())
+		require.Len(grants, 1)
+		assert.Equal("id=*;type=*;actions=*", grants[0].GetRawGrant())
+	})
+	t.Run("project", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		org, err := NewOrg(WithName(testId(t)))
+		require.NoError(err)
+		org, err = repo.CreateScopeWithAdmin(ctx, org, user.GetPublicId())
+		require.NoError(err)
+		p, err := NewProject(org.GetPublicId(), WithName(testId(t)))
+		require.NoError(err)
+		p, err = repo.CreateScopeWithAdmin(ctx, p, user.GetPublicId())
+		require.NoError(err)
+
+		// Projects only have an admin role
+		foundRoles, err := repo.ListRoles(ctx, p.GetPublicId())
+		require.NoError(err)
+		assert.Len(foundRoles, 1)
+	})
+
+	tests := []struct {
+		name        string
+		adminUserId string
+		opt         []Option
+	}{
+		{name: "missing-admin", adminUserId: ""},
+		{name: "anonymous-admin", adminUserId: "u_anon"},
+		{name: "authenticated-admin", adminUserId: "u_auth"},
+		{name: "recovery-admin", adminUserId: "u_recovery"},
+		{name: "skip-admin-role", adminUserId: user.GetPublicId(), opt: []Option{WithSkipAdminRoleCreation(true)}},
+		{name: "unknown-admin", adminUserId: "u_1234567890"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			publicId, err := newScopeId(scope.Org)
+			require.NoError(err)
+			s, err := NewOrg()
+			require.NoError(err)
+			got, err := repo.CreateScopeWithAdmin(ctx, s, tt.adminUserId, append(tt.opt, WithPublicId(publicId))...)
+			require.Error(err)
+			assert.True(errors.Is(err, db.ErrInvalidParameter))
+			assert.Nil(got)
+
+			// Nothing is left behind
+			found, err := repo.LookupScope(ctx, publicId)
+			require.NoError(err)
+			assert.Nil(found)
+		})
+	}
+}
+
 func Test_Repository_Scope_Update(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -201,6 +284,109 @@ func Test_Repository_Scope_Update(t *testing.T) {
 	})
 }
 
+func Test_Repository_Scope_Move(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := TestScopes(t, repo)
+		newOrg := TestOrg(t, repo)
+		role := TestRole(t, conn, prj.PublicId)
+
+		moved, rowsUpdated, err := repo.MoveScope(ctx, prj.PublicId, newOrg.PublicId, prj.Version)
+		require.NoError(err)
+		assert.Equal(1, rowsUpdated)
+		assert.Equal(newOrg.PublicId, moved.ParentId)
+
+		found, err := repo.LookupScope(ctx, prj.PublicId)
+		require.NoError(err)
+		assert.Equal(newOrg.PublicId, found.ParentId)
+		assert.Equal(prj.Version+1, found.Version)
+
+		projects, err := repo.ListProjects(ctx, newOrg.PublicId)
+		require.NoError(err)
+		require.Len(projects, 1)
+		assert.Equal(prj.PublicId, projects[0].PublicId)
+
+		// Roles in the project move with it
+		foundRole, _, _, err := repo.LookupRole(ctx, role.PublicId)
+		require.NoError(err)
+		assert.Equal(prj.PublicId, foundRole.ScopeId)
+
+		err = db.TestVerifyOplog(t, rw, prj.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_UPDATE), db.WithCreateNotBefore(10*time.Second))
+		assert.NoError(err)
+	})
+	t.Run("granted-from-org", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		org, prj := TestScopes(t, repo)
+		newOrg := TestOrg(t, repo)
+		_ = TestRole(t, conn, org.PublicId, WithGrantScopeId(prj.PublicId))
+
+		moved, rowsUpdated, err := repo.MoveScope(ctx, prj.PublicId, newOrg.PublicId, prj.Version)
+		require.Error(err)
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+		assert.Nil(moved)
+		assert.Equal(db.NoRowsAffected, rowsUpdated)
+
+		found, err := repo.LookupScope(ctx, prj.PublicId)
+		require.NoError(err)
+		assert.Equal(org.PublicId, found.ParentId)
+	})
+	t.Run("duplicate-name", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		name := testId(t)
+		org := TestOrg(t, repo)
+		prj, err := NewProject(org.PublicId, WithName(name))
+		require.NoError(err)
+		prj, err = repo.CreateScope(ctx, prj, "")
+		require.NoError(err)
+		newOrg := TestOrg(t, repo)
+		dup, err := NewProject(newOrg.PublicId, WithName(name))
+		require.NoError(err)
+		_, err = repo.CreateScope(ctx, dup, "")
+		require.NoError(err)
+
+		moved, _, err := repo.MoveScope(ctx, prj.PublicId, newOrg.PublicId, prj.Version)
+		require.Error(err)
+		assert.True(errors.Is(err, db.ErrNotUnique))
+		assert.Nil(moved)
+	})
+
+	org, prj := TestScopes(t, repo)
+	otherOrg, otherPrj := TestScopes(t, repo)
+	tests := []struct {
+		name        string
+		scopeId     string
+		newParentId string
+		version     uint32
+		wantIsErr   error
+	}{
+		{name: "missing-public-id", newParentId: otherOrg.PublicId, version: 1, wantIsErr: db.ErrInvalidParameter},
+		{name: "missing-parent-id", scopeId: prj.PublicId, version: 1, wantIsErr: db.ErrInvalidParameter},
+		{name: "missing-version", scopeId: prj.PublicId, newParentId: otherOrg.PublicId, wantIsErr: db.ErrInvalidParameter},
+		{name: "not-found", scopeId: "p_1234567890", newParentId: otherOrg.PublicId, version: 1, wantIsErr: db.ErrRecordNotFound},
+		{name: "org", scopeId: org.PublicId, newParentId: "global", version: 1, wantIsErr: db.ErrInvalidParameter},
+		{name: "same-parent", scopeId: prj.PublicId, newParentId: org.PublicId, version: 1, wantIsErr: db.ErrInvalidParameter},
+		{name: "parent-project", scopeId: prj.PublicId, newParentId: otherPrj.PublicId, version: 1, wantIsErr: db.ErrInvalidParameter},
+		{name: "parent-global", scopeId: prj.PublicId, newParentId: "global", version: 1, wantIsErr: db.ErrInvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			moved, rowsUpdated, err := repo.MoveScope(ctx, tt.scopeId, tt.newParentId, tt.version)
+			require.Error(err)
+			assert.True(errors.Is(err, tt.wantIsErr))
+			assert.Nil(moved)
+			assert.Equal(db.NoRowsAffected, rowsUpdated)
+		})
+	}
+}
+
 func Test_Repository_Scope_Lookup(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -0,0 +1,140 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// CreateSignInCidr inserts c into the repository and returns a new
+// SignInCidr containing its PublicId. c is not changed. Sign in cidrs can be
+// created in the global scope and in orgs, the scopes auth methods are
+// created in. If c has an AuthMethodId, the auth method must be in c's
+// scope. The Cidr is stored with the host bits of its address cleared. All
+// options are ignored.
+func (r *Repository) CreateSignInCidr(ctx context.Context, c *SignInCidr, opt ...Option) (*SignInCidr, error) {
+	if c == nil {
+		return nil, fmt.Errorf("create sign in cidr: missing sign in cidr: %w", db.ErrInvalidParameter)
+	}
+	if c.PublicId != "" {
+		return nil, fmt.Errorf("create sign in cidr: public id not empty: %w", db.ErrInvalidParameter)
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("create sign in cidr: %w", err)
+	}
+	if c.ScopeId != scope.Global.String() && !strings.HasPrefix(c.ScopeId, scope.Org.Prefix()+"_") {
+		return nil, fmt.Errorf("create sign in cidr: sign in cidrs can only be created in the global scope or an org: %w", db.ErrInvalidParameter)
+	}
+	if c.AuthMethodId != "" {
+		scopeId, err := r.authMethodScope(ctx, c.AuthMethodId)
+		if err != nil {
+			return nil, fmt.Errorf("create sign in cidr: %w", err)
+		}
+		if scopeId != c.ScopeId {
+			return nil, fmt.Errorf("create sign in cidr: auth method %s is not in scope %s: %w", c.AuthMethodId, c.ScopeId, db.ErrInvalidParameter)
+		}
+	}
+
+	id, err := newSignInCidrId()
+	if err != nil {
+		return nil, fmt.Errorf("create sign in cidr: %w", err)
+	}
+	newCidr := *c
+	newCidr.PublicId = id
+	_, ipNet, _ := net.ParseCIDR(strings.TrimSpace(c.Cidr))
+	newCidr.Cidr = ipNet.String()
+	// sign in cidrs only affect logins, which aren't replicated, so they don't
+	// need oplog entries.
+	if err := r.writer.Create(ctx, &newCidr); err != nil {
+		return nil, fmt.Errorf("create sign in cidr: %w", err)
+	}
+	return &newCidr, nil
+}
+
+// LookupSignInCidr returns the sign in cidr with id, or nil if there isn't
+// one. All options are ignored.
+func (r *Repository) LookupSignInCidr(ctx context.Context, id string, opt ...Option) (*SignInCidr, error) {
+	if id == "" {
+		return nil, fmt.Errorf("lookup sign in cidr: missing public id: %w", db.ErrInvalidParameter)
+	}
+	c := &SignInCidr{PublicId: id}
+	if err := r.reader.LookupByPublicId(ctx, c); err != nil {
+		if err == db.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup sign in cidr: %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// ListSignInCidrs returns the sign in cidrs of scopeId, including those which
+// only apply to one of its auth methods. All options are ignored.
+func (r *Repository) ListSignInCidrs(ctx context.Context, scopeId string, opt ...Option) ([]*SignInCidr, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list sign in cidrs: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	var cidrs []*SignInCidr
+	if err := r.reader.SearchWhere(ctx, &cidrs, "scope_id = ?", []interface{}{scopeId}, db.WithLimit(-1), db.WithOrder("create_time")); err != nil {
+		return nil, fmt.Errorf("list sign in cidrs: %w", err)
+	}
+	return cidrs, nil
+}
+
+// DeleteSignInCidr deletes the sign in cidr with id and returns the number of
+// sign in cidrs deleted. Deleting the last sign in cidr of a scope lifts the
+// restriction on the addresses its accounts can sign in from. All options
+// are ignored.
+func (r *Repository) DeleteSignInCidr(ctx context.Context, id string, opt ...Option) (int, error) {
+	if id == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete sign in cidr: missing public id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.writer.Delete(ctx, &SignInCidr{PublicId: id})
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("delete sign in cidr: %s: %w", id, err)
+	}
+	return rows, nil
+}
+
+// SignInAllowed returns true if the accounts of authMethodId in scopeId can
+// sign in, or use their auth tokens, from clientIp. It's true if the scope
+// has no sign in cidrs applying to the auth method, or if clientIp is within
+// one of them. An empty or unparseable clientIp, such as that of a request
+// made over a Unix domain socket, is only allowed if there are none.
+func (r *Repository) SignInAllowed(ctx context.Context, scopeId, authMethodId, clientIp string) (bool, error) {
+	if scopeId == "" {
+		return false, fmt.Errorf("sign in allowed: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	if authMethodId == "" {
+		return false, fmt.Errorf("sign in allowed: missing auth method id: %w", db.ErrInvalidParameter)
+	}
+	var cidrs []*SignInCidr
+	if err := r.reader.SearchWhere(ctx, &cidrs, "scope_id = ? and (auth_method_id is null or auth_method_id = ?)", []interface{}{scopeId, authMethodId}, db.WithLimit(-1)); err != nil {
+		return false, fmt.Errorf("sign in allowed: %w", err)
+	}
+	return signInAllowed(cidrs, clientIp), nil
+}
+
+// authMethodScope returns the id of the scope of the auth method with id
+// authMethodId.
+func (r *Repository) authMethodScope(ctx context.Context, authMethodId string) (string, error) {
+	rows, err := r.reader.Query(ctx, authMethodScopeQuery, []interface{}{authMethodId})
+	if err != nil {
+		return "", fmt.Errorf("unable to look up auth method: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("unable to look up auth method: %w", err)
+		}
+		return "", fmt.Errorf("auth method %s: %w", authMethodId, db.ErrRecordNotFound)
+	}
+	var scopeId string
+	if err := rows.Scan(&scopeId); err != nil {
+		return "", fmt.Errorf("unable to look up auth method: %w", err)
+	}
+	return scopeId, nil
+}
@@ -0,0 +1,117 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_SignInCidrs(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	org, proj := TestScopes(t, repo)
+	otherOrg, _ := TestScopes(t, repo)
+	authMethodId := testAuthMethod(t, conn, org.PublicId)
+	otherAuthMethodId := testAuthMethod(t, conn, org.PublicId)
+	otherOrgAuthMethodId := testAuthMethod(t, conn, otherOrg.PublicId)
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			cidr      *SignInCidr
+			wantErrIs error
+		}{
+			{name: "nil", wantErrIs: db.ErrInvalidParameter},
+			{name: "public id", cidr: &SignInCidr{PublicId: "iamsic_1234567890", ScopeId: org.PublicId, Cidr: "10.0.0.0/8"}, wantErrIs: db.ErrInvalidParameter},
+			{name: "invalid cidr", cidr: &SignInCidr{ScopeId: org.PublicId, Cidr: "10.0.0.1"}, wantErrIs: db.ErrInvalidParameter},
+			{name: "project scope", cidr: &SignInCidr{ScopeId: proj.PublicId, Cidr: "10.0.0.0/8"}, wantErrIs: db.ErrInvalidParameter},
+			{name: "auth method in another org", cidr: &SignInCidr{ScopeId: org.PublicId, AuthMethodId: otherOrgAuthMethodId, Cidr: "10.0.0.0/8"}, wantErrIs: db.ErrInvalidParameter},
+			{name: "missing auth method", cidr: &SignInCidr{ScopeId: org.PublicId, AuthMethodId: "am_1234567890", Cidr: "10.0.0.0/8"}, wantErrIs: db.ErrRecordNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := repo.CreateSignInCidr(ctx, tt.cidr)
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErrIs), "got %v", err)
+			})
+		}
+	})
+
+	allowed, err := repo.SignInAllowed(ctx, org.PublicId, authMethodId, "203.0.113.1")
+	require.NoError(t, err)
+	assert.True(t, allowed, "no sign in cidrs allow any address")
+
+	scopeCidr, err := repo.CreateSignInCidr(ctx, &SignInCidr{ScopeId: org.PublicId, Cidr: "10.1.2.3/16", Description: "office"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, scopeCidr.PublicId)
+	assert.Equal(t, "10.1.0.0/16", scopeCidr.Cidr)
+	methodCidr, err := repo.CreateSignInCidr(ctx, &SignInCidr{ScopeId: org.PublicId, AuthMethodId: authMethodId, Cidr: "192.168.1.0/24"})
+	require.NoError(t, err)
+
+	got, err := repo.LookupSignInCidr(ctx, scopeCidr.PublicId)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "10.1.0.0/16", got.Cidr)
+	assert.Equal(t, "office", got.Description)
+	assert.False(t, got.CreateTime.IsZero())
+
+	listed, err := repo.ListSignInCidrs(ctx, org.PublicId)
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+	listed, err = repo.ListSignInCidrs(ctx, otherOrg.PublicId)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	tests := []struct {
+		name         string
+		authMethodId string
+		clientIp     string
+		want         bool
+	}{
+		{"scope cidr", authMethodId, "10.1.200.1", true},
+		{"auth method cidr", authMethodId, "192.168.1.7", true},
+		{"outside", authMethodId, "203.0.113.1", false},
+		{"other auth method scope cidr", otherAuthMethodId, "10.1.200.1", true},
+		{"other auth method outside", otherAuthMethodId, "192.168.1.7", false},
+		{"unknown address", authMethodId, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := repo.SignInAllowed(ctx, org.PublicId, tt.authMethodId, tt.clientIp)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, allowed)
+		})
+	}
+	allowed, err = repo.SignInAllowed(ctx, otherOrg.PublicId, otherOrgAuthMethodId, "203.0.113.1")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// Sign in cidrs can't be changed
+	_, err = repo.writer.Exec(ctx, "update iam_sign_in_cidr set cidr = '10.2.0.0/16' where public_id = ?", []interface{}{scopeCidr.PublicId})
+	require.Error(t, err)
+
+	rows, err := repo.DeleteSignInCidr(ctx, scopeCidr.PublicId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rows)
+	got, err = repo.LookupSignInCidr(ctx, scopeCidr.PublicId)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	allowed, err = repo.SignInAllowed(ctx, org.PublicId, otherAuthMethodId, "203.0.113.1")
+	require.NoError(t, err)
+	assert.True(t, allowed, "only the auth method's cidr is left")
+
+	// Deleting the auth method deletes its sign in cidrs
+	_, err = repo.writer.Exec(ctx, "delete from auth_method where public_id = ?", []interface{}{authMethodId})
+	require.NoError(t, err)
+	got, err = repo.LookupSignInCidr(ctx, methodCidr.PublicId)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
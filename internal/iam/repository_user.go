@@ -177,7 +177,11 @@ func (r *Repository) LookupUser(ctx context.Context, userId string, opt ...Optio
 	return &user, currentAccountIds, nil
 }
 
-// DeleteUser will delete a user from the repository
+// DeleteUser will delete a user from the repository. It returns a
+// ResourceInUse error listing the roles the user is assigned to and the
+// user's sessions which haven't been terminated, unless the WithForce option
+// is set. The auth tokens of the user's accounts are deleted and the user's
+// sessions are canceled along with the user.
 func (r *Repository) DeleteUser(ctx context.Context, withPublicId string, opt ...Option) (int, error) {
 	if withPublicId == "" {
 		return db.NoRowsAffected, fmt.Errorf("delete user: missing public id %w", db.ErrInvalidParameter)
@@ -187,26 +191,50 @@ func (r *Repository) DeleteUser(ctx context.Context, withPublicId string, opt ..
 	if err := r.reader.LookupByPublicId(ctx, &user); err != nil {
 		return db.NoRowsAffected, fmt.Errorf("delete user: failed %w for %s", err, withPublicId)
 	}
-	rowsDeleted, err := r.delete(ctx, &user)
+	rowsDeleted, err := r.delete(ctx, &user, append(opt, withReferencesQuery(userReferencesQuery))...)
 	if err != nil {
 		return db.NoRowsAffected, fmt.Errorf("delete user: failed %w for %s", err, withPublicId)
 	}
 	return rowsDeleted, nil
 }
 
-// ListUsers in an org and supports the WithLimit option.
+// ListUsers in an org and supports the WithLimit, WithName and WithOrder options.
 func (r *Repository) ListUsers(ctx context.Context, withOrgId string, opt ...Option) ([]*User, error) {
 	if withOrgId == "" {
 		return nil, fmt.Errorf("list users: missing org id %w", db.ErrInvalidParameter)
 	}
 	var users []*User
-	err := r.list(ctx, &users, "scope_id = ?", []interface{}{withOrgId}, opt...)
+	where, args := withNameCondition("scope_id = ?", []interface{}{withOrgId}, opt...)
+	err := r.list(ctx, &users, where, args, opt...)
 	if err != nil {
 		return nil, fmt.Errorf("list users: %w", err)
 	}
 	return users, nil
 }
 
+// CountUsers returns the number of users in the org, or global scope, with id
+// withOrgId. All options are ignored.
+func (r *Repository) CountUsers(ctx context.Context, withOrgId string, opt ...Option) (int, error) {
+	if withOrgId == "" {
+		return 0, fmt.Errorf("count users: missing org id %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, countUsersQuery, []interface{}{withOrgId})
+	if err != nil {
+		return 0, fmt.Errorf("count users: %w", err)
+	}
+	defer rows.Close()
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 0, fmt.Errorf("count users: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("count users: %w", err)
+	}
+	return count, nil
+}
+
 // LookupUserWithLogin will attempt to lookup the user with a matching
 // account id and return the user if found. If a user is not found and the
 // WithAutoVivify() option is true, then a new iam User will be
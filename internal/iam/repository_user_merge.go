@@ -0,0 +1,172 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// A DuplicateUserCandidate is a pair of users in the same scope whose
+// accounts, usually from different auth methods, share a login name or email
+// address, so they may be the same person.
+type DuplicateUserCandidate struct {
+	UserId      string
+	OtherUserId string
+	// Value is the login name or email address the users' accounts share,
+	// in lower case.
+	Value string
+}
+
+// ListDuplicateUserCandidates returns the pairs of users in the org with id
+// scopeId whose accounts share a login name or email address, ignoring case.
+// Email addresses are taken from the "email" attribute of the accounts' last
+// authentication. A pair sharing several values is returned once per value.
+// All options are ignored.
+func (r *Repository) ListDuplicateUserCandidates(ctx context.Context, scopeId string, opt ...Option) ([]*DuplicateUserCandidate, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list duplicate user candidates: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, duplicateUserCandidatesQuery, []interface{}{scopeId})
+	if err != nil {
+		return nil, fmt.Errorf("list duplicate user candidates: %w", err)
+	}
+	defer rows.Close()
+	var candidates []*DuplicateUserCandidate
+	for rows.Next() {
+		var c DuplicateUserCandidate
+		if err := rows.Scan(&c.UserId, &c.OtherUserId, &c.Value); err != nil {
+			return nil, fmt.Errorf("list duplicate user candidates: %w", err)
+		}
+		candidates = append(candidates, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list duplicate user candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// MergeUsers merges the user with id duplicateId into the user with id
+// primaryId, which must be in the same scope, and deletes the duplicate.
+// The duplicate's accounts, and the auth tokens of those accounts, its role
+// assignments, group memberships, aliases and sessions are given to the
+// primary user in one transaction. It returns the primary user and its
+// account ids after the merge. All options are ignored.
+func (r *Repository) MergeUsers(ctx context.Context, primaryId, duplicateId string, opt ...Option) (*User, []string, error) {
+	switch {
+	case primaryId == "":
+		return nil, nil, fmt.Errorf("merge users: missing primary user id: %w", db.ErrInvalidParameter)
+	case duplicateId == "":
+		return nil, nil, fmt.Errorf("merge users: missing duplicate user id: %w", db.ErrInvalidParameter)
+	case primaryId == duplicateId:
+		return nil, nil, fmt.Errorf("merge users: a user can't be merged with itself: %w", db.ErrInvalidParameter)
+	}
+	for _, id := range []string{primaryId, duplicateId} {
+		switch id {
+		case "u_anon", "u_auth", "u_recovery":
+			return nil, nil, fmt.Errorf("merge users: %s can't be merged: %w", id, db.ErrInvalidParameter)
+		}
+	}
+
+	primary := allocUser()
+	primary.PublicId = primaryId
+	if err := r.reader.LookupByPublicId(ctx, &primary); err != nil {
+		return nil, nil, fmt.Errorf("merge users: unable to lookup user %s: %w", primaryId, err)
+	}
+	duplicate := allocUser()
+	duplicate.PublicId = duplicateId
+	if err := r.reader.LookupByPublicId(ctx, &duplicate); err != nil {
+		return nil, nil, fmt.Errorf("merge users: unable to lookup user %s: %w", duplicateId, err)
+	}
+	if primary.ScopeId != duplicate.ScopeId {
+		return nil, nil, fmt.Errorf("merge users: users are in different scopes: %w", db.ErrInvalidParameter)
+	}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, primary.ScopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, nil, fmt.Errorf("merge users: unable to get oplog wrapper: %w", err)
+	}
+
+	var mergedUser *User
+	var currentAccountIds []string
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			userTicket, err := w.GetTicket(&primary)
+			if err != nil {
+				return fmt.Errorf("unable to get ticket: %w", err)
+			}
+			updatedUser := allocUser()
+			updatedUser.PublicId = primaryId
+			updatedUser.Version = primary.Version + 1
+			var userOplogMsg oplog.Message
+			rowsUpdated, err := w.Update(ctx, &updatedUser, []string{"Version"}, nil, db.NewOplogMsg(&userOplogMsg), db.WithVersion(&primary.Version))
+			if err != nil {
+				return fmt.Errorf("unable to update user version: %w", err)
+			}
+			if rowsUpdated != 1 {
+				return fmt.Errorf("updated user and %d rows updated", rowsUpdated)
+			}
+			for _, q := range mergeUserQueries {
+				if _, err := w.Exec(ctx, q, []interface{}{primaryId, duplicateId}); err != nil {
+					return fmt.Errorf("unable to reassign references: %w", err)
+				}
+			}
+			metadata := oplog.Metadata{
+				"op-type":            []string{oplog.OpType_OP_TYPE_UPDATE.String()},
+				"scope-id":           []string{primary.ScopeId},
+				"scope-type":         []string{scope.Org.String()},
+				"resource-public-id": []string{primaryId},
+			}
+			if err := w.WriteOplogEntryWith(ctx, oplogWrapper, userTicket, metadata, []*oplog.Message{&userOplogMsg}); err != nil {
+				return fmt.Errorf("unable to write oplog: %w", err)
+			}
+
+			// The duplicate's role assignments and group memberships are
+			// removed with it.
+			deleteMetadata := oplog.Metadata{
+				"op-type":            []string{oplog.OpType_OP_TYPE_DELETE.String()},
+				"scope-id":           []string{duplicate.ScopeId},
+				"scope-type":         []string{scope.Org.String()},
+				"resource-public-id": []string{duplicateId},
+				"resource-type":      []string{duplicate.ResourceType().String()},
+			}
+			deleteUser := duplicate.Clone().(*User)
+			rowsDeleted, err := w.Delete(ctx, deleteUser, db.WithOplog(oplogWrapper, deleteMetadata))
+			if err != nil {
+				return fmt.Errorf("unable to delete user %s: %w", duplicateId, err)
+			}
+			if rowsDeleted != 1 {
+				return fmt.Errorf("deleted user and %d rows deleted", rowsDeleted)
+			}
+
+			merged := allocUser()
+			merged.PublicId = primaryId
+			if err := reader.LookupByPublicId(ctx, &merged); err != nil {
+				return fmt.Errorf("unable to lookup merged user: %w", err)
+			}
+			mergedUser = &merged
+			// we need a new repo, that's using the same reader/writer as this TxHandler
+			txRepo := &Repository{
+				reader: reader,
+				writer: w,
+				kms:    r.kms,
+				// intentionally not setting the defaultLimit, so we'll get all
+				// the account ids without a limit
+			}
+			currentAccountIds, err = txRepo.ListUserAccounts(ctx, primaryId)
+			if err != nil {
+				return fmt.Errorf("unable to retrieve current account ids after merge: %w", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("merge users: %w", err)
+	}
+	return mergedUser, currentAccountIds, nil
+}
@@ -0,0 +1,118 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ListDuplicateUserCandidates(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	org, _ := TestScopes(t, repo)
+	ctx := context.Background()
+
+	u1 := TestUser(t, repo, org.PublicId)
+	u2 := TestUser(t, repo, org.PublicId)
+	u3 := TestUser(t, repo, org.PublicId)
+	setEmail := func(userId, email string) {
+		acct := testAccount(t, conn, org.PublicId, testAuthMethod(t, conn, org.PublicId), userId)
+		_, err := rw.Exec(ctx, "insert into auth_account_attributes (account_id, attributes) values ($1, $2)",
+			[]interface{}{acct.PublicId, `{"email": "` + email + `"}`})
+		require.NoError(t, err)
+	}
+	setEmail(u1.PublicId, "alice@example.com")
+	setEmail(u2.PublicId, "Alice@Example.com")
+	setEmail(u3.PublicId, "bob@example.com")
+
+	got, err := repo.ListDuplicateUserCandidates(ctx, org.PublicId)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	ids := []string{u1.PublicId, u2.PublicId}
+	sort.Strings(ids)
+	assert.Equal(t, ids[0], got[0].UserId)
+	assert.Equal(t, ids[1], got[0].OtherUserId)
+	assert.Equal(t, "alice@example.com", got[0].Value)
+
+	_, err = repo.ListDuplicateUserCandidates(ctx, "")
+	assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+}
+
+func TestRepository_MergeUsers(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	org, _ := TestScopes(t, repo)
+	otherOrg, _ := TestScopes(t, repo)
+	ctx := context.Background()
+
+	primary := TestUser(t, repo, org.PublicId)
+	duplicate := TestUser(t, repo, org.PublicId)
+	primaryAcct := testAccount(t, conn, org.PublicId, testAuthMethod(t, conn, org.PublicId), primary.PublicId)
+	duplicateAcct := testAccount(t, conn, org.PublicId, testAuthMethod(t, conn, org.PublicId), duplicate.PublicId)
+
+	sharedRole := TestRole(t, conn, org.PublicId)
+	TestUserRole(t, conn, sharedRole.PublicId, primary.PublicId)
+	TestUserRole(t, conn, sharedRole.PublicId, duplicate.PublicId)
+	duplicateRole := TestRole(t, conn, org.PublicId)
+	TestUserRole(t, conn, duplicateRole.PublicId, duplicate.PublicId)
+	grp := TestGroup(t, conn, org.PublicId)
+	TestGroupMember(t, conn, grp.PublicId, duplicate.PublicId)
+
+	merged, accountIds, err := repo.MergeUsers(ctx, primary.PublicId, duplicate.PublicId)
+	require.NoError(t, err)
+	require.NotNil(t, merged)
+	assert.Equal(t, primary.PublicId, merged.PublicId)
+	assert.Equal(t, primary.Version+1, merged.Version)
+	want := []string{primaryAcct.PublicId, duplicateAcct.PublicId}
+	sort.Strings(want)
+	sort.Strings(accountIds)
+	assert.Equal(t, want, accountIds)
+
+	err = db.TestVerifyOplog(t, rw, primary.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_UPDATE), db.WithCreateNotBefore(10*time.Second))
+	assert.NoError(t, err)
+	err = db.TestVerifyOplog(t, rw, duplicate.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_DELETE), db.WithCreateNotBefore(10*time.Second))
+	assert.NoError(t, err)
+
+	got, _, err := repo.LookupUser(ctx, duplicate.PublicId)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	var roles []*UserRole
+	require.NoError(t, rw.SearchWhere(ctx, &roles, "principal_id = ?", []interface{}{primary.PublicId}))
+	assert.Len(t, roles, 2)
+	var members []*GroupMemberUser
+	require.NoError(t, rw.SearchWhere(ctx, &members, "member_id = ?", []interface{}{primary.PublicId}))
+	assert.Len(t, members, 1)
+
+	otherUser := TestUser(t, repo, otherOrg.PublicId)
+	tests := []struct {
+		name        string
+		primaryId   string
+		duplicateId string
+	}{
+		{name: "missing-primary", duplicateId: otherUser.PublicId},
+		{name: "missing-duplicate", primaryId: primary.PublicId},
+		{name: "same-user", primaryId: primary.PublicId, duplicateId: primary.PublicId},
+		{name: "builtin-user", primaryId: primary.PublicId, duplicateId: "u_anon"},
+		{name: "different-scopes", primaryId: primary.PublicId, duplicateId: otherUser.PublicId},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := repo.MergeUsers(ctx, tt.primaryId, tt.duplicateId)
+			assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -11,6 +10,7 @@ import (
 
 	"github.com/hashicorp/boundary/internal/db"
 	dbassert "github.com/hashicorp/boundary/internal/db/assert"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/sdk/strutil"
@@ -461,6 +461,25 @@ func TestRepository_DeleteUser(t *testing.T) {
 			require.NoError(err)
 		})
 	}
+	t.Run("in-use", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		u := TestUser(t, repo, org.PublicId)
+		role := TestRole(t, conn, org.PublicId)
+		TestUserRole(t, conn, role.PublicId, u.PublicId)
+
+		deletedRows, err := repo.DeleteUser(context.Background(), u.PublicId)
+		require.Error(err)
+		assert.Equal(0, deletedRows)
+		assert.True(errors.Is(err, errors.ErrResourceInUse))
+		var inUse *errors.ResourceInUse
+		require.True(errors.As(err, &inUse))
+		assert.Equal(u.PublicId, inUse.PublicId)
+		assert.Equal([]string{role.PublicId}, inUse.ReferencedBy)
+
+		deletedRows, err = repo.DeleteUser(context.Background(), u.PublicId, WithForce(true))
+		require.NoError(err)
+		assert.Equal(1, deletedRows)
+	})
 }
 
 func TestRepository_ListUsers(t *testing.T) {
@@ -541,6 +560,33 @@ func TestRepository_ListUsers(t *testing.T) {
 	}
 }
 
+func TestRepository_CountUsers(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	org, _ := TestScopes(t, repo)
+	otherOrg, _ := TestScopes(t, repo)
+	ctx := context.Background()
+
+	_, err := repo.CountUsers(ctx, "")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+
+	got, err := repo.CountUsers(ctx, org.PublicId)
+	require.NoError(t, err)
+	assert.Equal(t, 0, got)
+
+	for i := 0; i < 3; i++ {
+		TestUser(t, repo, org.PublicId)
+	}
+	TestUser(t, repo, otherOrg.PublicId)
+
+	got, err = repo.CountUsers(ctx, org.PublicId)
+	require.NoError(t, err)
+	assert.Equal(t, 3, got)
+}
+
 func TestRepository_LookupUserWithLogin(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -105,5 +105,8 @@ func validateScopeForWrite(ctx context.Context, r db.Reader, resource ResourceWi
 			return errors.New("not allowed to change a resource's scope")
 		}
 	}
+	if err := vetResourceScopeForWrite(ctx, r, resource); err != nil {
+		return err
+	}
 	return nil
 }
@@ -92,6 +92,7 @@ func (*Role) Actions() map[string]action.Type {
 	ret[action.AddPrincipals.String()] = action.AddPrincipals
 	ret[action.RemovePrincipals.String()] = action.RemovePrincipals
 	ret[action.SetPrincipals.String()] = action.SetPrincipals
+	ret[action.Clone.String()] = action.Clone
 	return ret
 }
 
@@ -83,6 +83,9 @@ func (g *RoleGrant) VetForWrite(ctx context.Context, r db.Reader, opType db.OpTy
 	}
 	g.CanonicalGrant = canonical
 
+	if err := vetRoleScopeForWrite(ctx, r, g.RoleId); err != nil {
+		return fmt.Errorf("vet role grant for writing: %w", err)
+	}
 	return nil
 }
 
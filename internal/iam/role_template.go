@@ -0,0 +1,100 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// A RoleTemplate is a named set of grants for a common job, such as auditing
+// or managing sessions, from which roles can be created in any scope instead
+// of assembling the grant strings by hand.
+type RoleTemplate struct {
+	// Name identifies the template, such as "auditor".
+	Name string
+	// RoleName and Description are given to roles created from the
+	// template unless others are provided.
+	RoleName    string
+	Description string
+	Grants      []string
+}
+
+var roleTemplates = map[string]RoleTemplate{
+	"auditor": {
+		Name:        "auditor",
+		RoleName:    "Auditor",
+		Description: "Read-only access to every resource",
+		Grants: []string{
+			"id=*;type=*;actions=read,list",
+		},
+	},
+	"session-manager": {
+		Name:        "session-manager",
+		RoleName:    "Session Manager",
+		Description: "Views targets and views and cancels sessions",
+		Grants: []string{
+			"id=*;type=target;actions=read,list",
+			"id=*;type=session;actions=read,list,cancel",
+		},
+	},
+	"target-admin": {
+		Name:        "target-admin",
+		RoleName:    "Target Administration",
+		Description: "Manages targets and views the hosts they connect to",
+		Grants: []string{
+			"id=*;type=target;actions=*",
+			"id=*;type=host-catalog;actions=read,list",
+			"id=*;type=host-set;actions=read,list",
+			"id=*;type=host;actions=read,list",
+		},
+	},
+}
+
+// RoleTemplates returns the role templates, sorted by name.
+func RoleTemplates() []RoleTemplate {
+	ret := make([]RoleTemplate, 0, len(roleTemplates))
+	for _, t := range roleTemplates {
+		ret = append(ret, t)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// LookupRoleTemplate returns the role template with name, and false if there
+// is no such template.
+func LookupRoleTemplate(name string) (RoleTemplate, bool) {
+	t, ok := roleTemplates[name]
+	return t, ok
+}
+
+// CreateRoleFromTemplate creates a role in the scope with the grants of the
+// role template with templateName, returning the role and its grants. The role
+// is named and described by the template unless WithName or WithDescription
+// are given. WithGrantScopeId is also supported.
+func (r *Repository) CreateRoleFromTemplate(ctx context.Context, scopeId, templateName string, opt ...Option) (*Role, []*RoleGrant, error) {
+	if scopeId == "" {
+		return nil, nil, fmt.Errorf("create role from template: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	t, ok := LookupRoleTemplate(templateName)
+	if !ok {
+		return nil, nil, fmt.Errorf("create role from template: unknown template %q: %w", templateName, db.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	role, err := NewRole(scopeId, WithName(t.RoleName), WithDescription(t.Description), WithGrantScopeId(opts.withGrantScopeId))
+	if err != nil {
+		return nil, nil, fmt.Errorf("create role from template: %w", err)
+	}
+	if opts.withName != "" {
+		role.Name = opts.withName
+	}
+	if opts.withDescription != "" {
+		role.Description = opts.withDescription
+	}
+	created, grants, err := r.createRoleWithGrants(ctx, role, t.Grants)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create role from template: %w", err)
+	}
+	return created, grants, nil
+}
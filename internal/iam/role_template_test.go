@@ -0,0 +1,34 @@
+package iam
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleTemplates(t *testing.T) {
+	t.Parallel()
+	templates := RoleTemplates()
+	require.NotEmpty(t, templates)
+	for i, tmpl := range templates {
+		tmpl := tmpl
+		if i > 0 {
+			assert.Less(t, templates[i-1].Name, tmpl.Name, "templates are not sorted")
+		}
+		t.Run(tmpl.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.NotEmpty(tmpl.RoleName)
+			assert.NotEmpty(tmpl.Grants)
+			for _, g := range tmpl.Grants {
+				_, err := NewRoleGrant("r_1234567890", g)
+				assert.NoError(err, g)
+			}
+			got, ok := LookupRoleTemplate(tmpl.Name)
+			assert.True(ok)
+			assert.Equal(tmpl, got)
+		})
+	}
+	_, ok := LookupRoleTemplate("unknown")
+	assert.False(t, ok)
+}
@@ -107,12 +107,25 @@ func (s *Scope) VetForWrite(ctx context.Context, r db.Reader, opType db.OpType,
 	}
 	if opType == db.UpdateOp {
 		dbOptions := db.GetOpts(opt...)
-		for _, path := range dbOptions.WithFieldMaskPaths {
+		onlyReadOnly := true
+		for _, path := range append(dbOptions.WithFieldMaskPaths, dbOptions.WithNullPaths...) {
 			switch path {
 			case "ParentId":
 				return errors.New("you cannot change a scope's parent")
 			case "Type":
 				return errors.New("you cannot change a scope's type")
+			case "ReadOnly":
+				if s.PublicId == scope.Global.String() {
+					return errors.New("global scope cannot be read only")
+				}
+			default:
+				onlyReadOnly = false
+			}
+		}
+		// A read only scope can still be made writable again
+		if !onlyReadOnly {
+			if err := VetScopeForWrite(ctx, r, s.PublicId); err != nil {
+				return err
 			}
 		}
 	}
@@ -135,6 +148,9 @@ func (s *Scope) VetForWrite(ctx context.Context, r db.Reader, opType db.OpType,
 			if parentScope.Type != scope.Org.String() {
 				return errors.New("project parent scope is not an org")
 			}
+			if err := VetScopeForWrite(ctx, r, s.ParentId); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -147,7 +163,9 @@ func (s *Scope) ResourceType() resource.Type {
 
 // Actions returns the available actions for Scopes
 func (*Scope) Actions() map[string]action.Type {
-	return CrudlActions()
+	ret := CrudlActions()
+	ret[action.ReadQuotaUsage.String()] = action.ReadQuotaUsage
+	return ret
 }
 
 // GetScope returns the scope for the "scope" if there is one defined
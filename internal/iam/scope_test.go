@@ -163,6 +163,7 @@ func TestScope_Actions(t *testing.T) {
 	assert.Equal(a[action.Read.String()], action.Read)
 	assert.Equal(a[action.Delete.String()], action.Delete)
 	assert.Equal(a[action.List.String()], action.List)
+	assert.Equal(a[action.ReadQuotaUsage.String()], action.ReadQuotaUsage)
 }
 
 func TestScope_ResourceType(t *testing.T) {
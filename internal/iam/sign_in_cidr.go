@@ -0,0 +1,80 @@
+package iam
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+const (
+	SignInCidrPrefix = "iamsic"
+)
+
+// A SignInCidr is a range of addresses the accounts of a scope's auth methods
+// can sign in from. Once a scope has any, its accounts can only authenticate,
+// and use the auth tokens they were issued, from an address within one of
+// them. A SignInCidr with an AuthMethodId only applies to the accounts of that
+// auth method. SignInCidrs can't be changed once created.
+type SignInCidr struct {
+	PublicId     string `gorm:"primary_key"`
+	ScopeId      string
+	AuthMethodId string `gorm:"default:null"`
+	// Cidr is the range in CIDR notation, such as 10.0.0.0/8.
+	Cidr        string
+	Description string    `gorm:"default:null"`
+	CreateTime  time.Time `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for sign in cidrs.
+func (c *SignInCidr) TableName() string {
+	return "iam_sign_in_cidr"
+}
+
+// GetPublicId returns the id of the sign in cidr.
+func (c *SignInCidr) GetPublicId() string {
+	return c.PublicId
+}
+
+func newSignInCidrId() (string, error) {
+	id, err := db.NewPublicId(SignInCidrPrefix)
+	if err != nil {
+		return "", fmt.Errorf("new sign in cidr id: %w", err)
+	}
+	return id, nil
+}
+
+// validate returns an error if c is incomplete or its Cidr can't be parsed.
+func (c *SignInCidr) validate() error {
+	if c.ScopeId == "" {
+		return fmt.Errorf("missing scope id: %w", db.ErrInvalidParameter)
+	}
+	if _, _, err := net.ParseCIDR(strings.TrimSpace(c.Cidr)); err != nil {
+		return fmt.Errorf("invalid cidr %q: %w", c.Cidr, db.ErrInvalidParameter)
+	}
+	return nil
+}
+
+// signInAllowed returns true if there are no cidrs, or if clientIp is within
+// one of them. An address which can't be parsed is never within a cidr.
+func signInAllowed(cidrs []*SignInCidr, clientIp string) bool {
+	if len(cidrs) == 0 {
+		return true
+	}
+	ip := net.ParseIP(clientIp)
+	if ip == nil {
+		return false
+	}
+	for _, c := range cidrs {
+		_, ipNet, err := net.ParseCIDR(c.Cidr)
+		if err != nil {
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
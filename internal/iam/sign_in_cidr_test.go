@@ -0,0 +1,64 @@
+package iam
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignInCidr_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    SignInCidr
+		wantErr bool
+	}{
+		{"ipv4", SignInCidr{ScopeId: "global", Cidr: "10.0.0.0/8"}, false},
+		{"ipv6", SignInCidr{ScopeId: "global", Cidr: "2001:db8::/32"}, false},
+		{"host bits", SignInCidr{ScopeId: "global", Cidr: "10.1.2.3/8"}, false},
+		{"missing scope", SignInCidr{Cidr: "10.0.0.0/8"}, true},
+		{"missing cidr", SignInCidr{ScopeId: "global"}, true},
+		{"address", SignInCidr{ScopeId: "global", Cidr: "10.0.0.1"}, true},
+		{"invalid", SignInCidr{ScopeId: "global", Cidr: "10.0.0.0/33"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cidr.validate()
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, db.ErrInvalidParameter), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_signInAllowed(t *testing.T) {
+	cidrs := []*SignInCidr{
+		{Cidr: "10.0.0.0/8"},
+		{Cidr: "192.168.1.0/24"},
+		{Cidr: "2001:db8::/32"},
+	}
+	tests := []struct {
+		name     string
+		cidrs    []*SignInCidr
+		clientIp string
+		want     bool
+	}{
+		{"no cidrs", nil, "203.0.113.1", true},
+		{"no cidrs unknown address", nil, "", true},
+		{"within", cidrs, "10.1.2.3", true},
+		{"within second", cidrs, "192.168.1.20", true},
+		{"within ipv6", cidrs, "2001:db8::1", true},
+		{"outside", cidrs, "192.168.2.20", false},
+		{"outside ipv6", cidrs, "2001:db9::1", false},
+		{"unknown address", cidrs, "", false},
+		{"unparseable address", cidrs, "@", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, signInAllowed(tt.cidrs, tt.clientIp))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/metric"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/multiwrapper"
+)
+
+// KeyOperation is an operation done with a data encryption key.
+type KeyOperation string
+
+const (
+	EncryptOperation KeyOperation = "encrypt"
+	DecryptOperation KeyOperation = "decrypt"
+)
+
+// KeyUsage is the number of times a version of a scope's data encryption key
+// for a purpose was used for an operation, as counted by every controller.
+// The use times are only as precise as how often controllers flush their
+// counts.
+type KeyUsage struct {
+	ScopeId      string
+	Purpose      string
+	KeyVersionId string
+	// KeyVersion is 0 if the key version has been destroyed.
+	KeyVersion   uint32
+	Operation    KeyOperation
+	UseCount     uint64
+	FirstUseTime time.Time
+	LastUseTime  time.Time
+}
+
+// TableName returns the view key usage is read from.
+func (*KeyUsage) TableName() string {
+	return "kms_key_usage_view"
+}
+
+type keyUsageKey struct {
+	scopeId   string
+	purpose   KeyPurpose
+	keyId     string
+	operation KeyOperation
+}
+
+// keyUsageRecorder counts the operations done with the wrappers of a Kms
+// since it was last flushed. It is safe for concurrent use.
+type keyUsageRecorder struct {
+	mu     sync.Mutex
+	counts map[keyUsageKey]uint64
+}
+
+func newKeyUsageRecorder() *keyUsageRecorder {
+	return &keyUsageRecorder{counts: make(map[keyUsageKey]uint64)}
+}
+
+func (u *keyUsageRecorder) add(key keyUsageKey, n uint64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.counts[key] += n
+}
+
+// take returns the recorded counts and clears them.
+func (u *keyUsageRecorder) take() map[keyUsageKey]uint64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.counts) == 0 {
+		return nil
+	}
+	counts := u.counts
+	u.counts = make(map[keyUsageKey]uint64)
+	return counts
+}
+
+// usageWrapper is the multiwrapper for a scope and purpose, counting the
+// operations done with each of its key versions.
+type usageWrapper struct {
+	*multiwrapper.MultiWrapper
+	scopeId string
+	purpose KeyPurpose
+	usage   *keyUsageRecorder
+}
+
+func (w *usageWrapper) record(keyId string, op KeyOperation) {
+	w.usage.add(keyUsageKey{scopeId: w.scopeId, purpose: w.purpose, keyId: keyId, operation: op}, 1)
+	metric.KmsKeyOperations.WithLabelValues(w.scopeId, w.purpose.String(), keyId, string(op)).Inc()
+}
+
+// Encrypt encrypts pt with the current key version and counts it.
+func (w *usageWrapper) Encrypt(ctx context.Context, pt []byte, aad []byte) (*wrapping.EncryptedBlobInfo, error) {
+	blob, err := w.MultiWrapper.Encrypt(ctx, pt, aad)
+	if err != nil {
+		return nil, err
+	}
+	w.record(blob.GetKeyInfo().GetKeyID(), EncryptOperation)
+	return blob, nil
+}
+
+// Decrypt decrypts ct with the key version it was encrypted with and counts
+// it.
+func (w *usageWrapper) Decrypt(ctx context.Context, ct *wrapping.EncryptedBlobInfo, aad []byte) ([]byte, error) {
+	pt, err := w.MultiWrapper.Decrypt(ctx, ct, aad)
+	if err != nil {
+		return nil, err
+	}
+	keyId := ct.GetKeyInfo().GetKeyID()
+	if keyId == "" {
+		keyId = w.MultiWrapper.KeyID()
+	}
+	w.record(keyId, DecryptOperation)
+	return pt, nil
+}
+
+// FlushKeyUsage adds the operations counted by the Kms since it was last
+// flushed to the counts kept in the database and returns how many counts were
+// updated. Operations in scopes deleted since they were counted are skipped.
+// If the update fails the operations are counted again so the next flush
+// retries them.
+func (k *Kms) FlushKeyUsage(ctx context.Context) (int, error) {
+	counts := k.usage.take()
+	if len(counts) == 0 {
+		return db.NoRowsAffected, nil
+	}
+	var rowsUpdated int
+	_, err := k.repo.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			rowsUpdated = 0
+			for key, n := range counts {
+				// Key usage isn't replicated, so it doesn't need oplog entries.
+				rows, err := w.Exec(ctx, addKeyUsageQuery, []interface{}{key.keyId, string(key.operation), key.scopeId, key.purpose.String(), n})
+				if err != nil {
+					return err
+				}
+				rowsUpdated += rows
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		for key, n := range counts {
+			k.usage.add(key, n)
+		}
+		return db.NoRowsAffected, fmt.Errorf("flush key usage: %w", err)
+	}
+	return rowsUpdated, nil
+}
+
+// ListKeyUsage returns the usage of the versions of the data encryption keys
+// of the scope with id scopeId, as flushed by every controller. Supports the
+// options WithLimit and WithOrder, which defaults to ordering by purpose, then
+// newest key version first.
+func (r *Repository) ListKeyUsage(ctx context.Context, scopeId string, opt ...Option) ([]*KeyUsage, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list key usage: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	if opts.withOrder == "" {
+		opt = append(opt, WithOrder("purpose, key_version desc, operation"))
+	}
+	var usage []*KeyUsage
+	if err := r.list(ctx, &usage, "scope_id = ?", []interface{}{scopeId}, opt...); err != nil {
+		return nil, fmt.Errorf("list key usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,80 @@
+package kms_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKms_KeyUsage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo, err := kms.NewRepository(rw, rw)
+	require.NoError(t, err)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	org, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	// Nothing has been counted yet
+	flushed, err := kmsCache.FlushKeyUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, flushed)
+
+	orgWrapper, err := kmsCache.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeDatabase)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		blob, err := orgWrapper.Encrypt(ctx, []byte("secret"), nil)
+		require.NoError(t, err)
+		_, err = orgWrapper.Decrypt(ctx, blob, nil)
+		require.NoError(t, err)
+	}
+	projWrapper, err := kmsCache.GetWrapper(ctx, proj.GetPublicId(), kms.KeyPurposeTokens)
+	require.NoError(t, err)
+	_, err = projWrapper.Encrypt(ctx, []byte("secret"), nil)
+	require.NoError(t, err)
+
+	flushed, err = kmsCache.FlushKeyUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, flushed)
+
+	usage, err := repo.ListKeyUsage(ctx, org.GetPublicId())
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	for _, u := range usage {
+		assert.Equal(t, org.GetPublicId(), u.ScopeId)
+		assert.Equal(t, kms.KeyPurposeDatabase.String(), u.Purpose)
+		assert.Equal(t, orgWrapper.KeyID(), u.KeyVersionId)
+		assert.Equal(t, uint32(1), u.KeyVersion)
+		assert.Equal(t, uint64(2), u.UseCount)
+	}
+	assert.Equal(t, kms.DecryptOperation, usage[0].Operation)
+	assert.Equal(t, kms.EncryptOperation, usage[1].Operation)
+
+	// Counts from later flushes are added
+	_, err = orgWrapper.Encrypt(ctx, []byte("secret"), nil)
+	require.NoError(t, err)
+	_, err = kmsCache.FlushKeyUsage(ctx)
+	require.NoError(t, err)
+	usage, err = repo.ListKeyUsage(ctx, org.GetPublicId())
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	assert.Equal(t, uint64(3), usage[1].UseCount)
+	assert.False(t, usage[1].LastUseTime.Before(usage[1].FirstUseTime))
+
+	usage, err = repo.ListKeyUsage(ctx, proj.GetPublicId())
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, kms.KeyPurposeTokens.String(), usage[0].Purpose)
+	assert.Equal(t, kms.EncryptOperation, usage[0].Operation)
+
+	_, err = repo.ListKeyUsage(ctx, "")
+	assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/go-hclog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
@@ -43,37 +45,74 @@ func (e *ExternalWrappers) Recovery() wrapping.Wrapper {
 	return e.recovery
 }
 
+// DefaultCacheTTL is how long a Kms caches the wrappers for a scope and
+// purpose unless WithCacheTTL is used.
+const DefaultCacheTTL = 1 * time.Hour
+
 // Kms is a way to access wrappers for a given scope and purpose. Since keys can
 // never change, only be added or (eventually) removed, it opportunistically
 // caches, going to the database as needed.
 type Kms struct {
 	logger hclog.Logger
 
-	// scopePurposeCache holds a per-scope-purpose multiwrapper containing the
+	// scopePurposeCache holds a per-scope-purpose cachedWrapper containing the
 	// current encrypting key and all previous key versions, for decryption
 	scopePurposeCache sync.Map
+	cacheTTL          time.Duration
 
 	externalScopeCache      map[string]*ExternalWrappers
 	externalScopeCacheMutex sync.RWMutex
 
 	repo *Repository
+
+	// usage counts the operations done with the cached wrappers until
+	// they're flushed with FlushKeyUsage
+	usage *keyUsageRecorder
+}
+
+// cachedWrapper is a multiwrapper for a scope and purpose and when it
+// expires from the cache. It never expires if expires is zero.
+type cachedWrapper struct {
+	wrapper *usageWrapper
+	expires time.Time
 }
 
-// NewKms takes in a repo and returns a Kms. Supported options: WithLogger.
+// NewKms takes in a repo and returns a Kms. Supported options: WithLogger and
+// WithCacheTTL.
 func NewKms(repo *Repository, opt ...Option) (*Kms, error) {
 	if repo == nil {
 		return nil, errors.New("new kms created without an underlying repo")
 	}
 
 	opts := getOpts(opt...)
+	cacheTTL := opts.withCacheTTL
+	if cacheTTL == 0 {
+		cacheTTL = DefaultCacheTTL
+	}
 
 	return &Kms{
 		logger:             opts.withLogger,
+		cacheTTL:           cacheTTL,
 		externalScopeCache: make(map[string]*ExternalWrappers),
 		repo:               repo,
+		usage:              newKeyUsageRecorder(),
 	}, nil
 }
 
+// InvalidateWrappers removes the cached wrappers for the scope so they're
+// reloaded from the database the next time they're needed. It must be called
+// when keys are added to or removed from a scope, such as when they're
+// rotated or the scope is deleted. If no purposes are given, the wrappers for
+// every purpose are removed. Other controllers reload them once they expire.
+func (k *Kms) InvalidateWrappers(scopeId string, purpose ...KeyPurpose) {
+	if len(purpose) == 0 {
+		purpose = []KeyPurpose{KeyPurposeDatabase, KeyPurposeOplog, KeyPurposeTokens, KeyPurposeSessions}
+	}
+	for _, p := range purpose {
+		k.scopePurposeCache.Delete(scopeId + p.String())
+	}
+}
+
 // GetScopePurposeCache is used in test functions for validation. Since the
 // tests need to be in a different package to avoid circular dependencies, this
 // is exported.
@@ -141,6 +180,14 @@ func generateKeyId(scopeId string, purpose KeyPurpose, version uint32) string {
 	return fmt.Sprintf("%s_%s_%d", scopeId, purpose, version)
 }
 
+// DatabaseWrapperFn returns a db.WrapperFn which gets the database wrappers
+// of scopes, for use with db.WithWrapperFn.
+func (k *Kms) DatabaseWrapperFn() db.WrapperFn {
+	return func(ctx context.Context, scopeId, keyId string) (wrapping.Wrapper, error) {
+		return k.GetWrapper(ctx, scopeId, KeyPurposeDatabase, WithKeyId(keyId))
+	}
+}
+
 // GetWrapper returns a wrapper for the given scope and purpose. When a keyId is
 // passed, it will ensure that the returning wrapper has that key ID in the
 // multiwrapper. This is not necesary for encryption but should be supplied for
@@ -159,14 +206,17 @@ func (k *Kms) GetWrapper(ctx context.Context, scopeId string, purpose KeyPurpose
 	}
 
 	opts := getOpts(opt...)
-	// Fast-path: we have a valid key at the scope/purpose. Verify the key with
-	// that ID is in the multiwrapper; if not, fall through to reload from the
-	// DB.
+	// Fast-path: we have a valid key at the scope/purpose which hasn't
+	// expired. Verify the key with that ID is in the multiwrapper; if not,
+	// fall through to reload from the DB. Key IDs include the key version, so
+	// a key version added since the multiwrapper was cached is found this way.
 	val, ok := k.scopePurposeCache.Load(scopeId + purpose.String())
 	if ok {
-		wrapper := val.(*multiwrapper.MultiWrapper)
-		if opts.withKeyId == "" || wrapper.WrapperForKeyID(opts.withKeyId) != nil {
-			return wrapper, nil
+		cached := val.(*cachedWrapper)
+		if cached.expires.IsZero() || time.Now().Before(cached.expires) {
+			if opts.withKeyId == "" || cached.wrapper.WrapperForKeyID(opts.withKeyId) != nil {
+				return cached.wrapper, nil
+			}
 		}
 		// Fall through to refresh our multiwrapper for this scope/purpose from the DB
 	}
@@ -187,9 +237,47 @@ func (k *Kms) GetWrapper(ctx context.Context, scopeId string, purpose KeyPurpose
 	if err != nil {
 		return nil, fmt.Errorf("error loading %s for scope %s: %w", purpose.String(), scopeId, err)
 	}
-	k.scopePurposeCache.Store(scopeId+purpose.String(), wrapper)
+	cached := &cachedWrapper{
+		wrapper: &usageWrapper{
+			MultiWrapper: wrapper,
+			scopeId:      scopeId,
+			purpose:      purpose,
+			usage:        k.usage,
+		},
+	}
+	if k.cacheTTL > 0 {
+		cached.expires = time.Now().Add(k.cacheTTL)
+	}
+	k.scopePurposeCache.Store(scopeId+purpose.String(), cached)
+
+	return cached.wrapper, nil
+}
 
-	return wrapper, nil
+// OplogWrapperForKeyId returns the oplog wrapper of the scope the oplog key
+// version with id keyId belongs to, which can be used to verify the oplog
+// entries written with it.
+func (k *Kms) OplogWrapperForKeyId(ctx context.Context, keyId string) (wrapping.Wrapper, error) {
+	if keyId == "" {
+		return nil, fmt.Errorf("oplog wrapper for key id: missing key id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := k.repo.reader.Query(ctx, oplogKeyVersionScopeQuery, []interface{}{keyId})
+	if err != nil {
+		return nil, fmt.Errorf("oplog wrapper for key id: %w", err)
+	}
+	defer rows.Close()
+	var scopeId string
+	for rows.Next() {
+		if err := rows.Scan(&scopeId); err != nil {
+			return nil, fmt.Errorf("oplog wrapper for key id: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("oplog wrapper for key id: %w", err)
+	}
+	if scopeId == "" {
+		return nil, fmt.Errorf("oplog wrapper for key id: key %s: %w", keyId, db.ErrRecordNotFound)
+	}
+	return k.GetWrapper(ctx, scopeId, KeyPurposeOplog, WithKeyId(keyId))
 }
 
 func (k *Kms) loadRoot(ctx context.Context, scopeId string, opt ...Option) (*multiwrapper.MultiWrapper, string, error) {
@@ -3,16 +3,20 @@ package kms_test
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/types/scope"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/hashicorp/go-kms-wrapping/wrappers/aead"
-	"github.com/hashicorp/go-kms-wrapping/wrappers/multiwrapper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -87,7 +91,10 @@ func TestKms(t *testing.T) {
 						continue
 					}
 					require.NoError(err)
-					multi, ok := wrapper.(*multiwrapper.MultiWrapper)
+					multi, ok := wrapper.(interface {
+						wrapping.Wrapper
+						WrapperForKeyID(string) wrapping.Wrapper
+					})
 					require.True(ok)
 					aeadWrapper, ok := multi.WrapperForKeyID(multi.KeyID()).(*aead.Wrapper)
 					require.True(ok)
@@ -125,3 +132,100 @@ func TestKms(t *testing.T) {
 		})
 	}
 }
+
+func TestKms_CacheExpiryAndInvalidation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	org, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	repo, err := kms.NewRepository(rw, rw)
+	require.NoError(t, err)
+
+	newKms := func(t *testing.T, ttl time.Duration) *kms.Kms {
+		k, err := kms.NewKms(repo, kms.WithCacheTTL(ttl))
+		require.NoError(t, err)
+		require.NoError(t, k.AddExternalWrappers(kms.WithRootWrapper(wrapper)))
+		return k
+	}
+	cached := func(k *kms.Kms) interface{} {
+		v, _ := k.GetScopePurposeCache().Load(org.GetPublicId() + kms.KeyPurposeDatabase.String())
+		return v
+	}
+
+	t.Run("cached until invalidated", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		k := newKms(t, -1)
+		_, err := k.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeDatabase)
+		require.NoError(err)
+		_, err = k.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeOplog)
+		require.NoError(err)
+		first := cached(k)
+		require.NotNil(first)
+
+		_, err = k.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeDatabase)
+		require.NoError(err)
+		assert.Same(first, cached(k))
+
+		k.InvalidateWrappers(org.GetPublicId(), kms.KeyPurposeDatabase)
+		assert.Nil(cached(k))
+		_, ok := k.GetScopePurposeCache().Load(org.GetPublicId() + kms.KeyPurposeOplog.String())
+		assert.True(ok)
+
+		_, err = k.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeDatabase)
+		require.NoError(err)
+		assert.NotSame(first, cached(k))
+
+		// Without purposes every purpose is invalidated
+		k.InvalidateWrappers(org.GetPublicId())
+		var count int
+		k.GetScopePurposeCache().Range(func(key interface{}, value interface{}) bool {
+			if strings.HasPrefix(key.(string), org.GetPublicId()) {
+				count++
+			}
+			return true
+		})
+		assert.Equal(0, count)
+	})
+	t.Run("expires", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		k := newKms(t, 100*time.Millisecond)
+		_, err := k.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeDatabase)
+		require.NoError(err)
+		first := cached(k)
+		require.NotNil(first)
+
+		time.Sleep(200 * time.Millisecond)
+		_, err = k.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeDatabase)
+		require.NoError(err)
+		assert.NotSame(first, cached(k))
+	})
+}
+
+func TestKms_OplogWrapperForKeyId(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+
+	orgWrapper, err := kmsCache.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeOplog)
+	require.NoError(t, err)
+	got, err := kmsCache.OplogWrapperForKeyId(ctx, orgWrapper.KeyID())
+	require.NoError(t, err)
+	assert.Equal(t, orgWrapper.KeyID(), got.KeyID())
+
+	_, err = kmsCache.OplogWrapperForKeyId(ctx, "")
+	assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+	_, err = kmsCache.OplogWrapperForKeyId(ctx, "kdkv_doesnotexist")
+	assert.Truef(t, errors.Is(err, db.ErrRecordNotFound), "want err: %q got: %q", db.ErrRecordNotFound, err)
+
+	// The oplog entries written by a repository can be verified with it
+	iam.TestUser(t, iamRepo, org.GetPublicId())
+	verifier, err := oplog.NewChainVerifier(conn, kmsCache.OplogWrapperForKeyId)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.VerifyChain(ctx, 0, math.MaxUint32))
+}
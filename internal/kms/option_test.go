@@ -2,6 +2,7 @@ package kms
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -27,4 +28,11 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withLimit = 1
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithCacheTTL", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithCacheTTL(time.Minute))
+		testOpts := getDefaultOptions()
+		testOpts.withCacheTTL = time.Minute
+		assert.Equal(opts, testOpts)
+	})
 }
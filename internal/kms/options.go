@@ -1,6 +1,8 @@
 package kms
 
 import (
+	"time"
+
 	"github.com/hashicorp/go-hclog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 )
@@ -27,6 +29,7 @@ type options struct {
 	withRepository        *Repository
 	withOrder             string
 	withKeyId             string
+	withCacheTTL          time.Duration
 }
 
 func getDefaultOptions() options {
@@ -93,3 +96,12 @@ func WithKeyId(keyId string) Option {
 		o.withKeyId = keyId
 	}
 }
+
+// WithCacheTTL sets how long a Kms caches the wrappers for a scope and purpose
+// before reloading them from the database. If the ttl is negative, wrappers
+// are cached until they're invalidated.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.withCacheTTL = ttl
+	}
+}
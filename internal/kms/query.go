@@ -0,0 +1,28 @@
+package kms
+
+const (
+	// addKeyUsageQuery adds $5 to the count of $2 operations done with the
+	// key version $1 of the scope $3 for the purpose $4, unless the scope has
+	// been deleted.
+	addKeyUsageQuery = `
+	insert into kms_key_usage
+		(key_version_id, operation, scope_id, purpose, use_count, first_use_time, last_use_time)
+	select $1, $2, public_id, $4, $5, now(), now()
+	from iam_scope
+	where public_id = $3
+	on conflict (key_version_id, operation)
+	do update set
+		use_count = kms_key_usage.use_count + excluded.use_count,
+		last_use_time = now();
+	`
+
+	// oplogKeyVersionScopeQuery returns the scope of the oplog key version
+	// $1.
+	oplogKeyVersionScopeQuery = `
+	select rk.scope_id
+	from kms_oplog_key_version okv
+		join kms_oplog_key ok on ok.private_id = okv.oplog_key_id
+		join kms_root_key rk on rk.private_id = ok.root_key_id
+	where okv.private_id = $1;
+	`
+)
@@ -0,0 +1,143 @@
+// Package metric contains the Prometheus metrics exported by controllers and
+// workers. The metrics are registered with the default Prometheus registry
+// and are served from the /metrics path of "ops" listeners.
+package metric
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "boundary"
+
+var (
+	// SessionsCreated counts the sessions created.
+	SessionsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "sessions_created_total",
+		Help:      "Number of sessions created.",
+	})
+
+	// SessionsTerminated counts the sessions terminated by termination reason.
+	SessionsTerminated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "sessions_terminated_total",
+		Help:      "Number of sessions terminated, by termination reason.",
+	}, []string{"reason"})
+
+	// WorkerActiveConnections is the number of active connections last
+	// reported by each worker.
+	WorkerActiveConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "worker_active_connections",
+		Help:      "Number of active connections last reported by each worker.",
+	}, []string{"worker"})
+
+	// AuthorizeSessionDuration observes the time taken to authorize sessions.
+	AuthorizeSessionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "authorize_session_duration_seconds",
+		Help:      "Time taken to handle authorize-session requests, by outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+
+	// DbQueryDuration observes the time taken by database operations.
+	DbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Time taken by database operations, by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"op"})
+
+	// DbLockWaitDuration observes the time taken to acquire database
+	// advisory locks, by lock and outcome.
+	DbLockWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "lock_wait_duration_seconds",
+		Help:      "Time taken trying to acquire advisory locks, by lock and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"lock", "outcome"})
+
+	// DbLockHeldDuration observes how long database advisory locks were held.
+	DbLockHeldDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "lock_held_duration_seconds",
+		Help:      "Time advisory locks were held for, by lock.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"lock"})
+
+	// DbLocksHeld is the number of database advisory locks currently held.
+	DbLocksHeld = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "locks_held",
+		Help:      "Number of advisory locks currently held, by lock.",
+	}, []string{"lock"})
+
+	// KmsKeyOperations counts the encrypt and decrypt operations done with
+	// the versions of scopes' data encryption keys.
+	KmsKeyOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "kms_key_operations_total",
+		Help:      "Number of operations done with scopes' data encryption keys, by scope, key purpose, key version and operation.",
+	}, []string{"scope_id", "purpose", "key_id", "operation"})
+
+	// ProxyActiveConnections is the number of connections a worker is
+	// currently proxying.
+	ProxyActiveConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "proxy_active_connections",
+		Help:      "Number of connections the worker is currently proxying.",
+	})
+
+	// ProxyActiveSessions is the number of sessions a worker currently has
+	// connections for.
+	ProxyActiveSessions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "proxy_active_sessions",
+		Help:      "Number of sessions the worker is currently proxying connections for.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		SessionsCreated,
+		SessionsTerminated,
+		WorkerActiveConnections,
+		AuthorizeSessionDuration,
+		DbQueryDuration,
+		DbLockWaitDuration,
+		DbLockHeldDuration,
+		DbLocksHeld,
+		KmsKeyOperations,
+		ProxyActiveConnections,
+		ProxyActiveSessions,
+	)
+}
+
+// Handler returns an http.Handler serving all metrics registered with the
+// default Prometheus registry, including those of the telemetry sink if
+// Prometheus telemetry is enabled.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Outcome returns the outcome label value for err.
+func Outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
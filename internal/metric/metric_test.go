@@ -0,0 +1,50 @@
+package metric
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	SessionsCreated.Inc()
+	SessionsTerminated.WithLabelValues("timed out").Inc()
+	WorkerActiveConnections.WithLabelValues("worker1").Set(3)
+	AuthorizeSessionDuration.WithLabelValues(Outcome(nil)).Observe(0.2)
+	DbQueryDuration.WithLabelValues("create").Observe(0.01)
+	DbLockWaitDuration.WithLabelValues("scheduler", "acquired").Observe(0.01)
+	DbLockHeldDuration.WithLabelValues("scheduler").Observe(1)
+	DbLocksHeld.WithLabelValues("scheduler").Inc()
+	KmsKeyOperations.WithLabelValues("o_1234567890", "database", "kdkv_1234567890", "encrypt").Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(200, rec.Code)
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(err)
+	for _, want := range []string{
+		"boundary_controller_sessions_created_total",
+		`boundary_controller_sessions_terminated_total{reason="timed out"} 1`,
+		`boundary_controller_worker_active_connections{worker="worker1"} 3`,
+		`boundary_controller_authorize_session_duration_seconds_count{outcome="success"} 1`,
+		`boundary_db_query_duration_seconds_count{op="create"}`,
+		`boundary_db_lock_wait_duration_seconds_count{lock="scheduler",outcome="acquired"} 1`,
+		`boundary_db_lock_held_duration_seconds_count{lock="scheduler"} 1`,
+		`boundary_db_locks_held{lock="scheduler"} 1`,
+		`boundary_controller_kms_key_operations_total{key_id="kdkv_1234567890",operation="encrypt",purpose="database",scope_id="o_1234567890"} 1`,
+		"boundary_worker_proxy_active_connections",
+		"boundary_worker_proxy_active_sessions",
+	} {
+		assert.Contains(string(body), want)
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, "success", Outcome(nil))
+	assert.Equal(t, "error", Outcome(errors.New("failed")))
+}
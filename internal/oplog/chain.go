@@ -0,0 +1,239 @@
+package oplog
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/aead"
+	"github.com/jinzhu/gorm"
+	"golang.org/x/crypto/hkdf"
+)
+
+// ErrChainBroken is returned when verifying entries finds one that was
+// changed, removed or inserted after it was written.
+var ErrChainBroken = errors.New("oplog chain broken")
+
+// hmacKeyInfo is the HKDF info used to derive an entry's hmac key from the
+// oplog key it's encrypted with
+const hmacKeyInfo = "oplog-entry-hmac"
+
+// lastHmacQuery returns the hmac of the latest entry for an aggregate
+const lastHmacQuery = `
+select hmac
+  from oplog_entry
+ where aggregate_name = $1
+order by id desc
+limit 1;
+`
+
+// chain sets the entry's hmac, which covers its encrypted data and metadata
+// and the hmac of the previous entry for its aggregate. Entries for an
+// aggregate are written one at a time by redeeming its ticket, so the
+// previous entry read here is still the latest one when the entry is written.
+func (e *Entry) chain(tx Writer) error {
+	w, err := hmacWrapper(e.Cipherer, "")
+	if err != nil {
+		return err
+	}
+	if w.KeyID() == "" {
+		return errors.New("wrapper has no key id for hmac")
+	}
+	prev, err := tx.lastHmac(e.AggregateName)
+	if err != nil {
+		return err
+	}
+	e.HmacKeyId = w.KeyID()
+	e.PrevHmac = prev
+	sum, err := entryHmac(e.Entry, w)
+	if err != nil {
+		return err
+	}
+	e.Hmac = sum
+	return nil
+}
+
+// hmacWrapper returns the aead wrapper for the key with id keyId, or for the
+// current key if keyId is empty, whose key bytes the hmac key is derived from
+func hmacWrapper(w wrapping.Wrapper, keyId string) (*aead.Wrapper, error) {
+	switch w := w.(type) {
+	case interface {
+		WrapperForKeyID(string) wrapping.Wrapper
+	}:
+		id := keyId
+		if id == "" {
+			id = "__base__"
+		}
+		if aeadWrapper, ok := w.WrapperForKeyID(id).(*aead.Wrapper); ok {
+			return aeadWrapper, nil
+		}
+		return nil, fmt.Errorf("no aead wrapper found for key %q", keyId)
+	case *aead.Wrapper:
+		if keyId != "" && w.KeyID() != keyId {
+			return nil, fmt.Errorf("wrapper key is %q not %q", w.KeyID(), keyId)
+		}
+		return w, nil
+	default:
+		return nil, fmt.Errorf("unsupported wrapper type %T for hmac", w)
+	}
+}
+
+// entryHmac returns the hmac of the entry's version, aggregate name,
+// encrypted data, metadata, hmac key id and previous hmac. Each value is
+// length prefixed and the metadata is sorted, so no two entries share an
+// encoding.
+func entryHmac(e *store.Entry, w *aead.Wrapper) ([]byte, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, w.GetKeyBytes(), nil, []byte(hmacKeyInfo)), key); err != nil {
+		return nil, fmt.Errorf("error deriving hmac key: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	writeHmacField(mac, []byte(e.Version))
+	writeHmacField(mac, []byte(e.AggregateName))
+	writeHmacField(mac, e.CtData)
+	md := make([][2]string, 0, len(e.Metadata))
+	for _, m := range e.Metadata {
+		md = append(md, [2]string{m.Key, m.Value})
+	}
+	sort.Slice(md, func(i, j int) bool {
+		if md[i][0] != md[j][0] {
+			return md[i][0] < md[j][0]
+		}
+		return md[i][1] < md[j][1]
+	})
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(md)))
+	mac.Write(n[:])
+	for _, kv := range md {
+		writeHmacField(mac, []byte(kv[0]))
+		writeHmacField(mac, []byte(kv[1]))
+	}
+	writeHmacField(mac, []byte(e.HmacKeyId))
+	writeHmacField(mac, e.PrevHmac)
+	return mac.Sum(nil), nil
+}
+
+func writeHmacField(h hash.Hash, b []byte) {
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(b)))
+	h.Write(n[:])
+	h.Write(b)
+}
+
+// WrapperFn returns the oplog wrapper holding the key with id keyId
+type WrapperFn func(ctx context.Context, keyId string) (wrapping.Wrapper, error)
+
+// ChainVerifier verifies the hmac chains of oplog entries
+type ChainVerifier struct {
+	tx        *gorm.DB
+	wrapperFn WrapperFn
+}
+
+// NewChainVerifier creates a ChainVerifier reading entries with tx and
+// getting the keys of their hmacs with wrapperFn
+func NewChainVerifier(tx *gorm.DB, wrapperFn WrapperFn) (*ChainVerifier, error) {
+	if tx == nil {
+		return nil, errors.New("new chain verifier: tx is nil")
+	}
+	if wrapperFn == nil {
+		return nil, errors.New("new chain verifier: wrapper func is nil")
+	}
+	return &ChainVerifier{tx: tx, wrapperFn: wrapperFn}, nil
+}
+
+// VerifyChain verifies the entries with ids from through to. Each entry's
+// hmac must match its contents, and it must follow the previous entry for its
+// aggregate, including the last one before from. Entries written before
+// chaining was added have no hmac and must come before any chained entry for
+// their aggregate. Entries removed from the end of an aggregate's chain can't
+// be detected. A failed verification returns an error wrapping
+// ErrChainBroken.
+func (v *ChainVerifier) VerifyChain(ctx context.Context, from, to uint32) error {
+	if from > to {
+		return fmt.Errorf("verify chain: from %d is after to %d", from, to)
+	}
+	var entries []*store.Entry
+	if err := v.tx.Preload("Metadata").Where("id between ? and ?", from, to).Order("id").Find(&entries).Error; err != nil {
+		return fmt.Errorf("verify chain: error reading entries: %w", err)
+	}
+	prevHmacs := map[string][]byte{}
+	wrappers := map[string]*aead.Wrapper{}
+	for _, e := range entries {
+		prev, ok := prevHmacs[e.AggregateName]
+		if !ok {
+			var err error
+			if prev, err = v.previousHmac(e); err != nil {
+				return fmt.Errorf("verify chain: %w", err)
+			}
+		}
+		if e.Hmac == nil {
+			if prev != nil {
+				return fmt.Errorf("verify chain: entry %d has no hmac but follows a chained entry: %w", e.Id, ErrChainBroken)
+			}
+			prevHmacs[e.AggregateName] = nil
+			continue
+		}
+		if !bytes.Equal(e.PrevHmac, prev) {
+			return fmt.Errorf("verify chain: entry %d doesn't follow the previous entry for %s: %w", e.Id, e.AggregateName, ErrChainBroken)
+		}
+		w, ok := wrappers[e.HmacKeyId]
+		if !ok {
+			kw, err := v.wrapperFn(ctx, e.HmacKeyId)
+			if err != nil {
+				return fmt.Errorf("verify chain: error getting wrapper for key %s: %w", e.HmacKeyId, err)
+			}
+			if w, err = hmacWrapper(kw, e.HmacKeyId); err != nil {
+				return fmt.Errorf("verify chain: %w", err)
+			}
+			wrappers[e.HmacKeyId] = w
+		}
+		sum, err := entryHmac(e, w)
+		if err != nil {
+			return fmt.Errorf("verify chain: %w", err)
+		}
+		if !hmac.Equal(sum, e.Hmac) {
+			return fmt.Errorf("verify chain: entry %d doesn't match its hmac: %w", e.Id, ErrChainBroken)
+		}
+		prevHmacs[e.AggregateName] = e.Hmac
+	}
+	return nil
+}
+
+// previousHmac returns the hmac of the entry before e for its aggregate
+func (v *ChainVerifier) previousHmac(e *store.Entry) ([]byte, error) {
+	rows, err := v.tx.Raw(previousHmacQuery, e.AggregateName, e.Id).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("error reading previous hmac: %w", err)
+	}
+	defer rows.Close()
+	var prev []byte
+	for rows.Next() {
+		if err := rows.Scan(&prev); err != nil {
+			return nil, fmt.Errorf("error reading previous hmac: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading previous hmac: %w", err)
+	}
+	return prev, nil
+}
+
+// previousHmacQuery returns the hmac of the entry for an aggregate before
+// the one with id $2
+const previousHmacQuery = `
+select hmac
+  from oplog_entry
+ where aggregate_name = $1
+   and id < $2
+order by id desc
+limit 1;
+`
@@ -142,6 +142,9 @@ func (e *Entry) WriteEntryWith(ctx context.Context, tx Writer, ticket *store.Tic
 		if err := e.EncryptData(ctx); err != nil {
 			return fmt.Errorf("error encrypting entry: %w", err)
 		}
+		if err := e.chain(tx); err != nil {
+			return fmt.Errorf("error chaining entry: %w", err)
+		}
 	}
 	if err := tx.Create(e); err != nil {
 		return fmt.Errorf("error writing data to storage: %w", err)
@@ -162,6 +165,9 @@ func (e *Entry) Write(ctx context.Context, tx Writer, ticket *store.Ticket) erro
 		if err := e.EncryptData(ctx); err != nil {
 			return fmt.Errorf("error encrypting entry: %w", err)
 		}
+		if err := e.chain(tx); err != nil {
+			return fmt.Errorf("error chaining entry: %w", err)
+		}
 	}
 	if err := tx.Create(e); err != nil {
 		return fmt.Errorf("error writing data to storage: %w", err)
@@ -70,6 +70,10 @@ func testWrapper(t *testing.T) wrapping.Wrapper {
 	require.NoError(t, err)
 	require.Equal(t, n, 32)
 	root := aead.NewWrapper(nil)
+	_, err = root.SetConfig(map[string]string{
+		"key_id": testId(t),
+	})
+	require.NoError(t, err)
 	err = root.SetAESGCMKeyBytes(rootKey)
 	require.NoError(t, err)
 	return root
@@ -32,6 +32,9 @@ type Writer interface {
 
 	// DropTableIfExists will drop the table if it exists
 	dropTableIfExists(tableName string) error
+
+	// lastHmac returns the hmac of the latest entry for aggregateName
+	lastHmac(aggregateName string) ([]byte, error)
 }
 
 // GormWriter uses a gorm DB connection for writing
@@ -137,3 +140,26 @@ func (w *GormWriter) dropTableIfExists(tableName string) error {
 	}
 	return w.Tx.DropTableIfExists(tableName).Error
 }
+
+// lastHmac returns the hmac of the latest entry for aggregateName, which is
+// nil if there are no entries for it or the latest one isn't chained
+func (w *GormWriter) lastHmac(aggregateName string) ([]byte, error) {
+	if w.Tx == nil {
+		return nil, errors.New("last hmac Tx is nil")
+	}
+	rows, err := w.Tx.Raw(lastHmacQuery, aggregateName).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("error reading last hmac: %w", err)
+	}
+	defer rows.Close()
+	var hmac []byte
+	for rows.Next() {
+		if err := rows.Scan(&hmac); err != nil {
+			return nil, fmt.Errorf("error reading last hmac: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading last hmac: %w", err)
+	}
+	return hmac, nil
+}
@@ -14,6 +14,8 @@ construction is thus synthesizing something reasonable from a set of Grants.
 */
 
 import (
+	"sort"
+
 	"github.com/hashicorp/boundary/internal/types/action"
 	"github.com/hashicorp/boundary/internal/types/resource"
 )
@@ -30,6 +32,10 @@ type ACL struct {
 type ACLResults struct {
 	Allowed bool
 
+	// OnlySelf is true if the action is only allowed on resources belonging
+	// to the requesting user.
+	OnlySelf bool
+
 	// This is included but unexported for testing/debugging
 	scopeMap map[string][]Grant
 }
@@ -65,62 +71,188 @@ func NewACL(grants ...Grant) ACL {
 }
 
 // Allowed determines if the grants for an ACL allow an action for a resource.
+// If the action is only granted on the requesting user's own resources, such
+// as through read:self for read, the results are Allowed and OnlySelf and the
+// caller is responsible for checking that the resource belongs to the user.
 func (a ACL) Allowed(r Resource, aType action.Type) (results ACLResults) {
 	// First, get the grants within the specified scope
 	grants := a.scopeMap[r.ScopeId]
 	results.scopeMap = a.scopeMap
 
-	// Now, go through and check the cases indicated above
+	selfType := aType.Self()
+	var onlySelf bool
 	for _, grant := range grants {
-		if !(grant.actions[aType] || grant.actions[action.All]) {
+		switch {
+		case grant.actions[aType] || grant.actions[action.All]:
+			if grant.matches(r, aType) {
+				results.Allowed = true
+				return
+			}
+		case selfType != action.Unknown && grant.actions[selfType]:
+			if grant.matches(r, aType) {
+				onlySelf = true
+			}
+		}
+	}
+	if onlySelf {
+		results.Allowed = true
+		results.OnlySelf = true
+	}
+	return
+}
+
+// ResourcePermissions describes the resources of a type within a scope that
+// an action is allowed on.
+type ResourcePermissions struct {
+	// All is true if the action is allowed on every resource of the type.
+	All bool
+
+	// OnlySelf is true if the action is allowed on every resource of the type
+	// belonging to the requesting user, such as through read:self.
+	OnlySelf bool
+
+	// Ids are the resources the action is allowed on by id. They may include
+	// ids of resources of other types.
+	Ids []string
+
+	// Pins are the resources the action is allowed on the children of, such
+	// as the host catalogs for hosts.
+	Pins []string
+}
+
+// ResourcePermissions returns the resources of type typ within scopeId that
+// the grants allow aType on. Rather than checking resources one at a time as
+// Allowed does, it expands the grants into a description of the resources,
+// so the resources can then be looked up all at once.
+func (a ACL) ResourcePermissions(scopeId string, typ resource.Type, aType action.Type) ResourcePermissions {
+	var ret ResourcePermissions
+	ids := make(map[string]bool)
+	pins := make(map[string]bool)
+	selfType := aType.Self()
+	for _, grant := range a.scopeMap[scopeId] {
+		typeMatches := grant.typ == typ || grant.typ == resource.All
+		switch {
+		case grant.actions[aType] || grant.actions[action.All]:
+		case selfType != action.Unknown && grant.actions[selfType]:
+			if grant.id == "*" && typeMatches {
+				ret.OnlySelf = true
+			}
+			continue
+		default:
 			continue
 		}
+
 		switch {
-		// id=<resource.id>;actions=<action> where ID cannot be a wildcard
-		case grant.id == r.Id &&
-			grant.id != "" &&
-			grant.id != "*" &&
-			grant.typ == resource.Unknown:
-
-			results.Allowed = true
-			return
-
-		// type=<resource.type>;actions=<action> when action is list or create.
-		// Must be a top level collection, otherwise must be one of the two
-		// formats specified below.
-		case grant.id == "" &&
-			r.Id == "" &&
-			grant.typ == r.Type &&
-			grant.typ != resource.Unknown &&
-			topLevelType(r.Type) &&
-			(aType == action.List || aType == action.Create):
-
-			results.Allowed = true
-			return
-
-		// id=*;type=<resource.type>;actions=<action> where type cannot be
-		// unknown but can be a wildcard to allow any resource at all
-		case grant.id == "*" &&
-			grant.typ != resource.Unknown &&
-			(grant.typ == r.Type ||
-				grant.typ == resource.All):
-
-			results.Allowed = true
-			return
-
-		// id=<pin>;type=<resource.type>;actions=<action> where type can be a
-		// wildcard and this this is operating on a non-top-level type
-		case grant.id != "" &&
-			grant.id == r.Pin &&
-			grant.typ != resource.Unknown &&
-			(grant.typ == r.Type || grant.typ == resource.All) &&
-			!topLevelType(r.Type):
-
-			results.Allowed = true
-			return
+		// id=*;type=<resource.type>;actions=<action>
+		case grant.id == "*" && typeMatches:
+			return ResourcePermissions{All: true}
+
+		// id=<resource.id>;actions=<action>
+		case grant.id != "" && grant.id != "*" && grant.typ == resource.Unknown:
+			ids[grant.id] = true
+
+		// id=<pin>;type=<resource.type>;actions=<action>
+		case grant.id != "" && grant.id != "*" && typeMatches && !topLevelType(typ):
+			pins[grant.id] = true
 		}
 	}
-	return
+	ret.Ids, ret.Pins = sortedKeys(ids), sortedKeys(pins)
+	return ret
+}
+
+// Intersect returns the resources described by both p and o, for actions
+// which must be allowed by two sets of grants, such as those of a user and of
+// the api token a request is made with. Resources allowed by id in one and
+// only as the user's own in the other can't be described, so they're left
+// out.
+func (p ResourcePermissions) Intersect(o ResourcePermissions) ResourcePermissions {
+	switch {
+	case p.All:
+		return o
+	case o.All:
+		return p
+	}
+	return ResourcePermissions{
+		OnlySelf: p.OnlySelf && o.OnlySelf,
+		Ids:      intersectSorted(p.Ids, o.Ids),
+		Pins:     intersectSorted(p.Pins, o.Pins),
+	}
+}
+
+// intersectSorted returns the strings in both a and b, which are sorted.
+func intersectSorted(a, b []string) []string {
+	var ret []string
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			ret = append(ret, a[i])
+			i++
+			j++
+		}
+	}
+	return ret
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// matches returns true if the grant applies to performing aType on r; see the
+// comment at the top of the file.
+func (g Grant) matches(r Resource, aType action.Type) bool {
+	switch {
+	// id=<resource.id>;actions=<action> where ID cannot be a wildcard
+	case g.id == r.Id &&
+		g.id != "" &&
+		g.id != "*" &&
+		g.typ == resource.Unknown:
+
+		return true
+
+	// type=<resource.type>;actions=<action> when action is list or create.
+	// Must be a top level collection, otherwise must be one of the two
+	// formats specified below.
+	case g.id == "" &&
+		r.Id == "" &&
+		g.typ == r.Type &&
+		g.typ != resource.Unknown &&
+		topLevelType(r.Type) &&
+		(aType == action.List || aType == action.Create):
+
+		return true
+
+	// id=*;type=<resource.type>;actions=<action> where type cannot be
+	// unknown but can be a wildcard to allow any resource at all
+	case g.id == "*" &&
+		g.typ != resource.Unknown &&
+		(g.typ == r.Type ||
+			g.typ == resource.All):
+
+		return true
+
+	// id=<pin>;type=<resource.type>;actions=<action> where type can be a
+	// wildcard and this this is operating on a non-top-level type
+	case g.id != "" &&
+		g.id == r.Pin &&
+		g.typ != resource.Unknown &&
+		(g.typ == r.Type || g.typ == resource.All) &&
+		!topLevelType(r.Type):
+
+		return true
+	}
+	return false
 }
 
 func topLevelType(typ resource.Type) bool {
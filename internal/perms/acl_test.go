@@ -271,3 +271,219 @@ func Test_ACLAllowed(t *testing.T) {
 		})
 	}
 }
+
+func Test_ACLAllowedSelf(t *testing.T) {
+	t.Parallel()
+
+	var grants []Grant
+	for _, g := range []string{
+		"id=*;type=session;actions=list,read:self,cancel:self",
+		"id=*;type=auth-token;actions=read,delete:self",
+		"id=s_1234567890;actions=cancel",
+	} {
+		grant, err := Parse("p_1234567890", g)
+		require.NoError(t, err)
+		grants = append(grants, grant)
+	}
+	acl := NewACL(grants...)
+
+	tests := []struct {
+		name         string
+		resource     Resource
+		action       action.Type
+		wantAllowed  bool
+		wantOnlySelf bool
+	}{
+		{
+			name:         "self read",
+			resource:     Resource{ScopeId: "p_1234567890", Id: "s_abcdefghij", Type: resource.Session},
+			action:       action.Read,
+			wantAllowed:  true,
+			wantOnlySelf: true,
+		},
+		{
+			name:         "self cancel",
+			resource:     Resource{ScopeId: "p_1234567890", Id: "s_abcdefghij", Type: resource.Session},
+			action:       action.Cancel,
+			wantAllowed:  true,
+			wantOnlySelf: true,
+		},
+		{
+			name:        "full grant wins over self",
+			resource:    Resource{ScopeId: "p_1234567890", Id: "s_1234567890", Type: resource.Session},
+			action:      action.Cancel,
+			wantAllowed: true,
+		},
+		{
+			name:     "no self variant",
+			resource: Resource{ScopeId: "p_1234567890", Id: "s_abcdefghij", Type: resource.Session},
+			action:   action.Delete,
+		},
+		{
+			name:         "self delete of auth token",
+			resource:     Resource{ScopeId: "p_1234567890", Id: "at_1234567890", Type: resource.AuthToken},
+			action:       action.Delete,
+			wantAllowed:  true,
+			wantOnlySelf: true,
+		},
+		{
+			name:        "full read of auth token",
+			resource:    Resource{ScopeId: "p_1234567890", Id: "at_1234567890", Type: resource.AuthToken},
+			action:      action.Read,
+			wantAllowed: true,
+		},
+		{
+			name:     "other scope",
+			resource: Resource{ScopeId: "p_abcdefghij", Id: "s_abcdefghij", Type: resource.Session},
+			action:   action.Read,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := acl.Allowed(tt.resource, tt.action)
+			assert.Equal(t, tt.wantAllowed, results.Allowed)
+			assert.Equal(t, tt.wantOnlySelf, results.OnlySelf)
+		})
+	}
+}
+
+func Test_ACLResourcePermissions(t *testing.T) {
+	t.Parallel()
+
+	var grants []Grant
+	for _, g := range []struct{ scope, grant string }{
+		{"o_1234567890", "id=*;type=target;actions=read"},
+		{"p_1234567890", "id=ttcp_1234567890;actions=read,authorize-session"},
+		{"p_1234567890", "id=ttcp_abcdefghij;actions=*"},
+		{"p_1234567890", "id=ttcp_1234567890;actions=update"},
+		{"p_1234567890", "id=hcst_1234567890;type=host;actions=read"},
+		{"p_0987654321", "id=*;type=session;actions=list,read:self"},
+		{"p_abcdefghij", "id=*;type=*;actions=*"},
+	} {
+		grant, err := Parse(g.scope, g.grant)
+		require.NoError(t, err)
+		grants = append(grants, grant)
+	}
+	acl := NewACL(grants...)
+
+	tests := []struct {
+		name    string
+		scopeId string
+		typ     resource.Type
+		action  action.Type
+		want    ResourcePermissions
+	}{
+		{
+			name:    "ids",
+			scopeId: "p_1234567890",
+			typ:     resource.Target,
+			action:  action.Read,
+			want:    ResourcePermissions{Ids: []string{"ttcp_1234567890", "ttcp_abcdefghij"}},
+		},
+		{
+			name:    "id with all actions",
+			scopeId: "p_1234567890",
+			typ:     resource.Target,
+			action:  action.Delete,
+			want:    ResourcePermissions{Ids: []string{"ttcp_abcdefghij"}},
+		},
+		{
+			name:    "self",
+			scopeId: "p_0987654321",
+			typ:     resource.Session,
+			action:  action.Read,
+			want:    ResourcePermissions{OnlySelf: true},
+		},
+		{
+			// Id grants aren't specific to a type
+			name:    "pin",
+			scopeId: "p_1234567890",
+			typ:     resource.Host,
+			action:  action.Read,
+			want: ResourcePermissions{
+				Ids:  []string{"ttcp_1234567890", "ttcp_abcdefghij"},
+				Pins: []string{"hcst_1234567890"},
+			},
+		},
+		{
+			name:    "wildcard",
+			scopeId: "o_1234567890",
+			typ:     resource.Target,
+			action:  action.Read,
+			want:    ResourcePermissions{All: true},
+		},
+		{
+			name:    "wildcard type",
+			scopeId: "p_abcdefghij",
+			typ:     resource.Session,
+			action:  action.Cancel,
+			want:    ResourcePermissions{All: true},
+		},
+		{
+			name:    "no grants",
+			scopeId: "p_1111111111",
+			typ:     resource.Target,
+			action:  action.Read,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, acl.ResourcePermissions(tt.scopeId, tt.typ, tt.action))
+		})
+	}
+}
+
+func Test_ResourcePermissionsIntersect(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		p, o ResourcePermissions
+		want ResourcePermissions
+	}{
+		{
+			name: "all",
+			p:    ResourcePermissions{All: true},
+			o:    ResourcePermissions{Ids: []string{"ttcp_1234567890"}},
+			want: ResourcePermissions{Ids: []string{"ttcp_1234567890"}},
+		},
+		{
+			name: "all other",
+			p:    ResourcePermissions{OnlySelf: true},
+			o:    ResourcePermissions{All: true},
+			want: ResourcePermissions{OnlySelf: true},
+		},
+		{
+			name: "ids and pins",
+			p: ResourcePermissions{
+				Ids:  []string{"ttcp_1234567890", "ttcp_abcdefghij"},
+				Pins: []string{"hcst_1234567890"},
+			},
+			o: ResourcePermissions{
+				Ids:  []string{"ttcp_0987654321", "ttcp_abcdefghij"},
+				Pins: []string{"hcst_0987654321", "hcst_1234567890"},
+			},
+			want: ResourcePermissions{
+				Ids:  []string{"ttcp_abcdefghij"},
+				Pins: []string{"hcst_1234567890"},
+			},
+		},
+		{
+			name: "self and ids",
+			p:    ResourcePermissions{OnlySelf: true},
+			o:    ResourcePermissions{Ids: []string{"s_1234567890"}},
+			want: ResourcePermissions{},
+		},
+		{
+			name: "self",
+			p:    ResourcePermissions{OnlySelf: true, Ids: []string{"s_1234567890"}},
+			o:    ResourcePermissions{OnlySelf: true},
+			want: ResourcePermissions{OnlySelf: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.p.Intersect(tt.o))
+			assert.Equal(t, tt.want, tt.o.Intersect(tt.p))
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Package quota counts the API requests made in and the sessions active in
+// each organization, and limits them to configured quotas, so the tenants of
+// a shared cluster can be billed for and protected from each other's usage.
+// Request counts are kept in the database so they are shared by all of the
+// controllers of a cluster.
+package quota
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Store keeps the counts quotas are checked against.
+type Store interface {
+	// AddRequests adds count to the requests made in the organization with
+	// id scopeId in the period starting at periodStart, and returns the
+	// period's new total.
+	AddRequests(ctx context.Context, scopeId string, periodStart time.Time, count uint64) (uint64, error)
+
+	// RequestCount returns the requests made in the organization in the
+	// period starting at periodStart.
+	RequestCount(ctx context.Context, scopeId string, periodStart time.Time) (uint64, error)
+
+	// ActiveSessionCount returns the number of pending and active sessions
+	// in the organization's projects.
+	ActiveSessionCount(ctx context.Context, scopeId string) (uint64, error)
+}
+
+// Usage is an organization's use of its quotas.
+type Usage struct {
+	ScopeId string
+
+	// PeriodStart and PeriodEnd bound the period Requests were counted in.
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+
+	Requests       uint64
+	ActiveSessions uint64
+
+	// Limits are the organization's quotas. Zero means unlimited.
+	Limits
+}
+
+// periodKey identifies the requests made in an organization in a period.
+type periodKey struct {
+	scopeId     string
+	periodStart time.Time
+}
+
+// Accountant counts API requests and checks them and active sessions against
+// each organization's quotas. Requests are counted in memory and written to
+// the Store by Flush, so the requests made through other controllers are
+// only seen by this one after it flushes, and an organization can go over
+// its request quota by what the controllers count between flushes. It is
+// safe for concurrent use.
+type Accountant struct {
+	store Store
+
+	l    sync.Mutex
+	conf *Config
+	// pending are the requests counted since the last flush
+	pending map[periodKey]uint64
+	// flushed are the totals returned by the store at the last flush
+	flushed map[periodKey]uint64
+
+	// now returns the current time and is replaced in tests.
+	now func() time.Time
+}
+
+// NewAccountant creates an Accountant with the quotas in c which keeps its
+// counts in store.
+func NewAccountant(store Store, c *Config) (*Accountant, error) {
+	if store == nil {
+		return nil, errors.New("new quota accountant: missing store")
+	}
+	if !c.Enabled() {
+		return nil, errors.New("new quota accountant: no quotas configured")
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &Accountant{
+		store:   store,
+		conf:    c,
+		pending: make(map[periodKey]uint64),
+		flushed: make(map[periodKey]uint64),
+		now:     time.Now,
+	}, nil
+}
+
+// CountRequest counts a request made in the organization with id scopeId. It
+// returns an *ExceededError, and doesn't count the request, if the
+// organization has already made as many requests in this period as its quota
+// allows.
+func (a *Accountant) CountRequest(scopeId string) error {
+	a.l.Lock()
+	defer a.l.Unlock()
+	start, end := a.conf.periodBounds(a.now())
+	key := periodKey{scopeId: scopeId, periodStart: start}
+	used := a.flushed[key] + a.pending[key]
+	if limit := a.conf.limits(scopeId).MaxRequests; limit > 0 && used >= limit {
+		return &ExceededError{
+			ScopeId:   scopeId,
+			Quota:     Requests,
+			Limit:     limit,
+			Used:      used,
+			ResetTime: end,
+		}
+	}
+	a.pending[key]++
+	return nil
+}
+
+// CheckSessions returns an *ExceededError if the organization with id
+// scopeId already has as many pending and active sessions as its quota
+// allows.
+func (a *Accountant) CheckSessions(ctx context.Context, scopeId string) error {
+	a.l.Lock()
+	limit := a.conf.limits(scopeId).MaxActiveSessions
+	a.l.Unlock()
+	if limit == 0 {
+		return nil
+	}
+	active, err := a.store.ActiveSessionCount(ctx, scopeId)
+	if err != nil {
+		return err
+	}
+	if active >= limit {
+		return &ExceededError{
+			ScopeId: scopeId,
+			Quota:   ActiveSessions,
+			Limit:   limit,
+			Used:    active,
+		}
+	}
+	return nil
+}
+
+// Flush writes the requests counted since the last flush to the store and
+// returns the number of organizations whose counts were written. Counts
+// which fail to be written are kept for the next flush.
+func (a *Accountant) Flush(ctx context.Context) (int, error) {
+	a.l.Lock()
+	pending := a.pending
+	a.pending = make(map[periodKey]uint64)
+	a.l.Unlock()
+
+	var flushed int
+	var errs []error
+	for key, count := range pending {
+		total, err := a.store.AddRequests(ctx, key.scopeId, key.periodStart, count)
+		a.l.Lock()
+		if err != nil {
+			a.pending[key] += count
+		} else {
+			a.flushed[key] = total
+			flushed++
+		}
+		a.l.Unlock()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Forget the totals of past periods
+	a.l.Lock()
+	start, _ := a.conf.periodBounds(a.now())
+	for key := range a.flushed {
+		if key.periodStart.Before(start) {
+			delete(a.flushed, key)
+		}
+	}
+	a.l.Unlock()
+
+	if len(errs) > 0 {
+		return flushed, errs[0]
+	}
+	return flushed, nil
+}
+
+// Usage returns the current usage and limits of the organization with id
+// scopeId, including the requests this controller has counted but not yet
+// flushed.
+func (a *Accountant) Usage(ctx context.Context, scopeId string) (*Usage, error) {
+	a.l.Lock()
+	start, end := a.conf.periodBounds(a.now())
+	limits := a.conf.limits(scopeId)
+	pending := a.pending[periodKey{scopeId: scopeId, periodStart: start}]
+	a.l.Unlock()
+
+	requests, err := a.store.RequestCount(ctx, scopeId, start)
+	if err != nil {
+		return nil, err
+	}
+	active, err := a.store.ActiveSessionCount(ctx, scopeId)
+	if err != nil {
+		return nil, err
+	}
+	return &Usage{
+		ScopeId:        scopeId,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Requests:       requests + pending,
+		ActiveSessions: active,
+		Limits:         limits,
+	}, nil
+}
+
+// SetConfig replaces the quotas with those in c. Counts are kept, and are
+// checked against the new limits from the next request. Changing the period
+// starts the new period's counts from what has been stored for it.
+func (a *Accountant) SetConfig(c *Config) error {
+	if !c.Enabled() {
+		return errors.New("set quotas: no quotas configured")
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	a.l.Lock()
+	defer a.l.Unlock()
+	a.conf = c
+	return nil
+}
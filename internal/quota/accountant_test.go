@@ -0,0 +1,165 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore is a Store keeping its counts in memory.
+type testStore struct {
+	sync.Mutex
+	requests map[periodKey]uint64
+	sessions map[string]uint64
+	err      error
+}
+
+func newTestStore() *testStore {
+	return &testStore{
+		requests: make(map[periodKey]uint64),
+		sessions: make(map[string]uint64),
+	}
+}
+
+func (s *testStore) AddRequests(_ context.Context, scopeId string, periodStart time.Time, count uint64) (uint64, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	key := periodKey{scopeId: scopeId, periodStart: periodStart}
+	s.requests[key] += count
+	return s.requests[key], nil
+}
+
+func (s *testStore) RequestCount(_ context.Context, scopeId string, periodStart time.Time) (uint64, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.requests[periodKey{scopeId: scopeId, periodStart: periodStart}], s.err
+}
+
+func (s *testStore) ActiveSessionCount(_ context.Context, scopeId string) (uint64, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.sessions[scopeId], s.err
+}
+
+func TestNewAccountant(t *testing.T) {
+	_, err := NewAccountant(nil, &Config{})
+	assert.Error(t, err)
+	_, err = NewAccountant(newTestStore(), nil)
+	assert.Error(t, err)
+	_, err = NewAccountant(newTestStore(), &Config{Period: "weekly"})
+	assert.Error(t, err)
+	_, err = NewAccountant(newTestStore(), &Config{})
+	assert.NoError(t, err)
+}
+
+func TestAccountant_CountRequest(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	store := newTestStore()
+	a, err := NewAccountant(store, &Config{
+		Period:      Daily,
+		MaxRequests: 3,
+		Scopes: []*ScopeConfig{
+			{ScopeId: "o_unlimited", MaxRequests: 0},
+		},
+	})
+	require.NoError(err)
+	now := time.Date(2021, time.March, 10, 15, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	dayStart := time.Date(2021, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	// Requests from other controllers are seen once flushed
+	store.requests[periodKey{scopeId: "o_1234567890", periodStart: dayStart}] = 1
+	require.NoError(a.CountRequest("o_1234567890"))
+	flushed, err := a.Flush(ctx)
+	require.NoError(err)
+	assert.Equal(1, flushed)
+	require.NoError(a.CountRequest("o_1234567890"))
+
+	err = a.CountRequest("o_1234567890")
+	require.Error(err)
+	assert.True(errors.Is(err, ErrExceeded))
+	var exceeded *ExceededError
+	require.True(errors.As(err, &exceeded))
+	assert.Equal(&ExceededError{
+		ScopeId:   "o_1234567890",
+		Quota:     Requests,
+		Limit:     3,
+		Used:      3,
+		ResetTime: dayStart.AddDate(0, 0, 1),
+	}, exceeded)
+
+	// Scopes can be unlimited
+	for i := 0; i < 5; i++ {
+		require.NoError(a.CountRequest("o_unlimited"))
+	}
+
+	// A new period starts over
+	now = now.AddDate(0, 0, 1)
+	require.NoError(a.CountRequest("o_1234567890"))
+
+	usage, err := a.Usage(ctx, "o_1234567890")
+	require.NoError(err)
+	assert.Equal(uint64(1), usage.Requests)
+	assert.Equal(uint64(3), usage.MaxRequests)
+	assert.Equal(dayStart.AddDate(0, 0, 1), usage.PeriodStart)
+	assert.Equal(dayStart.AddDate(0, 0, 2), usage.PeriodEnd)
+}
+
+func TestAccountant_Flush(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	store := newTestStore()
+	a, err := NewAccountant(store, &Config{Period: Hourly})
+	require.NoError(err)
+	start, _ := a.conf.periodBounds(a.now())
+
+	store.err = errors.New("unavailable")
+	require.NoError(a.CountRequest("o_1234567890"))
+	require.NoError(a.CountRequest("o_1234567890"))
+	_, err = a.Flush(ctx)
+	require.Error(err)
+
+	// Counts which failed to be written are kept
+	store.err = nil
+	require.NoError(a.CountRequest("o_1234567890"))
+	flushed, err := a.Flush(ctx)
+	require.NoError(err)
+	assert.Equal(1, flushed)
+	assert.Equal(uint64(3), store.requests[periodKey{scopeId: "o_1234567890", periodStart: start}])
+
+	flushed, err = a.Flush(ctx)
+	require.NoError(err)
+	assert.Equal(0, flushed)
+}
+
+func TestAccountant_CheckSessions(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	store := newTestStore()
+	a, err := NewAccountant(store, &Config{MaxActiveSessions: 2})
+	require.NoError(err)
+
+	store.sessions["o_1234567890"] = 1
+	require.NoError(a.CheckSessions(ctx, "o_1234567890"))
+	store.sessions["o_1234567890"] = 2
+	err = a.CheckSessions(ctx, "o_1234567890")
+	require.Error(err)
+	var exceeded *ExceededError
+	require.True(errors.As(err, &exceeded))
+	assert.Equal(ActiveSessions, exceeded.Quota)
+	assert.Equal(uint64(2), exceeded.Used)
+	assert.True(exceeded.ResetTime.IsZero())
+
+	require.NoError(a.SetConfig(&Config{MaxActiveSessions: 3}))
+	require.NoError(a.CheckSessions(ctx, "o_1234567890"))
+	assert.Error(a.SetConfig(nil))
+}
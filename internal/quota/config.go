@@ -0,0 +1,116 @@
+package quota
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// The periods API requests are counted over. Periods are aligned to the
+// hour, day or month in UTC.
+const (
+	Hourly  = "hourly"
+	Daily   = "daily"
+	Monthly = "monthly"
+)
+
+// Config is the configuration of quotas, as given in the "quota" block of a
+// controller's configuration.
+type Config struct {
+	// Period is how often request counts start over, one of "hourly",
+	// "daily" or "monthly". If empty, "monthly" is used.
+	Period string `hcl:"period"`
+
+	// MaxRequests is the number of API requests each organization can make
+	// in a period. Zero means requests are counted but not limited.
+	MaxRequests int `hcl:"max_requests"`
+
+	// MaxActiveSessions is the number of pending and active sessions each
+	// organization can have at once. Zero means sessions are not limited.
+	MaxActiveSessions int `hcl:"max_active_sessions"`
+
+	// Scopes override the limits for individual organizations.
+	Scopes []*ScopeConfig `hcl:"scope"`
+}
+
+// ScopeConfig overrides the limits of a Config for an organization, given as
+// a "scope" block labeled with the organization's id.
+type ScopeConfig struct {
+	ScopeId string `hcl:",key"`
+
+	// MaxRequests replaces Config.MaxRequests for the organization.
+	MaxRequests int `hcl:"max_requests"`
+
+	// MaxActiveSessions replaces Config.MaxActiveSessions for the
+	// organization.
+	MaxActiveSessions int `hcl:"max_active_sessions"`
+}
+
+// Limits are the quotas of an organization. Zero means unlimited.
+type Limits struct {
+	MaxRequests       uint64
+	MaxActiveSessions uint64
+}
+
+// Validate returns an error if c is invalid. A nil Config is valid.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Period {
+	case "", Hourly, Daily, Monthly:
+	default:
+		return fmt.Errorf("quota: unknown period %q", c.Period)
+	}
+	if c.MaxRequests < 0 || c.MaxActiveSessions < 0 {
+		return fmt.Errorf("quota: limits must not be negative")
+	}
+	seen := make(map[string]bool, len(c.Scopes))
+	for _, s := range c.Scopes {
+		if s.MaxRequests < 0 || s.MaxActiveSessions < 0 {
+			return fmt.Errorf("quota: limits of scope %q must not be negative", s.ScopeId)
+		}
+		if !strings.HasPrefix(s.ScopeId, scope.Org.Prefix()+"_") {
+			return fmt.Errorf("quota: scope %q is not an organization", s.ScopeId)
+		}
+		if seen[s.ScopeId] {
+			return fmt.Errorf("quota: scope %q is given more than once", s.ScopeId)
+		}
+		seen[s.ScopeId] = true
+	}
+	return nil
+}
+
+// Enabled returns true if c is set. Requests are counted when quotas are
+// enabled even if no limits are configured.
+func (c *Config) Enabled() bool {
+	return c != nil
+}
+
+// limits returns the limits of the organization with id scopeId.
+func (c *Config) limits(scopeId string) Limits {
+	for _, s := range c.Scopes {
+		if s.ScopeId == scopeId {
+			return Limits{MaxRequests: uint64(s.MaxRequests), MaxActiveSessions: uint64(s.MaxActiveSessions)}
+		}
+	}
+	return Limits{MaxRequests: uint64(c.MaxRequests), MaxActiveSessions: uint64(c.MaxActiveSessions)}
+}
+
+// periodBounds returns the start and end of the period containing t.
+func (c *Config) periodBounds(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	switch c.Period {
+	case Hourly:
+		start := t.Truncate(time.Hour)
+		return start, start.Add(time.Hour)
+	case Daily:
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	default:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+}
@@ -0,0 +1,49 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	var c *Config
+	assert.NoError(t, c.Validate())
+	assert.NoError(t, (&Config{Period: Monthly, Scopes: []*ScopeConfig{{ScopeId: "o_1234567890"}}}).Validate())
+	assert.Error(t, (&Config{Period: "weekly"}).Validate())
+	assert.Error(t, (&Config{Scopes: []*ScopeConfig{{ScopeId: "p_1234567890"}}}).Validate())
+	assert.Error(t, (&Config{Scopes: []*ScopeConfig{{ScopeId: "o_1234567890"}, {ScopeId: "o_1234567890"}}}).Validate())
+	assert.Error(t, (&Config{MaxRequests: -1}).Validate())
+	assert.Error(t, (&Config{Scopes: []*ScopeConfig{{ScopeId: "o_1234567890", MaxActiveSessions: -1}}}).Validate())
+}
+
+func TestConfig_limits(t *testing.T) {
+	c := &Config{
+		MaxRequests:       100,
+		MaxActiveSessions: 10,
+		Scopes: []*ScopeConfig{
+			{ScopeId: "o_1234567890", MaxRequests: 1000},
+		},
+	}
+	assert.Equal(t, Limits{MaxRequests: 100, MaxActiveSessions: 10}, c.limits("o_0987654321"))
+	assert.Equal(t, Limits{MaxRequests: 1000}, c.limits("o_1234567890"))
+}
+
+func TestConfig_periodBounds(t *testing.T) {
+	now := time.Date(2021, time.December, 31, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		period     string
+		start, end time.Time
+	}{
+		{Hourly, time.Date(2021, time.December, 31, 23, 0, 0, 0, time.UTC), time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{Daily, time.Date(2021, time.December, 31, 0, 0, 0, 0, time.UTC), time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{Monthly, time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"", time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		start, end := (&Config{Period: tt.period}).periodBounds(now)
+		assert.Equal(t, tt.start, start, tt.period)
+		assert.Equal(t, tt.end, end, tt.period)
+	}
+}
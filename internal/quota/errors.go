@@ -0,0 +1,49 @@
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Type is a kind of quota.
+type Type string
+
+const (
+	// Requests is the quota of API requests made in a period.
+	Requests Type = "requests"
+
+	// ActiveSessions is the quota of sessions which are pending or active at
+	// once.
+	ActiveSessions Type = "active_sessions"
+)
+
+// ErrExceeded matches every *ExceededError with errors.Is.
+var ErrExceeded = errors.New("quota exceeded")
+
+// ExceededError is returned when an organization has used all of a quota.
+type ExceededError struct {
+	// ScopeId is the id of the organization.
+	ScopeId string
+
+	// Quota is the quota which was used up.
+	Quota Type
+
+	// Limit is the organization's limit for the quota and Used how much of it
+	// has been used.
+	Limit uint64
+	Used  uint64
+
+	// ResetTime is when the quota is next reset. It is the zero time for
+	// quotas which aren't reset, such as ActiveSessions.
+	ResetTime time.Time
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s quota of %d exceeded for scope %s", e.Quota, e.Limit, e.ScopeId)
+}
+
+// Is returns true if target is ErrExceeded.
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrExceeded
+}
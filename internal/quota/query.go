@@ -0,0 +1,44 @@
+package quota
+
+const (
+	// addRequestsQuery adds $3 requests to the count of the organization $1
+	// in the period starting at $2 and returns the new count.
+	addRequestsQuery = `
+	insert into quota_request_count
+		(scope_id, period_start, request_count, update_time)
+	values
+		($1, $2, $3, now())
+	on conflict (scope_id, period_start)
+	do update set
+		request_count = quota_request_count.request_count + excluded.request_count,
+		update_time = now()
+	returning request_count;
+	`
+
+	// requestCountQuery returns the count of requests made in the
+	// organization $1 in the period starting at $2.
+	requestCountQuery = `
+	select request_count
+	from quota_request_count
+	where
+		scope_id = $1 and
+		period_start = $2;
+	`
+
+	// activeSessionCountQuery returns the number of sessions in the projects
+	// of the organization $1 whose current state is pending or active.
+	activeSessionCountQuery = `
+	select count(*)
+	from
+		session s,
+		session_state ss,
+		iam_scope_project p
+	where
+		s.public_id = ss.session_id and
+		s.scope_id = p.scope_id and
+		p.parent_id = $1 and
+		ss.state in ('pending', 'active') and
+		-- if there's no end_time, then this is the current state.
+		ss.end_time is null;
+	`
+)
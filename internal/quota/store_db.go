@@ -0,0 +1,107 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// DbStore keeps request counts in the database, so that they are shared by
+// all of the controllers using it.
+type DbStore struct {
+	reader db.Reader
+	writer db.Writer
+}
+
+var _ Store = (*DbStore)(nil)
+
+// NewDbStore creates a new DbStore.
+func NewDbStore(r db.Reader, w db.Writer) (*DbStore, error) {
+	if r == nil {
+		return nil, fmt.Errorf("new quota db store: missing reader: %w", db.ErrInvalidParameter)
+	}
+	if w == nil {
+		return nil, fmt.Errorf("new quota db store: missing writer: %w", db.ErrInvalidParameter)
+	}
+	return &DbStore{
+		reader: r,
+		writer: w,
+	}, nil
+}
+
+// AddRequests implements Store.
+func (s *DbStore) AddRequests(ctx context.Context, scopeId string, periodStart time.Time, count uint64) (uint64, error) {
+	if scopeId == "" {
+		return 0, fmt.Errorf("add requests: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	if periodStart.IsZero() {
+		return 0, fmt.Errorf("add requests: missing period start: %w", db.ErrInvalidParameter)
+	}
+
+	var total uint64
+	_, err := s.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, _ db.Writer) error {
+			rows, err := reader.Query(ctx, addRequestsQuery, []interface{}{scopeId, periodStart, count})
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				if err := rows.Scan(&total); err != nil {
+					return err
+				}
+			}
+			return rows.Err()
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("add requests: %s: %w", scopeId, err)
+	}
+	return total, nil
+}
+
+// RequestCount implements Store.
+func (s *DbStore) RequestCount(ctx context.Context, scopeId string, periodStart time.Time) (uint64, error) {
+	if scopeId == "" {
+		return 0, fmt.Errorf("request count: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	count, err := s.count(ctx, requestCountQuery, scopeId, periodStart)
+	if err != nil {
+		return 0, fmt.Errorf("request count: %s: %w", scopeId, err)
+	}
+	return count, nil
+}
+
+// ActiveSessionCount implements Store.
+func (s *DbStore) ActiveSessionCount(ctx context.Context, scopeId string) (uint64, error) {
+	if scopeId == "" {
+		return 0, fmt.Errorf("active session count: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	count, err := s.count(ctx, activeSessionCountQuery, scopeId)
+	if err != nil {
+		return 0, fmt.Errorf("active session count: %s: %w", scopeId, err)
+	}
+	return count, nil
+}
+
+// count runs a query returning at most one count, and returns zero if it
+// returns no rows.
+func (s *DbStore) count(ctx context.Context, query string, args ...interface{}) (uint64, error) {
+	rows, err := s.reader.Query(ctx, query, args)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var count uint64
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+	}
+	return count, rows.Err()
+}
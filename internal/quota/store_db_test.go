@@ -0,0 +1,74 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDbStore(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	s, err := NewDbStore(rw, rw)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("requests", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		org, _ := iam.TestScopes(t, iamRepo)
+		start := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+		count, err := s.RequestCount(ctx, org.PublicId, start)
+		require.NoError(err)
+		assert.Equal(uint64(0), count)
+
+		total, err := s.AddRequests(ctx, org.PublicId, start, 5)
+		require.NoError(err)
+		assert.Equal(uint64(5), total)
+		total, err = s.AddRequests(ctx, org.PublicId, start, 3)
+		require.NoError(err)
+		assert.Equal(uint64(8), total)
+
+		// Periods are counted separately
+		total, err = s.AddRequests(ctx, org.PublicId, start.AddDate(0, 1, 0), 1)
+		require.NoError(err)
+		assert.Equal(uint64(1), total)
+
+		count, err = s.RequestCount(ctx, org.PublicId, start)
+		require.NoError(err)
+		assert.Equal(uint64(8), count)
+	})
+	t.Run("active-sessions", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		sess := session.TestDefaultSession(t, conn, wrapper, iamRepo)
+		proj, err := iamRepo.LookupScope(ctx, sess.ScopeId)
+		require.NoError(err)
+
+		count, err := s.ActiveSessionCount(ctx, proj.ParentId)
+		require.NoError(err)
+		assert.Equal(uint64(1), count)
+
+		session.TestState(t, conn, sess.PublicId, session.StatusTerminated)
+		count, err = s.ActiveSessionCount(ctx, proj.ParentId)
+		require.NoError(err)
+		assert.Equal(uint64(0), count)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := s.AddRequests(ctx, "", time.Now(), 1)
+		assert.Error(t, err)
+		_, err = s.AddRequests(ctx, "o_1234567890", time.Time{}, 1)
+		assert.Error(t, err)
+		_, err = s.RequestCount(ctx, "", time.Now())
+		assert.Error(t, err)
+		_, err = s.ActiveSessionCount(ctx, "")
+		assert.Error(t, err)
+	})
+}
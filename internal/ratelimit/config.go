@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// MemoryStore keeps token buckets in the memory of each controller, so
+	// each controller limits requests on its own.
+	MemoryStore = "memory"
+
+	// DatabaseStore keeps token buckets in the database, so the limits are
+	// shared by all of the controllers in a cluster.
+	DatabaseStore = "database"
+)
+
+// Config is the configuration of API rate limiting, as given in the
+// "api_rate_limit" block of a controller's configuration.
+type Config struct {
+	// PerTokenRate is the number of requests per second allowed for each
+	// auth token. Zero disables the per-token limit.
+	PerTokenRate float64 `hcl:"per_token_rate"`
+
+	// PerTokenBurst is the number of requests an auth token can make at once.
+	// If zero, the rate rounded up is used.
+	PerTokenBurst int `hcl:"per_token_burst"`
+
+	// PerIpRate is the number of requests per second allowed from each client
+	// IP address. Zero disables the per-IP limit.
+	PerIpRate float64 `hcl:"per_ip_rate"`
+
+	// PerIpBurst is the number of requests a client IP address can make at
+	// once. If zero, the rate rounded up is used.
+	PerIpBurst int `hcl:"per_ip_burst"`
+
+	// Store is where token buckets are kept, either "memory" or "database".
+	// If empty, "memory" is used.
+	Store string `hcl:"store"`
+}
+
+// Validate returns an error if c is invalid. A nil Config is valid.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.PerTokenRate < 0 || c.PerIpRate < 0 {
+		return errors.New("api_rate_limit: rates must not be negative")
+	}
+	if c.PerTokenBurst < 0 || c.PerIpBurst < 0 {
+		return errors.New("api_rate_limit: bursts must not be negative")
+	}
+	switch c.Store {
+	case "", MemoryStore, DatabaseStore:
+	default:
+		return fmt.Errorf("api_rate_limit: unknown store %q", c.Store)
+	}
+	return nil
+}
+
+// Enabled returns true if c limits any requests.
+func (c *Config) Enabled() bool {
+	return c != nil && (c.PerTokenRate > 0 || c.PerIpRate > 0)
+}
@@ -0,0 +1,111 @@
+// Package ratelimit limits the rate of API requests made with each auth token
+// and from each client IP address using token buckets. Buckets are kept in
+// memory or, so that the controllers of a cluster share their limits, in the
+// database.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is the limit of a token bucket. Rate tokens are added to the bucket
+// each second, up to Burst tokens, and each request takes one.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func newLimit(rate float64, burst int) Limit {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return Limit{Rate: rate, Burst: burst}
+}
+
+// retryAfter returns how long until a bucket holding tokens has a whole token.
+func (l Limit) retryAfter(tokens float64) time.Duration {
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / l.Rate * float64(time.Second))
+}
+
+// Store holds token buckets.
+type Store interface {
+	// Take takes a token from the bucket with the given key, which is created
+	// full if it doesn't exist. It returns whether a token was taken and, if
+	// not, how long until one can be.
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
+
+// Limiter limits requests per auth token and per client IP address.
+type Limiter struct {
+	store Store
+
+	l        sync.RWMutex
+	perToken Limit
+	perIp    Limit
+}
+
+// NewLimiter creates a Limiter with the limits in c which keeps its buckets
+// in store.
+func NewLimiter(store Store, c *Config) (*Limiter, error) {
+	if store == nil {
+		return nil, errors.New("new limiter: missing store")
+	}
+	if !c.Enabled() {
+		return nil, errors.New("new limiter: no limits configured")
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &Limiter{
+		store:    store,
+		perToken: newLimit(c.PerTokenRate, c.PerTokenBurst),
+		perIp:    newLimit(c.PerIpRate, c.PerIpBurst),
+	}, nil
+}
+
+// Allow returns whether a request made with the auth token with the given id
+// from the given client IP address is allowed and, if it isn't, how long the
+// client should wait before retrying. Either of tokenId or ip may be empty, in
+// which case the corresponding limit is not applied.
+func (l *Limiter) Allow(ctx context.Context, tokenId, ip string) (bool, time.Duration, error) {
+	l.l.RLock()
+	perToken, perIp := l.perToken, l.perIp
+	l.l.RUnlock()
+
+	if ip != "" && perIp.Rate > 0 {
+		ok, retryAfter, err := l.store.Take(ctx, "ip:"+ip, perIp)
+		if err != nil || !ok {
+			return ok, retryAfter, err
+		}
+	}
+	if tokenId != "" && perToken.Rate > 0 {
+		ok, retryAfter, err := l.store.Take(ctx, "token:"+tokenId, perToken)
+		if err != nil || !ok {
+			return ok, retryAfter, err
+		}
+	}
+	return true, 0, nil
+}
+
+// SetLimits replaces the limits with those in c. Existing buckets are kept
+// and refill at the new rates.
+func (l *Limiter) SetLimits(c *Config) error {
+	if !c.Enabled() {
+		return errors.New("set limits: no limits configured")
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	l.l.Lock()
+	defer l.l.Unlock()
+	l.perToken = newLimit(c.PerTokenRate, c.PerTokenBurst)
+	l.perIp = newLimit(c.PerIpRate, c.PerIpBurst)
+	return nil
+}
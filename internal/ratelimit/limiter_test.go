@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *Config
+		wantErr bool
+	}{
+		{name: "nil", conf: nil},
+		{name: "empty", conf: &Config{}},
+		{name: "memory", conf: &Config{PerTokenRate: 1, Store: MemoryStore}},
+		{name: "database", conf: &Config{PerIpRate: 1, PerIpBurst: 5, Store: DatabaseStore}},
+		{name: "negative-rate", conf: &Config{PerTokenRate: -1}, wantErr: true},
+		{name: "negative-burst", conf: &Config{PerIpRate: 1, PerIpBurst: -1}, wantErr: true},
+		{name: "unknown-store", conf: &Config{PerIpRate: 1, Store: "redis"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.conf.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	_, err := NewLimiter(nil, &Config{PerTokenRate: 1})
+	assert.Error(t, err)
+	_, err = NewLimiter(NewMemoryStore(), &Config{})
+	assert.Error(t, err)
+	_, err = NewLimiter(NewMemoryStore(), &Config{PerTokenRate: 1, Store: "redis"})
+	assert.Error(t, err)
+
+	l, err := NewLimiter(NewMemoryStore(), &Config{PerTokenRate: 2.5, PerIpRate: 10, PerIpBurst: 20})
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 2.5, Burst: 3}, l.perToken)
+	assert.Equal(t, Limit{Rate: 10, Burst: 20}, l.perIp)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	now := time.Now()
+	s := newMemoryStore(func() time.Time { return now })
+	limit := Limit{Rate: 2, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		ok, _, err := s.Take(ctx, "k", limit)
+		require.NoError(err)
+		assert.True(ok)
+	}
+	ok, retryAfter, err := s.Take(ctx, "k", limit)
+	require.NoError(err)
+	assert.False(ok)
+	assert.Equal(500*time.Millisecond, retryAfter)
+
+	// Other keys have their own buckets
+	ok, _, err = s.Take(ctx, "other", limit)
+	require.NoError(err)
+	assert.True(ok)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _, err = s.Take(ctx, "k", limit)
+	require.NoError(err)
+	assert.True(ok)
+	ok, _, err = s.Take(ctx, "k", limit)
+	require.NoError(err)
+	assert.False(ok)
+
+	// Buckets which have refilled are swept
+	now = now.Add(memorySweepInterval)
+	ok, _, err = s.Take(ctx, "k", limit)
+	require.NoError(err)
+	assert.True(ok)
+	assert.Len(s.buckets, 1)
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	l, err := NewLimiter(NewMemoryStore(), &Config{PerTokenRate: 0.001, PerTokenBurst: 1, PerIpRate: 0.001, PerIpBurst: 2})
+	require.NoError(t, err)
+
+	t.Run("per-token", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ok, _, err := l.Allow(ctx, "at_1", "10.0.0.1")
+		require.NoError(err)
+		assert.True(ok)
+		ok, retryAfter, err := l.Allow(ctx, "at_1", "10.0.0.2")
+		require.NoError(err)
+		assert.False(ok)
+		assert.True(retryAfter > 0)
+	})
+	t.Run("per-ip", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ok, _, err := l.Allow(ctx, "at_2", "10.0.0.1")
+		require.NoError(err)
+		assert.True(ok)
+		ok, _, err = l.Allow(ctx, "at_3", "10.0.0.1")
+		require.NoError(err)
+		assert.False(ok)
+	})
+	t.Run("unauthenticated", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ok, _, err := l.Allow(ctx, "", "10.0.0.3")
+		require.NoError(err)
+		assert.True(ok)
+	})
+}
+
+func TestLimiter_SetLimits(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	l, err := NewLimiter(NewMemoryStore(), &Config{PerIpRate: 0.001, PerIpBurst: 1})
+	require.NoError(err)
+
+	ok, _, err := l.Allow(ctx, "at_1", "10.0.0.1")
+	require.NoError(err)
+	assert.True(ok)
+
+	require.Error(l.SetLimits(&Config{}))
+	require.Error(l.SetLimits(&Config{PerIpRate: -1}))
+
+	// Removing the per-IP limit allows the address again, and the new
+	// per-token limit applies
+	require.NoError(l.SetLimits(&Config{PerTokenRate: 0.001, PerTokenBurst: 1}))
+	ok, _, err = l.Allow(ctx, "at_1", "10.0.0.1")
+	require.NoError(err)
+	assert.True(ok)
+	ok, _, err = l.Allow(ctx, "at_1", "10.0.0.1")
+	require.NoError(err)
+	assert.False(ok)
+}
@@ -0,0 +1,31 @@
+package ratelimit
+
+const (
+	// takeTokenQuery refills the bucket with key $1 at rate $2 up to burst $3
+	// and takes a token from it if it has one. A bucket which doesn't exist is
+	// created full.
+	takeTokenQuery = `
+	insert into api_rate_limit_bucket
+		(key, tokens, allowed, update_time)
+	values
+		($1, $3::double precision - 1, true, now())
+	on conflict (key)
+	do update set
+		tokens = case
+			when least($3::double precision, api_rate_limit_bucket.tokens + extract(epoch from now() - api_rate_limit_bucket.update_time) * $2::double precision) >= 1
+			then least($3::double precision, api_rate_limit_bucket.tokens + extract(epoch from now() - api_rate_limit_bucket.update_time) * $2::double precision) - 1
+			else least($3::double precision, api_rate_limit_bucket.tokens + extract(epoch from now() - api_rate_limit_bucket.update_time) * $2::double precision)
+		end,
+		allowed = least($3::double precision, api_rate_limit_bucket.tokens + extract(epoch from now() - api_rate_limit_bucket.update_time) * $2::double precision) >= 1,
+		update_time = now()
+	returning tokens, allowed;
+	`
+
+	// deleteIdleBucketsQuery deletes the buckets which haven't been used for
+	// $1 milliseconds.
+	deleteIdleBucketsQuery = `
+	delete from api_rate_limit_bucket
+	where
+		update_time < now() - $1::bigint * interval '1 millisecond';
+	`
+)
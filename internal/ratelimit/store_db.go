@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// DbStore keeps token buckets in the database, so that they are shared by
+// all of the controllers using it.
+type DbStore struct {
+	reader db.Reader
+	writer db.Writer
+}
+
+var _ Store = (*DbStore)(nil)
+
+// NewDbStore creates a new DbStore.
+func NewDbStore(r db.Reader, w db.Writer) (*DbStore, error) {
+	if r == nil {
+		return nil, fmt.Errorf("new rate limit db store: missing reader: %w", db.ErrInvalidParameter)
+	}
+	if w == nil {
+		return nil, fmt.Errorf("new rate limit db store: missing writer: %w", db.ErrInvalidParameter)
+	}
+	return &DbStore{
+		reader: r,
+		writer: w,
+	}, nil
+}
+
+// Take implements Store.
+func (s *DbStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	if key == "" {
+		return false, 0, fmt.Errorf("take token: missing key: %w", db.ErrInvalidParameter)
+	}
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return false, 0, fmt.Errorf("take token: rate and burst must be positive: %w", db.ErrInvalidParameter)
+	}
+
+	var tokens float64
+	var allowed bool
+	_, err := s.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, _ db.Writer) error {
+			rows, err := reader.Query(ctx, takeTokenQuery, []interface{}{key, limit.Rate, limit.Burst})
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				if err := rows.Scan(&tokens, &allowed); err != nil {
+					return err
+				}
+			}
+			return rows.Err()
+		},
+	)
+	if err != nil {
+		return false, 0, fmt.Errorf("take token: %s: %w", key, err)
+	}
+	return allowed, limit.retryAfter(tokens), nil
+}
+
+// DeleteIdle deletes the buckets which haven't been used for the given
+// duration and returns the number deleted.
+func (s *DbStore) DeleteIdle(ctx context.Context, idle time.Duration) (int, error) {
+	if idle <= 0 {
+		return db.NoRowsAffected, fmt.Errorf("delete idle buckets: idle must be positive: %w", db.ErrInvalidParameter)
+	}
+	deleted, err := s.writer.Exec(ctx, deleteIdleBucketsQuery, []interface{}{idle.Milliseconds()})
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("delete idle buckets: %w", err)
+	}
+	return deleted, nil
+}
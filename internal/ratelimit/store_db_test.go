@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDbStore(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	s, err := NewDbStore(rw, rw)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("take", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		limit := Limit{Rate: 0.001, Burst: 2}
+		for i := 0; i < 2; i++ {
+			ok, _, err := s.Take(ctx, "take", limit)
+			require.NoError(err)
+			assert.True(ok)
+		}
+		ok, retryAfter, err := s.Take(ctx, "take", limit)
+		require.NoError(err)
+		assert.False(ok)
+		assert.True(retryAfter > 0)
+	})
+	t.Run("refill", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		limit := Limit{Rate: 100, Burst: 1}
+		ok, _, err := s.Take(ctx, "refill", limit)
+		require.NoError(err)
+		assert.True(ok)
+		time.Sleep(50 * time.Millisecond)
+		ok, _, err = s.Take(ctx, "refill", limit)
+		require.NoError(err)
+		assert.True(ok)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := s.Take(ctx, "", Limit{Rate: 1, Burst: 1})
+		assert.Error(t, err)
+		_, _, err = s.Take(ctx, "invalid", Limit{})
+		assert.Error(t, err)
+	})
+	t.Run("delete-idle", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, _, err := s.Take(ctx, "idle", Limit{Rate: 1, Burst: 1})
+		require.NoError(err)
+		time.Sleep(10 * time.Millisecond)
+		deleted, err := s.DeleteIdle(ctx, time.Millisecond)
+		require.NoError(err)
+		assert.True(deleted >= 1)
+		deleted, err = s.DeleteIdle(ctx, time.Hour)
+		require.NoError(err)
+		assert.Equal(0, deleted)
+	})
+}
@@ -0,0 +1,114 @@
+// Package registry resolves public ids to the resources they identify
+// without knowing the packages the resources belong to.
+//
+// The type of a resource is told from the prefix of its public id, as
+// registered with the publicid package, and the resource is then looked up
+// with the LookupFn registered for the type. Generic facilities such as
+// auditing, search, grants evaluation and oplog decoding can use a Registry
+// instead of importing each repository and switching on the resource type.
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/types/publicid"
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+var (
+	// ErrUnknownType is returned when the type of a public id is not known
+	// or no LookupFn is registered for it.
+	ErrUnknownType = errors.New("unknown resource type")
+
+	// ErrAlreadyRegistered is returned when registering a LookupFn for a
+	// type which already has one.
+	ErrAlreadyRegistered = errors.New("resource type already registered")
+)
+
+// Resource is what generic facilities need to know about a resource.
+type Resource struct {
+	PublicId    string
+	Type        resource.Type
+	ScopeId     string
+	Name        string
+	Description string
+}
+
+// LookupFn looks up the resource with the public id. It returns nil and no
+// error if the resource does not exist.
+type LookupFn func(ctx context.Context, publicId string) (*Resource, error)
+
+// Registry maps resource types to the functions looking up resources of
+// the type. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	lookups map[resource.Type]LookupFn
+}
+
+// New returns an empty Registry.
+func New() *Registry {
+	return &Registry{
+		lookups: make(map[resource.Type]LookupFn),
+	}
+}
+
+// Register registers fn to look up resources of type t. It returns an error
+// wrapping ErrAlreadyRegistered if t already has a LookupFn.
+func (r *Registry) Register(t resource.Type, fn LookupFn) error {
+	if t == resource.Unknown || t == resource.All {
+		return fmt.Errorf("register: invalid resource type %s", t)
+	}
+	if fn == nil {
+		return fmt.Errorf("register: missing lookup function for %s", t)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.lookups[t]; ok {
+		return fmt.Errorf("register: %s: %w", t, ErrAlreadyRegistered)
+	}
+	r.lookups[t] = fn
+	return nil
+}
+
+// Types returns the resource types which have been registered.
+func (r *Registry) Types() []resource.Type {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]resource.Type, 0, len(r.lookups))
+	for t := range r.lookups {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// Type returns the resource type of the public id, or resource.Unknown if
+// its prefix isn't registered.
+func (r *Registry) Type(publicId string) resource.Type {
+	return publicid.Type(publicId)
+}
+
+// Lookup returns the resource with the public id, or nil if it does not
+// exist. It returns an error wrapping ErrUnknownType if the type of the id
+// can't be told or has no LookupFn registered.
+func (r *Registry) Lookup(ctx context.Context, publicId string) (*Resource, error) {
+	t := publicid.Type(publicId)
+	r.mu.RLock()
+	fn, ok := r.lookups[t]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("lookup %s: %w", publicId, ErrUnknownType)
+	}
+	res, err := fn(ctx, publicId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup %s: %w", publicId, err)
+	}
+	if res != nil && res.Type == resource.Unknown {
+		res.Type = t
+	}
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := New()
+
+	targets := map[string]*Resource{
+		"ttcp_1234567890": {PublicId: "ttcp_1234567890", ScopeId: "p_1234567890", Name: "web"},
+	}
+	lookupTarget := func(_ context.Context, id string) (*Resource, error) {
+		return targets[id], nil
+	}
+	require.NoError(t, r.Register(resource.Target, lookupTarget))
+	require.NoError(t, r.Register(resource.Host, func(context.Context, string) (*Resource, error) {
+		return nil, errors.New("host lookup failed")
+	}))
+
+	t.Run("register", func(t *testing.T) {
+		assert := assert.New(t)
+		err := r.Register(resource.Target, lookupTarget)
+		assert.True(errors.Is(err, ErrAlreadyRegistered))
+		assert.Error(r.Register(resource.Unknown, lookupTarget))
+		assert.Error(r.Register(resource.Session, nil))
+		assert.Equal([]resource.Type{resource.Host, resource.Target}, r.Types())
+	})
+	t.Run("type", func(t *testing.T) {
+		assert := assert.New(t)
+		assert.Equal(resource.Target, r.Type("ttcp_1234567890"))
+		assert.Equal(resource.Session, r.Type("s_1234567890"))
+		assert.Equal(resource.Unknown, r.Type("nope_1234567890"))
+	})
+	t.Run("lookup", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := r.Lookup(ctx, "ttcp_1234567890")
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(resource.Target, got.Type)
+		assert.Equal("p_1234567890", got.ScopeId)
+		assert.Equal("web", got.Name)
+	})
+	t.Run("not-found", func(t *testing.T) {
+		assert := assert.New(t)
+		got, err := r.Lookup(ctx, "ttcp_doesnotexis")
+		assert.NoError(err)
+		assert.Nil(got)
+	})
+	t.Run("unregistered-type", func(t *testing.T) {
+		assert := assert.New(t)
+		_, err := r.Lookup(ctx, "s_1234567890")
+		assert.True(errors.Is(err, ErrUnknownType))
+		_, err = r.Lookup(ctx, "nope_1234567890")
+		assert.True(errors.Is(err, ErrUnknownType))
+	})
+	t.Run("lookup-error", func(t *testing.T) {
+		_, err := r.Lookup(ctx, "hst_1234567890")
+		assert.Error(t, err)
+	})
+}
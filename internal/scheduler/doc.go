@@ -0,0 +1,24 @@
+// Package scheduler runs background jobs on the controllers of a cluster.
+//
+// Jobs are registered with a Scheduler along with the interval at which they
+// should run. Each registered job is stored in the job table with the time of
+// its next scheduled run and the outcome of its last run. Every controller
+// runs a Scheduler and registers the same jobs; when a job is due, the first
+// controller to claim it in the database runs it, so a job is run by only one
+// controller at a time.
+//
+// # Claims
+//
+// A claim on a job is a database advisory lock held by the controller running
+// the job until it records the run's outcome. The database releases the lock
+// if the controller's connection is lost, so a job whose controller stops can
+// be claimed by another controller straight away. The scheduler checks the
+// claim's connection while the job is running and cancels the job's context
+// if the claim may have been lost.
+//
+// # Retries
+//
+// When a job fails it is retried with an exponential backoff, starting at one
+// second and never waiting longer than the job's interval. A successful run
+// resets the backoff.
+package scheduler
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// Job is background work run periodically by a Scheduler.
+type Job interface {
+	// Name uniquely identifies the job. Every controller registering a job
+	// with the same name shares its schedule.
+	Name() string
+
+	// Description is a human readable description of the job shown to
+	// operators.
+	Description() string
+
+	// Run performs the job. ctx is canceled if the scheduler is shut down or
+	// the scheduler loses its claim on the job, and Run should return promptly
+	// when it is.
+	Run(ctx context.Context) error
+}
+
+// RunStatus is the outcome of a job run.
+type RunStatus string
+
+const (
+	// RunCompleted is the status of a run which returned no error.
+	RunCompleted RunStatus = "completed"
+
+	// RunFailed is the status of a run which returned an error.
+	RunFailed RunStatus = "failed"
+
+	// RunInterrupted is the status of a run whose context was canceled
+	// before it finished.
+	RunInterrupted RunStatus = "interrupted"
+)
+
+// JobInfo is a job as stored in the job table.
+type JobInfo struct {
+	Name             string     `json:"name" gorm:"primary_key"`
+	Description      string     `json:"description"`
+	NextScheduledRun time.Time  `json:"next_scheduled_run"`
+	RunningServerId  *string    `json:"running_server_id,omitempty"`
+	LastRunStartTime *time.Time `json:"last_run_start_time,omitempty"`
+	LastRunEndTime   *time.Time `json:"last_run_end_time,omitempty"`
+	LastRunStatus    *string    `json:"last_run_status,omitempty"`
+	LastRunError     *string    `json:"last_run_error,omitempty"`
+	FailureCount     int        `json:"failure_count"`
+	CreateTime       time.Time  `json:"create_time"`
+}
+
+// TableName overrides the table name used by gorm.
+func (JobInfo) TableName() string {
+	return "job"
+}
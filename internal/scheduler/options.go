@@ -0,0 +1,49 @@
+package scheduler
+
+import "time"
+
+// getOpts - iterate the inbound Options and return a struct
+func getOpts(opt ...Option) options {
+	opts := getDefaultOptions()
+	for _, o := range opt {
+		o(&opts)
+	}
+	return opts
+}
+
+// Option - how Options are passed as arguments
+type Option func(*options)
+
+// options = how options are represented
+type options struct {
+	withRunJobsInterval    time.Duration
+	withClaimCheckInterval time.Duration
+}
+
+func getDefaultOptions() options {
+	return options{
+		withRunJobsInterval:    defaultRunJobsInterval,
+		withClaimCheckInterval: defaultClaimCheckInterval,
+	}
+}
+
+// WithRunJobsInterval provides an option to set how often the scheduler
+// checks for jobs which are due to run. If zero, the default is used.
+func WithRunJobsInterval(interval time.Duration) Option {
+	return func(o *options) {
+		if interval > 0 {
+			o.withRunJobsInterval = interval
+		}
+	}
+}
+
+// WithClaimCheckInterval provides an option to set how often the scheduler
+// checks it still holds its claims on running jobs. A job whose claim is lost
+// has its context canceled. If zero, the default is used.
+func WithClaimCheckInterval(interval time.Duration) Option {
+	return func(o *options) {
+		if interval > 0 {
+			o.withClaimCheckInterval = interval
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_GetOpts provides unit tests for GetOpts and all the options
+func Test_GetOpts(t *testing.T) {
+	t.Parallel()
+	t.Run("WithRunJobsInterval", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts()
+		testOpts := getDefaultOptions()
+		testOpts.withRunJobsInterval = defaultRunJobsInterval
+		assert.Equal(opts, testOpts)
+
+		opts = getOpts(WithRunJobsInterval(time.Second))
+		testOpts.withRunJobsInterval = time.Second
+		assert.Equal(opts, testOpts)
+
+		opts = getOpts(WithRunJobsInterval(0))
+		testOpts.withRunJobsInterval = defaultRunJobsInterval
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithClaimCheckInterval", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts()
+		testOpts := getDefaultOptions()
+		testOpts.withClaimCheckInterval = defaultClaimCheckInterval
+		assert.Equal(opts, testOpts)
+
+		opts = getOpts(WithClaimCheckInterval(time.Second))
+		testOpts.withClaimCheckInterval = time.Second
+		assert.Equal(opts, testOpts)
+
+		opts = getOpts(WithClaimCheckInterval(-1))
+		testOpts.withClaimCheckInterval = defaultClaimCheckInterval
+		assert.Equal(opts, testOpts)
+	})
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+const (
+	// upsertJobQuery registers a job, scheduling new jobs to run immediately.
+	upsertJobQuery = `
+	insert into job
+		(name, description)
+	values
+		($1, $2)
+	on conflict (name)
+	do update set
+		description = $2;
+	`
+
+	// claimJobQuery records the server running a job if the job is due. It
+	// must only be run while holding the job's lock. No row is returned if
+	// the job isn't due.
+	claimJobQuery = `
+	update job
+	set
+		running_server_id = $2
+	where
+		name = $1 and
+		next_scheduled_run <= now()
+	returning failure_count;
+	`
+
+	// completeRunQuery releases a server's claim on a job, records the
+	// outcome of the run and schedules the next run.
+	completeRunQuery = `
+	update job
+	set
+		running_server_id = null,
+		last_run_start_time = $3,
+		last_run_end_time = now(),
+		last_run_status = $4,
+		last_run_error = nullif($5, ''),
+		failure_count = case
+			when $4 = 'completed' then 0
+			else failure_count + 1
+		end,
+		next_scheduled_run = now() + $6 * interval '1 millisecond'
+	where
+		name = $1 and
+		running_server_id = $2;
+	`
+)
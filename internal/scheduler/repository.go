@@ -0,0 +1,203 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// ErrClaimLost is returned when a server checks or completes a claim on a job
+// it may no longer hold.
+var ErrClaimLost = errors.New("job claim lost")
+
+// Repository is the scheduler database repository.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+}
+
+// NewRepository creates a new scheduler Repository.
+func NewRepository(r db.Reader, w db.Writer) (*Repository, error) {
+	if r == nil {
+		return nil, fmt.Errorf("new scheduler repository: missing reader: %w", db.ErrInvalidParameter)
+	}
+	if w == nil {
+		return nil, fmt.Errorf("new scheduler repository: missing writer: %w", db.ErrInvalidParameter)
+	}
+	return &Repository{
+		reader: r,
+		writer: w,
+	}, nil
+}
+
+// UpsertJob stores a job so it can be claimed. A new job is scheduled to run
+// immediately; the schedule of an existing job is not changed.
+func (r *Repository) UpsertJob(ctx context.Context, name, description string) error {
+	if name == "" {
+		return fmt.Errorf("upsert job: missing name: %w", db.ErrInvalidParameter)
+	}
+	if _, err := r.writer.Exec(ctx, upsertJobQuery, []interface{}{name, description}); err != nil {
+		return fmt.Errorf("upsert job: %s: %w", name, err)
+	}
+	return nil
+}
+
+// A Claim is a server's claim on a job, held from when the job is claimed
+// until its run is completed. The claim is an advisory lock in the database,
+// which is released if the server's connection holding it is lost.
+type Claim struct {
+	// Name is the name of the claimed job.
+	Name string
+
+	// ServerId is the id of the server holding the claim.
+	ServerId string
+
+	// FailureCount is the number of consecutive failed runs of the job when
+	// it was claimed.
+	FailureCount int
+
+	lock *db.Lock
+}
+
+// Check returns an error wrapping ErrClaimLost if the claim's lock may have
+// been released, in which case another server may have claimed the job.
+func (c *Claim) Check(ctx context.Context) error {
+	if err := c.lock.Check(ctx); err != nil {
+		return fmt.Errorf("check claim: %s: %v: %w", c.Name, err, ErrClaimLost)
+	}
+	return nil
+}
+
+// jobLockName returns the name of the advisory lock held while the named job
+// is claimed.
+func jobLockName(name string) string {
+	return "scheduler_job:" + name
+}
+
+// locker is implemented by writers which can take advisory locks, such as
+// *db.Db.
+type locker interface {
+	TryLock(ctx context.Context, name string) (*db.Lock, error)
+}
+
+// ClaimJob claims the named job for serverId if the job is due to run and is
+// not claimed by another server. It returns nil if the job was not claimed.
+// The claim must be released by CompleteRun.
+func (r *Repository) ClaimJob(ctx context.Context, name, serverId string) (*Claim, error) {
+	if name == "" {
+		return nil, fmt.Errorf("claim job: missing name: %w", db.ErrInvalidParameter)
+	}
+	if serverId == "" {
+		return nil, fmt.Errorf("claim job: missing server id: %w", db.ErrInvalidParameter)
+	}
+	l, ok := r.writer.(locker)
+	if !ok {
+		return nil, fmt.Errorf("claim job: %s: writer does not support locking: %w", name, db.ErrInvalidParameter)
+	}
+	lock, err := l.TryLock(ctx, jobLockName(name))
+	switch {
+	case errors.Is(err, db.ErrLockHeld):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("claim job: %s: %w", name, err)
+	}
+
+	claimed, failureCount, err := r.markRunning(ctx, name, serverId)
+	if err != nil || !claimed {
+		if unlockErr := lock.Unlock(ctx); unlockErr != nil && err == nil {
+			err = unlockErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("claim job: %s: %w", name, err)
+		}
+		return nil, nil
+	}
+	return &Claim{
+		Name:         name,
+		ServerId:     serverId,
+		FailureCount: failureCount,
+		lock:         lock,
+	}, nil
+}
+
+// markRunning records serverId as running the named job if the job is due. It
+// returns whether the job was due and, if it was, the number of consecutive
+// failed runs of the job.
+func (r *Repository) markRunning(ctx context.Context, name, serverId string) (bool, int, error) {
+	rows, err := r.reader.Query(ctx, claimJobQuery, []interface{}{name, serverId})
+	if err != nil {
+		return false, 0, err
+	}
+	defer rows.Close()
+	var claimed bool
+	var failureCount int
+	for rows.Next() {
+		if err := rows.Scan(&failureCount); err != nil {
+			return false, 0, err
+		}
+		claimed = true
+	}
+	return claimed, failureCount, rows.Err()
+}
+
+// CompleteRun records the outcome of a run of the claimed job which started
+// at startTime, schedules its next run after nextRunIn and releases the claim.
+// runErr is recorded as the error of the last run if it is not nil. If the
+// claim has been lost, an error wrapping ErrClaimLost is returned and nothing
+// is recorded. The claim is released whether or not an error is returned.
+func (r *Repository) CompleteRun(ctx context.Context, claim *Claim, startTime time.Time, status RunStatus, runErr error, nextRunIn time.Duration) (retErr error) {
+	if claim == nil {
+		return fmt.Errorf("complete run: missing claim: %w", db.ErrInvalidParameter)
+	}
+	defer func() {
+		if err := claim.lock.Unlock(ctx); err != nil && retErr == nil {
+			retErr = fmt.Errorf("complete run: %s: %w", claim.Name, err)
+		}
+	}()
+	switch status {
+	case RunCompleted, RunFailed, RunInterrupted:
+	default:
+		return fmt.Errorf("complete run: unknown run status %q: %w", status, db.ErrInvalidParameter)
+	}
+	if err := claim.Check(ctx); err != nil {
+		return fmt.Errorf("complete run: %w", err)
+	}
+	var errMsg string
+	if runErr != nil {
+		errMsg = runErr.Error()
+	}
+	rowsAffected, err := r.writer.Exec(ctx, completeRunQuery,
+		[]interface{}{claim.Name, claim.ServerId, startTime, string(status), errMsg, nextRunIn.Milliseconds()})
+	if err != nil {
+		return fmt.Errorf("complete run: %s: %w", claim.Name, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("complete run: %s: %w", claim.Name, ErrClaimLost)
+	}
+	return nil
+}
+
+// ListJobs returns all jobs ordered by name.
+func (r *Repository) ListJobs(ctx context.Context) ([]*JobInfo, error) {
+	var jobs []*JobInfo
+	if err := r.reader.SearchWhere(ctx, &jobs, "", nil, db.WithLimit(-1), db.WithOrder("name")); err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// LookupJob returns the named job. If the job is not found, an error wrapping
+// db.ErrRecordNotFound is returned.
+func (r *Repository) LookupJob(ctx context.Context, name string) (*JobInfo, error) {
+	if name == "" {
+		return nil, fmt.Errorf("lookup job: missing name: %w", db.ErrInvalidParameter)
+	}
+	job := &JobInfo{}
+	if err := r.reader.LookupWhere(ctx, job, "name = ?", name); err != nil {
+		return nil, fmt.Errorf("lookup job: %s: %w", name, err)
+	}
+	return job, nil
+}
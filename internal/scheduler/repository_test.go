@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Claims(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("claim-complete", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.NoError(repo.UpsertJob(ctx, "claim-complete", "description"))
+
+		claim, err := repo.ClaimJob(ctx, "claim-complete", "c1")
+		require.NoError(err)
+		require.NotNil(claim)
+		assert.Equal(0, claim.FailureCount)
+		require.NoError(claim.Check(ctx))
+		job, err := repo.LookupJob(ctx, "claim-complete")
+		require.NoError(err)
+		require.NotNil(job.RunningServerId)
+		assert.Equal("c1", *job.RunningServerId)
+
+		// Another server can't claim a claimed job
+		other, err := repo.ClaimJob(ctx, "claim-complete", "c2")
+		require.NoError(err)
+		assert.Nil(other)
+
+		start := time.Now()
+		require.NoError(repo.CompleteRun(ctx, claim, start, RunFailed, errors.New("oops"), time.Hour))
+		job, err = repo.LookupJob(ctx, "claim-complete")
+		require.NoError(err)
+		assert.Nil(job.RunningServerId)
+		assert.Equal(1, job.FailureCount)
+		require.NotNil(job.LastRunError)
+		assert.Equal("oops", *job.LastRunError)
+
+		// Completing the run released the claim
+		assert.True(errors.Is(claim.Check(ctx), ErrClaimLost))
+
+		// Not due for an hour
+		other, err = repo.ClaimJob(ctx, "claim-complete", "c2")
+		require.NoError(err)
+		assert.Nil(other)
+
+		// Completing a run whose claim was released fails
+		err = repo.CompleteRun(ctx, claim, start, RunCompleted, nil, time.Hour)
+		assert.True(errors.Is(err, ErrClaimLost))
+	})
+	t.Run("lost-claim", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.NoError(repo.UpsertJob(ctx, "lost-claim", "description"))
+		claim, err := repo.ClaimJob(ctx, "lost-claim", "c1")
+		require.NoError(err)
+		require.NotNil(claim)
+
+		// Releasing the claim's lock without completing the run, as happens
+		// when the server's connection is lost, lets another server claim
+		// the job, and the first server can no longer complete it.
+		require.NoError(claim.lock.Unlock(ctx))
+		other, err := repo.ClaimJob(ctx, "lost-claim", "c2")
+		require.NoError(err)
+		require.NotNil(other)
+		job, err := repo.LookupJob(ctx, "lost-claim")
+		require.NoError(err)
+		require.NotNil(job.RunningServerId)
+		assert.Equal("c2", *job.RunningServerId)
+
+		assert.True(errors.Is(claim.Check(ctx), ErrClaimLost))
+		require.NoError(repo.CompleteRun(ctx, other, time.Now(), RunCompleted, nil, time.Hour))
+	})
+	t.Run("list", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		jobs, err := repo.ListJobs(ctx)
+		require.NoError(err)
+		var names []string
+		for _, j := range jobs {
+			names = append(names, j.Name)
+		}
+		assert.Equal([]string{"claim-complete", "lost-claim"}, names)
+	})
+}
@@ -0,0 +1,243 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	defaultRunJobsInterval    = 10 * time.Second
+	defaultClaimCheckInterval = 20 * time.Second
+
+	// minRetryDelay is how long the scheduler waits before retrying a job
+	// after its first failure. The delay doubles with each consecutive
+	// failure up to the job's interval.
+	minRetryDelay = time.Second
+)
+
+// RepoFactory returns a scheduler repository.
+type RepoFactory func() (*Repository, error)
+
+// Scheduler runs registered jobs at their intervals, coordinating with the
+// schedulers of other controllers through the database so each job is run by
+// a single controller at a time.
+type Scheduler struct {
+	serverId string
+	repoFn   RepoFactory
+	logger   hclog.Logger
+
+	runJobsInterval    time.Duration
+	claimCheckInterval time.Duration
+
+	jobsMu sync.Mutex
+	jobs   map[string]*registeredJob
+
+	// running contains the names of the jobs being run by this scheduler.
+	running sync.Map
+}
+
+type registeredJob struct {
+	Job
+	interval time.Duration
+}
+
+// New creates a Scheduler for the server serverId. Supports the options
+// WithRunJobsInterval and WithClaimCheckInterval.
+func New(serverId string, repoFn RepoFactory, logger hclog.Logger, opt ...Option) (*Scheduler, error) {
+	if serverId == "" {
+		return nil, errors.New("new scheduler: missing server id")
+	}
+	if repoFn == nil {
+		return nil, errors.New("new scheduler: missing repo factory")
+	}
+	if logger == nil {
+		return nil, errors.New("new scheduler: missing logger")
+	}
+	opts := getOpts(opt...)
+	return &Scheduler{
+		serverId:           serverId,
+		repoFn:             repoFn,
+		logger:             logger,
+		runJobsInterval:    opts.withRunJobsInterval,
+		claimCheckInterval: opts.withClaimCheckInterval,
+		jobs:               make(map[string]*registeredJob),
+	}, nil
+}
+
+// RegisterJob registers job to be run every interval and stores it in the
+// database. A job which has not been run before is run as soon as the
+// scheduler next checks for jobs. Registering a job with the name of an
+// already registered job replaces it.
+func (s *Scheduler) RegisterJob(ctx context.Context, job Job, interval time.Duration) error {
+	if job == nil {
+		return errors.New("register job: missing job")
+	}
+	if job.Name() == "" {
+		return errors.New("register job: missing job name")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("register job: %s: interval must be positive", job.Name())
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return fmt.Errorf("register job: %s: %w", job.Name(), err)
+	}
+	if err := repo.UpsertJob(ctx, job.Name(), job.Description()); err != nil {
+		return fmt.Errorf("register job: %w", err)
+	}
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	s.jobs[job.Name()] = &registeredJob{Job: job, interval: interval}
+	return nil
+}
+
+// Start checks for due jobs until ctx is done. Jobs are run with a context
+// derived from ctx.
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		timer := time.NewTimer(0)
+		for {
+			select {
+			case <-ctx.Done():
+				s.logger.Info("scheduler shutting down")
+				return
+
+			case <-timer.C:
+				s.runJobs(ctx)
+				timer.Reset(s.runJobsInterval)
+			}
+		}
+	}()
+}
+
+// runJobs claims and starts the registered jobs which are due.
+func (s *Scheduler) runJobs(ctx context.Context) {
+	s.jobsMu.Lock()
+	jobs := make([]*registeredJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.jobsMu.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name() < jobs[j].Name() })
+
+	repo, err := s.repoFn()
+	if err != nil {
+		s.logger.Error("error fetching repository for running jobs", "error", err)
+		return
+	}
+	for _, j := range jobs {
+		if _, running := s.running.Load(j.Name()); running {
+			continue
+		}
+		claim, err := repo.ClaimJob(ctx, j.Name(), s.serverId)
+		if err != nil {
+			s.logger.Error("error claiming job", "job", j.Name(), "error", err)
+			continue
+		}
+		if claim == nil {
+			continue
+		}
+		s.running.Store(j.Name(), true)
+		go s.runJob(ctx, j, claim)
+	}
+}
+
+// runJob runs a claimed job, checking the claim is still held until the job
+// returns, and then records its outcome and releases the claim.
+func (s *Scheduler) runJob(ctx context.Context, j *registeredJob, claim *Claim) {
+	defer s.running.Delete(j.Name())
+	logger := s.logger.With("job", j.Name())
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// The claim is checked until the job returns, and no longer, as it's
+	// released once the outcome is recorded.
+	done := make(chan struct{})
+	checkerDone := make(chan struct{})
+	go func() {
+		defer close(checkerDone)
+		ticker := time.NewTicker(s.claimCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+				if err := claim.Check(ctx); err != nil {
+					logger.Error("job claim lost; canceling job", "error", err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	logger.Debug("running job")
+	start := time.Now()
+	runErr := j.Run(jobCtx)
+	close(done)
+	<-checkerDone
+
+	status := RunCompleted
+	switch {
+	case jobCtx.Err() != nil:
+		status = RunInterrupted
+		if runErr == nil {
+			runErr = jobCtx.Err()
+		}
+	case runErr != nil:
+		status = RunFailed
+	}
+	event.WriteObservation(ctx, "scheduler.(Scheduler).runJob", map[string]interface{}{
+		"job":         j.Name(),
+		"server_id":   s.serverId,
+		"status":      string(status),
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+	nextRunIn := j.interval
+	if status != RunCompleted {
+		nextRunIn = retryDelay(claim.FailureCount+1, j.interval)
+		logger.Error("job run did not complete", "status", status, "error", runErr, "retry_in", nextRunIn)
+	} else {
+		logger.Debug("job run completed", "duration", time.Since(start))
+	}
+
+	// Record the outcome even if the scheduler is shutting down, so the job
+	// isn't run again before its next scheduled run.
+	recordCtx, recordCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer recordCancel()
+	repo, err := s.repoFn()
+	if err == nil {
+		err = repo.CompleteRun(recordCtx, claim, start, status, runErr, nextRunIn)
+	} else {
+		// Release the claim even though the outcome can't be recorded, so
+		// the job can be claimed again.
+		_ = claim.lock.Unlock(recordCtx)
+	}
+	if err != nil {
+		logger.Error("error recording job run", "error", err)
+	}
+}
+
+// retryDelay returns how long to wait before running a job again after
+// failures consecutive failed runs. The delay starts at minRetryDelay and
+// doubles with each failure, but is never longer than interval.
+func retryDelay(failures int, interval time.Duration) time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < failures && delay < interval; i++ {
+		delay *= 2
+	}
+	if delay > interval {
+		delay = interval
+	}
+	return delay
+}
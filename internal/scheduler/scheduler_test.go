@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testJob struct {
+	name string
+	runs int32
+	err  error
+}
+
+func (j *testJob) Name() string        { return j.name }
+func (j *testJob) Description() string { return "test job " + j.name }
+func (j *testJob) Run(context.Context) error {
+	atomic.AddInt32(&j.runs, 1)
+	return j.err
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		interval time.Duration
+		want     time.Duration
+	}{
+		{failures: 1, interval: time.Hour, want: time.Second},
+		{failures: 2, interval: time.Hour, want: 2 * time.Second},
+		{failures: 5, interval: time.Hour, want: 16 * time.Second},
+		{failures: 30, interval: time.Hour, want: time.Hour},
+		{failures: 3, interval: 3 * time.Second, want: 3 * time.Second},
+		{failures: 1, interval: 500 * time.Millisecond, want: 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, retryDelay(tt.failures, tt.interval), "failures %d interval %s", tt.failures, tt.interval)
+	}
+}
+
+func TestNew(t *testing.T) {
+	repoFn := func() (*Repository, error) { return nil, errors.New("unused") }
+	logger := hclog.NewNullLogger()
+
+	_, err := New("", repoFn, logger)
+	assert.Error(t, err)
+	_, err = New("c1", nil, logger)
+	assert.Error(t, err)
+	_, err = New("c1", repoFn, nil)
+	assert.Error(t, err)
+
+	s, err := New("c1", repoFn, logger, WithRunJobsInterval(time.Second), WithClaimCheckInterval(3*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, s.runJobsInterval)
+	assert.Equal(t, 3*time.Second, s.claimCheckInterval)
+
+	assert.Error(t, s.RegisterJob(context.Background(), nil, time.Minute))
+	assert.Error(t, s.RegisterJob(context.Background(), &testJob{name: "job"}, 0))
+}
+
+func TestScheduler_RunJobs(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	repoFn := func() (*Repository, error) { return NewRepository(rw, rw) }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Two schedulers sharing a job only run it once per interval
+	ok := &testJob{name: "ok"}
+	failing := &testJob{name: "failing", err: errors.New("failed")}
+	for _, id := range []string{"c1", "c2"} {
+		s, err := New(id, repoFn, hclog.NewNullLogger(), WithRunJobsInterval(100*time.Millisecond))
+		require.NoError(err)
+		require.NoError(s.RegisterJob(ctx, ok, time.Hour))
+		require.NoError(s.RegisterJob(ctx, failing, time.Hour))
+		s.Start(ctx)
+	}
+
+	repo, err := repoFn()
+	require.NoError(err)
+	assert.Eventually(func() bool {
+		j, err := repo.LookupJob(ctx, "failing")
+		return err == nil && j.FailureCount >= 2
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(int32(1), atomic.LoadInt32(&ok.runs))
+
+	j, err := repo.LookupJob(ctx, "ok")
+	require.NoError(err)
+	require.NotNil(j.LastRunStatus)
+	assert.Equal(string(RunCompleted), *j.LastRunStatus)
+	assert.Nil(j.RunningServerId)
+	assert.True(j.NextScheduledRun.After(time.Now().Add(50 * time.Minute)))
+
+	j, err = repo.LookupJob(ctx, "failing")
+	require.NoError(err)
+	require.NotNil(j.LastRunError)
+	assert.Equal("failed", *j.LastRunError)
+}
@@ -0,0 +1,36 @@
+package search
+
+const (
+	// searchQuery returns the resources matching the query $1 which are
+	// described by the condition, ranked by how well they match. A smaller
+	// rank is a better match: an exact id, then the name exactly, as a
+	// prefix, or anywhere within it ignoring case, then part of the id, the
+	// address and lastly the description.
+	searchQuery = `
+with
+matched as (
+  select public_id,
+         type,
+         scope_id,
+         coalesce(name, '') as name,
+         coalesce(description, '') as description,
+         coalesce(address, '') as address,
+         case
+           when public_id = $1                                  then 1
+           when lower(name) = lower($1)                         then 2
+           when strpos(lower(name), lower($1)) = 1              then 3
+           when strpos(lower(name), lower($1)) > 0              then 4
+           when strpos(lower(public_id), lower($1)) > 0         then 5
+           when strpos(lower(address), lower($1)) > 0           then 6
+           when strpos(lower(description), lower($1)) > 0       then 7
+         end as rank
+    from search_resource
+   where %s
+)
+select public_id, type, scope_id, name, description, address, rank
+  from matched
+ where rank is not null
+ order by rank, name, public_id
+ %s;
+`
+)
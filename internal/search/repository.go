@@ -0,0 +1,163 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+// Types are the types of resources which can be searched for.
+var Types = []resource.Type{
+	resource.Target,
+	resource.Host,
+	resource.Session,
+	resource.User,
+	resource.Group,
+}
+
+// Permission describes the resources of a type within a scope which a search
+// may return, as returned by the ACL of the user searching.
+type Permission struct {
+	ScopeId   string
+	Type      resource.Type
+	Resources perms.ResourcePermissions
+}
+
+// Result is a resource matching a search query.
+type Result struct {
+	PublicId    string
+	Type        resource.Type
+	ScopeId     string
+	Name        string
+	Description string
+	Address     string
+
+	// Rank is how well the resource matches the query. Smaller is better.
+	Rank int
+}
+
+// Repository is the search database repository.
+type Repository struct {
+	reader db.Reader
+
+	// defaultLimit provides a default for limiting the number of results returned from the repo
+	defaultLimit int
+}
+
+// NewRepository creates a new search Repository. Supports the options:
+// WithLimit which sets a default limit on results returned by repo
+// operations.
+func NewRepository(r db.Reader, opt ...Option) (*Repository, error) {
+	if r == nil {
+		return nil, errors.New("error creating db repository with nil reader")
+	}
+	opts := getOpts(opt...)
+	if opts.withLimit == 0 {
+		// zero signals the boundary defaults should be used.
+		opts.withLimit = db.DefaultLimit
+	}
+	return &Repository{
+		reader:       r,
+		defaultLimit: opts.withLimit,
+	}, nil
+}
+
+// Search returns the resources described by permissions whose name,
+// description, id or address match query, best matches first. userId is the
+// user searching, whose resources are returned when a permission is only
+// for the user's own resources. Supports the WithLimit option.
+func (r *Repository) Search(ctx context.Context, query, userId string, permissions []Permission, opt ...Option) ([]*Result, error) {
+	if query == "" {
+		return nil, fmt.Errorf("search: missing query: %w", db.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	limit := r.defaultLimit
+	if opts.withLimit != 0 {
+		// non-zero signals an override of the default limit for the repo.
+		limit = opts.withLimit
+	}
+
+	args := []interface{}{query}
+	var conditions []string
+	for _, p := range permissions {
+		if p.ScopeId == "" {
+			return nil, fmt.Errorf("search: missing scope id: %w", db.ErrInvalidParameter)
+		}
+		if !Searchable(p.Type) {
+			return nil, fmt.Errorf("search: unsupported resource type %q: %w", p.Type.String(), db.ErrInvalidParameter)
+		}
+		var resources []string
+		switch {
+		case p.Resources.All:
+			resources = append(resources, "true")
+		default:
+			if len(p.Resources.Ids) > 0 {
+				resources = append(resources, fmt.Sprintf("public_id in (%s)", inClause(&args, p.Resources.Ids)))
+			}
+			if len(p.Resources.Pins) > 0 {
+				resources = append(resources, fmt.Sprintf("parent_id in (%s)", inClause(&args, p.Resources.Pins)))
+			}
+			if p.Resources.OnlySelf && userId != "" {
+				args = append(args, userId)
+				resources = append(resources, fmt.Sprintf("owner_id = $%d", len(args)))
+			}
+		}
+		if len(resources) == 0 {
+			continue
+		}
+		args = append(args, p.ScopeId, p.Type.String())
+		conditions = append(conditions, fmt.Sprintf("(scope_id = $%d and type = $%d and (%s))", len(args)-1, len(args), strings.Join(resources, " or ")))
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	var limitClause string
+	if limit > 0 {
+		limitClause = fmt.Sprintf("limit %d", limit)
+	}
+	rows, err := r.reader.Query(ctx, fmt.Sprintf(searchQuery, strings.Join(conditions, " or "), limitClause), args)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	defer rows.Close()
+	var results []*Result
+	for rows.Next() {
+		var res Result
+		var typ string
+		if err := rows.Scan(&res.PublicId, &typ, &res.ScopeId, &res.Name, &res.Description, &res.Address, &res.Rank); err != nil {
+			return nil, fmt.Errorf("search: %w", err)
+		}
+		res.Type = resource.Map[typ]
+		results = append(results, &res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	return results, nil
+}
+
+// Searchable returns true if resources of type typ can be searched for.
+func Searchable(typ resource.Type) bool {
+	for _, t := range Types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// inClause appends ids to args and returns the placeholders for them.
+func inClause(args *[]interface{}, ids []string) string {
+	spots := make([]string, 0, len(ids))
+	for _, id := range ids {
+		*args = append(*args, id)
+		spots = append(spots, fmt.Sprintf("$%d", len(*args)))
+	}
+	return strings.Join(spots, ",")
+}
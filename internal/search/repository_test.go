@@ -0,0 +1,188 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_New(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+
+	repo, err := NewRepository(rw)
+	require.NoError(t, err)
+	assert.Equal(t, db.DefaultLimit, repo.defaultLimit)
+
+	repo, err = NewRepository(rw, WithLimit(5))
+	require.NoError(t, err)
+	assert.Equal(t, 5, repo.defaultLimit)
+
+	_, err = NewRepository(nil)
+	assert.Error(t, err)
+}
+
+func TestRepository_Search(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := NewRepository(rw)
+	require.NoError(t, err)
+
+	org, proj := iam.TestScopes(t, iamRepo)
+	web := target.TestTcpTarget(t, conn, proj.PublicId, "Web")
+	webProd := target.TestTcpTarget(t, conn, proj.PublicId, "web-prod")
+	prodWeb := target.TestTcpTarget(t, conn, proj.PublicId, "prod-web")
+	described := target.TestTcpTarget(t, conn, proj.PublicId, "other", target.WithDescription("the web servers"))
+	_ = target.TestTcpTarget(t, conn, proj.PublicId, "unrelated")
+	user := iam.TestUser(t, iamRepo, org.PublicId, iam.WithName("web admin"))
+	cat := static.TestCatalogs(t, conn, proj.PublicId, 1)[0]
+	hosts := static.TestHosts(t, conn, cat.PublicId, 2)
+	sess := session.TestDefaultSession(t, conn, wrapper, iamRepo)
+
+	all := perms.ResourcePermissions{All: true}
+	ids := func(results []*Result) []string {
+		var ret []string
+		for _, r := range results {
+			ret = append(ret, r.PublicId)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		userId      string
+		permissions []Permission
+		opt         []Option
+		want        []string
+		wantIsErr   error
+	}{
+		{
+			name:        "ranked",
+			query:       "web",
+			permissions: []Permission{{ScopeId: proj.PublicId, Type: resource.Target, Resources: all}},
+			want:        []string{web.PublicId, webProd.PublicId, prodWeb.PublicId, described.PublicId},
+		},
+		{
+			name:        "limited",
+			query:       "web",
+			permissions: []Permission{{ScopeId: proj.PublicId, Type: resource.Target, Resources: all}},
+			opt:         []Option{WithLimit(2)},
+			want:        []string{web.PublicId, webProd.PublicId},
+		},
+		{
+			name:        "exact-id-first",
+			query:       prodWeb.PublicId,
+			permissions: []Permission{{ScopeId: proj.PublicId, Type: resource.Target, Resources: all}},
+			want:        []string{prodWeb.PublicId},
+		},
+		{
+			name:        "by-id",
+			query:       "web",
+			permissions: []Permission{{ScopeId: proj.PublicId, Type: resource.Target, Resources: perms.ResourcePermissions{Ids: []string{prodWeb.PublicId, user.PublicId}}}},
+			want:        []string{prodWeb.PublicId},
+		},
+		{
+			name:  "across-types",
+			query: "web",
+			permissions: []Permission{
+				{ScopeId: proj.PublicId, Type: resource.Target, Resources: perms.ResourcePermissions{Ids: []string{web.PublicId}}},
+				{ScopeId: org.PublicId, Type: resource.User, Resources: all},
+			},
+			want: []string{web.PublicId, user.PublicId},
+		},
+		{
+			name:        "other-scope",
+			query:       "web",
+			permissions: []Permission{{ScopeId: org.PublicId, Type: resource.Target, Resources: all}},
+		},
+		{
+			name:        "hosts-by-catalog",
+			query:       cat.PublicId,
+			permissions: []Permission{{ScopeId: proj.PublicId, Type: resource.Host, Resources: perms.ResourcePermissions{Pins: []string{cat.PublicId}}}},
+			want:        sortedIds(hosts[0].PublicId, hosts[1].PublicId),
+		},
+		{
+			name:        "own-session",
+			query:       "127.0.0.1",
+			userId:      sess.UserId,
+			permissions: []Permission{{ScopeId: sess.ScopeId, Type: resource.Session, Resources: perms.ResourcePermissions{OnlySelf: true}}},
+			want:        []string{sess.PublicId},
+		},
+		{
+			name:        "other-users-session",
+			query:       "127.0.0.1",
+			userId:      user.PublicId,
+			permissions: []Permission{{ScopeId: sess.ScopeId, Type: resource.Session, Resources: perms.ResourcePermissions{OnlySelf: true}}},
+		},
+		{
+			name:  "no-permissions",
+			query: "web",
+		},
+		{
+			name:        "missing-query",
+			permissions: []Permission{{ScopeId: proj.PublicId, Type: resource.Target, Resources: all}},
+			wantIsErr:   db.ErrInvalidParameter,
+		},
+		{
+			name:        "missing-scope",
+			query:       "web",
+			permissions: []Permission{{Type: resource.Target, Resources: all}},
+			wantIsErr:   db.ErrInvalidParameter,
+		},
+		{
+			name:        "unsupported-type",
+			query:       "web",
+			permissions: []Permission{{ScopeId: proj.PublicId, Type: resource.Role, Resources: all}},
+			wantIsErr:   db.ErrInvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.Search(context.Background(), tt.query, tt.userId, tt.permissions, tt.opt...)
+			if tt.wantIsErr != nil {
+				assert.Error(err)
+				assert.True(errors.Is(err, tt.wantIsErr))
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.want, ids(got))
+		})
+	}
+
+	t.Run("result-fields", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.Search(context.Background(), hosts[0].Address, "",
+			[]Permission{{ScopeId: proj.PublicId, Type: resource.Host, Resources: all}})
+		require.NoError(err)
+		require.Len(got, 1)
+		assert.Equal(&Result{
+			PublicId: hosts[0].PublicId,
+			Type:     resource.Host,
+			ScopeId:  proj.PublicId,
+			Address:  hosts[0].Address,
+			Rank:     6,
+		}, got[0])
+	})
+}
+
+func sortedIds(ids ...string) []string {
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,50 @@
+package servers
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// The compressors a worker can use for the status and connection reports it
+// sends to controllers. Both are registered with gRPC when this package is
+// imported, so controllers can always decompress them.
+const (
+	CompressionNone   = ""
+	CompressionGzip   = gzip.Name
+	CompressionSnappy = "snappy"
+)
+
+func init() {
+	encoding.RegisterCompressor(snappyCompressor{})
+}
+
+// ValidCompression reports whether name is a compressor workers can use.
+func ValidCompression(name string) bool {
+	switch name {
+	case CompressionNone, CompressionGzip, CompressionSnappy:
+		return true
+	}
+	return false
+}
+
+// snappyCompressor is a gRPC compressor using the snappy stream format.
+// Snappy compresses less than gzip but costs much less CPU, which suits the
+// frequent, repetitive status reports of busy workers.
+type snappyCompressor struct{}
+
+var _ encoding.Compressor = snappyCompressor{}
+
+func (snappyCompressor) Name() string {
+	return CompressionSnappy
+}
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
@@ -0,0 +1,44 @@
+package servers
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestValidCompression(t *testing.T) {
+	assert.True(t, ValidCompression(CompressionNone))
+	assert.True(t, ValidCompression(CompressionGzip))
+	assert.True(t, ValidCompression(CompressionSnappy))
+	assert.False(t, ValidCompression("zstd"))
+}
+
+func TestCompressors(t *testing.T) {
+	msg := []byte(strings.Repeat("status report ", 100))
+	for _, name := range []string{CompressionGzip, CompressionSnappy} {
+		t.Run(name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			c := encoding.GetCompressor(name)
+			require.NotNil(c, "compressor is not registered")
+
+			var buf bytes.Buffer
+			w, err := c.Compress(&buf)
+			require.NoError(err)
+			_, err = w.Write(msg)
+			require.NoError(err)
+			require.NoError(w.Close())
+			assert.Less(buf.Len(), len(msg))
+
+			r, err := c.Decompress(&buf)
+			require.NoError(err)
+			got, err := ioutil.ReadAll(r)
+			require.NoError(err)
+			assert.Equal(msg, got)
+		})
+	}
+}
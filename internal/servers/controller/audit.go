@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/aead"
+	"golang.org/x/crypto/hkdf"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// auditSignatureInfo is the HKDF info used to derive the key signing audit
+// events from the global scope's database key.
+const auditSignatureInfo = "boundary-audit-event"
+
+type auditRecordKey struct{}
+
+// auditRecord collects the parts of a request which are only available to
+// the HTTP handler and the gateway, for inclusion in the request's audit
+// event.
+type auditRecord struct {
+	rpcMethod string
+	body      bytes.Buffer
+	maxBody   int64
+	status    int
+}
+
+// Write captures the request body as it is read by the gateway, up to the
+// maximum request size.
+func (a *auditRecord) Write(p []byte) (int, error) {
+	if room := a.maxBody - int64(a.body.Len()); room > 0 {
+		if int64(len(p)) > room {
+			a.body.Write(p[:room])
+		} else {
+			a.body.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// auditResponseWriter records the status code of the response.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	record *auditRecord
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if w.record.status == 0 {
+		w.record.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.record.status == 0 {
+		w.record.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered data to the client so streamed responses aren't
+// held back by the audit wrapper.
+func (w *auditResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// auditMetadataAnnotator records the gRPC method a request is routed to by
+// the gateway.
+func auditMetadataAnnotator(ctx context.Context, _ *http.Request) metadata.MD {
+	if record, ok := ctx.Value(auditRecordKey{}).(*auditRecord); ok {
+		record.rpcMethod, _ = runtime.RPCMethod(ctx)
+	}
+	return nil
+}
+
+// auditedAction returns true if requests performing a are audited. Only
+// requests which don't change anything are left out.
+func auditedAction(a action.Type) bool {
+	switch a {
+	case action.Unknown, action.Read, action.List:
+		return false
+	}
+	return true
+}
+
+// auditRequest emits an audit event for a request once it has been handled.
+func (c *Controller) auditRequest(ctx context.Context, r *http.Request, record *auditRecord) {
+	info := auth.GetAuditInfo(ctx)
+	if info == nil {
+		// Custom actions are audited even when the request is rejected before
+		// its authorization check
+		ca, ok := handlers.CustomActionForMethod(record.rpcMethod)
+		if !ok {
+			return
+		}
+		_, id, _ := handlers.CustomActionForPath(r.URL.Path)
+		info = &auth.AuditInfo{
+			Resource: perms.Resource{Id: id, Type: ca.Resource},
+			Action:   ca.Action,
+		}
+	}
+	// Everything done with the recovery KMS is audited, as it bypasses
+	// authorization entirely
+	if !auditedAction(info.Action) && info.RecoveryNonce == "" {
+		return
+	}
+	status := record.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	scopeId, scopeType := info.Resource.ScopeId, ""
+	if info.Scope != nil {
+		scopeId, scopeType = info.Scope.GetId(), info.Scope.GetType()
+	}
+	data := map[string]interface{}{
+		"time":       time.Now().UTC().Format(time.RFC3339Nano),
+		"rpc_method": record.rpcMethod,
+		"action":     info.Action.String(),
+		"actor": map[string]interface{}{
+			"user_id":       info.UserId,
+			"auth_token_id": info.AuthTokenId,
+		},
+		"scope": map[string]interface{}{
+			"id":   scopeId,
+			"type": scopeType,
+		},
+		"resource": map[string]interface{}{
+			"id":   info.Resource.Id,
+			"type": info.Resource.Type.String(),
+			"pin":  info.Resource.Pin,
+		},
+		"outcome": map[string]interface{}{
+			"status_code": status,
+			"authorized":  info.Allowed,
+			"success":     status < http.StatusBadRequest,
+		},
+	}
+	if info.RecoveryNonce != "" {
+		data["actor"].(map[string]interface{})["recovery_nonce"] = info.RecoveryNonce
+	}
+	params, err := auditRequestParameters(record.rpcMethod, r, record.body.Bytes())
+	if err != nil {
+		data["request_error"] = err.Error()
+	} else {
+		data["request"] = params
+	}
+	if err := c.signAuditData(ctx, data); err != nil {
+		c.logger.Error("error signing audit event", "error", err)
+	}
+
+	op := "controller.api." + strings.TrimPrefix(record.rpcMethod, "/controller.api.services.v1.")
+	if err := event.WriteAudit(ctx, op, data); err != nil {
+		c.logger.Error("error writing audit event", "op", op, "error", err)
+	}
+}
+
+// auditRequestParameters decodes the request body and query parameters into
+// the input message of rpcMethod, redacts its sensitive fields and returns it
+// as a map.
+func auditRequestParameters(rpcMethod string, r *http.Request, body []byte) (map[string]interface{}, error) {
+	parts := strings.Split(strings.TrimPrefix(rpcMethod, "/"), "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("unknown method %q", rpcMethod)
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("unknown service %q: %w", parts[0], err)
+	}
+	svc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", parts[0])
+	}
+	method := svc.Methods().ByName(protoreflect.Name(parts[1]))
+	if method == nil {
+		return nil, fmt.Errorf("unknown method %q", rpcMethod)
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+	if err != nil {
+		return nil, err
+	}
+	req := mt.New().Interface()
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+			return nil, fmt.Errorf("error decoding request: %w", err)
+		}
+	}
+	if err := runtime.PopulateQueryParameters(req, r.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+		return nil, fmt.Errorf("error decoding query parameters: %w", err)
+	}
+	handlers.RedactSensitiveFields(req)
+
+	raw, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// signAuditData adds a signature of data, made with a key derived from the
+// global scope's database key, to data.
+func (c *Controller) signAuditData(ctx context.Context, data map[string]interface{}) error {
+	if c.kms == nil {
+		return errors.New("no kms available")
+	}
+	wrapper, err := c.kms.GetWrapper(ctx, scope.Global.String(), kms.KeyPurposeDatabase)
+	if err != nil {
+		return err
+	}
+	sig, err := auditSignature(wrapper, data)
+	if err != nil {
+		return err
+	}
+	data["signature"] = map[string]interface{}{
+		"key_id":      wrapper.KeyID(),
+		"hmac_sha256": sig,
+	}
+	return nil
+}
+
+// auditSignature returns the base64 encoded HMAC of the JSON encoding of data,
+// excluding any existing signature, using a key derived from wrapper.
+func auditSignature(wrapper wrapping.Wrapper, data map[string]interface{}) (string, error) {
+	var aeadWrapper *aead.Wrapper
+	switch w := wrapper.(type) {
+	case interface{ WrapperForKeyID(string) wrapping.Wrapper }:
+		// A multiwrapper, or a kms wrapper embedding one
+		raw := w.WrapperForKeyID("__base__")
+		var ok bool
+		if aeadWrapper, ok = raw.(*aead.Wrapper); !ok {
+			return "", errors.New("unexpected wrapper type from multiwrapper base")
+		}
+	case *aead.Wrapper:
+		aeadWrapper = w
+	default:
+		return "", errors.New("unknown wrapper type")
+	}
+	key := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, aeadWrapper.GetKeyBytes(), nil, []byte(auditSignatureInfo)), key); err != nil {
+		return "", err
+	}
+
+	unsigned := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if k != "signature" {
+			unsigned[k] = v
+		}
+	}
+	// Map keys are sorted when encoded so the encoding is stable
+	raw, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(raw)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
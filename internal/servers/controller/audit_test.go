@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/api/authtokens"
+	"github.com/hashicorp/boundary/api/users"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditSignature(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	wrapper := db.TestWrapper(t)
+	data := map[string]interface{}{
+		"action": "create",
+		"actor":  map[string]interface{}{"user_id": "u_1234567890"},
+	}
+	sig, err := auditSignature(wrapper, data)
+	require.NoError(err)
+	assert.NotEmpty(sig)
+
+	// An existing signature is not part of the signed data
+	data["signature"] = map[string]interface{}{"hmac_sha256": sig}
+	again, err := auditSignature(wrapper, data)
+	require.NoError(err)
+	assert.Equal(sig, again)
+
+	data["action"] = "delete"
+	changed, err := auditSignature(wrapper, data)
+	require.NoError(err)
+	assert.NotEqual(sig, changed)
+
+	other, err := auditSignature(db.TestWrapper(t), map[string]interface{}{
+		"action": "create",
+		"actor":  map[string]interface{}{"user_id": "u_1234567890"},
+	})
+	require.NoError(err)
+	assert.NotEqual(sig, other)
+}
+
+func TestAuditRequestParameters(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	r := httptest.NewRequest("POST", "/v1/accounts/apw_1234567890:set-password", nil)
+	params, err := auditRequestParameters(
+		"/controller.api.services.v1.AccountService/SetPassword",
+		r,
+		[]byte(`{"version":1,"password":"secret","unknown":"ignored"}`))
+	require.NoError(err)
+	assert.Equal(map[string]interface{}{
+		"version":  float64(1),
+		"password": handlers.RedactedValue,
+	}, params)
+
+	r = httptest.NewRequest("DELETE", "/v1/users/u_1234567890?id=u_1234567890", nil)
+	params, err = auditRequestParameters("/controller.api.services.v1.UserService/DeleteUser", r, nil)
+	require.NoError(err)
+	assert.Equal(map[string]interface{}{"id": "u_1234567890"}, params)
+
+	_, err = auditRequestParameters("/controller.api.services.v1.UserService/Unknown", r, nil)
+	assert.Error(err)
+	_, err = auditRequestParameters("/controller.api.services.v1.AccountService/SetPassword", r, []byte("{"))
+	assert.Error(err)
+}
+
+func TestAuditEvents(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var buf bytes.Buffer
+	eventer, err := event.NewEventer(hclog.NewNullLogger(), &event.Config{})
+	require.NoError(err)
+	eventer.AddSink(event.NewWriterSink(&buf), event.AuditType)
+	event.InitSysEventer(eventer)
+	defer event.InitSysEventer(nil)
+
+	tc := NewTestController(t, &TestControllerOpts{
+		DefaultAuthMethodId: "ampw_1234567890",
+		DefaultLoginName:    "admin",
+		DefaultPassword:     "password123",
+	})
+	defer tc.Shutdown()
+
+	b, err := json.Marshal(map[string]interface{}{
+		"credentials": map[string]interface{}{
+			"login_name": "admin",
+			"password":   "password123",
+		},
+	})
+	require.NoError(err)
+	resp, err := http.Post(fmt.Sprintf("%s/v1/auth-methods/ampw_1234567890:authenticate", tc.ApiAddrs()[0]), "application/json", bytes.NewReader(b))
+	require.NoError(err)
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	// Reads are not audited
+	usersClient := users.NewClient(tc.Client())
+	_, err = usersClient.List(tc.Context(), scope.Global.String())
+	require.NoError(err)
+	u, err := usersClient.Create(tc.Context(), scope.Global.String(), users.WithName("audited"))
+	require.NoError(err)
+
+	var events []*event.Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		ev := new(event.Event)
+		require.NoError(json.Unmarshal([]byte(line), ev))
+		events = append(events, ev)
+	}
+	require.Len(events, 2)
+
+	wrapper, err := tc.Kms().GetWrapper(tc.Context(), scope.Global.String(), kms.KeyPurposeDatabase)
+	require.NoError(err)
+	for _, ev := range events {
+		sig, err := auditSignature(wrapper, ev.Data)
+		require.NoError(err)
+		assert.Equal(sig, ev.Data["signature"].(map[string]interface{})["hmac_sha256"])
+	}
+
+	authn := events[0]
+	assert.Equal(event.AuditType, authn.Type)
+	assert.Equal("controller.api.AuthMethodService/Authenticate", authn.Op)
+	assert.Equal("authenticate", authn.Data["action"])
+	assert.Equal(handlers.RedactedValue, authn.Data["request"].(map[string]interface{})["credentials"].(map[string]interface{})["password"])
+	assert.NotContains(fmt.Sprint(authn.Data), "password123")
+
+	create := events[1]
+	assert.Equal("controller.api.UserService/CreateUser", create.Op)
+	assert.Equal("create", create.Data["action"])
+	assert.Equal(tc.Token().UserId, create.Data["actor"].(map[string]interface{})["user_id"])
+	assert.Equal(tc.Token().Id, create.Data["actor"].(map[string]interface{})["auth_token_id"])
+	assert.Equal(scope.Global.String(), create.Data["scope"].(map[string]interface{})["id"])
+	assert.Equal(float64(http.StatusOK), create.Data["outcome"].(map[string]interface{})["status_code"])
+	assert.Equal(true, create.Data["outcome"].(map[string]interface{})["success"])
+	assert.Equal("audited", create.Data["request"].(map[string]interface{})["item"].(map[string]interface{})["name"])
+	assert.NotEmpty(u.Item.Id)
+}
+
+func TestAuditEvents_Recovery(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var buf bytes.Buffer
+	eventer, err := event.NewEventer(hclog.NewNullLogger(), &event.Config{})
+	require.NoError(err)
+	eventer.AddSink(event.NewWriterSink(&buf), event.AuditType)
+	event.InitSysEventer(eventer)
+	defer event.InitSysEventer(nil)
+
+	tc := NewTestController(t, nil)
+	defer tc.Shutdown()
+
+	client := tc.Client().Clone()
+	client.SetToken("")
+	client.SetRecoveryKmsWrapper(tc.Config().RecoveryKms)
+
+	// Even reads are audited when using the recovery KMS
+	_, err = users.NewClient(client).List(tc.Context(), scope.Global.String())
+	require.NoError(err)
+
+	var events []*event.Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		ev := new(event.Event)
+		require.NoError(json.Unmarshal([]byte(line), ev))
+		events = append(events, ev)
+	}
+	require.Len(events, 1)
+	list := events[0]
+	assert.Equal("controller.api.UserService/ListUsers", list.Op)
+	assert.Equal("list", list.Data["action"])
+	actor := list.Data["actor"].(map[string]interface{})
+	assert.Equal("u_recovery", actor["user_id"])
+	assert.NotEmpty(actor["recovery_nonce"])
+}
+
+func TestSecurityEvents(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var buf bytes.Buffer
+	eventer, err := event.NewEventer(hclog.NewNullLogger(), &event.Config{})
+	require.NoError(err)
+	eventer.AddSink(event.NewWriterSink(&buf), event.SecurityType)
+	event.InitSysEventer(eventer)
+	defer event.InitSysEventer(nil)
+
+	tc := NewTestController(t, &TestControllerOpts{
+		DefaultAuthMethodId: "ampw_1234567890",
+		DefaultLoginName:    "admin",
+		DefaultPassword:     "password123",
+	})
+	defer tc.Shutdown()
+
+	b, err := json.Marshal(map[string]interface{}{
+		"credentials": map[string]interface{}{
+			"login_name": "admin",
+			"password":   "wrong-password",
+		},
+	})
+	require.NoError(err)
+	resp, err := http.Post(fmt.Sprintf("%s/v1/auth-methods/ampw_1234567890:authenticate", tc.ApiAddrs()[0]), "application/json", bytes.NewReader(b))
+	require.NoError(err)
+	require.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	// Using a deleted token makes the request anonymous, which isn't allowed
+	// to list users
+	client := tc.Client()
+	_, err = authtokens.NewClient(client).Delete(tc.Context(), tc.Token().Id)
+	require.NoError(err)
+	_, err = users.NewClient(client).List(tc.Context(), scope.Global.String())
+	require.Error(err)
+
+	var events []*event.Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		ev := new(event.Event)
+		require.NoError(json.Unmarshal([]byte(line), ev))
+		events = append(events, ev)
+	}
+	require.Len(events, 3)
+	for _, ev := range events {
+		assert.Equal(event.SecurityType, ev.Type)
+		require.NotNil(ev.Request)
+		assert.NotEmpty(ev.Request.ClientIp)
+	}
+
+	authn := events[0]
+	assert.Equal(event.AuthenticationFailedKind, authn.Data["kind"])
+	assert.Equal("ampw_1234567890", authn.Data["auth_method_id"])
+	assert.Equal("admin", authn.Data["login_name"])
+	assert.NotContains(fmt.Sprint(authn.Data), "wrong-password")
+
+	invalid := events[1]
+	assert.Equal(event.InvalidAuthTokenKind, invalid.Data["kind"])
+	assert.Equal(tc.Token().Id, invalid.Data["auth_token_id"])
+
+	denied := events[2]
+	assert.Equal(event.AuthorizationDeniedKind, denied.Data["kind"])
+	assert.Equal("u_anon", denied.Data["user_id"])
+	assert.Equal(scope.Global.String(), denied.Data["scope_id"])
+	assert.Equal("user", denied.Data["resource_type"])
+	assert.Equal("list", denied.Data["action"])
+	assert.Equal("unauthenticated", denied.Data["decision"])
+}
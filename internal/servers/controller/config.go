@@ -3,6 +3,7 @@ package controller
 import (
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/session"
 )
 
 type Config struct {
@@ -14,4 +15,10 @@ type Config struct {
 	RawConfig *config.Config
 	// If set, authorization checking occurrs but failures are ignored
 	DisableAuthorizationFailures bool
+	// If set, the summaries of terminated sessions are replicated with it
+	// instead of to the database configured for session replication
+	SessionReplicator session.Replicator
+	// If set, the failures of preflight checks are logged but don't prevent
+	// the controller from starting
+	SkipPreflightChecks bool
 }
@@ -3,23 +3,34 @@ package controller
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/cmd/config"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/flowexport"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/quota"
+	"github.com/hashicorp/boundary/internal/ratelimit"
+	"github.com/hashicorp/boundary/internal/registry"
+	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/hashicorp/boundary/internal/search"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/version"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"github.com/hashicorp/vault/sdk/helper/mlock"
+	"github.com/jinzhu/gorm"
 	"github.com/patrickmn/go-cache"
 	ua "go.uber.org/atomic"
 )
@@ -31,8 +42,14 @@ type Controller struct {
 	baseContext context.Context
 	baseCancel  context.CancelFunc
 	started     ua.Bool
+	leader      ua.Bool
+
+	// leaderLock is the leader lock while this controller holds it. It's
+	// only used by the leader election goroutine.
+	leaderLock *db.Lock
 
 	workerAuthCache *cache.Cache
+	workerPki       *workerPki
 
 	// Used for testing
 	workerStatusUpdateTimes *sync.Map
@@ -41,12 +58,43 @@ type Controller struct {
 	AuthTokenRepoFn    common.AuthTokenRepoFactory
 	IamRepoFn          common.IamRepoFactory
 	PasswordAuthRepoFn common.PasswordAuthRepoFactory
+	SearchRepoFn       common.SearchRepoFactory
 	ServersRepoFn      common.ServersRepoFactory
 	SessionRepoFn      common.SessionRepoFactory
 	StaticHostRepoFn   common.StaticRepoFactory
 	TargetRepoFn       common.TargetRepoFactory
+	SchedulerRepoFn    scheduler.RepoFactory
+
+	// ResourceRegistry looks up resources of any type by their public id
+	ResourceRegistry *registry.Registry
+
+	kms       *kms.Kms
+	scheduler *scheduler.Scheduler
+
+	// sessionWatcher notifies clients watching sessions of status changes
+	sessionWatcher *session.Watcher
+
+	// rateLimiter limits the rate of API requests if rate limiting is
+	// configured; rateLimitStore is set when its buckets are kept in the
+	// database so idle ones can be cleaned up.
+	rateLimiter    *ratelimit.Limiter
+	rateLimitStore *ratelimit.DbStore
+
+	// quotas counts the API requests and checks the active sessions of each
+	// organization if quotas are configured.
+	quotas *quota.Accountant
+
+	// authTokenAccessRecorder batches the updates of the last access times
+	// of the auth tokens validated by this controller.
+	authTokenAccessRecorder *authtoken.AccessRecorder
 
-	kms *kms.Kms
+	// sessionReplicator replicates the summaries of terminated sessions to
+	// another region if session replication is configured.
+	sessionReplicator session.Replicator
+
+	// connectionExporter sends records of closed session connections to a
+	// collector if connection export is configured.
+	connectionExporter *flowexport.Exporter
 
 	clusterAddress string
 }
@@ -56,9 +104,11 @@ func New(conf *Config) (*Controller, error) {
 		conf:                    conf,
 		logger:                  conf.Logger.Named("controller"),
 		workerStatusUpdateTimes: new(sync.Map),
+		workerPki:               new(workerPki),
 	}
 
 	c.started.Store(false)
+	c.leader.Store(false)
 
 	if conf.SecureRandomReader == nil {
 		conf.SecureRandomReader = rand.Reader
@@ -108,13 +158,16 @@ func New(conf *Config) (*Controller, error) {
 		return nil, fmt.Errorf("error adding config keys to kms: %w", err)
 	}
 	c.IamRepoFn = func() (*iam.Repository, error) {
-		return iam.NewRepository(dbase, dbase, c.kms, iam.WithRandomReader(c.conf.SecureRandomReader))
+		return iam.NewRepository(dbase, dbase, c.kms,
+			iam.WithRandomReader(c.conf.SecureRandomReader),
+			iam.WithUniqueNames(c.conf.RawConfig.Controller.EnforceUniqueNames))
 	}
 	c.StaticHostRepoFn = func() (*static.Repository, error) {
 		return static.NewRepository(dbase, dbase, c.kms)
 	}
+	c.authTokenAccessRecorder = authtoken.NewAccessRecorder()
 	c.AuthTokenRepoFn = func() (*authtoken.Repository, error) {
-		return authtoken.NewRepository(dbase, dbase, c.kms)
+		return authtoken.NewRepository(dbase, dbase, c.kms, authtoken.WithAccessRecorder(c.authTokenAccessRecorder))
 	}
 	c.ServersRepoFn = func() (*servers.Repository, error) {
 		return servers.NewRepository(dbase, dbase, c.kms)
@@ -123,11 +176,64 @@ func New(conf *Config) (*Controller, error) {
 		return password.NewRepository(dbase, dbase, c.kms)
 	}
 	c.TargetRepoFn = func() (*target.Repository, error) {
-		return target.NewRepository(dbase, dbase, c.kms)
+		return target.NewRepository(dbase, dbase, c.kms, target.WithUniqueNames(c.conf.RawConfig.Controller.EnforceUniqueNames))
 	}
 	c.SessionRepoFn = func() (*session.Repository, error) {
 		return session.NewRepository(dbase, dbase, c.kms)
 	}
+	c.SearchRepoFn = func() (*search.Repository, error) {
+		return search.NewRepository(dbase)
+	}
+	c.SchedulerRepoFn = func() (*scheduler.Repository, error) {
+		return scheduler.NewRepository(dbase, dbase)
+	}
+	if c.ResourceRegistry, err = c.newResourceRegistry(); err != nil {
+		return nil, fmt.Errorf("error creating resource registry: %w", err)
+	}
+	c.scheduler, err = scheduler.New(c.conf.RawConfig.Controller.Name, c.SchedulerRepoFn, c.logger.Named("scheduler"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating scheduler: %w", err)
+	}
+	c.sessionWatcher = session.NewWatcher(c.SessionRepoFn, 0, c.logger.Named("session-watcher"))
+
+	if rl := c.conf.RawConfig.Controller.ApiRateLimit; rl.Enabled() {
+		if err := rl.Validate(); err != nil {
+			return nil, err
+		}
+		var store ratelimit.Store
+		switch rl.Store {
+		case ratelimit.DatabaseStore:
+			if c.rateLimitStore, err = ratelimit.NewDbStore(dbase, dbase); err != nil {
+				return nil, fmt.Errorf("error creating rate limit store: %w", err)
+			}
+			store = c.rateLimitStore
+		default:
+			store = ratelimit.NewMemoryStore()
+		}
+		if c.rateLimiter, err = ratelimit.NewLimiter(store, rl); err != nil {
+			return nil, fmt.Errorf("error creating rate limiter: %w", err)
+		}
+	}
+
+	if q := c.conf.RawConfig.Controller.Quota; q.Enabled() {
+		store, err := quota.NewDbStore(dbase, dbase)
+		if err != nil {
+			return nil, fmt.Errorf("error creating quota store: %w", err)
+		}
+		if c.quotas, err = quota.NewAccountant(store, q); err != nil {
+			return nil, fmt.Errorf("error creating quota accountant: %w", err)
+		}
+	}
+
+	if c.sessionReplicator, err = c.newSessionReplicator(); err != nil {
+		return nil, err
+	}
+
+	if ce := c.conf.RawConfig.Controller.ConnectionExport; ce.Enabled() {
+		if c.connectionExporter, err = flowexport.NewExporter(ce); err != nil {
+			return nil, fmt.Errorf("error creating connection exporter: %w", err)
+		}
+	}
 
 	c.workerAuthCache = cache.New(0, 0)
 
@@ -141,14 +247,35 @@ func (c *Controller) Start() error {
 	}
 	c.baseContext, c.baseCancel = context.WithCancel(context.Background())
 
+	if err := c.preflight(c.baseContext); err != nil {
+		var pfErr *PreflightError
+		if !errors.As(err, &pfErr) || !c.conf.SkipPreflightChecks {
+			return err
+		}
+		for _, f := range pfErr.Failures {
+			c.logger.Warn("ignoring failed preflight check", "check", f.Check, "error", f.Err)
+		}
+	}
+
 	if err := c.startListeners(); err != nil {
 		return fmt.Errorf("error starting controller listeners: %w", err)
 	}
 
 	c.startStatusTicking(c.baseContext)
+	c.startLeaderElection(c.baseContext)
 	c.startRecoveryNonceCleanupTicking(c.baseContext)
-	c.startTerminateCompletedSessionsTicking(c.baseContext)
+	c.startControllerJobTicking(c.baseContext)
+	if err := c.registerJobs(); err != nil {
+		return fmt.Errorf("error registering controller jobs: %w", err)
+	}
+	c.scheduler.Start(c.baseContext)
+	go c.sessionWatcher.Run(c.baseContext)
 	c.started.Store(true)
+	event.WriteSystem(c.baseContext, "controller.(Controller).Start", map[string]interface{}{
+		"name":            c.conf.RawConfig.Controller.Name,
+		"cluster_address": c.clusterAddress,
+		"version":         version.Get().VersionNumber(),
+	})
 
 	return nil
 }
@@ -162,11 +289,63 @@ func (c *Controller) Shutdown(serversOnly bool) error {
 	if err := c.stopListeners(serversOnly); err != nil {
 		return fmt.Errorf("error stopping controller listeners: %w", err)
 	}
+	c.runFinalFlushes()
 	c.clusterAddress = ""
 	c.started.Store(false)
+	event.WriteSystem(context.Background(), "controller.(Controller).Shutdown", map[string]interface{}{
+		"name": c.conf.RawConfig.Controller.Name,
+	})
 	return nil
 }
 
+// Reload applies the reloadable parts of newConf to the running controller,
+// which are the API rate limits. Turning rate limiting on or off, or changing
+// where its buckets are kept, requires a restart.
+func (c *Controller) Reload(newConf *config.Config) error {
+	var rl *ratelimit.Config
+	var q *quota.Config
+	if newConf.Controller != nil {
+		rl = newConf.Controller.ApiRateLimit
+		q = newConf.Controller.Quota
+	}
+	if err := c.reloadRateLimits(rl); err != nil {
+		return err
+	}
+	return c.reloadQuotas(q)
+}
+
+// reloadRateLimits replaces the API rate limits with those in rl.
+func (c *Controller) reloadRateLimits(rl *ratelimit.Config) error {
+	switch {
+	case c.rateLimiter == nil && !rl.Enabled():
+		return nil
+	case c.rateLimiter == nil || !rl.Enabled():
+		return errors.New("turning api rate limiting on or off requires a restart")
+	case storeName(rl) != storeName(c.conf.RawConfig.Controller.ApiRateLimit):
+		return errors.New("changing the api rate limit store requires a restart")
+	}
+	return c.rateLimiter.SetLimits(rl)
+}
+
+// reloadQuotas replaces the quotas with those in q.
+func (c *Controller) reloadQuotas(q *quota.Config) error {
+	switch {
+	case c.quotas == nil && !q.Enabled():
+		return nil
+	case c.quotas == nil || !q.Enabled():
+		return errors.New("turning quotas on or off requires a restart")
+	}
+	return c.quotas.SetConfig(q)
+}
+
+// storeName returns the store used for rl's buckets.
+func storeName(rl *ratelimit.Config) string {
+	if rl.Store == "" {
+		return ratelimit.MemoryStore
+	}
+	return rl.Store
+}
+
 // WorkerStatusUpdateTimes returns the map, which specifically is held in _this_
 // controller, not the DB. It's used in tests to verify that a given controller
 // is receiving updates from an expected set of workers, to test out balancing
@@ -174,3 +353,31 @@ func (c *Controller) Shutdown(serversOnly bool) error {
 func (c *Controller) WorkerStatusUpdateTimes() *sync.Map {
 	return c.workerStatusUpdateTimes
 }
+
+// newSessionReplicator returns the Replicator given in the controller's
+// config, or one writing to the configured session replication database, or
+// nil if session replication isn't configured.
+func (c *Controller) newSessionReplicator() (session.Replicator, error) {
+	if c.conf.SessionReplicator != nil {
+		return c.conf.SessionReplicator, nil
+	}
+	sr := c.conf.RawConfig.Controller.SessionReplication
+	if sr == nil || sr.DatabaseUrl == "" {
+		return nil, nil
+	}
+	url, err := config.ParseAddress(sr.DatabaseUrl)
+	if err != nil && err != config.ErrNotAUrl {
+		return nil, fmt.Errorf("error parsing session replication database url: %w", err)
+	}
+	conn, err := gorm.Open("postgres", strings.TrimSpace(url))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to session replication database: %w", err)
+	}
+	c.conf.ShutdownFuncs = append(c.conf.ShutdownFuncs, conn.Close)
+	rw := db.New(conn)
+	rep, err := session.NewDbReplicator(rw, rw)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session replicator: %w", err)
+	}
+	return rep, nil
+}
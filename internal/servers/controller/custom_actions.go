@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// apiErrorMarshaler encodes the errors returned by the controller outside of
+// the gateway the same way the gateway encodes errors.
+var apiErrorMarshaler = &runtime.JSONPb{
+	MarshalOptions: protojson.MarshalOptions{
+		UseProtoNames: true,
+	},
+}
+
+// wrapHandlerWithCustomActions rejects requests for custom actions which
+// aren't registered for the collection, or which aren't POSTs, with the same
+// errors for every collection. Other requests are passed to h.
+func wrapHandlerWithCustomActions(h http.Handler, c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ca, _, ok := handlers.CustomActionForPath(r.URL.Path)
+		switch {
+		case !ok:
+		case ca.Action == action.Unknown:
+			name := r.URL.Path[strings.LastIndex(r.URL.Path, ":")+1:]
+			handlers.ErrorHandler(c.logger)(r.Context(), nil, apiErrorMarshaler, w, r,
+				handlers.NotFoundErrorf("Action %q is not supported on %s.", name, ca.Collection))
+			return
+		case r.Method != http.MethodPost:
+			handlers.ErrorHandler(c.logger)(r.Context(), nil, apiErrorMarshaler, w, r,
+				handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Action %q must be performed with a POST.", ca.Action.String()))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
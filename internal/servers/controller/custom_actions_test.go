@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapHandlerWithCustomActions(t *testing.T) {
+	c := &Controller{logger: hclog.NewNullLogger()}
+	h := wrapHandlerWithCustomActions(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}), c)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "registered",
+			method:   http.MethodPost,
+			path:     "/v1/sessions/s_1234567890:cancel",
+			wantCode: http.StatusTeapot,
+		},
+		{
+			name:     "not-an-action",
+			method:   http.MethodGet,
+			path:     "/v1/sessions/s_1234567890",
+			wantCode: http.StatusTeapot,
+		},
+		{
+			name:     "collection-without-actions",
+			method:   http.MethodPost,
+			path:     "/v1/hosts/hst_1234567890:cancel",
+			wantCode: http.StatusTeapot,
+		},
+		{
+			name:     "unknown-action",
+			method:   http.MethodPost,
+			path:     "/v1/sessions/s_1234567890:set-grants",
+			wantCode: http.StatusNotFound,
+			wantErr:  "NotFound",
+		},
+		{
+			name:     "wrong-method",
+			method:   http.MethodGet,
+			path:     "/v1/roles/r_1234567890:set-grants",
+			wantCode: http.StatusNotImplemented,
+			wantErr:  "Unimplemented",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(tt.wantCode, w.Code)
+			if tt.wantErr == "" {
+				return
+			}
+			var body map[string]interface{}
+			require.NoError(json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(tt.wantErr, body["code"])
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/event"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/accounts"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/authmethods"
@@ -29,6 +31,7 @@ import (
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/hosts"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/roles"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/scopes"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/search"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/users"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -48,7 +51,8 @@ func (c *Controller) handler(props HandlerProperties) (http.Handler, error) {
 	if err != nil {
 		return nil, err
 	}
-	mux.Handle("/v1/", h)
+	mux.Handle("/v1/", wrapHandlerWithCustomActions(wrapHandlerWithIfMatch(h, c), c))
+	mux.Handle(sessionWatchPath, handleSessionWatch(c))
 	mux.Handle("/", handleUi(c))
 
 	corsWrappedHandler := wrapHandlerWithCors(mux, props)
@@ -78,6 +82,7 @@ func handleGrpcGateway(c *Controller, props HandlerProperties) (http.Handler, er
 		}),
 		runtime.WithErrorHandler(handlers.ErrorHandler(c.logger)),
 		runtime.WithForwardResponseOption(handlers.OutgoingInterceptor),
+		runtime.WithMetadata(auditMetadataAnnotator),
 	)
 	hcs, err := host_catalogs.NewService(c.StaticHostRepoFn, c.IamRepoFn)
 	if err != nil {
@@ -114,7 +119,7 @@ func handleGrpcGateway(c *Controller, props HandlerProperties) (http.Handler, er
 	if err := services.RegisterAuthMethodServiceHandlerServer(ctx, mux, authMethods); err != nil {
 		return nil, fmt.Errorf("failed to register auth method service handler: %w", err)
 	}
-	authtoks, err := authtokens.NewService(c.AuthTokenRepoFn, c.IamRepoFn)
+	authtoks, err := authtokens.NewService(c.kms, c.AuthTokenRepoFn, c.IamRepoFn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create auth token handler service: %w", err)
 	}
@@ -169,10 +174,21 @@ func handleGrpcGateway(c *Controller, props HandlerProperties) (http.Handler, er
 	if err := services.RegisterSessionServiceHandlerServer(ctx, mux, ss); err != nil {
 		return nil, fmt.Errorf("failed to register session service handler: %w", err)
 	}
+	srs, err := search.NewService(c.SearchRepoFn, c.IamRepoFn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create search handler service: %w", err)
+	}
+	if err := services.RegisterSearchServiceHandlerServer(ctx, mux, srs); err != nil {
+		return nil, fmt.Errorf("failed to register search service handler: %w", err)
+	}
 
 	return mux, nil
 }
 
+// requestIdHeader is the response header containing the id assigned to the
+// request, which is recorded in the request's events and oplog entries.
+const requestIdHeader = "X-Boundary-Request-Id"
+
 func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProperties) http.Handler {
 	var maxRequestDuration time.Duration
 	var maxRequestSize int64
@@ -196,16 +212,29 @@ func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProp
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Identify the request so its events and oplog entries can be
+		// correlated
+		reqInfo, err := event.NewRequestInfo()
+		if err != nil {
+			c.logger.Error("unable to generate request id", "error", err)
+			reqInfo = new(event.RequestInfo)
+		}
+		reqInfo.ClientIp = clientIp(r)
+		reqInfo.UserAgent = r.UserAgent()
 		if logUrls {
-			c.logger.Trace("request received", "method", r.Method, "url", r.URL.RequestURI())
+			c.logger.Trace("request received", "request_id", reqInfo.Id, "method", r.Method, "url", r.URL.RequestURI())
 		}
 
 		// Set the Cache-Control header for all responses returned
 		w.Header().Set("Cache-Control", "no-store")
+		if reqInfo.Id != "" {
+			w.Header().Set(requestIdHeader, reqInfo.Id)
+		}
 
 		// Start with the request context and our timeout
 		ctx, cancelFunc := context.WithTimeout(r.Context(), maxRequestDuration)
 		defer cancelFunc()
+		ctx = event.NewRequestInfoContext(ctx, reqInfo)
 
 		// Add a size limiter if desired
 		if maxRequestSize > 0 {
@@ -217,15 +246,35 @@ func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProp
 			Path:                 r.URL.Path,
 			Method:               r.Method,
 			DisableAuthzFailures: disableAuthzFailures,
+			Quotas:               c.quotas,
 		}
 
 		requestInfo.PublicId, requestInfo.EncryptedToken, requestInfo.TokenFormat = auth.GetTokenFromRequest(c.logger, c.kms, r)
 		ctx = auth.NewVerifierContext(ctx, c.logger, c.IamRepoFn, c.AuthTokenRepoFn, c.ServersRepoFn, c.kms, requestInfo)
 
+		if c.rateLimiter != nil && strings.HasPrefix(r.URL.Path, "/v1/") && !c.allowRequest(ctx, w, r, requestInfo.PublicId) {
+			return
+		}
+
+		// Capture what's needed for the request's audit event
+		record := &auditRecord{maxBody: maxRequestSize}
+		if record.maxBody <= 0 {
+			record.maxBody = globals.DefaultMaxRequestSize
+		}
+		ctx = context.WithValue(ctx, auditRecordKey{}, record)
+		if r.Body != nil {
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(r.Body, record), r.Body}
+		}
+
 		// Set the context back on the request
 		r = r.WithContext(ctx)
 
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(&auditResponseWriter{ResponseWriter: w, record: record}, r)
+
+		c.auditRequest(ctx, r, record)
 	})
 }
 
@@ -244,6 +293,7 @@ func wrapHandlerWithCors(h http.Handler, props HandlerProperties) http.Handler {
 		"Content-Type",
 		"X-Requested-With",
 		"Authorization",
+		"If-Match",
 	}, props.ListenerConfig.CorsAllowedHeaders...)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -293,6 +343,7 @@ func wrapHandlerWithCors(h http.Handler, props HandlerProperties) http.Handler {
 		}
 
 		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
 		w.Header().Set("Vary", "Origin")
 
 		// Apply headers for preflight requests
@@ -123,6 +123,7 @@ func TestHandleImplementedPaths(t *testing.T) {
 			// Creation end points
 			"v1/accounts",
 			"v1/auth-methods",
+			"v1/auth-tokens",
 			"v1/groups",
 			"v1/host-catalogs",
 			"v1/host-sets",
@@ -142,6 +143,7 @@ func TestHandleImplementedPaths(t *testing.T) {
 			"v1/host-sets/someid:add-hosts",
 			"v1/host-sets/someid:remove-hosts",
 			"v1/host-sets/someid:set-hosts",
+			"v1/hosts:import",
 			"v1/roles/someid:add-grants",
 			"v1/roles/someid:set-grants",
 			"v1/roles/someid:remove-grants",
@@ -28,6 +28,12 @@ func init() {
 	if maskManager, err = handlers.NewMaskManager(&store.Account{}, &pb.Account{}, &pb.PasswordAccountAttributes{}); err != nil {
 		panic(err)
 	}
+	if err := handlers.RegisterCustomActions(resource.Account, pbs.File_controller_api_services_v1_account_service_proto.Services().ByName("AccountService"), map[action.Type]string{
+		action.SetPassword:    "SetPassword",
+		action.ChangePassword: "ChangePassword",
+	}); err != nil {
+		panic(err)
+	}
 }
 
 // Service handles request as described by the pbs.AccountServiceServer interface.
@@ -50,18 +56,34 @@ func (s Service) ListAccounts(ctx context.Context, req *pbs.ListAccountsRequest)
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	_, authResults := s.parentAndAuthResult(ctx, req.GetAuthMethodId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	ul, err := s.listFromRepo(ctx, req.GetAuthMethodId())
+	ul, err := s.listFromRepo(ctx, req.GetAuthMethodId(), password.WithOrder(order))
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.Account
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
 	}
-	return &pbs.ListAccountsResponse{Items: ul}, nil
+	return &pbs.ListAccountsResponse{Items: finalItems}, nil
 }
 
 // GetAccount implements the interface pbs.AccountServiceServer.
@@ -157,7 +179,7 @@ func (s Service) SetPassword(ctx context.Context, req *pbs.SetPasswordRequest) (
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	u, err := s.setPasswordInRepo(ctx, authResults.Scope.GetId(), req.GetId(), req.GetVersion(), req.GetPassword())
+	u, err := s.setPasswordInRepo(ctx, authResults.Scope.GetId(), req.GetId(), req.GetVersion(), req.GetPassword(), req.GetMustChangePassword())
 	if err != nil {
 		return nil, err
 	}
@@ -280,12 +302,12 @@ func (s Service) deleteFromRepo(ctx context.Context, scopeId, id string) (bool,
 	return rows > 0, nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, authMethodId string) ([]*pb.Account, error) {
+func (s Service) listFromRepo(ctx context.Context, authMethodId string, opt ...password.Option) ([]*pb.Account, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	ul, err := repo.ListAccounts(ctx, authMethodId)
+	ul, err := repo.ListAccounts(ctx, authMethodId, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -325,12 +347,12 @@ func (s Service) changePasswordInRepo(ctx context.Context, scopeId, id string, v
 	return toProto(out)
 }
 
-func (s Service) setPasswordInRepo(ctx context.Context, scopeId, id string, version uint32, pw string) (*pb.Account, error) {
+func (s Service) setPasswordInRepo(ctx context.Context, scopeId, id string, version uint32, pw string, mustChange bool) (*pb.Account, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	out, err := repo.SetPassword(ctx, scopeId, id, pw, version)
+	out, err := repo.SetPassword(ctx, scopeId, id, pw, version, password.WithMustChangePassword(mustChange))
 	if err != nil {
 		switch {
 		case errors.Is(err, db.ErrRecordNotFound):
@@ -502,6 +524,9 @@ func validateSetPasswordRequest(req *pbs.SetPasswordRequest) error {
 	if req.GetVersion() == 0 {
 		badFields["version"] = "Existing resource version is required for an update."
 	}
+	if req.GetMustChangePassword() && req.GetPassword() == "" {
+		badFields["must_change_password"] = "A password is required when the password must be changed."
+	}
 	if len(badFields) > 0 {
 		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/boundary/internal/auth/password/store"
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/event"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authmethods"
 	pba "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authtokens"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
@@ -40,6 +41,11 @@ func init() {
 	if maskManager, err = handlers.NewMaskManager(&store.AuthMethod{}, &pb.AuthMethod{}, &pb.PasswordAuthMethodAttributes{}); err != nil {
 		panic(err)
 	}
+	if err := handlers.RegisterCustomActions(resource.AuthMethod, pbs.File_controller_api_services_v1_auth_method_service_proto.Services().ByName("AuthMethodService"), map[action.Type]string{
+		action.Authenticate: "Authenticate",
+	}); err != nil {
+		panic(err)
+	}
 }
 
 // Service handles request as described by the pbs.AuthMethodServiceServer interface.
@@ -71,18 +77,34 @@ func (s Service) ListAuthMethods(ctx context.Context, req *pbs.ListAuthMethodsRe
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	ul, err := s.listFromRepo(ctx, authResults.Scope.GetId())
+	ul, err := s.listFromRepo(ctx, authResults.Scope.GetId(), password.WithOrder(order))
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.AuthMethod
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
 	}
-	return &pbs.ListAuthMethodsResponse{Items: ul}, nil
+	return &pbs.ListAuthMethodsResponse{Items: finalItems}, nil
 }
 
 // GetAuthMethod implements the interface pbs.AuthMethodServiceServer.
@@ -187,12 +209,12 @@ func (s Service) getFromRepo(ctx context.Context, id string) (*pb.AuthMethod, er
 	return toAuthMethodProto(u)
 }
 
-func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.AuthMethod, error) {
+func (s Service) listFromRepo(ctx context.Context, scopeId string, opt ...password.Option) ([]*pb.AuthMethod, error) {
 	repo, err := s.pwRepoFn()
 	if err != nil {
 		return nil, err
 	}
-	ul, err := repo.ListAuthMethods(ctx, scopeId)
+	ul, err := repo.ListAuthMethods(ctx, scopeId, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -307,11 +329,40 @@ func (s Service) authenticateWithRepo(ctx context.Context, scopeId, authMethodId
 		return nil, err
 	}
 
+	// Sign ins from addresses outside the scope's sign in cidrs are refused
+	// before the password is checked.
+	var clientIp string
+	if info, ok := event.RequestInfoFromContext(ctx); ok {
+		clientIp = info.ClientIp
+	}
+	allowed, err := iamRepo.SignInAllowed(ctx, scopeId, authMethodId, clientIp)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		event.WriteSecurity(ctx, "authmethods.(Service).authenticateWithRepo", map[string]interface{}{
+			"kind":           event.SignInRestrictedKind,
+			"scope_id":       scopeId,
+			"auth_method_id": authMethodId,
+			"login_name":     loginName,
+		})
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.PermissionDenied, "Authenticating from this address is not allowed.")
+	}
+
 	acct, err := pwRepo.Authenticate(ctx, scopeId, authMethodId, loginName, pw)
 	if err != nil {
+		if errors.Is(err, password.ErrMustChangePassword) {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "Password must be changed before authenticating.")
+		}
 		return nil, err
 	}
 	if acct == nil {
+		event.WriteSecurity(ctx, "authmethods.(Service).authenticateWithRepo", map[string]interface{}{
+			"kind":           event.AuthenticationFailedKind,
+			"scope_id":       scopeId,
+			"auth_method_id": authMethodId,
+			"login_name":     loginName,
+		})
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unauthenticated, "Unable to authenticate.")
 	}
 
@@ -319,6 +370,12 @@ func (s Service) authenticateWithRepo(ctx context.Context, scopeId, authMethodId
 	if err != nil {
 		return nil, err
 	}
+	// Record the account's attributes and apply the attribute rules of its
+	// scope, so the groups they give the user are in place before the token
+	// is used.
+	if _, err := iamRepo.ApplyAccountAttributes(ctx, acct.GetPublicId(), acct.Attributes()); err != nil {
+		return nil, err
+	}
 	tok, err := atRepo.CreateAuthToken(ctx, u, acct.GetPublicId())
 	if err != nil {
 		return nil, err
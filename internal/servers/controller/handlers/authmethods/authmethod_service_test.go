@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/event"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authmethods"
 	scopepb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
@@ -1108,3 +1109,61 @@ func TestAuthenticate_AuthAccountConnectedToIamUser(t *testing.T) {
 	assert.NotEmpty(aToken.GetToken())
 	assert.True(strings.HasPrefix(aToken.GetToken(), aToken.GetId()))
 }
+
+func TestAuthenticate_SignInCidrs(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	o, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iam.TestRepo(t, conn, wrapper), nil
+	}
+	pwRepoFn := func() (*password.Repository, error) {
+		return password.NewRepository(rw, rw, kms)
+	}
+	atRepoFn := func() (*authtoken.Repository, error) {
+		return authtoken.NewRepository(rw, rw, kms)
+	}
+
+	am := password.TestAuthMethods(t, conn, o.GetPublicId(), 1)[0]
+	acct, err := password.NewAccount(am.GetPublicId(), password.WithLoginName(testLoginName))
+	require.NoError(t, err)
+	pwRepo, err := pwRepoFn()
+	require.NoError(t, err)
+	_, err = pwRepo.CreateAccount(context.Background(), o.GetPublicId(), acct, password.WithPassword(testPassword))
+	require.NoError(t, err)
+
+	iamRepo, err := iamRepoFn()
+	require.NoError(t, err)
+	_, err = iamRepo.CreateSignInCidr(context.Background(), &iam.SignInCidr{ScopeId: o.GetPublicId(), Cidr: "10.0.0.0/8"})
+	require.NoError(t, err)
+
+	s, err := authmethods.NewService(kms, pwRepoFn, iamRepoFn, atRepoFn)
+	require.NoError(t, err)
+	req := &pbs.AuthenticateRequest{
+		AuthMethodId: am.GetPublicId(),
+		Credentials: func() *structpb.Struct {
+			creds := map[string]*structpb.Value{
+				"login_name": {Kind: &structpb.Value_StringValue{StringValue: testLoginName}},
+				"password":   {Kind: &structpb.Value_StringValue{StringValue: testPassword}},
+			}
+			return &structpb.Struct{Fields: creds}
+		}(),
+	}
+	ctxFrom := func(clientIp string) context.Context {
+		ctx := auth.DisabledAuthTestContext(auth.WithScopeId(o.GetPublicId()))
+		return event.NewRequestInfoContext(ctx, &event.RequestInfo{Id: "req_1234567890", ClientIp: clientIp})
+	}
+
+	resp, err := s.Authenticate(ctxFrom("10.1.2.3"), req)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.GetItem().GetToken())
+
+	for _, clientIp := range []string{"192.168.1.1", ""} {
+		_, err = s.Authenticate(ctxFrom(clientIp), req)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, handlers.ApiErrorWithCode(codes.PermissionDenied)), "got %v", err)
+	}
+}
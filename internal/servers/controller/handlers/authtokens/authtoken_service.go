@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/db"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authtokens"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/perms"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/types/action"
@@ -17,21 +22,35 @@ import (
 	"github.com/hashicorp/boundary/internal/types/scope"
 )
 
+// sortColumns are the fields listed auth tokens can be sorted by. Auth
+// tokens don't have names.
+var sortColumns = handlers.SortColumns{
+	"created_time": "create_time",
+}
+
+// defaultApiTokenDuration is how long api tokens are created for when no
+// expiration time is provided.
+const defaultApiTokenDuration = 30 * 24 * time.Hour
+
 // Service handles request as described by the pbs.AuthTokenServiceServer interface.
 type Service struct {
+	kms       *kms.Kms
 	repoFn    common.AuthTokenRepoFactory
 	iamRepoFn common.IamRepoFactory
 }
 
 // NewService returns a user service which handles user related requests to boundary.
-func NewService(repo common.AuthTokenRepoFactory, iamRepoFn common.IamRepoFactory) (Service, error) {
+func NewService(kms *kms.Kms, repo common.AuthTokenRepoFactory, iamRepoFn common.IamRepoFactory) (Service, error) {
+	if kms == nil {
+		return Service{}, errors.New("nil kms provided")
+	}
 	if repo == nil {
 		return Service{}, fmt.Errorf("nil auth token repository provided")
 	}
 	if iamRepoFn == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{repoFn: repo, iamRepoFn: iamRepoFn}, nil
+	return Service{kms: kms, repoFn: repo, iamRepoFn: iamRepoFn}, nil
 }
 
 var _ pbs.AuthTokenServiceServer = Service{}
@@ -41,18 +60,53 @@ func (s Service) ListAuthTokens(ctx context.Context, req *pbs.ListAuthTokensRequ
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), sortColumns)
+	if err != nil {
+		return nil, err
+	}
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	ul, err := s.listFromRepo(ctx, req.GetScopeId())
+	// Callers who can only read their own auth tokens only list their own
+	opts := []authtoken.Option{authtoken.WithOrder(order)}
+	readAll := authResults.AdditionalVerification(ctx,
+		auth.WithType(resource.AuthToken),
+		auth.WithScopeId(authResults.Scope.GetId()),
+		auth.WithAction(action.Read),
+		auth.WithOwnerId(authResults.UserId))
+	if readAll.Error == nil && readAll.OnlySelf {
+		opts = append(opts, authtoken.WithUserId(authResults.UserId))
+	}
+	ul, err := s.listFromRepo(ctx, req.GetScopeId(), opts...)
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.AuthToken
 	for _, item := range ul {
+		read := authResults.AdditionalVerification(ctx,
+			auth.WithType(resource.AuthToken),
+			auth.WithScopeId(authResults.Scope.GetId()),
+			auth.WithId(item.GetId()),
+			auth.WithAction(action.Read),
+			auth.WithOwnerId(item.GetUserId()))
+		if read.Error != nil {
+			continue
+		}
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
 	}
-	return &pbs.ListAuthTokensResponse{Items: ul}, nil
+	return &pbs.ListAuthTokensResponse{Items: finalItems}, nil
 }
 
 // GetAuthToken implements the interface pbs.AuthTokenServiceServer.
@@ -72,6 +126,24 @@ func (s Service) GetAuthToken(ctx context.Context, req *pbs.GetAuthTokenRequest)
 	return &pbs.GetAuthTokenResponse{Item: u}, nil
 }
 
+// CreateAuthToken implements the interface pbs.AuthTokenServiceServer. It
+// creates an api token, which is restricted to the provided grants.
+func (s Service) CreateAuthToken(ctx context.Context, req *pbs.CreateAuthTokenRequest) (*pbs.CreateAuthTokenResponse, error) {
+	if err := validateCreateRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetItem().GetScopeId(), action.Create)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	at, err := s.createInRepo(ctx, req.GetItem())
+	if err != nil {
+		return nil, err
+	}
+	at.Scope = authResults.Scope
+	return &pbs.CreateAuthTokenResponse{Item: at, Uri: fmt.Sprintf("auth-tokens/%s", at.GetId())}, nil
+}
+
 // DeleteAuthToken implements the interface pbs.AuthTokenServiceServer.
 func (s Service) DeleteAuthToken(ctx context.Context, req *pbs.DeleteAuthTokenRequest) (*pbs.DeleteAuthTokenResponse, error) {
 	if err := validateDeleteRequest(req); err != nil {
@@ -106,6 +178,33 @@ func (s Service) getFromRepo(ctx context.Context, id string) (*pb.AuthToken, err
 	return toProto(u), nil
 }
 
+func (s Service) createInRepo(ctx context.Context, item *pb.AuthToken) (*pb.AuthToken, error) {
+	expiration := time.Now().Add(defaultApiTokenDuration)
+	if item.GetExpirationTime() != nil {
+		expiration = item.GetExpirationTime().AsTime()
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	at, err := repo.CreateApiToken(ctx, item.GetScopeId(), item.GetAccountId(), item.GetGrantStrings(), expiration)
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidParameter) || errors.Is(err, db.ErrRecordNotFound) {
+			return nil, handlers.InvalidArgumentErrorf("Unable to create API token.", map[string]string{
+				"account_id": "Must be an account in the provided scope which is linked to a user.",
+			})
+		}
+		return nil, fmt.Errorf("unable to create api token: %w", err)
+	}
+	token, err := authtoken.EncryptToken(ctx, s.kms, at.GetScopeId(), at.GetPublicId(), at.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	out := toProto(at)
+	out.Token = at.GetPublicId() + "_" + token
+	return out, nil
+}
+
 func (s Service) deleteFromRepo(ctx context.Context, id string) (bool, error) {
 	repo, err := s.repoFn()
 	if err != nil {
@@ -121,13 +220,13 @@ func (s Service) deleteFromRepo(ctx context.Context, id string) (bool, error) {
 	return rows > 0, nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, orgId string) ([]*pb.AuthToken, error) {
+func (s Service) listFromRepo(ctx context.Context, orgId string, opt ...authtoken.Option) ([]*pb.AuthToken, error) {
 	repo, err := s.repoFn()
 	_ = repo
 	if err != nil {
 		return nil, err
 	}
-	ul, err := repo.ListAuthTokens(ctx, orgId)
+	ul, err := repo.ListAuthTokens(ctx, orgId, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -176,7 +275,7 @@ func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.
 			return res
 		}
 		parentId = authTok.GetScopeId()
-		opts = append(opts, auth.WithId(id))
+		opts = append(opts, auth.WithId(id), auth.WithOwnerId(authTok.GetIamUserId()))
 	}
 	opts = append(opts, auth.WithScopeId(parentId))
 	return auth.Verify(ctx, opts...)
@@ -193,6 +292,8 @@ func toProto(in *authtoken.AuthToken) *pb.AuthToken {
 		UserId:                  in.GetIamUserId(),
 		AuthMethodId:            in.GetAuthMethodId(),
 		AccountId:               in.GetAuthAccountId(),
+		Api:                     in.GetApi(),
+		GrantStrings:            in.GetGrants(),
 	}
 	return &out
 }
@@ -206,6 +307,58 @@ func validateGetRequest(req *pbs.GetAuthTokenRequest) error {
 	return handlers.ValidateGetRequest(authtoken.AuthTokenPrefix, req, handlers.NoopValidatorFn)
 }
 
+func validateCreateRequest(req *pbs.CreateAuthTokenRequest) error {
+	item := req.GetItem()
+	badFields := map[string]string{}
+	if !handlers.ValidId(scope.Org.Prefix(), item.GetScopeId()) &&
+		item.GetScopeId() != scope.Global.String() {
+		badFields["scope_id"] = "Must be 'global' or a valid org scope id."
+	}
+	if !handlers.ValidId(password.AccountPrefix, item.GetAccountId()) {
+		badFields["account_id"] = "Must be a valid account id."
+	}
+	if len(item.GetGrantStrings()) == 0 {
+		badFields["grant_strings"] = "API tokens must be restricted to at least one grant."
+	}
+	for _, v := range item.GetGrantStrings() {
+		if len(strings.TrimSpace(v)) == 0 {
+			badFields["grant_strings"] = "Grant strings must not be empty."
+			break
+		}
+		if _, err := perms.Parse("p_anything", v); err != nil {
+			badFields["grant_strings"] = fmt.Sprintf("Improperly formatted grant %q.", v)
+			break
+		}
+	}
+	if item.GetExpirationTime() != nil {
+		exp := item.GetExpirationTime().AsTime()
+		switch {
+		case !exp.After(time.Now()):
+			badFields["expiration_time"] = "Must be in the future."
+		case exp.After(time.Now().Add(authtoken.MaxApiTokenDuration)):
+			badFields["expiration_time"] = fmt.Sprintf("Must be no more than %s away.", authtoken.MaxApiTokenDuration)
+		}
+	}
+	for k, v := range map[string]bool{
+		"id":                         item.GetId() != "",
+		"token":                      item.GetToken() != "",
+		"user_id":                    item.GetUserId() != "",
+		"auth_method_id":             item.GetAuthMethodId() != "",
+		"created_time":               item.GetCreatedTime() != nil,
+		"updated_time":               item.GetUpdatedTime() != nil,
+		"approximate_last_used_time": item.GetApproximateLastUsedTime() != nil,
+		"api":                        item.GetApi(),
+	} {
+		if v {
+			badFields[k] = "This is a read only field."
+		}
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
+
 func validateDeleteRequest(req *pbs.DeleteAuthTokenRequest) error {
 	return handlers.ValidateDeleteRequest(authtoken.AuthTokenPrefix, req, handlers.NoopValidatorFn)
 }
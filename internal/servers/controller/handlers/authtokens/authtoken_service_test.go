@@ -1,11 +1,15 @@
 package authtokens_test
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/db"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authtokens"
@@ -18,6 +22,7 @@ import (
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,7 +40,7 @@ func TestGet(t *testing.T) {
 		return authtoken.NewRepository(rw, rw, kms)
 	}
 
-	s, err := authtokens.NewService(repoFn, iamRepoFn)
+	s, err := authtokens.NewService(kms, repoFn, iamRepoFn)
 	require.NoError(t, err, "Couldn't create new auth token service.")
 
 	org, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrap))
@@ -178,7 +183,7 @@ func TestList(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			s, err := authtokens.NewService(repoFn, iamRepoFn)
+			s, err := authtokens.NewService(kms, repoFn, iamRepoFn)
 			require.NoError(t, err, "Couldn't create new user service.")
 
 			got, gErr := s.ListAuthTokens(auth.DisabledAuthTestContext(auth.WithScopeId(tc.scope)), &pbs.ListAuthTokensRequest{ScopeId: tc.scope})
@@ -207,7 +212,7 @@ func TestDelete(t *testing.T) {
 	org, _ := iam.TestScopes(t, iamRepo)
 	at := authtoken.TestAuthToken(t, conn, kms, org.GetPublicId())
 
-	s, err := authtokens.NewService(repoFn, iamRepoFn)
+	s, err := authtokens.NewService(kms, repoFn, iamRepoFn)
 	require.NoError(t, err, "Error when getting new user service.")
 
 	cases := []struct {
@@ -272,7 +277,7 @@ func TestDelete_twice(t *testing.T) {
 	org, _ := iam.TestScopes(t, iamRepo)
 	at := authtoken.TestAuthToken(t, conn, kms, org.GetPublicId())
 
-	s, err := authtokens.NewService(repoFn, iamRepoFn)
+	s, err := authtokens.NewService(kms, repoFn, iamRepoFn)
 	require.NoError(err, "Error when getting new user service")
 	req := &pbs.DeleteAuthTokenRequest{
 		Id: at.GetPublicId(),
@@ -283,3 +288,97 @@ func TestDelete_twice(t *testing.T) {
 	assert.Error(gErr, "Second attempt")
 	assert.True(errors.Is(gErr, handlers.ApiErrorWithCode(codes.NotFound)), "Expected permission denied for the second delete.")
 }
+
+func TestCreate(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrap := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrap)
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iam.TestRepo(t, conn, wrap), nil
+	}
+	repoFn := func() (*authtoken.Repository, error) {
+		return authtoken.NewRepository(rw, rw, kms)
+	}
+	iamRepo := iam.TestRepo(t, conn, wrap)
+
+	org, _ := iam.TestScopes(t, iamRepo)
+	authMethod := password.TestAuthMethods(t, conn, org.GetPublicId(), 1)[0]
+	acct := password.TestAccounts(t, conn, authMethod.GetPublicId(), 1)[0]
+	u, err := iamRepo.LookupUserWithLogin(context.Background(), acct.GetPublicId(), iam.WithAutoVivify(true))
+	require.NoError(t, err)
+
+	s, err := authtokens.NewService(kms, repoFn, iamRepoFn)
+	require.NoError(t, err, "Error when getting new auth token service.")
+
+	grants := []string{"id=*;type=target;actions=authorize-session"}
+	cases := []struct {
+		name string
+		item *pb.AuthToken
+		err  error
+	}{
+		{
+			name: "Create an api token",
+			item: &pb.AuthToken{ScopeId: org.GetPublicId(), AccountId: acct.GetPublicId(), GrantStrings: grants},
+		},
+		{
+			name: "Bad scope",
+			item: &pb.AuthToken{ScopeId: "p_1234567890", AccountId: acct.GetPublicId(), GrantStrings: grants},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Account in another scope",
+			item: &pb.AuthToken{ScopeId: scope.Global.String(), AccountId: acct.GetPublicId(), GrantStrings: grants},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "No grants",
+			item: &pb.AuthToken{ScopeId: org.GetPublicId(), AccountId: acct.GetPublicId()},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Bad grant",
+			item: &pb.AuthToken{ScopeId: org.GetPublicId(), AccountId: acct.GetPublicId(), GrantStrings: []string{"id=*;actions=read"}},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Expiration too far away",
+			item: &pb.AuthToken{
+				ScopeId:        org.GetPublicId(),
+				AccountId:      acct.GetPublicId(),
+				GrantStrings:   grants,
+				ExpirationTime: timestamppb.New(time.Now().Add(authtoken.MaxApiTokenDuration + time.Hour)),
+			},
+			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Read only field",
+			item: &pb.AuthToken{ScopeId: org.GetPublicId(), AccountId: acct.GetPublicId(), GrantStrings: grants, UserId: u.GetPublicId()},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, gErr := s.CreateAuthToken(auth.DisabledAuthTestContext(auth.WithScopeId(tc.item.GetScopeId())), &pbs.CreateAuthTokenRequest{Item: tc.item})
+			if tc.err != nil {
+				require.Error(gErr)
+				assert.True(errors.Is(gErr, tc.err), "CreateAuthToken(%+v) got error %v, wanted %v", tc.item, gErr, tc.err)
+				return
+			}
+			require.NoError(gErr)
+			item := got.GetItem()
+			assert.Equal("auth-tokens/"+item.GetId(), got.GetUri())
+			assert.True(item.GetApi())
+			assert.Equal(grants, item.GetGrantStrings())
+			assert.Equal(u.GetPublicId(), item.GetUserId())
+			assert.True(strings.HasPrefix(item.GetToken(), item.GetId()+"_"))
+
+			read, err := s.GetAuthToken(auth.DisabledAuthTestContext(auth.WithScopeId(org.GetPublicId())), &pbs.GetAuthTokenRequest{Id: item.GetId()})
+			require.NoError(err)
+			assert.True(read.GetItem().GetApi())
+			assert.Equal(grants, read.GetItem().GetGrantStrings())
+			assert.Empty(read.GetItem().GetToken())
+		})
+	}
+}
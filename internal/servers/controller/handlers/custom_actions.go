@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// CustomAction is an action, other than the create, read, update, delete and
+// list actions, which is performed on a single resource. Every custom action
+// is routed as a POST to /v1/<collection>/{id}:<action>, where <action> is
+// the name used for the action in grants.
+type CustomAction struct {
+	Resource   resource.Type
+	Action     action.Type
+	Collection string
+	// Method is the full gRPC method implementing the action, such as
+	// /controller.api.services.v1.SessionService/CancelSession.
+	Method string
+}
+
+// customActionPath matches the HTTP path of a custom action, capturing the
+// collection and the action.
+var customActionPath = regexp.MustCompile(`^/v1/([a-z-]+)/\{[a-z_]+\}:([a-z-]+)$`)
+
+var customActions = struct {
+	sync.RWMutex
+	byMethod     map[string]CustomAction
+	byCollection map[string]map[string]CustomAction
+	byResource   map[resource.Type][]action.Type
+}{
+	byMethod:     make(map[string]CustomAction),
+	byCollection: make(map[string]map[string]CustomAction),
+	byResource:   make(map[resource.Type][]action.Type),
+}
+
+// RegisterCustomActions registers the custom actions on resources of type res
+// which are implemented by the methods of svc. The methods are given by the
+// action they perform. An error is returned if an action is one of the
+// standard actions, a method doesn't exist or isn't routed as a POST to
+// /v1/<collection>/{id}:<action>, or an action is already registered for the
+// collection. Services call this from init.
+func RegisterCustomActions(res resource.Type, svc protoreflect.ServiceDescriptor, methods map[action.Type]string) error {
+	if svc == nil {
+		return fmt.Errorf("register custom actions: missing service")
+	}
+	customActions.Lock()
+	defer customActions.Unlock()
+	for act, name := range methods {
+		switch act {
+		case action.Unknown, action.All, action.List, action.Create, action.Read, action.Update, action.Delete:
+			return fmt.Errorf("register custom actions: %q is not a custom action", act.String())
+		}
+		m := svc.Methods().ByName(protoreflect.Name(name))
+		if m == nil {
+			return fmt.Errorf("register custom actions: %s has no method %q", svc.FullName(), name)
+		}
+		collection, err := customActionCollection(m, act)
+		if err != nil {
+			return fmt.Errorf("register custom actions: %w", err)
+		}
+		ca := CustomAction{
+			Resource:   res,
+			Action:     act,
+			Collection: collection,
+			Method:     fmt.Sprintf("/%s/%s", svc.FullName(), m.Name()),
+		}
+		if _, ok := customActions.byCollection[collection][act.String()]; ok {
+			return fmt.Errorf("register custom actions: %q is already registered for %s", act.String(), collection)
+		}
+		if customActions.byCollection[collection] == nil {
+			customActions.byCollection[collection] = make(map[string]CustomAction)
+		}
+		customActions.byCollection[collection][act.String()] = ca
+		customActions.byMethod[ca.Method] = ca
+		customActions.byResource[res] = append(customActions.byResource[res], act)
+	}
+	acts := customActions.byResource[res]
+	sort.Slice(acts, func(i, j int) bool { return acts[i] < acts[j] })
+	return nil
+}
+
+// customActionCollection returns the collection in the HTTP route of m,
+// checking that it is routed as custom action act.
+func customActionCollection(m protoreflect.MethodDescriptor, act action.Type) (string, error) {
+	opts, ok := m.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return "", fmt.Errorf("%s has no http route", m.FullName())
+	}
+	rule, ok := proto.GetExtension(opts, annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule.GetPost() == "" {
+		return "", fmt.Errorf("%s is not routed as a POST", m.FullName())
+	}
+	parts := customActionPath.FindStringSubmatch(rule.GetPost())
+	if parts == nil || parts[2] != act.String() {
+		return "", fmt.Errorf("%s is routed to %q, not /v1/<collection>/{id}:%s", m.FullName(), rule.GetPost(), act.String())
+	}
+	return parts[1], nil
+}
+
+// CustomActionForMethod returns the custom action implemented by the full
+// gRPC method name, if there is one.
+func CustomActionForMethod(method string) (CustomAction, bool) {
+	customActions.RLock()
+	defer customActions.RUnlock()
+	ca, ok := customActions.byMethod[method]
+	return ca, ok
+}
+
+// CustomActionForPath returns the custom action and the id of the resource a
+// request to path performs it on. If path has the form of a custom action but
+// no such action is registered for the collection, ok is true and the
+// returned action's Action is action.Unknown. Collections without custom
+// actions are left to the gateway.
+func CustomActionForPath(path string) (ca CustomAction, id string, ok bool) {
+	rest := strings.TrimPrefix(path, "/v1/")
+	if rest == path {
+		return CustomAction{}, "", false
+	}
+	segments := strings.Split(rest, "/")
+	if len(segments) != 2 {
+		return CustomAction{}, "", false
+	}
+	i := strings.LastIndex(segments[1], ":")
+	if i < 0 {
+		return CustomAction{}, "", false
+	}
+	collection, id, name := segments[0], segments[1][:i], segments[1][i+1:]
+	customActions.RLock()
+	defer customActions.RUnlock()
+	registered, ok := customActions.byCollection[collection]
+	if !ok {
+		return CustomAction{}, "", false
+	}
+	if ca, ok := registered[name]; ok {
+		return ca, id, true
+	}
+	return CustomAction{Collection: collection, Action: action.Unknown}, id, true
+}
+
+// CustomActions returns the custom actions registered for resources of type
+// res.
+func CustomActions(res resource.Type) []action.Type {
+	customActions.RLock()
+	defer customActions.RUnlock()
+	return append([]action.Type(nil), customActions.byResource[res]...)
+}
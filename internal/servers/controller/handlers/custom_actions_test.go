@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"testing"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCustomActions(t *testing.T) {
+	roles := pbs.File_controller_api_services_v1_role_service_proto.Services().ByName("RoleService")
+	require.NotNil(t, roles)
+
+	tests := []struct {
+		name    string
+		methods map[action.Type]string
+		wantErr string
+	}{
+		{
+			name:    "standard-action",
+			methods: map[action.Type]string{action.Update: "UpdateRole"},
+			wantErr: `"update" is not a custom action`,
+		},
+		{
+			name:    "unknown-method",
+			methods: map[action.Type]string{action.AddGrants: "AddGrants"},
+			wantErr: `has no method "AddGrants"`,
+		},
+		{
+			name:    "wrong-route",
+			methods: map[action.Type]string{action.SetGrants: "AddRoleGrants"},
+			wantErr: `not /v1/<collection>/{id}:set-grants`,
+		},
+		{
+			name:    "not-a-post",
+			methods: map[action.Type]string{action.AddGrants: "GetRole"},
+			wantErr: "is not routed as a POST",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterCustomActions(resource.Role, roles, tt.methods)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	require.NoError(t, RegisterCustomActions(resource.Role, roles, map[action.Type]string{
+		action.SetGrants: "SetRoleGrants",
+		action.AddGrants: "AddRoleGrants",
+	}))
+	err := RegisterCustomActions(resource.Role, roles, map[action.Type]string{action.AddGrants: "AddRoleGrants"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"add-grants" is already registered for roles`)
+
+	assert.Equal(t, []action.Type{action.AddGrants, action.SetGrants}, CustomActions(resource.Role))
+	assert.Empty(t, CustomActions(resource.Host))
+
+	ca, ok := CustomActionForMethod("/controller.api.services.v1.RoleService/SetRoleGrants")
+	require.True(t, ok)
+	assert.Equal(t, CustomAction{
+		Resource:   resource.Role,
+		Action:     action.SetGrants,
+		Collection: "roles",
+		Method:     "/controller.api.services.v1.RoleService/SetRoleGrants",
+	}, ca)
+	_, ok = CustomActionForMethod("/controller.api.services.v1.RoleService/GetRole")
+	assert.False(t, ok)
+
+	ca, id, ok := CustomActionForPath("/v1/roles/r_1234567890:add-grants")
+	require.True(t, ok)
+	assert.Equal(t, action.AddGrants, ca.Action)
+	assert.Equal(t, "r_1234567890", id)
+
+	ca, id, ok = CustomActionForPath("/v1/roles/r_1234567890:cancel")
+	require.True(t, ok)
+	assert.Equal(t, action.Unknown, ca.Action)
+	assert.Equal(t, "roles", ca.Collection)
+	assert.Equal(t, "r_1234567890", id)
+
+	for _, path := range []string{"/v1/roles/r_1234567890", "/v1/roles", "/v1/hosts/h_1234567890:cancel", "/roles/r_1234567890:add-grants"} {
+		_, _, ok = CustomActionForPath(path)
+		assert.False(t, ok, path)
+	}
+}
@@ -2,20 +2,25 @@ package handlers
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/event"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api"
+	"github.com/hashicorp/boundary/internal/quota"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -83,6 +88,16 @@ func NotFoundErrorf(msg string, a ...interface{}) error {
 	}}
 }
 
+// PreconditionFailedErrorf returns an ApiError indicating a precondition of
+// the request, such as an If-Match header, wasn't met.
+func PreconditionFailedErrorf(msg string, a ...interface{}) error {
+	return &apiError{&pb.Error{
+		Status:  http.StatusPreconditionFailed,
+		Code:    codes.FailedPrecondition.String(),
+		Message: fmt.Sprintf(msg, a...),
+	}}
+}
+
 func ForbiddenError() error {
 	return &apiError{&pb.Error{
 		Status:  http.StatusForbidden,
@@ -99,6 +114,38 @@ func UnauthenticatedError() error {
 	}}
 }
 
+// TooManyRequestsError returns an ApiError indicating the request exceeded a
+// rate limit and can be retried after the given number of seconds.
+func TooManyRequestsError(retryAfterSeconds uint32) error {
+	return &apiError{&pb.Error{
+		Status:  http.StatusTooManyRequests,
+		Code:    codes.ResourceExhausted.String(),
+		Message: "Too many requests, retry later.",
+		Details: &pb.ErrorDetails{RetryAfterSeconds: retryAfterSeconds},
+	}}
+}
+
+// QuotaExceededError returns an ApiError indicating the request was refused
+// because an organization used up one of its quotas. Quotas which are reset
+// at the end of a period can be retried once they are reset.
+func QuotaExceededError(e *quota.ExceededError) error {
+	details := &pb.ErrorDetails{
+		Quota:        string(e.Quota),
+		QuotaScopeId: e.ScopeId,
+	}
+	if !e.ResetTime.IsZero() {
+		if d := time.Until(e.ResetTime); d > 0 {
+			details.RetryAfterSeconds = uint32(math.Ceil(d.Seconds()))
+		}
+	}
+	return &apiError{&pb.Error{
+		Status:  http.StatusTooManyRequests,
+		Code:    codes.ResourceExhausted.String(),
+		Message: fmt.Sprintf("The %s quota of scope %s has been exceeded.", strings.ReplaceAll(string(e.Quota), "_", " "), e.ScopeId),
+		Details: details,
+	}}
+}
+
 func InvalidArgumentErrorf(msg string, fields map[string]string) error {
 	err := ApiErrorWithCodeAndMessage(codes.InvalidArgument, msg)
 	var apiErr *apiError
@@ -122,6 +169,10 @@ func InvalidArgumentErrorf(msg string, fields map[string]string) error {
 func backendErrorToApiError(inErr error) error {
 	stErr := status.Convert(inErr)
 
+	var nuErr *errors.NotUnique
+	var inUseErr *errors.ResourceInUse
+	var readOnlyErr *errors.ReadOnlyScope
+	var quotaErr *quota.ExceededError
 	switch {
 	case errors.Is(inErr, runtime.ErrNotMatch):
 		// grpc gateway uses this error when the path was not matched, but the error uses codes.Unimplemented which doesn't match the intention.
@@ -142,8 +193,18 @@ func backendErrorToApiError(inErr error) error {
 		return NotFoundErrorf(genericNotFoundMsg)
 	case errors.Is(inErr, db.ErrInvalidFieldMask), errors.Is(inErr, db.ErrEmptyFieldMask):
 		return InvalidArgumentErrorf("Error in provided request", map[string]string{"update_mask": "Invalid update mask provided."})
+	case errors.As(inErr, &nuErr):
+		return InvalidArgumentErrorf(genericUniquenessMsg, map[string]string{
+			"name": fmt.Sprintf("Name is already used by %s.", nuErr.ConflictingId),
+		})
 	case db.IsUniqueError(inErr), errors.Is(inErr, db.ErrNotUnique):
 		return InvalidArgumentErrorf(genericUniquenessMsg, nil)
+	case errors.As(inErr, &inUseErr):
+		return ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "Resource is in use by %s.", strings.Join(inUseErr.ReferencedBy, ", "))
+	case errors.As(inErr, &readOnlyErr):
+		return ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "Scope %s is read only.", readOnlyErr.ScopeId)
+	case errors.As(inErr, &quotaErr):
+		return QuotaExceededError(quotaErr)
 	}
 	return nil
 }
@@ -182,10 +243,20 @@ func ErrorHandler(logger hclog.Logger) runtime.ErrorHandlerFunc {
 				logger.Error("unable to generate internal error id", "error", err)
 				errId = "failed_to_generate_error_id"
 			}
-			logger.Error("internal error returned", "error id", errId, "error", inErr)
+			logger.Error("internal error returned", append([]interface{}{"error id", errId, "error", inErr}, errors.MetadataFrom(inErr).Pairs()...)...)
 			apiErr = getInternalError(errId)
 		}
 
+		if info, ok := event.RequestInfoFromContext(ctx); ok && info.Id != "" {
+			// Copy the error so package level errors aren't modified
+			inner := proto.Clone(apiErr.inner).(*pb.Error)
+			if inner.Details == nil {
+				inner.Details = new(pb.ErrorDetails)
+			}
+			inner.Details.RequestId = info.Id
+			apiErr = &apiError{inner: inner}
+		}
+
 		buf, merr := mar.Marshal(apiErr.inner)
 		if merr != nil {
 			logger.Error("failed to marshal error response", "response", fmt.Sprintf("%#v", apiErr.inner), "error", merr)
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -12,7 +11,10 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/event"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api"
+	"github.com/hashicorp/boundary/internal/quota"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -142,6 +144,68 @@ func TestApiErrorHandler(t *testing.T) {
 				Message: genericUniquenessMsg,
 			},
 		},
+		{
+			name: "Not unique name",
+			err: fmt.Errorf("test error: %w", &errors.NotUnique{
+				Resource:      "target",
+				Name:          "name",
+				ScopeId:       "p_1234567890",
+				ConflictingId: "ttcp_1234567890",
+			}),
+			expected: &pb.Error{
+				Status:  http.StatusBadRequest,
+				Code:    "InvalidArgument",
+				Message: genericUniquenessMsg,
+				Details: &pb.ErrorDetails{
+					RequestFields: []*pb.FieldError{
+						{
+							Name:        "name",
+							Description: "Name is already used by ttcp_1234567890.",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Resource in use",
+			err: fmt.Errorf("test error: %w", &errors.ResourceInUse{
+				Resource:     "user",
+				PublicId:     "u_1234567890",
+				ReferencedBy: []string{"r_1234567890", "s_1234567890"},
+			}),
+			expected: &pb.Error{
+				Status:  http.StatusBadRequest,
+				Code:    "FailedPrecondition",
+				Message: "Resource is in use by r_1234567890, s_1234567890.",
+			},
+		},
+		{
+			name: "Read only scope",
+			err:  fmt.Errorf("test error: %w", &errors.ReadOnlyScope{ScopeId: "o_1234567890"}),
+			expected: &pb.Error{
+				Status:  http.StatusBadRequest,
+				Code:    "FailedPrecondition",
+				Message: "Scope o_1234567890 is read only.",
+			},
+		},
+		{
+			name: "Quota exceeded",
+			err: fmt.Errorf("test error: %w", &quota.ExceededError{
+				ScopeId: "o_1234567890",
+				Quota:   quota.ActiveSessions,
+				Limit:   10,
+				Used:    10,
+			}),
+			expected: &pb.Error{
+				Status:  http.StatusTooManyRequests,
+				Code:    "ResourceExhausted",
+				Message: "The active sessions quota of scope o_1234567890 has been exceeded.",
+				Details: &pb.ErrorDetails{
+					Quota:        "active_sessions",
+					QuotaScopeId: "o_1234567890",
+				},
+			},
+		},
 		{
 			name: "Db record not found",
 			err:  fmt.Errorf("test error: %w", db.ErrRecordNotFound),
@@ -185,3 +249,24 @@ func TestApiErrorHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestApiErrorHandler_RequestId(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	req, err := http.NewRequest("GET", "madeup/for/the/test", nil)
+	require.NoError(err)
+	mux := runtime.NewServeMux()
+	inMarsh, outMarsh := runtime.MarshalerForRequest(mux, req)
+	ctx := event.NewRequestInfoContext(context.Background(), &event.RequestInfo{Id: "req_1234567890"})
+
+	inErr := NotFoundErrorf("Test")
+	w := httptest.NewRecorder()
+	ErrorHandler(hclog.L())(ctx, mux, outMarsh, w, req, inErr)
+
+	gotErr := &pb.Error{}
+	require.NoError(inMarsh.Unmarshal(w.Body.Bytes(), gotErr))
+	assert.Equal("req_1234567890", gotErr.GetDetails().GetRequestId())
+	// The error passed in is unchanged
+	var apiErr *apiError
+	require.True(errors.As(inErr, &apiErr))
+	assert.Nil(apiErr.inner.GetDetails())
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"github.com/hashicorp/boundary/internal/filter"
+)
+
+// NewFilter parses the filter of a list request. An InvalidArgument error
+// naming the filter field is returned if it can't be parsed.
+func NewFilter(expr string) (*filter.Filter, error) {
+	f, err := filter.New(expr)
+	if err != nil {
+		return nil, InvalidArgumentErrorf("Invalid filter.", map[string]string{"filter": err.Error()})
+	}
+	return f, nil
+}
@@ -30,6 +30,13 @@ func init() {
 	if maskManager, err = handlers.NewMaskManager(&store.Group{}, &pb.Group{}); err != nil {
 		panic(err)
 	}
+	if err := handlers.RegisterCustomActions(resource.Group, pbs.File_controller_api_services_v1_group_service_proto.Services().ByName("GroupService"), map[action.Type]string{
+		action.AddMembers:    "AddGroupMembers",
+		action.SetMembers:    "SetGroupMembers",
+		action.RemoveMembers: "RemoveGroupMembers",
+	}); err != nil {
+		panic(err)
+	}
 }
 
 // Service handles request as described by the pbs.GroupServiceServer interface.
@@ -52,18 +59,39 @@ func (s Service) ListGroups(ctx context.Context, req *pbs.ListGroupsRequest) (*p
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	gl, err := s.listFromRepo(ctx, req.GetScopeId())
+	// Narrow the listing to the name the filter requires, if any
+	opts := []iam.Option{iam.WithOrder(order)}
+	if name, ok := filter.Equality("/item/name"); ok {
+		opts = append(opts, iam.WithName(name))
+	}
+	gl, err := s.listFromRepo(ctx, req.GetScopeId(), opts...)
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.Group
 	for _, item := range gl {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
 	}
-	return &pbs.ListGroupsResponse{Items: gl}, nil
+	return &pbs.ListGroupsResponse{Items: finalItems}, nil
 }
 
 // GetGroups implements the interface pbs.GroupServiceServer.
@@ -242,15 +270,17 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build group for update: %v.", err)
 	}
 	g.PublicId = id
-	dbMask := maskManager.Translate(mask)
-	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+	dbMask, nullFields, err := maskManager.TranslateUpdate(mask, item)
+	if err != nil {
+		return nil, err
 	}
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	out, m, rowsUpdated, err := repo.UpdateGroup(ctx, g, version, dbMask)
+	// The repository determines which fields to set to null from the
+	// group's values so it is given every translated path
+	out, m, rowsUpdated, err := repo.UpdateGroup(ctx, g, version, append(dbMask, nullFields...))
 	if err != nil {
 		return nil, fmt.Errorf("unable to update group: %w", err)
 	}
@@ -275,12 +305,12 @@ func (s Service) deleteFromRepo(ctx context.Context, id string) (bool, error) {
 	return rows > 0, nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Group, error) {
+func (s Service) listFromRepo(ctx context.Context, scopeId string, opt ...iam.Option) ([]*pb.Group, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	gl, err := repo.ListGroups(ctx, scopeId)
+	gl, err := repo.ListGroups(ctx, scopeId, opt...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to list groups: %w", err)
 	}
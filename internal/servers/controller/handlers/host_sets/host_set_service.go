@@ -2,11 +2,12 @@ package host_sets
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/boundary/internal/auth"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/hostsets"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/host"
@@ -30,6 +31,13 @@ func init() {
 	if maskManager, err = handlers.NewMaskManager(&store.HostSet{}, &pb.HostSet{}); err != nil {
 		panic(err)
 	}
+	if err := handlers.RegisterCustomActions(resource.HostSet, pbs.File_controller_api_services_v1_host_set_service_proto.Services().ByName("HostSetService"), map[action.Type]string{
+		action.AddHosts:    "AddHostSetHosts",
+		action.SetHosts:    "SetHostSetHosts",
+		action.RemoveHosts: "RemoveHostSetHosts",
+	}); err != nil {
+		panic(err)
+	}
 }
 
 type Service struct {
@@ -51,18 +59,34 @@ func (s Service) ListHostSets(ctx context.Context, req *pbs.ListHostSetsRequest)
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	_, authResults := s.parentAndAuthResult(ctx, req.GetHostCatalogId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	hl, err := s.listFromRepo(ctx, req.GetHostCatalogId())
+	hl, err := s.listFromRepo(ctx, req.GetHostCatalogId(), static.WithOrder(order))
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.HostSet
 	for _, item := range hl {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
 	}
-	return &pbs.ListHostSetsResponse{Items: hl}, nil
+	return &pbs.ListHostSetsResponse{Items: finalItems}, nil
 }
 
 // GetHostSet implements the interface pbs.HostSetServiceServer.
@@ -274,12 +298,12 @@ func (s Service) deleteFromRepo(ctx context.Context, scopeId, id string) (bool,
 	return rows > 0, nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, catalogId string) ([]*pb.HostSet, error) {
+func (s Service) listFromRepo(ctx context.Context, catalogId string, opt ...static.Option) ([]*pb.HostSet, error) {
 	repo, err := s.staticRepoFn()
 	if err != nil {
 		return nil, err
 	}
-	hl, err := repo.ListSets(ctx, catalogId)
+	hl, err := repo.ListSets(ctx, catalogId, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -297,6 +321,9 @@ func (s Service) addInRepo(ctx context.Context, scopeId, setId string, hostIds [
 	}
 	_, err = repo.AddSetMembers(ctx, scopeId, setId, version, strutil.RemoveDuplicates(hostIds, false))
 	if err != nil {
+		if hostErr := hostIdsError(err, hostIds); hostErr != nil {
+			return nil, hostErr
+		}
 		// TODO: Figure out a way to surface more helpful error info beyond the Internal error.
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to add hosts to host set: %v.", err)
 	}
@@ -310,6 +337,28 @@ func (s Service) addInRepo(ctx context.Context, scopeId, setId string, hostIds [
 	return toProto(out, m), nil
 }
 
+// hostIdsError returns an InvalidArgument error naming the hosts in hostIds
+// the repository reported don't exist or aren't in the host set's scope, or
+// nil if err doesn't report any.
+func hostIdsError(err error, hostIds []string) error {
+	var multi *errors.Multi
+	if !errors.As(err, &multi) || !errors.Is(err, db.ErrInvalidParameter) {
+		return nil
+	}
+	var ids []string
+	for _, id := range multi.Ids() {
+		if strutil.StrListContains(hostIds, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{
+		"host_ids": fmt.Sprintf("Hosts %s don't exist or aren't in the host set's scope.", strings.Join(ids, ", ")),
+	})
+}
+
 func (s Service) setInRepo(ctx context.Context, scopeId, setId string, hostIds []string, version uint32) (*pb.HostSet, error) {
 	repo, err := s.staticRepoFn()
 	if err != nil {
@@ -317,6 +366,9 @@ func (s Service) setInRepo(ctx context.Context, scopeId, setId string, hostIds [
 	}
 	_, _, err = repo.SetSetMembers(ctx, scopeId, setId, version, strutil.RemoveDuplicates(hostIds, false))
 	if err != nil {
+		if hostErr := hostIdsError(err, hostIds); hostErr != nil {
+			return nil, hostErr
+		}
 		// TODO: Figure out a way to surface more helpful error info beyond the Internal error.
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to set hosts in host set: %v.", err)
 	}
@@ -864,6 +864,15 @@ func TestAddHostSetHosts(t *testing.T) {
 			},
 			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
 		},
+		{
+			name: "Hosts which don't exist in list",
+			req: &pbs.AddHostSetHostsRequest{
+				Id:      ss.GetPublicId(),
+				Version: ss.GetVersion(),
+				HostIds: []string{hs[0].GetPublicId(), static.HostPrefix + "_1234567890"},
+			},
+			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
 	}
 	for _, tc := range failCases {
 		t.Run(tc.name, func(t *testing.T) {
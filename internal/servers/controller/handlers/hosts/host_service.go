@@ -2,13 +2,14 @@ package hosts
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/boundary/internal/auth"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/hosts"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/host"
@@ -52,18 +53,34 @@ func (s Service) ListHosts(ctx context.Context, req *pbs.ListHostsRequest) (*pbs
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	_, authResults := s.parentAndAuthResult(ctx, req.GetHostCatalogId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	hl, err := s.listFromRepo(ctx, req.GetHostCatalogId())
+	hl, err := s.listFromRepo(ctx, req.GetHostCatalogId(), static.WithOrder(order))
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.Host
 	for _, item := range hl {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
 	}
-	return &pbs.ListHostsResponse{Items: hl}, nil
+	return &pbs.ListHostsResponse{Items: finalItems}, nil
 }
 
 // GetHost implements the interface pbs.HostServiceServer.
@@ -136,6 +153,25 @@ func (s Service) DeleteHost(ctx context.Context, req *pbs.DeleteHostRequest) (*p
 	return &pbs.DeleteHostResponse{}, nil
 }
 
+// ImportHosts implements the interface pbs.HostServiceServer.
+func (s Service) ImportHosts(ctx context.Context, req *pbs.ImportHostsRequest) (*pbs.ImportHostsResponse, error) {
+	if err := validateImportRequest(req); err != nil {
+		return nil, err
+	}
+	_, authResults := s.parentAndAuthResult(ctx, req.GetHostCatalogId(), action.Create)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	resp, err := s.importInRepo(ctx, authResults.Scope.GetId(), req.GetHostCatalogId(), req.GetItems())
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range resp.GetItems() {
+		item.Scope = authResults.Scope
+	}
+	return resp, nil
+}
+
 func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Host, error) {
 	repo, err := s.staticRepoFn()
 	if err != nil {
@@ -152,23 +188,9 @@ func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Host, error) {
 }
 
 func (s Service) createInRepo(ctx context.Context, scopeId, catalogId string, item *pb.Host) (*pb.Host, error) {
-	ha := &pb.StaticHostAttributes{}
-	if err := handlers.StructToProto(item.GetAttributes(), ha); err != nil {
-		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Failed converting attributes to subtype proto: %s", err)
-	}
-	var opts []static.Option
-	if ha.GetAddress() != nil {
-		opts = append(opts, static.WithAddress(ha.GetAddress().GetValue()))
-	}
-	if item.GetName() != nil {
-		opts = append(opts, static.WithName(item.GetName().GetValue()))
-	}
-	if item.GetDescription() != nil {
-		opts = append(opts, static.WithDescription(item.GetDescription().GetValue()))
-	}
-	h, err := static.NewHost(catalogId, opts...)
+	h, err := toStorageHost(catalogId, item)
 	if err != nil {
-		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build host for creation: %v.", err)
+		return nil, err
 	}
 
 	repo, err := s.staticRepoFn()
@@ -189,6 +211,29 @@ func (s Service) createInRepo(ctx context.Context, scopeId, catalogId string, it
 	return toProto(out, nil)
 }
 
+// toStorageHost returns the host to create in catalogId for item.
+func toStorageHost(catalogId string, item *pb.Host) (*static.Host, error) {
+	ha := &pb.StaticHostAttributes{}
+	if err := handlers.StructToProto(item.GetAttributes(), ha); err != nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Failed converting attributes to subtype proto: %s", err)
+	}
+	var opts []static.Option
+	if ha.GetAddress() != nil {
+		opts = append(opts, static.WithAddress(ha.GetAddress().GetValue()))
+	}
+	if item.GetName() != nil {
+		opts = append(opts, static.WithName(item.GetName().GetValue()))
+	}
+	if item.GetDescription() != nil {
+		opts = append(opts, static.WithDescription(item.GetDescription().GetValue()))
+	}
+	h, err := static.NewHost(catalogId, opts...)
+	if err != nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build host for creation: %v.", err)
+	}
+	return h, nil
+}
+
 func (s Service) updateInRepo(ctx context.Context, scopeId, catalogId, id string, mask []string, item *pb.Host) (*pb.Host, error) {
 	ha := &pb.StaticHostAttributes{}
 	if err := handlers.StructToProto(item.GetAttributes(), ha); err != nil {
@@ -227,6 +272,67 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, catalogId, id string
 	return toProto(out, nil)
 }
 
+func (s Service) importInRepo(ctx context.Context, scopeId, catalogId string, items []*pb.Host) (*pbs.ImportHostsResponse, error) {
+	resp := &pbs.ImportHostsResponse{}
+	var hosts []*static.Host
+	// rows maps the index of a host passed to the repository to its index in
+	// items.
+	var rows []uint32
+	for i, item := range items {
+		if badFields := validateImportItem(catalogId, item); len(badFields) > 0 {
+			resp.Errors = append(resp.Errors, &pbs.ImportHostError{
+				Row:     uint32(i),
+				Message: "Error in provided host.",
+				Fields:  badFields,
+			})
+			continue
+		}
+		h, err := toStorageHost(catalogId, item)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, h)
+		rows = append(rows, uint32(i))
+	}
+
+	if len(hosts) > 0 {
+		repo, err := s.staticRepoFn()
+		if err != nil {
+			return nil, err
+		}
+		created, err := repo.ImportHosts(ctx, scopeId, catalogId, hosts)
+		importErrs := &errors.Multi{}
+		if err != nil && !errors.As(err, &importErrs) {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to import hosts: %v.", err)
+		}
+		for _, h := range created {
+			item, err := toProto(h, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp.Items = append(resp.Items, item)
+		}
+		for _, e := range importErrs.Errors {
+			hostErr := &pbs.ImportHostError{Row: rows[e.Index]}
+			switch {
+			case errors.Is(e.Err, db.ErrNotUnique):
+				hostErr.Message = "Error in provided host."
+				hostErr.Fields = map[string]string{"name": "This name is already in use in the host catalog."}
+			case errors.Is(e.Err, static.ErrInvalidAddress):
+				hostErr.Message = "Error in provided host."
+				hostErr.Fields = map[string]string{"attributes.address": "This address is invalid."}
+			default:
+				hostErr.Message = fmt.Sprintf("Unable to create host: %v.", e.Err)
+			}
+			resp.Errors = append(resp.Errors, hostErr)
+		}
+	}
+	sort.Slice(resp.Errors, func(i, j int) bool {
+		return resp.Errors[i].GetRow() < resp.Errors[j].GetRow()
+	})
+	return resp, nil
+}
+
 func (s Service) deleteFromRepo(ctx context.Context, scopeId, id string) (bool, error) {
 	repo, err := s.staticRepoFn()
 	if err != nil {
@@ -239,12 +345,12 @@ func (s Service) deleteFromRepo(ctx context.Context, scopeId, id string) (bool,
 	return rows > 0, nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, catalogId string) ([]*pb.Host, error) {
+func (s Service) listFromRepo(ctx context.Context, catalogId string, opt ...static.Option) ([]*pb.Host, error) {
 	repo, err := s.staticRepoFn()
 	if err != nil {
 		return nil, err
 	}
-	hl, err := repo.ListHosts(ctx, catalogId)
+	hl, err := repo.ListHosts(ctx, catalogId, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -351,32 +457,38 @@ func validateCreateRequest(req *pbs.CreateHostRequest) error {
 		}
 		switch host.SubtypeFromId(req.GetItem().GetHostCatalogId()) {
 		case host.StaticSubtype:
-			if req.GetItem().GetType() != "" && req.GetItem().GetType() != host.StaticSubtype.String() {
-				badFields["type"] = "Doesn't match the parent resource's type."
-			}
-			attrs := &pb.StaticHostAttributes{}
-			if err := handlers.StructToProto(req.GetItem().GetAttributes(), attrs); err != nil {
-				badFields["attributes"] = "Attribute fields do not match the expected format."
-			}
-			if attrs.GetAddress() == nil ||
-				len(attrs.GetAddress().GetValue()) < static.MinHostAddressLength ||
-				len(attrs.GetAddress().GetValue()) > static.MaxHostAddressLength {
-				badFields["attributes.address"] = fmt.Sprintf("Address length must be between %d and %d characters.", static.MinHostAddressLength, static.MaxHostAddressLength)
-			}
-			_, _, err := net.SplitHostPort(attrs.GetAddress().GetValue())
-			switch {
-			case err == nil:
-				badFields["attributes.address"] = "Address for static hosts does not support a port."
-			case strings.Contains(err.Error(), "missing port in address"):
-				// Bare hostname, which we want
-			default:
-				badFields["attributes.address"] = fmt.Sprintf("Error parsing address: %v.", err)
-			}
+			validateStaticHost(req.GetItem(), badFields)
 		}
 		return badFields
 	})
 }
 
+// validateStaticHost adds the problems with the type and attributes of a
+// static host being created to badFields.
+func validateStaticHost(item *pb.Host, badFields map[string]string) {
+	if item.GetType() != "" && item.GetType() != host.StaticSubtype.String() {
+		badFields["type"] = "Doesn't match the parent resource's type."
+	}
+	attrs := &pb.StaticHostAttributes{}
+	if err := handlers.StructToProto(item.GetAttributes(), attrs); err != nil {
+		badFields["attributes"] = "Attribute fields do not match the expected format."
+	}
+	if attrs.GetAddress() == nil ||
+		len(attrs.GetAddress().GetValue()) < static.MinHostAddressLength ||
+		len(attrs.GetAddress().GetValue()) > static.MaxHostAddressLength {
+		badFields["attributes.address"] = fmt.Sprintf("Address length must be between %d and %d characters.", static.MinHostAddressLength, static.MaxHostAddressLength)
+	}
+	_, _, err := net.SplitHostPort(attrs.GetAddress().GetValue())
+	switch {
+	case err == nil:
+		badFields["attributes.address"] = "Address for static hosts does not support a port."
+	case strings.Contains(err.Error(), "missing port in address"):
+		// Bare hostname, which we want
+	default:
+		badFields["attributes.address"] = fmt.Sprintf("Error parsing address: %v.", err)
+	}
+}
+
 func validateUpdateRequest(req *pbs.UpdateHostRequest) error {
 	return handlers.ValidateUpdateRequest(static.HostPrefix, req, req.GetItem(), func() map[string]string {
 		badFields := map[string]string{}
@@ -409,6 +521,56 @@ func validateDeleteRequest(req *pbs.DeleteHostRequest) error {
 	return handlers.ValidateDeleteRequest(static.HostPrefix, req, handlers.NoopValidatorFn)
 }
 
+func validateImportRequest(req *pbs.ImportHostsRequest) error {
+	badFields := map[string]string{}
+	if !handlers.ValidId(static.HostCatalogPrefix, req.GetHostCatalogId()) {
+		badFields["host_catalog_id"] = "The field is incorrectly formatted."
+	}
+	switch {
+	case len(req.GetItems()) == 0:
+		badFields["items"] = "At least one host must be provided."
+	case len(req.GetItems()) > static.MaxImportHosts:
+		badFields["items"] = fmt.Sprintf("At most %d hosts can be imported at once.", static.MaxImportHosts)
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
+
+// validateImportItem returns the problems with one of the hosts of an import
+// into catalogId, which are the same as those of a create request.
+func validateImportItem(catalogId string, item *pb.Host) map[string]string {
+	badFields := map[string]string{}
+	if item == nil {
+		badFields["item"] = "This field is required."
+		return badFields
+	}
+	if item.GetId() != "" {
+		badFields["id"] = "This is a read only field."
+	}
+	if item.GetHostCatalogId() != "" && item.GetHostCatalogId() != catalogId {
+		badFields["host_catalog_id"] = "This must be empty or match the request's host_catalog_id."
+	}
+	if item.GetName() != nil {
+		item.GetName().Value = strings.TrimSpace(item.GetName().GetValue())
+	}
+	if item.GetDescription() != nil {
+		item.GetDescription().Value = strings.TrimSpace(item.GetDescription().GetValue())
+	}
+	if item.GetCreatedTime() != nil {
+		badFields["created_time"] = "This is a read only field."
+	}
+	if item.GetUpdatedTime() != nil {
+		badFields["updated_time"] = "This is a read only field."
+	}
+	if item.GetVersion() != 0 {
+		badFields["version"] = "Cannot specify this field in a create request."
+	}
+	validateStaticHost(item, badFields)
+	return badFields
+}
+
 func validateListRequest(req *pbs.ListHostsRequest) error {
 	badFields := map[string]string{}
 	if !handlers.ValidId(static.HostCatalogPrefix, req.GetHostCatalogId()) {
@@ -821,3 +821,77 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	rw := db.New(conn)
+	repoFn := func() (*static.Repository, error) {
+		return static.NewRepository(rw, rw, kms)
+	}
+	hc := static.TestCatalogs(t, conn, proj.GetPublicId(), 1)[0]
+	existing := static.TestHosts(t, conn, hc.GetPublicId(), 1)[0]
+	existing.Name = "existing"
+	_, err := rw.Update(context.Background(), existing, []string{"Name"}, nil)
+	require.NoError(t, err)
+
+	newItem := func(name, address string) *pb.Host {
+		return &pb.Host{
+			Name: &wrappers.StringValue{Value: name},
+			Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"address": structpb.NewStringValue(address),
+			}},
+		}
+	}
+
+	s, err := hosts.NewService(repoFn)
+	require.NoError(t, err, "Failed to create a new host service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(proj.GetPublicId()))
+
+	t.Run("invalid request", func(t *testing.T) {
+		assert := assert.New(t)
+		_, err := s.ImportHosts(ctx, &pbs.ImportHostsRequest{HostCatalogId: hc.GetPublicId()})
+		assert.True(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v", err)
+		_, err = s.ImportHosts(ctx, &pbs.ImportHostsRequest{HostCatalogId: "hcst_bad", Items: []*pb.Host{newItem("web", "10.0.0.1")}})
+		assert.True(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v", err)
+	})
+
+	t.Run("per-row errors", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		withPort := newItem("with-port", "10.0.0.2:22")
+		withId := newItem("with-id", "10.0.0.3")
+		withId.Id = static.HostPrefix + "_1234567890"
+		got, err := s.ImportHosts(ctx, &pbs.ImportHostsRequest{
+			HostCatalogId: hc.GetPublicId(),
+			Items: []*pb.Host{
+				newItem("web-1", "10.0.0.1"),
+				withPort,
+				newItem("existing", "10.0.0.4"),
+				withId,
+				newItem("web-2", "10.0.0.5"),
+			},
+		})
+		require.NoError(err)
+		require.Len(got.GetItems(), 2)
+		for _, item := range got.GetItems() {
+			assert.True(strings.HasPrefix(item.GetId(), static.HostPrefix))
+			assert.Equal(hc.GetPublicId(), item.GetHostCatalogId())
+			assert.Equal(proj.GetPublicId(), item.GetScope().GetId())
+		}
+		assert.Equal("web-1", got.GetItems()[0].GetName().GetValue())
+		assert.Equal("web-2", got.GetItems()[1].GetName().GetValue())
+
+		require.Len(got.GetErrors(), 3)
+		assert.Equal(uint32(1), got.GetErrors()[0].GetRow())
+		assert.Contains(got.GetErrors()[0].GetFields(), "attributes.address")
+		assert.Equal(uint32(2), got.GetErrors()[1].GetRow())
+		assert.Contains(got.GetErrors()[1].GetFields(), "name")
+		assert.Equal(uint32(3), got.GetErrors()[2].GetRow())
+		assert.Contains(got.GetErrors()[2].GetFields(), "id")
+	})
+}
@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	pb "github.com/hashicorp/boundary/internal/gen/controller/protooptions"
 )
@@ -82,6 +84,90 @@ func (m MaskManager) Translate(paths []string) []string {
 	return result
 }
 
+// readOnlyFields are the fields common to resources which can never be
+// updated through an update mask.
+var readOnlyFields = []string{"id", "scope_id", "scope", "type", "version", "created_time", "updated_time", "authorized_actions"}
+
+// TranslateUpdate validates the paths of an update request's field mask
+// against the definition of item and translates them for the destination's
+// protobuf. The translated paths are split into those which set a field, as
+// the field is populated in item, and those which clear it. Paths which don't
+// exist in item, read only fields and the fields in immutable are rejected
+// with an InvalidArgument error naming them, as is a mask without any valid
+// paths.
+func (m MaskManager) TranslateUpdate(paths []string, item proto.Message, immutable ...string) (dbMask []string, nullFields []string, err error) {
+	msg := item.ProtoReflect()
+	badFields := map[string]string{}
+	var unknown []string
+	for _, v := range paths {
+		for _, p := range strings.Split(v, ",") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			set, ok := maskPathSet(msg, p)
+			if !ok {
+				unknown = append(unknown, p)
+				continue
+			}
+			root := strings.SplitN(p, ".", 2)[0]
+			if MaskContains(readOnlyFields, root) || MaskContains(immutable, root) || MaskContains(immutable, p) {
+				badFields[p] = "This is a read only field and cannot be updated."
+				continue
+			}
+			dest, ok := m[p]
+			if !ok {
+				badFields[p] = "This field cannot be updated."
+				continue
+			}
+			if set {
+				dbMask = append(dbMask, dest)
+			} else {
+				nullFields = append(nullFields, dest)
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		badFields["update_mask"] = fmt.Sprintf("Unknown fields: %s.", strings.Join(unknown, ", "))
+	}
+	if len(badFields) > 0 {
+		return nil, nil, InvalidArgumentErrorf("Invalid fields included in the update mask.", badFields)
+	}
+	if len(dbMask) == 0 && len(nullFields) == 0 {
+		return nil, nil, InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+	}
+	return dbMask, nullFields, nil
+}
+
+// maskPathSet resolves the field mask path p against msg's definition. It
+// returns whether the field is populated in msg and false for ok if p doesn't
+// name a field. Paths may continue into the keys of a google.protobuf.Struct
+// field, such as a resource's attributes, but not into other messages.
+func maskPathSet(msg protoreflect.Message, p string) (set bool, ok bool) {
+	parts := strings.SplitN(p, ".", 2)
+	fd := msg.Descriptor().Fields().ByName(protoreflect.Name(parts[0]))
+	if fd == nil {
+		return false, false
+	}
+	if len(parts) == 1 {
+		return msg.Has(fd), true
+	}
+	if fd.Message() == nil || fd.Message().FullName() != "google.protobuf.Struct" || fd.IsList() || fd.IsMap() {
+		return false, false
+	}
+	if !msg.Has(fd) {
+		return false, true
+	}
+	st := msg.Get(fd).Message().Interface().(*structpb.Struct)
+	val, found := st.GetFields()[parts[1]]
+	if !found {
+		return false, true
+	}
+	_, isNull := val.GetKind().(*structpb.Value_NullValue)
+	return !isNull, true
+}
+
 func MaskContains(paths []string, s string) bool {
 	for _, p := range paths {
 		if p == s {
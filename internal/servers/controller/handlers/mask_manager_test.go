@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"testing"
 
+	hostpb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/hosts"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/protooptions"
+	hoststore "github.com/hashicorp/boundary/internal/host/static/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestMaskManager(t *testing.T) {
@@ -38,3 +44,95 @@ func TestMaskManager_errors(t *testing.T) {
 	_, err = NewMaskManager(&pb.TestBase{}, &pb.TestNotEnoughFields{})
 	assert.Error(t, err)
 }
+
+func TestMaskManager_TranslateUpdate(t *testing.T) {
+	mm, err := NewMaskManager(&hoststore.Host{}, &hostpb.Host{}, &hostpb.StaticHostAttributes{})
+	require.NoError(t, err)
+
+	attrs, err := structpb.NewStruct(map[string]interface{}{"address": "127.0.0.1"})
+	require.NoError(t, err)
+	nullAttrs, err := structpb.NewStruct(map[string]interface{}{"address": nil})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		paths      []string
+		item       *hostpb.Host
+		immutable  []string
+		wantMask   []string
+		wantNulls  []string
+		wantFields []string
+	}{
+		{
+			name:     "set",
+			paths:    []string{"name", "attributes.address"},
+			item:     &hostpb.Host{Name: wrapperspb.String("n"), Attributes: attrs},
+			wantMask: []string{"name", "address"},
+		},
+		{
+			name:      "clear",
+			paths:     []string{"name,description", "attributes.address"},
+			item:      &hostpb.Host{Description: wrapperspb.String("d"), Attributes: nullAttrs},
+			wantMask:  []string{"description"},
+			wantNulls: []string{"name", "address"},
+		},
+		{
+			name:      "nil-item",
+			paths:     []string{"name"},
+			item:      nil,
+			wantNulls: []string{"name"},
+		},
+		{
+			name:       "unknown",
+			paths:      []string{"name", "doesnt_exist", "name.value"},
+			item:       &hostpb.Host{},
+			wantFields: []string{"update_mask"},
+		},
+		{
+			name:       "read-only",
+			paths:      []string{"id", "created_time", "name"},
+			item:       &hostpb.Host{},
+			wantFields: []string{"created_time", "id"},
+		},
+		{
+			name:       "immutable",
+			paths:      []string{"name", "description"},
+			item:       &hostpb.Host{},
+			immutable:  []string{"name"},
+			wantFields: []string{"name"},
+		},
+		{
+			name:       "not-mapped",
+			paths:      []string{"host_catalog_id"},
+			item:       &hostpb.Host{},
+			wantFields: []string{"host_catalog_id"},
+		},
+		{
+			name:       "empty",
+			paths:      []string{" "},
+			item:       &hostpb.Host{},
+			wantFields: []string{"update_mask"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			mask, nulls, err := mm.TranslateUpdate(tt.paths, tt.item, tt.immutable...)
+			if len(tt.wantFields) > 0 {
+				require.Error(err)
+				assert.True(errors.Is(err, ApiErrorWithCode(codes.InvalidArgument)))
+				var apiErr *apiError
+				require.True(errors.As(err, &apiErr))
+				var fields []string
+				for _, f := range apiErr.inner.GetDetails().GetRequestFields() {
+					fields = append(fields, f.GetName())
+				}
+				assert.Equal(tt.wantFields, fields)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.wantMask, mask)
+			assert.Equal(tt.wantNulls, nulls)
+		})
+	}
+}
@@ -3,10 +3,12 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"strings"
 
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 const (
@@ -15,6 +17,9 @@ const (
 )
 
 func OutgoingInterceptor(ctx context.Context, w http.ResponseWriter, m proto.Message) error {
+	if v, ok := itemVersion(m); ok {
+		w.Header().Set("ETag", ETag(v))
+	}
 	m = m.ProtoReflect().Interface()
 	switch m := m.(type) {
 	case *pbs.AuthenticateResponse:
@@ -38,3 +43,24 @@ func OutgoingInterceptor(ctx context.Context, w http.ResponseWriter, m proto.Mes
 
 	return nil
 }
+
+// ETag returns the entity tag of a resource with version v.
+func ETag(v uint32) string {
+	return strconv.Quote(strconv.FormatUint(uint64(v), 10))
+}
+
+// itemVersion returns the version of the resource in the item field of a
+// response, if it has one.
+func itemVersion(m proto.Message) (uint32, bool) {
+	msg := m.ProtoReflect()
+	itemField := msg.Descriptor().Fields().ByName("item")
+	if itemField == nil || itemField.Kind() != protoreflect.MessageKind || itemField.IsList() || !msg.Has(itemField) {
+		return 0, false
+	}
+	item := msg.Get(itemField).Message()
+	versionField := item.Descriptor().Fields().ByName("version")
+	if versionField == nil || versionField.Kind() != protoreflect.Uint32Kind || !item.Has(versionField) {
+		return 0, false
+	}
+	return uint32(item.Get(versionField).Uint()), true
+}
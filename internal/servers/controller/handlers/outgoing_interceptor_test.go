@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authtokens"
+	pbu "github.com/hashicorp/boundary/internal/gen/controller/api/resources/users"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,3 +23,17 @@ func TestOutgoingSplitCookie(t *testing.T) {
 		{Name: JsVisibleCookieName, Value: "t_abc_12", Raw: "wt-js-token-cookie=t_abc_12"},
 	})
 }
+
+func TestOutgoingETag(t *testing.T) {
+	rec := httptest.NewRecorder()
+	OutgoingInterceptor(context.Background(), rec, &pbs.GetUserResponse{Item: &pbu.User{Id: "u_1234567890", Version: 3}})
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+	rec = httptest.NewRecorder()
+	OutgoingInterceptor(context.Background(), rec, &pbs.ListUsersResponse{Items: []*pbu.User{{Id: "u_1234567890", Version: 3}}})
+	assert.Empty(t, rec.Header().Get("ETag"))
+
+	rec = httptest.NewRecorder()
+	OutgoingInterceptor(context.Background(), rec, &pbs.AuthenticateResponse{Item: &pb.AuthToken{Token: "t_abc_1234567890"}})
+	assert.Empty(t, rec.Header().Get("ETag"))
+}
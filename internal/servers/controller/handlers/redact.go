@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/protooptions"
+)
+
+// RedactedValue replaces the value of sensitive string fields.
+const RedactedValue = "<redacted>"
+
+// RedactSensitiveFields redacts the fields of m, and of the messages nested in
+// it, which are marked with the sensitive custom option. String fields are set
+// to RedactedValue and all other fields are cleared. The keys of a
+// google.protobuf.Struct field are redacted if they match the name of a
+// sensitive field of a message declared in the same file as the message
+// containing the struct, since those messages describe the struct's layout.
+func RedactSensitiveFields(m proto.Message) {
+	redact(m.ProtoReflect())
+}
+
+func redact(m protoreflect.Message) {
+	var sensitive []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isSensitive(fd):
+			sensitive = append(sensitive, fd)
+		case fd.Message() == nil:
+		case fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				redact(l.Get(i).Message())
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redact(mv.Message())
+					return true
+				})
+			}
+		case fd.Message().FullName() == "google.protobuf.Struct":
+			redactStruct(v.Message().Interface().(*structpb.Struct), m.Descriptor().ParentFile())
+		default:
+			redact(v.Message())
+		}
+		return true
+	})
+	for _, fd := range sensitive {
+		switch {
+		case fd.IsList() || fd.IsMap():
+			m.Clear(fd)
+		case fd.Kind() == protoreflect.StringKind:
+			m.Set(fd, protoreflect.ValueOfString(RedactedValue))
+		case fd.Message() != nil && fd.Message().FullName() == "google.protobuf.StringValue":
+			m.Set(fd, protoreflect.ValueOfMessage(wrapperspb.String(RedactedValue).ProtoReflect()))
+		default:
+			m.Clear(fd)
+		}
+	}
+}
+
+func redactStruct(s *structpb.Struct, file protoreflect.FileDescriptor) {
+	if len(s.GetFields()) == 0 {
+		return
+	}
+	names := make(map[string]bool)
+	msgs := file.Messages()
+	for i := 0; i < msgs.Len(); i++ {
+		fields := msgs.Get(i).Fields()
+		for j := 0; j < fields.Len(); j++ {
+			if f := fields.Get(j); isSensitive(f) {
+				names[string(f.Name())] = true
+				names[f.JSONName()] = true
+			}
+		}
+	}
+	for k := range s.Fields {
+		if names[k] {
+			s.Fields[k] = structpb.NewStringValue(RedactedValue)
+		}
+	}
+}
+
+func isSensitive(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	return proto.GetExtension(opts, pb.E_Sensitive).(bool)
+}
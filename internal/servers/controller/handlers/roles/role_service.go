@@ -31,6 +31,17 @@ func init() {
 	if maskManager, err = handlers.NewMaskManager(&store.Role{}, &pb.Role{}); err != nil {
 		panic(err)
 	}
+	if err := handlers.RegisterCustomActions(resource.Role, pbs.File_controller_api_services_v1_role_service_proto.Services().ByName("RoleService"), map[action.Type]string{
+		action.AddPrincipals:    "AddRolePrincipals",
+		action.SetPrincipals:    "SetRolePrincipals",
+		action.RemovePrincipals: "RemoveRolePrincipals",
+		action.AddGrants:        "AddRoleGrants",
+		action.SetGrants:        "SetRoleGrants",
+		action.RemoveGrants:     "RemoveRoleGrants",
+		action.Clone:            "CloneRole",
+	}); err != nil {
+		panic(err)
+	}
 }
 
 // Service handles request as described by the pbs.RoleServiceServer interface.
@@ -53,18 +64,39 @@ func (s Service) ListRoles(ctx context.Context, req *pbs.ListRolesRequest) (*pbs
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	gl, err := s.listFromRepo(ctx, req.GetScopeId())
+	// Narrow the listing to the name the filter requires, if any
+	opts := []iam.Option{iam.WithOrder(order)}
+	if name, ok := filter.Equality("/item/name"); ok {
+		opts = append(opts, iam.WithName(name))
+	}
+	gl, err := s.listFromRepo(ctx, req.GetScopeId(), opts...)
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.Role
 	for _, item := range gl {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
 	}
-	return &pbs.ListRolesResponse{Items: gl}, nil
+	return &pbs.ListRolesResponse{Items: finalItems}, nil
 }
 
 // GetRoles implements the interface pbs.RoleServiceServer.
@@ -93,7 +125,7 @@ func (s Service) CreateRole(ctx context.Context, req *pbs.CreateRoleRequest) (*p
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	r, err := s.createInRepo(ctx, authResults.Scope.GetId(), req.GetItem())
+	r, err := s.createInRepo(ctx, authResults.Scope.GetId(), req.GetItem(), req.GetTemplate())
 	if err != nil {
 		return nil, err
 	}
@@ -236,6 +268,23 @@ func (s Service) RemoveRoleGrants(ctx context.Context, req *pbs.RemoveRoleGrants
 	return &pbs.RemoveRoleGrantsResponse{Item: r}, nil
 }
 
+// CloneRole implements the interface pbs.RoleServiceServer.
+func (s Service) CloneRole(ctx context.Context, req *pbs.CloneRoleRequest) (*pbs.CloneRoleResponse, error) {
+	if err := validateCloneRoleRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.Clone)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	r, err := s.cloneInRepo(ctx, req.GetId(), req.GetName(), req.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+	r.Scope = authResults.Scope
+	return &pbs.CloneRoleResponse{Item: r}, nil
+}
+
 func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Role, error) {
 	repo, err := s.repoFn()
 	if err != nil {
@@ -254,7 +303,7 @@ func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Role, error) {
 	return toProto(out, pr, roleGrants), nil
 }
 
-func (s Service) createInRepo(ctx context.Context, scopeId string, item *pb.Role) (*pb.Role, error) {
+func (s Service) createInRepo(ctx context.Context, scopeId string, item *pb.Role, template string) (*pb.Role, error) {
 	var opts []iam.Option
 	if item.GetName() != nil {
 		opts = append(opts, iam.WithName(item.GetName().GetValue()))
@@ -273,6 +322,13 @@ func (s Service) createInRepo(ctx context.Context, scopeId string, item *pb.Role
 	if err != nil {
 		return nil, err
 	}
+	if template != "" {
+		out, grants, err := repo.CreateRoleFromTemplate(ctx, scopeId, template, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create role from template: %w", err)
+		}
+		return toProto(out, nil, grants), nil
+	}
 	out, err := repo.CreateRole(ctx, u)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create role: %w", err)
@@ -283,6 +339,25 @@ func (s Service) createInRepo(ctx context.Context, scopeId string, item *pb.Role
 	return toProto(out, nil, nil), nil
 }
 
+func (s Service) cloneInRepo(ctx context.Context, id string, name, description *wrapperspb.StringValue) (*pb.Role, error) {
+	var opts []iam.Option
+	if name != nil {
+		opts = append(opts, iam.WithName(name.GetValue()))
+	}
+	if description != nil {
+		opts = append(opts, iam.WithDescription(description.GetValue()))
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	out, grants, err := repo.CloneRole(ctx, id, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone role: %w", err)
+	}
+	return toProto(out, nil, grants), nil
+}
+
 func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []string, item *pb.Role) (*pb.Role, error) {
 	var opts []iam.Option
 	if desc := item.GetDescription(); desc != nil {
@@ -334,12 +409,12 @@ func (s Service) deleteFromRepo(ctx context.Context, id string) (bool, error) {
 	return rows > 0, nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Role, error) {
+func (s Service) listFromRepo(ctx context.Context, scopeId string, opt ...iam.Option) ([]*pb.Role, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	rl, err := repo.ListRoles(ctx, scopeId)
+	rl, err := repo.ListRoles(ctx, scopeId, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -596,6 +671,11 @@ func validateCreateRequest(req *pbs.CreateRoleRequest) error {
 		if item.GetGrants() != nil {
 			badFields["grant_strings"] = "This is a read only field."
 		}
+		if t := req.GetTemplate(); t != "" {
+			if _, ok := iam.LookupRoleTemplate(t); !ok {
+				badFields["template"] = "Unknown role template."
+			}
+		}
 		return badFields
 	})
 }
@@ -643,6 +723,17 @@ func validateListRequest(req *pbs.ListRolesRequest) error {
 	return nil
 }
 
+func validateCloneRoleRequest(req *pbs.CloneRoleRequest) error {
+	badFields := map[string]string{}
+	if !handlers.ValidId(iam.RolePrefix, req.GetId()) {
+		badFields["id"] = "Incorrectly formatted identifier."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Errors in provided fields.", badFields)
+	}
+	return nil
+}
+
 func validateAddRolePrincipalsRequest(req *pbs.AddRolePrincipalsRequest) error {
 	badFields := map[string]string{}
 	if !handlers.ValidId(iam.RolePrefix, req.GetId()) {
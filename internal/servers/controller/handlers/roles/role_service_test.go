@@ -494,6 +494,82 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreate_template(t *testing.T) {
+	_, defaultProjRole, repo := createDefaultRolesAndRepo(t)
+	s, err := roles.NewService(repo)
+	require.NoError(t, err, "Error when getting new role service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(defaultProjRole.GetScopeId()))
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := s.CreateRole(ctx, &pbs.CreateRoleRequest{
+			Item:     &pb.Role{ScopeId: defaultProjRole.GetScopeId()},
+			Template: "session-manager",
+		})
+		require.NoError(err)
+		tmpl, ok := iam.LookupRoleTemplate("session-manager")
+		require.True(ok)
+		assert.Equal(tmpl.RoleName, got.GetItem().GetName().GetValue())
+		assert.Equal(tmpl.Description, got.GetItem().GetDescription().GetValue())
+		assert.ElementsMatch(tmpl.Grants, got.GetItem().GetGrantStrings())
+	})
+	t.Run("named", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := s.CreateRole(ctx, &pbs.CreateRoleRequest{
+			Item:     &pb.Role{ScopeId: defaultProjRole.GetScopeId(), Name: &wrapperspb.StringValue{Value: "auditors"}},
+			Template: "auditor",
+		})
+		require.NoError(err)
+		assert.Equal("auditors", got.GetItem().GetName().GetValue())
+	})
+	t.Run("unknown", func(t *testing.T) {
+		_, err := s.CreateRole(ctx, &pbs.CreateRoleRequest{
+			Item:     &pb.Role{ScopeId: defaultProjRole.GetScopeId()},
+			Template: "superuser",
+		})
+		assert.True(t, errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v", err)
+	})
+}
+
+func TestClone(t *testing.T) {
+	defaultOrgRole, _, repo := createDefaultRolesAndRepo(t)
+	iamRepo, err := repo()
+	require.NoError(t, err)
+	_, err = iamRepo.AddRoleGrants(context.Background(), defaultOrgRole.GetPublicId(), defaultOrgRole.GetVersion(), []string{"id=*;type=target;actions=read"})
+	require.NoError(t, err)
+	s, err := roles.NewService(repo)
+	require.NoError(t, err, "Error when getting new role service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(defaultOrgRole.GetScopeId()))
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := s.CloneRole(ctx, &pbs.CloneRoleRequest{
+			Id:   defaultOrgRole.GetPublicId(),
+			Name: &wrapperspb.StringValue{Value: "copy"},
+		})
+		require.NoError(err)
+		item := got.GetItem()
+		assert.NotEqual(defaultOrgRole.GetPublicId(), item.GetId())
+		assert.Equal(defaultOrgRole.GetScopeId(), item.GetScopeId())
+		assert.Equal("copy", item.GetName().GetValue())
+		assert.Equal(defaultOrgRole.GetDescription(), item.GetDescription().GetValue())
+		assert.Equal(defaultOrgRole.GetGrantScopeId(), item.GetGrantScopeId().GetValue())
+		assert.Equal([]string{"id=*;type=target;actions=read"}, item.GetGrantStrings())
+		assert.Empty(item.GetPrincipalIds())
+	})
+	t.Run("duplicate-name", func(t *testing.T) {
+		_, err := s.CloneRole(ctx, &pbs.CloneRoleRequest{
+			Id:   defaultOrgRole.GetPublicId(),
+			Name: &wrapperspb.StringValue{Value: defaultOrgRole.GetName()},
+		})
+		assert.Error(t, err)
+	})
+	t.Run("bad-id", func(t *testing.T) {
+		_, err := s.CloneRole(ctx, &pbs.CloneRoleRequest{Id: "bad_id"})
+		assert.True(t, errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v", err)
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	grantString := "id=*;type=*;actions=*"
 	g, err := perms.Parse("global", grantString)
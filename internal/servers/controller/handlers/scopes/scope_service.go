@@ -2,22 +2,26 @@ package scopes
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/quota"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/types/action"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -55,20 +59,44 @@ func (s Service) ListScopes(ctx context.Context, req *pbs.ListScopesRequest) (*p
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	pl, err := s.listFromRepo(ctx, authResults.Scope.GetId())
+	// Narrow the listing to the name the filter requires, if any
+	opts := []iam.Option{iam.WithOrder(order)}
+	if name, ok := filter.Equality("/item/name"); ok {
+		opts = append(opts, iam.WithName(name))
+	}
+	pl, err := s.listFromRepo(ctx, authResults.Scope.GetId(), opts...)
 	if err != nil {
 		return nil, err
 	}
+	if order == "" {
+		SortScopes(pl)
+	}
 
+	var finalItems []*pb.Scope
 	for _, item := range pl {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
 	}
 
-	return &pbs.ListScopesResponse{Items: pl}, nil
+	return &pbs.ListScopesResponse{Items: finalItems}, nil
 }
 
 // GetScopes implements the interface pbs.ScopeServiceServer.
@@ -138,6 +166,52 @@ func (s Service) DeleteScope(ctx context.Context, req *pbs.DeleteScopeRequest) (
 	return &pbs.DeleteScopeResponse{}, nil
 }
 
+// ListAuthorizedResources implements the interface pbs.ScopeServiceServer.
+func (s Service) ListAuthorizedResources(ctx context.Context, req *pbs.ListAuthorizedResourcesRequest) (*pbs.ListAuthorizedResourcesResponse, error) {
+	if err := validateListAuthorizedResourcesRequest(req); err != nil {
+		return nil, err
+	}
+	typ, act := resource.Map[req.GetType()], action.Map[req.GetAction()]
+	authResults := auth.Verify(ctx, auth.WithScopeId(req.GetId()), auth.WithType(typ), auth.WithAction(action.List))
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	scopeId := authResults.Scope.GetId()
+	ids, err := repo.ListAuthorizedResources(ctx, authResults.UserId, scopeId, typ, authResults.ResourcePermissions(scopeId, typ, act))
+	switch {
+	case errors.Is(err, db.ErrInvalidParameter):
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.",
+			map[string]string{"type": "Resources of this type are not listed within a scope."})
+	case err != nil:
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to list authorized resources: %v", err)
+	}
+	return &pbs.ListAuthorizedResourcesResponse{Ids: ids}, nil
+}
+
+// GetQuotaUsage implements the interface pbs.ScopeServiceServer.
+func (s Service) GetQuotaUsage(ctx context.Context, req *pbs.GetQuotaUsageRequest) (*pbs.GetQuotaUsageResponse, error) {
+	if err := validateGetQuotaUsageRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.ReadQuotaUsage)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	quotas := authResults.Quotas()
+	if quotas == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "Quotas are not enabled on this controller.")
+	}
+	u, err := quotas.Usage(ctx, req.GetId())
+	if err != nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to read quota usage: %v", err)
+	}
+	return &pbs.GetQuotaUsageResponse{Item: quotaUsageToProto(u)}, nil
+}
+
 func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Scope, error) {
 	repo, err := s.repoFn()
 	if err != nil {
@@ -162,7 +236,6 @@ func (s Service) createInRepo(ctx context.Context, authResults auth.VerifyResult
 	if item.GetDescription() != nil {
 		opts = append(opts, iam.WithDescription(item.GetDescription().GetValue()))
 	}
-	opts = append(opts, iam.WithSkipAdminRoleCreation(req.GetSkipAdminRoleCreation()))
 	opts = append(opts, iam.WithSkipDefaultRoleCreation(req.GetSkipDefaultRoleCreation()))
 
 	parentScope := authResults.Scope
@@ -181,7 +254,18 @@ func (s Service) createInRepo(ctx context.Context, authResults auth.VerifyResult
 	if err != nil {
 		return nil, err
 	}
-	out, err := repo.CreateScope(ctx, iamScope, authResults.UserId, opts...)
+	var out *iam.Scope
+	switch authResults.UserId {
+	case "", "u_anon", "u_auth", "u_recovery":
+		// These users are never made admins of the scopes they create
+		out, err = repo.CreateScope(ctx, iamScope, authResults.UserId, opts...)
+	default:
+		if req.GetSkipAdminRoleCreation() {
+			out, err = repo.CreateScope(ctx, iamScope, authResults.UserId, append(opts, iam.WithSkipAdminRoleCreation(true))...)
+		} else {
+			out, err = repo.CreateScopeWithAdmin(ctx, iamScope, authResults.UserId, opts...)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to create scope: %w", err)
 	}
@@ -213,6 +297,7 @@ func (s Service) updateInRepo(ctx context.Context, parentScope *scopes.ScopeInfo
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build scope for update: %v.", err)
 	}
 	iamScope.PublicId = scopeId
+	iamScope.ReadOnly = item.GetReadOnly().GetValue()
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
 		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
@@ -251,7 +336,7 @@ func SortScopes(scps []*pb.Scope) {
 	})
 }
 
-func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Scope, error) {
+func (s Service) listFromRepo(ctx context.Context, scopeId string, opt ...iam.Option) ([]*pb.Scope, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
@@ -260,9 +345,9 @@ func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Scope,
 	var scps []*iam.Scope
 	switch {
 	case scopeId == "global":
-		scps, err = repo.ListOrgs(ctx)
+		scps, err = repo.ListOrgs(ctx, opt...)
 	case strings.HasPrefix(scopeId, scope.Org.Prefix()):
-		scps, err = repo.ListProjects(ctx, scopeId)
+		scps, err = repo.ListProjects(ctx, scopeId, opt...)
 	default:
 		return nil, handlers.InvalidArgumentErrorf("Error in provided request.",
 			map[string]string{"scope_id": "This field must be 'global' or a valid org scope id."})
@@ -275,7 +360,6 @@ func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Scope,
 	for _, scp := range scps {
 		outPl = append(outPl, ToProto(scp))
 	}
-	SortScopes(outPl)
 	return outPl, nil
 }
 
@@ -333,9 +417,24 @@ func ToProto(in *iam.Scope) *pb.Scope {
 	if in.GetName() != "" {
 		out.Name = &wrapperspb.StringValue{Value: in.GetName()}
 	}
+	if in.GetReadOnly() {
+		out.ReadOnly = wrapperspb.Bool(true)
+	}
 	return &out
 }
 
+func quotaUsageToProto(in *quota.Usage) *pb.QuotaUsage {
+	return &pb.QuotaUsage{
+		ScopeId:           in.ScopeId,
+		PeriodStart:       timestamppb.New(in.PeriodStart),
+		PeriodEnd:         timestamppb.New(in.PeriodEnd),
+		Requests:          in.Requests,
+		MaxRequests:       in.MaxRequests,
+		ActiveSessions:    in.ActiveSessions,
+		MaxActiveSessions: in.MaxActiveSessions,
+	}
+}
+
 // A validateX method should exist for each method above.  These methods do not make calls to any backing service but enforce
 // requirements on the structure of the request.  They verify that:
 //  * The path passed in is correctly formatted
@@ -363,6 +462,36 @@ func validateGetRequest(req *pbs.GetScopeRequest) error {
 	return nil
 }
 
+func validateListAuthorizedResourcesRequest(req *pbs.ListAuthorizedResourcesRequest) error {
+	badFields := map[string]string{}
+	if err := validateGetRequest(&pbs.GetScopeRequest{Id: req.GetId()}); err != nil {
+		badFields["id"] = "Invalidly formatted scope id."
+	}
+	switch resource.Map[req.GetType()] {
+	case resource.Unknown, resource.All:
+		badFields["type"] = "Must be a single type of resource."
+	}
+	switch action.Map[req.GetAction()] {
+	case action.Unknown, action.All, action.List, action.Create, action.ReadSelf, action.CancelSelf, action.DeleteSelf:
+		badFields["action"] = "Must be a single action performed on existing resources."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
+
+func validateGetQuotaUsageRequest(req *pbs.GetQuotaUsageRequest) error {
+	badFields := map[string]string{}
+	if !handlers.ValidId(scope.Org.Prefix(), req.GetId()) {
+		badFields["id"] = "Quotas are only kept for org scopes."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
+
 func validateCreateRequest(req *pbs.CreateScopeRequest) error {
 	badFields := map[string]string{}
 	item := req.GetItem()
@@ -393,6 +522,9 @@ func validateCreateRequest(req *pbs.CreateScopeRequest) error {
 	if item.GetVersion() != 0 {
 		badFields["version"] = "This cannot be specified at create time."
 	}
+	if item.GetReadOnly() != nil {
+		badFields["read_only"] = "This cannot be specified at create time."
+	}
 	if len(badFields) > 0 {
 		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
 	}
@@ -424,6 +556,9 @@ func validateUpdateRequest(req *pbs.UpdateScopeRequest) error {
 	if req.GetUpdateMask() == nil {
 		badFields["update_mask"] = "UpdateMask not provided but is required to update a project."
 	}
+	if id == "global" && handlers.MaskContains(req.GetUpdateMask().GetPaths(), "read_only") {
+		badFields["read_only"] = "The global scope cannot be read only."
+	}
 
 	item := req.GetItem()
 	if item == nil {
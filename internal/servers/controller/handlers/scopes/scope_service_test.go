@@ -1014,3 +1014,96 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestListAuthorizedResources(t *testing.T) {
+	org, proj, repoFn := createDefaultScopesAndRepo(t)
+	s, err := scopes.NewService(repoFn)
+	require.NoError(t, err, "Error when getting new scopes service")
+
+	cases := []struct {
+		name string
+		req  *pbs.ListAuthorizedResourcesRequest
+		res  *pbs.ListAuthorizedResourcesResponse
+		err  error
+	}{
+		{
+			name: "Projects in an org",
+			req:  &pbs.ListAuthorizedResourcesRequest{Id: org.GetPublicId(), Type: "scope", Action: "read"},
+			res:  &pbs.ListAuthorizedResourcesResponse{Ids: []string{proj.GetPublicId()}},
+		},
+		{
+			name: "No targets in a project",
+			req:  &pbs.ListAuthorizedResourcesRequest{Id: proj.GetPublicId(), Type: "target", Action: "authorize-session"},
+			res:  &pbs.ListAuthorizedResourcesResponse{},
+		},
+		{
+			name: "Bad scope id",
+			req:  &pbs.ListAuthorizedResourcesRequest{Id: "j_1234567890", Type: "target", Action: "read"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Wildcard type",
+			req:  &pbs.ListAuthorizedResourcesRequest{Id: proj.GetPublicId(), Type: "*", Action: "read"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Collection action",
+			req:  &pbs.ListAuthorizedResourcesRequest{Id: proj.GetPublicId(), Type: "target", Action: "list"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Type not listed in a scope",
+			req:  &pbs.ListAuthorizedResourcesRequest{Id: proj.GetPublicId(), Type: "host", Action: "read"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, gErr := s.ListAuthorizedResources(auth.DisabledAuthTestContext(auth.WithScopeId(tc.req.GetId())), tc.req)
+			if tc.err != nil {
+				require.Error(gErr)
+				assert.True(errors.Is(gErr, tc.err), "ListAuthorizedResources(%+v) got error %v, wanted %v", tc.req, gErr, tc.err)
+				return
+			}
+			require.NoError(gErr)
+			assert.Empty(cmp.Diff(got, tc.res, protocmp.Transform()))
+		})
+	}
+}
+
+func TestGetQuotaUsage(t *testing.T) {
+	org, proj, repoFn := createDefaultScopesAndRepo(t)
+	s, err := scopes.NewService(repoFn)
+	require.NoError(t, err, "Error when getting new scopes service")
+
+	cases := []struct {
+		name string
+		req  *pbs.GetQuotaUsageRequest
+		err  error
+	}{
+		{
+			name: "Quotas not enabled",
+			req:  &pbs.GetQuotaUsageRequest{Id: org.GetPublicId()},
+			err:  handlers.ApiErrorWithCode(codes.FailedPrecondition),
+		},
+		{
+			name: "Project scope",
+			req:  &pbs.GetQuotaUsageRequest{Id: proj.GetPublicId()},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Global scope",
+			req:  &pbs.GetQuotaUsageRequest{Id: "global"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			_, gErr := s.GetQuotaUsage(auth.DisabledAuthTestContext(auth.WithScopeId("global")), tc.req)
+			require.Error(gErr)
+			assert.True(errors.Is(gErr, tc.err), "GetQuotaUsage(%+v) got error %v, wanted %v", tc.req, gErr, tc.err)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/search"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/search"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/grpc/codes"
+)
+
+// maxQueryLength is the longest query which can be searched for.
+const maxQueryLength = 256
+
+// Service handles requests as described by the pbs.SearchServiceServer interface.
+type Service struct {
+	repoFn    common.SearchRepoFactory
+	iamRepoFn common.IamRepoFactory
+}
+
+// NewService returns a search service which handles search related requests to boundary.
+func NewService(repoFn common.SearchRepoFactory, iamRepoFn common.IamRepoFactory) (Service, error) {
+	if repoFn == nil {
+		return Service{}, fmt.Errorf("nil search repository provided")
+	}
+	if iamRepoFn == nil {
+		return Service{}, fmt.Errorf("nil iam repository provided")
+	}
+	return Service{repoFn: repoFn, iamRepoFn: iamRepoFn}, nil
+}
+
+var _ pbs.SearchServiceServer = Service{}
+
+// Search implements the interface pbs.SearchServiceServer.
+func (s Service) Search(ctx context.Context, req *pbs.SearchRequest) (*pbs.SearchResponse, error) {
+	if req.GetScopeId() == "" {
+		req.ScopeId = scope.Global.String()
+	}
+	if err := validateSearchRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := auth.Verify(ctx, auth.WithScopeId(req.GetScopeId()), auth.WithType(resource.Scope), auth.WithAction(action.List))
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+
+	scps, err := s.searchedScopes(ctx, authResults.Scope, req.GetRecursive())
+	if err != nil {
+		return nil, err
+	}
+	types := search.Types
+	if len(req.GetTypes()) > 0 {
+		types = nil
+		for _, t := range req.GetTypes() {
+			types = append(types, resource.Map[t])
+		}
+	}
+	var permissions []search.Permission
+	for _, scp := range scps {
+		for _, typ := range types {
+			permissions = append(permissions, search.Permission{
+				ScopeId:   scp.GetId(),
+				Type:      typ,
+				Resources: authResults.ResourcePermissions(scp.GetId(), typ, action.Read),
+			})
+		}
+	}
+
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	results, err := repo.Search(ctx, req.GetQuery(), authResults.UserId, permissions)
+	switch {
+	case errors.Is(err, db.ErrInvalidParameter):
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"types": "Resources of these types can't be searched for."})
+	case err != nil:
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to search: %v", err)
+	}
+
+	scopeInfos := make(map[string]*scopes.ScopeInfo, len(scps))
+	for _, scp := range scps {
+		scopeInfos[scp.GetId()] = scp
+	}
+	items := make([]*pb.Result, 0, len(results))
+	for _, r := range results {
+		items = append(items, &pb.Result{
+			Id:          r.PublicId,
+			Type:        r.Type.String(),
+			Scope:       scopeInfos[r.ScopeId],
+			Name:        r.Name,
+			Description: r.Description,
+			Address:     r.Address,
+			Rank:        uint32(r.Rank),
+		})
+	}
+	return &pbs.SearchResponse{Items: items}, nil
+}
+
+// searchedScopes returns the scope being searched and, if recursive, the
+// scopes within it.
+func (s Service) searchedScopes(ctx context.Context, root *scopes.ScopeInfo, recursive bool) ([]*scopes.ScopeInfo, error) {
+	ret := []*scopes.ScopeInfo{root}
+	if !recursive {
+		return ret, nil
+	}
+	repo, err := s.iamRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	var parentIds []string
+	switch root.GetType() {
+	case scope.Global.String():
+		orgs, err := repo.ListOrgs(ctx, iam.WithLimit(-1))
+		if err != nil {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to list scopes: %v", err)
+		}
+		for _, o := range orgs {
+			ret = append(ret, toScopeInfo(o))
+			parentIds = append(parentIds, o.GetPublicId())
+		}
+	case scope.Org.String():
+		parentIds = append(parentIds, root.GetId())
+	}
+	for _, id := range parentIds {
+		projs, err := repo.ListProjects(ctx, id, iam.WithLimit(-1))
+		if err != nil {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to list scopes: %v", err)
+		}
+		for _, p := range projs {
+			ret = append(ret, toScopeInfo(p))
+		}
+	}
+	return ret, nil
+}
+
+func toScopeInfo(in *iam.Scope) *scopes.ScopeInfo {
+	return &scopes.ScopeInfo{
+		Id:            in.GetPublicId(),
+		Type:          in.GetType(),
+		Name:          in.GetName(),
+		Description:   in.GetDescription(),
+		ParentScopeId: in.GetParentId(),
+	}
+}
+
+func validateSearchRequest(req *pbs.SearchRequest) error {
+	badFields := map[string]string{}
+	if !handlers.ValidId(scope.Org.Prefix(), req.GetScopeId()) &&
+		!handlers.ValidId(scope.Project.Prefix(), req.GetScopeId()) &&
+		req.GetScopeId() != scope.Global.String() {
+		badFields["scope_id"] = "Incorrectly formatted identifier."
+	}
+	switch {
+	case req.GetQuery() == "":
+		badFields["query"] = "This is a required field."
+	case len(req.GetQuery()) > maxQueryLength:
+		badFields["query"] = fmt.Sprintf("Must be at most %d characters long.", maxQueryLength)
+	}
+	for _, t := range req.GetTypes() {
+		if !search.Searchable(resource.Map[t]) {
+			badFields["types"] = fmt.Sprintf("Resources of type %q can't be searched for.", t)
+			break
+		}
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
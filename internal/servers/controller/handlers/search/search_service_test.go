@@ -0,0 +1,126 @@
+package search_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/search"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/search"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	handler "github.com/hashicorp/boundary/internal/servers/controller/handlers/search"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestNewService(t *testing.T) {
+	repoFn := func() (*search.Repository, error) { return nil, nil }
+	iamRepoFn := func() (*iam.Repository, error) { return nil, nil }
+
+	_, err := handler.NewService(repoFn, iamRepoFn)
+	assert.NoError(t, err)
+	_, err = handler.NewService(nil, iamRepoFn)
+	assert.Error(t, err)
+	_, err = handler.NewService(repoFn, nil)
+	assert.Error(t, err)
+}
+
+func TestSearch(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iamRepo, nil
+	}
+	repoFn := func() (*search.Repository, error) {
+		return search.NewRepository(rw)
+	}
+	s, err := handler.NewService(repoFn, iamRepoFn)
+	require.NoError(t, err)
+
+	org, proj := iam.TestScopes(t, iamRepo)
+	web := target.TestTcpTarget(t, conn, proj.PublicId, "web")
+	prodWeb := target.TestTcpTarget(t, conn, proj.PublicId, "prod-web")
+	user := iam.TestUser(t, iamRepo, org.PublicId, iam.WithName("web admin"))
+
+	orgInfo := &scopes.ScopeInfo{Id: org.GetPublicId(), Type: scope.Org.String(), ParentScopeId: scope.Global.String()}
+	projInfo := &scopes.ScopeInfo{Id: proj.GetPublicId(), Type: scope.Project.String(), ParentScopeId: org.GetPublicId()}
+	webResult := &pb.Result{Id: web.GetPublicId(), Type: "target", Scope: projInfo, Name: "web", Rank: 2}
+	prodWebResult := &pb.Result{Id: prodWeb.GetPublicId(), Type: "target", Scope: projInfo, Name: "prod-web", Rank: 4}
+	userResult := &pb.Result{Id: user.GetPublicId(), Type: "user", Scope: orgInfo, Name: "web admin", Rank: 3}
+
+	cases := []struct {
+		name string
+		req  *pbs.SearchRequest
+		res  *pbs.SearchResponse
+		err  error
+	}{
+		{
+			name: "Project",
+			req:  &pbs.SearchRequest{ScopeId: proj.GetPublicId(), Query: "web"},
+			res:  &pbs.SearchResponse{Items: []*pb.Result{withScope(webResult, proj.GetPublicId()), withScope(prodWebResult, proj.GetPublicId())}},
+		},
+		{
+			name: "Org recursively",
+			req:  &pbs.SearchRequest{ScopeId: org.GetPublicId(), Query: "web", Recursive: true},
+			res:  &pbs.SearchResponse{Items: []*pb.Result{webResult, withScope(userResult, org.GetPublicId()), prodWebResult}},
+		},
+		{
+			name: "Org recursively by type",
+			req:  &pbs.SearchRequest{ScopeId: org.GetPublicId(), Query: "web", Types: []string{"user"}, Recursive: true},
+			res:  &pbs.SearchResponse{Items: []*pb.Result{withScope(userResult, org.GetPublicId())}},
+		},
+		{
+			name: "No matches",
+			req:  &pbs.SearchRequest{ScopeId: proj.GetPublicId(), Query: "database"},
+			res:  &pbs.SearchResponse{Items: []*pb.Result{}},
+		},
+		{
+			name: "Missing query",
+			req:  &pbs.SearchRequest{ScopeId: proj.GetPublicId()},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Bad scope id",
+			req:  &pbs.SearchRequest{ScopeId: "j_1234567890", Query: "web"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Unsearchable type",
+			req:  &pbs.SearchRequest{ScopeId: proj.GetPublicId(), Query: "web", Types: []string{"role"}},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, gErr := s.Search(auth.DisabledAuthTestContext(auth.WithScopeId(tc.req.GetScopeId())), tc.req)
+			if tc.err != nil {
+				require.Error(gErr)
+				assert.True(errors.Is(gErr, tc.err), "Search(%+v) got error %v, wanted %v", tc.req, gErr, tc.err)
+				return
+			}
+			require.NoError(gErr)
+			assert.Empty(cmp.Diff(got, tc.res, protocmp.Transform()))
+		})
+	}
+}
+
+// withScope returns r with the scope info of a searched scope when auth is
+// disabled, which only has the scope's id and type.
+func withScope(r *pb.Result, scopeId string) *pb.Result {
+	ret := proto.Clone(r).(*pb.Result)
+	ret.Scope = &scopes.ScopeInfo{Id: scopeId, Type: r.GetScope().GetType()}
+	return ret
+}
@@ -16,8 +16,25 @@ import (
 	"github.com/hashicorp/boundary/internal/types/action"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
+func init() {
+	if err := handlers.RegisterCustomActions(resource.Session, pbs.File_controller_api_services_v1_session_service_proto.Services().ByName("SessionService"), map[action.Type]string{
+		action.Cancel: "CancelSession",
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// sortColumns are the fields listed sessions can be sorted by. Sessions are
+// sorted by status alphabetically.
+var sortColumns = handlers.SortColumns{
+	"created_time": "s.create_time",
+	"status":       "ss.state",
+}
+
 // Service handles request as described by the pbs.SessionServiceServer interface.
 type Service struct {
 	repoFn    common.SessionRepoFactory
@@ -46,10 +63,17 @@ func (s Service) GetSession(ctx context.Context, req *pbs.GetSessionRequest) (*p
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	ses, err := s.getFromRepo(ctx, req.GetId())
+	// Only callers who can read the whole session need its certificate
+	ses, err := s.getFromRepo(ctx, req.GetId(), session.WithDecryptedCertificate(!authResults.OnlySelf))
 	if err != nil {
 		return nil, err
 	}
+	if ses.Connections, err = s.connectionsFromRepo(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	if authResults.OnlySelf {
+		trimSession(ses)
+	}
 	ses.Scope = authResults.Scope
 	return &pbs.GetSessionResponse{Item: ses}, nil
 }
@@ -59,18 +83,95 @@ func (s Service) ListSessions(ctx context.Context, req *pbs.ListSessionsRequest)
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), sortColumns)
+	if err != nil {
+		return nil, err
+	}
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	seslist, err := s.listFromRepo(ctx, authResults.Scope.GetId())
+	opts := []session.Option{session.WithOrder(order)}
+	// Narrow the listing to the status the filter requires, if any
+	if status, ok := filter.Equality("/item/status"); ok {
+		opts = append(opts, session.WithStatus(session.Status(status)))
+	}
+	if clientIp, ok := filter.Equality("/item/client_ip"); ok {
+		opts = append(opts, session.WithClientIp(clientIp))
+	}
+	// Callers who can only read their own sessions only list their own
+	readAll := authResults.AdditionalVerification(ctx,
+		auth.WithType(resource.Session),
+		auth.WithScopeId(authResults.Scope.GetId()),
+		auth.WithAction(action.Read),
+		auth.WithOwnerId(authResults.UserId))
+	if readAll.Error == nil && readAll.OnlySelf {
+		opts = append(opts, session.WithUserId(authResults.UserId))
+	}
+	seslist, err := s.listFromRepo(ctx, authResults.Scope.GetId(), opts...)
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.Session
 	for _, item := range seslist {
+		// Sessions the caller can't read are left out, and sessions they can
+		// only read as their own are returned without the details needed to
+		// connect to them
+		read := authResults.AdditionalVerification(ctx,
+			auth.WithType(resource.Session),
+			auth.WithScopeId(authResults.Scope.GetId()),
+			auth.WithId(item.GetId()),
+			auth.WithAction(action.Read),
+			auth.WithOwnerId(item.GetUserId()))
+		if read.Error != nil {
+			continue
+		}
+		if read.OnlySelf {
+			trimSession(item)
+		}
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
+	}
+	return &pbs.ListSessionsResponse{Items: finalItems}, nil
+}
+
+// CountSessions implements the interface pbs.SessionServiceServer. Counting
+// is authorized by the list action since the counts reveal no more than a
+// listing does.
+func (s Service) CountSessions(ctx context.Context, req *pbs.CountSessionsRequest) (*pbs.CountSessionsResponse, error) {
+	if err := validateCountRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	// Callers who can only read their own sessions only count their own.
+	// Callers who can only read some sessions by id can't be given counts
+	// without checking each session, which is what listing them does.
+	readAll := authResults.AdditionalVerification(ctx,
+		auth.WithType(resource.Session),
+		auth.WithScopeId(authResults.Scope.GetId()),
+		auth.WithAction(action.Read),
+		auth.WithOwnerId(authResults.UserId))
+	if readAll.Error != nil {
+		return nil, readAll.Error
+	}
+	var opts []session.Option
+	if readAll.OnlySelf {
+		opts = append(opts, session.WithUserId(authResults.UserId))
 	}
-	return &pbs.ListSessionsResponse{Items: seslist}, nil
+	return s.countFromRepo(ctx, authResults.Scope.GetId(), opts...)
 }
 
 // CancelSession implements the interface pbs.SessionServiceServer.
@@ -90,12 +191,12 @@ func (s Service) CancelSession(ctx context.Context, req *pbs.CancelSessionReques
 	return &pbs.CancelSessionResponse{Item: ses}, nil
 }
 
-func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Session, error) {
+func (s Service) getFromRepo(ctx context.Context, id string, opt ...session.Option) (*pb.Session, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	sess, _, err := repo.LookupSession(ctx, id)
+	sess, _, err := repo.LookupSession(ctx, id, opt...)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
 			return nil, handlers.NotFoundErrorf("Session %q doesn't exist.", id)
@@ -108,12 +209,32 @@ func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Session, error
 	return toProto(sess), nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Session, error) {
+func (s Service) connectionsFromRepo(ctx context.Context, sessionId string) ([]*pb.Connection, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	seslist, err := repo.ListSessions(ctx, session.WithScopeId(scopeId))
+	conns, err := repo.ListConnections(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	var out []*pb.Connection
+	for _, c := range conns {
+		pbConn, err := connectionToProto(c)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, pbConn)
+	}
+	return out, nil
+}
+
+func (s Service) listFromRepo(ctx context.Context, scopeId string, opt ...session.Option) ([]*pb.Session, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	seslist, err := repo.ListSessions(ctx, append(opt, session.WithScopeId(scopeId))...)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +245,25 @@ func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Sessio
 	return outSl, nil
 }
 
+func (s Service) countFromRepo(ctx context.Context, scopeId string, opt ...session.Option) (*pbs.CountSessionsResponse, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := repo.CountSessions(ctx, scopeId, opt...)
+	if err != nil {
+		return nil, err
+	}
+	res := &pbs.CountSessionsResponse{
+		Pending:    uint64(counts[session.StatusPending]),
+		Active:     uint64(counts[session.StatusActive]),
+		Canceling:  uint64(counts[session.StatusCanceling]),
+		Terminated: uint64(counts[session.StatusTerminated]),
+	}
+	res.Total = res.GetPending() + res.GetActive() + res.GetCanceling() + res.GetTerminated()
+	return res, nil
+}
+
 func (s Service) cancelInRepo(ctx context.Context, id string, version uint32) (*pb.Session, error) {
 	repo, err := s.repoFn()
 	if err != nil {
@@ -174,7 +314,7 @@ func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.
 			return res
 		}
 		parentId = t.ScopeId
-		opts = append(opts, auth.WithId(id))
+		opts = append(opts, auth.WithId(id), auth.WithOwnerId(t.UserId))
 	default:
 		res.Error = errors.New("unsupported action")
 		return res
@@ -197,11 +337,15 @@ func toProto(in *session.Session) *pb.Session {
 		Type:        target.SubtypeFromId(in.TargetId).String(),
 		// TODO: Provide the ServerType and the ServerId when that information becomes relevant in the API.
 
-		CreatedTime:    in.CreateTime.GetTimestamp(),
-		UpdatedTime:    in.UpdateTime.GetTimestamp(),
-		ExpirationTime: in.ExpirationTime.GetTimestamp(),
-		Certificate:    in.Certificate,
-		TerminationReason: in.TerminationReason,
+		CreatedTime:         in.CreateTime.GetTimestamp(),
+		UpdatedTime:         in.UpdateTime.GetTimestamp(),
+		ExpirationTime:      in.ExpirationTime.GetTimestamp(),
+		Certificate:         in.Certificate,
+		TerminationReason:   in.TerminationReason,
+		ClientIp:            in.ClientIp,
+		UserAgent:           in.UserAgent,
+		HostAddress:         in.HostAddress,
+		HostSelectionReason: in.HostSelectionReason,
 	}
 	if len(in.States) > 0 {
 		out.Status = in.States[0].Status.String()
@@ -221,6 +365,40 @@ func toProto(in *session.Session) *pb.Session {
 	return &out
 }
 
+func connectionToProto(in *session.Connection) (*pb.Connection, error) {
+	out := &pb.Connection{
+		Id:                 in.GetPublicId(),
+		ClientTcpAddress:   in.ClientTcpAddress,
+		ClientTcpPort:      in.ClientTcpPort,
+		EndpointTcpAddress: in.EndpointTcpAddress,
+		EndpointTcpPort:    in.EndpointTcpPort,
+		BytesUp:            in.BytesUp,
+		BytesDown:          in.BytesDown,
+		DatagramsUp:        in.DatagramsUp,
+		DatagramsDown:      in.DatagramsDown,
+		ClosedReason:       in.ClosedReason,
+		CreatedTime:        in.CreateTime.GetTimestamp(),
+		UpdatedTime:        in.UpdateTime.GetTimestamp(),
+		LastActivityTime:   in.LastActivityTime.GetTimestamp(),
+		Protocol:           in.Protocol,
+	}
+	if in.ProtocolMetadata != "" {
+		out.ProtocolMetadata = &structpb.Struct{}
+		if err := protojson.Unmarshal([]byte(in.ProtocolMetadata), out.ProtocolMetadata); err != nil {
+			return nil, fmt.Errorf("unable to parse protocol metadata of connection %q: %w", in.GetPublicId(), err)
+		}
+	}
+	return out, nil
+}
+
+// trimSession removes the fields of a session which aren't returned to callers
+// who can only read it because it is their own.
+func trimSession(in *pb.Session) {
+	in.AuthTokenId = ""
+	in.Certificate = nil
+	in.WorkerInfo = nil
+}
+
 // A validateX method should exist for each method above.  These methods do not make calls to any backing service but enforce
 // requirements on the structure of the request.  They verify that:
 //  * The path passed in is correctly formatted
@@ -241,6 +419,17 @@ func validateListRequest(req *pbs.ListSessionsRequest) error {
 	return nil
 }
 
+func validateCountRequest(req *pbs.CountSessionsRequest) error {
+	badFields := map[string]string{}
+	if !handlers.ValidId(scope.Project.Prefix(), req.GetScopeId()) {
+		badFields["scope_id"] = "This field is required to have a properly formatted project scope id."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Improperly formatted identifier.", badFields)
+	}
+	return nil
+}
+
 func validateCancelRequest(req *pbs.CancelSessionRequest) error {
 	badFields := map[string]string{}
 	if !handlers.ValidId(session.SessionPrefix, req.GetId()) {
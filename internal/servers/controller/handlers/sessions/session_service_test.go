@@ -1,6 +1,7 @@
 package sessions_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -62,6 +63,9 @@ func TestGetSession(t *testing.T) {
 		ScopeId:     p.GetPublicId(),
 		Endpoint:    "tcp://127.0.0.1:22",
 	})
+	c := session.TestConnection(t, conn, sess.GetPublicId(), "127.0.0.1", 22, "127.0.0.2", 23)
+	c, _, err = sessRepo.LookupConnection(context.Background(), c.GetPublicId())
+	require.NoError(t, err)
 
 	wireSess := &pb.Session{
 		Id:             sess.GetPublicId(),
@@ -81,6 +85,15 @@ func TestGetSession(t *testing.T) {
 		States:         []*pb.SessionState{{Status: session.StatusPending.String(), StartTime: sess.CreateTime.GetTimestamp()}},
 		Certificate:    sess.Certificate,
 		Type:           target.TcpSubType.String(),
+		Connections: []*pb.Connection{{
+			Id:                 c.GetPublicId(),
+			ClientTcpAddress:   "127.0.0.1",
+			ClientTcpPort:      22,
+			EndpointTcpAddress: "127.0.0.2",
+			EndpointTcpPort:    23,
+			CreatedTime:        c.CreateTime.GetTimestamp(),
+			UpdatedTime:        c.UpdateTime.GetTimestamp(),
+		}},
 	}
 
 	cases := []struct {
@@ -195,7 +208,6 @@ func TestList(t *testing.T) {
 			Scope:          &scopes.ScopeInfo{Id: pWithSessions.GetPublicId(), Type: scope.Project.String()},
 			Status:         status,
 			States:         states,
-			Certificate:    sess.Certificate,
 			Type:           target.TcpSubType.String(),
 		})
 	}
@@ -238,6 +250,71 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestCount(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	wrap := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrap)
+
+	iamRepo := iam.TestRepo(t, conn, wrap)
+
+	rw := db.New(conn)
+	sessRepo, err := session.NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iamRepo, nil
+	}
+	sessRepoFn := func() (*session.Repository, error) {
+		return sessRepo, nil
+	}
+
+	_, pNoSessions := iam.TestScopes(t, iamRepo)
+	composedOf := session.TestSessionParams(t, conn, wrap, iamRepo)
+	for i := 0; i < 3; i++ {
+		session.TestSession(t, conn, wrap, composedOf)
+	}
+	for i := 0; i < 2; i++ {
+		sess := session.TestSession(t, conn, wrap, composedOf)
+		session.TestState(t, conn, sess.GetPublicId(), session.StatusActive)
+	}
+
+	cases := []struct {
+		name string
+		req  *pbs.CountSessionsRequest
+		res  *pbs.CountSessionsResponse
+		err  error
+	}{
+		{
+			name: "Count Many Sessions",
+			req:  &pbs.CountSessionsRequest{ScopeId: composedOf.ScopeId},
+			res:  &pbs.CountSessionsResponse{Total: 5, Pending: 3, Active: 2},
+		},
+		{
+			name: "Count No Sessions",
+			req:  &pbs.CountSessionsRequest{ScopeId: pNoSessions.GetPublicId()},
+			res:  &pbs.CountSessionsResponse{},
+		},
+		{
+			name: "Bad Scope Id",
+			req:  &pbs.CountSessionsRequest{ScopeId: "o_1234567890"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := sessions.NewService(sessRepoFn, iamRepoFn)
+			require.NoError(t, err, "Couldn't create new session service.")
+
+			got, gErr := s.CountSessions(auth.DisabledAuthTestContext(auth.WithScopeId(tc.req.GetScopeId())), tc.req)
+			if tc.err != nil {
+				require.Error(t, gErr)
+				assert.True(t, errors.Is(gErr, tc.err), "CountSessions(%+v) got error %v, wanted %v", tc.req, gErr, tc.err)
+			}
+			assert.Empty(t, cmp.Diff(got, tc.res, protocmp.Transform()), "CountSessions(%q) got response %q, wanted %q", tc.req, got, tc.res)
+		})
+	}
+}
+
 func convertStates(in []*session.State) (string, []*pb.SessionState) {
 	out := make([]*pb.SessionState, 0, len(in))
 	for _, s := range in {
@@ -306,7 +383,6 @@ func TestCancel(t *testing.T) {
 		ExpirationTime: sess.ExpirationTime.GetTimestamp(),
 		Scope:          &scopes.ScopeInfo{Id: p.GetPublicId(), Type: scope.Project.String()},
 		Status:         session.StatusCanceling.String(),
-		Certificate:    sess.Certificate,
 		Type:           target.TcpSubType.String(),
 	}
 
@@ -0,0 +1,49 @@
+package sessions
+
+import (
+	"context"
+
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// maxWatchedSessions is the most sessions a single watch request can
+// subscribe to.
+const maxWatchedSessions = 100
+
+// Watch subscribes to the status changes of the sessions with the ids after
+// checking that the caller can read each of them. The returned Subscription
+// must be closed by the caller.
+func (s Service) Watch(ctx context.Context, watcher *session.Watcher, ids []string) (*session.Subscription, error) {
+	if err := validateWatchRequest(ids); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		authResults := s.authResult(ctx, id, action.Read)
+		if authResults.Error != nil {
+			return nil, authResults.Error
+		}
+	}
+	return watcher.Subscribe(ids...), nil
+}
+
+func validateWatchRequest(ids []string) error {
+	badFields := map[string]string{}
+	switch {
+	case len(ids) == 0:
+		badFields["ids"] = "At least one session id is required."
+	case len(ids) > maxWatchedSessions:
+		badFields["ids"] = "Too many session ids were provided."
+	}
+	for _, id := range ids {
+		if !handlers.ValidId(session.SessionPrefix, id) {
+			badFields["ids"] = "Improperly formatted identifier."
+			break
+		}
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Errors in provided fields.", badFields)
+	}
+	return nil
+}
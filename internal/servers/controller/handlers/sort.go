@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortColumns maps the fields a list request can sort its items by to the
+// columns the repository orders the listed resources by.
+type SortColumns map[string]string
+
+// DefaultSortColumns are the fields the items of most list requests can be
+// sorted by.
+var DefaultSortColumns = SortColumns{
+	"created_time": "create_time",
+	"name":         "name",
+}
+
+// NewOrder returns the order for a repository's WithOrder option which sorts
+// the items of a list request by the field sortBy in the direction sortDir,
+// "asc" or "desc", defaulting to "asc". An empty order is returned if sortBy
+// is empty. An InvalidArgument error naming the sort_by or sort_dir field is
+// returned if sortBy isn't one of the fields in columns or sortDir isn't a
+// direction.
+func NewOrder(sortBy, sortDir string, columns SortColumns) (string, error) {
+	dir := strings.ToLower(sortDir)
+	switch dir {
+	case "":
+		dir = "asc"
+	case "asc", "desc":
+	default:
+		return "", InvalidArgumentErrorf("Invalid sort direction.", map[string]string{"sort_dir": `Must be "asc" or "desc".`})
+	}
+	if sortBy == "" {
+		if sortDir != "" {
+			return "", InvalidArgumentErrorf("Invalid sort direction.", map[string]string{"sort_dir": "Can only be set with sort_by."})
+		}
+		return "", nil
+	}
+	column, ok := columns[sortBy]
+	if !ok {
+		fields := make([]string, 0, len(columns))
+		for f := range columns {
+			fields = append(fields, fmt.Sprintf("%q", f))
+		}
+		sort.Strings(fields)
+		return "", InvalidArgumentErrorf("Invalid sort field.", map[string]string{"sort_by": fmt.Sprintf("Must be one of %s.", strings.Join(fields, ", "))})
+	}
+	return fmt.Sprintf("%s %s", column, dir), nil
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestNewOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		sortBy    string
+		sortDir   string
+		columns   SortColumns
+		want      string
+		wantField string
+	}{
+		{name: "unsorted", columns: DefaultSortColumns},
+		{name: "default-direction", sortBy: "created_time", columns: DefaultSortColumns, want: "create_time asc"},
+		{name: "descending", sortBy: "name", sortDir: "desc", columns: DefaultSortColumns, want: "name desc"},
+		{name: "case-insensitive-direction", sortBy: "name", sortDir: "DESC", columns: DefaultSortColumns, want: "name desc"},
+		{name: "other-columns", sortBy: "status", columns: SortColumns{"status": "ss.state"}, want: "ss.state asc"},
+		{name: "not-allowed", sortBy: "status", columns: DefaultSortColumns, wantField: "sort_by"},
+		{name: "column-name", sortBy: "create_time", columns: DefaultSortColumns, wantField: "sort_by"},
+		{name: "bad-direction", sortBy: "name", sortDir: "up", columns: DefaultSortColumns, wantField: "sort_dir"},
+		{name: "direction-without-field", sortDir: "asc", columns: DefaultSortColumns, wantField: "sort_dir"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := NewOrder(tt.sortBy, tt.sortDir, tt.columns)
+			if tt.wantField != "" {
+				require.Error(err)
+				assert.True(errors.Is(err, ApiErrorWithCode(codes.InvalidArgument)))
+				var apiErr *apiError
+				require.True(errors.As(err, &apiErr))
+				require.Len(apiErr.inner.GetDetails().GetRequestFields(), 1)
+				assert.Equal(tt.wantField, apiErr.inner.GetDetails().GetRequestFields()[0].GetName())
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
@@ -6,16 +6,21 @@ import (
 	"fmt"
 	"math/rand"
 	"net/url"
+	"sort"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/hashicorp/boundary/internal/auth"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/event"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/host"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/metric"
+	"github.com/hashicorp/boundary/internal/quota"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
@@ -26,9 +31,7 @@ import (
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/boundary/sdk/strutil"
-	"github.com/mr-tron/base58"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
@@ -42,6 +45,14 @@ func init() {
 	if maskManager, err = handlers.NewMaskManager(&store.TcpTarget{}, &pb.Target{}, &pb.TcpTargetAttributes{}); err != nil {
 		panic(err)
 	}
+	if err := handlers.RegisterCustomActions(resource.Target, pbs.File_controller_api_services_v1_target_service_proto.Services().ByName("TargetService"), map[action.Type]string{
+		action.AuthorizeSession: "AuthorizeSession",
+		action.AddHostSets:      "AddTargetHostSets",
+		action.SetHostSets:      "SetTargetHostSets",
+		action.RemoveHostSets:   "RemoveTargetHostSets",
+	}); err != nil {
+		panic(err)
+	}
 }
 
 // Service handles request as described by the pbs.TargetServiceServer interface.
@@ -94,18 +105,48 @@ func (s Service) ListTargets(ctx context.Context, req *pbs.ListTargetsRequest) (
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	ul, err := s.listFromRepo(ctx, authResults.Scope.GetId())
+	ul, err := s.listFromRepo(ctx, authResults.Scope.GetId(), target.WithOrder(order))
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.Target
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
+	}
+	return &pbs.ListTargetsResponse{Items: finalItems}, nil
+}
+
+// CountTargets implements the interface pbs.TargetServiceServer. Counting is
+// authorized by the list action since the count reveals no more than a
+// listing does.
+func (s Service) CountTargets(ctx context.Context, req *pbs.CountTargetsRequest) (*pbs.CountTargetsResponse, error) {
+	if err := validateCountRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
+	if authResults.Error != nil {
+		return nil, authResults.Error
 	}
-	return &pbs.ListTargetsResponse{Items: ul}, nil
+	return s.countFromRepo(ctx, authResults.Scope.GetId())
 }
 
 // GetTargets implements the interface pbs.TargetServiceServer.
@@ -226,12 +267,34 @@ func (s Service) RemoveTargetHostSets(ctx context.Context, req *pbs.RemoveTarget
 	return &pbs.RemoveTargetHostSetsResponse{Item: u}, nil
 }
 
-func (s Service) AuthorizeSession(ctx context.Context, req *pbs.AuthorizeSessionRequest) (*pbs.AuthorizeSessionResponse, error) {
+func (s Service) AuthorizeSession(ctx context.Context, req *pbs.AuthorizeSessionRequest) (_ *pbs.AuthorizeSessionResponse, retErr error) {
+	defer func(start time.Time) {
+		metric.AuthorizeSessionDuration.WithLabelValues(metric.Outcome(retErr)).Observe(time.Since(start).Seconds())
+	}(time.Now())
+
 	if err := validateAuthorizeSessionRequest(req); err != nil {
 		return nil, err
 	}
+
+	// A dry run makes every check without creating a session, and returns
+	// the reasons the session would be denied instead of failing.
+	dryRun := req.GetDryRun()
+	var denials []string
+	deny := func(err error, reason string) error {
+		if !dryRun {
+			return err
+		}
+		denials = append(denials, reason)
+		return nil
+	}
+
 	authResults := s.authResult(ctx, req.GetId(), action.AuthorizeSession)
 	if authResults.Error != nil {
+		// A dry run stops at a denial by grants, so callers don't learn about
+		// the hosts and workers of targets they can't connect to.
+		if dryRun && errors.Is(authResults.Error, handlers.ForbiddenError()) {
+			return dryRunResponse(req.GetId(), authResults, "Not permitted to authorize sessions on the target."), nil
+		}
 		return nil, authResults.Error
 	}
 	// This could happen if, say, u_recovery was used or u_anon was granted. But
@@ -249,9 +312,25 @@ func (s Service) AuthorizeSession(ctx context.Context, req *pbs.AuthorizeSession
 	// * u_recovery access (which is fine, recovery is meant for recovering
 	// system state, no real reason to allow it to then connect to systems)
 	if authResults.AuthTokenId == "" {
+		if dryRun {
+			return dryRunResponse(req.GetId(), authResults, "Sessions can only be authorized with an auth token."), nil
+		}
 		return nil, handlers.ForbiddenError()
 	}
 
+	// Sessions can't be authorized once the target's organization has as
+	// many sessions as its quota allows.
+	if quotas := authResults.Quotas(); quotas != nil {
+		err := quotas.CheckSessions(ctx, auth.OrgId(authResults.Scope))
+		var quotaErr *quota.ExceededError
+		if errors.As(err, &quotaErr) {
+			err = deny(err, fmt.Sprintf("The org has reached its quota of %d active sessions.", quotaErr.Limit))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Get the target information
 	repo, err := s.repoFn()
 	if err != nil {
@@ -268,6 +347,40 @@ func (s Service) AuthorizeSession(ctx context.Context, req *pbs.AuthorizeSession
 		return nil, handlers.NotFoundErrorf("Target %q not found.", req.GetId())
 	}
 
+	// Workers can't connect to the hosts of ssh targets without the target's
+	// ssh credentials.
+	if t.GetProtocol() == target.SshProtocol && t.GetSshUsername() == "" {
+		if err := deny(
+			handlers.ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "Target %q has no ssh credentials.", t.GetPublicId()),
+			"The target has no ssh credentials.",
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sessions can only be authorized during the target's access schedule.
+	// If the target terminates sessions at the end of the schedule window,
+	// the session expires when the window ends.
+	var scheduleEnd *timestamppb.Timestamp
+	if t.GetAccessSchedule() != "" {
+		sched, err := target.ParseAccessSchedule(t.GetAccessSchedule())
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse access schedule of target %q: %w", t.GetPublicId(), err)
+		}
+		end, ok := sched.WindowEnd(time.Now())
+		if !ok {
+			if err := deny(
+				handlers.ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "Target %q can't be connected to outside of its access schedule.", t.GetPublicId()),
+				"The target can't be connected to outside of its access schedule.",
+			); err != nil {
+				return nil, err
+			}
+		}
+		if ok && t.GetTerminateSessionsAtScheduleEnd() {
+			scheduleEnd = timestamppb.New(end)
+		}
+	}
+
 	// Instantiate some repos
 	sessionRepo, err := s.sessionRepoFn()
 	if err != nil {
@@ -279,13 +392,9 @@ func (s Service) AuthorizeSession(ctx context.Context, req *pbs.AuthorizeSession
 	}
 
 	// First, fetch all available hosts. Unless one was chosen in the request,
-	// we will pick one at random.
-	type compoundHost struct {
-		hostSetId string
-		hostId    string
-	}
-
+	// we will pick one with the target's host selection strategy.
 	var chosenId *compoundHost
+	var selectionReason string
 	requestedId := req.GetHostId()
 	staticHostRepo, err := s.staticHostRepoFn()
 	if err != nil {
@@ -313,25 +422,81 @@ HostSetIterationLoop:
 			}
 		}
 	}
-	if requestedId != "" && chosenId == nil {
+	switch {
+	case chosenId != nil:
+		selectionReason = session.HostSelectionRequested
+	case requestedId != "":
 		// We didn't find it
-		return nil, handlers.InvalidArgumentErrorf(
+		if err := deny(handlers.InvalidArgumentErrorf(
 			"Errors in provided fields.",
 			map[string]string{
 				"host_id": "The requested host id is not available.",
-			})
+			}), "The requested host is not available."); err != nil {
+			return nil, err
+		}
+	case len(hostIds) == 0:
+		// No hosts were found, error
+		if err := deny(handlers.NotFoundErrorf("No hosts found from available target host sets."),
+			"No hosts are available from the target's host sets."); err != nil {
+			return nil, err
+		}
+	default:
+		chosenId, selectionReason, err = selectHost(ctx, repo, t, authResults.UserId, hostIds, dryRun)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Select the workers before creating the session so no session is created
+	// when there are no workers available to handle it. Workers are ordered
+	// least loaded first.
+	liveWorkers, err := serversRepo.ListWorkers(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if chosenId == nil {
-		if len(hostIds) == 0 {
-			// No hosts were found, error
-			return nil, handlers.NotFoundErrorf("No hosts found from available target host sets.")
+	// Sessions for targets registered by a worker from its configuration are
+	// only proxied by that worker
+	workerTarget, err := serversRepo.LookupWorkerTarget(ctx, t.GetPublicId())
+	switch {
+	case err == nil:
+		pinned := make([]*servers.Server, 0, 1)
+		for _, w := range liveWorkers {
+			if w.GetPrivateId() == workerTarget.WorkerName {
+				pinned = append(pinned, w)
+			}
 		}
-		chosenId = &hostIds[rand.Intn(len(hostIds))]
+		liveWorkers = pinned
+	case !errors.Is(err, db.ErrRecordNotFound):
+		return nil, err
+	}
+	var workers []*pb.WorkerInfo
+	for _, v := range servers.SelectWorkers(liveWorkers) {
+		workers = append(workers, &pb.WorkerInfo{Address: v.Address})
+	}
+	if len(workers) == 0 {
+		if err := deny(handlers.ApiErrorWithCodeAndMessage(codes.Unavailable, "No workers are available to handle the session."),
+			"No workers are available to handle the session."); err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		ret := dryRunResponse(t.GetPublicId(), authResults, denials...)
+		ret.Item.Type = t.GetType()
+		if chosenId != nil {
+			ret.Item.HostId = chosenId.hostId
+			ret.Item.HostSetId = chosenId.hostSetId
+		}
+		return ret, nil
 	}
 
 	// Generate the endpoint URL
+	protocol := t.GetType()
+	if t.GetProtocol() != "" {
+		protocol = t.GetProtocol()
+	}
 	endpointUrl := &url.URL{
-		Scheme: t.GetType(),
+		Scheme: protocol,
 	}
 	defaultPort := t.GetDefaultPort()
 	var endpointHost string
@@ -354,22 +519,44 @@ HostSetIterationLoop:
 
 	expTime := timestamppb.Now()
 	expTime.Seconds += int64(t.GetSessionMaxSeconds())
+	if scheduleEnd != nil && scheduleEnd.AsTime().Before(expTime.AsTime()) {
+		expTime = scheduleEnd
+	}
 	sessionComposition := session.ComposedOf{
-		UserId:          authResults.UserId,
-		HostId:          chosenId.hostId,
-		TargetId:        t.GetPublicId(),
-		HostSetId:       chosenId.hostSetId,
-		AuthTokenId:     authResults.AuthTokenId,
-		ScopeId:         authResults.Scope.Id,
-		Endpoint:        endpointUrl.String(),
-		ExpirationTime:  &timestamp.Timestamp{Timestamp: expTime},
-		ConnectionLimit: t.GetSessionConnectionLimit(),
+		UserId:                   authResults.UserId,
+		HostId:                   chosenId.hostId,
+		TargetId:                 t.GetPublicId(),
+		HostSetId:                chosenId.hostSetId,
+		AuthTokenId:              authResults.AuthTokenId,
+		ScopeId:                  authResults.Scope.Id,
+		Endpoint:                 endpointUrl.String(),
+		ExpirationTime:           &timestamp.Timestamp{Timestamp: expTime},
+		ConnectionLimit:          t.GetSessionConnectionLimit(),
+		ConnectionBandwidthLimit: t.GetConnectionBandwidthLimit(),
+		AllowedPorts:             t.GetAllowedPorts(),
+		HostAddress:              endpointHost,
+		HostSelectionReason:      selectionReason,
+	}
+	// Record the client which authorized the session
+	if reqInfo, ok := event.RequestInfoFromContext(ctx); ok {
+		sessionComposition.ClientIp = reqInfo.ClientIp
+		sessionComposition.UserAgent = reqInfo.UserAgent
+		if len(sessionComposition.UserAgent) > session.MaxUserAgentLength {
+			sessionComposition.UserAgent = sessionComposition.UserAgent[:session.MaxUserAgentLength]
+		}
 	}
 
 	sess, err := session.New(sessionComposition)
 	if err != nil {
 		return nil, err
 	}
+	// The user is given the same host next time while it's still one of the
+	// target's hosts, whether or not they asked for it.
+	if t.GetHostSelectionStrategy() == target.StickyHostSelection {
+		if err := repo.SetHostAffinity(ctx, t.GetPublicId(), authResults.UserId, chosenId.hostSetId, chosenId.hostId); err != nil {
+			return nil, err
+		}
+	}
 	wrapper, err := s.kmsCache.GetWrapper(ctx, authResults.Scope.Id, kms.KeyPurposeSessions)
 	if err != nil {
 		return nil, err
@@ -378,15 +565,10 @@ HostSetIterationLoop:
 	if err != nil {
 		return nil, err
 	}
-
-	var workers []*pb.WorkerInfo
-	servers, err := serversRepo.ListServers(ctx, servers.ServerTypeWorker)
+	endpointAddr, err := session.EndpointAddress(sess.Endpoint)
 	if err != nil {
 		return nil, err
 	}
-	for _, v := range servers {
-		workers = append(workers, &pb.WorkerInfo{Address: v.Address})
-	}
 
 	sad := &pb.SessionAuthorizationData{
 		SessionId:       sess.PublicId,
@@ -399,27 +581,44 @@ HostSetIterationLoop:
 		HostId:          chosenId.hostId,
 		WorkerInfo:      workers,
 		ConnectionLimit: t.GetSessionConnectionLimit(),
+		Protocol:        protocol,
+		AllowedPorts:    t.GetAllowedPorts(),
+		Endpoint:        endpointAddr,
 	}
-	marshaledSad, err := proto.Marshal(sad)
+	format := session.NegotiateAuthzFormat(req.GetMaxAuthorizationFormat())
+	encodedSad, err := session.EncodeAuthorizationData(sad, format)
 	if err != nil {
 		return nil, err
 	}
-	encodedMarshaledSad := base58.FastBase58Encoding(marshaledSad)
 
 	ret := &pb.SessionAuthorization{
-		SessionId:          sess.PublicId,
-		TargetId:           t.GetPublicId(),
-		Scope:              authResults.Scope,
-		CreatedTime:        sess.CreateTime.GetTimestamp(),
-		Type:               t.GetType(),
-		AuthorizationToken: string(encodedMarshaledSad),
-		UserId:             authResults.UserId,
-		HostId:             chosenId.hostId,
-		HostSetId:          chosenId.hostSetId,
+		SessionId:           sess.PublicId,
+		TargetId:            t.GetPublicId(),
+		Scope:               authResults.Scope,
+		CreatedTime:         sess.CreateTime.GetTimestamp(),
+		Type:                t.GetType(),
+		AuthorizationToken:  encodedSad,
+		AuthorizationFormat: format,
+		UserId:              authResults.UserId,
+		HostId:              chosenId.hostId,
+		HostSetId:           chosenId.hostSetId,
 	}
 	return &pbs.AuthorizeSessionResponse{Item: ret}, nil
 }
 
+// dryRunResponse returns the response to a dry run of AuthorizeSession on
+// the target with id targetId, which found the session would be denied for
+// reasons, if any.
+func dryRunResponse(targetId string, authResults auth.VerifyResults, reasons ...string) *pbs.AuthorizeSessionResponse {
+	return &pbs.AuthorizeSessionResponse{Item: &pb.SessionAuthorization{
+		TargetId:      targetId,
+		Scope:         authResults.Scope,
+		UserId:        authResults.UserId,
+		DryRun:        true,
+		DenialReasons: reasons,
+	}}
+}
+
 func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Target, error) {
 	repo, err := s.repoFn()
 	if err != nil {
@@ -449,6 +648,21 @@ func (s Service) createInRepo(ctx context.Context, item *pb.Target) (*pb.Target,
 	if item.GetSessionConnectionLimit() != nil {
 		opts = append(opts, target.WithSessionConnectionLimit(item.GetSessionConnectionLimit().GetValue()))
 	}
+	if item.GetConnectionIdleTimeoutSeconds() != nil {
+		opts = append(opts, target.WithConnectionIdleTimeoutSeconds(item.GetConnectionIdleTimeoutSeconds().GetValue()))
+	}
+	if item.GetConnectionBandwidthLimit() != nil {
+		opts = append(opts, target.WithConnectionBandwidthLimit(item.GetConnectionBandwidthLimit().GetValue()))
+	}
+	if item.GetAccessSchedule() != nil {
+		opts = append(opts, target.WithAccessSchedule(item.GetAccessSchedule().GetValue()))
+	}
+	if item.GetTerminateSessionsAtScheduleEnd() != nil {
+		opts = append(opts, target.WithTerminateSessionsAtScheduleEnd(item.GetTerminateSessionsAtScheduleEnd().GetValue()))
+	}
+	if item.GetAuthorizationValidSeconds() != nil {
+		opts = append(opts, target.WithAuthorizationValidSeconds(item.GetAuthorizationValidSeconds().GetValue()))
+	}
 	tcpAttrs := &pb.TcpTargetAttributes{}
 	if err := handlers.StructToProto(item.GetAttributes(), tcpAttrs); err != nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.InvalidArgument, "Provided attributes don't match expected format.")
@@ -456,6 +670,45 @@ func (s Service) createInRepo(ctx context.Context, item *pb.Target) (*pb.Target,
 	if tcpAttrs.GetDefaultPort().GetValue() != 0 {
 		opts = append(opts, target.WithDefaultPort(tcpAttrs.GetDefaultPort().GetValue()))
 	}
+	// Clearing the protocol resets it to tcp
+	protocol := target.TcpTargetType.String()
+	if tcpAttrs.GetProtocol() != nil {
+		protocol = tcpAttrs.GetProtocol().GetValue()
+	}
+	opts = append(opts, target.WithProtocol(protocol))
+	if tcpAttrs.GetAllowedPorts() != nil {
+		opts = append(opts, target.WithAllowedPorts(tcpAttrs.GetAllowedPorts().GetValue()))
+	}
+	if tcpAttrs.GetSshUsername() != nil {
+		opts = append(opts, target.WithSshUsername(tcpAttrs.GetSshUsername().GetValue()))
+	}
+	if tcpAttrs.GetSshPrivateKey() != nil {
+		opts = append(opts, target.WithSshPrivateKey([]byte(tcpAttrs.GetSshPrivateKey().GetValue())))
+	}
+	if tcpAttrs.GetSshHostKey() != nil {
+		opts = append(opts, target.WithSshHostKey(tcpAttrs.GetSshHostKey().GetValue()))
+	}
+	if tcpAttrs.GetEndpointTls() != nil {
+		opts = append(opts, target.WithEndpointTls(tcpAttrs.GetEndpointTls().GetValue()))
+	}
+	if tcpAttrs.GetTlsServerName() != nil {
+		opts = append(opts, target.WithTlsServerName(tcpAttrs.GetTlsServerName().GetValue()))
+	}
+	if tcpAttrs.GetTlsCaBundle() != nil {
+		opts = append(opts, target.WithTlsCaBundle([]byte(tcpAttrs.GetTlsCaBundle().GetValue())))
+	}
+	if tcpAttrs.GetTlsClientCertificate() != nil {
+		opts = append(opts, target.WithTlsClientCertificate(tcpAttrs.GetTlsClientCertificate().GetValue()))
+	}
+	if tcpAttrs.GetTlsClientPrivateKey() != nil {
+		opts = append(opts, target.WithTlsClientPrivateKey([]byte(tcpAttrs.GetTlsClientPrivateKey().GetValue())))
+	}
+	// Clearing the host selection strategy resets it to random
+	strategy := target.RandomHostSelection
+	if tcpAttrs.GetHostSelectionStrategy().GetValue() != "" {
+		strategy = tcpAttrs.GetHostSelectionStrategy().GetValue()
+	}
+	opts = append(opts, target.WithHostSelectionStrategy(strategy))
 	u, err := target.NewTcpTarget(item.GetScopeId(), opts...)
 	if err != nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build target for creation: %v.", err)
@@ -488,6 +741,21 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 	if item.GetSessionConnectionLimit() != nil {
 		opts = append(opts, target.WithSessionConnectionLimit(item.GetSessionConnectionLimit().GetValue()))
 	}
+	if item.GetConnectionIdleTimeoutSeconds() != nil {
+		opts = append(opts, target.WithConnectionIdleTimeoutSeconds(item.GetConnectionIdleTimeoutSeconds().GetValue()))
+	}
+	if item.GetConnectionBandwidthLimit() != nil {
+		opts = append(opts, target.WithConnectionBandwidthLimit(item.GetConnectionBandwidthLimit().GetValue()))
+	}
+	if item.GetAccessSchedule() != nil {
+		opts = append(opts, target.WithAccessSchedule(item.GetAccessSchedule().GetValue()))
+	}
+	if item.GetTerminateSessionsAtScheduleEnd() != nil {
+		opts = append(opts, target.WithTerminateSessionsAtScheduleEnd(item.GetTerminateSessionsAtScheduleEnd().GetValue()))
+	}
+	if item.GetAuthorizationValidSeconds() != nil {
+		opts = append(opts, target.WithAuthorizationValidSeconds(item.GetAuthorizationValidSeconds().GetValue()))
+	}
 	tcpAttrs := &pb.TcpTargetAttributes{}
 	if err := handlers.StructToProto(item.GetAttributes(), tcpAttrs); err != nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.InvalidArgument, "Provided attributes don't match expected format.")
@@ -495,6 +763,45 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 	if tcpAttrs.GetDefaultPort().GetValue() != 0 {
 		opts = append(opts, target.WithDefaultPort(tcpAttrs.GetDefaultPort().GetValue()))
 	}
+	// Clearing the protocol resets it to tcp
+	protocol := target.TcpTargetType.String()
+	if tcpAttrs.GetProtocol() != nil {
+		protocol = tcpAttrs.GetProtocol().GetValue()
+	}
+	opts = append(opts, target.WithProtocol(protocol))
+	if tcpAttrs.GetAllowedPorts() != nil {
+		opts = append(opts, target.WithAllowedPorts(tcpAttrs.GetAllowedPorts().GetValue()))
+	}
+	if tcpAttrs.GetSshUsername() != nil {
+		opts = append(opts, target.WithSshUsername(tcpAttrs.GetSshUsername().GetValue()))
+	}
+	if tcpAttrs.GetSshPrivateKey() != nil {
+		opts = append(opts, target.WithSshPrivateKey([]byte(tcpAttrs.GetSshPrivateKey().GetValue())))
+	}
+	if tcpAttrs.GetSshHostKey() != nil {
+		opts = append(opts, target.WithSshHostKey(tcpAttrs.GetSshHostKey().GetValue()))
+	}
+	if tcpAttrs.GetEndpointTls() != nil {
+		opts = append(opts, target.WithEndpointTls(tcpAttrs.GetEndpointTls().GetValue()))
+	}
+	if tcpAttrs.GetTlsServerName() != nil {
+		opts = append(opts, target.WithTlsServerName(tcpAttrs.GetTlsServerName().GetValue()))
+	}
+	if tcpAttrs.GetTlsCaBundle() != nil {
+		opts = append(opts, target.WithTlsCaBundle([]byte(tcpAttrs.GetTlsCaBundle().GetValue())))
+	}
+	if tcpAttrs.GetTlsClientCertificate() != nil {
+		opts = append(opts, target.WithTlsClientCertificate(tcpAttrs.GetTlsClientCertificate().GetValue()))
+	}
+	if tcpAttrs.GetTlsClientPrivateKey() != nil {
+		opts = append(opts, target.WithTlsClientPrivateKey([]byte(tcpAttrs.GetTlsClientPrivateKey().GetValue())))
+	}
+	// Clearing the host selection strategy resets it to random
+	strategy := target.RandomHostSelection
+	if tcpAttrs.GetHostSelectionStrategy().GetValue() != "" {
+		strategy = tcpAttrs.GetHostSelectionStrategy().GetValue()
+	}
+	opts = append(opts, target.WithHostSelectionStrategy(strategy))
 	version := item.GetVersion()
 	u, err := target.NewTcpTarget(scopeId, opts...)
 	if err != nil {
@@ -534,12 +841,24 @@ func (s Service) deleteFromRepo(ctx context.Context, id string) (bool, error) {
 	return rows > 0, nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Target, error) {
+func (s Service) countFromRepo(ctx context.Context, scopeId string) (*pbs.CountTargetsResponse, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	ul, err := repo.ListTargets(ctx, target.WithScopeId(scopeId))
+	count, err := repo.CountTargets(ctx, scopeId)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.CountTargetsResponse{Total: uint64(count)}, nil
+}
+
+func (s Service) listFromRepo(ctx context.Context, scopeId string, opt ...target.Option) ([]*pb.Target, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	ul, err := repo.ListTargets(ctx, append(opt, target.WithScopeId(scopeId))...)
 	if err != nil {
 		return nil, err
 	}
@@ -672,10 +991,53 @@ func toProto(in target.Target, m []*target.TargetSet) (*pb.Target, error) {
 	if in.GetName() != "" {
 		out.Name = wrapperspb.String(in.GetName())
 	}
+	if in.GetConnectionIdleTimeoutSeconds() > 0 {
+		out.ConnectionIdleTimeoutSeconds = wrapperspb.UInt32(in.GetConnectionIdleTimeoutSeconds())
+	}
+	if in.GetConnectionBandwidthLimit() > 0 {
+		out.ConnectionBandwidthLimit = wrapperspb.UInt32(in.GetConnectionBandwidthLimit())
+	}
+	if in.GetAccessSchedule() != "" {
+		out.AccessSchedule = wrapperspb.String(in.GetAccessSchedule())
+	}
+	if in.GetTerminateSessionsAtScheduleEnd() {
+		out.TerminateSessionsAtScheduleEnd = wrapperspb.Bool(true)
+	}
+	if in.GetAuthorizationValidSeconds() > 0 {
+		out.AuthorizationValidSeconds = wrapperspb.UInt32(in.GetAuthorizationValidSeconds())
+	}
 	attrs := &pb.TcpTargetAttributes{}
 	if in.GetDefaultPort() > 0 {
 		attrs.DefaultPort = &wrappers.UInt32Value{Value: in.GetDefaultPort()}
 	}
+	// Like the default port, the protocol is only included when it isn't the
+	// default
+	if p := in.GetProtocol(); p != "" && p != target.TcpTargetType.String() {
+		attrs.Protocol = wrapperspb.String(p)
+	}
+	if in.GetAllowedPorts() != "" {
+		attrs.AllowedPorts = wrapperspb.String(in.GetAllowedPorts())
+	}
+	// The ssh private key is never returned
+	if in.GetSshUsername() != "" {
+		attrs.SshUsername = wrapperspb.String(in.GetSshUsername())
+	}
+	if in.GetSshHostKey() != "" {
+		attrs.SshHostKey = wrapperspb.String(in.GetSshHostKey())
+	}
+	if s := in.GetHostSelectionStrategy(); s != "" && s != target.RandomHostSelection {
+		attrs.HostSelectionStrategy = wrapperspb.String(s)
+	}
+	// Neither is the TLS CA bundle or client private key
+	if in.GetEndpointTls() {
+		attrs.EndpointTls = wrapperspb.Bool(true)
+	}
+	if in.GetTlsServerName() != "" {
+		attrs.TlsServerName = wrapperspb.String(in.GetTlsServerName())
+	}
+	if in.GetTlsClientCertificate() != "" {
+		attrs.TlsClientCertificate = wrapperspb.String(in.GetTlsClientCertificate())
+	}
 	st, err := handlers.ProtoToStruct(attrs)
 	if err != nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "failed building password attribute struct: %v", err)
@@ -721,6 +1083,11 @@ func validateCreateRequest(req *pbs.CreateTargetRequest) error {
 		if req.GetItem().GetSessionMaxSeconds() != nil && req.GetItem().GetSessionMaxSeconds().GetValue() == 0 {
 			badFields["session_max_seconds"] = "This must be greater than zero."
 		}
+		if sched := req.GetItem().GetAccessSchedule(); sched != nil && sched.GetValue() != "" {
+			if _, err := target.ParseAccessSchedule(sched.GetValue()); err != nil {
+				badFields["access_schedule"] = "This must be an optional time zone followed by windows of days and times, such as \"TZ=America/New_York mon-fri 09:00-17:00\"."
+			}
+		}
 		switch target.SubtypeFromType(req.GetItem().GetType()) {
 		case target.TcpSubType:
 			tcpAttrs := &pb.TcpTargetAttributes{}
@@ -730,6 +1097,36 @@ func validateCreateRequest(req *pbs.CreateTargetRequest) error {
 			if tcpAttrs.GetDefaultPort() != nil && tcpAttrs.GetDefaultPort().GetValue() == 0 {
 				badFields["attributes.default_port"] = "This optional field cannot be set to 0."
 			}
+			if tcpAttrs.GetProtocol() != nil && !validProtocol(tcpAttrs.GetProtocol().GetValue()) {
+				badFields["attributes.protocol"] = "This must be tcp, udp or ssh."
+			}
+			if ports := tcpAttrs.GetAllowedPorts(); ports != nil && ports.GetValue() != "" {
+				if _, err := target.ParsePortRanges(ports.GetValue()); err != nil {
+					badFields["attributes.allowed_ports"] = "This must be a comma separated list of ports and ranges of ports, such as \"5432,5433,6000-6010\"."
+				}
+			}
+			if key := tcpAttrs.GetSshPrivateKey(); key != nil && key.GetValue() != "" {
+				if err := target.ValidateSshPrivateKey([]byte(key.GetValue())); err != nil {
+					badFields["attributes.ssh_private_key"] = "This must be an unencrypted PEM encoded private key."
+				}
+			}
+			if key := tcpAttrs.GetSshHostKey(); key != nil && key.GetValue() != "" {
+				if err := target.ValidateSshHostKey(key.GetValue()); err != nil {
+					badFields["attributes.ssh_host_key"] = "This must be a public key in authorized_keys format."
+				}
+			}
+			if s := tcpAttrs.GetHostSelectionStrategy(); s != nil && s.GetValue() != "" && !target.ValidHostSelectionStrategy(s.GetValue()) {
+				badFields["attributes.host_selection_strategy"] = "This must be random, round_robin or sticky."
+			}
+			if bundle := tcpAttrs.GetTlsCaBundle(); bundle != nil && bundle.GetValue() != "" {
+				if err := target.ValidateTlsCaBundle([]byte(bundle.GetValue())); err != nil {
+					badFields["attributes.tls_ca_bundle"] = "This must contain at least one PEM encoded certificate."
+				}
+			}
+			cert, key := []byte(tcpAttrs.GetTlsClientCertificate().GetValue()), []byte(tcpAttrs.GetTlsClientPrivateKey().GetValue())
+			if err := target.ValidateTlsClientCertificate(cert, key); err != nil {
+				badFields["attributes.tls_client_certificate"] = "This must be a PEM encoded certificate matching the unencrypted PEM encoded private key in tls_client_private_key."
+			}
 		}
 		switch req.GetItem().GetType() {
 		case target.TcpTargetType.String():
@@ -760,7 +1157,12 @@ func validateUpdateRequest(req *pbs.UpdateTargetRequest) error {
 		if req.GetItem().GetSessionMaxSeconds() != nil && req.GetItem().GetSessionMaxSeconds().GetValue() == 0 {
 			badFields["session_max_seconds"] = "This must be greater than zero."
 		}
-		switch target.SubtypeFromId(req.GetItem().GetType()) {
+		if sched := req.GetItem().GetAccessSchedule(); sched != nil && sched.GetValue() != "" {
+			if _, err := target.ParseAccessSchedule(sched.GetValue()); err != nil {
+				badFields["access_schedule"] = "This must be an optional time zone followed by windows of days and times, such as \"TZ=America/New_York mon-fri 09:00-17:00\"."
+			}
+		}
+		switch target.SubtypeFromId(req.GetId()) {
 		case target.TcpSubType:
 			if req.GetItem().GetType() != "" && target.SubtypeFromType(req.GetItem().GetType()) != target.TcpSubType {
 				badFields["type"] = "Cannot modify the resource type."
@@ -772,11 +1174,93 @@ func validateUpdateRequest(req *pbs.UpdateTargetRequest) error {
 			if tcpAttrs.GetDefaultPort() != nil && tcpAttrs.GetDefaultPort().GetValue() == 0 {
 				badFields["attributes.default_port"] = "This optional field cannot be set to 0."
 			}
+			if tcpAttrs.GetProtocol() != nil && !validProtocol(tcpAttrs.GetProtocol().GetValue()) {
+				badFields["attributes.protocol"] = "This must be tcp, udp or ssh."
+			}
+			if ports := tcpAttrs.GetAllowedPorts(); ports != nil && ports.GetValue() != "" {
+				if _, err := target.ParsePortRanges(ports.GetValue()); err != nil {
+					badFields["attributes.allowed_ports"] = "This must be a comma separated list of ports and ranges of ports, such as \"5432,5433,6000-6010\"."
+				}
+			}
+			if key := tcpAttrs.GetSshPrivateKey(); key != nil && key.GetValue() != "" {
+				if err := target.ValidateSshPrivateKey([]byte(key.GetValue())); err != nil {
+					badFields["attributes.ssh_private_key"] = "This must be an unencrypted PEM encoded private key."
+				}
+			}
+			if key := tcpAttrs.GetSshHostKey(); key != nil && key.GetValue() != "" {
+				if err := target.ValidateSshHostKey(key.GetValue()); err != nil {
+					badFields["attributes.ssh_host_key"] = "This must be a public key in authorized_keys format."
+				}
+			}
+			if s := tcpAttrs.GetHostSelectionStrategy(); s != nil && s.GetValue() != "" && !target.ValidHostSelectionStrategy(s.GetValue()) {
+				badFields["attributes.host_selection_strategy"] = "This must be random, round_robin or sticky."
+			}
+			if bundle := tcpAttrs.GetTlsCaBundle(); bundle != nil && bundle.GetValue() != "" {
+				if err := target.ValidateTlsCaBundle([]byte(bundle.GetValue())); err != nil {
+					badFields["attributes.tls_ca_bundle"] = "This must contain at least one PEM encoded certificate."
+				}
+			}
+			cert, key := []byte(tcpAttrs.GetTlsClientCertificate().GetValue()), []byte(tcpAttrs.GetTlsClientPrivateKey().GetValue())
+			if err := target.ValidateTlsClientCertificate(cert, key); err != nil {
+				badFields["attributes.tls_client_certificate"] = "This must be a PEM encoded certificate matching the unencrypted PEM encoded private key in tls_client_private_key."
+			}
 		}
 		return badFields
 	})
 }
 
+// A compoundHost is a host of one of a target's host sets.
+type compoundHost struct {
+	hostSetId string
+	hostId    string
+}
+
+// selectHost chooses the host of a session from hosts with the target's
+// host selection strategy and returns why it was chosen. A dry run doesn't
+// count a round robin choice, so it chooses at random instead.
+func selectHost(ctx context.Context, repo *target.Repository, t target.Target, userId string, hosts []compoundHost, dryRun bool) (*compoundHost, string, error) {
+	switch t.GetHostSelectionStrategy() {
+	case target.RoundRobinHostSelection:
+		if dryRun {
+			break
+		}
+		// Hosts are taken in turn in a stable order, however the target's
+		// host sets list them.
+		sort.Slice(hosts, func(i, j int) bool {
+			if hosts[i].hostId != hosts[j].hostId {
+				return hosts[i].hostId < hosts[j].hostId
+			}
+			return hosts[i].hostSetId < hosts[j].hostSetId
+		})
+		n, err := repo.NextRoundRobin(ctx, t.GetPublicId())
+		if err != nil {
+			return nil, "", err
+		}
+		return &hosts[n%uint64(len(hosts))], session.HostSelectionRoundRobin, nil
+	case target.StickyHostSelection:
+		a, err := repo.LookupHostAffinity(ctx, t.GetPublicId(), userId)
+		if err != nil {
+			return nil, "", err
+		}
+		if a != nil {
+			for i, h := range hosts {
+				if h.hostId == a.HostId && h.hostSetId == a.HostSetId {
+					return &hosts[i], session.HostSelectionSticky, nil
+				}
+			}
+		}
+	}
+	return &hosts[rand.Intn(len(hosts))], session.HostSelectionRandom, nil
+}
+
+func validProtocol(p string) bool {
+	switch p {
+	case "tcp", "udp", target.SshProtocol:
+		return true
+	}
+	return false
+}
+
 func validateDeleteRequest(req *pbs.DeleteTargetRequest) error {
 	return handlers.ValidateDeleteRequest(target.TcpTargetPrefix, req, handlers.NoopValidatorFn)
 }
@@ -792,6 +1276,17 @@ func validateListRequest(req *pbs.ListTargetsRequest) error {
 	return nil
 }
 
+func validateCountRequest(req *pbs.CountTargetsRequest) error {
+	badFields := map[string]string{}
+	if !handlers.ValidId(scope.Project.Prefix(), req.GetScopeId()) {
+		badFields["scope_id"] = "This field is required to have a properly formatted project scope id."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Improperly formatted identifier.", badFields)
+	}
+	return nil
+}
+
 func validateAddRequest(req *pbs.AddTargetHostSetsRequest) error {
 	badFields := map[string]string{}
 	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
@@ -199,6 +199,55 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestCount(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+
+	_, projNoTar := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	for i := 0; i < 5; i++ {
+		target.TestTcpTarget(t, conn, proj.GetPublicId(), fmt.Sprintf("tar%d", i))
+	}
+
+	cases := []struct {
+		name    string
+		scopeId string
+		res     *pbs.CountTargetsResponse
+		err     error
+	}{
+		{
+			name:    "Count Many Targets",
+			scopeId: proj.GetPublicId(),
+			res:     &pbs.CountTargetsResponse{Total: 5},
+		},
+		{
+			name:    "Count No Targets",
+			scopeId: projNoTar.GetPublicId(),
+			res:     &pbs.CountTargetsResponse{},
+		},
+		{
+			name:    "Bad Scope Id",
+			scopeId: "o_1234567890",
+			err:     handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			s, err := testService(t, conn, kms, wrapper)
+			require.NoError(err, "Couldn't create new target service.")
+
+			got, gErr := s.CountTargets(auth.DisabledAuthTestContext(auth.WithScopeId(tc.scopeId)), &pbs.CountTargetsRequest{ScopeId: tc.scopeId})
+			if tc.err != nil {
+				require.Error(gErr)
+				assert.True(errors.Is(gErr, tc.err), "CountTargets(%q) got error %v, wanted %v", tc.scopeId, gErr, tc.err)
+			}
+			assert.Empty(cmp.Diff(got, tc.res, protocmp.Transform()), "CountTargets(%q) got response %q, wanted %q", tc.scopeId, got, tc.res)
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -321,6 +370,87 @@ func TestCreate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Create with an idle timeout",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				ScopeId:                      proj.GetPublicId(),
+				Name:                         wrapperspb.String("idle name"),
+				Type:                         target.TcpTargetType.String(),
+				ConnectionIdleTimeoutSeconds: wrapperspb.UInt32(600),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port": structpb.NewNumberValue(2),
+				}},
+			}},
+			res: &pbs.CreateTargetResponse{
+				Uri: fmt.Sprintf("targets/%s_", target.TcpTargetPrefix),
+				Item: &pb.Target{
+					ScopeId: proj.GetPublicId(),
+					Scope:   &scopes.ScopeInfo{Id: proj.GetPublicId(), Type: scope.Project.String()},
+					Name:    wrapperspb.String("idle name"),
+					Type:    target.TcpTargetType.String(),
+					Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+						"default_port": structpb.NewNumberValue(2),
+					}},
+					SessionMaxSeconds:            wrapperspb.UInt32(28800),
+					SessionConnectionLimit:       wrapperspb.Int32(1),
+					ConnectionIdleTimeoutSeconds: wrapperspb.UInt32(600),
+				},
+			},
+		},
+		{
+			name: "Create with a bandwidth limit",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				ScopeId:                  proj.GetPublicId(),
+				Name:                     wrapperspb.String("bandwidth name"),
+				Type:                     target.TcpTargetType.String(),
+				ConnectionBandwidthLimit: wrapperspb.UInt32(1 << 20),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port": structpb.NewNumberValue(2),
+				}},
+			}},
+			res: &pbs.CreateTargetResponse{
+				Uri: fmt.Sprintf("targets/%s_", target.TcpTargetPrefix),
+				Item: &pb.Target{
+					ScopeId: proj.GetPublicId(),
+					Scope:   &scopes.ScopeInfo{Id: proj.GetPublicId(), Type: scope.Project.String()},
+					Name:    wrapperspb.String("bandwidth name"),
+					Type:    target.TcpTargetType.String(),
+					Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+						"default_port": structpb.NewNumberValue(2),
+					}},
+					SessionMaxSeconds:        wrapperspb.UInt32(28800),
+					SessionConnectionLimit:   wrapperspb.Int32(1),
+					ConnectionBandwidthLimit: wrapperspb.UInt32(1 << 20),
+				},
+			},
+		},
+		{
+			name: "Create with an authorization window",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				ScopeId:                   proj.GetPublicId(),
+				Name:                      wrapperspb.String("authorization valid name"),
+				Type:                      target.TcpTargetType.String(),
+				AuthorizationValidSeconds: wrapperspb.UInt32(300),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port": structpb.NewNumberValue(2),
+				}},
+			}},
+			res: &pbs.CreateTargetResponse{
+				Uri: fmt.Sprintf("targets/%s_", target.TcpTargetPrefix),
+				Item: &pb.Target{
+					ScopeId: proj.GetPublicId(),
+					Scope:   &scopes.ScopeInfo{Id: proj.GetPublicId(), Type: scope.Project.String()},
+					Name:    wrapperspb.String("authorization valid name"),
+					Type:    target.TcpTargetType.String(),
+					Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+						"default_port": structpb.NewNumberValue(2),
+					}},
+					SessionMaxSeconds:         wrapperspb.UInt32(28800),
+					SessionConnectionLimit:    wrapperspb.Int32(1),
+					AuthorizationValidSeconds: wrapperspb.UInt32(300),
+				},
+			},
+		},
 		{
 			name: "Create with default port 0",
 			req: &pbs.CreateTargetRequest{Item: &pb.Target{
@@ -333,6 +463,88 @@ func TestCreate(t *testing.T) {
 			}},
 			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
 		},
+		{
+			name: "Create with unknown protocol",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				Name:        wrapperspb.String("name"),
+				Description: wrapperspb.String("desc"),
+				Type:        target.TcpTargetType.String(),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port": structpb.NewNumberValue(2),
+					"protocol":     structpb.NewStringValue("sctp"),
+				}},
+			}},
+			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Create with invalid allowed ports",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				Name:        wrapperspb.String("name"),
+				Description: wrapperspb.String("desc"),
+				Type:        target.TcpTargetType.String(),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port":  structpb.NewNumberValue(2),
+					"allowed_ports": structpb.NewStringValue("6010-6000"),
+				}},
+			}},
+			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Create with invalid host selection strategy",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				Name:        wrapperspb.String("name"),
+				Description: wrapperspb.String("desc"),
+				Type:        target.TcpTargetType.String(),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port":            structpb.NewNumberValue(2),
+					"host_selection_strategy": structpb.NewStringValue("least_connections"),
+				}},
+			}},
+			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Create with invalid ssh private key",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				Name:        wrapperspb.String("name"),
+				Description: wrapperspb.String("desc"),
+				Type:        target.TcpTargetType.String(),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port":    structpb.NewNumberValue(22),
+					"protocol":        structpb.NewStringValue("ssh"),
+					"ssh_username":    structpb.NewStringValue("ubuntu"),
+					"ssh_private_key": structpb.NewStringValue("not a key"),
+				}},
+			}},
+			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Create with invalid ssh host key",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				Name:        wrapperspb.String("name"),
+				Description: wrapperspb.String("desc"),
+				Type:        target.TcpTargetType.String(),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port": structpb.NewNumberValue(22),
+					"protocol":     structpb.NewStringValue("ssh"),
+					"ssh_host_key": structpb.NewStringValue("not a key"),
+				}},
+			}},
+			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Create with invalid tls ca bundle",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				Name:        wrapperspb.String("name"),
+				Description: wrapperspb.String("desc"),
+				Type:        target.TcpTargetType.String(),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port":  structpb.NewNumberValue(443),
+					"endpoint_tls":  structpb.NewBoolValue(true),
+					"tls_ca_bundle": structpb.NewStringValue("not a certificate"),
+				}},
+			}},
+			err: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
 		{
 			name: "Create with unknown type",
 			req: &pbs.CreateTargetRequest{Item: &pb.Target{
@@ -31,6 +31,13 @@ func init() {
 	if maskManager, err = handlers.NewMaskManager(&store.User{}, &pb.User{}); err != nil {
 		panic(err)
 	}
+	if err := handlers.RegisterCustomActions(resource.User, pbs.File_controller_api_services_v1_user_service_proto.Services().ByName("UserService"), map[action.Type]string{
+		action.AddAccounts:    "AddUserAccounts",
+		action.SetAccounts:    "SetUserAccounts",
+		action.RemoveAccounts: "RemoveUserAccounts",
+	}); err != nil {
+		panic(err)
+	}
 }
 
 // Service handles request as described by the pbs.UserServiceServer interface.
@@ -53,18 +60,53 @@ func (s Service) ListUsers(ctx context.Context, req *pbs.ListUsersRequest) (*pbs
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	filter, err := handlers.NewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	order, err := handlers.NewOrder(req.GetSortBy(), req.GetSortDir(), handlers.DefaultSortColumns)
+	if err != nil {
+		return nil, err
+	}
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
-	ul, err := s.listFromRepo(ctx, req.GetScopeId())
+	// Narrow the listing to the name the filter requires, if any
+	opts := []iam.Option{iam.WithOrder(order)}
+	if name, ok := filter.Equality("/item/name"); ok {
+		opts = append(opts, iam.WithName(name))
+	}
+	ul, err := s.listFromRepo(ctx, req.GetScopeId(), opts...)
 	if err != nil {
 		return nil, err
 	}
+	var finalItems []*pb.User
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		ok, err := filter.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			finalItems = append(finalItems, item)
+		}
+	}
+	return &pbs.ListUsersResponse{Items: finalItems}, nil
+}
+
+// CountUsers implements the interface pbs.UserServiceServer. Counting is
+// authorized by the list action since the count reveals no more than a
+// listing does.
+func (s Service) CountUsers(ctx context.Context, req *pbs.CountUsersRequest) (*pbs.CountUsersResponse, error) {
+	if err := validateCountRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
+	if authResults.Error != nil {
+		return nil, authResults.Error
 	}
-	return &pbs.ListUsersResponse{Items: ul}, nil
+	return s.countFromRepo(ctx, req.GetScopeId())
 }
 
 // GetUsers implements the interface pbs.UserServiceServer.
@@ -276,12 +318,24 @@ func (s Service) deleteFromRepo(ctx context.Context, id string) (bool, error) {
 	return rows > 0, nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, orgId string) ([]*pb.User, error) {
+func (s Service) countFromRepo(ctx context.Context, orgId string) (*pbs.CountUsersResponse, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	ul, err := repo.ListUsers(ctx, orgId)
+	count, err := repo.CountUsers(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.CountUsersResponse{Total: uint64(count)}, nil
+}
+
+func (s Service) listFromRepo(ctx context.Context, orgId string, opt ...iam.Option) ([]*pb.User, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	ul, err := repo.ListUsers(ctx, orgId, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -453,6 +507,18 @@ func validateListRequest(req *pbs.ListUsersRequest) error {
 	return nil
 }
 
+func validateCountRequest(req *pbs.CountUsersRequest) error {
+	badFields := map[string]string{}
+	if !handlers.ValidId(scope.Org.Prefix(), req.GetScopeId()) &&
+		req.GetScopeId() != scope.Global.String() {
+		badFields["scope_id"] = "Must be 'global' or a valid org scope id when counting."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Improperly formatted identifier.", badFields)
+	}
+	return nil
+}
+
 func validateAddUserAccountsRequest(req *pbs.AddUserAccountsRequest) error {
 	badFields := map[string]string{}
 	if !handlers.ValidId(iam.UserPrefix, req.GetId()) {
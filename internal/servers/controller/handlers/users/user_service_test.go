@@ -136,6 +136,10 @@ func TestList(t *testing.T) {
 			Version:     1,
 		})
 	}
+	newestFirst := make([]*pb.User, 0, len(wantUsers))
+	for i := len(wantUsers) - 1; i >= 0; i-- {
+		newestFirst = append(newestFirst, wantUsers[i])
+	}
 
 	cases := []struct {
 		name string
@@ -153,6 +157,16 @@ func TestList(t *testing.T) {
 			req:  &pbs.ListUsersRequest{ScopeId: oNoUsers.GetPublicId()},
 			res:  &pbs.ListUsersResponse{},
 		},
+		{
+			name: "List Users Newest First",
+			req:  &pbs.ListUsersRequest{ScopeId: oWithUsers.GetPublicId(), SortBy: "created_time", SortDir: "desc"},
+			res:  &pbs.ListUsersResponse{Items: newestFirst},
+		},
+		{
+			name: "Unsortable Field",
+			req:  &pbs.ListUsersRequest{ScopeId: oWithUsers.GetPublicId(), SortBy: "version"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -170,6 +184,58 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestCount(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	wrap := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrap)
+	repoFn := func() (*iam.Repository, error) {
+		return iamRepo, nil
+	}
+
+	oNoUsers, _ := iam.TestScopes(t, iamRepo)
+	oWithUsers, _ := iam.TestScopes(t, iamRepo)
+	for i := 0; i < 10; i++ {
+		iam.TestUser(t, iamRepo, oWithUsers.GetPublicId())
+	}
+
+	cases := []struct {
+		name string
+		req  *pbs.CountUsersRequest
+		res  *pbs.CountUsersResponse
+		err  error
+	}{
+		{
+			name: "Count Many Users",
+			req:  &pbs.CountUsersRequest{ScopeId: oWithUsers.GetPublicId()},
+			res:  &pbs.CountUsersResponse{Total: 10},
+		},
+		{
+			name: "Count No Users",
+			req:  &pbs.CountUsersRequest{ScopeId: oNoUsers.GetPublicId()},
+			res:  &pbs.CountUsersResponse{},
+		},
+		{
+			name: "Project Scope",
+			req:  &pbs.CountUsersRequest{ScopeId: "p_1234567890"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			s, err := users.NewService(repoFn)
+			require.NoError(err, "Couldn't create new user service.")
+
+			got, gErr := s.CountUsers(auth.DisabledAuthTestContext(auth.WithScopeId(tc.req.GetScopeId())), tc.req)
+			if tc.err != nil {
+				require.Error(gErr)
+				assert.True(errors.Is(gErr, tc.err), "CountUsers(%+v) got error %v, wanted %v", tc.req, gErr, tc.err)
+			}
+			assert.Empty(cmp.Diff(got, tc.res, protocmp.Transform()), "CountUsers(%q) got response %q, wanted %q", tc.req, got, tc.res)
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	u, repo := createDefaultUserAndRepo(t)
 
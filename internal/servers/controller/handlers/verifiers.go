@@ -1,12 +1,12 @@
 package handlers
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	"github.com/hashicorp/boundary/internal/types/publicid"
 	"google.golang.org/genproto/protobuf/field_mask"
 )
 
@@ -147,13 +147,7 @@ func ValidateDeleteRequest(prefix string, r DeleteRequest, fn CustomValidatorFun
 	return nil
 }
 
-var reInvalidID = regexp.MustCompile("[^A-Za-z0-9]")
-
+// ValidId returns true if id is a well-formed public id with the prefix.
 func ValidId(prefix, id string) bool {
-	prefix = prefix + "_"
-	if !strings.HasPrefix(id, prefix) {
-		return false
-	}
-	id = strings.TrimPrefix(id, prefix)
-	return !reInvalidID.Match([]byte(id))
+	return publicid.ValidWithPrefix(prefix, id)
 }
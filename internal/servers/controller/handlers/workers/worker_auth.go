@@ -0,0 +1,78 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/servers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var _ pbs.WorkerAuthServiceServer = &workerServiceServer{}
+
+func (ws *workerServiceServer) RegisterWorker(ctx context.Context, req *pbs.RegisterWorkerRequest) (*pbs.RegisterWorkerResponse, error) {
+	ws.logger.Trace("got worker registration request")
+
+	repo, err := ws.serversRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting servers repo: %v", err)
+	}
+	certPem, caPem, err := repo.RegisterWorker(ctx, req.GetActivationToken(), req.GetCsrPem())
+	switch {
+	case errors.Is(err, servers.ErrInvalidActivationToken):
+		return nil, status.Error(codes.PermissionDenied, "Invalid activation token.")
+	case errors.Is(err, db.ErrInvalidParameter):
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid registration request: %v", err)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "Error registering worker: %v", err)
+	}
+
+	ws.logger.Info("worker registered")
+	return &pbs.RegisterWorkerResponse{
+		CertificatePem: certPem,
+		CaBundlePem:    caPem,
+	}, nil
+}
+
+func (ws *workerServiceServer) RotateCertificate(ctx context.Context, req *pbs.RotateCertificateRequest) (*pbs.RotateCertificateResponse, error) {
+	ws.logger.Trace("got worker certificate rotation request")
+
+	// Only a worker which authenticated with a certificate issued by a
+	// controller can rotate it
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Unable to determine worker certificate.")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok ||
+		tlsInfo.State.NegotiatedProtocol != servers.WorkerAuthPkiProto ||
+		len(tlsInfo.State.VerifiedChains) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "Worker did not authenticate with a certificate.")
+	}
+	current := tlsInfo.State.VerifiedChains[0][0]
+
+	repo, err := ws.serversRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting servers repo: %v", err)
+	}
+	certPem, caPem, err := repo.RotateWorkerCertificate(ctx, current, req.GetCsrPem())
+	switch {
+	case errors.Is(err, servers.ErrCertificateRevoked):
+		return nil, status.Error(codes.PermissionDenied, "Worker certificate has been revoked.")
+	case errors.Is(err, db.ErrInvalidParameter):
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid rotation request: %v", err)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "Error rotating worker certificate: %v", err)
+	}
+
+	ws.logger.Info("worker certificate rotated", "name", current.Subject.CommonName)
+	return &pbs.RotateCertificateResponse{
+		CertificatePem: certPem,
+		CaBundlePem:    caPem,
+	}, nil
+}
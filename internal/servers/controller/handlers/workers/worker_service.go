@@ -2,40 +2,68 @@ package workers
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/flowexport"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/metric"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/boundary/internal/target"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/go-hclog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
+// workerTokenGracePeriod is how long after a session expires the worker
+// tokens for it remain valid, so workers can still report its connections
+// closing.
+const workerTokenGracePeriod = 10 * time.Minute
+
 type workerServiceServer struct {
-	logger        hclog.Logger
-	serversRepoFn common.ServersRepoFactory
-	sessionRepoFn common.SessionRepoFactory
-	updateTimes   *sync.Map
-	kms           *kms.Kms
+	logger          hclog.Logger
+	serversRepoFn   common.ServersRepoFactory
+	sessionRepoFn   common.SessionRepoFactory
+	authTokenRepoFn common.AuthTokenRepoFactory
+	targetRepoFn    common.TargetRepoFactory
+	staticRepoFn    common.StaticRepoFactory
+	updateTimes     *sync.Map
+	kms             *kms.Kms
+
+	// exporter is sent records of the connections closed by workers. It is
+	// nil if connection export isn't configured.
+	exporter *flowexport.Exporter
 }
 
 func NewWorkerServiceServer(
 	logger hclog.Logger,
 	serversRepoFn common.ServersRepoFactory,
 	sessionRepoFn common.SessionRepoFactory,
+	authTokenRepoFn common.AuthTokenRepoFactory,
+	targetRepoFn common.TargetRepoFactory,
+	staticRepoFn common.StaticRepoFactory,
 	updateTimes *sync.Map,
-	kms *kms.Kms) *workerServiceServer {
+	kms *kms.Kms,
+	exporter *flowexport.Exporter) *workerServiceServer {
 	return &workerServiceServer{
-		logger:        logger,
-		serversRepoFn: serversRepoFn,
-		sessionRepoFn: sessionRepoFn,
-		updateTimes:   updateTimes,
-		kms:           kms,
+		logger:          logger,
+		serversRepoFn:   serversRepoFn,
+		sessionRepoFn:   sessionRepoFn,
+		authTokenRepoFn: authTokenRepoFn,
+		targetRepoFn:    targetRepoFn,
+		staticRepoFn:    staticRepoFn,
+		updateTimes:     updateTimes,
+		kms:             kms,
+		exporter:        exporter,
 	}
 }
 
@@ -45,19 +73,21 @@ var _ pbs.ServerCoordinationServiceServer = &workerServiceServer{}
 func (ws *workerServiceServer) Status(ctx context.Context, req *pbs.StatusRequest) (*pbs.StatusResponse, error) {
 	ws.logger.Trace("got status request from worker", "name", req.Worker.Name, "address", req.Worker.Address, "jobs", req.GetJobs())
 	ws.updateTimes.Store(req.Worker.Name, time.Now())
+	metric.WorkerActiveConnections.WithLabelValues(req.Worker.Name).Set(float64(req.Worker.GetActiveConnectionCount()))
 	repo, err := ws.serversRepoFn()
 	if err != nil {
 		ws.logger.Error("error getting servers repo", "error", err)
 		return &pbs.StatusResponse{}, status.Errorf(codes.Internal, "Error aqcuiring repo to store worker status: %v", err)
 	}
 	req.Worker.Type = resource.Worker.String()
-	controllers, _, err := repo.UpsertServer(ctx, req.Worker)
+	controllers, _, err := repo.UpsertWorkerStatus(ctx, req.Worker)
 	if err != nil {
 		ws.logger.Error("error storing worker status", "error", err)
 		return &pbs.StatusResponse{}, status.Errorf(codes.Internal, "Error storing worker status: %v", err)
 	}
 	ret := &pbs.StatusResponse{
 		Controllers: controllers,
+		Draining:    req.Worker.GetDraining(),
 	}
 
 	// Happy path
@@ -70,61 +100,113 @@ func (ws *workerServiceServer) Status(ctx context.Context, req *pbs.StatusReques
 		return nil, status.Errorf(codes.Internal, "Error getting session repo: %v", err)
 	}
 
+	reported := make(map[string]*pbs.SessionJobInfo, len(req.GetJobs()))
+	sessionIds := make([]string, 0, len(req.GetJobs()))
+	lastActivity := make(map[string]time.Time)
 	for _, jobStatus := range req.GetJobs() {
 		switch jobStatus.Job.GetType() {
-		// Check for session cancelation
 		case pbs.JOBTYPE_JOBTYPE_SESSION:
 			si := jobStatus.GetJob().GetSessionInfo()
 			if si == nil {
 				return nil, status.Error(codes.Internal, "Error getting session info at status time")
 			}
-			switch si.Status {
-			case pbs.SESSIONSTATUS_SESSIONSTATUS_CANCELING,
-				pbs.SESSIONSTATUS_SESSIONSTATUS_TERMINATED:
+			if si.Status == pbs.SESSIONSTATUS_SESSIONSTATUS_TERMINATED {
 				// No need to see about canceling anything
 				continue
 			}
-			sessionId := si.GetSessionId()
-			sessionInfo, _, err := sessRepo.LookupSession(ctx, sessionId)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "Error looking up session with id %s: %v", sessionId, err)
-			}
-			if sessionInfo == nil {
-				return nil, status.Errorf(codes.Internal, "Unknown session ID %s at status time.", sessionId)
-			}
-			if len(sessionInfo.States) == 0 {
-				return nil, status.Error(codes.Internal, "Empty session states during lookup at status time.")
-			}
-			// If the session from the DB is in canceling status, and we're
-			// here, it means the job is in pending or active; cancel it. If
-			// it's in termianted status something went wrong and we're
-			// mismatched, so ensure we cancel it also.
-			currState := sessionInfo.States[0].Status
-			if currState.ProtoVal() != si.Status {
-				switch currState {
-				case session.StatusCanceling,
-					session.StatusTerminated:
-					// If we're here the job is pending or active so we do want
-					// to actually send a change request
-					ret.JobsRequests = append(ret.JobsRequests, &pbs.JobChangeRequest{
-						Job: &pbs.Job{
-							Type: pbs.JOBTYPE_JOBTYPE_SESSION,
-							JobInfo: &pbs.Job_SessionInfo{
-								SessionInfo: &pbs.SessionJobInfo{
-									SessionId: sessionId,
-									Status:    currState.ProtoVal(),
-								},
-							},
-						},
-						RequestType: pbs.CHANGETYPE_CHANGETYPE_UPDATE_STATE,
-					})
+			reported[si.GetSessionId()] = si
+			sessionIds = append(sessionIds, si.GetSessionId())
+			for _, c := range si.GetConnections() {
+				if c.GetStatus() != pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED && c.GetLastActivityTime() != nil {
+					lastActivity[c.GetConnectionId()] = c.GetLastActivityTime().AsTime()
 				}
 			}
 		}
 	}
+	// Workers report the latest activity in every status, so failing to
+	// record it here is made up for by the next one rather than failing the
+	// status update.
+	if err := sessRepo.RecordConnectionActivity(ctx, lastActivity); err != nil {
+		ws.logger.Error("error recording connection activity", "error", err)
+	}
+	if len(sessionIds) == 0 {
+		return ret, nil
+	}
+
+	// Look up all changes to the reported sessions at once rather than
+	// looking up each session individually.
+	changes, err := sessRepo.ListSessionChanges(ctx, sessionIds)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error looking up session changes at status time: %v", err)
+	}
+	for _, change := range changes {
+		si, ok := reported[change.SessionId]
+		if !ok {
+			continue
+		}
+		if changeReq := sessionChangeRequest(si, change); changeReq != nil {
+			ret.JobsRequests = append(ret.JobsRequests, changeReq)
+		}
+	}
 	return ret, nil
 }
 
+// sessionChangeRequest returns the change request to send to a worker which
+// reported the session job si, or nil if the worker already knows about the
+// change. If the session is canceling or terminated in the database the
+// worker is told to update its status, and any connections closed in the
+// database which the worker reports as still open are returned as closed.
+func sessionChangeRequest(si *pbs.SessionJobInfo, change *session.StateReport) *pbs.JobChangeRequest {
+	var changed bool
+	sessionStatus := si.GetStatus()
+	switch change.Status {
+	case session.StatusCanceling,
+		session.StatusTerminated:
+		// If the session from the DB is in canceling status, and we're
+		// here, it means the job is pending or active; cancel it. If it's
+		// in terminated status something went wrong and we're mismatched,
+		// so ensure we cancel it also.
+		if change.Status.ProtoVal() != sessionStatus {
+			sessionStatus = change.Status.ProtoVal()
+			changed = true
+		}
+	}
+
+	open := make(map[string]bool, len(si.GetConnections()))
+	for _, c := range si.GetConnections() {
+		if c.GetStatus() != pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED {
+			open[c.GetConnectionId()] = true
+		}
+	}
+	var connections []*pbs.Connection
+	for _, id := range change.ClosedConnectionIds {
+		if open[id] {
+			connections = append(connections, &pbs.Connection{
+				ConnectionId: id,
+				Status:       pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED,
+			})
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return &pbs.JobChangeRequest{
+		Job: &pbs.Job{
+			Type: pbs.JOBTYPE_JOBTYPE_SESSION,
+			JobInfo: &pbs.Job_SessionInfo{
+				SessionInfo: &pbs.SessionJobInfo{
+					SessionId:   change.SessionId,
+					Status:      sessionStatus,
+					Connections: connections,
+				},
+			},
+		},
+		RequestType: pbs.CHANGETYPE_CHANGETYPE_UPDATE_STATE,
+	}
+}
+
 func (ws *workerServiceServer) LookupSession(ctx context.Context, req *pbs.LookupSessionRequest) (*pbs.LookupSessionResponse, error) {
 	ws.logger.Trace("got validate session request from worker", "session_id", req.GetSessionId())
 
@@ -133,7 +215,7 @@ func (ws *workerServiceServer) LookupSession(ctx context.Context, req *pbs.Looku
 		return nil, status.Errorf(codes.Internal, "Error getting session repo: %v", err)
 	}
 
-	sessionInfo, authzSummary, err := sessRepo.LookupSession(ctx, req.GetSessionId())
+	sessionInfo, authzSummary, err := sessRepo.LookupSession(ctx, req.GetSessionId(), session.WithDecryptedCertificate(true))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Error looking up session: %v", err)
 	}
@@ -144,22 +226,30 @@ func (ws *workerServiceServer) LookupSession(ctx context.Context, req *pbs.Looku
 		return nil, status.Error(codes.Internal, "Empty session states during lookup.")
 	}
 
+	endpointAddr, err := session.EndpointAddress(sessionInfo.Endpoint)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting session endpoint address: %v", err)
+	}
+
 	resp := &pbs.LookupSessionResponse{
 		Authorization: &targets.SessionAuthorizationData{
 			SessionId:   sessionInfo.GetPublicId(),
 			Certificate: sessionInfo.Certificate,
+			Endpoint:    endpointAddr,
 		},
-		Status:          sessionInfo.States[0].Status.ProtoVal(),
-		Version:         sessionInfo.Version,
-		TofuToken:       string(sessionInfo.TofuToken),
-		Endpoint:        sessionInfo.Endpoint,
-		Expiration:      sessionInfo.ExpirationTime.Timestamp,
-		ConnectionLimit: sessionInfo.ConnectionLimit,
-		ConnectionsLeft: authzSummary.ConnectionLimit,
-		HostId:          sessionInfo.HostId,
-		HostSetId:       sessionInfo.HostSetId,
-		TargetId:        sessionInfo.TargetId,
-		UserId:          sessionInfo.UserId,
+		Status:                   sessionInfo.States[0].Status.ProtoVal(),
+		Version:                  sessionInfo.Version,
+		TofuToken:                string(sessionInfo.TofuToken),
+		Endpoint:                 sessionInfo.Endpoint,
+		Expiration:               sessionInfo.ExpirationTime.Timestamp,
+		ConnectionLimit:          sessionInfo.ConnectionLimit,
+		ConnectionsLeft:          authzSummary.ConnectionLimit,
+		HostId:                   sessionInfo.HostId,
+		HostSetId:                sessionInfo.HostSetId,
+		TargetId:                 sessionInfo.TargetId,
+		UserId:                   sessionInfo.UserId,
+		ConnectionBandwidthLimit: sessionInfo.ConnectionBandwidthLimit,
+		AllowedPorts:             sessionInfo.AllowedPorts,
 	}
 	if resp.ConnectionsLeft != -1 {
 		resp.ConnectionsLeft -= int32(authzSummary.CurrentConnectionCount)
@@ -177,12 +267,105 @@ func (ws *workerServiceServer) LookupSession(ctx context.Context, req *pbs.Looku
 		return nil, status.Errorf(codes.Internal, "Error deriving session key: %v", err)
 	}
 
+	tokenRepo, err := ws.authTokenRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting authtoken repo: %v", err)
+	}
+	workerToken, err := tokenRepo.CreateSessionToken(ctx, sessionInfo.GetPublicId(), sessionInfo.ExpirationTime.GetTimestamp().AsTime().Add(workerTokenGracePeriod))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error creating worker token: %v", err)
+	}
+	resp.WorkerToken = workerToken.Token
+
+	// Workers authenticate to the endpoints of ssh targets with the target's
+	// credentials, which are looked up each time so they can be rotated
+	// without affecting existing sessions.
+	if strings.HasPrefix(sessionInfo.Endpoint, target.SshProtocol+"://") {
+		targetRepo, err := ws.targetRepoFn()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Error getting target repo: %v", err)
+		}
+		cred, err := targetRepo.LookupSshCredential(ctx, sessionInfo.TargetId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Error looking up ssh credential: %v", err)
+		}
+		if cred == nil {
+			return nil, status.Error(codes.FailedPrecondition, "Target has no ssh credentials.")
+		}
+		resp.SshCredential = &pbs.SshCredential{
+			Username:   cred.Username,
+			PrivateKey: cred.PrivateKey,
+			HostKey:    cred.HostKey,
+		}
+	}
+
+	// Likewise for how workers originate TLS to the endpoints of tcp targets.
+	if strings.HasPrefix(sessionInfo.Endpoint, "tcp://") {
+		targetRepo, err := ws.targetRepoFn()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Error getting target repo: %v", err)
+		}
+		endpointTls, err := targetRepo.LookupEndpointTls(ctx, sessionInfo.TargetId)
+		switch {
+		case errors.Is(err, db.ErrInvalidParameter):
+			return nil, status.Errorf(codes.FailedPrecondition, "Target's endpoint TLS settings are incomplete: %v", err)
+		case err != nil:
+			return nil, status.Errorf(codes.Internal, "Error looking up endpoint TLS settings: %v", err)
+		}
+		if endpointTls != nil {
+			resp.EndpointTls = &pbs.EndpointTls{
+				ServerName:        endpointTls.ServerName,
+				CaBundle:          endpointTls.CaBundle,
+				ClientCertificate: endpointTls.ClientCertificate,
+				ClientPrivateKey:  endpointTls.ClientPrivateKey,
+			}
+		}
+	}
+
 	return resp, nil
 }
 
+// checkWorkerToken returns an error unless token was given to a worker when
+// it looked up sessionId, so workers can only update the status of sessions
+// clients have connected to them for.
+func (ws *workerServiceServer) checkWorkerToken(ctx context.Context, sessionId, token string) error {
+	if sessionId == "" || token == "" {
+		return status.Error(codes.PermissionDenied, "Missing session ID or worker token.")
+	}
+	tokenRepo, err := ws.authTokenRepoFn()
+	if err != nil {
+		return status.Errorf(codes.Internal, "Error getting authtoken repo: %v", err)
+	}
+	valid, err := tokenRepo.ValidateSessionToken(ctx, sessionId, token)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Error validating worker token: %v", err)
+	}
+	if !valid {
+		return status.Error(codes.PermissionDenied, "Invalid worker token for session.")
+	}
+	return nil
+}
+
+// checkConnectionSession returns an error unless connectionId is a
+// connection of sessionId.
+func checkConnectionSession(ctx context.Context, sessRepo *session.Repository, sessionId, connectionId string) error {
+	conn, _, err := sessRepo.LookupConnection(ctx, connectionId)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Error looking up connection: %v", err)
+	}
+	if conn == nil || conn.SessionId != sessionId {
+		return status.Error(codes.PermissionDenied, "Unknown connection ID for session.")
+	}
+	return nil
+}
+
 func (ws *workerServiceServer) ActivateSession(ctx context.Context, req *pbs.ActivateSessionRequest) (*pbs.ActivateSessionResponse, error) {
 	ws.logger.Trace("got activate session request from worker", "session_id", req.GetSessionId())
 
+	if err := ws.checkWorkerToken(ctx, req.GetSessionId(), req.GetWorkerToken()); err != nil {
+		return nil, err
+	}
+
 	sessRepo, err := ws.sessionRepoFn()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error getting session repo: %v", err)
@@ -220,6 +403,10 @@ func (ws *workerServiceServer) ActivateSession(ctx context.Context, req *pbs.Act
 func (ws *workerServiceServer) AuthorizeConnection(ctx context.Context, req *pbs.AuthorizeConnectionRequest) (*pbs.AuthorizeConnectionResponse, error) {
 	ws.logger.Trace("got authorize connection request from worker", "session_id", req.GetSessionId())
 
+	if err := ws.checkWorkerToken(ctx, req.GetSessionId(), req.GetWorkerToken()); err != nil {
+		return nil, err
+	}
+
 	sessRepo, err := ws.sessionRepoFn()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error getting session repo: %v", err)
@@ -256,19 +443,38 @@ func (ws *workerServiceServer) AuthorizeConnection(ctx context.Context, req *pbs
 func (ws *workerServiceServer) ConnectConnection(ctx context.Context, req *pbs.ConnectConnectionRequest) (*pbs.ConnectConnectionResponse, error) {
 	ws.logger.Trace("got connection established information from worker", "connection_id", req.GetConnectionId())
 
+	if err := ws.checkWorkerToken(ctx, req.GetSessionId(), req.GetWorkerToken()); err != nil {
+		return nil, err
+	}
+
 	sessRepo, err := ws.sessionRepoFn()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error getting session repo: %v", err)
 	}
+	if err := checkConnectionSession(ctx, sessRepo, req.GetSessionId(), req.GetConnectionId()); err != nil {
+		return nil, err
+	}
 
-	connectionInfo, connStates, err := sessRepo.ConnectConnection(ctx, session.ConnectWith{
+	connectWith := session.ConnectWith{
 		ConnectionId:       req.GetConnectionId(),
 		ClientTcpAddress:   req.GetClientTcpAddress(),
 		ClientTcpPort:      req.GetClientTcpPort(),
 		EndpointTcpAddress: req.GetEndpointTcpAddress(),
 		EndpointTcpPort:    req.GetEndpointTcpPort(),
-	})
-	if err != nil {
+		Protocol:           req.GetProtocol(),
+	}
+	if md := req.GetProtocolMetadata(); len(md.GetFields()) > 0 {
+		js, err := protojson.Marshal(md)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "error marshaling protocol metadata: %v", err)
+		}
+		connectWith.ProtocolMetadata = string(js)
+	}
+	connectionInfo, connStates, err := sessRepo.ConnectConnection(ctx, connectWith)
+	switch {
+	case errors.Is(err, session.ErrConnectionConflict):
+		return nil, status.Errorf(codes.FailedPrecondition, "Connection can't be marked as connected: %v.", err)
+	case err != nil:
 		return nil, err
 	}
 	if connectionInfo == nil {
@@ -286,42 +492,132 @@ func (ws *workerServiceServer) ConnectConnection(ctx context.Context, req *pbs.C
 		"client_tcp_port", req.ClientTcpPort,
 	}
 	switch req.GetType() {
-	case "tcp":
+	case "tcp", "udp":
 		loggerPairs = append(loggerPairs,
 			"endpoint_tcp_address", connectionInfo.EndpointTcpAddress,
 			"endpoint_tcp_port", connectionInfo.EndpointTcpPort,
 		)
 	}
 
+	if req.GetProtocol() != "" {
+		loggerPairs = append(loggerPairs, "protocol", req.GetProtocol())
+	}
+
 	ws.logger.Info("connection established", loggerPairs...)
 
 	return ret, nil
 }
 
+// ReportEgressViolation records that a worker refused to dial an address for
+// a session because it isn't the session's endpoint, and emits a security
+// event for it.
+func (ws *workerServiceServer) ReportEgressViolation(ctx context.Context, req *pbs.ReportEgressViolationRequest) (*pbs.ReportEgressViolationResponse, error) {
+	ws.logger.Trace("got egress violation from worker", "session_id", req.GetSessionId(), "address", req.GetAddress())
+
+	if err := ws.checkWorkerToken(ctx, req.GetSessionId(), req.GetWorkerToken()); err != nil {
+		return nil, err
+	}
+
+	sessRepo, err := ws.sessionRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting session repo: %v", err)
+	}
+	if req.GetConnectionId() != "" {
+		if err := checkConnectionSession(ctx, sessRepo, req.GetSessionId(), req.GetConnectionId()); err != nil {
+			return nil, err
+		}
+	}
+	err = sessRepo.RecordEgressViolation(ctx, &session.EgressViolation{
+		SessionId:    req.GetSessionId(),
+		ConnectionId: req.GetConnectionId(),
+		WorkerId:     req.GetWorkerId(),
+		Endpoint:     req.GetEndpoint(),
+		Address:      req.GetAddress(),
+	})
+	switch {
+	case errors.Is(err, db.ErrInvalidParameter):
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid egress violation: %v.", err)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "Error recording egress violation: %v", err)
+	}
+
+	event.WriteSecurity(ctx, "workers.(workerServiceServer).ReportEgressViolation", map[string]interface{}{
+		"kind":          event.EgressViolationKind,
+		"session_id":    req.GetSessionId(),
+		"connection_id": req.GetConnectionId(),
+		"worker_id":     req.GetWorkerId(),
+		"endpoint":      req.GetEndpoint(),
+		"address":       req.GetAddress(),
+	})
+	return &pbs.ReportEgressViolationResponse{}, nil
+}
+
 func (ws *workerServiceServer) CloseConnection(ctx context.Context, req *pbs.CloseConnectionRequest) (*pbs.CloseConnectionResponse, error) {
 	numCloses := len(req.GetCloseRequestData())
 	if numCloses == 0 {
 		return &pbs.CloseConnectionResponse{}, nil
 	}
 
-	closeWiths := make([]session.CloseWith, 0, numCloses)
-	closeIds := make([]string, 0, numCloses)
+	sessRepo, err := ws.sessionRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting session repo: %v", err)
+	}
 
+	// Group the closes by session so each session's worker token is checked,
+	// and its connections are listed, once
+	var sessionIds []string
+	bySession := make(map[string][]*pbs.CloseConnectionRequestData, numCloses)
 	for _, v := range req.GetCloseRequestData() {
-		closeIds = append(closeIds, v.GetConnectionId())
-		closeWiths = append(closeWiths, session.CloseWith{
-			ConnectionId: v.GetConnectionId(),
-			BytesUp:      v.GetBytesUp(),
-			BytesDown:    v.GetBytesDown(),
-			ClosedReason: session.ClosedReason(v.GetReason()),
-		})
+		if _, ok := bySession[v.GetSessionId()]; !ok {
+			sessionIds = append(sessionIds, v.GetSessionId())
+		}
+		bySession[v.GetSessionId()] = append(bySession[v.GetSessionId()], v)
 	}
-	ws.logger.Trace("got connection close information from worker", "connection_ids", closeIds)
 
-	sessRepo, err := ws.sessionRepoFn()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting session repo: %v", err)
+	closeWiths := make([]session.CloseWith, 0, numCloses)
+	closeIds := make([]string, 0, numCloses)
+	// The connections being closed which weren't closed already, kept to
+	// be exported once they are
+	var exports []*session.Connection
+	for _, sessionId := range sessionIds {
+		checkedTokens := make(map[string]bool)
+		for _, v := range bySession[sessionId] {
+			if checkedTokens[v.GetWorkerToken()] {
+				continue
+			}
+			if err := ws.checkWorkerToken(ctx, sessionId, v.GetWorkerToken()); err != nil {
+				return nil, err
+			}
+			checkedTokens[v.GetWorkerToken()] = true
+		}
+		conns, err := sessRepo.ListConnections(ctx, sessionId, session.WithLimit(-1))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Error looking up connections: %v", err)
+		}
+		sessionConns := make(map[string]*session.Connection, len(conns))
+		for _, c := range conns {
+			sessionConns[c.PublicId] = c
+		}
+		for _, v := range bySession[sessionId] {
+			conn, ok := sessionConns[v.GetConnectionId()]
+			if !ok {
+				return nil, status.Error(codes.PermissionDenied, "Unknown connection ID for session.")
+			}
+			if ws.exporter != nil && conn.ClosedReason == "" {
+				exports = append(exports, conn)
+			}
+			closeIds = append(closeIds, v.GetConnectionId())
+			closeWiths = append(closeWiths, session.CloseWith{
+				ConnectionId:  v.GetConnectionId(),
+				BytesUp:       v.GetBytesUp(),
+				BytesDown:     v.GetBytesDown(),
+				DatagramsUp:   v.GetDatagramsUp(),
+				DatagramsDown: v.GetDatagramsDown(),
+				ClosedReason:  session.ClosedReason(v.GetReason()),
+			})
+		}
 	}
+	ws.logger.Trace("got connection close information from worker", "connection_ids", closeIds)
 
 	closeInfos, err := sessRepo.CloseConnections(ctx, closeWiths)
 	if err != nil {
@@ -331,7 +627,7 @@ func (ws *workerServiceServer) CloseConnection(ctx context.Context, req *pbs.Clo
 		return nil, status.Error(codes.Internal, "Invalid close connection response.")
 	}
 
-	closeData := make([]*pbs.CloseConnectionResponseData, 0, numCloses)
+	closeData := make([]*pbs.CloseConnectionResponseData, 0, len(closeInfos))
 	for _, v := range closeInfos {
 		if v.Connection == nil {
 			return nil, status.Errorf(codes.Internal, "No connection found while closing one of the connection IDs: %v", closeIds)
@@ -349,9 +645,70 @@ func (ws *workerServiceServer) CloseConnection(ctx context.Context, req *pbs.Clo
 		ws.logger.Info("connection closed", "connection_id", v.ConnectionId)
 	}
 
+	if len(exports) > 0 {
+		ws.exportConnections(ctx, sessRepo, exports, closeInfos)
+	}
+
 	ret := &pbs.CloseConnectionResponse{
 		CloseResponseData: closeData,
 	}
 
 	return ret, nil
 }
+
+// exportConnections sends records of conns, which have just been closed, to
+// the exporter. closeInfos are the results of closing them. Connections
+// are exported on a best effort basis; errors are logged rather than failing
+// the close.
+func (ws *workerServiceServer) exportConnections(ctx context.Context, sessRepo *session.Repository, conns []*session.Connection, closeInfos []session.CloseConnectionResp) {
+	closed := make(map[string]session.CloseConnectionResp, len(closeInfos))
+	for _, v := range closeInfos {
+		closed[v.Connection.GetPublicId()] = v
+	}
+	sessions := make(map[string]*session.Session)
+	records := make([]*flowexport.Record, 0, len(conns))
+	for _, c := range conns {
+		info, ok := closed[c.PublicId]
+		if !ok {
+			continue
+		}
+		sess, ok := sessions[c.SessionId]
+		if !ok {
+			var err error
+			sess, _, err = sessRepo.LookupSession(ctx, c.SessionId)
+			if err != nil {
+				ws.logger.Error("error looking up session of exported connection", "session_id", c.SessionId, "error", err)
+			}
+			sessions[c.SessionId] = sess
+		}
+		// The transport protocol isn't stored, but only udp connections
+		// count datagrams.
+		protocol := "tcp"
+		if info.Connection.DatagramsUp > 0 || info.Connection.DatagramsDown > 0 {
+			protocol = "udp"
+		}
+		r := &flowexport.Record{
+			ConnectionId:    c.PublicId,
+			SessionId:       c.SessionId,
+			Protocol:        protocol,
+			ClientAddress:   c.ClientTcpAddress,
+			ClientPort:      c.ClientTcpPort,
+			EndpointAddress: c.EndpointTcpAddress,
+			EndpointPort:    c.EndpointTcpPort,
+			BytesUp:         info.Connection.BytesUp,
+			BytesDown:       info.Connection.BytesDown,
+			DatagramsUp:     info.Connection.DatagramsUp,
+			DatagramsDown:   info.Connection.DatagramsDown,
+			StartTime:       c.CreateTime.GetTimestamp().AsTime(),
+			EndTime:         info.ConnectionStates[0].StartTime.GetTimestamp().AsTime(),
+			ClosedReason:    info.Connection.ClosedReason,
+		}
+		if sess != nil {
+			r.UserId = sess.UserId
+			r.TargetId = sess.TargetId
+			r.ScopeId = sess.ScopeId
+		}
+		records = append(records, r)
+	}
+	ws.exporter.Add(records...)
+}
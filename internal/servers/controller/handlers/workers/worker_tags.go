@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/servers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func (ws *workerServiceServer) SetWorkerTags(ctx context.Context, req *pbs.SetWorkerTagsRequest) (*pbs.SetWorkerTagsResponse, error) {
+	ws.logger.Trace("got set worker tags request", "name", req.GetWorkerName())
+	if req.GetWorkerName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Missing worker name.")
+	}
+
+	if !workerMayActFor(ctx, req.GetWorkerName()) {
+		return nil, status.Error(codes.PermissionDenied, "Workers can only set their own tags.")
+	}
+
+	repo, err := ws.serversRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting servers repo: %v", err)
+	}
+	err = repo.SetWorkerTags(ctx, req.GetWorkerName(), req.GetTags())
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "Worker %q has never reported its status.", req.GetWorkerName())
+	case errors.Is(err, db.ErrInvalidParameter):
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid tags: %v", err)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "Error setting worker tags: %v", err)
+	}
+	ws.logger.Info("worker tags set", "name", req.GetWorkerName(), "tags", servers.TagsToMap(req.GetTags()))
+	return &pbs.SetWorkerTagsResponse{}, nil
+}
+
+// workerMayActFor returns false if the worker making the request
+// authenticated with a certificate issued to a worker other than name.
+func workerMayActFor(ctx context.Context, name string) bool {
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok &&
+			tlsInfo.State.NegotiatedProtocol == servers.WorkerAuthPkiProto &&
+			len(tlsInfo.State.VerifiedChains) > 0 &&
+			tlsInfo.State.VerifiedChains[0][0].Subject.CommonName != name {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,208 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (ws *workerServiceServer) RegisterWorkerTargets(ctx context.Context, req *pbs.RegisterWorkerTargetsRequest) (*pbs.RegisterWorkerTargetsResponse, error) {
+	ws.logger.Trace("got register worker targets request", "name", req.GetWorkerName())
+	if req.GetWorkerName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Missing worker name.")
+	}
+	if !workerMayActFor(ctx, req.GetWorkerName()) {
+		return nil, status.Error(codes.PermissionDenied, "Workers can only register their own targets.")
+	}
+	if err := validateWorkerTargets(req.GetTargets()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid targets: %v", err)
+	}
+
+	serversRepo, err := ws.serversRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting servers repo: %v", err)
+	}
+	targetRepo, err := ws.targetRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting target repo: %v", err)
+	}
+	staticRepo, err := ws.staticRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting static host repo: %v", err)
+	}
+
+	// Targets the worker registered before, such as before it was restarted
+	// without shutting down cleanly, are replaced
+	if _, err := deregisterWorkerTargets(ctx, serversRepo, targetRepo, staticRepo, req.GetWorkerName()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Error deregistering previous worker targets: %v", err)
+	}
+
+	ret := &pbs.RegisterWorkerTargetsResponse{TargetIds: make(map[string]string, len(req.GetTargets()))}
+	failed := &errors.Multi{}
+	for i, wt := range req.GetTargets() {
+		id, err := registerWorkerTarget(ctx, serversRepo, targetRepo, staticRepo, req.GetWorkerName(), wt)
+		if err != nil {
+			failed.Append(i, wt.GetName(), err)
+			continue
+		}
+		ret.TargetIds[wt.GetName()] = id
+	}
+	if len(ret.GetTargetIds()) > 0 {
+		ws.logger.Info("worker targets registered", "name", req.GetWorkerName(), "targets", ret.GetTargetIds())
+	}
+	err = failed.ErrorOrNil()
+	switch {
+	case errors.Is(err, db.ErrInvalidParameter), errors.Is(err, db.ErrNotUnique):
+		return nil, status.Errorf(codes.InvalidArgument, "Error registering worker targets: %v", err)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "Error registering worker targets: %v", err)
+	}
+	return ret, nil
+}
+
+func (ws *workerServiceServer) DeregisterWorkerTargets(ctx context.Context, req *pbs.DeregisterWorkerTargetsRequest) (*pbs.DeregisterWorkerTargetsResponse, error) {
+	ws.logger.Trace("got deregister worker targets request", "name", req.GetWorkerName())
+	if req.GetWorkerName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Missing worker name.")
+	}
+	if !workerMayActFor(ctx, req.GetWorkerName()) {
+		return nil, status.Error(codes.PermissionDenied, "Workers can only deregister their own targets.")
+	}
+
+	serversRepo, err := ws.serversRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting servers repo: %v", err)
+	}
+	targetRepo, err := ws.targetRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting target repo: %v", err)
+	}
+	staticRepo, err := ws.staticRepoFn()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error getting static host repo: %v", err)
+	}
+	deregistered, err := deregisterWorkerTargets(ctx, serversRepo, targetRepo, staticRepo, req.GetWorkerName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Error deregistering worker targets: %v", err)
+	}
+	if deregistered > 0 {
+		ws.logger.Info("worker targets deregistered", "name", req.GetWorkerName(), "targets_deregistered", deregistered)
+	}
+	return &pbs.DeregisterWorkerTargetsResponse{TargetsDeregistered: uint32(deregistered)}, nil
+}
+
+// validateWorkerTargets returns an error if any of targets is incomplete or
+// they don't have unique names.
+func validateWorkerTargets(targets []*pbs.WorkerTarget) error {
+	names := make(map[string]bool, len(targets))
+	for i, wt := range targets {
+		switch {
+		case strings.TrimSpace(wt.GetName()) == "":
+			return fmt.Errorf("target %d is missing a name", i)
+		case names[wt.GetName()]:
+			return fmt.Errorf("target %q is defined more than once", wt.GetName())
+		case strings.TrimSpace(wt.GetAddress()) == "":
+			return fmt.Errorf("target %q is missing an address", wt.GetName())
+		case wt.GetPort() == 0 || wt.GetPort() > 65535:
+			return fmt.Errorf("target %q has an invalid port %d", wt.GetName(), wt.GetPort())
+		case !strings.HasPrefix(wt.GetScopeId(), scope.Project.Prefix()+"_"):
+			return fmt.Errorf("target %q must have the id of a project as its scope id", wt.GetName())
+		}
+		names[wt.GetName()] = true
+	}
+	return nil
+}
+
+// registerWorkerTarget creates a tcp target for wt, with a static host
+// catalog of its own holding its address, and records it as registered by
+// the named worker. It returns the id of the target. Nothing is left behind
+// if it fails.
+func registerWorkerTarget(ctx context.Context, serversRepo *servers.Repository, targetRepo *target.Repository, staticRepo *static.Repository, workerName string, wt *pbs.WorkerTarget) (string, error) {
+	scopeId := wt.GetScopeId()
+	catalog, err := static.NewHostCatalog(scopeId, static.WithDescription(fmt.Sprintf("Address of target %q registered by worker %q", wt.GetName(), workerName)))
+	if err != nil {
+		return "", err
+	}
+	if catalog, err = staticRepo.CreateCatalog(ctx, catalog); err != nil {
+		return "", err
+	}
+
+	var targetId string
+	err = func() error {
+		h, err := static.NewHost(catalog.GetPublicId(), static.WithAddress(strings.TrimSpace(wt.GetAddress())))
+		if err != nil {
+			return err
+		}
+		if h, err = staticRepo.CreateHost(ctx, scopeId, h); err != nil {
+			return err
+		}
+		set, err := static.NewHostSet(catalog.GetPublicId())
+		if err != nil {
+			return err
+		}
+		if set, err = staticRepo.CreateSet(ctx, scopeId, set); err != nil {
+			return err
+		}
+		if _, err := staticRepo.AddSetMembers(ctx, scopeId, set.GetPublicId(), set.GetVersion(), []string{h.GetPublicId()}); err != nil {
+			return err
+		}
+
+		description := wt.GetDescription()
+		if description == "" {
+			description = fmt.Sprintf("Registered by worker %q", workerName)
+		}
+		t, err := target.NewTcpTarget(scopeId,
+			target.WithName(wt.GetName()),
+			target.WithDescription(description),
+			target.WithDefaultPort(wt.GetPort()))
+		if err != nil {
+			return err
+		}
+		created, _, err := targetRepo.CreateTcpTarget(ctx, t, target.WithHostSets([]string{set.GetPublicId()}))
+		if err != nil {
+			return err
+		}
+		targetId = created.GetPublicId()
+		_, err = serversRepo.AddWorkerTarget(ctx, workerName, targetId, catalog.GetPublicId())
+		return err
+	}()
+	if err != nil {
+		if targetId != "" {
+			_, _ = targetRepo.DeleteTarget(ctx, targetId)
+		}
+		_, _ = staticRepo.DeleteCatalog(ctx, catalog.GetPublicId())
+		return "", err
+	}
+	return targetId, nil
+}
+
+// deregisterWorkerTargets deletes the targets registered by the named worker
+// and the host catalogs holding their addresses, and returns the number of
+// targets deleted.
+func deregisterWorkerTargets(ctx context.Context, serversRepo *servers.Repository, targetRepo *target.Repository, staticRepo *static.Repository, workerName string) (int, error) {
+	wts, err := serversRepo.ListWorkerTargets(ctx, workerName)
+	if err != nil {
+		return 0, err
+	}
+	var deleted int
+	for _, wt := range wts {
+		if _, err := targetRepo.DeleteTarget(ctx, wt.TargetId); err != nil {
+			return deleted, err
+		}
+		if _, err := staticRepo.DeleteCatalog(ctx, wt.HostCatalogId); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+)
+
+// wrapHandlerWithIfMatch makes updates, deletes and custom actions on a
+// resource conditional on the resource's version when the request has an
+// If-Match header with the resource's ETag. The resource is read with the
+// request's credentials first, and the request is rejected with a 412 if its
+// version isn't one of those listed. Updates and custom actions without a
+// version in their body are given the matched version, so the repository's
+// version check rejects them, again with a 412, if the resource changes
+// before they are applied; if it's deleted instead they fail with a 404.
+// Deletes aren't versioned in the repositories, so a delete can still remove
+// a resource changed after it was read.
+func wrapHandlerWithIfMatch(h http.Handler, c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+		resourcePath, ok := ifMatchResourcePath(r)
+		if ifMatch == "" || ifMatch == "*" || !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		writeErr := func(err error) {
+			handlers.ErrorHandler(c.logger)(r.Context(), nil, apiErrorMarshaler, w, r, err)
+		}
+		versions, err := parseIfMatch(ifMatch)
+		if err != nil {
+			writeErr(handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"If-Match": err.Error()}))
+			return
+		}
+
+		current, version, versioned := readIfMatchVersion(h, r, resourcePath)
+		if current.code != http.StatusOK {
+			current.copyTo(w)
+			return
+		}
+		if !versioned {
+			writeErr(handlers.PreconditionFailedErrorf("The resource at %s isn't versioned.", resourcePath))
+			return
+		}
+		if !versions[version] {
+			writeErr(handlers.PreconditionFailedErrorf("The resource's version is %d, which doesn't match the If-Match header.", version))
+			return
+		}
+
+		if r.Method != http.MethodDelete {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				writeErr(handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"body": "Unable to read the request body."}))
+				return
+			}
+			fields := map[string]json.RawMessage{}
+			if len(bytes.TrimSpace(body)) > 0 {
+				if err := json.Unmarshal(body, &fields); err != nil {
+					writeErr(handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"body": "Unable to parse the request body."}))
+					return
+				}
+			}
+			if v, ok := fields["version"]; ok {
+				var reqVersion uint32
+				if err := json.Unmarshal(v, &reqVersion); err != nil || reqVersion != version {
+					writeErr(handlers.PreconditionFailedErrorf("The request's version doesn't match the If-Match header."))
+					return
+				}
+			} else {
+				fields["version"] = json.RawMessage(strconv.FormatUint(uint64(version), 10))
+				if body, err = json.Marshal(fields); err != nil {
+					writeErr(err)
+					return
+				}
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+
+		// The repositories report a version mismatch as the resource not
+		// being found, so a 404 is only a mismatch if the resource can still
+		// be read at another version; if it was deleted the 404 stands.
+		resp := newBufferedResponse()
+		h.ServeHTTP(resp, r)
+		if resp.code == http.StatusNotFound && r.Method != http.MethodDelete {
+			if _, v, ok := readIfMatchVersion(h, r, resourcePath); ok && v != version {
+				writeErr(handlers.PreconditionFailedErrorf("The resource changed after its version was checked."))
+				return
+			}
+		}
+		resp.copyTo(w)
+	})
+}
+
+// readIfMatchVersion reads the resource at resourcePath with r's credentials.
+// It returns the response and, if the resource was read, its version and
+// whether it has one.
+func readIfMatchVersion(h http.Handler, r *http.Request, resourcePath string) (*bufferedResponse, uint32, bool) {
+	read := r.Clone(r.Context())
+	read.Method = http.MethodGet
+	read.URL.Path = resourcePath
+	read.URL.RawPath = ""
+	read.Body = http.NoBody
+	read.ContentLength = 0
+	current := newBufferedResponse()
+	h.ServeHTTP(current, read)
+	if current.code != http.StatusOK {
+		return current, 0, false
+	}
+	var resource struct {
+		Item struct {
+			Version *uint32 `json:"version"`
+		} `json:"item"`
+	}
+	if err := json.Unmarshal(current.body.Bytes(), &resource); err != nil || resource.Item.Version == nil {
+		return current, 0, false
+	}
+	return current, *resource.Item.Version, true
+}
+
+// ifMatchResourcePath returns the path of the resource a request to update,
+// delete or perform a custom action on a resource is for.
+func ifMatchResourcePath(r *http.Request) (string, bool) {
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+	if len(segments) != 2 || segments[1] == "" {
+		return "", false
+	}
+	switch r.Method {
+	case http.MethodPatch, http.MethodDelete:
+		if strings.Contains(segments[1], ":") {
+			return "", false
+		}
+		return r.URL.Path, true
+	case http.MethodPost:
+		i := strings.LastIndex(r.URL.Path, ":")
+		if !strings.Contains(segments[1], ":") {
+			return "", false
+		}
+		return r.URL.Path[:i], true
+	}
+	return "", false
+}
+
+// parseIfMatch returns the versions in the entity tags of an If-Match header.
+// Weak entity tags never match, so they are ignored.
+func parseIfMatch(h string) (map[uint32]bool, error) {
+	versions := make(map[uint32]bool)
+	for _, tag := range strings.Split(h, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			return nil, fmt.Errorf("Invalid entity tag %s.", tag)
+		}
+		v, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid entity tag %s.", tag)
+		}
+		versions[uint32(v)] = true
+	}
+	return versions, nil
+}
+
+// bufferedResponse is an http.ResponseWriter keeping the response in memory
+// so it can be inspected before it is written.
+type bufferedResponse struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header), code: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+func (b *bufferedResponse) WriteHeader(code int) { b.code = code }
+
+func (b *bufferedResponse) copyTo(w http.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(b.code)
+	io.Copy(w, &b.body)
+}
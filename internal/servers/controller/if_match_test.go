@@ -0,0 +1,304 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapHandlerWithIfMatch(t *testing.T) {
+	c := &Controller{logger: hclog.NewNullLogger()}
+	const currentVersion = 3
+	var gotBody map[string]interface{}
+	h := wrapHandlerWithIfMatch(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/users/u_missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"status": 404, "code": "NotFound"}`)
+		case r.Method == http.MethodGet:
+			fmt.Fprintf(w, `{"item": {"id": "u_1234567890", "version": %d}}`, currentVersion)
+		default:
+			gotBody = nil
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			if len(body) > 0 {
+				require.NoError(t, json.Unmarshal(body, &gotBody))
+			}
+			if v, ok := gotBody["version"]; ok && v != float64(currentVersion) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}), c)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		ifMatch     string
+		body        string
+		wantCode    int
+		wantErr     string
+		wantVersion interface{}
+	}{
+		{
+			name:     "no-if-match",
+			method:   http.MethodPatch,
+			path:     "/v1/users/u_1234567890",
+			body:     `{"name": "foo"}`,
+			wantCode: http.StatusTeapot,
+		},
+		{
+			name:     "any",
+			method:   http.MethodPatch,
+			path:     "/v1/users/u_1234567890",
+			ifMatch:  "*",
+			body:     `{"name": "foo"}`,
+			wantCode: http.StatusTeapot,
+		},
+		{
+			name:        "update-matches",
+			method:      http.MethodPatch,
+			path:        "/v1/users/u_1234567890",
+			ifMatch:     `"3"`,
+			body:        `{"name": "foo"}`,
+			wantCode:    http.StatusTeapot,
+			wantVersion: float64(currentVersion),
+		},
+		{
+			name:        "one-of-several-matches",
+			method:      http.MethodPatch,
+			path:        "/v1/users/u_1234567890",
+			ifMatch:     `"2", W/"3", "3"`,
+			body:        `{"name": "foo"}`,
+			wantCode:    http.StatusTeapot,
+			wantVersion: float64(currentVersion),
+		},
+		{
+			name:     "update-stale",
+			method:   http.MethodPatch,
+			path:     "/v1/users/u_1234567890",
+			ifMatch:  `"2"`,
+			body:     `{"name": "foo"}`,
+			wantCode: http.StatusPreconditionFailed,
+			wantErr:  "FailedPrecondition",
+		},
+		{
+			name:     "weak",
+			method:   http.MethodPatch,
+			path:     "/v1/users/u_1234567890",
+			ifMatch:  `W/"3"`,
+			body:     `{"name": "foo"}`,
+			wantCode: http.StatusPreconditionFailed,
+			wantErr:  "FailedPrecondition",
+		},
+		{
+			name:     "body-version-differs",
+			method:   http.MethodPatch,
+			path:     "/v1/users/u_1234567890",
+			ifMatch:  `"3"`,
+			body:     `{"name": "foo", "version": 2}`,
+			wantCode: http.StatusPreconditionFailed,
+			wantErr:  "FailedPrecondition",
+		},
+		{
+			name:     "invalid",
+			method:   http.MethodPatch,
+			path:     "/v1/users/u_1234567890",
+			ifMatch:  `3`,
+			body:     `{"name": "foo"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  "InvalidArgument",
+		},
+		{
+			name:        "custom-action",
+			method:      http.MethodPost,
+			path:        "/v1/users/u_1234567890:add-accounts",
+			ifMatch:     `"3"`,
+			body:        `{"account_ids": ["acctpw_1234567890"]}`,
+			wantCode:    http.StatusTeapot,
+			wantVersion: float64(currentVersion),
+		},
+		{
+			name:     "delete-matches",
+			method:   http.MethodDelete,
+			path:     "/v1/users/u_1234567890",
+			ifMatch:  `"3"`,
+			wantCode: http.StatusTeapot,
+		},
+		{
+			name:     "delete-stale",
+			method:   http.MethodDelete,
+			path:     "/v1/users/u_1234567890",
+			ifMatch:  `"4"`,
+			wantCode: http.StatusPreconditionFailed,
+			wantErr:  "FailedPrecondition",
+		},
+		{
+			name:     "missing",
+			method:   http.MethodDelete,
+			path:     "/v1/users/u_missing",
+			ifMatch:  `"3"`,
+			wantCode: http.StatusNotFound,
+			wantErr:  "NotFound",
+		},
+		{
+			name:     "create-ignored",
+			method:   http.MethodPost,
+			path:     "/v1/users",
+			ifMatch:  `"3"`,
+			body:     `{"name": "foo"}`,
+			wantCode: http.StatusTeapot,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			gotBody = nil
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			assert.Equal(tt.wantCode, w.Code)
+			if tt.wantVersion != nil {
+				assert.Equal(tt.wantVersion, gotBody["version"])
+				var sent map[string]interface{}
+				require.NoError(json.Unmarshal([]byte(tt.body), &sent))
+				for k, v := range sent {
+					assert.Equal(v, gotBody[k], "other fields are kept")
+				}
+			}
+			if tt.wantErr == "" {
+				return
+			}
+			var body map[string]interface{}
+			require.NoError(json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(tt.wantErr, body["code"])
+		})
+	}
+}
+
+func TestWrapHandlerWithIfMatch_Races(t *testing.T) {
+	c := &Controller{logger: hclog.NewNullLogger()}
+	const path = "/v1/users/u_1234567890"
+	var version uint32
+	var exists bool
+	// race is run before the write, between the If-Match check and the
+	// write being applied.
+	var race func()
+	h := wrapHandlerWithIfMatch(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && race != nil {
+			race()
+			race = nil
+		}
+		notFound := func() {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"status": 404, "code": "NotFound"}`)
+		}
+		if !exists {
+			notFound()
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"item": {"id": "u_1234567890", "version": %d}}`, version)
+		case http.MethodDelete:
+			exists = false
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			var body struct {
+				Version uint32 `json:"version"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			// Like the repositories, a version mismatch is reported as the
+			// resource not being found.
+			if body.Version != version {
+				notFound()
+				return
+			}
+			version++
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}), c)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		race     func()
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "update-no-race",
+			method:   http.MethodPatch,
+			path:     path,
+			body:     `{"name": "foo"}`,
+			wantCode: http.StatusTeapot,
+		},
+		{
+			name:     "update-races-update",
+			method:   http.MethodPatch,
+			path:     path,
+			body:     `{"name": "foo"}`,
+			race:     func() { version++ },
+			wantCode: http.StatusPreconditionFailed,
+			wantErr:  "FailedPrecondition",
+		},
+		{
+			name:     "update-races-delete",
+			method:   http.MethodPatch,
+			path:     path,
+			body:     `{"name": "foo"}`,
+			race:     func() { exists = false },
+			wantCode: http.StatusNotFound,
+			wantErr:  "NotFound",
+		},
+		{
+			name:     "custom-action-races-delete",
+			method:   http.MethodPost,
+			path:     path + ":add-accounts",
+			body:     `{"account_ids": ["acctpw_1234567890"]}`,
+			race:     func() { exists = false },
+			wantCode: http.StatusNotFound,
+			wantErr:  "NotFound",
+		},
+		{
+			name:     "delete-races-delete",
+			method:   http.MethodDelete,
+			path:     path,
+			race:     func() { exists = false },
+			wantCode: http.StatusNotFound,
+			wantErr:  "NotFound",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			version, exists, race = 3, true, tt.race
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("If-Match", `"3"`)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			assert.Equal(tt.wantCode, w.Code)
+			if tt.wantErr == "" {
+				return
+			}
+			var body map[string]interface{}
+			require.NoError(json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(tt.wantErr, body["code"])
+		})
+	}
+}
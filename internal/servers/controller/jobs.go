@@ -0,0 +1,444 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/scheduler"
+)
+
+// In the future we could make this configurable
+const (
+	terminationInterval = 1 * time.Minute
+
+	// idleConnectionInterval is how often connections are checked for being
+	// idle. Connections can stay open up to this long past their target's
+	// idle timeout.
+	idleConnectionInterval = 1 * time.Minute
+
+	// neverConnectedInterval is how often pending sessions are checked for
+	// having outlived their target's authorization window.
+	neverConnectedInterval = 1 * time.Minute
+
+	sessionTokenCleanupInterval = 10 * time.Minute
+
+	staleAuthTokenCleanupInterval = 1 * time.Hour
+
+	sessionReplicationInterval = 1 * time.Minute
+
+	rateLimitCleanupInterval = 10 * time.Minute
+	// rateLimitIdleTime is how long a rate limit bucket must go unused before
+	// it is deleted. It is long enough for any configured bucket to refill.
+	rateLimitIdleTime = 1 * time.Hour
+
+	// authTokenAccessFlushInterval is how often the last access times of
+	// the auth tokens validated by the controller are written.
+	authTokenAccessFlushInterval = 1 * time.Minute
+
+	// quotaFlushInterval is how often the API requests counted by the
+	// controller are added to the shared counts of their organizations.
+	quotaFlushInterval = 10 * time.Second
+
+	// kmsKeyUsageFlushInterval is how often the key operations counted by
+	// the controller are added to the shared counts of their key versions.
+	kmsKeyUsageFlushInterval = 1 * time.Minute
+)
+
+// registerJobs registers the controller's background jobs with its
+// scheduler.
+func (c *Controller) registerJobs() error {
+	if err := c.scheduler.RegisterJob(c.baseContext, &terminateCompletedSessionsJob{c: c}, terminationInterval); err != nil {
+		return err
+	}
+	if err := c.scheduler.RegisterJob(c.baseContext, &closeIdleConnectionsJob{c: c}, idleConnectionInterval); err != nil {
+		return err
+	}
+	if err := c.scheduler.RegisterJob(c.baseContext, &cancelNeverConnectedSessionsJob{c: c}, neverConnectedInterval); err != nil {
+		return err
+	}
+	if err := c.scheduler.RegisterJob(c.baseContext, &deleteExpiredSessionTokensJob{c: c}, sessionTokenCleanupInterval); err != nil {
+		return err
+	}
+	if err := c.scheduler.RegisterJob(c.baseContext, &deleteStaleAuthTokensJob{c: c}, staleAuthTokenCleanupInterval); err != nil {
+		return err
+	}
+	if c.sessionReplicator != nil {
+		if err := c.scheduler.RegisterJob(c.baseContext, &replicateTerminatedSessionsJob{c: c}, sessionReplicationInterval); err != nil {
+			return err
+		}
+	}
+	if c.rateLimitStore != nil {
+		if err := c.scheduler.RegisterJob(c.baseContext, &deleteIdleRateLimitBucketsJob{c: c}, rateLimitCleanupInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// controllerJob is a job which works on state held in the memory of the
+// controller, and so must be run by every controller rather than by any one
+// of them. Controller jobs run on tickers of the controller's own rather
+// than through the scheduler, which keeps a row for every job it has ever
+// registered: one per controller name would outlive controllers which are
+// renamed or whose names are generated.
+type controllerJob struct {
+	scheduler.Job
+	interval time.Duration
+}
+
+// controllerJobs returns the controller's jobs which work on state held in
+// its memory.
+func (c *Controller) controllerJobs() []*controllerJob {
+	jobs := []*controllerJob{
+		{Job: &flushAuthTokenAccessTimesJob{c: c}, interval: authTokenAccessFlushInterval},
+		{Job: &flushKmsKeyUsageJob{c: c}, interval: kmsKeyUsageFlushInterval},
+	}
+	if c.quotas != nil {
+		jobs = append(jobs, &controllerJob{Job: &flushQuotaCountsJob{c: c}, interval: quotaFlushInterval})
+	}
+	if c.connectionExporter != nil {
+		jobs = append(jobs, &controllerJob{
+			Job:      &exportConnectionsJob{c: c},
+			interval: c.conf.RawConfig.Controller.ConnectionExport.FlushInterval(),
+		})
+	}
+	return jobs
+}
+
+// startControllerJobTicking runs each of the controller's jobs which work on
+// state held in its memory every interval until cancelCtx is done.
+func (c *Controller) startControllerJobTicking(cancelCtx context.Context) {
+	for _, j := range c.controllerJobs() {
+		go func(j *controllerJob) {
+			timer := time.NewTimer(j.interval)
+			for {
+				select {
+				case <-cancelCtx.Done():
+					c.logger.Info("controller job ticking shutting down", "job", j.Name())
+					return
+
+				case <-timer.C:
+					if err := j.Run(cancelCtx); err != nil {
+						c.logger.Error("error running controller job", "job", j.Name(), "error", err)
+					}
+					timer.Reset(j.interval)
+				}
+			}
+		}(j)
+	}
+}
+
+// runFinalFlushes runs the jobs which work on state held in the controller's
+// memory one last time, once the controller has stopped handling requests,
+// and closes the connection exporter.
+func (c *Controller) runFinalFlushes() {
+	for _, j := range c.controllerJobs() {
+		if err := j.Run(context.Background()); err != nil {
+			c.logger.Error("error running job on shutdown", "job", j.Name(), "error", err)
+		}
+	}
+	if c.connectionExporter != nil {
+		if err := c.connectionExporter.Close(); err != nil {
+			c.logger.Error("error closing connection exporter", "error", err)
+		}
+	}
+}
+
+// terminateCompletedSessionsJob terminates sessions which can no longer be
+// used to make connections.
+type terminateCompletedSessionsJob struct {
+	c *Controller
+}
+
+func (j *terminateCompletedSessionsJob) Name() string {
+	return "terminate_completed_sessions"
+}
+
+func (j *terminateCompletedSessionsJob) Description() string {
+	return "Terminates sessions which have expired or reached their connection limit and have no open connections."
+}
+
+func (j *terminateCompletedSessionsJob) Run(ctx context.Context) error {
+	repo, err := j.c.SessionRepoFn()
+	if err != nil {
+		return err
+	}
+	terminationCount, err := repo.TerminateCompletedSessions(ctx)
+	if err != nil {
+		return err
+	}
+	if terminationCount > 0 {
+		j.c.logger.Info("terminating completed sessions successful", "sessions_terminated", terminationCount)
+	}
+	return nil
+}
+
+// closeIdleConnectionsJob closes connections which have been idle for longer
+// than their target allows.
+type closeIdleConnectionsJob struct {
+	c *Controller
+}
+
+func (j *closeIdleConnectionsJob) Name() string {
+	return "close_idle_connections"
+}
+
+func (j *closeIdleConnectionsJob) Description() string {
+	return "Closes session connections which have been idle for longer than their target's connection idle timeout."
+}
+
+func (j *closeIdleConnectionsJob) Run(ctx context.Context) error {
+	repo, err := j.c.SessionRepoFn()
+	if err != nil {
+		return err
+	}
+	closedCount, err := repo.CloseIdleConnections(ctx)
+	if err != nil {
+		return err
+	}
+	if closedCount > 0 {
+		j.c.logger.Info("closing idle connections successful", "connections_closed", closedCount)
+	}
+	return nil
+}
+
+// cancelNeverConnectedSessionsJob terminates sessions which were never
+// connected to within their target's authorization window.
+type cancelNeverConnectedSessionsJob struct {
+	c *Controller
+}
+
+func (j *cancelNeverConnectedSessionsJob) Name() string {
+	return "cancel_never_connected_sessions"
+}
+
+func (j *cancelNeverConnectedSessionsJob) Description() string {
+	return "Terminates pending sessions which were not connected to within their target's authorization valid seconds."
+}
+
+func (j *cancelNeverConnectedSessionsJob) Run(ctx context.Context) error {
+	repo, err := j.c.SessionRepoFn()
+	if err != nil {
+		return err
+	}
+	canceledCount, err := repo.CancelNeverConnectedSessions(ctx)
+	if err != nil {
+		return err
+	}
+	if canceledCount > 0 {
+		j.c.logger.Info("canceling never connected sessions successful", "sessions_canceled", canceledCount)
+	}
+	return nil
+}
+
+// deleteExpiredSessionTokensJob deletes the tokens given to workers for
+// sessions once they have expired.
+type deleteExpiredSessionTokensJob struct {
+	c *Controller
+}
+
+func (j *deleteExpiredSessionTokensJob) Name() string {
+	return "delete_expired_session_tokens"
+}
+
+func (j *deleteExpiredSessionTokensJob) Description() string {
+	return "Deletes expired session tokens given to workers."
+}
+
+func (j *deleteExpiredSessionTokensJob) Run(ctx context.Context) error {
+	repo, err := j.c.AuthTokenRepoFn()
+	if err != nil {
+		return err
+	}
+	deleted, err := repo.DeleteExpiredSessionTokens(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		j.c.logger.Debug("deleted expired session tokens", "tokens_deleted", deleted)
+	}
+	return nil
+}
+
+// deleteStaleAuthTokensJob deletes the auth tokens which have expired or
+// been idle too long, including those which are never presented again.
+type deleteStaleAuthTokensJob struct {
+	c *Controller
+}
+
+func (j *deleteStaleAuthTokensJob) Name() string {
+	return "delete_stale_auth_tokens"
+}
+
+func (j *deleteStaleAuthTokensJob) Description() string {
+	return "Deletes expired and idle auth tokens."
+}
+
+func (j *deleteStaleAuthTokensJob) Run(ctx context.Context) error {
+	repo, err := j.c.AuthTokenRepoFn()
+	if err != nil {
+		return err
+	}
+	deleted, err := repo.DeleteStaleAuthTokens(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		j.c.logger.Debug("deleted stale auth tokens", "tokens_deleted", deleted)
+	}
+	return nil
+}
+
+// deleteIdleRateLimitBucketsJob deletes the API rate limit buckets kept in the
+// database which haven't been used recently.
+type deleteIdleRateLimitBucketsJob struct {
+	c *Controller
+}
+
+func (j *deleteIdleRateLimitBucketsJob) Name() string {
+	return "delete_idle_rate_limit_buckets"
+}
+
+func (j *deleteIdleRateLimitBucketsJob) Description() string {
+	return "Deletes API rate limit buckets which have not been used recently."
+}
+
+func (j *deleteIdleRateLimitBucketsJob) Run(ctx context.Context) error {
+	deleted, err := j.c.rateLimitStore.DeleteIdle(ctx, rateLimitIdleTime)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		j.c.logger.Debug("deleted idle rate limit buckets", "buckets_deleted", deleted)
+	}
+	return nil
+}
+
+// replicateTerminatedSessionsJob replicates the summaries of terminated
+// sessions to another region for reporting.
+type replicateTerminatedSessionsJob struct {
+	c *Controller
+}
+
+func (j *replicateTerminatedSessionsJob) Name() string {
+	return "replicate_terminated_sessions"
+}
+
+func (j *replicateTerminatedSessionsJob) Description() string {
+	return "Replicates the summaries of terminated sessions to the session replication database."
+}
+
+func (j *replicateTerminatedSessionsJob) Run(ctx context.Context) error {
+	repo, err := j.c.SessionRepoFn()
+	if err != nil {
+		return err
+	}
+	replicated, err := repo.ReplicateTerminated(ctx, j.c.sessionReplicator)
+	if err != nil {
+		return err
+	}
+	if replicated > 0 {
+		j.c.logger.Debug("replicated terminated sessions", "sessions_replicated", replicated)
+	}
+	return nil
+}
+
+// exportConnectionsJob sends the records of closed connections buffered by
+// this controller to the connection export collector.
+type exportConnectionsJob struct {
+	c *Controller
+}
+
+func (j *exportConnectionsJob) Name() string {
+	return "export_connections"
+}
+
+func (j *exportConnectionsJob) Description() string {
+	return "Sends the records of session connections closed through this controller to the connection export collector."
+}
+
+func (j *exportConnectionsJob) Run(ctx context.Context) error {
+	exported, err := j.c.connectionExporter.Flush(ctx)
+	if exported > 0 {
+		j.c.logger.Trace("connection records exported", "records", exported)
+	}
+	if err != nil {
+		return fmt.Errorf("error exporting connection records, %d dropped: %w", j.c.connectionExporter.Dropped(), err)
+	}
+	return nil
+}
+
+// flushAuthTokenAccessTimesJob writes the last access times recorded by this
+// controller's auth token repositories.
+type flushAuthTokenAccessTimesJob struct {
+	c *Controller
+}
+
+func (j *flushAuthTokenAccessTimesJob) Name() string {
+	return "flush_auth_token_access_times"
+}
+
+func (j *flushAuthTokenAccessTimesJob) Description() string {
+	return "Writes the last access times of the auth tokens validated by this controller."
+}
+
+func (j *flushAuthTokenAccessTimesJob) Run(ctx context.Context) error {
+	repo, err := j.c.AuthTokenRepoFn()
+	if err != nil {
+		return err
+	}
+	flushed, err := repo.FlushAccessTimes(ctx)
+	if err != nil {
+		return err
+	}
+	if flushed > 0 {
+		j.c.logger.Trace("auth token access times flushed", "tokens_updated", flushed)
+	}
+	return nil
+}
+
+// flushQuotaCountsJob adds the API requests counted by this controller to
+// the request counts kept in the database, and reads back the totals counted
+// by every controller.
+type flushQuotaCountsJob struct {
+	c *Controller
+}
+
+func (j *flushQuotaCountsJob) Name() string {
+	return "flush_quota_request_counts"
+}
+
+func (j *flushQuotaCountsJob) Description() string {
+	return "Adds the API requests counted by this controller to the shared quota counts."
+}
+
+func (j *flushQuotaCountsJob) Run(ctx context.Context) error {
+	flushed, err := j.c.quotas.Flush(ctx)
+	if flushed > 0 {
+		j.c.logger.Trace("quota request counts flushed", "scopes_updated", flushed)
+	}
+	return err
+}
+
+// flushKmsKeyUsageJob adds the key operations counted by this controller's
+// kms to the counts kept in the database.
+type flushKmsKeyUsageJob struct {
+	c *Controller
+}
+
+func (j *flushKmsKeyUsageJob) Name() string {
+	return "flush_kms_key_usage"
+}
+
+func (j *flushKmsKeyUsageJob) Description() string {
+	return "Adds the key operations counted by this controller to the shared key version usage counts."
+}
+
+func (j *flushKmsKeyUsageJob) Run(ctx context.Context) error {
+	flushed, err := j.c.kms.FlushKeyUsage(ctx)
+	if flushed > 0 {
+		j.c.logger.Trace("kms key usage flushed", "counts_updated", flushed)
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package controller_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/hashicorp/boundary/internal/servers/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControllerJobs_NoStaleRows(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	c1 := controller.NewTestController(t, &controller.TestControllerOpts{Name: "first"})
+	defer c1.Shutdown()
+
+	rw := db.New(c1.DbConn())
+	repo, err := scheduler.NewRepository(rw, rw)
+	require.NoError(err)
+	jobNames := func() []string {
+		jobs, err := repo.ListJobs(c1.Context())
+		require.NoError(err)
+		var names []string
+		for _, j := range jobs {
+			names = append(names, j.Name)
+		}
+		return names
+	}
+	want := jobNames()
+	require.NotEmpty(want)
+
+	// A controller restarted under a new name, as dev mode and generated
+	// names do, leaves no jobs of its own behind.
+	c2 := c1.AddClusterControllerMember(t, &controller.TestControllerOpts{Name: "second"})
+	c2.Shutdown()
+	c3 := c1.AddClusterControllerMember(t, &controller.TestControllerOpts{Name: "third"})
+	defer c3.Shutdown()
+
+	got := jobNames()
+	assert.Equal(want, got)
+	for _, name := range got {
+		for _, controllerName := range []string{"first", "second", "third"} {
+			assert.Falsef(strings.Contains(name, controllerName), "job %q is named after controller %q", name, controllerName)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/event"
+)
+
+const (
+	// leaderLockName is the name of the advisory lock held by the controller
+	// which runs the cluster's singleton background jobs.
+	leaderLockName = "controller-leader"
+
+	// leaderCheckInterval is how often the leader checks it still holds the
+	// leader lock and how often other controllers attempt to acquire it. The
+	// database releases the lock as soon as the leader's connection is lost,
+	// so this bounds how long two controllers may both act as leader.
+	leaderCheckInterval = 10 * time.Second
+)
+
+// IsLeader returns true if the controller currently holds the leader lock.
+// Only the leader runs jobs which must be run by a single controller in the
+// cluster, such as cleaning up recovery nonces.
+func (c *Controller) IsLeader() bool {
+	return c.leader.Load()
+}
+
+// startLeaderElection attempts to acquire the leader lock and then keeps
+// checking it's still held, or attempting to acquire it, until cancelCtx is
+// done, at which point the lock is released if held so another controller can
+// take over at its next attempt.
+func (c *Controller) startLeaderElection(cancelCtx context.Context) {
+	// Make the first attempt before returning so the singleton jobs can run
+	// as soon as they start.
+	c.renewLeadership(cancelCtx)
+	go func() {
+		timer := time.NewTimer(leaderCheckInterval)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.releaseLeadership()
+				c.logger.Info("leader election shutting down")
+				return
+
+			case <-timer.C:
+				c.renewLeadership(cancelCtx)
+				timer.Reset(leaderCheckInterval)
+			}
+		}
+	}()
+}
+
+// renewLeadership checks the leader lock is still held if this controller is
+// the leader, or attempts to acquire it if not, and updates the controller's
+// leadership. It's only called from the leader election goroutine.
+func (c *Controller) renewLeadership(ctx context.Context) {
+	if c.leaderLock != nil {
+		err := c.leaderLock.Check(ctx)
+		if err == nil {
+			return
+		}
+		// The database releases the lock when its connection is lost, so
+		// another controller may already have taken over.
+		c.leader.Store(false)
+		c.logger.Warn("giving up controller leadership after the leader lock's connection failed", "error", err)
+		_ = c.leaderLock.Unlock(ctx)
+		c.leaderLock = nil
+		c.leadershipEvent(ctx, false)
+	}
+
+	repo, err := c.ServersRepoFn()
+	if err != nil {
+		c.logger.Error("error acquiring controller leader lock", "error", err)
+		return
+	}
+	lock, err := repo.TryLock(ctx, leaderLockName)
+	switch {
+	case errors.Is(err, db.ErrLockHeld):
+		return
+	case err != nil:
+		c.logger.Error("error acquiring controller leader lock", "error", err)
+		return
+	}
+	c.leaderLock = lock
+	c.leader.Store(true)
+	c.logger.Info("acquired controller leadership")
+	c.leadershipEvent(ctx, true)
+}
+
+// releaseLeadership releases the leader lock if this controller holds it.
+func (c *Controller) releaseLeadership() {
+	if c.leaderLock == nil {
+		return
+	}
+	c.leader.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// The lock is released even if Unlock fails, as its connection is closed.
+	err := c.leaderLock.Unlock(ctx)
+	c.leaderLock = nil
+	if err != nil {
+		c.logger.Error("error releasing controller leader lock", "error", err)
+	} else {
+		c.logger.Info("released controller leadership")
+	}
+	c.leadershipEvent(ctx, false)
+}
+
+// leadershipEvent emits a system event recording a change in the
+// controller's leadership.
+func (c *Controller) leadershipEvent(ctx context.Context, leader bool) {
+	event.WriteSystem(ctx, "controller.(Controller).leadership", map[string]interface{}{
+		"controller": c.conf.RawConfig.Controller.Name,
+		"leader":     leader,
+	})
+}
@@ -117,13 +117,30 @@ func (c *Controller) startListeners() error {
 		c.clusterAddress = l.Addr().String()
 		c.logger.Info("cluster address", "addr", c.clusterAddress)
 
+		// Workers authenticating with certificates issued by the controllers,
+		// and workers registering to receive one, negotiate their own protos
+		pkiListeners := make([]net.Listener, 0, len(workerPkiProtos))
+		for _, proto := range workerPkiProtos {
+			ln.Mux.UnregisterProto(proto)
+			pl, err := ln.Mux.RegisterProto(proto, &tls.Config{
+				GetConfigForClient: c.workerPkiTlsConfig(proto),
+			})
+			if err != nil {
+				return fmt.Errorf("error getting sub-listener for worker proto %q: %w", proto, err)
+			}
+			pkiListeners = append(pkiListeners, pl)
+		}
+
 		workerServer := grpc.NewServer(
 			grpc.MaxRecvMsgSize(math.MaxInt32),
 			grpc.MaxSendMsgSize(math.MaxInt32),
+			grpc.Creds(connStateCredentials{}),
+			grpc.UnaryInterceptor(registrationOnlyInterceptor),
 		)
-		workerService := workers.NewWorkerServiceServer(c.logger.Named("worker-handler"), c.ServersRepoFn, c.SessionRepoFn, c.workerStatusUpdateTimes, c.kms)
+		workerService := workers.NewWorkerServiceServer(c.logger.Named("worker-handler"), c.ServersRepoFn, c.SessionRepoFn, c.AuthTokenRepoFn, c.TargetRepoFn, c.StaticHostRepoFn, c.workerStatusUpdateTimes, c.kms, c.connectionExporter)
 		pbs.RegisterServerCoordinationServiceServer(workerServer, workerService)
 		pbs.RegisterSessionServiceServer(workerServer, workerService)
+		pbs.RegisterWorkerAuthServiceServer(workerServer, workerService)
 
 		interceptor := newInterceptingListener(c, l)
 		ln.ALPNListener = interceptor
@@ -131,6 +148,9 @@ func (c *Controller) startListeners() error {
 
 		servers = append(servers, func() {
 			go workerServer.Serve(interceptor)
+			for _, pl := range pkiListeners {
+				go workerServer.Serve(pl)
+			}
 		})
 		return nil
 	}
@@ -149,6 +169,10 @@ func (c *Controller) startListeners() error {
 				}
 			case "proxy":
 				// Do nothing, in a dev mode we might see it here
+			case "ops":
+				var opsServers []func()
+				opsServers, err = base.ConfigureOpsListener(c.baseContext, ln, c.logger)
+				servers = append(servers, opsServers...)
 			default:
 				err = fmt.Errorf("unknown listener purpose %q", purpose)
 			}
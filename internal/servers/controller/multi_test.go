@@ -62,3 +62,32 @@ func TestAuthenticationMulti(t *testing.T) {
 	require.NoError(err)
 	require.NotNil(proj.Item)
 }
+
+func TestLeaderElectionMulti(t *testing.T) {
+	assert := assert.New(t)
+	logger := hclog.New(&hclog.LoggerOptions{
+		Level: hclog.Trace,
+	})
+
+	c1 := controller.NewTestController(t, &controller.TestControllerOpts{
+		Logger: logger.Named("c1"),
+	})
+	defer c1.Shutdown()
+
+	c2 := c1.AddClusterControllerMember(t, &controller.TestControllerOpts{
+		Logger: logger.Named("c2"),
+	})
+	defer c2.Shutdown()
+
+	// The first controller acquires the leader lock when it starts
+	assert.True(c1.Controller().IsLeader())
+	assert.False(c2.Controller().IsLeader())
+
+	// Once the leader shuts down it releases the lock, and the other
+	// controller takes over at its next attempt. Only the controller is shut
+	// down as the test controller owns the database.
+	require.NoError(t, c1.Controller().Shutdown(false))
+	assert.Eventually(func() bool {
+		return c2.Controller().IsLeader()
+	}, 30*time.Second, 500*time.Millisecond)
+}
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/migrations"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// The names of the preflight checks.
+const (
+	PreflightDatabaseMigration  = "database_migration"
+	PreflightDatabaseExtensions = "database_extensions"
+	PreflightClockSkew          = "clock_skew"
+	PreflightKmsKeys            = "kms_keys"
+)
+
+// preflightMaxClockSkew is how far the controller's clock can be from the
+// database's. Times written by the controller and by the database, such as
+// session expirations and lease times, are compared with each other.
+const preflightMaxClockSkew = 30 * time.Second
+
+// requiredExtensions are the database extensions the migrations use.
+var requiredExtensions = []string{"pgcrypto"}
+
+const (
+	// migrationStateQuery returns the version of the schema and whether a
+	// migration failed part way.
+	migrationStateQuery = `select version, dirty from schema_migrations;`
+
+	// extensionsQuery returns the installed extensions.
+	extensionsQuery = `select extname from pg_extension;`
+
+	// clockQuery returns the database's time.
+	clockQuery = `select current_timestamp;`
+
+	// scopesMissingKeysQuery returns the scopes which don't have a root key
+	// or one of the data keys derived from it.
+	scopesMissingKeysQuery = `
+select s.public_id
+  from iam_scope s
+  left join kms_root_key r
+    on r.scope_id = s.public_id
+ where r.private_id is null
+    or not exists (select from kms_database_key k where k.root_key_id = r.private_id)
+    or not exists (select from kms_oplog_key k where k.root_key_id = r.private_id)
+    or not exists (select from kms_session_key k where k.root_key_id = r.private_id)
+    or not exists (select from kms_token_key k where k.root_key_id = r.private_id)
+ order by s.public_id;
+`
+)
+
+// A PreflightFailure is a preflight check which failed.
+type PreflightFailure struct {
+	// Check is the name of the check.
+	Check string
+	Err   error
+}
+
+// A PreflightError is returned by Start if preflight checks fail.
+type PreflightError struct {
+	Failures []*PreflightFailure
+}
+
+func (e *PreflightError) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.Check, f.Err))
+	}
+	return fmt.Sprintf("controller preflight checks failed: %s", strings.Join(msgs, "; "))
+}
+
+// preflight checks the controller can work with its database and KMS before
+// it starts serving, so a misconfigured controller doesn't join the cluster.
+// It returns a PreflightError listing every check which failed.
+func (c *Controller) preflight(ctx context.Context) error {
+	reader := db.New(c.conf.Database)
+	checks := []struct {
+		name string
+		run  func(context.Context, db.Reader) error
+	}{
+		{PreflightDatabaseMigration, preflightMigration},
+		{PreflightDatabaseExtensions, preflightExtensions},
+		{PreflightClockSkew, preflightClockSkew},
+		{PreflightKmsKeys, c.preflightKmsKeys},
+	}
+	var failures []*PreflightFailure
+	for _, check := range checks {
+		if err := check.run(ctx, reader); err != nil {
+			failures = append(failures, &PreflightFailure{Check: check.name, Err: err})
+		}
+	}
+	if len(failures) > 0 {
+		return &PreflightError{Failures: failures}
+	}
+	return nil
+}
+
+// preflightMigration checks the database's schema is at the version of the
+// controller's migrations.
+func preflightMigration(ctx context.Context, reader db.Reader) error {
+	migs, err := migrations.UpMigrations("postgres")
+	if err != nil {
+		return err
+	}
+	if len(migs) == 0 {
+		return fmt.Errorf("no migrations found")
+	}
+	want := migs[len(migs)-1].Version
+
+	rows, err := reader.Query(ctx, migrationStateQuery, nil)
+	if err != nil {
+		return fmt.Errorf("unable to read migration state, has the database been initialized? %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("unable to read migration state: %w", err)
+		}
+		return fmt.Errorf("database has no migration state, has it been initialized?")
+	}
+	var version int64
+	var dirty bool
+	if err := rows.Scan(&version, &dirty); err != nil {
+		return fmt.Errorf("unable to read migration state: %w", err)
+	}
+	switch {
+	case dirty:
+		return fmt.Errorf("migration %d of the database failed part way and must be fixed manually", version)
+	case uint(version) < want:
+		return fmt.Errorf("database schema is at version %d but this controller requires version %d, run boundary database migrate", version, want)
+	case uint(version) > want:
+		return fmt.Errorf("database schema is at version %d which is newer than this controller's version %d", version, want)
+	}
+	return nil
+}
+
+// preflightExtensions checks the extensions the migrations use are
+// installed.
+func preflightExtensions(ctx context.Context, reader db.Reader) error {
+	rows, err := reader.Query(ctx, extensionsQuery, nil)
+	if err != nil {
+		return fmt.Errorf("unable to list extensions: %w", err)
+	}
+	defer rows.Close()
+	installed := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("unable to list extensions: %w", err)
+		}
+		installed[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to list extensions: %w", err)
+	}
+	var missing []string
+	for _, ext := range requiredExtensions {
+		if !installed[ext] {
+			missing = append(missing, ext)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing extensions: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// preflightClockSkew checks the controller's clock is within
+// preflightMaxClockSkew of the database's. The database's time is compared
+// with the middle of the query's round trip.
+func preflightClockSkew(ctx context.Context, reader db.Reader) error {
+	start := time.Now()
+	rows, err := reader.Query(ctx, clockQuery, nil)
+	if err != nil {
+		return fmt.Errorf("unable to read database time: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return fmt.Errorf("unable to read database time: %w", rows.Err())
+	}
+	var dbNow time.Time
+	if err := rows.Scan(&dbNow); err != nil {
+		return fmt.Errorf("unable to read database time: %w", err)
+	}
+	end := time.Now()
+	now := start.Add(end.Sub(start) / 2)
+	skew := now.Sub(dbNow)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > preflightMaxClockSkew {
+		return fmt.Errorf("controller clock is %s from the database's, more than the %s allowed", skew.Round(time.Millisecond), preflightMaxClockSkew)
+	}
+	return nil
+}
+
+// preflightKmsKeys checks every scope has its keys, and that the root KMS
+// can decrypt the keys of the global scope. The other scopes' keys are only
+// decrypted when they are first used, as decrypting all of them could take
+// a long time.
+func (c *Controller) preflightKmsKeys(ctx context.Context, reader db.Reader) error {
+	rows, err := reader.Query(ctx, scopesMissingKeysQuery, nil)
+	if err != nil {
+		return fmt.Errorf("unable to list scope keys: %w", err)
+	}
+	defer rows.Close()
+	var missing []string
+	for rows.Next() {
+		var scopeId string
+		if err := rows.Scan(&scopeId); err != nil {
+			return fmt.Errorf("unable to list scope keys: %w", err)
+		}
+		missing = append(missing, scopeId)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to list scope keys: %w", err)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("scopes missing keys: %s", strings.Join(missing, ", "))
+	}
+
+	for _, purpose := range []kms.KeyPurpose{kms.KeyPurposeDatabase, kms.KeyPurposeOplog, kms.KeyPurposeTokens, kms.KeyPurposeSessions} {
+		if _, err := c.kms.GetWrapper(ctx, scope.Global.String(), purpose); err != nil {
+			return fmt.Errorf("unable to load %s key of the global scope: %w", purpose, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightError_Error(t *testing.T) {
+	err := &PreflightError{Failures: []*PreflightFailure{
+		{Check: PreflightClockSkew, Err: errors.New("too far")},
+		{Check: PreflightKmsKeys, Err: errors.New("missing")},
+	}}
+	assert.Equal(t, "controller preflight checks failed: clock_skew: too far; kms_keys: missing", err.Error())
+}
+
+func TestPreflight(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	ctx := context.Background()
+
+	c := &Controller{
+		conf: &Config{},
+		kms:  kms.TestKms(t, conn, wrapper),
+	}
+	c.conf.Server = &base.Server{Database: conn}
+	require.NoError(c.preflight(ctx))
+
+	_, err := rw.Exec(ctx, "delete from kms_oplog_key where root_key_id = (select private_id from kms_root_key where scope_id = ?)", []interface{}{org.PublicId})
+	require.NoError(err)
+	_, err = rw.Exec(ctx, "update schema_migrations set dirty = true", nil)
+	require.NoError(err)
+
+	err = c.preflight(ctx)
+	var pfErr *PreflightError
+	require.True(errors.As(err, &pfErr))
+	require.Len(pfErr.Failures, 2)
+	assert.Equal(PreflightDatabaseMigration, pfErr.Failures[0].Check)
+	assert.Equal(PreflightKmsKeys, pfErr.Failures[1].Check)
+	assert.Contains(pfErr.Failures[1].Err.Error(), org.PublicId)
+}
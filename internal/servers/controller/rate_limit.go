@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+)
+
+// allowRequest returns true if a request made with the given auth token id is
+// within the configured rate limits. Otherwise it writes a 429 response with
+// a Retry-After header and returns false. Requests are allowed if the limits
+// can't be checked so an unavailable store doesn't make the API unavailable.
+func (c *Controller) allowRequest(ctx context.Context, w http.ResponseWriter, r *http.Request, tokenId string) bool {
+	ok, retryAfter, err := c.rateLimiter.Allow(ctx, tokenId, clientIp(r))
+	if err != nil {
+		c.logger.Error("error checking rate limit, allowing request", "error", err)
+		return true
+	}
+	if ok {
+		return true
+	}
+
+	seconds := uint32(math.Ceil(retryAfter.Seconds()))
+	if seconds == 0 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.FormatUint(uint64(seconds), 10))
+	handlers.ErrorHandler(c.logger)(ctx, nil, apiErrorMarshaler, w, r, handlers.TooManyRequestsError(seconds))
+	return false
+}
+
+// clientIp returns the address of the client which made r, without its port.
+func clientIp(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/ratelimit"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowRequest(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), &ratelimit.Config{PerIpRate: 0.5, PerIpBurst: 1})
+	require.NoError(err)
+	c := &Controller{logger: hclog.NewNullLogger(), rateLimiter: limiter}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/scopes", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	w := httptest.NewRecorder()
+	assert.True(c.allowRequest(context.Background(), w, r, ""))
+
+	w = httptest.NewRecorder()
+	assert.False(c.allowRequest(context.Background(), w, r, ""))
+	assert.Equal(http.StatusTooManyRequests, w.Code)
+	assert.Equal("2", w.Header().Get("Retry-After"))
+
+	var body map[string]interface{}
+	require.NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal("ResourceExhausted", body["code"])
+	assert.Equal(map[string]interface{}{"retry_after_seconds": float64(2)}, body["details"])
+}
+
+func TestClientIp(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/scopes", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "10.0.0.1", clientIp(r))
+	r.RemoteAddr = "[::1]:1234"
+	assert.Equal(t, "::1", clientIp(r))
+	r.RemoteAddr = "10.0.0.1"
+	assert.Equal(t, "10.0.0.1", clientIp(r))
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/registry"
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+// notFound returns nil when err is a not found error, so lookups of
+// resources which don't exist return no resource rather than an error.
+func notFound(err error) error {
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// newResourceRegistry returns a registry which looks up each type of
+// resource with the controller's repositories.
+func (c *Controller) newResourceRegistry() (*registry.Registry, error) {
+	r := registry.New()
+	lookups := map[resource.Type]registry.LookupFn{
+		resource.Scope: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.IamRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			s, err := repo.LookupScope(ctx, id)
+			if err != nil || s == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: s.PublicId, ScopeId: s.ParentId, Name: s.Name, Description: s.Description}, nil
+		},
+		resource.User: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.IamRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			u, _, err := repo.LookupUser(ctx, id)
+			if err != nil || u == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: u.PublicId, ScopeId: u.ScopeId, Name: u.Name, Description: u.Description}, nil
+		},
+		resource.Group: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.IamRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			g, _, err := repo.LookupGroup(ctx, id)
+			if err != nil || g == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: g.PublicId, ScopeId: g.ScopeId, Name: g.Name, Description: g.Description}, nil
+		},
+		resource.Role: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.IamRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			role, _, _, err := repo.LookupRole(ctx, id)
+			if err != nil || role == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: role.PublicId, ScopeId: role.ScopeId, Name: role.Name, Description: role.Description}, nil
+		},
+		resource.AuthMethod: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.PasswordAuthRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			am, err := repo.LookupAuthMethod(ctx, id)
+			if err != nil || am == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: am.PublicId, ScopeId: am.ScopeId, Name: am.Name, Description: am.Description}, nil
+		},
+		resource.Account: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.PasswordAuthRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			a, err := repo.LookupAccount(ctx, id)
+			if err != nil || a == nil {
+				return nil, notFound(err)
+			}
+			am, err := repo.LookupAuthMethod(ctx, a.AuthMethodId)
+			if err != nil || am == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: a.PublicId, ScopeId: am.ScopeId, Name: a.Name, Description: a.Description}, nil
+		},
+		resource.AuthToken: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.AuthTokenRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			at, err := repo.LookupAuthToken(ctx, id)
+			if err != nil || at == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: at.PublicId, ScopeId: at.ScopeId}, nil
+		},
+		resource.HostCatalog: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.StaticHostRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			hc, err := repo.LookupCatalog(ctx, id)
+			if err != nil || hc == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: hc.PublicId, ScopeId: hc.ScopeId, Name: hc.Name, Description: hc.Description}, nil
+		},
+		resource.HostSet: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.StaticHostRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			hs, _, err := repo.LookupSet(ctx, id)
+			if err != nil || hs == nil {
+				return nil, notFound(err)
+			}
+			hc, err := repo.LookupCatalog(ctx, hs.CatalogId)
+			if err != nil || hc == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: hs.PublicId, ScopeId: hc.ScopeId, Name: hs.Name, Description: hs.Description}, nil
+		},
+		resource.Host: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.StaticHostRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			h, err := repo.LookupHost(ctx, id)
+			if err != nil || h == nil {
+				return nil, notFound(err)
+			}
+			hc, err := repo.LookupCatalog(ctx, h.CatalogId)
+			if err != nil || hc == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: h.PublicId, ScopeId: hc.ScopeId, Name: h.Name, Description: h.Description}, nil
+		},
+		resource.Target: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.TargetRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			t, _, err := repo.LookupTarget(ctx, id)
+			if err != nil || t == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: t.GetPublicId(), ScopeId: t.GetScopeId(), Name: t.GetName(), Description: t.GetDescription()}, nil
+		},
+		resource.Session: func(ctx context.Context, id string) (*registry.Resource, error) {
+			repo, err := c.SessionRepoFn()
+			if err != nil {
+				return nil, err
+			}
+			s, _, err := repo.LookupSession(ctx, id)
+			if err != nil || s == nil {
+				return nil, notFound(err)
+			}
+			return &registry.Resource{PublicId: s.PublicId, ScopeId: s.ScopeId}, nil
+		},
+	}
+	for t, fn := range lookups {
+		if err := r.Register(t, fn); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
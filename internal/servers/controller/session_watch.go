@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/sessions"
+	"github.com/hashicorp/boundary/internal/session"
+	"google.golang.org/grpc/codes"
+)
+
+// sessionWatchPath is where clients subscribe to session status changes.
+// The gateway can't stream responses from services registered in process,
+// so the endpoint is served outside of it.
+const sessionWatchPath = "/v1/sessions:watch"
+
+// watchSessionsRequest is the body of a request to watch sessions.
+type watchSessionsRequest struct {
+	Ids []string `json:"ids"`
+}
+
+// sessionStatusEvent is written to the response for each status change.
+type sessionStatusEvent struct {
+	Id     string    `json:"id"`
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+// handleSessionWatch streams the status changes of the requested sessions
+// as newline delimited JSON until every session is terminated, the client
+// goes away, or the maximum request duration is reached.
+func handleSessionWatch(c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		writeErr := func(err error) {
+			handlers.ErrorHandler(c.logger)(ctx, nil, apiErrorMarshaler, w, r, err)
+		}
+		if r.Method != http.MethodPost {
+			writeErr(handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Action %q must be performed with a POST.", "watch"))
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeErr(handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Streaming is not supported by this listener."))
+			return
+		}
+
+		var req watchSessionsRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, globals.DefaultMaxRequestSize)).Decode(&req); err != nil {
+			writeErr(handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"ids": "Unable to parse the request body."}))
+			return
+		}
+		s, err := sessions.NewService(c.SessionRepoFn, c.IamRepoFn)
+		if err != nil {
+			writeErr(err)
+			return
+		}
+		sub, err := s.Watch(ctx, c.sessionWatcher, req.Ids)
+		if err != nil {
+			writeErr(err)
+			return
+		}
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		enc := json.NewEncoder(w)
+		remaining := make(map[string]struct{}, len(req.Ids))
+		for _, id := range req.Ids {
+			remaining[id] = struct{}{}
+		}
+		for len(remaining) > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case change := <-sub.C:
+				if err := enc.Encode(sessionStatusEvent{
+					Id:     change.SessionId,
+					Status: change.Status.String(),
+					Time:   change.Time,
+				}); err != nil {
+					return
+				}
+				flusher.Flush()
+				if change.Status == session.StatusTerminated {
+					delete(remaining, change.SessionId)
+				}
+			}
+		}
+	})
+}
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"context"
-	"math/rand"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/servers"
@@ -11,8 +10,7 @@ import (
 
 // In the future we could make this configurable
 const (
-	statusInterval      = 10 * time.Second
-	terminationInterval = 1 * time.Minute
+	statusInterval = 10 * time.Second
 )
 
 // This is exported so it can be tweaked in tests
@@ -62,6 +60,11 @@ func (c *Controller) startRecoveryNonceCleanupTicking(cancelCtx context.Context)
 				return
 
 			case <-timer.C:
+				// Only the leader performs cleanup
+				if !c.IsLeader() {
+					timer.Reset(RecoveryNonceCleanupInterval)
+					continue
+				}
 				repo, err := c.ServersRepoFn()
 				if err != nil {
 					c.logger.Error("error fetching repository for recovery nonce cleanup", "error", err)
@@ -78,41 +81,3 @@ func (c *Controller) startRecoveryNonceCleanupTicking(cancelCtx context.Context)
 		}
 	}()
 }
-
-func (c *Controller) startTerminateCompletedSessionsTicking(cancelCtx context.Context) {
-	go func() {
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		// desynchronize calls from the controllers, to ease the load on the DB.
-		getRandomInterval := func() time.Duration {
-			// 0 to 0.5 adjustment to the base
-			f := r.Float64() / 2
-			// Half a chance to be faster, not slower
-			if r.Float32() > 0.5 {
-				f = -1 * f
-			}
-			return terminationInterval + time.Duration(f*float64(time.Minute))
-		}
-		timer := time.NewTimer(0)
-		for {
-			select {
-			case <-cancelCtx.Done():
-				c.logger.Info("terminating completed sessions ticking shutting down")
-				return
-
-			case <-timer.C:
-				repo, err := c.SessionRepoFn()
-				if err != nil {
-					c.logger.Error("error fetching repository for terminating completed sessions", "error", err)
-				} else {
-					terminationCount, err := repo.TerminateCompletedSessions(cancelCtx)
-					if err != nil {
-						c.logger.Error("error performing termination of completed sessions", "error", err)
-					} else if terminationCount > 0 {
-						c.logger.Info("terminating completed sessions successful", "sessions_terminated", terminationCount)
-					}
-				}
-				timer.Reset(getRandomInterval())
-			}
-		}
-	}()
-}
@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/servers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// workerPkiRefreshInterval is how often the certificate authorities are
+	// reloaded from the database and a new server certificate is issued.
+	workerPkiRefreshInterval = time.Hour
+
+	// workerPkiMinRefreshInterval is the least time between reloads of the
+	// certificate authorities forced by a worker certificate which couldn't
+	// be verified.
+	workerPkiMinRefreshInterval = time.Minute
+
+	// workerServerCertLifetime is how long the certificate the controller
+	// presents to workers is valid. It must be longer than
+	// workerPkiRefreshInterval.
+	workerServerCertLifetime = 24 * time.Hour
+)
+
+// workerPkiProtos are the ALPN protocols negotiated by workers which
+// authenticate with certificates issued by the controllers.
+var workerPkiProtos = []string{servers.WorkerAuthPkiProto, servers.WorkerAuthRegistrationProto}
+
+// workerPki holds the certificate authorities used to verify workers
+// authenticating with certificates and the certificate the controller
+// presents to them.
+type workerPki struct {
+	sync.Mutex
+	clientCas  *x509.CertPool
+	serverCert *tls.Certificate
+	loadedAt   time.Time
+	refreshAt  time.Time
+}
+
+// loadWorkerPki returns the certificate authority pool and the server
+// certificate, reloading them from the database if they are stale.
+func (c *Controller) loadWorkerPki(ctx context.Context) (*x509.CertPool, *tls.Certificate, error) {
+	c.workerPki.Lock()
+	defer c.workerPki.Unlock()
+	if c.workerPki.serverCert != nil && time.Now().Before(c.workerPki.refreshAt) {
+		return c.workerPki.clientCas, c.workerPki.serverCert, nil
+	}
+
+	repo, err := c.ServersRepoFn()
+	if err != nil {
+		return nil, nil, err
+	}
+	cas, err := repo.ListWorkerCas(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, ca := range cas {
+		pool.AddCert(ca.Certificate)
+	}
+
+	// Present a certificate from the oldest authority. Workers trust every
+	// authority which hasn't expired at the time they register or rotate
+	// their certificate, so those which have not rotated since a newer
+	// authority was created can still verify the controller.
+	signer := cas[len(cas)-1]
+	pub, priv, err := ed25519.GenerateKey(c.conf.SecureRandomReader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := signer.IssueServerCertificate(pub, time.Now().Add(workerServerCertLifetime))
+	if err != nil {
+		return nil, nil, err
+	}
+	c.workerPki.clientCas = pool
+	// The authority is included so registering workers, which only know its
+	// fingerprint, can verify the certificate.
+	c.workerPki.serverCert = &tls.Certificate{
+		Certificate: [][]byte{der, signer.Certificate.Raw},
+		PrivateKey:  priv,
+	}
+	c.workerPki.loadedAt = time.Now()
+	c.workerPki.refreshAt = c.workerPki.loadedAt.Add(workerPkiRefreshInterval)
+	return c.workerPki.clientCas, c.workerPki.serverCert, nil
+}
+
+// invalidateWorkerPki makes the next call to loadWorkerPki reload the
+// certificate authorities, unless they were loaded less than
+// workerPkiMinRefreshInterval ago. It returns whether they will be reloaded.
+func (c *Controller) invalidateWorkerPki() bool {
+	c.workerPki.Lock()
+	defer c.workerPki.Unlock()
+	if time.Since(c.workerPki.loadedAt) < workerPkiMinRefreshInterval {
+		return false
+	}
+	c.workerPki.refreshAt = time.Time{}
+	return true
+}
+
+// workerPkiTlsConfig returns the TLS config for a connection from a worker
+// negotiating proto. Workers registering with an activation token are not
+// asked for a certificate; all other workers must present a certificate
+// issued by one of the certificate authorities which hasn't been revoked.
+func (c *Controller) workerPkiTlsConfig(proto string) func(*tls.ClientHelloInfo) (*tls.Config, error) {
+	return func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		_, serverCert, err := c.loadWorkerPki(c.baseContext)
+		if err != nil {
+			c.logger.Error("error loading worker certificate authorities", "error", err)
+			return nil, err
+		}
+		tlsConfig := &tls.Config{
+			Certificates: []tls.Certificate{*serverCert},
+			NextProtos:   []string{proto},
+			MinVersion:   tls.VersionTLS13,
+		}
+		if proto == servers.WorkerAuthPkiProto {
+			// The certificate chain is verified by verifyWorkerCertificate so
+			// the certificate authorities can be reloaded if it fails.
+			tlsConfig.ClientAuth = tls.RequireAnyClientCert
+			tlsConfig.VerifyPeerCertificate = c.verifyWorkerCertificate
+		}
+		return tlsConfig, nil
+	}
+}
+
+// verifyWorkerCertificate verifies the certificate chain presented by a
+// worker and refuses connections from workers whose certificate has been
+// revoked. If the chain can't be verified, the certificate authorities are
+// reloaded and it is verified again, since another controller may have
+// created an authority since they were loaded.
+func (c *Controller) verifyWorkerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no worker certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	cert := certs[0]
+	err := c.verifyWorkerCertificateChain(certs)
+	if err != nil && c.invalidateWorkerPki() {
+		err = c.verifyWorkerCertificateChain(certs)
+	}
+	if err != nil {
+		c.logger.Info("refused worker certificate", "serial_number", cert.SerialNumber.String(), "error", err)
+		return err
+	}
+
+	repo, err := c.ServersRepoFn()
+	if err != nil {
+		return err
+	}
+	name, err := repo.ValidateWorkerCertificate(c.baseContext, cert)
+	if err != nil {
+		c.logger.Info("refused worker certificate", "serial_number", cert.SerialNumber.String(), "error", err)
+		return err
+	}
+	c.logger.Info("worker successfully authed with certificate", "name", name)
+	return nil
+}
+
+// verifyWorkerCertificateChain verifies the first of certs was issued to a
+// worker by one of the certificate authorities, with the rest of certs as
+// intermediates.
+func (c *Controller) verifyWorkerCertificateChain(certs []*x509.Certificate) error {
+	clientCas, _, err := c.loadWorkerPki(c.baseContext)
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         clientCas,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+// registrationOnlyInterceptor refuses every call other than worker
+// registration made on a connection negotiated for registration, since the
+// worker making it has not authenticated.
+func registrationOnlyInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if negotiatedProto(ctx) == servers.WorkerAuthRegistrationProto &&
+		info.FullMethod != "/controller.servers.services.v1.WorkerAuthService/RegisterWorker" {
+		return nil, status.Error(codes.PermissionDenied, "Worker is not authenticated.")
+	}
+	return handler(ctx, req)
+}
+
+func negotiatedProto(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	return tlsInfo.State.NegotiatedProtocol
+}
+
+// connStateCredentials exposes the state of the TLS connections accepted
+// from workers to gRPC handlers. The TLS handshake is performed before the
+// connection is handed to gRPC so no handshake is done here.
+type connStateCredentials struct{}
+
+var _ credentials.TransportCredentials = connStateCredentials{}
+
+func (connStateCredentials) ClientHandshake(_ context.Context, _ string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("client handshake not supported")
+}
+
+func (connStateCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return conn, nil, nil
+	}
+	return conn, credentials.TLSInfo{
+		State:          tlsConn.ConnectionState(),
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity},
+	}, nil
+}
+
+func (connStateCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "tls"}
+}
+
+func (connStateCredentials) Clone() credentials.TransportCredentials {
+	return connStateCredentials{}
+}
+
+func (connStateCredentials) OverrideServerName(string) error {
+	return nil
+}
@@ -52,6 +52,9 @@ func (c Controller) v1WorkerAuthConfig(protos []string) (*tls.Config, *base.Work
 	if firstMatchProto == "" {
 		return nil, nil, errors.New("no matching proto found")
 	}
+	if c.conf.WorkerAuthKms == nil {
+		return nil, nil, errors.New("no worker auth kms configured")
+	}
 	marshaledEncInfo, err := base64.RawStdEncoding.DecodeString(encString)
 	if err != nil {
 		return nil, nil, err
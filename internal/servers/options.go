@@ -16,8 +16,9 @@ type Option func(*options)
 
 // options = how options are represented
 type options struct {
-	withLimit    int
-	withLiveness time.Duration
+	withLimit     int
+	withLiveness  time.Duration
+	withTagFilter map[string][]string
 }
 
 func getDefaultOptions() options {
@@ -36,10 +37,21 @@ func WithLimit(limit int) Option {
 	}
 }
 
-// WithSkipVetForWrite provides an option to allow skipping vet checks to allow
-// testing lower-level SQL triggers and constraints
+// WithLiveness provides an option to specify how recently a server must have
+// reported its status to be included in results. If zero, the default
+// liveness of the repository is used.
 func WithLiveness(liveness time.Duration) Option {
 	return func(o *options) {
 		o.withLiveness = liveness
 	}
 }
+
+// WithTagFilter provides an option to restrict results to servers with
+// matching tags. For each key in the filter, a server must have a tag with
+// that key whose value is one of the listed values. A key with no values
+// matches a tag with that key and any value.
+func WithTagFilter(filter map[string][]string) Option {
+	return func(o *options) {
+		o.withTagFilter = filter
+	}
+}
@@ -2,4 +2,90 @@ package servers
 
 const (
 	deleteWhereSql = `create_time < $1`
+
+	upsertServerQuery = `
+	insert into server
+		(private_id, type, name, description, address, update_time, release_version,
+		 active_session_count, active_connection_count, max_sessions)
+	values
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	on conflict on constraint server_pkey
+	do update set
+		name = $3,
+		description = $4,
+		address = $5,
+		update_time = $6,
+		release_version = $7,
+		active_session_count = $8,
+		active_connection_count = $9,
+		max_sessions = $10
+	returning draining;
+	`
+
+	setWorkerDrainingQuery = `
+	update server
+	set
+		draining = $1
+	where
+		private_id = $2 and
+		type = 'worker';
+	`
+
+	deleteServerTagsQuery = `
+	delete from server_tag
+	where
+		server_id = $1 and
+		server_type = $2 and
+		source = $3;
+	`
+
+	// redeemActivationTokenQuery marks an unexpired activation token as
+	// redeemed and returns the name of the worker it was created for. No row
+	// is returned if the token is not found, has expired, or has already been
+	// redeemed.
+	redeemActivationTokenQuery = `
+	update worker_activation_token
+	set
+		redeemed_time = now()
+	where
+		token_hash = $1 and
+		redeemed_time is null and
+		expiration_time > now()
+	returning worker_name;
+	`
+
+	// revokeReplacedWorkerCertificatesQuery revokes the certificate $3 which
+	// the worker $2's certificate $1 replaces, and the other certificates
+	// issued to replace it which were never presented.
+	revokeReplacedWorkerCertificatesQuery = `
+	update worker_auth_certificate
+	set
+		revoked_time = now()
+	where
+		worker_name = $2 and
+		serial_number != $1 and
+		revoked_time is null and
+		(serial_number = $3 or replaces_serial_number = $3);
+	`
+
+	// clearReplacedWorkerCertificateQuery records that a rotated certificate
+	// no longer replaces another.
+	clearReplacedWorkerCertificateQuery = `
+	update worker_auth_certificate
+	set
+		replaces_serial_number = null
+	where
+		serial_number = $1;
+	`
+
+	// revokeWorkerCertificatesQuery revokes all certificates issued to a
+	// worker.
+	revokeWorkerCertificatesQuery = `
+	update worker_auth_certificate
+	set
+		revoked_time = now()
+	where
+		worker_name = $1 and
+		revoked_time is null;
+	`
 )
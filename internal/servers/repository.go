@@ -82,27 +82,17 @@ func (r *Repository) UpsertServer(ctx context.Context, server *Server, opt ...Op
 	}
 	// Ensure, for now at least, the private ID is always equivalent to the name
 	server.PrivateId = server.Name
-	// Build query
-	q := `
-	insert into server
-		(private_id, type, name, description, address, update_time)
-	values
-		($1, $2, $3, $4, $5, $6)
-	on conflict on constraint server_pkey
-	do update set
-		name = $3,
-		description = $4,
-		address = $5,
-		update_time = $6;
-	`
-
-	rowsAffected, err := r.writer.Exec(ctx, q,
+	rowsAffected, err := r.writer.Exec(ctx, upsertServerQuery,
 		[]interface{}{server.PrivateId,
 			server.Type,
 			server.Name,
 			server.Description,
 			server.Address,
-			time.Now().Format(time.RFC3339)})
+			time.Now().Format(time.RFC3339),
+			server.ReleaseVersion,
+			server.ActiveSessionCount,
+			server.ActiveConnectionCount,
+			server.MaxSessions})
 	if err != nil {
 		return nil, db.NoRowsAffected, fmt.Errorf("error performing status upsert: %w", err)
 	}
@@ -0,0 +1,26 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// locker is implemented by writers which can take advisory locks, such as
+// *db.Db.
+type locker interface {
+	Lock(ctx context.Context, name string) (*db.Lock, error)
+	TryLock(ctx context.Context, name string) (*db.Lock, error)
+}
+
+// TryLock acquires the named advisory lock if no other controller holds it,
+// without waiting. It returns an error wrapping db.ErrLockHeld if the lock is
+// held elsewhere. The caller must Unlock the returned lock.
+func (r *Repository) TryLock(ctx context.Context, name string) (*db.Lock, error) {
+	l, ok := r.writer.(locker)
+	if !ok {
+		return nil, fmt.Errorf("try lock: writer does not support locking: %w", db.ErrInvalidParameter)
+	}
+	return l.TryLock(ctx, name)
+}
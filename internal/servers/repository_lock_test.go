@@ -0,0 +1,38 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_TryLock(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(err)
+	ctx := context.Background()
+
+	l, err := repo.TryLock(ctx, "leader")
+	require.NoError(err)
+
+	// Another controller can't take the lock while it's held
+	other, err := NewRepository(rw, rw, testKms)
+	require.NoError(err)
+	_, err = other.TryLock(ctx, "leader")
+	assert.True(errors.Is(err, db.ErrLockHeld))
+
+	// Once released another controller can take it
+	require.NoError(l.Unlock(ctx))
+	l, err = other.TryLock(ctx, "leader")
+	require.NoError(err)
+	require.NoError(l.Unlock(ctx))
+}
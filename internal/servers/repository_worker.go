@@ -0,0 +1,254 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/event"
+)
+
+// UpsertWorkerStatus adds or updates a worker in the repository along with
+// its tags. The worker's update time is set to the current time which is
+// used to determine its liveness. The configuration tags stored for the
+// worker are replaced by the tags in worker.Tags, leaving the tags set with
+// SetWorkerTags, and worker.Draining is set to the stored value.
+// The currently live controllers are returned so they can be passed back to
+// the worker.
+func (r *Repository) UpsertWorkerStatus(ctx context.Context, worker *Server, opt ...Option) ([]*Server, int, error) {
+	if worker == nil {
+		return nil, db.NoRowsAffected, fmt.Errorf("upsert worker status: missing worker: %w", db.ErrInvalidParameter)
+	}
+	if worker.Name == "" {
+		return nil, db.NoRowsAffected, fmt.Errorf("upsert worker status: missing worker name: %w", db.ErrInvalidParameter)
+	}
+	if worker.Type != "" && worker.Type != ServerTypeWorker.String() {
+		return nil, db.NoRowsAffected, fmt.Errorf("upsert worker status: server type %q is not a worker: %w", worker.Type, db.ErrInvalidParameter)
+	}
+	// Ensure, for now at least, the private ID is always equivalent to the name
+	worker.PrivateId = worker.Name
+	worker.Type = ServerTypeWorker.String()
+
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			rows, err := reader.Query(ctx, upsertServerQuery,
+				[]interface{}{worker.PrivateId,
+					worker.Type,
+					worker.Name,
+					worker.Description,
+					worker.Address,
+					time.Now().Format(time.RFC3339),
+					worker.ReleaseVersion,
+					worker.ActiveSessionCount,
+					worker.ActiveConnectionCount,
+					worker.MaxSessions})
+			if err != nil {
+				return fmt.Errorf("unable to upsert worker: %w", err)
+			}
+			defer rows.Close()
+			// The draining flag is only set by operators, so return the
+			// stored value to the caller.
+			var rowsAffected int
+			for rows.Next() {
+				rowsAffected++
+				if err := rows.Scan(&worker.Draining); err != nil {
+					return fmt.Errorf("unable to scan upserted worker: %w", err)
+				}
+			}
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("unable to upsert worker: %w", err)
+			}
+			if rowsAffected != 1 {
+				return fmt.Errorf("upsert of worker %s affected %d rows", worker.PrivateId, rowsAffected)
+			}
+			return replaceTags(ctx, w, worker.PrivateId, tagSourceConfiguration, worker.Tags)
+		},
+	)
+	if err != nil {
+		return nil, db.NoRowsAffected, fmt.Errorf("upsert worker status: %w", err)
+	}
+
+	// Fetch current controllers to feed to the workers
+	controllers, err := r.ListServers(ctx, ServerTypeController)
+	if err != nil {
+		return nil, db.NoRowsAffected, fmt.Errorf("upsert worker status: %w", err)
+	}
+	return controllers, len(controllers), nil
+}
+
+// ListWorkers returns the workers which have reported their status within the
+// liveness window, along with their tags. Supports the WithLiveness and
+// WithTagFilter options.
+func (r *Repository) ListWorkers(ctx context.Context, opt ...Option) ([]*Server, error) {
+	opts := getOpts(opt...)
+	workers, err := r.ListServers(ctx, ServerTypeWorker, opt...)
+	if err != nil {
+		return nil, fmt.Errorf("list workers: %w", err)
+	}
+	if len(workers) == 0 {
+		return nil, nil
+	}
+	tags, err := r.listTags(ctx, ServerTypeWorker, workers)
+	if err != nil {
+		return nil, fmt.Errorf("list workers: %w", err)
+	}
+	ret := make([]*Server, 0, len(workers))
+	for _, w := range workers {
+		w.Tags = tags[w.PrivateId]
+		if len(opts.withTagFilter) > 0 && !matchesTagFilter(w.Tags, opts.withTagFilter) {
+			continue
+		}
+		ret = append(ret, w)
+	}
+	return ret, nil
+}
+
+// SetWorkerTags replaces the tags set through the api for the named worker
+// with tags. Unlike the tags in the worker's configuration they are kept when
+// the worker reports its status, so they apply to worker filters until they
+// are set again. An empty tags removes them. If the worker is not found, an
+// error wrapping db.ErrRecordNotFound is returned.
+func (r *Repository) SetWorkerTags(ctx context.Context, name string, tags []*TagPair) error {
+	if name == "" {
+		return fmt.Errorf("set worker tags: missing worker name: %w", db.ErrInvalidParameter)
+	}
+	for _, t := range tags {
+		if t == nil || strings.TrimSpace(t.GetKey()) == "" || strings.TrimSpace(t.GetValue()) == "" {
+			return fmt.Errorf("set worker tags: tags must have a key and value: %w", db.ErrInvalidParameter)
+		}
+	}
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			worker := &Server{}
+			if err := reader.LookupWhere(ctx, worker, "private_id = ? and type = ?", name, ServerTypeWorker.String()); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return replaceTags(ctx, w, worker.PrivateId, tagSourceApi, tags)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("set worker tags: %w", err)
+	}
+	event.WriteSystem(ctx, "servers.(Repository).SetWorkerTags", map[string]interface{}{
+		"worker_name": name,
+		"tags":        TagsToMap(tags),
+	})
+	return nil
+}
+
+// replaceTags replaces the worker's tags from source with tags.
+func replaceTags(ctx context.Context, w db.Writer, workerId, source string, tags []*TagPair) error {
+	workerType := ServerTypeWorker.String()
+	if _, err := w.Exec(ctx, deleteServerTagsQuery, []interface{}{workerId, workerType, source}); err != nil {
+		return fmt.Errorf("unable to delete existing tags: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	// The same tag may be given more than once but is stored once
+	type kv struct{ key, value string }
+	seen := make(map[kv]bool, len(tags))
+	items := make([]interface{}, 0, len(tags))
+	for _, t := range tags {
+		if t == nil {
+			return errors.New("nil tag")
+		}
+		k := kv{t.Key, t.Value}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		items = append(items, &serverTag{
+			ServerId:   workerId,
+			ServerType: workerType,
+			Key:        t.Key,
+			Value:      t.Value,
+			Source:     source,
+		})
+	}
+	if err := w.CreateItems(ctx, items); err != nil {
+		return fmt.Errorf("unable to create tags: %w", err)
+	}
+	return nil
+}
+
+// listTags returns the tags for servers keyed by server private id. A tag
+// which comes from more than one source is only returned once.
+func (r *Repository) listTags(ctx context.Context, serverType ServerType, servers []*Server) (map[string][]*TagPair, error) {
+	args := []interface{}{serverType.String()}
+	inClause := make([]string, 0, len(servers))
+	for i, s := range servers {
+		inClause = append(inClause, fmt.Sprintf("$%d", i+2))
+		args = append(args, s.PrivateId)
+	}
+	var tags []*serverTag
+	if err := r.reader.SearchWhere(
+		ctx,
+		&tags,
+		fmt.Sprintf("server_type = $1 and server_id in (%s)", strings.Join(inClause, ",")),
+		args,
+		db.WithLimit(-1),
+		db.WithOrder("server_id, key, value"),
+	); err != nil {
+		return nil, fmt.Errorf("unable to list tags: %w", err)
+	}
+	ret := make(map[string][]*TagPair, len(servers))
+	for i, t := range tags {
+		if i > 0 && tags[i-1].ServerId == t.ServerId && tags[i-1].Key == t.Key && tags[i-1].Value == t.Value {
+			continue
+		}
+		ret[t.ServerId] = append(ret[t.ServerId], &TagPair{Key: t.Key, Value: t.Value})
+	}
+	return ret, nil
+}
+
+// LookupWorker returns the named worker along with its tags regardless of
+// when it last reported its status. If the worker is not found, an error
+// wrapping db.ErrRecordNotFound is returned.
+func (r *Repository) LookupWorker(ctx context.Context, name string) (*Server, error) {
+	if name == "" {
+		return nil, fmt.Errorf("lookup worker: missing worker name: %w", db.ErrInvalidParameter)
+	}
+	worker := &Server{}
+	if err := r.reader.LookupWhere(ctx, worker, "private_id = ? and type = ?", name, ServerTypeWorker.String()); err != nil {
+		return nil, fmt.Errorf("lookup worker: %s: %w", name, err)
+	}
+	tags, err := r.listTags(ctx, ServerTypeWorker, []*Server{worker})
+	if err != nil {
+		return nil, fmt.Errorf("lookup worker: %w", err)
+	}
+	worker.Tags = tags[worker.PrivateId]
+	return worker, nil
+}
+
+// SetWorkerDraining sets whether the named worker is draining. A draining
+// worker is not selected for new sessions but keeps handling the sessions it
+// already has. The worker remains draining, including across restarts, until
+// it is set to not draining. If the worker is not found, an error wrapping
+// db.ErrRecordNotFound is returned.
+func (r *Repository) SetWorkerDraining(ctx context.Context, name string, draining bool) error {
+	if name == "" {
+		return fmt.Errorf("set worker draining: missing worker name: %w", db.ErrInvalidParameter)
+	}
+	rowsAffected, err := r.writer.Exec(ctx, setWorkerDrainingQuery, []interface{}{draining, name})
+	if err != nil {
+		return fmt.Errorf("set worker draining: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("set worker draining: %s: %w", name, db.ErrRecordNotFound)
+	}
+	event.WriteSystem(ctx, "servers.(Repository).SetWorkerDraining", map[string]interface{}{
+		"worker_name": name,
+		"draining":    draining,
+	})
+	return nil
+}
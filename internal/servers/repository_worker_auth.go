@@ -0,0 +1,411 @@
+package servers
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/event"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+)
+
+// ErrInvalidActivationToken is returned when a worker registers with an
+// activation token which is not found, has expired or has already been used.
+var ErrInvalidActivationToken = errors.New("invalid activation token")
+
+// ErrCertificateRevoked is returned when a worker certificate is unknown or
+// has been revoked.
+var ErrCertificateRevoked = errors.New("certificate revoked")
+
+// CreateActivationToken creates a single use token the named worker can
+// present to a controller to register and receive a certificate. The token is
+// valid for ttl, or for a day if ttl is zero. The token includes the
+// fingerprints of the current certificate authorities so the worker can
+// verify the controller it registers with. Only a hash of the token is stored
+// so the returned token cannot be retrieved again.
+func (r *Repository) CreateActivationToken(ctx context.Context, workerName string, ttl time.Duration) (string, error) {
+	if workerName == "" {
+		return "", fmt.Errorf("create activation token: missing worker name: %w", db.ErrInvalidParameter)
+	}
+	if ttl < 0 {
+		return "", fmt.Errorf("create activation token: negative ttl: %w", db.ErrInvalidParameter)
+	}
+	if ttl == 0 {
+		ttl = defaultActivationTokenTtl
+	}
+	cas, err := r.ListWorkerCas(ctx)
+	if err != nil {
+		return "", fmt.Errorf("create activation token: %w", err)
+	}
+	token, err := base62.Random(32)
+	if err != nil {
+		return "", fmt.Errorf("create activation token: %w", err)
+	}
+	for _, ca := range cas {
+		token += activationTokenSeparator + CaFingerprint(ca.Certificate)
+	}
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			return w.Create(ctx, &workerActivationToken{
+				TokenHash:      hashActivationToken(token),
+				WorkerName:     workerName,
+				ExpirationTime: time.Now().Add(ttl),
+			})
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("create activation token: %w", err)
+	}
+	return token, nil
+}
+
+// ListWorkerCas returns the certificate authorities which have not expired,
+// newest first. A new certificate authority is created if there are none or
+// if the newest is close to expiring.
+func (r *Repository) ListWorkerCas(ctx context.Context) ([]*WorkerCa, error) {
+	wrapper, err := r.kms.GetWrapper(ctx, scope.Global.String(), kms.KeyPurposeDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("list worker cas: unable to get wrapper: %w", err)
+	}
+
+	rows, err := r.searchWorkerCas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list worker cas: %w", err)
+	}
+	if needsWorkerCa(rows) {
+		if rows, err = r.ensureWorkerCa(ctx); err != nil {
+			return nil, fmt.Errorf("list worker cas: %w", err)
+		}
+	}
+
+	cas := make([]*WorkerCa, 0, len(rows))
+	for _, row := range rows {
+		if err := row.decrypt(ctx, wrapper); err != nil {
+			return nil, fmt.Errorf("list worker cas: %w", err)
+		}
+		cert, err := x509.ParseCertificate(row.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("list worker cas: unable to parse certificate: %w", err)
+		}
+		cas = append(cas, &WorkerCa{
+			PrivateId:      row.PrivateId,
+			Certificate:    cert,
+			CertificatePem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: row.Certificate}),
+			PrivateKey:     row.PrivateKey,
+		})
+	}
+	return cas, nil
+}
+
+// workerCaLockName is the name of the advisory lock held while a worker
+// certificate authority is created.
+const workerCaLockName = "worker_auth_ca"
+
+// searchWorkerCas returns the certificate authorities which have not
+// expired, newest first.
+func (r *Repository) searchWorkerCas(ctx context.Context) ([]*workerCa, error) {
+	var rows []*workerCa
+	if err := r.reader.SearchWhere(ctx, &rows, "not_after > $1", []interface{}{time.Now()}, db.WithLimit(-1), db.WithOrder("not_after desc")); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// needsWorkerCa returns true if there are no certificate authorities or the
+// newest is close to expiring.
+func needsWorkerCa(rows []*workerCa) bool {
+	return len(rows) == 0 || time.Until(rows[0].NotAfter) < workerCaRenewBefore
+}
+
+// ensureWorkerCa creates a new certificate authority and returns the
+// certificate authorities which have not expired, newest first. Controllers
+// starting at the same time would each create one, so the authorities are
+// read again while holding an advisory lock and one is only created if
+// another controller hasn't already.
+func (r *Repository) ensureWorkerCa(ctx context.Context) ([]*workerCa, error) {
+	if l, ok := r.writer.(locker); ok {
+		lock, err := l.Lock(ctx, workerCaLockName)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := lock.Unlock(ctx); err != nil {
+				event.WriteSystem(ctx, "servers.(Repository).ensureWorkerCa", map[string]interface{}{
+					"msg":   "error releasing worker ca lock",
+					"error": err.Error(),
+				})
+			}
+		}()
+	}
+	rows, err := r.searchWorkerCas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !needsWorkerCa(rows) {
+		return rows, nil
+	}
+	row, err := r.createWorkerCa(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*workerCa{row}, rows...), nil
+}
+
+func (r *Repository) createWorkerCa(ctx context.Context) (*workerCa, error) {
+	ca, err := newWorkerCa()
+	if err != nil {
+		return nil, err
+	}
+	id, err := db.NewPrivateId("wca")
+	if err != nil {
+		return nil, err
+	}
+	wrapper, err := r.kms.GetWrapper(ctx, scope.Global.String(), kms.KeyPurposeDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get wrapper: %w", err)
+	}
+	row := &workerCa{
+		PrivateId:   id,
+		Certificate: ca.Certificate.Raw,
+		PrivateKey:  ca.PrivateKey,
+		NotBefore:   ca.Certificate.NotBefore,
+		NotAfter:    ca.Certificate.NotAfter,
+	}
+	if err := row.encrypt(ctx, wrapper); err != nil {
+		return nil, err
+	}
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			return w.Create(ctx, row)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create worker ca: %w", err)
+	}
+	return row, nil
+}
+
+// RegisterWorker redeems a worker's activation token and issues it a
+// certificate for the PEM encoded certificate request csrPem. The PEM encoded
+// certificate and the PEM encoded bundle of certificate authorities the
+// worker should trust are returned. ErrInvalidActivationToken is returned if
+// the token cannot be redeemed.
+func (r *Repository) RegisterWorker(ctx context.Context, activationToken string, csrPem []byte) ([]byte, []byte, error) {
+	if activationToken == "" {
+		return nil, nil, fmt.Errorf("register worker: missing activation token: %w", db.ErrInvalidParameter)
+	}
+	csr, err := parseCertificateRequest(csrPem)
+	if err != nil {
+		return nil, nil, fmt.Errorf("register worker: %v: %w", err, db.ErrInvalidParameter)
+	}
+	cas, err := r.ListWorkerCas(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("register worker: %w", err)
+	}
+
+	var cert *x509.Certificate
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			rows, err := reader.Query(ctx, redeemActivationTokenQuery, []interface{}{hashActivationToken(activationToken)})
+			if err != nil {
+				return fmt.Errorf("unable to redeem activation token: %w", err)
+			}
+			var workerName string
+			for rows.Next() {
+				if err := rows.Scan(&workerName); err != nil {
+					rows.Close()
+					return fmt.Errorf("unable to redeem activation token: %w", err)
+				}
+			}
+			rows.Close()
+			if workerName == "" {
+				return ErrInvalidActivationToken
+			}
+			if cert, err = cas[0].issueWorkerCertificate(workerName, csr); err != nil {
+				return err
+			}
+			return w.Create(ctx, &workerCertificate{
+				SerialNumber: cert.SerialNumber.String(),
+				WorkerName:   workerName,
+				CaId:         cas[0].PrivateId,
+				NotAfter:     cert.NotAfter,
+			})
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("register worker: %w", err)
+	}
+	event.WriteSystem(ctx, "servers.(Repository).RegisterWorker", map[string]interface{}{
+		"worker_name":   cert.Subject.CommonName,
+		"serial_number": cert.SerialNumber.String(),
+		"not_after":     cert.NotAfter,
+	})
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), caBundle(cas), nil
+}
+
+// RotateWorkerCertificate issues a new certificate to the worker which owns
+// current for the PEM encoded certificate request csrPem. The PEM encoded
+// certificate and the PEM encoded bundle of certificate authorities the
+// worker should trust are returned. current stays valid until the new
+// certificate is first presented, so a worker which doesn't receive the
+// response, or retries the request, can rotate again with current.
+func (r *Repository) RotateWorkerCertificate(ctx context.Context, current *x509.Certificate, csrPem []byte) ([]byte, []byte, error) {
+	if current == nil {
+		return nil, nil, fmt.Errorf("rotate worker certificate: missing current certificate: %w", db.ErrInvalidParameter)
+	}
+	csr, err := parseCertificateRequest(csrPem)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rotate worker certificate: %v: %w", err, db.ErrInvalidParameter)
+	}
+	workerName, err := r.ValidateWorkerCertificate(ctx, current)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rotate worker certificate: %w", err)
+	}
+	cas, err := r.ListWorkerCas(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rotate worker certificate: %w", err)
+	}
+
+	var cert *x509.Certificate
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			if cert, err = cas[0].issueWorkerCertificate(workerName, csr); err != nil {
+				return err
+			}
+			return w.Create(ctx, &workerCertificate{
+				SerialNumber:         cert.SerialNumber.String(),
+				WorkerName:           workerName,
+				CaId:                 cas[0].PrivateId,
+				NotAfter:             cert.NotAfter,
+				ReplacesSerialNumber: current.SerialNumber.String(),
+			})
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rotate worker certificate: %w", err)
+	}
+	event.WriteSystem(ctx, "servers.(Repository).RotateWorkerCertificate", map[string]interface{}{
+		"worker_name":            cert.Subject.CommonName,
+		"serial_number":          cert.SerialNumber.String(),
+		"replaces_serial_number": current.SerialNumber.String(),
+		"not_after":              cert.NotAfter,
+	})
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), caBundle(cas), nil
+}
+
+// RevokeWorkerCertificates revokes every certificate issued to the named
+// worker and returns the number of certificates revoked. The worker will
+// need a new activation token to register again.
+func (r *Repository) RevokeWorkerCertificates(ctx context.Context, workerName string) (int, error) {
+	if workerName == "" {
+		return db.NoRowsAffected, fmt.Errorf("revoke worker certificates: missing worker name: %w", db.ErrInvalidParameter)
+	}
+	var rowsAffected int
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			var err error
+			rowsAffected, err = w.Exec(ctx, revokeWorkerCertificatesQuery, []interface{}{workerName})
+			return err
+		},
+	)
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("revoke worker certificates: %w", err)
+	}
+	event.WriteSystem(ctx, "servers.(Repository).RevokeWorkerCertificates", map[string]interface{}{
+		"worker_name": workerName,
+		"revoked":     rowsAffected,
+	})
+	return rowsAffected, nil
+}
+
+// ValidateWorkerCertificate returns the name of the worker cert was issued
+// to. ErrCertificateRevoked is returned if cert was not issued to a worker,
+// has expired or has been revoked.
+func (r *Repository) ValidateWorkerCertificate(ctx context.Context, cert *x509.Certificate) (string, error) {
+	if cert == nil || cert.SerialNumber == nil {
+		return "", fmt.Errorf("validate worker certificate: missing certificate: %w", db.ErrInvalidParameter)
+	}
+	var wc workerCertificate
+	err := r.reader.LookupWhere(ctx, &wc,
+		"serial_number = ? and revoked_time is null and not_after > ?",
+		cert.SerialNumber.String(), time.Now())
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return "", fmt.Errorf("validate worker certificate: %w", ErrCertificateRevoked)
+	case err != nil:
+		return "", fmt.Errorf("validate worker certificate: %w", err)
+	}
+	if wc.WorkerName != cert.Subject.CommonName {
+		return "", fmt.Errorf("validate worker certificate: %w", ErrCertificateRevoked)
+	}
+	if wc.ReplacesSerialNumber != "" {
+		if err := r.revokeReplacedCertificates(ctx, &wc); err != nil {
+			return "", fmt.Errorf("validate worker certificate: %w", err)
+		}
+	}
+	return wc.WorkerName, nil
+}
+
+// revokeReplacedCertificates revokes the certificate wc was issued to replace
+// and any other certificates issued to replace it, now that the worker has
+// presented wc.
+func (r *Repository) revokeReplacedCertificates(ctx context.Context, wc *workerCertificate) error {
+	var revoked int
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			var err error
+			revoked, err = w.Exec(ctx, revokeReplacedWorkerCertificatesQuery, []interface{}{wc.SerialNumber, wc.WorkerName, wc.ReplacesSerialNumber})
+			if err != nil {
+				return fmt.Errorf("unable to revoke replaced certificates: %w", err)
+			}
+			if _, err := w.Exec(ctx, clearReplacedWorkerCertificateQuery, []interface{}{wc.SerialNumber}); err != nil {
+				return fmt.Errorf("unable to clear replaced certificate: %w", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	event.WriteSystem(ctx, "servers.(Repository).revokeReplacedCertificates", map[string]interface{}{
+		"worker_name":            wc.WorkerName,
+		"serial_number":          wc.SerialNumber,
+		"replaces_serial_number": wc.ReplacesSerialNumber,
+		"revoked":                revoked,
+	})
+	return nil
+}
+
+// caBundle returns the PEM encoded certificates of cas.
+func caBundle(cas []*WorkerCa) []byte {
+	var buf bytes.Buffer
+	for _, ca := range cas {
+		buf.Write(ca.CertificatePem)
+	}
+	return buf.Bytes()
+}
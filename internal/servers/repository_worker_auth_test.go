@@ -0,0 +1,156 @@
+package servers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_WorkerAuth(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	_ = iam.TestRepo(t, conn, wrapper)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	parse := func(t *testing.T, certPem []byte) *x509.Certificate {
+		t.Helper()
+		block, _ := pem.Decode(certPem)
+		require.NotNil(t, block)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		return cert
+	}
+
+	t.Run("cas-reused", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		first, err := repo.ListWorkerCas(ctx)
+		require.NoError(err)
+		require.Len(first, 1)
+		second, err := repo.ListWorkerCas(ctx)
+		require.NoError(err)
+		require.Len(second, 1)
+		assert.Equal(first[0].PrivateId, second[0].PrivateId)
+		assert.Equal(first[0].PrivateKey, second[0].PrivateKey)
+	})
+	t.Run("invalid-token", func(t *testing.T) {
+		_, _, err := repo.RegisterWorker(ctx, "not-a-token", testCertificateRequest(t, "worker-1"))
+		assert.True(t, errors.Is(err, ErrInvalidActivationToken))
+	})
+	t.Run("register-rotate-revoke", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		token, err := repo.CreateActivationToken(ctx, "worker-1", 0)
+		require.NoError(err)
+		cas, err := repo.ListWorkerCas(ctx)
+		require.NoError(err)
+		assert.Contains(ActivationTokenCaFingerprints(token), CaFingerprint(cas[0].Certificate))
+
+		certPem, caPem, err := repo.RegisterWorker(ctx, token, testCertificateRequest(t, "worker-1"))
+		require.NoError(err)
+		assert.NotEmpty(caPem)
+		cert := parse(t, certPem)
+		name, err := repo.ValidateWorkerCertificate(ctx, cert)
+		require.NoError(err)
+		assert.Equal("worker-1", name)
+
+		// The token can only be used once
+		_, _, err = repo.RegisterWorker(ctx, token, testCertificateRequest(t, "worker-1"))
+		assert.True(errors.Is(err, ErrInvalidActivationToken))
+
+		rotatedPem, _, err := repo.RotateWorkerCertificate(ctx, cert, testCertificateRequest(t, "worker-1"))
+		require.NoError(err)
+		rotated := parse(t, rotatedPem)
+		// The current certificate is valid until the rotated one is presented
+		_, err = repo.ValidateWorkerCertificate(ctx, cert)
+		require.NoError(err)
+		_, err = repo.ValidateWorkerCertificate(ctx, rotated)
+		require.NoError(err)
+		_, err = repo.ValidateWorkerCertificate(ctx, cert)
+		assert.True(errors.Is(err, ErrCertificateRevoked))
+
+		// A revoked certificate can't be rotated
+		_, _, err = repo.RotateWorkerCertificate(ctx, cert, testCertificateRequest(t, "worker-1"))
+		assert.True(errors.Is(err, ErrCertificateRevoked))
+
+		revoked, err := repo.RevokeWorkerCertificates(ctx, "worker-1")
+		require.NoError(err)
+		assert.Equal(1, revoked)
+		_, err = repo.ValidateWorkerCertificate(ctx, rotated)
+		assert.True(errors.Is(err, ErrCertificateRevoked))
+	})
+	t.Run("rotate-response-dropped", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		token, err := repo.CreateActivationToken(ctx, "worker-2", 0)
+		require.NoError(err)
+		certPem, _, err := repo.RegisterWorker(ctx, token, testCertificateRequest(t, "worker-2"))
+		require.NoError(err)
+		cert := parse(t, certPem)
+
+		// The worker never receives the certificate from the first rotation,
+		// so it rotates again with the certificate it has
+		droppedPem, _, err := repo.RotateWorkerCertificate(ctx, cert, testCertificateRequest(t, "worker-2"))
+		require.NoError(err)
+		dropped := parse(t, droppedPem)
+		rotatedPem, _, err := repo.RotateWorkerCertificate(ctx, cert, testCertificateRequest(t, "worker-2"))
+		require.NoError(err)
+		rotated := parse(t, rotatedPem)
+
+		name, err := repo.ValidateWorkerCertificate(ctx, rotated)
+		require.NoError(err)
+		assert.Equal("worker-2", name)
+
+		// Presenting the new certificate revokes the one it replaced and the
+		// one which was never received
+		_, err = repo.ValidateWorkerCertificate(ctx, cert)
+		assert.True(errors.Is(err, ErrCertificateRevoked))
+		_, err = repo.ValidateWorkerCertificate(ctx, dropped)
+		assert.True(errors.Is(err, ErrCertificateRevoked))
+		_, err = repo.ValidateWorkerCertificate(ctx, rotated)
+		require.NoError(err)
+	})
+}
+
+func TestRepository_ListWorkerCasConcurrent(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	_ = iam.TestRepo(t, conn, wrapper)
+	testKms := kms.TestKms(t, conn, wrapper)
+	ctx := context.Background()
+
+	// Controllers starting at the same time only create one authority
+	const controllers = 5
+	var wg sync.WaitGroup
+	ids := make(chan string, controllers)
+	for i := 0; i < controllers; i++ {
+		rw := db.New(conn)
+		repo, err := NewRepository(rw, rw, testKms)
+		require.NoError(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cas, err := repo.ListWorkerCas(ctx)
+			if assert.NoError(t, err) && assert.Len(t, cas, 1) {
+				ids <- cas[0].PrivateId
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[string]bool)
+	for id := range ids {
+		seen[id] = true
+	}
+	assert.Len(t, seen, 1)
+}
@@ -0,0 +1,74 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// WorkerTarget is a target registered by a worker from a target defined in
+// its configuration. Sessions for the target are only proxied by the worker.
+// The target's address is held by a static host catalog of its own, which is
+// deleted along with the target when the worker deregisters it.
+type WorkerTarget struct {
+	TargetId      string `gorm:"primary_key"`
+	WorkerName    string
+	HostCatalogId string
+	CreateTime    time.Time `gorm:"default:current_timestamp"`
+}
+
+// TableName overrides the table name used by gorm.
+func (WorkerTarget) TableName() string {
+	return "worker_target"
+}
+
+// AddWorkerTarget records that the target was registered by the named worker
+// with the host catalog holding its address.
+func (r *Repository) AddWorkerTarget(ctx context.Context, workerName, targetId, hostCatalogId string) (*WorkerTarget, error) {
+	if workerName == "" {
+		return nil, fmt.Errorf("add worker target: missing worker name: %w", db.ErrInvalidParameter)
+	}
+	if targetId == "" {
+		return nil, fmt.Errorf("add worker target: missing target id: %w", db.ErrInvalidParameter)
+	}
+	if hostCatalogId == "" {
+		return nil, fmt.Errorf("add worker target: missing host catalog id: %w", db.ErrInvalidParameter)
+	}
+	wt := &WorkerTarget{
+		TargetId:      targetId,
+		WorkerName:    workerName,
+		HostCatalogId: hostCatalogId,
+	}
+	if err := r.writer.Create(ctx, wt); err != nil {
+		return nil, fmt.Errorf("add worker target: %s: %w", targetId, err)
+	}
+	return wt, nil
+}
+
+// ListWorkerTargets returns the targets registered by the named worker.
+func (r *Repository) ListWorkerTargets(ctx context.Context, workerName string) ([]*WorkerTarget, error) {
+	if workerName == "" {
+		return nil, fmt.Errorf("list worker targets: missing worker name: %w", db.ErrInvalidParameter)
+	}
+	var targets []*WorkerTarget
+	if err := r.reader.SearchWhere(ctx, &targets, "worker_name = ?", []interface{}{workerName}, db.WithLimit(-1)); err != nil {
+		return nil, fmt.Errorf("list worker targets: %s: %w", workerName, err)
+	}
+	return targets, nil
+}
+
+// LookupWorkerTarget returns the worker target for the target. If the target
+// wasn't registered by a worker, an error wrapping db.ErrRecordNotFound is
+// returned.
+func (r *Repository) LookupWorkerTarget(ctx context.Context, targetId string) (*WorkerTarget, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup worker target: missing target id: %w", db.ErrInvalidParameter)
+	}
+	wt := &WorkerTarget{}
+	if err := r.reader.LookupWhere(ctx, wt, "target_id = ?", targetId); err != nil {
+		return nil, fmt.Errorf("lookup worker target: %s: %w", targetId, err)
+	}
+	return wt, nil
+}
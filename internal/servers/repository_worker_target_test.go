@@ -0,0 +1,76 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_WorkerTargets(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	catalogs := static.TestCatalogs(t, conn, proj.GetPublicId(), 2)
+	tcp1 := target.TestTcpTarget(t, conn, proj.GetPublicId(), "worker-target-1")
+	tcp2 := target.TestTcpTarget(t, conn, proj.GetPublicId(), "worker-target-2")
+
+	t.Run("invalid-parameters", func(t *testing.T) {
+		assert := assert.New(t)
+		_, err := repo.AddWorkerTarget(ctx, "", tcp1.PublicId, catalogs[0].PublicId)
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+		_, err = repo.AddWorkerTarget(ctx, "worker-1", "", catalogs[0].PublicId)
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+		_, err = repo.AddWorkerTarget(ctx, "worker-1", tcp1.PublicId, "")
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+		_, err = repo.ListWorkerTargets(ctx, "")
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+		_, err = repo.LookupWorkerTarget(ctx, "")
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+	})
+
+	assert, require := assert.New(t), require.New(t)
+	_, err = repo.AddWorkerTarget(ctx, "worker-1", tcp1.PublicId, catalogs[0].PublicId)
+	require.NoError(err)
+	_, err = repo.AddWorkerTarget(ctx, "worker-2", tcp2.PublicId, catalogs[1].PublicId)
+	require.NoError(err)
+
+	// A target is only registered by one worker
+	_, err = repo.AddWorkerTarget(ctx, "worker-2", tcp1.PublicId, catalogs[1].PublicId)
+	assert.Error(err)
+
+	found, err := repo.ListWorkerTargets(ctx, "worker-1")
+	require.NoError(err)
+	require.Len(found, 1)
+	assert.Equal(tcp1.PublicId, found[0].TargetId)
+	assert.Equal(catalogs[0].PublicId, found[0].HostCatalogId)
+
+	wt, err := repo.LookupWorkerTarget(ctx, tcp2.PublicId)
+	require.NoError(err)
+	assert.Equal("worker-2", wt.WorkerName)
+
+	// Deleting the target deletes the worker target
+	targetRepo, err := target.NewRepository(rw, rw, testKms)
+	require.NoError(err)
+	_, err = targetRepo.DeleteTarget(ctx, tcp2.PublicId)
+	require.NoError(err)
+	_, err = repo.LookupWorkerTarget(ctx, tcp2.PublicId)
+	assert.True(errors.Is(err, db.ErrRecordNotFound))
+
+	found, err = repo.ListWorkerTargets(ctx, "worker-2")
+	require.NoError(err)
+	assert.Empty(found)
+}
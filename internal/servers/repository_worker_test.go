@@ -0,0 +1,234 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_UpsertWorkerStatus(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("missing-worker", func(t *testing.T) {
+		_, _, err := repo.UpsertWorkerStatus(ctx, nil)
+		assert.Error(t, err)
+	})
+	t.Run("controller-type", func(t *testing.T) {
+		_, _, err := repo.UpsertWorkerStatus(ctx, &Server{Name: "controller-1", Type: ServerTypeController.String()})
+		assert.Error(t, err)
+	})
+	t.Run("replace-tags", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		worker := &Server{
+			Name:           "worker-1",
+			Address:        "127.0.0.1",
+			ReleaseVersion: "0.1.0",
+			Tags: TagsFromMap(map[string][]string{
+				"type":   {"web", "prod"},
+				"region": {"us-east-1"},
+			}),
+		}
+		_, _, err := repo.UpsertWorkerStatus(ctx, worker)
+		require.NoError(err)
+
+		workers, err := repo.ListWorkers(ctx)
+		require.NoError(err)
+		require.Len(workers, 1)
+		assert.Equal("0.1.0", workers[0].ReleaseVersion)
+		assert.Equal(map[string][]string{
+			"region": {"us-east-1"},
+			"type":   {"prod", "web"},
+		}, TagsToMap(workers[0].Tags))
+
+		worker.Tags = TagsFromMap(map[string][]string{"type": {"dev"}})
+		_, _, err = repo.UpsertWorkerStatus(ctx, worker)
+		require.NoError(err)
+		workers, err = repo.ListWorkers(ctx)
+		require.NoError(err)
+		require.Len(workers, 1)
+		assert.Equal(map[string][]string{"type": {"dev"}}, TagsToMap(workers[0].Tags))
+
+		// A repeated tag is stored once
+		worker.Tags = []*TagPair{{Key: "type", Value: "a"}, {Key: "type", Value: "a"}}
+		_, _, err = repo.UpsertWorkerStatus(ctx, worker)
+		require.NoError(err)
+		workers, err = repo.ListWorkers(ctx)
+		require.NoError(err)
+		require.Len(workers, 1)
+		assert.Equal(map[string][]string{"type": {"a"}}, TagsToMap(workers[0].Tags))
+	})
+}
+
+func TestRepository_ListWorkers(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for name, tags := range map[string]map[string][]string{
+		"worker-web":  {"type": {"web"}},
+		"worker-db":   {"type": {"db"}, "region": {"us-east-1"}},
+		"worker-none": nil,
+	} {
+		_, _, err := repo.UpsertWorkerStatus(ctx, &Server{Name: name, Address: "127.0.0.1", Tags: TagsFromMap(tags)})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		names []string
+	}{
+		{
+			name:  "no-filter",
+			names: []string{"worker-db", "worker-none", "worker-web"},
+		},
+		{
+			name:  "filter-type",
+			opts:  []Option{WithTagFilter(map[string][]string{"type": {"web"}})},
+			names: []string{"worker-web"},
+		},
+		{
+			name:  "filter-key-only",
+			opts:  []Option{WithTagFilter(map[string][]string{"type": nil})},
+			names: []string{"worker-db", "worker-web"},
+		},
+		{
+			name: "filter-no-match",
+			opts: []Option{WithTagFilter(map[string][]string{"region": {"eu-west-1"}})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			workers, err := repo.ListWorkers(ctx, tt.opts...)
+			require.NoError(err)
+			var names []string
+			for _, w := range workers {
+				names = append(names, w.Name)
+			}
+			assert.ElementsMatch(tt.names, names)
+		})
+	}
+
+	t.Run("liveness", func(t *testing.T) {
+		time.Sleep(2 * time.Second)
+		workers, err := repo.ListWorkers(ctx, WithLiveness(time.Second))
+		require.NoError(t, err)
+		assert.Empty(t, workers)
+	})
+}
+
+func TestRepository_SetWorkerDraining(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("unknown-worker", func(t *testing.T) {
+		err := repo.SetWorkerDraining(ctx, "unknown", true)
+		assert.True(t, errors.Is(err, db.ErrRecordNotFound))
+		_, err = repo.LookupWorker(ctx, "unknown")
+		assert.True(t, errors.Is(err, db.ErrRecordNotFound))
+	})
+	t.Run("drain", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		worker := &Server{Name: "worker-1", Address: "127.0.0.1", ActiveSessionCount: 2}
+		_, _, err := repo.UpsertWorkerStatus(ctx, worker)
+		require.NoError(err)
+		assert.False(worker.Draining)
+
+		require.NoError(repo.SetWorkerDraining(ctx, "worker-1", true))
+
+		// Status updates report the drain but don't clear it
+		worker = &Server{Name: "worker-1", Address: "127.0.0.1", ActiveSessionCount: 1}
+		_, _, err = repo.UpsertWorkerStatus(ctx, worker)
+		require.NoError(err)
+		assert.True(worker.Draining)
+
+		got, err := repo.LookupWorker(ctx, "worker-1")
+		require.NoError(err)
+		assert.True(got.Draining)
+		assert.Equal(uint32(1), got.ActiveSessionCount)
+
+		require.NoError(repo.SetWorkerDraining(ctx, "worker-1", false))
+		got, err = repo.LookupWorker(ctx, "worker-1")
+		require.NoError(err)
+		assert.False(got.Draining)
+	})
+}
+
+func TestRepository_SetWorkerTags(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("unknown-worker", func(t *testing.T) {
+		err := repo.SetWorkerTags(ctx, "unknown", TagsFromMap(map[string][]string{"type": {"web"}}))
+		assert.True(t, errors.Is(err, db.ErrRecordNotFound))
+	})
+	t.Run("invalid-tag", func(t *testing.T) {
+		err := repo.SetWorkerTags(ctx, "worker-1", []*TagPair{{Key: "type"}})
+		assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+	})
+	t.Run("set", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		worker := &Server{
+			Name:    "worker-1",
+			Address: "127.0.0.1",
+			Tags:    TagsFromMap(map[string][]string{"type": {"web"}}),
+		}
+		_, _, err := repo.UpsertWorkerStatus(ctx, worker)
+		require.NoError(err)
+
+		require.NoError(repo.SetWorkerTags(ctx, "worker-1", TagsFromMap(map[string][]string{
+			"type": {"web", "canary"},
+			"az":   {"us-east-1a"},
+		})))
+		got, err := repo.LookupWorker(ctx, "worker-1")
+		require.NoError(err)
+		assert.Equal(map[string][]string{
+			"az":   {"us-east-1a"},
+			"type": {"canary", "web"},
+		}, TagsToMap(got.Tags))
+
+		// Status updates replace the configuration tags but keep the api tags
+		worker.Tags = TagsFromMap(map[string][]string{"type": {"db"}})
+		_, _, err = repo.UpsertWorkerStatus(ctx, worker)
+		require.NoError(err)
+		workers, err := repo.ListWorkers(ctx, WithTagFilter(map[string][]string{"az": {"us-east-1a"}}))
+		require.NoError(err)
+		require.Len(workers, 1)
+		assert.Equal(map[string][]string{
+			"az":   {"us-east-1a"},
+			"type": {"canary", "db", "web"},
+		}, TagsToMap(workers[0].Tags))
+
+		require.NoError(repo.SetWorkerTags(ctx, "worker-1", nil))
+		got, err = repo.LookupWorker(ctx, "worker-1")
+		require.NoError(err)
+		assert.Equal(map[string][]string{"type": {"db"}}, TagsToMap(got.Tags))
+	})
+}
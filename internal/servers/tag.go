@@ -0,0 +1,93 @@
+package servers
+
+import (
+	"sort"
+)
+
+// The sources of server tags. Tags from a worker's configuration are
+// replaced each time it reports its status while tags set through the api
+// are kept until they are set again.
+const (
+	tagSourceConfiguration = "configuration"
+	tagSourceApi           = "api"
+)
+
+// serverTag is a single tag for a server as stored in the server_tag table.
+type serverTag struct {
+	ServerId   string
+	ServerType string
+	Key        string
+	Value      string
+	Source     string
+}
+
+// TableName overrides the table name used by gorm.
+func (serverTag) TableName() string {
+	return "server_tag"
+}
+
+// TagsFromMap converts a map of tag keys to values, such as the tags read
+// from a worker's configuration, into a sorted list of TagPairs. A value
+// listed more than once for a key is only included once.
+func TagsFromMap(tags map[string][]string) []*TagPair {
+	var pairs []*TagPair
+	for k, vs := range tags {
+		seen := make(map[string]bool, len(vs))
+		for _, v := range vs {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			pairs = append(pairs, &TagPair{Key: k, Value: v})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Key == pairs[j].Key {
+			return pairs[i].Value < pairs[j].Value
+		}
+		return pairs[i].Key < pairs[j].Key
+	})
+	return pairs
+}
+
+// TagsToMap converts a list of TagPairs into a map of tag keys to values.
+func TagsToMap(tags []*TagPair) map[string][]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	m := make(map[string][]string, len(tags))
+	for _, t := range tags {
+		m[t.GetKey()] = append(m[t.GetKey()], t.GetValue())
+	}
+	return m
+}
+
+// matchesTagFilter returns true if tags satisfy filter. For each key in the
+// filter, tags must contain that key with one of the filter's values, or
+// with any value if the filter lists no values for the key.
+func matchesTagFilter(tags []*TagPair, filter map[string][]string) bool {
+	have := TagsToMap(tags)
+	for k, wantValues := range filter {
+		haveValues, ok := have[k]
+		if !ok {
+			return false
+		}
+		if len(wantValues) == 0 {
+			continue
+		}
+		var found bool
+	ValueLoop:
+		for _, want := range wantValues {
+			for _, v := range haveValues {
+				if v == want {
+					found = true
+					break ValueLoop
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagsFromMap(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(TagsFromMap(nil))
+	got := TagsFromMap(map[string][]string{
+		"type":   {"web", "prod"},
+		"region": {"us-east-1"},
+	})
+	want := []*TagPair{
+		{Key: "region", Value: "us-east-1"},
+		{Key: "type", Value: "prod"},
+		{Key: "type", Value: "web"},
+	}
+	assert.Equal(len(want), len(got))
+	for i := range want {
+		assert.Equal(want[i].Key, got[i].Key)
+		assert.Equal(want[i].Value, got[i].Value)
+	}
+	assert.Equal(map[string][]string{
+		"region": {"us-east-1"},
+		"type":   {"prod", "web"},
+	}, TagsToMap(got))
+	// Repeated values are only included once
+	got = TagsFromMap(map[string][]string{
+		"type": {"a", "a", "b"},
+	})
+	assert.Equal(map[string][]string{"type": {"a", "b"}}, TagsToMap(got))
+}
+
+func TestMatchesTagFilter(t *testing.T) {
+	tags := TagsFromMap(map[string][]string{
+		"type":   {"web", "prod"},
+		"region": {"us-east-1"},
+	})
+	tests := []struct {
+		name   string
+		filter map[string][]string
+		want   bool
+	}{
+		{
+			name: "empty-filter",
+			want: true,
+		},
+		{
+			name:   "single-match",
+			filter: map[string][]string{"type": {"prod"}},
+			want:   true,
+		},
+		{
+			name:   "any-of-values",
+			filter: map[string][]string{"type": {"dev", "web"}},
+			want:   true,
+		},
+		{
+			name:   "key-only",
+			filter: map[string][]string{"region": nil},
+			want:   true,
+		},
+		{
+			name:   "all-keys-must-match",
+			filter: map[string][]string{"type": {"prod"}, "region": {"eu-west-1"}},
+			want:   false,
+		},
+		{
+			name:   "missing-key",
+			filter: map[string][]string{"az": nil},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchesTagFilter(tags, tt.filter))
+		})
+	}
+}
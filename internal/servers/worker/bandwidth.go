@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// bandwidthLimiter is a token bucket which limits how many bytes per second
+// are proxied in one direction of a connection. Up to one second's worth of
+// bytes can be proxied at once after the connection has been quiet, so
+// interactive traffic isn't delayed while bulk transfers are held to the
+// limit. It is not safe for concurrent use; each direction of a connection
+// has its own.
+type bandwidthLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newBandwidthLimiter returns a limiter allowing bytesPerSecond, or nil if
+// bytesPerSecond is 0 and so the connection is not limited.
+func newBandwidthLimiter(bytesPerSecond uint32) *bandwidthLimiter {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+	return &bandwidthLimiter{
+		rate:   float64(bytesPerSecond),
+		burst:  float64(bytesPerSecond),
+		tokens: float64(bytesPerSecond),
+		last:   time.Now(),
+	}
+}
+
+// wait takes n tokens from the bucket, waiting until the bucket is no
+// longer overdrawn or ctx is done. A nil limiter never waits.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(time.Duration(-l.tokens / l.rate * float64(time.Second)))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// maxRead returns how many bytes should be read at once so reads are no
+// larger than the bucket.
+func (l *bandwidthLimiter) maxRead(n int) int {
+	if l != nil && float64(n) > l.burst {
+		return int(l.burst)
+	}
+	return n
+}
+
+// throttledReader limits the rate at which bytes are read from r.
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *bandwidthLimiter
+}
+
+// throttle returns r limited to bytesPerSecond, or r itself if bytesPerSecond
+// is 0 so that io.Copy can still make use of its optimizations.
+func throttle(ctx context.Context, r io.Reader, bytesPerSecond uint32) io.Reader {
+	limiter := newBandwidthLimiter(bytesPerSecond)
+	if limiter == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, r: r, limiter: limiter}
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p[:t.limiter.maxRead(len(p))])
+	if n > 0 {
+		if werr := t.limiter.wait(t.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
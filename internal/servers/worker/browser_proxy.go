@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/internal/session"
+	"google.golang.org/protobuf/encoding/protojson"
+	"nhooyr.io/websocket"
+)
+
+// browserHandshakeTimeout is how long a browser client has to send its
+// handshake after connecting.
+const browserHandshakeTimeout = 30 * time.Second
+
+// handleBrowserProxy proxies connections for clients, such as browsers, which
+// can't present the session's certificate. Instead the first message the
+// client sends is its handshake as JSON, including the session's
+// authorization token, and the handshake result is sent back as JSON. After
+// that the connection is proxied the same as any other.
+func (w *Worker) handleBrowserProxy() http.HandlerFunc {
+	return http.HandlerFunc(func(wr http.ResponseWriter, r *http.Request) {
+		clientAddr, err := w.clientAddr(r)
+		if err != nil {
+			wr.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		opts := &websocket.AcceptOptions{
+			Subprotocols:   proxySubprotocols,
+			OriginPatterns: w.conf.RawConfig.Worker.BrowserOrigins,
+		}
+		conn, err := websocket.Accept(wr, r, opts)
+		if err != nil {
+			w.logger.Error("error during websocket upgrade", "error", err)
+			return
+		}
+		// Later calls will cause this to noop if they return a different status
+		defer conn.Close(websocket.StatusNormalClosure, "done")
+
+		handshakeCtx, handshakeCancel := context.WithTimeout(r.Context(), browserHandshakeTimeout)
+		defer handshakeCancel()
+
+		typ, b, err := conn.Read(handshakeCtx)
+		if err != nil {
+			w.logger.Error("error reading handshake from client", "error", err)
+			conn.Close(websocket.StatusPolicyViolation, "invalid handshake received")
+			return
+		}
+		var handshake proxy.ClientHandshake
+		if typ != websocket.MessageText || protojson.Unmarshal(b, &handshake) != nil {
+			w.logger.Error("invalid handshake received from client")
+			conn.Close(websocket.StatusPolicyViolation, "invalid handshake received")
+			return
+		}
+
+		si, err := w.authorizeBrowserSession(handshake.GetAuthorizationToken())
+		if err != nil {
+			w.logger.Error("unable to authorize browser client", "error", err)
+			conn.Close(websocket.StatusPolicyViolation, "invalid authorization token")
+			return
+		}
+
+		w.proxyConnection(r, conn, clientAddr, si, &handshake, func(ctx context.Context, result *proxy.HandshakeResult) error {
+			b, err := protojson.Marshal(result)
+			if err != nil {
+				return err
+			}
+			return conn.Write(ctx, websocket.MessageText, b)
+		})
+	})
+}
+
+// authorizeBrowserSession looks up the session the authorization token was
+// issued for and checks the token holds the session's certificate and key,
+// which is what other clients prove by connecting with them.
+func (w *Worker) authorizeBrowserSession(token string) (*sessionInfo, error) {
+	data, _, err := session.DecodeAuthorizationData(token)
+	if err != nil {
+		if errors.Is(err, session.ErrUnsupportedAuthzFormat) {
+			return nil, errors.New("authorization token format not supported by this worker")
+		}
+		return nil, errors.New("unable to decode authorization token")
+	}
+	if data.GetSessionId() == "" {
+		return nil, errors.New("no session in authorization token")
+	}
+
+	resp, err := w.lookupSession(data.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+	authz := resp.GetAuthorization()
+	if !bytes.Equal(authz.GetCertificate(), data.GetCertificate()) ||
+		subtle.ConstantTimeCompare(authz.GetPrivateKey(), data.GetPrivateKey()) != 1 {
+		return nil, errors.New("authorization token does not match session")
+	}
+	return w.storeSessionInfo(resp, nil), nil
+}
@@ -8,7 +8,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
-	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -18,6 +17,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"google.golang.org/grpc"
@@ -25,6 +26,23 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// controllerRpcRetries is the number of attempts made for a call to the
+// controller which fails with a retryable error.
+const controllerRpcRetries = 3
+
+// retryableControllerMethods are the controller methods which are safe to
+// call again when a call fails in a way that leaves it unknown whether the
+// controller handled it. Calls to any other method are not retried.
+var retryableControllerMethods = map[string]bool{
+	"/controller.servers.services.v1.ServerCoordinationService/Status":                  true,
+	"/controller.servers.services.v1.ServerCoordinationService/SetWorkerTags":           true,
+	"/controller.servers.services.v1.ServerCoordinationService/DeregisterWorkerTargets": true,
+	"/controller.servers.services.v1.SessionService/LookupSession":                      true,
+	// The worker's current certificate stays valid until a new one is
+	// presented, so a lost rotation can be repeated.
+	"/controller.servers.services.v1.WorkerAuthService/RotateCertificate": true,
+}
+
 func (w *Worker) startControllerConnections() error {
 	initialAddrs := make([]resolver.Address, 0, len(w.conf.RawConfig.Worker.Controllers))
 	for _, addr := range w.conf.RawConfig.Worker.Controllers {
@@ -48,6 +66,12 @@ func (w *Worker) startControllerConnections() error {
 		return errors.New("no initial controller addresses found")
 	}
 
+	if w.usePki() {
+		if err := w.ensureWorkerCredentials(initialAddrs); err != nil {
+			return err
+		}
+	}
+
 	w.Resolver().InitialState(resolver.State{
 		Addresses: initialAddrs,
 	})
@@ -60,7 +84,15 @@ func (w *Worker) startControllerConnections() error {
 
 func (w Worker) controllerDialerFunc() func(context.Context, string) (net.Conn, error) {
 	return func(ctx context.Context, addr string) (net.Conn, error) {
-		tlsConf, authInfo, err := w.workerAuthTLSConfig()
+		var tlsConf *tls.Config
+		var authInfo *base.WorkerAuthInfo
+		var err error
+		switch {
+		case w.usePki():
+			tlsConf, err = w.workerPkiTLSConfig()
+		default:
+			tlsConf, authInfo, err = w.workerAuthTLSConfig()
+		}
 		if err != nil {
 			return nil, fmt.Errorf("error creating tls config for worker auth: %w", err)
 		}
@@ -76,6 +108,10 @@ func (w Worker) controllerDialerFunc() func(context.Context, string) (net.Conn,
 			return nil, fmt.Errorf("unable to dial to controller: %w", err)
 		}
 		tlsConn := tls.Client(nonTlsConn, tlsConf)
+		if authInfo == nil {
+			// Authenticated by the worker's certificate, no nonce is needed
+			return tlsConn, nil
+		}
 		written, err := tlsConn.Write([]byte(authInfo.ConnectionNonce))
 		if err != nil {
 			if err := nonTlsConn.Close(); err != nil {
@@ -112,6 +148,7 @@ func (w *Worker) createClientConn(addr string) error {
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(math.MaxInt32)),
 		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(math.MaxInt32)),
 		grpc.WithContextDialer(w.controllerDialerFunc()),
+		grpc.WithUnaryInterceptor(w.retryUnaryInterceptor()),
 		grpc.WithInsecure(),
 		grpc.WithDefaultServiceConfig(defServiceConfig),
 		// Don't have the resolver reach out for a service config from the
@@ -124,11 +161,38 @@ func (w *Worker) createClientConn(addr string) error {
 
 	w.controllerStatusConn.Store(pbs.NewServerCoordinationServiceClient(cc))
 	w.controllerSessionConn.Store(pbs.NewSessionServiceClient(cc))
+	w.controllerAuthConn.Store(pbs.NewWorkerAuthServiceClient(cc))
 
 	w.logger.Info("connected to controller", "address", addr)
 	return nil
 }
 
+// retryUnaryInterceptor returns a client interceptor which retries calls to
+// the controller that fail with an error errors.IsRetryable recognizes, such
+// as the connection being reset while a controller restarts. Only calls to
+// retryableControllerMethods are retried.
+func (w *Worker) retryUnaryInterceptor() grpc.UnaryClientInterceptor {
+	backoff := db.ExpBackoff{}
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !retryableControllerMethods[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		var err error
+		for attempt := uint(1); ; attempt++ {
+			err = invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || !errors.IsRetryable(err) || attempt >= controllerRpcRetries {
+				return err
+			}
+			w.logger.Trace("retrying call to controller", "method", method, "attempt", attempt, "error", err)
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff.Duration(attempt)):
+			}
+		}
+	}
+}
+
 func (w Worker) workerAuthTLSConfig() (*tls.Config, *base.WorkerAuthInfo, error) {
 	var err error
 	info := &base.WorkerAuthInfo{
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/boundary/internal/target"
+)
+
+// egressAllowed returns an error unless addr is authorized, the host and
+// port of a session's endpoint, or the same host with one of allowedPorts.
+func egressAllowed(authorized, allowedPorts, addr string) error {
+	authHost, authPort, err := net.SplitHostPort(authorized)
+	if err != nil {
+		return fmt.Errorf("cannot parse authorized endpoint: %w", err)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("cannot parse address: %w", err)
+	}
+	if !strings.EqualFold(host, authHost) {
+		return fmt.Errorf("host %q is not the authorized endpoint's host %q", host, authHost)
+	}
+	if port == authPort {
+		return nil
+	}
+	if allowedPorts == "" {
+		return fmt.Errorf("port %s is not the authorized endpoint's port and no other ports are allowed", port)
+	}
+	p, err := strconv.ParseUint(port, 10, 32)
+	if err != nil {
+		return fmt.Errorf("cannot parse port %q: %w", port, err)
+	}
+	ranges, err := target.ParsePortRanges(allowedPorts)
+	if err != nil {
+		return err
+	}
+	if !ranges.Contains(uint32(p)) {
+		return fmt.Errorf("port %s is not allowed", port)
+	}
+	return nil
+}
+
+// dialEndpoint dials addr for the connection ci of the session si, unless
+// addr isn't allowed by the session's authorization data, in which case the
+// violation is reported to the controller and an error is returned without
+// dialing.
+func (w *Worker) dialEndpoint(ctx context.Context, si *sessionInfo, ci *connInfo, network, addr string) (net.Conn, error) {
+	si.RLock()
+	sessionId := si.lookupSessionResponse.GetAuthorization().GetSessionId()
+	workerToken := si.lookupSessionResponse.GetWorkerToken()
+	authorized := si.lookupSessionResponse.GetAuthorization().GetEndpoint()
+	endpoint := si.lookupSessionResponse.GetEndpoint()
+	allowedPorts := si.lookupSessionResponse.GetAllowedPorts()
+	si.RUnlock()
+
+	// Controllers that predate the endpoint in the authorization data don't
+	// send it, so fall back to the session's endpoint
+	if authorized == "" {
+		var err error
+		if authorized, err = session.EndpointAddress(endpoint); err != nil {
+			return nil, err
+		}
+	}
+	if err := egressAllowed(authorized, allowedPorts, addr); err != nil {
+		w.logger.Error("refusing to dial address not authorized for session", "error", err, "session_id", sessionId, "endpoint", authorized, "address", addr)
+		if err := w.reportEgressViolation(ctx, &pbs.ReportEgressViolationRequest{
+			SessionId:    sessionId,
+			WorkerToken:  workerToken,
+			WorkerId:     w.conf.RawConfig.Worker.Name,
+			ConnectionId: ci.id,
+			Endpoint:     authorized,
+			Address:      addr,
+		}); err != nil {
+			w.logger.Error("error reporting egress violation", "error", err, "session_id", sessionId)
+		}
+		return nil, fmt.Errorf("address %s is not authorized for the session: %w", addr, err)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, addr)
+}
+
+func (w *Worker) reportEgressViolation(ctx context.Context, req *pbs.ReportEgressViolationRequest) error {
+	rawConn := w.controllerSessionConn.Load()
+	if rawConn == nil {
+		return errors.New("could not get a controller client")
+	}
+	conn, ok := rawConn.(pbs.SessionServiceClient)
+	if !ok {
+		return errors.New("could not cast atomic controller client to the real thing")
+	}
+	if conn == nil {
+		return errors.New("controller client is nil")
+	}
+	_, err := conn.ReportEgressViolation(ctx, req)
+	return err
+}
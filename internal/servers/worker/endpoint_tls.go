@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+)
+
+// endpointTlsHandshakeTimeout is how long the worker waits for the TLS
+// handshake with an endpoint to complete.
+const endpointTlsHandshakeTimeout = 10 * time.Second
+
+// endpointTlsConfig returns the config the worker originates TLS to an
+// endpoint at host with. The endpoint's certificate is verified against the
+// target's server name, or host if it has none.
+func endpointTlsConfig(et *pbs.EndpointTls, host string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: et.GetServerName(),
+		MinVersion: tls.VersionTLS12,
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = host
+	}
+	if len(et.GetCaBundle()) > 0 {
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(et.GetCaBundle()) {
+			return nil, errors.New("no certificates in ca bundle")
+		}
+	}
+	if len(et.GetClientCertificate()) > 0 {
+		cert, err := tls.X509KeyPair(et.GetClientCertificate(), et.GetClientPrivateKey())
+		if err != nil {
+			return nil, fmt.Errorf("error parsing client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// originateTls wraps conn, the worker's connection to an endpoint at host,
+// in TLS and completes the handshake.
+func originateTls(conn net.Conn, et *pbs.EndpointTls, host string) (*tls.Conn, error) {
+	cfg, err := endpointTlsConfig(et, host)
+	if err != nil {
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.SetDeadline(time.Now().Add(endpointTlsHandshakeTimeout)); err != nil {
+		return nil, err
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+	if err := tlsConn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return tlsConn, nil
+}
@@ -2,13 +2,17 @@ package worker
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/boundary/globals"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/internal/target"
 	"github.com/hashicorp/shared-secure-libs/configutil"
 	"nhooyr.io/websocket"
 	"nhooyr.io/websocket/wspb"
@@ -25,6 +29,7 @@ func (w *Worker) handler(props HandlerProperties) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.Handle("/v1/proxy", w.handleProxy())
+	mux.Handle("/v1/proxy/browser", w.handleBrowserProxy())
 
 	genericWrappedHandler := w.wrapGenericHandler(mux, props)
 
@@ -40,22 +45,11 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		}
 		sessionId := r.TLS.ServerName
 
-		clientIp, clientPort, err := net.SplitHostPort(r.RemoteAddr)
+		clientAddr, err := w.clientAddr(r)
 		if err != nil {
-			w.logger.Error("unable to understand remote address", "error", err, "remote_addr", r.RemoteAddr)
 			wr.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		numPort, err := strconv.Atoi(clientPort)
-		if err != nil {
-			w.logger.Error("unable to understand remote port", "error", err)
-			wr.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		clientAddr := &net.TCPAddr{
-			IP:   net.ParseIP(clientIp),
-			Port: numPort,
-		}
 
 		w.logger.Trace("received TLS connection")
 
@@ -68,17 +62,12 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		si := siRaw.(*sessionInfo)
 		si.RLock()
 		expiration := si.lookupSessionResponse.GetExpiration()
-		tofuToken := si.lookupSessionResponse.GetTofuToken()
-		version := si.lookupSessionResponse.GetVersion()
-		endpoint := si.lookupSessionResponse.GetEndpoint()
-		//userId := si.lookupSessionResponse.GetAuthorization()
-		sessStatus := si.status
 		si.RUnlock()
 
 		w.logger.Trace("found session in session info map")
 
 		opts := &websocket.AcceptOptions{
-			Subprotocols: []string{globals.TcpProxyV1},
+			Subprotocols: proxySubprotocols,
 		}
 		conn, err := websocket.Accept(wr, r, opts)
 		if err != nil {
@@ -91,91 +80,181 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 
 		w.logger.Trace("websocket upgrade done")
 
-		connCtx, connCancel := context.WithDeadline(r.Context(), expiration.AsTime())
-		defer connCancel()
+		handshakeCtx, handshakeCancel := context.WithDeadline(r.Context(), expiration.AsTime())
+		defer handshakeCancel()
 
 		var handshake proxy.ClientHandshake
-		if err := wspb.Read(connCtx, conn, &handshake); err != nil {
+		if err := wspb.Read(handshakeCtx, conn, &handshake); err != nil {
 			w.logger.Error("error reading handshake from client", "error", err)
 			conn.Close(websocket.StatusPolicyViolation, "invalid handshake received")
 			return
 		}
-		if len(handshake.GetTofuToken()) < 20 {
-			w.logger.Error("invalid tofu token")
-			conn.Close(websocket.StatusUnsupportedData, "invalid tofu token")
-			return
-		}
 
-		w.logger.Trace("proxy handshake finished")
+		w.proxyConnection(r, conn, clientAddr, si, &handshake, func(ctx context.Context, result *proxy.HandshakeResult) error {
+			return wspb.Write(ctx, conn, result)
+		})
+	})
+}
 
-		if tofuToken != "" {
-			if tofuToken != handshake.GetTofuToken() {
-				w.logger.Error("WARNING: mismatched tofu token", "session_id", sessionId)
-				conn.Close(websocket.StatusPolicyViolation, "tofu token not allowed")
-				return
-			}
-		} else {
-			if sessStatus != pbs.SESSIONSTATUS_SESSIONSTATUS_PENDING {
-				w.logger.Error("no tofu token but not in correct session state", "error", err)
-				conn.Close(websocket.StatusInternalError, "refusing to activate session")
-				return
-			}
-			w.logger.Trace("activating session")
-			sessStatus, err = w.activateSession(r.Context(), sessionId, handshake.GetTofuToken(), version)
-			if err != nil {
-				w.logger.Error("unable to validate session", "error", err)
-				conn.Close(websocket.StatusInternalError, "unable to activate session")
-				return
-			}
-		}
+// proxySubprotocols are the websocket subprotocols clients can proxy
+// connections with.
+var proxySubprotocols = []string{globals.TcpProxyV1, globals.UdpProxyV1}
+
+// clientAddr returns the address of the client making the request.
+func (w *Worker) clientAddr(r *http.Request) (*net.TCPAddr, error) {
+	clientIp, clientPort, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		w.logger.Error("unable to understand remote address", "error", err, "remote_addr", r.RemoteAddr)
+		return nil, err
+	}
+	numPort, err := strconv.Atoi(clientPort)
+	if err != nil {
+		w.logger.Error("unable to understand remote port", "error", err)
+		return nil, err
+	}
+	return &net.TCPAddr{
+		IP:   net.ParseIP(clientIp),
+		Port: numPort,
+	}, nil
+}
 
-		var ci *connInfo
-		var connsLeft int32
-		ci, connsLeft, err = w.authorizeConnection(r.Context(), sessionId)
-		if err != nil {
-			w.logger.Error("unable to authorize connection", "error", err)
-			conn.Close(websocket.StatusInternalError, "unable to authorize connection")
+// proxyConnection authorizes a connection for the session in si once the
+// client has sent its handshake, sends the client the result with
+// writeResult, and proxies the connection to the session's endpoint.
+func (w *Worker) proxyConnection(r *http.Request, conn *websocket.Conn, clientAddr *net.TCPAddr, si *sessionInfo, handshake *proxy.ClientHandshake, writeResult func(context.Context, *proxy.HandshakeResult) error) {
+	si.RLock()
+	sessionId := si.id
+	expiration := si.lookupSessionResponse.GetExpiration()
+	tofuToken := si.lookupSessionResponse.GetTofuToken()
+	version := si.lookupSessionResponse.GetVersion()
+	endpoint := si.lookupSessionResponse.GetEndpoint()
+	workerToken := si.lookupSessionResponse.GetWorkerToken()
+	allowedPorts := si.lookupSessionResponse.GetAllowedPorts()
+	sessStatus := si.status
+	si.RUnlock()
+
+	connCtx, connCancel := context.WithDeadline(r.Context(), expiration.AsTime())
+	defer connCancel()
+
+	var err error
+	if len(handshake.GetTofuToken()) < 20 {
+		w.logger.Error("invalid tofu token")
+		conn.Close(websocket.StatusUnsupportedData, "invalid tofu token")
+		return
+	}
+
+	w.logger.Trace("proxy handshake finished")
+
+	if tofuToken != "" {
+		if tofuToken != handshake.GetTofuToken() {
+			w.logger.Error("WARNING: mismatched tofu token", "session_id", sessionId)
+			conn.Close(websocket.StatusPolicyViolation, "tofu token not allowed")
 			return
 		}
-
-		defer func() {
-			connectionId := ci.id
-			if err := w.closeConnections(r.Context(), map[string]string{
-				connectionId: si.id,
-			}); err != nil {
-				w.logger.Error("error marking connection closed", "error", err, "connection_id", connectionId)
-			}
-		}()
-
-		si.Lock()
-		ci.connCtx = connCtx
-		ci.connCancel = connCancel
-		si.connInfoMap[ci.id] = ci
-		si.status = sessStatus
-		connectionLimit := si.lookupSessionResponse.GetConnectionLimit()
-		si.Unlock()
-
-		w.logger.Trace("authorized connection", "connection_id", ci.id)
-
-		handshakeResult := &proxy.HandshakeResult{
-			Expiration:      expiration,
-			ConnectionLimit: connectionLimit,
-			ConnectionsLeft: connsLeft,
+	} else {
+		if sessStatus != pbs.SESSIONSTATUS_SESSIONSTATUS_PENDING {
+			w.logger.Error("no tofu token but not in correct session state", "error", err)
+			conn.Close(websocket.StatusInternalError, "refusing to activate session")
+			return
 		}
-		if err := wspb.Write(connCtx, conn, handshakeResult); err != nil {
-			w.logger.Error("error sending handshake result to client", "error", err)
-			conn.Close(websocket.StatusProtocolError, "unable to send handshake result")
+		w.logger.Trace("activating session")
+		sessStatus, err = w.activateSession(r.Context(), sessionId, workerToken, handshake.GetTofuToken(), version)
+		if err != nil {
+			w.logger.Error("unable to validate session", "error", err)
+			conn.Close(websocket.StatusInternalError, "unable to activate session")
 			return
 		}
+	}
+
+	// The client can ask for any of the target's allowed ports instead of
+	// the endpoint's port. This is checked before the connection is
+	// authorized so a refused port doesn't count against the connection
+	// limit.
+	endpoint, err = endpointForPort(endpoint, allowedPorts, handshake.GetPort())
+	if err != nil {
+		w.logger.Error("refusing connection to port", "error", err, "session_id", sessionId, "port", handshake.GetPort())
+		conn.Close(websocket.StatusPolicyViolation, "port not allowed")
+		return
+	}
+
+	var ci *connInfo
+	var connsLeft int32
+	ci, connsLeft, err = w.authorizeConnection(r.Context(), sessionId, workerToken)
+	if err != nil {
+		w.logger.Error("unable to authorize connection", "error", err)
+		conn.Close(websocket.StatusInternalError, "unable to authorize connection")
+		return
+	}
+
+	defer func() {
+		connectionId := ci.id
+		if err := w.reportConnectionClosed(r.Context(), connectionId, si.id); err != nil {
+			w.logger.Error("error marking connection closed", "error", err, "connection_id", connectionId)
+		}
+	}()
+
+	si.Lock()
+	ci.connCtx = connCtx
+	ci.connCancel = connCancel
+	si.connInfoMap[ci.id] = ci
+	si.status = sessStatus
+	connectionLimit := si.lookupSessionResponse.GetConnectionLimit()
+	si.Unlock()
+
+	w.logger.Trace("authorized connection", "connection_id", ci.id)
+
+	handshakeResult := &proxy.HandshakeResult{
+		Expiration:      expiration,
+		ConnectionLimit: connectionLimit,
+		ConnectionsLeft: connsLeft,
+	}
+	if err := writeResult(connCtx, handshakeResult); err != nil {
+		w.logger.Error("error sending handshake result to client", "error", err)
+		conn.Close(websocket.StatusProtocolError, "unable to send handshake result")
+		return
+	}
 
-		switch conn.Subprotocol() {
-		case globals.TcpProxyV1:
-			w.handleTcpProxyV1(connCtx, clientAddr, conn, si, ci.id, endpoint)
-		default:
-			conn.Close(websocket.StatusProtocolError, "unsupported-protocol")
+	switch conn.Subprotocol() {
+	case globals.TcpProxyV1:
+		if strings.HasPrefix(endpoint, target.SshProtocol+"://") {
+			w.handleSshProxyV1(connCtx, clientAddr, conn, si, ci, endpoint)
 			return
 		}
-	})
+		w.handleTcpProxyV1(connCtx, clientAddr, conn, si, ci, endpoint)
+	case globals.UdpProxyV1:
+		w.handleUdpProxyV1(connCtx, clientAddr, conn, si, ci, endpoint)
+	default:
+		conn.Close(websocket.StatusProtocolError, "unsupported-protocol")
+		return
+	}
+}
+
+// endpointForPort returns endpoint with its port replaced by port, or an
+// error if port is neither the endpoint's port nor one of allowedPorts. If
+// port is 0 endpoint is returned unchanged.
+func endpointForPort(endpoint, allowedPorts string, port uint32) (string, error) {
+	if port == 0 {
+		return endpoint, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse endpoint url: %w", err)
+	}
+	if u.Port() == strconv.FormatUint(uint64(port), 10) {
+		return endpoint, nil
+	}
+	if allowedPorts == "" {
+		return "", fmt.Errorf("port %d is not the endpoint's port and no other ports are allowed", port)
+	}
+	ranges, err := target.ParsePortRanges(allowedPorts)
+	if err != nil {
+		return "", err
+	}
+	if !ranges.Contains(port) {
+		return "", fmt.Errorf("port %d is not allowed", port)
+	}
+	u.Host = net.JoinHostPort(u.Hostname(), strconv.FormatUint(uint64(port), 10))
+	return u.String(), nil
 }
 
 func (w *Worker) wrapGenericHandler(h http.Handler, props HandlerProperties) http.Handler {
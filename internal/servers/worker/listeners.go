@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/libs/alpnmux"
 	"github.com/hashicorp/go-multierror"
 )
@@ -25,6 +26,18 @@ func (w *Worker) startListeners() error {
 				// We may have this in dev mode; ignore
 				continue
 
+			case "ops":
+				// In dev mode the controller serves the ops listener
+				if w.conf.RawConfig.DevController {
+					continue
+				}
+				opsServers, err := base.ConfigureOpsListener(w.baseContext, ln, w.logger)
+				if err != nil {
+					return err
+				}
+				servers = append(servers, opsServers...)
+				continue
+
 			case "proxy":
 				// Do nothing; handle below
 
@@ -78,6 +91,18 @@ func (w *Worker) startListeners() error {
 			servers = append(servers, func() {
 				go server.Serve(l)
 			})
+
+			if !ln.Config.TLSDisable {
+				// Browser clients can't present a session certificate so they
+				// connect using the listener's own certificate
+				bl := ln.Mux.GetListener("http/1.1")
+				if bl == nil {
+					return errors.New("could not get browser tls listener")
+				}
+				servers = append(servers, func() {
+					go server.Serve(bl)
+				})
+			}
 		}
 	}
 
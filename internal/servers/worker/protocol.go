@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// protocolDetectTimeout is how long the worker waits for an endpoint to
+	// identify itself when a tcp connection is made. Connections to endpoints
+	// which wait for the client to speak first are delayed by this long.
+	protocolDetectTimeout = 200 * time.Millisecond
+
+	// maxSshIdentLen is the longest an ssh identification string can be,
+	// including its trailing CR LF. See RFC 4253 section 4.2.
+	maxSshIdentLen = 255
+)
+
+// detectProtocol detects the protocol spoken over conn from what the endpoint
+// sends when the connection is made, returning the protocol and what was
+// learned about it. The protocol is empty if it wasn't detected. Whatever is
+// read from conn stays buffered in the returned reader, which must be used
+// to read from conn from then on.
+func detectProtocol(conn net.Conn) (*bufio.Reader, string, *structpb.Struct) {
+	br := bufio.NewReader(conn)
+	if err := conn.SetReadDeadline(time.Now().Add(protocolDetectTimeout)); err != nil {
+		return br, "", nil
+	}
+	defer conn.SetReadDeadline(time.Time{})
+
+	if prefix, err := br.Peek(4); err != nil || !bytes.Equal(prefix, []byte("SSH-")) {
+		return br, "", nil
+	}
+	for n := 5; n <= maxSshIdentLen; n++ {
+		ident, err := br.Peek(n)
+		if err != nil {
+			return br, "", nil
+		}
+		if ident[n-1] == '\n' {
+			md, err := structpb.NewStruct(map[string]interface{}{
+				"ssh_server_version": strings.TrimRight(string(ident), "\r\n"),
+			})
+			if err != nil {
+				return br, "ssh", nil
+			}
+			return br, "ssh", md
+		}
+	}
+	return br, "", nil
+}
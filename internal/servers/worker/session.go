@@ -9,10 +9,13 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/session"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -20,6 +23,18 @@ const (
 )
 
 type connInfo struct {
+	// The traffic proxied for the connection, updated atomically. These are
+	// first to keep them 64-bit aligned.
+	bytesUp       uint64
+	bytesDown     uint64
+	datagramsUp   uint64
+	datagramsDown uint64
+
+	// The traffic seen when activity was last checked, and when that was
+	// found to have changed, as unix nanoseconds. Both are updated atomically.
+	lastTraffic  uint64
+	lastActivity int64
+
 	id         string
 	connCtx    context.Context
 	connCancel context.CancelFunc
@@ -47,6 +62,46 @@ func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error)
 		return nil, fmt.Errorf("could not find session ID in SNI")
 	}
 
+	resp, err := w.lookupSession(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedCert, err := x509.ParseCertificate(resp.GetAuthorization().Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing session certificate: %w", err)
+	}
+
+	if len(parsedCert.DNSNames) != 1 {
+		return nil, fmt.Errorf("invalid length of DNS names (%d) in parsed certificate", len(parsedCert.DNSNames))
+	}
+
+	certPool := x509.NewCertPool()
+	certPool.AddCert(parsedCert)
+
+	tlsConf := &tls.Config{
+		Certificates: []tls.Certificate{
+			{
+				Certificate: [][]byte{resp.GetAuthorization().Certificate},
+				PrivateKey:  ed25519.PrivateKey(resp.GetAuthorization().PrivateKey),
+				Leaf:        parsedCert,
+			},
+		},
+		ServerName: parsedCert.DNSNames[0],
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  certPool,
+		MinVersion: tls.VersionTLS13,
+	}
+
+	w.storeSessionInfo(resp, tlsConf)
+
+	w.logger.Trace("returning TLS configuration", "session_id", sessionId)
+	return tlsConf, nil
+}
+
+// lookupSession asks the controller for the session, returning an error if
+// it has expired.
+func (w *Worker) lookupSession(sessionId string) (*pbs.LookupSessionResponse, error) {
 	rawConn := w.controllerSessionConn.Load()
 	if rawConn == nil {
 		w.logger.Trace("could not get a controller client", "session_id", sessionId)
@@ -77,32 +132,12 @@ func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error)
 		return nil, fmt.Errorf("session is expired")
 	}
 
-	parsedCert, err := x509.ParseCertificate(resp.GetAuthorization().Certificate)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing session certificate: %w", err)
-	}
-
-	if len(parsedCert.DNSNames) != 1 {
-		return nil, fmt.Errorf("invalid length of DNS names (%d) in parsed certificate", len(parsedCert.DNSNames))
-	}
-
-	certPool := x509.NewCertPool()
-	certPool.AddCert(parsedCert)
-
-	tlsConf := &tls.Config{
-		Certificates: []tls.Certificate{
-			{
-				Certificate: [][]byte{resp.GetAuthorization().Certificate},
-				PrivateKey:  ed25519.PrivateKey(resp.GetAuthorization().PrivateKey),
-				Leaf:        parsedCert,
-			},
-		},
-		ServerName: parsedCert.DNSNames[0],
-		ClientAuth: tls.RequireAndVerifyClientCert,
-		ClientCAs:  certPool,
-		MinVersion: tls.VersionTLS13,
-	}
+	return resp, nil
+}
 
+// storeSessionInfo records the looked up session in the session info map,
+// returning the info for it.
+func (w *Worker) storeSessionInfo(resp *pbs.LookupSessionResponse, tlsConf *tls.Config) *sessionInfo {
 	si := &sessionInfo{
 		id:                    resp.GetAuthorization().GetSessionId(),
 		sessionTls:            tlsConf,
@@ -114,20 +149,19 @@ func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error)
 	// not in cancellation because they could be on the way to being
 	// established. However, since cert lifetimes are short, we can simply range
 	// through and remove values that are expired.
-	actualSiRaw, loaded := w.sessionInfoMap.LoadOrStore(sessionId, si)
+	actualSiRaw, loaded := w.sessionInfoMap.LoadOrStore(si.id, si)
 	if loaded {
 		// Update the response to the latest
 		actualSi := actualSiRaw.(*sessionInfo)
 		actualSi.Lock()
 		actualSi.lookupSessionResponse = resp
 		actualSi.Unlock()
+		return actualSi
 	}
-
-	w.logger.Trace("returning TLS configuration", "session_id", sessionId)
-	return tlsConf, nil
+	return si
 }
 
-func (w *Worker) activateSession(ctx context.Context, sessionId, tofuToken string, version uint32) (pbs.SESSIONSTATUS, error) {
+func (w *Worker) activateSession(ctx context.Context, sessionId, workerToken, tofuToken string, version uint32) (pbs.SESSIONSTATUS, error) {
 	rawConn := w.controllerSessionConn.Load()
 	if rawConn == nil {
 		return pbs.SESSIONSTATUS_SESSIONSTATUS_UNSPECIFIED, errors.New("could not get a controller client")
@@ -141,10 +175,11 @@ func (w *Worker) activateSession(ctx context.Context, sessionId, tofuToken strin
 	}
 
 	resp, err := conn.ActivateSession(ctx, &pbs.ActivateSessionRequest{
-		SessionId: sessionId,
-		TofuToken: tofuToken,
-		Version:   version,
-		WorkerId:  w.conf.RawConfig.Worker.Name,
+		SessionId:   sessionId,
+		TofuToken:   tofuToken,
+		Version:     version,
+		WorkerId:    w.conf.RawConfig.Worker.Name,
+		WorkerToken: workerToken,
 	})
 	if err != nil {
 		return pbs.SESSIONSTATUS_SESSIONSTATUS_UNSPECIFIED, fmt.Errorf("error activating session: %w", err)
@@ -152,7 +187,7 @@ func (w *Worker) activateSession(ctx context.Context, sessionId, tofuToken strin
 	return resp.GetStatus(), nil
 }
 
-func (w *Worker) authorizeConnection(ctx context.Context, sessionId string) (*connInfo, int32, error) {
+func (w *Worker) authorizeConnection(ctx context.Context, sessionId, workerToken string) (*connInfo, int32, error) {
 	rawConn := w.controllerSessionConn.Load()
 	if rawConn == nil {
 		return nil, 0, errors.New("could not get a controller client")
@@ -166,18 +201,31 @@ func (w *Worker) authorizeConnection(ctx context.Context, sessionId string) (*co
 	}
 
 	resp, err := conn.AuthorizeConnection(ctx, &pbs.AuthorizeConnectionRequest{
-		SessionId: sessionId,
+		SessionId:   sessionId,
+		WorkerToken: workerToken,
 	})
 	if err != nil {
 		return nil, 0, fmt.Errorf("error authorizing connection: %w", err)
 	}
 
 	return &connInfo{
-		id:     resp.ConnectionId,
-		status: resp.GetStatus(),
+		id:           resp.ConnectionId,
+		status:       resp.GetStatus(),
+		lastActivity: time.Now().UnixNano(),
 	}, resp.GetConnectionsLeft(), nil
 }
 
+// activity returns when traffic was last proxied for the connection. Traffic
+// is only looked for when this is called, so it's as accurate as the interval
+// it's called at.
+func (ci *connInfo) activity() time.Time {
+	traffic := atomic.LoadUint64(&ci.bytesUp) + atomic.LoadUint64(&ci.bytesDown)
+	if atomic.SwapUint64(&ci.lastTraffic, traffic) != traffic {
+		atomic.StoreInt64(&ci.lastActivity, time.Now().UnixNano())
+	}
+	return time.Unix(0, atomic.LoadInt64(&ci.lastActivity))
+}
+
 func (w *Worker) connectConnection(ctx context.Context, req *pbs.ConnectConnectionRequest) (pbs.CONNECTIONSTATUS, error) {
 	rawConn := w.controllerSessionConn.Load()
 	if rawConn == nil {
@@ -216,7 +264,7 @@ func (w *Worker) closeConnection(ctx context.Context, req *pbs.CloseConnectionRe
 		return nil, errors.New("controller client is nil")
 	}
 
-	resp, err := conn.CloseConnection(ctx, req)
+	resp, err := conn.CloseConnection(ctx, req, w.reportCallOptions()...)
 	if err != nil {
 		return nil, err
 	}
@@ -231,11 +279,25 @@ func (w *Worker) closeConnections(ctx context.Context, closeMap map[string]strin
 	w.logger.Trace("marking connections as closed", "session_and_connection_ids", fmt.Sprintf("%#v", closeMap))
 
 	closeData := make([]*pbs.CloseConnectionRequestData, 0, len(closeMap))
-	for connId := range closeMap {
-		closeData = append(closeData, &pbs.CloseConnectionRequestData{
+	for connId, sessId := range closeMap {
+		data := &pbs.CloseConnectionRequestData{
 			ConnectionId: connId,
+			SessionId:    sessId,
 			Reason:       session.UnknownReason.String(),
-		})
+		}
+		if siRaw, ok := w.sessionInfoMap.Load(sessId); ok {
+			si := siRaw.(*sessionInfo)
+			si.RLock()
+			data.WorkerToken = si.lookupSessionResponse.GetWorkerToken()
+			if ci := si.connInfoMap[connId]; ci != nil {
+				data.BytesUp = atomic.LoadUint64(&ci.bytesUp)
+				data.BytesDown = atomic.LoadUint64(&ci.bytesDown)
+				data.DatagramsUp = atomic.LoadUint64(&ci.datagramsUp)
+				data.DatagramsDown = atomic.LoadUint64(&ci.datagramsDown)
+			}
+			si.RUnlock()
+		}
+		closeData = append(closeData, data)
 	}
 	closeInfo := &pbs.CloseConnectionRequest{
 		CloseRequestData: closeData,
@@ -273,3 +335,51 @@ func (w *Worker) closeConnections(ctx context.Context, closeMap map[string]strin
 	w.logger.Trace("connections successfully marked closed", "connection_ids", closedIds)
 	return nil
 }
+
+// reportConnectionClosed reports to the controller that the connection has
+// closed. When connection reports are batched it's held until the next status
+// report instead.
+func (w *Worker) reportConnectionClosed(ctx context.Context, connectionId, sessionId string) error {
+	if w.conf.RawConfig.Worker.BatchConnectionReports {
+		w.pendingClosesLock.Lock()
+		w.pendingCloses[connectionId] = sessionId
+		w.pendingClosesLock.Unlock()
+		return nil
+	}
+	return w.closeConnections(ctx, map[string]string{
+		connectionId: sessionId,
+	})
+}
+
+// takePendingCloses returns the closed connections waiting to be reported
+// and clears them.
+func (w *Worker) takePendingCloses() map[string]string {
+	w.pendingClosesLock.Lock()
+	defer w.pendingClosesLock.Unlock()
+	pending := make(map[string]string, len(w.pendingCloses))
+	for connId, sessId := range w.pendingCloses {
+		pending[connId] = sessId
+		delete(w.pendingCloses, connId)
+	}
+	return pending
+}
+
+// requeuePendingCloses holds closed connections whose report failed so they
+// are sent with the next status report.
+func (w *Worker) requeuePendingCloses(closeMap map[string]string) {
+	w.pendingClosesLock.Lock()
+	defer w.pendingClosesLock.Unlock()
+	for connId, sessId := range closeMap {
+		w.pendingCloses[connId] = sessId
+	}
+}
+
+// reportCallOptions are the options for the status and connection report
+// calls, which are made often enough by busy workers that compressing them
+// is worthwhile.
+func (w *Worker) reportCallOptions() []grpc.CallOption {
+	if c := w.conf.RawConfig.Worker.ReportCompression; c != servers.CompressionNone {
+		return []grpc.CallOption{grpc.UseCompressor(c)}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package worker
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/crypto/ssh"
+	"nhooyr.io/websocket"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/target"
+)
+
+// handleSshProxyV1 proxies connections to ssh targets. Rather than relaying
+// the client's ssh connection, the worker terminates it and opens its own
+// connection to the endpoint, authenticating with the target's credential,
+// so the credential never leaves the worker. The client has already been
+// authenticated by the session, so its ssh connection requires no
+// authentication.
+func (w *Worker) handleSshProxyV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, ci *connInfo, endpoint string) {
+	connectionId := ci.id
+	si.RLock()
+	sessionId := si.lookupSessionResponse.GetAuthorization().GetSessionId()
+	workerToken := si.lookupSessionResponse.GetWorkerToken()
+	bandwidthLimit := si.lookupSessionResponse.GetConnectionBandwidthLimit()
+	cred := si.lookupSessionResponse.GetSshCredential()
+	si.RUnlock()
+
+	sessionUrl, err := url.Parse(endpoint)
+	if err != nil {
+		w.logger.Error("error parsing endpoint information", "error", err, "session_id", sessionId, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "cannot parse endpoint url")
+		return
+	}
+	if sessionUrl.Scheme != target.SshProtocol {
+		w.logger.Error("invalid scheme for ssh proxy", "session_id", sessionId, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "invalid scheme for type")
+		return
+	}
+	if cred == nil {
+		w.logger.Error("no ssh credential for session", "session_id", sessionId)
+		conn.Close(websocket.StatusInternalError, "no ssh credential for session")
+		return
+	}
+	remoteConfig, err := sshClientConfig(cred)
+	if err != nil {
+		w.logger.Error("error loading ssh credential", "error", err, "session_id", sessionId)
+		conn.Close(websocket.StatusInternalError, "cannot load ssh credential")
+		return
+	}
+	clientConfig, err := sshServerConfig()
+	if err != nil {
+		w.logger.Error("error generating ssh host key", "error", err, "session_id", sessionId)
+		conn.Close(websocket.StatusInternalError, "cannot generate ssh host key")
+		return
+	}
+
+	addr := sessionUrl.Host
+	if sessionUrl.Port() == "" {
+		addr = net.JoinHostPort(sessionUrl.Hostname(), "22")
+	}
+	remoteConn, err := w.dialEndpoint(connCtx, si, ci, "tcp", addr)
+	if err != nil {
+		w.logger.Error("error dialing endpoint", "error", err, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "endpoint dialing failed")
+		return
+	}
+	remoteSsh, remoteChans, remoteReqs, err := ssh.NewClientConn(remoteConn, addr, remoteConfig)
+	if err != nil {
+		remoteConn.Close()
+		w.logger.Error("error authenticating to endpoint", "error", err, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "endpoint authentication failed")
+		return
+	}
+	defer remoteSsh.Close()
+
+	endpointAddr := remoteConn.RemoteAddr().(*net.TCPAddr)
+	connectionInfo := &pbs.ConnectConnectionRequest{
+		ConnectionId:       connectionId,
+		SessionId:          sessionId,
+		WorkerToken:        workerToken,
+		ClientTcpAddress:   clientAddr.IP.String(),
+		ClientTcpPort:      uint32(clientAddr.Port),
+		EndpointTcpAddress: endpointAddr.IP.String(),
+		EndpointTcpPort:    uint32(endpointAddr.Port),
+		Type:               "tcp",
+		Protocol:           target.SshProtocol,
+	}
+
+	connStatus, err := w.connectConnection(connCtx, connectionInfo)
+	if err != nil {
+		w.logger.Error("error marking connection as connected", "error", err)
+		conn.Close(websocket.StatusInternalError, "failed to mark connection as connected")
+		return
+	}
+	si.Lock()
+	si.connInfoMap[connectionId].status = connStatus
+	si.Unlock()
+
+	// Get a wrapped net.Conn so the ssh server can use it; counting it
+	// counts the bytes of the client's ssh connection.
+	wsConn := websocket.NetConn(connCtx, conn, websocket.MessageBinary)
+	netConn := &countingConn{
+		Conn: wsConn,
+		r:    throttle(connCtx, wsConn, bandwidthLimit),
+		ci:   ci,
+	}
+
+	clientSsh, clientChans, clientReqs, err := ssh.NewServerConn(netConn, clientConfig)
+	if err != nil {
+		w.logger.Error("error accepting ssh connection from client", "error", err, "session_id", sessionId)
+		netConn.Close()
+		return
+	}
+	defer clientSsh.Close()
+
+	logger := w.logger.With("session_id", sessionId, "connection_id", connectionId)
+	go proxySshRequests(logger, remoteSsh, clientReqs)
+	go proxySshRequests(logger, clientSsh, remoteReqs)
+	go proxySshChannels(logger, remoteSsh, clientChans)
+	go proxySshChannels(logger, clientSsh, remoteChans)
+
+	go func() {
+		err := clientSsh.Wait()
+		logger.Debug("client ssh connection done", "error", err)
+		remoteSsh.Close()
+	}()
+	err = remoteSsh.Wait()
+	logger.Debug("endpoint ssh connection done", "error", err)
+}
+
+// sshClientConfig returns the config the worker connects to the endpoint of
+// an ssh target with. The endpoint's host key is only checked if the target
+// has one.
+func sshClientConfig(cred *pbs.SshCredential) (*ssh.ClientConfig, error) {
+	signer, err := ssh.ParsePrivateKey(cred.GetPrivateKey())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key: %w", err)
+	}
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if cred.GetHostKey() != "" {
+		hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cred.GetHostKey()))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing host key: %w", err)
+		}
+		hostKeyCallback = ssh.FixedHostKey(hostKey)
+	}
+	return &ssh.ClientConfig{
+		User:            cred.GetUsername(),
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+	}, nil
+}
+
+// sshServerConfig returns the config the worker accepts the client's ssh
+// connection with. The host key is generated for each connection, as clients
+// have no way to know it in advance.
+func sshServerConfig() (*ssh.ServerConfig, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+	return config, nil
+}
+
+// proxySshRequests sends the global requests in reqs to dst, replying to them
+// with dst's replies.
+func proxySshRequests(logger hclog.Logger, dst ssh.Conn, reqs <-chan *ssh.Request) {
+	for req := range reqs {
+		ok, payload, err := dst.SendRequest(req.Type, req.WantReply, req.Payload)
+		if err != nil {
+			logger.Debug("error proxying ssh request", "type", req.Type, "error", err)
+		}
+		if req.WantReply {
+			req.Reply(ok, payload)
+		}
+	}
+}
+
+// proxySshChannels opens a channel on dst for every channel in chans and
+// proxies the channels' data and requests.
+func proxySshChannels(logger hclog.Logger, dst ssh.Conn, chans <-chan ssh.NewChannel) {
+	for newChan := range chans {
+		go proxySshChannel(logger, dst, newChan)
+	}
+}
+
+func proxySshChannel(logger hclog.Logger, dst ssh.Conn, newChan ssh.NewChannel) {
+	dstChan, dstReqs, err := dst.OpenChannel(newChan.ChannelType(), newChan.ExtraData())
+	if err != nil {
+		if openErr, ok := err.(*ssh.OpenChannelError); ok {
+			newChan.Reject(openErr.Reason, openErr.Message)
+		} else {
+			newChan.Reject(ssh.ConnectionFailed, err.Error())
+		}
+		return
+	}
+	srcChan, srcReqs, err := newChan.Accept()
+	if err != nil {
+		logger.Debug("error accepting ssh channel", "type", newChan.ChannelType(), "error", err)
+		dstChan.Close()
+		return
+	}
+
+	// The opener's requests and data are forwarded until it closes the
+	// channel, after which dst's channel is closed too.
+	go func() {
+		proxySshChannelRequests(dstChan, srcReqs)
+		dstChan.Close()
+	}()
+	go func() {
+		io.Copy(dstChan, srcChan)
+		dstChan.CloseWrite()
+	}()
+	go io.Copy(dstChan.Stderr(), srcChan.Stderr())
+
+	// dst's requests are forwarded until it closes the channel, and its data
+	// is completely copied, before the opener's channel is closed, so exit
+	// statuses and trailing output aren't lost.
+	reqsDone := make(chan struct{})
+	go func() {
+		proxySshChannelRequests(srcChan, dstReqs)
+		close(reqsDone)
+	}()
+	copyWg := new(sync.WaitGroup)
+	copyWg.Add(2)
+	go func() {
+		defer copyWg.Done()
+		io.Copy(srcChan, dstChan)
+	}()
+	go func() {
+		defer copyWg.Done()
+		io.Copy(srcChan.Stderr(), dstChan.Stderr())
+	}()
+	copyWg.Wait()
+	srcChan.CloseWrite()
+	<-reqsDone
+	srcChan.Close()
+}
+
+func proxySshChannelRequests(dst ssh.Channel, reqs <-chan *ssh.Request) {
+	for req := range reqs {
+		ok, err := dst.SendRequest(req.Type, req.WantReply, req.Payload)
+		if err != nil {
+			ok = false
+		}
+		if req.WantReply {
+			req.Reply(ok, nil)
+		}
+	}
+}
+
+// countingConn counts the bytes read from and written to a client's
+// connection in its connInfo, throttling reads with r.
+type countingConn struct {
+	net.Conn
+	r  io.Reader
+	ci *connInfo
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	atomic.AddUint64(&c.ci.bytesUp, uint64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	atomic.AddUint64(&c.ci.bytesDown, uint64(n))
+	return n, err
+}
@@ -6,9 +6,12 @@ import (
 	"time"
 
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/metric"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/version"
 	"google.golang.org/grpc/resolver"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // In the future we could make this configurable
@@ -48,6 +51,7 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 				// First send info as-is. We'll perform cleanup duties after we
 				// get cancel/job change info back.
 				var activeJobs []*pbs.JobStatus
+				var activeSessions, activeConnections uint32
 				w.sessionInfoMap.Range(func(key, value interface{}) bool {
 					var jobInfo pbs.SessionJobInfo
 					sessionId := key.(string)
@@ -57,11 +61,20 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					connections := make([]*pbs.Connection, 0, len(si.connInfoMap))
 					for k, v := range si.connInfoMap {
 						connections = append(connections, &pbs.Connection{
-							ConnectionId: k,
-							Status:       v.status,
+							ConnectionId:     k,
+							Status:           v.status,
+							LastActivityTime: timestamppb.New(v.activity()),
 						})
+						if v.status != pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED {
+							activeConnections++
+						}
 					}
 					si.RUnlock()
+					switch status {
+					case pbs.SESSIONSTATUS_SESSIONSTATUS_PENDING,
+						pbs.SESSIONSTATUS_SESSIONSTATUS_ACTIVE:
+						activeSessions++
+					}
 					jobInfo.SessionId = sessionId
 					activeJobs = append(activeJobs, &pbs.JobStatus{
 						Job: &pbs.Job{
@@ -77,17 +90,26 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					})
 					return true
 				})
+				metric.ProxyActiveSessions.Set(float64(activeSessions))
+				metric.ProxyActiveConnections.Set(float64(activeConnections))
 				client := w.controllerStatusConn.Load().(pbs.ServerCoordinationServiceClient)
-				result, err := client.Status(cancelCtx, &pbs.StatusRequest{
+				statusReq := &pbs.StatusRequest{
 					Jobs: activeJobs,
 					Worker: &servers.Server{
-						PrivateId:   w.conf.RawConfig.Worker.Name,
-						Name:        w.conf.RawConfig.Worker.Name,
-						Type:        resource.Worker.String(),
-						Description: w.conf.RawConfig.Worker.Description,
-						Address:     w.conf.RawConfig.Worker.PublicAddr,
+						PrivateId:      w.conf.RawConfig.Worker.Name,
+						Name:           w.conf.RawConfig.Worker.Name,
+						Type:           resource.Worker.String(),
+						Description:    w.conf.RawConfig.Worker.Description,
+						Address:        w.conf.RawConfig.Worker.PublicAddr,
+						ReleaseVersion: version.Get().VersionNumber(),
+						Tags:           servers.TagsFromMap(w.tags.Load().(map[string][]string)),
+
+						ActiveSessionCount:    activeSessions,
+						ActiveConnectionCount: activeConnections,
+						MaxSessions:           w.conf.RawConfig.Worker.MaxSessions,
 					},
-				})
+				}
+				result, err := client.Status(cancelCtx, statusReq, w.reportCallOptions()...)
 				if err != nil {
 					w.logger.Error("error making status request to controller", "error", err)
 				} else {
@@ -105,8 +127,30 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					default:
 						w.Resolver().UpdateState(resolver.State{Addresses: addrs})
 					}
+					prev, _ := w.lastStatusSuccess.Load().(*LastStatusInformation)
+					wasDraining := prev != nil && prev.GetDraining()
+					switch {
+					case result.GetDraining() && !wasDraining:
+						w.logger.Info("worker is draining; no new sessions will be assigned", "active_sessions", activeSessions)
+					case !result.GetDraining() && wasDraining:
+						w.logger.Info("worker is no longer draining")
+					}
 					w.lastStatusSuccess.Store(&LastStatusInformation{StatusResponse: result, StatusTime: time.Now()})
 
+					// Targets are registered once the worker is known to the
+					// controllers, so sessions for them can be assigned to it
+					if len(w.conf.RawConfig.Worker.Targets) > 0 && !w.targetsRegistered.Load() {
+						if err := w.registerTargets(cancelCtx); err != nil {
+							w.logger.Error("error registering worker targets", "error", err)
+						}
+					}
+
+					if w.usePki() {
+						if err := w.rotateWorkerCredentials(cancelCtx); err != nil {
+							w.logger.Error("error rotating worker certificate", "error", err)
+						}
+					}
+
 					for _, request := range result.GetJobsRequests() {
 						switch request.GetRequestType() {
 						case pbs.CHANGETYPE_CHANGETYPE_UPDATE_STATE:
@@ -122,6 +166,22 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 								si := siRaw.(*sessionInfo)
 								si.Lock()
 								si.status = sessInfo.GetStatus()
+								// Connections the controller has already
+								// recorded as closed are canceled here and
+								// don't need to be marked closed again.
+								for _, conn := range sessInfo.GetConnections() {
+									if conn.GetStatus() != pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED {
+										continue
+									}
+									ci, ok := si.connInfoMap[conn.GetConnectionId()]
+									if !ok || !ci.closeTime.IsZero() {
+										continue
+									}
+									ci.connCancel()
+									ci.status = pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED
+									ci.closeTime = time.Now()
+									w.logger.Info("terminated connection closed by controller", "session_id", sessionId, "connection_id", conn.GetConnectionId())
+								}
 								si.Unlock()
 							}
 						}
@@ -162,11 +222,19 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					return true
 				})
 
+				// Connections that closed since the last status, when
+				// connection reports are batched, are reported with these.
+				pendingCloses := w.takePendingCloses()
+				for k, v := range pendingCloses {
+					closeInfo[k] = v
+				}
+
 				// Note that we won't clean these from the info map until the
 				// next time we run this function
 				if len(closeInfo) > 0 {
 					if err := w.closeConnections(cancelCtx, closeInfo); err != nil {
 						w.logger.Error("error marking connections closed", "error", err)
+						w.requeuePendingCloses(pendingCloses)
 					}
 				}
 
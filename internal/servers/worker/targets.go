@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// targetDeregistrationTimeout is how long a shutting down worker waits for
+// the controller to deregister its targets.
+const targetDeregistrationTimeout = 10 * time.Second
+
+// validateTargets returns an error if any of the targets in the worker's
+// configuration is incomplete or they don't have unique names.
+func validateTargets(targets []*config.WorkerTarget) error {
+	names := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		switch {
+		case strings.TrimSpace(t.Name) == "":
+			return errors.New("worker targets must have a name")
+		case names[t.Name]:
+			return fmt.Errorf("worker target %q is defined more than once", t.Name)
+		case strings.TrimSpace(t.Address) == "":
+			return fmt.Errorf("worker target %q is missing an address", t.Name)
+		case t.Port <= 0 || t.Port > 65535:
+			return fmt.Errorf("worker target %q has an invalid port %d", t.Name, t.Port)
+		case t.ScopeId == "":
+			return fmt.Errorf("worker target %q is missing a scope id", t.Name)
+		}
+		names[t.Name] = true
+	}
+	return nil
+}
+
+// registerTargets registers the targets in the worker's configuration with
+// the controllers, replacing any it registered before.
+func (w *Worker) registerTargets(ctx context.Context) error {
+	client, ok := w.controllerStatusConn.Load().(pbs.ServerCoordinationServiceClient)
+	if !ok || client == nil {
+		return errors.New("could not get a controller client")
+	}
+	req := &pbs.RegisterWorkerTargetsRequest{
+		WorkerName: w.conf.RawConfig.Worker.Name,
+	}
+	for _, t := range w.conf.RawConfig.Worker.Targets {
+		req.Targets = append(req.Targets, &pbs.WorkerTarget{
+			Name:        t.Name,
+			Description: t.Description,
+			Address:     t.Address,
+			Port:        uint32(t.Port),
+			ScopeId:     t.ScopeId,
+		})
+	}
+	resp, err := client.RegisterWorkerTargets(ctx, req)
+	// Some targets may have been registered even if others failed, and
+	// trying again wouldn't fix the ones which failed, so registration is
+	// only tried again if the controller couldn't be reached.
+	if status.Code(err) != codes.Unavailable {
+		w.targetsRegistered.Store(true)
+	}
+	if err != nil {
+		return fmt.Errorf("error registering worker targets: %w", err)
+	}
+	w.logger.Info("worker targets registered", "targets", resp.GetTargetIds())
+	return nil
+}
+
+// deregisterTargets deletes the targets the worker registered with the
+// controllers.
+func (w *Worker) deregisterTargets(ctx context.Context) error {
+	client, ok := w.controllerStatusConn.Load().(pbs.ServerCoordinationServiceClient)
+	if !ok || client == nil {
+		return errors.New("could not get a controller client")
+	}
+	resp, err := client.DeregisterWorkerTargets(ctx, &pbs.DeregisterWorkerTargetsRequest{
+		WorkerName: w.conf.RawConfig.Worker.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("error deregistering worker targets: %w", err)
+	}
+	w.targetsRegistered.Store(false)
+	w.logger.Info("worker targets deregistered", "targets_deregistered", resp.GetTargetsDeregistered())
+	return nil
+}
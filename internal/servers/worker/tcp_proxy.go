@@ -6,15 +6,20 @@ import (
 	"net"
 	"net/url"
 	"sync"
+	"sync/atomic"
 
 	"nhooyr.io/websocket"
 
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 )
 
-func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, connectionId, endpoint string) {
+func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, ci *connInfo, endpoint string) {
+	connectionId := ci.id
 	si.RLock()
 	sessionId := si.lookupSessionResponse.GetAuthorization().GetSessionId()
+	workerToken := si.lookupSessionResponse.GetWorkerToken()
+	bandwidthLimit := si.lookupSessionResponse.GetConnectionBandwidthLimit()
+	endpointTls := si.lookupSessionResponse.GetEndpointTls()
 	si.RUnlock()
 
 	sessionUrl, err := url.Parse(endpoint)
@@ -28,23 +33,35 @@ func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAd
 		conn.Close(websocket.StatusInternalError, "invalid scheme for type")
 		return
 	}
-	remoteConn, err := net.Dial("tcp", sessionUrl.Host)
+	remoteConn, err := w.dialEndpoint(connCtx, si, ci, "tcp", sessionUrl.Host)
 	if err != nil {
 		w.logger.Error("error dialing endpoint", "error", err, "endpoint", endpoint)
 		conn.Close(websocket.StatusInternalError, "endpoint dialing failed")
 		return
 	}
-	// Assert this for better Go 1.11 splice support
-	tcpRemoteConn := remoteConn.(*net.TCPConn)
-
-	endpointAddr := tcpRemoteConn.RemoteAddr().(*net.TCPAddr)
+	endpointAddr := remoteConn.RemoteAddr().(*net.TCPAddr)
+	if endpointTls != nil {
+		tlsConn, err := originateTls(remoteConn, endpointTls, sessionUrl.Hostname())
+		if err != nil {
+			remoteConn.Close()
+			w.logger.Error("error originating tls to endpoint", "error", err, "session_id", sessionId, "endpoint", endpoint)
+			conn.Close(websocket.StatusInternalError, "endpoint tls handshake failed")
+			return
+		}
+		remoteConn = tlsConn
+	}
+	remoteReader, protocol, protocolMetadata := detectProtocol(remoteConn)
 	connectionInfo := &pbs.ConnectConnectionRequest{
 		ConnectionId:       connectionId,
+		SessionId:          sessionId,
+		WorkerToken:        workerToken,
 		ClientTcpAddress:   clientAddr.IP.String(),
 		ClientTcpPort:      uint32(clientAddr.Port),
 		EndpointTcpAddress: endpointAddr.IP.String(),
 		EndpointTcpPort:    uint32(endpointAddr.Port),
 		Type:               "tcp",
+		Protocol:           protocol,
+		ProtocolMetadata:   protocolMetadata,
 	}
 
 	connStatus, err := w.connectConnection(connCtx, connectionInfo)
@@ -64,12 +81,14 @@ func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAd
 	connWg.Add(2)
 	go func() {
 		defer connWg.Done()
-		_, err := io.Copy(netConn, tcpRemoteConn)
+		n, err := io.Copy(netConn, throttle(connCtx, remoteReader, bandwidthLimit))
+		atomic.AddUint64(&ci.bytesDown, uint64(n))
 		w.logger.Debug("copy from client to endpoint done", "error", err)
 	}()
 	go func() {
 		defer connWg.Done()
-		_, err := io.Copy(tcpRemoteConn, netConn)
+		n, err := io.Copy(remoteConn, throttle(connCtx, netConn, bandwidthLimit))
+		atomic.AddUint64(&ci.bytesUp, uint64(n))
 		w.logger.Debug("copy from endpoint to client done", "error", err)
 	}()
 	connWg.Wait()
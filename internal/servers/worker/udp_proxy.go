@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"nhooyr.io/websocket"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+)
+
+// maxDatagramSize is the largest payload a UDP datagram can carry.
+const maxDatagramSize = 65507
+
+// handleUdpProxyV1 relays datagrams between the client and a udp endpoint.
+// Each binary websocket message from the client is sent to the endpoint as a
+// datagram, and each datagram the endpoint sends back is sent to the client
+// as a message.
+func (w *Worker) handleUdpProxyV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, ci *connInfo, endpoint string) {
+	si.RLock()
+	sessionId := si.lookupSessionResponse.GetAuthorization().GetSessionId()
+	workerToken := si.lookupSessionResponse.GetWorkerToken()
+	bandwidthLimit := si.lookupSessionResponse.GetConnectionBandwidthLimit()
+	si.RUnlock()
+
+	sessionUrl, err := url.Parse(endpoint)
+	if err != nil {
+		w.logger.Error("error parsing endpoint information", "error", err, "session_id", sessionId, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "cannot parse endpoint url")
+		return
+	}
+	if sessionUrl.Scheme != "udp" {
+		w.logger.Error("invalid scheme for udp proxy", "session_id", sessionId, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "invalid scheme for type")
+		return
+	}
+	remoteConn, err := w.dialEndpoint(connCtx, si, ci, "udp", sessionUrl.Host)
+	if err != nil {
+		w.logger.Error("error dialing endpoint", "error", err, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "endpoint dialing failed")
+		return
+	}
+	defer remoteConn.Close()
+	conn.SetReadLimit(maxDatagramSize)
+
+	endpointAddr := remoteConn.RemoteAddr().(*net.UDPAddr)
+	connectionInfo := &pbs.ConnectConnectionRequest{
+		ConnectionId:       ci.id,
+		SessionId:          sessionId,
+		WorkerToken:        workerToken,
+		ClientTcpAddress:   clientAddr.IP.String(),
+		ClientTcpPort:      uint32(clientAddr.Port),
+		EndpointTcpAddress: endpointAddr.IP.String(),
+		EndpointTcpPort:    uint32(endpointAddr.Port),
+		Type:               "udp",
+	}
+
+	connStatus, err := w.connectConnection(connCtx, connectionInfo)
+	if err != nil {
+		w.logger.Error("error marking connection as connected", "error", err)
+		conn.Close(websocket.StatusInternalError, "failed to mark connection as connected")
+		return
+	}
+	si.Lock()
+	si.connInfoMap[ci.id].status = connStatus
+	si.Unlock()
+
+	// When either direction stops, stop the other: closing the remote
+	// connection ends the read from the endpoint, and canceling the context
+	// ends the read from the client.
+	relayCtx, relayCancel := context.WithCancel(connCtx)
+	defer relayCancel()
+
+	relayWg := new(sync.WaitGroup)
+	relayWg.Add(2)
+	go func() {
+		defer relayWg.Done()
+		defer remoteConn.Close()
+		limiter := newBandwidthLimiter(bandwidthLimit)
+		for {
+			typ, b, err := conn.Read(relayCtx)
+			if err != nil {
+				w.logger.Debug("relay from client to endpoint done", "error", err)
+				return
+			}
+			if typ != websocket.MessageBinary {
+				continue
+			}
+			if err := limiter.wait(relayCtx, len(b)); err != nil {
+				w.logger.Debug("relay from client to endpoint done", "error", err)
+				return
+			}
+			if _, err := remoteConn.Write(b); err != nil {
+				w.logger.Debug("relay from client to endpoint done", "error", err)
+				return
+			}
+			atomic.AddUint64(&ci.datagramsUp, 1)
+			atomic.AddUint64(&ci.bytesUp, uint64(len(b)))
+		}
+	}()
+	go func() {
+		defer relayWg.Done()
+		defer relayCancel()
+		buf := make([]byte, maxDatagramSize)
+		limiter := newBandwidthLimiter(bandwidthLimit)
+		for {
+			n, err := remoteConn.Read(buf)
+			if err != nil {
+				// An earlier datagram not being delivered doesn't stop
+				// later ones from being delivered
+				if errors.Is(err, syscall.ECONNREFUSED) {
+					continue
+				}
+				w.logger.Debug("relay from endpoint to client done", "error", err)
+				return
+			}
+			if err := limiter.wait(relayCtx, n); err != nil {
+				w.logger.Debug("relay from endpoint to client done", "error", err)
+				return
+			}
+			if err := conn.Write(relayCtx, websocket.MessageBinary, buf[:n]); err != nil {
+				w.logger.Debug("relay from endpoint to client done", "error", err)
+				return
+			}
+			atomic.AddUint64(&ci.datagramsDown, 1)
+			atomic.AddUint64(&ci.bytesDown, uint64(n))
+		}
+	}()
+	relayWg.Wait()
+}
@@ -3,11 +3,16 @@ package worker
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 
 	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/event"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/version"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"github.com/hashicorp/vault/sdk/helper/mlock"
@@ -31,7 +36,24 @@ type Worker struct {
 	controllerResolverCleanup *atomic.Value
 
 	controllerSessionConn *atomic.Value
+	controllerAuthConn    *atomic.Value
 	sessionInfoMap        *sync.Map
+
+	// The credentials used to authenticate to controllers when the worker
+	// uses a certificate issued by a controller
+	workerCredentials *atomic.Value
+
+	// The tags reported to controllers, which can be changed by reloading
+	tags *atomic.Value
+
+	// Closed connections waiting to be reported with the next status when
+	// connection reports are batched, mapping connection IDs to session IDs
+	pendingClosesLock *sync.Mutex
+	pendingCloses     map[string]string
+
+	// Whether the targets in the worker's configuration have been
+	// registered with the controllers
+	targetsRegistered ua.Bool
 }
 
 func New(conf *Config) (*Worker, error) {
@@ -43,7 +65,12 @@ func New(conf *Config) (*Worker, error) {
 		controllerResolver:        new(atomic.Value),
 		controllerResolverCleanup: new(atomic.Value),
 		controllerSessionConn:     new(atomic.Value),
+		controllerAuthConn:        new(atomic.Value),
 		sessionInfoMap:            new(sync.Map),
+		workerCredentials:         new(atomic.Value),
+		tags:                      new(atomic.Value),
+		pendingCloses:             make(map[string]string),
+		pendingClosesLock:         new(sync.Mutex),
 	}
 
 	w.lastStatusSuccess.Store((*LastStatusInformation)(nil))
@@ -64,6 +91,14 @@ func New(conf *Config) (*Worker, error) {
 			return nil, fmt.Errorf("error auto-generating worker name: %w", err)
 		}
 	}
+	w.tags.Store(conf.RawConfig.Worker.Tags)
+	if !servers.ValidCompression(conf.RawConfig.Worker.ReportCompression) {
+		return nil, fmt.Errorf("unknown report compression %q; must be %q or %q", conf.RawConfig.Worker.ReportCompression, servers.CompressionGzip, servers.CompressionSnappy)
+	}
+
+	if err := validateTargets(conf.RawConfig.Worker.Targets); err != nil {
+		return nil, err
+	}
 
 	if !conf.RawConfig.DisableMlock {
 		// Ensure our memory usage is locked into physical RAM
@@ -105,6 +140,10 @@ func (w *Worker) Start() error {
 
 	w.startStatusTicking(w.baseContext)
 	w.started.Store(true)
+	event.WriteSystem(w.baseContext, "worker.(Worker).Start", map[string]interface{}{
+		"name":    w.conf.RawConfig.Worker.Name,
+		"version": version.Get().VersionNumber(),
+	})
 
 	return nil
 }
@@ -118,6 +157,13 @@ func (w *Worker) Shutdown(skipListeners bool) error {
 		w.logger.Info("already shut down, skipping")
 		return nil
 	}
+	if w.targetsRegistered.Load() {
+		ctx, cancel := context.WithTimeout(context.Background(), targetDeregistrationTimeout)
+		if err := w.deregisterTargets(ctx); err != nil {
+			w.logger.Error("error deregistering worker targets", "error", err)
+		}
+		cancel()
+	}
 	w.Resolver().UpdateState(resolver.State{Addresses: []resolver.Address{}})
 	w.controllerResolverCleanup.Load().(func())()
 	w.baseCancel()
@@ -127,6 +173,39 @@ func (w *Worker) Shutdown(skipListeners bool) error {
 		}
 	}
 	w.started.Store(false)
+	event.WriteSystem(context.Background(), "worker.(Worker).Shutdown", map[string]interface{}{
+		"name": w.conf.RawConfig.Worker.Name,
+	})
+	return nil
+}
+
+// Reload applies the reloadable parts of newConf to the running worker,
+// which are its tags. They are sent to the controllers with the next status
+// report.
+func (w *Worker) Reload(newConf *config.Config) {
+	var tags map[string][]string
+	if newConf.Worker != nil {
+		tags = newConf.Worker.Tags
+	}
+	w.tags.Store(tags)
+}
+
+// SetTags replaces the tags set at runtime for the worker with tags, such as
+// labels an autoscaled worker learns after it has registered. They are
+// stored by the controller in addition to the tags in the configuration and
+// are kept until they are set again, including across restarts.
+func (w *Worker) SetTags(ctx context.Context, tags map[string][]string) error {
+	client, ok := w.controllerStatusConn.Load().(pbs.ServerCoordinationServiceClient)
+	if !ok || client == nil {
+		return errors.New("could not get a controller client")
+	}
+	_, err := client.SetWorkerTags(ctx, &pbs.SetWorkerTagsRequest{
+		WorkerName: w.conf.RawConfig.Worker.Name,
+		Tags:       servers.TagsFromMap(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting worker tags: %w", err)
+	}
 	return nil
 }
 
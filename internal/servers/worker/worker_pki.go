@@ -0,0 +1,278 @@
+package worker
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/servers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+)
+
+// workerAuthFile is the name of the file in the worker's auth storage path
+// containing the certificate, key and certificate authorities the worker uses
+// to authenticate to controllers.
+const workerAuthFile = "worker_auth.json"
+
+// workerCredentials are the certificate and key issued to the worker by a
+// controller along with the certificate authorities used to verify the
+// controllers, as stored in the worker's auth storage path.
+type workerCredentials struct {
+	CertificatePem []byte `json:"certificate_pem"`
+	PrivateKeyPem  []byte `json:"private_key_pem"`
+	CaBundlePem    []byte `json:"ca_bundle_pem"`
+
+	tlsCert     tls.Certificate
+	certificate *x509.Certificate
+	rootCas     *x509.CertPool
+}
+
+// parse populates the unexported fields of c from its PEM encoded fields.
+func (c *workerCredentials) parse() error {
+	var err error
+	if c.tlsCert, err = tls.X509KeyPair(c.CertificatePem, c.PrivateKeyPem); err != nil {
+		return fmt.Errorf("error parsing worker certificate: %w", err)
+	}
+	if c.certificate, err = x509.ParseCertificate(c.tlsCert.Certificate[0]); err != nil {
+		return fmt.Errorf("error parsing worker certificate: %w", err)
+	}
+	c.rootCas = x509.NewCertPool()
+	if !c.rootCas.AppendCertsFromPEM(c.CaBundlePem) {
+		return errors.New("no certificate authorities found")
+	}
+	return nil
+}
+
+// needsRotation returns true once two thirds of the certificate's lifetime
+// has passed.
+func (c *workerCredentials) needsRotation() bool {
+	lifetime := c.certificate.NotAfter.Sub(c.certificate.NotBefore)
+	return time.Now().After(c.certificate.NotBefore.Add(lifetime * 2 / 3))
+}
+
+// usePki returns true if the worker authenticates to controllers with a
+// certificate issued by a controller rather than with the worker auth KMS.
+func (w *Worker) usePki() bool {
+	return w.conf.RawConfig.Worker.AuthStoragePath != ""
+}
+
+func (w *Worker) loadWorkerCredentials() (*workerCredentials, error) {
+	path := filepath.Join(w.conf.RawConfig.Worker.AuthStoragePath, workerAuthFile)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	creds := new(workerCredentials)
+	if err := json.Unmarshal(raw, creds); err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+	if err := creds.parse(); err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// storeWorkerCredentials writes creds to the worker's auth storage path and
+// makes them the credentials used for new controller connections.
+func (w *Worker) storeWorkerCredentials(creds *workerCredentials) error {
+	if err := creds.parse(); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	dir := w.conf.RawConfig.Worker.AuthStoragePath
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("error creating auth storage path: %w", err)
+	}
+	// Write to a temporary file and rename it so a failure never leaves the
+	// worker without usable credentials.
+	tmp := filepath.Join(dir, workerAuthFile+".tmp")
+	if err := ioutil.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("error writing worker credentials: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, workerAuthFile)); err != nil {
+		return fmt.Errorf("error writing worker credentials: %w", err)
+	}
+	w.workerCredentials.Store(creds)
+	return nil
+}
+
+// ensureWorkerCredentials loads the worker's stored credentials or, if there
+// are none, registers the worker with one of the controllers using its
+// activation token.
+func (w *Worker) ensureWorkerCredentials(addrs []resolver.Address) error {
+	creds, err := w.loadWorkerCredentials()
+	switch {
+	case err == nil:
+		w.workerCredentials.Store(creds)
+		return nil
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	token := w.conf.RawConfig.Worker.ActivationToken
+	if token == "" {
+		return errors.New("worker has not registered and no activation token was provided")
+	}
+	var errs []string
+	for _, addr := range addrs {
+		err := w.registerWorker(addr.Addr, token)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", addr.Addr, err))
+	}
+	return fmt.Errorf("error registering worker: %s", strings.Join(errs, "; "))
+}
+
+// registerWorker exchanges the activation token for a certificate with the
+// controller at addr.
+func (w *Worker) registerWorker(addr, token string) error {
+	key, csrPem, err := w.newCertificateRequest()
+	if err != nil {
+		return err
+	}
+
+	fingerprints := servers.ActivationTokenCaFingerprints(token)
+	if len(fingerprints) == 0 {
+		return errors.New("activation token does not include any certificate authority fingerprints")
+	}
+
+	// The worker does not yet have the certificate authorities it will use to
+	// verify controllers, only the fingerprints in the activation token, so
+	// the default verification is replaced with verifying the controller's
+	// certificate was issued by one of those authorities.
+	tlsConf := &tls.Config{
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return servers.VerifyPinnedServerCertificate(fingerprints, rawCerts)
+		},
+		NextProtos: []string{servers.WorkerAuthRegistrationProto},
+		MinVersion: tls.VersionTLS13,
+	}
+	ctx, cancel := context.WithTimeout(w.baseContext, 30*time.Second)
+	defer cancel()
+	cc, err := grpc.DialContext(ctx, addr,
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			dialer := &net.Dialer{}
+			var conn net.Conn
+			var err error
+			switch {
+			case strings.HasPrefix(addr, "/"):
+				conn, err = dialer.DialContext(ctx, "unix", addr)
+			default:
+				conn, err = dialer.DialContext(ctx, "tcp", addr)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return tls.Client(conn, tlsConf), nil
+		}),
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return fmt.Errorf("error dialing controller: %w", err)
+	}
+	defer cc.Close()
+
+	resp, err := pbs.NewWorkerAuthServiceClient(cc).RegisterWorker(ctx, &pbs.RegisterWorkerRequest{
+		ActivationToken: token,
+		CsrPem:          csrPem,
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.storeWorkerCredentials(&workerCredentials{
+		CertificatePem: resp.GetCertificatePem(),
+		PrivateKeyPem:  key,
+		CaBundlePem:    resp.GetCaBundlePem(),
+	}); err != nil {
+		return err
+	}
+	w.logger.Info("worker registered", "address", addr)
+	return nil
+}
+
+// rotateWorkerCredentials exchanges the worker's certificate for a new one if
+// it is close to expiring.
+func (w *Worker) rotateWorkerCredentials(ctx context.Context) error {
+	creds, _ := w.workerCredentials.Load().(*workerCredentials)
+	if creds == nil || !creds.needsRotation() {
+		return nil
+	}
+	key, csrPem, err := w.newCertificateRequest()
+	if err != nil {
+		return err
+	}
+	client := w.controllerAuthConn.Load().(pbs.WorkerAuthServiceClient)
+	resp, err := client.RotateCertificate(ctx, &pbs.RotateCertificateRequest{
+		CsrPem: csrPem,
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.storeWorkerCredentials(&workerCredentials{
+		CertificatePem: resp.GetCertificatePem(),
+		PrivateKeyPem:  key,
+		CaBundlePem:    resp.GetCaBundlePem(),
+	}); err != nil {
+		return err
+	}
+	w.logger.Info("worker certificate rotated")
+	return nil
+}
+
+// newCertificateRequest generates a new key and returns it and a
+// certificate request for it, both PEM encoded.
+func (w *Worker) newCertificateRequest() ([]byte, []byte, error) {
+	_, priv, err := ed25519.GenerateKey(w.conf.SecureRandomReader)
+	if err != nil {
+		return nil, nil, err
+	}
+	marshaledKey, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(w.conf.SecureRandomReader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: w.conf.RawConfig.Worker.Name},
+	}, priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: marshaledKey}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}),
+		nil
+}
+
+// workerPkiTLSConfig returns the TLS config for connecting to a controller
+// with the worker's certificate.
+func (w *Worker) workerPkiTLSConfig() (*tls.Config, error) {
+	creds, _ := w.workerCredentials.Load().(*workerCredentials)
+	if creds == nil {
+		return nil, errors.New("worker has no certificate")
+	}
+	return &tls.Config{
+		ServerName:   servers.WorkerAuthServerName,
+		Certificates: []tls.Certificate{creds.tlsCert},
+		RootCAs:      creds.rootCas,
+		NextProtos:   []string{servers.WorkerAuthPkiProto},
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
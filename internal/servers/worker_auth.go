@@ -0,0 +1,298 @@
+package servers
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/structwrapping"
+)
+
+const (
+	// WorkerAuthServerName is the DNS name included in the certificates
+	// controllers present to workers authenticating with certificates. Workers
+	// verify the controller's certificate against this name.
+	WorkerAuthServerName = "boundary-cluster"
+
+	// WorkerAuthPkiProto is the ALPN protocol negotiated by a worker
+	// authenticating with a certificate issued by a controller.
+	WorkerAuthPkiProto = "v1workerpki"
+
+	// WorkerAuthRegistrationProto is the ALPN protocol negotiated by a worker
+	// registering with an activation token.
+	WorkerAuthRegistrationProto = "v1workerreg"
+
+	// workerCaLifetime is how long a worker certificate authority is valid.
+	workerCaLifetime = 365 * 24 * time.Hour
+
+	// workerCaRenewBefore is how long before a certificate authority expires
+	// that a new one is created. It must be longer than the lifetime of the
+	// certificates the authority issues.
+	workerCaRenewBefore = 30 * 24 * time.Hour
+
+	// workerCertLifetime is how long a certificate issued to a worker is valid.
+	workerCertLifetime = 14 * 24 * time.Hour
+
+	// activationTokenSeparator separates the secret of an activation token
+	// from the fingerprints of the certificate authorities it pins.
+	activationTokenSeparator = "."
+
+	// defaultActivationTokenTtl is how long an activation token can be used
+	// if no ttl is specified.
+	defaultActivationTokenTtl = 24 * time.Hour
+)
+
+// WorkerCa is a certificate authority used to issue the certificates
+// workers use to authenticate to controllers.
+type WorkerCa struct {
+	PrivateId      string
+	Certificate    *x509.Certificate
+	CertificatePem []byte
+	PrivateKey     ed25519.PrivateKey
+}
+
+// IssueServerCertificate issues a certificate for a controller to present to
+// workers. The certificate is valid for WorkerAuthServerName until
+// expiration.
+func (ca *WorkerCa) IssueServerCertificate(pub crypto.PublicKey, expiration time.Time) ([]byte, error) {
+	if expiration.After(ca.Certificate.NotAfter) {
+		expiration = ca.Certificate.NotAfter
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: WorkerAuthServerName},
+		DNSNames:    []string{WorkerAuthServerName},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		NotBefore:   time.Now().Add(-1 * time.Minute),
+		NotAfter:    expiration,
+	}
+	return ca.issue(template, pub)
+}
+
+// issueWorkerCertificate issues a client certificate for the named worker
+// for the public key in csr.
+func (ca *WorkerCa) issueWorkerCertificate(workerName string, csr *x509.CertificateRequest) (*x509.Certificate, error) {
+	expiration := time.Now().Add(workerCertLifetime)
+	if expiration.After(ca.Certificate.NotAfter) {
+		expiration = ca.Certificate.NotAfter
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: workerName},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		NotBefore:   time.Now().Add(-1 * time.Minute),
+		NotAfter:    expiration,
+	}
+	der, err := ca.issue(template, csr.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+func (ca *WorkerCa) issue(template *x509.Certificate, pub crypto.PublicKey) ([]byte, error) {
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, pub, ca.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error creating certificate: %w", err)
+	}
+	return der, nil
+}
+
+// newWorkerCa creates a new self signed certificate authority.
+func newWorkerCa() (*WorkerCa, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating key: %w", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Boundary Worker CA"},
+		SerialNumber:          serial,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		NotBefore:             time.Now().Add(-1 * time.Minute),
+		NotAfter:              time.Now().Add(workerCaLifetime),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ca certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing ca certificate: %w", err)
+	}
+	return &WorkerCa{
+		Certificate:    cert,
+		CertificatePem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		PrivateKey:     priv,
+	}, nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, fmt.Errorf("error generating serial number: %w", err)
+	}
+	return serial, nil
+}
+
+// parseCertificateRequest parses a PEM encoded certificate request and
+// verifies its signature.
+func parseCertificateRequest(csrPem []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(csrPem)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("certificate request is not PEM encoded")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing certificate request: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate request signature: %w", err)
+	}
+	return csr, nil
+}
+
+// CaFingerprint returns the fingerprint of a worker certificate authority
+// included in activation tokens.
+func CaFingerprint(cert *x509.Certificate) string {
+	h := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// ActivationTokenCaFingerprints returns the fingerprints of the certificate
+// authorities pinned by an activation token. A worker registering with the
+// token only trusts a controller presenting a certificate issued by one of
+// them.
+func ActivationTokenCaFingerprints(token string) []string {
+	parts := strings.Split(token, activationTokenSeparator)
+	return parts[1:]
+}
+
+// VerifyPinnedServerCertificate verifies the certificate chain rawCerts
+// presented by a controller to a registering worker. The chain must include
+// a certificate authority with one of the fingerprints which issued the
+// controller's certificate for WorkerAuthServerName.
+func VerifyPinnedServerCertificate(fingerprints []string, rawCerts [][]byte) error {
+	if len(fingerprints) == 0 {
+		return errors.New("no pinned certificate authorities")
+	}
+	if len(rawCerts) == 0 {
+		return errors.New("no server certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("error parsing server certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	pinned := make(map[string]bool, len(fingerprints))
+	for _, fp := range fingerprints {
+		pinned[fp] = true
+	}
+	roots := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		if pinned[CaFingerprint(cert)] {
+			roots.AddCert(cert)
+		}
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:   roots,
+		DNSName: WorkerAuthServerName,
+	}); err != nil {
+		return fmt.Errorf("server certificate not issued by a pinned certificate authority: %w", err)
+	}
+	return nil
+}
+
+// hashActivationToken returns the value stored in place of an activation
+// token. Only the secret is hashed, not the fingerprints following it.
+func hashActivationToken(token string) []byte {
+	secret := strings.SplitN(token, activationTokenSeparator, 2)[0]
+	h := sha256.Sum256([]byte(secret))
+	return h[:]
+}
+
+// workerCa is a certificate authority as stored in the worker_auth_ca table.
+type workerCa struct {
+	PrivateId    string `gorm:"primary_key"`
+	Certificate  []byte
+	CtPrivateKey []byte `gorm:"column:private_key" wrapping:"ct,private_key"`
+	PrivateKey   []byte `gorm:"-" wrapping:"pt,private_key"`
+	KeyId        string
+	NotBefore    time.Time
+	NotAfter     time.Time
+}
+
+// TableName overrides the table name used by gorm.
+func (workerCa) TableName() string {
+	return "worker_auth_ca"
+}
+
+func (c *workerCa) encrypt(ctx context.Context, cipher wrapping.Wrapper) error {
+	if err := structwrapping.WrapStruct(ctx, cipher, c, nil); err != nil {
+		return fmt.Errorf("error encrypting worker ca: %w", err)
+	}
+	c.KeyId = cipher.KeyID()
+	return nil
+}
+
+func (c *workerCa) decrypt(ctx context.Context, cipher wrapping.Wrapper) error {
+	if err := structwrapping.UnwrapStruct(ctx, cipher, c, nil); err != nil {
+		return fmt.Errorf("error decrypting worker ca: %w", err)
+	}
+	return nil
+}
+
+// workerActivationToken is an activation token as stored in the
+// worker_activation_token table.
+type workerActivationToken struct {
+	TokenHash      []byte `gorm:"primary_key"`
+	WorkerName     string
+	ExpirationTime time.Time
+}
+
+// TableName overrides the table name used by gorm.
+func (workerActivationToken) TableName() string {
+	return "worker_activation_token"
+}
+
+// workerCertificate is a certificate issued to a worker as stored in the
+// worker_auth_certificate table.
+type workerCertificate struct {
+	SerialNumber string `gorm:"primary_key"`
+	WorkerName   string
+	CaId         string
+	NotAfter     time.Time
+	// ReplacesSerialNumber is the certificate this certificate was issued to
+	// replace, until this certificate is first presented.
+	ReplacesSerialNumber string `gorm:"default:null"`
+}
+
+// TableName overrides the table name used by gorm.
+func (workerCertificate) TableName() string {
+	return "worker_auth_certificate"
+}
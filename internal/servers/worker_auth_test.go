@@ -0,0 +1,174 @@
+package servers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCertificateRequest(t *testing.T, name string) []byte {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: name},
+	}, priv)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})
+}
+
+func TestWorkerCa(t *testing.T) {
+	ca, err := newWorkerCa()
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Certificate)
+
+	t.Run("worker-certificate", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		csr, err := parseCertificateRequest(testCertificateRequest(t, "ignored"))
+		require.NoError(err)
+		cert, err := ca.issueWorkerCertificate("worker-1", csr)
+		require.NoError(err)
+		assert.Equal("worker-1", cert.Subject.CommonName)
+		assert.WithinDuration(time.Now().Add(workerCertLifetime), cert.NotAfter, time.Minute)
+		_, err = cert.Verify(x509.VerifyOptions{
+			Roots:     pool,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		assert.NoError(err)
+	})
+	t.Run("server-certificate", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(err)
+		der, err := ca.IssueServerCertificate(pub, time.Now().Add(2*workerCaLifetime))
+		require.NoError(err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(err)
+		assert.Equal(ca.Certificate.NotAfter, cert.NotAfter)
+		_, err = cert.Verify(x509.VerifyOptions{
+			Roots:   pool,
+			DNSName: WorkerAuthServerName,
+		})
+		assert.NoError(err)
+	})
+}
+
+func TestParseCertificateRequest(t *testing.T) {
+	valid := testCertificateRequest(t, "worker-1")
+	tampered := make([]byte, len(valid))
+	copy(tampered, valid)
+	block, _ := pem.Decode(tampered)
+	block.Bytes[len(block.Bytes)-1] ^= 0xff
+	tampered = pem.EncodeToMemory(block)
+
+	tests := []struct {
+		name    string
+		in      []byte
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			in:   valid,
+		},
+		{
+			name:    "not-pem",
+			in:      []byte("not a certificate request"),
+			wantErr: true,
+		},
+		{
+			name:    "wrong-pem-type",
+			in:      pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: block.Bytes}),
+			wantErr: true,
+		},
+		{
+			name:    "bad-signature",
+			in:      tampered,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCertificateRequest(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifyPinnedServerCertificate(t *testing.T) {
+	ca, err := newWorkerCa()
+	require.NoError(t, err)
+	other, err := newWorkerCa()
+	require.NoError(t, err)
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := ca.IssueServerCertificate(pub, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	otherDer, err := other.IssueServerCertificate(pub, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		fingerprints []string
+		rawCerts     [][]byte
+		wantErr      bool
+	}{
+		{
+			name:         "pinned",
+			fingerprints: []string{CaFingerprint(other.Certificate), CaFingerprint(ca.Certificate)},
+			rawCerts:     [][]byte{der, ca.Certificate.Raw},
+		},
+		{
+			name:     "no-fingerprints",
+			rawCerts: [][]byte{der, ca.Certificate.Raw},
+			wantErr:  true,
+		},
+		{
+			name:         "authority-not-presented",
+			fingerprints: []string{CaFingerprint(ca.Certificate)},
+			rawCerts:     [][]byte{der},
+			wantErr:      true,
+		},
+		{
+			name:         "authority-not-pinned",
+			fingerprints: []string{CaFingerprint(ca.Certificate)},
+			rawCerts:     [][]byte{otherDer, other.Certificate.Raw},
+			wantErr:      true,
+		},
+		{
+			name:         "not-issued-by-presented-authority",
+			fingerprints: []string{CaFingerprint(ca.Certificate)},
+			rawCerts:     [][]byte{otherDer, ca.Certificate.Raw},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyPinnedServerCertificate(tt.fingerprints, tt.rawCerts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestActivationToken(t *testing.T) {
+	assert := assert.New(t)
+	assert.Empty(ActivationTokenCaFingerprints("secret"))
+	assert.Equal([]string{"fp1", "fp2"}, ActivationTokenCaFingerprints("secret.fp1.fp2"))
+	// Only the secret is hashed
+	assert.Equal(hashActivationToken("secret"), hashActivationToken("secret.fp1.fp2"))
+}
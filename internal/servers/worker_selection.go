@@ -0,0 +1,37 @@
+package servers
+
+import (
+	"sort"
+)
+
+// AtCapacity returns true if the worker has reported a maximum number of
+// sessions and is already handling at least that many sessions.
+func (s *Server) AtCapacity() bool {
+	if s.GetMaxSessions() == 0 {
+		return false
+	}
+	return s.GetActiveSessionCount() >= s.GetMaxSessions()
+}
+
+// SelectWorkers orders workers for session authorization by their reported
+// load. Workers which are draining or at capacity are removed and the remaining workers
+// are ordered so the least loaded worker, determined first by the number of
+// active connections and then by the number of active sessions, is first.
+// The workers slice is not modified.
+func SelectWorkers(workers []*Server) []*Server {
+	selected := make([]*Server, 0, len(workers))
+	for _, w := range workers {
+		if w.GetDraining() || w.AtCapacity() {
+			continue
+		}
+		selected = append(selected, w)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		a, b := selected[i], selected[j]
+		if a.GetActiveConnectionCount() != b.GetActiveConnectionCount() {
+			return a.GetActiveConnectionCount() < b.GetActiveConnectionCount()
+		}
+		return a.GetActiveSessionCount() < b.GetActiveSessionCount()
+	})
+	return selected
+}
@@ -0,0 +1,73 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectWorkers(t *testing.T) {
+	w := func(name string, sessions, connections, max uint32) *Server {
+		return &Server{
+			PrivateId:             name,
+			ActiveSessionCount:    sessions,
+			ActiveConnectionCount: connections,
+			MaxSessions:           max,
+		}
+	}
+	tests := []struct {
+		name    string
+		workers []*Server
+		want    []string
+	}{
+		{
+			name: "no-workers",
+			want: []string{},
+		},
+		{
+			name:    "ordered-by-connections",
+			workers: []*Server{w("w1", 1, 5, 0), w("w2", 1, 1, 0), w("w3", 1, 3, 0)},
+			want:    []string{"w2", "w3", "w1"},
+		},
+		{
+			name:    "ties-ordered-by-sessions",
+			workers: []*Server{w("w1", 4, 2, 0), w("w2", 2, 2, 0), w("w3", 0, 3, 0)},
+			want:    []string{"w2", "w1", "w3"},
+		},
+		{
+			name:    "equal-load-keeps-order",
+			workers: []*Server{w("w1", 1, 1, 0), w("w2", 1, 1, 0)},
+			want:    []string{"w1", "w2"},
+		},
+		{
+			name:    "at-capacity-removed",
+			workers: []*Server{w("w1", 2, 0, 2), w("w2", 5, 9, 10), w("w3", 3, 0, 2)},
+			want:    []string{"w2"},
+		},
+		{
+			name: "draining-removed",
+			workers: []*Server{
+				w("w1", 0, 0, 0),
+				{PrivateId: "w2", Draining: true},
+				w("w3", 1, 1, 0),
+			},
+			want: []string{"w1", "w3"},
+		},
+		{
+			name:    "all-at-capacity",
+			workers: []*Server{w("w1", 1, 0, 1)},
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got := SelectWorkers(tt.workers)
+			ids := make([]string, 0, len(got))
+			for _, s := range got {
+				ids = append(ids, s.PrivateId)
+			}
+			assert.Equal(tt.want, ids)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	"github.com/mr-tron/base58"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// AuthzFormatBare is the original authorization token format: a bare
+	// marshaled SessionAuthorizationData message. Every client and worker
+	// can read it.
+	AuthzFormatBare uint32 = 1
+
+	// AuthzFormatEnveloped wraps the marshaled SessionAuthorizationData in a
+	// SessionAuthorizationEnvelope recording the format.
+	AuthzFormatEnveloped uint32 = 2
+
+	// CurrentAuthzFormat is the newest format this version can read and
+	// write.
+	CurrentAuthzFormat = AuthzFormatEnveloped
+)
+
+// envelopeMarker is the first byte of an enveloped token. A marshaled
+// protobuf message never starts with a zero byte, as field number zero is
+// invalid, so it tells enveloped tokens apart from bare ones.
+const envelopeMarker byte = 0x00
+
+// ErrUnsupportedAuthzFormat is returned when decoding an authorization token
+// encoded in a format newer than CurrentAuthzFormat.
+var ErrUnsupportedAuthzFormat = errors.New("unsupported authorization token format")
+
+// NegotiateAuthzFormat returns the format to encode an authorization token in
+// for a caller that can read formats up to maxFormat. Callers that don't say
+// which formats they read get AuthzFormatBare, so older clients keep working
+// during upgrades.
+func NegotiateAuthzFormat(maxFormat uint32) uint32 {
+	switch {
+	case maxFormat < AuthzFormatBare:
+		return AuthzFormatBare
+	case maxFormat > CurrentAuthzFormat:
+		return CurrentAuthzFormat
+	default:
+		return maxFormat
+	}
+}
+
+// EncodeAuthorizationData marshals data into a base58 authorization token
+// in the given format.
+func EncodeAuthorizationData(data *targets.SessionAuthorizationData, format uint32) (string, error) {
+	if data == nil {
+		return "", fmt.Errorf("encode authorization data: missing data: %w", db.ErrInvalidParameter)
+	}
+	marshaled, err := proto.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("encode authorization data: %w", err)
+	}
+	switch format {
+	case AuthzFormatBare:
+	case AuthzFormatEnveloped:
+		env, err := proto.Marshal(&targets.SessionAuthorizationEnvelope{
+			Format: format,
+			Data:   marshaled,
+		})
+		if err != nil {
+			return "", fmt.Errorf("encode authorization data: %w", err)
+		}
+		marshaled = append([]byte{envelopeMarker}, env...)
+	default:
+		return "", fmt.Errorf("encode authorization data: format %d: %w", format, ErrUnsupportedAuthzFormat)
+	}
+	return base58.FastBase58Encoding(marshaled), nil
+}
+
+// DecodeAuthorizationData decodes an authorization token in any format up to
+// CurrentAuthzFormat and returns the data and the format it was encoded in.
+// Tokens in newer formats return an error wrapping
+// ErrUnsupportedAuthzFormat rather than being misread.
+func DecodeAuthorizationData(token string) (*targets.SessionAuthorizationData, uint32, error) {
+	marshaled, err := base58.FastBase58Decoding(token)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decode authorization data: unable to base58-decode: %w", err)
+	}
+	if len(marshaled) == 0 {
+		return nil, 0, errors.New("decode authorization data: zero length after decoding")
+	}
+
+	format := AuthzFormatBare
+	if marshaled[0] == envelopeMarker {
+		var env targets.SessionAuthorizationEnvelope
+		if err := proto.Unmarshal(marshaled[1:], &env); err != nil {
+			return nil, 0, fmt.Errorf("decode authorization data: unable to proto-decode envelope: %w", err)
+		}
+		format = env.GetFormat()
+		switch format {
+		case AuthzFormatEnveloped:
+			marshaled = env.GetData()
+		default:
+			return nil, format, fmt.Errorf("decode authorization data: format %d is newer than the supported format %d: %w", format, CurrentAuthzFormat, ErrUnsupportedAuthzFormat)
+		}
+	}
+
+	data := new(targets.SessionAuthorizationData)
+	if err := proto.Unmarshal(marshaled, data); err != nil {
+		return nil, format, fmt.Errorf("decode authorization data: unable to proto-decode: %w", err)
+	}
+	return data, format, nil
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiateAuthzFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		maxFormat uint32
+		want      uint32
+	}{
+		{maxFormat: 0, want: AuthzFormatBare},
+		{maxFormat: AuthzFormatBare, want: AuthzFormatBare},
+		{maxFormat: AuthzFormatEnveloped, want: AuthzFormatEnveloped},
+		{maxFormat: CurrentAuthzFormat + 1, want: CurrentAuthzFormat},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NegotiateAuthzFormat(tt.maxFormat), "max format %d", tt.maxFormat)
+	}
+}
+
+func TestAuthorizationData_RoundTrip(t *testing.T) {
+	t.Parallel()
+	data := &targets.SessionAuthorizationData{
+		SessionId:       "s_1234567890",
+		TargetId:        "ttcp_1234567890",
+		Certificate:     []byte("certificate"),
+		PrivateKey:      []byte("private key"),
+		ConnectionLimit: 1,
+	}
+	for _, format := range []uint32{AuthzFormatBare, AuthzFormatEnveloped} {
+		token, err := EncodeAuthorizationData(data, format)
+		require.NoError(t, err)
+		got, gotFormat, err := DecodeAuthorizationData(token)
+		require.NoError(t, err)
+		assert.Equal(t, format, gotFormat)
+		assert.True(t, proto.Equal(data, got), "format %d", format)
+	}
+
+	t.Run("bare-is-unwrapped", func(t *testing.T) {
+		// Clients from before envelopes existed decode tokens this way.
+		token, err := EncodeAuthorizationData(data, AuthzFormatBare)
+		require.NoError(t, err)
+		marshaled, err := base58.FastBase58Decoding(token)
+		require.NoError(t, err)
+		got := new(targets.SessionAuthorizationData)
+		require.NoError(t, proto.Unmarshal(marshaled, got))
+		assert.True(t, proto.Equal(data, got))
+	})
+	t.Run("encode-unknown-format", func(t *testing.T) {
+		_, err := EncodeAuthorizationData(data, CurrentAuthzFormat+1)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrUnsupportedAuthzFormat))
+	})
+	t.Run("encode-nil-data", func(t *testing.T) {
+		_, err := EncodeAuthorizationData(nil, CurrentAuthzFormat)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+	})
+	t.Run("decode-newer-format", func(t *testing.T) {
+		env, err := proto.Marshal(&targets.SessionAuthorizationEnvelope{
+			Format: CurrentAuthzFormat + 1,
+			Data:   []byte("something new"),
+		})
+		require.NoError(t, err)
+		token := base58.FastBase58Encoding(append([]byte{envelopeMarker}, env...))
+		_, format, err := DecodeAuthorizationData(token)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrUnsupportedAuthzFormat))
+		assert.Equal(t, CurrentAuthzFormat+1, format)
+	})
+	t.Run("decode-garbage", func(t *testing.T) {
+		_, _, err := DecodeAuthorizationData("")
+		require.Error(t, err)
+		_, _, err = DecodeAuthorizationData("0OIl")
+		require.Error(t, err)
+	})
+}
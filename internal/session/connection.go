@@ -31,8 +31,21 @@ type Connection struct {
 	BytesUp uint64 `json:"bytes_up,omitempty" gorm:"default:null"`
 	// BytesDown of the connection
 	BytesDown uint64 `json:"bytes_down,omitempty" gorm:"default:null"`
+	// DatagramsUp of the connection, for udp targets
+	DatagramsUp uint64 `json:"datagrams_up,omitempty" gorm:"default:null"`
+	// DatagramsDown of the connection, for udp targets
+	DatagramsDown uint64 `json:"datagrams_down,omitempty" gorm:"default:null"`
 	// ClosedReason of the conneciont
 	ClosedReason string `json:"closed_reason,omitempty" gorm:"default:null"`
+	// Protocol is the application protocol the worker detected being spoken
+	// over the connection, such as ssh
+	Protocol string `json:"protocol,omitempty" gorm:"default:null"`
+	// ProtocolMetadata is a JSON object of what the worker learned about the
+	// protocol when the connection was made
+	ProtocolMetadata string `json:"protocol_metadata,omitempty" gorm:"default:null"`
+	// LastActivityTime is when traffic was last proxied for the connection,
+	// as reported by the worker
+	LastActivityTime *timestamp.Timestamp `json:"last_activity_time,omitempty" gorm:"default:null"`
 	// CreateTime from the RDBMS
 	CreateTime *timestamp.Timestamp `json:"create_time,omitempty" gorm:"default:current_timestamp"`
 	// UpdateTime from the RDBMS
@@ -82,7 +95,11 @@ func (c *Connection) Clone() interface{} {
 		EndpointTcpPort:    c.EndpointTcpPort,
 		BytesUp:            c.BytesUp,
 		BytesDown:          c.BytesDown,
+		DatagramsUp:        c.DatagramsUp,
+		DatagramsDown:      c.DatagramsDown,
 		ClosedReason:       c.ClosedReason,
+		Protocol:           c.Protocol,
+		ProtocolMetadata:   c.ProtocolMetadata,
 		Version:            c.Version,
 	}
 	if c.CreateTime != nil {
@@ -101,6 +118,14 @@ func (c *Connection) Clone() interface{} {
 			},
 		}
 	}
+	if c.LastActivityTime != nil {
+		clone.LastActivityTime = &timestamp.Timestamp{
+			Timestamp: &timestamppb.Timestamp{
+				Seconds: c.LastActivityTime.Timestamp.Seconds,
+				Nanos:   c.LastActivityTime.Timestamp.Nanos,
+			},
+		}
+	}
 	return clone
 }
 
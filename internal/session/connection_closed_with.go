@@ -12,7 +12,10 @@ type CloseWith struct {
 	ConnectionId string
 	BytesUp      uint64
 	BytesDown    uint64
-	ClosedReason ClosedReason
+	// DatagramsUp and DatagramsDown are only counted for udp targets
+	DatagramsUp   uint64
+	DatagramsDown uint64
+	ClosedReason  ClosedReason
 }
 
 func (c CloseWith) validate() error {
@@ -22,6 +25,6 @@ func (c CloseWith) validate() error {
 	if c.ClosedReason.String() == "" {
 		return fmt.Errorf("missing closed reason: %w", db.ErrInvalidParameter)
 	}
-	// 0 is valid for BytesUp and BytesDown
+	// 0 is valid for BytesUp, BytesDown, DatagramsUp and DatagramsDown
 	return nil
 }
@@ -0,0 +1,107 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/target"
+)
+
+// EndpointAddress returns the host and port of a session's endpoint, which
+// are included in its authorization data so workers can refuse to dial
+// anything else for the session. The port of an ssh endpoint without one is
+// 22.
+func EndpointAddress(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("endpoint address: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("endpoint address: %q has no host: %w", endpoint, db.ErrInvalidParameter)
+	}
+	if u.Port() == "" && u.Scheme == target.SshProtocol {
+		return net.JoinHostPort(u.Hostname(), "22"), nil
+	}
+	return u.Host, nil
+}
+
+// EgressViolation is an address a worker refused to dial for a session
+// because it isn't the session's endpoint.
+type EgressViolation struct {
+	SessionId string
+	// ConnectionId is the connection the address was dialed for, if any.
+	ConnectionId string
+	WorkerId     string
+	// Endpoint is the host and port in the session's authorization data.
+	Endpoint string
+	// Address is the host and port the worker refused to dial.
+	Address    string
+	CreateTime time.Time
+}
+
+// RecordEgressViolation records that a worker refused to dial an address for
+// a session. The session, worker, endpoint and address are required.
+func (r *Repository) RecordEgressViolation(ctx context.Context, v *EgressViolation) error {
+	switch {
+	case v == nil:
+		return fmt.Errorf("record egress violation: missing violation: %w", db.ErrInvalidParameter)
+	case v.SessionId == "":
+		return fmt.Errorf("record egress violation: missing session id: %w", db.ErrInvalidParameter)
+	case v.WorkerId == "":
+		return fmt.Errorf("record egress violation: missing worker id: %w", db.ErrInvalidParameter)
+	case v.Endpoint == "":
+		return fmt.Errorf("record egress violation: missing endpoint: %w", db.ErrInvalidParameter)
+	case v.Address == "":
+		return fmt.Errorf("record egress violation: missing address: %w", db.ErrInvalidParameter)
+	}
+	var connectionId interface{}
+	if v.ConnectionId != "" {
+		connectionId = v.ConnectionId
+	}
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			_, err := w.Exec(ctx, insertEgressViolation, []interface{}{v.SessionId, connectionId, v.WorkerId, v.Endpoint, v.Address})
+			return err
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("record egress violation: %w", err)
+	}
+	return nil
+}
+
+// ListEgressViolations returns the egress violations reported for the
+// session, oldest first.
+func (r *Repository) ListEgressViolations(ctx context.Context, sessionId string) ([]*EgressViolation, error) {
+	if sessionId == "" {
+		return nil, fmt.Errorf("list egress violations: missing session id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, listEgressViolations, []interface{}{sessionId})
+	if err != nil {
+		return nil, fmt.Errorf("list egress violations: %w", err)
+	}
+	defer rows.Close()
+	var violations []*EgressViolation
+	for rows.Next() {
+		var v EgressViolation
+		var connectionId *string
+		if err := rows.Scan(&v.SessionId, &connectionId, &v.WorkerId, &v.Endpoint, &v.Address, &v.CreateTime); err != nil {
+			return nil, fmt.Errorf("list egress violations: %w", err)
+		}
+		if connectionId != nil {
+			v.ConnectionId = *connectionId
+		}
+		violations = append(violations, &v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list egress violations: %w", err)
+	}
+	return violations, nil
+}
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{name: "tcp", endpoint: "tcp://10.0.0.1:8080", want: "10.0.0.1:8080"},
+		{name: "ssh-default-port", endpoint: "ssh://10.0.0.1", want: "10.0.0.1:22"},
+		{name: "ssh-port", endpoint: "ssh://10.0.0.1:2222", want: "10.0.0.1:2222"},
+		{name: "ipv6", endpoint: "udp://[::1]:53", want: "[::1]:53"},
+		{name: "no-host", endpoint: "tcp://", wantErr: true},
+		{name: "invalid", endpoint: "tcp://%zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := EndpointAddress(tt.endpoint)
+			if tt.wantErr {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestRepository_EgressViolations(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	s := TestDefaultSession(t, conn, wrapper, iamRepo)
+	c := TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			v    *EgressViolation
+		}{
+			{name: "nil"},
+			{name: "missing-session", v: &EgressViolation{WorkerId: "w", Endpoint: "127.0.0.1:2222", Address: "127.0.0.2:2222"}},
+			{name: "missing-worker", v: &EgressViolation{SessionId: s.PublicId, Endpoint: "127.0.0.1:2222", Address: "127.0.0.2:2222"}},
+			{name: "missing-endpoint", v: &EgressViolation{SessionId: s.PublicId, WorkerId: "w", Address: "127.0.0.2:2222"}},
+			{name: "missing-address", v: &EgressViolation{SessionId: s.PublicId, WorkerId: "w", Endpoint: "127.0.0.1:2222"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := repo.RecordEgressViolation(ctx, tt.v)
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+			})
+		}
+		_, err := repo.ListEgressViolations(ctx, "")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+	})
+
+	got, err := repo.ListEgressViolations(ctx, s.PublicId)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	require.NoError(t, repo.RecordEgressViolation(ctx, &EgressViolation{
+		SessionId:    s.PublicId,
+		ConnectionId: c.PublicId,
+		WorkerId:     "w_1234567890",
+		Endpoint:     "127.0.0.1:2222",
+		Address:      "127.0.0.2:2222",
+	}))
+	require.NoError(t, repo.RecordEgressViolation(ctx, &EgressViolation{
+		SessionId: s.PublicId,
+		WorkerId:  "w_1234567890",
+		Endpoint:  "127.0.0.1:2222",
+		Address:   "127.0.0.1:3333",
+	}))
+
+	got, err = repo.ListEgressViolations(ctx, s.PublicId)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	for _, v := range got {
+		assert.Equal(t, s.PublicId, v.SessionId)
+		assert.Equal(t, "w_1234567890", v.WorkerId)
+		assert.Equal(t, "127.0.0.1:2222", v.Endpoint)
+		assert.False(t, v.CreateTime.IsZero())
+	}
+	byAddress := map[string]*EgressViolation{got[0].Address: got[0], got[1].Address: got[1]}
+	require.Contains(t, byAddress, "127.0.0.2:2222")
+	require.Contains(t, byAddress, "127.0.0.1:3333")
+	assert.Equal(t, c.PublicId, byAddress["127.0.0.2:2222"].ConnectionId)
+	assert.Empty(t, byAddress["127.0.0.1:3333"].ConnectionId)
+}
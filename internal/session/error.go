@@ -1,6 +1,9 @@
 package session
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Errors returned from this package may be tested against these errors
 // with errors.Is.
@@ -13,7 +16,39 @@ var (
 	// because it's not in a pending state.
 	ErrSessionNotPending = errors.New("session is not in a pending state")
 
-	// ErrOpenConnection indicates that a session can not be terminated because
-	// it has open connections.
+	// ErrOpenConnection indicates that a session can not be terminated or
+	// deleted because it has open connections.
 	ErrOpenConnection = errors.New("session has open connections")
+
+	// ErrConnectionConflict indicates that a connection wasn't updated
+	// because it was changed or closed since it was read. The errors returned
+	// for it are *ConnectionConflictError.
+	ErrConnectionConflict = errors.New("connection update conflict")
 )
+
+// ConnectionConflictError is returned when an update of a connection loses a
+// race with another update, such as a worker reporting a connection as
+// connected after an operator closed it. It matches ErrConnectionConflict.
+type ConnectionConflictError struct {
+	ConnectionId string
+	// Version is the version the update expected, or 0 if the update wasn't
+	// version checked, and CurrentVersion is the connection's version when
+	// the update was made.
+	Version        uint32
+	CurrentVersion uint32
+	// Closed is true if the connection had been closed. Closing always wins,
+	// so closed connections can't be changed by other updates.
+	Closed bool
+}
+
+func (e *ConnectionConflictError) Error() string {
+	if e.Closed {
+		return fmt.Sprintf("connection %s has been closed", e.ConnectionId)
+	}
+	return fmt.Sprintf("connection %s is at version %d, not %d", e.ConnectionId, e.CurrentVersion, e.Version)
+}
+
+// Is returns true if target is ErrConnectionConflict.
+func (e *ConnectionConflictError) Is(target error) bool {
+	return target == ErrConnectionConflict
+}
@@ -3,6 +3,8 @@ package session
 import (
 	"fmt"
 
+	"github.com/hashicorp/boundary/internal/types/publicid"
+
 	"github.com/hashicorp/boundary/internal/db"
 )
 
@@ -21,7 +23,7 @@ const (
 )
 
 func newId() (string, error) {
-	id, err := db.NewPublicId(SessionPrefix)
+	id, err := publicid.New(SessionPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new session id: %w", err)
 	}
@@ -42,10 +42,19 @@ func TestSession_ImmutableFields(t *testing.T) {
 			name: "certificate",
 			update: func() *Session {
 				s := new.Clone().(*Session)
-				s.Certificate = []byte("fake cert for test")
+				s.CtCertificate = []byte("fake cert for test")
 				return s
 			}(),
-			fieldMask: []string{"Certificate"},
+			fieldMask: []string{"CtCertificate"},
+		},
+		{
+			name: "certificate key id",
+			update: func() *Session {
+				s := new.Clone().(*Session)
+				s.CertificateKeyId = "fake key id for test"
+				return s
+			}(),
+			fieldMask: []string{"CertificateKeyId"},
 		},
 		{
 			name: "expiration time",
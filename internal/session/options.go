@@ -1,6 +1,7 @@
 package session
 
 import (
+	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
 )
 
@@ -26,6 +27,13 @@ type options struct {
 	withTestTofu       []byte
 	withListingConvert bool
 	withSessionIds     []string
+	withStatus         Status
+	withDecryptedCert  bool
+	withProtocol       string
+	withClientIp       string
+	withForce          bool
+	withReader         db.Reader
+	withWriter         db.Writer
 }
 
 func getDefaultOptions() options {
@@ -84,6 +92,58 @@ func WithSessionIds(ids ...string) Option {
 	}
 }
 
+// WithStatus allows specifying the current status of the sessions for the
+// operation.
+func WithStatus(status Status) Option {
+	return func(o *options) {
+		o.withStatus = status
+	}
+}
+
+// WithDecryptedCertificate allows specifying that the session's certificate
+// should be decrypted when it's looked up. It should only be used by callers
+// which need the certificate to authorize connections.
+func WithDecryptedCertificate(decrypt bool) Option {
+	return func(o *options) {
+		o.withDecryptedCert = decrypt
+	}
+}
+
+// WithProtocol allows specifying that only connections over which the
+// protocol was detected should be listed.
+func WithProtocol(protocol string) Option {
+	return func(o *options) {
+		o.withProtocol = protocol
+	}
+}
+
+// WithClientIp allows specifying the address of the client which authorized
+// the sessions for the operation.
+func WithClientIp(ip string) Option {
+	return func(o *options) {
+		o.withClientIp = ip
+	}
+}
+
+// WithForce allows DeleteSession to delete a session which still has open
+// connections.
+func WithForce(force bool) Option {
+	return func(o *options) {
+		o.withForce = force
+	}
+}
+
+// WithReaderWriter allows CreateSession and ConnectConnection to run in a
+// transaction managed by the caller, using its reader and writer, instead of
+// in their own transaction. The caller is responsible for committing or
+// rolling back the transaction, and for retrying it.
+func WithReaderWriter(r db.Reader, w db.Writer) Option {
+	return func(o *options) {
+		o.withReader = r
+		o.withWriter = w
+	}
+}
+
 func withListingConvert(withListingConvert bool) Option {
 	return func(o *options) {
 		o.withListingConvert = withListingConvert
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -77,4 +78,41 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withSessionIds = []string{"s_1", "s_2", "s_3"}
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithStatus", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithStatus(StatusActive))
+		testOpts := getDefaultOptions()
+		testOpts.withStatus = StatusActive
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithProtocol", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithProtocol("ssh"))
+		testOpts := getDefaultOptions()
+		testOpts.withProtocol = "ssh"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithClientIp", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithClientIp("127.0.0.1"))
+		testOpts := getDefaultOptions()
+		testOpts.withClientIp = "127.0.0.1"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithForce", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithForce(true))
+		testOpts := getDefaultOptions()
+		testOpts.withForce = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithReaderWriter", func(t *testing.T) {
+		assert := assert.New(t)
+		rw := &db.Db{}
+		opts := getOpts(WithReaderWriter(rw, rw))
+		testOpts := getDefaultOptions()
+		testOpts.withReader = rw
+		testOpts.withWriter = rw
+		assert.Equal(opts, testOpts)
+	})
 }
@@ -144,14 +144,52 @@ select expiration_time, connection_limit, current_connection_count
 from  
 	session_connection_limit, session_connection_count;	
 `
+	// sessionList lists sessions with only their current state, which is
+	// found for each session with a lateral join on the covering index of
+	// session_state so the cost of each row doesn't grow with the number of
+	// states the session has been through.
 	sessionList = `
-select * 
+select
+	s.public_id,
+	s.user_id,
+	s.host_id,
+	s.server_id,
+	s.server_type,
+	s.target_id,
+	s.host_set_id,
+	s.auth_token_id,
+	s.scope_id,
+	s.certificate,
+	s.expiration_time,
+	s.connection_limit,
+	s.tofu_token,
+	s.key_id,
+	s.termination_reason,
+	s.version,
+	s.create_time,
+	s.update_time,
+	s.endpoint,
+	s.client_ip,
+	s.user_agent,
+	s.connection_bandwidth_limit,
+	s.allowed_ports,
+	s.host_address,
+	s.host_selection_reason,
+	ss.state,
+	ss.previous_end_time,
+	ss.start_time,
+	ss.end_time
 from
-	(select public_id from session %s) s,
-	session_with_state ss
-where 
-	s.public_id = ss.public_id 
-	%s
+	session s
+	cross join lateral (
+		select state, previous_end_time, start_time, end_time
+		from session_state
+		where session_id = s.public_id
+		order by start_time desc
+		limit 1
+	) ss
+%s
+%s
 %s
 `
 
@@ -231,5 +269,296 @@ where
                	end_time is null
     )
 )
+returning termination_reason;
+`
+
+	// sessionChanges returns the current state of the given sessions which
+	// are canceling or terminated, along with any of their connections which
+	// are closed. A session with closed connections is returned regardless of
+	// its state, in which case there is one row per closed connection.
+	sessionChanges = `
+select 
+	ss.session_id,
+	ss.state,
+	cc.connection_id
+from
+	session_state ss
+left join (
+	select 
+		sc.session_id,
+		sc.public_id as connection_id
+	from
+		session_connection sc,
+		session_connection_state scs
+	where
+		sc.public_id = scs.connection_id and
+		scs.state = 'closed' and
+		scs.end_time is null
+) cc on cc.session_id = ss.session_id
+where
+	-- if there's no end_time, then this is the current state.
+	ss.end_time is null and
+	ss.session_id in (%s) and
+	(
+		ss.state in ('canceling', 'terminated') or
+		cc.connection_id is not null
+	)
+order by ss.session_id;
+`
+
+	workerSessionDigests = `
+select
+	s.public_id,
+	s.version,
+	ss.state
+from
+	session s,
+	session_state ss
+where
+	ss.session_id = s.public_id and
+	-- if there's no end_time, then this is the current state.
+	ss.end_time is null and
+	ss.state != 'terminated' and
+	s.server_id = $1
+order by s.public_id;
+`
+
+	// updateConnectionActivity only records activity for connections which
+	// aren't closed, and only when it's at least a minute newer than what's
+	// recorded so connections aren't updated for every worker status.
+	updateConnectionActivity = `
+update session_connection
+set
+	last_activity_time = $2
+where
+	public_id = $1 and
+	closed_reason is null and
+	(
+		last_activity_time is null or
+		last_activity_time < $2::timestamptz - interval '1 minute'
+	);
+`
+
+	// closeIdleConnections closes the connections which have gone longer
+	// than their target's idle timeout without any activity. Connections
+	// which have never reported activity are idle since they were created.
+	// closeConnections is formatted with a values row of (public_id,
+	// bytes_up, bytes_down, datagrams_up, datagrams_down, closed_reason) for
+	// each connection. Closing always wins: a connection which is already
+	// closed keeps its closed reason, and only takes counts larger than
+	// those it has, such as the worker's counts for a connection an
+	// operator closed.
+	closeConnections = `
+update session_connection c
+set
+	bytes_up = greatest(c.bytes_up, v.bytes_up),
+	bytes_down = greatest(c.bytes_down, v.bytes_down),
+	datagrams_up = greatest(c.datagrams_up, v.datagrams_up),
+	datagrams_down = greatest(c.datagrams_down, v.datagrams_down),
+	closed_reason = coalesce(c.closed_reason, v.closed_reason)
+from
+	(values %s) as v(public_id, bytes_up, bytes_down, datagrams_up, datagrams_down, closed_reason)
+where
+	c.public_id = v.public_id and (
+		c.closed_reason is null or
+		coalesce(c.bytes_up, 0) < v.bytes_up or
+		coalesce(c.bytes_down, 0) < v.bytes_down or
+		coalesce(c.datagrams_up, 0) < v.datagrams_up or
+		coalesce(c.datagrams_down, 0) < v.datagrams_down
+	);
+`
+
+	closeIdleConnections = `
+update session_connection
+set
+	closed_reason = 'idle-timeout'
+where
+	closed_reason is null and
+	public_id in (
+		select
+			sc.public_id
+		from
+			session_connection sc,
+			session s,
+			target_tcp t
+		where
+			sc.session_id = s.public_id and
+			s.target_id = t.public_id and
+			sc.closed_reason is null and
+			t.connection_idle_timeout_seconds > 0 and
+			coalesce(sc.last_activity_time, sc.create_time) <
+				now() - make_interval(secs => t.connection_idle_timeout_seconds)
+	)
+returning public_id;
+`
+
+	// cancelNeverConnectedSessions terminates the sessions which are still
+	// pending longer after they were authorized than their target allows.
+	// Pending sessions have no connections, so setting the termination
+	// reason moves them straight to terminated.
+	cancelNeverConnectedSessions = `
+update session us
+set
+	termination_reason = 'never-connected'
+from
+	target_tcp t,
+	session_state ss
+where
+	us.target_id = t.public_id and
+	ss.session_id = us.public_id and
+	us.termination_reason is null and
+	t.authorization_valid_seconds > 0 and
+	ss.state = 'pending' and
+	ss.end_time is null and
+	us.create_time < now() - make_interval(secs => t.authorization_valid_seconds)
+returning us.public_id;
+`
+
+	// sessionCountByStatus counts the sessions matching the where clause by
+	// their current state, found the same way as in sessionList.
+	sessionCountByStatus = `
+select
+	ss.state,
+	count(*)
+from
+	session s
+	cross join lateral (
+		select state
+		from session_state
+		where session_id = s.public_id
+		order by start_time desc
+		limit 1
+	) ss
+where %s
+group by ss.state;
+`
+
+	openConnectionCount = `
+select count(*)
+  from session_connection
+ where session_id = $1 and closed_reason is null;
+`
+
+	// purgeTerminatedSessions deletes up to $2 sessions whose current state
+	// is terminated and started more than $1 seconds ago. Sessions locked by
+	// another transaction are skipped rather than waited for. Their states,
+	// connections and connection states are deleted by cascade.
+	purgeTerminatedSessions = `
+delete from session
+where
+	public_id in (
+		select
+			s.public_id
+		from
+			session s,
+			session_state ss
+		where
+			ss.session_id = s.public_id and
+			ss.state = 'terminated' and
+			ss.end_time is null and
+			ss.start_time < now() - make_interval(secs => $1)
+		limit $2
+		for update of s skip locked
+	);
+`
+
+	// sessionReferencesQuery checks that the host set of a session is one of
+	// its target's host sets, that its host is a member of the host set, and
+	// that its auth token belongs to its user.
+	sessionReferencesQuery = `
+select
+  exists (
+    select 1
+      from target_host_set
+     where target_id = $1 and host_set_id = $2
+  ) as host_set_in_target,
+  exists (
+    select 1
+      from static_host_set_member
+     where set_id = $2 and host_id = $3
+  ) as host_in_host_set,
+  exists (
+    select 1
+      from auth_token at
+      join auth_account aa on aa.public_id = at.auth_account_id
+     where at.public_id = $4 and aa.iam_user_id = $5
+  ) as auth_token_of_user;
+`
+
+	// terminatedSummariesQuery returns the summaries of the sessions
+	// terminated after the session terminated at $1 with id $2, in the order
+	// they were terminated.
+	terminatedSummariesQuery = `
+select s.public_id,
+       coalesce(s.scope_id, '') as scope_id,
+       coalesce(s.user_id, '') as user_id,
+       coalesce(s.target_id, '') as target_id,
+       coalesce(s.host_id, '') as host_id,
+       coalesce(s.host_set_id, '') as host_set_id,
+       coalesce(s.auth_token_id, '') as auth_token_id,
+       coalesce(s.server_id, '') as server_id,
+       coalesce(s.endpoint, '') as endpoint,
+       coalesce(s.termination_reason, '') as termination_reason,
+       s.create_time,
+       ss.start_time as terminate_time,
+       count(sc.public_id) as connection_count,
+       coalesce(sum(sc.bytes_up), 0) as bytes_up,
+       coalesce(sum(sc.bytes_down), 0) as bytes_down
+  from session s
+  join session_state ss
+    on ss.session_id = s.public_id and ss.state = 'terminated'
+  left join session_connection sc
+    on sc.session_id = s.public_id
+ where (ss.start_time, s.public_id) > ($1, $2)
+ group by s.public_id, ss.start_time
+ order by ss.start_time, s.public_id
+ limit $3;
+`
+
+	// upsertSummaryQuery inserts a session summary into a replica database,
+	// or updates it if the session was already replicated.
+	upsertSummaryQuery = `
+insert into session_summary
+  (public_id, scope_id, user_id, target_id, host_id, host_set_id, auth_token_id, server_id, endpoint, termination_reason, create_time, terminate_time, connection_count, bytes_up, bytes_down)
+values
+  ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+on conflict (public_id) do update
+  set scope_id           = excluded.scope_id,
+      user_id            = excluded.user_id,
+      target_id          = excluded.target_id,
+      host_id            = excluded.host_id,
+      host_set_id        = excluded.host_set_id,
+      auth_token_id      = excluded.auth_token_id,
+      server_id          = excluded.server_id,
+      endpoint           = excluded.endpoint,
+      termination_reason = excluded.termination_reason,
+      create_time        = excluded.create_time,
+      terminate_time     = excluded.terminate_time,
+      connection_count   = excluded.connection_count,
+      bytes_up           = excluded.bytes_up,
+      bytes_down         = excluded.bytes_down;
+`
+
+	insertEgressViolation = `
+insert into session_egress_violation
+  (session_id, connection_id, worker_id, endpoint, address)
+values
+  ($1, $2, $3, $4, $5);
+`
+
+	listEgressViolations = `
+select session_id, connection_id, worker_id, endpoint, address, create_time
+  from session_egress_violation
+ where session_id = $1
+ order by create_time, address;
+`
+
+	// lastSummaryQuery returns the most recently terminated session which was
+	// replicated.
+	lastSummaryQuery = `
+select terminate_time, public_id
+  from session_summary
+ order by terminate_time desc, public_id desc
+ limit 1;
 `
 )
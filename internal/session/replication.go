@@ -0,0 +1,179 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// defaultReplicationBatchSize is how many summaries are read from the
+// primary database and sent to the Replicator at a time.
+const defaultReplicationBatchSize = 500
+
+// Summary is what's kept of a terminated session for reporting: who
+// connected to what, for how long, and how much data was proxied.
+type Summary struct {
+	PublicId          string
+	ScopeId           string
+	UserId            string
+	TargetId          string
+	HostId            string
+	HostSetId         string
+	AuthTokenId       string
+	ServerId          string
+	Endpoint          string
+	TerminationReason string
+	CreateTime        time.Time
+	TerminateTime     time.Time
+	ConnectionCount   int64
+	BytesUp           int64
+	BytesDown         int64
+}
+
+// Replicator copies the summaries of terminated sessions to another region,
+// so reporting can be done there without querying the primary database.
+// Summaries can be sent more than once, so UpsertSummaries must be
+// idempotent.
+type Replicator interface {
+	// UpsertSummaries stores the summaries, replacing any already stored
+	// for the same sessions.
+	UpsertSummaries(ctx context.Context, summaries []*Summary) error
+
+	// LastReplicated returns the terminate time and id of the most recently
+	// terminated session stored, so replication resumes from where it left
+	// off. It returns the zero time if nothing has been stored.
+	LastReplicated(ctx context.Context) (time.Time, string, error)
+}
+
+// TerminatedSummaries returns up to limit summaries of the sessions
+// terminated after the session sessionId terminated at terminatedAfter, in
+// the order they were terminated. Pass the zero time and an empty id to
+// start from the first terminated session.
+func (r *Repository) TerminatedSummaries(ctx context.Context, terminatedAfter time.Time, sessionId string, limit int) ([]*Summary, error) {
+	if limit <= 0 {
+		limit = r.defaultLimit
+	}
+	rows, err := r.reader.Query(ctx, terminatedSummariesQuery, []interface{}{terminatedAfter, sessionId, limit})
+	if err != nil {
+		return nil, fmt.Errorf("terminated summaries: %w", err)
+	}
+	defer rows.Close()
+	var summaries []*Summary
+	for rows.Next() {
+		s := &Summary{}
+		if err := rows.Scan(&s.PublicId, &s.ScopeId, &s.UserId, &s.TargetId, &s.HostId, &s.HostSetId,
+			&s.AuthTokenId, &s.ServerId, &s.Endpoint, &s.TerminationReason, &s.CreateTime, &s.TerminateTime,
+			&s.ConnectionCount, &s.BytesUp, &s.BytesDown); err != nil {
+			return nil, fmt.Errorf("terminated summaries: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("terminated summaries: %w", err)
+	}
+	return summaries, nil
+}
+
+// ReplicateTerminated sends the summaries of the sessions terminated since
+// the last one replicated to rep in batches, and returns how many were sent.
+// Sessions must be replicated before they are purged to be included.
+func (r *Repository) ReplicateTerminated(ctx context.Context, rep Replicator) (int, error) {
+	if rep == nil {
+		return 0, fmt.Errorf("replicate terminated: missing replicator: %w", db.ErrInvalidParameter)
+	}
+	after, afterId, err := rep.LastReplicated(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("replicate terminated: unable to get last replicated session: %w", err)
+	}
+	var sent int
+	for {
+		summaries, err := r.TerminatedSummaries(ctx, after, afterId, defaultReplicationBatchSize)
+		if err != nil {
+			return sent, fmt.Errorf("replicate terminated: %w", err)
+		}
+		if len(summaries) == 0 {
+			return sent, nil
+		}
+		if err := rep.UpsertSummaries(ctx, summaries); err != nil {
+			return sent, fmt.Errorf("replicate terminated: %w", err)
+		}
+		sent += len(summaries)
+		last := summaries[len(summaries)-1]
+		after, afterId = last.TerminateTime, last.PublicId
+		if len(summaries) < defaultReplicationBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// DbReplicator is a Replicator storing summaries in the session_summary
+// table of a database in another region.
+type DbReplicator struct {
+	reader db.Reader
+	writer db.Writer
+}
+
+var _ Replicator = (*DbReplicator)(nil)
+
+// NewDbReplicator creates a DbReplicator reading and writing the replica
+// database with r and w.
+func NewDbReplicator(r db.Reader, w db.Writer) (*DbReplicator, error) {
+	if r == nil {
+		return nil, fmt.Errorf("new db replicator: nil reader: %w", db.ErrInvalidParameter)
+	}
+	if w == nil {
+		return nil, fmt.Errorf("new db replicator: nil writer: %w", db.ErrInvalidParameter)
+	}
+	return &DbReplicator{reader: r, writer: w}, nil
+}
+
+// UpsertSummaries implements Replicator. The summaries are written in one
+// transaction.
+func (d *DbReplicator) UpsertSummaries(ctx context.Context, summaries []*Summary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+	_, err := d.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			for _, s := range summaries {
+				if _, err := w.Exec(ctx, upsertSummaryQuery, []interface{}{
+					s.PublicId, s.ScopeId, s.UserId, s.TargetId, s.HostId, s.HostSetId, s.AuthTokenId,
+					s.ServerId, s.Endpoint, s.TerminationReason, s.CreateTime, s.TerminateTime,
+					s.ConnectionCount, s.BytesUp, s.BytesDown,
+				}); err != nil {
+					return fmt.Errorf("session %s: %w", s.PublicId, err)
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("upsert summaries: %w", err)
+	}
+	return nil
+}
+
+// LastReplicated implements Replicator.
+func (d *DbReplicator) LastReplicated(ctx context.Context) (time.Time, string, error) {
+	rows, err := d.reader.Query(ctx, lastSummaryQuery, nil)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("last replicated: %w", err)
+	}
+	defer rows.Close()
+	var terminateTime time.Time
+	var id string
+	if rows.Next() {
+		if err := rows.Scan(&terminateTime, &id); err != nil {
+			return time.Time{}, "", fmt.Errorf("last replicated: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return time.Time{}, "", fmt.Errorf("last replicated: %w", err)
+	}
+	return terminateTime, id, nil
+}
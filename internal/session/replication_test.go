@@ -0,0 +1,136 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReplicator is a Replicator keeping the summaries it's sent in
+// memory.
+type recordingReplicator struct {
+	summaries map[string]*Summary
+}
+
+func (r *recordingReplicator) UpsertSummaries(_ context.Context, summaries []*Summary) error {
+	for _, s := range summaries {
+		r.summaries[s.PublicId] = s
+	}
+	return nil
+}
+
+func (r *recordingReplicator) LastReplicated(context.Context) (time.Time, string, error) {
+	var last *Summary
+	for _, s := range r.summaries {
+		if last == nil || s.TerminateTime.After(last.TerminateTime) ||
+			(s.TerminateTime.Equal(last.TerminateTime) && s.PublicId > last.PublicId) {
+			last = s
+		}
+	}
+	if last == nil {
+		return time.Time{}, "", nil
+	}
+	return last.TerminateTime, last.PublicId, nil
+}
+
+func TestRepository_ReplicateTerminated(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	terminate := func(t *testing.T) *Session {
+		t.Helper()
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		s, err := repo.TerminateSession(ctx, s.PublicId, s.Version, ClosedByUser)
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("summaries", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		active := TestDefaultSession(t, conn, wrapper, iamRepo)
+		terminated := terminate(t)
+
+		summaries, err := repo.TerminatedSummaries(ctx, time.Time{}, "", 0)
+		require.NoError(err)
+		var found bool
+		for _, s := range summaries {
+			assert.NotEqual(active.PublicId, s.PublicId)
+			if s.PublicId != terminated.PublicId {
+				continue
+			}
+			found = true
+			assert.Equal(terminated.ScopeId, s.ScopeId)
+			assert.Equal(terminated.UserId, s.UserId)
+			assert.Equal(terminated.TargetId, s.TargetId)
+			assert.Equal(ClosedByUser.String(), s.TerminationReason)
+			assert.False(s.TerminateTime.IsZero())
+			assert.Zero(s.ConnectionCount)
+		}
+		assert.True(found)
+	})
+
+	t.Run("resumes", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		rep := &recordingReplicator{summaries: map[string]*Summary{}}
+		first := terminate(t)
+		_, err := repo.ReplicateTerminated(ctx, rep)
+		require.NoError(err)
+		assert.Contains(rep.summaries, first.PublicId)
+
+		second := terminate(t)
+		sent, err := repo.ReplicateTerminated(ctx, rep)
+		require.NoError(err)
+		assert.Equal(1, sent)
+		assert.Contains(rep.summaries, second.PublicId)
+
+		sent, err = repo.ReplicateTerminated(ctx, rep)
+		require.NoError(err)
+		assert.Zero(sent)
+	})
+
+	t.Run("db-replicator", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		replicaConn, _ := db.TestSetup(t, "postgres")
+		replicaRw := db.New(replicaConn)
+		rep, err := NewDbReplicator(replicaRw, replicaRw)
+		require.NoError(err)
+
+		when, id, err := rep.LastReplicated(ctx)
+		require.NoError(err)
+		assert.True(when.IsZero())
+		assert.Empty(id)
+
+		s := terminate(t)
+		_, err = repo.ReplicateTerminated(ctx, rep)
+		require.NoError(err)
+		when, id, err = rep.LastReplicated(ctx)
+		require.NoError(err)
+		assert.Equal(s.PublicId, id)
+		assert.False(when.IsZero())
+
+		// Upserts are idempotent
+		summaries, err := repo.TerminatedSummaries(ctx, time.Time{}, "", 0)
+		require.NoError(err)
+		require.NoError(rep.UpsertSummaries(ctx, summaries))
+		require.NoError(rep.UpsertSummaries(ctx, summaries))
+	})
+
+	t.Run("missing-replicator", func(t *testing.T) {
+		_, err := repo.ReplicateTerminated(ctx, nil)
+		assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+	})
+}
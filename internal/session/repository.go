@@ -25,6 +25,18 @@ type Repository struct {
 	defaultLimit int
 }
 
+// Repo is the subset of Repository used to look up, list and cancel sessions.
+// sessiontest.Repo implements it in memory for tests which don't need a
+// database.
+type Repo interface {
+	LookupSession(ctx context.Context, sessionId string, opt ...Option) (*Session, *ConnectionAuthzSummary, error)
+	ListSessions(ctx context.Context, opt ...Option) ([]*Session, error)
+	CancelSession(ctx context.Context, sessionId string, sessionVersion uint32) (*Session, error)
+	DeleteSession(ctx context.Context, publicId string, opt ...Option) (int, error)
+}
+
+var _ Repo = (*Repository)(nil)
+
 // NewRepository creates a new session Repository. Supports the options: WithLimit
 // which sets a default limit on results returned by repo operations.
 func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repository, error) {
@@ -85,30 +97,37 @@ func (r *Repository) convertToSessions(ctx context.Context, sessionsWithState []
 			}
 			prevSessionId = sv.PublicId
 			workingSession = &Session{
-				PublicId:          sv.PublicId,
-				UserId:            sv.UserId,
-				HostId:            sv.HostId,
-				ServerId:          sv.ServerId,
-				ServerType:        sv.ServerType,
-				TargetId:          sv.TargetId,
-				HostSetId:         sv.HostSetId,
-				AuthTokenId:       sv.AuthTokenId,
-				ScopeId:           sv.ScopeId,
-				Certificate:       sv.Certificate,
-				ExpirationTime:    sv.ExpirationTime,
-				CtTofuToken:       sv.CtTofuToken,
-				TofuToken:         sv.TofuToken, // will always be nil since it's not stored in the database.
-				TerminationReason: sv.TerminationReason,
-				CreateTime:        sv.CreateTime,
-				UpdateTime:        sv.UpdateTime,
-				Version:           sv.Version,
-				Endpoint:          sv.Endpoint,
-				ConnectionLimit:   sv.ConnectionLimit,
-				KeyId:             sv.KeyId}
+				PublicId:                 sv.PublicId,
+				UserId:                   sv.UserId,
+				HostId:                   sv.HostId,
+				ServerId:                 sv.ServerId,
+				ServerType:               sv.ServerType,
+				TargetId:                 sv.TargetId,
+				HostSetId:                sv.HostSetId,
+				AuthTokenId:              sv.AuthTokenId,
+				ScopeId:                  sv.ScopeId,
+				CtCertificate:            sv.CtCertificate,
+				ExpirationTime:           sv.ExpirationTime,
+				CtTofuToken:              sv.CtTofuToken,
+				TofuToken:                sv.TofuToken, // will always be nil since it's not stored in the database.
+				TerminationReason:        sv.TerminationReason,
+				CreateTime:               sv.CreateTime,
+				UpdateTime:               sv.UpdateTime,
+				Version:                  sv.Version,
+				Endpoint:                 sv.Endpoint,
+				ConnectionLimit:          sv.ConnectionLimit,
+				ClientIp:                 sv.ClientIp,
+				UserAgent:                sv.UserAgent,
+				ConnectionBandwidthLimit: sv.ConnectionBandwidthLimit,
+				AllowedPorts:             sv.AllowedPorts,
+				HostAddress:              sv.HostAddress,
+				HostSelectionReason:      sv.HostSelectionReason,
+				KeyId:                    sv.KeyId}
 			if opts.withListingConvert {
 				workingSession.CtTofuToken = nil // CtTofuToken should not returned in lists
 				workingSession.TofuToken = nil   // TofuToken should not returned in lists
 				workingSession.KeyId = ""        // KeyId should not be returned in lists
+				workingSession.CtCertificate = nil
 			} else {
 				if len(workingSession.CtTofuToken) > 0 {
 					databaseWrapper, err := r.kms.GetWrapper(ctx, workingSession.ScopeId, kms.KeyPurposeDatabase, kms.WithKeyId(workingSession.KeyId))
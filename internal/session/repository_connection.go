@@ -42,11 +42,17 @@ func (r *Repository) LookupConnection(ctx context.Context, connectionId string,
 	return &connection, states, nil
 }
 
-// ListConnections will sessions.  Supports the WithLimit and WithOrder options.
+// ListConnections will sessions.  Supports the WithLimit, WithOrder and
+// WithProtocol options.
 func (r *Repository) ListConnections(ctx context.Context, sessionId string, opt ...Option) ([]*Connection, error) {
 	opts := getOpts(opt...)
+	where, args := "session_id = ?", []interface{}{sessionId}
+	if opts.withProtocol != "" {
+		where += " and protocol = ?"
+		args = append(args, opts.withProtocol)
+	}
 	var connections []*Connection
-	err := r.list(ctx, &connections, "session_id = ?", []interface{}{sessionId}, opts) // pass options, so WithLimit and WithOrder are supported
+	err := r.list(ctx, &connections, where, args, opts) // pass options, so WithLimit and WithOrder are supported
 	if err != nil {
 		return nil, fmt.Errorf("list connections: %w", err)
 	}
@@ -127,6 +127,30 @@ func TestRepository_ListConnection(t *testing.T) {
 			assert.True(first.Before(second))
 		}
 	})
+	t.Run("withProtocol", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.NoError(conn.Where("1=1").Delete(AllocConnection()).Error)
+		for i := 0; i < 3; i++ {
+			_ = TestConnection(t, conn,
+				session.PublicId,
+				"127.0.0.1",
+				22,
+				"127.0.0.1",
+				2222,
+			)
+		}
+		c := TestConnection(t, conn, session.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+		c.Protocol = "ssh"
+		c.ProtocolMetadata = `{"ssh_server_version": "SSH-2.0-OpenSSH_8.4"}`
+		_, err := rw.Update(context.Background(), c, []string{"Protocol", "ProtocolMetadata"}, nil)
+		require.NoError(err)
+
+		got, err := repo.ListConnections(context.Background(), session.PublicId, WithProtocol("ssh"))
+		require.NoError(err)
+		require.Len(got, 1)
+		assert.Equal(c.PublicId, got[0].PublicId)
+		assert.JSONEq(`{"ssh_server_version": "SSH-2.0-OpenSSH_8.4"}`, got[0].ProtocolMetadata)
+	})
 }
 
 func TestRepository_DeleteConnection(t *testing.T) {
@@ -4,13 +4,16 @@ import (
 	"context"
 	"crypto/ed25519"
 	"crypto/subtle"
-	"errors"
+	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/metric"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,8 +21,10 @@ import (
 
 // CreateSession inserts into the repository and returns the new Session with
 // its State of "Pending".  The following fields must be empty when creating a
-// session: ServerId, ServerType, and PublicId.  No options are
-// currently supported.
+// session: ServerId, ServerType, and PublicId.  The session's certificate is
+// encrypted with the scope's database key before it's stored; the returned
+// Session includes it in plaintext. Supported options: WithReaderWriter, to
+// create the session in the caller's transaction.
 func (r *Repository) CreateSession(ctx context.Context, sessionWrapper wrapping.Wrapper, newSession *Session, opt ...Option) (*Session, ed25519.PrivateKey, error) {
 	if newSession == nil {
 		return nil, nil, fmt.Errorf("create session: missing session: %w", db.ErrInvalidParameter)
@@ -27,9 +32,12 @@ func (r *Repository) CreateSession(ctx context.Context, sessionWrapper wrapping.
 	if newSession.PublicId != "" {
 		return nil, nil, fmt.Errorf("create session: public id is not empty: %w", db.ErrInvalidParameter)
 	}
-	if len(newSession.Certificate) != 0 {
+	if len(newSession.Certificate) != 0 || len(newSession.CtCertificate) != 0 {
 		return nil, nil, fmt.Errorf("create session: certificate is not empty: %w", db.ErrInvalidParameter)
 	}
+	if newSession.CertificateKeyId != "" {
+		return nil, nil, fmt.Errorf("create session: certificate key id is not empty: %w", db.ErrInvalidParameter)
+	}
 	if newSession.TargetId == "" {
 		return nil, nil, fmt.Errorf("create session: target id is empty: %w", db.ErrInvalidParameter)
 	}
@@ -76,45 +84,86 @@ func (r *Repository) CreateSession(ctx context.Context, sessionWrapper wrapping.
 	newSession.Certificate = certBytes
 	newSession.PublicId = id
 
-	var returnedSession *Session
-	_, err = r.writer.DoTx(
-		ctx,
-		db.StdRetryCnt,
-		db.ExpBackoff{},
-		func(read db.Reader, w db.Writer) error {
-			returnedSession = newSession.Clone().(*Session)
-			if err = w.Create(ctx, returnedSession); err != nil {
-				return err
-			}
-			var foundStates []*State
-			// trigger will create new "Pending" state
-			if foundStates, err = fetchStates(ctx, read, returnedSession.PublicId); err != nil {
-				return err
-			}
-			if len(foundStates) != 1 {
-				return fmt.Errorf("%d states found for new session %s", len(foundStates), returnedSession.PublicId)
-			}
-			if len(foundStates) == 0 {
-				return fmt.Errorf("no states found for new session %s", returnedSession.PublicId)
-			}
-			returnedSession.States = foundStates
-			if returnedSession.States[0].Status != StatusPending {
-				return fmt.Errorf("new session %s state is not valid: %s", returnedSession.PublicId, returnedSession.States[0].Status)
-			}
-			return nil
-		},
-	)
+	databaseWrapper, err := r.kms.GetWrapper(ctx, newSession.ScopeId, kms.KeyPurposeDatabase)
 	if err != nil {
+		return nil, nil, fmt.Errorf("create session: unable to get database wrapper: %w", err)
+	}
+	if err := newSession.encryptCertificate(ctx, databaseWrapper); err != nil {
 		return nil, nil, fmt.Errorf("create session: %w", err)
 	}
+
+	var returnedSession *Session
+	err = r.inTx(ctx, getOpts(opt...), func(read db.Reader, w db.Writer) error {
+		if err := checkSessionReferences(ctx, read, newSession); err != nil {
+			return err
+		}
+		returnedSession = newSession.Clone().(*Session)
+		if err = w.Create(ctx, returnedSession); err != nil {
+			return err
+		}
+		var foundStates []*State
+		// trigger will create new "Pending" state
+		if foundStates, err = fetchStates(ctx, read, returnedSession.PublicId); err != nil {
+			return err
+		}
+		if len(foundStates) != 1 {
+			return fmt.Errorf("%d states found for new session %s", len(foundStates), returnedSession.PublicId)
+		}
+		if len(foundStates) == 0 {
+			return fmt.Errorf("no states found for new session %s", returnedSession.PublicId)
+		}
+		returnedSession.States = foundStates
+		if returnedSession.States[0].Status != StatusPending {
+			return fmt.Errorf("new session %s state is not valid: %s", returnedSession.PublicId, returnedSession.States[0].Status)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.WithResourceId(fmt.Errorf("create session: %w", err), newSession.PublicId, newSession.ScopeId)
+	}
+	metric.SessionsCreated.Inc()
 	return returnedSession, privKey, err
 }
 
+// checkSessionReferences checks that the resources s references are in its
+// scope and related to each other: the host must be a member of the host set,
+// which must be one of the target's host sets, and the auth token must belong
+// to the user.
+func checkSessionReferences(ctx context.Context, r db.Reader, s *Session) error {
+	if err := db.CheckScope(ctx, r, s.ScopeId, s.TargetId, s.HostSetId, s.HostId); err != nil {
+		return err
+	}
+	rows, err := r.Query(ctx, sessionReferencesQuery, []interface{}{s.TargetId, s.HostSetId, s.HostId, s.AuthTokenId, s.UserId})
+	if err != nil {
+		return fmt.Errorf("check session references: %w", err)
+	}
+	defer rows.Close()
+	var hostSetInTarget, hostInHostSet, authTokenOfUser bool
+	for rows.Next() {
+		if err := rows.Scan(&hostSetInTarget, &hostInHostSet, &authTokenOfUser); err != nil {
+			return fmt.Errorf("check session references: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("check session references: %w", err)
+	}
+	switch {
+	case !hostSetInTarget:
+		return fmt.Errorf("check session references: host set %s is not a host set of target %s: %w", s.HostSetId, s.TargetId, db.ErrInvalidParameter)
+	case !hostInHostSet:
+		return fmt.Errorf("check session references: host %s is not a member of host set %s: %w", s.HostId, s.HostSetId, db.ErrInvalidParameter)
+	case !authTokenOfUser:
+		return fmt.Errorf("check session references: auth token %s does not belong to user %s: %w", s.AuthTokenId, s.UserId, db.ErrInvalidParameter)
+	}
+	return nil
+}
+
 // LookupSession will look up a session in the repository and return the session
 // with its states.  Returned States are ordered by start time descending.  If the
-// session is not found, it will return nil, nil, nil. No options are currently
-// supported.
+// session is not found, it will return nil, nil, nil. The session's certificate
+// is only decrypted when the WithDecryptedCertificate option is used.
 func (r *Repository) LookupSession(ctx context.Context, sessionId string, opt ...Option) (*Session, *ConnectionAuthzSummary, error) {
+	opts := getOpts(opt...)
 	if sessionId == "" {
 		return nil, nil, fmt.Errorf("lookup session: missing sessionId id: %w", db.ErrInvalidParameter)
 	}
@@ -140,7 +189,7 @@ func (r *Repository) LookupSession(ctx context.Context, sessionId string, opt ..
 		if errors.Is(err, db.ErrRecordNotFound) {
 			return nil, nil, nil
 		}
-		return nil, nil, fmt.Errorf("lookup session: %w", err)
+		return nil, nil, errors.WithResourceId(fmt.Errorf("lookup session: %w", err), sessionId, "")
 	}
 	if len(session.CtTofuToken) > 0 {
 		databaseWrapper, err := r.kms.GetWrapper(ctx, session.ScopeId, kms.KeyPurposeDatabase, kms.WithKeyId(session.KeyId))
@@ -153,6 +202,18 @@ func (r *Repository) LookupSession(ctx context.Context, sessionId string, opt ..
 	} else {
 		session.CtTofuToken = nil
 	}
+	if opts.withDecryptedCert {
+		var databaseWrapper wrapping.Wrapper
+		if session.CertificateKeyId != "" {
+			databaseWrapper, err = r.kms.GetWrapper(ctx, session.ScopeId, kms.KeyPurposeDatabase, kms.WithKeyId(session.CertificateKeyId))
+			if err != nil {
+				return nil, nil, fmt.Errorf("lookup session: unable to get database wrapper: %w", err)
+			}
+		}
+		if err := session.decryptCertificate(ctx, databaseWrapper); err != nil {
+			return nil, nil, fmt.Errorf("lookup session: %w", err)
+		}
+	}
 
 	authzSummary, err := r.sessionAuthzSummary(ctx, sessionId)
 	if err != nil {
@@ -162,20 +223,22 @@ func (r *Repository) LookupSession(ctx context.Context, sessionId string, opt ..
 	return &session, authzSummary, nil
 }
 
-// ListSessions will sessions.  Supports the WithLimit, WithScopeId and WithSessionIds options.
+// ListSessions will sessions.  Supports the WithLimit, WithScopeId, WithSessionIds,
+// WithStatus and WithClientIp options. Only the current state of each session
+// is returned.
 func (r *Repository) ListSessions(ctx context.Context, opt ...Option) ([]*Session, error) {
 	opts := getOpts(opt...)
 	var where []string
 	var args []interface{}
 
 	inClauseCnt := 0
-	switch {
-	case opts.withScopeId != "":
+	if opts.withScopeId != "" {
 		inClauseCnt += 1
-		where, args = append(where, fmt.Sprintf("scope_id = $%d", inClauseCnt)), append(args, opts.withScopeId)
-	case opts.withUserId != "":
+		where, args = append(where, fmt.Sprintf("s.scope_id = $%d", inClauseCnt)), append(args, opts.withScopeId)
+	}
+	if opts.withUserId != "" {
 		inClauseCnt += 1
-		where, args = append(where, fmt.Sprintf("user_id = $%d", inClauseCnt)), append(args, opts.withUserId)
+		where, args = append(where, fmt.Sprintf("s.user_id = $%d", inClauseCnt)), append(args, opts.withUserId)
 	}
 	if len(opts.withSessionIds) > 0 {
 		idsInClause := make([]string, 0, len(opts.withSessionIds))
@@ -185,6 +248,14 @@ func (r *Repository) ListSessions(ctx context.Context, opt ...Option) ([]*Sessio
 		}
 		where = append(where, fmt.Sprintf("s.public_id in(%s)", strings.Join(idsInClause, ",")))
 	}
+	if opts.withStatus != "" {
+		inClauseCnt += 1
+		where, args = append(where, fmt.Sprintf("ss.state = $%d", inClauseCnt)), append(args, opts.withStatus.String())
+	}
+	if opts.withClientIp != "" {
+		inClauseCnt += 1
+		where, args = append(where, fmt.Sprintf("s.client_ip = $%d", inClauseCnt)), append(args, opts.withClientIp)
+	}
 
 	var limit string
 	switch {
@@ -202,10 +273,9 @@ func (r *Repository) ListSessions(ctx context.Context, opt ...Option) ([]*Sessio
 
 	var whereClause string
 	if len(where) > 0 {
-		whereClause = " and " + strings.Join(where, " and")
+		whereClause = "where " + strings.Join(where, " and ")
 	}
-	q := sessionList
-	query := fmt.Sprintf(q, limit, whereClause, opts.withOrder)
+	query := fmt.Sprintf(sessionList, whereClause, opts.withOrder, limit)
 
 	rows, err := r.reader.Query(ctx, query, args)
 	if err != nil {
@@ -228,11 +298,159 @@ func (r *Repository) ListSessions(ctx context.Context, opt ...Option) ([]*Sessio
 	return sessions, nil
 }
 
-// DeleteSession will delete a session from the repository.
+// CountSessions returns the number of sessions in the scope with id
+// withScopeId by their current status. Statuses without any sessions are not
+// included. Supports the WithUserId option to only count the sessions of a
+// user.
+func (r *Repository) CountSessions(ctx context.Context, withScopeId string, opt ...Option) (map[Status]int, error) {
+	if withScopeId == "" {
+		return nil, fmt.Errorf("count sessions: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	where, args := []string{"s.scope_id = $1"}, []interface{}{withScopeId}
+	if opts.withUserId != "" {
+		where, args = append(where, "s.user_id = $2"), append(args, opts.withUserId)
+	}
+	query := fmt.Sprintf(sessionCountByStatus, strings.Join(where, " and "))
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("count sessions: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[Status]int)
+	for rows.Next() {
+		var state string
+		var count int
+		if err := rows.Scan(&state, &count); err != nil {
+			return nil, fmt.Errorf("count sessions: scan row failed: %w", err)
+		}
+		counts[Status(state)] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("count sessions: %w", err)
+	}
+	return counts, nil
+}
+
+// StateReport is a change in a session which a worker handling the session
+// needs to act upon. Status is the current status of the session and
+// ClosedConnectionIds are the ids of the session's connections which are
+// closed.
+type StateReport struct {
+	SessionId           string
+	Status              Status
+	ClosedConnectionIds []string
+}
+
+// ListSessionChanges returns a StateReport for each of the sessions in
+// sessionIds which is canceling or terminated or which has closed
+// connections. Sessions without changes and sessions which are not found are
+// not included.
+func (r *Repository) ListSessionChanges(ctx context.Context, sessionIds []string) ([]*StateReport, error) {
+	if len(sessionIds) == 0 {
+		return nil, nil
+	}
+	inClause := make([]string, 0, len(sessionIds))
+	args := make([]interface{}, 0, len(sessionIds))
+	for i, id := range sessionIds {
+		inClause, args = append(inClause, fmt.Sprintf("$%d", i+1)), append(args, id)
+	}
+	query := fmt.Sprintf(sessionChanges, strings.Join(inClause, ","))
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("list session changes: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*StateReport
+	var current *StateReport
+	for rows.Next() {
+		var sessionId, state string
+		var connectionId sql.NullString
+		if err := rows.Scan(&sessionId, &state, &connectionId); err != nil {
+			return nil, fmt.Errorf("list session changes: scan row failed: %w", err)
+		}
+		if current == nil || current.SessionId != sessionId {
+			current = &StateReport{
+				SessionId: sessionId,
+				Status:    Status(state),
+			}
+			reports = append(reports, current)
+		}
+		if connectionId.Valid {
+			current.ClosedConnectionIds = append(current.ClosedConnectionIds, connectionId.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list session changes: %w", err)
+	}
+	return reports, nil
+}
+
+// Digest is the compact state of a session assigned to a worker, which the
+// worker compares with the sessions it has cached to find those it needs to
+// look up again or forget.
+type Digest struct {
+	SessionId string
+	Version   uint32
+	Status    Status
+}
+
+// ListWorkerSessionDigests returns a Digest for each of the sessions
+// activated by the worker with serverId which are not terminated, ordered by
+// session id.
+func (r *Repository) ListWorkerSessionDigests(ctx context.Context, serverId string) ([]*Digest, error) {
+	if serverId == "" {
+		return nil, fmt.Errorf("list worker session digests: missing server id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, workerSessionDigests, []interface{}{serverId})
+	if err != nil {
+		return nil, fmt.Errorf("list worker session digests: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var digests []*Digest
+	for rows.Next() {
+		var d Digest
+		var state string
+		if err := rows.Scan(&d.SessionId, &d.Version, &state); err != nil {
+			return nil, fmt.Errorf("list worker session digests: scan row failed: %w", err)
+		}
+		d.Status = Status(state)
+		digests = append(digests, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list worker session digests: %w", err)
+	}
+	return digests, nil
+}
+
+// CurrentStates returns the current state of each of the sessions which
+// exist. It's used to watch for changes in the status of sessions.
+func (r *Repository) CurrentStates(ctx context.Context, sessionIds []string) ([]*State, error) {
+	if len(sessionIds) == 0 {
+		return nil, fmt.Errorf("current states: missing session ids: %w", db.ErrInvalidParameter)
+	}
+	var states []*State
+	if err := r.reader.SearchWhere(ctx, &states, "session_id in (?) and end_time is null", []interface{}{sessionIds}); err != nil {
+		return nil, fmt.Errorf("current states: %w", err)
+	}
+	return states, nil
+}
+
+// DeleteSession will delete a session from the repository. The session's
+// states, connections and connection states are deleted with it. Sessions
+// which still have open connections are not deleted and an error wrapping
+// ErrOpenConnection is returned, unless WithForce(true) is passed, since the
+// workers proxying the connections would no longer be told to close them.
 func (r *Repository) DeleteSession(ctx context.Context, publicId string, opt ...Option) (int, error) {
 	if publicId == "" {
 		return db.NoRowsAffected, fmt.Errorf("delete session: missing public id %w", db.ErrInvalidParameter)
 	}
+	opts := getOpts(opt...)
 	session := AllocSession()
 	session.PublicId = publicId
 	if err := r.reader.LookupByPublicId(ctx, &session); err != nil {
@@ -244,7 +462,16 @@ func (r *Repository) DeleteSession(ctx context.Context, publicId string, opt ...
 		ctx,
 		db.StdRetryCnt,
 		db.ExpBackoff{},
-		func(_ db.Reader, w db.Writer) error {
+		func(reader db.Reader, w db.Writer) error {
+			if !opts.withForce {
+				openConnections, err := countOpenConnections(ctx, reader, publicId)
+				if err != nil {
+					return err
+				}
+				if openConnections > 0 {
+					return fmt.Errorf("%d connections are open: %w", openConnections, ErrOpenConnection)
+				}
+			}
 			deleteSession := session.Clone()
 			var err error
 			rowsDeleted, err = w.Delete(
@@ -264,6 +491,66 @@ func (r *Repository) DeleteSession(ctx context.Context, publicId string, opt ...
 	return rowsDeleted, nil
 }
 
+// countOpenConnections returns the number of connections of the session
+// which have not been closed.
+func countOpenConnections(ctx context.Context, r db.Reader, sessionId string) (int, error) {
+	rows, err := r.Query(ctx, openConnectionCount, []interface{}{sessionId})
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+	}
+	return count, rows.Err()
+}
+
+// purgeBatchSize is the default number of sessions PurgeTerminatedSessions
+// deletes in each transaction.
+const purgeBatchSize = 1000
+
+// PurgeTerminatedSessions deletes sessions which were terminated longer than
+// olderThan ago, along with their states, connections and connection states,
+// and returns the number of sessions deleted. Sessions are deleted in
+// batches of purgeBatchSize, or of the limit set with WithLimit, each in its
+// own transaction so that rows aren't locked for long when there are many
+// sessions to delete.
+func (r *Repository) PurgeTerminatedSessions(ctx context.Context, olderThan time.Duration, opt ...Option) (int, error) {
+	if olderThan < 0 {
+		return db.NoRowsAffected, fmt.Errorf("purge terminated sessions: negative duration: %w", db.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	batchSize := purgeBatchSize
+	if opts.withLimit > 0 {
+		batchSize = opts.withLimit
+	}
+
+	var purged int
+	for {
+		var deleted int
+		_, err := r.writer.DoTx(
+			ctx,
+			db.StdRetryCnt,
+			db.ExpBackoff{},
+			func(_ db.Reader, w db.Writer) error {
+				var err error
+				deleted, err = w.Exec(ctx, purgeTerminatedSessions, []interface{}{olderThan.Seconds(), batchSize})
+				return err
+			},
+		)
+		if err != nil {
+			return purged, fmt.Errorf("purge terminated sessions: %w", err)
+		}
+		purged += deleted
+		if deleted < batchSize {
+			return purged, nil
+		}
+	}
+}
+
 // CancelSession sets a session's state to "canceling" in the repo.  It's called
 // when the user cancels a session and the controller wants to update the
 // session state to "canceling" for the given reason, so the workers can get the
@@ -328,6 +615,7 @@ func (r *Repository) TerminateSession(ctx context.Context, sessionId string, ses
 	if err != nil {
 		return nil, fmt.Errorf("terminate session: %w", err)
 	}
+	metric.SessionsTerminated.WithLabelValues(reason.String()).Inc()
 	return &updatedSession, nil
 }
 
@@ -338,23 +626,36 @@ func (r *Repository) TerminateSession(ctx context.Context, sessionId string, ses
 // This function should called on a periodic basis a Controllers via it's
 // "ticker" pattern.
 func (r *Repository) TerminateCompletedSessions(ctx context.Context) (int, error) {
-	var rowsAffected int
+	var reasons map[string]int
 	_, err := r.writer.DoTx(
 		ctx,
 		db.StdRetryCnt,
 		db.ExpBackoff{},
 		func(reader db.Reader, w db.Writer) error {
-			var err error
-			rowsAffected, err = w.Exec(ctx, termSessionsUpdate, nil)
+			reasons = make(map[string]int)
+			rows, err := reader.Query(ctx, termSessionsUpdate, nil)
 			if err != nil {
 				return err
 			}
-			return nil
+			defer rows.Close()
+			for rows.Next() {
+				var reason string
+				if err := rows.Scan(&reason); err != nil {
+					return err
+				}
+				reasons[reason]++
+			}
+			return rows.Err()
 		},
 	)
 	if err != nil {
 		return db.NoRowsAffected, fmt.Errorf("terminate completed sessions: %w", err)
 	}
+	var rowsAffected int
+	for reason, count := range reasons {
+		metric.SessionsTerminated.WithLabelValues(reason).Add(float64(count))
+		rowsAffected += count
+	}
 	return rowsAffected, nil
 }
 
@@ -435,18 +736,17 @@ func (r *Repository) sessionAuthzSummary(ctx context.Context, sessionId string)
 	return info, nil
 }
 
-// ConnectConnection updates a connection in the repo with a state of "connected".
-func (r *Repository) ConnectConnection(ctx context.Context, c ConnectWith) (*Connection, []*ConnectionState, error) {
+// ConnectConnection updates a connection in the repo with a state of
+// "connected". Supported options: WithReaderWriter, to update the connection
+// in the caller's transaction.
+func (r *Repository) ConnectConnection(ctx context.Context, c ConnectWith, opt ...Option) (*Connection, []*ConnectionState, error) {
 	// ConnectWith.validate will check all the fields...
 	if err := c.validate(); err != nil {
 		return nil, nil, fmt.Errorf("connect session: %w", err)
 	}
 	var connection Connection
 	var connectionStates []*ConnectionState
-	_, err := r.writer.DoTx(
-		ctx,
-		db.StdRetryCnt,
-		db.ExpBackoff{},
+	err := r.inTx(ctx, getOpts(opt...),
 		func(reader db.Reader, w db.Writer) error {
 			connection = AllocConnection()
 			connection.PublicId = c.ConnectionId
@@ -460,10 +760,27 @@ func (r *Repository) ConnectConnection(ctx context.Context, c ConnectWith) (*Con
 				"EndpointTcpAddress",
 				"EndpointTcpPort",
 			}
-			rowsUpdated, err := w.Update(ctx, &connection, fieldMask, nil)
+			if c.Protocol != "" {
+				connection.Protocol = c.Protocol
+				fieldMask = append(fieldMask, "Protocol")
+			}
+			if c.ProtocolMetadata != "" {
+				connection.ProtocolMetadata = c.ProtocolMetadata
+				fieldMask = append(fieldMask, "ProtocolMetadata")
+			}
+			// Closing always wins, so closed connections aren't connected.
+			updateOpts := []db.Option{db.WithWhere("closed_reason is null")}
+			if c.Version != 0 {
+				version := c.Version
+				updateOpts = append(updateOpts, db.WithVersion(&version))
+			}
+			rowsUpdated, err := w.Update(ctx, &connection, fieldMask, nil, updateOpts...)
 			if err != nil {
 				return err
 			}
+			if rowsUpdated == 0 {
+				return connectionConflict(ctx, reader, c.ConnectionId, c.Version)
+			}
 			if err == nil && rowsUpdated > 1 {
 				// return err, which will result in a rollback of the update
 				return errors.New("error more than 1 connection would have been updated ")
@@ -488,6 +805,37 @@ func (r *Repository) ConnectConnection(ctx context.Context, c ConnectWith) (*Con
 	return &connection, connectionStates, nil
 }
 
+// inTx runs fn in the caller's transaction when opts has the reader and
+// writer given with WithReaderWriter, and otherwise in a new transaction.
+func (r *Repository) inTx(ctx context.Context, opts options, fn db.TxHandler) error {
+	switch {
+	case opts.withReader == nil && opts.withWriter == nil:
+		_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, fn)
+		return err
+	case opts.withReader == nil:
+		return fmt.Errorf("missing reader: %w", db.ErrInvalidParameter)
+	case opts.withWriter == nil:
+		return fmt.Errorf("missing writer: %w", db.ErrInvalidParameter)
+	}
+	return fn(opts.withReader, opts.withWriter)
+}
+
+// connectionConflict returns why an update of the connection with id
+// connectionId, expected to be at version, changed no rows.
+func connectionConflict(ctx context.Context, r db.Reader, connectionId string, version uint32) error {
+	current := AllocConnection()
+	current.PublicId = connectionId
+	if err := r.LookupById(ctx, &current); err != nil {
+		return fmt.Errorf("unable to lookup connection %s: %w", connectionId, err)
+	}
+	return &ConnectionConflictError{
+		ConnectionId:   connectionId,
+		Version:        version,
+		CurrentVersion: current.Version,
+		Closed:         current.ClosedReason != "",
+	}
+}
+
 // CloseConnectionRep is just a wrapper for the response from CloseConnections.
 // It wraps the connection and its states for each connection closed.
 type CloseConnectionResp struct {
@@ -497,53 +845,93 @@ type CloseConnectionResp struct {
 
 // CloseConnections set's a connection's state to "closed" in the repo.  It's
 // called by a worker after it's closed a connection between the client and the
-// endpoint
+// endpoint, with every connection it has closed since it last reported.
+//
+// Duplicate reports for a connection are merged, keeping the largest byte and
+// datagram counts and the first closed reason, and all the connections are
+// updated with a single statement. Closing always wins over other updates of
+// a connection, so closes aren't version checked. Closing a connection which
+// is already closed, such as one an operator closed before its worker
+// reported it, is not an error; its closed reason is left unchanged, any
+// larger counts are kept, and it's returned with its states like the others.
+//
+// If any of closeWith are invalid or are for connections which don't exist,
+// no connections are closed and the returned error wraps an *errors.Multi
+// with an ItemError for each of them, whose Index is its index in closeWith.
 func (r *Repository) CloseConnections(ctx context.Context, closeWith []CloseWith, opt ...Option) ([]CloseConnectionResp, error) {
 	if len(closeWith) == 0 {
 		return nil, fmt.Errorf("close connections: missing connections to close: %w", db.ErrInvalidParameter)
 	}
-	for _, cw := range closeWith {
+	var invalid errors.Multi
+	// first is the index of the first report of each connection in closeWith.
+	first := make(map[string]int, len(closeWith))
+	for i, cw := range closeWith {
 		if err := cw.validate(); err != nil {
-			return nil, fmt.Errorf("close connections: %s was invalid: %w", cw.ConnectionId, err)
+			invalid.Append(i, cw.ConnectionId, err)
+			continue
+		}
+		if _, ok := first[cw.ConnectionId]; !ok {
+			first[cw.ConnectionId] = i
 		}
 	}
+	if err := invalid.ErrorOrNil(); err != nil {
+		return nil, fmt.Errorf("close connections: %w", err)
+	}
+	merged := mergeCloseWith(closeWith)
+
+	ids := make([]string, 0, len(merged))
+	values := make([]string, 0, len(merged))
+	args := make([]interface{}, 0, len(merged)*6)
+	for _, cw := range merged {
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d::bigint, $%d::bigint, $%d::bigint, $%d::bigint, $%d)", n+1, n+2, n+3, n+4, n+5, n+6))
+		args = append(args, cw.ConnectionId, cw.BytesUp, cw.BytesDown, cw.DatagramsUp, cw.DatagramsDown, cw.ClosedReason.String())
+		ids = append(ids, cw.ConnectionId)
+	}
+
 	var resp []CloseConnectionResp
 	_, err := r.writer.DoTx(
 		ctx,
 		db.StdRetryCnt,
 		db.ExpBackoff{},
 		func(reader db.Reader, w db.Writer) error {
-			for _, cw := range closeWith {
-				updateConnection := AllocConnection()
-				updateConnection.PublicId = cw.ConnectionId
-				updateConnection.BytesUp = cw.BytesUp
-				updateConnection.BytesDown = cw.BytesDown
-				updateConnection.ClosedReason = cw.ClosedReason.String()
-				// updating the ClosedReason will trigger an insert into the
-				// session_connection_state with a state of closed.
-				rowsUpdated, err := w.Update(
-					ctx,
-					&updateConnection,
-					[]string{"BytesUp", "BytesDown", "ClosedReason"},
-					nil,
-				)
-				if err != nil {
-					return fmt.Errorf("unable to update connection %s: %w", cw.ConnectionId, err)
-				}
-				if rowsUpdated != 1 {
-					return fmt.Errorf("%d would have been updated for connection %s", rowsUpdated, cw.ConnectionId)
-				}
-				states, err := fetchConnectionStates(ctx, reader, cw.ConnectionId, db.WithOrder("start_time desc"))
-				if err != nil {
-					return err
+			resp = nil
+			// updating the closed_reason will trigger an insert into the
+			// session_connection_state with a state of closed.
+			if _, err := w.Exec(ctx, fmt.Sprintf(closeConnections, strings.Join(values, ", ")), args); err != nil {
+				return fmt.Errorf("unable to update connections: %w", err)
+			}
+
+			var connections []*Connection
+			if err := reader.SearchWhere(ctx, &connections, "public_id in (?)", []interface{}{ids}); err != nil {
+				return fmt.Errorf("unable to read connections: %w", err)
+			}
+			byId := make(map[string]*Connection, len(connections))
+			for _, c := range connections {
+				byId[c.PublicId] = c
+			}
+			var states []*ConnectionState
+			if err := reader.SearchWhere(ctx, &states, "connection_id in (?)", []interface{}{ids}, db.WithOrder("start_time desc")); err != nil {
+				return fmt.Errorf("unable to read connection states: %w", err)
+			}
+			statesById := make(map[string][]*ConnectionState, len(connections))
+			for _, st := range states {
+				statesById[st.ConnectionId] = append(statesById[st.ConnectionId], st)
+			}
+
+			var notFound errors.Multi
+			for _, id := range ids {
+				c, ok := byId[id]
+				if !ok {
+					notFound.Append(first[id], id, fmt.Errorf("connection not found: %w", db.ErrRecordNotFound))
+					continue
 				}
 				resp = append(resp, CloseConnectionResp{
-					Connection:       &updateConnection,
-					ConnectionStates: states,
+					Connection:       c,
+					ConnectionStates: statesById[id],
 				})
-
 			}
-			return nil
+			return notFound.ErrorOrNil()
 		},
 	)
 	if err != nil {
@@ -552,6 +940,128 @@ func (r *Repository) CloseConnections(ctx context.Context, closeWith []CloseWith
 	return resp, nil
 }
 
+// mergeCloseWith returns closeWith with a single entry for each connection,
+// in the order the connections were first reported. The counts reported for
+// a connection only grow, so the largest are kept.
+func mergeCloseWith(closeWith []CloseWith) []CloseWith {
+	merged := make([]CloseWith, 0, len(closeWith))
+	idx := make(map[string]int, len(closeWith))
+	for _, cw := range closeWith {
+		i, ok := idx[cw.ConnectionId]
+		if !ok {
+			idx[cw.ConnectionId] = len(merged)
+			merged = append(merged, cw)
+			continue
+		}
+		m := &merged[i]
+		if cw.BytesUp > m.BytesUp {
+			m.BytesUp = cw.BytesUp
+		}
+		if cw.BytesDown > m.BytesDown {
+			m.BytesDown = cw.BytesDown
+		}
+		if cw.DatagramsUp > m.DatagramsUp {
+			m.DatagramsUp = cw.DatagramsUp
+		}
+		if cw.DatagramsDown > m.DatagramsDown {
+			m.DatagramsDown = cw.DatagramsDown
+		}
+	}
+	return merged
+}
+
+// RecordConnectionActivity records when traffic was last proxied for open
+// connections, keyed by connection id, as reported by the worker proxying
+// them. Activity is only recorded to the nearest minute.
+func (r *Repository) RecordConnectionActivity(ctx context.Context, lastActivity map[string]time.Time) error {
+	if len(lastActivity) == 0 {
+		return nil
+	}
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			for connectionId, t := range lastActivity {
+				if connectionId == "" || t.IsZero() {
+					continue
+				}
+				if _, err := w.Exec(ctx, updateConnectionActivity, []interface{}{connectionId, t}); err != nil {
+					return fmt.Errorf("unable to update connection %s: %w", connectionId, err)
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("record connection activity: %w", err)
+	}
+	return nil
+}
+
+// CloseIdleConnections closes the connections which have been idle for
+// longer than their target's connection idle timeout, with a closed reason
+// of "idle-timeout", and returns the number closed. The workers proxying them
+// are told the connections are closed when they next report their status.
+// It's run periodically by a controller job.
+func (r *Repository) CloseIdleConnections(ctx context.Context) (int, error) {
+	var closed int
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			closed = 0
+			rows, err := reader.Query(ctx, closeIdleConnections, nil)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				closed++
+			}
+			return rows.Err()
+		},
+	)
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("close idle connections: %w", err)
+	}
+	return closed, nil
+}
+
+// CancelNeverConnectedSessions terminates the sessions which are still
+// pending longer after they were authorized than their target's
+// authorization valid seconds, with a termination reason of
+// "never-connected", and returns the number terminated. It's run
+// periodically by a controller job.
+func (r *Repository) CancelNeverConnectedSessions(ctx context.Context) (int, error) {
+	var canceled int
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			canceled = 0
+			rows, err := reader.Query(ctx, cancelNeverConnectedSessions, nil)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				canceled++
+			}
+			return rows.Err()
+		},
+	)
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("cancel never connected sessions: %w", err)
+	}
+	if canceled > 0 {
+		metric.SessionsTerminated.WithLabelValues(NeverConnected.String()).Add(float64(canceled))
+	}
+	return canceled, nil
+}
+
 // ActivateSession will activate the session and is called by a worker after
 // authenticating the session. The session must be in a "pending" state to be
 // activated. States are ordered by start time descending. Returns an
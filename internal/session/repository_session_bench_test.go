@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/require"
+)
+
+// benchmarkSessionCounts are the numbers of sessions in the repository the
+// benchmarks are run with. Comparing the cost per row between them shows
+// whether listing sessions scales with the number of sessions.
+var benchmarkSessionCounts = []int{100, 1000, 10000}
+
+func BenchmarkRepository_ListSessions(b *testing.B) {
+	conn, _ := db.TestSetup(b, "postgres")
+	wrapper := db.TestWrapper(b)
+	iamRepo := iam.TestRepo(b, conn, wrapper)
+	rw := db.New(conn)
+	kms := kms.TestKms(b, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(b, err)
+	composedOf := TestSessionParams(b, conn, wrapper, iamRepo)
+
+	var created int
+	for _, count := range benchmarkSessionCounts {
+		// Each count adds to the sessions created for the last
+		TestSessionFixtures(b, conn, wrapper, composedOf, count-created)
+		created = count
+
+		benchmarks := []struct {
+			name string
+			opt  []Option
+		}{
+			{name: "all", opt: []Option{WithLimit(-1)}},
+			{name: "default-limit"},
+			{name: "scope", opt: []Option{WithScopeId(composedOf.ScopeId), WithLimit(-1)}},
+			{name: "user-ordered", opt: []Option{WithUserId(composedOf.UserId), WithOrder("create_time desc"), WithLimit(-1)}},
+			{name: "active", opt: []Option{WithStatus(StatusActive), WithLimit(-1)}},
+		}
+		for _, bm := range benchmarks {
+			bm := bm
+			b.Run(fmt.Sprintf("%s/%d", bm.name, count), func(b *testing.B) {
+				ctx := context.Background()
+				var rows int
+				b.ResetTimer()
+				start := time.Now()
+				for i := 0; i < b.N; i++ {
+					got, err := repo.ListSessions(ctx, bm.opt...)
+					if err != nil {
+						b.Fatal(err)
+					}
+					rows += len(got)
+				}
+				if rows > 0 {
+					b.ReportMetric(float64(time.Since(start).Nanoseconds())/float64(rows), "ns/row")
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkRepository_LookupSession(b *testing.B) {
+	conn, _ := db.TestSetup(b, "postgres")
+	wrapper := db.TestWrapper(b)
+	iamRepo := iam.TestRepo(b, conn, wrapper)
+	rw := db.New(conn)
+	kms := kms.TestKms(b, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(b, err)
+	composedOf := TestSessionParams(b, conn, wrapper, iamRepo)
+
+	var created int
+	for _, count := range benchmarkSessionCounts {
+		ids := TestSessionFixtures(b, conn, wrapper, composedOf, count-created)
+		created = count
+
+		b.Run(fmt.Sprintf("%d", count), func(b *testing.B) {
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.LookupSession(ctx, ids[i%len(ids)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRepository_CreateSession(b *testing.B) {
+	conn, _ := db.TestSetup(b, "postgres")
+	wrapper := db.TestWrapper(b)
+	iamRepo := iam.TestRepo(b, conn, wrapper)
+	rw := db.New(conn)
+	kms := kms.TestKms(b, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(b, err)
+	composedOf := TestSessionParams(b, conn, wrapper, iamRepo)
+
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s, err := New(composedOf)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, _, err := repo.CreateSession(ctx, wrapper, s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package session
 
 import (
 	"context"
-	"errors"
 	"testing"
 	"time"
 
@@ -11,6 +10,7 @@ import (
 	authtokenStore "github.com/hashicorp/boundary/internal/authtoken/store"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/host/static"
 	staticStore "github.com/hashicorp/boundary/internal/host/static/store"
 	"github.com/hashicorp/boundary/internal/target"
@@ -22,6 +22,7 @@ import (
 
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -111,8 +112,8 @@ func TestRepository_ListSession(t *testing.T) {
 			require.NoError(err)
 			assert.Equal(tt.wantCnt, len(got))
 			if tt.wantCnt > 0 {
+				require.Len(got[0].States, 1)
 				assert.Equal(StatusActive, got[0].States[0].Status)
-				assert.Equal(StatusPending, got[0].States[1].Status)
 			}
 		})
 	}
@@ -148,6 +149,34 @@ func TestRepository_ListSession(t *testing.T) {
 		assert.Equal(1, len(got))
 		assert.Equal(got[0].UserId, s.UserId)
 	})
+	t.Run("withScopeIdAndUserId", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.NoError(conn.Where("1=1").Delete(AllocSession()).Error)
+		_ = TestSession(t, conn, wrapper, composedOf)
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		got, err := repo.ListSessions(context.Background(), WithScopeId(s.ScopeId), WithUserId(s.UserId))
+		require.NoError(err)
+		require.Equal(1, len(got))
+		assert.Equal(s.PublicId, got[0].PublicId)
+		got, err = repo.ListSessions(context.Background(), WithScopeId(composedOf.ScopeId), WithUserId(s.UserId))
+		require.NoError(err)
+		assert.Empty(got)
+	})
+	t.Run("withClientIp", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.NoError(conn.Where("1=1").Delete(AllocSession()).Error)
+		_ = TestSession(t, conn, wrapper, composedOf)
+		c := composedOf
+		c.ClientIp = "203.0.113.10"
+		c.UserAgent = "boundary-cli/0.1.8"
+		s := TestSession(t, conn, wrapper, c)
+		got, err := repo.ListSessions(context.Background(), WithClientIp("203.0.113.10"))
+		require.NoError(err)
+		require.Equal(1, len(got))
+		assert.Equal(s.PublicId, got[0].PublicId)
+		assert.Equal("203.0.113.10", got[0].ClientIp)
+		assert.Equal("boundary-cli/0.1.8", got[0].UserAgent)
+	})
 	t.Run("WithSessionIds", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
 		require.NoError(conn.Where("1=1").Delete(AllocSession()).Error)
@@ -162,11 +191,50 @@ func TestRepository_ListSession(t *testing.T) {
 		got, err := repo.ListSessions(context.Background(), WithSessionIds(withIds...), WithOrder("create_time asc"))
 		require.NoError(err)
 		assert.Equal(2, len(got))
+		require.Len(got[0].States, 1)
 		assert.Equal(StatusActive, got[0].States[0].Status)
-		assert.Equal(StatusPending, got[0].States[1].Status)
 	})
 }
 
+func TestRepository_CountSessions(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	rw := db.New(conn)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+	composedOf := TestSessionParams(t, conn, wrapper, iamRepo)
+
+	_, err = repo.CountSessions(ctx, "")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+
+	for i := 0; i < 3; i++ {
+		_ = TestSession(t, conn, wrapper, composedOf)
+	}
+	for i := 0; i < 2; i++ {
+		s := TestSession(t, conn, wrapper, composedOf)
+		_ = TestState(t, conn, s.PublicId, StatusActive)
+	}
+	// Sessions in other scopes aren't counted
+	other := TestDefaultSession(t, conn, wrapper, iamRepo)
+
+	got, err := repo.CountSessions(ctx, composedOf.ScopeId)
+	require.NoError(t, err)
+	assert.Equal(t, map[Status]int{StatusPending: 3, StatusActive: 2}, got)
+
+	got, err = repo.CountSessions(ctx, other.ScopeId, WithUserId(other.UserId))
+	require.NoError(t, err)
+	assert.Equal(t, map[Status]int{StatusPending: 1}, got)
+
+	got, err = repo.CountSessions(ctx, composedOf.ScopeId, WithUserId(other.UserId))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
 func TestRepository_CreateSession(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -265,6 +333,42 @@ func TestRepository_CreateSession(t *testing.T) {
 			wantErr:     true,
 			wantIsError: db.ErrInvalidParameter,
 		},
+		{
+			name: "host-in-other-scope",
+			args: args{
+				composedOf: func() ComposedOf {
+					c := TestSessionParams(t, conn, wrapper, iamRepo)
+					c.HostId = TestSessionParams(t, conn, wrapper, iamRepo).HostId
+					return c
+				}(),
+			},
+			wantErr:     true,
+			wantIsError: db.ErrInvalidParameter,
+		},
+		{
+			name: "host-set-not-in-target",
+			args: args{
+				composedOf: func() ComposedOf {
+					c := TestSessionParams(t, conn, wrapper, iamRepo)
+					c.TargetId = target.TestTcpTarget(t, conn, c.ScopeId, "other target").PublicId
+					return c
+				}(),
+			},
+			wantErr:     true,
+			wantIsError: db.ErrInvalidParameter,
+		},
+		{
+			name: "auth-token-of-other-user",
+			args: args{
+				composedOf: func() ComposedOf {
+					c := TestSessionParams(t, conn, wrapper, iamRepo)
+					c.AuthTokenId = TestSessionParams(t, conn, wrapper, iamRepo).AuthTokenId
+					return c
+				}(),
+			},
+			wantErr:     true,
+			wantIsError: db.ErrInvalidParameter,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -296,8 +400,19 @@ func TestRepository_CreateSession(t *testing.T) {
 			assert.NotNil(ses.CreateTime)
 			assert.NotNil(ses.States[0].StartTime)
 			assert.Equal(ses.States[0].Status, StatusPending)
+			assert.NotEmpty(ses.Certificate)
+			assert.NotEmpty(ses.CertificateKeyId)
+			assert.NotEqual(ses.Certificate, ses.CtCertificate)
+
+			// The certificate is only decrypted when asked for
 			foundSession, _, err := repo.LookupSession(context.Background(), ses.PublicId)
-			assert.NoError(err)
+			require.NoError(err)
+			assert.Nil(foundSession.Certificate)
+			assert.Equal(ses.CtCertificate, foundSession.CtCertificate)
+
+			foundSession, _, err = repo.LookupSession(context.Background(), ses.PublicId, WithDecryptedCertificate(true))
+			require.NoError(err)
+			assert.Equal(ses.Certificate, foundSession.Certificate)
 
 			// Account for slight offsets in nanos
 			assert.True(foundSession.ExpirationTime.Timestamp.AsTime().Sub(ses.ExpirationTime.Timestamp.AsTime()) < time.Second)
@@ -312,6 +427,61 @@ func TestRepository_CreateSession(t *testing.T) {
 			assert.Equal(foundSession.States[0].Status, StatusPending)
 		})
 	}
+	t.Run("with-reader-writer", func(t *testing.T) {
+		newSession := func() *Session {
+			c := TestSessionParams(t, conn, wrapper, iamRepo)
+			return &Session{
+				UserId:          c.UserId,
+				HostId:          c.HostId,
+				TargetId:        c.TargetId,
+				HostSetId:       c.HostSetId,
+				AuthTokenId:     c.AuthTokenId,
+				ScopeId:         c.ScopeId,
+				Endpoint:        "tcp://127.0.0.1:22",
+				ExpirationTime:  c.ExpirationTime,
+				ConnectionLimit: c.ConnectionLimit,
+			}
+		}
+		t.Run("commit", func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			var ses *Session
+			_, err := rw.DoTx(context.Background(), db.StdRetryCnt, db.ExpBackoff{}, func(r db.Reader, w db.Writer) error {
+				var err error
+				ses, _, err = repo.CreateSession(context.Background(), wrapper, newSession(), WithReaderWriter(r, w))
+				return err
+			})
+			require.NoError(err)
+			found, _, err := repo.LookupSession(context.Background(), ses.PublicId)
+			require.NoError(err)
+			require.NotNil(found)
+			assert.Equal(ses.PublicId, found.PublicId)
+		})
+		t.Run("rollback", func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			var ses *Session
+			rollback := errors.New("rollback")
+			_, err := rw.DoTx(context.Background(), db.StdRetryCnt, db.ExpBackoff{}, func(r db.Reader, w db.Writer) error {
+				var err error
+				if ses, _, err = repo.CreateSession(context.Background(), wrapper, newSession(), WithReaderWriter(r, w)); err != nil {
+					return err
+				}
+				return rollback
+			})
+			require.Error(err)
+			assert.True(errors.Is(err, rollback))
+			require.NotNil(ses)
+			found, _, err := repo.LookupSession(context.Background(), ses.PublicId)
+			require.NoError(err)
+			assert.Nil(found)
+		})
+		t.Run("missing-writer", func(t *testing.T) {
+			assert := assert.New(t)
+			ses, _, err := repo.CreateSession(context.Background(), wrapper, newSession(), WithReaderWriter(rw, nil))
+			assert.Error(err)
+			assert.Nil(ses)
+			assert.True(errors.Is(err, db.ErrInvalidParameter))
+		})
+	})
 }
 
 func TestRepository_updateState(t *testing.T) {
@@ -607,6 +777,59 @@ func TestRepository_ConnectConnection(t *testing.T) {
 			wantErr:     true,
 			wantIsError: db.ErrInvalidParameter,
 		},
+		{
+			name: "valid-protocol",
+			connectWith: func() ConnectWith {
+				cw := setupFn()
+				cw.Protocol = "ssh"
+				cw.ProtocolMetadata = `{"ssh_server_version": "SSH-2.0-OpenSSH_8.4"}`
+				return cw
+			}(),
+		},
+		{
+			name: "invalid-ProtocolMetadata",
+			connectWith: func() ConnectWith {
+				cw := setupFn()
+				cw.Protocol = "ssh"
+				cw.ProtocolMetadata = "SSH-2.0-OpenSSH_8.4"
+				return cw
+			}(),
+			wantErr:     true,
+			wantIsError: db.ErrInvalidParameter,
+		},
+		{
+			name: "valid-version",
+			connectWith: func() ConnectWith {
+				cw := setupFn()
+				c, _, err := repo.LookupConnection(context.Background(), cw.ConnectionId)
+				require.NoError(t, err)
+				cw.Version = c.Version
+				return cw
+			}(),
+		},
+		{
+			name: "stale-version",
+			connectWith: func() ConnectWith {
+				cw := setupFn()
+				c, _, err := repo.LookupConnection(context.Background(), cw.ConnectionId)
+				require.NoError(t, err)
+				cw.Version = c.Version + 1
+				return cw
+			}(),
+			wantErr:     true,
+			wantIsError: ErrConnectionConflict,
+		},
+		{
+			name: "closed",
+			connectWith: func() ConnectWith {
+				cw := setupFn()
+				_, err := repo.CloseConnections(context.Background(), []CloseWith{{ConnectionId: cw.ConnectionId, ClosedReason: ConnectionCanceled}})
+				require.NoError(t, err)
+				return cw
+			}(),
+			wantErr:     true,
+			wantIsError: ErrConnectionConflict,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -624,6 +847,13 @@ func TestRepository_ConnectConnection(t *testing.T) {
 			require.NotNil(c)
 			require.NotNil(cs)
 			assert.Equal(StatusConnected, cs[0].Status)
+
+			found, _, err := repo.LookupConnection(context.Background(), tt.connectWith.ConnectionId)
+			require.NoError(err)
+			assert.Equal(tt.connectWith.Protocol, found.Protocol)
+			if tt.connectWith.ProtocolMetadata != "" {
+				assert.JSONEq(tt.connectWith.ProtocolMetadata, found.ProtocolMetadata)
+			}
 		})
 	}
 }
@@ -986,14 +1216,77 @@ func TestRepository_CloseConnections(t *testing.T) {
 		name        string
 		closeWith   []CloseWith
 		reason      TerminationReason
+		wantCnt     int
 		wantErr     bool
 		wantIsError error
+		// wantIndexes are the indexes of the failed closeWith.
+		wantIndexes []int
 	}{
 		{
 			name:      "valid",
 			closeWith: setupFn(2),
 			reason:    ClosedByUser,
 		},
+		{
+			name: "duplicates",
+			closeWith: func() []CloseWith {
+				cw := setupFn(2)
+				dup := cw[0]
+				dup.BytesUp = 10
+				dup.ClosedReason = ConnectionCanceled
+				return append(cw, dup)
+			}(),
+			reason:  ClosedByUser,
+			wantCnt: 2,
+		},
+		{
+			name: "already-closed",
+			closeWith: func() []CloseWith {
+				cw := setupFn(2)
+				_, err := repo.CloseConnections(context.Background(), cw[:1])
+				require.NoError(t, err)
+				return cw
+			}(),
+			reason: ClosedByUser,
+		},
+		{
+			name: "closed-by-operator",
+			closeWith: func() []CloseWith {
+				cw := setupFn(2)
+				// An operator closes the connection before its worker
+				// reports its counts
+				_, err := repo.CloseConnections(context.Background(), []CloseWith{{ConnectionId: cw[0].ConnectionId, ClosedReason: ConnectionClosedByUser}})
+				require.NoError(t, err)
+				cw[0].ClosedReason = ConnectionCanceled
+				return cw
+			}(),
+			reason: ClosedByUser,
+		},
+		{
+			name: "not-found",
+			closeWith: func() []CloseWith {
+				cw := setupFn(1)
+				cw[0].ConnectionId = "sc_doesnotexist"
+				return cw
+			}(),
+			reason:      ClosedByUser,
+			wantErr:     true,
+			wantIsError: db.ErrRecordNotFound,
+			wantIndexes: []int{0},
+		},
+		{
+			name: "several-not-found",
+			closeWith: func() []CloseWith {
+				cw := setupFn(3)
+				cw[0].ConnectionId = "sc_doesnotexist"
+				cw[2].ConnectionId = "sc_doesnotexist2"
+				return append(cw, cw[0])
+			}(),
+			reason:      ClosedByUser,
+			wantErr:     true,
+			wantIsError: db.ErrRecordNotFound,
+			wantIndexes: []int{0, 2},
+		},
 		{
 			name:        "empty-closed-with",
 			closeWith:   []CloseWith{},
@@ -1012,6 +1305,7 @@ func TestRepository_CloseConnections(t *testing.T) {
 			reason:      ClosedByUser,
 			wantErr:     true,
 			wantIsError: db.ErrInvalidParameter,
+			wantIndexes: []int{1},
 		},
 	}
 	for _, tt := range tests {
@@ -1023,19 +1317,174 @@ func TestRepository_CloseConnections(t *testing.T) {
 				if tt.wantIsError != nil {
 					assert.Truef(errors.Is(err, tt.wantIsError), "unexpected error %s", err.Error())
 				}
+				if tt.wantIndexes != nil {
+					var multi *errors.Multi
+					require.True(errors.As(err, &multi))
+					var indexes []int
+					for _, e := range multi.Errors {
+						indexes = append(indexes, e.Index)
+					}
+					assert.Equal(tt.wantIndexes, indexes)
+				}
 				return
 			}
 			require.NoError(err)
-			assert.Equal(len(tt.closeWith), len(resp))
+			wantCnt := tt.wantCnt
+			if wantCnt == 0 {
+				wantCnt = len(tt.closeWith)
+			}
+			assert.Equal(wantCnt, len(resp))
 			for _, r := range resp {
 				require.NotNil(r.Connection)
 				require.NotNil(r.ConnectionStates)
 				assert.Equal(StatusClosed, r.ConnectionStates[0].Status)
+				assert.Equal(ConnectionClosedByUser.String(), r.Connection.ClosedReason)
+				assert.Equal(uint64(2), r.Connection.BytesDown)
 			}
 		})
 	}
 }
 
+func Test_mergeCloseWith(t *testing.T) {
+	t.Parallel()
+	closeWith := []CloseWith{
+		{ConnectionId: "sc_1", BytesUp: 1, BytesDown: 20, ClosedReason: ConnectionClosedByUser},
+		{ConnectionId: "sc_2", BytesUp: 3, ClosedReason: ConnectionCanceled},
+		{ConnectionId: "sc_1", BytesUp: 10, BytesDown: 2, DatagramsUp: 4, ClosedReason: ConnectionCanceled},
+	}
+	want := []CloseWith{
+		{ConnectionId: "sc_1", BytesUp: 10, BytesDown: 20, DatagramsUp: 4, ClosedReason: ConnectionClosedByUser},
+		{ConnectionId: "sc_2", BytesUp: 3, ClosedReason: ConnectionCanceled},
+	}
+	assert.Equal(t, want, mergeCloseWith(closeWith))
+}
+
+func TestRepository_RecordConnectionActivity(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+	ctx := context.Background()
+
+	s := TestDefaultSession(t, conn, wrapper, iamRepo)
+	c := TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+	lastActivity := func() time.Time {
+		t.Helper()
+		found, _, err := repo.LookupConnection(ctx, c.PublicId)
+		require.NoError(err)
+		require.NotNil(found.LastActivityTime)
+		return found.LastActivityTime.GetTimestamp().AsTime()
+	}
+
+	first := time.Now().Truncate(time.Second)
+	require.NoError(repo.RecordConnectionActivity(ctx, map[string]time.Time{c.PublicId: first}))
+	assert.True(first.Equal(lastActivity()))
+
+	// Activity within a minute of what's recorded isn't recorded
+	require.NoError(repo.RecordConnectionActivity(ctx, map[string]time.Time{c.PublicId: first.Add(10 * time.Second)}))
+	assert.True(first.Equal(lastActivity()))
+
+	second := first.Add(2 * time.Minute)
+	require.NoError(repo.RecordConnectionActivity(ctx, map[string]time.Time{c.PublicId: second}))
+	assert.True(second.Equal(lastActivity()))
+
+	// Activity for closed connections isn't recorded
+	_, err = repo.CloseConnections(ctx, []CloseWith{{ConnectionId: c.PublicId, ClosedReason: ConnectionClosedByUser}})
+	require.NoError(err)
+	require.NoError(repo.RecordConnectionActivity(ctx, map[string]time.Time{c.PublicId: second.Add(time.Hour)}))
+	assert.True(second.Equal(lastActivity()))
+}
+
+func TestRepository_CloseIdleConnections(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+	ctx := context.Background()
+
+	s := TestDefaultSession(t, conn, wrapper, iamRepo)
+	idle := TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+	active := TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2223)
+
+	// Without an idle timeout nothing is closed
+	closed, err := repo.CloseIdleConnections(ctx)
+	require.NoError(err)
+	assert.Equal(0, closed)
+
+	_, err = rw.Exec(ctx, "update target_tcp set connection_idle_timeout_seconds = 1 where public_id = ?", []interface{}{s.TargetId})
+	require.NoError(err)
+	require.NoError(repo.RecordConnectionActivity(ctx, map[string]time.Time{active.PublicId: time.Now().Add(time.Hour)}))
+	time.Sleep(2 * time.Second)
+
+	closed, err = repo.CloseIdleConnections(ctx)
+	require.NoError(err)
+	assert.Equal(1, closed)
+
+	found, states, err := repo.LookupConnection(ctx, idle.PublicId)
+	require.NoError(err)
+	assert.Equal(ConnectionIdleTimeout.String(), found.ClosedReason)
+	assert.Equal(StatusClosed, states[0].Status)
+
+	found, states, err = repo.LookupConnection(ctx, active.PublicId)
+	require.NoError(err)
+	assert.Empty(found.ClosedReason)
+	assert.NotEqual(StatusClosed, states[0].Status)
+}
+
+func TestRepository_CancelNeverConnectedSessions(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+	ctx := context.Background()
+
+	pending := TestDefaultSession(t, conn, wrapper, iamRepo)
+	other := TestDefaultSession(t, conn, wrapper, iamRepo)
+
+	// Without an authorization window nothing is canceled
+	canceled, err := repo.CancelNeverConnectedSessions(ctx)
+	require.NoError(err)
+	assert.Equal(0, canceled)
+
+	_, err = rw.Exec(ctx, "update target_tcp set authorization_valid_seconds = 1 where public_id = ?", []interface{}{pending.TargetId})
+	require.NoError(err)
+	time.Sleep(2 * time.Second)
+
+	canceled, err = repo.CancelNeverConnectedSessions(ctx)
+	require.NoError(err)
+	assert.Equal(1, canceled)
+
+	found, _, err := repo.LookupSession(ctx, pending.PublicId)
+	require.NoError(err)
+	assert.Equal(NeverConnected.String(), found.TerminationReason)
+	assert.Equal(StatusTerminated, found.States[0].Status)
+
+	found, _, err = repo.LookupSession(ctx, other.PublicId)
+	require.NoError(err)
+	assert.Empty(found.TerminationReason)
+	assert.Equal(StatusPending, found.States[0].Status)
+
+	// Sessions are only canceled once
+	canceled, err = repo.CancelNeverConnectedSessions(ctx)
+	require.NoError(err)
+	assert.Equal(0, canceled)
+}
+
 func TestRepository_CancelSession(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -1494,8 +1943,10 @@ func TestRepository_DeleteSession(t *testing.T) {
 	tests := []struct {
 		name            string
 		args            args
+		connections     int
 		wantRowsDeleted int
 		wantErr         bool
+		wantIsErr       error
 		wantErrMsg      string
 	}{
 		{
@@ -1506,6 +1957,27 @@ func TestRepository_DeleteSession(t *testing.T) {
 			wantRowsDeleted: 1,
 			wantErr:         false,
 		},
+		{
+			name: "open-connections",
+			args: args{
+				session: TestDefaultSession(t, conn, wrapper, iamRepo),
+			},
+			connections:     2,
+			wantRowsDeleted: 0,
+			wantErr:         true,
+			wantIsErr:       ErrOpenConnection,
+			wantErrMsg:      "2 connections are open: session has open connections",
+		},
+		{
+			name: "force-open-connections",
+			args: args{
+				session: TestDefaultSession(t, conn, wrapper, iamRepo),
+				opt:     []Option{WithForce(true)},
+			},
+			connections:     2,
+			wantRowsDeleted: 1,
+			wantErr:         false,
+		},
 		{
 			name: "no-public-id",
 			args: args{
@@ -1536,12 +2008,20 @@ func TestRepository_DeleteSession(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert := assert.New(t)
+			assert, require := assert.New(t), require.New(t)
+			var connectionIds []string
+			for i := 0; i < tt.connections; i++ {
+				c := TestConnection(t, conn, tt.args.session.PublicId, "127.0.0.1", 22, "127.0.0.1", uint32(2222+i))
+				connectionIds = append(connectionIds, c.PublicId)
+			}
 			deletedRows, err := repo.DeleteSession(context.Background(), tt.args.session.PublicId, tt.args.opt...)
 			if tt.wantErr {
 				assert.Error(err)
 				assert.Equal(0, deletedRows)
 				assert.Contains(err.Error(), tt.wantErrMsg)
+				if tt.wantIsErr != nil {
+					assert.True(errors.Is(err, tt.wantIsErr))
+				}
 				err = db.TestVerifyOplog(t, rw, tt.args.session.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_DELETE), db.WithCreateNotBefore(10*time.Second))
 				assert.Error(err)
 				assert.True(errors.Is(db.ErrRecordNotFound, err))
@@ -1552,9 +2032,181 @@ func TestRepository_DeleteSession(t *testing.T) {
 			foundSession, _, err := repo.LookupSession(context.Background(), tt.args.session.PublicId)
 			assert.NoError(err)
 			assert.Nil(foundSession)
+			for _, id := range connectionIds {
+				// The connections and their states are deleted by cascade
+				found, states, err := repo.LookupConnection(context.Background(), id)
+				require.NoError(err)
+				assert.Nil(found)
+				assert.Empty(states)
+			}
 
 			err = db.TestVerifyOplog(t, rw, tt.args.session.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_DELETE), db.WithCreateNotBefore(10*time.Second))
 			assert.Error(err)
 		})
 	}
 }
+
+func TestRepository_PurgeTerminatedSessions(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+	ctx := context.Background()
+
+	// Every fourth of the sessions is terminated
+	ids := TestSessionFixtures(t, conn, wrapper, TestSessionParams(t, conn, wrapper, iamRepo), 9)
+	terminated := []string{ids[0], ids[4], ids[8]}
+
+	_, err = repo.PurgeTerminatedSessions(ctx, -time.Hour)
+	require.Error(err)
+	assert.True(errors.Is(err, db.ErrInvalidParameter))
+
+	purged, err := repo.PurgeTerminatedSessions(ctx, time.Hour)
+	require.NoError(err)
+	assert.Equal(0, purged)
+
+	// A batch size smaller than the number of sessions purges them all
+	// over several batches
+	purged, err = repo.PurgeTerminatedSessions(ctx, 0, WithLimit(2))
+	require.NoError(err)
+	assert.Equal(len(terminated), purged)
+
+	sessions, err := repo.ListSessions(ctx, WithLimit(-1))
+	require.NoError(err)
+	assert.Len(sessions, len(ids)-len(terminated))
+	for _, s := range sessions {
+		assert.NotContains(terminated, s.PublicId)
+	}
+
+	purged, err = repo.PurgeTerminatedSessions(ctx, 0)
+	require.NoError(err)
+	assert.Equal(0, purged)
+}
+
+func TestRepository_ListSessionChanges(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	activeFn := func() (*Session, *Connection) {
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		srv := TestWorker(t, conn, wrapper)
+		s, _, err := repo.ActivateSession(ctx, s.PublicId, s.Version, srv.PrivateId, srv.Type, TestTofu(t))
+		require.NoError(t, err)
+		c := TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+		return s, c
+	}
+
+	unchanged, _ := activeFn()
+
+	canceled, _ := activeFn()
+	_, err = repo.CancelSession(ctx, canceled.PublicId, canceled.Version)
+	require.NoError(t, err)
+
+	closed, closedConn := activeFn()
+	_ = TestConnection(t, conn, closed.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+	_, err = repo.CloseConnections(ctx, []CloseWith{{
+		ConnectionId: closedConn.PublicId,
+		BytesUp:      1,
+		BytesDown:    1,
+		ClosedReason: ConnectionClosedByUser,
+	}})
+	require.NoError(t, err)
+
+	t.Run("no-sessions", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListSessionChanges(ctx, nil)
+		require.NoError(err)
+		assert.Empty(got)
+	})
+	t.Run("unchanged", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListSessionChanges(ctx, []string{unchanged.PublicId, "s_notfound"})
+		require.NoError(err)
+		assert.Empty(got)
+	})
+	t.Run("changed", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListSessionChanges(ctx, []string{unchanged.PublicId, canceled.PublicId, closed.PublicId})
+		require.NoError(err)
+		want := []*StateReport{
+			{
+				SessionId: canceled.PublicId,
+				Status:    StatusCanceling,
+			},
+			{
+				SessionId:           closed.PublicId,
+				Status:              StatusActive,
+				ClosedConnectionIds: []string{closedConn.PublicId},
+			},
+		}
+		assert.ElementsMatch(want, got)
+	})
+}
+
+func TestRepository_ListWorkerSessionDigests(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	worker := TestWorker(t, conn, wrapper)
+	otherWorker := TestWorker(t, conn, wrapper)
+	activateFn := func(srv *servers.Server) *Session {
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		s, _, err := repo.ActivateSession(ctx, s.PublicId, s.Version, srv.PrivateId, srv.Type, TestTofu(t))
+		require.NoError(t, err)
+		return s
+	}
+
+	// Pending sessions aren't assigned to a worker
+	_ = TestDefaultSession(t, conn, wrapper, iamRepo)
+	active := activateFn(worker)
+	canceled := activateFn(worker)
+	canceled, err = repo.CancelSession(ctx, canceled.PublicId, canceled.Version)
+	require.NoError(t, err)
+	terminated := activateFn(worker)
+	_, err = repo.TerminateSession(ctx, terminated.PublicId, terminated.Version, ClosedByUser)
+	require.NoError(t, err)
+	_ = activateFn(otherWorker)
+
+	t.Run("missing-server-id", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListWorkerSessionDigests(ctx, "")
+		require.Error(err)
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+		assert.Nil(got)
+	})
+	t.Run("no-sessions", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListWorkerSessionDigests(ctx, "w_notfound")
+		require.NoError(err)
+		assert.Empty(got)
+	})
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListWorkerSessionDigests(ctx, worker.PrivateId)
+		require.NoError(err)
+		want := []*Digest{
+			{SessionId: active.PublicId, Version: active.Version, Status: StatusActive},
+			{SessionId: canceled.PublicId, Version: canceled.Version, Status: StatusCanceling},
+		}
+		assert.ElementsMatch(want, got)
+	})
+}
@@ -8,18 +8,43 @@ import (
 	"fmt"
 	"math/big"
 	mathrand "math/rand"
+	"net"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/iam"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/hashicorp/go-kms-wrapping/structwrapping"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// The reasons the host of a session is chosen.
+const (
+	// HostSelectionRequested means the client asked for the host.
+	HostSelectionRequested = "requested"
+
+	// HostSelectionRandom means the host was chosen at random from the
+	// hosts of the target's host sets.
+	HostSelectionRandom = "random"
+
+	// HostSelectionRoundRobin means the host was the next of the target's
+	// hosts in turn.
+	HostSelectionRoundRobin = "round_robin"
+
+	// HostSelectionSticky means the host was the one the user was last given
+	// by the target.
+	HostSelectionSticky = "sticky"
+)
+
 const (
 	defaultSessionTableName = "session"
+
+	// MaxUserAgentLength is the longest user agent which can be recorded for
+	// a session.
+	MaxUserAgentLength = 1024
 )
 
 // ComposedOf defines the boundary data that is referenced to compose a session.
@@ -44,6 +69,24 @@ type ComposedOf struct {
 	ExpirationTime *timestamp.Timestamp
 	// Max connections for the session
 	ConnectionLimit int32
+	// Bytes per second each of the session's connections can proxy in each
+	// direction. 0 means connections are not limited.
+	ConnectionBandwidthLimit uint32
+	// The ports and ranges of ports, besides the endpoint's port, which
+	// connections can be made to. Empty means only the endpoint's port.
+	AllowedPorts string
+	// HostAddress is the address of the host chosen for the session. It is
+	// optional.
+	HostAddress string
+	// HostSelectionReason is why the host was chosen, one of the
+	// HostSelection constants. It is optional.
+	HostSelectionReason string
+	// ClientIp is the address of the client which authorized the session. It
+	// is optional.
+	ClientIp string
+	// UserAgent is the user agent of the client which authorized the session.
+	// It is optional.
+	UserAgent string
 }
 
 // Session contains information about a user's session with a target
@@ -68,8 +111,15 @@ type Session struct {
 	ScopeId string `json:"scope_id,omitempty" gorm:"default:null"`
 	// Certificate to use when connecting (or if using custom certs, to
 	// serve as the "login"). Raw DER bytes.  Private key is not, and should not be
-	// stored in the database.
-	Certificate []byte `json:"certificate,omitempty" gorm:"default:null"`
+	// stored in the database. It is only set when the session is created or
+	// when it's looked up using WithDecryptedCertificate.
+	Certificate []byte `json:"certificate,omitempty" gorm:"-"`
+	// CtCertificate is the ciphertext certificate stored in the database
+	CtCertificate []byte `json:"ct_certificate,omitempty" gorm:"column:certificate;default:null"`
+	// CertificateKeyId is the id of the key the certificate was encrypted
+	// with. It is empty for sessions created before certificates were
+	// encrypted, whose CtCertificate is the certificate itself.
+	CertificateKeyId string `json:"certificate_key_id,omitempty" gorm:"default:null"`
 	// ExpirationTime - after this time the connection will be expired, e.g. forcefully terminated
 	ExpirationTime *timestamp.Timestamp `json:"expiration_time,omitempty" gorm:"default:null"`
 	// CtTofuToken is the ciphertext Tofutoken value stored in the database
@@ -88,6 +138,21 @@ type Session struct {
 	Endpoint string `json:"-" gorm:"default:null"`
 	// Maximum number of connections in a session
 	ConnectionLimit int32 `json:"connection_limit,omitempty" gorm:"default:null"`
+	// ClientIp is the address of the client which authorized the session
+	ClientIp string `json:"client_ip,omitempty" gorm:"default:null"`
+	// UserAgent is the user agent of the client which authorized the session
+	UserAgent string `json:"user_agent,omitempty" gorm:"default:null"`
+	// ConnectionBandwidthLimit is how many bytes per second each connection
+	// can proxy in each direction
+	ConnectionBandwidthLimit uint32 `json:"connection_bandwidth_limit,omitempty" gorm:"default:null"`
+	// AllowedPorts are the ports and ranges of ports, besides the endpoint's
+	// port, which connections can be made to
+	AllowedPorts string `json:"allowed_ports,omitempty" gorm:"default:null"`
+	// HostAddress is the address of the host chosen for the session when it
+	// was authorized
+	HostAddress string `json:"host_address,omitempty" gorm:"default:null"`
+	// HostSelectionReason is why the host was chosen
+	HostSelectionReason string `json:"host_selection_reason,omitempty" gorm:"default:null"`
 
 	// key_id is the key ID that was used for the encryption operation. It can be
 	// used to identify a specific version of the key needed to decrypt the value,
@@ -111,15 +176,21 @@ var _ db.VetForWriter = (*Session)(nil)
 // New creates a new in memory session.
 func New(c ComposedOf, opt ...Option) (*Session, error) {
 	s := Session{
-		UserId:          c.UserId,
-		HostId:          c.HostId,
-		TargetId:        c.TargetId,
-		HostSetId:       c.HostSetId,
-		AuthTokenId:     c.AuthTokenId,
-		ScopeId:         c.ScopeId,
-		Endpoint:        c.Endpoint,
-		ExpirationTime:  c.ExpirationTime,
-		ConnectionLimit: c.ConnectionLimit,
+		UserId:                   c.UserId,
+		HostId:                   c.HostId,
+		TargetId:                 c.TargetId,
+		HostSetId:                c.HostSetId,
+		AuthTokenId:              c.AuthTokenId,
+		ScopeId:                  c.ScopeId,
+		Endpoint:                 c.Endpoint,
+		ExpirationTime:           c.ExpirationTime,
+		ConnectionLimit:          c.ConnectionLimit,
+		ClientIp:                 c.ClientIp,
+		UserAgent:                c.UserAgent,
+		ConnectionBandwidthLimit: c.ConnectionBandwidthLimit,
+		AllowedPorts:             c.AllowedPorts,
+		HostAddress:              c.HostAddress,
+		HostSelectionReason:      c.HostSelectionReason,
 	}
 	if err := s.validateNewSession("new session:"); err != nil {
 		return nil, err
@@ -135,19 +206,26 @@ func AllocSession() Session {
 // Clone creates a clone of the Session
 func (s *Session) Clone() interface{} {
 	clone := &Session{
-		PublicId:          s.PublicId,
-		UserId:            s.UserId,
-		HostId:            s.HostId,
-		ServerId:          s.ServerId,
-		ServerType:        s.ServerType,
-		TargetId:          s.TargetId,
-		HostSetId:         s.HostSetId,
-		AuthTokenId:       s.AuthTokenId,
-		ScopeId:           s.ScopeId,
-		TerminationReason: s.TerminationReason,
-		Version:           s.Version,
-		Endpoint:          s.Endpoint,
-		ConnectionLimit:   s.ConnectionLimit,
+		PublicId:                 s.PublicId,
+		UserId:                   s.UserId,
+		HostId:                   s.HostId,
+		ServerId:                 s.ServerId,
+		ServerType:               s.ServerType,
+		TargetId:                 s.TargetId,
+		HostSetId:                s.HostSetId,
+		AuthTokenId:              s.AuthTokenId,
+		ScopeId:                  s.ScopeId,
+		CertificateKeyId:         s.CertificateKeyId,
+		TerminationReason:        s.TerminationReason,
+		Version:                  s.Version,
+		Endpoint:                 s.Endpoint,
+		ConnectionLimit:          s.ConnectionLimit,
+		ClientIp:                 s.ClientIp,
+		UserAgent:                s.UserAgent,
+		ConnectionBandwidthLimit: s.ConnectionBandwidthLimit,
+		AllowedPorts:             s.AllowedPorts,
+		HostAddress:              s.HostAddress,
+		HostSelectionReason:      s.HostSelectionReason,
 	}
 	if len(s.States) > 0 {
 		clone.States = make([]*State, 0, len(s.States))
@@ -168,6 +246,10 @@ func (s *Session) Clone() interface{} {
 		clone.Certificate = make([]byte, len(s.Certificate))
 		copy(clone.Certificate, s.Certificate)
 	}
+	if s.CtCertificate != nil {
+		clone.CtCertificate = make([]byte, len(s.CtCertificate))
+		copy(clone.CtCertificate, s.CtCertificate)
+	}
 	if s.ExpirationTime != nil {
 		clone.ExpirationTime = &timestamp.Timestamp{
 			Timestamp: &timestamppb.Timestamp{
@@ -207,9 +289,14 @@ func (s *Session) VetForWrite(ctx context.Context, r db.Reader, opType db.OpType
 		if err := s.validateNewSession("session vet for write:"); err != nil {
 			return err
 		}
-		if len(s.Certificate) == 0 {
+		if len(s.CtCertificate) == 0 {
 			return fmt.Errorf("session vet for write: certificate is missing: %w", db.ErrInvalidParameter)
 		}
+		// Existing sessions can still be canceled and terminated, but no new
+		// ones can be created in a read only scope.
+		if err := iam.VetScopeForWrite(ctx, r, s.ScopeId); err != nil {
+			return fmt.Errorf("session vet for write: %w", err)
+		}
 	case db.UpdateOp:
 		switch {
 		case contains(opts.WithFieldMaskPaths, "PublicId"):
@@ -224,8 +311,10 @@ func (s *Session) VetForWrite(ctx context.Context, r db.Reader, opType db.OpType
 			return fmt.Errorf("session vet for write: host set id is immutable: %w", db.ErrInvalidParameter)
 		case contains(opts.WithFieldMaskPaths, "AuthTokenId"):
 			return fmt.Errorf("session vet for write: auth token id is immutable: %w", db.ErrInvalidParameter)
-		case contains(opts.WithFieldMaskPaths, "Certificate"):
+		case contains(opts.WithFieldMaskPaths, "Certificate"), contains(opts.WithFieldMaskPaths, "CtCertificate"):
 			return fmt.Errorf("session vet for write: certificate is immutable: %w", db.ErrInvalidParameter)
+		case contains(opts.WithFieldMaskPaths, "CertificateKeyId"):
+			return fmt.Errorf("session vet for write: certificate key id is immutable: %w", db.ErrInvalidParameter)
 		case contains(opts.WithFieldMaskPaths, "CreateTime"):
 			return fmt.Errorf("session vet for write: create time is immutable: %w", db.ErrInvalidParameter)
 		case contains(opts.WithFieldMaskPaths, "UpdateTime"):
@@ -236,6 +325,18 @@ func (s *Session) VetForWrite(ctx context.Context, r db.Reader, opType db.OpType
 			return fmt.Errorf("session vet for write: expiration time is immutable: %w", db.ErrInvalidParameter)
 		case contains(opts.WithFieldMaskPaths, "ConnectionLimit"):
 			return fmt.Errorf("session vet for write: connection limit is immutable: %w", db.ErrInvalidParameter)
+		case contains(opts.WithFieldMaskPaths, "ClientIp"):
+			return fmt.Errorf("session vet for write: client ip is immutable: %w", db.ErrInvalidParameter)
+		case contains(opts.WithFieldMaskPaths, "UserAgent"):
+			return fmt.Errorf("session vet for write: user agent is immutable: %w", db.ErrInvalidParameter)
+		case contains(opts.WithFieldMaskPaths, "ConnectionBandwidthLimit"):
+			return fmt.Errorf("session vet for write: connection bandwidth limit is immutable: %w", db.ErrInvalidParameter)
+		case contains(opts.WithFieldMaskPaths, "AllowedPorts"):
+			return fmt.Errorf("session vet for write: allowed ports are immutable: %w", db.ErrInvalidParameter)
+		case contains(opts.WithFieldMaskPaths, "HostAddress"):
+			return fmt.Errorf("session vet for write: host address is immutable: %w", db.ErrInvalidParameter)
+		case contains(opts.WithFieldMaskPaths, "HostSelectionReason"):
+			return fmt.Errorf("session vet for write: host selection reason is immutable: %w", db.ErrInvalidParameter)
 		case contains(opts.WithFieldMaskPaths, "TerminationReason"):
 			if _, err := convertToReason(s.TerminationReason); err != nil {
 				return fmt.Errorf("session vet for write: termination reason '%s' is invalid: %w", s.TerminationReason, db.ErrInvalidParameter)
@@ -286,6 +387,12 @@ func (s *Session) validateNewSession(errorPrefix string) error {
 	if s.ExpirationTime.GetTimestamp().AsTime().IsZero() {
 		return fmt.Errorf("%s missing expiration time: %w", errorPrefix, db.ErrInvalidParameter)
 	}
+	if s.ClientIp != "" && net.ParseIP(s.ClientIp) == nil {
+		return fmt.Errorf("%s client ip %q is not a valid ip address: %w", errorPrefix, s.ClientIp, db.ErrInvalidParameter)
+	}
+	if len(s.UserAgent) > MaxUserAgentLength {
+		return fmt.Errorf("%s user agent is longer than %d characters: %w", errorPrefix, MaxUserAgentLength, db.ErrInvalidParameter)
+	}
 	if s.TerminationReason != "" {
 		return fmt.Errorf("%s termination reason must be empty: %w", errorPrefix, db.ErrInvalidParameter)
 	}
@@ -363,28 +470,70 @@ func (s *Session) decrypt(ctx context.Context, cipher wrapping.Wrapper) error {
 	return nil
 }
 
+// encryptCertificate encrypts the session's certificate into CtCertificate.
+// It isn't wrapped along with the tofu token since the certificate is stored
+// when the session is created, before there is a tofu token.
+func (s *Session) encryptCertificate(ctx context.Context, cipher wrapping.Wrapper) error {
+	blobInfo, err := cipher.Encrypt(ctx, s.Certificate, nil)
+	if err != nil {
+		return fmt.Errorf("error encrypting session certificate: %w", err)
+	}
+	ct, err := proto.Marshal(blobInfo)
+	if err != nil {
+		return fmt.Errorf("error marshaling encrypted session certificate: %w", err)
+	}
+	s.CtCertificate = ct
+	s.CertificateKeyId = cipher.KeyID()
+	return nil
+}
+
+// decryptCertificate sets the session's certificate from CtCertificate.
+func (s *Session) decryptCertificate(ctx context.Context, cipher wrapping.Wrapper) error {
+	if s.CertificateKeyId == "" {
+		// stored before certificates were encrypted
+		s.Certificate = s.CtCertificate
+		return nil
+	}
+	blobInfo := new(wrapping.EncryptedBlobInfo)
+	if err := proto.Unmarshal(s.CtCertificate, blobInfo); err != nil {
+		return fmt.Errorf("error unmarshaling encrypted session certificate: %w", err)
+	}
+	pt, err := cipher.Decrypt(ctx, blobInfo, nil)
+	if err != nil {
+		return fmt.Errorf("error decrypting session certificate: %w", err)
+	}
+	s.Certificate = pt
+	return nil
+}
+
 type sessionView struct {
 	// Session fields
-	PublicId          string               `json:"public_id,omitempty" gorm:"primary_key"`
-	UserId            string               `json:"user_id,omitempty" gorm:"default:null"`
-	HostId            string               `json:"host_id,omitempty" gorm:"default:null"`
-	ServerId          string               `json:"server_id,omitempty" gorm:"default:null"`
-	ServerType        string               `json:"server_type,omitempty" gorm:"default:null"`
-	TargetId          string               `json:"target_id,omitempty" gorm:"default:null"`
-	HostSetId         string               `json:"host_set_id,omitempty" gorm:"default:null"`
-	AuthTokenId       string               `json:"auth_token_id,omitempty" gorm:"default:null"`
-	ScopeId           string               `json:"scope_id,omitempty" gorm:"default:null"`
-	Certificate       []byte               `json:"certificate,omitempty" gorm:"default:null"`
-	ExpirationTime    *timestamp.Timestamp `json:"expiration_time,omitempty" gorm:"default:null"`
-	CtTofuToken       []byte               `json:"ct_tofu_token,omitempty" gorm:"column:tofu_token;default:null" wrapping:"ct,tofu_token"`
-	TofuToken         []byte               `json:"tofu_token,omitempty" gorm:"-" wrapping:"pt,tofu_token"`
-	TerminationReason string               `json:"termination_reason,omitempty" gorm:"default:null"`
-	CreateTime        *timestamp.Timestamp `json:"create_time,omitempty" gorm:"default:current_timestamp"`
-	UpdateTime        *timestamp.Timestamp `json:"update_time,omitempty" gorm:"default:current_timestamp"`
-	Version           uint32               `json:"version,omitempty" gorm:"default:null"`
-	Endpoint          string               `json:"-" gorm:"default:null"`
-	ConnectionLimit   int32                `json:"connection_limit,omitempty" gorm:"default:null"`
-	KeyId             string               `json:"key_id,omitempty" gorm:"not_null"`
+	PublicId                 string               `json:"public_id,omitempty" gorm:"primary_key"`
+	UserId                   string               `json:"user_id,omitempty" gorm:"default:null"`
+	HostId                   string               `json:"host_id,omitempty" gorm:"default:null"`
+	ServerId                 string               `json:"server_id,omitempty" gorm:"default:null"`
+	ServerType               string               `json:"server_type,omitempty" gorm:"default:null"`
+	TargetId                 string               `json:"target_id,omitempty" gorm:"default:null"`
+	HostSetId                string               `json:"host_set_id,omitempty" gorm:"default:null"`
+	AuthTokenId              string               `json:"auth_token_id,omitempty" gorm:"default:null"`
+	ScopeId                  string               `json:"scope_id,omitempty" gorm:"default:null"`
+	CtCertificate            []byte               `json:"ct_certificate,omitempty" gorm:"column:certificate;default:null"`
+	ExpirationTime           *timestamp.Timestamp `json:"expiration_time,omitempty" gorm:"default:null"`
+	CtTofuToken              []byte               `json:"ct_tofu_token,omitempty" gorm:"column:tofu_token;default:null" wrapping:"ct,tofu_token"`
+	TofuToken                []byte               `json:"tofu_token,omitempty" gorm:"-" wrapping:"pt,tofu_token"`
+	TerminationReason        string               `json:"termination_reason,omitempty" gorm:"default:null"`
+	CreateTime               *timestamp.Timestamp `json:"create_time,omitempty" gorm:"default:current_timestamp"`
+	UpdateTime               *timestamp.Timestamp `json:"update_time,omitempty" gorm:"default:current_timestamp"`
+	Version                  uint32               `json:"version,omitempty" gorm:"default:null"`
+	Endpoint                 string               `json:"-" gorm:"default:null"`
+	ClientIp                 string               `json:"client_ip,omitempty" gorm:"default:null"`
+	UserAgent                string               `json:"user_agent,omitempty" gorm:"default:null"`
+	ConnectionLimit          int32                `json:"connection_limit,omitempty" gorm:"default:null"`
+	ConnectionBandwidthLimit uint32               `json:"connection_bandwidth_limit,omitempty" gorm:"default:null"`
+	AllowedPorts             string               `json:"allowed_ports,omitempty" gorm:"default:null"`
+	HostAddress              string               `json:"host_address,omitempty" gorm:"default:null"`
+	HostSelectionReason      string               `json:"host_selection_reason,omitempty" gorm:"default:null"`
+	KeyId                    string               `json:"key_id,omitempty" gorm:"not_null"`
 
 	// State fields
 	Status          string               `json:"state,omitempty" gorm:"column:state"`
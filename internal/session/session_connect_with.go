@@ -1,6 +1,7 @@
 package session
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/hashicorp/boundary/internal/db"
@@ -14,6 +15,15 @@ type ConnectWith struct {
 	ClientTcpPort      uint32
 	EndpointTcpAddress string
 	EndpointTcpPort    uint32
+
+	// Protocol and ProtocolMetadata are optional. ProtocolMetadata must be a
+	// JSON object and can only be set along with Protocol.
+	Protocol         string
+	ProtocolMetadata string
+
+	// Version is optional. If set, the connection is only updated if it's
+	// still at this version.
+	Version uint32
 }
 
 func (c ConnectWith) validate() error {
@@ -32,5 +42,14 @@ func (c ConnectWith) validate() error {
 	if c.EndpointTcpPort == 0 {
 		return fmt.Errorf("missing endpoint ctp port: %w", db.ErrInvalidParameter)
 	}
+	if c.ProtocolMetadata != "" {
+		if c.Protocol == "" {
+			return fmt.Errorf("protocol metadata without a protocol: %w", db.ErrInvalidParameter)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(c.ProtocolMetadata), &m); err != nil || m == nil {
+			return fmt.Errorf("protocol metadata is not a json object: %w", db.ErrInvalidParameter)
+		}
+	}
 	return nil
 }
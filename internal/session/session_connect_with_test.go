@@ -16,6 +16,8 @@ func TestConnectWith_validate(t *testing.T) {
 		ClientTcpPort      uint32
 		EndpointTcpAddress string
 		EndpointTcpPort    uint32
+		Protocol           string
+		ProtocolMetadata   string
 	}
 	tests := []struct {
 		name    string
@@ -82,6 +84,43 @@ func TestConnectWith_validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid-protocol-metadata",
+			fields: fields{
+				SessionId:          id,
+				ClientTcpAddress:   "0.0.0.1",
+				ClientTcpPort:      22,
+				EndpointTcpAddress: "0.0.0.1",
+				EndpointTcpPort:    2222,
+				Protocol:           "ssh",
+				ProtocolMetadata:   `{"ssh_server_version":"SSH-2.0-OpenSSH_8.4"}`,
+			},
+		},
+		{
+			name: "protocol-metadata-without-protocol",
+			fields: fields{
+				SessionId:          id,
+				ClientTcpAddress:   "0.0.0.1",
+				ClientTcpPort:      22,
+				EndpointTcpAddress: "0.0.0.1",
+				EndpointTcpPort:    2222,
+				ProtocolMetadata:   `{"ssh_server_version":"SSH-2.0-OpenSSH_8.4"}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "protocol-metadata-not-an-object",
+			fields: fields{
+				SessionId:          id,
+				ClientTcpAddress:   "0.0.0.1",
+				ClientTcpPort:      22,
+				EndpointTcpAddress: "0.0.0.1",
+				EndpointTcpPort:    2222,
+				Protocol:           "ssh",
+				ProtocolMetadata:   `["SSH-2.0-OpenSSH_8.4"]`,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -91,6 +130,8 @@ func TestConnectWith_validate(t *testing.T) {
 				ClientTcpPort:      tt.fields.ClientTcpPort,
 				EndpointTcpAddress: tt.fields.EndpointTcpAddress,
 				EndpointTcpPort:    tt.fields.EndpointTcpPort,
+				Protocol:           tt.fields.Protocol,
+				ProtocolMetadata:   tt.fields.ProtocolMetadata,
 			}
 			if err := c.validate(); (err != nil) != tt.wantErr {
 				t.Errorf("ConnectWith.validate() error = %v, wantErr %v", err, tt.wantErr)
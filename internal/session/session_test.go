@@ -3,6 +3,7 @@ package session
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -57,6 +58,55 @@ func TestSession_Create(t *testing.T) {
 			},
 			create: true,
 		},
+		{
+			name: "valid-with-client-info",
+			args: args{
+				composedOf: func() ComposedOf {
+					c := composedOf
+					c.ClientIp = "203.0.113.10"
+					c.UserAgent = "boundary-cli/0.1.8"
+					return c
+				}(),
+			},
+			want: &Session{
+				UserId:          composedOf.UserId,
+				HostId:          composedOf.HostId,
+				TargetId:        composedOf.TargetId,
+				HostSetId:       composedOf.HostSetId,
+				AuthTokenId:     composedOf.AuthTokenId,
+				ScopeId:         composedOf.ScopeId,
+				Endpoint:        "tcp://127.0.0.1:22",
+				ExpirationTime:  composedOf.ExpirationTime,
+				ConnectionLimit: composedOf.ConnectionLimit,
+				ClientIp:        "203.0.113.10",
+				UserAgent:       "boundary-cli/0.1.8",
+			},
+			create: true,
+		},
+		{
+			name: "invalid-clientIp",
+			args: args{
+				composedOf: func() ComposedOf {
+					c := composedOf
+					c.ClientIp = "not-an-ip"
+					return c
+				}(),
+			},
+			wantErr:   true,
+			wantIsErr: db.ErrInvalidParameter,
+		},
+		{
+			name: "userAgent-too-long",
+			args: args{
+				composedOf: func() ComposedOf {
+					c := composedOf
+					c.UserAgent = strings.Repeat("a", MaxUserAgentLength+1)
+					return c
+				}(),
+			},
+			wantErr:   true,
+			wantIsErr: db.ErrInvalidParameter,
+		},
 		{
 			name: "empty-userId",
 			args: args{
@@ -148,6 +198,7 @@ func TestSession_Create(t *testing.T) {
 				_, certBytes, err := newCert(wrapper, got.UserId, id, composedOf.ExpirationTime.Timestamp.AsTime())
 				require.NoError(err)
 				got.Certificate = certBytes
+				require.NoError(got.encryptCertificate(context.Background(), wrapper))
 				err = db.New(conn).Create(context.Background(), got)
 				if tt.wantCreateErr {
 					assert.Error(err)
@@ -0,0 +1,145 @@
+// Package sessiontest provides an in-memory implementation of session.Repo,
+// so that code using session repositories can be tested without a database.
+package sessiontest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/session"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Repo is an in-memory session.Repo. Creating a session needs a database, so
+// sessions are added to a Repo with AddSession. Options are ignored. It is
+// safe for concurrent use.
+type Repo struct {
+	l        sync.Mutex
+	sessions map[string]*session.Session
+}
+
+var _ session.Repo = (*Repo)(nil)
+
+// NewRepo creates an empty Repo.
+func NewRepo() *Repo {
+	return &Repo{
+		sessions: make(map[string]*session.Session),
+	}
+}
+
+// AddSession adds a copy of s to the repo and returns it. A public id is
+// generated if s doesn't have one, the version is set to 1 and a pending
+// state is added if s has no states. States are ordered by start time
+// descending, as LookupSession returns them.
+func (r *Repo) AddSession(s *session.Session) (*session.Session, error) {
+	if s == nil {
+		return nil, fmt.Errorf("add session: missing session: %w", db.ErrInvalidParameter)
+	}
+	n := s.Clone().(*session.Session)
+	if n.PublicId == "" {
+		id, err := db.NewPublicId(session.SessionPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("add session: %w", err)
+		}
+		n.PublicId = id
+	}
+	n.Version = 1
+	n.CreateTime, n.UpdateTime = now(), now()
+	if len(n.States) == 0 {
+		n.States = []*session.State{{SessionId: n.PublicId, Status: session.StatusPending, StartTime: now()}}
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.sessions[n.PublicId]; ok {
+		return nil, fmt.Errorf("add session: %s: %w", n.PublicId, db.ErrNotUnique)
+	}
+	r.sessions[n.PublicId] = n
+	return n.Clone().(*session.Session), nil
+}
+
+// LookupSession implements session.Repo. The returned summary has no
+// connections.
+func (r *Repo) LookupSession(ctx context.Context, sessionId string, opt ...session.Option) (*session.Session, *session.ConnectionAuthzSummary, error) {
+	if sessionId == "" {
+		return nil, nil, fmt.Errorf("lookup session: missing sessionId id: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	s, ok := r.sessions[sessionId]
+	if !ok {
+		return nil, nil, nil
+	}
+	summary := &session.ConnectionAuthzSummary{
+		ExpirationTime:  s.ExpirationTime,
+		ConnectionLimit: s.ConnectionLimit,
+	}
+	return s.Clone().(*session.Session), summary, nil
+}
+
+// ListSessions implements session.Repo. Sessions are ordered by public id
+// and only their current states are returned.
+func (r *Repo) ListSessions(ctx context.Context, opt ...session.Option) ([]*session.Session, error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	sessions := make([]*session.Session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		cp := s.Clone().(*session.Session)
+		cp.States = cp.States[:1]
+		sessions = append(sessions, cp)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].PublicId < sessions[j].PublicId })
+	return sessions, nil
+}
+
+// CancelSession implements session.Repo.
+func (r *Repo) CancelSession(ctx context.Context, sessionId string, sessionVersion uint32) (*session.Session, error) {
+	if sessionId == "" {
+		return nil, fmt.Errorf("cancel session: missing session id: %w", db.ErrInvalidParameter)
+	}
+	if sessionVersion == 0 {
+		return nil, fmt.Errorf("cancel session: missing session version: %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	s, ok := r.sessions[sessionId]
+	if !ok || s.Version != sessionVersion {
+		return nil, fmt.Errorf("cancel session: session %s version %d: %w", sessionId, sessionVersion, db.ErrRecordNotFound)
+	}
+	s.Version++
+	s.UpdateTime = now()
+	switch s.States[0].Status {
+	case session.StatusCanceling, session.StatusTerminated:
+	default:
+		s.States[0].EndTime = now()
+		s.States = append([]*session.State{{
+			SessionId:       sessionId,
+			Status:          session.StatusCanceling,
+			PreviousEndTime: s.States[0].EndTime,
+			StartTime:       now(),
+		}}, s.States...)
+	}
+	return s.Clone().(*session.Session), nil
+}
+
+// DeleteSession implements session.Repo. Sessions don't have connections in
+// a Repo, so they can always be deleted.
+func (r *Repo) DeleteSession(ctx context.Context, publicId string, opt ...session.Option) (int, error) {
+	if publicId == "" {
+		return db.NoRowsAffected, fmt.Errorf("delete session: missing public id %w", db.ErrInvalidParameter)
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	if _, ok := r.sessions[publicId]; !ok {
+		return db.NoRowsAffected, fmt.Errorf("delete session: failed %w for %s", db.ErrRecordNotFound, publicId)
+	}
+	delete(r.sessions, publicId)
+	return 1, nil
+}
+
+func now() *timestamp.Timestamp {
+	return &timestamp.Timestamp{Timestamp: timestamppb.Now()}
+}
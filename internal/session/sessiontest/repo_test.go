@@ -0,0 +1,56 @@
+package sessiontest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	repo := NewRepo()
+
+	s, err := repo.AddSession(&session.Session{UserId: "u_1234567890", ScopeId: "p_1234567890", ConnectionLimit: 2})
+	require.NoError(err)
+	db.AssertPublicId(t, session.SessionPrefix, s.PublicId)
+	require.Len(s.States, 1)
+	assert.Equal(session.StatusPending, s.States[0].Status)
+
+	got, summary, err := repo.LookupSession(ctx, s.PublicId)
+	require.NoError(err)
+	assert.Equal(s, got)
+	assert.Equal(int32(2), summary.ConnectionLimit)
+
+	_, err = repo.CancelSession(ctx, s.PublicId, s.Version+1)
+	assert.True(errors.Is(err, db.ErrRecordNotFound))
+	canceled, err := repo.CancelSession(ctx, s.PublicId, s.Version)
+	require.NoError(err)
+	assert.Equal(s.Version+1, canceled.Version)
+	require.Len(canceled.States, 2)
+	assert.Equal(session.StatusCanceling, canceled.States[0].Status)
+
+	// Canceling is idempotent
+	canceled, err = repo.CancelSession(ctx, s.PublicId, canceled.Version)
+	require.NoError(err)
+	assert.Len(canceled.States, 2)
+
+	sessions, err := repo.ListSessions(ctx)
+	require.NoError(err)
+	require.Len(sessions, 1)
+	assert.Len(sessions[0].States, 1)
+
+	deleted, err := repo.DeleteSession(ctx, s.PublicId)
+	require.NoError(err)
+	assert.Equal(1, deleted)
+	got, _, err = repo.LookupSession(ctx, s.PublicId)
+	require.NoError(err)
+	assert.Nil(got)
+	_, err = repo.DeleteSession(ctx, s.PublicId)
+	assert.True(errors.Is(err, db.ErrRecordNotFound))
+}
@@ -3,6 +3,8 @@ package session
 import (
 	"context"
 	"crypto/ed25519"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,7 +27,7 @@ import (
 )
 
 // TestConnection creates a test connection for the sessionId in the repository.
-func TestConnection(t *testing.T, conn *gorm.DB, sessionId, clientTcpAddr string, clientTcpPort uint32, endpointTcpAddr string, endpointTcpPort uint32) *Connection {
+func TestConnection(t testing.TB, conn *gorm.DB, sessionId, clientTcpAddr string, clientTcpPort uint32, endpointTcpAddr string, endpointTcpPort uint32) *Connection {
 	t.Helper()
 	require := require.New(t)
 	rw := db.New(conn)
@@ -45,7 +47,7 @@ func TestConnection(t *testing.T, conn *gorm.DB, sessionId, clientTcpAddr string
 }
 
 // TestConnectionState creates a test connection state for the connectionId in the repository.
-func TestConnectionState(t *testing.T, conn *gorm.DB, connectionId string, state ConnectionStatus) *ConnectionState {
+func TestConnectionState(t testing.TB, conn *gorm.DB, connectionId string, state ConnectionStatus) *ConnectionState {
 	t.Helper()
 	require := require.New(t)
 	rw := db.New(conn)
@@ -57,7 +59,7 @@ func TestConnectionState(t *testing.T, conn *gorm.DB, connectionId string, state
 }
 
 // TestState creates a test state for the sessionId in the repository.
-func TestState(t *testing.T, conn *gorm.DB, sessionId string, state Status) *State {
+func TestState(t testing.TB, conn *gorm.DB, sessionId string, state Status) *State {
 	t.Helper()
 	require := require.New(t)
 	rw := db.New(conn)
@@ -69,7 +71,7 @@ func TestState(t *testing.T, conn *gorm.DB, sessionId string, state Status) *Sta
 }
 
 // TestSession creates a test session composed of c in the repository.
-func TestSession(t *testing.T, conn *gorm.DB, wrapper wrapping.Wrapper, c ComposedOf, opt ...Option) *Session {
+func TestSession(t testing.TB, conn *gorm.DB, wrapper wrapping.Wrapper, c ComposedOf, opt ...Option) *Session {
 	t.Helper()
 	require := require.New(t)
 	if c.ExpirationTime == nil {
@@ -86,6 +88,9 @@ func TestSession(t *testing.T, conn *gorm.DB, wrapper wrapping.Wrapper, c Compos
 	_, certBytes, err := newCert(wrapper, c.UserId, id, c.ExpirationTime.Timestamp.AsTime())
 	require.NoError(err)
 	s.Certificate = certBytes
+	databaseWrapper, err := kms.TestKms(t, conn, wrapper).GetWrapper(context.Background(), c.ScopeId, kms.KeyPurposeDatabase)
+	require.NoError(err)
+	require.NoError(s.encryptCertificate(context.Background(), databaseWrapper))
 
 	if len(s.TofuToken) != 0 {
 		err = s.encrypt(context.Background(), wrapper)
@@ -101,7 +106,7 @@ func TestSession(t *testing.T, conn *gorm.DB, wrapper wrapping.Wrapper, c Compos
 }
 
 // TestDefaultSession creates a test session in the repository using defaults.
-func TestDefaultSession(t *testing.T, conn *gorm.DB, wrapper wrapping.Wrapper, iamRepo *iam.Repository, opt ...Option) *Session {
+func TestDefaultSession(t testing.TB, conn *gorm.DB, wrapper wrapping.Wrapper, iamRepo *iam.Repository, opt ...Option) *Session {
 	t.Helper()
 	require := require.New(t)
 	composedOf := TestSessionParams(t, conn, wrapper, iamRepo)
@@ -111,9 +116,105 @@ func TestDefaultSession(t *testing.T, conn *gorm.DB, wrapper wrapping.Wrapper, i
 	return TestSession(t, conn, wrapper, composedOf, WithExpirationTime(exp))
 }
 
+// testFixtureBatchSize is how many sessions, or session states,
+// TestSessionFixtures inserts with each statement.
+const testFixtureBatchSize = 500
+
+// testSessionFixtureQuery inserts copies of the session with the id $1 with
+// the ids in its values list.
+const testSessionFixtureQuery = `
+insert into session
+	(public_id, user_id, host_id, target_id, host_set_id, auth_token_id, scope_id,
+	 certificate, certificate_key_id, expiration_time, connection_limit, endpoint,
+	 key_id, tofu_token, client_ip, user_agent, connection_bandwidth_limit,
+	 allowed_ports, host_address, host_selection_reason)
+select
+	v.public_id, s.user_id, s.host_id, s.target_id, s.host_set_id, s.auth_token_id, s.scope_id,
+	s.certificate, s.certificate_key_id, s.expiration_time, s.connection_limit, s.endpoint,
+	s.key_id, s.tofu_token, s.client_ip, s.user_agent, s.connection_bandwidth_limit,
+	s.allowed_ports, s.host_address, s.host_selection_reason
+from
+	session s,
+	(values %s) as v(public_id)
+where
+	s.public_id = $1;
+`
+
+// TestSessionFixtures creates count sessions composed of c in the repository
+// for benchmarks and load tests which need many sessions. The first is
+// created with TestSession and the rest are copies of it inserted in
+// batches, which is much faster than creating each of them. Every other
+// session is activated and every fourth is then terminated, so the sessions
+// have been through differing numbers of states. It returns the ids of the
+// sessions in the order they were created.
+func TestSessionFixtures(t testing.TB, conn *gorm.DB, wrapper wrapping.Wrapper, c ComposedOf, count int) []string {
+	t.Helper()
+	require := require.New(t)
+	ctx := context.Background()
+	rw := db.New(conn)
+	if count <= 0 {
+		return nil
+	}
+
+	ids := []string{TestSession(t, conn, wrapper, c).PublicId}
+	for len(ids) < count {
+		n := count - len(ids)
+		if n > testFixtureBatchSize {
+			n = testFixtureBatchSize
+		}
+		values := make([]string, 0, n)
+		args := []interface{}{ids[0]}
+		for i := 0; i < n; i++ {
+			id, err := newId()
+			require.NoError(err)
+			ids = append(ids, id)
+			args = append(args, id)
+			values = append(values, fmt.Sprintf("($%d)", len(args)))
+		}
+		_, err := rw.Exec(ctx, fmt.Sprintf(testSessionFixtureQuery, strings.Join(values, ",")), args)
+		require.NoError(err)
+	}
+
+	var activated, terminated []string
+	for i, id := range ids {
+		if i%2 == 0 {
+			activated = append(activated, id)
+		}
+		if i%4 == 0 {
+			terminated = append(terminated, id)
+		}
+	}
+	testInsertStates(t, rw, activated, StatusActive)
+	testInsertStates(t, rw, terminated, StatusTerminated)
+	return ids
+}
+
+// testInsertStates inserts the state for each of the sessions with the ids
+// in batches.
+func testInsertStates(t testing.TB, w db.Writer, sessionIds []string, state Status) {
+	t.Helper()
+	require := require.New(t)
+	for len(sessionIds) > 0 {
+		n := len(sessionIds)
+		if n > testFixtureBatchSize {
+			n = testFixtureBatchSize
+		}
+		values := make([]string, 0, n)
+		args := make([]interface{}, 0, n+1)
+		args = append(args, state.String())
+		for _, id := range sessionIds[:n] {
+			args = append(args, id)
+			values = append(values, fmt.Sprintf("($%d, $1)", len(args)))
+		}
+		_, err := w.Exec(context.Background(), fmt.Sprintf("insert into session_state (session_id, state) values %s", strings.Join(values, ",")), args)
+		require.NoError(err)
+		sessionIds = sessionIds[n:]
+	}
+}
+
 // TestSessionParams returns an initialized ComposedOf which can be used to
 // create a session in the repository.
-func TestSessionParams(t *testing.T, conn *gorm.DB, wrapper wrapping.Wrapper, iamRepo *iam.Repository) ComposedOf {
+func TestSessionParams(t testing.TB, conn *gorm.DB, wrapper wrapping.Wrapper, iamRepo *iam.Repository) ComposedOf {
 	t.Helper()
 	ctx := context.Background()
 
@@ -162,7 +263,7 @@ func TestSessionParams(t *testing.T, conn *gorm.DB, wrapper wrapping.Wrapper, ia
 }
 
 // TestTofu will create a test "trust on first use" token
-func TestTofu(t *testing.T) []byte {
+func TestTofu(t testing.TB) []byte {
 	t.Helper()
 	require := require.New(t)
 	tofu, err := base62.Random(20)
@@ -170,7 +271,7 @@ func TestTofu(t *testing.T) []byte {
 	return []byte(tofu)
 }
 
-func TestWorker(t *testing.T, conn *gorm.DB, wrapper wrapping.Wrapper) *servers.Server {
+func TestWorker(t testing.TB, conn *gorm.DB, wrapper wrapping.Wrapper) *servers.Server {
 	t.Helper()
 	rw := db.New(conn)
 	kms := kms.TestKms(t, conn, wrapper)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,6 +21,37 @@ func Test_TestSession(t *testing.T) {
 	assert.NotEmpty(s.PublicId)
 }
 
+func Test_TestSessionFixtures(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+	composedOf := TestSessionParams(t, conn, wrapper, iamRepo)
+
+	ids := TestSessionFixtures(t, conn, wrapper, composedOf, testFixtureBatchSize+10)
+	assert.Len(ids, testFixtureBatchSize+10)
+
+	got, err := repo.ListSessions(context.Background(), WithLimit(-1))
+	require.NoError(err)
+	require.Len(got, len(ids))
+	counts := map[Status]int{}
+	for _, s := range got {
+		counts[s.States[0].Status]++
+	}
+	assert.Equal(map[Status]int{
+		StatusPending:    len(ids) / 2,
+		StatusActive:     len(ids)/2 - (len(ids)+3)/4,
+		StatusTerminated: (len(ids) + 3) / 4,
+	}, counts)
+
+	active, err := repo.ListSessions(context.Background(), WithStatus(StatusActive), WithLimit(-1))
+	require.NoError(err)
+	assert.Len(active, counts[StatusActive])
+}
+
 func Test_TestState(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
 	conn, _ := db.TestSetup(t, "postgres")
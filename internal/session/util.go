@@ -8,7 +8,6 @@ import (
 
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/hashicorp/go-kms-wrapping/wrappers/aead"
-	"github.com/hashicorp/go-kms-wrapping/wrappers/multiwrapper"
 	"golang.org/x/crypto/hkdf"
 )
 
@@ -17,7 +16,8 @@ import (
 func DeriveED25519Key(wrapper wrapping.Wrapper, userId, jobId string) (ed25519.PublicKey, ed25519.PrivateKey, error) {
 	var aeadWrapper *aead.Wrapper
 	switch w := wrapper.(type) {
-	case *multiwrapper.MultiWrapper:
+	case interface{ WrapperForKeyID(string) wrapping.Wrapper }:
+		// A multiwrapper, or a kms wrapper embedding one
 		raw := w.WrapperForKeyID("__base__")
 		var ok bool
 		if aeadWrapper, ok = raw.(*aead.Wrapper); !ok {
@@ -0,0 +1,171 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// DefaultWatchInterval is how often a Watcher checks the states of the
+// sessions being watched if no interval is given.
+const DefaultWatchInterval = time.Second
+
+// watchBufferSize is the number of status changes a Subscription holds for
+// its subscriber. Changes which don't fit are sent on a later check.
+const watchBufferSize = 16
+
+// StatusChange is the status a watched session changed to and when.
+type StatusChange struct {
+	SessionId string
+	Status    Status
+	Time      time.Time
+}
+
+// Watcher notifies subscribers when the status of the sessions they are
+// watching changes. It periodically reads the current states of every
+// watched session in one query, so the cost of watching doesn't depend on
+// the number of subscribers, and nothing is read while no sessions are
+// watched.
+type Watcher struct {
+	repoFn   func() (*Repository, error)
+	interval time.Duration
+	logger   hclog.Logger
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewWatcher returns a Watcher reading session states with the repository
+// returned by repoFn every interval. An interval of 0 uses
+// DefaultWatchInterval.
+func NewWatcher(repoFn func() (*Repository, error), interval time.Duration, logger hclog.Logger) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+	return &Watcher{
+		repoFn:   repoFn,
+		interval: interval,
+		logger:   logger,
+		subs:     make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives the status changes of the sessions it watches on C.
+// The current status of each session is sent after subscribing, followed by
+// each change. C is closed when the Subscription is closed.
+type Subscription struct {
+	C <-chan StatusChange
+
+	c   chan StatusChange
+	w   *Watcher
+	ids []string
+	// last is the last status sent for each session, guarded by the
+	// Watcher's mutex
+	last map[string]Status
+}
+
+// Subscribe returns a Subscription to the status changes of the sessions.
+// It must be closed when no longer needed.
+func (w *Watcher) Subscribe(sessionIds ...string) *Subscription {
+	c := make(chan StatusChange, watchBufferSize)
+	s := &Subscription{
+		C:    c,
+		c:    c,
+		w:    w,
+		ids:  sessionIds,
+		last: make(map[string]Status, len(sessionIds)),
+	}
+	w.mu.Lock()
+	w.subs[s] = struct{}{}
+	w.mu.Unlock()
+	return s
+}
+
+// Close stops the Subscription and closes C. It is safe to call more than
+// once.
+func (s *Subscription) Close() {
+	s.w.mu.Lock()
+	defer s.w.mu.Unlock()
+	if _, ok := s.w.subs[s]; ok {
+		delete(s.w.subs, s)
+		close(s.c)
+	}
+}
+
+// Run checks the states of the watched sessions every interval until ctx is
+// done.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.check(ctx); err != nil {
+				w.logger.Error("error checking watched session states", "error", err)
+			}
+		}
+	}
+}
+
+// check reads the current states of the watched sessions and sends each
+// subscriber the ones which changed.
+func (w *Watcher) check(ctx context.Context) error {
+	w.mu.Lock()
+	watched := make(map[string]struct{})
+	for s := range w.subs {
+		for _, id := range s.ids {
+			watched[id] = struct{}{}
+		}
+	}
+	w.mu.Unlock()
+	if len(watched) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(watched))
+	for id := range watched {
+		ids = append(ids, id)
+	}
+
+	repo, err := w.repoFn()
+	if err != nil {
+		return err
+	}
+	states, err := repo.CurrentStates(ctx, ids)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]*State, len(states))
+	for _, st := range states {
+		current[st.SessionId] = st
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for s := range w.subs {
+		for _, id := range s.ids {
+			st, ok := current[id]
+			if !ok || s.last[id] == st.Status {
+				continue
+			}
+			change := StatusChange{SessionId: id, Status: st.Status}
+			if st.StartTime != nil {
+				change.Time = st.StartTime.GetTimestamp().AsTime()
+			}
+			select {
+			case s.c <- change:
+				s.last[id] = st.Status
+			default:
+				// The subscriber is behind; the change is sent on a later
+				// check
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	repoFn := func() (*Repository, error) {
+		return repo, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := NewWatcher(repoFn, 10*time.Millisecond, nil)
+	go w.Run(ctx)
+
+	next := func(t *testing.T, sub *Subscription) StatusChange {
+		t.Helper()
+		select {
+		case change, ok := <-sub.C:
+			require.True(t, ok, "subscription closed")
+			return change
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for status change")
+		}
+		return StatusChange{}
+	}
+
+	t.Run("status-changes", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		sub := w.Subscribe(s.PublicId)
+		defer sub.Close()
+
+		change := next(t, sub)
+		assert.Equal(s.PublicId, change.SessionId)
+		assert.Equal(StatusPending, change.Status)
+		assert.False(change.Time.IsZero())
+
+		_, err := repo.CancelSession(ctx, s.PublicId, s.Version)
+		require.NoError(err)
+		change = next(t, sub)
+		assert.Equal(s.PublicId, change.SessionId)
+		assert.Equal(StatusCanceling, change.Status)
+	})
+	t.Run("close", func(t *testing.T) {
+		assert := assert.New(t)
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		sub := w.Subscribe(s.PublicId)
+		sub.Close()
+		sub.Close()
+		for range sub.C {
+		}
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		assert.NotContains(w.subs, sub)
+	})
+}
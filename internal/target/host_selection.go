@@ -0,0 +1,128 @@
+package target
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// The strategies a target can choose the host of a session with when the
+// user doesn't ask for one.
+const (
+	// RandomHostSelection chooses one of the target's hosts at random. It's
+	// the default.
+	RandomHostSelection = "random"
+
+	// RoundRobinHostSelection chooses each of the target's hosts in turn.
+	RoundRobinHostSelection = "round_robin"
+
+	// StickyHostSelection chooses the host the user was last given, as
+	// recorded by the user's HostAffinity, while it's still one of the
+	// target's hosts. Otherwise a host is chosen at random.
+	StickyHostSelection = "sticky"
+)
+
+// ValidHostSelectionStrategy returns true if s is a known host selection
+// strategy.
+func ValidHostSelectionStrategy(s string) bool {
+	switch s {
+	case RandomHostSelection, RoundRobinHostSelection, StickyHostSelection:
+		return true
+	}
+	return false
+}
+
+// A HostAffinity is the host a user was last given by the sticky host
+// selection strategy of a target.
+type HostAffinity struct {
+	TargetId   string
+	UserId     string
+	HostSetId  string
+	HostId     string
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// NextRoundRobin returns the number of hosts chosen for the target with id
+// targetId by the round robin strategy before this one, and counts this
+// one. Counts are shared by all controllers. All options are ignored.
+func (r *Repository) NextRoundRobin(ctx context.Context, targetId string, opt ...Option) (uint64, error) {
+	if targetId == "" {
+		return 0, fmt.Errorf("next round robin: missing target id: %w", db.ErrInvalidParameter)
+	}
+	var count uint64
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, _ db.Writer) error {
+			rows, err := reader.Query(ctx, nextRoundRobinQuery, []interface{}{targetId})
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				if err := rows.Scan(&count); err != nil {
+					return err
+				}
+			}
+			return rows.Err()
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("next round robin: %s: %w", targetId, err)
+	}
+	// The query returns the count including this host.
+	return count - 1, nil
+}
+
+// LookupHostAffinity returns the host the user with id userId was last given
+// by the sticky strategy of the target with id targetId. It returns nil, nil
+// if the user has no affinity for the target. All options are ignored.
+func (r *Repository) LookupHostAffinity(ctx context.Context, targetId, userId string, opt ...Option) (*HostAffinity, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup host affinity: missing target id: %w", db.ErrInvalidParameter)
+	}
+	if userId == "" {
+		return nil, fmt.Errorf("lookup host affinity: missing user id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, lookupHostAffinityQuery, []interface{}{targetId, userId})
+	if err != nil {
+		return nil, fmt.Errorf("lookup host affinity: %s: %w", targetId, err)
+	}
+	defer rows.Close()
+	var a *HostAffinity
+	for rows.Next() {
+		a = &HostAffinity{TargetId: targetId, UserId: userId}
+		if err := rows.Scan(&a.HostSetId, &a.HostId, &a.CreateTime, &a.UpdateTime); err != nil {
+			return nil, fmt.Errorf("lookup host affinity: %s: %w", targetId, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("lookup host affinity: %s: %w", targetId, err)
+	}
+	return a, nil
+}
+
+// SetHostAffinity records that the user with id userId was given the host
+// with id hostId, from the host set with id hostSetId, by the sticky strategy
+// of the target with id targetId. It replaces the user's previous affinity
+// for the target. All options are ignored.
+func (r *Repository) SetHostAffinity(ctx context.Context, targetId, userId, hostSetId, hostId string, opt ...Option) error {
+	switch {
+	case targetId == "":
+		return fmt.Errorf("set host affinity: missing target id: %w", db.ErrInvalidParameter)
+	case userId == "":
+		return fmt.Errorf("set host affinity: missing user id: %w", db.ErrInvalidParameter)
+	case hostSetId == "":
+		return fmt.Errorf("set host affinity: missing host set id: %w", db.ErrInvalidParameter)
+	case hostId == "":
+		return fmt.Errorf("set host affinity: missing host id: %w", db.ErrInvalidParameter)
+	}
+	if _, err := r.writer.Exec(ctx, setHostAffinityQuery, []interface{}{targetId, userId, hostSetId, hostId}); err != nil {
+		return fmt.Errorf("set host affinity: %s: %w", targetId, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package target
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidHostSelectionStrategy(t *testing.T) {
+	t.Parallel()
+	for _, s := range []string{RandomHostSelection, RoundRobinHostSelection, StickyHostSelection} {
+		assert.Truef(t, ValidHostSelectionStrategy(s), "strategy %q", s)
+	}
+	for _, s := range []string{"", "Random", "round-robin", "least_connections"} {
+		assert.Falsef(t, ValidHostSelectionStrategy(s), "strategy %q", s)
+	}
+}
+
+func TestRepository_NextRoundRobin(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	ctx := context.Background()
+	tar := TestTcpTarget(t, conn, proj.PublicId, "round-robin", WithHostSelectionStrategy(RoundRobinHostSelection))
+	other := TestTcpTarget(t, conn, proj.PublicId, "other", WithHostSelectionStrategy(RoundRobinHostSelection))
+
+	for want := uint64(0); want < 3; want++ {
+		got, err := repo.NextRoundRobin(ctx, tar.PublicId)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	// Each target is counted separately
+	got, err := repo.NextRoundRobin(ctx, other.PublicId)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), got)
+
+	_, err = repo.NextRoundRobin(ctx, "")
+	assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+}
+
+func TestRepository_HostAffinity(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, proj := iam.TestScopes(t, iamRepo)
+	user := iam.TestUser(t, iamRepo, org.PublicId)
+
+	cats := static.TestCatalogs(t, conn, proj.PublicId, 1)
+	hosts := static.TestHosts(t, conn, cats[0].PublicId, 2)
+	sets := static.TestSets(t, conn, cats[0].PublicId, 1)
+	static.TestSetMembers(t, conn, sets[0].PublicId, hosts)
+	tar := TestTcpTarget(t, conn, proj.PublicId, "sticky", WithHostSelectionStrategy(StickyHostSelection), WithHostSets([]string{sets[0].PublicId}))
+
+	ctx := context.Background()
+	got, err := repo.LookupHostAffinity(ctx, tar.PublicId, user.PublicId)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	require.NoError(t, repo.SetHostAffinity(ctx, tar.PublicId, user.PublicId, sets[0].PublicId, hosts[0].PublicId))
+	got, err = repo.LookupHostAffinity(ctx, tar.PublicId, user.PublicId)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, hosts[0].PublicId, got.HostId)
+	assert.Equal(t, sets[0].PublicId, got.HostSetId)
+
+	// Setting the affinity again replaces it
+	require.NoError(t, repo.SetHostAffinity(ctx, tar.PublicId, user.PublicId, sets[0].PublicId, hosts[1].PublicId))
+	got, err = repo.LookupHostAffinity(ctx, tar.PublicId, user.PublicId)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, hosts[1].PublicId, got.HostId)
+	assert.False(t, got.UpdateTime.Before(got.CreateTime))
+
+	// The affinity is removed with its host
+	_, err = rw.Exec(ctx, "delete from static_host where public_id = $1", []interface{}{hosts[1].PublicId})
+	require.NoError(t, err)
+	got, err = repo.LookupHostAffinity(ctx, tar.PublicId, user.PublicId)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	err = repo.SetHostAffinity(ctx, tar.PublicId, "", sets[0].PublicId, hosts[0].PublicId)
+	assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+}
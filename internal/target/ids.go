@@ -3,7 +3,7 @@ package target
 import (
 	"fmt"
 
-	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/types/publicid"
 )
 
 const (
@@ -11,7 +11,7 @@ const (
 )
 
 func newTcpTargetId() (string, error) {
-	id, err := db.NewPublicId(TcpTargetPrefix)
+	id, err := publicid.New(TcpTargetPrefix)
 	if err != nil {
 		return "", fmt.Errorf("new tcp target id: %w", err)
 	}
@@ -20,6 +20,7 @@ type options struct {
 	withDescription            string
 	withDefaultPort            uint32
 	withLimit                  int
+	withOrder                  string
 	withScopeId                string
 	withUserId                 string
 	withTargetType             *TargetType
@@ -27,6 +28,23 @@ type options struct {
 	withSessionMaxSeconds      uint32
 	withSessionConnectionLimit int32
 	withPublicId               string
+	withProtocol               string
+	withConnectionIdleTimeout  uint32
+	withConnectionBandwidth    uint32
+	withAccessSchedule         string
+	withTerminateAtScheduleEnd bool
+	withAllowedPorts           string
+	withSshUsername            string
+	withSshPrivateKey          []byte
+	withSshHostKey             string
+	withHostSelectionStrategy  string
+	withEndpointTls            bool
+	withTlsServerName          string
+	withTlsCaBundle            []byte
+	withTlsClientCertificate   string
+	withTlsClientPrivateKey    []byte
+	withAuthorizationValid     uint32
+	withUniqueNames            bool
 }
 
 func getDefaultOptions() options {
@@ -42,6 +60,22 @@ func getDefaultOptions() options {
 		withSessionMaxSeconds:      uint32((8 * time.Hour).Seconds()),
 		withSessionConnectionLimit: 1,
 		withPublicId:               "",
+		withProtocol:               "",
+		withConnectionIdleTimeout:  0,
+		withConnectionBandwidth:    0,
+		withAccessSchedule:         "",
+		withTerminateAtScheduleEnd: false,
+		withAllowedPorts:           "",
+		withSshUsername:            "",
+		withSshPrivateKey:          nil,
+		withSshHostKey:             "",
+		withHostSelectionStrategy:  "",
+		withEndpointTls:            false,
+		withTlsServerName:          "",
+		withTlsCaBundle:            nil,
+		withTlsClientCertificate:   "",
+		withTlsClientPrivateKey:    nil,
+		withAuthorizationValid:     0,
 	}
 }
 
@@ -68,6 +102,14 @@ func WithLimit(limit int) Option {
 	}
 }
 
+// WithOrder provides an option to order the results of a listing by a column
+// of the listed resources, such as "create_time desc".
+func WithOrder(order string) Option {
+	return func(o *options) {
+		o.withOrder = order
+	}
+}
+
 // WithDefaultPort provides an option to specify the default target port.
 func WithDefaultPort(p uint32) Option {
 	return func(o *options) {
@@ -75,6 +117,14 @@ func WithDefaultPort(p uint32) Option {
 	}
 }
 
+// WithProtocol provides an option to specify the protocol proxied to a
+// target's hosts, either "tcp" or "udp".
+func WithProtocol(p string) Option {
+	return func(o *options) {
+		o.withProtocol = p
+	}
+}
+
 // WithScopeId provides an option to search by a scope id
 func WithScopeId(scopeId string) Option {
 	return func(o *options) {
@@ -115,9 +165,145 @@ func WithSessionConnectionLimit(limit int32) Option {
 	}
 }
 
+// WithConnectionIdleTimeoutSeconds provides an option to specify how long a
+// connection to one of the target's hosts can be idle before it's closed. 0
+// means connections are never closed for being idle.
+func WithConnectionIdleTimeoutSeconds(secs uint32) Option {
+	return func(o *options) {
+		o.withConnectionIdleTimeout = secs
+	}
+}
+
+// WithConnectionBandwidthLimit provides an option to specify how many bytes
+// per second a connection to one of the target's hosts can proxy in each
+// direction. 0 means connections are not limited.
+func WithConnectionBandwidthLimit(bytesPerSecond uint32) Option {
+	return func(o *options) {
+		o.withConnectionBandwidth = bytesPerSecond
+	}
+}
+
+// WithAuthorizationValidSeconds provides an option to specify how long a
+// session authorized for the target can stay pending before it's canceled.
+// 0 means sessions can stay pending until they expire.
+func WithAuthorizationValidSeconds(secs uint32) Option {
+	return func(o *options) {
+		o.withAuthorizationValid = secs
+	}
+}
+
+// WithAccessSchedule provides an option to specify the weekly windows during
+// which sessions can be authorized for the target, in the form parsed by
+// ParseAccessSchedule. An empty schedule allows sessions at any time.
+func WithAccessSchedule(schedule string) Option {
+	return func(o *options) {
+		o.withAccessSchedule = schedule
+	}
+}
+
+// WithTerminateSessionsAtScheduleEnd provides an option to specify that
+// sessions expire when the access schedule window they were authorized in
+// ends.
+func WithTerminateSessionsAtScheduleEnd(terminate bool) Option {
+	return func(o *options) {
+		o.withTerminateAtScheduleEnd = terminate
+	}
+}
+
+// WithAllowedPorts provides an option to specify the ports and ranges of
+// ports, besides the default port, which connections to the target's hosts
+// can be made to, in the form parsed by ParsePortRanges.
+func WithAllowedPorts(ports string) Option {
+	return func(o *options) {
+		o.withAllowedPorts = ports
+	}
+}
+
+// WithSshUsername provides an option to specify the username workers
+// authenticate to the hosts of an ssh target as.
+func WithSshUsername(username string) Option {
+	return func(o *options) {
+		o.withSshUsername = username
+	}
+}
+
+// WithSshPrivateKey provides an option to specify the PEM encoded private
+// key workers authenticate to the hosts of an ssh target with.
+func WithSshPrivateKey(key []byte) Option {
+	return func(o *options) {
+		o.withSshPrivateKey = key
+	}
+}
+
+// WithSshHostKey provides an option to specify the public key, in
+// authorized_keys format, the hosts of an ssh target must present.
+func WithSshHostKey(key string) Option {
+	return func(o *options) {
+		o.withSshHostKey = key
+	}
+}
+
+// WithHostSelectionStrategy provides an option to specify how the host of a
+// session is chosen when none is requested. It must be one of
+// RandomHostSelection, RoundRobinHostSelection or StickyHostSelection.
+func WithHostSelectionStrategy(strategy string) Option {
+	return func(o *options) {
+		o.withHostSelectionStrategy = strategy
+	}
+}
+
+// WithEndpointTls provides an option to specify whether workers originate
+// TLS to the hosts of a target whose protocol is tcp.
+func WithEndpointTls(enabled bool) Option {
+	return func(o *options) {
+		o.withEndpointTls = enabled
+	}
+}
+
+// WithTlsServerName provides an option to specify the name the certificates
+// of a target's hosts are verified against.
+func WithTlsServerName(name string) Option {
+	return func(o *options) {
+		o.withTlsServerName = name
+	}
+}
+
+// WithTlsCaBundle provides an option to specify the PEM encoded certificates
+// the certificates of a target's hosts are verified with.
+func WithTlsCaBundle(bundle []byte) Option {
+	return func(o *options) {
+		o.withTlsCaBundle = bundle
+	}
+}
+
+// WithTlsClientCertificate provides an option to specify the PEM encoded
+// certificate workers authenticate to a target's hosts with.
+func WithTlsClientCertificate(cert string) Option {
+	return func(o *options) {
+		o.withTlsClientCertificate = cert
+	}
+}
+
+// WithTlsClientPrivateKey provides an option to specify the PEM encoded
+// private key of the client certificate.
+func WithTlsClientPrivateKey(key []byte) Option {
+	return func(o *options) {
+		o.withTlsClientPrivateKey = key
+	}
+}
+
 // WithPublicId provides an optional public id
 func WithPublicId(id string) Option {
 	return func(o *options) {
 		o.withPublicId = id
 	}
 }
+
+// WithUniqueNames provides an option for a repository to require target
+// names to be unique within their scope ignoring case. Names which only
+// match exactly are always rejected.
+func WithUniqueNames(unique bool) Option {
+	return func(o *options) {
+		o.withUniqueNames = unique
+	}
+}
@@ -90,4 +90,105 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withHostSets = []string{"alice", "bob"}
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithConnectionIdleTimeoutSeconds", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithConnectionIdleTimeoutSeconds(600))
+		testOpts := getDefaultOptions()
+		testOpts.withConnectionIdleTimeout = 600
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithConnectionBandwidthLimit", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithConnectionBandwidthLimit(1 << 20))
+		testOpts := getDefaultOptions()
+		testOpts.withConnectionBandwidth = 1 << 20
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithAuthorizationValidSeconds", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithAuthorizationValidSeconds(300))
+		testOpts := getDefaultOptions()
+		testOpts.withAuthorizationValid = 300
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithAccessSchedule", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithAccessSchedule("mon-fri 09:00-17:00"))
+		testOpts := getDefaultOptions()
+		testOpts.withAccessSchedule = "mon-fri 09:00-17:00"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithTerminateSessionsAtScheduleEnd", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithTerminateSessionsAtScheduleEnd(true))
+		testOpts := getDefaultOptions()
+		testOpts.withTerminateAtScheduleEnd = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithAllowedPorts", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithAllowedPorts("5432,6000-6010"))
+		testOpts := getDefaultOptions()
+		testOpts.withAllowedPorts = "5432,6000-6010"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithSshUsername", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithSshUsername("admin"))
+		testOpts := getDefaultOptions()
+		testOpts.withSshUsername = "admin"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithSshPrivateKey", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithSshPrivateKey([]byte("key")))
+		testOpts := getDefaultOptions()
+		testOpts.withSshPrivateKey = []byte("key")
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithSshHostKey", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithSshHostKey("ssh-ed25519 AAAA"))
+		testOpts := getDefaultOptions()
+		testOpts.withSshHostKey = "ssh-ed25519 AAAA"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithHostSelectionStrategy", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithHostSelectionStrategy(StickyHostSelection))
+		testOpts := getDefaultOptions()
+		testOpts.withHostSelectionStrategy = StickyHostSelection
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithEndpointTls", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(
+			WithEndpointTls(true),
+			WithTlsServerName("db.example.com"),
+			WithTlsCaBundle([]byte("ca")),
+			WithTlsClientCertificate("cert"),
+			WithTlsClientPrivateKey([]byte("key")),
+		)
+		testOpts := getDefaultOptions()
+		testOpts.withEndpointTls = true
+		testOpts.withTlsServerName = "db.example.com"
+		testOpts.withTlsCaBundle = []byte("ca")
+		testOpts.withTlsClientCertificate = "cert"
+		testOpts.withTlsClientPrivateKey = []byte("key")
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithUniqueNames", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithUniqueNames(true))
+		testOpts := getDefaultOptions()
+		testOpts.withUniqueNames = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithOrder", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithOrder("create_time desc"))
+		testOpts := getDefaultOptions()
+		testOpts.withOrder = "create_time desc"
+		assert.Equal(opts, testOpts)
+	})
 }
@@ -0,0 +1,83 @@
+package target
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// maxPortRanges is the most ports and ranges of ports a target can allow.
+const maxPortRanges = 64
+
+// PortRanges are the destination ports, besides its default port, which
+// connections to a target's hosts can be made to. Its text form is a comma
+// separated list of ports and inclusive ranges of ports:
+//
+//	5432,5433,6000-6010
+type PortRanges []portRange
+
+type portRange struct {
+	first, last uint32
+}
+
+// ParsePortRanges parses the text form of a target's allowed ports.
+func ParsePortRanges(s string) (PortRanges, error) {
+	var ranges PortRanges
+	for _, r := range strings.Split(s, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		pr, err := parsePortRange(r)
+		if err != nil {
+			return nil, fmt.Errorf("parse port ranges: %q: %s: %w", r, err, db.ErrInvalidParameter)
+		}
+		ranges = append(ranges, pr)
+	}
+	switch {
+	case len(ranges) == 0:
+		return nil, fmt.Errorf("parse port ranges: no ports: %w", db.ErrInvalidParameter)
+	case len(ranges) > maxPortRanges:
+		return nil, fmt.Errorf("parse port ranges: more than %d ports and ranges: %w", maxPortRanges, db.ErrInvalidParameter)
+	}
+	return ranges, nil
+}
+
+func parsePortRange(s string) (portRange, error) {
+	first, last := s, s
+	if i := strings.Index(s, "-"); i >= 0 {
+		first, last = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+	}
+	var pr portRange
+	var err error
+	if pr.first, err = parsePort(first); err != nil {
+		return portRange{}, err
+	}
+	if pr.last, err = parsePort(last); err != nil {
+		return portRange{}, err
+	}
+	if pr.last < pr.first {
+		return portRange{}, fmt.Errorf("range ends before it starts")
+	}
+	return pr, nil
+}
+
+func parsePort(s string) (uint32, error) {
+	p, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || p == 0 {
+		return 0, fmt.Errorf("ports must be between 1 and 65535")
+	}
+	return uint32(p), nil
+}
+
+// Contains returns true if port is one of the ports in r.
+func (r PortRanges) Contains(port uint32) bool {
+	for _, pr := range r {
+		if port >= pr.first && port <= pr.last {
+			return true
+		}
+	}
+	return false
+}
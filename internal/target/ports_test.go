@@ -0,0 +1,65 @@
+package target
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortRanges(t *testing.T) {
+	t.Parallel()
+	tooMany := make([]string, maxPortRanges+1)
+	for i := range tooMany {
+		tooMany[i] = strconv.Itoa(i + 1)
+	}
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{name: "single", in: "5432"},
+		{name: "list", in: "5432,5433"},
+		{name: "range", in: "6000-6010"},
+		{name: "mixed", in: " 5432, 6000 - 6010 ,"},
+		{name: "single-port-range", in: "22-22"},
+		{name: "full-range", in: "1-65535"},
+		{name: "empty", in: "", wantErr: true},
+		{name: "only-separators", in: ",,", wantErr: true},
+		{name: "zero", in: "0", wantErr: true},
+		{name: "too-large", in: "65536", wantErr: true},
+		{name: "negative", in: "-22", wantErr: true},
+		{name: "not-a-number", in: "ssh", wantErr: true},
+		{name: "open-range", in: "6000-", wantErr: true},
+		{name: "reversed-range", in: "6010-6000", wantErr: true},
+		{name: "too-many", in: strings.Join(tooMany, ","), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePortRanges(tt.in)
+			if tt.wantErr {
+				assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, got)
+		})
+	}
+}
+
+func TestPortRanges_Contains(t *testing.T) {
+	t.Parallel()
+	r, err := ParsePortRanges("5432,5433,6000-6010")
+	require.NoError(t, err)
+	for _, p := range []uint32{5432, 5433, 6000, 6005, 6010} {
+		assert.Truef(t, r.Contains(p), "port %d", p)
+	}
+	for _, p := range []uint32{0, 5431, 5434, 5999, 6011, 65536} {
+		assert.Falsef(t, r.Contains(p), "port %d", p)
+	}
+}
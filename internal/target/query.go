@@ -0,0 +1,61 @@
+package target
+
+const (
+	// conflictingNameQuery returns the target, other than the one with the
+	// id $2, whose name matches $3 ignoring case within the scope $1, or
+	// within the scope of the target with the id $2 if $1 is empty. A target
+	// whose name matches exactly is preferred.
+	conflictingNameQuery = `
+select public_id, scope_id
+  from target_all_subtypes
+ where scope_id = coalesce(nullif($1, ''), (select scope_id from target where public_id = $2))
+   and public_id != $2
+   and lower(name) = lower($3)
+ order by name = $3 desc
+ limit 1;
+`
+
+	// nextRoundRobinQuery counts a host chosen by the round robin strategy of
+	// the target $1 and returns the count of hosts chosen, including it.
+	nextRoundRobinQuery = `
+insert into target_round_robin
+  (target_id, host_count, update_time)
+values
+  ($1, 1, now())
+on conflict (target_id)
+do update set
+  host_count = target_round_robin.host_count + 1,
+  update_time = now()
+returning host_count;
+`
+
+	// lookupHostAffinityQuery returns the host set and host the user $2 was
+	// last given by the sticky strategy of the target $1.
+	lookupHostAffinityQuery = `
+select host_set_id, host_id, create_time, update_time
+  from target_host_affinity
+ where target_id = $1
+   and user_id = $2;
+`
+
+	// setHostAffinityQuery records the host set $3 and host $4 as the ones
+	// the user $2 was last given by the sticky strategy of the target $1.
+	setHostAffinityQuery = `
+insert into target_host_affinity
+  (target_id, user_id, host_set_id, host_id)
+values
+  ($1, $2, $3, $4)
+on conflict (target_id, user_id)
+do update set
+  host_set_id = excluded.host_set_id,
+  host_id = excluded.host_id,
+  update_time = now();
+`
+
+	// countTargetsQuery counts the targets of all types in the project $1.
+	countTargetsQuery = `
+select count(*)
+  from target
+ where scope_id = $1;
+`
+)
@@ -2,11 +2,11 @@ package target
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
 )
@@ -28,10 +28,15 @@ type Repository struct {
 
 	// defaultLimit provides a default for limiting the number of results returned from the repo
 	defaultLimit int
+
+	// uniqueNames is true if target names must be unique within their scope
+	// ignoring case, rather than only when compared exactly.
+	uniqueNames bool
 }
 
 // NewRepository creates a new target Repository. Supports the options: WithLimit
-// which sets a default limit on results returned by repo operations.
+// which sets a default limit on results returned by repo operations, and
+// WithUniqueNames.
 func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repository, error) {
 	if r == nil {
 		return nil, errors.New("error creating db repository with nil reader")
@@ -52,6 +57,7 @@ func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repo
 		writer:       w,
 		kms:          kms,
 		defaultLimit: opts.withLimit,
+		uniqueNames:  opts.withUniqueNames,
 	}, nil
 }
 
@@ -104,7 +110,7 @@ func fetchSets(ctx context.Context, r db.Reader, targetId string) ([]*TargetSet,
 	return hostSets, nil
 }
 
-// ListTargets in targets in a scope.  Supports the WithScopeId, WithLimit, WithTargetType options.
+// ListTargets in targets in a scope.  Supports the WithScopeId, WithLimit, WithOrder, WithTargetType options.
 func (r *Repository) ListTargets(ctx context.Context, opt ...Option) ([]Target, error) {
 	opts := getOpts(opt...)
 	if opts.withScopeId == "" && opts.withUserId == "" {
@@ -138,8 +144,31 @@ func (r *Repository) ListTargets(ctx context.Context, opt ...Option) ([]Target,
 	return targets, nil
 }
 
-// list will return a listing of resources and honor the WithLimit option or the
-// repo defaultLimit
+// CountTargets returns the number of targets in the project with id
+// withScopeId. All options are ignored.
+func (r *Repository) CountTargets(ctx context.Context, withScopeId string, opt ...Option) (int, error) {
+	if withScopeId == "" {
+		return 0, fmt.Errorf("count targets: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, countTargetsQuery, []interface{}{withScopeId})
+	if err != nil {
+		return 0, fmt.Errorf("count targets: %w", err)
+	}
+	defer rows.Close()
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 0, fmt.Errorf("count targets: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("count targets: %w", err)
+	}
+	return count, nil
+}
+
+// list will return a listing of resources and honor the WithOrder option and
+// the WithLimit option or the repo defaultLimit
 func (r *Repository) list(ctx context.Context, resources interface{}, where string, args []interface{}, opt ...Option) error {
 	opts := getOpts(opt...)
 	limit := r.defaultLimit
@@ -148,7 +177,7 @@ func (r *Repository) list(ctx context.Context, resources interface{}, where stri
 		// non-zero signals an override of the default limit for the repo.
 		limit = opts.withLimit
 	}
-	dbOpts = append(dbOpts, db.WithLimit(limit))
+	dbOpts = append(dbOpts, db.WithLimit(limit), db.WithOrder(opts.withOrder))
 	return r.reader.SearchWhere(ctx, resources, where, args, dbOpts...)
 }
 
@@ -217,6 +246,7 @@ func (r *Repository) update(ctx context.Context, target Target, version uint32,
 	}
 	dbOpts := []db.Option{
 		db.WithVersion(&version),
+		db.WithWrapperFn(r.kms.DatabaseWrapperFn()),
 	}
 	scopeId := target.GetScopeId()
 	if scopeId == "" {
@@ -324,6 +354,9 @@ func (r *Repository) AddTargetHostSets(ctx context.Context, targetId string, tar
 			if err != nil {
 				return fmt.Errorf("add target host sets: unable to get ticket: %w", err)
 			}
+			if err := db.CheckScope(ctx, reader, t.GetScopeId(), hostSetIds...); err != nil {
+				return fmt.Errorf("add target host sets: %w", err)
+			}
 			updatedTarget = target.(Cloneable).Clone()
 			var targetOplogMsg oplog.Message
 			rowsUpdated, err := w.Update(ctx, updatedTarget, []string{"Version"}, nil, db.NewOplogMsg(&targetOplogMsg), db.WithVersion(&targetVersion))
@@ -481,6 +514,7 @@ func (r *Repository) SetTargetHostSets(ctx context.Context, targetId string, tar
 		found[s.PublicId] = s
 	}
 	addHostSets := make([]interface{}, 0, len(hostSetIds))
+	addHostSetIds := make([]string, 0, len(hostSetIds))
 	for _, id := range hostSetIds {
 		if _, ok := found[id]; ok {
 			// found a match, so do nothing (we want to keep it), but remove it
@@ -493,6 +527,7 @@ func (r *Repository) SetTargetHostSets(ctx context.Context, targetId string, tar
 			return nil, db.NoRowsAffected, fmt.Errorf("set target host set: unable to create in memory target host set: %w", err)
 		}
 		addHostSets = append(addHostSets, hs)
+		addHostSetIds = append(addHostSetIds, id)
 	}
 	deleteHostSets := make([]interface{}, 0, len(hostSetIds))
 	if len(found) > 0 {
@@ -532,6 +567,9 @@ func (r *Repository) SetTargetHostSets(ctx context.Context, targetId string, tar
 		db.StdRetryCnt,
 		db.ExpBackoff{},
 		func(reader db.Reader, w db.Writer) error {
+			if err := db.CheckScope(ctx, reader, t.GetScopeId(), addHostSetIds...); err != nil {
+				return fmt.Errorf("set target host sets: %w", err)
+			}
 			msgs := make([]*oplog.Message, 0, 2)
 			targetTicket, err := w.GetTicket(target)
 			if err != nil {
@@ -589,3 +627,52 @@ func (r *Repository) SetTargetHostSets(ctx context.Context, targetId string, tar
 	}
 	return currentHostSets, totalRowsAffected, nil
 }
+
+// conflictingName returns a NotUnique error if a target other than the one
+// with publicId has the name within the scope, ignoring case. If scopeId is
+// empty the scope of the target with publicId is used. It returns nil if
+// there is no such target.
+func conflictingName(ctx context.Context, r db.Reader, scopeId, publicId, name string) error {
+	if name == "" {
+		return nil
+	}
+	rows, err := r.Query(ctx, conflictingNameQuery, []interface{}{scopeId, publicId, name})
+	if err != nil {
+		return fmt.Errorf("conflicting name: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var conflictingId, conflictingScopeId string
+		if err := rows.Scan(&conflictingId, &conflictingScopeId); err != nil {
+			return fmt.Errorf("conflicting name: %w", err)
+		}
+		return &errors.NotUnique{
+			Resource:      "target",
+			Name:          name,
+			ScopeId:       conflictingScopeId,
+			ConflictingId: conflictingId,
+		}
+	}
+	return rows.Err()
+}
+
+// containsField returns true if the field is one of the fields.
+// removeField returns fields without field.
+func removeField(fields []string, field string) []string {
+	var out []string
+	for _, f := range fields {
+		if !strings.EqualFold(f, field) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,7 @@ import (
 	dbcommon "github.com/hashicorp/boundary/internal/db/common"
 
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
 )
@@ -31,6 +32,37 @@ func (r *Repository) CreateTcpTarget(ctx context.Context, target *TcpTarget, opt
 	if target.PublicId != "" {
 		return nil, nil, fmt.Errorf("create tcp target: public id not empty: %w", db.ErrInvalidParameter)
 	}
+	if target.AccessSchedule != "" {
+		if _, err := ParseAccessSchedule(target.AccessSchedule); err != nil {
+			return nil, nil, fmt.Errorf("create tcp target: %w", err)
+		}
+	}
+	if target.AllowedPorts != "" {
+		if _, err := ParsePortRanges(target.AllowedPorts); err != nil {
+			return nil, nil, fmt.Errorf("create tcp target: %w", err)
+		}
+	}
+	if len(target.SshPrivateKey) > 0 {
+		if err := ValidateSshPrivateKey(target.SshPrivateKey); err != nil {
+			return nil, nil, fmt.Errorf("create tcp target: %w", err)
+		}
+	}
+	if target.SshHostKey != "" {
+		if err := ValidateSshHostKey(target.SshHostKey); err != nil {
+			return nil, nil, fmt.Errorf("create tcp target: %w", err)
+		}
+	}
+	if target.HostSelectionStrategy != "" && !ValidHostSelectionStrategy(target.HostSelectionStrategy) {
+		return nil, nil, fmt.Errorf("create tcp target: unknown host selection strategy %q: %w", target.HostSelectionStrategy, db.ErrInvalidParameter)
+	}
+	if len(target.TlsCaBundle) > 0 {
+		if err := ValidateTlsCaBundle(target.TlsCaBundle); err != nil {
+			return nil, nil, fmt.Errorf("create tcp target: %w", err)
+		}
+	}
+	if err := ValidateTlsClientCertificate([]byte(target.TlsClientCertificate), target.TlsClientPrivateKey); err != nil {
+		return nil, nil, fmt.Errorf("create tcp target: %w", err)
+	}
 
 	t := target.Clone().(*TcpTarget)
 
@@ -70,6 +102,14 @@ func (r *Repository) CreateTcpTarget(ctx context.Context, target *TcpTarget, opt
 		db.StdRetryCnt,
 		db.ExpBackoff{},
 		func(read db.Reader, w db.Writer) error {
+			if err := db.CheckScope(ctx, read, t.ScopeId, opts.withHostSets...); err != nil {
+				return fmt.Errorf("create tcp target: %w", err)
+			}
+			if r.uniqueNames {
+				if err := conflictingName(ctx, read, t.ScopeId, t.PublicId, t.Name); err != nil {
+					return err
+				}
+			}
 			targetTicket, err := w.GetTicket(t)
 			if err != nil {
 				return fmt.Errorf("create tcp target: unable to get ticket: %w", err)
@@ -77,7 +117,7 @@ func (r *Repository) CreateTcpTarget(ctx context.Context, target *TcpTarget, opt
 			msgs := make([]*oplog.Message, 0, 2)
 			var targetOplogMsg oplog.Message
 			returnedTarget = t.Clone()
-			if err := w.Create(ctx, returnedTarget, db.NewOplogMsg(&targetOplogMsg)); err != nil {
+			if err := w.Create(ctx, returnedTarget, db.NewOplogMsg(&targetOplogMsg), db.WithWrapperFn(r.kms.DatabaseWrapperFn())); err != nil {
 				return err
 			}
 			msgs = append(msgs, &targetOplogMsg)
@@ -99,6 +139,11 @@ func (r *Repository) CreateTcpTarget(ctx context.Context, target *TcpTarget, opt
 		},
 	)
 	if err != nil {
+		if db.IsUniqueError(err) {
+			if nuErr := conflictingName(ctx, r.reader, t.ScopeId, t.PublicId, t.Name); errors.Is(nuErr, errors.ErrNotUnique) {
+				err = nuErr
+			}
+		}
 		return nil, nil, fmt.Errorf("create tcp target: %w for %s target id id", err, t.PublicId)
 	}
 	return returnedTarget.(*TcpTarget), returnedHostSet, err
@@ -127,6 +172,56 @@ func (r *Repository) UpdateTcpTarget(ctx context.Context, target *TcpTarget, ver
 		case strings.EqualFold("defaultport", f):
 		case strings.EqualFold("sessionmaxseconds", f):
 		case strings.EqualFold("sessionconnectionlimit", f):
+		case strings.EqualFold("protocol", f):
+		case strings.EqualFold("connectionidletimeoutseconds", f):
+		case strings.EqualFold("connectionbandwidthlimit", f):
+		case strings.EqualFold("authorizationvalidseconds", f):
+		case strings.EqualFold("accessschedule", f):
+			if target.AccessSchedule != "" {
+				if _, err := ParseAccessSchedule(target.AccessSchedule); err != nil {
+					return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", err)
+				}
+			}
+		case strings.EqualFold("terminatesessionsatscheduleend", f):
+		case strings.EqualFold("allowedports", f):
+			if target.AllowedPorts != "" {
+				if _, err := ParsePortRanges(target.AllowedPorts); err != nil {
+					return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", err)
+				}
+			}
+		case strings.EqualFold("sshusername", f):
+		case strings.EqualFold("sshhostkey", f):
+			if target.SshHostKey != "" {
+				if err := ValidateSshHostKey(target.SshHostKey); err != nil {
+					return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", err)
+				}
+			}
+		case strings.EqualFold("sshprivatekey", f):
+			if len(target.SshPrivateKey) > 0 {
+				if err := ValidateSshPrivateKey(target.SshPrivateKey); err != nil {
+					return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", err)
+				}
+			}
+		case strings.EqualFold("endpointtls", f):
+		case strings.EqualFold("tlsservername", f):
+		case strings.EqualFold("tlscabundle", f):
+			if len(target.TlsCaBundle) > 0 {
+				if err := ValidateTlsCaBundle(target.TlsCaBundle); err != nil {
+					return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", err)
+				}
+			}
+		case strings.EqualFold("tlsclientcertificate", f):
+			if err := ValidateTlsClientCertificate([]byte(target.TlsClientCertificate), nil); err != nil {
+				return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", err)
+			}
+		case strings.EqualFold("tlsclientprivatekey", f):
+			if err := ValidateTlsClientCertificate(nil, target.TlsClientPrivateKey); err != nil {
+				return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", err)
+			}
+		case strings.EqualFold("hostselectionstrategy", f):
+			if target.HostSelectionStrategy != "" && !ValidHostSelectionStrategy(target.HostSelectionStrategy) {
+				return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: unknown host selection strategy %q: %w", target.HostSelectionStrategy, db.ErrInvalidParameter)
+			}
 		default:
 			return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: field: %s: %w", f, db.ErrInvalidFieldMask)
 		}
@@ -134,18 +229,49 @@ func (r *Repository) UpdateTcpTarget(ctx context.Context, target *TcpTarget, ver
 	var dbMask, nullFields []string
 	dbMask, nullFields = dbcommon.BuildUpdatePaths(
 		map[string]interface{}{
-			"Name":                   target.Name,
-			"Description":            target.Description,
-			"DefaultPort":            target.DefaultPort,
-			"SessionMaxSeconds":      target.SessionMaxSeconds,
-			"SessionConnectionLimit": target.SessionConnectionLimit,
+			"Name":                           target.Name,
+			"Description":                    target.Description,
+			"DefaultPort":                    target.DefaultPort,
+			"SessionMaxSeconds":              target.SessionMaxSeconds,
+			"SessionConnectionLimit":         target.SessionConnectionLimit,
+			"Protocol":                       target.Protocol,
+			"ConnectionIdleTimeoutSeconds":   target.ConnectionIdleTimeoutSeconds,
+			"ConnectionBandwidthLimit":       target.ConnectionBandwidthLimit,
+			"AccessSchedule":                 target.AccessSchedule,
+			"TerminateSessionsAtScheduleEnd": target.TerminateSessionsAtScheduleEnd,
+			"AllowedPorts":                   target.AllowedPorts,
+			"SshUsername":                    target.SshUsername,
+			"SshHostKey":                     target.SshHostKey,
+			"SshPrivateKey":                  target.SshPrivateKey,
+			"HostSelectionStrategy":          target.HostSelectionStrategy,
+			"EndpointTls":                    target.EndpointTls,
+			"TlsServerName":                  target.TlsServerName,
+			"TlsCaBundle":                    target.TlsCaBundle,
+			"TlsClientCertificate":           target.TlsClientCertificate,
+			"TlsClientPrivateKey":            target.TlsClientPrivateKey,
+			"AuthorizationValidSeconds":      target.AuthorizationValidSeconds,
 		},
 		fieldMaskPaths,
-		[]string{"SessionMaxSeconds", "SessionConnectionLimit"},
+		[]string{"SessionMaxSeconds", "SessionConnectionLimit", "ConnectionIdleTimeoutSeconds", "ConnectionBandwidthLimit", "TerminateSessionsAtScheduleEnd", "EndpointTls", "AuthorizationValidSeconds"},
 	)
 	if len(dbMask) == 0 && len(nullFields) == 0 {
 		return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", db.ErrEmptyFieldMask)
 	}
+	// The private keys and CA bundle are encrypted with the database key of
+	// the target's scope when they're written, so the scope must be known.
+	target = target.Clone().(*TcpTarget)
+	var encrypted bool
+	for _, f := range []string{"SshPrivateKey", "TlsCaBundle", "TlsClientPrivateKey"} {
+		encrypted = encrypted || containsField(dbMask, f)
+	}
+	if encrypted && target.ScopeId == "" {
+		tv := allocTargetView()
+		tv.PublicId = target.PublicId
+		if err := r.reader.LookupByPublicId(ctx, &tv); err != nil {
+			return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: lookup failed %w for %s", err, target.PublicId)
+		}
+		target.ScopeId = tv.ScopeId
+	}
 	var returnedTarget Target
 	var rowsUpdated int
 	var targetSets []*TargetSet
@@ -156,6 +282,11 @@ func (r *Repository) UpdateTcpTarget(ctx context.Context, target *TcpTarget, ver
 		func(read db.Reader, w db.Writer) error {
 			var err error
 			t := target.Clone().(*TcpTarget)
+			if r.uniqueNames && containsField(dbMask, "Name") {
+				if err := conflictingName(ctx, read, t.ScopeId, t.PublicId, t.Name); err != nil {
+					return err
+				}
+			}
 			returnedTarget, targetSets, rowsUpdated, err = r.update(ctx, t, version, dbMask, nullFields)
 			if err != nil {
 				return err
@@ -165,8 +296,14 @@ func (r *Repository) UpdateTcpTarget(ctx context.Context, target *TcpTarget, ver
 	)
 	if err != nil {
 		if db.IsUniqueError(err) {
+			if nuErr := conflictingName(ctx, r.reader, target.ScopeId, target.PublicId, target.Name); errors.Is(nuErr, errors.ErrNotUnique) {
+				return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", nuErr)
+			}
 			return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: target %s already exists in scope %s: %w", target.Name, target.ScopeId, db.ErrNotUnique)
 		}
+		if errors.Is(err, errors.ErrNotUnique) {
+			return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", err)
+		}
 		return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w for %s", err, target.PublicId)
 	}
 	return returnedTarget.(Target), targetSets, rowsUpdated, err
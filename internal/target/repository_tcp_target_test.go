@@ -2,12 +2,12 @@ package target
 
 import (
 	"context"
-	"errors"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	dbassert "github.com/hashicorp/boundary/internal/db/assert"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
@@ -149,6 +149,71 @@ func TestRepository_CreateTcpTarget(t *testing.T) {
 	}
 }
 
+func TestRepository_UniqueTcpTargetNames(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	_, otherProj := iam.TestScopes(t, iamRepo)
+	existing := TestTcpTarget(t, conn, proj.PublicId, "Web")
+
+	t.Run("exact-match", func(t *testing.T) {
+		require, assert := require.New(t), assert.New(t)
+		repo, err := NewRepository(rw, rw, testKms)
+		require.NoError(err)
+		tar, err := NewTcpTarget(proj.PublicId, WithName("Web"))
+		require.NoError(err)
+		_, _, err = repo.CreateTcpTarget(context.Background(), tar)
+		require.Error(err)
+		var nuErr *errors.NotUnique
+		require.True(errors.As(err, &nuErr))
+		assert.Equal(existing.PublicId, nuErr.ConflictingId)
+		assert.Equal(proj.PublicId, nuErr.ScopeId)
+		assert.True(errors.Is(err, db.ErrNotUnique))
+	})
+	t.Run("case-only-without-flag", func(t *testing.T) {
+		require := require.New(t)
+		repo, err := NewRepository(rw, rw, testKms)
+		require.NoError(err)
+		tar, err := NewTcpTarget(proj.PublicId, WithName("WEB"))
+		require.NoError(err)
+		_, _, err = repo.CreateTcpTarget(context.Background(), tar)
+		require.NoError(err)
+	})
+	t.Run("case-only-with-flag", func(t *testing.T) {
+		require, assert := require.New(t), assert.New(t)
+		repo, err := NewRepository(rw, rw, testKms, WithUniqueNames(true))
+		require.NoError(err)
+		tar, err := NewTcpTarget(proj.PublicId, WithName("web"))
+		require.NoError(err)
+		_, _, err = repo.CreateTcpTarget(context.Background(), tar)
+		require.Error(err)
+		var nuErr *errors.NotUnique
+		require.True(errors.As(err, &nuErr))
+		assert.Equal(existing.PublicId, nuErr.ConflictingId)
+
+		other := TestTcpTarget(t, conn, proj.PublicId, "other")
+		update := other.Clone().(*TcpTarget)
+		update.Name = "wEb"
+		_, _, _, err = repo.UpdateTcpTarget(context.Background(), update, other.Version, []string{"Name"})
+		require.Error(err)
+		require.True(errors.As(err, &nuErr))
+		assert.Equal(existing.PublicId, nuErr.ConflictingId)
+	})
+	t.Run("other-scope-with-flag", func(t *testing.T) {
+		require := require.New(t)
+		repo, err := NewRepository(rw, rw, testKms, WithUniqueNames(true))
+		require.NoError(err)
+		tar, err := NewTcpTarget(otherProj.PublicId, WithName("web"))
+		require.NoError(err)
+		_, _, err = repo.CreateTcpTarget(context.Background(), tar)
+		require.NoError(err)
+	})
+}
+
 func TestRepository_UpdateTcpTarget(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -202,6 +202,37 @@ func TestRepository_ListTargets(t *testing.T) {
 	}
 }
 
+func TestRepository_CountTargets(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	_, otherProj := iam.TestScopes(t, iamRepo)
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = repo.CountTargets(ctx, "")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+
+	got, err := repo.CountTargets(ctx, proj.PublicId)
+	require.NoError(t, err)
+	assert.Equal(t, 0, got)
+
+	for i := 0; i < 3; i++ {
+		TestTcpTarget(t, conn, proj.PublicId, strconv.Itoa(i))
+	}
+	TestTcpTarget(t, conn, otherProj.PublicId, "other")
+
+	got, err = repo.CountTargets(ctx, proj.PublicId)
+	require.NoError(t, err)
+	assert.Equal(t, 3, got)
+}
+
 func TestRepository_DeleteTarget(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -0,0 +1,208 @@
+package target
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// maxScheduleWindows is the most windows an access schedule can have.
+const maxScheduleWindows = 64
+
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// AccessSchedule is the set of weekly windows during which sessions can be
+// authorized for a target. Its cron-like text form is an optional time zone
+// followed by windows separated by semicolons:
+//
+//	TZ=America/New_York mon-fri 09:00-17:00; sat 10:00-14:00
+//
+// Each window is a comma separated list of days or ranges of days, or * for
+// every day, and a time range. A window ending at or before its start time
+// ends on the following day, so "fri 22:00-02:00" runs from Friday night to
+// Saturday morning. Times are in the schedule's time zone, which is UTC if
+// not given.
+type AccessSchedule struct {
+	loc     *time.Location
+	windows []scheduleWindow
+}
+
+type scheduleWindow struct {
+	days       [7]bool
+	start, end int // minutes after midnight
+}
+
+// ParseAccessSchedule parses the text form of an access schedule.
+func ParseAccessSchedule(s string) (*AccessSchedule, error) {
+	s = strings.TrimSpace(s)
+	sched := &AccessSchedule{loc: time.UTC}
+	if strings.HasPrefix(s, "TZ=") {
+		fields := strings.SplitN(s, " ", 2)
+		loc, err := time.LoadLocation(strings.TrimPrefix(fields[0], "TZ="))
+		if err != nil {
+			return nil, fmt.Errorf("parse access schedule: invalid time zone: %w", db.ErrInvalidParameter)
+		}
+		sched.loc = loc
+		s = ""
+		if len(fields) == 2 {
+			s = fields[1]
+		}
+	}
+	for _, w := range strings.Split(s, ";") {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		window, err := parseScheduleWindow(w)
+		if err != nil {
+			return nil, fmt.Errorf("parse access schedule: %q: %s: %w", w, err, db.ErrInvalidParameter)
+		}
+		sched.windows = append(sched.windows, window)
+	}
+	switch {
+	case len(sched.windows) == 0:
+		return nil, fmt.Errorf("parse access schedule: no windows: %w", db.ErrInvalidParameter)
+	case len(sched.windows) > maxScheduleWindows:
+		return nil, fmt.Errorf("parse access schedule: more than %d windows: %w", maxScheduleWindows, db.ErrInvalidParameter)
+	}
+	return sched, nil
+}
+
+func parseScheduleWindow(s string) (scheduleWindow, error) {
+	var w scheduleWindow
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return w, fmt.Errorf("want days and a time range")
+	}
+	if fields[0] == "*" {
+		for i := range w.days {
+			w.days[i] = true
+		}
+	} else {
+		for _, r := range strings.Split(strings.ToLower(fields[0]), ",") {
+			first, last := r, r
+			if i := strings.Index(r, "-"); i >= 0 {
+				first, last = r[:i], r[i+1:]
+			}
+			from, ok := scheduleDays[first]
+			if !ok {
+				return w, fmt.Errorf("unknown day %q", first)
+			}
+			to, ok := scheduleDays[last]
+			if !ok {
+				return w, fmt.Errorf("unknown day %q", last)
+			}
+			for d := from; ; d = (d + 1) % 7 {
+				w.days[d] = true
+				if d == to {
+					break
+				}
+			}
+		}
+	}
+	times := strings.Split(fields[1], "-")
+	if len(times) != 2 {
+		return w, fmt.Errorf("want a time range like 09:00-17:00")
+	}
+	var err error
+	if w.start, err = parseScheduleTime(times[0], false); err != nil {
+		return w, err
+	}
+	if w.end, err = parseScheduleTime(times[1], true); err != nil {
+		return w, err
+	}
+	if w.start == w.end {
+		return w, fmt.Errorf("window starts and ends at the same time")
+	}
+	return w, nil
+}
+
+// parseScheduleTime parses an HH:MM time into minutes after midnight. 24:00
+// is only allowed as an end time.
+func parseScheduleTime(s string, end bool) (int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	switch {
+	case end && h == 24 && m == 0:
+	case h < 0 || h > 23 || m < 0 || m > 59:
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// WindowEnd returns when the window of the schedule containing t ends, and
+// false if t isn't in any window. Windows which overlap or follow on from
+// each other are treated as one window.
+func (s *AccessSchedule) WindowEnd(t time.Time) (time.Time, bool) {
+	end, ok := s.windowEnd(t)
+	if !ok {
+		return time.Time{}, false
+	}
+	// Follow on into adjacent windows, such as mon-fri 00:00-24:00, for at
+	// most a week since a schedule covering every day has no end.
+	limit := t.AddDate(0, 0, 7)
+	for end.Before(limit) {
+		next, ok := s.windowEnd(end)
+		if !ok || !next.After(end) {
+			break
+		}
+		end = next
+	}
+	return end, true
+}
+
+// windowEnd returns the latest end of the windows containing t.
+func (s *AccessSchedule) windowEnd(t time.Time) (time.Time, bool) {
+	t = t.In(s.loc)
+	var end time.Time
+	var found bool
+	// A window starting yesterday can run past midnight into today.
+	for _, offset := range []int{-1, 0} {
+		day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, s.loc)
+		for _, w := range s.windows {
+			if !w.days[day.Weekday()] {
+				continue
+			}
+			ws := time.Date(day.Year(), day.Month(), day.Day(), w.start/60, w.start%60, 0, 0, s.loc)
+			endDay := day
+			if w.end <= w.start {
+				endDay = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, s.loc)
+			}
+			we := time.Date(endDay.Year(), endDay.Month(), endDay.Day(), w.end/60, w.end%60, 0, 0, s.loc)
+			if t.Before(ws) || !t.Before(we) {
+				continue
+			}
+			if !found || we.After(end) {
+				end, found = we, true
+			}
+		}
+	}
+	return end, found
+}
+
+// Contains reports whether t is in a window of the schedule.
+func (s *AccessSchedule) Contains(t time.Time) bool {
+	_, ok := s.windowEnd(t)
+	return ok
+}
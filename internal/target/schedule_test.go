@@ -0,0 +1,147 @@
+package target
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccessSchedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{name: "weekdays", in: "mon-fri 09:00-17:00"},
+		{name: "time-zone", in: "TZ=America/New_York mon-fri 09:00-17:00; sat 10:00-14:00"},
+		{name: "every-day", in: "* 00:00-24:00"},
+		{name: "day-list", in: "Mon,wed,fri-sun 22:00-02:00"},
+		{name: "trailing-separator", in: "mon 09:00-17:00;"},
+		{name: "empty", in: "", wantErr: true},
+		{name: "only-time-zone", in: "TZ=UTC", wantErr: true},
+		{name: "unknown-time-zone", in: "TZ=Nowhere/Special mon 09:00-17:00", wantErr: true},
+		{name: "unknown-day", in: "monday 09:00-17:00", wantErr: true},
+		{name: "missing-times", in: "mon-fri", wantErr: true},
+		{name: "bad-time", in: "mon 9:00-17:00", wantErr: true},
+		{name: "out-of-range", in: "mon 09:00-25:00", wantErr: true},
+		{name: "start-at-24", in: "mon 24:00-02:00", wantErr: true},
+		{name: "zero-length", in: "mon 09:00-09:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAccessSchedule(tt.in)
+			if tt.wantErr {
+				assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, got)
+		})
+	}
+}
+
+func TestAccessSchedule_WindowEnd(t *testing.T) {
+	t.Parallel()
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	at := func(loc *time.Location, day, hour, min int) time.Time {
+		// 2021-03-01 is a Monday
+		return time.Date(2021, 3, day, hour, min, 0, 0, loc)
+	}
+	tests := []struct {
+		name     string
+		schedule string
+		at       time.Time
+		wantEnd  time.Time
+		wantOk   bool
+	}{
+		{
+			name:     "inside",
+			schedule: "mon-fri 09:00-17:00",
+			at:       at(time.UTC, 1, 12, 0),
+			wantEnd:  at(time.UTC, 1, 17, 0),
+			wantOk:   true,
+		},
+		{
+			name:     "at-start",
+			schedule: "mon-fri 09:00-17:00",
+			at:       at(time.UTC, 1, 9, 0),
+			wantEnd:  at(time.UTC, 1, 17, 0),
+			wantOk:   true,
+		},
+		{
+			name:     "at-end",
+			schedule: "mon-fri 09:00-17:00",
+			at:       at(time.UTC, 1, 17, 0),
+		},
+		{
+			name:     "weekend",
+			schedule: "mon-fri 09:00-17:00",
+			at:       at(time.UTC, 6, 12, 0),
+		},
+		{
+			name:     "time-zone",
+			schedule: "TZ=America/New_York mon-fri 09:00-17:00",
+			at:       at(ny, 1, 16, 0).UTC(),
+			wantEnd:  at(ny, 1, 17, 0),
+			wantOk:   true,
+		},
+		{
+			name:     "time-zone-outside",
+			schedule: "TZ=America/New_York mon-fri 09:00-17:00",
+			at:       at(time.UTC, 1, 12, 0),
+		},
+		{
+			name:     "overnight-after-midnight",
+			schedule: "fri 22:00-02:00",
+			at:       at(time.UTC, 6, 1, 0),
+			wantEnd:  at(time.UTC, 6, 2, 0),
+			wantOk:   true,
+		},
+		{
+			name:     "overnight-wrong-day",
+			schedule: "fri 22:00-02:00",
+			at:       at(time.UTC, 5, 1, 0),
+		},
+		{
+			name:     "adjacent-windows",
+			schedule: "mon 09:00-12:00; mon 12:00-17:00",
+			at:       at(time.UTC, 1, 10, 0),
+			wantEnd:  at(time.UTC, 1, 17, 0),
+			wantOk:   true,
+		},
+		{
+			name:     "consecutive-days",
+			schedule: "mon-wed 00:00-24:00",
+			at:       at(time.UTC, 1, 10, 0),
+			wantEnd:  at(time.UTC, 4, 0, 0),
+			wantOk:   true,
+		},
+		{
+			name:     "always",
+			schedule: "* 00:00-24:00",
+			at:       at(time.UTC, 1, 10, 0),
+			wantEnd:  at(time.UTC, 9, 0, 0),
+			wantOk:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			s, err := ParseAccessSchedule(tt.schedule)
+			require.NoError(err)
+			end, ok := s.WindowEnd(tt.at)
+			assert.Equal(tt.wantOk, ok)
+			assert.Equal(tt.wantOk, s.Contains(tt.at))
+			if tt.wantOk {
+				assert.True(tt.wantEnd.Equal(end), "want %s got %s", tt.wantEnd, end)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package target
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// SshProtocol is the protocol of tcp targets whose sessions are proxied by
+// workers which authenticate to the target's hosts with the target's
+// SshCredential.
+const SshProtocol = "ssh"
+
+// SshCredential is the credential an ssh target brokers to the workers
+// proxying its sessions. Users connecting through the worker never see it.
+type SshCredential struct {
+	// Username is the user the worker authenticates to the host as.
+	Username string
+	// PrivateKey is the PEM encoded private key the worker authenticates
+	// with.
+	PrivateKey []byte
+	// HostKey is the public key, in authorized_keys format, the host must
+	// present. If empty, the host key isn't checked.
+	HostKey string
+}
+
+// ValidateSshPrivateKey returns an error if key isn't an unencrypted PEM
+// encoded private key, as the private key of an ssh target must be.
+func ValidateSshPrivateKey(key []byte) error {
+	if _, err := ssh.ParsePrivateKey(key); err != nil {
+		return fmt.Errorf("invalid ssh private key: %w", db.ErrInvalidParameter)
+	}
+	return nil
+}
+
+// ValidateSshHostKey returns an error if key isn't a public key in
+// authorized_keys format, as the host key of an ssh target must be.
+func ValidateSshHostKey(key string) error {
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key)); err != nil {
+		return fmt.Errorf("invalid ssh host key: %w", db.ErrInvalidParameter)
+	}
+	return nil
+}
+
+// LookupSshCredential returns the ssh credential of the tcp target with id
+// targetId. It returns nil, nil if the target doesn't exist or has no
+// username and private key. All options are ignored.
+func (r *Repository) LookupSshCredential(ctx context.Context, targetId string, opt ...Option) (*SshCredential, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup ssh credential: missing target id: %w", db.ErrInvalidParameter)
+	}
+	t := allocTcpTarget()
+	t.PublicId = targetId
+	if err := r.reader.LookupByPublicId(ctx, &t, db.WithWrapperFn(r.kms.DatabaseWrapperFn())); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup ssh credential: %s: %w", targetId, err)
+	}
+	if t.SshUsername == "" || len(t.SshPrivateKey) == 0 {
+		return nil, nil
+	}
+	return &SshCredential{
+		Username:   t.SshUsername,
+		PrivateKey: t.SshPrivateKey,
+		HostKey:    t.SshHostKey,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package target
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func testSshKeys(t *testing.T) ([]byte, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	pub, err := ssh.NewPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}),
+		string(ssh.MarshalAuthorizedKey(pub))
+}
+
+func TestValidateSshKeys(t *testing.T) {
+	t.Parallel()
+	privateKey, hostKey := testSshKeys(t)
+
+	t.Run("private-key", func(t *testing.T) {
+		assert.NoError(t, ValidateSshPrivateKey(privateKey))
+		for _, k := range [][]byte{nil, []byte("not a key"), []byte(hostKey)} {
+			err := ValidateSshPrivateKey(k)
+			assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		}
+	})
+	t.Run("host-key", func(t *testing.T) {
+		assert.NoError(t, ValidateSshHostKey(hostKey))
+		for _, k := range []string{"", "not a key", string(privateKey)} {
+			err := ValidateSshHostKey(k)
+			assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		}
+	})
+}
+
+func TestTcpTarget_EncryptSsh(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	wrapper := db.TestWrapper(t)
+	fn := func(context.Context, string, string) (wrapping.Wrapper, error) { return wrapper, nil }
+	privateKey, _ := testSshKeys(t)
+
+	tar := allocTcpTarget()
+	tar.ScopeId = "p_1234567890"
+	tar.SshPrivateKey = privateKey
+	require.NoError(t, db.Encrypt(ctx, &tar, fn))
+	assert.Empty(t, tar.SshPrivateKey)
+	assert.NotEmpty(t, tar.CtSshPrivateKey)
+	assert.Equal(t, wrapper.KeyID(), tar.KeyId)
+
+	require.NoError(t, db.Decrypt(ctx, &tar, fn))
+	assert.Equal(t, privateKey, tar.SshPrivateKey)
+}
+
+func TestRepository_LookupSshCredential(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	privateKey, hostKey := testSshKeys(t)
+	otherKey, _ := testSshKeys(t)
+
+	tar, err := NewTcpTarget(proj.PublicId, WithName("ssh"), WithProtocol(SshProtocol),
+		WithSshUsername("user"), WithSshPrivateKey(privateKey), WithSshHostKey(hostKey))
+	require.NoError(t, err)
+	created, _, err := repo.CreateTcpTarget(ctx, tar)
+	require.NoError(t, err)
+	id := created.GetPublicId()
+
+	cred, err := repo.LookupSshCredential(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, cred)
+	assert.Equal(t, "user", cred.Username)
+	assert.Equal(t, privateKey, cred.PrivateKey)
+	assert.Equal(t, hostKey, cred.HostKey)
+
+	// Updating other fields, without the target's scope, leaves the key as
+	// it was.
+	upd := allocTcpTarget()
+	upd.PublicId = id
+	upd.Name = "renamed"
+	_, _, _, err = repo.UpdateTcpTarget(ctx, &upd, created.GetVersion(), []string{"Name"})
+	require.NoError(t, err)
+	cred, err = repo.LookupSshCredential(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, privateKey, cred.PrivateKey)
+
+	upd = allocTcpTarget()
+	upd.PublicId = id
+	upd.SshPrivateKey = otherKey
+	_, _, _, err = repo.UpdateTcpTarget(ctx, &upd, created.GetVersion()+1, []string{"SshPrivateKey"})
+	require.NoError(t, err)
+	cred, err = repo.LookupSshCredential(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, otherKey, cred.PrivateKey)
+
+	upd = allocTcpTarget()
+	upd.PublicId = id
+	_, _, _, err = repo.UpdateTcpTarget(ctx, &upd, created.GetVersion()+2, []string{"SshPrivateKey"})
+	require.NoError(t, err)
+	cred, err = repo.LookupSshCredential(ctx, id)
+	require.NoError(t, err)
+	assert.Nil(t, cred)
+}
@@ -21,6 +21,19 @@ type Target interface {
 	GetUpdateTime() *timestamp.Timestamp
 	GetSessionMaxSeconds() uint32
 	GetSessionConnectionLimit() int32
+	GetProtocol() string
+	GetConnectionIdleTimeoutSeconds() uint32
+	GetConnectionBandwidthLimit() uint32
+	GetAccessSchedule() string
+	GetTerminateSessionsAtScheduleEnd() bool
+	GetAllowedPorts() string
+	GetSshUsername() string
+	GetSshHostKey() string
+	GetHostSelectionStrategy() string
+	GetEndpointTls() bool
+	GetTlsServerName() string
+	GetTlsClientCertificate() string
+	GetAuthorizationValidSeconds() uint32
 	oplog(op oplog.OpType) oplog.Metadata
 }
 
@@ -92,6 +105,19 @@ func (t *targetView) targetSubType() (Target, error) {
 		tcpTarget.Version = t.Version
 		tcpTarget.SessionMaxSeconds = t.SessionMaxSeconds
 		tcpTarget.SessionConnectionLimit = t.SessionConnectionLimit
+		tcpTarget.Protocol = t.Protocol
+		tcpTarget.ConnectionIdleTimeoutSeconds = t.ConnectionIdleTimeoutSeconds
+		tcpTarget.ConnectionBandwidthLimit = t.ConnectionBandwidthLimit
+		tcpTarget.AccessSchedule = t.AccessSchedule
+		tcpTarget.TerminateSessionsAtScheduleEnd = t.TerminateSessionsAtScheduleEnd
+		tcpTarget.AllowedPorts = t.AllowedPorts
+		tcpTarget.SshUsername = t.SshUsername
+		tcpTarget.SshHostKey = t.SshHostKey
+		tcpTarget.HostSelectionStrategy = t.HostSelectionStrategy
+		tcpTarget.EndpointTls = t.EndpointTls
+		tcpTarget.TlsServerName = t.TlsServerName
+		tcpTarget.TlsClientCertificate = t.TlsClientCertificate
+		tcpTarget.AuthorizationValidSeconds = t.AuthorizationValidSeconds
 		return &tcpTarget, nil
 	}
 	return nil, fmt.Errorf("%s is an unknown target subtype of %s", t.PublicId, t.Type)
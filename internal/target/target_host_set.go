@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/boundary/internal/db"
 	hostStore "github.com/hashicorp/boundary/internal/host/store"
+	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/target/store"
 	"google.golang.org/protobuf/proto"
@@ -66,6 +67,14 @@ func (t *TargetHostSet) VetForWrite(ctx context.Context, r db.Reader, opType db.
 			return fmt.Errorf("target host set: vet for write: missing host set id: %w", db.ErrInvalidParameter)
 		}
 	}
+	target := allocTcpTarget()
+	target.PublicId = t.TargetId
+	if err := r.LookupById(ctx, &target); err != nil {
+		return fmt.Errorf("target host set: vet for write: unable to look up target: %w", err)
+	}
+	if err := iam.VetScopeForWrite(ctx, r, target.ScopeId); err != nil {
+		return fmt.Errorf("target host set: vet for write: %w", err)
+	}
 	return nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/target/store"
 	"google.golang.org/protobuf/proto"
@@ -23,8 +24,14 @@ var _ Target = (*TcpTarget)(nil)
 var _ db.VetForWriter = (*TcpTarget)(nil)
 var _ oplog.ReplayableMessage = (*TcpTarget)(nil)
 
-// NewTcpTarget creates a new in memory tcp target.  WithName, WithDescription and
-// WithDefaultPort options are supported
+// NewTcpTarget creates a new in memory tcp target.  WithName, WithDescription,
+// WithDefaultPort, WithProtocol, WithConnectionIdleTimeoutSeconds,
+// WithConnectionBandwidthLimit, WithAccessSchedule,
+// WithTerminateSessionsAtScheduleEnd, WithAllowedPorts, WithSshUsername,
+// WithSshPrivateKey, WithSshHostKey, WithHostSelectionStrategy,
+// WithEndpointTls, WithTlsServerName, WithTlsCaBundle,
+// WithTlsClientCertificate, WithTlsClientPrivateKey and
+// WithAuthorizationValidSeconds options are supported
 func NewTcpTarget(scopeId string, opt ...Option) (*TcpTarget, error) {
 	opts := getOpts(opt...)
 	if scopeId == "" {
@@ -32,12 +39,28 @@ func NewTcpTarget(scopeId string, opt ...Option) (*TcpTarget, error) {
 	}
 	t := &TcpTarget{
 		TcpTarget: &store.TcpTarget{
-			ScopeId:                scopeId,
-			Name:                   opts.withName,
-			Description:            opts.withDescription,
-			DefaultPort:            opts.withDefaultPort,
-			SessionConnectionLimit: opts.withSessionConnectionLimit,
-			SessionMaxSeconds:      opts.withSessionMaxSeconds,
+			ScopeId:                        scopeId,
+			Name:                           opts.withName,
+			Description:                    opts.withDescription,
+			DefaultPort:                    opts.withDefaultPort,
+			SessionConnectionLimit:         opts.withSessionConnectionLimit,
+			SessionMaxSeconds:              opts.withSessionMaxSeconds,
+			Protocol:                       opts.withProtocol,
+			ConnectionIdleTimeoutSeconds:   opts.withConnectionIdleTimeout,
+			ConnectionBandwidthLimit:       opts.withConnectionBandwidth,
+			AccessSchedule:                 opts.withAccessSchedule,
+			TerminateSessionsAtScheduleEnd: opts.withTerminateAtScheduleEnd,
+			AllowedPorts:                   opts.withAllowedPorts,
+			SshUsername:                    opts.withSshUsername,
+			SshPrivateKey:                  opts.withSshPrivateKey,
+			SshHostKey:                     opts.withSshHostKey,
+			HostSelectionStrategy:          opts.withHostSelectionStrategy,
+			EndpointTls:                    opts.withEndpointTls,
+			TlsServerName:                  opts.withTlsServerName,
+			TlsCaBundle:                    opts.withTlsCaBundle,
+			TlsClientCertificate:           opts.withTlsClientCertificate,
+			TlsClientPrivateKey:            opts.withTlsClientPrivateKey,
+			AuthorizationValidSeconds:      opts.withAuthorizationValid,
 		},
 	}
 	return t, nil
@@ -72,6 +95,17 @@ func (t *TcpTarget) VetForWrite(ctx context.Context, r db.Reader, opType db.OpTy
 			return fmt.Errorf("tcp target vet for write: missing name id: %w", db.ErrInvalidParameter)
 		}
 	}
+	scopeId := t.ScopeId
+	if scopeId == "" {
+		found := t.Clone().(*TcpTarget)
+		if err := r.LookupById(ctx, found); err != nil {
+			return fmt.Errorf("tcp target vet for write: unable to look up scope: %w", err)
+		}
+		scopeId = found.ScopeId
+	}
+	if err := iam.VetScopeForWrite(ctx, r, scopeId); err != nil {
+		return fmt.Errorf("tcp target vet for write: %w", err)
+	}
 	return nil
 }
 
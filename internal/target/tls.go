@@ -0,0 +1,109 @@
+package target
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// EndpointTls is how the workers proxying a tcp target's connections
+// originate TLS to its hosts.
+type EndpointTls struct {
+	// ServerName is the name the host's certificate is verified against. If
+	// empty, the host's address is used.
+	ServerName string
+	// CaBundle is the PEM encoded certificates the host's certificate is
+	// verified with. If empty, the worker's system roots are used.
+	CaBundle []byte
+	// ClientCertificate and ClientPrivateKey are the PEM encoded certificate
+	// and key the worker authenticates to the host with. If empty, no client
+	// certificate is sent.
+	ClientCertificate []byte
+	ClientPrivateKey  []byte
+}
+
+// ValidateTlsCaBundle returns an error if bundle doesn't hold at least one
+// PEM encoded certificate.
+func ValidateTlsCaBundle(bundle []byte) error {
+	if !x509.NewCertPool().AppendCertsFromPEM(bundle) {
+		return fmt.Errorf("invalid tls ca bundle: %w", db.ErrInvalidParameter)
+	}
+	return nil
+}
+
+// ValidateTlsClientCertificate returns an error if cert isn't a PEM encoded
+// certificate or key isn't an unencrypted PEM encoded private key, or if both
+// are given and key isn't the certificate's key. Either can be empty when
+// only the other is being changed.
+func ValidateTlsClientCertificate(cert, key []byte) error {
+	if len(cert) > 0 {
+		block, _ := pem.Decode(cert)
+		if block == nil || block.Type != "CERTIFICATE" {
+			return fmt.Errorf("invalid tls client certificate: %w", db.ErrInvalidParameter)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("invalid tls client certificate: %w", db.ErrInvalidParameter)
+		}
+	}
+	if len(key) > 0 {
+		block, _ := pem.Decode(key)
+		if block == nil || !parsesAsPrivateKey(block.Bytes) {
+			return fmt.Errorf("invalid tls client private key: %w", db.ErrInvalidParameter)
+		}
+	}
+	if len(cert) > 0 && len(key) > 0 {
+		if _, err := tls.X509KeyPair(cert, key); err != nil {
+			return fmt.Errorf("tls client private key doesn't match the certificate: %w", db.ErrInvalidParameter)
+		}
+	}
+	return nil
+}
+
+func parsesAsPrivateKey(der []byte) bool {
+	if _, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return true
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return true
+	}
+	if _, err := x509.ParseECPrivateKey(der); err == nil {
+		return true
+	}
+	return false
+}
+
+// LookupEndpointTls returns how workers originate TLS to the hosts of the
+// tcp target with id targetId. It returns nil, nil if the target doesn't
+// exist or doesn't originate TLS. All options are ignored.
+func (r *Repository) LookupEndpointTls(ctx context.Context, targetId string, opt ...Option) (*EndpointTls, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup endpoint tls: missing target id: %w", db.ErrInvalidParameter)
+	}
+	t := allocTcpTarget()
+	t.PublicId = targetId
+	if err := r.reader.LookupByPublicId(ctx, &t, db.WithWrapperFn(r.kms.DatabaseWrapperFn())); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup endpoint tls: %s: %w", targetId, err)
+	}
+	if !t.EndpointTls {
+		return nil, nil
+	}
+	// A client certificate without its key, or a key without its
+	// certificate, can't be used.
+	if (t.TlsClientCertificate == "") != (len(t.TlsClientPrivateKey) == 0) {
+		return nil, fmt.Errorf("lookup endpoint tls: %s: client certificate and private key must both be set: %w", targetId, db.ErrInvalidParameter)
+	}
+	return &EndpointTls{
+		ServerName:        t.TlsServerName,
+		CaBundle:          t.TlsCaBundle,
+		ClientCertificate: []byte(t.TlsClientCertificate),
+		ClientPrivateKey:  t.TlsClientPrivateKey,
+	}, nil
+}
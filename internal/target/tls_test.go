@@ -0,0 +1,146 @@
+package target
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTlsCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "endpoint"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDer, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDer}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestValidateTls(t *testing.T) {
+	t.Parallel()
+	cert, key := testTlsCertificate(t)
+	otherCert, otherKey := testTlsCertificate(t)
+
+	t.Run("ca-bundle", func(t *testing.T) {
+		assert.NoError(t, ValidateTlsCaBundle(cert))
+		assert.NoError(t, ValidateTlsCaBundle(append(cert, otherCert...)))
+		for _, b := range [][]byte{nil, []byte("not a certificate"), key} {
+			err := ValidateTlsCaBundle(b)
+			assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		}
+	})
+	t.Run("client-certificate", func(t *testing.T) {
+		assert.NoError(t, ValidateTlsClientCertificate(cert, key))
+		assert.NoError(t, ValidateTlsClientCertificate(cert, nil))
+		assert.NoError(t, ValidateTlsClientCertificate(nil, key))
+		assert.NoError(t, ValidateTlsClientCertificate(nil, nil))
+		for _, v := range []struct{ cert, key []byte }{
+			{[]byte("not a certificate"), nil},
+			{key, nil},
+			{nil, []byte("not a key")},
+			{nil, cert},
+			{cert, otherKey},
+		} {
+			err := ValidateTlsClientCertificate(v.cert, v.key)
+			assert.Truef(t, errors.Is(err, db.ErrInvalidParameter), "want err: %q got: %q", db.ErrInvalidParameter, err)
+		}
+	})
+}
+
+func TestTcpTarget_EncryptTls(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	wrapper := db.TestWrapper(t)
+	fn := func(context.Context, string, string) (wrapping.Wrapper, error) { return wrapper, nil }
+	cert, key := testTlsCertificate(t)
+
+	tar := allocTcpTarget()
+	tar.ScopeId = "p_1234567890"
+	tar.TlsCaBundle = cert
+	tar.TlsClientPrivateKey = key
+	require.NoError(t, db.Encrypt(ctx, &tar, fn))
+	assert.Empty(t, tar.TlsCaBundle)
+	assert.Empty(t, tar.TlsClientPrivateKey)
+	assert.NotEmpty(t, tar.CtTlsCaBundle)
+	assert.NotEmpty(t, tar.CtTlsClientPrivateKey)
+	assert.Equal(t, wrapper.KeyID(), tar.KeyId)
+
+	require.NoError(t, db.Decrypt(ctx, &tar, fn))
+	assert.Equal(t, cert, tar.TlsCaBundle)
+	assert.Equal(t, key, tar.TlsClientPrivateKey)
+
+	// Only what's set is encrypted
+	tar = allocTcpTarget()
+	tar.ScopeId = "p_1234567890"
+	tar.TlsCaBundle = cert
+	require.NoError(t, db.Encrypt(ctx, &tar, fn))
+	assert.NotEmpty(t, tar.CtTlsCaBundle)
+	assert.Empty(t, tar.CtTlsClientPrivateKey)
+}
+
+func TestRepository_LookupEndpointTls(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cert, key := testTlsCertificate(t)
+	sshKey, _ := testSshKeys(t)
+
+	tar, err := NewTcpTarget(proj.PublicId, WithName("tls"), WithEndpointTls(true),
+		WithTlsCaBundle(cert), WithTlsClientCertificate(string(cert)), WithTlsClientPrivateKey(key),
+		WithSshUsername("user"), WithSshPrivateKey(sshKey))
+	require.NoError(t, err)
+	created, _, err := repo.CreateTcpTarget(ctx, tar)
+	require.NoError(t, err)
+	id := created.GetPublicId()
+
+	got, err := repo.LookupEndpointTls(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, cert, got.CaBundle)
+	assert.Equal(t, cert, got.ClientCertificate)
+	assert.Equal(t, key, got.ClientPrivateKey)
+
+	// The ssh private key shares the key id and is still decrypted after
+	// the CA bundle is cleared.
+	upd := allocTcpTarget()
+	upd.PublicId = id
+	_, _, _, err = repo.UpdateTcpTarget(ctx, &upd, created.GetVersion(), []string{"TlsCaBundle"})
+	require.NoError(t, err)
+	got, err = repo.LookupEndpointTls(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, got.CaBundle)
+	assert.Equal(t, key, got.ClientPrivateKey)
+	cred, err := repo.LookupSshCredential(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, cred)
+	assert.Equal(t, sshKey, cred.PrivateKey)
+}
@@ -7,13 +7,20 @@ import (
 
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/hostcatalogs"
+	"github.com/hashicorp/boundary/api/hosts"
 	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/sessions"
 	"github.com/hashicorp/boundary/api/targets"
+	targetspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller"
+	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/boundary/internal/target"
+	"github.com/mr-tron/base58"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestCustom(t *testing.T) {
@@ -59,6 +66,189 @@ func TestCustom(t *testing.T) {
 	assert.Empty(tar.Item.HostSetIds)
 }
 
+func TestAuthorizeSession(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	tc := controller.NewTestController(t, nil)
+	defer tc.Shutdown()
+
+	token := tc.Token()
+	_, proj := iam.TestScopes(t, tc.IamRepo(), iam.WithUserId(token.UserId))
+	client := tc.Client().Clone()
+	client.SetToken(token.Token)
+
+	hc, err := hostcatalogs.NewClient(client).Create(tc.Context(), "static", proj.GetPublicId())
+	require.NoError(err)
+	h, err := hosts.NewClient(client).Create(tc.Context(), hc.Item.Id, hosts.WithStaticHostAddress("10.0.0.1"))
+	require.NoError(err)
+	hSet, err := hostsets.NewClient(client).Create(tc.Context(), hc.Item.Id)
+	require.NoError(err)
+	hSet, err = hostsets.NewClient(client).AddHosts(tc.Context(), hSet.Item.Id, hSet.Item.Version, []string{h.Item.Id})
+	require.NoError(err)
+
+	tarClient := targets.NewClient(client)
+	tar, err := tarClient.Create(tc.Context(), "tcp", proj.GetPublicId(), targets.WithName("foo"), targets.WithTcpTargetDefaultPort(22))
+	require.NoError(err)
+	tar, err = tarClient.AddHostSets(tc.Context(), tar.Item.Id, tar.Item.Version, []string{hSet.Item.Id})
+	require.NoError(err)
+
+	// Without a worker there is nothing to handle the session
+	_, err = tarClient.AuthorizeSession(tc.Context(), tar.Item.Id)
+	require.Error(err)
+	apiErr := api.AsServerError(err)
+	require.NotNil(apiErr)
+	assert.EqualValues(http.StatusServiceUnavailable, apiErr.Status)
+
+	// A dry run reports why instead of failing
+	sar, err := tarClient.AuthorizeSession(tc.Context(), tar.Item.Id, targets.WithDryRun(true))
+	require.NoError(err)
+	assert.True(sar.Item.DryRun)
+	assert.Equal([]string{"No workers are available to handle the session."}, sar.Item.DenialReasons)
+	assert.Equal(h.Item.Id, sar.Item.HostId)
+	assert.Empty(sar.Item.SessionId)
+	assert.Empty(sar.Item.AuthorizationToken)
+
+	_, _, err = tc.ServersRepo().UpsertServer(tc.Context(), &servers.Server{
+		Name:    "test-worker",
+		Type:    servers.ServerTypeWorker.String(),
+		Address: "127.0.0.1:9202",
+	})
+	require.NoError(err)
+
+	// Every check is made in a dry run, and no session is created when the
+	// session could be authorized
+	sar, err = tarClient.AuthorizeSession(tc.Context(), tar.Item.Id, targets.WithDryRun(true), targets.WithHostId("hst_1234567890"))
+	require.NoError(err)
+	assert.Equal([]string{"The requested host is not available."}, sar.Item.DenialReasons)
+	sar, err = tarClient.AuthorizeSession(tc.Context(), tar.Item.Id, targets.WithDryRun(true))
+	require.NoError(err)
+	assert.True(sar.Item.DryRun)
+	assert.Empty(sar.Item.DenialReasons)
+	assert.Empty(sar.Item.SessionId)
+	sl, err := sessions.NewClient(client).List(tc.Context(), proj.GetPublicId())
+	require.NoError(err)
+	assert.Empty(sl.Items)
+
+	sar, err = tarClient.AuthorizeSession(tc.Context(), tar.Item.Id)
+	require.NoError(err)
+	sa := sar.Item
+	assert.Equal(tar.Item.Id, sa.TargetId)
+	assert.Equal(token.UserId, sa.UserId)
+	assert.Equal(h.Item.Id, sa.HostId)
+	assert.Equal(hSet.Item.Id, sa.HostSetId)
+
+	// The session records the host's address and why it was chosen
+	sr, err := sessions.NewClient(client).Read(tc.Context(), sa.SessionId)
+	require.NoError(err)
+	assert.Equal("10.0.0.1", sr.Item.HostAddress)
+	assert.Equal(session.HostSelectionRandom, sr.Item.HostSelectionReason)
+
+	marshaled, err := base58.FastBase58Decoding(sa.AuthorizationToken)
+	require.NoError(err)
+	sad := new(targetspb.SessionAuthorizationData)
+	require.NoError(proto.Unmarshal(marshaled, sad))
+	assert.Equal(sa.SessionId, sad.GetSessionId())
+	assert.Equal(h.Item.Id, sad.GetHostId())
+	assert.NotEmpty(sad.GetCertificate())
+	assert.NotEmpty(sad.GetPrivateKey())
+	require.Len(sad.GetWorkerInfo(), 1)
+	assert.Equal("127.0.0.1:9202", sad.GetWorkerInfo()[0].GetAddress())
+	assert.Equal(session.AuthzFormatBare, sa.AuthorizationFormat)
+
+	// Clients that can read enveloped tokens get one
+	sar, err = tarClient.AuthorizeSession(tc.Context(), tar.Item.Id, targets.WithMaxAuthorizationFormat(session.CurrentAuthzFormat))
+	require.NoError(err)
+	sa = sar.Item
+	assert.Equal(session.CurrentAuthzFormat, sa.AuthorizationFormat)
+	sad, format, err := session.DecodeAuthorizationData(sa.AuthorizationToken)
+	require.NoError(err)
+	assert.Equal(session.CurrentAuthzFormat, format)
+	assert.Equal(sa.SessionId, sad.GetSessionId())
+	assert.NotEmpty(sad.GetCertificate())
+
+	// An unknown host is rejected
+	_, err = tarClient.AuthorizeSession(tc.Context(), tar.Item.Id, targets.WithHostId("hst_1234567890"))
+	require.Error(err)
+	apiErr = api.AsServerError(err)
+	require.NotNil(apiErr)
+	assert.EqualValues(http.StatusBadRequest, apiErr.Status)
+}
+
+func TestAuthorizeSession_HostSelection(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	tc := controller.NewTestController(t, nil)
+	defer tc.Shutdown()
+
+	token := tc.Token()
+	_, proj := iam.TestScopes(t, tc.IamRepo(), iam.WithUserId(token.UserId))
+	client := tc.Client().Clone()
+	client.SetToken(token.Token)
+
+	hc, err := hostcatalogs.NewClient(client).Create(tc.Context(), "static", proj.GetPublicId())
+	require.NoError(err)
+	var hostIds []string
+	for _, addr := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		h, err := hosts.NewClient(client).Create(tc.Context(), hc.Item.Id, hosts.WithStaticHostAddress(addr))
+		require.NoError(err)
+		hostIds = append(hostIds, h.Item.Id)
+	}
+	hSet, err := hostsets.NewClient(client).Create(tc.Context(), hc.Item.Id)
+	require.NoError(err)
+	hSet, err = hostsets.NewClient(client).AddHosts(tc.Context(), hSet.Item.Id, hSet.Item.Version, hostIds)
+	require.NoError(err)
+
+	tarClient := targets.NewClient(client)
+	tar, err := tarClient.Create(tc.Context(), "tcp", proj.GetPublicId(), targets.WithName("foo"), targets.WithTcpTargetDefaultPort(22),
+		targets.WithTcpTargetHostSelectionStrategy("round_robin"))
+	require.NoError(err)
+	assert.Equal("round_robin", tar.Item.Attributes["host_selection_strategy"])
+	tar, err = tarClient.AddHostSets(tc.Context(), tar.Item.Id, tar.Item.Version, []string{hSet.Item.Id})
+	require.NoError(err)
+
+	_, _, err = tc.ServersRepo().UpsertServer(tc.Context(), &servers.Server{
+		Name:    "test-worker",
+		Type:    servers.ServerTypeWorker.String(),
+		Address: "127.0.0.1:9202",
+	})
+	require.NoError(err)
+
+	sessClient := sessions.NewClient(client)
+	authorize := func() *sessions.Session {
+		t.Helper()
+		sar, err := tarClient.AuthorizeSession(tc.Context(), tar.Item.Id)
+		require.NoError(err)
+		sr, err := sessClient.Read(tc.Context(), sar.Item.SessionId)
+		require.NoError(err)
+		return sr.Item
+	}
+
+	// Round robin gives each host in turn
+	seen := map[string]bool{}
+	for range hostIds {
+		s := authorize()
+		assert.Equal(session.HostSelectionRoundRobin, s.HostSelectionReason)
+		seen[s.HostId] = true
+	}
+	assert.Len(seen, len(hostIds))
+
+	// Sticky gives the user the host they were last given
+	tar, err = tarClient.Update(tc.Context(), tar.Item.Id, tar.Item.Version, targets.WithTcpTargetHostSelectionStrategy("sticky"))
+	require.NoError(err)
+	first := authorize()
+	assert.Equal(session.HostSelectionRandom, first.HostSelectionReason)
+	for i := 0; i < 3; i++ {
+		s := authorize()
+		assert.Equal(session.HostSelectionSticky, s.HostSelectionReason)
+		assert.Equal(first.HostId, s.HostId)
+	}
+
+	// An unknown strategy is rejected
+	_, err = tarClient.Update(tc.Context(), tar.Item.Id, tar.Item.Version, targets.WithTcpTargetHostSelectionStrategy("least_connections"))
+	require.Error(err)
+	apiErr := api.AsServerError(err)
+	require.NotNil(apiErr)
+	assert.EqualValues(http.StatusBadRequest, apiErr.Status)
+}
+
 func TestList(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
 	tc := controller.NewTestController(t, nil)
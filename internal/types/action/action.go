@@ -37,6 +37,11 @@ const (
 	AddAccounts      Type = 28
 	SetAccounts      Type = 29
 	RemoveAccounts   Type = 30
+	ReadSelf         Type = 31
+	CancelSelf       Type = 32
+	DeleteSelf       Type = 33
+	Clone            Type = 34
+	ReadQuotaUsage   Type = 35
 )
 
 var Map = map[string]Type{
@@ -70,6 +75,11 @@ var Map = map[string]Type{
 	AddAccounts.String():      AddAccounts,
 	SetAccounts.String():      SetAccounts,
 	RemoveAccounts.String():   RemoveAccounts,
+	ReadSelf.String():         ReadSelf,
+	CancelSelf.String():       CancelSelf,
+	DeleteSelf.String():       DeleteSelf,
+	Clone.String():            Clone,
+	ReadQuotaUsage.String():   ReadQuotaUsage,
 }
 
 func (a Type) String() string {
@@ -105,5 +115,25 @@ func (a Type) String() string {
 		"add-accounts",
 		"set-accounts",
 		"remove-accounts",
+		"read:self",
+		"cancel:self",
+		"delete:self",
+		"clone",
+		"read-quota-usage",
 	}[a]
 }
+
+// Self returns the action which grants action a on only the resources
+// belonging to the requesting user, such as read:self for read, or Unknown if
+// a can't be granted that way.
+func (a Type) Self() Type {
+	switch a {
+	case Read:
+		return ReadSelf
+	case Cancel:
+		return CancelSelf
+	case Delete:
+		return DeleteSelf
+	}
+	return Unknown
+}
@@ -75,6 +75,26 @@ func TestAction(t *testing.T) {
 			action: Deauthenticate,
 			want:   "deauthenticate",
 		},
+		{
+			action: ReadSelf,
+			want:   "read:self",
+		},
+		{
+			action: CancelSelf,
+			want:   "cancel:self",
+		},
+		{
+			action: DeleteSelf,
+			want:   "delete:self",
+		},
+		{
+			action: Clone,
+			want:   "clone",
+		},
+		{
+			action: ReadQuotaUsage,
+			want:   "read-quota-usage",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
@@ -83,3 +103,11 @@ func TestAction(t *testing.T) {
 		})
 	}
 }
+
+func TestSelf(t *testing.T) {
+	assert.Equal(t, ReadSelf, Read.Self())
+	assert.Equal(t, CancelSelf, Cancel.Self())
+	assert.Equal(t, DeleteSelf, Delete.Self())
+	assert.Equal(t, Unknown, Update.Self())
+	assert.Equal(t, Unknown, ReadSelf.Self())
+}
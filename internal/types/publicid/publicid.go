@@ -0,0 +1,155 @@
+// Package publicid registers the prefixes of the public ids of Boundary's
+// resources, and generates and validates public ids.
+//
+// A public id is a registered prefix, an underscore and a random base62
+// string, such as "ttcp_1234567890". Each prefix is registered for exactly
+// one resource type, so the type of a resource can be told from its id.
+package publicid
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+)
+
+// GlobalScopeId is the id of the global scope. It's the only public id
+// without a prefix.
+const GlobalScopeId = "global"
+
+// idLength is the length of the random part of generated ids.
+const idLength = 10
+
+var (
+	// ErrInvalidPublicId is returned when an id isn't a well-formed public id
+	// of the expected resource type.
+	ErrInvalidPublicId = errors.New("invalid public id")
+
+	// ErrPrefixRegistered is returned when registering a prefix which is
+	// already registered.
+	ErrPrefixRegistered = errors.New("prefix already registered")
+
+	// ErrUnknownPrefix is returned when generating an id with a prefix which
+	// isn't registered.
+	ErrUnknownPrefix = errors.New("unknown prefix")
+)
+
+var (
+	reValidPrefix = regexp.MustCompile("^[a-z]+$")
+	reInvalidId   = regexp.MustCompile("[^A-Za-z0-9]")
+)
+
+// registry maps each registered prefix to its resource type.
+var registry = struct {
+	sync.RWMutex
+	prefixes map[string]resource.Type
+}{
+	prefixes: map[string]resource.Type{
+		"o":    resource.Scope,
+		"p":    resource.Scope,
+		"u":    resource.User,
+		"g":    resource.Group,
+		"r":    resource.Role,
+		"ampw": resource.AuthMethod,
+		"apw":  resource.Account,
+		"at":   resource.AuthToken,
+		"hcst": resource.HostCatalog,
+		"hsst": resource.HostSet,
+		"hst":  resource.Host,
+		"ttcp": resource.Target,
+		"s":    resource.Session,
+	},
+}
+
+// Register registers the prefix for ids of the resource type. It returns an
+// error wrapping ErrPrefixRegistered if the prefix is already registered,
+// even for the same type, so two resources can't use the same prefix.
+func Register(t resource.Type, prefix string) error {
+	if t == resource.Unknown || t == resource.All {
+		return fmt.Errorf("register prefix %q: invalid resource type %s", prefix, t)
+	}
+	if !reValidPrefix.MatchString(prefix) {
+		return fmt.Errorf("register prefix %q: prefixes must be lowercase letters", prefix)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if existing, ok := registry.prefixes[prefix]; ok {
+		return fmt.Errorf("register prefix %q: used by %s: %w", prefix, existing, ErrPrefixRegistered)
+	}
+	registry.prefixes[prefix] = t
+	return nil
+}
+
+// Prefixes returns the sorted prefixes registered for the resource type.
+func Prefixes(t resource.Type) []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	var prefixes []string
+	for p, pt := range registry.prefixes {
+		if pt == t {
+			prefixes = append(prefixes, p)
+		}
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// Type returns the resource type of the id, or resource.Unknown if the id
+// isn't a well-formed public id with a registered prefix.
+func Type(id string) resource.Type {
+	if id == GlobalScopeId {
+		return resource.Scope
+	}
+	i := strings.Index(id, "_")
+	if i < 0 || !ValidWithPrefix(id[:i], id) {
+		return resource.Unknown
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	if t, ok := registry.prefixes[id[:i]]; ok {
+		return t
+	}
+	return resource.Unknown
+}
+
+// New returns a new public id with the prefix. It returns an error wrapping
+// ErrUnknownPrefix if the prefix isn't registered.
+func New(prefix string) (string, error) {
+	registry.RLock()
+	_, ok := registry.prefixes[prefix]
+	registry.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("new public id: %q: %w", prefix, ErrUnknownPrefix)
+	}
+	id, err := base62.Random(idLength)
+	if err != nil {
+		return "", fmt.Errorf("new public id: unable to generate id: %w", err)
+	}
+	return fmt.Sprintf("%s_%s", prefix, id), nil
+}
+
+// Validate returns an error wrapping ErrInvalidPublicId unless the id is a
+// well-formed public id with one of the prefixes registered for the
+// resource type.
+func Validate(id string, t resource.Type) error {
+	if got := Type(id); got != t {
+		return fmt.Errorf("%q is not a valid %s id: %w", id, t, ErrInvalidPublicId)
+	}
+	return nil
+}
+
+// ValidWithPrefix returns true if the id is the prefix, an underscore and a
+// non-empty base62 string. The prefix doesn't need to be registered.
+func ValidWithPrefix(prefix, id string) bool {
+	prefix = prefix + "_"
+	if !strings.HasPrefix(id, prefix) {
+		return false
+	}
+	id = strings.TrimPrefix(id, prefix)
+	return id != "" && !reInvalidId.MatchString(id)
+}
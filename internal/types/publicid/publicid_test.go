@@ -0,0 +1,84 @@
+package publicid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+	t.Run("registered", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		id, err := New("ttcp")
+		require.NoError(err)
+		assert.True(strings.HasPrefix(id, "ttcp_"))
+		assert.Len(id, len("ttcp_")+idLength)
+		assert.NoError(Validate(id, resource.Target))
+	})
+	t.Run("unknown", func(t *testing.T) {
+		assert := assert.New(t)
+		id, err := New("nope")
+		assert.Empty(id)
+		assert.True(errors.Is(err, ErrUnknownPrefix))
+	})
+}
+
+func Test_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		id      string
+		typ     resource.Type
+		wantErr bool
+	}{
+		{name: "target", id: "ttcp_1234567890", typ: resource.Target},
+		{name: "org", id: "o_1234567890", typ: resource.Scope},
+		{name: "global", id: "global", typ: resource.Scope},
+		{name: "reserved-user", id: "u_anon", typ: resource.User},
+		{name: "wrong-type", id: "ttcp_1234567890", typ: resource.Host, wantErr: true},
+		{name: "unregistered", id: "abc_1234567890", typ: resource.Host, wantErr: true},
+		{name: "no-prefix", id: "1234567890", typ: resource.Host, wantErr: true},
+		{name: "empty-suffix", id: "hst_", typ: resource.Host, wantErr: true},
+		{name: "bad-characters", id: "hst_1234-67890", typ: resource.Host, wantErr: true},
+		{name: "empty", typ: resource.Host, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			err := Validate(tt.id, tt.typ)
+			if tt.wantErr {
+				assert.True(errors.Is(err, ErrInvalidPublicId))
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func Test_Register(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	err := Register(resource.Host, "hst")
+	assert.True(errors.Is(err, ErrPrefixRegistered))
+	err = Register(resource.Host, "s")
+	assert.True(errors.Is(err, ErrPrefixRegistered))
+	assert.Error(Register(resource.Host, "Hst2"))
+	assert.Error(Register(resource.Unknown, "unk"))
+
+	assert.NoError(Register(resource.Host, "hsttest"))
+	assert.Equal([]string{"hst", "hsttest"}, Prefixes(resource.Host))
+	assert.Equal(resource.Host, Type("hsttest_1234567890"))
+}
+
+func Test_Prefixes(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	assert.Equal([]string{"o", "p"}, Prefixes(resource.Scope))
+	assert.Equal([]string{"ttcp"}, Prefixes(resource.Target))
+	assert.Empty(Prefixes(resource.Worker))
+}
@@ -620,6 +620,13 @@ var role = &Resource{
 						"id=<id>;actions=remove-grants",
 					},
 				},
+				&Action{
+					Name:        "clone",
+					Description: "Create a copy of a role, with its grants, in the same scope",
+					Examples: []string{
+						"id=<id>;actions=clone",
+					},
+				},
 			),
 		},
 	},